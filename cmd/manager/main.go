@@ -12,17 +12,21 @@ import (
 	"net/http/pprof"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"go.elastic.co/apm/v2"
 	"go.uber.org/automaxprocs/maxprocs"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -31,60 +35,99 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	crwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/about"
 	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	alertingv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/alerting/v1alpha1"
 	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
 	apmv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1beta1"
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	dataviewv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/dataview/v1alpha1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	esv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1beta1"
 	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
 	entv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1beta1"
+	fleetv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/fleet/v1alpha1"
+	ilmv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/ilm/v1alpha1"
+	indexv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/index/v1alpha1"
+	ingestv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/ingest/v1alpha1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	kbv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1beta1"
+	kibanaspacev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibanaspace/v1alpha1"
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
 	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
+	remoteclusterv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/remotecluster/v1alpha1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
 	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
+	transformv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/transform/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/agent"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/alertingrule"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/apmserver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
 	associationctl "github.com/elastic/cloud-on-k8s/v2/pkg/controller/association/controller"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/autoscaling"
 	esavalidation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/autoscaling/elasticsearch/validation"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/beat"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore/external"
 	commonlicense "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/multicluster"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/namespaces"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/queue"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/sharding"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing/apmclientgo"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	commonwebhook "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/webhook"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/dataview"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 	esvalidation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/validation"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearchindex"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearchrestore"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearchrole"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearchrolemapping"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearchuser"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/enterprisesearch"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/fleetpolicy"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/ilmpolicy"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/indextemplate"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/ingestpipeline"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibanaspace"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/license"
 	licensetrial "github.com/elastic/cloud-on-k8s/v2/pkg/controller/license/trial"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash"
 	lsvalidation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/validation"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/maps"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/remoteca"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/remoteclusterlink"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/snapshotlifecyclepolicy"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/snapshotrepository"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/stackconfigpolicy"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/transform"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/webhook"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/dev"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/dev/portforward"
@@ -107,11 +150,21 @@ const (
 	LeaderElectionLeaseName = "elastic-operator-leader"
 
 	debugHTTPShutdownTimeout = 5 * time.Second // time to allow for the debug HTTP server to shutdown
+
+	DefaultShutdownGracePeriod            = 10 * time.Second
+	DefaultReconciliationStalledThreshold = 1 * time.Hour
+
+	// shutdownMargin is the additional time given to the manager to stop once in-flight reconciliations are interrupted
+	shutdownMargin = 3 * time.Second
 )
 
 var (
 	configFile string
 	log        logr.Logger
+
+	// caCertRotation and certRotation are shared by all the controllers, and updated when the configuration changes.
+	caCertRotation = certificates.NewDynamicRotationParams(certificates.RotationParams{})
+	certRotation   = certificates.NewDynamicRotationParams(certificates.RotationParams{})
 )
 
 func Command() *cobra.Command {
@@ -119,26 +172,14 @@ func Command() *cobra.Command {
 		Use:   "manager",
 		Short: "Start the Elastic Cloud on Kubernetes operator",
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
-			// enable using dashed notation in flags and underscores in env
-			viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
-
-			if err := viper.BindPFlags(cmd.Flags()); err != nil {
-				return fmt.Errorf("failed to bind flags: %w", err)
-			}
-
-			viper.AutomaticEnv()
-
-			if configFile != "" {
-				viper.SetConfigFile(configFile)
-				if err := viper.ReadInConfig(); err != nil {
-					return fmt.Errorf("failed to read config file %s: %w", configFile, err)
-				}
+			if err := loadConfig(viper.GetViper(), cmd.Flags()); err != nil {
+				return err
 			}
 
 			logconf.ChangeVerbosity(viper.GetInt(logconf.FlagName))
 			log = logf.Log.WithName("manager")
 
-			return nil
+			return setLogVerbosityOverrides(viper.GetViper())
 		},
 		RunE: doRun,
 	}
@@ -149,6 +190,21 @@ func Command() *cobra.Command {
 		"Enables automatic port-forwarding "+
 			"(for dev use only as it exposes k8s resources on ephemeral ports to localhost)",
 	)
+	cmd.Flags().String(
+		operator.BeatAutodiscoverClusterRoleFlag,
+		"",
+		"Name of the ClusterRole bound to the ServiceAccount of the Beats using autodiscover. The operator must be allowed to bind it. The ServiceAccount and ClusterRoleBinding are not created if empty.",
+	)
+	cmd.Flags().Bool(
+		operator.CacheLabeledResourcesOnlyFlag,
+		false,
+		"Only cache the Secrets, ConfigMaps, Pods and Services created by the operator to reduce memory usage. Other resources of these kinds are read from the Kubernetes API and their changes are not watched.",
+	)
+	cmd.Flags().Duration(
+		operator.CacheSyncPeriodFlag,
+		0,
+		"Period after which all the resources watched by the operator are reconciled again, even if they did not change. Defaults to 10 hours if 0.",
+	)
 	cmd.Flags().String(
 		operator.CADirFlag,
 		"",
@@ -180,6 +236,16 @@ func Command() *cobra.Command {
 		"",
 		"Path to the file containing the operator configuration",
 	)
+	cmd.Flags().Bool(
+		operator.ControllerSaturationReadinessFlag,
+		false,
+		fmt.Sprintf("Fail the readiness probe served on %s while a controller is wedged. This also makes the webhook endpoints unavailable", operator.HealthProbeBindAddressFlag),
+	)
+	cmd.Flags().Duration(
+		operator.ControllerSaturationThresholdFlag,
+		queue.DefaultSaturationThreshold,
+		"Duration after which a controller with items waiting to be processed is considered wedged",
+	)
 	cmd.Flags().String(
 		operator.ContainerRegistryFlag,
 		container.DefaultContainerRegistry,
@@ -205,16 +271,66 @@ func Command() *cobra.Command {
 		false,
 		"Disable watching the configuration file for changes",
 	)
+	cmd.Flags().String(
+		operator.ElasticsearchClientAuditLogFlag,
+		"",
+		fmt.Sprintf("Path to a file to which an audit entry is appended for every Elasticsearch API call made by the operator. Use %q to write to the standard output. Disabled if empty.", logconf.AuditLogStdout),
+	)
+	cmd.Flags().Int(
+		operator.ElasticsearchClientBurst,
+		0,
+		fmt.Sprintf("Maximum burst of requests to each Elasticsearch cluster. Defaults to twice %s if set.", operator.ElasticsearchClientQPS),
+	)
+	cmd.Flags().Int(
+		operator.ElasticsearchClientGlobalBurst,
+		0,
+		fmt.Sprintf("Maximum burst of requests to all the Elasticsearch clusters. Defaults to twice %s if set.", operator.ElasticsearchClientGlobalQPS),
+	)
+	cmd.Flags().Float64(
+		operator.ElasticsearchClientGlobalQPS,
+		0,
+		"Maximum average number of requests per second to all the Elasticsearch clusters. Requests above the limit are delayed. Zero means no limit.",
+	)
+	cmd.Flags().Duration(
+		operator.ElasticsearchClientIdleConnTimeout,
+		esclient.DefaultConnectionPoolSettings.IdleConnTimeout,
+		"Duration after which idle connections to an Elasticsearch cluster are closed. Zero means no limit.",
+	)
+	cmd.Flags().Int(
+		operator.ElasticsearchClientMaxConnsPerHost,
+		esclient.DefaultConnectionPoolSettings.MaxConnsPerHost,
+		"Maximum number of connections to each Elasticsearch endpoint. Zero means no limit.",
+	)
+	cmd.Flags().Int(
+		operator.ElasticsearchClientMaxIdleConnsPerHost,
+		esclient.DefaultConnectionPoolSettings.MaxIdleConnsPerHost,
+		"Maximum number of idle connections kept open to each Elasticsearch endpoint, to be reused by the following requests.",
+	)
+	cmd.Flags().Float64(
+		operator.ElasticsearchClientQPS,
+		0,
+		"Maximum average number of requests per second to each Elasticsearch cluster. Requests above the limit are delayed. Zero means no limit.",
+	)
 	cmd.Flags().Duration(
 		operator.ElasticsearchClientTimeout,
 		3*time.Minute,
 		"Default timeout for requests made by the Elasticsearch client.",
 	)
+	cmd.Flags().Duration(
+		operator.ElasticsearchHealthBatchWindowFlag,
+		0,
+		"Delay before reconciling an Elasticsearch cluster whose health changed, so that the health changes happening within this window are reported with a single status update. Reconciles immediately if 0.",
+	)
 	cmd.Flags().Duration(
 		operator.ElasticsearchObservationIntervalFlag,
 		10*time.Second,
 		"Interval between observations of Elasticsearch health, non-positive values disable asynchronous observation",
 	)
+	cmd.Flags().StringSlice(
+		operator.DisableControllersFlag,
+		[]string{},
+		"Names of the controllers which must not reconcile any resource, for example kibana-controller. Can be changed without restarting the operator.",
+	)
 	cmd.Flags().Bool(
 		operator.DisableTelemetryFlag,
 		false,
@@ -228,13 +344,23 @@ func Command() *cobra.Command {
 	cmd.Flags().Bool(
 		operator.EnforceRBACOnRefsFlag,
 		false, // Set to false for backward compatibility
-		"Restrict cross-namespace resource association through RBAC (eg. referencing Elasticsearch from Kibana)",
+		"Restrict cross-namespace resource association through RBAC or AssociationPolicy resources (eg. referencing Elasticsearch from Kibana)",
 	)
 	cmd.Flags().Bool(
 		operator.EnableLeaderElection,
 		true,
 		"Enable leader election. Enabling this will ensure there is only one active operator.",
 	)
+	cmd.Flags().Bool(
+		operator.EnableShardingFlag,
+		false,
+		"Distribute the reconciliation of resources between all the operator replicas. Tasks which must run only once remain performed by the elected leader.",
+	)
+	cmd.Flags().String(
+		operator.ShardingScopeFlag,
+		string(sharding.ResourceScope),
+		"Unit of distribution of the resources between the operator replicas when sharding is enabled. Possible values: resource (each resource is assigned independently), namespace (all the resources of a namespace are assigned to the same replica).",
+	)
 	cmd.Flags().Bool(
 		operator.EnableTracingFlag,
 		false,
@@ -244,11 +370,55 @@ func Command() *cobra.Command {
 		false,
 		"Enables a validating webhook server in the operator process.",
 	)
+	cmd.Flags().Duration(
+		operator.EventsDedupWindowFlag,
+		0,
+		"Duration during which an event identical to a previously emitted one for the same resource is not emitted again. Deduplication is disabled if 0.",
+	)
+	cmd.Flags().String(
+		operator.EventsMinSeverityFlag,
+		corev1.EventTypeNormal,
+		"Minimum type of the Kubernetes events emitted by the operator. Possible values: Normal (all events), Warning (only warnings)",
+	)
+	cmd.Flags().Int(
+		operator.EventsRateLimitBurstFlag,
+		0,
+		"Maximum number of events that can be emitted in a burst for a given resource. Defaults to the Kubernetes client default (25) if 0.",
+	)
+	cmd.Flags().Float32(
+		operator.EventsRateLimitQPSFlag,
+		0,
+		"Sustained rate of events per second that can be emitted for a given resource once the burst is exhausted. Defaults to the Kubernetes client default (one every 5 minutes) if 0.",
+	)
 	cmd.Flags().StringSlice(
 		operator.ExposedNodeLabels,
 		[]string{},
 		"Comma separated list of node labels which are allowed to be copied as annotations on Elasticsearch Pods, empty by default",
 	)
+	cmd.Flags().StringSlice(
+		operator.ExternalSecretProvidersFlag,
+		[]string{},
+		"Comma separated list of external secret stores from which secure settings can be fetched, among vault, aws and gcp. "+
+			fmt.Sprintf("Secrets are fetched with the credentials of the operator, within the path prefixes set by %s. Disabled if empty.", operator.ExternalSecretPathPrefixesFlag),
+	)
+	cmd.Flags().StringToString(
+		operator.ExternalSecretPathPrefixesFlag,
+		nil,
+		fmt.Sprintf("Path prefix of the secrets that can be fetched from each external secret store, per provider (eg. vault=secret/data/eck/%s/). "+
+			"%s is replaced by the namespace of the resource referencing the secret. Required for each of the %s.",
+			commonv1.NamespacePlaceholder, commonv1.NamespacePlaceholder, operator.ExternalSecretProvidersFlag),
+	)
+	cmd.Flags().String(
+		operator.HealthProbeBindAddressFlag,
+		"",
+		"Address on which the liveness (/healthz) and readiness (/readyz) probes are served, for example :8081. Disabled if empty.",
+	)
+	cmd.Flags().StringToString(
+		operator.ImageMappingsFlag,
+		nil,
+		"Container images overriding the default ones, per image name (eg. elasticsearch=mirror.example.com/elastic/elasticsearch) or per image name and version "+
+			"(eg. kibana:8.15.0=mirror.example.com/elastic/kibana@sha256:...). A repository mapped to an image name is suffixed with the version.",
+	)
 	cmd.Flags().Int(
 		operator.PasswordHashCacheSize,
 		0,
@@ -262,6 +432,11 @@ func Command() *cobra.Command {
 		"",
 		"Set the IP family to use. Possible values: IPv4, IPv6, \"\" (= auto-detect) ",
 	)
+	cmd.Flags().String(
+		operator.IPFamilyPolicyFlag,
+		"",
+		"Set the IP family policy of the services created by the operator, unless set in the service specification of the resource. Possible values: SingleStack, PreferDualStack, RequireDualStack, \"\" (= Kubernetes default)",
+	)
 	cmd.Flags().Duration(
 		operator.KubeClientTimeout,
 		60*time.Second,
@@ -272,6 +447,21 @@ func Command() *cobra.Command {
 		0,
 		"Maximum number of queries per second to the Kubernetes API.",
 	)
+	cmd.Flags().Int(
+		operator.KubeClientBurst,
+		0,
+		fmt.Sprintf("Maximum burst of queries to the Kubernetes API. Defaults to twice %s if set.", operator.KubeClientQPS),
+	)
+	cmd.Flags().String(
+		operator.LocalVolumeFailurePolicyFlag,
+		string(operator.LocalVolumeFailurePolicyWait),
+		"Defines how to handle Elasticsearch Pods whose local volumes are lost with their Kubernetes node. Possible values: Wait (Pods stay Pending until the node is back), Recreate (volume claims and Pods are deleted, for the data to be recovered from replicas).",
+	)
+	cmd.Flags().Bool(
+		operator.MaintenanceModeFlag,
+		false,
+		"Pauses the reconciliation of all resources, as if they were annotated with eck.k8s.elastic.co/managed=false. Resources are not modified, only the health of Elasticsearch clusters is still reported in their status. Can be changed without restarting the operator.",
+	)
 	cmd.Flags().Bool(
 		operator.ManageWebhookCertsFlag,
 		true,
@@ -282,6 +472,11 @@ func Command() *cobra.Command {
 		3,
 		"Sets maximum number of concurrent reconciles per controller (Elasticsearch, Kibana, Apm Server etc). Affects the ability of the operator to process changes concurrently.",
 	)
+	cmd.Flags().Bool(
+		operator.MetadataOnlyWatchesFlag,
+		false,
+		"Only cache the metadata of Pods and Secrets to reduce memory usage, and read them from the Kubernetes API when their content is needed.",
+	)
 	cmd.Flags().Int(
 		operator.MetricsPortFlag,
 		DefaultMetricPort,
@@ -292,11 +487,21 @@ func Command() *cobra.Command {
 		"0.0.0.0",
 		fmt.Sprintf("The host to which the operator should bind to serve metrics in the Prometheus format. Will be combined with %s.", operator.MetricsPortFlag),
 	)
+	cmd.Flags().String(
+		operator.NamespaceLabelSelectorFlag,
+		"",
+		fmt.Sprintf("Label selector of the namespaces in which this operator should manage resources, in addition to the operator namespace. Namespaces are managed as soon as they match the selector. Cannot be combined with %s.", operator.NamespacesFlag),
+	)
 	cmd.Flags().StringSlice(
 		operator.NamespacesFlag,
 		nil,
 		"Comma-separated list of namespaces in which this operator should manage resources (defaults to all namespaces)",
 	)
+	cmd.Flags().String(
+		operator.ProfileFlag,
+		operator.DefaultProfile,
+		fmt.Sprintf("Set of default settings tuned for a kind of installation: %q keeps the default settings, %q tunes the operator for installations managing hundreds of resources. Settings explicitly configured take precedence over the profile.", operator.DefaultProfile, operator.ScaleProfile),
+	)
 	cmd.Flags().String(
 		operator.OperatorNamespaceFlag,
 		"",
@@ -307,6 +512,11 @@ func Command() *cobra.Command {
 		1*time.Hour,
 		"Interval between ECK telemetry data updates",
 	)
+	cmd.Flags().StringToString(
+		operator.TierStorageClassesFlag,
+		nil,
+		"Storage class of the Elasticsearch volume claims that do not specify any, per data tier role (eg. data_hot=nvme,data_warm=standard,data_frozen=cheap)",
+	)
 	cmd.Flags().Bool(
 		operator.UBIOnlyFlag,
 		false,
@@ -338,11 +548,36 @@ func Command() *cobra.Command {
 		WebhookPort,
 		"Port is the port that the webhook server serves at.",
 	)
+	cmd.Flags().Duration(
+		operator.ReconciliationStalledThresholdFlag,
+		DefaultReconciliationStalledThreshold,
+		"Duration after which an Elasticsearch cluster with changes still pending is reported as stalled with the ReconciliationStalled condition. Set to 0 to disable the detection.",
+	)
+	cmd.Flags().Bool(
+		operator.ReconciliationStalledRemediationFlag,
+		false,
+		"Enables the known remediations of stalled reconciliations, such as removing the shard allocation exclusions left behind by earlier data migrations.",
+	)
+	cmd.Flags().Bool(
+		operator.RequireImageDigestsFlag,
+		false,
+		fmt.Sprintf("Refuse to deploy Elastic Stack images that are not referenced by digest, either through %s or the image of the resource spec", operator.ImageMappingsFlag),
+	)
+	cmd.Flags().Duration(
+		operator.ServiceAccountTokenRotationFlag,
+		0,
+		"Interval after which the Elasticsearch service account tokens used by the associations, such as Kibana or Fleet Server to Elasticsearch, are replaced. The previous token remains valid for one hour after a rotation. Set to 0 to never rotate them.",
+	)
 	cmd.Flags().String(
 		operator.SetDefaultSecurityContextFlag,
 		"auto-detect",
 		"Enables setting the default security context with fsGroup=1000 for Elasticsearch 8.0+ Pods. Ignored pre-8.0. Possible values: true, false, auto-detect",
 	)
+	cmd.Flags().Duration(
+		operator.ShutdownGracePeriodFlag,
+		DefaultShutdownGracePeriod,
+		"Maximum duration during which in-flight reconciliations can complete when the operator shuts down. Should be lower than the termination grace period of the operator Pod. Must be lower than 17s when sharding is enabled. Set to 0 to interrupt them immediately.",
+	)
 
 	// hide development mode flags from the usage message
 	_ = cmd.Flags().MarkHidden(operator.AutoPortForwardFlag)
@@ -362,7 +597,213 @@ func Command() *cobra.Command {
 	return cmd
 }
 
-func doRun(_ *cobra.Command, _ []string) error {
+// loadConfig loads the operator configuration from the given flags, the environment and the configuration file.
+func loadConfig(v *viper.Viper, flags *pflag.FlagSet) error {
+	// enable using dashed notation in flags and underscores in env
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+
+	if err := v.BindPFlags(flags); err != nil {
+		return fmt.Errorf("failed to bind flags: %w", err)
+	}
+
+	v.AutomaticEnv()
+
+	if configFile != "" {
+		v.SetConfigFile(configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", configFile, err)
+		}
+	}
+
+	// the settings of the profile are defaults, which explicitly configured settings take precedence over
+	profileSettings, err := operator.ProfileSettings(v.GetString(operator.ProfileFlag))
+	if err != nil {
+		return err
+	}
+	for name, value := range profileSettings {
+		v.SetDefault(name, value)
+	}
+	return nil
+}
+
+// setLogVerbosityOverrides sets the verbosity of specific loggers from the given configuration.
+func setLogVerbosityOverrides(v *viper.Viper) error {
+	overrides, err := logconf.ParseVerbosityOverrides(v.GetStringMapString(logconf.OverridesFlagName))
+	if err != nil {
+		return err
+	}
+	logconf.SetVerbosityOverrides(overrides)
+	return nil
+}
+
+// reloadableSettings are the settings which are applied without restarting the operator when the configuration file
+// changes, along with the function applying them from the updated configuration.
+var reloadableSettings = []struct {
+	names []string
+	apply func(*viper.Viper) error
+}{
+	{
+		names: []string{logconf.FlagName, logconf.OverridesFlagName},
+		apply: func(v *viper.Viper) error {
+			if err := setLogVerbosityOverrides(v); err != nil {
+				return err
+			}
+			logconf.SetVerbosity(v.GetInt(logconf.FlagName))
+			return nil
+		},
+	},
+	{
+		names: []string{operator.ElasticsearchClientTimeout},
+		apply: func(v *viper.Viper) error {
+			esclient.SetDefaultTimeout(v.GetDuration(operator.ElasticsearchClientTimeout))
+			return nil
+		},
+	},
+	{
+		names: []string{
+			operator.ElasticsearchClientIdleConnTimeout,
+			operator.ElasticsearchClientMaxConnsPerHost,
+			operator.ElasticsearchClientMaxIdleConnsPerHost,
+		},
+		apply: setConnectionPoolSettings,
+	},
+	{
+		names: []string{
+			operator.ElasticsearchClientQPS,
+			operator.ElasticsearchClientBurst,
+			operator.ElasticsearchClientGlobalQPS,
+			operator.ElasticsearchClientGlobalBurst,
+		},
+		apply: setRateLimitSettings,
+	},
+	{
+		names: []string{operator.MaintenanceModeFlag},
+		apply: func(v *viper.Viper) error {
+			common.SetMaintenanceMode(v.GetBool(operator.MaintenanceModeFlag))
+			return nil
+		},
+	},
+	{
+		names: []string{operator.DisableControllersFlag},
+		apply: func(v *viper.Viper) error {
+			common.SetDisabledControllers(v.GetStringSlice(operator.DisableControllersFlag))
+			return nil
+		},
+	},
+	{
+		names: []string{operator.CACertValidityFlag, operator.CACertRotateBeforeFlag},
+		apply: func(v *viper.Viper) error {
+			return setRotationParams(v, caCertRotation, operator.CACertValidityFlag, operator.CACertRotateBeforeFlag)
+		},
+	},
+	{
+		names: []string{operator.CertValidityFlag, operator.CertRotateBeforeFlag},
+		apply: func(v *viper.Viper) error {
+			return setRotationParams(v, certRotation, operator.CertValidityFlag, operator.CertRotateBeforeFlag)
+		},
+	},
+}
+
+// setConnectionPoolSettings sets the settings of the connections to Elasticsearch clusters from the configuration.
+func setConnectionPoolSettings(v *viper.Viper) error {
+	settings := esclient.ConnectionPoolSettings{
+		MaxIdleConnsPerHost: v.GetInt(operator.ElasticsearchClientMaxIdleConnsPerHost),
+		MaxConnsPerHost:     v.GetInt(operator.ElasticsearchClientMaxConnsPerHost),
+		IdleConnTimeout:     v.GetDuration(operator.ElasticsearchClientIdleConnTimeout),
+	}
+	if settings.MaxIdleConnsPerHost < 0 || settings.MaxConnsPerHost < 0 || settings.IdleConnTimeout < 0 {
+		return fmt.Errorf("%s, %s and %s must not be negative",
+			operator.ElasticsearchClientMaxIdleConnsPerHost, operator.ElasticsearchClientMaxConnsPerHost, operator.ElasticsearchClientIdleConnTimeout)
+	}
+	esclient.SetConnectionPoolSettings(settings)
+	return nil
+}
+
+// setRateLimitSettings sets the rate limits of the requests to Elasticsearch clusters from the configuration.
+func setRateLimitSettings(v *viper.Viper) error {
+	settings := esclient.RateLimitSettings{
+		QPS:         v.GetFloat64(operator.ElasticsearchClientQPS),
+		Burst:       v.GetInt(operator.ElasticsearchClientBurst),
+		GlobalQPS:   v.GetFloat64(operator.ElasticsearchClientGlobalQPS),
+		GlobalBurst: v.GetInt(operator.ElasticsearchClientGlobalBurst),
+	}
+	if settings.QPS < 0 || settings.Burst < 0 || settings.GlobalQPS < 0 || settings.GlobalBurst < 0 {
+		return fmt.Errorf("%s, %s, %s and %s must not be negative",
+			operator.ElasticsearchClientQPS, operator.ElasticsearchClientBurst, operator.ElasticsearchClientGlobalQPS, operator.ElasticsearchClientGlobalBurst)
+	}
+	esclient.SetRateLimitSettings(settings)
+	return nil
+}
+
+// reloadConfig reloads the configuration and applies the updated settings in place, if they can all be applied without
+// restarting the operator. It returns false if the operator must be restarted to apply the configuration.
+func reloadConfig(flags *pflag.FlagSet) bool {
+	updated := viper.New()
+	if err := loadConfig(updated, flags); err != nil {
+		log.Error(err, "Failed to reload configuration")
+		return false
+	}
+
+	changed := changedSettings(viper.AllSettings(), updated.AllSettings())
+	if len(changed) == 0 {
+		return true
+	}
+	reloadable := map[string]bool{}
+	for _, s := range reloadableSettings {
+		for _, name := range s.names {
+			reloadable[name] = changed[name]
+		}
+	}
+	for name := range changed {
+		if _, exists := reloadable[name]; !exists {
+			log.Info("Updated setting requires a restart", "setting", name)
+			return false
+		}
+	}
+
+	for _, s := range reloadableSettings {
+		if !slices.ContainsFunc(s.names, func(name string) bool { return reloadable[name] }) {
+			continue
+		}
+		if err := s.apply(updated); err != nil {
+			log.Error(err, "Failed to apply updated configuration", "settings", s.names)
+			return false
+		}
+	}
+	for name := range changed {
+		viper.Set(name, updated.Get(name))
+		log.Info("Updated setting", "setting", name, "value", updated.Get(name))
+	}
+	return true
+}
+
+// changedSettings returns the names of the top-level settings which differ between the two configurations.
+func changedSettings(current, updated map[string]interface{}) map[string]bool {
+	changed := map[string]bool{}
+	for name, value := range current {
+		if !reflect.DeepEqual(value, updated[name]) {
+			changed[name] = true
+		}
+	}
+	for name := range updated {
+		if _, exists := current[name]; !exists {
+			changed[name] = true
+		}
+	}
+	return changed
+}
+
+// setRotationParams validates the given certificate validity and rotation settings before updating the rotation params.
+func setRotationParams(v *viper.Viper, params *certificates.DynamicRotationParams, validityFlag, rotateBeforeFlag string) error {
+	validity, rotateBefore, err := validateCertExpirationFlags(v, validityFlag, rotateBeforeFlag)
+	if err != nil {
+		return err
+	}
+	params.Set(certificates.RotationParams{Validity: validity, RotateBefore: rotateBefore})
+	return nil
+}
+
+func doRun(cmd *cobra.Command, _ []string) error {
 	ctx := signals.SetupSignalHandler()
 
 	// receive config/CA file update events over a channel
@@ -385,7 +826,11 @@ func doRun(_ *cobra.Command, _ []string) error {
 		)
 	}
 
-	onConfChange := func(_ []string) {
+	onConfChange := func(updated []string) {
+		// some settings are applied without restarting the operator, see reloadableSettings
+		if len(updated) == 1 && updated[0] == configFile && reloadConfig(cmd.Flags()) {
+			return
+		}
 		confUpdateChan <- struct{}{}
 	}
 	watcher := fs.NewFileWatcher(ctx, toWatch, onConfChange, 15*time.Second)
@@ -516,6 +961,24 @@ func startOperator(ctx context.Context) error {
 		version.GlobalMinStackVersion = version.From(7, 10, 0)
 	}
 
+	// override the default images with the configured mappings
+	imageMappings, err := validateImageMappings(viper.GetStringMapString(operator.ImageMappingsFlag))
+	if err != nil {
+		log.Error(err, "Invalid image mappings")
+		return err
+	}
+	if len(imageMappings) > 0 {
+		log.Info("Setting image mappings", "image_mappings", imageMappings)
+		container.SetImageMappings(imageMappings)
+	}
+
+	// refuse images not referenced by digest if requested
+	requireImageDigests := viper.GetBool(operator.RequireImageDigestsFlag)
+	if requireImageDigests {
+		log.Info("Requiring container images to be referenced by digest")
+		container.SetRequireImageDigests(requireImageDigests)
+	}
+
 	// Get a config to talk to the apiserver
 	cfg, err := ctrl.GetConfig()
 	if err != nil {
@@ -527,6 +990,9 @@ func startOperator(ctx context.Context) error {
 		cfg.QPS = qps
 		cfg.Burst = int(qps * 2)
 	}
+	if burst := viper.GetInt(operator.KubeClientBurst); burst > 0 {
+		cfg.Burst = burst
+	}
 
 	// set up APM  tracing if configured
 	var tracer *apm.Tracer
@@ -541,7 +1007,29 @@ func startOperator(ctx context.Context) error {
 	// set the timeout for API client
 	cfg.Timeout = viper.GetDuration(operator.KubeClientTimeout)
 	// set the timeout for Elasticsearch requests
-	esclient.DefaultESClientTimeout = viper.GetDuration(operator.ElasticsearchClientTimeout)
+	esclient.SetDefaultTimeout(viper.GetDuration(operator.ElasticsearchClientTimeout))
+	// set the pool of connections to Elasticsearch clusters
+	if err := setConnectionPoolSettings(viper.GetViper()); err != nil {
+		log.Error(err, "Invalid Elasticsearch client connection pool settings")
+		return err
+	}
+	// limit the rate of the requests to Elasticsearch clusters
+	if err := setRateLimitSettings(viper.GetViper()); err != nil {
+		log.Error(err, "Invalid Elasticsearch client rate limit settings")
+		return err
+	}
+
+	// set up the audit log of Elasticsearch API calls if configured
+	if auditLogDestination := viper.GetString(operator.ElasticsearchClientAuditLogFlag); auditLogDestination != "" {
+		auditLog, err := logconf.OpenAuditLog(auditLogDestination)
+		if err != nil {
+			log.Error(err, "Failed to open Elasticsearch client audit log")
+			return err
+		}
+		defer auditLog.Close()
+		log.Info("Recording Elasticsearch API calls in audit log", "destination", auditLogDestination)
+		esclient.SetAuditLogger(logconf.NewAuditLogger(auditLog))
+	}
 
 	// Setup Scheme for all resources
 	log.Info("Setting up scheme")
@@ -556,12 +1044,30 @@ func startOperator(ctx context.Context) error {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaderElectionID:           LeaderElectionLeaseName,
 		LeaderElectionNamespace:    operatorNamespace,
-		Logger:                     log.WithName("eck-operator"),
+		// the lease is renewed until in-flight reconciliations are drained, then released so that another replica can
+		// take over without waiting for the lease to expire
+		LeaderElectionReleaseOnCancel: true,
+		Logger:                        log.WithName("eck-operator"),
 	}
 
 	// configure the manager cache based on the number of managed namespaces
 	managedNamespaces := viper.GetStringSlice(operator.NamespacesFlag)
+	namespaceSelector := viper.GetString(operator.NamespaceLabelSelectorFlag)
 	switch {
+	case namespaceSelector != "":
+		if len(managedNamespaces) > 0 {
+			err := fmt.Errorf("%s and %s cannot be combined", operator.NamespaceLabelSelectorFlag, operator.NamespacesFlag)
+			log.Error(err, "Invalid managed namespaces configuration")
+			return err
+		}
+		selector, err := labels.Parse(namespaceSelector)
+		if err != nil {
+			log.Error(err, "Invalid namespace label selector", "selector", namespaceSelector)
+			return err
+		}
+		log.Info("Operator configured to manage namespaces matching a label selector", "selector", namespaceSelector, "operator_namespace", operatorNamespace)
+		// the operator namespace is always managed so that we can work with operator-internal resources
+		opts.NewCache = namespaces.NewCacheFunc(selector, operatorNamespace)
 	case len(managedNamespaces) == 0:
 		log.Info("Operator configured to manage all namespaces")
 	case len(managedNamespaces) == 1 && managedNamespaces[0] == operatorNamespace:
@@ -578,6 +1084,22 @@ func startOperator(ctx context.Context) error {
 	for _, ns := range managedNamespaces {
 		opts.Cache.DefaultNamespaces[ns] = cache.Config{}
 	}
+	if syncPeriod := viper.GetDuration(operator.CacheSyncPeriodFlag); syncPeriod > 0 {
+		opts.Cache.SyncPeriod = &syncPeriod
+	}
+	if viper.GetBool(operator.CacheLabeledResourcesOnlyFlag) {
+		log.Info("Only caching the Secrets, ConfigMaps, Pods and Services created by the operator")
+		k8s.CacheLabeledResourcesOnly(&opts.Cache, operatorNamespace)
+		opts.NewClient = k8s.NewFallbackClientFunc()
+	}
+	metadataOnly := viper.GetBool(operator.MetadataOnlyWatchesFlag)
+	if metadataOnly {
+		log.Info("Only caching the metadata of Pods and Secrets")
+		watches.SetMetadataOnly(true)
+		k8s.ReadMetadataOnlyObjectsUncached(&opts.Client)
+	}
+	// reading only the metadata of Pods and Secrets is served by the cache, whether their full objects are cached or not
+	opts.NewClient = k8s.NewMetadataCachedClientFunc(opts.NewClient, metadataOnly)
 
 	// only expose prometheus metrics if provided a non-zero port
 	metricsPort := viper.GetInt(operator.MetricsPortFlag)
@@ -589,6 +1111,45 @@ func startOperator(ctx context.Context) error {
 		BindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort), // 0 to disable
 	}
 
+	opts.HealthProbeBindAddress = viper.GetString(operator.HealthProbeBindAddressFlag)
+
+	if viper.GetBool(operator.MaintenanceModeFlag) {
+		log.Info("Maintenance mode enabled, resources are not reconciled")
+		common.SetMaintenanceMode(true)
+	}
+	if disabledControllers := viper.GetStringSlice(operator.DisableControllersFlag); len(disabledControllers) > 0 {
+		log.Info("Controllers disabled, their resources are not reconciled", "controllers", disabledControllers)
+		common.SetDisabledControllers(disabledControllers)
+	}
+
+	// let in-flight reconciliations complete on shutdown, see common.NewController
+	shutdownGracePeriod := viper.GetDuration(operator.ShutdownGracePeriodFlag)
+	if err := validateShutdownGracePeriod(shutdownGracePeriod, viper.GetBool(operator.EnableShardingFlag)); err != nil {
+		log.Error(err, "Invalid shutdown grace period")
+		return err
+	}
+	if shutdownGracePeriod > 0 {
+		opts.GracefulShutdownTimeout = ptr.To(shutdownGracePeriod + shutdownMargin)
+	}
+
+	eventsMinSeverity, err := events.ParseSeverity(viper.GetString(operator.EventsMinSeverityFlag))
+	if err != nil {
+		log.Error(err, "Invalid events minimum severity")
+		return err
+	}
+	eventOpts := events.EmissionOptions{
+		DedupWindow:    viper.GetDuration(operator.EventsDedupWindowFlag),
+		RateLimitQPS:   float32(viper.GetFloat64(operator.EventsRateLimitQPSFlag)),
+		RateLimitBurst: viper.GetInt(operator.EventsRateLimitBurstFlag),
+		MinSeverity:    eventsMinSeverity,
+	}
+	if !eventOpts.IsDefault() {
+		log.Info("Configuring event emission", "dedup_window", eventOpts.DedupWindow, "rate_limit_qps", eventOpts.RateLimitQPS,
+			"rate_limit_burst", eventOpts.RateLimitBurst, "min_severity", eventOpts.MinSeverity)
+		// the broadcaster lives as long as the operator process, there is no risk of leaking it
+		opts.EventBroadcaster = events.NewBroadcaster(eventOpts) //nolint:staticcheck
+	}
+
 	webhookPort := viper.GetInt(operator.WebhookPortFlag)
 	webhookCertDir := viper.GetString(operator.WebhookCertDirFlag)
 	opts.WebhookServer = crwebhook.NewServer(crwebhook.Options{
@@ -602,6 +1163,79 @@ func startOperator(ctx context.Context) error {
 		return err
 	}
 
+	// namespaces matching the label selector are managed dynamically by the manager cache
+	var managed namespaces.Managed = namespaces.Static(managedNamespaces)
+	if namespaceSelector != "" {
+		nsCache, ok := mgr.GetCache().(*namespaces.Cache)
+		if !ok {
+			err := fmt.Errorf("unexpected cache type %T for %s", mgr.GetCache(), operator.NamespaceLabelSelectorFlag)
+			log.Error(err, "Failed to set up managed namespaces")
+			return err
+		}
+		managed = nsCache
+	}
+
+	// track the saturation of the controllers workqueues
+	queueMonitor := queue.NewMonitor(viper.GetDuration(operator.ControllerSaturationThresholdFlag))
+	if err := crmetrics.Registry.Register(queueMonitor); err != nil {
+		log.Error(err, "Failed to register controllers queue metrics")
+		return err
+	}
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		log.Error(err, "Failed to set up liveness probe")
+		return err
+	}
+	// a saturated operator keeps serving webhooks and reconciling its share of the resources unless configured otherwise
+	if viper.GetBool(operator.ControllerSaturationReadinessFlag) {
+		if err := mgr.AddReadyzCheck("controllers", queueMonitor.Check); err != nil {
+			log.Error(err, "Failed to set up readiness probe")
+			return err
+		}
+	}
+
+	// distribute the reconciliation of resources between the operator replicas
+	var membership *sharding.Membership
+	if viper.GetBool(operator.EnableShardingFlag) {
+		identity, err := os.Hostname()
+		if err != nil {
+			log.Error(err, "Failed to determine the name of the operator Pod")
+			return err
+		}
+		scope, err := validateShardingScope(viper.GetString(operator.ShardingScopeFlag))
+		if err != nil {
+			log.Error(err, "Invalid sharding scope")
+			return err
+		}
+		membership = sharding.NewMembership(mgr.GetAPIReader(), mgr.GetClient(), operatorNamespace, identity, scope, sharding.DefaultSyncPeriod)
+		if err := mgr.Add(membership); err != nil {
+			log.Error(err, "Failed to set up sharding")
+			return err
+		}
+		log.Info("Sharding enabled", "identity", identity, "scope", scope)
+	}
+
+	// fetch secure settings from external secret stores
+	if providers := viper.GetStringSlice(operator.ExternalSecretProvidersFlag); len(providers) > 0 {
+		prefixes, err := externalSecretPathPrefixes(providers, viper.GetStringMapString(operator.ExternalSecretPathPrefixesFlag))
+		if err != nil {
+			log.Error(err, "Invalid external secret path prefixes")
+			return err
+		}
+		if err := commonv1.SetExternalSecretPathPrefixes(prefixes); err != nil {
+			log.Error(err, "Invalid external secret path prefixes")
+			return err
+		}
+		if err := external.SetProviders(providers); err != nil {
+			log.Error(err, "Failed to set up external secret providers")
+			return err
+		}
+		if err := mgr.Add(external.NewRefresher(mgr.GetClient(), external.DefaultRefreshPeriod)); err != nil {
+			log.Error(err, "Failed to set up the refresh of external secrets")
+			return err
+		}
+		log.Info("External secret providers enabled", "providers", providers, "path_prefixes", prefixes)
+	}
+
 	// Retrieve globally shared CA if any
 	ca, err := readOptionalCA(viper.GetString(operator.CADirFlag))
 	if err != nil {
@@ -610,27 +1244,43 @@ func startOperator(ctx context.Context) error {
 	}
 
 	// Verify cert validity options
-	caCertValidity, caCertRotateBefore, err := validateCertExpirationFlags(operator.CACertValidityFlag, operator.CACertRotateBeforeFlag)
-	if err != nil {
+	if err := setRotationParams(viper.GetViper(), caCertRotation, operator.CACertValidityFlag, operator.CACertRotateBeforeFlag); err != nil {
 		log.Error(err, "Invalid CA certificate rotation parameters")
 		return err
 	}
 
-	log.V(1).Info("Using certificate authority rotation parameters", operator.CACertValidityFlag, caCertValidity, operator.CACertRotateBeforeFlag, caCertRotateBefore)
+	log.V(1).Info("Using certificate authority rotation parameters", operator.CACertValidityFlag, caCertRotation.Get().Validity, operator.CACertRotateBeforeFlag, caCertRotation.Get().RotateBefore)
 
-	certValidity, certRotateBefore, err := validateCertExpirationFlags(operator.CertValidityFlag, operator.CertRotateBeforeFlag)
-	if err != nil {
+	if err := setRotationParams(viper.GetViper(), certRotation, operator.CertValidityFlag, operator.CertRotateBeforeFlag); err != nil {
 		log.Error(err, "Invalid certificate rotation parameters")
 		return err
 	}
 
-	log.V(1).Info("Using certificate rotation parameters", operator.CertValidityFlag, certValidity, operator.CertRotateBeforeFlag, certRotateBefore)
+	log.V(1).Info("Using certificate rotation parameters", operator.CertValidityFlag, certRotation.Get().Validity, operator.CertRotateBeforeFlag, certRotation.Get().RotateBefore)
 
 	ipFamily, err := chooseAndValidateIPFamily(viper.GetString(operator.IPFamilyFlag), net.ToIPFamily(os.Getenv(settings.EnvPodIP)))
 	if err != nil {
 		log.Error(err, "Invalid IP family parameter")
 		return err
 	}
+	ipFamilyPolicy, err := validateIPFamilyPolicy(viper.GetString(operator.IPFamilyPolicyFlag))
+	if err != nil {
+		log.Error(err, "Invalid IP family policy parameter")
+		return err
+	}
+	common.SetServiceIPFamilyPolicy(ipFamilyPolicy)
+
+	localVolumeFailurePolicy, err := validateLocalVolumeFailurePolicy(viper.GetString(operator.LocalVolumeFailurePolicyFlag))
+	if err != nil {
+		log.Error(err, "Invalid local volume failure policy parameter")
+		return err
+	}
+
+	tierStorageClasses, err := validateTierStorageClasses(viper.GetStringMapString(operator.TierStorageClassesFlag))
+	if err != nil {
+		log.Error(err, "Invalid tier storage classes parameter")
+		return err
+	}
 
 	// Setup a client to set the operator uuid config map
 	clientset, err := kubernetes.NewForConfig(cfg)
@@ -660,6 +1310,12 @@ func startOperator(ctx context.Context) error {
 		return err
 	}
 
+	controllerOptions, err := parseControllerOptions()
+	if err != nil {
+		log.Error(err, "Invalid controller options")
+		return err
+	}
+
 	// default hash cache is arbitrarily set to 5 x MaxConcurrentReconcilesFlag
 	hashCacheSize := viper.GetInt(operator.MaxConcurrentReconcilesFlag) * 5
 	if viper.IsSet(operator.PasswordHashCacheSize) {
@@ -672,53 +1328,61 @@ func startOperator(ctx context.Context) error {
 	}
 
 	params := operator.Parameters{
+		BeatAutodiscoverClusterRole:      viper.GetString(operator.BeatAutodiscoverClusterRoleFlag),
+		CacheSyncPeriod:                  cacheSyncPeriod(),
 		Dialer:                           dialer,
 		ElasticsearchObservationInterval: viper.GetDuration(operator.ElasticsearchObservationIntervalFlag),
+		ElasticsearchHealthBatchWindow:   viper.GetDuration(operator.ElasticsearchHealthBatchWindowFlag),
 		ExposedNodeLabels:                exposedNodeLabels,
 		IPFamily:                         ipFamily,
+		LocalVolumeFailurePolicy:         localVolumeFailurePolicy,
 		OperatorNamespace:                operatorNamespace,
 		OperatorInfo:                     operatorInfo,
 		GlobalCA:                         ca,
-		CACertRotation: certificates.RotationParams{
-			Validity:     caCertValidity,
-			RotateBefore: caCertRotateBefore,
-		},
-		CertRotation: certificates.RotationParams{
-			Validity:     certValidity,
-			RotateBefore: certRotateBefore,
-		},
-		PasswordHasher:            passwordHasher,
-		MaxConcurrentReconciles:   viper.GetInt(operator.MaxConcurrentReconcilesFlag),
-		SetDefaultSecurityContext: setDefaultSecurityContext,
-		ValidateStorageClass:      viper.GetBool(operator.ValidateStorageClassFlag),
-		Tracer:                    tracer,
+		CACertRotation:                   caCertRotation,
+		CertRotation:                     certRotation,
+		PasswordHasher:                   passwordHasher,
+		MaxConcurrentReconciles:          viper.GetInt(operator.MaxConcurrentReconcilesFlag),
+		ControllerOptions:                controllerOptions,
+		QueueMonitor:                     queueMonitor,
+		Sharding:                         membership,
+		ReconciliationStalledThreshold:   viper.GetDuration(operator.ReconciliationStalledThresholdFlag),
+		ReconciliationStalledRemediation: viper.GetBool(operator.ReconciliationStalledRemediationFlag),
+		ServiceAccountTokenRotation:      viper.GetDuration(operator.ServiceAccountTokenRotationFlag),
+		SetDefaultSecurityContext:        setDefaultSecurityContext,
+		ShutdownGracePeriod:              shutdownGracePeriod,
+		TierStorageClasses:               tierStorageClasses,
+		ValidateStorageClass:             viper.GetBool(operator.ValidateStorageClassFlag),
+		Tracer:                           tracer,
 	}
 
 	if viper.GetBool(operator.EnableWebhookFlag) {
-		setupWebhook(ctx, mgr, params, webhookCertDir, clientset, exposedNodeLabels, managedNamespaces, tracer)
+		setupWebhook(ctx, mgr, params, webhookCertDir, clientset, exposedNodeLabels, managed, tracer)
 	}
 
-	enforceRbacOnRefs := viper.GetBool(operator.EnforceRBACOnRefsFlag)
-
-	var accessReviewer rbac.AccessReviewer
-	if enforceRbacOnRefs {
-		accessReviewer = rbac.NewSubjectAccessReviewer(clientset)
-	} else {
-		accessReviewer = rbac.NewPermissiveAccessReviewer()
+	if err := registerControllers(mgr, params, newAccessReviewer(mgr.GetClient(), clientset)); err != nil {
+		return err
 	}
 
-	if err := registerControllers(mgr, params, accessReviewer); err != nil {
+	if err := setupManagedClusters(mgr, opts, params, eventOpts, tracer); err != nil {
+		log.Error(err, "Failed to set up managed clusters")
 		return err
 	}
 
 	disableTelemetry := viper.GetBool(operator.DisableTelemetryFlag)
 	telemetryInterval := viper.GetDuration(operator.TelemetryIntervalFlag)
-	go asyncTasks(ctx, mgr, cfg, managedNamespaces, operatorNamespace, operatorInfo, disableTelemetry, telemetryInterval, tracer)
+	go asyncTasks(ctx, mgr, cfg, managed, operatorNamespace, operatorInfo, disableTelemetry, telemetryInterval, tracer)
 
 	log.Info("Starting the manager", "uuid", operatorInfo.OperatorUUID,
 		"namespace", operatorNamespace, "version", operatorInfo.BuildInfo.Version,
 		"build_hash", operatorInfo.BuildInfo.Hash, "build_date", operatorInfo.BuildInfo.Date,
 		"build_snapshot", operatorInfo.BuildInfo.Snapshot)
+	if cryptutil.FIPSEnabled() {
+		log.Info("Using FIPS approved cryptography", "boringcrypto", cryptutil.BoringCryptoEnabled())
+		if !cryptutil.BoringCryptoEnabled() {
+			log.Info("The BoringCrypto module is not in use: the operator must be built with cgo to use FIPS validated cryptographic primitives")
+		}
+	}
 
 	exitOnErr := make(chan error)
 
@@ -754,6 +1418,14 @@ func startOperator(ctx context.Context) error {
 	}
 }
 
+// cacheSyncPeriod returns the period after which all the resources are reconciled again, which defaults to 10 hours.
+func cacheSyncPeriod() time.Duration {
+	if syncPeriod := viper.GetDuration(operator.CacheSyncPeriodFlag); syncPeriod > 0 {
+		return syncPeriod
+	}
+	return 10 * time.Hour
+}
+
 func readOptionalCA(caDir string) (*certificates.CA, error) {
 	if caDir == "" {
 		return nil, nil
@@ -766,7 +1438,7 @@ func asyncTasks(
 	ctx context.Context,
 	mgr manager.Manager,
 	cfg *rest.Config,
-	managedNamespaces []string,
+	managedNamespaces namespaces.Managed,
 	operatorNamespace string,
 	operatorInfo about.OperatorInfo,
 	disableTelemetry bool,
@@ -787,6 +1459,12 @@ func asyncTasks(
 		r.Start(ctx, licensing.ResourceReporterFrequency)
 	}()
 
+	// Start the resources metrics reporter
+	go func() {
+		// all resources are reconciled again at least once per cache sync period
+		metrics.NewResourcesReporter(mgr.GetClient(), 2*cacheSyncPeriod()).Start(ctx, metrics.ResourcesReporterFrequency)
+	}()
+
 	if !disableTelemetry {
 		// Start the telemetry reporter
 		go func() {
@@ -796,9 +1474,9 @@ func asyncTasks(
 	}
 
 	// Garbage collect orphaned secrets leftover from deleted resources while the operator was not running
-	// - association user secrets
+	// - association user secrets, in the namespaces managed once the cache is synced
 	gcCtx := tracing.NewContextTransaction(ctx, tracer, tracing.RunOnceTxType, "garbage-collection", nil)
-	err := garbageCollectUsers(gcCtx, cfg, managedNamespaces)
+	err := garbageCollectUsers(gcCtx, cfg, managedNamespaces.Namespaces())
 	if err != nil {
 		log.Error(err, "exiting due to unrecoverable error")
 		os.Exit(1)
@@ -821,6 +1499,106 @@ func chooseAndValidateIPFamily(ipFamilyStr string, ipFamilyDefault corev1.IPFami
 	}
 }
 
+func validateIPFamilyPolicy(ipFamilyPolicyStr string) (corev1.IPFamilyPolicy, error) {
+	for _, policy := range []corev1.IPFamilyPolicy{"", corev1.IPFamilyPolicySingleStack, corev1.IPFamilyPolicyPreferDualStack, corev1.IPFamilyPolicyRequireDualStack} {
+		if strings.EqualFold(ipFamilyPolicyStr, string(policy)) {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("IP family policy can be one of: SingleStack, PreferDualStack, RequireDualStack or \"\" for the Kubernetes default, but was %s", ipFamilyPolicyStr)
+}
+
+func validateLocalVolumeFailurePolicy(policyStr string) (operator.LocalVolumeFailurePolicy, error) {
+	for _, policy := range []operator.LocalVolumeFailurePolicy{operator.LocalVolumeFailurePolicyWait, operator.LocalVolumeFailurePolicyRecreate} {
+		if strings.EqualFold(policyStr, string(policy)) {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("local volume failure policy can be one of: Wait or Recreate, but was %s", policyStr)
+}
+
+// validateShutdownGracePeriod checks that in-flight reconciliations are interrupted before the leases of their shards
+// can be acquired by another replica, if sharding is enabled. The leader election lease is not a concern as it is
+// renewed until the reconciliations are drained.
+func validateShutdownGracePeriod(gracePeriod time.Duration, shardingEnabled bool) error {
+	if !shardingEnabled {
+		return nil
+	}
+	if deadline := sharding.ShutdownDeadline(sharding.DefaultSyncPeriod); gracePeriod+shutdownMargin >= deadline {
+		return fmt.Errorf("%s must be lower than %s when %s is set, but was %s",
+			operator.ShutdownGracePeriodFlag, deadline-shutdownMargin, operator.EnableShardingFlag, gracePeriod)
+	}
+	return nil
+}
+
+func validateShardingScope(scopeStr string) (sharding.Scope, error) {
+	for _, scope := range []sharding.Scope{sharding.ResourceScope, sharding.NamespaceScope} {
+		if strings.EqualFold(scopeStr, string(scope)) {
+			return scope, nil
+		}
+	}
+	return "", fmt.Errorf("sharding scope can be one of: resource or namespace, but was %s", scopeStr)
+}
+
+func validateTierStorageClasses(tierStorageClasses map[string]string) (map[esv1.NodeRole]string, error) {
+	tierRoles := []esv1.NodeRole{esv1.DataHotRole, esv1.DataContentRole, esv1.DataWarmRole, esv1.DataColdRole, esv1.DataFrozenRole}
+	result := make(map[esv1.NodeRole]string, len(tierStorageClasses))
+	for role, storageClass := range tierStorageClasses {
+		if !slices.Contains(tierRoles, esv1.NodeRole(role)) {
+			return nil, fmt.Errorf("tier storage classes can only be set for the data_hot, data_content, data_warm, data_cold and data_frozen roles, but got %s", role)
+		}
+		if storageClass == "" {
+			return nil, fmt.Errorf("storage class of the %s role cannot be empty", role)
+		}
+		result[esv1.NodeRole(role)] = storageClass
+	}
+	return result, nil
+}
+
+// externalSecretPathPrefixes returns the path prefixes of the given external secret providers, each of which must have one.
+func externalSecretPathPrefixes(providers []string, prefixes map[string]string) (map[commonv1.ExternalSecretProvider]string, error) {
+	result := make(map[commonv1.ExternalSecretProvider]string, len(providers))
+	for _, provider := range providers {
+		prefix, ok := prefixes[provider]
+		if !ok {
+			return nil, fmt.Errorf("%s must set the path prefix of external secret provider %s", operator.ExternalSecretPathPrefixesFlag, provider)
+		}
+		result[commonv1.ExternalSecretProvider(provider)] = prefix
+	}
+	for provider := range prefixes {
+		if !slices.Contains(providers, provider) {
+			return nil, fmt.Errorf("external secret provider %s has a path prefix but is not enabled in %s", provider, operator.ExternalSecretProvidersFlag)
+		}
+	}
+	return result, nil
+}
+
+func validateImageMappings(imageMappings map[string]string) (map[string]string, error) {
+	result := make(map[string]string, len(imageMappings))
+	for key, image := range imageMappings {
+		if image == "" {
+			return nil, fmt.Errorf("image mapped to %s cannot be empty", key)
+		}
+		name, ver, hasVersion := strings.Cut(key, ":")
+		if name == "" {
+			return nil, fmt.Errorf("image mapping %s must start with an image name", key)
+		}
+		if !hasVersion {
+			if strings.ContainsAny(image[strings.LastIndex(image, "/")+1:], ":@") {
+				return nil, fmt.Errorf("image %s mapped to %s must be a repository without tag or digest", image, key)
+			}
+			result[name] = image
+			continue
+		}
+		v, err := version.Parse(ver)
+		if err != nil {
+			return nil, fmt.Errorf("image mapping %s has an invalid version: %w", key, err)
+		}
+		result[fmt.Sprintf("%s:%s", name, v)] = image
+	}
+	return result, nil
+}
+
 // determineSetDefaultSecurityContext determines what settings we need to use for security context by using the following rules:
 //  1. If the setDefaultSecurityContext is explicitly set to either true, or false, use this value.
 //  2. use OpenShift detection to determine whether or not we are running within an OpenShift cluster.
@@ -886,6 +1664,22 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 		{name: "Maps", registerFunc: maps.Add},
 		{name: "StackConfigPolicy", registerFunc: stackconfigpolicy.Add},
 		{name: "Logstash", registerFunc: logstash.Add},
+		{name: "SnapshotRepository", registerFunc: snapshotrepository.Add},
+		{name: "SnapshotLifecyclePolicy", registerFunc: snapshotlifecyclepolicy.Add},
+		{name: "RemoteClusterLink", registerFunc: remoteclusterlink.Add},
+		{name: "ElasticsearchRestore", registerFunc: elasticsearchrestore.Add},
+		{name: "ILMPolicy", registerFunc: ilmpolicy.Add},
+		{name: "ElasticsearchUser", registerFunc: elasticsearchuser.Add},
+		{name: "ElasticsearchRole", registerFunc: elasticsearchrole.Add},
+		{name: "ElasticsearchRoleMapping", registerFunc: elasticsearchrolemapping.Add},
+		{name: "IndexTemplate", registerFunc: indextemplate.Add},
+		{name: "ElasticsearchIndex", registerFunc: elasticsearchindex.Add},
+		{name: "IngestPipeline", registerFunc: ingestpipeline.Add},
+		{name: "AlertingRule", registerFunc: alertingrule.Add},
+		{name: "Transform", registerFunc: transform.Add},
+		{name: "DataView", registerFunc: dataview.Add},
+		{name: "KibanaSpace", registerFunc: kibanaspace.Add},
+		{name: "FleetPolicy", registerFunc: fleetpolicy.Add},
 	}
 
 	for _, c := range controllers {
@@ -928,9 +1722,105 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 	return nil
 }
 
-func validateCertExpirationFlags(validityFlag string, rotateBeforeFlag string) (time.Duration, time.Duration, error) {
-	certValidity := viper.GetDuration(validityFlag)
-	certRotateBefore := viper.GetDuration(rotateBeforeFlag)
+// newAccessReviewer returns the access reviewer checking whether the resources can reference each other. When RBAC is
+// enforced, the references allowed by an AssociationPolicy do not require any RBAC permission.
+func newAccessReviewer(k8sClient k8s.Client, clientset kubernetes.Interface) rbac.AccessReviewer {
+	if viper.GetBool(operator.EnforceRBACOnRefsFlag) {
+		return rbac.NewAssociationPolicyReviewer(k8sClient, rbac.NewSubjectAccessReviewer(clientset))
+	}
+	return rbac.NewPermissiveAccessReviewer()
+}
+
+// setupManagedClusters creates a manager running the controllers of the operator for each remote cluster listed in
+// the configuration file, and adds it to the manager of the cluster the operator runs in. The remote managers share
+// the options of the local one, except for the endpoints served by the operator and the client rate limits.
+func setupManagedClusters(mgr manager.Manager, opts ctrl.Options, params operator.Parameters, eventOpts events.EmissionOptions, tracer *apm.Tracer) error {
+	var clusters map[string]operator.ManagedCluster
+	if err := viper.UnmarshalKey(operator.ManagedClustersConfigKey, &clusters); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", operator.ManagedClustersConfigKey, err)
+	}
+	if len(clusters) == 0 {
+		return nil
+	}
+
+	status := multicluster.NewStatus()
+	if err := crmetrics.Registry.Register(status); err != nil {
+		return fmt.Errorf("failed to register managed clusters metrics: %w", err)
+	}
+	if err := mgr.AddReadyzCheck("managed-clusters", status.Check); err != nil {
+		return fmt.Errorf("failed to set up managed clusters readiness check: %w", err)
+	}
+
+	for name, c := range clusters {
+		if err := operator.ValidateManagedCluster(name, c); err != nil {
+			return fmt.Errorf("invalid %s for %s: %w", operator.ManagedClustersConfigKey, name, err)
+		}
+		cfg, err := multicluster.RestConfig(c, float32(viper.GetFloat64(operator.KubeClientQPS)), viper.GetInt(operator.KubeClientBurst))
+		if err != nil {
+			return err
+		}
+		cfg.Timeout = viper.GetDuration(operator.KubeClientTimeout)
+		if tracer != nil {
+			cfg.Wrap(tracing.ClientGoTransportWrapper(
+				apmclientgo.WithDefaultTransaction(tracing.ClientGoCacheTx(tracer)),
+			))
+		}
+
+		clusterOpts := opts
+		// leader election, metrics and probes are handled by the manager of the cluster the operator runs in
+		clusterOpts.LeaderElection = false
+		clusterOpts.Metrics = metricsserver.Options{BindAddress: "0"}
+		clusterOpts.HealthProbeBindAddress = "0"
+		clusterOpts.WebhookServer = nil
+		clusterOpts.Logger = opts.Logger.WithValues("cluster", name)
+		if opts.EventBroadcaster != nil {
+			// events are recorded in the cluster of the resources they relate to
+			clusterOpts.EventBroadcaster = events.NewBroadcaster(eventOpts) //nolint:staticcheck
+		}
+		clusterMgr, err := ctrl.NewManager(cfg, clusterOpts)
+		if err != nil {
+			return fmt.Errorf("failed to create controller manager for cluster %s: %w", name, err)
+		}
+		clientset, err := kubernetes.NewForConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create Kubernetes client for cluster %s: %w", name, err)
+		}
+
+		clusterParams := params
+		clusterParams.ClusterName = name
+		// the DNS names of the remote Services resolve to the local ones, connect to the remote Pods instead
+		clusterParams.Dialer = multicluster.NewDialer(name, clusterMgr.GetClient())
+		if err := registerControllers(clusterMgr, clusterParams, newAccessReviewer(clusterMgr.GetClient(), clientset)); err != nil {
+			return fmt.Errorf("failed to register controllers for cluster %s: %w", name, err)
+		}
+		// with sharding enabled the resources of the remote clusters are distributed between all the replicas as well
+		needLeaderElection := opts.LeaderElection && params.Sharding == nil
+		if err := mgr.Add(multicluster.NewCluster(name, clusterMgr, needLeaderElection, status)); err != nil {
+			return err
+		}
+		log.Info("Managing remote cluster", "cluster", name, "host", cfg.Host, "qps", cfg.QPS, "burst", cfg.Burst)
+	}
+	return nil
+}
+
+// parseControllerOptions reads the per controller settings from the configuration file.
+func parseControllerOptions() (map[string]operator.ControllerOptions, error) {
+	var options map[string]operator.ControllerOptions
+	if err := viper.UnmarshalKey(operator.ControllerOptionsConfigKey, &options); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", operator.ControllerOptionsConfigKey, err)
+	}
+	for name, o := range options {
+		if err := o.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s for %s: %w", operator.ControllerOptionsConfigKey, name, err)
+		}
+		log.Info("Using controller specific options", "controller", name, "options", o)
+	}
+	return options, nil
+}
+
+func validateCertExpirationFlags(v *viper.Viper, validityFlag string, rotateBeforeFlag string) (time.Duration, time.Duration, error) {
+	certValidity := v.GetDuration(validityFlag)
+	certRotateBefore := v.GetDuration(rotateBeforeFlag)
 
 	if certRotateBefore > certValidity {
 		return certValidity, certRotateBefore, fmt.Errorf("%s must be larger than %s", validityFlag, rotateBeforeFlag)
@@ -990,11 +1880,11 @@ func setupWebhook(
 	webhookCertDir string,
 	clientset kubernetes.Interface,
 	exposedNodeLabels esvalidation.NodeLabels,
-	managedNamespaces []string,
+	managedNamespaces namespaces.Managed,
 	tracer *apm.Tracer) {
 	manageWebhookCerts := viper.GetBool(operator.ManageWebhookCertsFlag)
 	if manageWebhookCerts {
-		if err := reconcileWebhookCertsAndAddController(ctx, mgr, params.CertRotation, clientset, tracer); err != nil {
+		if err := reconcileWebhookCertsAndAddController(ctx, mgr, params.CertRotation.Get(), clientset, tracer); err != nil {
 			log.Error(err, "unable to setup the webhook certificates")
 			os.Exit(1)
 		}
@@ -1018,14 +1908,30 @@ func setupWebhook(
 		&kbv1beta1.Kibana{},
 		&emsv1alpha1.ElasticMapsServer{},
 		&policyv1alpha1.StackConfigPolicy{},
+		&snapshotv1alpha1.SnapshotRepository{},
+		&snapshotv1alpha1.SnapshotLifecyclePolicy{},
+		&snapshotv1alpha1.ElasticsearchRestore{},
+		&remoteclusterv1alpha1.RemoteClusterLink{},
+		&ilmv1alpha1.ILMPolicy{},
+		&securityv1alpha1.ElasticsearchUser{},
+		&securityv1alpha1.ElasticsearchRole{},
+		&securityv1alpha1.ElasticsearchRoleMapping{},
+		&indexv1alpha1.IndexTemplate{},
+		&indexv1alpha1.ElasticsearchIndex{},
+		&ingestv1alpha1.IngestPipeline{},
+		&alertingv1alpha1.AlertingRule{},
+		&transformv1alpha1.Transform{},
+		&dataviewv1alpha1.DataView{},
+		&kibanaspacev1alpha1.KibanaSpace{},
+		&fleetv1alpha1.FleetPolicy{},
 	}
 	for _, obj := range webhookObjects {
 		if err := commonwebhook.SetupValidatingWebhookWithConfig(&commonwebhook.Config{
-			Manager:          mgr,
-			WebhookPath:      obj.WebhookPath(),
-			ManagedNamespace: managedNamespaces,
-			Validator:        obj,
-			LicenseChecker:   checker,
+			Manager:           mgr,
+			WebhookPath:       obj.WebhookPath(),
+			ManagedNamespaces: managedNamespaces,
+			Validator:         obj,
+			LicenseChecker:    checker,
 		}); err != nil {
 			gvk := obj.GetObjectKind().GroupVersionKind()
 			log.Error(err, "Failed to setup webhook", "group", gvk.Group, "version", gvk.Version, "kind", gvk.Kind)
@@ -7,8 +7,12 @@ package manager
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -22,10 +26,16 @@ import (
 
 	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/sharding"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -337,3 +347,215 @@ func newFakeK8sClientsetWithDiscovery(resources []*metav1.APIResourceList, disco
 	}
 	return client
 }
+
+func Test_reloadConfig(t *testing.T) {
+	defer viper.Reset()
+	defer esclient.SetDefaultTimeout(esclient.DefaultESClientTimeout)
+	previousConfigFile := configFile
+	defer func() { configFile = previousConfigFile }()
+
+	flags := Command().Flags()
+	configFile = filepath.Join(t.TempDir(), "eck.yaml")
+	writeConfig := func(content string) {
+		require.NoError(t, os.WriteFile(configFile, []byte(content), 0o600))
+	}
+	writeConfig("cert-validity: 100h\ncert-rotate-before: 10h\n")
+	require.NoError(t, loadConfig(viper.GetViper(), flags))
+	require.NoError(t, setRotationParams(viper.GetViper(), certRotation, "cert-validity", "cert-rotate-before"))
+
+	// unchanged configuration
+	require.True(t, reloadConfig(flags))
+
+	// reloadable settings are applied in place
+	writeConfig("cert-validity: 50h\ncert-rotate-before: 5h\nelasticsearch-client-timeout: 10s\n")
+	require.True(t, reloadConfig(flags))
+	require.Equal(t, certificates.RotationParams{Validity: 50 * time.Hour, RotateBefore: 5 * time.Hour}, certRotation.Get())
+	require.Equal(t, 10*time.Second, esclient.DefaultTimeout())
+	require.Equal(t, 50*time.Hour, viper.GetDuration("cert-validity"))
+
+	// invalid settings are not applied
+	writeConfig("cert-validity: 1h\ncert-rotate-before: 5h\nelasticsearch-client-timeout: 10s\n")
+	require.False(t, reloadConfig(flags))
+	require.Equal(t, certificates.RotationParams{Validity: 50 * time.Hour, RotateBefore: 5 * time.Hour}, certRotation.Get())
+
+	// maintenance mode and disabled controllers are applied in place
+	defer common.SetMaintenanceMode(false)
+	defer common.SetDisabledControllers(nil)
+	writeConfig("cert-validity: 50h\ncert-rotate-before: 5h\nelasticsearch-client-timeout: 10s\nmaintenance-mode: true\ndisable-controllers: [kibana-controller]\n")
+	require.True(t, reloadConfig(flags))
+	require.True(t, common.IsMaintenanceMode())
+	require.True(t, common.IsControllerDisabled("kibana-controller"))
+	require.False(t, common.IsControllerDisabled("elasticsearch-controller"))
+	writeConfig("cert-validity: 50h\ncert-rotate-before: 5h\nelasticsearch-client-timeout: 10s\n")
+	require.True(t, reloadConfig(flags))
+	require.False(t, common.IsMaintenanceMode())
+	require.False(t, common.IsControllerDisabled("kibana-controller"))
+
+	// other settings require a restart
+	writeConfig("cert-validity: 50h\ncert-rotate-before: 5h\nelasticsearch-client-timeout: 10s\nnamespaces: ns1\n")
+	require.False(t, reloadConfig(flags))
+}
+
+func Test_loadConfig_profile(t *testing.T) {
+	previousConfigFile := configFile
+	defer func() { configFile = previousConfigFile }()
+	flags := Command().Flags()
+	configFile = filepath.Join(t.TempDir(), "eck.yaml")
+	load := func(content string) (*viper.Viper, error) {
+		require.NoError(t, os.WriteFile(configFile, []byte(content), 0o600))
+		v := viper.New()
+		return v, loadConfig(v, flags)
+	}
+
+	// flag defaults apply with the default profile
+	v, err := load("log-verbosity: 1\n")
+	require.NoError(t, err)
+	require.Equal(t, 0, v.GetInt(operator.KubeClientQPS))
+	require.Equal(t, 10*time.Second, v.GetDuration(operator.ElasticsearchObservationIntervalFlag))
+
+	// the profile settings apply unless explicitly configured
+	v, err = load("profile: scale\nkube-client-qps: 10\n")
+	require.NoError(t, err)
+	require.Equal(t, 10, v.GetInt(operator.KubeClientQPS))
+	require.Equal(t, 100, v.GetInt(operator.KubeClientBurst))
+	require.Equal(t, 30*time.Second, v.GetDuration(operator.ElasticsearchObservationIntervalFlag))
+	require.Equal(t, 24*time.Hour, v.GetDuration(operator.CacheSyncPeriodFlag))
+
+	_, err = load("profile: unknown\n")
+	require.Error(t, err)
+}
+
+func Test_setConnectionPoolSettings(t *testing.T) {
+	defer esclient.SetConnectionPoolSettings(esclient.DefaultConnectionPoolSettings)
+	v := viper.New()
+	v.Set(operator.ElasticsearchClientMaxIdleConnsPerHost, 8)
+	v.Set(operator.ElasticsearchClientMaxConnsPerHost, 16)
+	v.Set(operator.ElasticsearchClientIdleConnTimeout, "2m")
+	require.NoError(t, setConnectionPoolSettings(v))
+
+	v.Set(operator.ElasticsearchClientMaxConnsPerHost, -1)
+	require.Error(t, setConnectionPoolSettings(v))
+}
+
+func Test_setRateLimitSettings(t *testing.T) {
+	defer esclient.SetRateLimitSettings(esclient.RateLimitSettings{})
+	v := viper.New()
+	v.Set(operator.ElasticsearchClientQPS, 5)
+	v.Set(operator.ElasticsearchClientGlobalQPS, 50)
+	v.Set(operator.ElasticsearchClientGlobalBurst, 100)
+	require.NoError(t, setRateLimitSettings(v))
+
+	v.Set(operator.ElasticsearchClientBurst, -1)
+	require.Error(t, setRateLimitSettings(v))
+}
+
+func Test_changedSettings(t *testing.T) {
+	current := map[string]interface{}{"a": "1", "b": map[string]interface{}{"c": "2"}, "d": "3"}
+	updated := map[string]interface{}{"a": "1", "b": map[string]interface{}{"c": "4"}, "e": "5"}
+	require.Equal(t, map[string]bool{"b": true, "d": true, "e": true}, changedSettings(current, updated))
+	require.Empty(t, changedSettings(current, current))
+}
+
+func Test_validateIPFamilyPolicy(t *testing.T) {
+	for value, want := range map[string]corev1.IPFamilyPolicy{
+		"":                 "",
+		"SingleStack":      corev1.IPFamilyPolicySingleStack,
+		"preferdualstack":  corev1.IPFamilyPolicyPreferDualStack,
+		"RequireDualStack": corev1.IPFamilyPolicyRequireDualStack,
+	} {
+		got, err := validateIPFamilyPolicy(value)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	_, err := validateIPFamilyPolicy("DualStack")
+	require.Error(t, err)
+}
+
+func Test_validateLocalVolumeFailurePolicy(t *testing.T) {
+	for value, want := range map[string]operator.LocalVolumeFailurePolicy{
+		"Wait":     operator.LocalVolumeFailurePolicyWait,
+		"recreate": operator.LocalVolumeFailurePolicyRecreate,
+	} {
+		got, err := validateLocalVolumeFailurePolicy(value)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	_, err := validateLocalVolumeFailurePolicy("")
+	require.Error(t, err)
+}
+
+func Test_validateShutdownGracePeriod(t *testing.T) {
+	require.NoError(t, validateShutdownGracePeriod(0, true))
+	require.NoError(t, validateShutdownGracePeriod(DefaultShutdownGracePeriod, true))
+	require.NoError(t, validateShutdownGracePeriod(time.Minute, false))
+	// the shard leases of in-flight reconciliations could expire during the shutdown
+	require.Error(t, validateShutdownGracePeriod(time.Minute, true))
+}
+
+func Test_validateShardingScope(t *testing.T) {
+	for value, want := range map[string]sharding.Scope{
+		"resource":  sharding.ResourceScope,
+		"Namespace": sharding.NamespaceScope,
+	} {
+		got, err := validateShardingScope(value)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	_, err := validateShardingScope("cluster")
+	require.Error(t, err)
+}
+
+func Test_validateTierStorageClasses(t *testing.T) {
+	got, err := validateTierStorageClasses(map[string]string{"data_hot": "nvme", "data_frozen": "cheap"})
+	require.NoError(t, err)
+	require.Equal(t, map[esv1.NodeRole]string{esv1.DataHotRole: "nvme", esv1.DataFrozenRole: "cheap"}, got)
+
+	_, err = validateTierStorageClasses(map[string]string{"master": "nvme"})
+	require.Error(t, err)
+	_, err = validateTierStorageClasses(map[string]string{"data_warm": ""})
+	require.Error(t, err)
+}
+
+func Test_externalSecretPathPrefixes(t *testing.T) {
+	got, err := externalSecretPathPrefixes([]string{"vault", "gcp"}, map[string]string{
+		"vault": "secret/data/eck/{namespace}/",
+		"gcp":   "projects/p/secrets/{namespace}_",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[commonv1.ExternalSecretProvider]string{
+		commonv1.VaultSecretProvider: "secret/data/eck/{namespace}/",
+		commonv1.GCPSecretProvider:   "projects/p/secrets/{namespace}_",
+	}, got)
+
+	_, err = externalSecretPathPrefixes([]string{"vault", "aws"}, map[string]string{"vault": "secret/data/eck/{namespace}/"})
+	require.EqualError(t, err, "external-secret-path-prefixes must set the path prefix of external secret provider aws")
+	_, err = externalSecretPathPrefixes([]string{"vault"}, map[string]string{"vault": "secret/data/eck/{namespace}/", "aws": "eck/{namespace}/"})
+	require.EqualError(t, err, "external secret provider aws has a path prefix but is not enabled in external-secret-providers")
+}
+
+func Test_validateImageMappings(t *testing.T) {
+	got, err := validateImageMappings(map[string]string{
+		"elasticsearch":  "mirror.example.com:5000/elastic/elasticsearch",
+		"kibana:8.15.0":  "mirror.example.com/elastic/kibana@sha256:0123456789abcdef",
+		"elastic-agent:": "",
+	})
+	require.Error(t, err)
+	require.Nil(t, got)
+
+	got, err = validateImageMappings(map[string]string{
+		"elasticsearch": "mirror.example.com:5000/elastic/elasticsearch",
+		"kibana:8.15.0": "mirror.example.com/elastic/kibana@sha256:0123456789abcdef",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"elasticsearch": "mirror.example.com:5000/elastic/elasticsearch",
+		"kibana:8.15.0": "mirror.example.com/elastic/kibana@sha256:0123456789abcdef",
+	}, got)
+
+	_, err = validateImageMappings(map[string]string{"elasticsearch": "mirror.example.com/elastic/elasticsearch:8.15.0"})
+	require.Error(t, err)
+	_, err = validateImageMappings(map[string]string{"kibana:not-a-version": "mirror.example.com/elastic/kibana:8.15.0"})
+	require.Error(t, err)
+	_, err = validateImageMappings(map[string]string{":8.15.0": "mirror.example.com/elastic/kibana:8.15.0"})
+	require.Error(t, err)
+}
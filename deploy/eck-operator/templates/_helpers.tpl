@@ -157,6 +157,19 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - get
   - watch
   - update
+{{- if .Values.config.enableSharding }}
+- apiGroups:
+  - coordination.k8s.io
+  resources:
+  - leases
+  resourceNames:
+  {{- range until 32 }}
+  - elastic-operator-shard-{{ . }}
+  {{- end }}
+  verbs:
+  - get
+  - update
+{{- end }}
 - apiGroups:
   - ""
   resources:
@@ -174,6 +187,7 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - secrets
   - services
   - configmaps
+  - serviceaccounts
   verbs:
   - get
   - list
@@ -182,6 +196,18 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - update
   - patch
   - delete
+- apiGroups:
+  - ""
+  resources:
+  - pods/exec
+  verbs:
+  - create
+- apiGroups:
+  - ""
+  resources:
+  - pods/log
+  verbs:
+  - get
 - apiGroups:
   - apps
   resources:
@@ -208,6 +234,52 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - update
   - patch
   - delete
+- apiGroups:
+  - networking.k8s.io
+  resources:
+  - ingresses
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - gateway.networking.k8s.io
+  resources:
+  - httproutes
+  - tlsroutes
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - monitoring.coreos.com
+  resources:
+  - servicemonitors
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - snapshot.storage.k8s.io
+  resources:
+  - volumesnapshots
+  verbs:
+  - get
+  - list
+  - create
+  - delete
 - apiGroups:
   - elasticsearch.k8s.elastic.co
   resources:
@@ -338,6 +410,172 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - create
   - update
   - patch
+- apiGroups:
+  - snapshot.k8s.elastic.co
+  resources:
+  - snapshotrepositories
+  - snapshotrepositories/status
+  - snapshotrepositories/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  - snapshotlifecyclepolicies
+  - snapshotlifecyclepolicies/status
+  - snapshotlifecyclepolicies/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  - elasticsearchrestores
+  - elasticsearchrestores/status
+  - elasticsearchrestores/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+- apiGroups:
+  - ilm.k8s.elastic.co
+  resources:
+  - ilmpolicies
+  - ilmpolicies/status
+  - ilmpolicies/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+- apiGroups:
+  - security.k8s.elastic.co
+  resources:
+  - elasticsearchusers
+  - elasticsearchusers/status
+  - elasticsearchusers/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  - elasticsearchroles
+  - elasticsearchroles/status
+  - elasticsearchroles/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  - elasticsearchrolemappings
+  - elasticsearchrolemappings/status
+  - elasticsearchrolemappings/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+- apiGroups:
+  - index.k8s.elastic.co
+  resources:
+  - indextemplates
+  - indextemplates/status
+  - indextemplates/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  - elasticsearchindices
+  - elasticsearchindices/status
+  - elasticsearchindices/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+- apiGroups:
+  - ingest.k8s.elastic.co
+  resources:
+  - ingestpipelines
+  - ingestpipelines/status
+  - ingestpipelines/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+- apiGroups:
+  - alerting.k8s.elastic.co
+  resources:
+  - alertingrules
+  - alertingrules/status
+  - alertingrules/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+- apiGroups:
+  - transform.k8s.elastic.co
+  resources:
+  - transforms
+  - transforms/status
+  - transforms/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+- apiGroups:
+  - dataview.k8s.elastic.co
+  resources:
+  - dataviews
+  - dataviews/status
+  - dataviews/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+- apiGroups:
+  - remotecluster.k8s.elastic.co
+  resources:
+  - remoteclusterlinks
+  - remoteclusterlinks/status
+  - remoteclusterlinks/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+- apiGroups:
+  - kibanaspace.k8s.elastic.co
+  resources:
+  - kibanaspaces
+  - kibanaspaces/status
+  - kibanaspaces/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+- apiGroups:
+  - fleet.k8s.elastic.co
+  resources:
+  - fleetpolicies
+  - fleetpolicies/status
+  - fleetpolicies/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+- apiGroups:
+  - association.k8s.elastic.co
+  resources:
+  - associationpolicies
+  verbs:
+  - get
+  - list
+  - watch
 {{- end -}}
 
 {{/*
@@ -364,6 +602,16 @@ RBAC permissions on non-namespaced resources
   - update
   - patch
   - delete
+- apiGroups:
+  - certificates.k8s.io
+  resources:
+  - certificatesigningrequests
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - delete
 {{- end -}}
 
 {{/*
@@ -379,3 +627,51 @@ RBAC permissions to read node labels
   - list
   - watch
 {{- end -}}
+
+{{/*
+RBAC permissions to detect local volumes lost with their node
+*/}}
+{{- define "eck-operator.readLocalVolumesRbacRule" -}}
+- apiGroups:
+  - ""
+  resources:
+  - nodes
+  - persistentvolumes
+  verbs:
+  - get
+  - list
+  - watch
+{{- end -}}
+
+{{/*
+Name of the ClusterRole bound to the ServiceAccount of the Beats using autodiscover
+*/}}
+{{- define "eck-operator.beatAutodiscoverClusterRole" -}}
+{{ printf "%s-beat-autodiscover" (include "eck-operator.fullname" .) | trunc 63 }}
+{{- end -}}
+
+{{/*
+RBAC permissions to bind the ClusterRole of Beats autodiscover to the ServiceAccount of the Beats
+*/}}
+{{- define "eck-operator.bindBeatAutodiscoverRbacRule" -}}
+- apiGroups:
+  - rbac.authorization.k8s.io
+  resources:
+  - clusterrolebindings
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - rbac.authorization.k8s.io
+  resources:
+  - clusterroles
+  resourceNames:
+  - {{ include "eck-operator.beatAutodiscoverClusterRole" . }}
+  verbs:
+  - bind
+{{- end -}}
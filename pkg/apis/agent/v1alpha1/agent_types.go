@@ -54,8 +54,8 @@ type AgentSpec struct {
 	ConfigRef *commonv1.ConfigSource `json:"configRef,omitempty"`
 
 	// SecureSettings is a list of references to Kubernetes Secrets containing sensitive configuration options for the Agent.
-	// Secrets data can be then referenced in the Agent config using the Secret's keys or as specified in `Entries` field of
-	// each SecureSetting.
+	// Secrets data is exposed to the Agent as environment variables named after the Secret's keys or as specified in `Entries`
+	// field of each SecureSetting, which can be referenced in the Agent config.
 	// +kubebuilder:validation:Optional
 	SecureSettings []commonv1.SecretSource `json:"secureSettings,omitempty"`
 
@@ -122,6 +122,11 @@ type DaemonSetSpec struct {
 	PodTemplate corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
 	// +kubebuilder:validation:Optional
 	UpdateStrategy appsv1.DaemonSetUpdateStrategy `json:"updateStrategy,omitempty"`
+	// Variants replace the DaemonSet by one DaemonSet per variant, each deployed on the nodes selected by the variant
+	// from the same Pod template. This covers heterogeneous clusters, for example nodes of different architectures or
+	// tainted nodes, without duplicating the resource. The node selectors of the variants must not overlap.
+	// +kubebuilder:validation:Optional
+	Variants []commonv1.DaemonSetVariant `json:"variants,omitempty"`
 }
 
 type DeploymentSpec struct {
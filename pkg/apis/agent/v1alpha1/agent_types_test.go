@@ -31,6 +31,11 @@ func TestAgentESAssociation_AssociationConfAnnotationName(t *testing.T) {
 				Name:      "elasticsearch1elasticsearch1elastics"},
 			want: "association.k8s.elastic.co/es-conf-3419573237",
 		},
+		{
+			name: "CA secret name gives the same hash",
+			ref:  commonv1.ObjectSelector{Namespace: "namespace1", Name: "elasticsearch1", CASecretName: "proxy-ca"},
+			want: "association.k8s.elastic.co/es-conf-2150608354",
+		},
 		{
 			name: "secret name gives a different hash",
 			ref:  commonv1.ObjectSelector{Namespace: "namespace1", SecretName: "elasticsearch1"},
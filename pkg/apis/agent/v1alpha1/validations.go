@@ -30,6 +30,13 @@ var (
 		checkEmptyConfigForFleetMode,
 		checkFleetServerOnlyInFleetMode,
 		checkHTTPConfigOnlyForFleetServer,
+		checkIngress,
+		checkGatewayRoute,
+		checkServiceMesh,
+		checkCertificateRotation,
+		checkHostnames,
+		checkSecureSettings,
+		checkDaemonSetVariants,
 		checkFleetServerOrFleetServerRef,
 		checkReferenceSetForMode,
 		checkSingleESRefInFleetMode,
@@ -256,6 +263,38 @@ func checkHTTPConfigOnlyForFleetServer(a *Agent) field.ErrorList {
 	return nil
 }
 
+func checkIngress(a *Agent) field.ErrorList {
+	return commonv1.CheckIngress(field.NewPath("spec").Child("http", "ingress"), a.Spec.HTTP)
+}
+
+func checkGatewayRoute(a *Agent) field.ErrorList {
+	return commonv1.CheckGatewayRoute(field.NewPath("spec").Child("http", "gatewayRoute"), a.Spec.HTTP)
+}
+
+func checkServiceMesh(a *Agent) field.ErrorList {
+	return commonv1.CheckServiceMesh(field.NewPath("spec").Child("http", "tls"), a.Spec.HTTP.TLS)
+}
+
+func checkCertificateRotation(a *Agent) field.ErrorList {
+	return commonv1.CheckCertificateRotation(field.NewPath("spec").Child("http", "tls", "certificateRotation"), a.Spec.HTTP.TLS.CertificateRotation)
+}
+
+func checkHostnames(a *Agent) field.ErrorList {
+	return commonv1.CheckHostnames(field.NewPath("spec").Child("http", "hostnames"), a.Spec.HTTP)
+}
+
+func checkDaemonSetVariants(a *Agent) field.ErrorList {
+	if a.Spec.DaemonSet == nil {
+		return nil
+	}
+	return commonv1.CheckDaemonSetVariants(field.NewPath("spec").Child("daemonSet", "variants"), a.Spec.DaemonSet.Variants)
+}
+
+// checkSecureSettings ensures the secure settings reference either Secrets or external secrets.
+func checkSecureSettings(a *Agent) field.ErrorList {
+	return commonv1.CheckSecretSources(field.NewPath("spec").Child("secureSettings"), a.Spec.SecureSettings, a.Namespace, true)
+}
+
 func checkReferenceSetForMode(a *Agent) field.ErrorList {
 	var errors field.ErrorList
 	if a.Spec.StandaloneModeEnabled() {
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -830,3 +831,33 @@ func Test_checkNoDowngrade(t *testing.T) {
 		})
 	}
 }
+
+func Test_checkSecureSettings(t *testing.T) {
+	require.NoError(t, commonv1.SetExternalSecretPathPrefixes(map[commonv1.ExternalSecretProvider]string{commonv1.VaultSecretProvider: "secret/data/{namespace}/"}))
+	t.Cleanup(func() { require.NoError(t, commonv1.SetExternalSecretPathPrefixes(nil)) })
+	tests := []struct {
+		name    string
+		sources []commonv1.SecretSource
+		wantErr bool
+	}{
+		{
+			name:    "Kubernetes Secret",
+			sources: []commonv1.SecretSource{{SecretName: "agent-secure-settings"}},
+		},
+		{
+			name:    "external secret of the namespace",
+			sources: []commonv1.SecretSource{{External: &commonv1.ExternalSecretSource{Provider: commonv1.VaultSecretProvider, Path: "secret/data/ns/agent"}}},
+		},
+		{
+			name:    "external secret of another namespace",
+			sources: []commonv1.SecretSource{{External: &commonv1.ExternalSecretSource{Provider: commonv1.VaultSecretProvider, Path: "secret/data/other/agent"}}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := checkSecureSettings(&Agent{ObjectMeta: metav1.ObjectMeta{Namespace: "ns"}, Spec: AgentSpec{SecureSettings: tc.sources}})
+			assert.Equal(t, tc.wantErr, len(got) > 0)
+		})
+	}
+}
@@ -237,6 +237,13 @@ func (in *DaemonSetSpec) DeepCopyInto(out *DaemonSetSpec) {
 	*out = *in
 	in.PodTemplate.DeepCopyInto(&out.PodTemplate)
 	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make([]v1.DaemonSetVariant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetSpec.
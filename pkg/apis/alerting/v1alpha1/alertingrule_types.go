@@ -0,0 +1,120 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// AlertingRuleKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	AlertingRuleKind = "AlertingRule"
+)
+
+func init() {
+	SchemeBuilder.Register(&AlertingRule{}, &AlertingRuleList{})
+}
+
+// +kubebuilder:object:root=true
+
+// AlertingRule represents a Kibana alerting rule and the connectors its actions use.
+// +kubebuilder:resource:categories=elastic,shortName=kbrule
+// +kubebuilder:printcolumn:name="Kibana",type="string",JSONPath=".spec.kibanaRef.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type AlertingRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AlertingRuleSpec   `json:"spec,omitempty"`
+	Status AlertingRuleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AlertingRuleList contains a list of AlertingRule resources.
+type AlertingRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AlertingRule `json:"items"`
+}
+
+type AlertingRuleSpec struct {
+	// KibanaRef is a reference to the Kibana instance, in the same namespace as the AlertingRule, the rule is configured
+	// on. Kibana must be associated with an Elasticsearch cluster managed by the operator.
+	KibanaRef commonv1.LocalObjectSelector `json:"kibanaRef"`
+
+	// Rule is the definition of the rule, as expected in the body of the Kibana create rule API. The id of an action
+	// can be the name of one of the Connectors, it is replaced by the id of the connector in Kibana.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Rule *commonv1.Config `json:"rule,omitempty"`
+
+	// Connectors are the connectors created in Kibana for the actions of the rule.
+	// +kubebuilder:validation:Optional
+	Connectors []Connector `json:"connectors,omitempty"`
+}
+
+// Connector is a Kibana connector used by the actions of a rule.
+type Connector struct {
+	// Name is the name of the connector in Kibana, unique among the connectors of the rule.
+	Name string `json:"name"`
+
+	// ConnectorTypeID is the type of the connector, for example .slack, .email or .webhook.
+	ConnectorTypeID string `json:"connectorTypeID"`
+
+	// Config is the configuration of the connector, as expected in the config field of the Kibana create connector API.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Config *commonv1.Config `json:"config,omitempty"`
+
+	// SecretName is the name of a Secret, in the same namespace as the AlertingRule, whose keys and values are the
+	// secrets of the connector, such as credentials or webhook URLs.
+	// +kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+type AlertingRuleStatus struct {
+	// Phase is the phase of the AlertingRule.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the rule is not configured yet, or why its configuration failed.
+	Message string `json:"message,omitempty"`
+	// RuleID is the id of the rule in Kibana.
+	RuleID string `json:"ruleID,omitempty"`
+	// ConnectorIDs are the ids of the connectors in Kibana, indexed by connector name.
+	ConnectorIDs map[string]string `json:"connectorIDs,omitempty"`
+	// ConnectorsHash is a hash of the connectors definitions and of the versions of their Secrets last configured in Kibana,
+	// which does not return the secrets of connectors.
+	ConnectorsHash string `json:"connectorsHash,omitempty"`
+	// LastDriftTime is the last time the rule or its connectors were found modified outside of the operator and restored.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this AlertingRule.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// Phase is the phase of an AlertingRule.
+type Phase = commonv1.APIResourcePhase
+
+const (
+	ReadyPhase           = commonv1.APIResourceReadyPhase
+	ApplyingChangesPhase = commonv1.APIResourceApplyingChangesPhase
+	ErrorPhase           = commonv1.APIResourceErrorPhase
+	InvalidPhase         = commonv1.APIResourceInvalidPhase
+)
+
+// IsMarkedForDeletion returns true if the AlertingRule resource is going to be deleted.
+func (r *AlertingRule) IsMarkedForDeletion() bool {
+	return !r.DeletionTimestamp.IsZero()
+}
+
+// IsDegraded returns true when the AlertingRuleStatus is degraded compared to the previous status.
+func (s AlertingRuleStatus) IsDegraded(prev AlertingRuleStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
@@ -0,0 +1,154 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	// alertingRuleWebhookPath is the HTTP path for the AlertingRule validating webhook.
+	alertingRuleWebhookPath = "/validate-alerting-k8s-elastic-co-v1alpha1-alertingrules"
+
+	crossNamespaceRefErrMsg       = "Kibana must be in the same namespace as the resource"
+	serviceNameNotSupportedErrMsg = "a custom service is not supported to reach Kibana"
+	ruleIDNotSupportedErrMsg      = "the id of the rule is set by the operator"
+)
+
+var (
+	alertingRuleGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: AlertingRuleKind}
+	validationLog         = ulog.Log.WithName("alerting-v1alpha1-validation")
+
+	// requiredRuleFields are the fields of the body of the Kibana create rule API without default value.
+	requiredRuleFields = []string{"name", "rule_type_id", "consumer", "schedule"}
+
+	alertingRuleDefaultChecks = []func(*AlertingRule) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		validKibanaRef,
+		validRule,
+		validConnectors,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-alerting-k8s-elastic-co-v1alpha1-alertingrules,mutating=false,failurePolicy=ignore,groups=alerting.k8s.elastic.co,resources=alertingrules,verbs=create;update,versions=v1alpha1,name=elastic-alertingrule-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &AlertingRule{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (r *AlertingRule) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (r *AlertingRule) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", r.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (r *AlertingRule) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", r.Name)
+	if _, ok := old.(*AlertingRule); !ok {
+		return nil, errors.New("cannot cast old object to AlertingRule type")
+	}
+	return r.validate()
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (r *AlertingRule) WebhookPath() string {
+	return alertingRuleWebhookPath
+}
+
+func (r *AlertingRule) validate() (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range alertingRuleDefaultChecks {
+		if err := dc(r); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(alertingRuleGroupKind, r.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkNoUnknownFields(r *AlertingRule) field.ErrorList {
+	return commonv1.NoUnknownFields(r, r.ObjectMeta)
+}
+
+func checkNameLength(r *AlertingRule) field.ErrorList {
+	return commonv1.CheckNameLength(r)
+}
+
+// validKibanaRef validates the reference to Kibana, which must be in the namespace of the rule.
+func validKibanaRef(r *AlertingRule) field.ErrorList {
+	path := field.NewPath("spec").Child("kibanaRef")
+	switch {
+	case r.Spec.KibanaRef.Name == "":
+		return field.ErrorList{field.Required(path.Child("name"), "Kibana name is mandatory")}
+	case r.Spec.KibanaRef.Namespace != "" && r.Spec.KibanaRef.Namespace != r.Namespace:
+		return field.ErrorList{field.Invalid(path.Child("namespace"), r.Spec.KibanaRef.Namespace, crossNamespaceRefErrMsg)}
+	case r.Spec.KibanaRef.ServiceName != "":
+		return field.ErrorList{field.Forbidden(path.Child("serviceName"), serviceNameNotSupportedErrMsg)}
+	}
+	return nil
+}
+
+// validRule checks the fields required by Kibana to create the rule, its content is validated by Kibana.
+func validRule(r *AlertingRule) field.ErrorList {
+	path := field.NewPath("spec").Child("rule")
+	if r.Spec.Rule == nil {
+		return field.ErrorList{field.Required(path, "rule is mandatory")}
+	}
+	var errs field.ErrorList
+	for _, key := range requiredRuleFields {
+		if value, ok := r.Spec.Rule.Data[key]; !ok || value == nil {
+			errs = append(errs, field.Required(path.Child(key), "mandatory to create the rule"))
+		}
+	}
+	if _, exists := r.Spec.Rule.Data["id"]; exists {
+		errs = append(errs, field.Forbidden(path.Child("id"), ruleIDNotSupportedErrMsg))
+	}
+	return errs
+}
+
+// validConnectors checks that the connectors have a distinct name and a type.
+func validConnectors(r *AlertingRule) field.ErrorList {
+	var errs field.ErrorList
+	names := set.Make()
+	for i, connector := range r.Spec.Connectors {
+		path := field.NewPath("spec").Child("connectors").Index(i)
+		switch {
+		case connector.Name == "":
+			errs = append(errs, field.Required(path.Child("name"), "connector name is mandatory"))
+		case names.Has(connector.Name):
+			errs = append(errs, field.Duplicate(path.Child("name"), connector.Name))
+		}
+		names.Add(connector.Name)
+		if connector.ConnectorTypeID == "" {
+			errs = append(errs, field.Required(path.Child("connectorTypeID"), "connector type is mandatory"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,151 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	alertingv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/alerting/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkAlertingRule(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-kibana-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkAlertingRule(uid)
+				r.Spec.KibanaRef = commonv1.LocalObjectSelector{Namespace: "other", Name: "kb"}
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.kibanaRef.namespace: Invalid value: "other": Kibana must be in the same namespace as the resource`,
+			),
+		},
+		{
+			Name:      "no-kibana",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkAlertingRule(uid)
+				r.Spec.KibanaRef = commonv1.LocalObjectSelector{}
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.kibanaRef.name: Required value: Kibana name is mandatory`,
+			),
+		},
+		{
+			Name:      "no-rule",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkAlertingRule(uid)
+				r.Spec.Rule = nil
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.rule: Required value: rule is mandatory`,
+			),
+		},
+		{
+			Name:      "incomplete-rule",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkAlertingRule(uid)
+				r.Spec.Rule = &commonv1.Config{Data: map[string]interface{}{"id": "my-rule", "name": "high-cpu", "consumer": "alerts"}}
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.rule.rule_type_id: Required value: mandatory to create the rule`,
+				`spec.rule.schedule: Required value: mandatory to create the rule`,
+				`spec.rule.id: Forbidden: the id of the rule is set by the operator`,
+			),
+		},
+		{
+			Name:      "invalid-connectors",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkAlertingRule(uid)
+				r.Spec.Connectors = append(r.Spec.Connectors, alertingv1alpha1.Connector{Name: "ops-slack"}, alertingv1alpha1.Connector{ConnectorTypeID: ".email"})
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.connectors\[1\].name: Duplicate value: "ops-slack"`,
+				`spec.connectors\[1\].connectorTypeID: Required value: connector type is mandatory`,
+				`spec.connectors\[2\].name: Required value: connector name is mandatory`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkAlertingRule(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkAlertingRule(uid)
+				r.Spec.Rule.Data["schedule"] = map[string]interface{}{"interval": "5m"}
+				r.Spec.Connectors = nil
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+	}
+
+	validator := &alertingv1alpha1.AlertingRule{}
+	gvk := metav1.GroupVersionKind{Group: alertingv1alpha1.GroupVersion.Group, Version: alertingv1alpha1.GroupVersion.Version, Kind: alertingv1alpha1.AlertingRuleKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkAlertingRule(uid string) *alertingv1alpha1.AlertingRule {
+	return &alertingv1alpha1.AlertingRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "alerting-rule-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: alertingv1alpha1.AlertingRuleSpec{
+			KibanaRef: commonv1.LocalObjectSelector{Name: "kb"},
+			Rule: &commonv1.Config{Data: map[string]interface{}{
+				"name":         "high-cpu",
+				"rule_type_id": ".index-threshold",
+				"consumer":     "alerts",
+				"schedule":     map[string]interface{}{"interval": "1m"},
+			}},
+			Connectors: []alertingv1alpha1.Connector{{Name: "ops-slack", ConnectorTypeID: ".slack", SecretName: "slack-webhook"}},
+		},
+	}
+}
+
+func serialize(t *testing.T, rule *alertingv1alpha1.AlertingRule) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(rule)
+	require.NoError(t, err)
+
+	return objBytes
+}
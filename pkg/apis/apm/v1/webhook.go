@@ -38,6 +38,12 @@ var (
 		checkSupportedVersion,
 		checkAgentConfigurationMinVersion,
 		checkAssociations,
+		checkIngress,
+		checkGatewayRoute,
+		checkServiceMesh,
+		checkCertificateRotation,
+		checkHostnames,
+		checkSecureSettings,
 	}
 
 	updateChecks = []func(old, curr *ApmServer) field.ErrorList{
@@ -152,3 +158,27 @@ func checkAssociations(as *ApmServer) field.ErrorList {
 	err2 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("kibanaRef"), as.Spec.KibanaRef)
 	return append(err1, err2...)
 }
+
+func checkIngress(as *ApmServer) field.ErrorList {
+	return commonv1.CheckIngress(field.NewPath("spec").Child("http", "ingress"), as.Spec.HTTP)
+}
+
+func checkGatewayRoute(as *ApmServer) field.ErrorList {
+	return commonv1.CheckGatewayRoute(field.NewPath("spec").Child("http", "gatewayRoute"), as.Spec.HTTP)
+}
+
+func checkServiceMesh(as *ApmServer) field.ErrorList {
+	return commonv1.CheckServiceMesh(field.NewPath("spec").Child("http", "tls"), as.Spec.HTTP.TLS)
+}
+
+func checkCertificateRotation(as *ApmServer) field.ErrorList {
+	return commonv1.CheckCertificateRotation(field.NewPath("spec").Child("http", "tls", "certificateRotation"), as.Spec.HTTP.TLS.CertificateRotation)
+}
+
+func checkHostnames(as *ApmServer) field.ErrorList {
+	return commonv1.CheckHostnames(field.NewPath("spec").Child("http", "hostnames"), as.Spec.HTTP)
+}
+
+func checkSecureSettings(as *ApmServer) field.ErrorList {
+	return commonv1.CheckSecretSources(field.NewPath("spec").Child("secureSettings"), as.Spec.SecureSettings, as.Namespace, true)
+}
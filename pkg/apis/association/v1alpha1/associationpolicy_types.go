@@ -0,0 +1,112 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	Kind = "AssociationPolicy"
+
+	// DefaultServiceAccountName is the service account of the resources which do not specify any.
+	DefaultServiceAccountName = "default"
+)
+
+func init() {
+	SchemeBuilder.Register(&AssociationPolicy{}, &AssociationPolicyList{})
+}
+
+// +kubebuilder:object:root=true
+
+// AssociationPolicy allows resources in other namespaces to reference resources of its namespace, such as an
+// Elasticsearch cluster, when the operator enforces RBAC on cross-namespace references. The references it allows do not
+// require the referencing resources to be granted the permission to get the referenced resources.
+// +kubebuilder:resource:categories=elastic,shortName=assocpolicy
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
+type AssociationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AssociationPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AssociationPolicyList contains a list of AssociationPolicy resources.
+type AssociationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AssociationPolicy `json:"items"`
+}
+
+// AssociationPolicySpec defines the resources allowed to reference resources of the namespace of the AssociationPolicy.
+type AssociationPolicySpec struct {
+	// From lists the namespaces, and optionally the service accounts, of the resources allowed to reference the resources
+	// selected by To.
+	// +kubebuilder:validation:MinItems=1
+	From []AssociationPolicySubject `json:"from"`
+
+	// To lists the resources of the namespace of the AssociationPolicy that can be referenced. All the resources of the
+	// namespace can be referenced if empty.
+	// +kubebuilder:validation:Optional
+	To []AssociationPolicyTarget `json:"to,omitempty"`
+}
+
+// AssociationPolicySubject selects the resources allowed to reference other resources.
+type AssociationPolicySubject struct {
+	// Namespace of the referencing resources.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// ServiceAccountName restricts the referencing resources to the ones running with this service account, as set in
+	// their spec.serviceAccountName. All the resources of the namespace are allowed if empty.
+	// +kubebuilder:validation:Optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// AssociationPolicyTarget selects resources that can be referenced.
+type AssociationPolicyTarget struct {
+	// Kind of the referenced resources, such as Elasticsearch or Kibana.
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// Name of the referenced resource. All the resources of the kind can be referenced if empty.
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+}
+
+// Allows returns true if the policy allows the resources running with the given service account in the given namespace
+// to reference the resource of the given kind and name, in the namespace of the policy.
+func (p *AssociationPolicy) Allows(serviceAccount, sourceNamespace, kind, name string) bool {
+	if serviceAccount == "" {
+		serviceAccount = DefaultServiceAccountName
+	}
+	return p.Spec.allowsSubject(serviceAccount, sourceNamespace) && p.Spec.allowsTarget(kind, name)
+}
+
+func (s AssociationPolicySpec) allowsSubject(serviceAccount, namespace string) bool {
+	for _, from := range s.From {
+		if from.Namespace == namespace && (from.ServiceAccountName == "" || from.ServiceAccountName == serviceAccount) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s AssociationPolicySpec) allowsTarget(kind, name string) bool {
+	if len(s.To) == 0 {
+		return true
+	}
+	for _, to := range s.To {
+		if to.Kind == kind && (to.Name == "" || to.Name == name) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
@@ -41,13 +42,99 @@ type ElasticsearchAutoscalerSpec struct {
 	// +kubebuilder:validation:Required
 	ElasticsearchRef ElasticsearchRef `json:"elasticsearchRef,omitempty"`
 
-	AutoscalingPolicySpecs v1alpha1.AutoscalingPolicySpecs `json:"policies"`
+	// Mode is the source of the scaling decisions. Deciders, the default, relies on the Elasticsearch autoscaling API and
+	// the autoscaling policies. Metrics scales the number of nodes of NodeSets according to the metric rules.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Deciders;Metrics
+	Mode AutoscalingMode `json:"mode,omitempty"`
+
+	// AutoscalingPolicySpecs are the autoscaling policies used in Deciders mode.
+	// +kubebuilder:validation:Optional
+	AutoscalingPolicySpecs v1alpha1.AutoscalingPolicySpecs `json:"policies,omitempty"`
+
+	// MetricRules scale the number of nodes of NodeSets on statistics returned by the Elasticsearch nodes stats API,
+	// in Metrics mode.
+	// +kubebuilder:validation:Optional
+	MetricRules []MetricScalingRule `json:"metricRules,omitempty"`
 
 	// +kubebuilder:validation:Optional
-	// PollingPeriod is the period at which to synchronize with the Elasticsearch autoscaling API.
+	// PollingPeriod is the period at which to synchronize with the Elasticsearch autoscaling API, or to sample the
+	// metrics in Metrics mode.
 	PollingPeriod *metav1.Duration `json:"pollingPeriod,omitempty"`
 }
 
+// AutoscalingMode is the source of the scaling decisions of an autoscaler.
+type AutoscalingMode string
+
+const (
+	// DecidersMode relies on the deciders of the Elasticsearch autoscaling API.
+	DecidersMode AutoscalingMode = "Deciders"
+	// MetricsMode relies on statistics returned by the Elasticsearch nodes stats API.
+	MetricsMode AutoscalingMode = "Metrics"
+)
+
+// MetricScalingRule scales the number of nodes of a NodeSet to keep the average value of metrics close to a target.
+type MetricScalingRule struct {
+	// NodeSet is the name of the NodeSet to scale.
+	// +kubebuilder:validation:MinLength=1
+	NodeSet string `json:"nodeSet"`
+
+	// NodeCountRange is the minimum and the maximum number of nodes of the NodeSet.
+	NodeCountRange v1alpha1.CountRange `json:"nodeCount"`
+
+	// Metrics are the metrics the number of nodes is computed from. The highest number of nodes required by the
+	// metrics is retained.
+	// +kubebuilder:validation:MinItems=1
+	Metrics []NodeStatsMetric `json:"metrics"`
+}
+
+// NodeStatsMetric is a metric computed from the statistics returned by the Elasticsearch nodes stats API.
+type NodeStatsMetric struct {
+	// Path is the dot-separated path of a numeric value in the statistics of a node,
+	// for example "thread_pool.write.queue" or "thread_pool.write.rejected".
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+
+	// Type is the way the value is computed. Gauge, the default, uses the current value. Rate uses the increase per
+	// second of a counter. Ratio divides the increase of a counter by the increase of the counter at DivisorPath.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Gauge;Rate;Ratio
+	Type MetricType `json:"type,omitempty"`
+
+	// DivisorPath is the path of the counter the increase of the counter at Path is divided by, for Ratio metrics.
+	// For example "indices.search.query_total" to compute the average search latency from "indices.search.query_time_in_millis".
+	// +kubebuilder:validation:Optional
+	DivisorPath string `json:"divisorPath,omitempty"`
+
+	// Target is the average value per node the number of nodes is adjusted to.
+	Target resource.Quantity `json:"target"`
+}
+
+// MetricType is the way the value of a metric is computed.
+type MetricType string
+
+const (
+	// GaugeMetric uses the current value.
+	GaugeMetric MetricType = "Gauge"
+	// RateMetric uses the increase per second of a counter.
+	RateMetric MetricType = "Rate"
+	// RatioMetric divides the increase of a counter by the increase of another counter.
+	RatioMetric MetricType = "Ratio"
+)
+
+// TypeOrDefault returns the type of the metric, Gauge if not set.
+func (m NodeStatsMetric) TypeOrDefault() MetricType {
+	if m.Type == "" {
+		return GaugeMetric
+	}
+	return m.Type
+}
+
+// IsMetricsMode returns true if the autoscaler scales the NodeSets on metric rules.
+func (esa *ElasticsearchAutoscaler) IsMetricsMode() bool {
+	return esa.Spec.Mode == MetricsMode
+}
+
 func (esa *ElasticsearchAutoscaler) GetAutoscalingPolicySpecs() (v1alpha1.AutoscalingPolicySpecs, error) {
 	if esa == nil {
 		return nil, nil
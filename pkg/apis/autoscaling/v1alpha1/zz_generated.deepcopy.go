@@ -84,6 +84,13 @@ func (in *ElasticsearchAutoscalerSpec) DeepCopyInto(out *ElasticsearchAutoscaler
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MetricRules != nil {
+		in, out := &in.MetricRules, &out.MetricRules
+		*out = make([]MetricScalingRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.PollingPeriod != nil {
 		in, out := &in.PollingPeriod, &out.PollingPeriod
 		*out = new(v1.Duration)
@@ -115,3 +122,42 @@ func (in *ElasticsearchRef) DeepCopy() *ElasticsearchRef {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricScalingRule) DeepCopyInto(out *MetricScalingRule) {
+	*out = *in
+	out.NodeCountRange = in.NodeCountRange
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]NodeStatsMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricScalingRule.
+func (in *MetricScalingRule) DeepCopy() *MetricScalingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricScalingRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeStatsMetric) DeepCopyInto(out *NodeStatsMetric) {
+	*out = *in
+	out.Target = in.Target.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeStatsMetric.
+func (in *NodeStatsMetric) DeepCopy() *NodeStatsMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeStatsMetric)
+	in.DeepCopyInto(out)
+	return out
+}
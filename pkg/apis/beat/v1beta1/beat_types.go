@@ -81,6 +81,11 @@ type BeatSpec struct {
 	// +kubebuilder:validation:Optional
 	Deployment *DeploymentSpec `json:"deployment,omitempty"`
 
+	// Autodiscover generates the configuration of the Kubernetes autodiscover provider of a Filebeat or Metricbeat,
+	// and the ServiceAccount and ClusterRoleBinding it requires.
+	// +kubebuilder:validation:Optional
+	Autodiscover *AutodiscoverSpec `json:"autodiscover,omitempty"`
+
 	// Monitoring enables you to collect and ship logs and metrics for this Beat.
 	// Metricbeat and/or Filebeat sidecars are configured and send monitoring data to an
 	// Elasticsearch monitoring cluster running in the same Kubernetes cluster.
@@ -96,6 +101,11 @@ type DaemonSetSpec struct {
 	PodTemplate corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
 	// +kubebuilder:validation:Optional
 	UpdateStrategy appsv1.DaemonSetUpdateStrategy `json:"updateStrategy,omitempty"`
+	// Variants replace the DaemonSet by one DaemonSet per variant, each deployed on the nodes selected by the variant
+	// from the same Pod template. This covers heterogeneous clusters, for example nodes of different architectures or
+	// tainted nodes, without duplicating the resource. The node selectors of the variants must not overlap.
+	// +kubebuilder:validation:Optional
+	Variants []commonv1.DaemonSetVariant `json:"variants,omitempty"`
 }
 
 type DeploymentSpec struct {
@@ -106,6 +116,55 @@ type DeploymentSpec struct {
 	Strategy appsv1.DeploymentStrategy `json:"strategy,omitempty"`
 }
 
+// AutodiscoverTemplate is a configuration generated for common autodiscover use cases.
+// +kubebuilder:validation:Enum=ContainerLogs;PodMetrics
+type AutodiscoverTemplate string
+
+const (
+	// ContainerLogsTemplate collects the logs of all the containers of the Kubernetes nodes, with Filebeat.
+	ContainerLogsTemplate AutodiscoverTemplate = "ContainerLogs"
+	// PodMetricsTemplate collects the metrics of the Pods and containers from the kubelet, with Metricbeat.
+	PodMetricsTemplate AutodiscoverTemplate = "PodMetrics"
+)
+
+// AutodiscoverSpec configures the Kubernetes autodiscover provider of a Beat.
+type AutodiscoverSpec struct {
+	// Hints enables hints-based autodiscover: the discovered Pods are configured through their `co.elastic.*`
+	// annotations. Defaults to true.
+	// +kubebuilder:validation:Optional
+	Hints *bool `json:"hints,omitempty"`
+
+	// Templates are the configurations generated for common use cases: ContainerLogs for Filebeat, PodMetrics for
+	// Metricbeat.
+	// +kubebuilder:validation:Optional
+	Templates []AutodiscoverTemplate `json:"templates,omitempty"`
+
+	// RBAC creates a ServiceAccount for the Beat Pods, bound to the ClusterRole that grants the read access to the
+	// Kubernetes API required by autodiscover. The ClusterRole is configured in the operator. Defaults to true.
+	// +kubebuilder:validation:Optional
+	RBAC *bool `json:"rbac,omitempty"`
+}
+
+// HintsEnabled returns true if hints-based autodiscover is enabled.
+func (a AutodiscoverSpec) HintsEnabled() bool {
+	return a.Hints == nil || *a.Hints
+}
+
+// RBACEnabled returns true if the RBAC resources required by autodiscover are generated.
+func (a AutodiscoverSpec) RBACEnabled() bool {
+	return a.RBAC == nil || *a.RBAC
+}
+
+// HasTemplate returns true if the given template is enabled.
+func (a AutodiscoverSpec) HasTemplate(template AutodiscoverTemplate) bool {
+	for _, t := range a.Templates {
+		if t == template {
+			return true
+		}
+	}
+	return false
+}
+
 // BeatStatus defines the observed state of a Beat.
 type BeatStatus struct {
 	// Version of the stack resource currently running. During version upgrades, multiple versions may run
@@ -5,6 +5,7 @@
 package v1beta1
 
 import (
+	"fmt"
 	"regexp"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -26,6 +27,9 @@ var (
 		checkSpec,
 		checkAssociations,
 		checkMonitoring,
+		checkSecureSettings,
+		checkDaemonSetVariants,
+		checkAutodiscover,
 	}
 
 	updateChecks = []func(old, curr *Beat) field.ErrorList{
@@ -121,5 +125,47 @@ func checkAssociations(b *Beat) field.ErrorList {
 }
 
 func checkMonitoring(b *Beat) field.ErrorList {
-	return validations.Validate(b, b.Spec.Version, validations.MinStackVersion)
+	errs := validations.Validate(b, b.Spec.Version, validations.MinStackVersion)
+	if b.Spec.Monitoring.Prometheus != nil {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("monitoring").Child("prometheus"),
+			"Prometheus ServiceMonitors are not supported for Beats"))
+	}
+	return errs
+}
+
+func checkDaemonSetVariants(b *Beat) field.ErrorList {
+	if b.Spec.DaemonSet == nil {
+		return nil
+	}
+	return commonv1.CheckDaemonSetVariants(field.NewPath("spec").Child("daemonSet", "variants"), b.Spec.DaemonSet.Variants)
+}
+
+func checkSecureSettings(b *Beat) field.ErrorList {
+	return commonv1.CheckSecretSources(field.NewPath("spec").Child("secureSettings"), b.Spec.SecureSettings, b.Namespace, true)
+}
+
+// autodiscoverTemplateTypes are the types of the Beats supporting each autodiscover template.
+var autodiscoverTemplateTypes = map[AutodiscoverTemplate]string{
+	ContainerLogsTemplate: "filebeat",
+	PodMetricsTemplate:    "metricbeat",
+}
+
+func checkAutodiscover(b *Beat) field.ErrorList {
+	if b.Spec.Autodiscover == nil {
+		return nil
+	}
+	path := field.NewPath("spec").Child("autodiscover")
+	if b.Spec.Type != "filebeat" && b.Spec.Type != "metricbeat" {
+		return field.ErrorList{field.Forbidden(path, "autodiscover is only supported for filebeat and metricbeat")}
+	}
+	var errs field.ErrorList
+	for i, template := range b.Spec.Autodiscover.Templates {
+		if beatType, ok := autodiscoverTemplateTypes[template]; ok && beatType != b.Spec.Type {
+			errs = append(errs, field.Invalid(path.Child("templates").Index(i), template, fmt.Sprintf("template is only supported for %s", beatType)))
+		}
+		if b.Spec.DaemonSet == nil {
+			errs = append(errs, field.Invalid(path.Child("templates").Index(i), template, "template requires a daemonSet to run on every Kubernetes node"))
+		}
+	}
+	return errs
 }
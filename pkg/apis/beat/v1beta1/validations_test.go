@@ -343,6 +343,27 @@ func Test_checkMonitoring(t *testing.T) {
 			},
 			want: nil,
 		},
+		{
+			name: "prometheus monitoring is not supported",
+			beat: &Beat{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testbeat",
+					Namespace: "test",
+				},
+				Spec: BeatSpec{
+					Type:      "filebeat",
+					Version:   "8.2.3",
+					DaemonSet: &DaemonSetSpec{},
+					Monitoring: commonv1.Monitoring{
+						Prometheus: &commonv1.PrometheusMonitoring{Port: 5066},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(field.NewPath("spec").Child("monitoring").Child("prometheus"),
+					"Prometheus ServiceMonitors are not supported for Beats"),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -352,3 +373,59 @@ func Test_checkMonitoring(t *testing.T) {
 		})
 	}
 }
+
+func Test_checkAutodiscover(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		typ          string
+		autodiscover *AutodiscoverSpec
+		daemonSet    *DaemonSetSpec
+		wantErr      bool
+	}{
+		{
+			name: "no autodiscover",
+			typ:  "heartbeat",
+		},
+		{
+			name:         "filebeat with container logs",
+			typ:          "filebeat",
+			autodiscover: &AutodiscoverSpec{Templates: []AutodiscoverTemplate{ContainerLogsTemplate}},
+			daemonSet:    &DaemonSetSpec{},
+		},
+		{
+			name:         "metricbeat with pod metrics",
+			typ:          "metricbeat",
+			autodiscover: &AutodiscoverSpec{Templates: []AutodiscoverTemplate{PodMetricsTemplate}},
+			daemonSet:    &DaemonSetSpec{},
+		},
+		{
+			name:         "metricbeat deployment with hints only",
+			typ:          "metricbeat",
+			autodiscover: &AutodiscoverSpec{},
+		},
+		{
+			name:         "template without daemonSet",
+			typ:          "metricbeat",
+			autodiscover: &AutodiscoverSpec{Templates: []AutodiscoverTemplate{PodMetricsTemplate}},
+			wantErr:      true,
+		},
+		{
+			name:         "unsupported type",
+			typ:          "heartbeat",
+			autodiscover: &AutodiscoverSpec{},
+			wantErr:      true,
+		},
+		{
+			name:         "template of another type",
+			typ:          "filebeat",
+			autodiscover: &AutodiscoverSpec{Templates: []AutodiscoverTemplate{ContainerLogsTemplate, PodMetricsTemplate}},
+			daemonSet:    &DaemonSetSpec{},
+			wantErr:      true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkAutodiscover(&Beat{Spec: BeatSpec{Type: tt.typ, Autodiscover: tt.autodiscover, DaemonSet: tt.daemonSet}})
+			require.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
@@ -13,6 +13,36 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutodiscoverSpec) DeepCopyInto(out *AutodiscoverSpec) {
+	*out = *in
+	if in.Hints != nil {
+		in, out := &in.Hints, &out.Hints
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]AutodiscoverTemplate, len(*in))
+		copy(*out, *in)
+	}
+	if in.RBAC != nil {
+		in, out := &in.RBAC, &out.RBAC
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutodiscoverSpec.
+func (in *AutodiscoverSpec) DeepCopy() *AutodiscoverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutodiscoverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Beat) DeepCopyInto(out *Beat) {
 	*out = *in
@@ -181,6 +211,11 @@ func (in *BeatSpec) DeepCopyInto(out *BeatSpec) {
 		*out = new(DeploymentSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autodiscover != nil {
+		in, out := &in.Autodiscover, &out.Autodiscover
+		*out = new(AutodiscoverSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
 	if in.RevisionHistoryLimit != nil {
 		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
@@ -226,6 +261,13 @@ func (in *DaemonSetSpec) DeepCopyInto(out *DaemonSetSpec) {
 	*out = *in
 	in.PodTemplate.DeepCopyInto(&out.PodTemplate)
 	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make([]v1.DaemonSetVariant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetSpec.
@@ -299,5 +299,24 @@ func (ac *AssociationConf) GetURL() string {
 func ElasticsearchConfigAnnotationName(o ObjectSelector) string {
 	// annotation key should be stable to allow the Elasticsearch Controller to only pick up the ones it expects,
 	// based on the ObjectSelector
-	return FormatNameWithID(ElasticsearchConfigAnnotationNameBase+"%s", hash.HashObject(o))
+	return FormatNameWithID(ElasticsearchConfigAnnotationNameBase+"%s", o.IdentityHash())
+}
+
+// IdentityHash returns a hash of the fields of the ObjectSelector which identify the referenced object, excluding the
+// ones which only configure the connection to it such as CASecretName. It is used in the name of the association conf
+// annotations, which must not change when these fields are set.
+func (o ObjectSelector) IdentityHash() string {
+	// the type is shadowed so that the hash remains the same as before CASecretName was added to ObjectSelector
+	type ObjectSelector struct {
+		Namespace   string
+		Name        string
+		ServiceName string
+		SecretName  string
+	}
+	return hash.HashObject(ObjectSelector{
+		Namespace:   o.Namespace,
+		Name:        o.Name,
+		ServiceName: o.ServiceName,
+		SecretName:  o.SecretName,
+	})
 }
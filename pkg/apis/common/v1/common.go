@@ -8,6 +8,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
@@ -121,8 +123,14 @@ type ObjectSelector struct {
 	// - `password`: the password of the user to be authenticated to the Elastic resource
 	// - `ca.crt`: the CA certificate in PEM format (optional)
 	// - `api-key`: the key to authenticate against the Elastic resource instead of a username and password (supported only for `elasticsearchRefs` in AgentSpec and in BeatSpec)
-	// This field cannot be used in combination with the other fields name, namespace or serviceName.
+	// This field cannot be used in combination with the other fields name, namespace, serviceName or caSecretName.
 	SecretName string `json:"secretName,omitempty"`
+
+	// CASecretName is the name of an existing Kubernetes secret, in the namespace of the referencing resource, which
+	// contains additional CA certificates in PEM format under the `ca.crt` key. They are trusted in addition to the CA
+	// of the referenced object, for example when it is reached through a proxy or a load balancer which re-encrypts the
+	// traffic with its own certificate. It can only be used in combination with name.
+	CASecretName string `json:"caSecretName,omitempty"`
 }
 
 // WithDefaultNamespace adds a default namespace to a given ObjectSelector if none is set.
@@ -131,10 +139,11 @@ func (o ObjectSelector) WithDefaultNamespace(defaultNamespace string) ObjectSele
 		return o
 	}
 	return ObjectSelector{
-		Namespace:   defaultNamespace,
-		Name:        o.Name,
-		ServiceName: o.ServiceName,
-		SecretName:  o.SecretName,
+		Namespace:    defaultNamespace,
+		Name:         o.Name,
+		ServiceName:  o.ServiceName,
+		SecretName:   o.SecretName,
+		CASecretName: o.CASecretName,
 	}
 }
 
@@ -180,6 +189,9 @@ func (o ObjectSelector) IsValid() error {
 	if o.Name == "" && (o.Namespace != "") {
 		return errors.New("namespace can only be used in combination with name")
 	}
+	if o.Name == "" && (o.CASecretName != "") {
+		return errors.New("caSecretName can only be used in combination with name")
+	}
 	return nil
 }
 
@@ -197,6 +209,19 @@ type HTTPConfig struct {
 	Service ServiceTemplate `json:"service,omitempty"`
 	// TLS defines options for configuring TLS for HTTP.
 	TLS TLSOptions `json:"tls,omitempty"`
+	// Ingress defines the template for a Kubernetes Ingress exposing the HTTP service.
+	// The Ingress is created by the operator and kept in sync with the service.
+	// +kubebuilder:validation:Optional
+	Ingress *IngressTemplate `json:"ingress,omitempty"`
+	// GatewayRoute defines the template for a Gateway API route exposing the HTTP service, as an alternative to an Ingress.
+	// The route is created by the operator and kept in sync with the service.
+	// +kubebuilder:validation:Optional
+	GatewayRoute *GatewayRouteTemplate `json:"gatewayRoute,omitempty"`
+	// Hostnames are the public DNS names of the HTTP endpoint. They are published by external-dns through the annotations
+	// of the Ingress if any, or of the HTTP service otherwise, and are added to the SANs of the HTTP certificate.
+	// They are also the default hosts of the Ingress and the default hostnames of the Gateway route.
+	// +kubebuilder:validation:Optional
+	Hostnames []string `json:"hostnames,omitempty"`
 }
 
 // Protocol returns the inferrred protocol (http or https) for this configuration.
@@ -219,14 +244,66 @@ type TLSOptions struct {
 	// - `tls.crt`: The certificate (or a chain).
 	// - `tls.key`: The private key to the first certificate in the certificate chain.
 	Certificate SecretRef `json:"certificate,omitempty"`
+
+	// ServiceMesh indicates that the Pods are connected to a service mesh providing mutual TLS between services.
+	// TLS is then disabled at the application level, and the Pods are configured to run along the mesh sidecar containers.
+	// +kubebuilder:validation:Optional
+	ServiceMesh *ServiceMeshOptions `json:"serviceMesh,omitempty"`
+
+	// CertificateRotation overrides the validity and rotation settings of the operator for the self-signed
+	// certificate and CA of this resource.
+	// +kubebuilder:validation:Optional
+	CertificateRotation *CertificateRotation `json:"certificateRotation,omitempty"`
+}
+
+// CertificateRotation overrides the validity and rotation settings set in the operator configuration for the
+// certificates and the CA generated by the operator for a resource.
+type CertificateRotation struct {
+	// CertificateValidity is the validity of the certificates, overriding the cert-validity operator flag.
+	// +kubebuilder:validation:Optional
+	CertificateValidity *metav1.Duration `json:"certificateValidity,omitempty"`
+	// CertificateRotateBefore is how long before their expiration the certificates are rotated, overriding the
+	// cert-rotate-before operator flag.
+	// +kubebuilder:validation:Optional
+	CertificateRotateBefore *metav1.Duration `json:"certificateRotateBefore,omitempty"`
+	// CAValidity is the validity of the CA, overriding the ca-cert-validity operator flag.
+	// +kubebuilder:validation:Optional
+	CAValidity *metav1.Duration `json:"caValidity,omitempty"`
+	// CARotateBefore is how long before its expiration the CA is rotated, overriding the ca-cert-rotate-before
+	// operator flag.
+	// +kubebuilder:validation:Optional
+	CARotateBefore *metav1.Duration `json:"caRotateBefore,omitempty"`
 }
 
 // Enabled returns true when TLS is enabled based on this option struct.
 func (tls TLSOptions) Enabled() bool {
+	if tls.ServiceMeshEnabled() {
+		return false
+	}
 	selfSigned := tls.SelfSignedCertificate
 	return selfSigned == nil || !selfSigned.Disabled || tls.Certificate.SecretName != ""
 }
 
+// ServiceMeshEnabled returns true when TLS is provided by a service mesh.
+func (tls TLSOptions) ServiceMeshEnabled() bool {
+	return tls.ServiceMesh != nil
+}
+
+// ServiceMeshProvider is the service mesh implementation the Pods are connected to.
+type ServiceMeshProvider string
+
+const (
+	IstioServiceMeshProvider   ServiceMeshProvider = "istio"
+	LinkerdServiceMeshProvider ServiceMeshProvider = "linkerd"
+)
+
+// ServiceMeshOptions holds the configuration of the service mesh providing TLS.
+type ServiceMeshOptions struct {
+	// Provider is the service mesh implementation injecting its sidecar containers in the Pods.
+	// +kubebuilder:validation:Enum=istio;linkerd
+	Provider ServiceMeshProvider `json:"provider"`
+}
+
 // SelfSignedCertificate holds configuration for the self-signed certificate generated by the operator.
 type SelfSignedCertificate struct {
 	// SubjectAlternativeNames is a list of SANs to include in the generated HTTP TLS certificate.
@@ -255,6 +332,167 @@ type ServiceTemplate struct {
 	Spec v1.ServiceSpec `json:"spec,omitempty"`
 }
 
+// DaemonSetVariant defines a DaemonSet deployed in place of the default one on a subset of the Kubernetes nodes, for
+// example the nodes of a given operating system or architecture, or tainted nodes.
+type DaemonSetVariant struct {
+	// Name of the variant, appended to the name of the DaemonSet.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// NodeSelector restricts the variant to the nodes with these labels. It is merged into the node selector of the Pod
+	// template, for example to select the nodes by kubernetes.io/os or kubernetes.io/arch.
+	// +kubebuilder:validation:Optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are added to the tolerations of the Pod template, to schedule the variant on tainted nodes.
+	// +kubebuilder:validation:Optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+}
+
+// IngressTemplate defines the template for a Kubernetes Ingress exposing the HTTP service.
+type IngressTemplate struct {
+	// ObjectMeta is the metadata of the Ingress, such as the annotations configuring the Ingress controller.
+	// The name and namespace provided here are managed by ECK and will be ignored.
+	// +kubebuilder:validation:Optional
+	ObjectMeta metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// ClassName is the name of the IngressClass of the Ingress. Defaults to the default IngressClass of the cluster.
+	// +kubebuilder:validation:Optional
+	ClassName *string `json:"className,omitempty"`
+
+	// Host is the fully qualified domain name of the Ingress. Optional if hostnames are set in the HTTP configuration,
+	// the Ingress then routing all of them.
+	// +kubebuilder:validation:Optional
+	Host string `json:"host,omitempty"`
+
+	// Path routed to the HTTP service. Defaults to /.
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
+
+	// TLS defines options for terminating TLS at the Ingress controller. TLS is not terminated at the Ingress controller if not set.
+	// +kubebuilder:validation:Optional
+	TLS *IngressTLSOptions `json:"tls,omitempty"`
+}
+
+// PathOrDefault returns the path routed to the HTTP service.
+func (i IngressTemplate) PathOrDefault() string {
+	if i.Path == "" {
+		return "/"
+	}
+	return i.Path
+}
+
+// IngressTLSOptions holds TLS configuration options for an Ingress.
+type IngressTLSOptions struct {
+	// SecretName is the name of a Kubernetes secret that contains the certificate (`tls.crt`) and private key (`tls.key`)
+	// presented by the Ingress controller. If not set, the HTTP certificate managed by the operator is presented, and
+	// the host of the Ingress is added to the subject alternative names of the self-signed certificate.
+	// +kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// IngressHosts returns the hosts routed by the Ingress: its own host if set, then the public hostnames.
+func (http HTTPConfig) IngressHosts() []string {
+	if http.Ingress == nil {
+		return nil
+	}
+	var hosts []string
+	if http.Ingress.Host != "" {
+		hosts = append(hosts, http.Ingress.Host)
+	}
+	return appendMissing(hosts, http.Hostnames...)
+}
+
+// GatewayRouteHostnames returns the hostnames of the Gateway route, defaulting to the public hostnames.
+func (http HTTPConfig) GatewayRouteHostnames() []string {
+	if http.GatewayRoute == nil {
+		return nil
+	}
+	if len(http.GatewayRoute.Hostnames) > 0 {
+		return http.GatewayRoute.Hostnames
+	}
+	return http.Hostnames
+}
+
+// ExternalSANs returns the subject alternative names to add to the self-signed HTTP certificate for it to be presented
+// to the clients of the public hostnames, of the Ingress or of the Gateway route.
+func (http HTTPConfig) ExternalSANs() []SubjectAlternativeName {
+	hostnames := appendMissing(nil, http.Hostnames...)
+	if http.Ingress != nil && http.Ingress.TLS != nil && http.Ingress.TLS.SecretName == "" {
+		hostnames = appendMissing(hostnames, http.IngressHosts()...)
+	}
+	if http.GatewayRoute != nil && http.GatewayRoute.TLSModeOrDefault() == GatewayTLSPassthroughMode {
+		hostnames = appendMissing(hostnames, http.GatewayRouteHostnames()...)
+	}
+	var sans []SubjectAlternativeName
+	for _, hostname := range hostnames {
+		sans = append(sans, SubjectAlternativeName{DNS: hostname})
+	}
+	return sans
+}
+
+func appendMissing(values []string, candidates ...string) []string {
+	for _, candidate := range candidates {
+		if !slices.Contains(values, candidate) {
+			values = append(values, candidate)
+		}
+	}
+	return values
+}
+
+// GatewayTLSMode is the way TLS is handled by a Gateway for a route.
+type GatewayTLSMode string
+
+const (
+	// GatewayTLSTerminateMode terminates TLS at the Gateway, which routes the HTTP requests to the service with an HTTPRoute.
+	GatewayTLSTerminateMode GatewayTLSMode = "Terminate"
+	// GatewayTLSPassthroughMode forwards the TLS connections to the service with a TLSRoute, the HTTP certificate being
+	// presented to the clients.
+	GatewayTLSPassthroughMode GatewayTLSMode = "Passthrough"
+)
+
+// GatewayRouteTemplate defines the template for a Gateway API route exposing the HTTP service.
+type GatewayRouteTemplate struct {
+	// ObjectMeta is the metadata of the route.
+	// The name and namespace provided here are managed by ECK and will be ignored.
+	// +kubebuilder:validation:Optional
+	ObjectMeta metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// ParentRefs are the Gateways the route attaches to.
+	// +kubebuilder:validation:MinItems=1
+	ParentRefs []GatewayParentRef `json:"parentRefs"`
+
+	// Hostnames matched by the route. Mandatory with the Passthrough TLS mode.
+	// +kubebuilder:validation:Optional
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// TLSMode is the way TLS is handled by the Gateway. With Terminate, the Gateway terminates TLS and routes the HTTP
+	// requests to the service with an HTTPRoute. With Passthrough, the Gateway forwards the TLS connections to the
+	// service with a TLSRoute, and the hostnames are added to the subject alternative names of the self-signed HTTP
+	// certificate. Defaults to Terminate.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Terminate;Passthrough
+	TLSMode GatewayTLSMode `json:"tlsMode,omitempty"`
+}
+
+// TLSModeOrDefault returns the way TLS is handled by the Gateway for the route.
+func (r GatewayRouteTemplate) TLSModeOrDefault() GatewayTLSMode {
+	if r.TLSMode == "" {
+		return GatewayTLSTerminateMode
+	}
+	return r.TLSMode
+}
+
+// GatewayParentRef is a reference to a Gateway, or to one of its listeners.
+type GatewayParentRef struct {
+	// Name of the Gateway.
+	Name string `json:"name"`
+	// Namespace of the Gateway. Defaults to the namespace of the resource.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// SectionName is the name of the listener of the Gateway the route attaches to. Defaults to all the listeners.
+	// +kubebuilder:validation:Optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
 // DefaultPodDisruptionBudgetMaxUnavailable is the default max unavailable pods in a PDB.
 var DefaultPodDisruptionBudgetMaxUnavailable = intstr.FromInt(1)
 
@@ -275,6 +513,22 @@ func (p *PodDisruptionBudgetTemplate) IsDisabled() bool {
 	return reflect.DeepEqual(p, &PodDisruptionBudgetTemplate{})
 }
 
+// MaintenanceWindow defines a recurring period of time during which the operator performs the disruptive changes,
+// such as the restart of the Pods to apply a new version or configuration.
+type MaintenanceWindow struct {
+	// Schedule is the start of the window as a cron expression made of 5 fields: minute, hour, day of month, month and
+	// day of week. For example "0 2 * * 6" starts the window every Saturday at 2am.
+	Schedule string `json:"schedule"`
+
+	// Duration of the window, for example "4h".
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the name of the time zone of the schedule, as defined in the IANA time zone database, for example
+	// "Europe/Paris". Defaults to UTC.
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
 // NamespacedSecretSource defines a data source based on a Kubernetes Secret in a given namespace.
 type NamespacedSecretSource struct {
 	// Namespace is the namespace of the secret.
@@ -290,13 +544,67 @@ type NamespacedSecretSource struct {
 
 // SecretSource defines a data source based on a Kubernetes Secret.
 type SecretSource struct {
-	// SecretName is the name of the secret.
-	SecretName string `json:"secretName"`
+	// SecretName is the name of the secret. Exactly one of SecretName or External must be set.
+	// +kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
 	// Entries define how to project each key-value pair in the secret to filesystem paths.
 	// If not defined, all keys will be projected to similarly named paths in the filesystem.
 	// If defined, only the specified keys will be projected to the corresponding paths.
 	// +kubebuilder:validation:Optional
 	Entries []KeyToPath `json:"entries,omitempty"`
+	// External references a secret held by an external secret store instead of a Kubernetes Secret. The operator
+	// fetches it with its own credentials, and refreshes it periodically.
+	// Exactly one of SecretName or External must be set.
+	// +kubebuilder:validation:Optional
+	External *ExternalSecretSource `json:"external,omitempty"`
+}
+
+// IsExternal returns true if the secret is held by an external secret store.
+func (s SecretSource) IsExternal() bool {
+	return s.External != nil
+}
+
+// ExternalSecretProvider is the name of an external secret store.
+type ExternalSecretProvider string
+
+const (
+	// VaultSecretProvider is HashiCorp Vault.
+	VaultSecretProvider ExternalSecretProvider = "vault"
+	// AWSSecretProvider is AWS Secrets Manager.
+	AWSSecretProvider ExternalSecretProvider = "aws"
+	// GCPSecretProvider is Google Cloud Secret Manager.
+	GCPSecretProvider ExternalSecretProvider = "gcp"
+)
+
+// ExternalSecretSource references a secret held by an external secret store.
+type ExternalSecretSource struct {
+	// Provider is the external secret store holding the secret: vault, aws or gcp.
+	// +kubebuilder:validation:Enum=vault;aws;gcp
+	Provider ExternalSecretProvider `json:"provider"`
+	// Path identifies the secret in the store: the path of a KV secret in Vault, for example secret/data/elasticsearch,
+	// the name or ARN of a secret in AWS Secrets Manager, or the resource name of a secret in Google Cloud Secret Manager,
+	// for example projects/my-project/secrets/elasticsearch. The fields of a secret holding a JSON object are the keys of
+	// the secret, any other secret is available under the value key.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+	// RefreshInterval is the interval between two fetches of the secret from the store. Defaults to 5m, cannot be less than 1m.
+	// +kubebuilder:validation:Optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+const (
+	// DefaultExternalSecretRefreshInterval is the interval between two fetches of an external secret if not specified.
+	DefaultExternalSecretRefreshInterval = 5 * time.Minute
+	// MinExternalSecretRefreshInterval is the minimum interval between two fetches of an external secret.
+	MinExternalSecretRefreshInterval = time.Minute
+)
+
+// RefreshIntervalOrDefault returns the interval between two fetches of the secret, or the default interval if not specified.
+func (s ExternalSecretSource) RefreshIntervalOrDefault() time.Duration {
+	if s.RefreshInterval == nil {
+		return DefaultExternalSecretRefreshInterval
+	}
+	return s.RefreshInterval.Duration
 }
 
 // KeyToPath defines how to map a key in a Secret object to a filesystem path.
@@ -376,6 +684,10 @@ type HasIdentityLabels interface {
 // DisableDowngradeValidationAnnotation allows circumventing downgrade/upgrade checks.
 const DisableDowngradeValidationAnnotation = "eck.k8s.elastic.co/disable-downgrade-validation"
 
+// RotateCAAnnotation forces the rotation of the CAs generated by the operator for a resource each time its value
+// changes. The previous CAs remain trusted until the certificates issued by the new ones are in use.
+const RotateCAAnnotation = "eck.k8s.elastic.co/rotate-ca"
+
 // IsConfiguredToAllowDowngrades returns true if the DisableDowngradeValidation annotation is set to the value of true.
 func IsConfiguredToAllowDowngrades(o metav1.Object) bool {
 	val, exists := o.GetAnnotations()[DisableDowngradeValidationAnnotation]
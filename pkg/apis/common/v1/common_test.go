@@ -15,6 +15,7 @@ func TestTLSOptions_Enabled(t *testing.T) {
 	type fields struct {
 		SelfSignedCertificate *SelfSignedCertificate
 		Certificate           SecretRef
+		ServiceMesh           *ServiceMeshOptions
 	}
 	tests := []struct {
 		name   string
@@ -59,12 +60,20 @@ func TestTLSOptions_Enabled(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "disabled: provided by the service mesh",
+			fields: fields{
+				ServiceMesh: &ServiceMeshOptions{Provider: IstioServiceMeshProvider},
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tls := TLSOptions{
 				SelfSignedCertificate: tt.fields.SelfSignedCertificate,
 				Certificate:           tt.fields.Certificate,
+				ServiceMesh:           tt.fields.ServiceMesh,
 			}
 			if got := tls.Enabled(); got != tt.want {
 				t.Errorf("TLSOptions.Enabled() = %v, want %v", got, tt.want)
@@ -311,6 +320,22 @@ func TestObjectSelector_IsValid(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "name and caSecretName: OK",
+			objectSelector: ObjectSelector{
+				Name:         "a",
+				CASecretName: "d",
+			},
+			wantErr: false,
+		},
+		{
+			name: "secretName and caSecretName: KO",
+			objectSelector: ObjectSelector{
+				SecretName:   "s",
+				CASecretName: "d",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -320,3 +345,106 @@ func TestObjectSelector_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPConfig_ExternalSANs(t *testing.T) {
+	tests := []struct {
+		name string
+		http HTTPConfig
+		want []SubjectAlternativeName
+	}{
+		{
+			name: "no Ingress",
+			http: HTTPConfig{},
+		},
+		{
+			name: "Ingress without TLS",
+			http: HTTPConfig{Ingress: &IngressTemplate{Host: "es.example.com"}},
+		},
+		{
+			name: "Ingress with a custom certificate",
+			http: HTTPConfig{Ingress: &IngressTemplate{Host: "es.example.com", TLS: &IngressTLSOptions{SecretName: "es-cert"}}},
+		},
+		{
+			name: "Ingress presenting the HTTP certificate",
+			http: HTTPConfig{Ingress: &IngressTemplate{Host: "es.example.com", TLS: &IngressTLSOptions{}}},
+			want: []SubjectAlternativeName{{DNS: "es.example.com"}},
+		},
+		{
+			name: "Gateway route terminating TLS",
+			http: HTTPConfig{GatewayRoute: &GatewayRouteTemplate{Hostnames: []string{"es.example.com"}}},
+		},
+		{
+			name: "Gateway route passing TLS through",
+			http: HTTPConfig{GatewayRoute: &GatewayRouteTemplate{Hostnames: []string{"es.example.com", "*.es.example.com"}, TLSMode: GatewayTLSPassthroughMode}},
+			want: []SubjectAlternativeName{{DNS: "es.example.com"}, {DNS: "*.es.example.com"}},
+		},
+		{
+			name: "Ingress and Gateway route",
+			http: HTTPConfig{
+				Ingress:      &IngressTemplate{Host: "es.example.com", TLS: &IngressTLSOptions{}},
+				GatewayRoute: &GatewayRouteTemplate{Hostnames: []string{"es.internal.example.com"}, TLSMode: GatewayTLSPassthroughMode},
+			},
+			want: []SubjectAlternativeName{{DNS: "es.example.com"}, {DNS: "es.internal.example.com"}},
+		},
+		{
+			name: "public hostnames",
+			http: HTTPConfig{Hostnames: []string{"es.example.com", "search.example.com"}},
+			want: []SubjectAlternativeName{{DNS: "es.example.com"}, {DNS: "search.example.com"}},
+		},
+		{
+			name: "public hostnames routed by the Ingress and the Gateway route",
+			http: HTTPConfig{
+				Hostnames:    []string{"es.example.com"},
+				Ingress:      &IngressTemplate{Host: "es.internal.example.com", TLS: &IngressTLSOptions{}},
+				GatewayRoute: &GatewayRouteTemplate{TLSMode: GatewayTLSPassthroughMode},
+			},
+			want: []SubjectAlternativeName{{DNS: "es.example.com"}, {DNS: "es.internal.example.com"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.http.ExternalSANs())
+		})
+	}
+}
+
+func TestHTTPConfig_IngressHosts(t *testing.T) {
+	tests := []struct {
+		name string
+		http HTTPConfig
+		want []string
+	}{
+		{
+			name: "no Ingress",
+			http: HTTPConfig{Hostnames: []string{"es.example.com"}},
+		},
+		{
+			name: "Ingress host",
+			http: HTTPConfig{Ingress: &IngressTemplate{Host: "es.example.com"}},
+			want: []string{"es.example.com"},
+		},
+		{
+			name: "Ingress host and public hostnames",
+			http: HTTPConfig{Hostnames: []string{"search.example.com", "es.example.com"}, Ingress: &IngressTemplate{Host: "es.example.com"}},
+			want: []string{"es.example.com", "search.example.com"},
+		},
+		{
+			name: "public hostnames only",
+			http: HTTPConfig{Hostnames: []string{"es.example.com"}, Ingress: &IngressTemplate{}},
+			want: []string{"es.example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.http.IngressHosts())
+		})
+	}
+}
+
+func TestHTTPConfig_GatewayRouteHostnames(t *testing.T) {
+	hostnames := []string{"es.example.com"}
+	assert.Nil(t, HTTPConfig{Hostnames: hostnames}.GatewayRouteHostnames())
+	assert.Equal(t, hostnames, HTTPConfig{Hostnames: hostnames, GatewayRoute: &GatewayRouteTemplate{}}.GatewayRouteHostnames())
+	assert.Equal(t, []string{"es.internal.example.com"},
+		HTTPConfig{Hostnames: hostnames, GatewayRoute: &GatewayRouteTemplate{Hostnames: []string{"es.internal.example.com"}}}.GatewayRouteHostnames())
+}
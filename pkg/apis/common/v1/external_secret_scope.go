@@ -0,0 +1,76 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// NamespacePlaceholder is replaced by the namespace of the resource referencing an external secret in the path
+// prefixes external secrets are restricted to.
+const NamespacePlaceholder = "{namespace}"
+
+var (
+	externalSecretPathPrefixesMu sync.RWMutex
+	externalSecretPathPrefixes   = map[ExternalSecretProvider]string{}
+)
+
+// SetExternalSecretPathPrefixes restricts the external secrets each provider can fetch to the paths starting with the
+// given prefix, in which NamespacePlaceholder is replaced by the namespace of the resource referencing the secret.
+// External secrets of a provider without a prefix cannot be referenced.
+func SetExternalSecretPathPrefixes(prefixes map[ExternalSecretProvider]string) error {
+	for provider, prefix := range prefixes {
+		if err := checkPathPrefix(prefix); err != nil {
+			return fmt.Errorf("invalid path prefix %q for external secret provider %s: %w", prefix, provider, err)
+		}
+	}
+	externalSecretPathPrefixesMu.Lock()
+	defer externalSecretPathPrefixesMu.Unlock()
+	externalSecretPathPrefixes = make(map[ExternalSecretProvider]string, len(prefixes))
+	for provider, prefix := range prefixes {
+		externalSecretPathPrefixes[provider] = prefix
+	}
+	return nil
+}
+
+// checkPathPrefix checks that the prefix contains the namespace exactly once, followed by a character that cannot be
+// part of a namespace name. Otherwise the prefix of a namespace would also be the prefix of other namespaces, for
+// example secret/eck/{namespace} would allow the namespace a to fetch the secrets of the namespace a-b.
+func checkPathPrefix(prefix string) error {
+	if strings.Count(prefix, NamespacePlaceholder) != 1 {
+		return fmt.Errorf("must contain %s exactly once", NamespacePlaceholder)
+	}
+	rest := prefix[strings.Index(prefix, NamespacePlaceholder)+len(NamespacePlaceholder):]
+	if rest == "" || isNamespaceChar(rest[0]) {
+		return fmt.Errorf("%s must be followed by a separator that cannot be part of a namespace name, such as /", NamespacePlaceholder)
+	}
+	return nil
+}
+
+func isNamespaceChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-'
+}
+
+// CheckPathScope returns an error if the secret cannot be referenced from the given namespace, because its path does
+// not start with the path prefix of its provider in that namespace.
+func (s ExternalSecretSource) CheckPathScope(namespace string) error {
+	externalSecretPathPrefixesMu.RLock()
+	prefix, ok := externalSecretPathPrefixes[s.Provider]
+	externalSecretPathPrefixesMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no path prefix is configured for external secret provider %s", s.Provider)
+	}
+	if strings.ContainsAny(s.Path, `%?#\`) || slices.Contains(strings.Split(s.Path, "/"), "..") {
+		return fmt.Errorf("path %s must not contain any .. segment or any of the characters %%?#\\", s.Path)
+	}
+	expected := strings.ReplaceAll(prefix, NamespacePlaceholder, namespace)
+	if !strings.HasPrefix(s.Path, expected) || len(s.Path) == len(expected) {
+		return fmt.Errorf("path %s is not allowed in namespace %s, it must start with %s", s.Path, namespace, expected)
+	}
+	return nil
+}
@@ -4,6 +4,8 @@
 
 package v1
 
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 // Monitoring holds references to both the metrics, and logs Elasticsearch clusters for
 // configuring stack monitoring.
 type Monitoring struct {
@@ -13,6 +15,9 @@ type Monitoring struct {
 	// Logs holds references to Elasticsearch clusters which receive log data from an associated resource.
 	// +kubebuilder:validation:Optional
 	Logs LogsMonitoring `json:"logs,omitempty"`
+	// Prometheus configures the scraping of the Pods by a Prometheus managed by the Prometheus Operator.
+	// +kubebuilder:validation:Optional
+	Prometheus *PrometheusMonitoring `json:"prometheus,omitempty"`
 }
 
 // MetricsMonitoring holds a list of Elasticsearch clusters which receive monitoring data from
@@ -40,3 +45,60 @@ type LogsMonitoring struct {
 	// +kubebuilder:validation:Optional
 	ElasticsearchRefs []ObjectSelector `json:"elasticsearchRefs,omitempty"`
 }
+
+// PrometheusMonitoring defines the ServiceMonitor created for the Prometheus Operator to scrape a metrics endpoint of the
+// Pods, typically served by an exporter container added to the Pod template.
+type PrometheusMonitoring struct {
+	// ObjectMeta is the metadata of the ServiceMonitor, such as the labels selected by Prometheus.
+	// The name and namespace provided here are managed by ECK and will be ignored.
+	// +kubebuilder:validation:Optional
+	ObjectMeta metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Port is the port of the Pods serving the metrics.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Path serving the metrics. Defaults to /metrics.
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
+
+	// Scheme used to scrape the metrics. Defaults to http.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=http;https
+	Scheme string `json:"scheme,omitempty"`
+
+	// Interval between two scrapes, for ex. 30s. Defaults to the scrape interval of Prometheus.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern="^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$"
+	Interval string `json:"interval,omitempty"`
+
+	// Exporter injects a Prometheus exporter sidecar container in the Pods, serving the metrics on Port.
+	// Only supported for Elasticsearch. The container can be customized in the Pod template, using the name
+	// `elasticsearch-exporter`.
+	// +kubebuilder:validation:Optional
+	Exporter *PrometheusExporter `json:"exporter,omitempty"`
+}
+
+// PrometheusExporter configures the Prometheus exporter sidecar container injected in the Pods.
+type PrometheusExporter struct {
+	// Image is the container image of the exporter. Defaults to the Elasticsearch exporter of the Prometheus community.
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+}
+
+// PathOrDefault returns the path serving the metrics.
+func (p PrometheusMonitoring) PathOrDefault() string {
+	if p.Path == "" {
+		return "/metrics"
+	}
+	return p.Path
+}
+
+// SchemeOrDefault returns the scheme used to scrape the metrics.
+func (p PrometheusMonitoring) SchemeOrDefault() string {
+	if p.Scheme == "" {
+		return "http"
+	}
+	return p.Scheme
+}
@@ -0,0 +1,53 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+// APIResourcePhase is the phase of a resource configured through the Elasticsearch or Kibana API, such as an ILM policy
+// or a Kibana space, overall or on one Elasticsearch cluster or Kibana instance.
+type APIResourcePhase string
+
+const (
+	APIResourceReadyPhase           APIResourcePhase = "Ready"
+	APIResourceApplyingChangesPhase APIResourcePhase = "ApplyingChanges"
+	APIResourceErrorPhase           APIResourcePhase = "Error"
+	APIResourceInvalidPhase         APIResourcePhase = "Invalid"
+)
+
+// apiResourcePhaseOrder maps phases to integers in ascending order of severity.
+var apiResourcePhaseOrder = map[APIResourcePhase]int{
+	APIResourceReadyPhase:           0,
+	APIResourceApplyingChangesPhase: 1,
+	APIResourceErrorPhase:           2,
+	APIResourceInvalidPhase:         3,
+}
+
+// Worse returns the most severe of this phase and the given phase.
+func (p APIResourcePhase) Worse(other APIResourcePhase) APIResourcePhase {
+	if apiResourcePhaseOrder[other] > apiResourcePhaseOrder[p] {
+		return other
+	}
+	return p
+}
+
+// IsDegraded returns true if this phase is degraded compared to the previous phase. Applying changes is not a
+// degradation.
+func (p APIResourcePhase) IsDegraded(prev APIResourcePhase) bool {
+	return prev == APIResourceReadyPhase && p != APIResourceReadyPhase && p != APIResourceApplyingChangesPhase
+}
+
+// SummarizePhases returns the number of Elasticsearch clusters a resource is configured on, the number of clusters on
+// which it is ready, and the worst of the given phase and of the phases of the resource on each cluster, read from its
+// status for that cluster with phaseOf.
+func SummarizePhases[T any](details map[string]T, phaseOf func(T) APIResourcePhase, phase APIResourcePhase) (int, int, APIResourcePhase) {
+	ready := 0
+	for _, status := range details {
+		clusterPhase := phaseOf(status)
+		if clusterPhase == APIResourceReadyPhase {
+			ready++
+		}
+		phase = phase.Worse(clusterPhase)
+	}
+	return len(details), ready, phase
+}
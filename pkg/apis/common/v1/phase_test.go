@@ -0,0 +1,54 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIResourcePhase_IsDegraded(t *testing.T) {
+	tests := []struct {
+		name  string
+		prev  APIResourcePhase
+		phase APIResourcePhase
+		want  bool
+	}{
+		{name: "ready to error", prev: APIResourceReadyPhase, phase: APIResourceErrorPhase, want: true},
+		{name: "ready to invalid", prev: APIResourceReadyPhase, phase: APIResourceInvalidPhase, want: true},
+		{name: "ready to applying changes", prev: APIResourceReadyPhase, phase: APIResourceApplyingChangesPhase, want: false},
+		{name: "error to invalid", prev: APIResourceErrorPhase, phase: APIResourceInvalidPhase, want: false},
+		{name: "unknown to error", prev: "", phase: APIResourceErrorPhase, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.phase.IsDegraded(tt.prev))
+		})
+	}
+}
+
+func TestSummarizePhases(t *testing.T) {
+	phaseOf := func(phase APIResourcePhase) APIResourcePhase { return phase }
+
+	resources, ready, phase := SummarizePhases(map[string]APIResourcePhase{}, phaseOf, APIResourceReadyPhase)
+	require.Equal(t, 0, resources)
+	require.Equal(t, 0, ready)
+	require.Equal(t, APIResourceReadyPhase, phase)
+
+	resources, ready, phase = SummarizePhases(map[string]APIResourcePhase{
+		"es1": APIResourceReadyPhase,
+		"es2": APIResourceErrorPhase,
+		"es3": APIResourceApplyingChangesPhase,
+		"es4": APIResourceReadyPhase,
+	}, phaseOf, APIResourceReadyPhase)
+	require.Equal(t, 4, resources)
+	require.Equal(t, 2, ready)
+	require.Equal(t, APIResourceErrorPhase, phase)
+
+	// the given phase is kept if it is worse than the phases of all the clusters
+	_, _, phase = SummarizePhases(map[string]APIResourcePhase{"es1": APIResourceErrorPhase}, phaseOf, APIResourceInvalidPhase)
+	require.Equal(t, APIResourceInvalidPhase, phase)
+}
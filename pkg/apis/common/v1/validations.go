@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	common_name "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
@@ -105,6 +106,158 @@ func CheckAssociationRefs(path *field.Path, refs ...ObjectSelector) field.ErrorL
 	return nil
 }
 
+// CheckIngress checks the Ingress template of the given HTTP configuration.
+func CheckIngress(path *field.Path, http HTTPConfig) field.ErrorList {
+	ingress := http.Ingress
+	if ingress == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if ingress.Host == "" {
+		if len(http.Hostnames) == 0 {
+			errs = append(errs, field.Required(path.Child("host"), "Ingress host is mandatory if no hostnames are set"))
+		}
+	} else {
+		for _, msg := range validation.IsDNS1123Subdomain(strings.TrimPrefix(ingress.Host, "*.")) {
+			errs = append(errs, field.Invalid(path.Child("host"), ingress.Host, msg))
+		}
+	}
+	if ingress.Path != "" && !strings.HasPrefix(ingress.Path, "/") {
+		errs = append(errs, field.Invalid(path.Child("path"), ingress.Path, "Ingress path must be absolute"))
+	}
+	if ingress.TLS != nil && ingress.TLS.SecretName == "" && !http.TLS.Enabled() {
+		errs = append(errs, field.Forbidden(path.Child("tls"), "the HTTP certificate can only be presented by the Ingress if TLS is enabled, set tls.secretName instead"))
+	}
+	return errs
+}
+
+// CheckHostnames checks the public hostnames of the given HTTP configuration.
+func CheckHostnames(path *field.Path, http HTTPConfig) field.ErrorList {
+	var errs field.ErrorList
+	for i, hostname := range http.Hostnames {
+		for _, msg := range validation.IsDNS1123Subdomain(strings.TrimPrefix(hostname, "*.")) {
+			errs = append(errs, field.Invalid(path.Index(i), hostname, msg))
+		}
+	}
+	return errs
+}
+
+// CheckServiceMesh checks that TLS is not configured at the application level when provided by a service mesh.
+func CheckServiceMesh(path *field.Path, tls TLSOptions) field.ErrorList {
+	if !tls.ServiceMeshEnabled() || tls.Certificate.SecretName == "" {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(path.Child("certificate"), "a custom certificate cannot be used when TLS is provided by the service mesh")}
+}
+
+// CheckGatewayRoute checks the Gateway route template of the given HTTP configuration.
+func CheckGatewayRoute(path *field.Path, http HTTPConfig) field.ErrorList {
+	route := http.GatewayRoute
+	if route == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if len(route.ParentRefs) == 0 {
+		errs = append(errs, field.Required(path.Child("parentRefs"), "at least one Gateway is mandatory"))
+	}
+	for i, ref := range route.ParentRefs {
+		if ref.Name == "" {
+			errs = append(errs, field.Required(path.Child("parentRefs").Index(i).Child("name"), "Gateway name is mandatory"))
+		}
+	}
+	for i, hostname := range route.Hostnames {
+		for _, msg := range validation.IsDNS1123Subdomain(strings.TrimPrefix(hostname, "*.")) {
+			errs = append(errs, field.Invalid(path.Child("hostnames").Index(i), hostname, msg))
+		}
+	}
+	if route.TLSModeOrDefault() == GatewayTLSPassthroughMode {
+		if len(http.GatewayRouteHostnames()) == 0 {
+			errs = append(errs, field.Required(path.Child("hostnames"), "hostnames are mandatory with the Passthrough TLS mode"))
+		}
+		if !http.TLS.Enabled() {
+			errs = append(errs, field.Forbidden(path.Child("tlsMode"), "the Passthrough TLS mode requires TLS to be enabled"))
+		}
+	}
+	return errs
+}
+
+// CheckSecretSources checks that each of the given secret sources references either a Kubernetes Secret or, if
+// allowed, an external secret within the path prefix of its provider in the given namespace.
+func CheckSecretSources(path *field.Path, sources []SecretSource, namespace string, allowExternal bool) field.ErrorList {
+	var errs field.ErrorList
+	for i, source := range sources {
+		sourcePath := path.Index(i)
+		switch {
+		case source.External == nil && source.SecretName == "":
+			errs = append(errs, field.Required(sourcePath.Child("secretName"), "secretName or external must be set"))
+		case source.External == nil:
+			continue
+		case !allowExternal:
+			errs = append(errs, field.Forbidden(sourcePath.Child("external"), "external secrets are not supported here, use secretName instead"))
+		case source.SecretName != "":
+			errs = append(errs, field.Forbidden(sourcePath, "secretName and external cannot be both set"))
+		case source.External.RefreshIntervalOrDefault() < MinExternalSecretRefreshInterval:
+			errs = append(errs, field.Invalid(sourcePath.Child("external", "refreshInterval"), source.External.RefreshInterval.Duration.String(),
+				fmt.Sprintf("refresh interval cannot be less than %s", MinExternalSecretRefreshInterval)))
+		default:
+			if err := source.External.CheckPathScope(namespace); err != nil {
+				errs = append(errs, field.Forbidden(sourcePath.Child("external", "path"), err.Error()))
+			}
+		}
+	}
+	return errs
+}
+
+// CheckCertificateRotation checks that the given certificate rotation settings are positive durations and that
+// certificates and CAs are not rotated before they are issued.
+func CheckCertificateRotation(path *field.Path, rotation *CertificateRotation) field.ErrorList {
+	if rotation == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	for _, d := range []struct {
+		name  string
+		value *metav1.Duration
+	}{
+		{"certificateValidity", rotation.CertificateValidity},
+		{"certificateRotateBefore", rotation.CertificateRotateBefore},
+		{"caValidity", rotation.CAValidity},
+		{"caRotateBefore", rotation.CARotateBefore},
+	} {
+		if d.value != nil && d.value.Duration <= 0 {
+			errs = append(errs, field.Invalid(path.Child(d.name), d.value.Duration.String(), "must be a positive duration"))
+		}
+	}
+	if rotation.CertificateValidity != nil && rotation.CertificateRotateBefore != nil &&
+		rotation.CertificateRotateBefore.Duration >= rotation.CertificateValidity.Duration {
+		errs = append(errs, field.Invalid(path.Child("certificateRotateBefore"), rotation.CertificateRotateBefore.Duration.String(),
+			"must be less than certificateValidity"))
+	}
+	if rotation.CAValidity != nil && rotation.CARotateBefore != nil &&
+		rotation.CARotateBefore.Duration >= rotation.CAValidity.Duration {
+		errs = append(errs, field.Invalid(path.Child("caRotateBefore"), rotation.CARotateBefore.Duration.String(),
+			"must be less than caValidity"))
+	}
+	return errs
+}
+
+// CheckDaemonSetVariants checks that the names of the given DaemonSet variants are unique and can be used in the names
+// of the DaemonSets and in labels.
+func CheckDaemonSetVariants(path *field.Path, variants []DaemonSetVariant) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(variants))
+	for i, variant := range variants {
+		if _, exists := names[variant.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Index(i).Child("name"), variant.Name))
+		}
+		names[variant.Name] = struct{}{}
+		for _, msg := range validation.IsDNS1123Label(variant.Name) {
+			errs = append(errs, field.Invalid(path.Index(i).Child("name"), variant.Name, msg))
+		}
+	}
+	return errs
+}
+
 func ParseVersion(ver string) (*version.Version, field.ErrorList) {
 	v, err := version.Parse(ver)
 	if err != nil {
@@ -0,0 +1,410 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestCheckIngress(t *testing.T) {
+	tlsDisabled := TLSOptions{SelfSignedCertificate: &SelfSignedCertificate{Disabled: true}}
+	tests := []struct {
+		name    string
+		http    HTTPConfig
+		wantErr string
+	}{
+		{
+			name: "no Ingress is OK",
+			http: HTTPConfig{},
+		},
+		{
+			name: "Ingress presenting the HTTP certificate is OK",
+			http: HTTPConfig{Ingress: &IngressTemplate{Host: "es.example.com", Path: "/es", TLS: &IngressTLSOptions{}}},
+		},
+		{
+			name: "wildcard host is OK",
+			http: HTTPConfig{Ingress: &IngressTemplate{Host: "*.example.com"}},
+		},
+		{
+			name: "custom certificate with TLS disabled is OK",
+			http: HTTPConfig{TLS: tlsDisabled, Ingress: &IngressTemplate{Host: "es.example.com", TLS: &IngressTLSOptions{SecretName: "es-cert"}}},
+		},
+		{
+			name: "missing host with public hostnames is OK",
+			http: HTTPConfig{Hostnames: []string{"es.example.com"}, Ingress: &IngressTemplate{}},
+		},
+		{
+			name:    "missing host is NOK",
+			http:    HTTPConfig{Ingress: &IngressTemplate{}},
+			wantErr: "Ingress host is mandatory",
+		},
+		{
+			name:    "invalid host is NOK",
+			http:    HTTPConfig{Ingress: &IngressTemplate{Host: "ES_example"}},
+			wantErr: "spec.http.ingress.host: Invalid value",
+		},
+		{
+			name:    "relative path is NOK",
+			http:    HTTPConfig{Ingress: &IngressTemplate{Host: "es.example.com", Path: "es"}},
+			wantErr: "Ingress path must be absolute",
+		},
+		{
+			name:    "HTTP certificate with TLS disabled is NOK",
+			http:    HTTPConfig{TLS: tlsDisabled, Ingress: &IngressTemplate{Host: "es.example.com", TLS: &IngressTLSOptions{}}},
+			wantErr: "the HTTP certificate can only be presented by the Ingress if TLS is enabled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckIngress(field.NewPath("spec").Child("http", "ingress"), tt.http)
+			if tt.wantErr == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Contains(t, got[0].Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckGatewayRoute(t *testing.T) {
+	tlsDisabled := TLSOptions{SelfSignedCertificate: &SelfSignedCertificate{Disabled: true}}
+	gateways := []GatewayParentRef{{Name: "gateway", Namespace: "infra", SectionName: "https"}}
+	tests := []struct {
+		name    string
+		http    HTTPConfig
+		wantErr string
+	}{
+		{
+			name: "no route is OK",
+			http: HTTPConfig{},
+		},
+		{
+			name: "route without hostnames is OK",
+			http: HTTPConfig{GatewayRoute: &GatewayRouteTemplate{ParentRefs: gateways}},
+		},
+		{
+			name: "Passthrough route with wildcard hostname is OK",
+			http: HTTPConfig{GatewayRoute: &GatewayRouteTemplate{ParentRefs: gateways, Hostnames: []string{"*.example.com"}, TLSMode: GatewayTLSPassthroughMode}},
+		},
+		{
+			name: "Terminate route with TLS disabled is OK",
+			http: HTTPConfig{TLS: tlsDisabled, GatewayRoute: &GatewayRouteTemplate{ParentRefs: gateways, TLSMode: GatewayTLSTerminateMode}},
+		},
+		{
+			name:    "missing Gateway is NOK",
+			http:    HTTPConfig{GatewayRoute: &GatewayRouteTemplate{}},
+			wantErr: "at least one Gateway is mandatory",
+		},
+		{
+			name:    "missing Gateway name is NOK",
+			http:    HTTPConfig{GatewayRoute: &GatewayRouteTemplate{ParentRefs: []GatewayParentRef{{Namespace: "infra"}}}},
+			wantErr: "spec.http.gatewayRoute.parentRefs[0].name: Required value",
+		},
+		{
+			name:    "invalid hostname is NOK",
+			http:    HTTPConfig{GatewayRoute: &GatewayRouteTemplate{ParentRefs: gateways, Hostnames: []string{"ES_example"}}},
+			wantErr: "spec.http.gatewayRoute.hostnames[0]: Invalid value",
+		},
+		{
+			name:    "Passthrough route without hostnames is NOK",
+			http:    HTTPConfig{GatewayRoute: &GatewayRouteTemplate{ParentRefs: gateways, TLSMode: GatewayTLSPassthroughMode}},
+			wantErr: "hostnames are mandatory with the Passthrough TLS mode",
+		},
+		{
+			name: "Passthrough route with public hostnames is OK",
+			http: HTTPConfig{Hostnames: []string{"es.example.com"}, GatewayRoute: &GatewayRouteTemplate{ParentRefs: gateways, TLSMode: GatewayTLSPassthroughMode}},
+		},
+		{
+			name:    "Passthrough route with TLS disabled is NOK",
+			http:    HTTPConfig{TLS: tlsDisabled, GatewayRoute: &GatewayRouteTemplate{ParentRefs: gateways, Hostnames: []string{"es.example.com"}, TLSMode: GatewayTLSPassthroughMode}},
+			wantErr: "the Passthrough TLS mode requires TLS to be enabled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckGatewayRoute(field.NewPath("spec").Child("http", "gatewayRoute"), tt.http)
+			if tt.wantErr == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Contains(t, got[0].Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckServiceMesh(t *testing.T) {
+	mesh := &ServiceMeshOptions{Provider: LinkerdServiceMeshProvider}
+	tests := []struct {
+		name    string
+		tls     TLSOptions
+		wantErr string
+	}{
+		{
+			name: "no service mesh is OK",
+			tls:  TLSOptions{Certificate: SecretRef{SecretName: "es-cert"}},
+		},
+		{
+			name: "service mesh is OK",
+			tls:  TLSOptions{ServiceMesh: mesh},
+		},
+		{
+			name:    "service mesh with a custom certificate is NOK",
+			tls:     TLSOptions{ServiceMesh: mesh, Certificate: SecretRef{SecretName: "es-cert"}},
+			wantErr: "spec.http.tls.certificate: Forbidden: a custom certificate cannot be used when TLS is provided by the service mesh",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckServiceMesh(field.NewPath("spec").Child("http", "tls"), tt.tls)
+			if tt.wantErr == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Contains(t, got[0].Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckHostnames(t *testing.T) {
+	tests := []struct {
+		name    string
+		http    HTTPConfig
+		wantErr string
+	}{
+		{
+			name: "no hostnames is OK",
+			http: HTTPConfig{},
+		},
+		{
+			name: "valid hostnames are OK",
+			http: HTTPConfig{Hostnames: []string{"es.example.com", "*.es.example.com"}},
+		},
+		{
+			name:    "invalid hostname is NOK",
+			http:    HTTPConfig{Hostnames: []string{"es.example.com", "ES_example"}},
+			wantErr: "spec.http.hostnames[1]: Invalid value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckHostnames(field.NewPath("spec").Child("http", "hostnames"), tt.http)
+			if tt.wantErr == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Contains(t, got[0].Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckDaemonSetVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants []DaemonSetVariant
+		wantErr  string
+	}{
+		{
+			name: "no variants is OK",
+		},
+		{
+			name:     "valid variants are OK",
+			variants: []DaemonSetVariant{{Name: "amd64"}, {Name: "arm64"}},
+		},
+		{
+			name:     "duplicate name is NOK",
+			variants: []DaemonSetVariant{{Name: "amd64"}, {Name: "amd64"}},
+			wantErr:  "spec.daemonSet.variants[1].name: Duplicate value",
+		},
+		{
+			name:     "invalid name is NOK",
+			variants: []DaemonSetVariant{{Name: "Windows_Nodes"}},
+			wantErr:  "spec.daemonSet.variants[0].name: Invalid value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckDaemonSetVariants(field.NewPath("spec").Child("daemonSet", "variants"), tt.variants)
+			if tt.wantErr == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Contains(t, got[0].Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckSecretSources(t *testing.T) {
+	require.NoError(t, SetExternalSecretPathPrefixes(map[ExternalSecretProvider]string{
+		VaultSecretProvider: "secret/data/eck/{namespace}/",
+		AWSSecretProvider:   "eck/{namespace}/",
+	}))
+	t.Cleanup(func() { require.NoError(t, SetExternalSecretPathPrefixes(nil)) })
+	vault := &ExternalSecretSource{Provider: VaultSecretProvider, Path: "secret/data/eck/ns/es"}
+	tests := []struct {
+		name          string
+		sources       []SecretSource
+		allowExternal bool
+		wantErr       string
+	}{
+		{
+			name:    "Kubernetes Secret is OK",
+			sources: []SecretSource{{SecretName: "es-secure-settings"}},
+		},
+		{
+			name:          "external secret is OK",
+			sources:       []SecretSource{{External: vault}},
+			allowExternal: true,
+		},
+		{
+			name:    "no secret is NOK",
+			sources: []SecretSource{{SecretName: "es-secure-settings"}, {}},
+			wantErr: "spec.secureSettings[1].secretName: Required value",
+		},
+		{
+			name:    "external secret is NOK if not allowed",
+			sources: []SecretSource{{External: vault}},
+			wantErr: "spec.secureSettings[0].external: Forbidden: external secrets are not supported here",
+		},
+		{
+			name:          "Kubernetes Secret and external secret is NOK",
+			sources:       []SecretSource{{SecretName: "es-secure-settings", External: vault}},
+			allowExternal: true,
+			wantErr:       "spec.secureSettings[0]: Forbidden: secretName and external cannot be both set",
+		},
+		{
+			name: "short refresh interval is NOK",
+			sources: []SecretSource{{External: &ExternalSecretSource{
+				Provider: AWSSecretProvider, Path: "eck/ns/es", RefreshInterval: &metav1.Duration{Duration: 10 * time.Second},
+			}}},
+			allowExternal: true,
+			wantErr:       "spec.secureSettings[0].external.refreshInterval: Invalid value: \"10s\": refresh interval cannot be less than 1m0s",
+		},
+		{
+			name:          "external secret of another namespace is NOK",
+			sources:       []SecretSource{{External: &ExternalSecretSource{Provider: VaultSecretProvider, Path: "secret/data/eck/other/es"}}},
+			allowExternal: true,
+			wantErr:       "spec.secureSettings[0].external.path: Forbidden: path secret/data/eck/other/es is not allowed in namespace ns, it must start with secret/data/eck/ns/",
+		},
+		{
+			name:          "external secret of a provider without prefix is NOK",
+			sources:       []SecretSource{{External: &ExternalSecretSource{Provider: GCPSecretProvider, Path: "projects/p/secrets/es"}}},
+			allowExternal: true,
+			wantErr:       "spec.secureSettings[0].external.path: Forbidden: no path prefix is configured for external secret provider gcp",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckSecretSources(field.NewPath("spec").Child("secureSettings"), tt.sources, "ns", tt.allowExternal)
+			if tt.wantErr == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Contains(t, got[0].Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSetExternalSecretPathPrefixes(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, SetExternalSecretPathPrefixes(nil)) })
+	for _, tt := range []struct {
+		prefix  string
+		wantErr string
+	}{
+		{prefix: "secret/data/eck/{namespace}/"},
+		{prefix: "projects/p/secrets/{namespace}_"},
+		{prefix: "secret/data/eck/", wantErr: "must contain {namespace} exactly once"},
+		{prefix: "{namespace}/{namespace}/", wantErr: "must contain {namespace} exactly once"},
+		{prefix: "secret/data/eck/{namespace}", wantErr: "must be followed by a separator"},
+		{prefix: "eck/{namespace}-", wantErr: "must be followed by a separator"},
+	} {
+		t.Run(tt.prefix, func(t *testing.T) {
+			err := SetExternalSecretPathPrefixes(map[ExternalSecretProvider]string{VaultSecretProvider: tt.prefix})
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestExternalSecretSource_CheckPathScope(t *testing.T) {
+	require.NoError(t, SetExternalSecretPathPrefixes(map[ExternalSecretProvider]string{VaultSecretProvider: "secret/data/eck/{namespace}/"}))
+	t.Cleanup(func() { require.NoError(t, SetExternalSecretPathPrefixes(nil)) })
+	for _, tt := range []struct {
+		path    string
+		wantErr bool
+	}{
+		{path: "secret/data/eck/a/es"},
+		{path: "secret/data/eck/a/nested/es"},
+		{path: "secret/data/eck/a/", wantErr: true},
+		{path: "secret/data/eck/a-b/es", wantErr: true},
+		{path: "secret/data/eck/b/es", wantErr: true},
+		{path: "secret/data/eck/a/../b/es", wantErr: true},
+		{path: "secret/data/eck/a/%2e%2e/b/es", wantErr: true},
+		{path: "secret/data/other", wantErr: true},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			err := ExternalSecretSource{Provider: VaultSecretProvider, Path: tt.path}.CheckPathScope("a")
+			require.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestCheckCertificateRotation(t *testing.T) {
+	duration := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+	tests := []struct {
+		name     string
+		rotation *CertificateRotation
+		wantErr  string
+	}{
+		{
+			name: "no rotation settings is OK",
+		},
+		{
+			name: "rotation settings are OK",
+			rotation: &CertificateRotation{
+				CertificateValidity:     duration(90 * 24 * time.Hour),
+				CertificateRotateBefore: duration(7 * 24 * time.Hour),
+				CAValidity:              duration(365 * 24 * time.Hour),
+			},
+		},
+		{
+			name:     "negative duration is NOK",
+			rotation: &CertificateRotation{CARotateBefore: duration(-time.Hour)},
+			wantErr:  "spec.http.tls.certificateRotation.caRotateBefore: Invalid value: \"-1h0m0s\": must be a positive duration",
+		},
+		{
+			name: "rotation before issuance is NOK",
+			rotation: &CertificateRotation{
+				CertificateValidity:     duration(24 * time.Hour),
+				CertificateRotateBefore: duration(48 * time.Hour),
+			},
+			wantErr: "spec.http.tls.certificateRotation.certificateRotateBefore: Invalid value: \"48h0m0s\": must be less than certificateValidity",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckCertificateRotation(field.NewPath("spec").Child("http", "tls", "certificateRotation"), tt.rotation)
+			if tt.wantErr == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Contains(t, got[0].Error(), tt.wantErr)
+		})
+	}
+}
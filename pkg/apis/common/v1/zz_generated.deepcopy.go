@@ -8,7 +8,10 @@
 
 package v1
 
-import ()
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AssociationConf) DeepCopyInto(out *AssociationConf) {
@@ -56,6 +59,41 @@ func (in *Config) DeepCopy() *Config {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRotation) DeepCopyInto(out *CertificateRotation) {
+	*out = *in
+	if in.CertificateValidity != nil {
+		in, out := &in.CertificateValidity, &out.CertificateValidity
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CertificateRotateBefore != nil {
+		in, out := &in.CertificateRotateBefore, &out.CertificateRotateBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CAValidity != nil {
+		in, out := &in.CAValidity, &out.CAValidity
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CARotateBefore != nil {
+		in, out := &in.CARotateBefore, &out.CARotateBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRotation.
+func (in *CertificateRotation) DeepCopy() *CertificateRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapRef) DeepCopyInto(out *ConfigMapRef) {
 	*out = *in
@@ -87,6 +125,35 @@ func (in *ConfigSource) DeepCopy() *ConfigSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonSetVariant) DeepCopyInto(out *DaemonSetVariant) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetVariant.
+func (in *DaemonSetVariant) DeepCopy() *DaemonSetVariant {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonSetVariant)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentStatus) DeepCopyInto(out *DeploymentStatus) {
 	*out = *in
@@ -102,11 +169,87 @@ func (in *DeploymentStatus) DeepCopy() *DeploymentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretSource) DeepCopyInto(out *ExternalSecretSource) {
+	*out = *in
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretSource.
+func (in *ExternalSecretSource) DeepCopy() *ExternalSecretSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayParentRef) DeepCopyInto(out *GatewayParentRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayParentRef.
+func (in *GatewayParentRef) DeepCopy() *GatewayParentRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayParentRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayRouteTemplate) DeepCopyInto(out *GatewayRouteTemplate) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.ParentRefs != nil {
+		in, out := &in.ParentRefs, &out.ParentRefs
+		*out = make([]GatewayParentRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRouteTemplate.
+func (in *GatewayRouteTemplate) DeepCopy() *GatewayRouteTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayRouteTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPConfig) DeepCopyInto(out *HTTPConfig) {
 	*out = *in
 	in.Service.DeepCopyInto(&out.Service)
 	in.TLS.DeepCopyInto(&out.TLS)
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GatewayRoute != nil {
+		in, out := &in.GatewayRoute, &out.GatewayRoute
+		*out = new(GatewayRouteTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPConfig.
@@ -119,6 +262,47 @@ func (in *HTTPConfig) DeepCopy() *HTTPConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressTLSOptions) DeepCopyInto(out *IngressTLSOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressTLSOptions.
+func (in *IngressTLSOptions) DeepCopy() *IngressTLSOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressTLSOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressTemplate) DeepCopyInto(out *IngressTemplate) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.ClassName != nil {
+		in, out := &in.ClassName, &out.ClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(IngressTLSOptions)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressTemplate.
+func (in *IngressTemplate) DeepCopy() *IngressTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyToPath) DeepCopyInto(out *KeyToPath) {
 	*out = *in
@@ -169,6 +353,22 @@ func (in *LogsMonitoring) DeepCopy() *LogsMonitoring {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricsMonitoring) DeepCopyInto(out *MetricsMonitoring) {
 	*out = *in
@@ -194,6 +394,11 @@ func (in *Monitoring) DeepCopyInto(out *Monitoring) {
 	*out = *in
 	in.Metrics.DeepCopyInto(&out.Metrics)
 	in.Logs.DeepCopyInto(&out.Logs)
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusMonitoring)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Monitoring.
@@ -258,6 +463,42 @@ func (in *PodDisruptionBudgetTemplate) DeepCopy() *PodDisruptionBudgetTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusExporter) DeepCopyInto(out *PrometheusExporter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusExporter.
+func (in *PrometheusExporter) DeepCopy() *PrometheusExporter {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusExporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusMonitoring) DeepCopyInto(out *PrometheusMonitoring) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Exporter != nil {
+		in, out := &in.Exporter, &out.Exporter
+		*out = new(PrometheusExporter)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusMonitoring.
+func (in *PrometheusMonitoring) DeepCopy() *PrometheusMonitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusMonitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretRef) DeepCopyInto(out *SecretRef) {
 	*out = *in
@@ -281,6 +522,11 @@ func (in *SecretSource) DeepCopyInto(out *SecretSource) {
 		*out = make([]KeyToPath, len(*in))
 		copy(*out, *in)
 	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalSecretSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSource.
@@ -313,6 +559,21 @@ func (in *SelfSignedCertificate) DeepCopy() *SelfSignedCertificate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMeshOptions) DeepCopyInto(out *ServiceMeshOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMeshOptions.
+func (in *ServiceMeshOptions) DeepCopy() *ServiceMeshOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMeshOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceTemplate) DeepCopyInto(out *ServiceTemplate) {
 	*out = *in
@@ -354,6 +615,16 @@ func (in *TLSOptions) DeepCopyInto(out *TLSOptions) {
 		(*in).DeepCopyInto(*out)
 	}
 	out.Certificate = in.Certificate
+	if in.ServiceMesh != nil {
+		in, out := &in.ServiceMesh, &out.ServiceMesh
+		*out = new(ServiceMeshOptions)
+		**out = **in
+	}
+	if in.CertificateRotation != nil {
+		in, out := &in.CertificateRotation, &out.CertificateRotation
+		*out = new(CertificateRotation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSOptions.
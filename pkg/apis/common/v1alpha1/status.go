@@ -18,6 +18,9 @@ type Condition struct {
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 	// +optional
 	Message string `json:"message,omitempty"`
+	// Reason is a machine-readable explanation of the status of the condition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
 }
 
 type Conditions []Condition
@@ -41,7 +44,8 @@ func (c Conditions) MergeWith(nextConditions ...Condition) Conditions {
 		if index := cp.Index(nextCondition.Type); index >= 0 {
 			currentCondition := c[index]
 			if currentCondition.Status != nextCondition.Status ||
-				currentCondition.Message != nextCondition.Message {
+				currentCondition.Message != nextCondition.Message ||
+				currentCondition.Reason != nextCondition.Reason {
 				// Update condition
 				cp[index] = nextCondition
 			}
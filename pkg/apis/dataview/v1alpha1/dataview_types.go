@@ -0,0 +1,168 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// DataViewKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	DataViewKind = "DataView"
+
+	// DefaultSpaceID is the id of the default Kibana space.
+	DefaultSpaceID = "default"
+)
+
+func init() {
+	SchemeBuilder.Register(&DataView{}, &DataViewList{})
+}
+
+// +kubebuilder:object:root=true
+
+// DataView represents a Kibana data view, which lets dashboards, visualizations and Discover query Elasticsearch indices.
+// +kubebuilder:resource:categories=elastic,shortName=kbdataview
+// +kubebuilder:printcolumn:name="Kibana",type="string",JSONPath=".spec.kibanaRef.name"
+// +kubebuilder:printcolumn:name="Index pattern",type="string",JSONPath=".spec.indexPattern"
+// +kubebuilder:printcolumn:name="Space",type="string",JSONPath=".status.spaceID"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type DataView struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DataViewSpec   `json:"spec,omitempty"`
+	Status DataViewStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DataViewList contains a list of DataView resources.
+type DataViewList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DataView `json:"items"`
+}
+
+type DataViewSpec struct {
+	// KibanaRef is a reference to the Kibana instance, in the same namespace as the DataView, the data view is
+	// configured on. Kibana must be associated with an Elasticsearch cluster managed by the operator.
+	// It cannot be changed once the DataView is created.
+	KibanaRef commonv1.LocalObjectSelector `json:"kibanaRef"`
+
+	// SpaceID is the id of the Kibana space the data view is created in. Defaults to the default space.
+	// It cannot be changed once the DataView is created.
+	// +kubebuilder:validation:Optional
+	SpaceID string `json:"spaceID,omitempty"`
+
+	// DataViewID is the id of the data view in Kibana. Defaults to the name of the DataView.
+	// It cannot be changed once the DataView is created.
+	// +kubebuilder:validation:Optional
+	DataViewID string `json:"dataViewID,omitempty"`
+
+	// Title is the name of the data view displayed in Kibana. Defaults to the index pattern.
+	// +kubebuilder:validation:Optional
+	Title string `json:"title,omitempty"`
+
+	// IndexPattern is the pattern of the indices, data streams and aliases the data view queries, for example logs-*.
+	// Several patterns can be separated by commas.
+	IndexPattern string `json:"indexPattern"`
+
+	// TimeFieldName is the name of the field used to filter the documents by time.
+	// +kubebuilder:validation:Optional
+	TimeFieldName string `json:"timeFieldName,omitempty"`
+
+	// RuntimeFields are the fields computed by a script when the data view is queried.
+	// +kubebuilder:validation:Optional
+	RuntimeFields []RuntimeField `json:"runtimeFields,omitempty"`
+
+	// Default sets the data view as the default data view of the space. Only one DataView can be the default data
+	// view of a space.
+	// +kubebuilder:validation:Optional
+	Default bool `json:"default,omitempty"`
+}
+
+// RuntimeField is a field of a data view computed by a script.
+type RuntimeField struct {
+	// Name is the name of the field.
+	Name string `json:"name"`
+	// Type is the type of the values emitted by the script.
+	// +kubebuilder:validation:Enum=keyword;long;double;date;ip;boolean;geo_point
+	Type string `json:"type"`
+	// Script is the Painless script computing the value of the field, for example emit(doc['price'].value * 2).
+	Script string `json:"script"`
+}
+
+type DataViewStatus struct {
+	// Phase is the phase of the DataView.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the data view is not configured yet, or why its configuration failed.
+	Message string `json:"message,omitempty"`
+	// DataViewID is the id of the data view in Kibana.
+	DataViewID string `json:"dataViewID,omitempty"`
+	// SpaceID is the id of the Kibana space of the data view.
+	SpaceID string `json:"spaceID,omitempty"`
+	// Default is true if the data view was set as the default data view of its space by the operator.
+	Default bool `json:"default,omitempty"`
+	// LastDriftTime is the last time the data view was found modified outside of the operator and restored.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this DataView.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// Phase is the phase of a DataView.
+type Phase = commonv1.APIResourcePhase
+
+const (
+	ReadyPhase           = commonv1.APIResourceReadyPhase
+	ApplyingChangesPhase = commonv1.APIResourceApplyingChangesPhase
+	ErrorPhase           = commonv1.APIResourceErrorPhase
+	InvalidPhase         = commonv1.APIResourceInvalidPhase
+)
+
+// DataViewIDOrDefault returns the id of the data view in Kibana.
+func (d *DataView) DataViewIDOrDefault() string {
+	if d.Spec.DataViewID != "" {
+		return d.Spec.DataViewID
+	}
+	return d.Name
+}
+
+// SpaceIDOrDefault returns the id of the Kibana space of the data view.
+func (d *DataView) SpaceIDOrDefault() string {
+	if d.Spec.SpaceID != "" {
+		return d.Spec.SpaceID
+	}
+	return DefaultSpaceID
+}
+
+// TitleOrDefault returns the name of the data view displayed in Kibana.
+func (d *DataView) TitleOrDefault() string {
+	if d.Spec.Title != "" {
+		return d.Spec.Title
+	}
+	return d.Spec.IndexPattern
+}
+
+// References returns true if the data view is configured on the given Kibana.
+func (d *DataView) References(kb types.NamespacedName) bool {
+	return d.Spec.KibanaRef.WithDefaultNamespace(d.Namespace).NamespacedName() == kb
+}
+
+// IsMarkedForDeletion returns true if the DataView resource is going to be deleted.
+func (d *DataView) IsMarkedForDeletion() bool {
+	return !d.DeletionTimestamp.IsZero()
+}
+
+// IsDegraded returns true when the DataViewStatus is degraded compared to the previous status.
+func (s DataViewStatus) IsDegraded(prev DataViewStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
@@ -0,0 +1,191 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	// dataViewWebhookPath is the HTTP path for the DataView validating webhook.
+	dataViewWebhookPath = "/validate-dataview-k8s-elastic-co-v1alpha1-dataviews"
+
+	crossNamespaceRefErrMsg       = "Kibana must be in the same namespace as the resource"
+	serviceNameNotSupportedErrMsg = "a custom service is not supported to reach Kibana"
+	invalidSpaceIDErrMsg          = "must contain lowercase alphanumeric characters, hyphens and underscores"
+	kibanaChangeErrMsg            = "the Kibana instance of the data view cannot be changed"
+	spaceIDChangeErrMsg           = "the space of the data view cannot be changed"
+	dataViewIDChangeErrMsg        = "the data view id cannot be changed"
+)
+
+var (
+	dataViewGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: DataViewKind}
+	validationLog     = ulog.Log.WithName("dataview-v1alpha1-validation")
+
+	// spaceIDRegexp matches the space ids accepted by Kibana.
+	spaceIDRegexp = regexp.MustCompile(`^[a-z0-9_\-]+$`)
+
+	dataViewDefaultChecks = []func(*DataView) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		validKibanaRef,
+		validSpaceID,
+		validIndexPattern,
+		validRuntimeFields,
+	}
+
+	dataViewUpdateChecks = []func(old, curr *DataView) field.ErrorList{
+		checkKibanaRefChange,
+		checkSpaceIDChange,
+		checkDataViewIDChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-dataview-k8s-elastic-co-v1alpha1-dataviews,mutating=false,failurePolicy=ignore,groups=dataview.k8s.elastic.co,resources=dataviews,verbs=create;update,versions=v1alpha1,name=elastic-dataview-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &DataView{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (d *DataView) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", d.Name)
+	return d.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (d *DataView) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", d.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (d *DataView) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", d.Name)
+	oldObj, ok := old.(*DataView)
+	if !ok {
+		return nil, errors.New("cannot cast old object to DataView type")
+	}
+	return d.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (d *DataView) WebhookPath() string {
+	return dataViewWebhookPath
+}
+
+func (d *DataView) validate(old *DataView) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range dataViewDefaultChecks {
+		if err := dc(d); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range dataViewUpdateChecks {
+			if err := uc(old, d); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(dataViewGroupKind, d.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkNoUnknownFields(d *DataView) field.ErrorList {
+	return commonv1.NoUnknownFields(d, d.ObjectMeta)
+}
+
+func checkNameLength(d *DataView) field.ErrorList {
+	return commonv1.CheckNameLength(d)
+}
+
+// validKibanaRef validates the reference to Kibana, which must be in the namespace of the data view.
+func validKibanaRef(d *DataView) field.ErrorList {
+	path := field.NewPath("spec").Child("kibanaRef")
+	switch {
+	case d.Spec.KibanaRef.Name == "":
+		return field.ErrorList{field.Required(path.Child("name"), "Kibana name is mandatory")}
+	case d.Spec.KibanaRef.Namespace != "" && d.Spec.KibanaRef.Namespace != d.Namespace:
+		return field.ErrorList{field.Invalid(path.Child("namespace"), d.Spec.KibanaRef.Namespace, crossNamespaceRefErrMsg)}
+	case d.Spec.KibanaRef.ServiceName != "":
+		return field.ErrorList{field.Forbidden(path.Child("serviceName"), serviceNameNotSupportedErrMsg)}
+	}
+	return nil
+}
+
+func validSpaceID(d *DataView) field.ErrorList {
+	if !spaceIDRegexp.MatchString(d.SpaceIDOrDefault()) {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("spaceID"), d.Spec.SpaceID, invalidSpaceIDErrMsg)}
+	}
+	return nil
+}
+
+func validIndexPattern(d *DataView) field.ErrorList {
+	if d.Spec.IndexPattern == "" {
+		return field.ErrorList{field.Required(field.NewPath("spec").Child("indexPattern"), "index pattern is mandatory")}
+	}
+	return nil
+}
+
+// validRuntimeFields checks that runtime fields have a distinct name and a script, the script is compiled by
+// Elasticsearch when the data view is queried.
+func validRuntimeFields(d *DataView) field.ErrorList {
+	var errs field.ErrorList
+	names := set.Make()
+	for i, runtimeField := range d.Spec.RuntimeFields {
+		path := field.NewPath("spec").Child("runtimeFields").Index(i)
+		switch {
+		case runtimeField.Name == "":
+			errs = append(errs, field.Required(path.Child("name"), "runtime field name is mandatory"))
+		case names.Has(runtimeField.Name):
+			errs = append(errs, field.Duplicate(path.Child("name"), runtimeField.Name))
+		case runtimeField.Script == "":
+			errs = append(errs, field.Required(path.Child("script"), "runtime field script is mandatory"))
+		}
+		names.Add(runtimeField.Name)
+	}
+	return errs
+}
+
+func checkKibanaRefChange(old, curr *DataView) field.ErrorList {
+	if old.Spec.KibanaRef.Name != curr.Spec.KibanaRef.Name {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("kibanaRef"), kibanaChangeErrMsg)}
+	}
+	return nil
+}
+
+func checkSpaceIDChange(old, curr *DataView) field.ErrorList {
+	if old.SpaceIDOrDefault() != curr.SpaceIDOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("spaceID"), spaceIDChangeErrMsg)}
+	}
+	return nil
+}
+
+func checkDataViewIDChange(old, curr *DataView) field.ErrorList {
+	if old.DataViewIDOrDefault() != curr.DataViewIDOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("dataViewID"), dataViewIDChangeErrMsg)}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	dataviewv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/dataview/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkDataView(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-kibana-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				dv := mkDataView(uid)
+				dv.Spec.KibanaRef = commonv1.LocalObjectSelector{Namespace: "other", Name: "kb"}
+				return serialize(t, dv)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.kibanaRef.namespace: Invalid value: "other": Kibana must be in the same namespace as the resource`,
+			),
+		},
+		{
+			Name:      "invalid-space-and-index-pattern",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				dv := mkDataView(uid)
+				dv.Spec.SpaceID = "Team A"
+				dv.Spec.IndexPattern = ""
+				return serialize(t, dv)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.spaceID: Invalid value: "Team A": must contain lowercase alphanumeric characters, hyphens and underscores`,
+				`spec.indexPattern: Required value: index pattern is mandatory`,
+			),
+		},
+		{
+			Name:      "invalid-runtime-fields",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				dv := mkDataView(uid)
+				dv.Spec.RuntimeFields = append(dv.Spec.RuntimeFields,
+					dataviewv1alpha1.RuntimeField{Name: "day_of_week", Type: "keyword", Script: "emit('monday')"},
+					dataviewv1alpha1.RuntimeField{Name: "hour_of_day", Type: "long"},
+				)
+				return serialize(t, dv)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.runtimeFields\[1\].name: Duplicate value: "day_of_week"`,
+				`spec.runtimeFields\[2\].script: Required value: runtime field script is mandatory`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkDataView(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				dv := mkDataView(uid)
+				dv.Spec.DataViewID = dv.Name
+				dv.Spec.IndexPattern = "logs-*,metrics-*"
+				dv.Spec.Default = false
+				return serialize(t, dv)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-immutable-fields",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkDataView(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				dv := mkDataView(uid)
+				dv.Spec.KibanaRef.Name = "other-kb"
+				dv.Spec.SpaceID = "default"
+				dv.Spec.DataViewID = "other-logs"
+				return serialize(t, dv)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.kibanaRef: Forbidden: the Kibana instance of the data view cannot be changed`,
+				`spec.spaceID: Forbidden: the space of the data view cannot be changed`,
+				`spec.dataViewID: Forbidden: the data view id cannot be changed`,
+			),
+		},
+	}
+
+	validator := &dataviewv1alpha1.DataView{}
+	gvk := metav1.GroupVersionKind{Group: dataviewv1alpha1.GroupVersion.Group, Version: dataviewv1alpha1.GroupVersion.Version, Kind: dataviewv1alpha1.DataViewKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkDataView(uid string) *dataviewv1alpha1.DataView {
+	return &dataviewv1alpha1.DataView{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "logs",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: dataviewv1alpha1.DataViewSpec{
+			KibanaRef:     commonv1.LocalObjectSelector{Name: "kb"},
+			SpaceID:       "team-a",
+			Title:         "Application logs",
+			IndexPattern:  "logs-*",
+			TimeFieldName: "@timestamp",
+			RuntimeFields: []dataviewv1alpha1.RuntimeField{
+				{Name: "day_of_week", Type: "keyword", Script: "emit(doc['@timestamp'].value.dayOfWeekEnum.toString())"},
+			},
+			Default: true,
+		},
+	}
+}
+
+func serialize(t *testing.T, dataView *dataviewv1alpha1.DataView) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(dataView)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -5,11 +5,17 @@
 package v1
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -33,9 +39,32 @@ const (
 	DisableUpgradePredicatesAnnotation = "eck.k8s.elastic.co/disable-upgrade-predicates"
 	// DownwardNodeLabelsAnnotation holds an optional list of expected node labels to be set as annotations on the Elasticsearch Pods.
 	DownwardNodeLabelsAnnotation = "eck.k8s.elastic.co/downward-node-labels"
+	// RestartTriggerAnnotation allows users to trigger a rolling restart of all the nodes of the cluster. Every time its
+	// value changes, the nodes are restarted one after the other following the rolling upgrade orchestration, which
+	// respects the change budget and the upgrade predicates.
+	RestartTriggerAnnotation = "eck.k8s.elastic.co/restart"
 	// SuspendAnnotation allows users to annotate the Elasticsearch resource with the names of Pods they want to suspend
 	// for debugging purposes.
 	SuspendAnnotation = "eck.k8s.elastic.co/suspend"
+	// StorageClassMigrationAnnotation allows users to confirm that the nodeSets whose names are listed in its value
+	// can be moved to a new storage class. Changing the storage class of the volume claim templates of those nodeSets
+	// creates a new StatefulSet with new volumes, migrates the data to its Pods, then removes the former StatefulSet.
+	StorageClassMigrationAnnotation = "eck.k8s.elastic.co/migrate-storage-class"
+	// VolumeResizeMigrationAnnotation allows users to confirm that the nodeSets whose names are listed in its value can
+	// be moved to new volumes when the storage requests of their volume claim templates are increased but the storage
+	// class does not allow volume expansion. A new StatefulSet is then created with the larger volumes, the data is
+	// migrated to its Pods, and the former StatefulSet is removed, as for a storage class migration.
+	VolumeResizeMigrationAnnotation = "eck.k8s.elastic.co/migrate-volume-resize"
+	// StatefulSetNameSuffixesAnnotation is managed by the operator to record the suffix appended to the name of the
+	// StatefulSet of each nodeSet that has been moved to a new storage class, serialized as a JSON map.
+	StatefulSetNameSuffixesAnnotation = "eck.k8s.elastic.co/statefulset-name-suffixes"
+	// VolumeSnapshotPrepopulationAnnotation allows users to list the nodeSets whose missing volumes are created from the
+	// most recent ready VolumeSnapshot of the same volume claim, instead of being created empty.
+	VolumeSnapshotPrepopulationAnnotation = "eck.k8s.elastic.co/prepopulate-volumes-from-snapshots"
+	// DryRunExplainAnnotation allows users to get, in the warnings of the validating webhook response, a description of
+	// the changes the operator makes to the cluster to apply the specification. It is meant to be set with a server-side
+	// dry-run, to review a change before it is applied.
+	DryRunExplainAnnotation = "eck.k8s.elastic.co/dry-run-explain"
 	// ElasticsearchAutoscalingSpecAnnotationName is the name of the annotation used to store the autoscaling specification.
 	// Deprecated: the autoscaling annotation has been deprecated in favor of the ElasticsearchAutoscaler custom resource.
 	ElasticsearchAutoscalingSpecAnnotationName = "elasticsearch.alpha.elastic.co/autoscaling-spec"
@@ -86,6 +115,11 @@ type ElasticsearchSpec struct {
 	// +kubebuilder:validation:Optional
 	HTTP commonv1.HTTPConfig `json:"http,omitempty"`
 
+	// HTTPServiceProfiles define additional HTTP Services, each targeting the nodes with some roles. The selectors of
+	// the Services are kept in sync by the operator as the NodeSets change.
+	// +kubebuilder:validation:Optional
+	HTTPServiceProfiles []ServiceProfile `json:"httpServiceProfiles,omitempty"`
+
 	// Transport holds transport layer settings for Elasticsearch.
 	// +kubebuilder:validation:Optional
 	Transport TransportConfig `json:"transport,omitempty"`
@@ -109,10 +143,21 @@ type ElasticsearchSpec struct {
 	// +kubebuilder:validation:Optional
 	Auth Auth `json:"auth,omitempty"`
 
+	// Auditing enables the security audit log of Elasticsearch and optionally ships it to a monitoring cluster.
+	// +kubebuilder:validation:Optional
+	Auditing *Auditing `json:"auditing,omitempty"`
+
 	// SecureSettings is a list of references to Kubernetes secrets containing sensitive configuration options for Elasticsearch.
 	// +kubebuilder:validation:Optional
 	SecureSettings []commonv1.SecretSource `json:"secureSettings,omitempty"`
 
+	// KeystorePassword is a reference to the key of a Secret, in the same namespace as the Elasticsearch cluster,
+	// holding the password used to encrypt the keystore of the nodes. It is mounted in the Elasticsearch Pods and
+	// provided to Elasticsearch with the ES_KEYSTORE_PASSPHRASE_FILE environment variable. The keystore is not
+	// encrypted if not set.
+	// +kubebuilder:validation:Optional
+	KeystorePassword *SecretKeyRef `json:"keystorePassword,omitempty"`
+
 	// ServiceAccountName is used to check access from the current resource to a resource (for ex. a remote Elasticsearch cluster) in a different namespace.
 	// Can only be used if ECK is enforcing RBAC on references.
 	// +optional
@@ -123,9 +168,10 @@ type ElasticsearchSpec struct {
 	RemoteClusters []RemoteCluster `json:"remoteClusters,omitempty"`
 
 	// VolumeClaimDeletePolicy sets the policy for handling deletion of PersistentVolumeClaims for all NodeSets.
-	// Possible values are DeleteOnScaledownOnly and DeleteOnScaledownAndClusterDeletion. Defaults to DeleteOnScaledownAndClusterDeletion.
+	// Possible values are DeleteOnScaledownOnly, DeleteOnScaledownAndClusterDeletion, DeleteOnClusterDeletionOnly and Retain.
+	// Defaults to DeleteOnScaledownAndClusterDeletion. Can be overridden per NodeSet.
 	// +kubebuilder:validation:Optional
-	// +kubebuilder:validation:Enum=DeleteOnScaledownOnly;DeleteOnScaledownAndClusterDeletion
+	// +kubebuilder:validation:Enum=DeleteOnScaledownOnly;DeleteOnScaledownAndClusterDeletion;DeleteOnClusterDeletionOnly;Retain
 	VolumeClaimDeletePolicy VolumeClaimDeletePolicy `json:"volumeClaimDeletePolicy,omitempty"`
 
 	// Monitoring enables you to collect and ship log and monitoring data of this Elasticsearch cluster.
@@ -137,8 +183,221 @@ type ElasticsearchSpec struct {
 
 	// RevisionHistoryLimit is the number of revisions to retain to allow rollback in the underlying StatefulSets.
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// PreUpgradeVolumeSnapshots enables taking CSI VolumeSnapshots of the volumes of the cluster right before each
+	// version upgrade, as a coarse-grained rollback safety net. The upgrade only starts once all the snapshots are taken.
+	// +kubebuilder:validation:Optional
+	PreUpgradeVolumeSnapshots *PreUpgradeVolumeSnapshots `json:"preUpgradeVolumeSnapshots,omitempty"`
+
+	// SnapshotRepositories are snapshot repositories registered on the cluster once it is formed. Removing a repository
+	// from the list does not unregister it.
+	// +kubebuilder:validation:Optional
+	SnapshotRepositories []SnapshotRepository `json:"snapshotRepositories,omitempty"`
+
+	// SnapshotLifecyclePolicies are snapshot lifecycle management (SLM) policies configured on the cluster once it is
+	// formed. Removing a policy from the list deletes it from the cluster, without deleting the snapshots it took.
+	// +kubebuilder:validation:Optional
+	SnapshotLifecyclePolicies []SnapshotLifecyclePolicy `json:"snapshotLifecyclePolicies,omitempty"`
+
+	// InitialRestore restores a snapshot into the cluster when it is created, before the cluster is reported as ready.
+	// It can only be set when the cluster is created.
+	// +kubebuilder:validation:Optional
+	InitialRestore *InitialRestore `json:"initialRestore,omitempty"`
+
+	// FinalSnapshot takes a snapshot of the cluster when the Elasticsearch resource is deleted. The deletion only
+	// proceeds once the snapshot is successfully completed.
+	// +kubebuilder:validation:Optional
+	FinalSnapshot *FinalSnapshot `json:"finalSnapshot,omitempty"`
+
+	// MaintenanceWindows restrict the rolling upgrades and restarts of the Pods to the given periods of time. The other
+	// changes, such as scaling the cluster or updating its settings, are still applied immediately.
+	// Disruptive changes are applied at any time if no window is set.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []commonv1.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// UpgradeDeprecationChecks controls how the critical issues reported by the Elasticsearch deprecation API are handled
+	// before upgrading the cluster to the next major version: Block (default) waits for the issues to be resolved,
+	// Warn only reports them, Disabled skips the checks.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Block;Warn;Disabled
+	UpgradeDeprecationChecks UpgradeDeprecationChecksMode `json:"upgradeDeprecationChecks,omitempty"`
+}
+
+// FinalSnapshot specifies the snapshot taken before the cluster is deleted.
+type FinalSnapshot struct {
+	// Repository is the name of the snapshot repository, registered on the cluster, the snapshot is stored in.
+	// +kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+}
+
+// InitialRestore specifies the snapshot restored into a new cluster.
+type InitialRestore struct {
+	// Repository is the snapshot repository registered on the cluster to restore the snapshot from.
+	Repository InitialRestoreRepository `json:"repository"`
+
+	// Snapshot is the name of the snapshot to restore. It can be a pattern with wildcards, in which case the most
+	// recent successful snapshot matching the pattern is restored.
+	Snapshot string `json:"snapshot"`
+
+	// Indices are the indices and data streams to restore, supporting wildcards. Defaults to all the indices and
+	// data streams of the snapshot.
+	// +kubebuilder:validation:Optional
+	Indices []string `json:"indices,omitempty"`
+
+	// IncludeGlobalState restores the cluster state of the snapshot, including templates, pipelines and
+	// persistent settings. Defaults to false.
+	// +kubebuilder:validation:Optional
+	IncludeGlobalState bool `json:"includeGlobalState,omitempty"`
 }
 
+// SnapshotRepository is a snapshot repository registered on the cluster.
+type SnapshotRepository struct {
+	// Name is the name of the repository in Elasticsearch.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Type is the type of the repository.
+	// +kubebuilder:validation:Enum=s3;gcs;azure;fs
+	Type string `json:"type"`
+
+	// Settings are the settings of the repository, as expected by the Elasticsearch snapshot repository API.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Settings *commonv1.Config `json:"settings,omitempty"`
+
+	// SecureSettings are Secrets holding the client credentials of the repository, added to the keystore of the
+	// cluster. Each key of the Secrets must be the name of a secure setting, for example s3.client.default.secret_key.
+	// +kubebuilder:validation:Optional
+	SecureSettings []commonv1.SecretSource `json:"secureSettings,omitempty"`
+
+	// Verify enables the verification of the repository on all the nodes of the cluster when it is registered.
+	// Defaults to true.
+	// +kubebuilder:validation:Optional
+	Verify *bool `json:"verify,omitempty"`
+}
+
+// VerifyOrDefault returns true if the repository must be verified when it is registered.
+func (r SnapshotRepository) VerifyOrDefault() bool {
+	return r.Verify == nil || *r.Verify
+}
+
+// DefaultSnapshotLifecyclePolicyFailureThreshold is the default duration after which a snapshot lifecycle policy
+// that keeps failing is reported as unhealthy.
+const DefaultSnapshotLifecyclePolicyFailureThreshold = 24 * time.Hour
+
+// SnapshotLifecyclePolicy is a snapshot lifecycle management (SLM) policy configured on the cluster.
+type SnapshotLifecyclePolicy struct {
+	// Name is the id of the policy in Elasticsearch.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Schedule is the periodic or absolute schedule at which the policy takes snapshots, as a cron expression.
+	// For example "0 30 1 * * ?" to take a snapshot every day at 1:30 AM UTC.
+	Schedule string `json:"schedule"`
+
+	// SnapshotName is the name of the snapshots taken by the policy, supporting date math.
+	// Defaults to "<name-{now/d}>".
+	// +kubebuilder:validation:Optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+
+	// Repository is the name of the snapshot repository, registered on the cluster, snapshots are stored in.
+	// +kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+
+	// Config is the configuration of the snapshots taken by the policy, for example the indices to include.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Config *commonv1.Config `json:"config,omitempty"`
+
+	// Retention is the retention of the snapshots taken by the policy.
+	// +kubebuilder:validation:Optional
+	Retention *SnapshotLifecyclePolicyRetention `json:"retention,omitempty"`
+
+	// FailureThreshold is the duration after which a policy that did not take any successful snapshot since its
+	// first failure is reported in the SnapshotLifecyclePoliciesReady condition and in a warning event.
+	// Defaults to 24h.
+	// +kubebuilder:validation:Optional
+	FailureThreshold *metav1.Duration `json:"failureThreshold,omitempty"`
+}
+
+// SnapshotLifecyclePolicyRetention holds the retention rules of the snapshots taken by a policy.
+type SnapshotLifecyclePolicyRetention struct {
+	// ExpireAfter is the time period after which snapshots are deleted, for example "30d".
+	// +kubebuilder:validation:Optional
+	ExpireAfter string `json:"expireAfter,omitempty"`
+	// MinCount is the minimum number of snapshots to retain, even if they are expired.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MinCount *int32 `json:"minCount,omitempty"`
+	// MaxCount is the maximum number of snapshots to retain, even if they are not expired.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxCount *int32 `json:"maxCount,omitempty"`
+}
+
+// SnapshotNameOrDefault returns the name of the snapshots taken by the policy.
+func (p SnapshotLifecyclePolicy) SnapshotNameOrDefault() string {
+	if p.SnapshotName != "" {
+		return p.SnapshotName
+	}
+	return fmt.Sprintf("<%s-{now/d}>", p.Name)
+}
+
+// FailureThresholdOrDefault returns the duration after which a failing policy is reported as unhealthy.
+func (p SnapshotLifecyclePolicy) FailureThresholdOrDefault() time.Duration {
+	if p.FailureThreshold != nil {
+		return p.FailureThreshold.Duration
+	}
+	return DefaultSnapshotLifecyclePolicyFailureThreshold
+}
+
+// InitialRestoreRepository is a snapshot repository registered on a new cluster.
+type InitialRestoreRepository struct {
+	// Name is the name of the repository in Elasticsearch.
+	Name string `json:"name"`
+
+	// Type is the type of the repository, for example s3, gcs, azure or fs.
+	Type string `json:"type"`
+
+	// Settings are the settings of the repository, for example the bucket name. The repository is registered as
+	// read-only unless the readonly setting is set. Credentials are read from the keystore, populated with
+	// SecureSettings.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Settings *commonv1.Config `json:"settings,omitempty"`
+}
+
+// PreUpgradeVolumeSnapshots specifies the VolumeSnapshots taken before a version upgrade.
+type PreUpgradeVolumeSnapshots struct {
+	// VolumeSnapshotClassName is the name of the VolumeSnapshotClass of the snapshots.
+	// Defaults to the default VolumeSnapshotClass of the CSI driver.
+	// +kubebuilder:validation:Optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+
+	// NodeSets is the list of the NodeSets whose volumes are snapshotted, for example the NodeSets of the master nodes.
+	// Defaults to all the NodeSets.
+	// +kubebuilder:validation:Optional
+	NodeSets []string `json:"nodeSets,omitempty"`
+
+	// RetainedUpgrades is the number of upgrades for which the snapshots are kept, the snapshots of older upgrades
+	// being deleted. Defaults to keeping the snapshots of all the upgrades.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	RetainedUpgrades *int32 `json:"retainedUpgrades,omitempty"`
+}
+
+// UpgradeDeprecationChecksMode describes how the critical deprecation issues are handled before a major version upgrade.
+type UpgradeDeprecationChecksMode string
+
+const (
+	// UpgradeDeprecationChecksBlock holds the upgrade until the critical deprecation issues are resolved.
+	UpgradeDeprecationChecksBlock UpgradeDeprecationChecksMode = "Block"
+	// UpgradeDeprecationChecksWarn reports the critical deprecation issues but proceeds with the upgrade.
+	UpgradeDeprecationChecksWarn UpgradeDeprecationChecksMode = "Warn"
+	// UpgradeDeprecationChecksDisabled does not check the deprecation issues before the upgrade.
+	UpgradeDeprecationChecksDisabled UpgradeDeprecationChecksMode = "Disabled"
+)
+
 // VolumeClaimDeletePolicy describes the delete policy for handling PersistentVolumeClaims that hold Elasticsearch data.
 // Inspired by https://github.com/kubernetes/enhancements/pull/2440
 type VolumeClaimDeletePolicy string
@@ -149,8 +408,42 @@ const (
 	// DeleteOnScaledownOnlyPolicy removes PersistentVolumeClaims on scale down of Elasticsearch nodes but retains all
 	// current PersistenVolumeClaims when the Elasticsearch cluster has been deleted.
 	DeleteOnScaledownOnlyPolicy VolumeClaimDeletePolicy = "DeleteOnScaledownOnly"
+	// DeleteOnClusterDeletionOnlyPolicy retains PersistentVolumeClaims on scale down of Elasticsearch nodes but removes
+	// them when the Elasticsearch cluster has been deleted.
+	DeleteOnClusterDeletionOnlyPolicy VolumeClaimDeletePolicy = "DeleteOnClusterDeletionOnly"
+	// RetainPolicy retains PersistentVolumeClaims both on scale down of Elasticsearch nodes and when the Elasticsearch
+	// cluster has been deleted.
+	RetainPolicy VolumeClaimDeletePolicy = "Retain"
 )
 
+// DeleteOnScaledown returns true if PersistentVolumeClaims must be removed on scale down of Elasticsearch nodes.
+func (p VolumeClaimDeletePolicy) DeleteOnScaledown() bool {
+	return p == DeleteOnScaledownAndClusterDeletionPolicy || p == DeleteOnScaledownOnlyPolicy
+}
+
+// DeleteOnClusterDeletion returns true if PersistentVolumeClaims must be removed when the Elasticsearch cluster
+// has been deleted.
+func (p VolumeClaimDeletePolicy) DeleteOnClusterDeletion() bool {
+	return p == DeleteOnScaledownAndClusterDeletionPolicy || p == DeleteOnClusterDeletionOnlyPolicy
+}
+
+// ServiceProfile defines an additional HTTP Service targeting the Elasticsearch nodes with some roles.
+type ServiceProfile struct {
+	// Name of the profile. The Service is named <cluster name>-es-<profile name>-http.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Roles of the nodes targeted by the Service. A node is targeted if it has at least one of the roles.
+	// The coordinating value targets the coordinating-only nodes, which have no role.
+	// +kubebuilder:validation:MinItems=1
+	Roles []string `json:"roles"`
+	// Service defines the template for the Kubernetes Service. The selector of the Service is managed by the operator.
+	// +kubebuilder:validation:Optional
+	Service commonv1.ServiceTemplate `json:"service,omitempty"`
+}
+
+// CoordinatingOnlyProfileRole is the service profile role targeting the coordinating-only nodes, which have no role.
+const CoordinatingOnlyProfileRole = "coordinating"
+
 // TransportConfig holds the transport layer settings for Elasticsearch.
 type TransportConfig struct {
 	// Service defines the template for the associated Kubernetes Service object.
@@ -179,12 +472,36 @@ type TransportTLSOptions struct {
 	CertificateAuthorities commonv1.ConfigMapRef `json:"certificateAuthorities,omitempty"`
 	// SelfSignedCertificates allows configuring the self-signed certificate generated by the operator.
 	SelfSignedCertificates *SelfSignedTransportCertificates `json:"selfSignedCertificates,omitempty"`
+	// CertificatesSecretShards is the number of Secrets across which the transport certificates of the Pods of each
+	// nodeSet are spread, Pods being assigned to a Secret based on their ordinal. Spreading the certificates keeps the
+	// Secrets small for nodeSets with many nodes. Defaults to 1. Changing it restarts all the Pods of the cluster.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	CertificatesSecretShards *int32 `json:"certificatesSecretShards,omitempty"`
+	// Issuer configures an external signer, such as a cert-manager issuer, to issue the node transport certificates
+	// through Kubernetes CertificateSigningRequests instead of the operator CA. The CA of the signer must be trusted
+	// through CertificateAuthorities.
+	// +kubebuilder:validation:Optional
+	Issuer *TransportCertificatesIssuer `json:"issuer,omitempty"`
+	// CertificateRotation overrides the validity and rotation settings of the operator for the node transport
+	// certificates and the transport CA of this cluster.
+	// +kubebuilder:validation:Optional
+	CertificateRotation *commonv1.CertificateRotation `json:"certificateRotation,omitempty"`
 }
 
 func (tto TransportTLSOptions) SelfSignedEnabled() bool {
 	return tto.SelfSignedCertificates == nil || !tto.SelfSignedCertificates.Disabled
 }
 
+// CertificatesSecretShardsCount returns the number of Secrets across which the transport certificates of each nodeSet
+// are spread, defaulting to 1.
+func (tto TransportTLSOptions) CertificatesSecretShardsCount() int32 {
+	if tto.CertificatesSecretShards == nil || *tto.CertificatesSecretShards < 1 {
+		return 1
+	}
+	return *tto.CertificatesSecretShards
+}
+
 // SelfSignedTransportCertificates holds configuration for the self-signed certificates generated by the operator.
 type SelfSignedTransportCertificates struct {
 	// Disabled indicates that provisioning of the self-signed certificates should be disabled.
@@ -195,6 +512,21 @@ func (tto TransportTLSOptions) UserDefinedCA() bool {
 	return tto.Certificate.SecretName != ""
 }
 
+// IssuerEnabled returns true if the node transport certificates are issued by an external signer.
+func (tto TransportTLSOptions) IssuerEnabled() bool {
+	return tto.Issuer != nil && tto.Issuer.SignerName != ""
+}
+
+// TransportCertificatesIssuer holds the configuration of the external signer of the node transport certificates.
+type TransportCertificatesIssuer struct {
+	// SignerName is the name of the signer of the CertificateSigningRequests created by the operator for each node,
+	// for example "issuers.cert-manager.io/<namespace>.<issuer-name>" or "clusterissuers.cert-manager.io/<issuer-name>"
+	// for cert-manager. The requests must be approved by an approver of the cluster, such as cert-manager approver-policy.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SignerName string `json:"signerName"`
+}
+
 // RemoteCluster declares a remote Elasticsearch cluster connection.
 type RemoteCluster struct {
 	// Name is the name of the remote cluster as it is set in the Elasticsearch settings.
@@ -206,10 +538,61 @@ type RemoteCluster struct {
 	// ElasticsearchRef is a reference to an Elasticsearch cluster running within the same k8s cluster.
 	ElasticsearchRef commonv1.LocalObjectSelector `json:"elasticsearchRef,omitempty"`
 
+	// Seeds are the addresses of the nodes of a remote Elasticsearch cluster not managed by the operator, such as a
+	// cluster running outside Kubernetes. Seeds cannot be combined with ElasticsearchRef or ProxyAddress.
+	// +optional
+	Seeds []string `json:"seeds,omitempty"`
+
+	// APIKey is a reference to the key of a Secret, in the same namespace as the Elasticsearch cluster, holding a
+	// cross-cluster API key created on the remote cluster. The operator loads it into the keystore to connect to the
+	// remote cluster with the API key based security model. It requires Seeds or ProxyAddress, and Elasticsearch
+	// 8.10.0 or later.
+	// +optional
+	APIKey *SecretKeyRef `json:"apiKey,omitempty"`
+
+	// Mode is the connection mode to the remote cluster: sniff, the default, connects to the seeds and discovers the
+	// other nodes of the remote cluster, proxy connects to a single address, such as a load balancer.
+	// +kubebuilder:validation:Enum=sniff;proxy
+	// +optional
+	Mode RemoteClusterMode `json:"mode,omitempty"`
+
+	// ProxyAddress is the address of the remote cluster in proxy mode. Defaults to the transport service of the cluster
+	// referenced by ElasticsearchRef.
+	// +optional
+	ProxyAddress string `json:"proxyAddress,omitempty"`
+
+	// ServerName is the server name sent in the TLS SNI extension in proxy mode.
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// NumSocketConnections is the number of socket connections opened to the remote cluster in proxy mode.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	NumSocketConnections *int32 `json:"numSocketConnections,omitempty"`
+
+	// SkipUnavailable makes cross-cluster searches skip the remote cluster if it is unavailable.
+	// +optional
+	SkipUnavailable *bool `json:"skipUnavailable,omitempty"`
+
 	// TODO: Allow the user to specify some options (transport.compress, transport.ping_schedule)
 
 }
 
+// RemoteClusterMode is the connection mode to a remote cluster.
+type RemoteClusterMode string
+
+const (
+	// RemoteClusterSniffMode connects to the seeds and discovers the other nodes of the remote cluster.
+	RemoteClusterSniffMode RemoteClusterMode = "sniff"
+	// RemoteClusterProxyMode connects to the remote cluster through a single proxy address.
+	RemoteClusterProxyMode RemoteClusterMode = "proxy"
+)
+
+// IsProxyMode returns true if the remote cluster is connected to in proxy mode.
+func (r RemoteCluster) IsProxyMode() bool {
+	return r.Mode == RemoteClusterProxyMode
+}
+
 func (r RemoteCluster) ConfigHash() string {
 	return hash.HashObject(r)
 }
@@ -230,6 +613,15 @@ func (es ElasticsearchSpec) VolumeClaimDeletePolicyOrDefault() VolumeClaimDelete
 	return es.VolumeClaimDeletePolicy
 }
 
+// NodeSetVolumeClaimDeletePolicy returns the delete policy of the PersistentVolumeClaims of the given NodeSet, which
+// defaults to the policy of the cluster.
+func (es ElasticsearchSpec) NodeSetVolumeClaimDeletePolicy(nodeSet NodeSet) VolumeClaimDeletePolicy {
+	if nodeSet.VolumeClaimDeletePolicy == "" {
+		return es.VolumeClaimDeletePolicyOrDefault()
+	}
+	return nodeSet.VolumeClaimDeletePolicy
+}
+
 // Auth contains user authentication and authorization security settings for Elasticsearch.
 type Auth struct {
 	// Roles to propagate to the Elasticsearch cluster.
@@ -238,6 +630,59 @@ type Auth struct {
 	FileRealm []FileRealmSource `json:"fileRealm,omitempty"`
 	// DisableElasticUser disables the default elastic user that is created by ECK.
 	DisableElasticUser bool `json:"disableElasticUser,omitempty"`
+	// OIDC realms let users log in to Kibana with an OpenID Connect provider. The operator configures the realms, loads
+	// their client secrets in the keystore and configures the matching authentication providers of Kibana.
+	OIDC []OIDCRealm `json:"oidc,omitempty"`
+	// SAML realms let users log in to Kibana with a SAML identity provider. The operator configures the realms, mounts
+	// their identity provider metadata and configures the matching authentication providers of Kibana.
+	SAML []SAMLRealm `json:"saml,omitempty"`
+}
+
+// Auditing configures the security audit log of Elasticsearch, written to the <cluster>_audit.json file of the logs
+// directory. See https://www.elastic.co/guide/en/elasticsearch/reference/current/enable-audit-logging.html.
+type Auditing struct {
+	// IncludeEvents are the types of the audited events. Defaults to the Elasticsearch default.
+	// +kubebuilder:validation:Optional
+	IncludeEvents []string `json:"includeEvents,omitempty"`
+
+	// ExcludeEvents are the types of events excluded from the audit log.
+	// +kubebuilder:validation:Optional
+	ExcludeEvents []string `json:"excludeEvents,omitempty"`
+
+	// IgnoreFilters are policies excluding events from the audit log. An event is ignored if it matches all the
+	// criteria of at least one policy.
+	// +kubebuilder:validation:Optional
+	IgnoreFilters []AuditIgnoreFilter `json:"ignoreFilters,omitempty"`
+
+	// ElasticsearchRefs is a reference to a monitoring Elasticsearch cluster running in the same Kubernetes cluster, which
+	// receives the audit log through a Filebeat sidecar, as for the logs of spec.monitoring.logs. It cannot be used in
+	// combination with spec.monitoring.logs.elasticsearchRefs, which already ships the audit log with the other logs.
+	// Only a single Elasticsearch cluster is supported.
+	// +kubebuilder:validation:Optional
+	ElasticsearchRefs []commonv1.ObjectSelector `json:"elasticsearchRefs,omitempty"`
+}
+
+// AuditIgnoreFilter is a policy excluding the events matching all its criteria from the audit log. Each criterion is a
+// list of values supporting wildcards.
+type AuditIgnoreFilter struct {
+	// Name of the policy.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Users are the names of the users of the ignored events.
+	// +kubebuilder:validation:Optional
+	Users []string `json:"users,omitempty"`
+	// Realms are the authentication realms of the ignored events.
+	// +kubebuilder:validation:Optional
+	Realms []string `json:"realms,omitempty"`
+	// Actions are the action names of the ignored events.
+	// +kubebuilder:validation:Optional
+	Actions []string `json:"actions,omitempty"`
+	// Roles are the roles of the users of the ignored events.
+	// +kubebuilder:validation:Optional
+	Roles []string `json:"roles,omitempty"`
+	// Indices are the indices of the ignored events.
+	// +kubebuilder:validation:Optional
+	Indices []string `json:"indices,omitempty"`
 }
 
 // RoleSource references roles to create in the Elasticsearch cluster.
@@ -300,6 +745,154 @@ type FileRealmSource struct {
 	commonv1.SecretRef `json:",inline"`
 }
 
+const (
+	// OIDCRealmType is the type of the OpenID Connect realms.
+	OIDCRealmType = "oidc"
+	// SAMLRealmType is the type of the SAML realms.
+	SAMLRealmType = "saml"
+)
+
+// SSORealm contains the settings shared by the OpenID Connect and SAML realms.
+type SSORealm struct {
+	// Name of the realm, unique among the OpenID Connect and SAML realms of the cluster.
+	Name string `json:"name"`
+	// Order of the realm in the realm chain. Defaults to the position of the realm among the OpenID Connect and SAML
+	// realms, after the file and native realms.
+	// +kubebuilder:validation:Optional
+	Order *int32 `json:"order,omitempty"`
+	// KibanaRef is a reference to the Kibana users log in to with this realm. The authentication providers of this
+	// Kibana are configured by the operator. Kibana must be associated with this Elasticsearch cluster.
+	KibanaRef commonv1.LocalObjectSelector `json:"kibanaRef"`
+	// KibanaURL is the public URL of Kibana, as reached by the browsers of the users, for example https://kibana.example.com.
+	// The redirect URLs registered with the identity provider are derived from it.
+	KibanaURL string `json:"kibanaURL"`
+	// Description is the label of the login selector of the realm in Kibana.
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+}
+
+// OIDCRealm configures an OpenID Connect realm.
+type OIDCRealm struct {
+	SSORealm `json:",inline"`
+	// Issuer is the issuer identifier of the OpenID Connect provider.
+	Issuer string `json:"issuer"`
+	// AuthorizationEndpoint is the URL of the authorization endpoint of the OpenID Connect provider.
+	AuthorizationEndpoint string `json:"authorizationEndpoint"`
+	// TokenEndpoint is the URL of the token endpoint of the OpenID Connect provider.
+	TokenEndpoint string `json:"tokenEndpoint"`
+	// JWKSetURL is the URL of the JSON Web Key Set of the OpenID Connect provider.
+	JWKSetURL string `json:"jwkSetURL"`
+	// UserinfoEndpoint is the URL of the userinfo endpoint of the OpenID Connect provider.
+	// +kubebuilder:validation:Optional
+	UserinfoEndpoint string `json:"userinfoEndpoint,omitempty"`
+	// EndSessionEndpoint is the URL of the end session endpoint of the OpenID Connect provider, used to log users out.
+	// +kubebuilder:validation:Optional
+	EndSessionEndpoint string `json:"endSessionEndpoint,omitempty"`
+	// ClientID is the id of the client registered with the OpenID Connect provider.
+	ClientID string `json:"clientID"`
+	// ClientSecret is a reference to the key of a Secret, in the same namespace as the Elasticsearch cluster, holding
+	// the secret of the client. It is loaded in the keystore of Elasticsearch.
+	ClientSecret SecretKeyRef `json:"clientSecret"`
+	// RequestedScopes are the scopes requested to the OpenID Connect provider. Defaults to openid.
+	// +kubebuilder:validation:Optional
+	RequestedScopes []string `json:"requestedScopes,omitempty"`
+	// PrincipalClaim is the claim holding the principal of the users. Defaults to sub.
+	// +kubebuilder:validation:Optional
+	PrincipalClaim string `json:"principalClaim,omitempty"`
+	// GroupsClaim is the claim holding the groups of the users, which role mappings can rely on.
+	// +kubebuilder:validation:Optional
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+}
+
+// SAMLRealm configures a SAML realm.
+type SAMLRealm struct {
+	SSORealm `json:",inline"`
+	// IdPMetadata is the source of the metadata of the identity provider.
+	IdPMetadata SAMLMetadataSource `json:"idpMetadata"`
+	// IdPEntityID is the entity id of the identity provider, as found in its metadata.
+	IdPEntityID string `json:"idpEntityID"`
+	// PrincipalAttribute is the attribute holding the principal of the users. Defaults to nameid:persistent.
+	// +kubebuilder:validation:Optional
+	PrincipalAttribute string `json:"principalAttribute,omitempty"`
+	// GroupsAttribute is the attribute holding the groups of the users, which role mappings can rely on.
+	// +kubebuilder:validation:Optional
+	GroupsAttribute string `json:"groupsAttribute,omitempty"`
+}
+
+// SAMLMetadataSource is the source of the metadata of a SAML identity provider, either a URL or a Secret.
+type SAMLMetadataSource struct {
+	// URL of the metadata, which Elasticsearch downloads and refreshes periodically.
+	// +kubebuilder:validation:Optional
+	URL string `json:"url,omitempty"`
+	// Secret is a reference to the key of a Secret, in the same namespace as the Elasticsearch cluster, holding the
+	// metadata. It is mounted in the Elasticsearch Pods.
+	// +kubebuilder:validation:Optional
+	Secret *SecretKeyRef `json:"secret,omitempty"`
+}
+
+// SecretKeyRef is a reference to the key of a Secret.
+type SecretKeyRef struct {
+	// SecretName is the name of the Secret.
+	SecretName string `json:"secretName"`
+	// Key is the key of the Secret.
+	Key string `json:"key"`
+}
+
+// OrderOrDefault returns the order of the realm at the given position among the OpenID Connect and SAML realms.
+func (r SSORealm) OrderOrDefault(position int) int32 {
+	if r.Order != nil {
+		return *r.Order
+	}
+	return int32(position) + 1
+}
+
+// KibanaSSORealm is an OpenID Connect or SAML realm Kibana users log in with.
+type KibanaSSORealm struct {
+	// Type is the type of the realm, oidc or saml.
+	Type string
+	// Name is the name of the realm.
+	Name string
+	// Order is the order of the realm in the realm chain.
+	Order int32
+	// KibanaRef is a reference to the Kibana users log in to with the realm.
+	KibanaRef commonv1.LocalObjectSelector
+	// Description is the label of the login selector of the realm in Kibana.
+	Description string
+}
+
+func newKibanaSSORealm(realmType string, realm SSORealm, position int) KibanaSSORealm {
+	return KibanaSSORealm{
+		Type:        realmType,
+		Name:        realm.Name,
+		Order:       realm.OrderOrDefault(position),
+		KibanaRef:   realm.KibanaRef,
+		Description: realm.Description,
+	}
+}
+
+// SSORealms returns the OpenID Connect and SAML realms, in their default order.
+func (a Auth) SSORealms() []KibanaSSORealm {
+	realms := make([]KibanaSSORealm, 0, len(a.OIDC)+len(a.SAML))
+	for _, realm := range a.OIDC {
+		realms = append(realms, newKibanaSSORealm(OIDCRealmType, realm.SSORealm, len(realms)))
+	}
+	for _, realm := range a.SAML {
+		realms = append(realms, newKibanaSSORealm(SAMLRealmType, realm.SSORealm, len(realms)))
+	}
+	return realms
+}
+
+// KibanaSSORealms returns the OpenID Connect and SAML realms of the given Elasticsearch cluster used by the given Kibana.
+func (es Elasticsearch) KibanaSSORealms(kb types.NamespacedName) []KibanaSSORealm {
+	var realms []KibanaSSORealm
+	for _, realm := range es.Spec.Auth.SSORealms() {
+		if realm.KibanaRef.WithDefaultNamespace(es.Namespace).NamespacedName() == kb {
+			realms = append(realms, realm)
+		}
+	}
+	return realms
+}
+
 // NodeSet is the specification for a group of Elasticsearch nodes sharing the same configuration and a Pod template.
 type NodeSet struct {
 	// Name of this set of nodes. Becomes a part of the Elasticsearch node.name setting.
@@ -322,10 +915,192 @@ type NodeSet struct {
 	PodTemplate corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
 
 	// VolumeClaimTemplates is a list of persistent volume claims to be used by each Pod in this NodeSet.
-	// Every claim in this list must have a matching volumeMount in one of the containers defined in the PodTemplate.
+	// Every claim in this list must have a matching volumeMount in one of the containers defined in the PodTemplate,
+	// or be listed in AdditionalVolumes.
 	// Items defined here take precedence over any default claims added by the operator with the same name.
 	// +kubebuilder:validation:Optional
 	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
+
+	// VolumeClaimDeletePolicy sets the policy for handling deletion of the PersistentVolumeClaims of this NodeSet.
+	// Possible values are DeleteOnScaledownOnly, DeleteOnScaledownAndClusterDeletion, DeleteOnClusterDeletionOnly and Retain.
+	// Defaults to the VolumeClaimDeletePolicy of the Elasticsearch cluster.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=DeleteOnScaledownOnly;DeleteOnScaledownAndClusterDeletion;DeleteOnClusterDeletionOnly;Retain
+	VolumeClaimDeletePolicy VolumeClaimDeletePolicy `json:"volumeClaimDeletePolicy,omitempty"`
+
+	// EphemeralStorage stores the Elasticsearch data of this NodeSet in an emptyDir or CSI ephemeral volume instead of a
+	// persistent volume.
+	// The data is lost whenever a Pod is deleted or rescheduled: this is only supported for nodes holding no data that
+	// cannot be recovered from elsewhere, such as coordinating, machine learning, or frozen tier nodes.
+	// Cannot be combined with VolumeClaimTemplates.
+	// +kubebuilder:validation:Optional
+	EphemeralStorage *EphemeralStorage `json:"ephemeralStorage,omitempty"`
+
+	// AdditionalVolumes mounts volume claim templates of this NodeSet in the Elasticsearch container as additional data
+	// paths, or in place of the default logs or temporary directory volumes, for instance to use several local disks
+	// per Kubernetes node. Every volume must match the name of a claim in VolumeClaimTemplates.
+	// +kubebuilder:validation:Optional
+	AdditionalVolumes []AdditionalVolume `json:"additionalVolumes,omitempty"`
+
+	// FrozenCache configures the shared cache of the searchable snapshots mounted by the frozen tier nodes of this NodeSet.
+	// Only supported for nodes with the data_frozen role.
+	// +kubebuilder:validation:Optional
+	FrozenCache *FrozenCache `json:"frozenCache,omitempty"`
+
+	// JVMDiagnostics enables the collection of the JVM heap dumps and GC logs of the nodes of this NodeSet to a dedicated
+	// volume, optionally uploaded to an object store bucket.
+	// +kubebuilder:validation:Optional
+	JVMDiagnostics *JVMDiagnostics `json:"jvmDiagnostics,omitempty"`
+
+	// ZoneAwareness spreads the nodes of this NodeSet evenly across the given zones. The operator creates one StatefulSet
+	// per zone, named after the NodeSet and the zone, schedules its Pods in the zone and sets the zone node attribute.
+	// +kubebuilder:validation:Optional
+	ZoneAwareness *ZoneAwareness `json:"zoneAwareness,omitempty"`
+
+	// PodDisruptionBudget overrides the default PodDisruptionBudget for the Pods of this NodeSet, which are then covered
+	// by a dedicated PodDisruptionBudget and excluded from the default one of the cluster. The selector defaults to the
+	// Pods of the NodeSet and maxUnavailable to 1.
+	// To exclude the Pods of this NodeSet from any PodDisruptionBudget, set it to the empty value (`{}` in YAML).
+	// +kubebuilder:validation:Optional
+	PodDisruptionBudget *commonv1.PodDisruptionBudgetTemplate `json:"podDisruptionBudget,omitempty"`
+}
+
+// ZoneAwareness specifies the zones the nodes of a NodeSet are spread across.
+type ZoneAwareness struct {
+	// TopologyKey is the label of the Kubernetes nodes holding their zone. Defaults to topology.kubernetes.io/zone.
+	// +kubebuilder:validation:Optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// Zones the nodes are spread across, as values of the TopologyKey label.
+	// +kubebuilder:validation:MinItems=1
+	Zones []string `json:"zones"`
+}
+
+// AdditionalVolumeUsage is the usage of an additional volume in the Elasticsearch container.
+type AdditionalVolumeUsage string
+
+const (
+	// DataVolumeUsage appends the volume to the data paths of Elasticsearch (path.data).
+	// Multiple data paths are not supported from Elasticsearch 8.0.0.
+	DataVolumeUsage AdditionalVolumeUsage = "Data"
+	// LogsVolumeUsage stores the Elasticsearch logs in the volume (path.logs).
+	LogsVolumeUsage AdditionalVolumeUsage = "Logs"
+	// TempVolumeUsage uses the volume as the temporary directory of Elasticsearch.
+	TempVolumeUsage AdditionalVolumeUsage = "Temp"
+)
+
+// MultipleDataPathsRemovedVersion is the first version of Elasticsearch that does not support multiple data paths.
+var MultipleDataPathsRemovedVersion = semver.MustParse("8.0.0")
+
+// AdditionalVolume maps a volume claim template of a NodeSet to a usage in the Elasticsearch container.
+type AdditionalVolume struct {
+	// Name of the volume claim template backing the volume.
+	Name string `json:"name"`
+
+	// Usage of the volume. Possible values are Data, Logs and Temp.
+	// +kubebuilder:validation:Enum=Data;Logs;Temp
+	Usage AdditionalVolumeUsage `json:"usage"`
+}
+
+// EphemeralStorage specifies the emptyDir or CSI ephemeral volume used to store the Elasticsearch data of a NodeSet.
+type EphemeralStorage struct {
+	// SizeLimit is the total amount of local storage the data volume can use.
+	// +kubebuilder:validation:Optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+
+	// Medium is the storage medium backing the data volume. Defaults to the node's default medium.
+	// Set to "Memory" to use a tmpfs, which counts against the memory limit of the Elasticsearch container.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum="";Memory
+	Medium corev1.StorageMedium `json:"medium,omitempty"`
+
+	// CSI stores the data in a CSI ephemeral volume provided by the given driver instead of an emptyDir volume, for
+	// instance to use the local disks of the Kubernetes nodes. Cannot be combined with SizeLimit and Medium.
+	// +kubebuilder:validation:Optional
+	CSI *corev1.CSIVolumeSource `json:"csi,omitempty"`
+}
+
+// FrozenCache specifies the shared cache of the frozen tier nodes of a NodeSet.
+type FrozenCache struct {
+	// Size of the shared cache, either as a percentage of the data volume, such as "90%", or as a quantity, such as "100Gi".
+	// Sets xpack.searchable.snapshot.shared_cache.size. Defaults to 90% of the data volume for dedicated frozen nodes.
+	// +kubebuilder:validation:Optional
+	Size string `json:"size,omitempty"`
+
+	// Volume is a volume dedicated to the shared cache, which replaces the default data volume of the NodeSet.
+	// Cannot be combined with a volume claim template for the data volume, or with EphemeralStorage.
+	// +kubebuilder:validation:Optional
+	Volume *FrozenCacheVolume `json:"volume,omitempty"`
+}
+
+// FrozenCacheVolume specifies the volume storing the shared cache of the frozen tier nodes.
+type FrozenCacheVolume struct {
+	// Storage is the size of the volume.
+	Storage resource.Quantity `json:"storage"`
+
+	// StorageClassName is the storage class of the PersistentVolumeClaims. Defaults to the default storage class.
+	// Cannot be changed on an existing NodeSet.
+	// +kubebuilder:validation:Optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Ephemeral stores the shared cache in an emptyDir volume limited to Storage instead of a PersistentVolumeClaim.
+	// The cache is populated again from the snapshot repositories whenever a Pod is recreated.
+	// Cannot be changed on an existing NodeSet.
+	// +kubebuilder:validation:Optional
+	Ephemeral bool `json:"ephemeral,omitempty"`
+}
+
+// JVMDiagnostics specifies where the JVM of the Elasticsearch nodes writes its heap dumps on OutOfMemoryError and its
+// GC logs.
+type JVMDiagnostics struct {
+	// VolumeClaimTemplate is the name of a volume claim template of the NodeSet storing the heap dumps and GC logs.
+	// Defaults to an emptyDir volume, which outlives restarts of the Elasticsearch container but not the deletion of the Pod.
+	// +kubebuilder:validation:Optional
+	VolumeClaimTemplate string `json:"volumeClaimTemplate,omitempty"`
+
+	// SizeLimit is the total amount of local storage the default emptyDir volume can use.
+	// Ignored if VolumeClaimTemplate is set.
+	// +kubebuilder:validation:Optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+
+	// Upload uploads the heap dumps to an object store bucket from a sidecar container, and removes them from the volume
+	// once uploaded.
+	// +kubebuilder:validation:Optional
+	Upload *JVMDiagnosticsUpload `json:"upload,omitempty"`
+}
+
+// JVMDiagnosticsUpload specifies the object store bucket the heap dumps are uploaded to.
+type JVMDiagnosticsUpload struct {
+	// SecretName is the name of a Secret, in the namespace of the Elasticsearch cluster, holding the URL of the bucket in
+	// its bucket entry, such as s3://my-bucket/heap-dumps or gs://my-bucket/heap-dumps. All the entries of the Secret are
+	// exposed as environment variables to the uploader container, to provide it with the credentials of the bucket.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// Image is the container image of the uploader, which must provide a shell and the aws CLI for S3 buckets, or the
+	// gsutil CLI for GCS buckets.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+}
+
+// SharedCacheSize returns the value of the xpack.searchable.snapshot.shared_cache.size setting for the given size:
+// percentages are kept as is, quantities are converted to a number of bytes.
+func (c FrozenCache) SharedCacheSize() (string, error) {
+	if strings.HasSuffix(c.Size, "%") {
+		percentage, err := strconv.ParseFloat(strings.TrimSuffix(c.Size, "%"), 64)
+		if err != nil || percentage <= 0 || percentage > 100 {
+			return "", fmt.Errorf("invalid percentage %s", c.Size)
+		}
+		return c.Size, nil
+	}
+	quantity, err := resource.ParseQuantity(c.Size)
+	if err != nil {
+		return "", err
+	}
+	if quantity.Sign() <= 0 {
+		return "", fmt.Errorf("invalid size %s", c.Size)
+	}
+	return fmt.Sprintf("%db", quantity.Value()), nil
 }
 
 // +kubebuilder:object:generate=false
@@ -452,6 +1227,11 @@ func (es Elasticsearch) IsConfiguredToAllowDowngrades() bool {
 	return commonv1.IsConfiguredToAllowDowngrades(&es)
 }
 
+// IsDryRunExplainRequested returns true if the DryRunExplain annotation is set to the value of true.
+func (es Elasticsearch) IsDryRunExplainRequested() bool {
+	return es.Annotations[DryRunExplainAnnotation] == "true"
+}
+
 func (es *Elasticsearch) ServiceAccountName() string {
 	return es.Spec.ServiceAccountName
 }
@@ -468,13 +1248,79 @@ func (es Elasticsearch) IsAutoscalingAnnotationSet() bool {
 }
 
 func (es Elasticsearch) SecureSettings() []commonv1.SecretSource {
-	return es.Spec.SecureSettings
+	var remoteClusterAPIKeys, repositorySecureSettings int
+	for _, remoteCluster := range es.Spec.RemoteClusters {
+		if remoteCluster.APIKey != nil {
+			remoteClusterAPIKeys++
+		}
+	}
+	for _, repository := range es.Spec.SnapshotRepositories {
+		repositorySecureSettings += len(repository.SecureSettings)
+	}
+	if len(es.Spec.Auth.OIDC) == 0 && remoteClusterAPIKeys == 0 && repositorySecureSettings == 0 {
+		return es.Spec.SecureSettings
+	}
+	secureSettings := make([]commonv1.SecretSource, 0, len(es.Spec.SecureSettings)+len(es.Spec.Auth.OIDC)+remoteClusterAPIKeys+repositorySecureSettings)
+	secureSettings = append(secureSettings, es.Spec.SecureSettings...)
+	// load the client secrets of the OpenID Connect realms in the keystore
+	for _, realm := range es.Spec.Auth.OIDC {
+		secureSettings = append(secureSettings, commonv1.SecretSource{
+			SecretName: realm.ClientSecret.SecretName,
+			Entries:    []commonv1.KeyToPath{{Key: realm.ClientSecret.Key, Path: OIDCClientSecretSetting(realm.Name)}},
+		})
+	}
+	// load the cross-cluster API keys of the remote clusters in the keystore
+	for _, remoteCluster := range es.Spec.RemoteClusters {
+		if remoteCluster.APIKey == nil {
+			continue
+		}
+		secureSettings = append(secureSettings, commonv1.SecretSource{
+			SecretName: remoteCluster.APIKey.SecretName,
+			Entries:    []commonv1.KeyToPath{{Key: remoteCluster.APIKey.Key, Path: RemoteClusterCredentialsSetting(remoteCluster.Name)}},
+		})
+	}
+	// load the client credentials of the snapshot repositories in the keystore
+	for _, repository := range es.Spec.SnapshotRepositories {
+		secureSettings = append(secureSettings, repository.SecureSettings...)
+	}
+	return secureSettings
 }
 
 func (es Elasticsearch) SuspendedPodNames() set.StringSet {
 	return setFromAnnotations(SuspendAnnotation, es.Annotations)
 }
 
+// StorageClassMigrationNodeSets returns the names of the nodeSets that can be moved to a new storage class.
+func (es Elasticsearch) StorageClassMigrationNodeSets() set.StringSet {
+	return setFromAnnotations(StorageClassMigrationAnnotation, es.Annotations)
+}
+
+// VolumeResizeMigrationNodeSets returns the names of the nodeSets that can be moved to new volumes when their storage
+// class does not allow volume expansion.
+func (es Elasticsearch) VolumeResizeMigrationNodeSets() set.StringSet {
+	return setFromAnnotations(VolumeResizeMigrationAnnotation, es.Annotations)
+}
+
+// VolumeSnapshotPrepopulationNodeSets returns the names of the nodeSets whose missing volumes are created from a
+// VolumeSnapshot.
+func (es Elasticsearch) VolumeSnapshotPrepopulationNodeSets() set.StringSet {
+	return setFromAnnotations(VolumeSnapshotPrepopulationAnnotation, es.Annotations)
+}
+
+// StatefulSetNameSuffixes returns the suffixes appended to the StatefulSet names of the nodeSets moved to a new
+// storage class or to larger volumes, indexed by nodeSet name. An invalid annotation is ignored: it can only be set by the operator.
+func (es Elasticsearch) StatefulSetNameSuffixes() map[string]string {
+	suffixes := map[string]string{}
+	value, exists := es.Annotations[StatefulSetNameSuffixesAnnotation]
+	if !exists {
+		return suffixes
+	}
+	if err := json.Unmarshal([]byte(value), &suffixes); err != nil {
+		return map[string]string{}
+	}
+	return suffixes
+}
+
 // GetObservedGeneration will return the observed generation from the Elasticsearch status.
 func (es Elasticsearch) GetObservedGeneration() int64 {
 	return es.Status.ObservedGeneration
@@ -508,7 +1354,7 @@ func (es *Elasticsearch) GetAssociations() []commonv1.Association {
 			})
 		}
 	}
-	for _, ref := range es.Spec.Monitoring.Logs.ElasticsearchRefs {
+	for _, ref := range es.GetMonitoringLogsRefs() {
 		if ref.IsDefined() {
 			associations = append(associations, &EsMonitoringAssociation{
 				Elasticsearch: es,
@@ -580,10 +1426,23 @@ func (es *Elasticsearch) GetMonitoringMetricsRefs() []commonv1.ObjectSelector {
 	return es.Spec.Monitoring.Metrics.ElasticsearchRefs
 }
 
+// GetMonitoringLogsRefs returns the references to the clusters receiving the logs of this cluster. The audit log
+// references share the Filebeat sidecar and the association of the logs monitoring, and are only used if no logs
+// monitoring reference is set.
 func (es *Elasticsearch) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
+	if len(es.Spec.Monitoring.Logs.ElasticsearchRefs) == 0 && es.Spec.Auditing != nil {
+		return es.Spec.Auditing.ElasticsearchRefs
+	}
 	return es.Spec.Monitoring.Logs.ElasticsearchRefs
 }
 
+// AuditLogsOnly returns true if only the audit log is shipped to a monitoring cluster, through the references of
+// spec.auditing.
+func (es *Elasticsearch) AuditLogsOnly() bool {
+	return len(es.Spec.Monitoring.Logs.ElasticsearchRefs) == 0 && es.Spec.Auditing != nil &&
+		len(es.Spec.Auditing.ElasticsearchRefs) > 0
+}
+
 func (es *Elasticsearch) MonitoringAssociation(ref commonv1.ObjectSelector) commonv1.Association {
 	return &EsMonitoringAssociation{
 		Elasticsearch: es,
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -396,3 +397,117 @@ func Test_AssociationConfs(t *testing.T) {
 	}
 	assert.Equal(t, 2, len(esMon.AssocConfs))
 }
+
+func TestFrozenCache_SharedCacheSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    string
+		wantErr bool
+	}{
+		{size: "90%", want: "90%"},
+		{size: "12.5%", want: "12.5%"},
+		{size: "100Gi", want: "107374182400b"},
+		{size: "500M", want: "500000000b"},
+		{size: "0%", wantErr: true},
+		{size: "101%", wantErr: true},
+		{size: "-1Gi", wantErr: true},
+		{size: "lots", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := FrozenCache{Size: tt.size}.SharedCacheSize()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestElasticsearch_SecureSettings(t *testing.T) {
+	es := Elasticsearch{Spec: ElasticsearchSpec{
+		SecureSettings: []commonv1.SecretSource{{SecretName: "user-settings"}},
+		Auth: Auth{OIDC: []OIDCRealm{{
+			SSORealm:     SSORealm{Name: "okta"},
+			ClientSecret: SecretKeyRef{SecretName: "okta-client", Key: "secret"},
+		}}},
+	}}
+	require.Equal(t, []commonv1.SecretSource{
+		{SecretName: "user-settings"},
+		{SecretName: "okta-client", Entries: []commonv1.KeyToPath{{Key: "secret", Path: "xpack.security.authc.realms.oidc.okta.rp.client_secret"}}},
+	}, es.SecureSettings())
+}
+
+func TestElasticsearch_SecureSettings_RemoteClusters(t *testing.T) {
+	es := Elasticsearch{Spec: ElasticsearchSpec{
+		RemoteClusters: []RemoteCluster{
+			{Name: "internal", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es2"}},
+			{Name: "external", Seeds: []string{"es.example.com:9443"}, APIKey: &SecretKeyRef{SecretName: "remote-keys", Key: "external"}},
+		},
+	}}
+	require.Equal(t, []commonv1.SecretSource{
+		{SecretName: "remote-keys", Entries: []commonv1.KeyToPath{{Key: "external", Path: "cluster.remote.external.credentials"}}},
+	}, es.SecureSettings())
+
+	// without API keys, only the user secure settings are loaded
+	es.Spec.RemoteClusters = es.Spec.RemoteClusters[:1]
+	require.Empty(t, es.SecureSettings())
+}
+
+func TestElasticsearch_SecureSettings_SnapshotRepositories(t *testing.T) {
+	es := Elasticsearch{Spec: ElasticsearchSpec{
+		SecureSettings: []commonv1.SecretSource{{SecretName: "user-settings"}},
+		SnapshotRepositories: []SnapshotRepository{
+			{Name: "backups", Type: "s3", SecureSettings: []commonv1.SecretSource{{SecretName: "s3-credentials"}}},
+			{Name: "local", Type: "fs"},
+		},
+	}}
+	require.Equal(t, []commonv1.SecretSource{
+		{SecretName: "user-settings"},
+		{SecretName: "s3-credentials"},
+	}, es.SecureSettings())
+}
+
+func TestElasticsearch_KibanaSSORealms(t *testing.T) {
+	es := Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns"},
+		Spec: ElasticsearchSpec{Auth: Auth{
+			OIDC: []OIDCRealm{
+				{SSORealm: SSORealm{Name: "okta", KibanaRef: commonv1.LocalObjectSelector{Name: "kb"}}},
+				{SSORealm: SSORealm{Name: "other", KibanaRef: commonv1.LocalObjectSelector{Name: "kb", Namespace: "other"}}},
+			},
+			SAML: []SAMLRealm{
+				{SSORealm: SSORealm{Name: "azure", Order: ptr.To[int32](10), Description: "Log in with Azure", KibanaRef: commonv1.LocalObjectSelector{Name: "kb", Namespace: "ns"}}},
+			},
+		}},
+	}
+	require.Equal(t, []KibanaSSORealm{
+		{Type: "oidc", Name: "okta", Order: 1, KibanaRef: commonv1.LocalObjectSelector{Name: "kb"}},
+		{Type: "saml", Name: "azure", Order: 10, Description: "Log in with Azure", KibanaRef: commonv1.LocalObjectSelector{Name: "kb", Namespace: "ns"}},
+	}, es.KibanaSSORealms(types.NamespacedName{Namespace: "ns", Name: "kb"}))
+	require.Len(t, es.KibanaSSORealms(types.NamespacedName{Namespace: "other", Name: "kb"}), 1)
+	require.Empty(t, es.KibanaSSORealms(types.NamespacedName{Namespace: "ns", Name: "unknown"}))
+}
+
+func TestElasticsearch_GetMonitoringLogsRefs(t *testing.T) {
+	logs := []commonv1.ObjectSelector{{Name: "logs", Namespace: "observability"}}
+	audit := []commonv1.ObjectSelector{{Name: "audit", Namespace: "observability"}}
+	es := Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default"}}
+	require.Empty(t, es.GetMonitoringLogsRefs())
+	require.False(t, es.AuditLogsOnly())
+
+	// the audit log references share the association of the logs monitoring
+	es.Spec.Auditing = &Auditing{ElasticsearchRefs: audit}
+	require.Equal(t, audit, es.GetMonitoringLogsRefs())
+	require.True(t, es.AuditLogsOnly())
+	require.Len(t, es.GetAssociations(), 1)
+	require.Equal(t, commonv1.ObjectSelector{Name: "audit", Namespace: "observability"}, es.GetAssociations()[0].AssociationRef())
+
+	// the audit log is shipped with the other logs
+	es.Spec.Monitoring.Logs.ElasticsearchRefs = logs
+	require.Equal(t, logs, es.GetMonitoringLogsRefs())
+	require.False(t, es.AuditLogsOnly())
+	require.Len(t, es.GetAssociations(), 1)
+}
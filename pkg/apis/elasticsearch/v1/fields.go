@@ -34,6 +34,8 @@ const (
 	ShardAwarenessAttributes = "cluster.routing.allocation.awareness.attributes"
 	NodeAttr                 = "node.attr"
 
+	SearchableSnapshotSharedCacheSize = "xpack.searchable.snapshot.shared_cache.size"
+
 	XPackSecurityAuthcRealmsFileFile1Order     = "xpack.security.authc.realms.file.file1.order"     // 7.x realm syntax
 	XPackSecurityAuthcRealmsFile1Order         = "xpack.security.authc.realms.file1.order"          // 6.x realm syntax
 	XPackSecurityAuthcRealmsFile1Type          = "xpack.security.authc.realms.file1.type"           // 6.x realm syntax
@@ -41,6 +43,13 @@ const (
 	XPackSecurityAuthcRealmsNative1Order       = "xpack.security.authc.realms.native1.order"        // 6.x realm syntax
 	XPackSecurityAuthcRealmsNative1Type        = "xpack.security.authc.realms.native1.type"         // 6.x realm syntax
 
+	XPackSecurityAuthcRealms = "xpack.security.authc.realms"
+
+	XPackSecurityAuditEnabled                    = "xpack.security.audit.enabled"
+	XPackSecurityAuditLogfileEventsExclude       = "xpack.security.audit.logfile.events.exclude"
+	XPackSecurityAuditLogfileEventsIgnoreFilters = "xpack.security.audit.logfile.events.ignore_filters"
+	XPackSecurityAuditLogfileEventsInclude       = "xpack.security.audit.logfile.events.include"
+
 	XPackSecurityAuthcReservedRealmEnabled          = "xpack.security.authc.reserved_realm.enabled"
 	XPackSecurityEnabled                            = "xpack.security.enabled"
 	XPackSecurityHttpSslCertificate                 = "xpack.security.http.ssl.certificate"             //nolint:revive
@@ -76,3 +85,19 @@ var UnsupportedSettings = []string{
 	XPackSecurityTransportSslEnabled,
 	XPackSecurityTransportSslVerificationMode,
 }
+
+// SSORealmSetting returns the key of a setting of the OpenID Connect or SAML realm of the given type and name.
+func SSORealmSetting(realmType, name, setting string) string {
+	return XPackSecurityAuthcRealms + "." + realmType + "." + name + "." + setting
+}
+
+// OIDCClientSecretSetting returns the key of the secure setting holding the client secret of the given OpenID Connect realm.
+func OIDCClientSecretSetting(name string) string {
+	return SSORealmSetting(OIDCRealmType, name, "rp.client_secret")
+}
+
+// RemoteClusterCredentialsSetting returns the key of the secure setting holding the cross-cluster API key of the given
+// remote cluster.
+func RemoteClusterCredentialsSetting(name string) string {
+	return "cluster.remote." + name + ".credentials"
+}
@@ -9,8 +9,10 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	common_name "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
@@ -29,7 +31,9 @@ const (
 	unicastHostsConfigMapSuffix                  = "unicast-hosts"
 	licenseSecretSuffix                          = "license"
 	defaultPodDisruptionBudget                   = "default"
+	podDisruptionBudgetSuffix                    = "pdb"
 	scriptsConfigMapSuffix                       = "scripts"
+	diagnosticsConfigMapSuffix                   = "diagnostics"
 	legacyTransportCertsSecretSuffix             = "transport-certificates"
 	statefulSetTransportCertificatesSecretSuffix = "transport-certs"
 
@@ -41,6 +45,9 @@ const (
 	remoteCaNameSuffix = "remote-ca"
 
 	controllerRevisionHashLen = 10
+	// statefulSetNameSuffixLen is the length of the suffix appended to the StatefulSet name of a nodeSet moved to a
+	// new storage class.
+	statefulSetNameSuffixLen = 6
 )
 
 var (
@@ -60,6 +67,7 @@ var (
 		scriptsConfigMapSuffix,
 		statefulSetTransportCertificatesSecretSuffix,
 		remoteCaNameSuffix,
+		diagnosticsConfigMapSuffix,
 	}
 )
 
@@ -70,7 +78,7 @@ func ValidateNames(es Elasticsearch) error {
 	}
 	nodeSetNames := map[string]struct{}{}
 	// validate ssets
-	for _, nodeSet := range es.Spec.NodeSets {
+	for _, nodeSet := range es.Spec.ExpandedNodeSets() {
 		if _, ok := nodeSetNames[nodeSet.Name]; ok {
 			return errors.Errorf("duplicated nodeSet name: '%s'", nodeSet.Name)
 		}
@@ -80,7 +88,14 @@ func ValidateNames(es Elasticsearch) error {
 			return errors.Errorf("invalid nodeSet name '%s': [%s]", nodeSet.Name, strings.Join(errs, ","))
 		}
 
-		ssetName, err := ESNamer.SafeSuffix(es.Name, nodeSet.Name)
+		ssetSuffixes := []string{nodeSet.Name}
+		if suffix, exists := es.StatefulSetNameSuffixes()[nodeSet.Name]; exists {
+			ssetSuffixes = append(ssetSuffixes, suffix)
+		} else if es.StorageClassMigrationNodeSets().Has(nodeSet.Name) || es.VolumeResizeMigrationNodeSets().Has(nodeSet.Name) {
+			// leave enough space for a suffix to be appended if the storage class or the volume size is changed
+			ssetSuffixes = append(ssetSuffixes, strings.Repeat("x", statefulSetNameSuffixLen))
+		}
+		ssetName, err := ESNamer.SafeSuffix(es.Name, ssetSuffixes...)
 		if err != nil {
 			return errors.Wrapf(err, "error generating StatefulSet name for nodeSet: '%s'", nodeSet.Name)
 		}
@@ -110,6 +125,41 @@ func StatefulSet(esName string, nodeSetName string) string {
 	return ESNamer.Suffix(esName, nodeSetName)
 }
 
+// StatefulSetName returns the name of the StatefulSet corresponding to the given NodeSet, including the suffix
+// appended to it if the NodeSet has been moved to a new storage class.
+func (es Elasticsearch) StatefulSetName(nodeSetName string) string {
+	if suffix, exists := es.StatefulSetNameSuffixes()[nodeSetName]; exists {
+		return ESNamer.Suffix(es.Name, nodeSetName, suffix)
+	}
+	return StatefulSet(es.Name, nodeSetName)
+}
+
+// IsStatefulSetOfNodeSet returns true if the given StatefulSet name is the name of a StatefulSet of the given NodeSet,
+// with or without the suffix appended to it when the NodeSet is moved to new volumes.
+func IsStatefulSetOfNodeSet(esName string, nodeSetName string, statefulSetName string) bool {
+	name := StatefulSet(esName, nodeSetName)
+	if statefulSetName == name {
+		return true
+	}
+	suffix, found := strings.CutPrefix(statefulSetName, name+"-")
+	return found && len(suffix) == statefulSetNameSuffixLen && !strings.Contains(suffix, "-")
+}
+
+// NewStatefulSetNameSuffix returns the suffix to append to the StatefulSet name of a NodeSet moved to the storage
+// classes and sizes of the given volume claim templates.
+func NewStatefulSetNameSuffix(claims []corev1.PersistentVolumeClaim) string {
+	storageClasses := make([]string, 0, len(claims))
+	for _, claim := range claims {
+		storageClasses = append(storageClasses,
+			claim.Name+"="+ptr.Deref(claim.Spec.StorageClassName, "")+":"+claim.Spec.Resources.Requests.Storage().String())
+	}
+	suffix := hash.HashObject(storageClasses)
+	if len(suffix) > statefulSetNameSuffixLen {
+		suffix = suffix[len(suffix)-statefulSetNameSuffixLen:]
+	}
+	return suffix
+}
+
 func ConfigSecret(ssetName string) string {
 	return ESNamer.Suffix(ssetName, configSecretSuffix)
 }
@@ -122,6 +172,15 @@ func StatefulSetTransportCertificatesSecret(ssetName string) string {
 	return ESNamer.Suffix(ssetName, statefulSetTransportCertificatesSecretSuffix)
 }
 
+// StatefulSetTransportCertificatesSecretShard returns the name of the given shard of the Secrets containing the transport
+// certificates of a StatefulSet. The first shard is the Secret used when certificates are not sharded.
+func StatefulSetTransportCertificatesSecretShard(ssetName string, shard int32) string {
+	if shard == 0 {
+		return StatefulSetTransportCertificatesSecret(ssetName)
+	}
+	return ESNamer.Suffix(ssetName, statefulSetTransportCertificatesSecretSuffix, strconv.Itoa(int(shard)))
+}
+
 // LegacyTransportCertsSecretSuffix returns the former name of the Secret which used to contain the transport certificates.
 // This function only exists to let the controller delete that Secret.
 func LegacyTransportCertsSecretSuffix(esName string) string {
@@ -140,6 +199,11 @@ func HTTPService(esName string) string {
 	return ESNamer.Suffix(esName, httpServiceSuffix)
 }
 
+// ServiceProfileService returns the name of the HTTP Service of the given service profile.
+func ServiceProfileService(esName string, profileName string) string {
+	return ESNamer.Suffix(esName, profileName, httpServiceSuffix)
+}
+
 func ElasticUserSecret(esName string) string {
 	return ESNamer.Suffix(esName, elasticUserSecretSuffix)
 }
@@ -161,6 +225,17 @@ func ScriptsConfigMap(esName string) string {
 	return ESNamer.Suffix(esName, scriptsConfigMapSuffix)
 }
 
+// DiagnosticsConfigMap returns the name of the ConfigMap into which the operator dumps its internal state for a given cluster.
+func DiagnosticsConfigMap(esName string) string {
+	return ESNamer.Suffix(esName, diagnosticsConfigMapSuffix)
+}
+
+// DiagnosticsBundleSecret returns the name of the Secret into which the operator writes the diagnostics bundle of a
+// given cluster. It shares the name of the diagnostics ConfigMap.
+func DiagnosticsBundleSecret(esName string) string {
+	return ESNamer.Suffix(esName, diagnosticsConfigMapSuffix)
+}
+
 func LicenseSecretName(esName string) string {
 	return ESNamer.Suffix(esName, licenseSecretSuffix)
 }
@@ -169,6 +244,11 @@ func DefaultPodDisruptionBudget(esName string) string {
 	return ESNamer.Suffix(esName, defaultPodDisruptionBudget)
 }
 
+// NodeSetPodDisruptionBudget returns the name of the PodDisruptionBudget overriding the default one for the given NodeSet.
+func NodeSetPodDisruptionBudget(esName string, nodeSetName string) string {
+	return ESNamer.Suffix(esName, nodeSetName, podDisruptionBudgetSuffix)
+}
+
 func RemoteCaSecretName(esName string) string {
 	return ESNamer.Suffix(esName, remoteCaNameSuffix)
 }
@@ -8,13 +8,17 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 )
 
 func TestValidate(t *testing.T) {
 	testCases := []struct {
 		name          string
 		esName        string
+		annotations   map[string]string
 		nodeSpecNames []string
 		wantErr       bool
 		wantErrMsg    string
@@ -53,14 +57,30 @@ func TestValidate(t *testing.T) {
 			wantErr:       true,
 			wantErrMsg:    "duplicated nodeSet name",
 		},
+		{
+			name:          "nodeSet name too long to be moved to a new storage class",
+			esName:        "test-es",
+			annotations:   map[string]string{StorageClassMigrationAnnotation: "a-long-nodeset-name"},
+			nodeSpecNames: []string{"default", "a-long-nodeset-name"},
+			wantErr:       true,
+			wantErrMsg:    "suffix exceeds max length",
+		},
+		{
+			name:          "nodeSet moved to a new storage class",
+			esName:        "test-es",
+			annotations:   map[string]string{StatefulSetNameSuffixesAnnotation: `{"default":"123456"}`},
+			nodeSpecNames: []string{"default", "a-long-nodeset-name"},
+			wantErr:       false,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			es := Elasticsearch{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      tc.esName,
-					Namespace: "test",
+					Name:        tc.esName,
+					Namespace:   "test",
+					Annotations: tc.annotations,
 				},
 				Spec: ElasticsearchSpec{},
 			}
@@ -79,3 +99,64 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestElasticsearch_StatefulSetName(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{
+		{
+			name: "no suffix",
+			want: "test-es-es-default",
+		},
+		{
+			name:        "nodeSet moved to a new storage class",
+			annotations: map[string]string{StatefulSetNameSuffixesAnnotation: `{"default":"123456","other":"654321"}`},
+			want:        "test-es-es-default-123456",
+		},
+		{
+			name:        "other nodeSet moved to a new storage class",
+			annotations: map[string]string{StatefulSetNameSuffixesAnnotation: `{"other":"654321"}`},
+			want:        "test-es-es-default",
+		},
+		{
+			name:        "invalid annotation",
+			annotations: map[string]string{StatefulSetNameSuffixesAnnotation: `default=123456`},
+			want:        "test-es-es-default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "test-es", Annotations: tt.annotations}}
+			require.Equal(t, tt.want, es.StatefulSetName("default"))
+		})
+	}
+}
+
+func TestNewStatefulSetNameSuffix(t *testing.T) {
+	claims := func(storageClass string, storage string) []corev1.PersistentVolumeClaim {
+		return []corev1.PersistentVolumeClaim{{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: ptr.To(storageClass),
+				Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(storage),
+				}},
+			},
+		}}
+	}
+	suffix := NewStatefulSetNameSuffix(claims("fast", "1Gi"))
+	require.Len(t, suffix, statefulSetNameSuffixLen)
+	require.Equal(t, suffix, NewStatefulSetNameSuffix(claims("fast", "1Gi")))
+	require.NotEqual(t, suffix, NewStatefulSetNameSuffix(claims("slow", "1Gi")))
+	require.NotEqual(t, suffix, NewStatefulSetNameSuffix(claims("fast", "2Gi")))
+}
+
+func TestIsStatefulSetOfNodeSet(t *testing.T) {
+	require.True(t, IsStatefulSetOfNodeSet("es", "data", "es-es-data"))
+	require.True(t, IsStatefulSetOfNodeSet("es", "data", "es-es-data-a1b2c3"))
+	require.False(t, IsStatefulSetOfNodeSet("es", "data", "es-es-data-hot"))
+	require.False(t, IsStatefulSetOfNodeSet("es", "data", "es-es-data-hot-a1b2c3"))
+	require.False(t, IsStatefulSetOfNodeSet("es", "data", "es-es-master"))
+}
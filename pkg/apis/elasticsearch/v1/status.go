@@ -76,6 +76,23 @@ type ElasticsearchStatus struct {
 	// **This API is in technical preview and may be changed or removed in a future release.**
 	InProgressOperations `json:"inProgressOperations"`
 
+	// PreUpgradeVolumeSnapshots lists the VolumeSnapshots taken before the version upgrades of the cluster.
+	// +optional
+	PreUpgradeVolumeSnapshots []UpgradeVolumeSnapshots `json:"preUpgradeVolumeSnapshots,omitempty"`
+
+	// InitialRestore reports the progress of the restore of the snapshot specified in spec.initialRestore.
+	// +optional
+	InitialRestore *InitialRestoreStatus `json:"initialRestore,omitempty"`
+
+	// RemoteClusters reports the state of the connections to the remote clusters declared in spec.remoteClusters.
+	// +optional
+	RemoteClusters []RemoteClusterStatus `json:"remoteClusters,omitempty"`
+
+	// SnapshotLifecyclePolicies reports the executions of the snapshot lifecycle policies declared in
+	// spec.snapshotLifecyclePolicies.
+	// +optional
+	SnapshotLifecyclePolicies []SnapshotLifecyclePolicyStatus `json:"snapshotLifecyclePolicies,omitempty"`
+
 	// ObservedGeneration is the most recent generation observed for this Elasticsearch cluster.
 	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
 	// If the generation observed in status diverges from the generation in metadata, the Elasticsearch
@@ -83,6 +100,80 @@ type ElasticsearchStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// UpgradeVolumeSnapshots are the VolumeSnapshots taken before a version upgrade.
+type UpgradeVolumeSnapshots struct {
+	// FromVersion is the version of Elasticsearch running when the snapshots were taken.
+	FromVersion string `json:"fromVersion"`
+	// ToVersion is the version of Elasticsearch the cluster was upgraded to.
+	ToVersion string `json:"toVersion"`
+	// VolumeSnapshots are the names of the VolumeSnapshots.
+	VolumeSnapshots []string `json:"volumeSnapshots"`
+}
+
+// RemoteClusterStatus is the state of the connection to a remote cluster, as reported by the remote cluster info API.
+type RemoteClusterStatus struct {
+	// Name is the alias of the remote cluster.
+	Name string `json:"name"`
+	// Connected is true if the cluster is connected to the remote cluster.
+	Connected bool `json:"connected"`
+	// ConnectedNodes is the number of nodes of the remote cluster the cluster is connected to.
+	// +optional
+	ConnectedNodes int32 `json:"connectedNodes,omitempty"`
+	// LastError explains why the remote cluster is not connected.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// LastTransitionTime is the last time the connection state changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// SnapshotLifecyclePolicyStatus is the status of a snapshot lifecycle policy configured on the cluster.
+type SnapshotLifecyclePolicyStatus struct {
+	// Name is the id of the policy in Elasticsearch.
+	Name string `json:"name"`
+	// LastSuccessTime is the time of the last snapshot successfully taken by the policy.
+	// +optional
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+	// LastFailureTime is the time of the last snapshot the policy failed to take.
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+	// LastFailure holds the reason of the last snapshot the policy failed to take.
+	// +optional
+	LastFailure string `json:"lastFailure,omitempty"`
+	// FailingSince is the time of the first failure following the last successful snapshot, if the policy failed
+	// its last snapshot.
+	// +optional
+	FailingSince *metav1.Time `json:"failingSince,omitempty"`
+	// NextExecutionTime is the time at which the policy takes the next snapshot.
+	// +optional
+	NextExecutionTime *metav1.Time `json:"nextExecutionTime,omitempty"`
+}
+
+// InitialRestorePhase is the phase of the restore of the initial snapshot of a cluster.
+type InitialRestorePhase string
+
+const (
+	// InitialRestorePendingPhase is the phase of a restore waiting for the cluster to be reachable or for the
+	// snapshot to exist.
+	InitialRestorePendingPhase InitialRestorePhase = "Pending"
+	// InitialRestoreInProgressPhase is the phase of a restore started in Elasticsearch.
+	InitialRestoreInProgressPhase InitialRestorePhase = "InProgress"
+	// InitialRestoreCompletedPhase is the phase of a restore that completed.
+	InitialRestoreCompletedPhase InitialRestorePhase = "Completed"
+	// InitialRestoreFailedPhase is the phase of a restore Elasticsearch refused to start.
+	InitialRestoreFailedPhase InitialRestorePhase = "Failed"
+)
+
+// InitialRestoreStatus is the status of the restore of the initial snapshot of a cluster.
+type InitialRestoreStatus struct {
+	// Phase is the phase of the restore.
+	Phase InitialRestorePhase `json:"phase"`
+	// Snapshot is the name of the restored snapshot, resolved from the snapshot pattern of the specification.
+	Snapshot string `json:"snapshot,omitempty"`
+	// Message explains why the restore is not started yet, or why it failed.
+	Message string `json:"message,omitempty"`
+}
+
 // IsDegraded returns true if the current status is worse than the previous.
 func (es ElasticsearchStatus) IsDegraded(prev ElasticsearchStatus) bool {
 	return es.Health.Less(prev.Health)
@@ -106,10 +197,44 @@ func (es *Elasticsearch) SetAssociationStatusMap(typ commonv1.AssociationType, s
 }
 
 const (
+	// BackupHealthy reports whether the snapshot lifecycle policies of a cluster with a snapshot repository are
+	// running without failure.
+	BackupHealthy v1alpha1.ConditionType = "BackupHealthy"
+	// DiskSpaceAvailable reports whether the disk usage of all the nodes is below the high disk watermark.
+	DiskSpaceAvailable       v1alpha1.ConditionType = "DiskSpaceAvailable"
 	ElasticsearchIsReachable v1alpha1.ConditionType = "ElasticsearchIsReachable"
 	ReconciliationComplete   v1alpha1.ConditionType = "ReconciliationComplete"
+	// ReconciliationStalled reports whether changes have been pending for longer than the threshold configured in the
+	// operator, with a reason describing what is not progressing.
+	ReconciliationStalled    v1alpha1.ConditionType = "ReconciliationStalled"
 	ResourcesAwareManagement v1alpha1.ConditionType = "ResourcesAwareManagement"
 	RunningDesiredVersion    v1alpha1.ConditionType = "RunningDesiredVersion"
+	// SnapshotLifecyclePoliciesReady reports whether the snapshot lifecycle policies of the specification are
+	// configured, and have not been failing for longer than their failure threshold.
+	SnapshotLifecyclePoliciesReady v1alpha1.ConditionType = "SnapshotLifecyclePoliciesReady"
+	// SnapshotRepositoriesReady reports whether the snapshot repositories of the specification are registered and
+	// verified.
+	SnapshotRepositoriesReady v1alpha1.ConditionType = "SnapshotRepositoriesReady"
+	// UpgradePreflightChecksPassed reports whether the cluster has no critical deprecation issue preventing an upgrade
+	// to the next major version of Elasticsearch.
+	UpgradePreflightChecksPassed v1alpha1.ConditionType = "UpgradePreflightChecksPassed"
+	// VolumeExpansionComplete reports the progress of the expansion of the volumes of the cluster, once the storage
+	// requests of a volume claim template have been increased.
+	VolumeExpansionComplete v1alpha1.ConditionType = "VolumeExpansionComplete"
+)
+
+// Reasons of the ReconciliationStalled condition.
+const (
+	// StalledBootstrapNotComplete reports that the cluster has not formed yet.
+	StalledBootstrapNotComplete = "BootstrapNotComplete"
+	// StalledNodesNotJoining reports that some of the expected nodes are not available.
+	StalledNodesNotJoining = "NodesNotJoining"
+	// StalledDataMigrationNotProgressing reports that the data of the nodes to remove is not migrated.
+	StalledDataMigrationNotProgressing = "DataMigrationNotProgressing"
+	// StalledChangesPending reports that changes are pending for any other reason.
+	StalledChangesPending = "ChangesPending"
+	// NotStalled reports that no change has been pending for longer than the threshold.
+	NotStalled = "NotStalled"
 )
 
 // NewNodeStatus provides details about the status of nodes which are expected to be created and added to the Elasticsearch cluster.
@@ -187,6 +312,20 @@ type DownscaledNode struct {
 	// Explanation provides details about an in progress node shutdown. It is only available for clusters managed with the
 	// Elasticsearch shutdown API.
 	Explanation *string `json:"explanation,omitempty"`
+
+	// RemainingShards is the number of shards still to be migrated away from the node before it can be removed.
+	// +optional
+	RemainingShards *int `json:"remainingShards,omitempty"`
+
+	// RemainingBytes is the size in bytes of the shards still to be migrated away from the node. It is not available
+	// if the size of the shards cannot be retrieved.
+	// +optional
+	RemainingBytes *int64 `json:"remainingBytes,omitempty"`
+
+	// EstimatedCompletionTime is when the data migration is expected to be complete, given the rate at which data has
+	// been migrated away from the node since the previous update of the status.
+	// +optional
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
 }
 
 // DownscaleOperation provides details about in progress downscale operations.
@@ -197,6 +336,11 @@ type DownscaleOperation struct {
 	// Nodes which are scheduled to be removed from the cluster.
 	Nodes []DownscaledNode `json:"nodes,omitempty"`
 
+	// ExcludedNodes are the nodes being drained of their data, either excluded from shard allocation or shut down with
+	// the Elasticsearch shutdown API.
+	// +optional
+	ExcludedNodes []string `json:"excludedNodes,omitempty"`
+
 	// Stalled represents a state where no progress can be made.
 	// It is only available for clusters managed with the Elasticsearch shutdown API.
 	// +optional
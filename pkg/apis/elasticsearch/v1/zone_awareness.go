@@ -0,0 +1,118 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ZoneAttr is the node attribute holding the zone of the nodes of zone-aware NodeSets.
+	ZoneAttr = "zone"
+	// DefaultZoneTopologyKey is the default label of the Kubernetes nodes holding their zone.
+	DefaultZoneTopologyKey = corev1.LabelTopologyZone
+)
+
+var invalidZoneNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// TopologyKeyOrDefault returns the label of the Kubernetes nodes holding their zone.
+func (z ZoneAwareness) TopologyKeyOrDefault() string {
+	if z.TopologyKey == "" {
+		return DefaultZoneTopologyKey
+	}
+	return z.TopologyKey
+}
+
+// ZoneNodeSetName returns the name of the NodeSet holding the nodes of the given zone-aware NodeSet in the given zone.
+// Characters of the zone that are not allowed in a NodeSet name are replaced with dashes.
+func ZoneNodeSetName(nodeSetName, zone string) string {
+	suffix := strings.Trim(invalidZoneNameChars.ReplaceAllString(strings.ToLower(zone), "-"), "-")
+	return nodeSetName + "-" + suffix
+}
+
+// Zone returns the zone of the nodes of a NodeSet expanded from a zone-aware NodeSet, or an empty string.
+func (n NodeSet) Zone() string {
+	if n.ZoneAwareness == nil || len(n.ZoneAwareness.Zones) != 1 {
+		return ""
+	}
+	return n.ZoneAwareness.Zones[0]
+}
+
+// ExpandZones returns the NodeSets the given NodeSet is made of: one NodeSet per zone for a zone-aware NodeSet, the
+// NodeSet itself otherwise. The nodes are spread evenly across the zones, the first zones holding one more node if
+// the count is not a multiple of the number of zones. The Pods of each zone are scheduled on the Kubernetes nodes of
+// the zone, and the ZoneAwareness of each NodeSet only holds its zone.
+func (n NodeSet) ExpandZones() []NodeSet {
+	if n.ZoneAwareness == nil || len(n.ZoneAwareness.Zones) == 0 {
+		return []NodeSet{n}
+	}
+	zones := n.ZoneAwareness.Zones
+	topologyKey := n.ZoneAwareness.TopologyKeyOrDefault()
+	nodeSets := make([]NodeSet, 0, len(zones))
+	for i, zone := range zones {
+		nodeSet := n.DeepCopy()
+		nodeSet.Name = ZoneNodeSetName(n.Name, zone)
+		nodeSet.Count = n.Count / int32(len(zones))
+		if int32(i) < n.Count%int32(len(zones)) {
+			nodeSet.Count++
+		}
+		nodeSet.ZoneAwareness = &ZoneAwareness{TopologyKey: topologyKey, Zones: []string{zone}}
+		requireZone(&nodeSet.PodTemplate, topologyKey, zone)
+		nodeSets = append(nodeSets, *nodeSet)
+	}
+	return nodeSets
+}
+
+// requireZone restricts the scheduling of the Pods of the given template to the Kubernetes nodes of the given zone,
+// in addition to the node affinity of the template.
+func requireZone(template *corev1.PodTemplateSpec, topologyKey, zone string) {
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      topologyKey,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{zone},
+	}
+	if template.Spec.Affinity == nil {
+		template.Spec.Affinity = &corev1.Affinity{}
+	}
+	if template.Spec.Affinity.NodeAffinity == nil {
+		template.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	nodeAffinity := template.Spec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	selector := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(selector.NodeSelectorTerms) == 0 {
+		selector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	// terms are ORed, the zone must be required by each of them
+	for i := range selector.NodeSelectorTerms {
+		selector.NodeSelectorTerms[i].MatchExpressions = append(selector.NodeSelectorTerms[i].MatchExpressions, requirement)
+	}
+}
+
+// ExpandedNodeSets returns the NodeSets of the cluster, zone-aware NodeSets being expanded into one NodeSet per zone.
+// There is one StatefulSet per expanded NodeSet.
+func (es ElasticsearchSpec) ExpandedNodeSets() []NodeSet {
+	nodeSets := make([]NodeSet, 0, len(es.NodeSets))
+	for _, nodeSet := range es.NodeSets {
+		nodeSets = append(nodeSets, nodeSet.ExpandZones()...)
+	}
+	return nodeSets
+}
+
+// ZoneAware returns true if all the NodeSets of the cluster are zone-aware, in which case the allocation of the
+// shards takes the zones of the nodes into account.
+func (es ElasticsearchSpec) ZoneAware() bool {
+	for _, nodeSet := range es.NodeSets {
+		if nodeSet.ZoneAwareness == nil {
+			return false
+		}
+	}
+	return len(es.NodeSets) > 0
+}
@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNodeSet_ExpandZones(t *testing.T) {
+	t.Run("NodeSet without zone awareness", func(t *testing.T) {
+		nodeSet := NodeSet{Name: "default", Count: 3}
+		assert.Equal(t, []NodeSet{nodeSet}, nodeSet.ExpandZones())
+	})
+
+	t.Run("nodes are spread evenly across the zones", func(t *testing.T) {
+		nodeSet := NodeSet{
+			Name:          "data",
+			Count:         5,
+			ZoneAwareness: &ZoneAwareness{Zones: []string{"europe-west1-b", "europe-west1-c", "europe-west1-d"}},
+		}
+		nodeSets := nodeSet.ExpandZones()
+		require.Len(t, nodeSets, 3)
+		var names []string
+		var counts []int32
+		for _, ns := range nodeSets {
+			names = append(names, ns.Name)
+			counts = append(counts, ns.Count)
+		}
+		assert.Equal(t, []string{"data-europe-west1-b", "data-europe-west1-c", "data-europe-west1-d"}, names)
+		assert.Equal(t, []int32{2, 2, 1}, counts)
+		assert.Equal(t, "europe-west1-c", nodeSets[1].Zone())
+		assert.Equal(t, &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: DefaultZoneTopologyKey, Operator: corev1.NodeSelectorOpIn, Values: []string{"europe-west1-c"}},
+				},
+			}}},
+		}}, nodeSets[1].PodTemplate.Spec.Affinity)
+		// the original NodeSet is not modified
+		assert.Nil(t, nodeSet.PodTemplate.Spec.Affinity)
+		assert.Len(t, nodeSet.ZoneAwareness.Zones, 3)
+	})
+
+	t.Run("the zone is required by each node selector term of the template", func(t *testing.T) {
+		nodeSet := NodeSet{
+			Name:          "data",
+			Count:         1,
+			ZoneAwareness: &ZoneAwareness{TopologyKey: "zone", Zones: []string{"A_1"}},
+		}
+		nodeSet.PodTemplate.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "disk", Operator: corev1.NodeSelectorOpIn, Values: []string{"ssd"}}}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "disk", Operator: corev1.NodeSelectorOpIn, Values: []string{"nvme"}}}},
+			}},
+		}}
+		nodeSets := nodeSet.ExpandZones()
+		require.Len(t, nodeSets, 1)
+		assert.Equal(t, "data-a-1", nodeSets[0].Name)
+		for _, term := range nodeSets[0].PodTemplate.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			require.Len(t, term.MatchExpressions, 2)
+			assert.Equal(t, corev1.NodeSelectorRequirement{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"A_1"}}, term.MatchExpressions[1])
+		}
+		// the template of the original NodeSet is not modified
+		assert.Len(t, nodeSet.PodTemplate.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, 1)
+	})
+}
+
+func TestElasticsearchSpec_ZoneAware(t *testing.T) {
+	zoneAware := NodeSet{Name: "a", ZoneAwareness: &ZoneAwareness{Zones: []string{"a"}}}
+	assert.False(t, ElasticsearchSpec{}.ZoneAware())
+	assert.False(t, ElasticsearchSpec{NodeSets: []NodeSet{zoneAware, {Name: "b"}}}.ZoneAware())
+	assert.True(t, ElasticsearchSpec{NodeSets: []NodeSet{zoneAware}}.ZoneAware())
+}
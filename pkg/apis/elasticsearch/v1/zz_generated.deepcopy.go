@@ -12,9 +12,102 @@ import (
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalVolume) DeepCopyInto(out *AdditionalVolume) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalVolume.
+func (in *AdditionalVolume) DeepCopy() *AdditionalVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditIgnoreFilter) DeepCopyInto(out *AuditIgnoreFilter) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Realms != nil {
+		in, out := &in.Realms, &out.Realms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditIgnoreFilter.
+func (in *AuditIgnoreFilter) DeepCopy() *AuditIgnoreFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditIgnoreFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Auditing) DeepCopyInto(out *Auditing) {
+	*out = *in
+	if in.IncludeEvents != nil {
+		in, out := &in.IncludeEvents, &out.IncludeEvents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeEvents != nil {
+		in, out := &in.ExcludeEvents, &out.ExcludeEvents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IgnoreFilters != nil {
+		in, out := &in.IgnoreFilters, &out.IgnoreFilters
+		*out = make([]AuditIgnoreFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ElasticsearchRefs != nil {
+		in, out := &in.ElasticsearchRefs, &out.ElasticsearchRefs
+		*out = make([]commonv1.ObjectSelector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Auditing.
+func (in *Auditing) DeepCopy() *Auditing {
+	if in == nil {
+		return nil
+	}
+	out := new(Auditing)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Auth) DeepCopyInto(out *Auth) {
 	*out = *in
@@ -28,6 +121,20 @@ func (in *Auth) DeepCopyInto(out *Auth) {
 		*out = make([]FileRealmSource, len(*in))
 		copy(*out, *in)
 	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = make([]OIDCRealm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SAML != nil {
+		in, out := &in.SAML, &out.SAML
+		*out = make([]SAMLRealm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Auth.
@@ -128,6 +235,11 @@ func (in *DownscaleOperation) DeepCopyInto(out *DownscaleOperation) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ExcludedNodes != nil {
+		in, out := &in.ExcludedNodes, &out.ExcludedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Stalled != nil {
 		in, out := &in.Stalled, &out.Stalled
 		*out = new(bool)
@@ -153,6 +265,20 @@ func (in *DownscaledNode) DeepCopyInto(out *DownscaledNode) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.RemainingShards != nil {
+		in, out := &in.RemainingShards, &out.RemainingShards
+		*out = new(int)
+		**out = **in
+	}
+	if in.RemainingBytes != nil {
+		in, out := &in.RemainingBytes, &out.RemainingBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DownscaledNode.
@@ -256,6 +382,13 @@ func (in *ElasticsearchSettings) DeepCopy() *ElasticsearchSettings {
 func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 	*out = *in
 	in.HTTP.DeepCopyInto(&out.HTTP)
+	if in.HTTPServiceProfiles != nil {
+		in, out := &in.HTTPServiceProfiles, &out.HTTPServiceProfiles
+		*out = make([]ServiceProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.Transport.DeepCopyInto(&out.Transport)
 	if in.NodeSets != nil {
 		in, out := &in.NodeSets, &out.NodeSets
@@ -271,6 +404,11 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.Auth.DeepCopyInto(&out.Auth)
+	if in.Auditing != nil {
+		in, out := &in.Auditing, &out.Auditing
+		*out = new(Auditing)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SecureSettings != nil {
 		in, out := &in.SecureSettings, &out.SecureSettings
 		*out = make([]commonv1.SecretSource, len(*in))
@@ -278,10 +416,17 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.KeystorePassword != nil {
+		in, out := &in.KeystorePassword, &out.KeystorePassword
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
 	if in.RemoteClusters != nil {
 		in, out := &in.RemoteClusters, &out.RemoteClusters
 		*out = make([]RemoteCluster, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
 	if in.RevisionHistoryLimit != nil {
@@ -289,6 +434,40 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.PreUpgradeVolumeSnapshots != nil {
+		in, out := &in.PreUpgradeVolumeSnapshots, &out.PreUpgradeVolumeSnapshots
+		*out = new(PreUpgradeVolumeSnapshots)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotRepositories != nil {
+		in, out := &in.SnapshotRepositories, &out.SnapshotRepositories
+		*out = make([]SnapshotRepository, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SnapshotLifecyclePolicies != nil {
+		in, out := &in.SnapshotLifecyclePolicies, &out.SnapshotLifecyclePolicies
+		*out = make([]SnapshotLifecyclePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitialRestore != nil {
+		in, out := &in.InitialRestore, &out.InitialRestore
+		*out = new(InitialRestore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FinalSnapshot != nil {
+		in, out := &in.FinalSnapshot, &out.FinalSnapshot
+		*out = new(FinalSnapshot)
+		**out = **in
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]commonv1.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSpec.
@@ -319,6 +498,32 @@ func (in *ElasticsearchStatus) DeepCopyInto(out *ElasticsearchStatus) {
 		}
 	}
 	in.InProgressOperations.DeepCopyInto(&out.InProgressOperations)
+	if in.PreUpgradeVolumeSnapshots != nil {
+		in, out := &in.PreUpgradeVolumeSnapshots, &out.PreUpgradeVolumeSnapshots
+		*out = make([]UpgradeVolumeSnapshots, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitialRestore != nil {
+		in, out := &in.InitialRestore, &out.InitialRestore
+		*out = new(InitialRestoreStatus)
+		**out = **in
+	}
+	if in.RemoteClusters != nil {
+		in, out := &in.RemoteClusters, &out.RemoteClusters
+		*out = make([]RemoteClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SnapshotLifecyclePolicies != nil {
+		in, out := &in.SnapshotLifecyclePolicies, &out.SnapshotLifecyclePolicies
+		*out = make([]SnapshotLifecyclePolicyStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchStatus.
@@ -331,6 +536,31 @@ func (in *ElasticsearchStatus) DeepCopy() *ElasticsearchStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EphemeralStorage) DeepCopyInto(out *EphemeralStorage) {
+	*out = *in
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(corev1.CSIVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralStorage.
+func (in *EphemeralStorage) DeepCopy() *EphemeralStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(EphemeralStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EsMonitoringAssociation) DeepCopyInto(out *EsMonitoringAssociation) {
 	*out = *in
@@ -368,6 +598,62 @@ func (in *FileRealmSource) DeepCopy() *FileRealmSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalSnapshot) DeepCopyInto(out *FinalSnapshot) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalSnapshot.
+func (in *FinalSnapshot) DeepCopy() *FinalSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FrozenCache) DeepCopyInto(out *FrozenCache) {
+	*out = *in
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(FrozenCacheVolume)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrozenCache.
+func (in *FrozenCache) DeepCopy() *FrozenCache {
+	if in == nil {
+		return nil
+	}
+	out := new(FrozenCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FrozenCacheVolume) DeepCopyInto(out *FrozenCacheVolume) {
+	*out = *in
+	out.Storage = in.Storage.DeepCopy()
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrozenCacheVolume.
+func (in *FrozenCacheVolume) DeepCopy() *FrozenCacheVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(FrozenCacheVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InProgressOperations) DeepCopyInto(out *InProgressOperations) {
 	*out = *in
@@ -386,6 +672,117 @@ func (in *InProgressOperations) DeepCopy() *InProgressOperations {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitialRestore) DeepCopyInto(out *InitialRestore) {
+	*out = *in
+	in.Repository.DeepCopyInto(&out.Repository)
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitialRestore.
+func (in *InitialRestore) DeepCopy() *InitialRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(InitialRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitialRestoreRepository) DeepCopyInto(out *InitialRestoreRepository) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitialRestoreRepository.
+func (in *InitialRestoreRepository) DeepCopy() *InitialRestoreRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(InitialRestoreRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitialRestoreStatus) DeepCopyInto(out *InitialRestoreStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitialRestoreStatus.
+func (in *InitialRestoreStatus) DeepCopy() *InitialRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InitialRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMDiagnostics) DeepCopyInto(out *JVMDiagnostics) {
+	*out = *in
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Upload != nil {
+		in, out := &in.Upload, &out.Upload
+		*out = new(JVMDiagnosticsUpload)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMDiagnostics.
+func (in *JVMDiagnostics) DeepCopy() *JVMDiagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMDiagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMDiagnosticsUpload) DeepCopyInto(out *JVMDiagnosticsUpload) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMDiagnosticsUpload.
+func (in *JVMDiagnosticsUpload) DeepCopy() *JVMDiagnosticsUpload {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMDiagnosticsUpload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KibanaSSORealm) DeepCopyInto(out *KibanaSSORealm) {
+	*out = *in
+	out.KibanaRef = in.KibanaRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaSSORealm.
+func (in *KibanaSSORealm) DeepCopy() *KibanaSSORealm {
+	if in == nil {
+		return nil
+	}
+	out := new(KibanaSSORealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NewNode) DeepCopyInto(out *NewNode) {
 	*out = *in
@@ -476,6 +873,36 @@ func (in *NodeSet) DeepCopyInto(out *NodeSet) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EphemeralStorage != nil {
+		in, out := &in.EphemeralStorage, &out.EphemeralStorage
+		*out = new(EphemeralStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = make([]AdditionalVolume, len(*in))
+		copy(*out, *in)
+	}
+	if in.FrozenCache != nil {
+		in, out := &in.FrozenCache, &out.FrozenCache
+		*out = new(FrozenCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JVMDiagnostics != nil {
+		in, out := &in.JVMDiagnostics, &out.JVMDiagnostics
+		*out = new(JVMDiagnostics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ZoneAwareness != nil {
+		in, out := &in.ZoneAwareness, &out.ZoneAwareness
+		*out = new(ZoneAwareness)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(commonv1.PodDisruptionBudgetTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSet.
@@ -488,10 +915,82 @@ func (in *NodeSet) DeepCopy() *NodeSet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCRealm) DeepCopyInto(out *OIDCRealm) {
+	*out = *in
+	in.SSORealm.DeepCopyInto(&out.SSORealm)
+	out.ClientSecret = in.ClientSecret
+	if in.RequestedScopes != nil {
+		in, out := &in.RequestedScopes, &out.RequestedScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCRealm.
+func (in *OIDCRealm) DeepCopy() *OIDCRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreUpgradeVolumeSnapshots) DeepCopyInto(out *PreUpgradeVolumeSnapshots) {
+	*out = *in
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeSets != nil {
+		in, out := &in.NodeSets, &out.NodeSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetainedUpgrades != nil {
+		in, out := &in.RetainedUpgrades, &out.RetainedUpgrades
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreUpgradeVolumeSnapshots.
+func (in *PreUpgradeVolumeSnapshots) DeepCopy() *PreUpgradeVolumeSnapshots {
+	if in == nil {
+		return nil
+	}
+	out := new(PreUpgradeVolumeSnapshots)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RemoteCluster) DeepCopyInto(out *RemoteCluster) {
 	*out = *in
 	out.ElasticsearchRef = in.ElasticsearchRef
+	if in.Seeds != nil {
+		in, out := &in.Seeds, &out.Seeds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.NumSocketConnections != nil {
+		in, out := &in.NumSocketConnections, &out.NumSocketConnections
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SkipUnavailable != nil {
+		in, out := &in.SkipUnavailable, &out.SkipUnavailable
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteCluster.
@@ -504,6 +1003,22 @@ func (in *RemoteCluster) DeepCopy() *RemoteCluster {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterStatus) DeepCopyInto(out *RemoteClusterStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterStatus.
+func (in *RemoteClusterStatus) DeepCopy() *RemoteClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleSource) DeepCopyInto(out *RoleSource) {
 	*out = *in
@@ -520,6 +1035,79 @@ func (in *RoleSource) DeepCopy() *RoleSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLMetadataSource) DeepCopyInto(out *SAMLMetadataSource) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLMetadataSource.
+func (in *SAMLMetadataSource) DeepCopy() *SAMLMetadataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLMetadataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLRealm) DeepCopyInto(out *SAMLRealm) {
+	*out = *in
+	in.SSORealm.DeepCopyInto(&out.SSORealm)
+	in.IdPMetadata.DeepCopyInto(&out.IdPMetadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLRealm.
+func (in *SAMLRealm) DeepCopy() *SAMLRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSORealm) DeepCopyInto(out *SSORealm) {
+	*out = *in
+	if in.Order != nil {
+		in, out := &in.Order, &out.Order
+		*out = new(int32)
+		**out = **in
+	}
+	out.KibanaRef = in.KibanaRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSORealm.
+func (in *SSORealm) DeepCopy() *SSORealm {
+	if in == nil {
+		return nil
+	}
+	out := new(SSORealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyRef) DeepCopyInto(out *SecretKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyRef.
+func (in *SecretKeyRef) DeepCopy() *SecretKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelfSignedTransportCertificates) DeepCopyInto(out *SelfSignedTransportCertificates) {
 	*out = *in
@@ -535,6 +1123,143 @@ func (in *SelfSignedTransportCertificates) DeepCopy() *SelfSignedTransportCertif
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceProfile) DeepCopyInto(out *ServiceProfile) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Service.DeepCopyInto(&out.Service)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceProfile.
+func (in *ServiceProfile) DeepCopy() *ServiceProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotLifecyclePolicy) DeepCopyInto(out *SnapshotLifecyclePolicy) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = (*in).DeepCopy()
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(SnapshotLifecyclePolicyRetention)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotLifecyclePolicy.
+func (in *SnapshotLifecyclePolicy) DeepCopy() *SnapshotLifecyclePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotLifecyclePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotLifecyclePolicyRetention) DeepCopyInto(out *SnapshotLifecyclePolicyRetention) {
+	*out = *in
+	if in.MinCount != nil {
+		in, out := &in.MinCount, &out.MinCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxCount != nil {
+		in, out := &in.MaxCount, &out.MaxCount
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotLifecyclePolicyRetention.
+func (in *SnapshotLifecyclePolicyRetention) DeepCopy() *SnapshotLifecyclePolicyRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotLifecyclePolicyRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotLifecyclePolicyStatus) DeepCopyInto(out *SnapshotLifecyclePolicyStatus) {
+	*out = *in
+	if in.LastSuccessTime != nil {
+		in, out := &in.LastSuccessTime, &out.LastSuccessTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FailingSince != nil {
+		in, out := &in.FailingSince, &out.FailingSince
+		*out = (*in).DeepCopy()
+	}
+	if in.NextExecutionTime != nil {
+		in, out := &in.NextExecutionTime, &out.NextExecutionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotLifecyclePolicyStatus.
+func (in *SnapshotLifecyclePolicyStatus) DeepCopy() *SnapshotLifecyclePolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotLifecyclePolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRepository) DeepCopyInto(out *SnapshotRepository) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = (*in).DeepCopy()
+	}
+	if in.SecureSettings != nil {
+		in, out := &in.SecureSettings, &out.SecureSettings
+		*out = make([]commonv1.SecretSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRepository.
+func (in *SnapshotRepository) DeepCopy() *SnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TransportConfig) DeepCopyInto(out *TransportConfig) {
 	*out = *in
@@ -552,6 +1277,21 @@ func (in *TransportConfig) DeepCopy() *TransportConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransportCertificatesIssuer) DeepCopyInto(out *TransportCertificatesIssuer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransportCertificatesIssuer.
+func (in *TransportCertificatesIssuer) DeepCopy() *TransportCertificatesIssuer {
+	if in == nil {
+		return nil
+	}
+	out := new(TransportCertificatesIssuer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TransportTLSOptions) DeepCopyInto(out *TransportTLSOptions) {
 	*out = *in
@@ -567,6 +1307,21 @@ func (in *TransportTLSOptions) DeepCopyInto(out *TransportTLSOptions) {
 		*out = new(SelfSignedTransportCertificates)
 		**out = **in
 	}
+	if in.CertificatesSecretShards != nil {
+		in, out := &in.CertificatesSecretShards, &out.CertificatesSecretShards
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Issuer != nil {
+		in, out := &in.Issuer, &out.Issuer
+		*out = new(TransportCertificatesIssuer)
+		**out = **in
+	}
+	if in.CertificateRotation != nil {
+		in, out := &in.CertificateRotation, &out.CertificateRotation
+		*out = new(commonv1.CertificateRotation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransportTLSOptions.
@@ -618,6 +1373,26 @@ func (in *UpgradeOperation) DeepCopy() *UpgradeOperation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeVolumeSnapshots) DeepCopyInto(out *UpgradeVolumeSnapshots) {
+	*out = *in
+	if in.VolumeSnapshots != nil {
+		in, out := &in.VolumeSnapshots, &out.VolumeSnapshots
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeVolumeSnapshots.
+func (in *UpgradeVolumeSnapshots) DeepCopy() *UpgradeVolumeSnapshots {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeVolumeSnapshots)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpgradedNode) DeepCopyInto(out *UpgradedNode) {
 	*out = *in
@@ -665,3 +1440,23 @@ func (in *UpscaleOperation) DeepCopy() *UpscaleOperation {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneAwareness) DeepCopyInto(out *ZoneAwareness) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneAwareness.
+func (in *ZoneAwareness) DeepCopy() *ZoneAwareness {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneAwareness)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -33,6 +33,11 @@ var (
 		checkNameLength,
 		checkSupportedVersion,
 		checkAssociation,
+		checkIngress,
+		checkGatewayRoute,
+		checkServiceMesh,
+		checkCertificateRotation,
+		checkHostnames,
 	}
 
 	updateChecks = []func(old, curr *EnterpriseSearch) field.ErrorList{
@@ -123,3 +128,23 @@ func checkNoDowngrade(prev, curr *EnterpriseSearch) field.ErrorList {
 func checkAssociation(ent *EnterpriseSearch) field.ErrorList {
 	return commonv1.CheckAssociationRefs(field.NewPath("spec").Child("elasticsearchRef"), ent.Spec.ElasticsearchRef)
 }
+
+func checkIngress(ent *EnterpriseSearch) field.ErrorList {
+	return commonv1.CheckIngress(field.NewPath("spec").Child("http", "ingress"), ent.Spec.HTTP)
+}
+
+func checkGatewayRoute(ent *EnterpriseSearch) field.ErrorList {
+	return commonv1.CheckGatewayRoute(field.NewPath("spec").Child("http", "gatewayRoute"), ent.Spec.HTTP)
+}
+
+func checkServiceMesh(ent *EnterpriseSearch) field.ErrorList {
+	return commonv1.CheckServiceMesh(field.NewPath("spec").Child("http", "tls"), ent.Spec.HTTP.TLS)
+}
+
+func checkCertificateRotation(ent *EnterpriseSearch) field.ErrorList {
+	return commonv1.CheckCertificateRotation(field.NewPath("spec").Child("http", "tls", "certificateRotation"), ent.Spec.HTTP.TLS.CertificateRotation)
+}
+
+func checkHostnames(ent *EnterpriseSearch) field.ErrorList {
+	return commonv1.CheckHostnames(field.NewPath("spec").Child("http", "hostnames"), ent.Spec.HTTP)
+}
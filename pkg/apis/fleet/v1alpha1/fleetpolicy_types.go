@@ -0,0 +1,156 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// FleetPolicyKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	FleetPolicyKind = "FleetPolicy"
+
+	// DefaultPolicyNamespace is the data stream namespace of the agent policy if not set in its definition.
+	DefaultPolicyNamespace = "default"
+)
+
+func init() {
+	SchemeBuilder.Register(&FleetPolicy{}, &FleetPolicyList{})
+}
+
+// +kubebuilder:object:root=true
+
+// FleetPolicy represents a Fleet agent policy, and the integrations added to it, Elastic Agents in Fleet mode are
+// enrolled in.
+// +kubebuilder:resource:categories=elastic,shortName=fleetpol
+// +kubebuilder:printcolumn:name="Kibana",type="string",JSONPath=".spec.kibanaRef.name"
+// +kubebuilder:printcolumn:name="Policy",type="string",JSONPath=".status.policyID"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type FleetPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FleetPolicySpec   `json:"spec,omitempty"`
+	Status FleetPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FleetPolicyList contains a list of FleetPolicy resources.
+type FleetPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FleetPolicy `json:"items"`
+}
+
+type FleetPolicySpec struct {
+	// KibanaRef is a reference to the Kibana instance, in the same namespace as the FleetPolicy, the policy is
+	// configured on. Kibana must be associated with an Elasticsearch cluster managed by the operator.
+	// It cannot be changed once the FleetPolicy is created.
+	KibanaRef commonv1.LocalObjectSelector `json:"kibanaRef"`
+
+	// PolicyID is the id of the agent policy in Fleet. Defaults to the name of the FleetPolicy.
+	// Agents referencing the same Kibana are enrolled in the policy by setting their policyID to this id.
+	// It cannot be changed once the FleetPolicy is created.
+	// +kubebuilder:validation:Optional
+	PolicyID string `json:"policyID,omitempty"`
+
+	// Policy is the definition of the agent policy, as expected in the body of the Fleet create agent policy API,
+	// for example name, namespace, description or monitoring_enabled. The name defaults to the name of the FleetPolicy
+	// and the namespace to default.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Policy *commonv1.Config `json:"policy,omitempty"`
+
+	// PackagePolicies are the integrations added to the agent policy. Their packages are installed if needed.
+	// +kubebuilder:validation:Optional
+	PackagePolicies []PackagePolicy `json:"packagePolicies,omitempty"`
+}
+
+// PackagePolicy is an integration added to an agent policy.
+type PackagePolicy struct {
+	// Name is the name of the integration, unique among the integrations of the policy.
+	Name string `json:"name"`
+
+	// Package is the package the integration is created from.
+	Package Package `json:"package"`
+
+	// Config is the configuration of the integration, as expected in the body of the Fleet create package policy API
+	// in the simplified format, for example description, vars or inputs indexed by their ids.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Config *commonv1.Config `json:"config,omitempty"`
+}
+
+// Package is a Fleet package from the package registry.
+type Package struct {
+	// Name is the name of the package, for example system or kubernetes.
+	Name string `json:"name"`
+	// Version is the version of the package, for example 1.54.0.
+	Version string `json:"version"`
+}
+
+type FleetPolicyStatus struct {
+	// Phase is the phase of the FleetPolicy.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the policy is not configured yet, or why its configuration failed.
+	Message string `json:"message,omitempty"`
+	// PolicyID is the id of the agent policy in Fleet.
+	PolicyID string `json:"policyID,omitempty"`
+	// PackagePolicyIDs are the ids of the integrations in Fleet, indexed by integration name.
+	PackagePolicyIDs map[string]string `json:"packagePolicyIDs,omitempty"`
+	// Agents are the names of the Agents enrolled in the policy.
+	Agents []string `json:"agents,omitempty"`
+	// LastDriftTime is the last time the policy or its integrations were found modified outside of the operator and
+	// restored.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this FleetPolicy.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// Phase is the phase of a FleetPolicy.
+type Phase = commonv1.APIResourcePhase
+
+const (
+	ReadyPhase           = commonv1.APIResourceReadyPhase
+	ApplyingChangesPhase = commonv1.APIResourceApplyingChangesPhase
+	ErrorPhase           = commonv1.APIResourceErrorPhase
+	InvalidPhase         = commonv1.APIResourceInvalidPhase
+)
+
+// PolicyIDOrDefault returns the id of the agent policy in Fleet.
+func (p *FleetPolicy) PolicyIDOrDefault() string {
+	if p.Spec.PolicyID != "" {
+		return p.Spec.PolicyID
+	}
+	return p.Name
+}
+
+// PackagePolicyID returns the id in Fleet of the integration of the given name.
+func (p *FleetPolicy) PackagePolicyID(name string) string {
+	return p.PolicyIDOrDefault() + "-" + name
+}
+
+// References returns true if the policy is configured on the given Kibana.
+func (p *FleetPolicy) References(kb types.NamespacedName) bool {
+	return p.Spec.KibanaRef.WithDefaultNamespace(p.Namespace).NamespacedName() == kb
+}
+
+// IsMarkedForDeletion returns true if the FleetPolicy resource is going to be deleted.
+func (p *FleetPolicy) IsMarkedForDeletion() bool {
+	return !p.DeletionTimestamp.IsZero()
+}
+
+// IsDegraded returns true when the FleetPolicyStatus is degraded compared to the previous status.
+func (s FleetPolicyStatus) IsDegraded(prev FleetPolicyStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
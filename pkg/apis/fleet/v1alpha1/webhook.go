@@ -0,0 +1,208 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	// fleetPolicyWebhookPath is the HTTP path for the FleetPolicy validating webhook.
+	fleetPolicyWebhookPath = "/validate-fleet-k8s-elastic-co-v1alpha1-fleetpolicies"
+
+	crossNamespaceRefErrMsg       = "Kibana must be in the same namespace as the resource"
+	serviceNameNotSupportedErrMsg = "a custom service is not supported to reach Kibana"
+	invalidIDErrMsg               = "must contain alphanumeric characters, hyphens and underscores"
+	setByOperatorErrMsg           = "set by the operator"
+	kibanaChangeErrMsg            = "the Kibana instance of the policy cannot be changed"
+	policyIDChangeErrMsg          = "the policy id cannot be changed"
+)
+
+var (
+	fleetPolicyGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: FleetPolicyKind}
+	validationLog        = ulog.Log.WithName("fleet-v1alpha1-validation")
+
+	// idRegexp matches the policy and integration names the operator derives Fleet ids from.
+	idRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+
+	// operatorPolicyFields are the fields of the agent policy definition set by the operator.
+	operatorPolicyFields = []string{"id"}
+	// operatorPackagePolicyFields are the fields of the integration definition set by the operator.
+	operatorPackagePolicyFields = []string{"id", "policy_id", "policy_ids", "package"}
+
+	fleetPolicyDefaultChecks = []func(*FleetPolicy) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		validKibanaRef,
+		validPolicyID,
+		validPolicy,
+		validPackagePolicies,
+	}
+
+	fleetPolicyUpdateChecks = []func(old, curr *FleetPolicy) field.ErrorList{
+		checkKibanaRefChange,
+		checkPolicyIDChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-fleet-k8s-elastic-co-v1alpha1-fleetpolicies,mutating=false,failurePolicy=ignore,groups=fleet.k8s.elastic.co,resources=fleetpolicies,verbs=create;update,versions=v1alpha1,name=elastic-fleetpolicy-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &FleetPolicy{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (p *FleetPolicy) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", p.Name)
+	return p.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (p *FleetPolicy) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", p.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (p *FleetPolicy) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", p.Name)
+	oldObj, ok := old.(*FleetPolicy)
+	if !ok {
+		return nil, errors.New("cannot cast old object to FleetPolicy type")
+	}
+	return p.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (p *FleetPolicy) WebhookPath() string {
+	return fleetPolicyWebhookPath
+}
+
+func (p *FleetPolicy) validate(old *FleetPolicy) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range fleetPolicyDefaultChecks {
+		if err := dc(p); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range fleetPolicyUpdateChecks {
+			if err := uc(old, p); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(fleetPolicyGroupKind, p.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkNoUnknownFields(p *FleetPolicy) field.ErrorList {
+	return commonv1.NoUnknownFields(p, p.ObjectMeta)
+}
+
+func checkNameLength(p *FleetPolicy) field.ErrorList {
+	return commonv1.CheckNameLength(p)
+}
+
+// validKibanaRef validates the reference to Kibana, which must be in the namespace of the policy.
+func validKibanaRef(p *FleetPolicy) field.ErrorList {
+	path := field.NewPath("spec").Child("kibanaRef")
+	switch {
+	case p.Spec.KibanaRef.Name == "":
+		return field.ErrorList{field.Required(path.Child("name"), "Kibana name is mandatory")}
+	case p.Spec.KibanaRef.Namespace != "" && p.Spec.KibanaRef.Namespace != p.Namespace:
+		return field.ErrorList{field.Invalid(path.Child("namespace"), p.Spec.KibanaRef.Namespace, crossNamespaceRefErrMsg)}
+	case p.Spec.KibanaRef.ServiceName != "":
+		return field.ErrorList{field.Forbidden(path.Child("serviceName"), serviceNameNotSupportedErrMsg)}
+	}
+	return nil
+}
+
+func validPolicyID(p *FleetPolicy) field.ErrorList {
+	if !idRegexp.MatchString(p.PolicyIDOrDefault()) {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("policyID"), p.PolicyIDOrDefault(), invalidIDErrMsg)}
+	}
+	return nil
+}
+
+// validPolicy checks that the definition of the agent policy does not set the fields managed by the operator, its
+// content is validated by Fleet.
+func validPolicy(p *FleetPolicy) field.ErrorList {
+	if p.Spec.Policy == nil {
+		return nil
+	}
+	return forbiddenFields(field.NewPath("spec").Child("policy"), p.Spec.Policy, operatorPolicyFields)
+}
+
+// validPackagePolicies checks that the integrations have a distinct name and a package.
+func validPackagePolicies(p *FleetPolicy) field.ErrorList {
+	var errs field.ErrorList
+	names := set.Make()
+	for i, packagePolicy := range p.Spec.PackagePolicies {
+		path := field.NewPath("spec").Child("packagePolicies").Index(i)
+		switch {
+		case packagePolicy.Name == "":
+			errs = append(errs, field.Required(path.Child("name"), "integration name is mandatory"))
+		case !idRegexp.MatchString(packagePolicy.Name):
+			errs = append(errs, field.Invalid(path.Child("name"), packagePolicy.Name, invalidIDErrMsg))
+		case names.Has(packagePolicy.Name):
+			errs = append(errs, field.Duplicate(path.Child("name"), packagePolicy.Name))
+		}
+		names.Add(packagePolicy.Name)
+		if packagePolicy.Package.Name == "" {
+			errs = append(errs, field.Required(path.Child("package", "name"), "package name is mandatory"))
+		}
+		if packagePolicy.Package.Version == "" {
+			errs = append(errs, field.Required(path.Child("package", "version"), "package version is mandatory"))
+		}
+		if packagePolicy.Config != nil {
+			errs = append(errs, forbiddenFields(path.Child("config"), packagePolicy.Config, operatorPackagePolicyFields)...)
+		}
+	}
+	return errs
+}
+
+func forbiddenFields(path *field.Path, config *commonv1.Config, fields []string) field.ErrorList {
+	var errs field.ErrorList
+	for _, key := range fields {
+		if _, exists := config.Data[key]; exists {
+			errs = append(errs, field.Forbidden(path.Child(key), setByOperatorErrMsg))
+		}
+	}
+	return errs
+}
+
+func checkKibanaRefChange(old, curr *FleetPolicy) field.ErrorList {
+	if old.Spec.KibanaRef.Name != curr.Spec.KibanaRef.Name {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("kibanaRef"), kibanaChangeErrMsg)}
+	}
+	return nil
+}
+
+func checkPolicyIDChange(old, curr *FleetPolicy) field.ErrorList {
+	if old.PolicyIDOrDefault() != curr.PolicyIDOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("policyID"), policyIDChangeErrMsg)}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	fleetv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/fleet/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkFleetPolicy(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-kibana-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkFleetPolicy(uid)
+				p.Spec.KibanaRef = commonv1.LocalObjectSelector{Namespace: "other", Name: "kb"}
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.kibanaRef.namespace: Invalid value: "other": Kibana must be in the same namespace as the resource`,
+			),
+		},
+		{
+			Name:      "invalid-policy",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkFleetPolicy(uid)
+				p.Spec.PolicyID = "eck/agents"
+				p.Spec.Policy.Data["id"] = "other"
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.policyID: Invalid value: "eck/agents": must contain alphanumeric characters, hyphens and underscores`,
+				`spec.policy.id: Forbidden: set by the operator`,
+			),
+		},
+		{
+			Name:      "invalid-package-policies",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkFleetPolicy(uid)
+				p.Spec.PackagePolicies = append(p.Spec.PackagePolicies,
+					fleetv1alpha1.PackagePolicy{Name: "system", Package: fleetv1alpha1.Package{Name: "system"}},
+					fleetv1alpha1.PackagePolicy{
+						Package: fleetv1alpha1.Package{Name: "kubernetes", Version: "1.62.0"},
+						Config:  &commonv1.Config{Data: map[string]interface{}{"policy_id": "other"}},
+					},
+				)
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.packagePolicies\[1\].name: Duplicate value: "system"`,
+				`spec.packagePolicies\[1\].package.version: Required value: package version is mandatory`,
+				`spec.packagePolicies\[2\].name: Required value: integration name is mandatory`,
+				`spec.packagePolicies\[2\].config.policy_id: Forbidden: set by the operator`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkFleetPolicy(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkFleetPolicy(uid)
+				p.Spec.Policy.Data["description"] = "Agents of the production cluster"
+				p.Spec.PackagePolicies = nil
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-policy-id",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkFleetPolicy(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkFleetPolicy(uid)
+				p.Spec.PolicyID = "other"
+				p.Spec.KibanaRef.Name = "other"
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.kibanaRef: Forbidden: the Kibana instance of the policy cannot be changed`,
+				`spec.policyID: Forbidden: the policy id cannot be changed`,
+			),
+		},
+	}
+
+	validator := &fleetv1alpha1.FleetPolicy{}
+	gvk := metav1.GroupVersionKind{Group: fleetv1alpha1.GroupVersion.Group, Version: fleetv1alpha1.GroupVersion.Version, Kind: fleetv1alpha1.FleetPolicyKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkFleetPolicy(uid string) *fleetv1alpha1.FleetPolicy {
+	return &fleetv1alpha1.FleetPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fleet-policy-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: fleetv1alpha1.FleetPolicySpec{
+			KibanaRef: commonv1.LocalObjectSelector{Name: "kb"},
+			Policy: &commonv1.Config{Data: map[string]interface{}{
+				"name":               "ECK agents",
+				"monitoring_enabled": []interface{}{"logs", "metrics"},
+			}},
+			PackagePolicies: []fleetv1alpha1.PackagePolicy{
+				{Name: "system", Package: fleetv1alpha1.Package{Name: "system", Version: "1.54.0"}},
+			},
+		},
+	}
+}
+
+func serialize(t *testing.T, p *fleetv1alpha1.FleetPolicy) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(p)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -0,0 +1,172 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	Kind = "ILMPolicy"
+)
+
+func init() {
+	SchemeBuilder.Register(&ILMPolicy{}, &ILMPolicyList{})
+}
+
+// +kubebuilder:object:root=true
+
+// ILMPolicy represents an index lifecycle management (ILM) policy configured on Elasticsearch clusters.
+// +kubebuilder:resource:categories=elastic,shortName=esilm
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.readyCount",description="Elasticsearch clusters configured"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type ILMPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ILMPolicySpec   `json:"spec,omitempty"`
+	Status ILMPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ILMPolicyList contains a list of ILMPolicy resources.
+type ILMPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ILMPolicy `json:"items"`
+}
+
+type ILMPolicySpec struct {
+	// ElasticsearchRefs are references to the Elasticsearch clusters the policy is configured on.
+	// The clusters must be in the same namespace as the ILMPolicy.
+	// +kubebuilder:validation:MinItems=1
+	ElasticsearchRefs []commonv1.LocalObjectSelector `json:"elasticsearchRefs"`
+
+	// PolicyName is the name of the policy in Elasticsearch. Defaults to the name of the ILMPolicy.
+	// It cannot be changed once the ILMPolicy is created.
+	// +kubebuilder:validation:Optional
+	PolicyName string `json:"policyName,omitempty"`
+
+	// Policy is the definition of the policy, as expected in the policy field of the Elasticsearch ILM API.
+	// It must contain the phases of the policy.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Policy *commonv1.Config `json:"policy"`
+}
+
+type ILMPolicyStatus struct {
+	// Details holds the status of the policy for each Elasticsearch cluster, indexed by cluster name.
+	Details map[string]ElasticsearchPolicyStatus `json:"details,omitempty"`
+	// Resources is the number of Elasticsearch clusters the policy is configured on.
+	Resources int `json:"resources,omitempty"`
+	// Ready is the number of Elasticsearch clusters on which the policy is successfully configured.
+	Ready int `json:"ready,omitempty"`
+	// ReadyCount is a human representation of the number of clusters on which the policy is successfully configured.
+	ReadyCount string `json:"readyCount,omitempty"`
+	// Phase is the phase of the ILMPolicy.
+	Phase Phase `json:"phase,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this ILMPolicy.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ElasticsearchPolicyStatus models the status of the policy for one Elasticsearch cluster.
+type ElasticsearchPolicyStatus struct {
+	// Phase is the phase of the policy on the Elasticsearch cluster.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the policy is not configured yet, or why its configuration failed.
+	Message string `json:"message,omitempty"`
+	// InUseBy is the number of resources using the policy on the Elasticsearch cluster.
+	InUseBy *PolicyUsage `json:"inUseBy,omitempty"`
+	// LastDriftTime is the last time the policy was found modified outside of the operator and restored.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+}
+
+// PolicyUsage is the number of resources using a policy.
+type PolicyUsage struct {
+	// Indices is the number of indices managed by the policy.
+	Indices int `json:"indices,omitempty"`
+	// DataStreams is the number of data streams managed by the policy.
+	DataStreams int `json:"dataStreams,omitempty"`
+	// ComposableTemplates is the number of index templates configuring the policy.
+	ComposableTemplates int `json:"composableTemplates,omitempty"`
+}
+
+// Phase is the phase of an ILMPolicy, overall or on one Elasticsearch cluster.
+type Phase = commonv1.APIResourcePhase
+
+const (
+	ReadyPhase           = commonv1.APIResourceReadyPhase
+	ApplyingChangesPhase = commonv1.APIResourceApplyingChangesPhase
+	ErrorPhase           = commonv1.APIResourceErrorPhase
+	InvalidPhase         = commonv1.APIResourceInvalidPhase
+)
+
+// PolicyNameOrDefault returns the name of the policy in Elasticsearch.
+func (p *ILMPolicy) PolicyNameOrDefault() string {
+	if p.Spec.PolicyName != "" {
+		return p.Spec.PolicyName
+	}
+	return p.Name
+}
+
+// References returns true if the policy is configured on the given Elasticsearch cluster.
+func (p *ILMPolicy) References(es types.NamespacedName) bool {
+	for _, ref := range p.Spec.ElasticsearchRefs {
+		if ref.WithDefaultNamespace(p.Namespace).NamespacedName() == es {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMarkedForDeletion returns true if the ILMPolicy resource is going to be deleted.
+func (p *ILMPolicy) IsMarkedForDeletion() bool {
+	return !p.DeletionTimestamp.IsZero()
+}
+
+func NewStatus(policy ILMPolicy) ILMPolicyStatus {
+	status := ILMPolicyStatus{
+		Details:            map[string]ElasticsearchPolicyStatus{},
+		Phase:              ReadyPhase,
+		ObservedGeneration: policy.Generation,
+	}
+	status.setReadyCount()
+	return status
+}
+
+func (s *ILMPolicyStatus) setReadyCount() {
+	s.ReadyCount = fmt.Sprintf("%d/%d", s.Ready, s.Resources)
+}
+
+// SetElasticsearchStatus sets the status of the policy for the given Elasticsearch cluster.
+func (s *ILMPolicyStatus) SetElasticsearchStatus(esName string, status ElasticsearchPolicyStatus) {
+	if s.Details == nil {
+		s.Details = map[string]ElasticsearchPolicyStatus{}
+	}
+	s.Details[esName] = status
+	s.Update()
+}
+
+// Update updates the policy status from its clusters statuses.
+func (s *ILMPolicyStatus) Update() {
+	phaseOf := func(status ElasticsearchPolicyStatus) Phase { return status.Phase }
+	s.Resources, s.Ready, s.Phase = commonv1.SummarizePhases(s.Details, phaseOf, s.Phase)
+	s.setReadyCount()
+}
+
+// IsDegraded returns true when the ILMPolicyStatus is degraded compared to the previous status.
+func (s ILMPolicyStatus) IsDegraded(prev ILMPolicyStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
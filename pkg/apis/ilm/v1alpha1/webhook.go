@@ -0,0 +1,188 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	// webhookPath is the HTTP path for the ILMPolicy validating webhook.
+	webhookPath = "/validate-ilm-k8s-elastic-co-v1alpha1-ilmpolicies"
+
+	crossNamespaceRefErrMsg       = "Elasticsearch clusters must be in the same namespace as the resource"
+	minAgeErrMsg                  = "must be a time value, for example 30d or 12h"
+	policyNameChangeErrMsg        = "the policy name cannot be changed"
+	serviceNameNotSupportedErrMsg = "a custom service is not supported to reach Elasticsearch"
+)
+
+var (
+	// timeValueRegex matches the time values accepted by Elasticsearch for the min_age of the phases.
+	timeValueRegex = regexp.MustCompile(`^(-1|0|[0-9]+(d|h|m|s|ms|micros|nanos))$`)
+
+	groupKind     = schema.GroupKind{Group: GroupVersion.Group, Kind: Kind}
+	validationLog = ulog.Log.WithName("ilm-v1alpha1-validation")
+
+	defaultChecks = []func(*ILMPolicy) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		validElasticsearchRefs,
+		validPolicy,
+	}
+
+	updateChecks = []func(old, curr *ILMPolicy) field.ErrorList{
+		checkPolicyNameChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-ilm-k8s-elastic-co-v1alpha1-ilmpolicies,mutating=false,failurePolicy=ignore,groups=ilm.k8s.elastic.co,resources=ilmpolicies,verbs=create;update,versions=v1alpha1,name=elastic-ilmpolicy-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &ILMPolicy{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (p *ILMPolicy) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", p.Name)
+	return p.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (p *ILMPolicy) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", p.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (p *ILMPolicy) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", p.Name)
+	oldObj, ok := old.(*ILMPolicy)
+	if !ok {
+		return nil, errors.New("cannot cast old object to ILMPolicy type")
+	}
+	return p.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (p *ILMPolicy) WebhookPath() string {
+	return webhookPath
+}
+
+func (p *ILMPolicy) validate(old *ILMPolicy) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range defaultChecks {
+		if err := dc(p); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range updateChecks {
+			if err := uc(old, p); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(groupKind, p.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkNoUnknownFields(p *ILMPolicy) field.ErrorList {
+	return commonv1.NoUnknownFields(p, p.ObjectMeta)
+}
+
+func checkNameLength(p *ILMPolicy) field.ErrorList {
+	return commonv1.CheckNameLength(p)
+}
+
+// validElasticsearchRefs validates the references to the Elasticsearch clusters, which must be in the namespace of the policy.
+func validElasticsearchRefs(p *ILMPolicy) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("elasticsearchRefs")
+	if len(p.Spec.ElasticsearchRefs) == 0 {
+		return field.ErrorList{field.Required(path, "at least one Elasticsearch cluster must be referenced")}
+	}
+	names := set.Make()
+	for i, ref := range p.Spec.ElasticsearchRefs {
+		switch {
+		case ref.Name == "":
+			errs = append(errs, field.Required(path.Index(i).Child("name"), "Elasticsearch name is mandatory"))
+		case ref.Namespace != "" && ref.Namespace != p.Namespace:
+			errs = append(errs, field.Invalid(path.Index(i).Child("namespace"), ref.Namespace, crossNamespaceRefErrMsg))
+		case ref.ServiceName != "":
+			errs = append(errs, field.Forbidden(path.Index(i).Child("serviceName"), serviceNameNotSupportedErrMsg))
+		case names.Has(ref.Name):
+			errs = append(errs, field.Duplicate(path.Index(i).Child("name"), ref.Name))
+		}
+		names.Add(ref.Name)
+	}
+	return errs
+}
+
+func validPolicy(p *ILMPolicy) field.ErrorList {
+	path := field.NewPath("spec").Child("policy")
+	if p.Spec.Policy == nil {
+		return field.ErrorList{field.Required(path, "policy is mandatory")}
+	}
+	phases, ok := p.Spec.Policy.Data["phases"].(map[string]interface{})
+	if !ok {
+		return field.ErrorList{field.Required(path.Child("phases"), "the phases of the policy are mandatory")}
+	}
+	return validPhases(path.Child("phases"), phases)
+}
+
+// validPhases validates that the phases are objects with a valid min_age. The names of the phases and their actions
+// depend on the version of Elasticsearch, they are validated by the controller against the referenced clusters.
+func validPhases(path *field.Path, phases map[string]interface{}) field.ErrorList {
+	var errs field.ErrorList
+	for _, name := range sortedKeys(phases) {
+		phase, ok := phases[name].(map[string]interface{})
+		if !ok {
+			errs = append(errs, field.Invalid(path.Child(name), phases[name], "the phase must be an object"))
+			continue
+		}
+		if minAge, exists := phase["min_age"]; exists {
+			if value, ok := minAge.(string); !ok || !timeValueRegex.MatchString(value) {
+				errs = append(errs, field.Invalid(path.Child(name).Child("min_age"), minAge, minAgeErrMsg))
+			}
+		}
+	}
+	return errs
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func checkPolicyNameChange(old, curr *ILMPolicy) field.ErrorList {
+	if old.PolicyNameOrDefault() != curr.PolicyNameOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("policyName"), policyNameChangeErrMsg)}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ilmv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/ilm/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkILMPolicy(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-elasticsearch-refs",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkILMPolicy(uid)
+				p.Spec.ElasticsearchRefs = []commonv1.LocalObjectSelector{{Name: "es"}, {Namespace: "other", Name: "es2"}, {Name: "es"}}
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRefs\[1\].namespace: Invalid value: "other": Elasticsearch clusters must be in the same namespace as the resource`,
+				`spec.elasticsearchRefs\[2\].name: Duplicate value: "es"`,
+			),
+		},
+		{
+			Name:      "no-policy",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkILMPolicy(uid)
+				p.Spec.Policy = nil
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.policy: Required value: policy is mandatory`,
+			),
+		},
+		{
+			Name:      "no-phases",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkILMPolicy(uid)
+				p.Spec.Policy = &commonv1.Config{Data: map[string]interface{}{"_meta": map[string]interface{}{"team": "logs"}}}
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.policy.phases: Required value: the phases of the policy are mandatory`,
+			),
+		},
+		{
+			Name:      "invalid-phases",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkILMPolicy(uid)
+				p.Spec.Policy = &commonv1.Config{Data: map[string]interface{}{
+					"phases": map[string]interface{}{
+						"hot": map[string]interface{}{"actions": map[string]interface{}{
+							"rollover": map[string]interface{}{"max_age": "1d"},
+							"delete":   map[string]interface{}{},
+						}},
+						"warm":     map[string]interface{}{"min_age": "seven days"},
+						"lukewarm": "30d",
+						"delete": map[string]interface{}{"min_age": "30d", "actions": map[string]interface{}{
+							"delete":        map[string]interface{}{},
+							"future_action": map[string]interface{}{},
+						}},
+					},
+				}}
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.policy.phases.lukewarm: Invalid value: "30d": the phase must be an object`,
+				`spec.policy.phases.warm.min_age: Invalid value: "seven days": must be a time value`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkILMPolicy(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkILMPolicy(uid)
+				p.Spec.PolicyName = p.Name
+				p.Spec.ElasticsearchRefs = append(p.Spec.ElasticsearchRefs, commonv1.LocalObjectSelector{Name: "es2"})
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-policy-name",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkILMPolicy(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkILMPolicy(uid)
+				p.Spec.PolicyName = "other-policy"
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.policyName: Forbidden: the policy name cannot be changed`,
+			),
+		},
+	}
+
+	validator := &ilmv1alpha1.ILMPolicy{}
+	gvk := metav1.GroupVersionKind{Group: ilmv1alpha1.GroupVersion.Group, Version: ilmv1alpha1.GroupVersion.Version, Kind: ilmv1alpha1.Kind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkILMPolicy(uid string) *ilmv1alpha1.ILMPolicy {
+	return &ilmv1alpha1.ILMPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ilm-policy-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: ilmv1alpha1.ILMPolicySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			Policy: &commonv1.Config{Data: map[string]interface{}{
+				"phases": map[string]interface{}{
+					"delete": map[string]interface{}{"min_age": "30d", "actions": map[string]interface{}{"delete": map[string]interface{}{}}},
+				},
+			}},
+		},
+	}
+}
+
+func serialize(t *testing.T, policy *ilmv1alpha1.ILMPolicy) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(policy)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -0,0 +1,245 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// ElasticsearchIndexKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	ElasticsearchIndexKind = "ElasticsearchIndex"
+)
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchIndex{}, &ElasticsearchIndexList{})
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchIndex represents an index or a data stream, with its settings, mappings and aliases, configured on an
+// Elasticsearch cluster.
+// +kubebuilder:resource:categories=elastic,shortName=esindex
+// +kubebuilder:printcolumn:name="Elasticsearch",type="string",JSONPath=".spec.elasticsearchRef.name"
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type ElasticsearchIndex struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchIndexSpec   `json:"spec,omitempty"`
+	Status ElasticsearchIndexStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchIndexList contains a list of ElasticsearchIndex resources.
+type ElasticsearchIndexList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchIndex `json:"items"`
+}
+
+// IndexType is the type of index managed by an ElasticsearchIndex.
+type IndexType string
+
+const (
+	// RegularIndexType is a regular index, created with its settings, mappings and aliases.
+	RegularIndexType IndexType = "Index"
+	// DataStreamIndexType is a data stream, created from the index template matching its name.
+	DataStreamIndexType IndexType = "DataStream"
+)
+
+// DeletionPolicy defines what happens to the index in Elasticsearch when its ElasticsearchIndex is deleted.
+type DeletionPolicy string
+
+const (
+	// RetainDeletionPolicy keeps the index and its documents in Elasticsearch.
+	RetainDeletionPolicy DeletionPolicy = "Retain"
+	// DeleteDeletionPolicy deletes the index and its documents from Elasticsearch.
+	DeleteDeletionPolicy DeletionPolicy = "Delete"
+)
+
+type ElasticsearchIndexSpec struct {
+	// ElasticsearchRef is a reference to the Elasticsearch cluster the index is configured on.
+	// The cluster must be in the same namespace as the ElasticsearchIndex. It cannot be changed once the
+	// ElasticsearchIndex is created.
+	ElasticsearchRef commonv1.LocalObjectSelector `json:"elasticsearchRef"`
+
+	// IndexName is the name of the index or data stream in Elasticsearch. Defaults to the name of the ElasticsearchIndex.
+	// It cannot be changed once the ElasticsearchIndex is created.
+	// +kubebuilder:validation:Optional
+	IndexName string `json:"indexName,omitempty"`
+
+	// Type is the type of the index: Index for a regular index or DataStream for a data stream. Defaults to Index.
+	// A data stream is created from the index template matching its name, which must exist.
+	// It cannot be changed once the ElasticsearchIndex is created.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Index;DataStream
+	Type IndexType `json:"type,omitempty"`
+
+	// Settings are the index settings, for example {"number_of_shards": 1, "number_of_replicas": 1}. Static settings
+	// are only applied when the index is created and cannot be changed afterwards. Settings of a data stream are
+	// applied to all its backing indices, and must not include static settings.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Settings *commonv1.Config `json:"settings,omitempty"`
+
+	// Mappings are the index mappings. Fields can be added once the index is created, but the existing fields cannot
+	// be removed or change type.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Mappings *commonv1.Config `json:"mappings,omitempty"`
+
+	// Aliases are the aliases of the index or data stream. Aliases removed from this list are removed from
+	// Elasticsearch, other aliases of the index are left untouched.
+	// +kubebuilder:validation:Optional
+	Aliases []IndexAlias `json:"aliases,omitempty"`
+
+	// DeletionPolicy defines what happens to the index when the ElasticsearchIndex is deleted: Retain keeps the index
+	// and its documents, Delete deletes them. Defaults to Retain.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Retain;Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// IndexAlias is an alias of an index or data stream.
+type IndexAlias struct {
+	// Name is the name of the alias.
+	Name string `json:"name"`
+	// Filter is a query used to limit the documents the alias can access.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Filter *commonv1.Config `json:"filter,omitempty"`
+	// Routing is used to route indexing and search operations through the alias to a specific shard.
+	// Not supported for data streams.
+	// +kubebuilder:validation:Optional
+	Routing string `json:"routing,omitempty"`
+	// IsWriteIndex sets the index or data stream as the write index of the alias.
+	// +kubebuilder:validation:Optional
+	IsWriteIndex *bool `json:"isWriteIndex,omitempty"`
+}
+
+type ElasticsearchIndexStatus struct {
+	// Phase is the phase of the ElasticsearchIndex.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the index is not configured yet, or why its configuration failed.
+	Message string `json:"message,omitempty"`
+	// IndexName is the name of the index or data stream in Elasticsearch.
+	IndexName string `json:"indexName,omitempty"`
+	// Aliases are the aliases configured by the operator, they are removed from Elasticsearch once removed from the
+	// specification.
+	Aliases []string `json:"aliases,omitempty"`
+	// LastDriftTime is the last time the index was found modified outside of the operator and restored.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this ElasticsearchIndex.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// staticSettings are the index settings, without their index. prefix, that can only be set when the index is created
+// or when it is closed. Settings of the same name with a suffix, such as sort.field, are static as well.
+var staticSettings = []string{
+	"number_of_shards",
+	"number_of_routing_shards",
+	"routing_partition_size",
+	"codec",
+	"mode",
+	"soft_deletes",
+	"load_fixed_bitset_filters_eagerly",
+	"shard.check_on_startup",
+	"store",
+	"sort",
+	"analysis",
+	"similarity",
+	"hidden",
+	"time_series",
+	"routing_path",
+}
+
+// IsStaticSetting returns true if the given index setting, with or without its index. prefix and in flat format,
+// cannot be changed once the index is created.
+func IsStaticSetting(name string) bool {
+	name = strings.TrimPrefix(name, "index.")
+	for _, static := range staticSettings {
+		if name == static || strings.HasPrefix(name, static+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// FlatSettings returns the settings of the specification indexed by their dotted key prefixed with index., as returned
+// by Elasticsearch with flat settings, with string values.
+func (i *ElasticsearchIndex) FlatSettings() map[string]string {
+	flattened := map[string]string{}
+	if i.Spec.Settings == nil {
+		return flattened
+	}
+	var flattenInto func(prefix string, settings map[string]interface{})
+	flattenInto = func(prefix string, settings map[string]interface{}) {
+		for k, v := range settings {
+			if nested, ok := v.(map[string]interface{}); ok {
+				flattenInto(prefix+k+".", nested)
+				continue
+			}
+			key := prefix + k
+			if !strings.HasPrefix(key, "index.") {
+				key = "index." + key
+			}
+			flattened[key] = fmt.Sprintf("%v", v)
+		}
+	}
+	flattenInto("", i.Spec.Settings.Data)
+	return flattened
+}
+
+// IndexNameOrDefault returns the name of the index or data stream in Elasticsearch.
+func (i *ElasticsearchIndex) IndexNameOrDefault() string {
+	if i.Spec.IndexName != "" {
+		return i.Spec.IndexName
+	}
+	return i.Name
+}
+
+// TypeOrDefault returns the type of the index.
+func (i *ElasticsearchIndex) TypeOrDefault() IndexType {
+	if i.Spec.Type != "" {
+		return i.Spec.Type
+	}
+	return RegularIndexType
+}
+
+// DeletionPolicyOrDefault returns the deletion policy of the index.
+func (i *ElasticsearchIndex) DeletionPolicyOrDefault() DeletionPolicy {
+	if i.Spec.DeletionPolicy != "" {
+		return i.Spec.DeletionPolicy
+	}
+	return RetainDeletionPolicy
+}
+
+// References returns true if the index is configured on the given Elasticsearch cluster.
+func (i *ElasticsearchIndex) References(es types.NamespacedName) bool {
+	return i.Spec.ElasticsearchRef.WithDefaultNamespace(i.Namespace).NamespacedName() == es
+}
+
+// IsMarkedForDeletion returns true if the ElasticsearchIndex resource is going to be deleted.
+func (i *ElasticsearchIndex) IsMarkedForDeletion() bool {
+	return !i.DeletionTimestamp.IsZero()
+}
+
+// IsDegraded returns true when the ElasticsearchIndexStatus is degraded compared to the previous status.
+func (s ElasticsearchIndexStatus) IsDegraded(prev ElasticsearchIndexStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
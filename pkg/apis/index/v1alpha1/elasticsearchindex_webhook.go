@@ -0,0 +1,291 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	// elasticsearchIndexWebhookPath is the HTTP path for the ElasticsearchIndex validating webhook.
+	elasticsearchIndexWebhookPath = "/validate-index-k8s-elastic-co-v1alpha1-elasticsearchindices"
+
+	// maxIndexNameBytes is the maximum length in bytes of an index name accepted by Elasticsearch.
+	maxIndexNameBytes = 255
+
+	// invalidIndexNameChars are the characters Elasticsearch does not accept in index names.
+	invalidIndexNameChars = `\/*?"<>| ,#:`
+
+	invalidIndexNameErrMsg      = "must be lowercase, at most 255 bytes long, not start with -, _ or +, and not contain any of " + invalidIndexNameChars
+	indexNameChangeErrMsg       = "the index name cannot be changed"
+	indexTypeChangeErrMsg       = "the index type cannot be changed"
+	indexElasticsearchErrMsg    = "the Elasticsearch cluster of the index cannot be changed"
+	staticSettingChangeErrMsg   = "static settings cannot be changed once the index is created"
+	dataStreamStaticSettingsMsg = "static settings of a data stream are set by its index template"
+	dataStreamRoutingErrMsg     = "routing is not supported by data stream aliases"
+	mappingRemovedErrMsg        = "fields cannot be removed from the mappings once the index is created"
+	mappingTypeChangeErrMsg     = "the type of a field cannot be changed once the index is created"
+)
+
+var (
+	elasticsearchIndexGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: ElasticsearchIndexKind}
+
+	elasticsearchIndexDefaultChecks = []func(*ElasticsearchIndex) field.ErrorList{
+		checkIndexNoUnknownFields,
+		checkIndexNameLength,
+		validIndexElasticsearchRef,
+		validIndexName,
+		validIndexSettings,
+		validIndexAliases,
+	}
+
+	elasticsearchIndexUpdateChecks = []func(old, curr *ElasticsearchIndex) field.ErrorList{
+		checkIndexElasticsearchRefChange,
+		checkIndexNameChange,
+		checkIndexTypeChange,
+		checkStaticSettingsChange,
+		checkMappingsChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-index-k8s-elastic-co-v1alpha1-elasticsearchindices,mutating=false,failurePolicy=ignore,groups=index.k8s.elastic.co,resources=elasticsearchindices,verbs=create;update,versions=v1alpha1,name=elastic-elasticsearchindex-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &ElasticsearchIndex{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (i *ElasticsearchIndex) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", i.Name)
+	return i.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (i *ElasticsearchIndex) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", i.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (i *ElasticsearchIndex) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", i.Name)
+	oldObj, ok := old.(*ElasticsearchIndex)
+	if !ok {
+		return nil, errors.New("cannot cast old object to ElasticsearchIndex type")
+	}
+	return i.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (i *ElasticsearchIndex) WebhookPath() string {
+	return elasticsearchIndexWebhookPath
+}
+
+func (i *ElasticsearchIndex) validate(old *ElasticsearchIndex) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range elasticsearchIndexDefaultChecks {
+		if err := dc(i); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range elasticsearchIndexUpdateChecks {
+			if err := uc(old, i); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(elasticsearchIndexGroupKind, i.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkIndexNoUnknownFields(i *ElasticsearchIndex) field.ErrorList {
+	return commonv1.NoUnknownFields(i, i.ObjectMeta)
+}
+
+func checkIndexNameLength(i *ElasticsearchIndex) field.ErrorList {
+	return commonv1.CheckNameLength(i)
+}
+
+// validIndexElasticsearchRef validates the reference to Elasticsearch, which must be in the namespace of the index.
+func validIndexElasticsearchRef(i *ElasticsearchIndex) field.ErrorList {
+	path := field.NewPath("spec").Child("elasticsearchRef")
+	switch {
+	case i.Spec.ElasticsearchRef.Name == "":
+		return field.ErrorList{field.Required(path.Child("name"), "Elasticsearch name is mandatory")}
+	case i.Spec.ElasticsearchRef.Namespace != "" && i.Spec.ElasticsearchRef.Namespace != i.Namespace:
+		return field.ErrorList{field.Invalid(path.Child("namespace"), i.Spec.ElasticsearchRef.Namespace, crossNamespaceRefErrMsg)}
+	case i.Spec.ElasticsearchRef.ServiceName != "":
+		return field.ErrorList{field.Forbidden(path.Child("serviceName"), serviceNameNotSupportedErrMsg)}
+	}
+	return nil
+}
+
+// validIndexName checks that the name of the index, which defaults to the name of the resource, is accepted by
+// Elasticsearch.
+func validIndexName(i *ElasticsearchIndex) field.ErrorList {
+	name := i.IndexNameOrDefault()
+	if len(name) > maxIndexNameBytes ||
+		name == "." || name == ".." ||
+		strings.ToLower(name) != name ||
+		strings.ContainsAny(name, invalidIndexNameChars) ||
+		strings.IndexAny(name, "-_+") == 0 {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("indexName"), name, invalidIndexNameErrMsg)}
+	}
+	return nil
+}
+
+// validIndexSettings checks that the settings of a data stream do not include static settings, which are set by its
+// index template when its backing indices are created.
+func validIndexSettings(i *ElasticsearchIndex) field.ErrorList {
+	if i.TypeOrDefault() != DataStreamIndexType {
+		return nil
+	}
+	var errs field.ErrorList
+	for _, key := range sortedStaticSettings(i.FlatSettings()) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("settings").Key(key), dataStreamStaticSettingsMsg))
+	}
+	return errs
+}
+
+// validIndexAliases checks that aliases have a distinct name, and that data stream aliases do not use routing.
+func validIndexAliases(i *ElasticsearchIndex) field.ErrorList {
+	var errs field.ErrorList
+	names := set.Make()
+	for j, alias := range i.Spec.Aliases {
+		path := field.NewPath("spec").Child("aliases").Index(j)
+		switch {
+		case alias.Name == "":
+			errs = append(errs, field.Required(path.Child("name"), "alias name is mandatory"))
+		case names.Has(alias.Name):
+			errs = append(errs, field.Duplicate(path.Child("name"), alias.Name))
+		case alias.Routing != "" && i.TypeOrDefault() == DataStreamIndexType:
+			errs = append(errs, field.Forbidden(path.Child("routing"), dataStreamRoutingErrMsg))
+		}
+		names.Add(alias.Name)
+	}
+	return errs
+}
+
+func checkIndexElasticsearchRefChange(old, curr *ElasticsearchIndex) field.ErrorList {
+	if old.Spec.ElasticsearchRef.Name != curr.Spec.ElasticsearchRef.Name {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("elasticsearchRef"), indexElasticsearchErrMsg)}
+	}
+	return nil
+}
+
+func checkIndexNameChange(old, curr *ElasticsearchIndex) field.ErrorList {
+	if old.IndexNameOrDefault() != curr.IndexNameOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("indexName"), indexNameChangeErrMsg)}
+	}
+	return nil
+}
+
+func checkIndexTypeChange(old, curr *ElasticsearchIndex) field.ErrorList {
+	if old.TypeOrDefault() != curr.TypeOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("type"), indexTypeChangeErrMsg)}
+	}
+	return nil
+}
+
+// checkStaticSettingsChange prevents changes of the static settings, which Elasticsearch only accepts when the index
+// is created.
+func checkStaticSettingsChange(old, curr *ElasticsearchIndex) field.ErrorList {
+	oldSettings := old.FlatSettings()
+	currSettings := curr.FlatSettings()
+	keys := set.Make(sortedStaticSettings(oldSettings)...)
+	keys.MergeWith(set.Make(sortedStaticSettings(currSettings)...))
+	var errs field.ErrorList
+	for _, key := range keys.AsSortedSlice() {
+		oldValue, inOld := oldSettings[key]
+		currValue, inCurr := currSettings[key]
+		if inOld != inCurr || oldValue != currValue {
+			errs = append(errs, field.Forbidden(field.NewPath("spec").Child("settings").Key(key), staticSettingChangeErrMsg))
+		}
+	}
+	return errs
+}
+
+// checkMappingsChange prevents the removal of fields from the mappings, and changes of their type, which would require
+// to reindex the documents in a new index.
+func checkMappingsChange(old, curr *ElasticsearchIndex) field.ErrorList {
+	if old.Spec.Mappings == nil {
+		return nil
+	}
+	var currMappings map[string]interface{}
+	if curr.Spec.Mappings != nil {
+		currMappings = curr.Spec.Mappings.Data
+	}
+	return checkFieldsChange(field.NewPath("spec").Child("mappings"), old.Spec.Mappings.Data, currMappings)
+}
+
+// checkFieldsChange checks recursively that the fields in the properties of the old mappings are in the properties
+// of the current mappings, with the same type.
+func checkFieldsChange(path *field.Path, old, curr map[string]interface{}) field.ErrorList {
+	oldProperties, _ := old["properties"].(map[string]interface{})
+	currProperties, _ := curr["properties"].(map[string]interface{})
+	names := make([]string, 0, len(oldProperties))
+	for name := range oldProperties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs field.ErrorList
+	for _, name := range names {
+		fieldPath := path.Child("properties").Key(name)
+		oldField, _ := oldProperties[name].(map[string]interface{})
+		currField, exists := currProperties[name].(map[string]interface{})
+		if !exists {
+			errs = append(errs, field.Forbidden(fieldPath, mappingRemovedErrMsg))
+			continue
+		}
+		if oldType, currType := fieldType(oldField), fieldType(currField); oldType != currType {
+			errs = append(errs, field.Invalid(fieldPath.Child("type"), currType, mappingTypeChangeErrMsg))
+			continue
+		}
+		errs = append(errs, checkFieldsChange(fieldPath, oldField, currField)...)
+	}
+	return errs
+}
+
+// fieldType returns the type of a field of the mappings, object fields have no explicit type.
+func fieldType(mapping map[string]interface{}) string {
+	if fieldType, exists := mapping["type"]; exists {
+		return fmt.Sprintf("%v", fieldType)
+	}
+	return "object"
+}
+
+// sortedStaticSettings returns the sorted keys of the static settings in the given flat settings.
+func sortedStaticSettings(settings map[string]string) []string {
+	var keys []string
+	for key := range settings {
+		if IsStaticSetting(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
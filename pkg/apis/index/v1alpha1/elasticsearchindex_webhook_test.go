@@ -0,0 +1,221 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	indexv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/index/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestElasticsearchIndexWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serializeIndex(t, mkElasticsearchIndex(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "create-valid-data-stream",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serializeIndex(t, mkDataStreamIndex(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-elasticsearch-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				index := mkElasticsearchIndex(uid)
+				index.Spec.ElasticsearchRef = commonv1.LocalObjectSelector{Namespace: "other", Name: "es"}
+				return serializeIndex(t, index)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef.namespace: Invalid value: "other": Elasticsearch clusters must be in the same namespace as the resource`,
+			),
+		},
+		{
+			Name:      "invalid-index-name",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				index := mkElasticsearchIndex(uid)
+				index.Spec.IndexName = "_Orders"
+				return serializeIndex(t, index)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.indexName: Invalid value: "_Orders": must be lowercase`,
+			),
+		},
+		{
+			Name:      "invalid-aliases",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				index := mkDataStreamIndex(uid)
+				index.Spec.Aliases = []indexv1alpha1.IndexAlias{{Name: "logs-app"}, {Name: "logs-app"}, {Name: "logs"}, {Name: "logs-other", Routing: "1"}}
+				return serializeIndex(t, index)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.aliases\[1\].name: Duplicate value: "logs-app"`,
+				`spec.aliases\[3\].routing: Forbidden: routing is not supported by data stream aliases`,
+			),
+		},
+		{
+			Name:      "data-stream-static-settings",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				index := mkDataStreamIndex(uid)
+				index.Spec.Settings = &commonv1.Config{Data: map[string]interface{}{"index": map[string]interface{}{"number_of_shards": 2, "number_of_replicas": 0}}}
+				return serializeIndex(t, index)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.settings\[index.number_of_shards\]: Forbidden: static settings of a data stream are set by its index template`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serializeIndex(t, mkElasticsearchIndex(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				index := mkElasticsearchIndex(uid)
+				index.Spec.IndexName = index.Name
+				index.Spec.Settings.Data["number_of_replicas"] = 2
+				properties := index.Spec.Mappings.Data["properties"].(map[string]interface{})
+				properties["customer"].(map[string]interface{})["properties"].(map[string]interface{})["email"] = map[string]interface{}{"type": "keyword"}
+				properties["quantity"] = map[string]interface{}{"type": "integer"}
+				index.Spec.Aliases = nil
+				return serializeIndex(t, index)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-immutable-fields",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serializeIndex(t, mkElasticsearchIndex(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				index := mkElasticsearchIndex(uid)
+				index.Spec.ElasticsearchRef.Name = "other-es"
+				index.Spec.IndexName = "other-orders"
+				index.Spec.Type = indexv1alpha1.DataStreamIndexType
+				return serializeIndex(t, index)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef: Forbidden: the Elasticsearch cluster of the index cannot be changed`,
+				`spec.indexName: Forbidden: the index name cannot be changed`,
+				`spec.type: Forbidden: the index type cannot be changed`,
+			),
+		},
+		{
+			Name:      "update-static-settings",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serializeIndex(t, mkElasticsearchIndex(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				index := mkElasticsearchIndex(uid)
+				index.Spec.Settings.Data["number_of_shards"] = 6
+				index.Spec.Settings.Data["codec"] = "best_compression"
+				return serializeIndex(t, index)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.settings\[index.codec\]: Forbidden: static settings cannot be changed once the index is created`,
+				`spec.settings\[index.number_of_shards\]: Forbidden: static settings cannot be changed once the index is created`,
+			),
+		},
+		{
+			Name:      "update-mappings",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serializeIndex(t, mkElasticsearchIndex(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				index := mkElasticsearchIndex(uid)
+				index.Spec.Mappings = &commonv1.Config{Data: map[string]interface{}{
+					"properties": map[string]interface{}{
+						"price":    map[string]interface{}{"type": "double"},
+						"customer": map[string]interface{}{"properties": map[string]interface{}{}},
+					},
+				}}
+				return serializeIndex(t, index)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.mappings.properties\[customer\].properties\[name\]: Forbidden: fields cannot be removed from the mappings once the index is created`,
+				`spec.mappings.properties\[price\].type: Invalid value: "double": the type of a field cannot be changed once the index is created`,
+			),
+		},
+	}
+
+	validator := &indexv1alpha1.ElasticsearchIndex{}
+	gvk := metav1.GroupVersionKind{Group: indexv1alpha1.GroupVersion.Group, Version: indexv1alpha1.GroupVersion.Version, Kind: indexv1alpha1.ElasticsearchIndexKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkElasticsearchIndex(uid string) *indexv1alpha1.ElasticsearchIndex {
+	return &indexv1alpha1.ElasticsearchIndex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "orders",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: indexv1alpha1.ElasticsearchIndexSpec{
+			ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es"},
+			Settings:         &commonv1.Config{Data: map[string]interface{}{"number_of_shards": 3, "number_of_replicas": 1}},
+			Mappings: &commonv1.Config{Data: map[string]interface{}{
+				"properties": map[string]interface{}{
+					"price":    map[string]interface{}{"type": "float"},
+					"customer": map[string]interface{}{"properties": map[string]interface{}{"name": map[string]interface{}{"type": "text"}}},
+				},
+			}},
+			Aliases: []indexv1alpha1.IndexAlias{{Name: "orders-current"}},
+		},
+	}
+}
+
+func mkDataStreamIndex(uid string) *indexv1alpha1.ElasticsearchIndex {
+	index := mkElasticsearchIndex(uid)
+	index.Spec.IndexName = "logs-app-default"
+	index.Spec.Type = indexv1alpha1.DataStreamIndexType
+	index.Spec.Settings = &commonv1.Config{Data: map[string]interface{}{"number_of_replicas": 0}}
+	index.Spec.Aliases = []indexv1alpha1.IndexAlias{{Name: "logs-app"}}
+	return index
+}
+
+func serializeIndex(t *testing.T, index *indexv1alpha1.ElasticsearchIndex) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(index)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -0,0 +1,211 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// IndexTemplateKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	IndexTemplateKind = "IndexTemplate"
+)
+
+func init() {
+	SchemeBuilder.Register(&IndexTemplate{}, &IndexTemplateList{})
+}
+
+// +kubebuilder:object:root=true
+
+// IndexTemplate represents a composable index template or a component template configured on Elasticsearch clusters.
+// +kubebuilder:resource:categories=elastic,shortName=estemplate
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.readyCount",description="Elasticsearch clusters configured"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type IndexTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IndexTemplateSpec   `json:"spec,omitempty"`
+	Status IndexTemplateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IndexTemplateList contains a list of IndexTemplate resources.
+type IndexTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IndexTemplate `json:"items"`
+}
+
+// TemplateType is the type of template managed by an IndexTemplate.
+type TemplateType string
+
+const (
+	// ComposableTemplateType is a composable index template, applied to the indices and data streams matching its
+	// index patterns.
+	ComposableTemplateType TemplateType = "Composable"
+	// ComponentTemplateType is a component template, a building block of composable index templates.
+	ComponentTemplateType TemplateType = "Component"
+)
+
+type IndexTemplateSpec struct {
+	// ElasticsearchRefs are references to the Elasticsearch clusters the template is configured on.
+	// The clusters must be in the same namespace as the IndexTemplate.
+	// +kubebuilder:validation:Optional
+	ElasticsearchRefs []commonv1.LocalObjectSelector `json:"elasticsearchRefs,omitempty"`
+
+	// ElasticsearchSelector selects the Elasticsearch clusters, in the same namespace as the IndexTemplate, the template
+	// is configured on, in addition to the ones referenced in ElasticsearchRefs.
+	// +kubebuilder:validation:Optional
+	ElasticsearchSelector *metav1.LabelSelector `json:"elasticsearchSelector,omitempty"`
+
+	// Type is the type of the template: Composable for an index template or Component for a component template.
+	// Defaults to Composable. It cannot be changed once the IndexTemplate is created.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Composable;Component
+	Type TemplateType `json:"type,omitempty"`
+
+	// TemplateName is the name of the template in Elasticsearch. Defaults to the name of the IndexTemplate.
+	// It cannot be changed once the IndexTemplate is created.
+	// +kubebuilder:validation:Optional
+	TemplateName string `json:"templateName,omitempty"`
+
+	// Template is the definition of the template, as expected in the body of the Elasticsearch index template or
+	// component template API. An index template must contain its index_patterns, a component template its template.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Template *commonv1.Config `json:"template"`
+}
+
+type IndexTemplateStatus struct {
+	// Details holds the status of the template for each Elasticsearch cluster, indexed by cluster name.
+	Details map[string]ElasticsearchTemplateStatus `json:"details,omitempty"`
+	// Resources is the number of Elasticsearch clusters the template is configured on.
+	Resources int `json:"resources,omitempty"`
+	// Ready is the number of Elasticsearch clusters on which the template is successfully configured.
+	Ready int `json:"ready,omitempty"`
+	// ReadyCount is a human representation of the number of clusters on which the template is successfully configured.
+	ReadyCount string `json:"readyCount,omitempty"`
+	// Phase is the phase of the IndexTemplate.
+	Phase Phase `json:"phase,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this IndexTemplate.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ElasticsearchTemplateStatus models the status of the template for one Elasticsearch cluster.
+type ElasticsearchTemplateStatus struct {
+	// Phase is the phase of the template on the Elasticsearch cluster.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the template is not configured yet, or why its configuration failed.
+	Message string `json:"message,omitempty"`
+	// InUseBy is the number of resources using the template on the Elasticsearch cluster.
+	InUseBy *TemplateUsage `json:"inUseBy,omitempty"`
+	// LastDriftTime is the last time the template was found modified outside of the operator and restored.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+}
+
+// TemplateUsage is the number of resources using a template.
+type TemplateUsage struct {
+	// DataStreams is the number of data streams created from the template, or from the index templates composed of
+	// the component template.
+	DataStreams int `json:"dataStreams,omitempty"`
+	// IndexTemplates is the number of index templates composed of the component template.
+	IndexTemplates int `json:"indexTemplates,omitempty"`
+}
+
+// Phase is the phase of an IndexTemplate, overall or on one Elasticsearch cluster.
+type Phase = commonv1.APIResourcePhase
+
+const (
+	ReadyPhase           = commonv1.APIResourceReadyPhase
+	ApplyingChangesPhase = commonv1.APIResourceApplyingChangesPhase
+	ErrorPhase           = commonv1.APIResourceErrorPhase
+	InvalidPhase         = commonv1.APIResourceInvalidPhase
+)
+
+// TemplateNameOrDefault returns the name of the template in Elasticsearch.
+func (t *IndexTemplate) TemplateNameOrDefault() string {
+	if t.Spec.TemplateName != "" {
+		return t.Spec.TemplateName
+	}
+	return t.Name
+}
+
+// TypeOrDefault returns the type of the template.
+func (t *IndexTemplate) TypeOrDefault() TemplateType {
+	if t.Spec.Type != "" {
+		return t.Spec.Type
+	}
+	return ComposableTemplateType
+}
+
+// Selects returns true if the template is configured on the given Elasticsearch cluster, either because it is
+// referenced or because its labels match the selector.
+func (t *IndexTemplate) Selects(es types.NamespacedName, esLabels map[string]string) bool {
+	for _, ref := range t.Spec.ElasticsearchRefs {
+		if ref.WithDefaultNamespace(t.Namespace).NamespacedName() == es {
+			return true
+		}
+	}
+	if t.Spec.ElasticsearchSelector == nil || es.Namespace != t.Namespace {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(t.Spec.ElasticsearchSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(esLabels))
+}
+
+// IsMarkedForDeletion returns true if the IndexTemplate resource is going to be deleted.
+func (t *IndexTemplate) IsMarkedForDeletion() bool {
+	return !t.DeletionTimestamp.IsZero()
+}
+
+func NewStatus(template IndexTemplate) IndexTemplateStatus {
+	status := IndexTemplateStatus{
+		Details:            map[string]ElasticsearchTemplateStatus{},
+		Phase:              ReadyPhase,
+		ObservedGeneration: template.Generation,
+	}
+	status.setReadyCount()
+	return status
+}
+
+func (s *IndexTemplateStatus) setReadyCount() {
+	s.ReadyCount = fmt.Sprintf("%d/%d", s.Ready, s.Resources)
+}
+
+// SetElasticsearchStatus sets the status of the template for the given Elasticsearch cluster.
+func (s *IndexTemplateStatus) SetElasticsearchStatus(esName string, status ElasticsearchTemplateStatus) {
+	if s.Details == nil {
+		s.Details = map[string]ElasticsearchTemplateStatus{}
+	}
+	s.Details[esName] = status
+	s.Update()
+}
+
+// Update updates the template status from its clusters statuses.
+func (s *IndexTemplateStatus) Update() {
+	phaseOf := func(status ElasticsearchTemplateStatus) Phase { return status.Phase }
+	s.Resources, s.Ready, s.Phase = commonv1.SummarizePhases(s.Details, phaseOf, s.Phase)
+	s.setReadyCount()
+}
+
+// IsDegraded returns true when the IndexTemplateStatus is degraded compared to the previous status.
+func (s IndexTemplateStatus) IsDegraded(prev IndexTemplateStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
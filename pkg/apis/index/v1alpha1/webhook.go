@@ -0,0 +1,188 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	// indexTemplateWebhookPath is the HTTP path for the IndexTemplate validating webhook.
+	indexTemplateWebhookPath = "/validate-index-k8s-elastic-co-v1alpha1-indextemplates"
+
+	crossNamespaceRefErrMsg       = "Elasticsearch clusters must be in the same namespace as the resource"
+	serviceNameNotSupportedErrMsg = "a custom service is not supported to reach Elasticsearch"
+	noElasticsearchErrMsg         = "at least one Elasticsearch cluster must be referenced or selected"
+	templateNameChangeErrMsg      = "the template name cannot be changed"
+	templateTypeChangeErrMsg      = "the template type cannot be changed"
+)
+
+var (
+	indexTemplateGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: IndexTemplateKind}
+	validationLog          = ulog.Log.WithName("index-v1alpha1-validation")
+
+	indexTemplateDefaultChecks = []func(*IndexTemplate) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		validElasticsearchClusters,
+		validTemplate,
+	}
+
+	indexTemplateUpdateChecks = []func(old, curr *IndexTemplate) field.ErrorList{
+		checkTemplateNameChange,
+		checkTemplateTypeChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-index-k8s-elastic-co-v1alpha1-indextemplates,mutating=false,failurePolicy=ignore,groups=index.k8s.elastic.co,resources=indextemplates,verbs=create;update,versions=v1alpha1,name=elastic-indextemplate-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &IndexTemplate{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (t *IndexTemplate) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", t.Name)
+	return t.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (t *IndexTemplate) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", t.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (t *IndexTemplate) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", t.Name)
+	oldObj, ok := old.(*IndexTemplate)
+	if !ok {
+		return nil, errors.New("cannot cast old object to IndexTemplate type")
+	}
+	return t.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (t *IndexTemplate) WebhookPath() string {
+	return indexTemplateWebhookPath
+}
+
+func (t *IndexTemplate) validate(old *IndexTemplate) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range indexTemplateDefaultChecks {
+		if err := dc(t); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range indexTemplateUpdateChecks {
+			if err := uc(old, t); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(indexTemplateGroupKind, t.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkNoUnknownFields(t *IndexTemplate) field.ErrorList {
+	return commonv1.NoUnknownFields(t, t.ObjectMeta)
+}
+
+func checkNameLength(t *IndexTemplate) field.ErrorList {
+	return commonv1.CheckNameLength(t)
+}
+
+// validElasticsearchClusters validates the Elasticsearch clusters referenced in spec.elasticsearchRefs, which must be
+// distinct and in the namespace of the template, and the selector in spec.elasticsearchSelector. At least one of them
+// must be set.
+func validElasticsearchClusters(t *IndexTemplate) field.ErrorList {
+	path := field.NewPath("spec")
+	if len(t.Spec.ElasticsearchRefs) == 0 && t.Spec.ElasticsearchSelector == nil {
+		return field.ErrorList{field.Required(path.Child("elasticsearchRefs"), noElasticsearchErrMsg)}
+	}
+	var errs field.ErrorList
+	names := set.Make()
+	for i, ref := range t.Spec.ElasticsearchRefs {
+		refPath := path.Child("elasticsearchRefs").Index(i)
+		switch {
+		case ref.Name == "":
+			errs = append(errs, field.Required(refPath.Child("name"), "Elasticsearch name is mandatory"))
+		case ref.Namespace != "" && ref.Namespace != t.Namespace:
+			errs = append(errs, field.Invalid(refPath.Child("namespace"), ref.Namespace, crossNamespaceRefErrMsg))
+		case ref.ServiceName != "":
+			errs = append(errs, field.Forbidden(refPath.Child("serviceName"), serviceNameNotSupportedErrMsg))
+		case names.Has(ref.Name):
+			errs = append(errs, field.Duplicate(refPath.Child("name"), ref.Name))
+		}
+		names.Add(ref.Name)
+	}
+	if t.Spec.ElasticsearchSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(t.Spec.ElasticsearchSelector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("elasticsearchSelector"), t.Spec.ElasticsearchSelector, err.Error()))
+		}
+	}
+	return errs
+}
+
+// validTemplate checks the fields required by Elasticsearch for the type of template, its content is validated by Elasticsearch.
+func validTemplate(t *IndexTemplate) field.ErrorList {
+	path := field.NewPath("spec").Child("template")
+	if t.Spec.Template == nil {
+		return field.ErrorList{field.Required(path, "template is mandatory")}
+	}
+	switch t.TypeOrDefault() {
+	case ComposableTemplateType:
+		if patterns, ok := t.Spec.Template.Data["index_patterns"]; !ok || patterns == nil {
+			return field.ErrorList{field.Required(path.Child("index_patterns"), "the index patterns of an index template are mandatory")}
+		}
+		if _, ok := t.Spec.Template.Data["template"].(map[string]interface{}); !ok && t.Spec.Template.Data["template"] != nil {
+			return field.ErrorList{field.Invalid(path.Child("template"), t.Spec.Template.Data["template"], "must be an object")}
+		}
+	case ComponentTemplateType:
+		if _, ok := t.Spec.Template.Data["template"].(map[string]interface{}); !ok {
+			return field.ErrorList{field.Required(path.Child("template"), "the template of a component template is mandatory")}
+		}
+		for _, key := range []string{"index_patterns", "composed_of", "data_stream", "priority"} {
+			if _, exists := t.Spec.Template.Data[key]; exists {
+				return field.ErrorList{field.Forbidden(path.Child(key), "not supported in a component template")}
+			}
+		}
+	}
+	return nil
+}
+
+func checkTemplateNameChange(old, curr *IndexTemplate) field.ErrorList {
+	if old.TemplateNameOrDefault() != curr.TemplateNameOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("templateName"), templateNameChangeErrMsg)}
+	}
+	return nil
+}
+
+func checkTemplateTypeChange(old, curr *IndexTemplate) field.ErrorList {
+	if old.TypeOrDefault() != curr.TypeOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("type"), templateTypeChangeErrMsg)}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	indexv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/index/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkIndexTemplate(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "create-valid-component-template",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkComponentTemplate(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "no-elasticsearch",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				it := mkIndexTemplate(uid)
+				it.Spec.ElasticsearchRefs = nil
+				return serialize(t, it)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRefs: Required value: at least one Elasticsearch cluster must be referenced or selected`,
+			),
+		},
+		{
+			Name:      "invalid-elasticsearch-refs",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				it := mkIndexTemplate(uid)
+				it.Spec.ElasticsearchRefs = []commonv1.LocalObjectSelector{{Name: "es"}, {Namespace: "other", Name: "es2"}, {Name: "es"}}
+				return serialize(t, it)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRefs\[1\].namespace: Invalid value: "other": Elasticsearch clusters must be in the same namespace as the resource`,
+				`spec.elasticsearchRefs\[2\].name: Duplicate value: "es"`,
+			),
+		},
+		{
+			Name:      "selector-only",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				it := mkIndexTemplate(uid)
+				it.Spec.ElasticsearchRefs = nil
+				it.Spec.ElasticsearchSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+				return serialize(t, it)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "no-template",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				it := mkIndexTemplate(uid)
+				it.Spec.Template = nil
+				return serialize(t, it)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.template: Required value: template is mandatory`,
+			),
+		},
+		{
+			Name:      "no-index-patterns",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				it := mkIndexTemplate(uid)
+				delete(it.Spec.Template.Data, "index_patterns")
+				return serialize(t, it)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.template.index_patterns: Required value: the index patterns of an index template are mandatory`,
+			),
+		},
+		{
+			Name:      "component-template-with-index-patterns",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				it := mkComponentTemplate(uid)
+				it.Spec.Template.Data["index_patterns"] = []interface{}{"logs-*"}
+				return serialize(t, it)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.template.index_patterns: Forbidden: not supported in a component template`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkIndexTemplate(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				it := mkIndexTemplate(uid)
+				it.Spec.TemplateName = it.Name
+				it.Spec.Type = indexv1alpha1.ComposableTemplateType
+				it.Spec.Template.Data["priority"] = 200
+				return serialize(t, it)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-template-name-and-type",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkIndexTemplate(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				it := mkComponentTemplate(uid)
+				it.Spec.TemplateName = "other-template"
+				return serialize(t, it)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.templateName: Forbidden: the template name cannot be changed`,
+				`spec.type: Forbidden: the template type cannot be changed`,
+			),
+		},
+	}
+
+	validator := &indexv1alpha1.IndexTemplate{}
+	gvk := metav1.GroupVersionKind{Group: indexv1alpha1.GroupVersion.Group, Version: indexv1alpha1.GroupVersion.Version, Kind: indexv1alpha1.IndexTemplateKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkIndexTemplate(uid string) *indexv1alpha1.IndexTemplate {
+	return &indexv1alpha1.IndexTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "index-template-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: indexv1alpha1.IndexTemplateSpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			Template: &commonv1.Config{Data: map[string]interface{}{
+				"index_patterns": []interface{}{"logs-app-*"},
+				"data_stream":    map[string]interface{}{},
+				"composed_of":    []interface{}{"logs-mappings"},
+				"priority":       500,
+			}},
+		},
+	}
+}
+
+func mkComponentTemplate(uid string) *indexv1alpha1.IndexTemplate {
+	it := mkIndexTemplate(uid)
+	it.Spec.Type = indexv1alpha1.ComponentTemplateType
+	it.Spec.Template = &commonv1.Config{Data: map[string]interface{}{
+		"template": map[string]interface{}{
+			"settings": map[string]interface{}{"number_of_shards": 1},
+		},
+	}}
+	return it
+}
+
+func serialize(t *testing.T, template *indexv1alpha1.IndexTemplate) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(template)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -0,0 +1,207 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// IngestPipelineKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	IngestPipelineKind = "IngestPipeline"
+
+	// DefaultPipelineKey is the default key of the ConfigMap holding the definition of a pipeline.
+	DefaultPipelineKey = "pipeline.json"
+)
+
+func init() {
+	SchemeBuilder.Register(&IngestPipeline{}, &IngestPipelineList{})
+}
+
+// +kubebuilder:object:root=true
+
+// IngestPipeline represents an ingest pipeline configured on Elasticsearch clusters.
+// +kubebuilder:resource:categories=elastic,shortName=espipeline
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.readyCount",description="Elasticsearch clusters configured"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type IngestPipeline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IngestPipelineSpec   `json:"spec,omitempty"`
+	Status IngestPipelineStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IngestPipelineList contains a list of IngestPipeline resources.
+type IngestPipelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IngestPipeline `json:"items"`
+}
+
+type IngestPipelineSpec struct {
+	// ElasticsearchRefs are references to the Elasticsearch clusters the pipeline is configured on.
+	// The clusters must be in the same namespace as the IngestPipeline.
+	// +kubebuilder:validation:Optional
+	ElasticsearchRefs []commonv1.LocalObjectSelector `json:"elasticsearchRefs,omitempty"`
+
+	// ElasticsearchSelector selects the Elasticsearch clusters, in the same namespace as the IngestPipeline, the
+	// pipeline is configured on, in addition to the ones referenced in ElasticsearchRefs.
+	// +kubebuilder:validation:Optional
+	ElasticsearchSelector *metav1.LabelSelector `json:"elasticsearchSelector,omitempty"`
+
+	// PipelineName is the name of the pipeline in Elasticsearch. Defaults to the name of the IngestPipeline.
+	// It cannot be changed once the IngestPipeline is created.
+	// +kubebuilder:validation:Optional
+	PipelineName string `json:"pipelineName,omitempty"`
+
+	// Pipeline is the definition of the pipeline, as expected in the body of the Elasticsearch ingest pipeline API.
+	// It must contain the processors of the pipeline. At most one of [`Pipeline`, `PipelineRef`] can be specified.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Pipeline *commonv1.Config `json:"pipeline,omitempty"`
+
+	// PipelineRef is a reference to a ConfigMap, in the same namespace, holding the definition of the pipeline in JSON
+	// or YAML. At most one of [`Pipeline`, `PipelineRef`] can be specified.
+	// +kubebuilder:validation:Optional
+	PipelineRef *PipelineConfigMapRef `json:"pipelineRef,omitempty"`
+
+	// SimulateDocuments are sample documents run through the pipeline with the Elasticsearch simulate pipeline API
+	// before the pipeline is configured. The pipeline is not configured if one of them fails to be processed.
+	// Each document is the source of a document, for example {"message": "GET /index.html 200"}.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	SimulateDocuments []commonv1.Config `json:"simulateDocuments,omitempty"`
+}
+
+// PipelineConfigMapRef is a reference to the key of a ConfigMap holding the definition of a pipeline.
+type PipelineConfigMapRef struct {
+	// ConfigMapName is the name of the ConfigMap.
+	ConfigMapName string `json:"configMapName"`
+	// Key is the key of the ConfigMap holding the definition of the pipeline. Defaults to "pipeline.json".
+	// +kubebuilder:validation:Optional
+	Key string `json:"key,omitempty"`
+}
+
+// KeyOrDefault returns the key of the ConfigMap holding the definition of the pipeline.
+func (r PipelineConfigMapRef) KeyOrDefault() string {
+	if r.Key != "" {
+		return r.Key
+	}
+	return DefaultPipelineKey
+}
+
+type IngestPipelineStatus struct {
+	// Details holds the status of the pipeline for each Elasticsearch cluster, indexed by cluster name.
+	Details map[string]ElasticsearchPipelineStatus `json:"details,omitempty"`
+	// Resources is the number of Elasticsearch clusters the pipeline is configured on.
+	Resources int `json:"resources,omitempty"`
+	// Ready is the number of Elasticsearch clusters on which the pipeline is successfully configured.
+	Ready int `json:"ready,omitempty"`
+	// ReadyCount is a human representation of the number of clusters on which the pipeline is successfully configured.
+	ReadyCount string `json:"readyCount,omitempty"`
+	// Phase is the phase of the IngestPipeline.
+	Phase Phase `json:"phase,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this IngestPipeline.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ElasticsearchPipelineStatus models the status of the pipeline for one Elasticsearch cluster.
+type ElasticsearchPipelineStatus struct {
+	// Phase is the phase of the pipeline on the Elasticsearch cluster.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the pipeline is not configured yet, or why its configuration or its simulation failed.
+	Message string `json:"message,omitempty"`
+	// LastDriftTime is the last time the pipeline was found modified outside of the operator and restored.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+}
+
+// Phase is the phase of an IngestPipeline, overall or on one Elasticsearch cluster.
+type Phase = commonv1.APIResourcePhase
+
+const (
+	ReadyPhase           = commonv1.APIResourceReadyPhase
+	ApplyingChangesPhase = commonv1.APIResourceApplyingChangesPhase
+	ErrorPhase           = commonv1.APIResourceErrorPhase
+	InvalidPhase         = commonv1.APIResourceInvalidPhase
+)
+
+// PipelineNameOrDefault returns the name of the pipeline in Elasticsearch.
+func (p *IngestPipeline) PipelineNameOrDefault() string {
+	if p.Spec.PipelineName != "" {
+		return p.Spec.PipelineName
+	}
+	return p.Name
+}
+
+// Selects returns true if the pipeline is configured on the given Elasticsearch cluster, either because it is
+// referenced or because its labels match the selector.
+func (p *IngestPipeline) Selects(es types.NamespacedName, esLabels map[string]string) bool {
+	for _, ref := range p.Spec.ElasticsearchRefs {
+		if ref.WithDefaultNamespace(p.Namespace).NamespacedName() == es {
+			return true
+		}
+	}
+	if p.Spec.ElasticsearchSelector == nil || es.Namespace != p.Namespace {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(p.Spec.ElasticsearchSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(esLabels))
+}
+
+// IsMarkedForDeletion returns true if the IngestPipeline resource is going to be deleted.
+func (p *IngestPipeline) IsMarkedForDeletion() bool {
+	return !p.DeletionTimestamp.IsZero()
+}
+
+func NewStatus(pipeline IngestPipeline) IngestPipelineStatus {
+	status := IngestPipelineStatus{
+		Details:            map[string]ElasticsearchPipelineStatus{},
+		Phase:              ReadyPhase,
+		ObservedGeneration: pipeline.Generation,
+	}
+	status.setReadyCount()
+	return status
+}
+
+func (s *IngestPipelineStatus) setReadyCount() {
+	s.ReadyCount = fmt.Sprintf("%d/%d", s.Ready, s.Resources)
+}
+
+// SetElasticsearchStatus sets the status of the pipeline for the given Elasticsearch cluster.
+func (s *IngestPipelineStatus) SetElasticsearchStatus(esName string, status ElasticsearchPipelineStatus) {
+	if s.Details == nil {
+		s.Details = map[string]ElasticsearchPipelineStatus{}
+	}
+	s.Details[esName] = status
+	s.Update()
+}
+
+// Update updates the pipeline status from its clusters statuses.
+func (s *IngestPipelineStatus) Update() {
+	phaseOf := func(status ElasticsearchPipelineStatus) Phase { return status.Phase }
+	s.Resources, s.Ready, s.Phase = commonv1.SummarizePhases(s.Details, phaseOf, s.Phase)
+	s.setReadyCount()
+}
+
+// IsDegraded returns true when the IngestPipelineStatus is degraded compared to the previous status.
+func (s IngestPipelineStatus) IsDegraded(prev IngestPipelineStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
@@ -0,0 +1,179 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	// ingestPipelineWebhookPath is the HTTP path for the IngestPipeline validating webhook.
+	ingestPipelineWebhookPath = "/validate-ingest-k8s-elastic-co-v1alpha1-ingestpipelines"
+
+	crossNamespaceRefErrMsg       = "Elasticsearch clusters must be in the same namespace as the resource"
+	serviceNameNotSupportedErrMsg = "a custom service is not supported to reach Elasticsearch"
+	noElasticsearchErrMsg         = "at least one Elasticsearch cluster must be referenced or selected"
+	pipelineNameChangeErrMsg      = "the pipeline name cannot be changed"
+	pipelineRequiredErrMsg        = "exactly one of pipeline or pipelineRef must be set"
+)
+
+var (
+	ingestPipelineGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: IngestPipelineKind}
+	validationLog           = ulog.Log.WithName("ingest-v1alpha1-validation")
+
+	ingestPipelineDefaultChecks = []func(*IngestPipeline) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		validElasticsearchClusters,
+		validPipeline,
+	}
+
+	ingestPipelineUpdateChecks = []func(old, curr *IngestPipeline) field.ErrorList{
+		checkPipelineNameChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-ingest-k8s-elastic-co-v1alpha1-ingestpipelines,mutating=false,failurePolicy=ignore,groups=ingest.k8s.elastic.co,resources=ingestpipelines,verbs=create;update,versions=v1alpha1,name=elastic-ingestpipeline-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &IngestPipeline{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (p *IngestPipeline) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", p.Name)
+	return p.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (p *IngestPipeline) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", p.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (p *IngestPipeline) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", p.Name)
+	oldObj, ok := old.(*IngestPipeline)
+	if !ok {
+		return nil, errors.New("cannot cast old object to IngestPipeline type")
+	}
+	return p.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (p *IngestPipeline) WebhookPath() string {
+	return ingestPipelineWebhookPath
+}
+
+func (p *IngestPipeline) validate(old *IngestPipeline) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range ingestPipelineDefaultChecks {
+		if err := dc(p); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range ingestPipelineUpdateChecks {
+			if err := uc(old, p); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(ingestPipelineGroupKind, p.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkNoUnknownFields(p *IngestPipeline) field.ErrorList {
+	return commonv1.NoUnknownFields(p, p.ObjectMeta)
+}
+
+func checkNameLength(p *IngestPipeline) field.ErrorList {
+	return commonv1.CheckNameLength(p)
+}
+
+// validElasticsearchClusters validates the Elasticsearch clusters referenced in spec.elasticsearchRefs, which must be
+// distinct and in the namespace of the pipeline, and the selector in spec.elasticsearchSelector. At least one of them
+// must be set.
+func validElasticsearchClusters(p *IngestPipeline) field.ErrorList {
+	path := field.NewPath("spec")
+	if len(p.Spec.ElasticsearchRefs) == 0 && p.Spec.ElasticsearchSelector == nil {
+		return field.ErrorList{field.Required(path.Child("elasticsearchRefs"), noElasticsearchErrMsg)}
+	}
+	var errs field.ErrorList
+	names := set.Make()
+	for i, ref := range p.Spec.ElasticsearchRefs {
+		refPath := path.Child("elasticsearchRefs").Index(i)
+		switch {
+		case ref.Name == "":
+			errs = append(errs, field.Required(refPath.Child("name"), "Elasticsearch name is mandatory"))
+		case ref.Namespace != "" && ref.Namespace != p.Namespace:
+			errs = append(errs, field.Invalid(refPath.Child("namespace"), ref.Namespace, crossNamespaceRefErrMsg))
+		case ref.ServiceName != "":
+			errs = append(errs, field.Forbidden(refPath.Child("serviceName"), serviceNameNotSupportedErrMsg))
+		case names.Has(ref.Name):
+			errs = append(errs, field.Duplicate(refPath.Child("name"), ref.Name))
+		}
+		names.Add(ref.Name)
+	}
+	if p.Spec.ElasticsearchSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(p.Spec.ElasticsearchSelector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("elasticsearchSelector"), p.Spec.ElasticsearchSelector, err.Error()))
+		}
+	}
+	return errs
+}
+
+// validPipeline checks that the pipeline is defined either inline with its processors, or in a ConfigMap whose content
+// is validated by the controller.
+func validPipeline(p *IngestPipeline) field.ErrorList {
+	path := field.NewPath("spec")
+	switch {
+	case p.Spec.Pipeline == nil && p.Spec.PipelineRef == nil:
+		return field.ErrorList{field.Required(path.Child("pipeline"), pipelineRequiredErrMsg)}
+	case p.Spec.Pipeline != nil && p.Spec.PipelineRef != nil:
+		return field.ErrorList{field.Forbidden(path.Child("pipelineRef"), pipelineRequiredErrMsg)}
+	case p.Spec.PipelineRef != nil && p.Spec.PipelineRef.ConfigMapName == "":
+		return field.ErrorList{field.Required(path.Child("pipelineRef").Child("configMapName"), "ConfigMap name is mandatory")}
+	case p.Spec.Pipeline != nil:
+		return ValidatePipelineDefinition(path.Child("pipeline"), p.Spec.Pipeline.Data)
+	}
+	return nil
+}
+
+// ValidatePipelineDefinition checks that the given pipeline definition contains processors, their content is validated
+// by Elasticsearch.
+func ValidatePipelineDefinition(path *field.Path, pipeline map[string]interface{}) field.ErrorList {
+	if processors, ok := pipeline["processors"].([]interface{}); !ok || len(processors) == 0 {
+		return field.ErrorList{field.Required(path.Child("processors"), "the processors of the pipeline are mandatory")}
+	}
+	return nil
+}
+
+func checkPipelineNameChange(old, curr *IngestPipeline) field.ErrorList {
+	if old.PipelineNameOrDefault() != curr.PipelineNameOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("pipelineName"), pipelineNameChangeErrMsg)}
+	}
+	return nil
+}
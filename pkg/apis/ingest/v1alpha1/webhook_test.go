@@ -0,0 +1,175 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ingestv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/ingest/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkIngestPipeline(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "create-valid-configmap",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkIngestPipeline(uid)
+				p.Spec.Pipeline = nil
+				p.Spec.PipelineRef = &ingestv1alpha1.PipelineConfigMapRef{ConfigMapName: "pipelines"}
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-elasticsearch-refs",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkIngestPipeline(uid)
+				p.Spec.ElasticsearchRefs = []commonv1.LocalObjectSelector{{Name: "es"}, {Namespace: "other", Name: "es2"}, {Name: "es"}}
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRefs\[1\].namespace: Invalid value: "other": Elasticsearch clusters must be in the same namespace as the resource`,
+				`spec.elasticsearchRefs\[2\].name: Duplicate value: "es"`,
+			),
+		},
+		{
+			Name:      "no-elasticsearch",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkIngestPipeline(uid)
+				p.Spec.ElasticsearchRefs = nil
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRefs: Required value: at least one Elasticsearch cluster must be referenced or selected`,
+			),
+		},
+		{
+			Name:      "no-pipeline",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkIngestPipeline(uid)
+				p.Spec.Pipeline = nil
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.pipeline: Required value: exactly one of pipeline or pipelineRef must be set`,
+			),
+		},
+		{
+			Name:      "pipeline-and-pipeline-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkIngestPipeline(uid)
+				p.Spec.PipelineRef = &ingestv1alpha1.PipelineConfigMapRef{ConfigMapName: "pipelines"}
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.pipelineRef: Forbidden: exactly one of pipeline or pipelineRef must be set`,
+			),
+		},
+		{
+			Name:      "no-processors",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkIngestPipeline(uid)
+				p.Spec.Pipeline = &commonv1.Config{Data: map[string]interface{}{"description": "no processors"}}
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.pipeline.processors: Required value: the processors of the pipeline are mandatory`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkIngestPipeline(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkIngestPipeline(uid)
+				p.Spec.PipelineName = p.Name
+				p.Spec.ElasticsearchSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-pipeline-name",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkIngestPipeline(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkIngestPipeline(uid)
+				p.Spec.PipelineName = "other-pipeline"
+				return serialize(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.pipelineName: Forbidden: the pipeline name cannot be changed`,
+			),
+		},
+	}
+
+	validator := &ingestv1alpha1.IngestPipeline{}
+	gvk := metav1.GroupVersionKind{Group: ingestv1alpha1.GroupVersion.Group, Version: ingestv1alpha1.GroupVersion.Version, Kind: ingestv1alpha1.IngestPipelineKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkIngestPipeline(uid string) *ingestv1alpha1.IngestPipeline {
+	return &ingestv1alpha1.IngestPipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingest-pipeline-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: ingestv1alpha1.IngestPipelineSpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			Pipeline: &commonv1.Config{Data: map[string]interface{}{
+				"processors": []interface{}{
+					map[string]interface{}{"set": map[string]interface{}{"field": "team", "value": "payments"}},
+				},
+			}},
+		},
+	}
+}
+
+func serialize(t *testing.T, pipeline *ingestv1alpha1.IngestPipeline) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(pipeline)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -109,6 +109,12 @@ type KibanaSpec struct {
 	// Elasticsearch monitoring clusters running in the same Kubernetes cluster.
 	// +kubebuilder:validation:Optional
 	Monitoring commonv1.Monitoring `json:"monitoring,omitempty"`
+
+	// MaintenanceWindows restrict the updates of the Pod template of the Deployment, which replace the Kibana Pods, to
+	// the given periods of time. The other changes are still applied immediately.
+	// Pod template updates are applied at any time if no window is set.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []commonv1.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // KibanaStatus defines the observed state of Kibana
@@ -133,6 +139,9 @@ type KibanaStatus struct {
 	// If the generation observed in status diverges from the generation in metadata, the Kibana
 	// controller has not yet processed the changes contained in the Kibana specification.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// PendingChange describes the update of the Kibana Pods waiting for the next maintenance window, if any.
+	PendingChange string `json:"pendingChange,omitempty"`
 }
 
 // IsMarkedForDeletion returns true if the Kibana is going to be deleted
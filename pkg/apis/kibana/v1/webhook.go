@@ -15,6 +15,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/maintenance"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/monitoring"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
@@ -36,6 +37,13 @@ var (
 		checkSupportedVersion,
 		checkMonitoring,
 		checkAssociations,
+		checkIngress,
+		checkGatewayRoute,
+		checkServiceMesh,
+		checkCertificateRotation,
+		checkHostnames,
+		checkSecureSettings,
+		checkMaintenanceWindows,
 	}
 
 	updateChecks = []func(old, curr *Kibana) field.ErrorList{
@@ -130,6 +138,10 @@ func checkMonitoring(k *Kibana) field.ErrorList {
 		errs = append(errs, field.Invalid(field.NewPath("spec").Child("elasticsearchRef"), k.Spec.ElasticsearchRef,
 			validations.InvalidKibanaElasticsearchRefForStackMonitoringMsg))
 	}
+	if k.Spec.Monitoring.Prometheus != nil && k.Spec.Monitoring.Prometheus.Exporter != nil {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("monitoring").Child("prometheus").Child("exporter"),
+			"the Prometheus exporter sidecar is only supported for Elasticsearch"))
+	}
 	return errs
 }
 
@@ -141,3 +153,31 @@ func checkAssociations(k *Kibana) field.ErrorList {
 	err4 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("enterpriseSearchRef"), k.Spec.EnterpriseSearchRef)
 	return append(err1, append(err2, append(err3, err4...)...)...)
 }
+
+func checkIngress(k *Kibana) field.ErrorList {
+	return commonv1.CheckIngress(field.NewPath("spec").Child("http", "ingress"), k.Spec.HTTP)
+}
+
+func checkGatewayRoute(k *Kibana) field.ErrorList {
+	return commonv1.CheckGatewayRoute(field.NewPath("spec").Child("http", "gatewayRoute"), k.Spec.HTTP)
+}
+
+func checkServiceMesh(k *Kibana) field.ErrorList {
+	return commonv1.CheckServiceMesh(field.NewPath("spec").Child("http", "tls"), k.Spec.HTTP.TLS)
+}
+
+func checkCertificateRotation(k *Kibana) field.ErrorList {
+	return commonv1.CheckCertificateRotation(field.NewPath("spec").Child("http", "tls", "certificateRotation"), k.Spec.HTTP.TLS.CertificateRotation)
+}
+
+func checkHostnames(k *Kibana) field.ErrorList {
+	return commonv1.CheckHostnames(field.NewPath("spec").Child("http", "hostnames"), k.Spec.HTTP)
+}
+
+func checkSecureSettings(k *Kibana) field.ErrorList {
+	return commonv1.CheckSecretSources(field.NewPath("spec").Child("secureSettings"), k.Spec.SecureSettings, k.Namespace, true)
+}
+
+func checkMaintenanceWindows(k *Kibana) field.ErrorList {
+	return maintenance.Validate(field.NewPath("spec").Child("maintenanceWindows"), k.Spec.MaintenanceWindows)
+}
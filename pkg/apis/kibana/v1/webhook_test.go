@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
@@ -21,6 +22,8 @@ import (
 )
 
 func TestWebhook(t *testing.T) {
+	require.NoError(t, commonv1.SetExternalSecretPathPrefixes(map[commonv1.ExternalSecretProvider]string{commonv1.VaultSecretProvider: "secret/data/{namespace}/"}))
+	t.Cleanup(func() { require.NoError(t, commonv1.SetExternalSecretPathPrefixes(nil)) })
 	testCases := []test.ValidationWebhookTestCase{
 		{
 			Name:      "create-valid",
@@ -318,6 +321,81 @@ func TestWebhook(t *testing.T) {
 				`spec.monitoring.logs: Forbidden: Invalid association reference: serviceName or namespace can only be used in combination with name, not with secretName`,
 			),
 		},
+		{
+			Name:      "external-secure-settings",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				kb := mkKibana(uid)
+				kb.Namespace = "ns"
+				kb.Spec.SecureSettings = []commonv1.SecretSource{
+					{SecretName: "kb-secure-settings"},
+					{External: &commonv1.ExternalSecretSource{Provider: commonv1.VaultSecretProvider, Path: "secret/data/ns/kibana"}},
+				}
+				return serialize(t, kb)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "external-secure-settings-of-another-namespace",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				kb := mkKibana(uid)
+				kb.Namespace = "ns"
+				kb.Spec.SecureSettings = []commonv1.SecretSource{
+					{External: &commonv1.ExternalSecretSource{Provider: commonv1.VaultSecretProvider, Path: "secret/data/other/kibana"}},
+				}
+				return serialize(t, kb)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.secureSettings\[0\].external.path: Forbidden: path secret/data/other/kibana is not allowed in namespace ns`,
+			),
+		},
+		{
+			Name:      "invalid-secure-settings",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				kb := mkKibana(uid)
+				kb.Spec.SecureSettings = []commonv1.SecretSource{{
+					SecretName: "kb-secure-settings",
+					External:   &commonv1.ExternalSecretSource{Provider: commonv1.VaultSecretProvider, Path: "secret/data/kibana"},
+				}}
+				return serialize(t, kb)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.secureSettings\[0\]: Forbidden: secretName and external cannot be both set`,
+			),
+		},
+		{
+			Name:      "maintenance-windows",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				kb := mkKibana(uid)
+				kb.Spec.MaintenanceWindows = []commonv1.MaintenanceWindow{
+					{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}, TimeZone: "Europe/Paris"},
+				}
+				return serialize(t, kb)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-maintenance-windows",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				kb := mkKibana(uid)
+				kb.Spec.MaintenanceWindows = []commonv1.MaintenanceWindow{
+					{Schedule: "0 25 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+				}
+				return serialize(t, kb)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.maintenanceWindows\[0\].schedule: Invalid value: "0 25 \* \* \*": invalid value "25" in hour field`,
+			),
+		},
 	}
 
 	validator := &kbv1.Kibana{}
@@ -174,6 +174,11 @@ func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
 		}
 	}
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]commonv1.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaSpec.
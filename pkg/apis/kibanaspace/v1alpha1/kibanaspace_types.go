@@ -0,0 +1,183 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// KibanaSpaceKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	KibanaSpaceKind = "KibanaSpace"
+
+	// DefaultSpaceID is the id of the default Kibana space, which always exists and cannot be deleted.
+	DefaultSpaceID = "default"
+)
+
+func init() {
+	SchemeBuilder.Register(&KibanaSpace{}, &KibanaSpaceList{})
+}
+
+// +kubebuilder:object:root=true
+
+// KibanaSpace represents a Kibana space, and the saved objects such as dashboards and visualizations imported into it.
+// +kubebuilder:resource:categories=elastic,shortName=kbspace
+// +kubebuilder:printcolumn:name="Kibana",type="string",JSONPath=".spec.kibanaRef.name"
+// +kubebuilder:printcolumn:name="Space",type="string",JSONPath=".status.spaceID"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type KibanaSpace struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KibanaSpaceSpec   `json:"spec,omitempty"`
+	Status KibanaSpaceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// KibanaSpaceList contains a list of KibanaSpace resources.
+type KibanaSpaceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KibanaSpace `json:"items"`
+}
+
+type KibanaSpaceSpec struct {
+	// KibanaRef is a reference to the Kibana instance, in the same namespace as the KibanaSpace, the space is
+	// configured on. Kibana must be associated with an Elasticsearch cluster managed by the operator.
+	// It cannot be changed once the KibanaSpace is created.
+	KibanaRef commonv1.LocalObjectSelector `json:"kibanaRef"`
+
+	// SpaceID is the id of the space in Kibana, used in its URL. Defaults to the name of the KibanaSpace.
+	// It cannot be changed once the KibanaSpace is created.
+	// +kubebuilder:validation:Optional
+	SpaceID string `json:"spaceID,omitempty"`
+
+	// DisplayName is the name of the space displayed in Kibana. Defaults to the name of the KibanaSpace.
+	// +kubebuilder:validation:Optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Description is the description of the space displayed in Kibana.
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+
+	// Color is the hexadecimal color of the avatar of the space, for example #aabbcc. Chosen by Kibana if not set.
+	// +kubebuilder:validation:Optional
+	Color string `json:"color,omitempty"`
+
+	// Initials are the characters displayed in the avatar of the space. Computed by Kibana from the name if not set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=2
+	Initials string `json:"initials,omitempty"`
+
+	// DisabledFeatures are the ids of the Kibana features hidden in the space, for example discover or dev_tools.
+	// +kubebuilder:validation:Optional
+	DisabledFeatures []string `json:"disabledFeatures,omitempty"`
+
+	// SavedObjects are the sources of the saved objects, such as dashboards, visualizations or data views, imported
+	// into the space. Saved objects with the same type and id are overwritten.
+	// +kubebuilder:validation:Optional
+	SavedObjects []SavedObjectsSource `json:"savedObjects,omitempty"`
+}
+
+// SavedObjectsSource is a source of saved objects to import into a Kibana space.
+type SavedObjectsSource struct {
+	// ConfigMapName is the name of the ConfigMap, in the namespace of the KibanaSpace, holding the saved objects in the
+	// NDJSON format of the Kibana export API. Each entry of the ConfigMap is imported.
+	ConfigMapName string `json:"configMapName"`
+}
+
+type KibanaSpaceStatus struct {
+	// Phase is the phase of the KibanaSpace.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the space is not configured yet, or why its configuration failed.
+	Message string `json:"message,omitempty"`
+	// SpaceID is the id of the space in Kibana.
+	SpaceID string `json:"spaceID,omitempty"`
+	// SavedObjectsHash is the hash of the saved objects last imported successfully into the space.
+	SavedObjectsHash string `json:"savedObjectsHash,omitempty"`
+	// ImportErrors are the errors reported by Kibana for the saved objects that could not be imported.
+	ImportErrors []SavedObjectImportError `json:"importErrors,omitempty"`
+	// LastDriftTime is the last time the space was found modified outside of the operator and restored.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this KibanaSpace.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// SavedObjectImportError is an error reported by Kibana for a saved object that could not be imported.
+type SavedObjectImportError struct {
+	// ConfigMapName is the name of the ConfigMap holding the saved object.
+	ConfigMapName string `json:"configMapName"`
+	// Key is the entry of the ConfigMap holding the saved object.
+	Key string `json:"key"`
+	// Type is the type of the saved object, for example dashboard.
+	Type string `json:"type,omitempty"`
+	// ID is the id of the saved object.
+	ID string `json:"id,omitempty"`
+	// Error is the reason the saved object could not be imported, for example missing_references.
+	Error string `json:"error"`
+}
+
+// Phase is the phase of a KibanaSpace.
+type Phase = commonv1.APIResourcePhase
+
+const (
+	ReadyPhase           = commonv1.APIResourceReadyPhase
+	ApplyingChangesPhase = commonv1.APIResourceApplyingChangesPhase
+	ErrorPhase           = commonv1.APIResourceErrorPhase
+	InvalidPhase         = commonv1.APIResourceInvalidPhase
+)
+
+// SpaceIDOrDefault returns the id of the space in Kibana.
+func (s *KibanaSpace) SpaceIDOrDefault() string {
+	if s.Spec.SpaceID != "" {
+		return s.Spec.SpaceID
+	}
+	return s.Name
+}
+
+// DisplayNameOrDefault returns the name of the space displayed in Kibana.
+func (s *KibanaSpace) DisplayNameOrDefault() string {
+	if s.Spec.DisplayName != "" {
+		return s.Spec.DisplayName
+	}
+	return s.Name
+}
+
+// IsDefaultSpace returns true if the KibanaSpace configures the default Kibana space.
+func (s *KibanaSpace) IsDefaultSpace() bool {
+	return s.SpaceIDOrDefault() == DefaultSpaceID
+}
+
+// ConfigMapNames returns the names of the ConfigMaps holding the saved objects of the space.
+func (s *KibanaSpace) ConfigMapNames() []string {
+	names := make([]string, 0, len(s.Spec.SavedObjects))
+	for _, source := range s.Spec.SavedObjects {
+		names = append(names, source.ConfigMapName)
+	}
+	return names
+}
+
+// References returns true if the space is configured on the given Kibana.
+func (s *KibanaSpace) References(kb types.NamespacedName) bool {
+	return s.Spec.KibanaRef.WithDefaultNamespace(s.Namespace).NamespacedName() == kb
+}
+
+// IsMarkedForDeletion returns true if the KibanaSpace resource is going to be deleted.
+func (s *KibanaSpace) IsMarkedForDeletion() bool {
+	return !s.DeletionTimestamp.IsZero()
+}
+
+// IsDegraded returns true when the KibanaSpaceStatus is degraded compared to the previous status.
+func (s KibanaSpaceStatus) IsDegraded(prev KibanaSpaceStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
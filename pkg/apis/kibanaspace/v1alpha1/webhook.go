@@ -0,0 +1,182 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	// kibanaSpaceWebhookPath is the HTTP path for the KibanaSpace validating webhook.
+	kibanaSpaceWebhookPath = "/validate-kibanaspace-k8s-elastic-co-v1alpha1-kibanaspaces"
+
+	crossNamespaceRefErrMsg       = "Kibana must be in the same namespace as the resource"
+	serviceNameNotSupportedErrMsg = "a custom service is not supported to reach Kibana"
+	invalidSpaceIDErrMsg          = "must contain lowercase alphanumeric characters, hyphens and underscores"
+	invalidColorErrMsg            = "must be a hexadecimal color such as #aabbcc"
+	kibanaChangeErrMsg            = "the Kibana instance of the space cannot be changed"
+	spaceIDChangeErrMsg           = "the space id cannot be changed"
+)
+
+var (
+	kibanaSpaceGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: KibanaSpaceKind}
+	validationLog        = ulog.Log.WithName("kibanaspace-v1alpha1-validation")
+
+	// spaceIDRegexp matches the space ids accepted by Kibana.
+	spaceIDRegexp = regexp.MustCompile(`^[a-z0-9_\-]+$`)
+	// colorRegexp matches the avatar colors accepted by Kibana.
+	colorRegexp = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+	kibanaSpaceDefaultChecks = []func(*KibanaSpace) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		validKibanaRef,
+		validSpaceID,
+		validColor,
+		validSavedObjects,
+	}
+
+	kibanaSpaceUpdateChecks = []func(old, curr *KibanaSpace) field.ErrorList{
+		checkKibanaRefChange,
+		checkSpaceIDChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-kibanaspace-k8s-elastic-co-v1alpha1-kibanaspaces,mutating=false,failurePolicy=ignore,groups=kibanaspace.k8s.elastic.co,resources=kibanaspaces,verbs=create;update,versions=v1alpha1,name=elastic-kibanaspace-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &KibanaSpace{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (s *KibanaSpace) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", s.Name)
+	return s.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (s *KibanaSpace) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", s.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (s *KibanaSpace) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", s.Name)
+	oldObj, ok := old.(*KibanaSpace)
+	if !ok {
+		return nil, errors.New("cannot cast old object to KibanaSpace type")
+	}
+	return s.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (s *KibanaSpace) WebhookPath() string {
+	return kibanaSpaceWebhookPath
+}
+
+func (s *KibanaSpace) validate(old *KibanaSpace) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range kibanaSpaceDefaultChecks {
+		if err := dc(s); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range kibanaSpaceUpdateChecks {
+			if err := uc(old, s); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(kibanaSpaceGroupKind, s.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkNoUnknownFields(s *KibanaSpace) field.ErrorList {
+	return commonv1.NoUnknownFields(s, s.ObjectMeta)
+}
+
+func checkNameLength(s *KibanaSpace) field.ErrorList {
+	return commonv1.CheckNameLength(s)
+}
+
+// validKibanaRef validates the reference to Kibana, which must be in the namespace of the space.
+func validKibanaRef(s *KibanaSpace) field.ErrorList {
+	path := field.NewPath("spec").Child("kibanaRef")
+	switch {
+	case s.Spec.KibanaRef.Name == "":
+		return field.ErrorList{field.Required(path.Child("name"), "Kibana name is mandatory")}
+	case s.Spec.KibanaRef.Namespace != "" && s.Spec.KibanaRef.Namespace != s.Namespace:
+		return field.ErrorList{field.Invalid(path.Child("namespace"), s.Spec.KibanaRef.Namespace, crossNamespaceRefErrMsg)}
+	case s.Spec.KibanaRef.ServiceName != "":
+		return field.ErrorList{field.Forbidden(path.Child("serviceName"), serviceNameNotSupportedErrMsg)}
+	}
+	return nil
+}
+
+func validSpaceID(s *KibanaSpace) field.ErrorList {
+	if !spaceIDRegexp.MatchString(s.SpaceIDOrDefault()) {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("spaceID"), s.SpaceIDOrDefault(), invalidSpaceIDErrMsg)}
+	}
+	return nil
+}
+
+func validColor(s *KibanaSpace) field.ErrorList {
+	if s.Spec.Color != "" && !colorRegexp.MatchString(s.Spec.Color) {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("color"), s.Spec.Color, invalidColorErrMsg)}
+	}
+	return nil
+}
+
+// validSavedObjects checks that the saved objects are imported from distinct ConfigMaps.
+func validSavedObjects(s *KibanaSpace) field.ErrorList {
+	var errs field.ErrorList
+	names := set.Make()
+	for i, source := range s.Spec.SavedObjects {
+		path := field.NewPath("spec").Child("savedObjects").Index(i).Child("configMapName")
+		switch {
+		case source.ConfigMapName == "":
+			errs = append(errs, field.Required(path, "ConfigMap name is mandatory"))
+		case names.Has(source.ConfigMapName):
+			errs = append(errs, field.Duplicate(path, source.ConfigMapName))
+		}
+		names.Add(source.ConfigMapName)
+	}
+	return errs
+}
+
+func checkKibanaRefChange(old, curr *KibanaSpace) field.ErrorList {
+	if old.Spec.KibanaRef.Name != curr.Spec.KibanaRef.Name {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("kibanaRef"), kibanaChangeErrMsg)}
+	}
+	return nil
+}
+
+func checkSpaceIDChange(old, curr *KibanaSpace) field.ErrorList {
+	if old.SpaceIDOrDefault() != curr.SpaceIDOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("spaceID"), spaceIDChangeErrMsg)}
+	}
+	return nil
+}
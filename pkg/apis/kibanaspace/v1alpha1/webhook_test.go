@@ -0,0 +1,144 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kibanaspacev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibanaspace/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkKibanaSpace(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-kibana-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				s := mkKibanaSpace(uid)
+				s.Spec.KibanaRef = commonv1.LocalObjectSelector{Namespace: "other", Name: "kb"}
+				return serialize(t, s)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.kibanaRef.namespace: Invalid value: "other": Kibana must be in the same namespace as the resource`,
+			),
+		},
+		{
+			Name:      "invalid-space-id-and-color",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				s := mkKibanaSpace(uid)
+				s.Spec.SpaceID = "Team A"
+				s.Spec.Color = "blue"
+				return serialize(t, s)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.spaceID: Invalid value: "Team A": must contain lowercase alphanumeric characters, hyphens and underscores`,
+				`spec.color: Invalid value: "blue": must be a hexadecimal color such as #aabbcc`,
+			),
+		},
+		{
+			Name:      "invalid-saved-objects",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				s := mkKibanaSpace(uid)
+				s.Spec.SavedObjects = append(s.Spec.SavedObjects,
+					kibanaspacev1alpha1.SavedObjectsSource{ConfigMapName: "dashboards"},
+					kibanaspacev1alpha1.SavedObjectsSource{},
+				)
+				return serialize(t, s)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.savedObjects\[1\].configMapName: Duplicate value: "dashboards"`,
+				`spec.savedObjects\[2\].configMapName: Required value: ConfigMap name is mandatory`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkKibanaSpace(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				s := mkKibanaSpace(uid)
+				s.Spec.DisplayName = "Team A (production)"
+				s.Spec.DisabledFeatures = nil
+				s.Spec.SavedObjects = nil
+				return serialize(t, s)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-immutable-fields",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkKibanaSpace(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				s := mkKibanaSpace(uid)
+				s.Spec.KibanaRef.Name = "other-kb"
+				s.Spec.SpaceID = "team-b"
+				return serialize(t, s)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.kibanaRef: Forbidden: the Kibana instance of the space cannot be changed`,
+				`spec.spaceID: Forbidden: the space id cannot be changed`,
+			),
+		},
+	}
+
+	validator := &kibanaspacev1alpha1.KibanaSpace{}
+	gvk := metav1.GroupVersionKind{Group: kibanaspacev1alpha1.GroupVersion.Group, Version: kibanaspacev1alpha1.GroupVersion.Version, Kind: kibanaspacev1alpha1.KibanaSpaceKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkKibanaSpace(uid string) *kibanaspacev1alpha1.KibanaSpace {
+	return &kibanaspacev1alpha1.KibanaSpace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "team-a",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: kibanaspacev1alpha1.KibanaSpaceSpec{
+			KibanaRef:        commonv1.LocalObjectSelector{Name: "kb"},
+			DisplayName:      "Team A",
+			Color:            "#aabbcc",
+			DisabledFeatures: []string{"dev_tools"},
+			SavedObjects:     []kibanaspacev1alpha1.SavedObjectsSource{{ConfigMapName: "dashboards"}},
+		},
+	}
+}
+
+func serialize(t *testing.T, space *kibanaspacev1alpha1.KibanaSpace) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(space)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -12,7 +12,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 )
 
 type LogstashHealth string
@@ -74,6 +73,13 @@ type LogstashSpec struct {
 	// +kubebuilder:validation:Optional
 	PipelinesRef *commonv1.ConfigSource `json:"pipelinesRef,omitempty"`
 
+	// PipelinesRefs contains references to existing Kubernetes Secrets or ConfigMaps holding additional Logstash Pipelines,
+	// under a single "pipelines.yml" entry. Their pipelines are appended, in order, to the pipelines specified in
+	// [`Pipelines`, `PipelinesRef`]. Pipeline IDs must be unique across all sources. Changes to the referenced Secrets and
+	// ConfigMaps are reloaded by Logstash without restarting the Pods.
+	// +kubebuilder:validation:Optional
+	PipelinesRefs []PipelinesSource `json:"pipelinesRefs,omitempty"`
+
 	// Services contains details of services that Logstash should expose - similar to the HTTP layer configuration for the
 	// rest of the stack, but also applicable for more use cases than the metrics API, as logstash may need to
 	// be opened up for other services: Beats, TCP, UDP, etc, inputs.
@@ -123,6 +129,15 @@ type LogstashService struct {
 	TLS commonv1.TLSOptions `json:"tls,omitempty"`
 }
 
+// PipelinesSource is a reference to a Secret or a ConfigMap, in the namespace of the Logstash resource, holding Logstash
+// pipelines. Exactly one of [`SecretName`, `ConfigMapName`] must be specified.
+type PipelinesSource struct {
+	// SecretName is the name of the Secret holding the pipelines.
+	SecretName string `json:"secretName,omitempty"`
+	// ConfigMapName is the name of the ConfigMap holding the pipelines.
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
 // ElasticsearchCluster is a named reference to an Elasticsearch cluster which can be used in a Logstash pipeline.
 type ElasticsearchCluster struct {
 	commonv1.ObjectSelector `json:",omitempty,inline"`
@@ -371,7 +386,7 @@ func (lsmon *LogstashMonitoringAssociation) Associated() commonv1.Associated {
 
 func (lsmon *LogstashMonitoringAssociation) AssociationConfAnnotationName() string {
 	// Use a custom suffix for monitoring elasticsearchRefs to avoid clashes with other elasticsearchRefs
-	return commonv1.FormatNameWithID(commonv1.ElasticsearchConfigAnnotationNameBase+"%s-sm", hash.HashObject(lsmon.ref))
+	return commonv1.FormatNameWithID(commonv1.ElasticsearchConfigAnnotationNameBase+"%s-sm", lsmon.ref.IdentityHash())
 }
 
 func (lsmon *LogstashMonitoringAssociation) AssociationType() commonv1.AssociationType {
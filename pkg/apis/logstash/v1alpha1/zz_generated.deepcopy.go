@@ -191,6 +191,11 @@ func (in *LogstashSpec) DeepCopyInto(out *LogstashSpec) {
 		*out = new(v1.ConfigSource)
 		**out = **in
 	}
+	if in.PipelinesRefs != nil {
+		in, out := &in.PipelinesRefs, &out.PipelinesRefs
+		*out = make([]PipelinesSource, len(*in))
+		copy(*out, *in)
+	}
 	if in.Services != nil {
 		in, out := &in.Services, &out.Services
 		*out = make([]LogstashService, len(*in))
@@ -260,3 +265,18 @@ func (in *LogstashStatus) DeepCopy() *LogstashStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelinesSource) DeepCopyInto(out *PipelinesSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelinesSource.
+func (in *PipelinesSource) DeepCopy() *PipelinesSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelinesSource)
+	in.DeepCopyInto(out)
+	return out
+}
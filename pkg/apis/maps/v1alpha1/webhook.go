@@ -31,6 +31,11 @@ var (
 		checkNameLength,
 		checkSupportedVersion,
 		checkAssociation,
+		checkIngress,
+		checkGatewayRoute,
+		checkServiceMesh,
+		checkCertificateRotation,
+		checkHostnames,
 	}
 )
 
@@ -95,3 +100,23 @@ func checkSupportedVersion(ems *ElasticMapsServer) field.ErrorList {
 func checkAssociation(ems *ElasticMapsServer) field.ErrorList {
 	return commonv1.CheckAssociationRefs(field.NewPath("spec").Child("elasticsearchRef"), ems.Spec.ElasticsearchRef)
 }
+
+func checkIngress(ems *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckIngress(field.NewPath("spec").Child("http", "ingress"), ems.Spec.HTTP)
+}
+
+func checkGatewayRoute(ems *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckGatewayRoute(field.NewPath("spec").Child("http", "gatewayRoute"), ems.Spec.HTTP)
+}
+
+func checkServiceMesh(ems *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckServiceMesh(field.NewPath("spec").Child("http", "tls"), ems.Spec.HTTP.TLS)
+}
+
+func checkCertificateRotation(ems *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckCertificateRotation(field.NewPath("spec").Child("http", "tls", "certificateRotation"), ems.Spec.HTTP.TLS.CertificateRotation)
+}
+
+func checkHostnames(ems *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckHostnames(field.NewPath("spec").Child("http", "hostnames"), ems.Spec.HTTP)
+}
@@ -0,0 +1,194 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	Kind = "RemoteClusterLink"
+
+	// ExchangeSecretCAKey is the key of the exchange Secret holding the transport CA certificate of the server cluster.
+	ExchangeSecretCAKey = "ca.crt"
+	// ExchangeSecretAPIKeyKey is the key of the exchange Secret holding the encoded cross-cluster API key.
+	ExchangeSecretAPIKeyKey = "api-key"
+	// ExchangeSecretAddressKey is the key of the exchange Secret holding the address of the server cluster.
+	ExchangeSecretAddressKey = "address"
+	// ExchangeSecretServerNameKey is the optional key of the exchange Secret holding the TLS server name of the server cluster.
+	ExchangeSecretServerNameKey = "server-name"
+)
+
+func init() {
+	SchemeBuilder.Register(&RemoteClusterLink{}, &RemoteClusterLinkList{})
+}
+
+// +kubebuilder:object:root=true
+
+// RemoteClusterLink represents one side of a link between a local Elasticsearch cluster and a remote cluster managed by
+// another operator, possibly in another Kubernetes cluster. Both sides exchange the CA certificate, the cross-cluster
+// API key and the address of the server cluster through a Secret.
+// +kubebuilder:resource:categories=elastic,shortName=esremotelink
+// +kubebuilder:printcolumn:name="Elasticsearch",type="string",JSONPath=".spec.elasticsearchRef.name"
+// +kubebuilder:printcolumn:name="Role",type="string",JSONPath=".status.role"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type RemoteClusterLink struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RemoteClusterLinkSpec   `json:"spec,omitempty"`
+	Status RemoteClusterLinkStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RemoteClusterLinkList contains a list of RemoteClusterLink resources.
+type RemoteClusterLinkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RemoteClusterLink `json:"items"`
+}
+
+type RemoteClusterLinkSpec struct {
+	// ElasticsearchRef is a reference to the local Elasticsearch cluster, in the same namespace as the RemoteClusterLink.
+	ElasticsearchRef commonv1.LocalObjectSelector `json:"elasticsearchRef"`
+
+	// ExchangeSecretName is the name of the Secret, in the same namespace as the RemoteClusterLink, through which the CA
+	// certificate, the cross-cluster API key and the address of the server cluster are exchanged. On the server side, the
+	// operator creates the Secret, which must then be copied to the client side where the operator reads it.
+	// +kubebuilder:validation:MinLength=1
+	ExchangeSecretName string `json:"exchangeSecretName"`
+
+	// Server exposes the local cluster to a remote client cluster. Exactly one of Server and Client must be set.
+	// +kubebuilder:validation:Optional
+	Server *RemoteClusterLinkServer `json:"server,omitempty"`
+
+	// Client connects the local cluster to a remote server cluster. Exactly one of Server and Client must be set.
+	// +kubebuilder:validation:Optional
+	Client *RemoteClusterLinkClient `json:"client,omitempty"`
+}
+
+// RemoteClusterLinkServer configures the side of the link exposing the local cluster.
+type RemoteClusterLinkServer struct {
+	// Address is the address, as host:port, at which the client cluster reaches the remote cluster server interface of
+	// the local cluster, for example a load balancer in front of the port 9443 of the nodes.
+	// +kubebuilder:validation:MinLength=1
+	Address string `json:"address"`
+
+	// ServerName is the server name the client cluster sends in the TLS SNI extension.
+	// +kubebuilder:validation:Optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// Access is the access granted to the client cluster with the cross-cluster API key.
+	Access CrossClusterAccess `json:"access"`
+}
+
+// CrossClusterAccess is the access granted to a cross-cluster API key.
+type CrossClusterAccess struct {
+	// Search grants cross-cluster search on the given indices.
+	// +kubebuilder:validation:Optional
+	Search []CrossClusterIndices `json:"search,omitempty"`
+
+	// Replication grants cross-cluster replication of the given indices.
+	// +kubebuilder:validation:Optional
+	Replication []CrossClusterIndices `json:"replication,omitempty"`
+}
+
+// CrossClusterIndices are the indices a cross-cluster API key grants access to.
+type CrossClusterIndices struct {
+	// Names are the names or patterns of the indices.
+	// +kubebuilder:validation:MinItems=1
+	Names []string `json:"names"`
+
+	// AllowRestrictedIndices allows the patterns to cover restricted indices.
+	// +kubebuilder:validation:Optional
+	AllowRestrictedIndices *bool `json:"allowRestrictedIndices,omitempty"`
+}
+
+// RemoteClusterLinkClient configures the side of the link connecting to the remote cluster.
+type RemoteClusterLinkClient struct {
+	// Alias is the name of the remote cluster in the settings of the local cluster. Defaults to the name of the
+	// RemoteClusterLink. It cannot be changed once the RemoteClusterLink is created.
+	// +kubebuilder:validation:Optional
+	Alias string `json:"alias,omitempty"`
+
+	// SkipUnavailable makes cross-cluster searches skip the remote cluster if it is unavailable.
+	// +kubebuilder:validation:Optional
+	SkipUnavailable *bool `json:"skipUnavailable,omitempty"`
+}
+
+type RemoteClusterLinkStatus struct {
+	// Role is the role of the local cluster in the link, Server or Client.
+	Role Role `json:"role,omitempty"`
+	// Phase is the phase of the RemoteClusterLink.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the link is not established yet, or why it failed.
+	Message string `json:"message,omitempty"`
+	// APIKeyID is the ID of the cross-cluster API key created on the server side.
+	APIKeyID string `json:"apiKeyId,omitempty"`
+	// AccessHash is the hash of the access granted to the cross-cluster API key, to update it when the access changes.
+	AccessHash string `json:"accessHash,omitempty"`
+	// Connected is true when the client side is connected to the remote cluster.
+	Connected bool `json:"connected,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this RemoteClusterLink.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// Role is the role of the local cluster in a link.
+type Role string
+
+const (
+	ServerRole Role = "Server"
+	ClientRole Role = "Client"
+)
+
+// Phase is the phase of a RemoteClusterLink.
+type Phase = commonv1.APIResourcePhase
+
+const (
+	ReadyPhase           = commonv1.APIResourceReadyPhase
+	ApplyingChangesPhase = commonv1.APIResourceApplyingChangesPhase
+	ErrorPhase           = commonv1.APIResourceErrorPhase
+	InvalidPhase         = commonv1.APIResourceInvalidPhase
+)
+
+// Role returns the role of the local cluster in the link.
+func (l *RemoteClusterLink) Role() Role {
+	if l.Spec.Server != nil {
+		return ServerRole
+	}
+	return ClientRole
+}
+
+// AliasOrDefault returns the name of the remote cluster in the settings of the local cluster on the client side.
+func (l *RemoteClusterLink) AliasOrDefault() string {
+	if l.Spec.Client != nil && l.Spec.Client.Alias != "" {
+		return l.Spec.Client.Alias
+	}
+	return l.Name
+}
+
+// References returns true if the link is configured on the given Elasticsearch cluster.
+func (l *RemoteClusterLink) References(es types.NamespacedName) bool {
+	return l.Spec.ElasticsearchRef.WithDefaultNamespace(l.Namespace).NamespacedName() == es
+}
+
+// IsMarkedForDeletion returns true if the RemoteClusterLink resource is going to be deleted.
+func (l *RemoteClusterLink) IsMarkedForDeletion() bool {
+	return !l.DeletionTimestamp.IsZero()
+}
+
+// IsDegraded returns true when the RemoteClusterLinkStatus is degraded compared to the previous status.
+func (s RemoteClusterLinkStatus) IsDegraded(prev RemoteClusterLinkStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
@@ -0,0 +1,186 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// webhookPath is the HTTP path for the RemoteClusterLink validating webhook.
+	webhookPath = "/validate-remotecluster-k8s-elastic-co-v1alpha1-remoteclusterlinks"
+
+	crossNamespaceRefErrMsg       = "the Elasticsearch cluster must be in the same namespace as the resource"
+	serviceNameNotSupportedErrMsg = "a custom service is not supported to reach Elasticsearch"
+	roleErrMsg                    = "exactly one of server and client must be set"
+	noAccessErrMsg                = "at least one of search and replication access must be granted"
+	elasticsearchRefChangeErrMsg  = "the Elasticsearch cluster cannot be changed"
+	roleChangeErrMsg              = "the role of the local cluster cannot be changed"
+	aliasChangeErrMsg             = "the alias of the remote cluster cannot be changed"
+)
+
+var (
+	groupKind     = schema.GroupKind{Group: GroupVersion.Group, Kind: Kind}
+	validationLog = ulog.Log.WithName("remotecluster-v1alpha1-validation")
+
+	defaultChecks = []func(*RemoteClusterLink) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		validElasticsearchRef,
+		validRole,
+		validAccess,
+	}
+
+	updateChecks = []func(old, curr *RemoteClusterLink) field.ErrorList{
+		checkElasticsearchRefChange,
+		checkRoleChange,
+		checkAliasChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-remotecluster-k8s-elastic-co-v1alpha1-remoteclusterlinks,mutating=false,failurePolicy=ignore,groups=remotecluster.k8s.elastic.co,resources=remoteclusterlinks,verbs=create;update,versions=v1alpha1,name=elastic-remoteclusterlink-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &RemoteClusterLink{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (l *RemoteClusterLink) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", l.Name)
+	return l.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (l *RemoteClusterLink) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", l.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (l *RemoteClusterLink) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", l.Name)
+	oldObj, ok := old.(*RemoteClusterLink)
+	if !ok {
+		return nil, errors.New("cannot cast old object to RemoteClusterLink type")
+	}
+	return l.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (l *RemoteClusterLink) WebhookPath() string {
+	return webhookPath
+}
+
+func (l *RemoteClusterLink) validate(old *RemoteClusterLink) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range defaultChecks {
+		if err := dc(l); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range updateChecks {
+			if err := uc(old, l); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(groupKind, l.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkNoUnknownFields(l *RemoteClusterLink) field.ErrorList {
+	return commonv1.NoUnknownFields(l, l.ObjectMeta)
+}
+
+func checkNameLength(l *RemoteClusterLink) field.ErrorList {
+	return commonv1.CheckNameLength(l)
+}
+
+// validElasticsearchRef validates the reference to the local Elasticsearch cluster, which must be in the namespace of the link.
+func validElasticsearchRef(l *RemoteClusterLink) field.ErrorList {
+	path := field.NewPath("spec").Child("elasticsearchRef")
+	ref := l.Spec.ElasticsearchRef
+	switch {
+	case ref.Name == "":
+		return field.ErrorList{field.Required(path.Child("name"), "Elasticsearch name is mandatory")}
+	case ref.Namespace != "" && ref.Namespace != l.Namespace:
+		return field.ErrorList{field.Invalid(path.Child("namespace"), ref.Namespace, crossNamespaceRefErrMsg)}
+	case ref.ServiceName != "":
+		return field.ErrorList{field.Forbidden(path.Child("serviceName"), serviceNameNotSupportedErrMsg)}
+	}
+	return nil
+}
+
+func validRole(l *RemoteClusterLink) field.ErrorList {
+	switch {
+	case l.Spec.Server == nil && l.Spec.Client == nil:
+		return field.ErrorList{field.Required(field.NewPath("spec"), roleErrMsg)}
+	case l.Spec.Server != nil && l.Spec.Client != nil:
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("client"), roleErrMsg)}
+	}
+	return nil
+}
+
+func validAccess(l *RemoteClusterLink) field.ErrorList {
+	if l.Spec.Server == nil {
+		return nil
+	}
+	path := field.NewPath("spec").Child("server").Child("access")
+	access := l.Spec.Server.Access
+	if len(access.Search) == 0 && len(access.Replication) == 0 {
+		return field.ErrorList{field.Required(path, noAccessErrMsg)}
+	}
+	var errs field.ErrorList
+	for i, indices := range access.Search {
+		if len(indices.Names) == 0 {
+			errs = append(errs, field.Required(path.Child("search").Index(i).Child("names"), "index names are mandatory"))
+		}
+	}
+	for i, indices := range access.Replication {
+		if len(indices.Names) == 0 {
+			errs = append(errs, field.Required(path.Child("replication").Index(i).Child("names"), "index names are mandatory"))
+		}
+	}
+	return errs
+}
+
+func checkElasticsearchRefChange(old, curr *RemoteClusterLink) field.ErrorList {
+	if old.Spec.ElasticsearchRef.WithDefaultNamespace(old.Namespace) != curr.Spec.ElasticsearchRef.WithDefaultNamespace(curr.Namespace) {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("elasticsearchRef"), elasticsearchRefChangeErrMsg)}
+	}
+	return nil
+}
+
+func checkRoleChange(old, curr *RemoteClusterLink) field.ErrorList {
+	if old.Role() != curr.Role() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec"), roleChangeErrMsg)}
+	}
+	return nil
+}
+
+func checkAliasChange(old, curr *RemoteClusterLink) field.ErrorList {
+	if curr.Spec.Client != nil && old.AliasOrDefault() != curr.AliasOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("client").Child("alias"), aliasChangeErrMsg)}
+	}
+	return nil
+}
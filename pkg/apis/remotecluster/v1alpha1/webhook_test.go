@@ -0,0 +1,206 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	remoteclusterv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/remotecluster/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid-server",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkServerLink(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "create-valid-client",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkClientLink(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-elasticsearch-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				l := mkClientLink(uid)
+				l.Spec.ElasticsearchRef = commonv1.LocalObjectSelector{Namespace: "other", Name: "es"}
+				return serialize(t, l)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef.namespace: Invalid value: "other": the Elasticsearch cluster must be in the same namespace as the resource`,
+			),
+		},
+		{
+			Name:      "no-role",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				l := mkClientLink(uid)
+				l.Spec.Client = nil
+				return serialize(t, l)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec: Required value: exactly one of server and client must be set`,
+			),
+		},
+		{
+			Name:      "both-roles",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				l := mkServerLink(uid)
+				l.Spec.Client = &remoteclusterv1alpha1.RemoteClusterLinkClient{}
+				return serialize(t, l)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.client: Forbidden: exactly one of server and client must be set`,
+			),
+		},
+		{
+			Name:      "no-access",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				l := mkServerLink(uid)
+				l.Spec.Server.Access = remoteclusterv1alpha1.CrossClusterAccess{}
+				return serialize(t, l)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.server.access: Required value: at least one of search and replication access must be granted`,
+			),
+		},
+		{
+			Name:      "no-index-names",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				l := mkServerLink(uid)
+				l.Spec.Server.Access.Replication = []remoteclusterv1alpha1.CrossClusterIndices{{}}
+				return serialize(t, l)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.server.access.replication\[0\].names: Required value: index names are mandatory`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkClientLink(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				l := mkClientLink(uid)
+				l.Spec.Client.Alias = l.Name
+				l.Spec.ExchangeSecretName = "other-secret"
+				return serialize(t, l)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-role-and-elasticsearch",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkClientLink(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				l := mkServerLink(uid)
+				l.Spec.ElasticsearchRef.Name = "es2"
+				return serialize(t, l)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef: Forbidden: the Elasticsearch cluster cannot be changed`,
+				`spec: Forbidden: the role of the local cluster cannot be changed`,
+			),
+		},
+		{
+			Name:      "update-alias",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkClientLink(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				l := mkClientLink(uid)
+				l.Spec.Client.Alias = "other-alias"
+				return serialize(t, l)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.client.alias: Forbidden: the alias of the remote cluster cannot be changed`,
+			),
+		},
+	}
+
+	validator := &remoteclusterv1alpha1.RemoteClusterLink{}
+	gvk := metav1.GroupVersionKind{Group: remoteclusterv1alpha1.GroupVersion.Group, Version: remoteclusterv1alpha1.GroupVersion.Version, Kind: remoteclusterv1alpha1.Kind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkServerLink(uid string) *remoteclusterv1alpha1.RemoteClusterLink {
+	return &remoteclusterv1alpha1.RemoteClusterLink{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "link-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: remoteclusterv1alpha1.RemoteClusterLinkSpec{
+			ElasticsearchRef:   commonv1.LocalObjectSelector{Name: "es"},
+			ExchangeSecretName: "link-test-exchange",
+			Server: &remoteclusterv1alpha1.RemoteClusterLinkServer{
+				Address: "es.example.com:9443",
+				Access: remoteclusterv1alpha1.CrossClusterAccess{
+					Search: []remoteclusterv1alpha1.CrossClusterIndices{{Names: []string{"logs-*"}}},
+				},
+			},
+		},
+	}
+}
+
+func mkClientLink(uid string) *remoteclusterv1alpha1.RemoteClusterLink {
+	return &remoteclusterv1alpha1.RemoteClusterLink{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "link-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: remoteclusterv1alpha1.RemoteClusterLinkSpec{
+			ElasticsearchRef:   commonv1.LocalObjectSelector{Name: "es"},
+			ExchangeSecretName: "link-test-exchange",
+			Client:             &remoteclusterv1alpha1.RemoteClusterLinkClient{},
+		},
+	}
+}
+
+func serialize(t *testing.T, link *remoteclusterv1alpha1.RemoteClusterLink) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(link)
+	require.NoError(t, err)
+
+	return objBytes
+}
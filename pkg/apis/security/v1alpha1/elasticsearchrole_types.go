@@ -0,0 +1,205 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// RoleKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	RoleKind = "ElasticsearchRole"
+)
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchRole{}, &ElasticsearchRoleList{})
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchRole represents a role of the native realm configured on Elasticsearch clusters.
+// +kubebuilder:resource:categories=elastic,shortName=esrole
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.readyCount",description="Elasticsearch clusters configured"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type ElasticsearchRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchRoleSpec   `json:"spec,omitempty"`
+	Status ElasticsearchRoleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchRoleList contains a list of ElasticsearchRole resources.
+type ElasticsearchRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchRole `json:"items"`
+}
+
+type ElasticsearchRoleSpec struct {
+	// ElasticsearchRefs are references to the Elasticsearch clusters the role is configured on.
+	// The clusters must be in the same namespace as the ElasticsearchRole.
+	// +kubebuilder:validation:Optional
+	ElasticsearchRefs []commonv1.LocalObjectSelector `json:"elasticsearchRefs,omitempty"`
+
+	// ElasticsearchSelector selects the Elasticsearch clusters, in the same namespace as the ElasticsearchRole, the
+	// role is configured on, in addition to the ones referenced in ElasticsearchRefs.
+	// +kubebuilder:validation:Optional
+	ElasticsearchSelector *metav1.LabelSelector `json:"elasticsearchSelector,omitempty"`
+
+	// RoleName is the name of the role in Elasticsearch. Defaults to the name of the ElasticsearchRole.
+	// It cannot be changed once the ElasticsearchRole is created.
+	// +kubebuilder:validation:Optional
+	RoleName string `json:"roleName,omitempty"`
+
+	// Cluster is the list of cluster privileges granted by the role.
+	// +kubebuilder:validation:Optional
+	Cluster []string `json:"cluster,omitempty"`
+
+	// Indices is the list of indices privileges granted by the role.
+	// +kubebuilder:validation:Optional
+	Indices []IndicesPrivileges `json:"indices,omitempty"`
+
+	// RunAs is the list of users the owners of the role can impersonate.
+	// +kubebuilder:validation:Optional
+	RunAs []string `json:"runAs,omitempty"`
+
+	// Metadata is arbitrary metadata attached to the role.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Metadata *commonv1.Config `json:"metadata,omitempty"`
+}
+
+// IndicesPrivileges are privileges granted on indices.
+type IndicesPrivileges struct {
+	// Names are the names or patterns of the indices the privileges are granted on.
+	// +kubebuilder:validation:MinItems=1
+	Names []string `json:"names"`
+	// Privileges are the indices privileges granted.
+	// +kubebuilder:validation:MinItems=1
+	Privileges []string `json:"privileges"`
+	// FieldSecurity restricts the fields of the documents that can be read (field level security).
+	// +kubebuilder:validation:Optional
+	FieldSecurity *FieldSecurity `json:"fieldSecurity,omitempty"`
+	// Query is the query, in JSON, that defines the documents that can be read (document level security).
+	// +kubebuilder:validation:Optional
+	Query string `json:"query,omitempty"`
+	// AllowRestrictedIndices allows the names to match restricted indices, such as the system indices.
+	// +kubebuilder:validation:Optional
+	AllowRestrictedIndices bool `json:"allowRestrictedIndices,omitempty"`
+}
+
+// FieldSecurity lists the fields of the documents that can be read.
+type FieldSecurity struct {
+	// Grant are the names or patterns of the fields that can be read.
+	// +kubebuilder:validation:MinItems=1
+	Grant []string `json:"grant"`
+	// Except are the names or patterns of the fields excluded from the granted ones.
+	// +kubebuilder:validation:Optional
+	Except []string `json:"except,omitempty"`
+}
+
+type ElasticsearchRoleStatus struct {
+	// Details holds the status of the role for each Elasticsearch cluster, indexed by cluster name.
+	Details map[string]ElasticsearchClusterStatus `json:"details,omitempty"`
+	// Resources is the number of Elasticsearch clusters the role is configured on.
+	Resources int `json:"resources,omitempty"`
+	// Ready is the number of Elasticsearch clusters on which the role is successfully configured.
+	Ready int `json:"ready,omitempty"`
+	// ReadyCount is a human representation of the number of clusters on which the role is successfully configured.
+	ReadyCount string `json:"readyCount,omitempty"`
+	// Phase is the phase of the ElasticsearchRole.
+	Phase Phase `json:"phase,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this ElasticsearchRole.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ElasticsearchClusterStatus models the status of a resource for one Elasticsearch cluster.
+type ElasticsearchClusterStatus struct {
+	// Phase is the phase of the resource on the Elasticsearch cluster.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the resource is not configured yet, or why its configuration failed.
+	Message string `json:"message,omitempty"`
+	// LastDriftTime is the last time the resource was found modified outside of the operator and restored.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+}
+
+// RoleNameOrDefault returns the name of the role in Elasticsearch.
+func (r *ElasticsearchRole) RoleNameOrDefault() string {
+	if r.Spec.RoleName != "" {
+		return r.Spec.RoleName
+	}
+	return r.Name
+}
+
+// Selects returns true if the role is configured on the given Elasticsearch cluster, either because it is referenced
+// or because its labels match the selector.
+func (r *ElasticsearchRole) Selects(es types.NamespacedName, esLabels map[string]string) bool {
+	for _, ref := range r.Spec.ElasticsearchRefs {
+		if ref.WithDefaultNamespace(r.Namespace).NamespacedName() == es {
+			return true
+		}
+	}
+	if r.Spec.ElasticsearchSelector == nil || es.Namespace != r.Namespace {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(r.Spec.ElasticsearchSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(esLabels))
+}
+
+// IsMarkedForDeletion returns true if the ElasticsearchRole resource is going to be deleted.
+func (r *ElasticsearchRole) IsMarkedForDeletion() bool {
+	return !r.DeletionTimestamp.IsZero()
+}
+
+func NewRoleStatus(role ElasticsearchRole) ElasticsearchRoleStatus {
+	status := ElasticsearchRoleStatus{
+		Details:            map[string]ElasticsearchClusterStatus{},
+		Phase:              ReadyPhase,
+		ObservedGeneration: role.Generation,
+	}
+	status.setReadyCount()
+	return status
+}
+
+func (s *ElasticsearchRoleStatus) setReadyCount() {
+	s.ReadyCount = fmt.Sprintf("%d/%d", s.Ready, s.Resources)
+}
+
+// SetElasticsearchStatus sets the status of the role for the given Elasticsearch cluster.
+func (s *ElasticsearchRoleStatus) SetElasticsearchStatus(esName string, status ElasticsearchClusterStatus) {
+	if s.Details == nil {
+		s.Details = map[string]ElasticsearchClusterStatus{}
+	}
+	s.Details[esName] = status
+	s.Update()
+}
+
+// Update updates the role status from its clusters statuses.
+func (s *ElasticsearchRoleStatus) Update() {
+	phaseOf := func(status ElasticsearchClusterStatus) Phase { return status.Phase }
+	s.Resources, s.Ready, s.Phase = commonv1.SummarizePhases(s.Details, phaseOf, s.Phase)
+	s.setReadyCount()
+}
+
+// IsDegraded returns true when the ElasticsearchRoleStatus is degraded compared to the previous status.
+func (s ElasticsearchRoleStatus) IsDegraded(prev ElasticsearchRoleStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
@@ -0,0 +1,154 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// roleWebhookPath is the HTTP path for the ElasticsearchRole validating webhook.
+	roleWebhookPath = "/validate-security-k8s-elastic-co-v1alpha1-elasticsearchroles"
+
+	reservedRoleErrMsg   = "the role name is reserved by the operator"
+	roleNameChangeErrMsg = "the role name cannot be changed"
+	invalidQueryErrMsg   = "the query must be valid JSON"
+)
+
+var (
+	roleGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: RoleKind}
+
+	// operatorRolePrefixes are the prefixes of the roles the operator creates in the file realm.
+	operatorRolePrefixes = []string{"elastic-internal", "elastic_internal", "eck_"}
+
+	roleDefaultChecks = []func(*ElasticsearchRole) field.ErrorList{
+		checkRoleNoUnknownFields,
+		checkRoleNameLength,
+		validRoleElasticsearchClusters,
+		validRoleName,
+		validIndicesPrivileges,
+	}
+
+	roleUpdateChecks = []func(old, curr *ElasticsearchRole) field.ErrorList{
+		checkRoleNameChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-security-k8s-elastic-co-v1alpha1-elasticsearchroles,mutating=false,failurePolicy=ignore,groups=security.k8s.elastic.co,resources=elasticsearchroles,verbs=create;update,versions=v1alpha1,name=elastic-elasticsearchrole-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &ElasticsearchRole{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (r *ElasticsearchRole) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", r.Name)
+	return r.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (r *ElasticsearchRole) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", r.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (r *ElasticsearchRole) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", r.Name)
+	oldObj, ok := old.(*ElasticsearchRole)
+	if !ok {
+		return nil, errors.New("cannot cast old object to ElasticsearchRole type")
+	}
+	return r.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (r *ElasticsearchRole) WebhookPath() string {
+	return roleWebhookPath
+}
+
+func (r *ElasticsearchRole) validate(old *ElasticsearchRole) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range roleDefaultChecks {
+		if err := dc(r); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range roleUpdateChecks {
+			if err := uc(old, r); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(roleGroupKind, r.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkRoleNoUnknownFields(r *ElasticsearchRole) field.ErrorList {
+	return commonv1.NoUnknownFields(r, r.ObjectMeta)
+}
+
+func checkRoleNameLength(r *ElasticsearchRole) field.ErrorList {
+	return commonv1.CheckNameLength(r)
+}
+
+func validRoleElasticsearchClusters(r *ElasticsearchRole) field.ErrorList {
+	return validateElasticsearchClusters(r.Spec.ElasticsearchRefs, r.Spec.ElasticsearchSelector, r.Namespace)
+}
+
+func validRoleName(r *ElasticsearchRole) field.ErrorList {
+	for _, prefix := range operatorRolePrefixes {
+		if strings.HasPrefix(r.RoleNameOrDefault(), prefix) {
+			return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("roleName"), reservedRoleErrMsg)}
+		}
+	}
+	return nil
+}
+
+func validIndicesPrivileges(r *ElasticsearchRole) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("indices")
+	for i, indices := range r.Spec.Indices {
+		if len(indices.Names) == 0 {
+			errs = append(errs, field.Required(path.Index(i).Child("names"), "indices names are mandatory"))
+		}
+		if len(indices.Privileges) == 0 {
+			errs = append(errs, field.Required(path.Index(i).Child("privileges"), "indices privileges are mandatory"))
+		}
+		if indices.FieldSecurity != nil && len(indices.FieldSecurity.Grant) == 0 {
+			errs = append(errs, field.Required(path.Index(i).Child("fieldSecurity", "grant"), "granted fields are mandatory"))
+		}
+		if indices.Query != "" && !json.Valid([]byte(indices.Query)) {
+			errs = append(errs, field.Invalid(path.Index(i).Child("query"), indices.Query, invalidQueryErrMsg))
+		}
+	}
+	return errs
+}
+
+func checkRoleNameChange(old, curr *ElasticsearchRole) field.ErrorList {
+	if old.RoleNameOrDefault() != curr.RoleNameOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("roleName"), roleNameChangeErrMsg)}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"testing"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestElasticsearchRoleWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchRole(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "create-valid-selector",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRole(uid)
+				r.Spec.ElasticsearchRefs = nil
+				r.Spec.ElasticsearchSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "no-elasticsearch",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRole(uid)
+				r.Spec.ElasticsearchRefs = nil
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRefs: Required value: at least one Elasticsearch cluster must be referenced or selected`,
+			),
+		},
+		{
+			Name:      "invalid-elasticsearch-refs-and-selector",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRole(uid)
+				r.Spec.ElasticsearchRefs = []commonv1.LocalObjectSelector{{Name: "es"}, {Namespace: "other", Name: "es2"}, {Name: "es"}}
+				r.Spec.ElasticsearchSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Unknown"}},
+				}
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRefs\[1\].namespace: Invalid value: "other": Elasticsearch clusters must be in the same namespace as the resource`,
+				`spec.elasticsearchRefs\[2\].name: Duplicate value: "es"`,
+				`spec.elasticsearchSelector: Invalid value`,
+			),
+		},
+		{
+			Name:      "operator-role-name",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRole(uid)
+				r.Spec.RoleName = "eck_logstash_user_role"
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.roleName: Forbidden: the role name is reserved by the operator`,
+			),
+		},
+		{
+			Name:      "invalid-indices-privileges",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRole(uid)
+				r.Spec.Indices = append(r.Spec.Indices, securityv1alpha1.IndicesPrivileges{
+					FieldSecurity: &securityv1alpha1.FieldSecurity{Except: []string{"secret"}},
+					Query:         `{"term":`,
+				})
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.indices\[1\].names: Required value: indices names are mandatory`,
+				`spec.indices\[1\].privileges: Required value: indices privileges are mandatory`,
+				`spec.indices\[1\].fieldSecurity.grant: Required value: granted fields are mandatory`,
+				`spec.indices\[1\].query: Invalid value: "{\\"term\\":": the query must be valid JSON`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchRole(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRole(uid)
+				r.Spec.RoleName = r.Name
+				r.Spec.Cluster = []string{"monitor"}
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-role-name",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchRole(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRole(uid)
+				r.Spec.RoleName = "other-role"
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.roleName: Forbidden: the role name cannot be changed`,
+			),
+		},
+	}
+
+	validator := &securityv1alpha1.ElasticsearchRole{}
+	gvk := metav1.GroupVersionKind{Group: securityv1alpha1.GroupVersion.Group, Version: securityv1alpha1.GroupVersion.Version, Kind: securityv1alpha1.RoleKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkElasticsearchRole(uid string) *securityv1alpha1.ElasticsearchRole {
+	return &securityv1alpha1.ElasticsearchRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "role-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: securityv1alpha1.ElasticsearchRoleSpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			Indices: []securityv1alpha1.IndicesPrivileges{{
+				Names:         []string{"logs-*"},
+				Privileges:    []string{"read"},
+				FieldSecurity: &securityv1alpha1.FieldSecurity{Grant: []string{"*"}, Except: []string{"secret"}},
+				Query:         `{"term":{"team":"a"}}`,
+			}},
+		},
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// RoleMappingKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	RoleMappingKind = "ElasticsearchRoleMapping"
+)
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchRoleMapping{}, &ElasticsearchRoleMappingList{})
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchRoleMapping represents a role mapping, which assigns roles to the users of realms such as OIDC, SAML
+// or LDAP, configured on Elasticsearch clusters.
+// +kubebuilder:resource:categories=elastic,shortName=esrolemapping
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.readyCount",description="Elasticsearch clusters configured"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type ElasticsearchRoleMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchRoleMappingSpec   `json:"spec,omitempty"`
+	Status ElasticsearchRoleMappingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchRoleMappingList contains a list of ElasticsearchRoleMapping resources.
+type ElasticsearchRoleMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchRoleMapping `json:"items"`
+}
+
+type ElasticsearchRoleMappingSpec struct {
+	// ElasticsearchRefs are references to the Elasticsearch clusters the role mapping is configured on.
+	// The clusters must be in the same namespace as the ElasticsearchRoleMapping.
+	// +kubebuilder:validation:Optional
+	ElasticsearchRefs []commonv1.LocalObjectSelector `json:"elasticsearchRefs,omitempty"`
+
+	// ElasticsearchSelector selects the Elasticsearch clusters, in the same namespace as the ElasticsearchRoleMapping,
+	// the role mapping is configured on, in addition to the ones referenced in ElasticsearchRefs.
+	// +kubebuilder:validation:Optional
+	ElasticsearchSelector *metav1.LabelSelector `json:"elasticsearchSelector,omitempty"`
+
+	// MappingName is the name of the role mapping in Elasticsearch. Defaults to the name of the ElasticsearchRoleMapping.
+	// It cannot be changed once the ElasticsearchRoleMapping is created.
+	// +kubebuilder:validation:Optional
+	MappingName string `json:"mappingName,omitempty"`
+
+	// Roles are the roles assigned to the users matching the rules.
+	// Exactly one of Roles or RoleTemplates must be set.
+	// +kubebuilder:validation:Optional
+	Roles []string `json:"roles,omitempty"`
+
+	// RoleTemplates are templates rendered into the names of the roles assigned to the users matching the rules,
+	// from the details of the users such as their groups or username.
+	// Exactly one of Roles or RoleTemplates must be set.
+	// +kubebuilder:validation:Optional
+	RoleTemplates []RoleTemplate `json:"roleTemplates,omitempty"`
+
+	// Rules select the users the roles are assigned to, as expected in the rules field of the Elasticsearch role
+	// mapping API. For example, {"field": {"groups": "cn=admins,dc=example,dc=com"}}.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Rules *commonv1.Config `json:"rules"`
+
+	// Enabled enables the role mapping. Defaults to true.
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Metadata is arbitrary metadata attached to the role mapping.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Metadata *commonv1.Config `json:"metadata,omitempty"`
+}
+
+// RoleTemplateFormat is the format of the result of a role template.
+type RoleTemplateFormat string
+
+const (
+	// StringRoleTemplateFormat renders the template into the name of a single role.
+	StringRoleTemplateFormat RoleTemplateFormat = "string"
+	// JSONRoleTemplateFormat renders the template into a JSON array of role names.
+	JSONRoleTemplateFormat RoleTemplateFormat = "json"
+)
+
+// RoleTemplate is a Mustache template rendered into the names of the roles assigned by a role mapping.
+type RoleTemplate struct {
+	// Source is the Mustache template. The details of the user, such as username, groups or metadata, are
+	// available as template variables.
+	// +kubebuilder:validation:MinLength=1
+	Source string `json:"source"`
+	// Format is the format of the rendered template: string for the name of a single role, or json for a JSON array
+	// of role names. Defaults to string.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=string;json
+	Format RoleTemplateFormat `json:"format,omitempty"`
+}
+
+// FormatOrDefault returns the format of the rendered template.
+func (t RoleTemplate) FormatOrDefault() RoleTemplateFormat {
+	if t.Format == "" {
+		return StringRoleTemplateFormat
+	}
+	return t.Format
+}
+
+type ElasticsearchRoleMappingStatus struct {
+	// Details holds the status of the role mapping for each Elasticsearch cluster, indexed by cluster name.
+	Details map[string]ElasticsearchClusterStatus `json:"details,omitempty"`
+	// Resources is the number of Elasticsearch clusters the role mapping is configured on.
+	Resources int `json:"resources,omitempty"`
+	// Ready is the number of Elasticsearch clusters on which the role mapping is successfully configured.
+	Ready int `json:"ready,omitempty"`
+	// ReadyCount is a human representation of the number of clusters on which the role mapping is successfully configured.
+	ReadyCount string `json:"readyCount,omitempty"`
+	// Phase is the phase of the ElasticsearchRoleMapping.
+	Phase Phase `json:"phase,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this ElasticsearchRoleMapping.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// MappingNameOrDefault returns the name of the role mapping in Elasticsearch.
+func (m *ElasticsearchRoleMapping) MappingNameOrDefault() string {
+	if m.Spec.MappingName != "" {
+		return m.Spec.MappingName
+	}
+	return m.Name
+}
+
+// EnabledOrDefault returns true if the role mapping is enabled.
+func (m *ElasticsearchRoleMapping) EnabledOrDefault() bool {
+	return m.Spec.Enabled == nil || *m.Spec.Enabled
+}
+
+// Selects returns true if the role mapping is configured on the given Elasticsearch cluster, either because it is
+// referenced or because its labels match the selector.
+func (m *ElasticsearchRoleMapping) Selects(es types.NamespacedName, esLabels map[string]string) bool {
+	for _, ref := range m.Spec.ElasticsearchRefs {
+		if ref.WithDefaultNamespace(m.Namespace).NamespacedName() == es {
+			return true
+		}
+	}
+	if m.Spec.ElasticsearchSelector == nil || es.Namespace != m.Namespace {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(m.Spec.ElasticsearchSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(esLabels))
+}
+
+// IsMarkedForDeletion returns true if the ElasticsearchRoleMapping resource is going to be deleted.
+func (m *ElasticsearchRoleMapping) IsMarkedForDeletion() bool {
+	return !m.DeletionTimestamp.IsZero()
+}
+
+func NewRoleMappingStatus(mapping ElasticsearchRoleMapping) ElasticsearchRoleMappingStatus {
+	status := ElasticsearchRoleMappingStatus{
+		Details:            map[string]ElasticsearchClusterStatus{},
+		Phase:              ReadyPhase,
+		ObservedGeneration: mapping.Generation,
+	}
+	status.setReadyCount()
+	return status
+}
+
+func (s *ElasticsearchRoleMappingStatus) setReadyCount() {
+	s.ReadyCount = fmt.Sprintf("%d/%d", s.Ready, s.Resources)
+}
+
+// SetElasticsearchStatus sets the status of the role mapping for the given Elasticsearch cluster.
+func (s *ElasticsearchRoleMappingStatus) SetElasticsearchStatus(esName string, status ElasticsearchClusterStatus) {
+	if s.Details == nil {
+		s.Details = map[string]ElasticsearchClusterStatus{}
+	}
+	s.Details[esName] = status
+	s.Update()
+}
+
+// Update updates the role mapping status from its clusters statuses.
+func (s *ElasticsearchRoleMappingStatus) Update() {
+	phaseOf := func(status ElasticsearchClusterStatus) Phase { return status.Phase }
+	s.Resources, s.Ready, s.Phase = commonv1.SummarizePhases(s.Details, phaseOf, s.Phase)
+	s.setReadyCount()
+}
+
+// IsDegraded returns true when the ElasticsearchRoleMappingStatus is degraded compared to the previous status.
+func (s ElasticsearchRoleMappingStatus) IsDegraded(prev ElasticsearchRoleMappingStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
@@ -0,0 +1,159 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// roleMappingWebhookPath is the HTTP path for the ElasticsearchRoleMapping validating webhook.
+	roleMappingWebhookPath = "/validate-security-k8s-elastic-co-v1alpha1-elasticsearchrolemappings"
+
+	mappingNameChangeErrMsg = "the role mapping name cannot be changed"
+	invalidRulesErrMsg      = "the rules must contain exactly one of the any, all, field or except rules"
+	rolesRequiredErrMsg     = "at least one role or role template must be assigned"
+	rolesAndTemplatesErrMsg = "roles and role templates cannot be both set"
+)
+
+var (
+	roleMappingGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: RoleMappingKind}
+
+	// ruleTypes are the types of rules accepted at the root of the rules of a role mapping.
+	ruleTypes = []string{"any", "all", "field", "except"}
+
+	roleMappingDefaultChecks = []func(*ElasticsearchRoleMapping) field.ErrorList{
+		checkRoleMappingNoUnknownFields,
+		checkRoleMappingNameLength,
+		validRoleMappingElasticsearchClusters,
+		validRoleMappingRoles,
+		validRoleMappingRules,
+	}
+
+	roleMappingUpdateChecks = []func(old, curr *ElasticsearchRoleMapping) field.ErrorList{
+		checkMappingNameChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-security-k8s-elastic-co-v1alpha1-elasticsearchrolemappings,mutating=false,failurePolicy=ignore,groups=security.k8s.elastic.co,resources=elasticsearchrolemappings,verbs=create;update,versions=v1alpha1,name=elastic-elasticsearchrolemapping-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &ElasticsearchRoleMapping{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (m *ElasticsearchRoleMapping) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", m.Name)
+	return m.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (m *ElasticsearchRoleMapping) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", m.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (m *ElasticsearchRoleMapping) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", m.Name)
+	oldObj, ok := old.(*ElasticsearchRoleMapping)
+	if !ok {
+		return nil, errors.New("cannot cast old object to ElasticsearchRoleMapping type")
+	}
+	return m.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (m *ElasticsearchRoleMapping) WebhookPath() string {
+	return roleMappingWebhookPath
+}
+
+func (m *ElasticsearchRoleMapping) validate(old *ElasticsearchRoleMapping) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range roleMappingDefaultChecks {
+		if err := dc(m); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range roleMappingUpdateChecks {
+			if err := uc(old, m); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(roleMappingGroupKind, m.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkRoleMappingNoUnknownFields(m *ElasticsearchRoleMapping) field.ErrorList {
+	return commonv1.NoUnknownFields(m, m.ObjectMeta)
+}
+
+func checkRoleMappingNameLength(m *ElasticsearchRoleMapping) field.ErrorList {
+	return commonv1.CheckNameLength(m)
+}
+
+func validRoleMappingElasticsearchClusters(m *ElasticsearchRoleMapping) field.ErrorList {
+	return validateElasticsearchClusters(m.Spec.ElasticsearchRefs, m.Spec.ElasticsearchSelector, m.Namespace)
+}
+
+func validRoleMappingRoles(m *ElasticsearchRoleMapping) field.ErrorList {
+	path := field.NewPath("spec")
+	switch {
+	case len(m.Spec.Roles) == 0 && len(m.Spec.RoleTemplates) == 0:
+		return field.ErrorList{field.Required(path.Child("roles"), rolesRequiredErrMsg)}
+	case len(m.Spec.Roles) > 0 && len(m.Spec.RoleTemplates) > 0:
+		return field.ErrorList{field.Forbidden(path.Child("roleTemplates"), rolesAndTemplatesErrMsg)}
+	}
+	var errs field.ErrorList
+	for i, template := range m.Spec.RoleTemplates {
+		if template.Source == "" {
+			errs = append(errs, field.Required(path.Child("roleTemplates").Index(i).Child("source"), "the source of a role template is mandatory"))
+		}
+	}
+	return errs
+}
+
+// validRoleMappingRules checks the root of the rules, their content is validated by Elasticsearch.
+func validRoleMappingRules(m *ElasticsearchRoleMapping) field.ErrorList {
+	path := field.NewPath("spec").Child("rules")
+	if m.Spec.Rules == nil {
+		return field.ErrorList{field.Required(path, "rules are mandatory")}
+	}
+	if len(m.Spec.Rules.Data) != 1 {
+		return field.ErrorList{field.Invalid(path, m.Spec.Rules.Data, invalidRulesErrMsg)}
+	}
+	for ruleType := range m.Spec.Rules.Data {
+		if !slices.Contains(ruleTypes, ruleType) {
+			return field.ErrorList{field.Invalid(path, m.Spec.Rules.Data, invalidRulesErrMsg)}
+		}
+	}
+	return nil
+}
+
+func checkMappingNameChange(old, curr *ElasticsearchRoleMapping) field.ErrorList {
+	if old.MappingNameOrDefault() != curr.MappingNameOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("mappingName"), mappingNameChangeErrMsg)}
+	}
+	return nil
+}
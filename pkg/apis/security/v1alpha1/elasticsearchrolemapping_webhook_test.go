@@ -0,0 +1,187 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"testing"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestElasticsearchRoleMappingWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchRoleMapping(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "no-elasticsearch",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchRoleMapping(uid)
+				m.Spec.ElasticsearchSelector = nil
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRefs: Required value: at least one Elasticsearch cluster must be referenced or selected`,
+			),
+		},
+		{
+			Name:      "no-roles-and-rules",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchRoleMapping(uid)
+				m.Spec.Roles = nil
+				m.Spec.Rules = nil
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.roles: Required value: at least one role or role template must be assigned`,
+				`spec.rules: Required value: rules are mandatory`,
+			),
+		},
+		{
+			Name:      "create-valid-role-templates",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchRoleMapping(uid)
+				m.Spec.Roles = nil
+				m.Spec.RoleTemplates = []securityv1alpha1.RoleTemplate{
+					{Source: "{{#tojson}}groups{{/tojson}}", Format: securityv1alpha1.JSONRoleTemplateFormat},
+					{Source: "{{metadata.department}}_user"},
+				}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "roles-and-role-templates",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchRoleMapping(uid)
+				m.Spec.RoleTemplates = []securityv1alpha1.RoleTemplate{{Source: "{{metadata.department}}_user"}}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.roleTemplates: Forbidden: roles and role templates cannot be both set`,
+			),
+		},
+		{
+			Name:      "empty-role-template",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchRoleMapping(uid)
+				m.Spec.Roles = nil
+				m.Spec.RoleTemplates = []securityv1alpha1.RoleTemplate{{Format: securityv1alpha1.JSONRoleTemplateFormat}}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.roleTemplates\[0\].source: Required value: the source of a role template is mandatory`,
+			),
+		},
+		{
+			Name:      "invalid-rules",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchRoleMapping(uid)
+				m.Spec.Rules = &commonv1.Config{Data: map[string]interface{}{
+					"field": map[string]interface{}{"groups": "admins"},
+					"all":   []interface{}{},
+				}}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.rules: Invalid value: .*: the rules must contain exactly one of the any, all, field or except rules`,
+			),
+		},
+		{
+			Name:      "unknown-rule",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchRoleMapping(uid)
+				m.Spec.Rules = &commonv1.Config{Data: map[string]interface{}{"groups": "admins"}}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.rules: Invalid value: .*: the rules must contain exactly one of the any, all, field or except rules`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchRoleMapping(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchRoleMapping(uid)
+				m.Spec.MappingName = m.Name
+				m.Spec.Roles = append(m.Spec.Roles, "monitoring_user")
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-mapping-name",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchRoleMapping(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchRoleMapping(uid)
+				m.Spec.MappingName = "other-mapping"
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.mappingName: Forbidden: the role mapping name cannot be changed`,
+			),
+		},
+	}
+
+	validator := &securityv1alpha1.ElasticsearchRoleMapping{}
+	gvk := metav1.GroupVersionKind{Group: securityv1alpha1.GroupVersion.Group, Version: securityv1alpha1.GroupVersion.Version, Kind: securityv1alpha1.RoleMappingKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkElasticsearchRoleMapping(uid string) *securityv1alpha1.ElasticsearchRoleMapping {
+	return &securityv1alpha1.ElasticsearchRoleMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "role-mapping-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: securityv1alpha1.ElasticsearchRoleMappingSpec{
+			ElasticsearchSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			Roles:                 []string{"viewer"},
+			Rules: &commonv1.Config{Data: map[string]interface{}{
+				"all": []interface{}{
+					map[string]interface{}{"field": map[string]interface{}{"realm.name": "oidc1"}},
+					map[string]interface{}{"field": map[string]interface{}{"groups": "admins"}},
+				},
+			}},
+		},
+	}
+}
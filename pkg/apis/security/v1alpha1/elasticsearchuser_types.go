@@ -0,0 +1,161 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// UserKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	UserKind = "ElasticsearchUser"
+
+	// DefaultPasswordKey is the default key of the Secret holding the password of a user.
+	DefaultPasswordKey = "password"
+)
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchUser{}, &ElasticsearchUserList{})
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchUser represents a user of the native realm of an Elasticsearch cluster.
+// +kubebuilder:resource:categories=elastic,shortName=esuser
+// +kubebuilder:printcolumn:name="Username",type="string",JSONPath=".status.username"
+// +kubebuilder:printcolumn:name="Elasticsearch",type="string",JSONPath=".spec.elasticsearchRef.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type ElasticsearchUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchUserSpec   `json:"spec,omitempty"`
+	Status ElasticsearchUserStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchUserList contains a list of ElasticsearchUser resources.
+type ElasticsearchUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchUser `json:"items"`
+}
+
+type ElasticsearchUserSpec struct {
+	// ElasticsearchRef is a reference to the Elasticsearch cluster the user is created in.
+	// The cluster must be in the same namespace as the ElasticsearchUser. It cannot be changed once the
+	// ElasticsearchUser is created.
+	ElasticsearchRef commonv1.LocalObjectSelector `json:"elasticsearchRef"`
+
+	// Username is the name of the user in Elasticsearch. Defaults to the name of the ElasticsearchUser.
+	// It cannot be changed once the ElasticsearchUser is created.
+	// +kubebuilder:validation:Optional
+	Username string `json:"username,omitempty"`
+
+	// PasswordSecretRef is a reference to the Secret, in the same namespace, holding the password of the user.
+	PasswordSecretRef PasswordSecretRef `json:"passwordSecretRef"`
+
+	// Roles are the roles of the user.
+	// +kubebuilder:validation:Optional
+	Roles []string `json:"roles,omitempty"`
+
+	// FullName is the full name of the user.
+	// +kubebuilder:validation:Optional
+	FullName string `json:"fullName,omitempty"`
+
+	// Email is the email of the user.
+	// +kubebuilder:validation:Optional
+	Email string `json:"email,omitempty"`
+
+	// Metadata is arbitrary metadata attached to the user.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Metadata *commonv1.Config `json:"metadata,omitempty"`
+
+	// Enabled allows the user to authenticate. Defaults to true.
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// PasswordSecretRef is a reference to the key of a Secret holding a password.
+type PasswordSecretRef struct {
+	// SecretName is the name of the Secret.
+	SecretName string `json:"secretName"`
+	// Key is the key of the Secret holding the password. Defaults to "password".
+	// +kubebuilder:validation:Optional
+	Key string `json:"key,omitempty"`
+}
+
+// KeyOrDefault returns the key of the Secret holding the password.
+func (r PasswordSecretRef) KeyOrDefault() string {
+	if r.Key != "" {
+		return r.Key
+	}
+	return DefaultPasswordKey
+}
+
+type ElasticsearchUserStatus struct {
+	// Phase is the phase of the ElasticsearchUser.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the user is not created yet, or why its creation failed.
+	Message string `json:"message,omitempty"`
+	// Username is the name of the user in Elasticsearch.
+	Username string `json:"username,omitempty"`
+	// ElasticsearchName is the name of the Elasticsearch cluster the user was last created or updated in.
+	ElasticsearchName string `json:"elasticsearchName,omitempty"`
+	// Hash is the hash of the user and of the version of its password Secret when the user was last updated in Elasticsearch. A different
+	// hash means the user must be updated.
+	Hash string `json:"hash,omitempty"`
+	// LastDriftTime is the last time the user was found modified outside of the operator and restored.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this ElasticsearchUser.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// Phase is the phase of a resource of the security API group.
+type Phase = commonv1.APIResourcePhase
+
+const (
+	ReadyPhase           = commonv1.APIResourceReadyPhase
+	ApplyingChangesPhase = commonv1.APIResourceApplyingChangesPhase
+	ErrorPhase           = commonv1.APIResourceErrorPhase
+	InvalidPhase         = commonv1.APIResourceInvalidPhase
+)
+
+// UsernameOrDefault returns the name of the user in Elasticsearch.
+func (u *ElasticsearchUser) UsernameOrDefault() string {
+	if u.Spec.Username != "" {
+		return u.Spec.Username
+	}
+	return u.Name
+}
+
+// EnabledOrDefault returns true if the user is allowed to authenticate.
+func (u *ElasticsearchUser) EnabledOrDefault() bool {
+	return u.Spec.Enabled == nil || *u.Spec.Enabled
+}
+
+// References returns true if the user is created in the given Elasticsearch cluster.
+func (u *ElasticsearchUser) References(es types.NamespacedName) bool {
+	return u.Spec.ElasticsearchRef.WithDefaultNamespace(u.Namespace).NamespacedName() == es
+}
+
+// IsMarkedForDeletion returns true if the ElasticsearchUser resource is going to be deleted.
+func (u *ElasticsearchUser) IsMarkedForDeletion() bool {
+	return !u.DeletionTimestamp.IsZero()
+}
+
+// IsDegraded returns true when the ElasticsearchUserStatus is degraded compared to the previous status.
+func (s ElasticsearchUserStatus) IsDegraded(prev ElasticsearchUserStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
@@ -0,0 +1,157 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// userWebhookPath is the HTTP path for the ElasticsearchUser validating webhook.
+	userWebhookPath = "/validate-security-k8s-elastic-co-v1alpha1-elasticsearchusers"
+
+	// operatorUsernamePrefix is the prefix of the users the operator creates to manage the cluster.
+	operatorUsernamePrefix = "elastic-internal"
+
+	reservedUsernameErrMsg        = "the username is reserved by Elasticsearch or by the operator"
+	usernameChangeErrMsg          = "the username cannot be changed"
+	userElasticsearchChangeErrMsg = "the Elasticsearch cluster of the user cannot be changed"
+)
+
+var (
+	userGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: UserKind}
+
+	// reservedUsernames are the built-in users of Elasticsearch, which cannot be managed through the security API.
+	reservedUsernames = []string{
+		"elastic", "kibana", "kibana_system", "logstash_system", "beats_system", "apm_system", "remote_monitoring_user",
+	}
+
+	userDefaultChecks = []func(*ElasticsearchUser) field.ErrorList{
+		checkUserNoUnknownFields,
+		checkUserNameLength,
+		validUserElasticsearchRef,
+		validUsername,
+		validPasswordSecretRef,
+	}
+
+	userUpdateChecks = []func(old, curr *ElasticsearchUser) field.ErrorList{
+		checkUsernameChange,
+		checkUserElasticsearchRefChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-security-k8s-elastic-co-v1alpha1-elasticsearchusers,mutating=false,failurePolicy=ignore,groups=security.k8s.elastic.co,resources=elasticsearchusers,verbs=create;update,versions=v1alpha1,name=elastic-elasticsearchuser-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &ElasticsearchUser{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (u *ElasticsearchUser) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", u.Name)
+	return u.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (u *ElasticsearchUser) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", u.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (u *ElasticsearchUser) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", u.Name)
+	oldObj, ok := old.(*ElasticsearchUser)
+	if !ok {
+		return nil, errors.New("cannot cast old object to ElasticsearchUser type")
+	}
+	return u.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (u *ElasticsearchUser) WebhookPath() string {
+	return userWebhookPath
+}
+
+func (u *ElasticsearchUser) validate(old *ElasticsearchUser) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range userDefaultChecks {
+		if err := dc(u); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range userUpdateChecks {
+			if err := uc(old, u); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(userGroupKind, u.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkUserNoUnknownFields(u *ElasticsearchUser) field.ErrorList {
+	return commonv1.NoUnknownFields(u, u.ObjectMeta)
+}
+
+func checkUserNameLength(u *ElasticsearchUser) field.ErrorList {
+	return commonv1.CheckNameLength(u)
+}
+
+func validUserElasticsearchRef(u *ElasticsearchUser) field.ErrorList {
+	return validateElasticsearchRef(field.NewPath("spec").Child("elasticsearchRef"), u.Spec.ElasticsearchRef, u.Namespace)
+}
+
+func validUsername(u *ElasticsearchUser) field.ErrorList {
+	username := u.UsernameOrDefault()
+	if strings.HasPrefix(username, operatorUsernamePrefix) {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("username"), reservedUsernameErrMsg)}
+	}
+	for _, reserved := range reservedUsernames {
+		if username == reserved {
+			return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("username"), reservedUsernameErrMsg)}
+		}
+	}
+	return nil
+}
+
+func validPasswordSecretRef(u *ElasticsearchUser) field.ErrorList {
+	if u.Spec.PasswordSecretRef.SecretName == "" {
+		return field.ErrorList{field.Required(field.NewPath("spec").Child("passwordSecretRef").Child("secretName"), "password Secret name is mandatory")}
+	}
+	return nil
+}
+
+func checkUsernameChange(old, curr *ElasticsearchUser) field.ErrorList {
+	if old.UsernameOrDefault() != curr.UsernameOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("username"), usernameChangeErrMsg)}
+	}
+	return nil
+}
+
+func checkUserElasticsearchRefChange(old, curr *ElasticsearchUser) field.ErrorList {
+	if old.Spec.ElasticsearchRef.Name != curr.Spec.ElasticsearchRef.Name {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("elasticsearchRef"), userElasticsearchChangeErrMsg)}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestElasticsearchUserWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchUser(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-elasticsearch-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				u := mkElasticsearchUser(uid)
+				u.Spec.ElasticsearchRef = commonv1.LocalObjectSelector{Namespace: "other", Name: "es"}
+				return serialize(t, u)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef.namespace: Invalid value: "other": Elasticsearch clusters must be in the same namespace as the resource`,
+			),
+		},
+		{
+			Name:      "reserved-username",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				u := mkElasticsearchUser(uid)
+				u.Spec.Username = "elastic"
+				return serialize(t, u)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.username: Forbidden: the username is reserved by Elasticsearch or by the operator`,
+			),
+		},
+		{
+			Name:      "operator-username",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				u := mkElasticsearchUser(uid)
+				u.Spec.Username = "elastic-internal-monitoring"
+				return serialize(t, u)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.username: Forbidden: the username is reserved by Elasticsearch or by the operator`,
+			),
+		},
+		{
+			Name:      "no-password-secret",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				u := mkElasticsearchUser(uid)
+				u.Spec.PasswordSecretRef = securityv1alpha1.PasswordSecretRef{}
+				return serialize(t, u)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.passwordSecretRef.secretName: Required value: password Secret name is mandatory`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchUser(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				u := mkElasticsearchUser(uid)
+				u.Spec.Username = u.Name
+				u.Spec.Roles = append(u.Spec.Roles, "monitoring_user")
+				return serialize(t, u)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-username-and-elasticsearch",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchUser(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				u := mkElasticsearchUser(uid)
+				u.Spec.Username = "other-user"
+				u.Spec.ElasticsearchRef = commonv1.LocalObjectSelector{Name: "es2"}
+				return serialize(t, u)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.username: Forbidden: the username cannot be changed`,
+				`spec.elasticsearchRef: Forbidden: the Elasticsearch cluster of the user cannot be changed`,
+			),
+		},
+	}
+
+	validator := &securityv1alpha1.ElasticsearchUser{}
+	gvk := metav1.GroupVersionKind{Group: securityv1alpha1.GroupVersion.Group, Version: securityv1alpha1.GroupVersion.Version, Kind: securityv1alpha1.UserKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkElasticsearchUser(uid string) *securityv1alpha1.ElasticsearchUser {
+	return &securityv1alpha1.ElasticsearchUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "user-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: securityv1alpha1.ElasticsearchUserSpec{
+			ElasticsearchRef:  commonv1.LocalObjectSelector{Name: "es"},
+			PasswordSecretRef: securityv1alpha1.PasswordSecretRef{SecretName: "user-test-password"},
+			Roles:             []string{"viewer"},
+		},
+	}
+}
+
+func serialize(t *testing.T, obj interface{}) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(obj)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	crossNamespaceRefErrMsg       = "Elasticsearch clusters must be in the same namespace as the resource"
+	serviceNameNotSupportedErrMsg = "a custom service is not supported to reach Elasticsearch"
+	noElasticsearchErrMsg         = "at least one Elasticsearch cluster must be referenced or selected"
+)
+
+var validationLog = ulog.Log.WithName("security-v1alpha1-validation")
+
+// validateElasticsearchRef validates a reference to an Elasticsearch cluster, which must be in the given namespace.
+func validateElasticsearchRef(path *field.Path, ref commonv1.LocalObjectSelector, namespace string) field.ErrorList {
+	switch {
+	case ref.Name == "":
+		return field.ErrorList{field.Required(path.Child("name"), "Elasticsearch name is mandatory")}
+	case ref.Namespace != "" && ref.Namespace != namespace:
+		return field.ErrorList{field.Invalid(path.Child("namespace"), ref.Namespace, crossNamespaceRefErrMsg)}
+	case ref.ServiceName != "":
+		return field.ErrorList{field.Forbidden(path.Child("serviceName"), serviceNameNotSupportedErrMsg)}
+	}
+	return nil
+}
+
+// validateElasticsearchRefs validates references to Elasticsearch clusters, which must be distinct and in the given namespace.
+func validateElasticsearchRefs(path *field.Path, refs []commonv1.LocalObjectSelector, namespace string) field.ErrorList {
+	var errs field.ErrorList
+	names := set.Make()
+	for i, ref := range refs {
+		if err := validateElasticsearchRef(path.Index(i), ref, namespace); err != nil {
+			errs = append(errs, err...)
+			continue
+		}
+		if names.Has(ref.Name) {
+			errs = append(errs, field.Duplicate(path.Index(i).Child("name"), ref.Name))
+		}
+		names.Add(ref.Name)
+	}
+	return errs
+}
+
+// validateElasticsearchClusters validates the Elasticsearch clusters of a resource, referenced in spec.elasticsearchRefs
+// or selected by spec.elasticsearchSelector, of which at least one must be set.
+func validateElasticsearchClusters(refs []commonv1.LocalObjectSelector, selector *metav1.LabelSelector, namespace string) field.ErrorList {
+	path := field.NewPath("spec")
+	if len(refs) == 0 && selector == nil {
+		return field.ErrorList{field.Required(path.Child("elasticsearchRefs"), noElasticsearchErrMsg)}
+	}
+	errs := validateElasticsearchRefs(path.Child("elasticsearchRefs"), refs, namespace)
+	if selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("elasticsearchSelector"), selector, err.Error()))
+		}
+	}
+	return errs
+}
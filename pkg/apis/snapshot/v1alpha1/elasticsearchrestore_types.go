@@ -0,0 +1,136 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// RestoreKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	RestoreKind = "ElasticsearchRestore"
+)
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchRestore{}, &ElasticsearchRestoreList{})
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchRestore represents the restore of a snapshot into an Elasticsearch cluster.
+// +kubebuilder:resource:categories=elastic,shortName=esrestore
+// +kubebuilder:printcolumn:name="Elasticsearch",type="string",JSONPath=".spec.elasticsearchRef.name"
+// +kubebuilder:printcolumn:name="Snapshot",type="string",JSONPath=".status.snapshot"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type ElasticsearchRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchRestoreSpec   `json:"spec,omitempty"`
+	Status ElasticsearchRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchRestoreList contains a list of ElasticsearchRestore resources.
+type ElasticsearchRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchRestore `json:"items"`
+}
+
+// ElasticsearchRestoreSpec holds the options of the restore. It cannot be changed once the restore is created.
+type ElasticsearchRestoreSpec struct {
+	// ElasticsearchRef is a reference to the Elasticsearch cluster the snapshot is restored into.
+	// The cluster must be in the same namespace as the ElasticsearchRestore.
+	ElasticsearchRef commonv1.LocalObjectSelector `json:"elasticsearchRef"`
+
+	// RepositoryRef is a reference to the SnapshotRepository, in the same namespace, the snapshot is stored in.
+	// The repository must be registered on the referenced Elasticsearch cluster.
+	RepositoryRef RepositoryReference `json:"repositoryRef"`
+
+	// Snapshot is the name of the snapshot to restore. It can be a pattern with wildcards, in which case the most
+	// recent successful snapshot matching the pattern is restored.
+	Snapshot string `json:"snapshot"`
+
+	// Indices are the indices and data streams to restore, supporting wildcards. Defaults to all the indices and
+	// data streams of the snapshot.
+	// +kubebuilder:validation:Optional
+	Indices []string `json:"indices,omitempty"`
+
+	// RenamePattern is a regular expression matched against the names of the restored indices and data streams.
+	// Matching names are renamed according to RenameReplacement.
+	// +kubebuilder:validation:Optional
+	RenamePattern string `json:"renamePattern,omitempty"`
+
+	// RenameReplacement is the replacement of the names matching RenamePattern, for example "restored-$1".
+	// +kubebuilder:validation:Optional
+	RenameReplacement string `json:"renameReplacement,omitempty"`
+
+	// IncludeGlobalState restores the cluster state of the snapshot, including templates, pipelines and
+	// persistent settings. Defaults to false.
+	// +kubebuilder:validation:Optional
+	IncludeGlobalState bool `json:"includeGlobalState,omitempty"`
+}
+
+// RestorePhase is the phase of an ElasticsearchRestore.
+type RestorePhase string
+
+const (
+	// RestorePendingPhase is the phase of a restore waiting for its prerequisites before being started.
+	RestorePendingPhase RestorePhase = "Pending"
+	// RestoreInProgressPhase is the phase of a restore started in Elasticsearch.
+	RestoreInProgressPhase RestorePhase = "InProgress"
+	// RestoreCompletedPhase is the phase of a restore that completed.
+	RestoreCompletedPhase RestorePhase = "Completed"
+	// RestoreFailedPhase is the phase of a restore Elasticsearch refused to start.
+	RestoreFailedPhase RestorePhase = "Failed"
+	// RestoreInvalidPhase is the phase of a restore with an invalid specification.
+	RestoreInvalidPhase RestorePhase = "Invalid"
+)
+
+type ElasticsearchRestoreStatus struct {
+	// Phase is the phase of the ElasticsearchRestore.
+	Phase RestorePhase `json:"phase,omitempty"`
+	// Message explains why the restore is not started yet, or why it failed.
+	Message string `json:"message,omitempty"`
+	// Snapshot is the name of the restored snapshot, resolved from the snapshot pattern of the specification.
+	Snapshot string `json:"snapshot,omitempty"`
+	// StartTime is the time at which the restore started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time at which the restore was observed as completed.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this ElasticsearchRestore.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// References returns true if the snapshot is restored into the given Elasticsearch cluster.
+func (r *ElasticsearchRestore) References(es types.NamespacedName) bool {
+	return r.Spec.ElasticsearchRef.WithDefaultNamespace(r.Namespace).NamespacedName() == es
+}
+
+// IsSnapshotPattern returns true if the snapshot to restore is a pattern to resolve.
+func (r *ElasticsearchRestore) IsSnapshotPattern() bool {
+	return strings.Contains(r.Spec.Snapshot, "*")
+}
+
+// IsMarkedForDeletion returns true if the ElasticsearchRestore resource is going to be deleted.
+func (r *ElasticsearchRestore) IsMarkedForDeletion() bool {
+	return !r.DeletionTimestamp.IsZero()
+}
+
+// IsDone returns true if the restore reached a final phase, after which it is not reconciled anymore.
+func (s ElasticsearchRestoreStatus) IsDone() bool {
+	return s.Phase == RestoreCompletedPhase || s.Phase == RestoreFailedPhase
+}
@@ -0,0 +1,144 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// restoreWebhookPath is the HTTP path for the ElasticsearchRestore validating webhook.
+	restoreWebhookPath = "/validate-snapshot-k8s-elastic-co-v1alpha1-elasticsearchrestores"
+
+	restoreSpecChangeErrMsg = "the restore specification cannot be changed, create a new ElasticsearchRestore instead"
+)
+
+var (
+	restoreGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: RestoreKind}
+
+	restoreDefaultChecks = []func(*ElasticsearchRestore) field.ErrorList{
+		checkRestoreNoUnknownFields,
+		checkRestoreNameLength,
+		validRestoreElasticsearchRef,
+		validRestoreRepositoryRef,
+		validRestoreSnapshot,
+		validRestoreRename,
+	}
+
+	restoreUpdateChecks = []func(old, curr *ElasticsearchRestore) field.ErrorList{
+		checkRestoreSpecChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-snapshot-k8s-elastic-co-v1alpha1-elasticsearchrestores,mutating=false,failurePolicy=ignore,groups=snapshot.k8s.elastic.co,resources=elasticsearchrestores,verbs=create;update,versions=v1alpha1,name=elastic-elasticsearchrestore-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &ElasticsearchRestore{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (r *ElasticsearchRestore) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", r.Name)
+	return r.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (r *ElasticsearchRestore) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", r.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (r *ElasticsearchRestore) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", r.Name)
+	oldObj, ok := old.(*ElasticsearchRestore)
+	if !ok {
+		return nil, errors.New("cannot cast old object to ElasticsearchRestore type")
+	}
+	return r.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (r *ElasticsearchRestore) WebhookPath() string {
+	return restoreWebhookPath
+}
+
+func (r *ElasticsearchRestore) validate(old *ElasticsearchRestore) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range restoreDefaultChecks {
+		if err := dc(r); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range restoreUpdateChecks {
+			if err := uc(old, r); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(restoreGroupKind, r.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkRestoreNoUnknownFields(r *ElasticsearchRestore) field.ErrorList {
+	return commonv1.NoUnknownFields(r, r.ObjectMeta)
+}
+
+func checkRestoreNameLength(r *ElasticsearchRestore) field.ErrorList {
+	return commonv1.CheckNameLength(r)
+}
+
+func validRestoreElasticsearchRef(r *ElasticsearchRestore) field.ErrorList {
+	return validateElasticsearchRef(field.NewPath("spec").Child("elasticsearchRef"), r.Spec.ElasticsearchRef, r.Namespace)
+}
+
+func validRestoreRepositoryRef(r *ElasticsearchRestore) field.ErrorList {
+	if r.Spec.RepositoryRef.Name == "" {
+		return field.ErrorList{field.Required(field.NewPath("spec").Child("repositoryRef").Child("name"), "SnapshotRepository name is mandatory")}
+	}
+	return nil
+}
+
+func validRestoreSnapshot(r *ElasticsearchRestore) field.ErrorList {
+	if r.Spec.Snapshot == "" {
+		return field.ErrorList{field.Required(field.NewPath("spec").Child("snapshot"), "snapshot name or pattern is mandatory")}
+	}
+	return nil
+}
+
+func validRestoreRename(r *ElasticsearchRestore) field.ErrorList {
+	switch {
+	case r.Spec.RenamePattern != "" && r.Spec.RenameReplacement == "":
+		return field.ErrorList{field.Required(field.NewPath("spec").Child("renameReplacement"), "renameReplacement is mandatory when renamePattern is set")}
+	case r.Spec.RenamePattern == "" && r.Spec.RenameReplacement != "":
+		return field.ErrorList{field.Required(field.NewPath("spec").Child("renamePattern"), "renamePattern is mandatory when renameReplacement is set")}
+	}
+	return nil
+}
+
+func checkRestoreSpecChange(old, curr *ElasticsearchRestore) field.ErrorList {
+	if !equality.Semantic.DeepEqual(old.Spec, curr.Spec) {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec"), restoreSpecChangeErrMsg)}
+	}
+	return nil
+}
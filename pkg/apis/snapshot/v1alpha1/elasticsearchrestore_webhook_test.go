@@ -0,0 +1,147 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestElasticsearchRestoreWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serializeRestore(t, mkElasticsearchRestore(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "create-valid-with-rename",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRestore(uid)
+				r.Spec.Indices = []string{"logs-*"}
+				r.Spec.RenamePattern = "(.+)"
+				r.Spec.RenameReplacement = "restored-$1"
+				return serializeRestore(t, r)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "elasticsearch-in-another-namespace",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRestore(uid)
+				r.Spec.ElasticsearchRef = commonv1.LocalObjectSelector{Namespace: "other", Name: "es"}
+				return serializeRestore(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef.namespace: Invalid value: "other": Elasticsearch clusters must be in the same namespace as the resource`,
+			),
+		},
+		{
+			Name:      "no-repository-no-snapshot",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRestore(uid)
+				r.Spec.RepositoryRef.Name = ""
+				r.Spec.Snapshot = ""
+				return serializeRestore(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.repositoryRef.name: Required value: SnapshotRepository name is mandatory`,
+				`spec.snapshot: Required value: snapshot name or pattern is mandatory`,
+			),
+		},
+		{
+			Name:      "rename-pattern-without-replacement",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRestore(uid)
+				r.Spec.RenamePattern = "(.+)"
+				return serializeRestore(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.renameReplacement: Required value: renameReplacement is mandatory when renamePattern is set`,
+			),
+		},
+		{
+			Name:      "update-metadata",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serializeRestore(t, mkElasticsearchRestore(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRestore(uid)
+				r.Labels = map[string]string{"runbook": "disaster-recovery"}
+				return serializeRestore(t, r)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-spec",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serializeRestore(t, mkElasticsearchRestore(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkElasticsearchRestore(uid)
+				r.Spec.Snapshot = "other-snapshot"
+				return serializeRestore(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec: Forbidden: the restore specification cannot be changed, create a new ElasticsearchRestore instead`,
+			),
+		},
+	}
+
+	validator := &snapshotv1alpha1.ElasticsearchRestore{}
+	gvk := metav1.GroupVersionKind{Group: snapshotv1alpha1.GroupVersion.Group, Version: snapshotv1alpha1.GroupVersion.Version, Kind: snapshotv1alpha1.RestoreKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkElasticsearchRestore(uid string) *snapshotv1alpha1.ElasticsearchRestore {
+	return &snapshotv1alpha1.ElasticsearchRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "elasticsearch-restore-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: snapshotv1alpha1.ElasticsearchRestoreSpec{
+			ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es"},
+			RepositoryRef:    snapshotv1alpha1.RepositoryReference{Name: "backups"},
+			Snapshot:         "nightly-*",
+		},
+	}
+}
+
+func serializeRestore(t *testing.T, restore *snapshotv1alpha1.ElasticsearchRestore) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(restore)
+	require.NoError(t, err)
+
+	return objBytes
+}
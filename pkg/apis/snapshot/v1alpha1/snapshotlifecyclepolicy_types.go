@@ -0,0 +1,212 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// PolicyKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	PolicyKind = "SnapshotLifecyclePolicy"
+)
+
+func init() {
+	SchemeBuilder.Register(&SnapshotLifecyclePolicy{}, &SnapshotLifecyclePolicyList{})
+}
+
+// +kubebuilder:object:root=true
+
+// SnapshotLifecyclePolicy represents a snapshot lifecycle management (SLM) policy configured on Elasticsearch clusters.
+// +kubebuilder:resource:categories=elastic,shortName=esslm
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Repository",type="string",JSONPath=".spec.repositoryRef.name"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.readyCount",description="Elasticsearch clusters configured"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type SnapshotLifecyclePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SnapshotLifecyclePolicySpec   `json:"spec,omitempty"`
+	Status SnapshotLifecyclePolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SnapshotLifecyclePolicyList contains a list of SnapshotLifecyclePolicy resources.
+type SnapshotLifecyclePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SnapshotLifecyclePolicy `json:"items"`
+}
+
+type SnapshotLifecyclePolicySpec struct {
+	// ElasticsearchRefs are references to the Elasticsearch clusters the policy is configured on.
+	// The clusters must be in the same namespace as the SnapshotLifecyclePolicy.
+	// +kubebuilder:validation:MinItems=1
+	ElasticsearchRefs []commonv1.LocalObjectSelector `json:"elasticsearchRefs"`
+
+	// PolicyName is the id of the policy in Elasticsearch. Defaults to the name of the SnapshotLifecyclePolicy.
+	// It cannot be changed once the SnapshotLifecyclePolicy is created.
+	// +kubebuilder:validation:Optional
+	PolicyName string `json:"policyName,omitempty"`
+
+	// Schedule is the periodic or absolute schedule at which the policy takes snapshots, as a cron expression.
+	// For example "0 30 1 * * ?" to take a snapshot every day at 1:30 AM UTC.
+	Schedule string `json:"schedule"`
+
+	// SnapshotName is the name of the snapshots taken by the policy, supporting date math.
+	// Defaults to "<policy-name-{now/d}>".
+	// +kubebuilder:validation:Optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+
+	// RepositoryRef is a reference to the SnapshotRepository, in the same namespace, snapshots are stored in.
+	// The repository must be registered on all the referenced Elasticsearch clusters.
+	RepositoryRef RepositoryReference `json:"repositoryRef"`
+
+	// Config is the configuration of the snapshots taken by the policy, for example the indices to include.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Config *commonv1.Config `json:"config,omitempty"`
+
+	// Retention is the retention of the snapshots taken by the policy.
+	// +kubebuilder:validation:Optional
+	Retention *SnapshotRetention `json:"retention,omitempty"`
+}
+
+// RepositoryReference is a reference to a SnapshotRepository.
+type RepositoryReference struct {
+	// Name of the SnapshotRepository.
+	Name string `json:"name"`
+}
+
+// SnapshotRetention holds the retention rules of the snapshots taken by a policy.
+type SnapshotRetention struct {
+	// ExpireAfter is the time period after which snapshots are deleted, for example "30d".
+	// +kubebuilder:validation:Optional
+	ExpireAfter string `json:"expireAfter,omitempty"`
+	// MinCount is the minimum number of snapshots to retain, even if they are expired.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MinCount *int32 `json:"minCount,omitempty"`
+	// MaxCount is the maximum number of snapshots to retain, even if they are not expired.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxCount *int32 `json:"maxCount,omitempty"`
+}
+
+type SnapshotLifecyclePolicyStatus struct {
+	// Details holds the status of the policy for each Elasticsearch cluster, indexed by cluster name.
+	Details map[string]ElasticsearchPolicyStatus `json:"details,omitempty"`
+	// Resources is the number of Elasticsearch clusters the policy is configured on.
+	Resources int `json:"resources,omitempty"`
+	// Ready is the number of Elasticsearch clusters on which the policy is successfully configured.
+	Ready int `json:"ready,omitempty"`
+	// ReadyCount is a human representation of the number of clusters on which the policy is successfully configured.
+	ReadyCount string `json:"readyCount,omitempty"`
+	// Phase is the phase of the SnapshotLifecyclePolicy.
+	Phase Phase `json:"phase,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this SnapshotLifecyclePolicy.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ElasticsearchPolicyStatus models the status of the policy for one Elasticsearch cluster.
+type ElasticsearchPolicyStatus struct {
+	// Phase is the phase of the policy on the Elasticsearch cluster.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the policy is not configured yet, or why its configuration failed.
+	Message string `json:"message,omitempty"`
+	// LastSuccess is the last snapshot successfully taken by the policy.
+	LastSuccess *SnapshotInvocation `json:"lastSuccess,omitempty"`
+	// LastFailure is the last snapshot the policy failed to take.
+	LastFailure *SnapshotInvocation `json:"lastFailure,omitempty"`
+	// NextExecution is the time at which the policy takes the next snapshot.
+	NextExecution *metav1.Time `json:"nextExecution,omitempty"`
+}
+
+// SnapshotInvocation is an execution of a policy.
+type SnapshotInvocation struct {
+	// SnapshotName is the name of the snapshot.
+	SnapshotName string `json:"snapshotName"`
+	// Time is the time of the execution.
+	Time metav1.Time `json:"time"`
+	// Details holds the reason of a failed execution.
+	Details string `json:"details,omitempty"`
+}
+
+// PolicyNameOrDefault returns the id of the policy in Elasticsearch.
+func (p *SnapshotLifecyclePolicy) PolicyNameOrDefault() string {
+	if p.Spec.PolicyName != "" {
+		return p.Spec.PolicyName
+	}
+	return p.Name
+}
+
+// SnapshotNameOrDefault returns the name of the snapshots taken by the policy.
+func (p *SnapshotLifecyclePolicy) SnapshotNameOrDefault() string {
+	if p.Spec.SnapshotName != "" {
+		return p.Spec.SnapshotName
+	}
+	return fmt.Sprintf("<%s-{now/d}>", p.PolicyNameOrDefault())
+}
+
+// References returns true if the policy is configured on the given Elasticsearch cluster.
+func (p *SnapshotLifecyclePolicy) References(es types.NamespacedName) bool {
+	for _, ref := range p.Spec.ElasticsearchRefs {
+		if ref.WithDefaultNamespace(p.Namespace).NamespacedName() == es {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMarkedForDeletion returns true if the SnapshotLifecyclePolicy resource is going to be deleted.
+func (p *SnapshotLifecyclePolicy) IsMarkedForDeletion() bool {
+	return !p.DeletionTimestamp.IsZero()
+}
+
+func NewPolicyStatus(policy SnapshotLifecyclePolicy) SnapshotLifecyclePolicyStatus {
+	status := SnapshotLifecyclePolicyStatus{
+		Details:            map[string]ElasticsearchPolicyStatus{},
+		Phase:              ReadyPhase,
+		ObservedGeneration: policy.Generation,
+	}
+	status.setReadyCount()
+	return status
+}
+
+func (s *SnapshotLifecyclePolicyStatus) setReadyCount() {
+	s.ReadyCount = fmt.Sprintf("%d/%d", s.Ready, s.Resources)
+}
+
+// SetElasticsearchStatus sets the status of the policy for the given Elasticsearch cluster.
+func (s *SnapshotLifecyclePolicyStatus) SetElasticsearchStatus(esName string, status ElasticsearchPolicyStatus) {
+	if s.Details == nil {
+		s.Details = map[string]ElasticsearchPolicyStatus{}
+	}
+	s.Details[esName] = status
+	s.Update()
+}
+
+// Update updates the policy status from its clusters statuses.
+func (s *SnapshotLifecyclePolicyStatus) Update() {
+	phaseOf := func(status ElasticsearchPolicyStatus) Phase { return status.Phase }
+	s.Resources, s.Ready, s.Phase = commonv1.SummarizePhases(s.Details, phaseOf, s.Phase)
+	s.setReadyCount()
+}
+
+// IsDegraded returns true when the SnapshotLifecyclePolicyStatus is degraded compared to the previous status.
+func (s SnapshotLifecyclePolicyStatus) IsDegraded(prev SnapshotLifecyclePolicyStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
@@ -0,0 +1,132 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// policyWebhookPath is the HTTP path for the SnapshotLifecyclePolicy validating webhook.
+	policyWebhookPath = "/validate-snapshot-k8s-elastic-co-v1alpha1-snapshotlifecyclepolicies"
+
+	policyNameChangeErrMsg = "the policy name cannot be changed"
+)
+
+var (
+	policyGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: PolicyKind}
+
+	policyDefaultChecks = []func(*SnapshotLifecyclePolicy) field.ErrorList{
+		checkPolicyNoUnknownFields,
+		checkPolicyNameLength,
+		validPolicyElasticsearchRefs,
+		validPolicySchedule,
+		validRepositoryRef,
+	}
+
+	policyUpdateChecks = []func(old, curr *SnapshotLifecyclePolicy) field.ErrorList{
+		checkPolicyNameChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-snapshot-k8s-elastic-co-v1alpha1-snapshotlifecyclepolicies,mutating=false,failurePolicy=ignore,groups=snapshot.k8s.elastic.co,resources=snapshotlifecyclepolicies,verbs=create;update,versions=v1alpha1,name=elastic-snapshotlifecyclepolicy-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &SnapshotLifecyclePolicy{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (p *SnapshotLifecyclePolicy) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", p.Name)
+	return p.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (p *SnapshotLifecyclePolicy) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", p.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (p *SnapshotLifecyclePolicy) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", p.Name)
+	oldObj, ok := old.(*SnapshotLifecyclePolicy)
+	if !ok {
+		return nil, errors.New("cannot cast old object to SnapshotLifecyclePolicy type")
+	}
+	return p.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (p *SnapshotLifecyclePolicy) WebhookPath() string {
+	return policyWebhookPath
+}
+
+func (p *SnapshotLifecyclePolicy) validate(old *SnapshotLifecyclePolicy) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range policyDefaultChecks {
+		if err := dc(p); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range policyUpdateChecks {
+			if err := uc(old, p); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(policyGroupKind, p.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkPolicyNoUnknownFields(p *SnapshotLifecyclePolicy) field.ErrorList {
+	return commonv1.NoUnknownFields(p, p.ObjectMeta)
+}
+
+func checkPolicyNameLength(p *SnapshotLifecyclePolicy) field.ErrorList {
+	return commonv1.CheckNameLength(p)
+}
+
+func validPolicyElasticsearchRefs(p *SnapshotLifecyclePolicy) field.ErrorList {
+	return validateElasticsearchRefs(p.Spec.ElasticsearchRefs, p.Namespace)
+}
+
+func validPolicySchedule(p *SnapshotLifecyclePolicy) field.ErrorList {
+	if p.Spec.Schedule == "" {
+		return field.ErrorList{field.Required(field.NewPath("spec").Child("schedule"), "schedule is mandatory")}
+	}
+	return nil
+}
+
+func validRepositoryRef(p *SnapshotLifecyclePolicy) field.ErrorList {
+	if p.Spec.RepositoryRef.Name == "" {
+		return field.ErrorList{field.Required(field.NewPath("spec").Child("repositoryRef").Child("name"), "SnapshotRepository name is mandatory")}
+	}
+	return nil
+}
+
+func checkPolicyNameChange(old, curr *SnapshotLifecyclePolicy) field.ErrorList {
+	if old.PolicyNameOrDefault() != curr.PolicyNameOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("policyName"), policyNameChangeErrMsg)}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestSnapshotLifecyclePolicyWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serializePolicy(t, mkSnapshotLifecyclePolicy(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "elasticsearch-in-another-namespace",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkSnapshotLifecyclePolicy(uid)
+				p.Spec.ElasticsearchRefs = []commonv1.LocalObjectSelector{{Namespace: "other", Name: "es"}}
+				return serializePolicy(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRefs\[0\].namespace: Invalid value: "other": Elasticsearch clusters must be in the same namespace as the resource`,
+			),
+		},
+		{
+			Name:      "no-schedule-no-repository",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkSnapshotLifecyclePolicy(uid)
+				p.Spec.Schedule = ""
+				p.Spec.RepositoryRef.Name = ""
+				return serializePolicy(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.schedule: Required value: schedule is mandatory`,
+				`spec.repositoryRef.name: Required value: SnapshotRepository name is mandatory`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serializePolicy(t, mkSnapshotLifecyclePolicy(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkSnapshotLifecyclePolicy(uid)
+				p.Spec.PolicyName = p.Name
+				p.Spec.Schedule = "0 0 * * * ?"
+				return serializePolicy(t, p)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-policy-name",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serializePolicy(t, mkSnapshotLifecyclePolicy(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				p := mkSnapshotLifecyclePolicy(uid)
+				p.Spec.PolicyName = "other-policy"
+				return serializePolicy(t, p)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.policyName: Forbidden: the policy name cannot be changed`,
+			),
+		},
+	}
+
+	validator := &snapshotv1alpha1.SnapshotLifecyclePolicy{}
+	gvk := metav1.GroupVersionKind{Group: snapshotv1alpha1.GroupVersion.Group, Version: snapshotv1alpha1.GroupVersion.Version, Kind: snapshotv1alpha1.PolicyKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkSnapshotLifecyclePolicy(uid string) *snapshotv1alpha1.SnapshotLifecyclePolicy {
+	return &snapshotv1alpha1.SnapshotLifecyclePolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "snapshot-lifecycle-policy-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: snapshotv1alpha1.SnapshotLifecyclePolicySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			Schedule:          "0 30 1 * * ?",
+			RepositoryRef:     snapshotv1alpha1.RepositoryReference{Name: "backups"},
+		},
+	}
+}
+
+func serializePolicy(t *testing.T, policy *snapshotv1alpha1.SnapshotLifecyclePolicy) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(policy)
+	require.NoError(t, err)
+
+	return objBytes
+}
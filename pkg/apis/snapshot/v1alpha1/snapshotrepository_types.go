@@ -0,0 +1,218 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	Kind = "SnapshotRepository"
+)
+
+func init() {
+	SchemeBuilder.Register(&SnapshotRepository{}, &SnapshotRepositoryList{})
+}
+
+// +kubebuilder:object:root=true
+
+// SnapshotRepository represents a snapshot repository registered on Elasticsearch clusters.
+// +kubebuilder:resource:categories=elastic,shortName=esrepo
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.readyCount",description="Elasticsearch clusters configured"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type SnapshotRepository struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SnapshotRepositorySpec   `json:"spec,omitempty"`
+	Status SnapshotRepositoryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SnapshotRepositoryList contains a list of SnapshotRepository resources.
+type SnapshotRepositoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SnapshotRepository `json:"items"`
+}
+
+// RepositoryType is the type of a snapshot repository.
+type RepositoryType string
+
+const (
+	S3RepositoryType    RepositoryType = "s3"
+	GCSRepositoryType   RepositoryType = "gcs"
+	AzureRepositoryType RepositoryType = "azure"
+	HDFSRepositoryType  RepositoryType = "hdfs"
+	FSRepositoryType    RepositoryType = "fs"
+)
+
+type SnapshotRepositorySpec struct {
+	// ElasticsearchRefs are references to the Elasticsearch clusters the repository is registered on.
+	// The clusters must be in the same namespace as the SnapshotRepository.
+	// +kubebuilder:validation:MinItems=1
+	ElasticsearchRefs []commonv1.LocalObjectSelector `json:"elasticsearchRefs"`
+
+	// RepositoryName is the name of the repository in Elasticsearch. Defaults to the name of the SnapshotRepository.
+	// It cannot be changed once the SnapshotRepository is created.
+	// +kubebuilder:validation:Optional
+	RepositoryName string `json:"repositoryName,omitempty"`
+
+	// Type is the type of the repository.
+	// +kubebuilder:validation:Enum=s3;gcs;azure;hdfs;fs
+	Type RepositoryType `json:"type"`
+
+	// Settings are the settings of the repository, as expected by the Elasticsearch snapshot repository API.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Settings *commonv1.Config `json:"settings,omitempty"`
+
+	// SecureSettings are Secrets holding the client credentials of the repository, added to the keystore of the
+	// Elasticsearch clusters. Each key of the Secrets must be the name of a secure setting, for example
+	// s3.client.default.secret_key.
+	// +kubebuilder:validation:Optional
+	SecureSettings []commonv1.SecretSource `json:"secureSettings,omitempty"`
+
+	// Verify enables the verification of the repository on all the nodes of the clusters when it is registered.
+	// Defaults to true.
+	// +kubebuilder:validation:Optional
+	Verify *bool `json:"verify,omitempty"`
+
+	// WorkloadIdentity configures the Elasticsearch clusters to access the repository with the cloud identity bound
+	// to a Kubernetes service account, instead of static credentials. Supported for the s3 type on EKS, the gcs type
+	// on GKE and the azure type on AKS.
+	// +kubebuilder:validation:Optional
+	WorkloadIdentity *WorkloadIdentity `json:"workloadIdentity,omitempty"`
+}
+
+// WorkloadIdentity holds the cloud identity the Elasticsearch pods use to access a repository.
+type WorkloadIdentity struct {
+	// ServiceAccountName is the name of the Kubernetes service account, in the namespace of the repository, the
+	// Elasticsearch pods run with. It is created if it does not exist.
+	ServiceAccountName string `json:"serviceAccountName"`
+	// AWSRoleARN is the ARN of the AWS IAM role assumed to access an s3 repository.
+	// +kubebuilder:validation:Optional
+	AWSRoleARN string `json:"awsRoleARN,omitempty"`
+	// GCPServiceAccount is the email of the Google Cloud service account impersonated to access a gcs repository.
+	// +kubebuilder:validation:Optional
+	GCPServiceAccount string `json:"gcpServiceAccount,omitempty"`
+	// AzureClientID is the client ID of the Azure managed identity used to access an azure repository.
+	// +kubebuilder:validation:Optional
+	AzureClientID string `json:"azureClientID,omitempty"`
+	// AzureTenantID is the ID of the Azure tenant of the managed identity used to access an azure repository.
+	// +kubebuilder:validation:Optional
+	AzureTenantID string `json:"azureTenantID,omitempty"`
+}
+
+type SnapshotRepositoryStatus struct {
+	// Details holds the status of the repository for each Elasticsearch cluster, indexed by cluster name.
+	Details map[string]ElasticsearchRepositoryStatus `json:"details,omitempty"`
+	// Resources is the number of Elasticsearch clusters the repository is registered on.
+	Resources int `json:"resources,omitempty"`
+	// Ready is the number of Elasticsearch clusters on which the repository is successfully registered.
+	Ready int `json:"ready,omitempty"`
+	// ReadyCount is a human representation of the number of clusters on which the repository is successfully registered.
+	ReadyCount string `json:"readyCount,omitempty"`
+	// Phase is the phase of the SnapshotRepository.
+	Phase Phase `json:"phase,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this SnapshotRepository.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ElasticsearchRepositoryStatus models the status of the repository for one Elasticsearch cluster.
+type ElasticsearchRepositoryStatus struct {
+	// Phase is the phase of the repository on the Elasticsearch cluster.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the repository is not registered yet, or why its registration failed.
+	Message string `json:"message,omitempty"`
+	// SecureSettingsHash is the hash of the secure settings of the repository when it was last registered or verified
+	// on the Elasticsearch cluster. A different hash means the credentials were rotated and must be verified again.
+	SecureSettingsHash string `json:"secureSettingsHash,omitempty"`
+}
+
+// Phase is the phase of a SnapshotRepository or SnapshotLifecyclePolicy, overall or on one Elasticsearch cluster.
+type Phase = commonv1.APIResourcePhase
+
+const (
+	ReadyPhase           = commonv1.APIResourceReadyPhase
+	ApplyingChangesPhase = commonv1.APIResourceApplyingChangesPhase
+	ErrorPhase           = commonv1.APIResourceErrorPhase
+	InvalidPhase         = commonv1.APIResourceInvalidPhase
+)
+
+// RepositoryNameOrDefault returns the name of the repository in Elasticsearch.
+func (r *SnapshotRepository) RepositoryNameOrDefault() string {
+	if r.Spec.RepositoryName != "" {
+		return r.Spec.RepositoryName
+	}
+	return r.Name
+}
+
+// VerifyOrDefault returns true if the repository must be verified when it is registered.
+func (r *SnapshotRepository) VerifyOrDefault() bool {
+	return r.Spec.Verify == nil || *r.Spec.Verify
+}
+
+// References returns true if the repository is registered on the given Elasticsearch cluster.
+func (r *SnapshotRepository) References(es types.NamespacedName) bool {
+	for _, ref := range r.Spec.ElasticsearchRefs {
+		if ref.WithDefaultNamespace(r.Namespace).NamespacedName() == es {
+			return true
+		}
+	}
+	return false
+}
+
+func NewStatus(repository SnapshotRepository) SnapshotRepositoryStatus {
+	status := SnapshotRepositoryStatus{
+		Details:            map[string]ElasticsearchRepositoryStatus{},
+		Phase:              ReadyPhase,
+		ObservedGeneration: repository.Generation,
+	}
+	status.setReadyCount()
+	return status
+}
+
+func (s *SnapshotRepositoryStatus) setReadyCount() {
+	s.ReadyCount = fmt.Sprintf("%d/%d", s.Ready, s.Resources)
+}
+
+// SetElasticsearchStatus sets the status of the repository for the given Elasticsearch cluster.
+func (s *SnapshotRepositoryStatus) SetElasticsearchStatus(esName string, status ElasticsearchRepositoryStatus) {
+	if s.Details == nil {
+		s.Details = map[string]ElasticsearchRepositoryStatus{}
+	}
+	s.Details[esName] = status
+	s.Update()
+}
+
+// Update updates the repository status from its clusters statuses.
+func (s *SnapshotRepositoryStatus) Update() {
+	phaseOf := func(status ElasticsearchRepositoryStatus) Phase { return status.Phase }
+	s.Resources, s.Ready, s.Phase = commonv1.SummarizePhases(s.Details, phaseOf, s.Phase)
+	s.setReadyCount()
+}
+
+// IsDegraded returns true when the SnapshotRepositoryStatus is degraded compared to the previous status.
+func (s SnapshotRepositoryStatus) IsDegraded(prev SnapshotRepositoryStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
+
+// IsMarkedForDeletion returns true if the SnapshotRepository resource is going to be deleted.
+func (r *SnapshotRepository) IsMarkedForDeletion() bool {
+	return !r.DeletionTimestamp.IsZero()
+}
@@ -0,0 +1,194 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	// webhookPath is the HTTP path for the SnapshotRepository validating webhook.
+	webhookPath = "/validate-snapshot-k8s-elastic-co-v1alpha1-snapshotrepositories"
+
+	crossNamespaceRefErrMsg            = "Elasticsearch clusters must be in the same namespace as the resource"
+	repositoryNameChangeErrMsg         = "the repository name cannot be changed"
+	serviceNameNotSupportedErrMsg      = "a custom service is not supported to reach Elasticsearch"
+	workloadIdentityNotSupportedErrMsg = "workload identity is only supported for s3, gcs and azure repositories"
+)
+
+var (
+	groupKind     = schema.GroupKind{Group: GroupVersion.Group, Kind: Kind}
+	validationLog = ulog.Log.WithName("snapshot-v1alpha1-validation")
+
+	defaultChecks = []func(*SnapshotRepository) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		validElasticsearchRefs,
+		validWorkloadIdentity,
+		validSecureSettings,
+	}
+
+	updateChecks = []func(old, curr *SnapshotRepository) field.ErrorList{
+		checkRepositoryNameChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-snapshot-k8s-elastic-co-v1alpha1-snapshotrepositories,mutating=false,failurePolicy=ignore,groups=snapshot.k8s.elastic.co,resources=snapshotrepositories,verbs=create;update,versions=v1alpha1,name=elastic-snapshotrepository-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &SnapshotRepository{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (r *SnapshotRepository) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", r.Name)
+	return r.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (r *SnapshotRepository) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", r.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (r *SnapshotRepository) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", r.Name)
+	oldObj, ok := old.(*SnapshotRepository)
+	if !ok {
+		return nil, errors.New("cannot cast old object to SnapshotRepository type")
+	}
+	return r.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (r *SnapshotRepository) WebhookPath() string {
+	return webhookPath
+}
+
+func (r *SnapshotRepository) validate(old *SnapshotRepository) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range defaultChecks {
+		if err := dc(r); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range updateChecks {
+			if err := uc(old, r); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(groupKind, r.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkNoUnknownFields(r *SnapshotRepository) field.ErrorList {
+	return commonv1.NoUnknownFields(r, r.ObjectMeta)
+}
+
+func checkNameLength(r *SnapshotRepository) field.ErrorList {
+	return commonv1.CheckNameLength(r)
+}
+
+func validElasticsearchRefs(r *SnapshotRepository) field.ErrorList {
+	return validateElasticsearchRefs(r.Spec.ElasticsearchRefs, r.Namespace)
+}
+
+// validateElasticsearchRefs validates references to Elasticsearch clusters that must be in the given namespace.
+func validateElasticsearchRefs(refs []commonv1.LocalObjectSelector, namespace string) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("elasticsearchRefs")
+	if len(refs) == 0 {
+		return field.ErrorList{field.Required(path, "at least one Elasticsearch cluster must be referenced")}
+	}
+	names := set.Make()
+	for i, ref := range refs {
+		if refErrs := validateElasticsearchRef(path.Index(i), ref, namespace); len(refErrs) > 0 {
+			errs = append(errs, refErrs...)
+		} else if names.Has(ref.Name) {
+			errs = append(errs, field.Duplicate(path.Index(i).Child("name"), ref.Name))
+		}
+		names.Add(ref.Name)
+	}
+	return errs
+}
+
+// validateElasticsearchRef validates a reference to an Elasticsearch cluster that must be in the given namespace.
+func validateElasticsearchRef(path *field.Path, ref commonv1.LocalObjectSelector, namespace string) field.ErrorList {
+	switch {
+	case ref.Name == "":
+		return field.ErrorList{field.Required(path.Child("name"), "Elasticsearch name is mandatory")}
+	case ref.Namespace != "" && ref.Namespace != namespace:
+		return field.ErrorList{field.Invalid(path.Child("namespace"), ref.Namespace, crossNamespaceRefErrMsg)}
+	case ref.ServiceName != "":
+		return field.ErrorList{field.Forbidden(path.Child("serviceName"), serviceNameNotSupportedErrMsg)}
+	}
+	return nil
+}
+
+// validSecureSettings ensures the secure settings of the repository reference Secrets, external secrets are not
+// supported by SnapshotRepository resources.
+func validSecureSettings(r *SnapshotRepository) field.ErrorList {
+	return commonv1.CheckSecretSources(field.NewPath("spec").Child("secureSettings"), r.Spec.SecureSettings, r.Namespace, false)
+}
+
+func validWorkloadIdentity(r *SnapshotRepository) field.ErrorList {
+	identity := r.Spec.WorkloadIdentity
+	if identity == nil {
+		return nil
+	}
+	path := field.NewPath("spec").Child("workloadIdentity")
+	var errs field.ErrorList
+	if identity.ServiceAccountName == "" {
+		errs = append(errs, field.Required(path.Child("serviceAccountName"), "service account name is mandatory"))
+	}
+	switch r.Spec.Type {
+	case S3RepositoryType:
+		if identity.AWSRoleARN == "" {
+			errs = append(errs, field.Required(path.Child("awsRoleARN"), "AWS IAM role ARN is mandatory for s3 repositories"))
+		}
+	case GCSRepositoryType:
+		if identity.GCPServiceAccount == "" {
+			errs = append(errs, field.Required(path.Child("gcpServiceAccount"), "Google Cloud service account is mandatory for gcs repositories"))
+		}
+	case AzureRepositoryType:
+		if identity.AzureClientID == "" {
+			errs = append(errs, field.Required(path.Child("azureClientID"), "Azure client ID is mandatory for azure repositories"))
+		}
+		if identity.AzureTenantID == "" {
+			errs = append(errs, field.Required(path.Child("azureTenantID"), "Azure tenant ID is mandatory for azure repositories"))
+		}
+	default:
+		errs = append(errs, field.Forbidden(path, workloadIdentityNotSupportedErrMsg))
+	}
+	return errs
+}
+
+func checkRepositoryNameChange(old, curr *SnapshotRepository) field.ErrorList {
+	if old.RepositoryNameOrDefault() != curr.RepositoryNameOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("repositoryName"), repositoryNameChangeErrMsg)}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkSnapshotRepository(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "no-elasticsearch",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkSnapshotRepository(uid)
+				r.Spec.ElasticsearchRefs = nil
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRefs: Required value: at least one Elasticsearch cluster must be referenced`,
+			),
+		},
+		{
+			Name:      "elasticsearch-in-another-namespace",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkSnapshotRepository(uid)
+				r.Spec.ElasticsearchRefs = append(r.Spec.ElasticsearchRefs, commonv1.LocalObjectSelector{Namespace: "other", Name: "es2"})
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRefs\[1\].namespace: Invalid value: "other": Elasticsearch clusters must be in the same namespace as the resource`,
+			),
+		},
+		{
+			Name:      "duplicate-elasticsearch",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkSnapshotRepository(uid)
+				r.Spec.ElasticsearchRefs = append(r.Spec.ElasticsearchRefs, commonv1.LocalObjectSelector{Namespace: "ns", Name: "es"})
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRefs\[1\].name: Duplicate value: "es"`,
+			),
+		},
+		{
+			Name:      "workload-identity-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkSnapshotRepository(uid)
+				r.Spec.WorkloadIdentity = &snapshotv1alpha1.WorkloadIdentity{
+					ServiceAccountName: "es-snapshots",
+					AWSRoleARN:         "arn:aws:iam::123456789012:role/es-snapshots",
+				}
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "workload-identity-missing-identity",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkSnapshotRepository(uid)
+				r.Spec.Type = snapshotv1alpha1.AzureRepositoryType
+				r.Spec.WorkloadIdentity = &snapshotv1alpha1.WorkloadIdentity{AWSRoleARN: "arn:aws:iam::123456789012:role/es-snapshots"}
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.workloadIdentity.serviceAccountName: Required value: service account name is mandatory`,
+				`spec.workloadIdentity.azureClientID: Required value: Azure client ID is mandatory for azure repositories`,
+				`spec.workloadIdentity.azureTenantID: Required value: Azure tenant ID is mandatory for azure repositories`,
+			),
+		},
+		{
+			Name:      "workload-identity-unsupported-type",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkSnapshotRepository(uid)
+				r.Spec.Type = snapshotv1alpha1.FSRepositoryType
+				r.Spec.WorkloadIdentity = &snapshotv1alpha1.WorkloadIdentity{ServiceAccountName: "es-snapshots"}
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.workloadIdentity: Forbidden: workload identity is only supported for s3, gcs and azure repositories`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkSnapshotRepository(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkSnapshotRepository(uid)
+				r.Spec.RepositoryName = r.Name
+				r.Spec.Settings = &commonv1.Config{Data: map[string]interface{}{"bucket": "other-bucket"}}
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-repository-name",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkSnapshotRepository(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkSnapshotRepository(uid)
+				r.Spec.RepositoryName = "other-repository"
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.repositoryName: Forbidden: the repository name cannot be changed`,
+			),
+		},
+		{
+			Name:      "external-secure-settings",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				r := mkSnapshotRepository(uid)
+				r.Spec.SecureSettings = []commonv1.SecretSource{
+					{External: &commonv1.ExternalSecretSource{Provider: commonv1.AWSSecretProvider, Path: "es-s3"}},
+				}
+				return serialize(t, r)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.secureSettings\[0\].external: Forbidden: external secrets are not supported here, use secretName instead`,
+			),
+		},
+	}
+
+	validator := &snapshotv1alpha1.SnapshotRepository{}
+	gvk := metav1.GroupVersionKind{Group: snapshotv1alpha1.GroupVersion.Group, Version: snapshotv1alpha1.GroupVersion.Version, Kind: snapshotv1alpha1.Kind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkSnapshotRepository(uid string) *snapshotv1alpha1.SnapshotRepository {
+	return &snapshotv1alpha1.SnapshotRepository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "snapshot-repository-test",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: snapshotv1alpha1.SnapshotRepositorySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			Type:              snapshotv1alpha1.S3RepositoryType,
+			Settings:          &commonv1.Config{Data: map[string]interface{}{"bucket": "bucket"}},
+		},
+	}
+}
+
+func serialize(t *testing.T, repository *snapshotv1alpha1.SnapshotRepository) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(repository)
+	require.NoError(t, err)
+
+	return objBytes
+}
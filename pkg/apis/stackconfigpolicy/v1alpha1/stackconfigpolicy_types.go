@@ -6,6 +6,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"slices"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -59,6 +60,45 @@ type StackConfigPolicySpec struct {
 	SecureSettings []commonv1.SecretSource       `json:"secureSettings,omitempty"`
 	Elasticsearch  ElasticsearchConfigPolicySpec `json:"elasticsearch,omitempty"`
 	Kibana         KibanaConfigPolicySpec        `json:"kibana,omitempty"`
+	// NamespaceOverrides customize the Elasticsearch settings of the policy for the resources of given namespaces, for
+	// example to configure different rollover sizes per tenant namespace in the same index lifecycle policy.
+	NamespaceOverrides []NamespaceOverride `json:"namespaceOverrides,omitempty"`
+}
+
+// NamespaceOverride holds Elasticsearch settings merged into the settings of the policy for the resources of the given
+// namespaces.
+type NamespaceOverride struct {
+	// Namespaces are the namespaces of the resources the override applies to. A namespace can only be part of one override.
+	// +kubebuilder:validation:MinItems=1
+	Namespaces []string `json:"namespaces"`
+	// Elasticsearch holds the Elasticsearch settings to merge into the settings of the policy. Objects are merged
+	// recursively, any other value replaces the value of the policy.
+	Elasticsearch ElasticsearchSettingsOverride `json:"elasticsearch,omitempty"`
+}
+
+// ElasticsearchSettingsOverride holds the Elasticsearch settings that can be overridden per namespace.
+type ElasticsearchSettingsOverride struct {
+	// ClusterSettings holds the Elasticsearch cluster settings (/_cluster/settings)
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ClusterSettings *commonv1.Config `json:"clusterSettings,omitempty"`
+	// SnapshotRepositories holds the Snapshot Repositories settings (/_snapshot)
+	// +kubebuilder:pruning:PreserveUnknownFields
+	SnapshotRepositories *commonv1.Config `json:"snapshotRepositories,omitempty"`
+	// SnapshotLifecyclePolicies holds the Snapshot Lifecycle Policies settings (/_slm/policy)
+	// +kubebuilder:pruning:PreserveUnknownFields
+	SnapshotLifecyclePolicies *commonv1.Config `json:"snapshotLifecyclePolicies,omitempty"`
+	// SecurityRoleMappings holds the Role Mappings settings (/_security/role_mapping)
+	// +kubebuilder:pruning:PreserveUnknownFields
+	SecurityRoleMappings *commonv1.Config `json:"securityRoleMappings,omitempty"`
+	// IndexLifecyclePolicies holds the Index Lifecycle policies settings (/_ilm/policy)
+	// +kubebuilder:pruning:PreserveUnknownFields
+	IndexLifecyclePolicies *commonv1.Config `json:"indexLifecyclePolicies,omitempty"`
+	// IngestPipelines holds the Ingest Pipelines settings (/_ingest/pipeline)
+	// +kubebuilder:pruning:PreserveUnknownFields
+	IngestPipelines *commonv1.Config `json:"ingestPipelines,omitempty"`
+	// IndexTemplates holds the Index and Component Templates settings
+	// +kubebuilder:pruning:PreserveUnknownFields
+	IndexTemplates IndexTemplates `json:"indexTemplates,omitempty"`
 }
 
 type ElasticsearchConfigPolicySpec struct {
@@ -119,6 +159,53 @@ type IndexTemplates struct {
 	ComposableIndexTemplates *commonv1.Config `json:"composableIndexTemplates,omitempty"`
 }
 
+// ElasticsearchSpecFor returns the Elasticsearch settings of the policy for a resource in the given namespace, with
+// the override of the namespace merged into them.
+func (p StackConfigPolicy) ElasticsearchSpecFor(namespace string) ElasticsearchConfigPolicySpec {
+	spec := *p.Spec.Elasticsearch.DeepCopy()
+	for _, override := range p.Spec.NamespaceOverrides {
+		if !slices.Contains(override.Namespaces, namespace) {
+			continue
+		}
+		settings := override.Elasticsearch.DeepCopy()
+		spec.ClusterSettings = mergeConfig(spec.ClusterSettings, settings.ClusterSettings)
+		spec.SnapshotRepositories = mergeConfig(spec.SnapshotRepositories, settings.SnapshotRepositories)
+		spec.SnapshotLifecyclePolicies = mergeConfig(spec.SnapshotLifecyclePolicies, settings.SnapshotLifecyclePolicies)
+		spec.SecurityRoleMappings = mergeConfig(spec.SecurityRoleMappings, settings.SecurityRoleMappings)
+		spec.IndexLifecyclePolicies = mergeConfig(spec.IndexLifecyclePolicies, settings.IndexLifecyclePolicies)
+		spec.IngestPipelines = mergeConfig(spec.IngestPipelines, settings.IngestPipelines)
+		spec.IndexTemplates.ComponentTemplates = mergeConfig(spec.IndexTemplates.ComponentTemplates, settings.IndexTemplates.ComponentTemplates)
+		spec.IndexTemplates.ComposableIndexTemplates = mergeConfig(spec.IndexTemplates.ComposableIndexTemplates, settings.IndexTemplates.ComposableIndexTemplates)
+	}
+	return spec
+}
+
+// mergeConfig merges the override into the base configuration, which is modified in place.
+func mergeConfig(base, override *commonv1.Config) *commonv1.Config {
+	if override == nil {
+		return base
+	}
+	if base == nil || base.Data == nil {
+		return override
+	}
+	base.Data = mergeMaps(base.Data, override.Data)
+	return base
+}
+
+// mergeMaps recursively merges the override map into the base map.
+func mergeMaps(base, override map[string]interface{}) map[string]interface{} {
+	for key, overrideValue := range override {
+		baseMap, baseIsMap := base[key].(map[string]interface{})
+		overrideMap, overrideIsMap := overrideValue.(map[string]interface{})
+		if baseIsMap && overrideIsMap {
+			base[key] = mergeMaps(baseMap, overrideMap)
+			continue
+		}
+		base[key] = overrideValue
+	}
+	return base
+}
+
 type StackConfigPolicyStatus struct {
 	// ResourcesStatuses holds the status for each resource to be configured.
 	// Deprecated: Details is used to store the status of resources from ECK 2.11
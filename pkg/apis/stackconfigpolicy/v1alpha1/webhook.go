@@ -32,6 +32,8 @@ var (
 		checkNoUnknownFields,
 		checkNameLength,
 		validSettings,
+		validNamespaceOverrides,
+		validSecureSettings,
 	}
 )
 
@@ -144,6 +146,30 @@ func validSettings(policy *StackConfigPolicy) field.ErrorList {
 	return nil
 }
 
+// validNamespaceOverrides ensures a namespace is only part of one override, to not depend on the order of the overrides.
+// validSecureSettings ensures the secure settings of the policy reference Secrets, external secrets are not supported
+// by policies.
+func validSecureSettings(policy *StackConfigPolicy) field.ErrorList {
+	errs := commonv1.CheckSecretSources(field.NewPath("spec").Child("secureSettings"), policy.Spec.SecureSettings, policy.Namespace, false)
+	errs = append(errs, commonv1.CheckSecretSources(field.NewPath("spec").Child("elasticsearch", "secureSettings"), policy.Spec.Elasticsearch.SecureSettings, policy.Namespace, false)...)
+	return append(errs, commonv1.CheckSecretSources(field.NewPath("spec").Child("kibana", "secureSettings"), policy.Spec.Kibana.SecureSettings, policy.Namespace, false)...)
+}
+
+func validNamespaceOverrides(policy *StackConfigPolicy) field.ErrorList {
+	var errs field.ErrorList
+	seen := make(map[string]struct{})
+	for i, override := range policy.Spec.NamespaceOverrides {
+		for j, namespace := range override.Namespaces {
+			path := field.NewPath("spec").Child("namespaceOverrides").Index(i).Child("namespaces").Index(j)
+			if _, exists := seen[namespace]; exists {
+				errs = append(errs, field.Duplicate(path, namespace))
+			}
+			seen[namespace] = struct{}{}
+		}
+	}
+	return errs
+}
+
 // uniqueSecretMountPaths returns true if all given mountpaths are unique
 func uniqueSecretMountPaths(secretMounts []SecretMount) bool {
 	mountPathMap := make(map[string]bool)
@@ -126,6 +126,40 @@ func TestWebhook(t *testing.T) {
 				"SecretMounts cannot have duplicate mount paths",
 			),
 		},
+		{
+			Name:      "create-valid-namespace-overrides",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkStackConfigPolicy(uid)
+				m.Spec.NamespaceOverrides = []policyv1alpha1.NamespaceOverride{
+					{Namespaces: []string{"tenant-a"}, Elasticsearch: policyv1alpha1.ElasticsearchSettingsOverride{
+						ClusterSettings: &commonv1.Config{Data: map[string]interface{}{"a": "c"}},
+					}},
+					{Namespaces: []string{"tenant-b", "tenant-c"}, Elasticsearch: policyv1alpha1.ElasticsearchSettingsOverride{
+						ClusterSettings: &commonv1.Config{Data: map[string]interface{}{"a": "d"}},
+					}},
+				}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "create-duplicate-override-namespaces",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkStackConfigPolicy(uid)
+				m.Spec.NamespaceOverrides = []policyv1alpha1.NamespaceOverride{
+					{Namespaces: []string{"tenant-a"}},
+					{Namespaces: []string{"tenant-b", "tenant-a"}},
+				}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`Duplicate value: "tenant-a"`,
+			),
+		},
 	}
 
 	validator := &policyv1alpha1.StackConfigPolicy{}
@@ -69,6 +69,46 @@ func (in *ElasticsearchConfigPolicySpec) DeepCopy() *ElasticsearchConfigPolicySp
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchSettingsOverride) DeepCopyInto(out *ElasticsearchSettingsOverride) {
+	*out = *in
+	if in.ClusterSettings != nil {
+		in, out := &in.ClusterSettings, &out.ClusterSettings
+		*out = (*in).DeepCopy()
+	}
+	if in.SnapshotRepositories != nil {
+		in, out := &in.SnapshotRepositories, &out.SnapshotRepositories
+		*out = (*in).DeepCopy()
+	}
+	if in.SnapshotLifecyclePolicies != nil {
+		in, out := &in.SnapshotLifecyclePolicies, &out.SnapshotLifecyclePolicies
+		*out = (*in).DeepCopy()
+	}
+	if in.SecurityRoleMappings != nil {
+		in, out := &in.SecurityRoleMappings, &out.SecurityRoleMappings
+		*out = (*in).DeepCopy()
+	}
+	if in.IndexLifecyclePolicies != nil {
+		in, out := &in.IndexLifecyclePolicies, &out.IndexLifecyclePolicies
+		*out = (*in).DeepCopy()
+	}
+	if in.IngestPipelines != nil {
+		in, out := &in.IngestPipelines, &out.IngestPipelines
+		*out = (*in).DeepCopy()
+	}
+	in.IndexTemplates.DeepCopyInto(&out.IndexTemplates)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSettingsOverride.
+func (in *ElasticsearchSettingsOverride) DeepCopy() *ElasticsearchSettingsOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchSettingsOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IndexTemplates) DeepCopyInto(out *IndexTemplates) {
 	*out = *in
@@ -118,6 +158,27 @@ func (in *KibanaConfigPolicySpec) DeepCopy() *KibanaConfigPolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceOverride) DeepCopyInto(out *NamespaceOverride) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Elasticsearch.DeepCopyInto(&out.Elasticsearch)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceOverride.
+func (in *NamespaceOverride) DeepCopy() *NamespaceOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyStatusError) DeepCopyInto(out *PolicyStatusError) {
 	*out = *in
@@ -236,6 +297,13 @@ func (in *StackConfigPolicySpec) DeepCopyInto(out *StackConfigPolicySpec) {
 	}
 	in.Elasticsearch.DeepCopyInto(&out.Elasticsearch)
 	in.Kibana.DeepCopyInto(&out.Kibana)
+	if in.NamespaceOverrides != nil {
+		in, out := &in.NamespaceOverrides, &out.NamespaceOverrides
+		*out = make([]NamespaceOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackConfigPolicySpec.
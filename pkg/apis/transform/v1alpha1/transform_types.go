@@ -0,0 +1,143 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// TransformKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	TransformKind = "Transform"
+)
+
+func init() {
+	SchemeBuilder.Register(&Transform{}, &TransformList{})
+}
+
+// +kubebuilder:object:root=true
+
+// Transform represents a continuous transform configured on an Elasticsearch cluster.
+// +kubebuilder:resource:categories=elastic,shortName=estransform
+// +kubebuilder:printcolumn:name="Elasticsearch",type="string",JSONPath=".spec.elasticsearchRef.name"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="State of the transform in Elasticsearch"
+// +kubebuilder:printcolumn:name="Checkpoint",type="integer",JSONPath=".status.checkpoint",description="Last completed checkpoint"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type Transform struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TransformSpec   `json:"spec,omitempty"`
+	Status TransformStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TransformList contains a list of Transform resources.
+type TransformList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Transform `json:"items"`
+}
+
+type TransformSpec struct {
+	// ElasticsearchRef is a reference to the Elasticsearch cluster the transform runs on.
+	// The cluster must be in the same namespace as the Transform. It cannot be changed once the Transform is created.
+	ElasticsearchRef commonv1.LocalObjectSelector `json:"elasticsearchRef"`
+
+	// TransformID is the id of the transform in Elasticsearch. Defaults to the name of the Transform.
+	// It cannot be changed once the Transform is created.
+	// +kubebuilder:validation:Optional
+	TransformID string `json:"transformID,omitempty"`
+
+	// Transform is the definition of the transform, as expected in the body of the Elasticsearch create transform API.
+	// It must contain the source and the destination of the transform, either a pivot or a latest configuration, and
+	// the sync configuration of a continuous transform.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Transform *commonv1.Config `json:"transform,omitempty"`
+
+	// State is the expected state of the transform: Started or Stopped. Defaults to Started.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Started;Stopped
+	State State `json:"state,omitempty"`
+}
+
+// State is the expected state of a transform.
+type State string
+
+const (
+	StartedState State = "Started"
+	StoppedState State = "Stopped"
+)
+
+type TransformStatus struct {
+	// Phase is the phase of the Transform.
+	Phase Phase `json:"phase,omitempty"`
+	// Message explains why the transform is not configured yet, or why its configuration failed.
+	Message string `json:"message,omitempty"`
+	// TransformID is the id of the transform in Elasticsearch.
+	TransformID string `json:"transformID,omitempty"`
+	// State is the state of the transform in Elasticsearch, for example started, indexing, stopped or failed.
+	State string `json:"state,omitempty"`
+	// Checkpoint is the last checkpoint completed by the transform.
+	Checkpoint int64 `json:"checkpoint,omitempty"`
+	// LastCheckpointTime is the time the last checkpoint was completed.
+	LastCheckpointTime *metav1.Time `json:"lastCheckpointTime,omitempty"`
+	// FailureReason is the reason reported by Elasticsearch when the transform failed.
+	FailureReason string `json:"failureReason,omitempty"`
+	// LastDriftTime is the last time the transform was found modified or stopped outside of the operator and restored.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this Transform.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// Phase is the phase of a Transform.
+type Phase = commonv1.APIResourcePhase
+
+const (
+	ReadyPhase           = commonv1.APIResourceReadyPhase
+	ApplyingChangesPhase = commonv1.APIResourceApplyingChangesPhase
+	ErrorPhase           = commonv1.APIResourceErrorPhase
+	InvalidPhase         = commonv1.APIResourceInvalidPhase
+)
+
+// TransformIDOrDefault returns the id of the transform in Elasticsearch.
+func (t *Transform) TransformIDOrDefault() string {
+	if t.Spec.TransformID != "" {
+		return t.Spec.TransformID
+	}
+	return t.Name
+}
+
+// StateOrDefault returns the expected state of the transform.
+func (t *Transform) StateOrDefault() State {
+	if t.Spec.State != "" {
+		return t.Spec.State
+	}
+	return StartedState
+}
+
+// References returns true if the transform runs on the given Elasticsearch cluster.
+func (t *Transform) References(es types.NamespacedName) bool {
+	return t.Spec.ElasticsearchRef.WithDefaultNamespace(t.Namespace).NamespacedName() == es
+}
+
+// IsMarkedForDeletion returns true if the Transform resource is going to be deleted.
+func (t *Transform) IsMarkedForDeletion() bool {
+	return !t.DeletionTimestamp.IsZero()
+}
+
+// IsDegraded returns true when the TransformStatus is degraded compared to the previous status.
+func (s TransformStatus) IsDegraded(prev TransformStatus) bool {
+	return s.Phase.IsDegraded(prev.Phase)
+}
@@ -0,0 +1,192 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// transformWebhookPath is the HTTP path for the Transform validating webhook.
+	transformWebhookPath = "/validate-transform-k8s-elastic-co-v1alpha1-transforms"
+
+	// maxTransformIDLength is the maximum length of a transform id accepted by Elasticsearch.
+	maxTransformIDLength = 64
+
+	crossNamespaceRefErrMsg       = "Elasticsearch must be in the same namespace as the resource"
+	serviceNameNotSupportedErrMsg = "a custom service is not supported to reach Elasticsearch"
+	invalidTransformIDErrMsg      = "must contain lowercase alphanumeric characters, hyphens and underscores, start and end with an alphanumeric character, and be at most 64 characters long"
+	transformIDChangeErrMsg       = "the transform id cannot be changed"
+	elasticsearchChangeErrMsg     = "the Elasticsearch cluster of the transform cannot be changed"
+	transformBodyIDErrMsg         = "the id of the transform is set by spec.transformID"
+	pivotOrLatestErrMsg           = "exactly one of pivot or latest must be set"
+)
+
+var (
+	transformGroupKind = schema.GroupKind{Group: GroupVersion.Group, Kind: TransformKind}
+	validationLog      = ulog.Log.WithName("transform-v1alpha1-validation")
+
+	// transformIDRegexp matches the transform ids accepted by Elasticsearch.
+	transformIDRegexp = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9_\-]*[a-z0-9])?$`)
+
+	transformDefaultChecks = []func(*Transform) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		validElasticsearchRef,
+		validTransformID,
+		validTransform,
+	}
+
+	transformUpdateChecks = []func(old, curr *Transform) field.ErrorList{
+		checkTransformIDChange,
+		checkElasticsearchRefChange,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-transform-k8s-elastic-co-v1alpha1-transforms,mutating=false,failurePolicy=ignore,groups=transform.k8s.elastic.co,resources=transforms,verbs=create;update,versions=v1alpha1,name=elastic-transform-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &Transform{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (t *Transform) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", t.Name)
+	return t.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (t *Transform) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", t.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (t *Transform) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", t.Name)
+	oldObj, ok := old.(*Transform)
+	if !ok {
+		return nil, errors.New("cannot cast old object to Transform type")
+	}
+	return t.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (t *Transform) WebhookPath() string {
+	return transformWebhookPath
+}
+
+func (t *Transform) validate(old *Transform) (admission.Warnings, error) {
+	var errs field.ErrorList
+
+	for _, dc := range transformDefaultChecks {
+		if err := dc(t); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if old != nil {
+		for _, uc := range transformUpdateChecks {
+			if err := uc(old, t); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return nil, apierrors.NewInvalid(transformGroupKind, t.Name, errs)
+	}
+	return nil, nil
+}
+
+func checkNoUnknownFields(t *Transform) field.ErrorList {
+	return commonv1.NoUnknownFields(t, t.ObjectMeta)
+}
+
+func checkNameLength(t *Transform) field.ErrorList {
+	return commonv1.CheckNameLength(t)
+}
+
+// validElasticsearchRef validates the reference to Elasticsearch, which must be in the namespace of the transform.
+func validElasticsearchRef(t *Transform) field.ErrorList {
+	path := field.NewPath("spec").Child("elasticsearchRef")
+	switch {
+	case t.Spec.ElasticsearchRef.Name == "":
+		return field.ErrorList{field.Required(path.Child("name"), "Elasticsearch name is mandatory")}
+	case t.Spec.ElasticsearchRef.Namespace != "" && t.Spec.ElasticsearchRef.Namespace != t.Namespace:
+		return field.ErrorList{field.Invalid(path.Child("namespace"), t.Spec.ElasticsearchRef.Namespace, crossNamespaceRefErrMsg)}
+	case t.Spec.ElasticsearchRef.ServiceName != "":
+		return field.ErrorList{field.Forbidden(path.Child("serviceName"), serviceNameNotSupportedErrMsg)}
+	}
+	return nil
+}
+
+// validTransformID checks that the id of the transform, which defaults to the name of the resource, is accepted by
+// Elasticsearch.
+func validTransformID(t *Transform) field.ErrorList {
+	id := t.TransformIDOrDefault()
+	if len(id) > maxTransformIDLength || !transformIDRegexp.MatchString(id) {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("transformID"), id, invalidTransformIDErrMsg)}
+	}
+	return nil
+}
+
+// validTransform checks the fields required by Elasticsearch to create a continuous transform, its content is
+// validated by Elasticsearch.
+func validTransform(t *Transform) field.ErrorList {
+	path := field.NewPath("spec").Child("transform")
+	if t.Spec.Transform == nil {
+		return field.ErrorList{field.Required(path, "transform is mandatory")}
+	}
+	definition := t.Spec.Transform.Data
+	var errs field.ErrorList
+	for _, key := range []string{"source", "dest"} {
+		if value, ok := definition[key]; !ok || value == nil {
+			errs = append(errs, field.Required(path.Child(key), "mandatory to create the transform"))
+		}
+	}
+	if definition["sync"] == nil {
+		errs = append(errs, field.Required(path.Child("sync"), "mandatory to create a continuous transform"))
+	}
+	_, hasPivot := definition["pivot"]
+	_, hasLatest := definition["latest"]
+	switch {
+	case !hasPivot && !hasLatest:
+		errs = append(errs, field.Required(path.Child("pivot"), pivotOrLatestErrMsg))
+	case hasPivot && hasLatest:
+		errs = append(errs, field.Forbidden(path.Child("latest"), pivotOrLatestErrMsg))
+	}
+	if _, exists := definition["id"]; exists {
+		errs = append(errs, field.Forbidden(path.Child("id"), transformBodyIDErrMsg))
+	}
+	return errs
+}
+
+func checkTransformIDChange(old, curr *Transform) field.ErrorList {
+	if old.TransformIDOrDefault() != curr.TransformIDOrDefault() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("transformID"), transformIDChangeErrMsg)}
+	}
+	return nil
+}
+
+func checkElasticsearchRefChange(old, curr *Transform) field.ErrorList {
+	if old.Spec.ElasticsearchRef.Name != curr.Spec.ElasticsearchRef.Name {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("elasticsearchRef"), elasticsearchChangeErrMsg)}
+	}
+	return nil
+}
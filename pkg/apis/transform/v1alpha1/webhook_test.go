@@ -0,0 +1,188 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	transformv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/transform/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkTransform(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "create-valid-latest",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				tr := mkTransform(uid)
+				delete(tr.Spec.Transform.Data, "pivot")
+				tr.Spec.Transform.Data["latest"] = map[string]interface{}{"unique_key": []interface{}{"customer_id"}, "sort": "order_date"}
+				tr.Spec.State = transformv1alpha1.StoppedState
+				return serialize(t, tr)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-elasticsearch-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				tr := mkTransform(uid)
+				tr.Spec.ElasticsearchRef = commonv1.LocalObjectSelector{Namespace: "other", Name: "es"}
+				return serialize(t, tr)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef.namespace: Invalid value: "other": Elasticsearch must be in the same namespace as the resource`,
+			),
+		},
+		{
+			Name:      "invalid-transform-id",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				tr := mkTransform(uid)
+				tr.Name = "orders.by.customer"
+				return serialize(t, tr)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.transformID: Invalid value: "orders.by.customer": must contain lowercase alphanumeric characters`,
+			),
+		},
+		{
+			Name:      "no-transform",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				tr := mkTransform(uid)
+				tr.Spec.Transform = nil
+				return serialize(t, tr)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.transform: Required value: transform is mandatory`,
+			),
+		},
+		{
+			Name:      "incomplete-transform",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				tr := mkTransform(uid)
+				tr.Spec.Transform = &commonv1.Config{Data: map[string]interface{}{
+					"id":     "orders",
+					"source": map[string]interface{}{"index": []interface{}{"orders"}},
+				}}
+				return serialize(t, tr)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.transform.dest: Required value: mandatory to create the transform`,
+				`spec.transform.sync: Required value: mandatory to create a continuous transform`,
+				`spec.transform.pivot: Required value: exactly one of pivot or latest must be set`,
+				`spec.transform.id: Forbidden: the id of the transform is set by spec.transformID`,
+			),
+		},
+		{
+			Name:      "pivot-and-latest",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				tr := mkTransform(uid)
+				tr.Spec.Transform.Data["latest"] = map[string]interface{}{"unique_key": []interface{}{"customer_id"}, "sort": "order_date"}
+				return serialize(t, tr)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.transform.latest: Forbidden: exactly one of pivot or latest must be set`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkTransform(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				tr := mkTransform(uid)
+				tr.Spec.TransformID = tr.Name
+				tr.Spec.State = transformv1alpha1.StoppedState
+				tr.Spec.Transform.Data["frequency"] = "5m"
+				return serialize(t, tr)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-transform-id-and-elasticsearch",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkTransform(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				tr := mkTransform(uid)
+				tr.Spec.TransformID = "other-transform"
+				tr.Spec.ElasticsearchRef.Name = "other-es"
+				return serialize(t, tr)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.transformID: Forbidden: the transform id cannot be changed`,
+				`spec.elasticsearchRef: Forbidden: the Elasticsearch cluster of the transform cannot be changed`,
+			),
+		},
+	}
+
+	validator := &transformv1alpha1.Transform{}
+	gvk := metav1.GroupVersionKind{Group: transformv1alpha1.GroupVersion.Group, Version: transformv1alpha1.GroupVersion.Version, Kind: transformv1alpha1.TransformKind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkTransform(uid string) *transformv1alpha1.Transform {
+	return &transformv1alpha1.Transform{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "orders-by-customer",
+			Namespace: "ns",
+			UID:       types.UID(uid),
+		},
+		Spec: transformv1alpha1.TransformSpec{
+			ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es"},
+			Transform: &commonv1.Config{Data: map[string]interface{}{
+				"source": map[string]interface{}{"index": []interface{}{"orders"}},
+				"dest":   map[string]interface{}{"index": "orders-by-customer"},
+				"pivot": map[string]interface{}{
+					"group_by":     map[string]interface{}{"customer_id": map[string]interface{}{"terms": map[string]interface{}{"field": "customer_id"}}},
+					"aggregations": map[string]interface{}{"total": map[string]interface{}{"sum": map[string]interface{}{"field": "price"}}},
+				},
+				"sync": map[string]interface{}{"time": map[string]interface{}{"field": "order_date", "delay": "60s"}},
+			}},
+		},
+	}
+}
+
+func serialize(t *testing.T, transform *transformv1alpha1.Transform) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(transform)
+	require.NoError(t, err)
+
+	return objBytes
+}
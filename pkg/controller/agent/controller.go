@@ -9,6 +9,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -22,8 +23,10 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/queue"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
@@ -61,7 +64,7 @@ func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileAg
 func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileAgent) error {
 	// Watch for changes to Agent
 	if err := c.Watch(
-		source.Kind(mgr.GetCache(), &agentv1alpha1.Agent{}, &handler.TypedEnqueueRequestForObject[*agentv1alpha1.Agent]{})); err != nil {
+		source.Kind(mgr.GetCache(), &agentv1alpha1.Agent{}, queue.EnqueueRequestForObject[*agentv1alpha1.Agent]())); err != nil {
 		return err
 	}
 
@@ -100,7 +103,7 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileAgent)
 
 	// Watch Secrets
 	if err := c.Watch(
-		source.Kind(mgr.GetCache(), &corev1.Secret{},
+		watches.Kind(mgr.GetCache(), &corev1.Secret{},
 			handler.TypedEnqueueRequestForOwner[*corev1.Secret](mgr.GetScheme(), mgr.GetRESTMapper(),
 				&agentv1alpha1.Agent{}, handler.OnlyControllerOwner()),
 		)); err != nil {
@@ -117,9 +120,22 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileAgent)
 		return err
 	}
 
+	// Watch ingresses
+	if err := c.Watch(source.Kind(mgr.GetCache(), &networkingv1.Ingress{}, handler.TypedEnqueueRequestForOwner[*networkingv1.Ingress](
+		mgr.GetScheme(), mgr.GetRESTMapper(),
+		&agentv1alpha1.Agent{}, handler.OnlyControllerOwner(),
+	))); err != nil {
+		return err
+	}
+
+	// Watch Gateway routes
+	if err := gateway.WatchRoutes(mgr, c, &agentv1alpha1.Agent{}); err != nil {
+		return err
+	}
+
 	// Watch dynamically referenced Secrets
 	return c.Watch(
-		source.Kind(mgr.GetCache(), &corev1.Secret{},
+		watches.Kind(mgr.GetCache(), &corev1.Secret{},
 			r.dynamicWatches.Secrets,
 		))
 }
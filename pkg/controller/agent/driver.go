@@ -22,6 +22,11 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/externaldns"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/ingress"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
@@ -122,11 +127,14 @@ func internalReconcile(params Params) (*reconciler.Results, agentv1alpha1.AgentS
 			Labels:                      params.Agent.GetIdentityLabels(),
 			Services:                    []corev1.Service{*svc},
 			GlobalCA:                    params.OperatorParams.GlobalCA,
-			CACertRotation:              params.OperatorParams.CACertRotation,
-			CertRotation:                params.OperatorParams.CertRotation,
+			CACertRotation:              params.OperatorParams.CACertRotation.Get(),
+			CertRotation:                params.OperatorParams.CertRotation.Get(),
 			GarbageCollectSecrets:       true,
 			DisableInternalCADefaulting: true, // we do not want placeholder CAs in the internal certificates secret as FLEET_CA replaces otherwise all well known CAs
-			ExtraHTTPSANs:               []commonv1.SubjectAlternativeName{{DNS: fmt.Sprintf("*.%s.%s.svc", HTTPServiceName(params.Agent.Name), params.Agent.Namespace)}},
+			ExtraHTTPSANs: append(
+				[]commonv1.SubjectAlternativeName{{DNS: fmt.Sprintf("*.%s.%s.svc", HTTPServiceName(params.Agent.Name), params.Agent.Namespace)}},
+				params.Agent.Spec.HTTP.ExternalSANs()...,
+			),
 		}.ReconcileCAAndHTTPCerts(params.Context)
 		if caResults.HasError() {
 			return results.WithResults(caResults), params.Status
@@ -134,6 +142,10 @@ func internalReconcile(params Params) (*reconciler.Results, agentv1alpha1.AgentS
 		_, _ = configHash.Write(fleetCerts.Data[certificates.CertFileName])
 	}
 
+	if err := reconcileIngress(params, svc); err != nil {
+		return results.WithError(err), params.Status
+	}
+
 	fleetToken := maybeReconcileFleetEnrollment(params, results)
 	if results.HasRequeue() || results.HasError() {
 		if results.HasRequeue() {
@@ -154,7 +166,16 @@ func internalReconcile(params Params) (*reconciler.Results, agentv1alpha1.AgentS
 		return results.WithError(err), params.Status
 	}
 
-	podTemplate, err := buildPodTemplate(params, fleetCerts, fleetToken, configHash)
+	// secure settings are exposed as environment variables, include them in the configHash to roll Agent on changes
+	secureSettings, err := keystore.ReconcileSecureSettingsSecret(params.Context, params, &params.Agent, params.Agent.GetIdentityLabels(), Namer)
+	if err != nil {
+		return results.WithError(err), params.Status
+	}
+	if secureSettings != nil {
+		_, _ = configHash.Write([]byte(hash.HashObject(secureSettings.Data)))
+	}
+
+	podTemplate, err := buildPodTemplate(params, fleetCerts, fleetToken, secureSettings, configHash)
 	if err != nil {
 		return results.WithError(err), params.Status
 	}
@@ -180,6 +201,26 @@ func reconcileService(params Params) (*corev1.Service, error) {
 	return common.ReconcileService(params.Context, params.Client, svc, &params.Agent)
 }
 
+// reconcileIngress reconciles the Ingress and the Gateway route exposing Fleet Server, if any.
+func reconcileIngress(params Params, svc *corev1.Service) error {
+	ingressParams := ingress.Params{
+		Owner:  &params.Agent,
+		HTTP:   params.Agent.Spec.HTTP,
+		Labels: params.Agent.GetIdentityLabels(),
+		Namer:  Namer,
+	}
+	if svc == nil {
+		// Fleet Server is not enabled, clean up the Ingress and the route if they were previously set up
+		ingressParams.HTTP = commonv1.HTTPConfig{}
+		svc = newService(params.Agent)
+	}
+	ingressParams.Service = *svc
+	if err := ingress.Reconcile(params.Context, params.Client, ingressParams); err != nil {
+		return err
+	}
+	return gateway.Reconcile(params.Context, params.Client, ingressParams)
+}
+
 func newService(agent agentv1alpha1.Agent) *corev1.Service {
 	svc := corev1.Service{
 		ObjectMeta: agent.Spec.HTTP.Service.ObjectMeta,
@@ -188,6 +229,7 @@ func newService(agent agentv1alpha1.Agent) *corev1.Service {
 
 	svc.ObjectMeta.Namespace = agent.Namespace
 	svc.ObjectMeta.Name = HTTPServiceName(agent.Name)
+	svc.ObjectMeta.Annotations = externaldns.ServiceAnnotations(agent.Spec.HTTP)
 
 	labels := agent.GetIdentityLabels()
 	ports := []corev1.ServicePort{
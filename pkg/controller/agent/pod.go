@@ -29,11 +29,13 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemesh"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
 )
 
 const (
@@ -123,7 +125,7 @@ var (
 	}
 )
 
-func buildPodTemplate(params Params, fleetCerts *certificates.CertificatesSecret, fleetToken EnrollmentAPIKey, configHash hash.Hash32) (corev1.PodTemplateSpec, error) {
+func buildPodTemplate(params Params, fleetCerts *certificates.CertificatesSecret, fleetToken EnrollmentAPIKey, secureSettings *corev1.Secret, configHash hash.Hash32) (corev1.PodTemplateSpec, error) {
 	defer tracing.Span(&params.Context)()
 	spec := &params.Agent.Spec
 	builder := defaults.NewPodTemplateBuilder(params.GetPodTemplate(), ContainerName)
@@ -158,6 +160,11 @@ func buildPodTemplate(params Params, fleetCerts *certificates.CertificatesSecret
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	image, err := container.ResolveImage(spec.Image, container.AgentImage, v)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
 	// volume with agent data path if version > 7.15 (available since 7.13 but non-functional as agent tries to fork child
 	// processes in data path directory and hostPath volumes are always mounted non-exec)
 	if v.GTE(version.MinFor(7, 15, 0)) {
@@ -181,7 +188,8 @@ func buildPodTemplate(params Params, fleetCerts *certificates.CertificatesSecret
 	builder = builder.
 		WithLabels(agentLabels).
 		WithAnnotations(annotations).
-		WithDockerImage(spec.Image, container.ImageRepository(container.AgentImage, v)).
+		WithAnnotations(servicemesh.PodAnnotations(params.Agent.Spec.HTTP.TLS)).
+		WithDockerImage(spec.Image, image).
 		WithAutomountServiceAccountToken().
 		WithVolumeLikes(vols...).
 		WithEnv(
@@ -190,11 +198,35 @@ func buildPodTemplate(params Params, fleetCerts *certificates.CertificatesSecret
 					FieldPath: "spec.nodeName",
 				},
 			}},
-		)
+		).
+		WithEnv(secureSettingsEnvVars(secureSettings)...)
 
 	return builder.PodTemplate, nil
 }
 
+// secureSettingsEnvVars exposes each key of the secure settings secret as an environment variable of the same name, which
+// can be referenced in the Agent configuration.
+func secureSettingsEnvVars(secureSettings *corev1.Secret) []corev1.EnvVar {
+	if secureSettings == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(secureSettings.Data))
+	for k := range secureSettings.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	vars := make([]corev1.EnvVar, 0, len(keys))
+	for _, k := range keys {
+		vars = append(vars, corev1.EnvVar{Name: k, ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secureSettings.Name},
+				Key:                  k,
+			},
+		}})
+	}
+	return vars
+}
+
 func amendBuilderForFleetMode(params Params, fleetCerts *certificates.CertificatesSecret, fleetToken EnrollmentAPIKey, builder *defaults.PodTemplateBuilder, configHash hash.Hash) (*defaults.PodTemplateBuilder, error) {
 	esAssociation, err := getRelatedEsAssoc(params)
 	if err != nil {
@@ -241,7 +273,7 @@ func amendBuilderForFleetMode(params Params, fleetCerts *certificates.Certificat
 }
 
 func applyEnvVars(params Params, fleetToken EnrollmentAPIKey, certs *certificates.CertificatesSecret, builder *defaults.PodTemplateBuilder) (*defaults.PodTemplateBuilder, error) {
-	fleetModeEnvVars, err := getFleetModeEnvVars(params.Context, params.Agent, params.Client, fleetToken, certs)
+	fleetModeEnvVars, err := getFleetModeEnvVars(params.Context, params.Agent, params.Client, fleetToken, certs, params.OperatorParams.IPFamily)
 	if err != nil {
 		return nil, err
 	}
@@ -477,13 +509,14 @@ func getFleetModeEnvVars(
 	client k8s.Client,
 	fleetToken EnrollmentAPIKey,
 	certs *certificates.CertificatesSecret,
+	ipFamily corev1.IPFamily,
 ) (map[string]string, error) {
 	result := map[string]string{}
 
 	for _, f := range []func(agentv1alpha1.Agent) (map[string]string, error){
 		getFleetSetupKibanaEnvVars(fleetToken),
 		getFleetSetupFleetEnvVars(client, fleetToken, certs),
-		getFleetSetupFleetServerEnvVars(ctx, client),
+		getFleetSetupFleetServerEnvVars(ctx, client, ipFamily),
 	} {
 		envVars, err := f(agent)
 		if err != nil {
@@ -570,7 +603,7 @@ func getFleetSetupFleetEnvVars(client k8s.Client, fleetToken EnrollmentAPIKey, f
 	}
 }
 
-func getFleetSetupFleetServerEnvVars(ctx context.Context, client k8s.Client) func(agent agentv1alpha1.Agent) (map[string]string, error) {
+func getFleetSetupFleetServerEnvVars(ctx context.Context, client k8s.Client, ipFamily corev1.IPFamily) func(agent agentv1alpha1.Agent) (map[string]string, error) {
 	return func(agent agentv1alpha1.Agent) (map[string]string, error) {
 		if !agent.Spec.FleetServerEnabled {
 			return map[string]string{}, nil
@@ -585,7 +618,7 @@ func getFleetSetupFleetServerEnvVars(ctx context.Context, client k8s.Client) fun
 			fleetServerCfg[FleetServerCertKey] = path.Join(FleetCertsMountPath, certificates.KeyFileName)
 		} else {
 			fleetServerCfg[FleetServerInsecureHTTP] = "true"
-			fleetServerCfg[FleetServerHost] = "0.0.0.0"
+			fleetServerCfg[FleetServerHost] = net.InAddrAnyFor(ipFamily).String()
 			fleetServerCfg[FleetServerPortEnv] = fmt.Sprintf("%d", FleetServerPort)
 		}
 
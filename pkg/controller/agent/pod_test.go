@@ -24,6 +24,7 @@ import (
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -309,6 +310,9 @@ func Test_amendBuilderForFleetMode(t *testing.T) {
 						},
 					},
 				},
+				OperatorParams: operator.Parameters{
+					IPFamily: corev1.IPv4Protocol,
+				},
 				Client: k8s.NewFakeClient(&corev1.Service{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "agent-agent-http",
@@ -646,6 +650,21 @@ func Test_applyEnvVars(t *testing.T) {
 	}
 }
 
+func Test_secureSettingsEnvVars(t *testing.T) {
+	require.Nil(t, secureSettingsEnvVars(nil))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-agent-secure-settings", Namespace: "default"},
+		Data:       map[string][]byte{"ES_PASSWORD": []byte("changeme"), "API_KEY": []byte("key")},
+	}
+	envVar := func(key string) corev1.EnvVar {
+		return corev1.EnvVar{Name: key, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "agent-agent-secure-settings"},
+			Key:                  key,
+		}}}
+	}
+	require.Equal(t, []corev1.EnvVar{envVar("API_KEY"), envVar("ES_PASSWORD")}, secureSettingsEnvVars(secret))
+}
+
 func Test_getVolumesFromAssociations(t *testing.T) {
 	// Note: we use setAssocConfs to set the AssociationConfs which are normally set in the reconciliation loop.
 	for _, tt := range []struct {
@@ -1495,7 +1514,7 @@ func Test_getFleetSetupFleetServerEnvVars(t *testing.T) {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			gotEnvVars, gotErr := getFleetSetupFleetServerEnvVars(context.Background(), tt.client)(tt.agent)
+			gotEnvVars, gotErr := getFleetSetupFleetServerEnvVars(context.Background(), tt.client, corev1.IPv4Protocol)(tt.agent)
 
 			require.Equal(t, tt.wantEnvVars, gotEnvVars)
 			require.Equal(t, tt.wantErr, gotErr != nil)
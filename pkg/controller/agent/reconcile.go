@@ -59,12 +59,6 @@ func reconcilePodVehicle(params Params, podTemplate corev1.PodTemplateSpec) (*re
 	case spec.Deployment != nil:
 		reconciliationFunc = reconcileDeployment
 		toDelete = append(toDelete,
-			&v1.DaemonSet{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      name,
-					Namespace: params.Agent.Namespace,
-				},
-			},
 			&v1.StatefulSet{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      name,
@@ -75,12 +69,6 @@ func reconcilePodVehicle(params Params, podTemplate corev1.PodTemplateSpec) (*re
 	case spec.StatefulSet != nil:
 		reconciliationFunc = reconcileStatefulSet
 		toDelete = append(toDelete,
-			&v1.DaemonSet{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      name,
-					Namespace: params.Agent.Namespace,
-				},
-			},
 			&v1.Deployment{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      name,
@@ -101,6 +89,10 @@ func reconcilePodVehicle(params Params, podTemplate corev1.PodTemplateSpec) (*re
 		return results.WithError(err), params.Status
 	}
 
+	if spec.DaemonSet == nil {
+		// the DaemonSets, including the ones of the variants, are garbage collected when no DaemonSet is used
+		results.WithError(daemonset.GarbageCollect(params.Context, params.Client, &params.Agent, params.Agent.GetIdentityLabels(), nil))
+	}
 	for _, obj := range toDelete {
 		// clean up the other ones
 		if err := params.Client.Get(params.Context, types.NamespacedName{
@@ -136,7 +128,7 @@ func reconcileDeployment(rp ReconciliationParams) (int32, int32, error) {
 		return 0, 0, err
 	}
 
-	reconciled, err := deployment.Reconcile(rp.ctx, rp.client, d, &rp.agent)
+	reconciled, err := deployment.Reconcile(rp.ctx, rp.client, d, &rp.agent, false)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -170,7 +162,7 @@ func reconcileStatefulSet(rp ReconciliationParams) (int32, int32, error) {
 }
 
 func reconcileDaemonSet(rp ReconciliationParams) (int32, int32, error) {
-	ds := daemonset.New(daemonset.Params{
+	expected := daemonset.NewVariants(daemonset.Params{
 		PodTemplate:          rp.podTemplate,
 		Name:                 Name(rp.agent.Name),
 		Owner:                &rp.agent,
@@ -178,18 +170,28 @@ func reconcileDaemonSet(rp ReconciliationParams) (int32, int32, error) {
 		Selectors:            rp.agent.GetIdentityLabels(),
 		RevisionHistoryLimit: rp.agent.Spec.RevisionHistoryLimit,
 		Strategy:             rp.agent.Spec.DaemonSet.UpdateStrategy,
-	})
+	}, rp.agent.Spec.DaemonSet.Variants)
 
-	if err := controllerutil.SetControllerReference(&rp.agent, &ds, scheme.Scheme); err != nil {
-		return 0, 0, err
+	var ready, desired int32
+	for i := range expected {
+		if err := controllerutil.SetControllerReference(&rp.agent, &expected[i], scheme.Scheme); err != nil {
+			return 0, 0, err
+		}
+
+		reconciled, err := daemonset.Reconcile(rp.ctx, rp.client, expected[i], &rp.agent)
+		if err != nil {
+			return 0, 0, err
+		}
+		ready += reconciled.Status.NumberReady
+		desired += reconciled.Status.DesiredNumberScheduled
 	}
 
-	reconciled, err := daemonset.Reconcile(rp.ctx, rp.client, ds, &rp.agent)
-	if err != nil {
+	// delete the DaemonSets of the removed variants, or the default DaemonSet replaced by variants
+	if err := daemonset.GarbageCollect(rp.ctx, rp.client, &rp.agent, rp.agent.GetIdentityLabels(), expected); err != nil {
 		return 0, 0, err
 	}
 
-	return reconciled.Status.NumberReady, reconciled.Status.DesiredNumberScheduled, nil
+	return ready, desired, nil
 }
 
 // ReconciliationParams are the parameters used during an Elastic Agent's reconciliation.
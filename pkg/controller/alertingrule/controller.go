@@ -0,0 +1,127 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package alertingrule
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	alertingv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/alerting/v1alpha1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	controllerName = "alertingrule-controller"
+
+	// RuleFinalizer lets the operator delete the rule and its connectors from Kibana before the AlertingRule resource
+	// is deleted.
+	RuleFinalizer = "alerting.k8s.elastic.co/delete-rule"
+)
+
+// config identifies the AlertingRule controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "AlertingRule",
+	NameField:      "rule_name",
+	Finalizer:      RuleFinalizer,
+}
+
+// Add creates a new AlertingRule Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Kibana and reconcile the AlertingRule resources referencing them
+		source.Kind[client.Object](mgr.GetCache(), &kbv1.Kibana{}, reconcileRequestForRules(r.Client, referencesKibana)),
+		// watch for changes to Secrets and reconcile the AlertingRule resources whose connectors use them
+		watches.Kind[client.Object](mgr.GetCache(), &corev1.Secret{}, reconcileRequestForRules(r.Client, referencesSecret)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of AlertingRule.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*alertingv1alpha1.AlertingRule, alertingv1alpha1.AlertingRuleStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &ruleKind{
+		Client:           c,
+		kbClientProvider: kbclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+func referencesKibana(rule *alertingv1alpha1.AlertingRule, obj client.Object) bool {
+	return rule.Spec.KibanaRef.Name == obj.GetName()
+}
+
+func referencesSecret(rule *alertingv1alpha1.AlertingRule, obj client.Object) bool {
+	for _, connector := range rule.Spec.Connectors {
+		if connector.SecretName == obj.GetName() {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileRequestForRules returns the requests to reconcile the AlertingRule resources, in the namespace of the watched
+// object, that reference it.
+func reconcileRequestForRules(clnt k8s.Client, references func(*alertingv1alpha1.AlertingRule, client.Object) bool) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &alertingv1alpha1.AlertingRuleList{}, references)
+}
+
+// ruleKind configures AlertingRule resources in Kibana.
+type ruleKind struct {
+	k8s.Client
+	kbClientProvider kbclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*alertingv1alpha1.AlertingRule, alertingv1alpha1.AlertingRuleStatus] = &ruleKind{}
+	_ apiresource.Remover[*alertingv1alpha1.AlertingRule]                                   = &ruleKind{}
+)
+
+func (r *ruleKind) NewObject() *alertingv1alpha1.AlertingRule {
+	return &alertingv1alpha1.AlertingRule{}
+}
+
+func (r *ruleKind) GetStatus(rule *alertingv1alpha1.AlertingRule) alertingv1alpha1.AlertingRuleStatus {
+	return rule.Status
+}
+
+func (r *ruleKind) SetStatus(rule *alertingv1alpha1.AlertingRule, status alertingv1alpha1.AlertingRuleStatus) {
+	rule.Status = status
+}
+
+func (r *ruleKind) InvalidStatus(rule *alertingv1alpha1.AlertingRule, err error) alertingv1alpha1.AlertingRuleStatus {
+	status := rule.Status
+	status.Phase = alertingv1alpha1.InvalidPhase
+	status.Message = err.Error()
+	return status
+}
+
+// Configure configures the connectors and the rule on the referenced Kibana.
+func (r *ruleKind) Configure(ctx context.Context, rule *alertingv1alpha1.AlertingRule) (*reconciler.Results, alertingv1alpha1.AlertingRuleStatus) {
+	status := r.reconcileKibana(ctx, *rule)
+	return reconciler.NewResult(ctx).WithResult(apiresource.Requeue(status.Phase == alertingv1alpha1.ReadyPhase)), status
+}
+
+// Remove deletes the rule and its connectors from Kibana.
+func (r *ruleKind) Remove(ctx context.Context, rule *alertingv1alpha1.AlertingRule) (reconcile.Result, error) {
+	return reconcile.Result{}, r.deleteRule(ctx, *rule)
+}
@@ -0,0 +1,363 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package alertingrule
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	alertingv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/alerting/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeKbClient stores rules and connectors in memory, adding server-side fields to rules like Kibana.
+type fakeKbClient struct {
+	// Client is embedded for the APIs the controller does not use
+	kbclient.Client
+	kibana *fakeKibana
+}
+
+type fakeKibana struct {
+	rules      map[string]map[string]interface{}
+	connectors map[string]kbclient.Connector
+	updates    int
+}
+
+func newFakeKibana() *fakeKibana {
+	return &fakeKibana{rules: map[string]map[string]interface{}{}, connectors: map[string]kbclient.Connector{}}
+}
+
+func notFound() error {
+	return &commonhttp.APIError{StatusCode: http.StatusNotFound}
+}
+
+func (c fakeKbClient) GetRule(_ context.Context, id string) (map[string]interface{}, error) {
+	rule, exists := c.kibana.rules[id]
+	if !exists {
+		return nil, notFound()
+	}
+	return rule, nil
+}
+
+func (c fakeKbClient) CreateRule(_ context.Context, id string, rule map[string]interface{}) error {
+	if _, exists := c.kibana.rules[id]; exists {
+		return fmt.Errorf("rule %s already exists", id)
+	}
+	stored := map[string]interface{}{"id": id, "enabled": true, "execution_status": map[string]interface{}{"status": "ok"}}
+	for k, v := range rule {
+		stored[k] = v
+	}
+	c.kibana.rules[id] = stored
+	return nil
+}
+
+func (c fakeKbClient) UpdateRule(_ context.Context, id string, rule map[string]interface{}) error {
+	stored, exists := c.kibana.rules[id]
+	if !exists {
+		return notFound()
+	}
+	for _, field := range []string{"rule_type_id", "consumer", "enabled"} {
+		if _, exists := rule[field]; exists {
+			return fmt.Errorf("[request body.%s]: definition for this key is missing", field)
+		}
+	}
+	c.kibana.updates++
+	for k, v := range rule {
+		stored[k] = v
+	}
+	return nil
+}
+
+func (c fakeKbClient) EnableRule(_ context.Context, id string) error {
+	c.kibana.rules[id]["enabled"] = true
+	return nil
+}
+
+func (c fakeKbClient) DisableRule(_ context.Context, id string) error {
+	c.kibana.rules[id]["enabled"] = false
+	return nil
+}
+
+func (c fakeKbClient) DeleteRule(_ context.Context, id string) error {
+	if _, exists := c.kibana.rules[id]; !exists {
+		return notFound()
+	}
+	delete(c.kibana.rules, id)
+	return nil
+}
+
+func (c fakeKbClient) GetConnector(_ context.Context, id string) (kbclient.Connector, error) {
+	connector, exists := c.kibana.connectors[id]
+	if !exists {
+		return kbclient.Connector{}, notFound()
+	}
+	// secrets are never returned
+	connector.Secrets = nil
+	return connector, nil
+}
+
+func (c fakeKbClient) CreateConnector(_ context.Context, id string, connector kbclient.Connector) error {
+	c.kibana.connectors[id] = connector
+	return nil
+}
+
+func (c fakeKbClient) UpdateConnector(_ context.Context, id string, connector kbclient.Connector) error {
+	stored, exists := c.kibana.connectors[id]
+	if !exists {
+		return notFound()
+	}
+	c.kibana.updates++
+	connector.ConnectorTypeID = stored.ConnectorTypeID
+	c.kibana.connectors[id] = connector
+	return nil
+}
+
+func (c fakeKbClient) DeleteConnector(_ context.Context, id string) error {
+	if _, exists := c.kibana.connectors[id]; !exists {
+		return notFound()
+	}
+	delete(c.kibana.connectors, id)
+	return nil
+}
+
+func (c fakeKbClient) Close() {}
+
+func fakeClientProvider(kibana *fakeKibana) kbclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ kbv1.Kibana) (kbclient.Client, error) {
+		return fakeKbClient{kibana: kibana}, nil
+	}
+}
+
+func cpuRule(threshold float64) map[string]interface{} {
+	return map[string]interface{}{
+		"name":         "high-cpu",
+		"rule_type_id": ".index-threshold",
+		"consumer":     "alerts",
+		"schedule":     map[string]interface{}{"interval": "1m"},
+		"params":       map[string]interface{}{"index": []interface{}{"metrics-*"}, "threshold": []interface{}{threshold}},
+		"actions": []interface{}{
+			map[string]interface{}{"id": "ops-slack", "group": "threshold met", "params": map[string]interface{}{"message": "CPU is high"}},
+		},
+	}
+}
+
+func TestReconcileAlertingRule_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	rule := &alertingv1alpha1.AlertingRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "high-cpu", UID: "6f3b7a8e-2c2b-4bb3-9b0e-1e1e3a1f6c7d"},
+		Spec: alertingv1alpha1.AlertingRuleSpec{
+			KibanaRef: commonv1.LocalObjectSelector{Name: "kb"},
+			Rule:      &commonv1.Config{Data: cpuRule(90)},
+			Connectors: []alertingv1alpha1.Connector{
+				{Name: "ops-slack", ConnectorTypeID: ".slack", SecretName: "slack-webhook"},
+			},
+		},
+	}
+	kb := &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Status:     kbv1.KibanaStatus{DeploymentStatus: commonv1.DeploymentStatus{Health: commonv1.RedHealth}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "slack-webhook"},
+		Data:       map[string][]byte{"webhookUrl": []byte("https://hooks.slack.com/services/T0")},
+	}
+	kibana := newFakeKibana()
+	k8sClient := k8s.NewFakeClient(rule, kb, secret)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &ruleKind{
+		Client:           k8sClient,
+		kbClientProvider: fakeClientProvider(kibana),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "high-cpu"}}
+	getRule := func() alertingv1alpha1.AlertingRule {
+		var actual alertingv1alpha1.AlertingRule
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+	ruleID := string(rule.UID)
+
+	// nothing is configured until Kibana is ready
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	actual := getRule()
+	require.Equal(t, alertingv1alpha1.ApplyingChangesPhase, actual.Status.Phase)
+	require.Equal(t, []string{RuleFinalizer}, actual.Finalizers)
+	require.Empty(t, kibana.rules)
+
+	// the connector and the rule using it are configured once Kibana is ready
+	kb.Status.Health = commonv1.GreenHealth
+	require.NoError(t, k8sClient.Status().Update(ctx, kb))
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	actual = getRule()
+	require.Equal(t, alertingv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, ruleID, actual.Status.RuleID)
+	connectorID := actual.Status.ConnectorIDs["ops-slack"]
+	require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, connectorID)
+	require.Equal(t, kbclient.Connector{
+		Name:            "ops-slack",
+		ConnectorTypeID: ".slack",
+		Config:          map[string]interface{}{},
+		Secrets:         map[string]string{"webhookUrl": "https://hooks.slack.com/services/T0"},
+	}, kibana.connectors[connectorID])
+	// the secrets of the connectors are not part of the hash stored in the status, only the version of their Secret
+	var currentSecret corev1.Secret
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "slack-webhook"}, &currentSecret))
+	withSecrets := []expectedConnector{{
+		ID:            connectorID,
+		Connector:     kibana.connectors[connectorID],
+		SecretVersion: fmt.Sprintf("%s/%s", currentSecret.UID, currentSecret.ResourceVersion),
+	}}
+	require.NotEqual(t, hash.HashObject(withSecrets), actual.Status.ConnectorsHash)
+	withSecrets[0].Connector.Secrets = nil
+	require.Equal(t, hash.HashObject(withSecrets), actual.Status.ConnectorsHash)
+	actions, ok := kibana.rules[ruleID]["actions"].([]interface{})
+	require.True(t, ok)
+	require.Equal(t, connectorID, actions[0].(map[string]interface{})["id"]) //nolint:forcetypeassert
+
+	// the rule returned by Kibana with its server-side fields is not updated
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 0, kibana.updates)
+	require.Empty(t, recorder.Events)
+
+	// the rule is restored if it is modified or disabled in Kibana
+	kibana.rules[ruleID]["schedule"] = map[string]interface{}{"interval": "1h"}
+	kibana.rules[ruleID]["enabled"] = false
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"interval": "1m"}, kibana.rules[ruleID]["schedule"])
+	require.Equal(t, true, kibana.rules[ruleID]["enabled"])
+	require.NotNil(t, getRule().Status.LastDriftTime)
+	require.Contains(t, <-recorder.Events, "Rule high-cpu was modified in Kibana kb")
+
+	// the connector is updated when its secret changes, which is not a drift
+	secret.Data["webhookUrl"] = []byte("https://hooks.slack.com/services/T1")
+	require.NoError(t, k8sClient.Update(ctx, secret))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "https://hooks.slack.com/services/T1", kibana.connectors[connectorID].Secrets["webhookUrl"])
+	require.Empty(t, recorder.Events)
+
+	// the rule is recreated when its type changes, the connectors removed from the specification are deleted
+	actual = getRule()
+	actual.Generation++
+	changed := cpuRule(90)
+	changed["rule_type_id"] = ".es-query"
+	changed["actions"] = []interface{}{}
+	actual.Spec.Rule = &commonv1.Config{Data: changed}
+	actual.Spec.Connectors = nil
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, ".es-query", kibana.rules[ruleID]["rule_type_id"])
+	require.Empty(t, kibana.connectors)
+	actual = getRule()
+	require.Empty(t, actual.Status.ConnectorIDs)
+	require.Empty(t, recorder.Events)
+
+	// the rule is deleted from Kibana with the AlertingRule
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, kibana.rules)
+	err = k8sClient.Get(ctx, request.NamespacedName, &alertingv1alpha1.AlertingRule{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileAlertingRule_MissingSecret(t *testing.T) {
+	ctx := context.Background()
+	rule := &alertingv1alpha1.AlertingRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "high-cpu", UID: "6f3b7a8e-2c2b-4bb3-9b0e-1e1e3a1f6c7d"},
+		Spec: alertingv1alpha1.AlertingRuleSpec{
+			KibanaRef:  commonv1.LocalObjectSelector{Name: "kb"},
+			Rule:       &commonv1.Config{Data: cpuRule(90)},
+			Connectors: []alertingv1alpha1.Connector{{Name: "ops-slack", ConnectorTypeID: ".slack", SecretName: "slack-webhook"}},
+		},
+	}
+	kb := &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Status:     kbv1.KibanaStatus{DeploymentStatus: commonv1.DeploymentStatus{Health: commonv1.GreenHealth}},
+	}
+	kibana := newFakeKibana()
+	k8sClient := k8s.NewFakeClient(rule, kb)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &ruleKind{
+		Client:           k8sClient,
+		kbClientProvider: fakeClientProvider(kibana),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "high-cpu"}}
+
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	var actual alertingv1alpha1.AlertingRule
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+	require.Equal(t, alertingv1alpha1.ErrorPhase, actual.Status.Phase)
+	require.Equal(t, "secret slack-webhook of connector ops-slack not found", actual.Status.Message)
+	require.Empty(t, kibana.rules)
+	require.Empty(t, kibana.connectors)
+}
+
+func Test_contains(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected interface{}
+		actual   interface{}
+		want     bool
+	}{
+		{
+			name:     "server-side fields are ignored",
+			expected: map[string]interface{}{"name": "cpu", "params": map[string]interface{}{"threshold": []interface{}{90}}},
+			actual:   map[string]interface{}{"id": "1", "name": "cpu", "params": map[string]interface{}{"threshold": []interface{}{float64(90)}, "aggType": "count"}},
+			want:     true,
+		},
+		{
+			name:     "different value",
+			expected: map[string]interface{}{"schedule": map[string]interface{}{"interval": "1m"}},
+			actual:   map[string]interface{}{"schedule": map[string]interface{}{"interval": "5m"}},
+			want:     false,
+		},
+		{
+			name:     "missing action",
+			expected: map[string]interface{}{"actions": []interface{}{map[string]interface{}{"id": "1"}}},
+			actual:   map[string]interface{}{"actions": []interface{}{}},
+			want:     false,
+		},
+		{
+			name:     "empty expected value",
+			expected: map[string]interface{}{"tags": []interface{}{}, "config": map[string]interface{}{}},
+			actual:   map[string]interface{}{"tags": nil},
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, contains(tt.expected, tt.actual))
+		})
+	}
+}
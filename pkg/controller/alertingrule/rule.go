@@ -0,0 +1,388 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package alertingrule
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	alertingv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/alerting/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// updatableRuleFields are the fields accepted by the Kibana update rule API, which rejects the other ones.
+var updatableRuleFields = []string{"name", "tags", "schedule", "params", "actions", "throttle", "notify_when", "alert_delay"}
+
+// expectedConnector is a connector of the specification with its secrets and its id in Kibana.
+type expectedConnector struct {
+	ID        string
+	Connector kbclient.Connector
+	// SecretVersion is the UID and resource version of the Secret holding the secrets of the connector, if any.
+	SecretVersion string
+}
+
+// reconcileKibana configures the connectors and the rule on the referenced Kibana if they do not exist yet or if they
+// differ from the specification, and returns the status of the rule.
+func (r *ruleKind) reconcileKibana(ctx context.Context, rule alertingv1alpha1.AlertingRule) alertingv1alpha1.AlertingRuleStatus {
+	defer tracing.Span(&ctx)()
+	kbName := rule.Spec.KibanaRef.Name
+	log := ulog.FromContext(ctx).WithValues("kibana_name", kbName)
+
+	previous := rule.Status
+	status := alertingv1alpha1.AlertingRuleStatus{
+		Phase:              alertingv1alpha1.ReadyPhase,
+		RuleID:             ruleID(rule),
+		ConnectorIDs:       previous.ConnectorIDs,
+		ConnectorsHash:     previous.ConnectorsHash,
+		LastDriftTime:      previous.LastDriftTime,
+		ObservedGeneration: rule.Generation,
+	}
+	withPhase := func(phase alertingv1alpha1.Phase, msg string) alertingv1alpha1.AlertingRuleStatus {
+		status.Phase = phase
+		status.Message = msg
+		return status
+	}
+	failed := func(msg string) alertingv1alpha1.AlertingRuleStatus {
+		r.recorder.Event(&rule, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return withPhase(alertingv1alpha1.ErrorPhase, msg)
+	}
+
+	var kb kbv1.Kibana
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: rule.Namespace, Name: kbName}, &kb); err != nil {
+		if apierrors.IsNotFound(err) {
+			return withPhase(alertingv1alpha1.ErrorPhase, fmt.Sprintf("Kibana %s not found", kbName))
+		}
+		return withPhase(alertingv1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	if kb.Status.Health != commonv1.GreenHealth {
+		return withPhase(alertingv1alpha1.ApplyingChangesPhase, "Waiting for Kibana to be ready")
+	}
+
+	connectors, err := r.expectedConnectors(ctx, rule)
+	if err != nil {
+		return withPhase(alertingv1alpha1.ErrorPhase, err.Error())
+	}
+	connectorsHash := hashConnectors(connectors)
+
+	kbClient, err := r.kbClientProvider(ctx, r.Client, r.params.Dialer, kb)
+	if err != nil {
+		return withPhase(alertingv1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	defer kbClient.Close()
+
+	// changes are drifts if the rule was already configured from the current specification and connectors secrets
+	configured := previous.Phase == alertingv1alpha1.ReadyPhase && previous.ObservedGeneration == rule.Generation &&
+		previous.ConnectorsHash == connectorsHash
+
+	// keep track of the previous and current connectors to be able to delete them
+	status.ConnectorIDs = make(map[string]string, len(connectors))
+	for name, id := range previous.ConnectorIDs {
+		status.ConnectorIDs[name] = id
+	}
+	expectedIDs := make(map[string]string, len(connectors))
+	for _, connector := range connectors {
+		status.ConnectorIDs[connector.Connector.Name] = connector.ID
+		expectedIDs[connector.Connector.Name] = connector.ID
+	}
+
+	connectorsChanged, err := reconcileConnectors(ctx, kbClient, connectors, connectorsHash != previous.ConnectorsHash)
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to configure connectors on Kibana %s: %s", kbName, err.Error()))
+	}
+	status.ConnectorsHash = connectorsHash
+
+	ruleChanged, err := reconcileRule(ctx, kbClient, status.RuleID, ruleDefinition(rule, expectedIDs))
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to configure rule on Kibana %s: %s", kbName, err.Error()))
+	}
+
+	if configured && (connectorsChanged || ruleChanged) {
+		msg := fmt.Sprintf("Rule %s was modified in Kibana %s, restoring it from the AlertingRule specification", rule.Name, kbName)
+		status.LastDriftTime = apiresource.RecordDrift(log, r.recorder, &rule, msg)
+	}
+
+	// delete the connectors removed from the specification, now that the rule no longer uses them
+	for name, id := range previous.ConnectorIDs {
+		if _, exists := expectedIDs[name]; exists {
+			continue
+		}
+		log.Info("Deleting connector", "connector", name)
+		if err := kbClient.DeleteConnector(ctx, id); err != nil && !kbclient.IsNotFound(err) {
+			return withPhase(alertingv1alpha1.ApplyingChangesPhase, fmt.Sprintf("Failed to delete connector %s: %s", name, err.Error()))
+		}
+		delete(status.ConnectorIDs, name)
+	}
+	if len(status.ConnectorIDs) == 0 {
+		status.ConnectorIDs = nil
+	}
+	return status
+}
+
+// expectedConnectors returns the connectors of the specification with their secrets read from the referenced Secrets.
+func (r *ruleKind) expectedConnectors(ctx context.Context, rule alertingv1alpha1.AlertingRule) ([]expectedConnector, error) {
+	connectors := make([]expectedConnector, 0, len(rule.Spec.Connectors))
+	for _, spec := range rule.Spec.Connectors {
+		connector := kbclient.Connector{Name: spec.Name, ConnectorTypeID: spec.ConnectorTypeID, Config: map[string]interface{}{}}
+		var secretVersion string
+		if spec.Config != nil {
+			connector.Config = spec.Config.Data
+		}
+		if spec.SecretName != "" {
+			var secret corev1.Secret
+			if err := r.Client.Get(ctx, types.NamespacedName{Namespace: rule.Namespace, Name: spec.SecretName}, &secret); err != nil {
+				if apierrors.IsNotFound(err) {
+					return nil, fmt.Errorf("secret %s of connector %s not found", spec.SecretName, spec.Name)
+				}
+				return nil, err
+			}
+			connector.Secrets = make(map[string]string, len(secret.Data))
+			for k, v := range secret.Data {
+				connector.Secrets[k] = string(v)
+			}
+			secretVersion = fmt.Sprintf("%s/%s", secret.UID, secret.ResourceVersion)
+		}
+		connectors = append(connectors, expectedConnector{ID: connectorID(rule, spec.Name), Connector: connector, SecretVersion: secretVersion})
+	}
+	return connectors, nil
+}
+
+// hashConnectors returns a hash of the given connectors. Kibana never returns the secrets of connectors, the hash tells
+// whether they changed since the last update through the version of the Secrets holding them, so that the secrets
+// themselves are not hashed into the status.
+func hashConnectors(connectors []expectedConnector) string {
+	withoutSecrets := make([]expectedConnector, len(connectors))
+	for i, connector := range connectors {
+		connector.Connector.Secrets = nil
+		withoutSecrets[i] = connector
+	}
+	return hash.HashObject(withoutSecrets)
+}
+
+// reconcileConnectors creates the missing connectors and updates the connectors that differ from the specification, or
+// all of them if their secrets may have changed since Kibana does not return them. It returns true if connectors were
+// missing or different.
+func reconcileConnectors(ctx context.Context, kbClient kbclient.Client, connectors []expectedConnector, secretsChanged bool) (bool, error) {
+	log := ulog.FromContext(ctx)
+	changed := false
+	for _, expected := range connectors {
+		actual, err := kbClient.GetConnector(ctx, expected.ID)
+		switch {
+		case kbclient.IsNotFound(err):
+			changed = true
+			log.Info("Creating connector", "connector", expected.Connector.Name)
+			if err := kbClient.CreateConnector(ctx, expected.ID, expected.Connector); err != nil {
+				return false, err
+			}
+		case err != nil:
+			return false, err
+		case actual.ConnectorTypeID != expected.Connector.ConnectorTypeID:
+			// the type of a connector cannot be updated
+			changed = true
+			log.Info("Recreating connector", "connector", expected.Connector.Name)
+			if err := kbClient.DeleteConnector(ctx, expected.ID); err != nil {
+				return false, err
+			}
+			if err := kbClient.CreateConnector(ctx, expected.ID, expected.Connector); err != nil {
+				return false, err
+			}
+		case secretsChanged || actual.Name != expected.Connector.Name || !contains(expected.Connector.Config, actual.Config):
+			changed = changed || !secretsChanged
+			log.Info("Updating connector", "connector", expected.Connector.Name)
+			if err := kbClient.UpdateConnector(ctx, expected.ID, expected.Connector); err != nil {
+				return false, err
+			}
+		}
+	}
+	return changed, nil
+}
+
+// reconcileRule creates the rule if it does not exist, recreates it if its type or consumer changed, and updates it if it
+// differs from the expected definition. It returns true if the rule was missing or different.
+func reconcileRule(ctx context.Context, kbClient kbclient.Client, id string, expected map[string]interface{}) (bool, error) {
+	log := ulog.FromContext(ctx)
+	actual, err := kbClient.GetRule(ctx, id)
+	if kbclient.IsNotFound(err) {
+		log.Info("Creating rule", "rule_id", id)
+		return true, kbClient.CreateRule(ctx, id, expected)
+	}
+	if err != nil {
+		return false, err
+	}
+	if !contains(expected["rule_type_id"], actual["rule_type_id"]) || !contains(expected["consumer"], actual["consumer"]) {
+		// the type and the consumer of a rule cannot be updated
+		log.Info("Recreating rule", "rule_id", id)
+		if err := kbClient.DeleteRule(ctx, id); err != nil {
+			return false, err
+		}
+		return true, kbClient.CreateRule(ctx, id, expected)
+	}
+
+	changed := false
+	update := make(map[string]interface{}, len(updatableRuleFields))
+	for _, field := range updatableRuleFields {
+		if value, exists := expected[field]; exists {
+			update[field] = value
+		}
+	}
+	if !contains(update, actual) {
+		changed = true
+		log.Info("Updating rule", "rule_id", id)
+		if err := kbClient.UpdateRule(ctx, id, update); err != nil {
+			return false, err
+		}
+	}
+
+	enabled := true
+	if value, ok := expected["enabled"].(bool); ok {
+		enabled = value
+	}
+	if actualEnabled, _ := actual["enabled"].(bool); actualEnabled != enabled {
+		changed = true
+		if enabled {
+			return changed, kbClient.EnableRule(ctx, id)
+		}
+		return changed, kbClient.DisableRule(ctx, id)
+	}
+	return changed, nil
+}
+
+// deleteRule deletes the rule and its connectors from Kibana.
+func (r *ruleKind) deleteRule(ctx context.Context, rule alertingv1alpha1.AlertingRule) error {
+	defer tracing.Span(&ctx)()
+
+	var kb kbv1.Kibana
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: rule.Namespace, Name: rule.Spec.KibanaRef.Name}, &kb); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	kbClient, err := r.kbClientProvider(ctx, r.Client, r.params.Dialer, kb)
+	if err != nil {
+		return err
+	}
+	defer kbClient.Close()
+
+	id := ruleID(rule)
+	ulog.FromContext(ctx).Info("Deleting rule", "rule_id", id, "kibana_name", kb.Name)
+	if err := kbClient.DeleteRule(ctx, id); err != nil && !kbclient.IsNotFound(err) {
+		return err
+	}
+	for _, connectorID := range rule.Status.ConnectorIDs {
+		if err := kbClient.DeleteConnector(ctx, connectorID); err != nil && !kbclient.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ruleDefinition returns the definition of the rule of the specification where the ids of the actions that are the names
+// of connectors of the specification are replaced by the ids of these connectors in Kibana.
+func ruleDefinition(rule alertingv1alpha1.AlertingRule, connectorIDs map[string]string) map[string]interface{} {
+	definition := make(map[string]interface{}, len(rule.Spec.Rule.Data))
+	for k, v := range rule.Spec.Rule.Data {
+		definition[k] = v
+	}
+	actions, ok := definition["actions"].([]interface{})
+	if !ok {
+		return definition
+	}
+	resolved := make([]interface{}, 0, len(actions))
+	for _, action := range actions {
+		actionMap, ok := action.(map[string]interface{})
+		if !ok {
+			resolved = append(resolved, action)
+			continue
+		}
+		name, _ := actionMap["id"].(string)
+		id, exists := connectorIDs[name]
+		if !exists {
+			resolved = append(resolved, action)
+			continue
+		}
+		resolvedAction := make(map[string]interface{}, len(actionMap))
+		for k, v := range actionMap {
+			resolvedAction[k] = v
+		}
+		resolvedAction["id"] = id
+		resolved = append(resolved, resolvedAction)
+	}
+	definition["actions"] = resolved
+	return definition
+}
+
+// ruleID returns the id of the rule in Kibana, the UID of the AlertingRule, which Kibana accepts as a UUID v4.
+func ruleID(rule alertingv1alpha1.AlertingRule) string {
+	if rule.Status.RuleID != "" {
+		return rule.Status.RuleID
+	}
+	return string(rule.UID)
+}
+
+// connectorID returns the id of a connector in Kibana, a UUID v4 derived from the UID of the AlertingRule and the name
+// of the connector.
+func connectorID(rule alertingv1alpha1.AlertingRule, name string) string {
+	if id, exists := rule.Status.ConnectorIDs[name]; exists {
+		return id
+	}
+	sum := sha256.Sum256([]byte(string(rule.UID) + "/" + name))
+	sum[6] = (sum[6] & 0x0f) | 0x40 // version 4
+	sum[8] = (sum[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// contains returns true if the expected value is a subset of the actual value returned by Kibana, which adds defaults
+// and server-side fields to rules and connectors.
+func contains(expected, actual interface{}) bool {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualMap, ok := actual.(map[string]interface{})
+		if !ok {
+			return len(expectedValue) == 0 && actual == nil
+		}
+		for k, v := range expectedValue {
+			if !contains(v, actualMap[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		actualSlice, ok := actual.([]interface{})
+		if !ok || len(actualSlice) != len(expectedValue) {
+			return len(expectedValue) == 0 && actual == nil
+		}
+		for i := range expectedValue {
+			if !contains(expectedValue[i], actualSlice[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		// compare the JSON representations of the values to ignore the differences of numeric types
+		expectedBytes, err := json.Marshal(expected)
+		if err != nil {
+			return false
+		}
+		actualBytes, err := json.Marshal(actual)
+		if err != nil {
+			return false
+		}
+		return string(expectedBytes) == string(actualBytes)
+	}
+}
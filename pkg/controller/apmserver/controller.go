@@ -13,6 +13,7 @@ import (
 	"go.elastic.co/apm/v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,10 +32,14 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/externaldns"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/finalizer"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/ingress"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/queue"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
@@ -98,7 +103,7 @@ func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileAp
 
 func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileApmServer) error {
 	// Watch for changes to ApmServer
-	err := c.Watch(source.Kind(mgr.GetCache(), &apmv1.ApmServer{}, &handler.TypedEnqueueRequestForObject[*apmv1.ApmServer]{}))
+	err := c.Watch(source.Kind(mgr.GetCache(), &apmv1.ApmServer{}, queue.EnqueueRequestForObject[*apmv1.ApmServer]()))
 	if err != nil {
 		return err
 	}
@@ -125,8 +130,21 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileApmSer
 		return err
 	}
 
+	// Watch ingresses
+	if err := c.Watch(source.Kind(mgr.GetCache(), &networkingv1.Ingress{}, handler.TypedEnqueueRequestForOwner[*networkingv1.Ingress](
+		mgr.GetScheme(), mgr.GetRESTMapper(),
+		&apmv1.ApmServer{}, handler.OnlyControllerOwner(),
+	))); err != nil {
+		return err
+	}
+
+	// Watch Gateway routes
+	if err := gateway.WatchRoutes(mgr, c, &apmv1.ApmServer{}); err != nil {
+		return err
+	}
+
 	// Watch owned and soft-owned secrets
-	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
+	if err := c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
 		mgr.GetScheme(), mgr.GetRESTMapper(),
 		&apmv1.ApmServer{}, handler.OnlyControllerOwner(),
 	))); err != nil {
@@ -137,7 +155,7 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileApmSer
 	}
 
 	// dynamically watch referenced secrets to connect to Elasticsearch
-	return c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets))
+	return c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets))
 }
 
 var _ reconcile.Reconciler = &ReconcileApmServer{}
@@ -235,12 +253,13 @@ func (r *ReconcileApmServer) doReconcile(ctx context.Context, as *apmv1.ApmServe
 		DynamicWatches:        r.DynamicWatches(),
 		Owner:                 as,
 		TLSOptions:            as.Spec.HTTP.TLS,
+		ExtraHTTPSANs:         as.Spec.HTTP.ExternalSANs(),
 		Namer:                 Namer,
 		Labels:                as.GetIdentityLabels(),
 		Services:              []corev1.Service{*svc},
 		GlobalCA:              r.GlobalCA,
-		CACertRotation:        r.CACertRotation,
-		CertRotation:          r.CertRotation,
+		CACertRotation:        r.CACertRotation.Get(),
+		CertRotation:          r.CertRotation.Get(),
 		GarbageCollectSecrets: true,
 	}.ReconcileCAAndHTTPCerts(ctx)
 	if results.HasError() {
@@ -249,6 +268,20 @@ func (r *ReconcileApmServer) doReconcile(ctx context.Context, as *apmv1.ApmServe
 		return results, state
 	}
 
+	exposureParams := ingress.Params{
+		Owner:   as,
+		HTTP:    as.Spec.HTTP,
+		Service: *svc,
+		Labels:  as.GetIdentityLabels(),
+		Namer:   Namer,
+	}
+	if err := ingress.Reconcile(ctx, r.Client, exposureParams); err != nil {
+		return results.WithError(err), state
+	}
+	if err := gateway.Reconcile(ctx, r.Client, exposureParams); err != nil {
+		return results.WithError(err), state
+	}
+
 	asVersion, err := version.Parse(as.Spec.Version)
 	if err != nil {
 		return results.WithError(err), state
@@ -357,6 +390,7 @@ func NewService(as apmv1.ApmServer) *corev1.Service {
 
 	svc.ObjectMeta.Namespace = as.Namespace
 	svc.ObjectMeta.Name = HTTPService(as.Name)
+	svc.ObjectMeta.Annotations = externaldns.ServiceAnnotations(as.Spec.HTTP)
 
 	labels := as.GetIdentityLabels()
 	ports := []corev1.ServicePort{
@@ -61,10 +61,10 @@ func TestReconcileApmServer_doReconcile(t *testing.T) {
 				recorder:       record.NewFakeRecorder(100),
 				dynamicWatches: watches.NewDynamicWatches(),
 				Parameters: operator.Parameters{
-					CACertRotation: certificates.RotationParams{
+					CACertRotation: certificates.NewDynamicRotationParams(certificates.RotationParams{
 						Validity:     certificates.DefaultCertValidity,
 						RotateBefore: certificates.DefaultRotateBefore,
-					},
+					}),
 				},
 			},
 			wantRequeue: false,
@@ -65,7 +65,7 @@ func (r *ReconcileApmServer) reconcileApmServerDeployment(ctx context.Context, s
 	}
 
 	deploy := deployment.New(params)
-	result, err := deployment.Reconcile(ctx, r.K8sClient(), deploy, as)
+	result, err := deployment.Reconcile(ctx, r.K8sClient(), deploy, as, true)
 	if err != nil {
 		return state, err
 	}
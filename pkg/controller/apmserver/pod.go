@@ -18,6 +18,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemesh"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
@@ -135,12 +136,17 @@ func newPodSpec(c k8s.Client, as *apmv1.ApmServer, p PodSpecParams) (corev1.PodT
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	image, err := container.ResolveImage(p.CustomImageName, container.APMServerImage, v)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	builder := defaults.NewPodTemplateBuilder(p.PodTemplate, apmv1.ApmServerContainerName).
 		WithLabels(labels).
 		WithAnnotations(annotations).
+		WithAnnotations(servicemesh.PodAnnotations(as.Spec.HTTP.TLS)).
 		WithResources(DefaultResources).
-		WithDockerImage(p.CustomImageName, container.ImageRepository(container.APMServerImage, v)).
+		WithDockerImage(p.CustomImageName, image).
 		WithReadinessProbe(readinessProbe(as.Spec.HTTP.TLS.Enabled())).
 		WithPorts(ports).
 		WithCommand(command).
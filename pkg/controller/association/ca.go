@@ -5,7 +5,10 @@
 package association
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"maps"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -32,8 +35,9 @@ func CACertSecretName(association commonv1.Association, associationName string)
 	return commonv1.FormatNameWithID(associatedName+"-"+associationName+"%s-ca", association.AssociationID())
 }
 
-// ReconcileCASecret keeps in sync a copy of the target service CA.
-// It is the responsibility of the association controller to set a watch on this CA.
+// ReconcileCASecret keeps in sync a copy of the target service CA, extended with the additional CA certificates of the
+// Secret referenced by caSecretName, if any.
+// It is the responsibility of the association controller to set a watch on these CAs.
 func (r *Reconciler) ReconcileCASecret(ctx context.Context, association commonv1.Association, namer name.Namer, associatedResource types.NamespacedName) (CASecret, error) {
 	associatedPublicHTTPCertificatesNSN := certificates.PublicCertsSecretRef(namer, associatedResource)
 
@@ -46,6 +50,16 @@ func (r *Reconciler) ReconcileCASecret(ctx context.Context, association commonv1
 		return CASecret{}, err
 	}
 
+	data := associatedPublicHTTPCertificatesSecret.Data
+	if caSecretName := association.AssociationRef().CASecretName; caSecretName != "" {
+		additionalCAs, err := r.additionalCACerts(ctx, types.NamespacedName{Namespace: association.GetNamespace(), Name: caSecretName})
+		if err != nil {
+			return CASecret{}, err
+		}
+		data = maps.Clone(data)
+		data[certificates.CAFileName] = appendPEM(data[certificates.CAFileName], additionalCAs)
+	}
+
 	labels := r.AssociationResourceLabels(k8s.ExtractNamespacedName(association), association.AssociationRef().NamespacedName())
 	// Certificate data should be copied over a secret in the association namespace
 	expectedSecret := corev1.Secret{
@@ -54,7 +68,7 @@ func (r *Reconciler) ReconcileCASecret(ctx context.Context, association commonv1
 			Name:      CACertSecretName(association, r.AssociationName),
 			Labels:    labels,
 		},
-		Data: associatedPublicHTTPCertificatesSecret.Data,
+		Data: data,
 	}
 	if _, err := reconciler.ReconcileSecret(ctx, r, expectedSecret, association.Associated()); err != nil {
 		return CASecret{}, err
@@ -63,3 +77,43 @@ func (r *Reconciler) ReconcileCASecret(ctx context.Context, association commonv1
 	caCertProvided := len(expectedSecret.Data[certificates.CAFileName]) > 0
 	return CASecret{Name: expectedSecret.Name, CACertProvided: caCertProvided}, nil
 }
+
+// filterWithCASecretName returns the associations which reference a Secret of additional CA certificates.
+func filterWithCASecretName(associations []commonv1.Association) []commonv1.Association {
+	var r []commonv1.Association
+	for _, a := range associations {
+		if a.AssociationRef().CASecretName != "" {
+			r = append(r, a)
+		}
+	}
+	return r
+}
+
+// additionalCACerts returns the PEM encoded CA certificates of the given Secret, referenced by caSecretName.
+func (r *Reconciler) additionalCACerts(ctx context.Context, secretName types.NamespacedName) ([]byte, error) {
+	var secret corev1.Secret
+	if err := r.Get(ctx, secretName, &secret); err != nil {
+		return nil, err
+	}
+	caCerts := secret.Data[certificates.CAFileName]
+	if len(caCerts) == 0 {
+		return nil, fmt.Errorf("no %s entry in the additional CA Secret %s", certificates.CAFileName, secretName)
+	}
+	certs, err := certificates.ParsePEMCerts(caCerts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s entry in the additional CA Secret %s: %w", certificates.CAFileName, secretName, err)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificate in the %s entry of the additional CA Secret %s", certificates.CAFileName, secretName)
+	}
+	return caCerts, nil
+}
+
+// appendPEM appends the given PEM encoded certificates to the bundle, on a new line.
+func appendPEM(bundle, certs []byte) []byte {
+	result := bytes.Clone(bundle)
+	if len(result) > 0 && !bytes.HasSuffix(result, []byte("\n")) {
+		result = append(result, '\n')
+	}
+	return append(result, certs...)
+}
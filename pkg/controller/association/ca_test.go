@@ -12,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -206,3 +207,84 @@ func TestReconcileAssociation_reconcileCASecret(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileAssociation_reconcileCASecret_additionalCAs(t *testing.T) {
+	proxyCA, err := certificates.NewSelfSignedCA(certificates.CABuilderOptions{})
+	require.NoError(t, err)
+	proxyCAPEM := certificates.EncodePEMCert(proxyCA.Cert.Raw)
+
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "es"}}
+	esCA := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: certificates.PublicCertsSecretName(esv1.ESNamer, es.Name)},
+		Data: map[string][]byte{
+			certificates.CertFileName: []byte("es-cert"),
+			certificates.CAFileName:   []byte("es-ca-cert"),
+		},
+	}
+	kibana := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kibana-ns", Name: "kibana"},
+		Spec: kbv1.KibanaSpec{ElasticsearchRef: commonv1.ObjectSelector{
+			Name: es.Name, Namespace: es.Namespace, CASecretName: "proxy-ca",
+		}},
+	}
+	additionalCA := func(data []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kibana-ns", Name: "proxy-ca"},
+			Data:       map[string][]byte{certificates.CAFileName: data},
+		}
+	}
+	tests := []struct {
+		name    string
+		objects []client.Object
+		wantCA  []byte
+		wantErr bool
+	}{
+		{
+			name:    "additional CAs appended to the Elasticsearch CA",
+			objects: []client.Object{&es, &esCA, additionalCA(proxyCAPEM)},
+			wantCA:  append([]byte("es-ca-cert\n"), proxyCAPEM...),
+		},
+		{
+			name:    "additional CA Secret not found",
+			objects: []client.Object{&es, &esCA},
+			wantErr: true,
+		},
+		{
+			name:    "invalid additional CAs",
+			objects: []client.Object{&es, &esCA, additionalCA([]byte("not a certificate"))},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(tt.objects...)
+			r := &Reconciler{
+				AssociationInfo: AssociationInfo{
+					Labels: func(associated types.NamespacedName) map[string]string {
+						return map[string]string{}
+					},
+					AssociationName:                       "kibana-es",
+					AssociationResourceNameLabelName:      "elasticsearch.k8s.elastic.co/cluster-name",
+					AssociationResourceNamespaceLabelName: "elasticsearch.k8s.elastic.co/cluster-namespace",
+				},
+				Client: c,
+			}
+			got, err := r.ReconcileCASecret(context.Background(), kibana.EsAssociation(), esv1.ESNamer, k8s.ExtractNamespacedName(&es))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, got.CACertProvided)
+
+			var kibanaCA corev1.Secret
+			require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "kibana-ns", Name: got.Name}, &kibanaCA))
+			require.Equal(t, tt.wantCA, kibanaCA.Data[certificates.CAFileName])
+			require.Equal(t, []byte("es-cert"), kibanaCA.Data[certificates.CertFileName])
+			// the Secret of the Elasticsearch CA is left untouched
+			var actualESCA corev1.Secret
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&esCA), &actualESCA))
+			require.Equal(t, []byte("es-ca-cert"), actualESCA.Data[certificates.CAFileName])
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/queue"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
 )
@@ -43,12 +44,12 @@ func AddAssociationController(
 
 func addWatches(mgr manager.Manager, c controller.Controller, r *Reconciler) error {
 	// Watch the associated resource (e.g. Kibana for a Kibana -> Elasticsearch association)
-	if err := c.Watch(source.Kind(mgr.GetCache(), r.AssociatedObjTemplate(), &handler.TypedEnqueueRequestForObject[commonv1.Associated]{})); err != nil {
+	if err := c.Watch(source.Kind(mgr.GetCache(), r.AssociatedObjTemplate(), queue.EnqueueRequestForObject[commonv1.Associated]())); err != nil {
 		return err
 	}
 
 	// Watch Secrets owned by the associated resource
-	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
+	if err := c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
 		mgr.GetScheme(), mgr.GetRESTMapper(),
 		r.AssociatedObjTemplate(), handler.OnlyControllerOwner(),
 	))); err != nil {
@@ -61,7 +62,7 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *Reconciler) err
 	}
 
 	// Dynamically watch Secrets (CA Secret of the referenced resource, ES user secret or custom referenced object secret)
-	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, r.watches.Secrets)); err != nil {
+	if err := c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, r.watches.Secrets)); err != nil {
 		return err
 	}
 
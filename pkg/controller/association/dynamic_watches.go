@@ -26,6 +26,12 @@ func referencedResourceCASecretWatchName(associated types.NamespacedName) string
 	return fmt.Sprintf("%s-%s-referenced-resource-ca-secret-watch", associated.Namespace, associated.Name)
 }
 
+// additionalCASecretWatchName is the name of the watch set on the Secrets containing the additional CA certificates
+// referenced by caSecretName.
+func additionalCASecretWatchName(associated types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-additional-ca-secret-watch", associated.Namespace, associated.Name)
+}
+
 // esUserWatchName returns the name of the watch setup on the ES user secret.
 func esUserWatchName(associated types.NamespacedName) string {
 	return fmt.Sprintf("%s-%s-es-user-watch", associated.Namespace, associated.Name)
@@ -46,6 +52,7 @@ func additionalSecretWatchName(associated types.NamespacedName) string {
 // reconcileWatches sets up dynamic watches for:
 // * the referenced resource(s) managed or not by ECK (e.g. Elasticsearch for Kibana -> Elasticsearch associations)
 // * the CA secret of the referenced resource in the referenced resource namespace
+// * the secret of additional CA certificates in the associated resource namespace
 // * the referenced service to access the referenced resource
 // * the referenced secret to access the referenced resource
 // * if there's an ES user to create, watch the user Secret in ES namespace
@@ -80,6 +87,16 @@ func (r *Reconciler) reconcileWatches(ctx context.Context, associated types.Name
 		return err
 	}
 
+	// watch the additional CA certificates users may have set up in the associated resource namespace
+	if err := ReconcileWatch(associated, filterWithCASecretName(managedElasticRef), r.watches.Secrets, additionalCASecretWatchName(associated), func(association commonv1.Association) types.NamespacedName {
+		return types.NamespacedName{
+			Name:      association.AssociationRef().CASecretName,
+			Namespace: association.GetNamespace(),
+		}
+	}); err != nil {
+		return err
+	}
+
 	// watch the custom services users may have setup to be able to react to updates on services that are not error related
 	// (error related updates are covered by re-queueing on unsuccessful reconciliation)
 	if err := ReconcileWatch(associated, filterWithServiceName(associations), r.watches.Services, serviceWatchName(associated), func(association commonv1.Association) types.NamespacedName {
@@ -185,6 +202,8 @@ func (r *Reconciler) removeWatches(associated types.NamespacedName) {
 	RemoveWatch(r.watches.ReferencedResources, referencedResourceWatchName(associated))
 	// - CA secret in referenced resource namespace
 	RemoveWatch(r.watches.Secrets, referencedResourceCASecretWatchName(associated))
+	// - additional CA secret in resource namespace
+	RemoveWatch(r.watches.Secrets, additionalCASecretWatchName(associated))
 	// - custom service watch in resource namespace
 	RemoveWatch(r.watches.Services, serviceWatchName(associated))
 	// - ES user secret
@@ -65,11 +65,11 @@ func CheckAndUnbind(
 
 // RequeueRbacCheck returns a reconcile result depending on the implementation of the AccessReviewer.
 // It is mostly used when using the subjectAccessReviewer implementation in which case a next reconcile loop should be
-// triggered later to keep the association in sync with the RBAC roles and bindings.
+// triggered later to keep the association in sync with the RBAC roles and bindings, and the association policies.
 // See https://github.com/elastic/cloud-on-k8s/issues/2468#issuecomment-579157063
 func RequeueRbacCheck(accessReviewer rbac.AccessReviewer) reconcile.Result {
 	switch accessReviewer.(type) {
-	case *rbac.SubjectAccessReviewer:
+	case *rbac.SubjectAccessReviewer, *rbac.AssociationPolicyReviewer:
 		return reconcile.Result{RequeueAfter: 15 * time.Minute}
 	default:
 		return reconcile.Result{}
@@ -17,6 +17,7 @@ import (
 	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
 )
 
@@ -145,6 +146,11 @@ func TestNextReconciliation(t *testing.T) {
 			args:                args{accessReviewer: rbac.NewSubjectAccessReviewer(fake.NewSimpleClientset())},
 			wantNonZeroDuration: true,
 		},
+		{
+			name:                "Schedule a requeue if access is controlled by association policies",
+			args:                args{accessReviewer: rbac.NewAssociationPolicyReviewer(k8s.NewFakeClient(), rbac.NewSubjectAccessReviewer(fake.NewSimpleClientset()))},
+			wantNonZeroDuration: true,
+		},
 		{
 			name:                "No requeue if there is no access control",
 			args:                args{accessReviewer: rbac.NewPermissiveAccessReviewer()},
@@ -221,6 +221,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	}
 	return results.
 		WithResult(RequeueRbacCheck(r.accessReviewer)).
+		WithResult(requeueTokenRotation(r.ServiceAccountTokenRotation)).
 		WithResult(resultFromStatuses(newStatusMap)).
 		Aggregate()
 }
@@ -379,6 +380,7 @@ func (r *Reconciler) reconcileAssociation(ctx context.Context, association commo
 			serviceAccount,
 			association.GetName(),
 			association.GetUID(),
+			r.ServiceAccountTokenRotation,
 		)
 		if err != nil {
 			return commonv1.AssociationFailed, err
@@ -34,6 +34,7 @@ import (
 	eslabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/cryptutil"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
 )
@@ -696,6 +697,9 @@ func TestReconciler_Reconcile_CustomServiceRef(t *testing.T) {
 }
 
 func TestReconciler_Reconcile_ExistingAssociation_NoOp(t *testing.T) {
+	if cryptutil.FIPSEnabled() {
+		t.Skip("fixtures use bcrypt password hashes, replaced with PBKDF2 with FIPS validated cryptography")
+	}
 	// association already established, reconciliation should be a no-op
 	kb := sampleAssociatedKibana()
 	r := testReconciler(&kb, &sampleES, &kibanaUserInESNamespace, &kibanaUserInKibanaNamespace, &esHTTPPublicCertsSecret, &esCertsInKibanaNamespace, esHTTPService())
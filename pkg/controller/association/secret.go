@@ -171,7 +171,7 @@ func (r UnmanagedAssociationConnectionInfo) Request(path string, out interface{}
 	}
 
 	httpClient := &http.Client{
-		Timeout: client.DefaultESClientTimeout,
+		Timeout: client.DefaultTimeout(),
 	}
 	// configure CA if it exists
 	if r.CaCert != "" {
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.elastic.co/apm/v2"
 	"golang.org/x/crypto/pbkdf2"
@@ -21,6 +22,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -39,6 +41,16 @@ const (
 
 	ServiceAccountNameField       = "serviceAccount"
 	ServiceAccountTokenValueField = "token"
+	// ServiceAccountTokenCreatedAtField records in the application Secret when the token was created, to rotate it. It is
+	// only set when the tokens are rotated.
+	ServiceAccountTokenCreatedAtField = "createdAt"
+	// serviceAccountPreviousTokenExpiresAtField records in the Elasticsearch Secret until when the previous token remains
+	// valid after a rotation.
+	serviceAccountPreviousTokenExpiresAtField = "previousExpiresAt"
+
+	// serviceAccountTokenGracePeriod is how long the previous token remains valid after a rotation, so that the Pods still
+	// using it keep access to Elasticsearch while they are replaced.
+	serviceAccountTokenGracePeriod = time.Hour
 )
 
 func applicationSecretLabels(es esv1.Elasticsearch) map[string]string {
@@ -65,6 +77,8 @@ func reconcileApplicationSecret(
 	commonLabels map[string]string,
 	tokenName string,
 	serviceAccount commonv1.ServiceAccountName,
+	rotation time.Duration,
+	now time.Time,
 ) (*Token, error) {
 	span, ctx := apm.StartSpan(ctx, "reconcile_sa_token_application", tracing.SpanTypeApp)
 	defer span.End()
@@ -78,13 +92,13 @@ func reconcileApplicationSecret(
 	var token *Token
 	if k8serrors.IsNotFound(err) || len(applicationStore.Data) == 0 {
 		// Secret does not exist or is empty, create a new token
-		token, err = newApplicationToken(serviceAccount, tokenName)
+		token, err = newApplicationToken(serviceAccount, tokenName, rotation, now)
 		if err != nil {
 			return nil, err
 		}
 	} else {
 		// Attempt to read current token, create a new one in case of an error.
-		token, err = getOrCreateToken(ctx, &es, applicationSecretName.Name, applicationStore.Data, serviceAccount, tokenName)
+		token, err = getOrCreateToken(ctx, &es, applicationSecretName.Name, applicationStore.Data, serviceAccount, tokenName, rotation, now)
 		if err != nil {
 			return nil, err
 		}
@@ -107,6 +121,9 @@ func reconcileApplicationSecret(
 			ServiceAccountNameField:             []byte(token.ServiceAccountName),
 		},
 	}
+	if !token.CreatedAt.IsZero() {
+		applicationStore.Data[ServiceAccountTokenCreatedAtField] = []byte(token.CreatedAt.Format(time.RFC3339))
+	}
 
 	if _, err := reconciler.ReconcileSecret(ctx, client, applicationStore, nil); err != nil {
 		return nil, err
@@ -122,13 +139,28 @@ func getOrCreateToken(
 	secretData map[string][]byte,
 	serviceAccountName commonv1.ServiceAccountName,
 	tokenName string,
+	rotation time.Duration,
+	now time.Time,
 ) (*Token, error) {
 	token := getCurrentApplicationToken(ctx, es, secretName, secretData)
 	if token == nil {
 		// We need to create a new token
-		return newApplicationToken(serviceAccountName, tokenName)
+		return newApplicationToken(serviceAccountName, tokenName, rotation, now)
+	}
+	if rotation <= 0 {
+		return token, nil
+	}
+	if token.CreatedAt.IsZero() {
+		// token created before the rotation was enabled, start counting from now
+		token.CreatedAt = now
+		return token, nil
 	}
-	return token, nil
+	if now.Before(token.CreatedAt.Add(rotation)) {
+		return token, nil
+	}
+	ulog.FromContext(ctx).Info("Rotating service account token", "es_name", es.Name, "namespace", es.Namespace, "secret", secretName)
+	// the new token has a different name, so that Elasticsearch accepts both tokens during the grace period
+	return newApplicationToken(serviceAccountName, fmt.Sprintf("%s_%d", tokenName, now.Unix()), rotation, now)
 }
 
 // reconcileElasticsearchSecret ensures the Secret for Elasticsearch exists and hold the expected token.
@@ -139,9 +171,14 @@ func reconcileElasticsearchSecret(
 	elasticsearchSecretName types.NamespacedName,
 	commonLabels map[string]string,
 	token Token,
+	now time.Time,
 ) error {
 	span, ctx := apm.StartSpan(ctx, "reconcile_sa_token_elasticsearch", tracing.SpanTypeApp)
 	defer span.End()
+	var current corev1.Secret
+	if err := client.Get(ctx, elasticsearchSecretName, &current); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
 	fullyQualifiedName := token.ServiceAccountName + "/" + token.TokenName
 	labels := esSecretsLabels(es)
 	for labelName, labelValue := range commonLabels {
@@ -158,10 +195,47 @@ func reconcileElasticsearchSecret(
 			esuser.ServiceAccountHashField:      []byte(token.Hash),
 		},
 	}
+	if previous := previousToken(current, fullyQualifiedName, token.Hash, now); previous != nil {
+		for k, v := range previous {
+			esSecret.Data[k] = v
+		}
+	}
 	_, err := reconciler.ReconcileSecret(ctx, client, esSecret, &es)
 	return err
 }
 
+// previousToken returns the fields of the Elasticsearch Secret which keep the previous token valid during the grace
+// period following a rotation, or nil if there is no such token.
+func previousToken(current corev1.Secret, name, hash string, now time.Time) map[string][]byte {
+	currentName, currentHash := current.Data[esuser.ServiceAccountTokenNameField], current.Data[esuser.ServiceAccountHashField]
+	if len(currentName) > 0 && string(currentName) != name {
+		// the token has just been rotated
+		return map[string][]byte{
+			esuser.ServiceAccountPreviousTokenNameField: currentName,
+			esuser.ServiceAccountPreviousHashField:      currentHash,
+			serviceAccountPreviousTokenExpiresAtField:   []byte(now.Add(serviceAccountTokenGracePeriod).Format(time.RFC3339)),
+		}
+	}
+	expiresAt, err := time.Parse(time.RFC3339, string(current.Data[serviceAccountPreviousTokenExpiresAtField]))
+	if err != nil || !now.Before(expiresAt) {
+		return nil
+	}
+	return map[string][]byte{
+		esuser.ServiceAccountPreviousTokenNameField: current.Data[esuser.ServiceAccountPreviousTokenNameField],
+		esuser.ServiceAccountPreviousHashField:      current.Data[esuser.ServiceAccountPreviousHashField],
+		serviceAccountPreviousTokenExpiresAtField:   current.Data[serviceAccountPreviousTokenExpiresAtField],
+	}
+}
+
+// requeueTokenRotation returns the reconciliation result which rotates the service account tokens, and removes the
+// previous tokens at the end of their grace period, on time.
+func requeueTokenRotation(rotation time.Duration) reconcile.Result {
+	if rotation <= 0 {
+		return reconcile.Result{}
+	}
+	return reconcile.Result{RequeueAfter: min(rotation, serviceAccountTokenGracePeriod)}
+}
+
 func ReconcileServiceAccounts(
 	ctx context.Context,
 	client k8s.Client,
@@ -172,13 +246,15 @@ func ReconcileServiceAccounts(
 	serviceAccount commonv1.ServiceAccountName,
 	applicationName string,
 	applicationUID types.UID,
+	rotation time.Duration,
 ) error {
+	now := time.Now()
 	tokenName := tokenName(applicationSecretName.Namespace, applicationName, applicationUID)
-	token, err := reconcileApplicationSecret(ctx, client, es, applicationSecretName, commonLabels, tokenName, serviceAccount)
+	token, err := reconcileApplicationSecret(ctx, client, es, applicationSecretName, commonLabels, tokenName, serviceAccount, rotation, now)
 	if err != nil {
 		return err
 	}
-	return reconcileElasticsearchSecret(ctx, client, es, elasticsearchSecretName, commonLabels, *token)
+	return reconcileElasticsearchSecret(ctx, client, es, elasticsearchSecretName, commonLabels, *token, now)
 }
 
 // getCurrentApplicationToken returns the current token from the application Secret, or nil if the content of the Secret is not valid.
@@ -212,6 +288,11 @@ func getCurrentApplicationToken(ctx context.Context, es *esv1.Elasticsearch, sec
 		return nil
 	}
 
+	if value, exists := secretData[ServiceAccountTokenCreatedAtField]; exists {
+		// an invalid creation time is reset as for the tokens created before the rotation was enabled
+		result.CreatedAt, _ = time.Parse(time.RFC3339, string(value))
+	}
+
 	return result
 }
 
@@ -227,8 +308,9 @@ func getFieldOrNil(ctx context.Context, es *esv1.Elasticsearch, secretName strin
 
 var prefix = [...]byte{0x0, 0x1, 0x0, 0x1}
 
-// newApplicationToken generates a new token for a given service account.
-func newApplicationToken(serviceAccountName commonv1.ServiceAccountName, tokenName string) (*Token, error) {
+// newApplicationToken generates a new token for a given service account. Its creation time is only recorded if the
+// tokens are rotated.
+func newApplicationToken(serviceAccountName commonv1.ServiceAccountName, tokenName string, rotation time.Duration, now time.Time) (*Token, error) {
 	secret := common.RandomBytes(64)
 	hash, err := pbkdf2Key(secret)
 	if err != nil {
@@ -239,12 +321,16 @@ func newApplicationToken(serviceAccountName commonv1.ServiceAccountName, tokenNa
 	suffix := []byte(fmt.Sprintf("%s/%s:%s", fullyQualifiedName, tokenName, secret))
 	token := base64.StdEncoding.EncodeToString(append(prefix[:], suffix...))
 
-	return &Token{
+	result := &Token{
 		ServiceAccountName: fullyQualifiedName,
 		TokenName:          tokenName,
 		Token:              token,
 		Hash:               hash,
-	}, nil
+	}
+	if rotation > 0 {
+		result.CreatedAt = now.Truncate(time.Second)
+	}
+	return result, nil
 }
 
 func tokenName(
@@ -260,6 +346,8 @@ type Token struct {
 	TokenName          string
 	Token              string
 	Hash               string
+	// CreatedAt is the creation time of the token, zero if the tokens are not rotated.
+	CreatedAt time.Time
 }
 
 func (u Token) MarshalJSON() ([]byte, error) {
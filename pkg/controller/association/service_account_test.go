@@ -13,6 +13,7 @@ import (
 	"regexp"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/pbkdf2"
@@ -193,6 +194,7 @@ func Test_ReconcileServiceAccounts(t *testing.T) {
 				tt.args.serviceAccount,
 				existingKibana.Name,
 				existingKibana.UID,
+				0,
 			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("store.EnsureTokenExists() error = %v, wantErr %v", err, tt.wantErr)
@@ -257,6 +259,120 @@ func Test_ReconcileServiceAccounts(t *testing.T) {
 	}
 }
 
+func Test_ReconcileServiceAccounts_rotation(t *testing.T) {
+	applicationSecretName := types.NamespacedName{Namespace: "e2e-venus", Name: "kibana-sample-kibana-user"}
+	elasticsearchSecretName := types.NamespacedName{Namespace: "e2e-mercury", Name: "e2e-venus-kibana-sample-kibana-user"}
+	existingName := "elastic/kibana/e2e-venus_kibana-sample_892ff7d8-9cf2-48f0-89bc-5a530e77a930"
+	existingHash := string(expectedElasticsearchUserSecret.Data["hash"])
+	withCreatedAt := func(createdAt time.Time) *corev1.Secret {
+		secret := expectedKibanaUserSecret.DeepCopy()
+		secret.Data[ServiceAccountTokenCreatedAtField] = []byte(createdAt.Format(time.RFC3339))
+		return secret
+	}
+	withPrevious := func(expiresAt time.Time) *corev1.Secret {
+		secret := expectedElasticsearchUserSecret.DeepCopy()
+		secret.Data["previousName"] = []byte("elastic/kibana/e2e-venus_kibana-sample_892ff7d8-9cf2-48f0-89bc-5a530e77a930_1")
+		secret.Data["previousHash"] = []byte("previous-hash")
+		secret.Data[serviceAccountPreviousTokenExpiresAtField] = []byte(expiresAt.Format(time.RFC3339))
+		return secret
+	}
+	now := time.Now()
+	tests := []struct {
+		name              string
+		applicationSecret *corev1.Secret
+		esSecret          *corev1.Secret
+		rotation          time.Duration
+		wantRotated       bool
+		wantPreviousName  string
+		wantPreviousHash  string
+	}{
+		{
+			name:              "rotation disabled",
+			applicationSecret: withCreatedAt(now.Add(-48 * time.Hour)),
+			esSecret:          expectedElasticsearchUserSecret.DeepCopy(),
+		},
+		{
+			name:              "token created before the rotation was enabled",
+			applicationSecret: expectedKibanaUserSecret.DeepCopy(),
+			esSecret:          expectedElasticsearchUserSecret.DeepCopy(),
+			rotation:          24 * time.Hour,
+		},
+		{
+			name:              "token not expired",
+			applicationSecret: withCreatedAt(now.Add(-time.Hour)),
+			esSecret:          expectedElasticsearchUserSecret.DeepCopy(),
+			rotation:          24 * time.Hour,
+		},
+		{
+			name:              "token rotated",
+			applicationSecret: withCreatedAt(now.Add(-48 * time.Hour)),
+			esSecret:          expectedElasticsearchUserSecret.DeepCopy(),
+			rotation:          24 * time.Hour,
+			wantRotated:       true,
+			wantPreviousName:  existingName,
+			wantPreviousHash:  existingHash,
+		},
+		{
+			name:              "previous token in its grace period",
+			applicationSecret: withCreatedAt(now.Add(-time.Hour)),
+			esSecret:          withPrevious(now.Add(time.Minute)),
+			rotation:          24 * time.Hour,
+			wantPreviousName:  "elastic/kibana/e2e-venus_kibana-sample_892ff7d8-9cf2-48f0-89bc-5a530e77a930_1",
+			wantPreviousHash:  "previous-hash",
+		},
+		{
+			name:              "previous token after its grace period",
+			applicationSecret: withCreatedAt(now.Add(-time.Hour)),
+			esSecret:          withPrevious(now.Add(-time.Minute)),
+			rotation:          24 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(existingElasticsearch.DeepCopy(), existingKibana.DeepCopy(), tt.applicationSecret, tt.esSecret)
+			err := ReconcileServiceAccounts(
+				context.Background(),
+				c,
+				existingElasticsearch,
+				expectedKibanaUserSecret.Labels,
+				applicationSecretName,
+				elasticsearchSecretName,
+				"kibana",
+				existingKibana.Name,
+				existingKibana.UID,
+				tt.rotation,
+			)
+			assert.NoError(t, err)
+
+			var applicationSecret, esSecret corev1.Secret
+			assert.NoError(t, c.Get(context.Background(), applicationSecretName, &applicationSecret))
+			assert.NoError(t, c.Get(context.Background(), elasticsearchSecretName, &esSecret))
+			name := string(applicationSecret.Data["name"])
+			if tt.wantRotated {
+				// the new token name is suffixed with the rotation time
+				assert.Regexp(t, `^e2e-venus_kibana-sample_892ff7d8-9cf2-48f0-89bc-5a530e77a930_\d+$`, name)
+				assert.NotEqual(t, existingHash, string(applicationSecret.Data["hash"]))
+				verifyToken(t, string(applicationSecret.Data["token"]), string(applicationSecret.Data["hash"]), "kibana", name)
+			} else {
+				assert.Equal(t, "e2e-venus_kibana-sample_892ff7d8-9cf2-48f0-89bc-5a530e77a930", name)
+				assert.Equal(t, existingHash, string(applicationSecret.Data["hash"]))
+			}
+			_, hasCreatedAt := applicationSecret.Data[ServiceAccountTokenCreatedAtField]
+			assert.Equal(t, tt.rotation > 0 || tt.applicationSecret.Data[ServiceAccountTokenCreatedAtField] != nil, hasCreatedAt)
+			assert.Equal(t, "elastic/kibana/"+name, string(esSecret.Data["name"]))
+			assert.Equal(t, string(applicationSecret.Data["hash"]), string(esSecret.Data["hash"]))
+			assert.Equal(t, tt.wantPreviousName, string(esSecret.Data["previousName"]))
+			assert.Equal(t, tt.wantPreviousHash, string(esSecret.Data["previousHash"]))
+		})
+	}
+}
+
+func Test_requeueTokenRotation(t *testing.T) {
+	assert.Equal(t, time.Duration(0), requeueTokenRotation(0).RequeueAfter)
+	assert.Equal(t, 10*time.Minute, requeueTokenRotation(10*time.Minute).RequeueAfter)
+	assert.Equal(t, serviceAccountTokenGracePeriod, requeueTokenRotation(30*24*time.Hour).RequeueAfter)
+}
+
 func Test_newApplicationToken(t *testing.T) {
 	type args struct {
 		serviceAccountName commonv1.ServiceAccountName
@@ -277,7 +393,7 @@ func Test_newApplicationToken(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := newApplicationToken(tt.args.serviceAccountName, tt.args.tokenName)
+			got, err := newApplicationToken(tt.args.serviceAccountName, tt.args.tokenName, 0, time.Now())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("newApplicationToken() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -8,7 +8,6 @@ import (
 	"context"
 
 	"go.elastic.co/apm/v2"
-	"golang.org/x/crypto/bcrypt"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -22,6 +21,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	eslabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	esuser "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/cryptutil"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -148,21 +148,11 @@ func reconcileEsUserSecret(
 	}
 
 	// reuse the existing hash if valid
-	var bcryptHash []byte
-	if existingHash, exists := existingUserSecret.Data[esuser.PasswordHashField]; exists {
-		if bcrypt.CompareHashAndPassword(existingHash, password) == nil {
-			bcryptHash = existingHash
-		}
-	}
-
-	if bcryptHash == nil {
-		bcryptHash, err = bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
-		if err != nil {
-			return err
-		}
+	passwordHash, err := cryptutil.ReuseOrGenerateHash(password, existingUserSecret.Data[esuser.PasswordHashField])
+	if err != nil {
+		return err
 	}
-
-	expectedEsUser.Data[esuser.PasswordHashField] = bcryptHash
+	expectedEsUser.Data[esuser.PasswordHashField] = passwordHash
 
 	owner := es // user is owned by the es resource in es namespace
 	_, err = reconciler.ReconcileSecret(ctx, c, expectedEsUser, &owner)
@@ -21,6 +21,7 @@ import (
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	esuser "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/cryptutil"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -32,6 +33,9 @@ const (
 )
 
 func Test_reconcileEsUser(t *testing.T) {
+	if cryptutil.FIPSEnabled() {
+		t.Skip("fixtures use bcrypt password hashes, replaced with PBKDF2 with FIPS validated cryptography")
+	}
 	esFixture := esv1.Elasticsearch{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "es-foo",
@@ -79,6 +79,9 @@ func (r baseReconcileAutoscaling) withRecorder(recorder record.EventRecorder) ba
 type ReconcileElasticsearchAutoscaler struct {
 	baseReconcileAutoscaling
 	Watches watches.DynamicWatches
+
+	// metricsSamples holds the last metrics sampled for the autoscalers in Metrics mode.
+	metricsSamples metricsSamples
 }
 
 // NewReconciler returns a new autoscaling reconcile.Reconciler
@@ -113,6 +116,7 @@ func (r *ReconcileElasticsearchAutoscaler) Reconcile(ctx context.Context, reques
 		if apierrors.IsNotFound(err) {
 			log.V(1).Info("ElasticsearchAutoscaler not found", "namespace", request.Namespace, "esa_name", request.Name)
 			r.Watches.ReferencedResources.RemoveHandlerForKey(dynamicWatchName(request))
+			r.metricsSamples.swap(request.NamespacedName, nil)
 			return reconcile.Result{}, nil
 		}
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
@@ -180,6 +184,12 @@ func (r *ReconcileElasticsearchAutoscaler) Reconcile(ctx context.Context, reques
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
+	if esa.IsMetricsMode() {
+		statusBuilder := v1alpha1.NewAutoscalingStatusBuilder()
+		reconciledEs, reconcileMetricsErr := r.reconcileMetrics(ctx, es, statusBuilder, esa)
+		return r.applyResults(ctx, log, esa, statusBuilder, reconciledEs, reconcileMetricsErr)
+	}
+
 	// Get autoscaling policies and the associated node sets.
 	v, err := version.Parse(es.Spec.Version)
 	if err != nil {
@@ -212,13 +222,25 @@ func (r *ReconcileElasticsearchAutoscaler) Reconcile(ctx context.Context, reques
 	}
 
 	statusBuilder := newStatusBuilder(log, esa.Spec.AutoscalingPolicySpecs)
-	results := &reconciler.Results{}
 
 	// Call the main function
 	reconciledEs, reconcileInternalErr := r.reconcileInternal(ctx, es, statusBuilder, autoscaledNodeSets, &esa)
-	if reconcileInternalErr != nil {
+	return r.applyResults(ctx, log, esa, statusBuilder, reconciledEs, reconcileInternalErr)
+}
+
+// applyResults updates the status of the autoscaler and the Elasticsearch resource once the resources have been computed.
+func (r *ReconcileElasticsearchAutoscaler) applyResults(
+	ctx context.Context,
+	log logr.Logger,
+	esa autoscalingv1alpha1.ElasticsearchAutoscaler,
+	statusBuilder *v1alpha1.AutoscalingStatusBuilder,
+	reconciledEs *esv1.Elasticsearch,
+	reconcileErr error,
+) (reconcile.Result, error) {
+	results := &reconciler.Results{}
+	if reconcileErr != nil {
 		// we do not return immediately as not all errors prevent to compute a reconciled Elasticsearch resource.
-		results.WithError(reconcileInternalErr)
+		results.WithError(reconcileErr)
 	}
 
 	// Update the new status
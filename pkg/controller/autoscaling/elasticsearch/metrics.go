@@ -0,0 +1,301 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+
+	autoscalingv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/autoscaling/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/autoscaling/elasticsearch/status"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	logconf "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// metricsTolerance is the relative deviation of a metric from its target within which the number of nodes is left
+// untouched, to not scale on small variations.
+const metricsTolerance = 0.1
+
+// nodeSample holds the values of the metrics of a node, sampled at a given time.
+type nodeSample struct {
+	time   time.Time
+	values map[string]float64
+}
+
+// metricsSamples keeps the last samples of each autoscaler in Metrics mode, indexed by node name, to compute the
+// rates and the ratios of counters from one reconciliation to the next one.
+type metricsSamples struct {
+	mutex   sync.Mutex
+	samples map[types.NamespacedName]map[string]nodeSample
+}
+
+// swap stores the samples of an autoscaler and returns the previous ones. Nil samples remove the autoscaler.
+func (m *metricsSamples) swap(autoscaler types.NamespacedName, next map[string]nodeSample) map[string]nodeSample {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	previous := m.samples[autoscaler]
+	if next == nil {
+		delete(m.samples, autoscaler)
+		return previous
+	}
+	if m.samples == nil {
+		m.samples = make(map[types.NamespacedName]map[string]nodeSample)
+	}
+	m.samples[autoscaler] = next
+	return previous
+}
+
+// reconcileMetrics scales the NodeSets of the metric rules according to the statistics of their nodes. If the metrics
+// cannot be sampled the node counts are only adjusted to be within the ranges.
+func (r *ReconcileElasticsearchAutoscaler) reconcileMetrics(
+	ctx context.Context,
+	es esv1.Elasticsearch,
+	statusBuilder *v1alpha1.AutoscalingStatusBuilder,
+	esa autoscalingv1alpha1.ElasticsearchAutoscaler,
+) (*esv1.Elasticsearch, error) {
+	defer tracing.Span(&ctx)()
+	log := logconf.FromContext(ctx)
+
+	var current map[string]nodeSample
+	var err error
+	if r.isElasticsearchReachable(ctx, es) {
+		current, err = r.sampleMetrics(ctx, es, esa.Spec.MetricRules)
+		if err != nil {
+			statusBuilder.SetOnline(false, err.Error())
+			log.Error(tracing.CaptureError(ctx, err), "failed to sample the metrics of the nodes")
+		} else {
+			statusBuilder.SetOnline(true, "Elasticsearch is available")
+		}
+	} else {
+		statusBuilder.SetOnline(false, "Elasticsearch is not available")
+	}
+	previous := r.metricsSamples.swap(k8s.ExtractNamespacedName(&esa), current)
+
+	nextClusterResources := make(v1alpha1.ClusterResources, 0, len(esa.Spec.MetricRules))
+	for _, rule := range esa.Spec.MetricRules {
+		i := nodeSetIndex(es, rule.NodeSet)
+		if i < 0 {
+			// This situation should be caught during the validation.
+			err := fmt.Errorf("nodeSet %s does not exist", rule.NodeSet)
+			statusBuilder.ForPolicy(rule.NodeSet).RecordEvent(v1alpha1.NoNodeSet, err.Error())
+			return nil, tracing.CaptureError(ctx, err)
+		}
+		currentCount := es.Spec.NodeSets[i].Count
+		nodes := nodeSetSamples(es, rule.NodeSet, current)
+		desiredCount, ok := desiredNodeCount(log, rule, currentCount, nodes, previous)
+		if !ok {
+			desiredCount = currentCount
+		}
+		if desiredCount > rule.NodeCountRange.Max {
+			statusBuilder.ForPolicy(rule.NodeSet).RecordEvent(
+				v1alpha1.HorizontalScalingLimitReached,
+				fmt.Sprintf("Can't provide total required node count %d, max number of nodes is %d", desiredCount, rule.NodeCountRange.Max),
+			)
+		}
+		desiredCount = rule.NodeCountRange.Enforce(desiredCount)
+		if desiredCount != currentCount {
+			log.Info("Scaling nodeSet on metrics", "nodeset", rule.NodeSet, "from", currentCount, "to", desiredCount)
+		}
+		es.Spec.NodeSets[i].Count = desiredCount
+		nextClusterResources = append(nextClusterResources, v1alpha1.NodeSetsResources{
+			Name:             rule.NodeSet,
+			NodeSetNodeCount: v1alpha1.NodeSetNodeCountList{{Name: rule.NodeSet, NodeCount: desiredCount}},
+		})
+	}
+
+	// Emit the K8S events
+	status.EmitEvents(es, r.recorder, statusBuilder.Build())
+
+	// Register new resources in the status
+	statusBuilder.UpdateResources(nextClusterResources, esa.Status)
+
+	return &es, err
+}
+
+// sampleMetrics retrieves from the nodes stats API the values of all the paths of the metric rules, for each node.
+func (r *ReconcileElasticsearchAutoscaler) sampleMetrics(
+	ctx context.Context,
+	es esv1.Elasticsearch,
+	rules []autoscalingv1alpha1.MetricScalingRule,
+) (map[string]nodeSample, error) {
+	var paths []string
+	for _, rule := range rules {
+		for _, metric := range rule.Metrics {
+			paths = append(paths, metric.Path)
+			if metric.DivisorPath != "" {
+				paths = append(paths, metric.DivisorPath)
+			}
+		}
+	}
+	esClient, err := r.esClientProvider(ctx, r.Client, r.Dialer, es)
+	if err != nil {
+		return nil, err
+	}
+	defer esClient.Close()
+	nodesMetrics, err := esClient.GetNodesMetrics(ctx, metricGroups(paths)...)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	samples := make(map[string]nodeSample, len(nodesMetrics.Nodes))
+	for _, node := range nodesMetrics.Nodes {
+		sample := nodeSample{time: now, values: make(map[string]float64, len(paths))}
+		for _, path := range paths {
+			if value, ok := node.Value(path); ok {
+				sample.values[path] = value
+			}
+		}
+		samples[node.Name()] = sample
+	}
+	return samples, nil
+}
+
+// metricGroups returns the distinct statistics groups, the first element of the paths, to restrict the nodes stats request to.
+func metricGroups(paths []string) []string {
+	groups := make([]string, 0, len(paths))
+	seen := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		group := strings.SplitN(path, ".", 2)[0]
+		if _, exists := seen[group]; exists {
+			continue
+		}
+		seen[group] = struct{}{}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// nodeSetSamples returns the samples of the nodes of a NodeSet.
+func nodeSetSamples(es esv1.Elasticsearch, nodeSet string, samples map[string]nodeSample) map[string]nodeSample {
+	statefulSetName := esv1.StatefulSet(es.Name, nodeSet)
+	nodeSetSamples := make(map[string]nodeSample)
+	for nodeName, sample := range samples {
+		if !strings.Contains(nodeName, "-") {
+			continue
+		}
+		if name, _, err := sset.StatefulSetName(nodeName); err == nil && name == statefulSetName {
+			nodeSetSamples[nodeName] = sample
+		}
+	}
+	return nodeSetSamples
+}
+
+// desiredNodeCount returns the number of nodes required to bring the average value of each metric of the rule close to
+// its target, retaining the highest one. It returns false if none of the metrics could be computed.
+func desiredNodeCount(
+	log logr.Logger,
+	rule autoscalingv1alpha1.MetricScalingRule,
+	currentCount int32,
+	current, previous map[string]nodeSample,
+) (int32, bool) {
+	var desiredCount int32
+	computed := false
+	for _, metric := range rule.Metrics {
+		average, ok := averageValue(metric, current, previous)
+		if !ok {
+			log.V(1).Info("No value for metric", "nodeset", rule.NodeSet, "path", metric.Path)
+			continue
+		}
+		count := currentCount
+		ratio := average / metric.Target.AsApproximateFloat64()
+		if math.Abs(ratio-1) > metricsTolerance {
+			count = int32(math.Ceil(float64(currentCount) * ratio))
+		}
+		log.V(1).Info("Metric value", "nodeset", rule.NodeSet, "path", metric.Path, "value", average, "node_count", count)
+		if !computed || count > desiredCount {
+			desiredCount = count
+		}
+		computed = true
+	}
+	return desiredCount, computed
+}
+
+// averageValue returns the average value of a metric over the nodes, ignoring the nodes for which the metric cannot be
+// computed.
+func averageValue(metric autoscalingv1alpha1.NodeStatsMetric, current, previous map[string]nodeSample) (float64, bool) {
+	var sum float64
+	var count int
+	for nodeName, sample := range current {
+		value, ok := nodeValue(metric, sample, previous[nodeName])
+		if !ok {
+			continue
+		}
+		sum += value
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// nodeValue computes the value of a metric for a node from its current and previous samples.
+func nodeValue(metric autoscalingv1alpha1.NodeStatsMetric, current, previous nodeSample) (float64, bool) {
+	value, ok := current.values[metric.Path]
+	if !ok {
+		return 0, false
+	}
+	if metric.TypeOrDefault() == autoscalingv1alpha1.GaugeMetric {
+		return value, true
+	}
+	delta, ok := counterDelta(metric.Path, current, previous)
+	if !ok {
+		return 0, false
+	}
+	switch metric.TypeOrDefault() {
+	case autoscalingv1alpha1.RateMetric:
+		elapsed := current.time.Sub(previous.time).Seconds()
+		if elapsed <= 0 {
+			return 0, false
+		}
+		return delta / elapsed, true
+	case autoscalingv1alpha1.RatioMetric:
+		divisor, ok := counterDelta(metric.DivisorPath, current, previous)
+		if !ok {
+			return 0, false
+		}
+		if divisor == 0 {
+			// nothing happened since the previous sample
+			return 0, true
+		}
+		return delta / divisor, true
+	}
+	return 0, false
+}
+
+// counterDelta returns the increase of a counter between two samples. It returns false if the counter is missing in
+// one of the samples, or if it has been reset by a restart of the node.
+func counterDelta(path string, current, previous nodeSample) (float64, bool) {
+	currentValue, ok := current.values[path]
+	if !ok {
+		return 0, false
+	}
+	previousValue, ok := previous.values[path]
+	if !ok || currentValue < previousValue {
+		return 0, false
+	}
+	return currentValue - previousValue, true
+}
+
+// nodeSetIndex returns the index of a NodeSet in the specification of Elasticsearch, or -1 if it does not exist.
+func nodeSetIndex(es esv1.Elasticsearch, name string) int {
+	for i, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,184 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	autoscalingv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/autoscaling/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_desiredNodeCount(t *testing.T) {
+	now := time.Now()
+	sample := func(at time.Time, values map[string]float64) nodeSample {
+		return nodeSample{time: at, values: values}
+	}
+	rule := func(metrics ...autoscalingv1alpha1.NodeStatsMetric) autoscalingv1alpha1.MetricScalingRule {
+		return autoscalingv1alpha1.MetricScalingRule{
+			NodeSet:        "ingest",
+			NodeCountRange: v1alpha1.CountRange{Min: 1, Max: 10},
+			Metrics:        metrics,
+		}
+	}
+	queue := autoscalingv1alpha1.NodeStatsMetric{Path: "thread_pool.write.queue", Target: resource.MustParse("10")}
+	rejections := autoscalingv1alpha1.NodeStatsMetric{Path: "thread_pool.write.rejected", Type: autoscalingv1alpha1.RateMetric, Target: resource.MustParse("1")}
+	latency := autoscalingv1alpha1.NodeStatsMetric{
+		Path:        "indices.search.query_time_in_millis",
+		Type:        autoscalingv1alpha1.RatioMetric,
+		DivisorPath: "indices.search.query_total",
+		Target:      resource.MustParse("50"),
+	}
+
+	tests := []struct {
+		name      string
+		rule      autoscalingv1alpha1.MetricScalingRule
+		current   map[string]nodeSample
+		previous  map[string]nodeSample
+		wantCount int32
+		wantOk    bool
+	}{
+		{
+			name: "Gauge above target scales up",
+			rule: rule(queue),
+			current: map[string]nodeSample{
+				"es-ingest-0": sample(now, map[string]float64{"thread_pool.write.queue": 30}),
+				"es-ingest-1": sample(now, map[string]float64{"thread_pool.write.queue": 10}),
+			},
+			wantCount: 4,
+			wantOk:    true,
+		},
+		{
+			name: "Gauge within the tolerance keeps the node count",
+			rule: rule(queue),
+			current: map[string]nodeSample{
+				"es-ingest-0": sample(now, map[string]float64{"thread_pool.write.queue": 11}),
+				"es-ingest-1": sample(now, map[string]float64{"thread_pool.write.queue": 10}),
+			},
+			wantCount: 2,
+			wantOk:    true,
+		},
+		{
+			name: "Gauge below target scales down",
+			rule: rule(queue),
+			current: map[string]nodeSample{
+				"es-ingest-0": sample(now, map[string]float64{"thread_pool.write.queue": 1}),
+				"es-ingest-1": sample(now, map[string]float64{"thread_pool.write.queue": 3}),
+			},
+			wantCount: 1,
+			wantOk:    true,
+		},
+		{
+			name: "Rate needs a previous sample",
+			rule: rule(rejections),
+			current: map[string]nodeSample{
+				"es-ingest-0": sample(now, map[string]float64{"thread_pool.write.rejected": 100}),
+			},
+			wantOk: false,
+		},
+		{
+			name: "Rate is computed from the previous sample",
+			rule: rule(rejections),
+			current: map[string]nodeSample{
+				"es-ingest-0": sample(now, map[string]float64{"thread_pool.write.rejected": 160}),
+				"es-ingest-1": sample(now, map[string]float64{"thread_pool.write.rejected": 40}),
+			},
+			previous: map[string]nodeSample{
+				"es-ingest-0": sample(now.Add(-time.Minute), map[string]float64{"thread_pool.write.rejected": 40}),
+				"es-ingest-1": sample(now.Add(-time.Minute), map[string]float64{"thread_pool.write.rejected": 40}),
+			},
+			// 2 rejections per second on the first node, none on the second one
+			wantCount: 2,
+			wantOk:    true,
+		},
+		{
+			name: "Counters reset by a restart are ignored",
+			rule: rule(rejections),
+			current: map[string]nodeSample{
+				"es-ingest-0": sample(now, map[string]float64{"thread_pool.write.rejected": 10}),
+			},
+			previous: map[string]nodeSample{
+				"es-ingest-0": sample(now.Add(-time.Minute), map[string]float64{"thread_pool.write.rejected": 400}),
+			},
+			wantOk: false,
+		},
+		{
+			name: "Ratio is computed from the increase of both counters",
+			rule: rule(latency),
+			current: map[string]nodeSample{
+				"es-ingest-0": sample(now, map[string]float64{"indices.search.query_time_in_millis": 11000, "indices.search.query_total": 200}),
+				"es-ingest-1": sample(now, map[string]float64{"indices.search.query_time_in_millis": 5000, "indices.search.query_total": 100}),
+			},
+			previous: map[string]nodeSample{
+				"es-ingest-0": sample(now.Add(-time.Minute), map[string]float64{"indices.search.query_time_in_millis": 1000, "indices.search.query_total": 100}),
+				"es-ingest-1": sample(now.Add(-time.Minute), map[string]float64{"indices.search.query_time_in_millis": 5000, "indices.search.query_total": 100}),
+			},
+			// 100ms on the first node, no search on the second one
+			wantCount: 2,
+			wantOk:    true,
+		},
+		{
+			name: "Highest node count among the metrics is retained",
+			rule: rule(queue, latency),
+			current: map[string]nodeSample{
+				"es-ingest-0": sample(now, map[string]float64{"thread_pool.write.queue": 0, "indices.search.query_time_in_millis": 20000, "indices.search.query_total": 200}),
+				"es-ingest-1": sample(now, map[string]float64{"thread_pool.write.queue": 0, "indices.search.query_time_in_millis": 20000, "indices.search.query_total": 200}),
+			},
+			previous: map[string]nodeSample{
+				"es-ingest-0": sample(now.Add(-time.Minute), map[string]float64{"indices.search.query_time_in_millis": 0, "indices.search.query_total": 0}),
+				"es-ingest-1": sample(now.Add(-time.Minute), map[string]float64{"indices.search.query_time_in_millis": 0, "indices.search.query_total": 0}),
+			},
+			wantCount: 4,
+			wantOk:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := desiredNodeCount(logTest, tt.rule, 2, tt.current, tt.previous)
+			assert.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				assert.Equal(t, tt.wantCount, got)
+			}
+		})
+	}
+}
+
+func Test_nodeSetSamples(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "es"}}
+	samples := map[string]nodeSample{
+		"es-es-ingest-0":     {},
+		"es-es-ingest-1":     {},
+		"es-es-ingest-hot-0": {},
+		"es-es-master-0":     {},
+		"node":               {},
+	}
+	got := nodeSetSamples(es, "ingest", samples)
+	assert.Len(t, got, 2)
+	assert.Contains(t, got, "es-es-ingest-0")
+	assert.Contains(t, got, "es-es-ingest-1")
+}
+
+func Test_metricGroups(t *testing.T) {
+	assert.Equal(t,
+		[]string{"thread_pool", "indices"},
+		metricGroups([]string{"thread_pool.write.queue", "indices.search.query_total", "thread_pool.write.rejected"}),
+	)
+}
+
+func Test_metricsSamples_swap(t *testing.T) {
+	var m metricsSamples
+	esa := types.NamespacedName{Namespace: "ns", Name: "esa"}
+	first := map[string]nodeSample{"es-es-ingest-0": {values: map[string]float64{"a": 1}}}
+	assert.Nil(t, m.swap(esa, first))
+	assert.Equal(t, first, m.swap(esa, nil))
+	assert.Nil(t, m.swap(esa, first))
+}
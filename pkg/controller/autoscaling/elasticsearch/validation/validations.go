@@ -20,6 +20,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 type validation func(autoscaler v1alpha1.ElasticsearchAutoscaler) (field.ErrorList, error)
@@ -77,7 +78,16 @@ func noAutoscalingAnnotation(ctx context.Context, esa v1alpha1.ElasticsearchAuto
 }
 
 func validAutoscalingConfiguration(ctx context.Context, esa v1alpha1.ElasticsearchAutoscaler, k8sClient k8s.Client) (field.ErrorList, error) {
+	if esa.IsMetricsMode() {
+		return validMetricRules(ctx, esa, k8sClient)
+	}
 	var errs field.ErrorList
+	if len(esa.Spec.MetricRules) > 0 {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("metricRules"), "metric rules are only supported in Metrics mode"))
+	}
+	if len(esa.Spec.AutoscalingPolicySpecs) == 0 {
+		errs = append(errs, field.Required(field.NewPath("spec").Child("policies"), "at least one autoscaling policy is required in Deciders mode"))
+	}
 	// Validate the autoscaling policies
 	errs = append(errs, autoscaling.ValidateAutoscalingPolicies(autoscalingSpecPath, esa.Spec.AutoscalingPolicySpecs)...)
 	if len(errs) > 0 {
@@ -105,6 +115,83 @@ func validAutoscalingConfiguration(ctx context.Context, esa v1alpha1.Elasticsear
 	return errs, nil
 }
 
+// validMetricRules validates the metric rules of an autoscaler in Metrics mode, which must each scale a distinct NodeSet
+// of the associated Elasticsearch resource.
+func validMetricRules(ctx context.Context, esa v1alpha1.ElasticsearchAutoscaler, k8sClient k8s.Client) (field.ErrorList, error) {
+	var errs field.ErrorList
+	rulesPath := field.NewPath("spec").Child("metricRules")
+	if len(esa.Spec.AutoscalingPolicySpecs) > 0 {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("policies"), "autoscaling policies are not supported in Metrics mode"))
+	}
+	if len(esa.Spec.MetricRules) == 0 {
+		return append(errs, field.Required(rulesPath, "at least one metric rule is required in Metrics mode")), nil
+	}
+
+	nodeSets := set.Make()
+	for i, rule := range esa.Spec.MetricRules {
+		rulePath := rulesPath.Index(i)
+		if nodeSets.Has(rule.NodeSet) {
+			errs = append(errs, field.Duplicate(rulePath.Child("nodeSet"), rule.NodeSet))
+		}
+		nodeSets.Add(rule.NodeSet)
+		if rule.NodeCountRange.Min < 0 {
+			errs = append(errs, field.Invalid(rulePath.Child("nodeCount", "min"), rule.NodeCountRange.Min, "min count must be equal or greater than 0"))
+		}
+		if rule.NodeCountRange.Max < 1 || rule.NodeCountRange.Max < rule.NodeCountRange.Min {
+			errs = append(errs, field.Invalid(rulePath.Child("nodeCount", "max"), rule.NodeCountRange.Max, "max count must be greater than 0 and equal or greater than min count"))
+		}
+		if len(rule.Metrics) == 0 {
+			errs = append(errs, field.Required(rulePath.Child("metrics"), "at least one metric is required"))
+		}
+		for j, metric := range rule.Metrics {
+			metricPath := rulePath.Child("metrics").Index(j)
+			if metric.Path == "" {
+				errs = append(errs, field.Required(metricPath.Child("path"), "path is mandatory"))
+			}
+			switch metric.TypeOrDefault() {
+			case v1alpha1.GaugeMetric, v1alpha1.RateMetric:
+				if metric.DivisorPath != "" {
+					errs = append(errs, field.Forbidden(metricPath.Child("divisorPath"), "divisor path is only supported by Ratio metrics"))
+				}
+			case v1alpha1.RatioMetric:
+				if metric.DivisorPath == "" {
+					errs = append(errs, field.Required(metricPath.Child("divisorPath"), "divisor path is mandatory for Ratio metrics"))
+				}
+			default:
+				errs = append(errs, field.NotSupported(metricPath.Child("type"), metric.Type, []string{string(v1alpha1.GaugeMetric), string(v1alpha1.RateMetric), string(v1alpha1.RatioMetric)}))
+			}
+			if metric.Target.Sign() <= 0 {
+				errs = append(errs, field.Invalid(metricPath.Child("target"), metric.Target.String(), "target must be greater than 0"))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs, nil
+	}
+
+	// Fetch the associated Elasticsearch resource to check that the NodeSets exist
+	var es esv1.Elasticsearch
+	esNamespacedName := types.NamespacedName{Name: esa.Spec.ElasticsearchRef.Name, Namespace: esa.Namespace}
+	if err := k8sClient.Get(ctx, esNamespacedName, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			esalog.Info("associated Elasticsearch not found")
+			return errs, err
+		}
+		esalog.Info("error while getting the associated Elasticsearch resource, skipping validation", "error", err.Error())
+		return errs, err
+	}
+	existing := set.Make()
+	for _, nodeSet := range es.Spec.NodeSets {
+		existing.Add(nodeSet.Name)
+	}
+	for i, rule := range esa.Spec.MetricRules {
+		if !existing.Has(rule.NodeSet) {
+			errs = append(errs, field.Invalid(rulesPath.Index(i).Child("nodeSet"), rule.NodeSet, "NodeSet does not exist in the Elasticsearch resource"))
+		}
+	}
+	return errs, nil
+}
+
 // validName checks whether the name is valid.
 func validName(esa v1alpha1.ElasticsearchAutoscaler) (field.ErrorList, error) {
 	if len(esa.Name) > common_name.MaxResourceNameLength {
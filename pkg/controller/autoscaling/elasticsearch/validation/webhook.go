@@ -18,9 +18,9 @@ import (
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/autoscaling/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/namespaces"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 // +kubebuilder:webhook:path=/validate-autoscaling-k8s-elastic-co-v1alpha1-elasticsearchautoscaler,mutating=false,failurePolicy=ignore,groups=autoscaling.k8s.elastic.co,resources=elasticsearchautoscalers,verbs=create;update,versions=v1alpha1,name=elastic-esa-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
@@ -32,13 +32,13 @@ const (
 var esalog = ulog.Log.WithName("esa-validation")
 
 // RegisterWebhook will register the Elasticsearch validating webhook.
-func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, licenseChecker license.Checker, managedNamespaces []string) {
+func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, licenseChecker license.Checker, managedNamespaces namespaces.Managed) {
 	wh := &validatingWebhook{
 		client:               mgr.GetClient(),
 		decoder:              admission.NewDecoder(mgr.GetScheme()),
 		validateStorageClass: validateStorageClass,
 		licenseChecker:       licenseChecker,
-		managedNamespaces:    set.Make(managedNamespaces...),
+		managedNamespaces:    managedNamespaces,
 	}
 	esalog.Info("Registering ElasticsearchAutoscaler validating webhook", "path", webhookPath)
 	mgr.GetWebhookServer().Register(webhookPath, &webhook.Admission{Handler: wh})
@@ -49,7 +49,7 @@ type validatingWebhook struct {
 	decoder              admission.Decoder
 	validateStorageClass bool
 	licenseChecker       license.Checker
-	managedNamespaces    set.StringSet
+	managedNamespaces    namespaces.Managed
 }
 
 func (wh *validatingWebhook) validate(ctx context.Context, esa v1alpha1.ElasticsearchAutoscaler) error {
@@ -114,7 +114,7 @@ func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request)
 
 	// If this Elasticsearch instance is not within the set of managed namespaces
 	// for this operator ignore this request.
-	if wh.managedNamespaces.Count() > 0 && !wh.managedNamespaces.Has(esa.Namespace) {
+	if !wh.managedNamespaces.IsManaged(esa.Namespace) {
 		esalog.V(1).Info("Skip Elasticsearch resource validation", "name", esa.Name, "namespace", esa.Namespace)
 		return admission.Allowed("")
 	}
@@ -593,6 +593,77 @@ func TestValidateElasticsearchAutoscaler(t *testing.T) {
 			},
 			wantValidationError: ptr.To[string]("ElasticsearchAutoscaler.autoscaling.k8s.elastic.co \"esa\" is invalid: spec.policies[2].name: Invalid value: \"ml\": ML nodes must be in a dedicated NodeSet"),
 		},
+		{
+			name: "Metric rules in Metrics mode",
+			args: args{
+				es: es(map[string]string{}, map[string][]string{"ingest": {"ingest"}}, nil, "8.0.0"),
+				esa: metricsESA(v1alpha1.MetricScalingRule{
+					NodeSet:        "ingest",
+					NodeCountRange: commonv1alpha1.CountRange{Min: 1, Max: 5},
+					Metrics: []v1alpha1.NodeStatsMetric{
+						{Path: "thread_pool.write.queue", Target: resource.MustParse("10")},
+						{Path: "indices.search.query_time_in_millis", Type: v1alpha1.RatioMetric, DivisorPath: "indices.search.query_total", Target: resource.MustParse("50")},
+					},
+				}),
+				checker: yesCheck,
+			},
+		},
+		{
+			name: "Metric rules are not supported in Deciders mode",
+			args: args{
+				es: es(map[string]string{}, map[string][]string{"ingest": {"ingest"}}, nil, "8.0.0"),
+				esa: func() v1alpha1.ElasticsearchAutoscaler {
+					esa := metricsESA(v1alpha1.MetricScalingRule{
+						NodeSet:        "ingest",
+						NodeCountRange: commonv1alpha1.CountRange{Min: 1, Max: 5},
+						Metrics:        []v1alpha1.NodeStatsMetric{{Path: "thread_pool.write.queue", Target: resource.MustParse("10")}},
+					})
+					esa.Spec.Mode = ""
+					return esa
+				}(),
+				checker: yesCheck,
+			},
+			wantValidationError: ptr.To[string]("spec.metricRules: Forbidden"),
+		},
+		{
+			name: "Metric rule on an unknown NodeSet",
+			args: args{
+				es: es(map[string]string{}, map[string][]string{"ingest": {"ingest"}}, nil, "8.0.0"),
+				esa: metricsESA(v1alpha1.MetricScalingRule{
+					NodeSet:        "hot",
+					NodeCountRange: commonv1alpha1.CountRange{Min: 1, Max: 5},
+					Metrics:        []v1alpha1.NodeStatsMetric{{Path: "thread_pool.write.queue", Target: resource.MustParse("10")}},
+				}),
+				checker: yesCheck,
+			},
+			wantValidationError: ptr.To[string]("spec.metricRules[0].nodeSet: Invalid value: \"hot\": NodeSet does not exist"),
+		},
+		{
+			name: "Ratio metric without divisor path",
+			args: args{
+				es: es(map[string]string{}, map[string][]string{"ingest": {"ingest"}}, nil, "8.0.0"),
+				esa: metricsESA(v1alpha1.MetricScalingRule{
+					NodeSet:        "ingest",
+					NodeCountRange: commonv1alpha1.CountRange{Min: 1, Max: 5},
+					Metrics:        []v1alpha1.NodeStatsMetric{{Path: "indices.search.query_time_in_millis", Type: v1alpha1.RatioMetric, Target: resource.MustParse("50")}},
+				}),
+				checker: yesCheck,
+			},
+			wantValidationError: ptr.To[string]("spec.metricRules[0].metrics[0].divisorPath: Required value"),
+		},
+		{
+			name: "Metric target must be greater than 0",
+			args: args{
+				es: es(map[string]string{}, map[string][]string{"ingest": {"ingest"}}, nil, "8.0.0"),
+				esa: metricsESA(v1alpha1.MetricScalingRule{
+					NodeSet:        "ingest",
+					NodeCountRange: commonv1alpha1.CountRange{Min: 1, Max: 5},
+					Metrics:        []v1alpha1.NodeStatsMetric{{Path: "thread_pool.write.queue", Target: resource.MustParse("0")}},
+				}),
+				checker: yesCheck,
+			},
+			wantValidationError: ptr.To[string]("spec.metricRules[0].metrics[0].target: Invalid value: \"0\": target must be greater than 0"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -646,6 +717,17 @@ func es(
 	return &es
 }
 
+func metricsESA(rules ...v1alpha1.MetricScalingRule) v1alpha1.ElasticsearchAutoscaler {
+	return v1alpha1.ElasticsearchAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "esa", Namespace: "ns"},
+		Spec: v1alpha1.ElasticsearchAutoscalerSpec{
+			ElasticsearchRef: v1alpha1.ElasticsearchRef{Name: "es"},
+			Mode:             v1alpha1.MetricsMode,
+			MetricRules:      rules,
+		},
+	}
+}
+
 func volumeClaimTemplates(volumeClaims []string) []corev1.PersistentVolumeClaim {
 	volumeClaimTemplates := make([]corev1.PersistentVolumeClaim, len(volumeClaims))
 	for i := range volumeClaims {
@@ -0,0 +1,260 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// AutodiscoverNamespaceLabelName is the label holding the namespace of the Beat on its cluster-scoped resources.
+	AutodiscoverNamespaceLabelName = "beat.k8s.elastic.co/namespace"
+
+	nodeNameEnvVar = "NODE_NAME"
+	nodeIPEnvVar   = "NODE_IP"
+)
+
+// AutodiscoverServiceAccountName returns the name of the ServiceAccount generated for a Beat using autodiscover.
+func AutodiscoverServiceAccountName(name string) string {
+	return namer.Suffix(name, "autodiscover")
+}
+
+// AutodiscoverClusterRoleBindingName returns the name of the ClusterRoleBinding generated for a Beat using autodiscover.
+func AutodiscoverClusterRoleBindingName(beat types.NamespacedName) string {
+	return fmt.Sprintf("elastic-beat-autodiscover-%s-%s", beat.Namespace, beat.Name)
+}
+
+// containerLogsConfig returns the input collecting the logs of the containers discovered by Filebeat.
+func containerLogsConfig(v version.Version) map[string]interface{} {
+	paths := []string{"/var/log/containers/*-${data.kubernetes.container.id}.log"}
+	if v.Major < 8 {
+		return map[string]interface{}{
+			"type":  "container",
+			"paths": paths,
+		}
+	}
+	return map[string]interface{}{
+		"type":                        "filestream",
+		"id":                          "kubernetes-container-logs-${data.kubernetes.pod.name}-${data.kubernetes.container.id}",
+		"paths":                       paths,
+		"parsers":                     []interface{}{map[string]interface{}{"container": map[string]interface{}{}}},
+		"prospector.scanner.symlinks": true,
+	}
+}
+
+// podMetricsConfig returns the module collecting the metrics of the Pods from the kubelet of the node.
+func podMetricsConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"module":                "kubernetes",
+		"period":                "10s",
+		"node":                  "${" + nodeNameEnvVar + "}",
+		"hosts":                 []string{"https://${" + nodeIPEnvVar + "}:10250"},
+		"bearer_token_file":     "/var/run/secrets/kubernetes.io/serviceaccount/token",
+		"ssl.verification_mode": "none",
+		"metricsets":            []string{"node", "system", "pod", "container", "volume"},
+	}
+}
+
+// buildAutodiscoverConfig returns the configuration generated for the autodiscover spec of the Beat.
+func buildAutodiscoverConfig(beat beatv1beta1.Beat) (*settings.CanonicalConfig, error) {
+	spec := beat.Spec.Autodiscover
+	if spec == nil {
+		return settings.NewCanonicalConfig(), nil
+	}
+	v, err := version.Parse(beat.Spec.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := map[string]interface{}{"type": "kubernetes"}
+	if beat.Spec.DaemonSet != nil {
+		provider["node"] = "${" + nodeNameEnvVar + "}"
+	} else {
+		provider["scope"] = "cluster"
+	}
+	cfg := map[string]interface{}{}
+	switch beat.Spec.Type {
+	case "filebeat":
+		containerLogs := spec.HasTemplate(beatv1beta1.ContainerLogsTemplate)
+		switch {
+		case spec.HintsEnabled() && containerLogs:
+			provider["hints.enabled"] = true
+			provider["hints.default_config"] = containerLogsConfig(v)
+		case spec.HintsEnabled():
+			// only collect the logs of the Pods annotated with co.elastic.logs/enabled: true
+			provider["hints.enabled"] = true
+			provider["hints.default_config.enabled"] = false
+		case containerLogs:
+			provider["templates"] = []interface{}{
+				map[string]interface{}{"config": []interface{}{containerLogsConfig(v)}},
+			}
+		default:
+			return settings.NewCanonicalConfig(), nil
+		}
+		cfg["filebeat.autodiscover.providers"] = []interface{}{provider}
+	case "metricbeat":
+		if spec.HintsEnabled() {
+			provider["hints.enabled"] = true
+			cfg["metricbeat.autodiscover.providers"] = []interface{}{provider}
+		}
+		if spec.HasTemplate(beatv1beta1.PodMetricsTemplate) {
+			cfg["metricbeat.modules"] = []interface{}{podMetricsConfig()}
+		}
+	}
+	return settings.NewCanonicalConfigFrom(cfg)
+}
+
+// withAutodiscover adds to the Pods of the Beat the environment variables, volumes and ServiceAccount required by
+// autodiscover.
+func withAutodiscover(builder *defaults.PodTemplateBuilder, params DriverParams) *defaults.PodTemplateBuilder {
+	spec := params.Beat.Spec.Autodiscover
+	if spec == nil {
+		return builder
+	}
+	env := []corev1.EnvVar{{Name: nodeNameEnvVar, ValueFrom: &corev1.EnvVarSource{
+		FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "spec.nodeName"},
+	}}}
+	if spec.HasTemplate(beatv1beta1.PodMetricsTemplate) {
+		env = append(env, corev1.EnvVar{Name: nodeIPEnvVar, ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "status.hostIP"},
+		}})
+	}
+	builder = builder.WithEnv(env...)
+	if spec.HasTemplate(beatv1beta1.ContainerLogsTemplate) {
+		for _, v := range []volume.HostVolume{
+			volume.NewReadOnlyHostVolume("varlogcontainers", "/var/log/containers", "/var/log/containers"),
+			volume.NewReadOnlyHostVolume("varlogpods", "/var/log/pods", "/var/log/pods"),
+			volume.NewReadOnlyHostVolume("varlibdockercontainers", "/var/lib/docker/containers", "/var/lib/docker/containers"),
+		} {
+			builder = builder.WithVolumes(v.Volume()).WithVolumeMounts(v.VolumeMount())
+		}
+	}
+	if autodiscoverRBACEnabled(params) {
+		builder = builder.WithServiceAccount(AutodiscoverServiceAccountName(params.Beat.Name))
+	}
+	// autodiscover requires access to the Kubernetes API, unless explicitly disabled by the user
+	if params.GetPodTemplate().Spec.AutomountServiceAccountToken == nil {
+		builder.PodTemplate.Spec.AutomountServiceAccountToken = ptr.To(true)
+	}
+	return builder
+}
+
+func autodiscoverRBACEnabled(params DriverParams) bool {
+	return params.AutodiscoverClusterRole != "" && params.Beat.Spec.Autodiscover != nil &&
+		params.Beat.Spec.Autodiscover.RBACEnabled()
+}
+
+// reconcileAutodiscoverRBAC creates the ServiceAccount of a Beat using autodiscover, and binds it to the ClusterRole
+// configured in the operator. It deletes them if they are not needed anymore.
+func reconcileAutodiscoverRBAC(params DriverParams) error {
+	if params.AutodiscoverClusterRole == "" {
+		// the operator does not manage the RBAC resources of autodiscover
+		return nil
+	}
+	beat := params.Beat
+	nsn := k8s.ExtractNamespacedName(&beat)
+	if !autodiscoverRBACEnabled(params) {
+		if err := DeleteAutodiscoverClusterRoleBinding(params.Context, params.Client, nsn); err != nil {
+			return err
+		}
+		return deleteIfExists(params.Context, params.Client, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Namespace: beat.Namespace,
+			Name:      AutodiscoverServiceAccountName(beat.Name),
+		}})
+	}
+
+	serviceAccount := corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: beat.Namespace,
+			Name:      AutodiscoverServiceAccountName(beat.Name),
+			Labels:    beat.GetIdentityLabels(),
+		},
+	}
+	reconciledServiceAccount := &corev1.ServiceAccount{}
+	if err := reconciler.ReconcileResource(reconciler.Params{
+		Context:    params.Context,
+		Client:     params.Client,
+		Owner:      &beat,
+		Expected:   &serviceAccount,
+		Reconciled: reconciledServiceAccount,
+		NeedsUpdate: func() bool {
+			return !maps.IsSubset(serviceAccount.Labels, reconciledServiceAccount.Labels)
+		},
+		UpdateReconciled: func() {
+			reconciledServiceAccount.Labels = maps.Merge(reconciledServiceAccount.Labels, serviceAccount.Labels)
+		},
+	}); err != nil {
+		return err
+	}
+
+	// ClusterRoleBindings are cluster-scoped and cannot be owned by the Beat, they are deleted with it by the controller
+	binding := rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   AutodiscoverClusterRoleBindingName(nsn),
+			Labels: maps.Merge(beat.GetIdentityLabels(), map[string]string{AutodiscoverNamespaceLabelName: beat.Namespace}),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     params.AutodiscoverClusterRole,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Namespace: beat.Namespace,
+			Name:      serviceAccount.Name,
+		}},
+	}
+	reconciledBinding := &rbacv1.ClusterRoleBinding{}
+	return reconciler.ReconcileResource(reconciler.Params{
+		Context:    params.Context,
+		Client:     params.Client,
+		Expected:   &binding,
+		Reconciled: reconciledBinding,
+		NeedsRecreate: func() bool {
+			// the role of a binding cannot be updated
+			return !reflect.DeepEqual(binding.RoleRef, reconciledBinding.RoleRef)
+		},
+		NeedsUpdate: func() bool {
+			return !maps.IsSubset(binding.Labels, reconciledBinding.Labels) ||
+				!reflect.DeepEqual(binding.Subjects, reconciledBinding.Subjects)
+		},
+		UpdateReconciled: func() {
+			reconciledBinding.Labels = maps.Merge(reconciledBinding.Labels, binding.Labels)
+			reconciledBinding.Subjects = binding.Subjects
+		},
+	})
+}
+
+// DeleteAutodiscoverClusterRoleBinding deletes the ClusterRoleBinding generated for the given Beat, if any.
+func DeleteAutodiscoverClusterRoleBinding(ctx context.Context, c k8s.Client, beat types.NamespacedName) error {
+	return deleteIfExists(ctx, c, &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{
+		Name: AutodiscoverClusterRoleBindingName(beat),
+	}})
+}
+
+func deleteIfExists(ctx context.Context, c k8s.Client, obj client.Object) error {
+	if err := c.Get(ctx, k8s.ExtractNamespacedName(obj), obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	return client.IgnoreNotFound(c.Delete(ctx, obj))
+}
@@ -0,0 +1,194 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func autodiscoverBeat(typ string, autodiscover *beatv1beta1.AutodiscoverSpec) beatv1beta1.Beat {
+	return beatv1beta1.Beat{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "beat"},
+		Spec: beatv1beta1.BeatSpec{
+			Type:         typ,
+			Version:      "8.15.0",
+			DaemonSet:    &beatv1beta1.DaemonSetSpec{},
+			Autodiscover: autodiscover,
+		},
+	}
+}
+
+func Test_buildAutodiscoverConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		beat beatv1beta1.Beat
+		want string
+	}{
+		{
+			name: "no autodiscover",
+			beat: autodiscoverBeat("filebeat", nil),
+			want: `{}`,
+		},
+		{
+			name: "filebeat hints only collect the logs of annotated Pods",
+			beat: autodiscoverBeat("filebeat", &beatv1beta1.AutodiscoverSpec{}),
+			want: `
+filebeat.autodiscover.providers:
+- type: kubernetes
+  node: ${NODE_NAME}
+  hints.enabled: true
+  hints.default_config.enabled: false`,
+		},
+		{
+			name: "filebeat hints with container logs",
+			beat: autodiscoverBeat("filebeat", &beatv1beta1.AutodiscoverSpec{
+				Templates: []beatv1beta1.AutodiscoverTemplate{beatv1beta1.ContainerLogsTemplate},
+			}),
+			want: `
+filebeat.autodiscover.providers:
+- type: kubernetes
+  node: ${NODE_NAME}
+  hints.enabled: true
+  hints.default_config:
+    type: filestream
+    id: kubernetes-container-logs-${data.kubernetes.pod.name}-${data.kubernetes.container.id}
+    paths: ["/var/log/containers/*-${data.kubernetes.container.id}.log"]
+    parsers: [{container: {}}]
+    prospector.scanner.symlinks: true`,
+		},
+		{
+			name: "filebeat container logs without hints",
+			beat: autodiscoverBeat("filebeat", &beatv1beta1.AutodiscoverSpec{
+				Hints:     ptr.To(false),
+				Templates: []beatv1beta1.AutodiscoverTemplate{beatv1beta1.ContainerLogsTemplate},
+			}),
+			want: `
+filebeat.autodiscover.providers:
+- type: kubernetes
+  node: ${NODE_NAME}
+  templates:
+  - config:
+    - type: filestream
+      id: kubernetes-container-logs-${data.kubernetes.pod.name}-${data.kubernetes.container.id}
+      paths: ["/var/log/containers/*-${data.kubernetes.container.id}.log"]
+      parsers: [{container: {}}]
+      prospector.scanner.symlinks: true`,
+		},
+		{
+			name: "metricbeat hints with pod metrics",
+			beat: autodiscoverBeat("metricbeat", &beatv1beta1.AutodiscoverSpec{
+				Templates: []beatv1beta1.AutodiscoverTemplate{beatv1beta1.PodMetricsTemplate},
+			}),
+			want: `
+metricbeat.autodiscover.providers:
+- type: kubernetes
+  node: ${NODE_NAME}
+  hints.enabled: true
+metricbeat.modules:
+- module: kubernetes
+  period: 10s
+  node: ${NODE_NAME}
+  hosts: ["https://${NODE_IP}:10250"]
+  bearer_token_file: /var/run/secrets/kubernetes.io/serviceaccount/token
+  ssl.verification_mode: none
+  metricsets: [node, system, pod, container, volume]`,
+		},
+		{
+			name: "metricbeat deployment watches the whole cluster",
+			beat: func() beatv1beta1.Beat {
+				beat := autodiscoverBeat("metricbeat", &beatv1beta1.AutodiscoverSpec{})
+				beat.Spec.DaemonSet = nil
+				beat.Spec.Deployment = &beatv1beta1.DeploymentSpec{}
+				return beat
+			}(),
+			want: `
+metricbeat.autodiscover.providers:
+- type: kubernetes
+  scope: cluster
+  hints.enabled: true`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildAutodiscoverConfig(tt.beat)
+			require.NoError(t, err)
+			diff := settings.MustParseConfig([]byte(tt.want)).Diff(got, nil)
+			require.Empty(t, diff)
+		})
+	}
+}
+
+func Test_withAutodiscover(t *testing.T) {
+	beat := autodiscoverBeat("filebeat", &beatv1beta1.AutodiscoverSpec{
+		Templates: []beatv1beta1.AutodiscoverTemplate{beatv1beta1.ContainerLogsTemplate},
+	})
+	params := DriverParams{Beat: beat, AutodiscoverClusterRole: "beat-autodiscover"}
+
+	podTemplate := withAutodiscover(defaults.NewPodTemplateBuilder(corev1.PodTemplateSpec{}, "filebeat"), params).PodTemplate
+	assert.Equal(t, "beat-beat-autodiscover", podTemplate.Spec.ServiceAccountName)
+	assert.Equal(t, ptr.To(true), podTemplate.Spec.AutomountServiceAccountToken)
+	assert.Len(t, podTemplate.Spec.Volumes, 3)
+	require.Len(t, podTemplate.Spec.Containers, 1)
+	assert.Len(t, podTemplate.Spec.Containers[0].VolumeMounts, 3)
+	require.Len(t, podTemplate.Spec.Containers[0].Env, 1)
+	assert.Equal(t, "NODE_NAME", podTemplate.Spec.Containers[0].Env[0].Name)
+
+	// the ServiceAccount of the user is kept
+	podTemplate = withAutodiscover(defaults.NewPodTemplateBuilder(corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{ServiceAccountName: "filebeat"},
+	}, "filebeat"), params).PodTemplate
+	assert.Equal(t, "filebeat", podTemplate.Spec.ServiceAccountName)
+
+	// no ServiceAccount if the operator does not manage the RBAC resources
+	params.AutodiscoverClusterRole = ""
+	podTemplate = withAutodiscover(defaults.NewPodTemplateBuilder(corev1.PodTemplateSpec{}, "filebeat"), params).PodTemplate
+	assert.Empty(t, podTemplate.Spec.ServiceAccountName)
+}
+
+func Test_reconcileAutodiscoverRBAC(t *testing.T) {
+	ctx := context.Background()
+	c := k8s.NewFakeClient()
+	beat := autodiscoverBeat("filebeat", &beatv1beta1.AutodiscoverSpec{})
+	params := DriverParams{Context: ctx, Client: c, Beat: beat, AutodiscoverClusterRole: "beat-autodiscover"}
+	bindingName := types.NamespacedName{Name: AutodiscoverClusterRoleBindingName(k8s.ExtractNamespacedName(&beat))}
+	serviceAccountName := types.NamespacedName{Namespace: "ns", Name: AutodiscoverServiceAccountName("beat")}
+
+	require.NoError(t, reconcileAutodiscoverRBAC(params))
+	var serviceAccount corev1.ServiceAccount
+	require.NoError(t, c.Get(ctx, serviceAccountName, &serviceAccount))
+	var binding rbacv1.ClusterRoleBinding
+	require.NoError(t, c.Get(ctx, bindingName, &binding))
+	assert.Equal(t, "beat-autodiscover", binding.RoleRef.Name)
+	assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Namespace: "ns", Name: serviceAccountName.Name}}, binding.Subjects)
+	assert.Equal(t, "ns", binding.Labels[AutodiscoverNamespaceLabelName])
+
+	// the binding is recreated if the ClusterRole changes
+	params.AutodiscoverClusterRole = "other-role"
+	require.NoError(t, reconcileAutodiscoverRBAC(params))
+	require.NoError(t, c.Get(ctx, bindingName, &binding))
+	assert.Equal(t, "other-role", binding.RoleRef.Name)
+
+	// the resources are deleted when RBAC is disabled
+	params.Beat.Spec.Autodiscover.RBAC = ptr.To(false)
+	require.NoError(t, reconcileAutodiscoverRBAC(params))
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, bindingName, &binding)))
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, serviceAccountName, &serviceAccount)))
+	// and nothing happens if they do not exist
+	require.NoError(t, reconcileAutodiscoverRBAC(params))
+}
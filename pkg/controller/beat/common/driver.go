@@ -42,6 +42,9 @@ type DriverParams struct {
 
 	Status *beatv1beta1.BeatStatus
 	Beat   beatv1beta1.Beat
+
+	// AutodiscoverClusterRole is the ClusterRole bound to the ServiceAccount of the Beats using autodiscover.
+	AutodiscoverClusterRole string
 }
 
 func (dp DriverParams) K8sClient() k8s.Client {
@@ -90,6 +93,10 @@ func Reconcile(
 		return results, params.Status // will eventually retry
 	}
 
+	if err := reconcileAutodiscoverRBAC(params); err != nil {
+		return results.WithError(err), params.Status
+	}
+
 	configHash := fnv.New32a()
 	if err := reconcileConfig(params, managedConfig, configHash); err != nil {
 		return results.WithError(err), params.Status
@@ -200,17 +200,22 @@ func buildPodTemplate(
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	image, err := container.ResolveImage(spec.Image, defaultImage, v)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	builder := defaults.NewPodTemplateBuilder(podTemplate, spec.Type).
 		WithLabels(labels).
 		WithAnnotations(annotations).
 		WithResources(defaultResources).
-		WithDockerImage(spec.Image, container.ImageRepository(defaultImage, v)).
+		WithDockerImage(spec.Image, image).
 		WithVolumes(volumes...).
 		WithVolumeMounts(volumeMounts...).
 		WithInitContainers(initContainers...).
 		WithInitContainerDefaults().
 		WithContainers(sideCars...)
+	builder = withAutodiscover(builder, params)
 
 	// If logs monitoring is enabled, remove the "-e" argument from the main container
 	// if it exists, and do not include the "-e" startup option for the Beat so that
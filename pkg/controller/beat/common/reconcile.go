@@ -45,12 +45,6 @@ func reconcilePodVehicle(podTemplate corev1.PodTemplateSpec, params DriverParams
 		}
 	case spec.Deployment != nil:
 		reconciliationFunc = reconcileDeployment
-		toDelete = &v1.DaemonSet{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      name,
-				Namespace: params.Beat.Namespace,
-			},
-		}
 	}
 
 	ready, desired, err := reconciliationFunc(ReconciliationParams{
@@ -64,7 +58,10 @@ func reconcilePodVehicle(podTemplate corev1.PodTemplateSpec, params DriverParams
 	}
 
 	// clean up the other one
-	if err := params.Client.Get(params.Context, types.NamespacedName{
+	if toDelete == nil {
+		// the DaemonSets, including the ones of the variants, are garbage collected when a Deployment is used
+		results.WithError(daemonset.GarbageCollect(params.Context, params.Client, &params.Beat, params.Beat.GetIdentityLabels(), nil))
+	} else if err := params.Client.Get(params.Context, types.NamespacedName{
 		Namespace: params.Beat.Namespace,
 		Name:      name,
 	}, toDelete); err == nil {
@@ -103,7 +100,7 @@ func reconcileDeployment(rp ReconciliationParams) (int32, int32, error) {
 		return 0, 0, err
 	}
 
-	reconciled, err := deployment.Reconcile(rp.ctx, rp.client, d, &rp.beat)
+	reconciled, err := deployment.Reconcile(rp.ctx, rp.client, d, &rp.beat, false)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -112,7 +109,7 @@ func reconcileDeployment(rp ReconciliationParams) (int32, int32, error) {
 }
 
 func reconcileDaemonSet(rp ReconciliationParams) (int32, int32, error) {
-	ds := daemonset.New(daemonset.Params{
+	expected := daemonset.NewVariants(daemonset.Params{
 		PodTemplate:          rp.podTemplate,
 		Name:                 Name(rp.beat.Name, rp.beat.Spec.Type),
 		Owner:                &rp.beat,
@@ -120,18 +117,28 @@ func reconcileDaemonSet(rp ReconciliationParams) (int32, int32, error) {
 		RevisionHistoryLimit: rp.beat.Spec.RevisionHistoryLimit,
 		Selectors:            rp.beat.GetIdentityLabels(),
 		Strategy:             rp.beat.Spec.DaemonSet.UpdateStrategy,
-	})
+	}, rp.beat.Spec.DaemonSet.Variants)
 
-	if err := controllerutil.SetControllerReference(&rp.beat, &ds, scheme.Scheme); err != nil {
-		return 0, 0, err
+	var ready, desired int32
+	for i := range expected {
+		if err := controllerutil.SetControllerReference(&rp.beat, &expected[i], scheme.Scheme); err != nil {
+			return 0, 0, err
+		}
+
+		reconciled, err := daemonset.Reconcile(rp.ctx, rp.client, expected[i], &rp.beat)
+		if err != nil {
+			return 0, 0, err
+		}
+		ready += reconciled.Status.NumberReady
+		desired += reconciled.Status.DesiredNumberScheduled
 	}
 
-	reconciled, err := daemonset.Reconcile(rp.ctx, rp.client, ds, &rp.beat)
-	if err != nil {
+	// delete the DaemonSets of the removed variants, or the default DaemonSet replaced by variants
+	if err := daemonset.GarbageCollect(rp.ctx, rp.client, &rp.beat, rp.beat.GetIdentityLabels(), expected); err != nil {
 		return 0, 0, err
 	}
 
-	return reconciled.Status.NumberReady, reconciled.Status.DesiredNumberScheduled, nil
+	return ready, desired, nil
 }
 
 // newStatus will calculate a new status from the state of the pods within the k8s cluster
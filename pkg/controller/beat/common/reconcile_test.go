@@ -15,6 +15,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/daemonset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
 )
@@ -114,3 +116,59 @@ func Test_reconcileDaemonSet(t *testing.T) {
 		})
 	}
 }
+
+func Test_reconcileDaemonSet_variants(t *testing.T) {
+	beat := beatv1beta1.Beat{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-beat", Namespace: "my-namespace", UID: "beat-uid"},
+		Spec: beatv1beta1.BeatSpec{
+			Type:      "filebeat",
+			DaemonSet: &beatv1beta1.DaemonSetSpec{},
+		},
+	}
+	params := ReconciliationParams{
+		ctx:    context.Background(),
+		client: k8s.NewFakeClient(),
+		beat:   beat,
+		podTemplate: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{NodeSelector: map[string]string{"kubernetes.io/os": "linux"}},
+		},
+	}
+	// start with the default DaemonSet
+	_, _, err := reconcileDaemonSet(params)
+	require.NoError(t, err)
+
+	params.beat.Spec.DaemonSet.Variants = []commonv1.DaemonSetVariant{
+		{Name: "amd64", NodeSelector: map[string]string{"kubernetes.io/arch": "amd64"}},
+		{
+			Name:         "gpu",
+			NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"},
+			Tolerations:  []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+		},
+	}
+	_, _, err = reconcileDaemonSet(params)
+	require.NoError(t, err)
+
+	var list appsv1.DaemonSetList
+	require.NoError(t, params.client.List(context.Background(), &list))
+	// the default DaemonSet is replaced by the DaemonSets of the variants
+	require.Len(t, list.Items, 2)
+	byName := map[string]appsv1.DaemonSet{}
+	for _, ds := range list.Items {
+		byName[ds.Name] = ds
+	}
+	amd64, gpu := byName["my-beat-beat-filebeat-amd64"], byName["my-beat-beat-filebeat-gpu"]
+	require.Equal(t, map[string]string{"kubernetes.io/os": "linux", "kubernetes.io/arch": "amd64"}, amd64.Spec.Template.Spec.NodeSelector)
+	require.Empty(t, amd64.Spec.Template.Spec.Tolerations)
+	require.Equal(t, "amd64", amd64.Spec.Selector.MatchLabels[daemonset.VariantLabelName])
+	require.Equal(t, "amd64", amd64.Spec.Template.Labels[daemonset.VariantLabelName])
+	require.Equal(t, map[string]string{"kubernetes.io/os": "linux", "kubernetes.io/arch": "arm64"}, gpu.Spec.Template.Spec.NodeSelector)
+	require.Len(t, gpu.Spec.Template.Spec.Tolerations, 1)
+
+	// removing a variant deletes its DaemonSet
+	params.beat.Spec.DaemonSet.Variants = params.beat.Spec.DaemonSet.Variants[:1]
+	_, _, err = reconcileDaemonSet(params)
+	require.NoError(t, err)
+	require.NoError(t, params.client.List(context.Background(), &list))
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "my-beat-beat-filebeat-amd64", list.Items[0].Name)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/queue"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
@@ -69,7 +70,7 @@ func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileBe
 // addWatches adds watches for all resources this controller cares about
 func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileBeat) error {
 	// Watch for changes to Beat
-	if err := c.Watch(source.Kind(mgr.GetCache(), &beatv1beta1.Beat{}, &handler.TypedEnqueueRequestForObject[*beatv1beta1.Beat]{})); err != nil {
+	if err := c.Watch(source.Kind(mgr.GetCache(), &beatv1beta1.Beat{}, queue.EnqueueRequestForObject[*beatv1beta1.Beat]())); err != nil {
 		return err
 	}
 
@@ -96,7 +97,7 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileBeat)
 	}
 
 	// Watch owned and soft-owned Secrets
-	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
+	if err := c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
 		mgr.GetScheme(), mgr.GetRESTMapper(),
 		&beatv1beta1.Beat{}, handler.OnlyControllerOwner(),
 	))); err != nil {
@@ -107,7 +108,7 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileBeat)
 	}
 
 	// Watch dynamically referenced Secrets
-	return c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets))
+	return c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets))
 }
 
 var _ reconcile.Reconciler = &ReconcileBeat{}
@@ -182,7 +183,7 @@ func (r *ReconcileBeat) doReconcile(ctx context.Context, beat beatv1beta1.Beat)
 		return results.WithError(err), &status
 	}
 
-	driverResults, updatedStatus := newDriver(ctx, r.recorder, r.Client, r.dynamicWatches, beat, status).Reconcile()
+	driverResults, updatedStatus := newDriver(ctx, r.recorder, r.Client, r.dynamicWatches, r.BeatAutodiscoverClusterRole, beat, status).Reconcile()
 	return results.WithResults(driverResults), updatedStatus
 }
 
@@ -202,6 +203,11 @@ func (r *ReconcileBeat) validate(ctx context.Context, beat *beatv1beta1.Beat) er
 func (r *ReconcileBeat) onDelete(ctx context.Context, obj types.NamespacedName) error {
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(obj))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(common.ConfigRefWatchName(obj))
+	if r.BeatAutodiscoverClusterRole != "" {
+		if err := beatcommon.DeleteAutodiscoverClusterRoleBinding(ctx, r.Client, obj); err != nil {
+			return err
+		}
+	}
 	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, obj, beatv1beta1.Kind)
 }
 
@@ -210,16 +216,18 @@ func newDriver(
 	recorder record.EventRecorder,
 	client k8s.Client,
 	dynamicWatches watches.DynamicWatches,
+	autodiscoverClusterRole string,
 	beat beatv1beta1.Beat,
 	status beatv1beta1.BeatStatus,
 ) beatcommon.Driver {
 	dp := beatcommon.DriverParams{
-		Client:        client,
-		Context:       ctx,
-		Watches:       dynamicWatches,
-		EventRecorder: recorder,
-		Status:        &status,
-		Beat:          beat,
+		Client:                  client,
+		Context:                 ctx,
+		Watches:                 dynamicWatches,
+		EventRecorder:           recorder,
+		Status:                  &status,
+		Beat:                    beat,
+		AutodiscoverClusterRole: autodiscoverClusterRole,
 	}
 
 	switch beat.Spec.Type {
@@ -0,0 +1,26 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package apiresource
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+)
+
+// RecordDrift reports that a resource was modified in Elasticsearch or Kibana outside of the operator, and returns the
+// time of the drift to set in its status.
+func RecordDrift(log logr.Logger, recorder record.EventRecorder, obj runtime.Object, msg string) *metav1.Time {
+	log.Info(msg)
+	recorder.Event(obj, corev1.EventTypeWarning, events.EventReasonUnexpected, msg)
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+	return &now
+}
@@ -0,0 +1,34 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package apiresource
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// ReadyElasticsearch returns the given Elasticsearch cluster and the Ready phase if the cluster is ready to be configured
+// through its API. Otherwise, it returns the phase of a resource waiting for the cluster, Error if the cluster does not
+// exist, and a message explaining why.
+func ReadyElasticsearch(ctx context.Context, c k8s.Client, esName types.NamespacedName) (esv1.Elasticsearch, commonv1.APIResourcePhase, string) {
+	var es esv1.Elasticsearch
+	if err := c.Get(ctx, esName, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return es, commonv1.APIResourceErrorPhase, fmt.Sprintf("Elasticsearch %s not found", esName.Name)
+		}
+		return es, commonv1.APIResourceApplyingChangesPhase, err.Error()
+	}
+	if es.Status.Phase != esv1.ElasticsearchReadyPhase {
+		return es, commonv1.APIResourceApplyingChangesPhase, "Waiting for Elasticsearch to be ready"
+	}
+	return es, commonv1.APIResourceReadyPhase, ""
+}
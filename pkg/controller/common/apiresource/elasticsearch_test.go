@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package apiresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReadyElasticsearch(t *testing.T) {
+	esName := types.NamespacedName{Namespace: "ns", Name: "es"}
+	tests := []struct {
+		name      string
+		objs      []client.Object
+		wantPhase commonv1.APIResourcePhase
+		wantMsg   string
+	}{
+		{
+			name:      "not found",
+			wantPhase: commonv1.APIResourceErrorPhase,
+			wantMsg:   "Elasticsearch es not found",
+		},
+		{
+			name: "not ready",
+			objs: []client.Object{&esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+				Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchApplyingChangesPhase},
+			}},
+			wantPhase: commonv1.APIResourceApplyingChangesPhase,
+			wantMsg:   "Waiting for Elasticsearch to be ready",
+		},
+		{
+			name: "ready",
+			objs: []client.Object{&esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+				Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+			}},
+			wantPhase: commonv1.APIResourceReadyPhase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es, phase, msg := ReadyElasticsearch(context.Background(), k8s.NewFakeClient(tt.objs...), esName)
+			require.Equal(t, tt.wantPhase, phase)
+			require.Equal(t, tt.wantMsg, msg)
+			if phase == commonv1.APIResourceReadyPhase {
+				require.Equal(t, "es", es.Name)
+			}
+		})
+	}
+}
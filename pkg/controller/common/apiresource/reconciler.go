@@ -0,0 +1,271 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package apiresource reconciles the resources configured in Elasticsearch or Kibana through their API, such as ILM
+// policies, roles or Kibana spaces. The reconciliation flow is shared by all the kinds of resources, each kind only
+// implements how the resource is configured in and removed from Elasticsearch or Kibana.
+package apiresource
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/queue"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+var (
+	// DefaultRequeue is used to reconcile again a resource that is not ready.
+	DefaultRequeue = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	// DriftCheckRequeue is used to periodically check that a ready resource was not modified in Elasticsearch or Kibana.
+	DriftCheckRequeue = reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Minute}
+)
+
+// Requeue returns the result to reconcile a resource again, soon if it is not ready, or later to detect the changes made
+// to it in Elasticsearch or Kibana outside of the operator.
+func Requeue(ready bool) reconcile.Result {
+	if !ready {
+		return DefaultRequeue
+	}
+	return DriftCheckRequeue
+}
+
+// Resource is a Kubernetes resource configured in Elasticsearch or Kibana.
+type Resource interface {
+	client.Object
+	IsMarkedForDeletion() bool
+	ValidateCreate() (admission.Warnings, error)
+}
+
+// Kind implements the reconciliation of a kind of Resource whose status is of type S.
+type Kind[T Resource, S any] interface {
+	// NewObject returns an empty resource of this kind.
+	NewObject() T
+	// GetStatus returns the status of the given resource.
+	GetStatus(obj T) S
+	// SetStatus sets the status of the given resource.
+	SetStatus(obj T, status S)
+	// InvalidStatus returns the status of a resource whose specification is invalid.
+	InvalidStatus(obj T, err error) S
+	// Configure configures the resource in Elasticsearch or Kibana, and returns the results of the reconciliation and
+	// the status of the resource.
+	Configure(ctx context.Context, obj T) (*reconciler.Results, S)
+}
+
+// Remover is implemented by the kinds of resources removed from Elasticsearch or Kibana when they are deleted. A
+// finalizer is set on these resources.
+type Remover[T Resource] interface {
+	// Remove removes the resource from Elasticsearch or Kibana. A non-zero result retries the removal later.
+	Remove(ctx context.Context, obj T) (reconcile.Result, error)
+}
+
+// Forgetter is implemented by the kinds that keep state about the resources, to be cleared once they are deleted.
+type Forgetter interface {
+	Forget(resource types.NamespacedName)
+}
+
+// Completer is implemented by the kinds of resources that are not reconciled anymore once completed.
+type Completer[T Resource] interface {
+	IsCompleted(obj T) bool
+}
+
+// degradable is implemented by the statuses that can report a degraded health compared to a previous status.
+type degradable[S any] interface {
+	IsDegraded(prev S) bool
+}
+
+// Config identifies the controller of a kind of resources.
+type Config struct {
+	// ControllerName is the name of the controller, for example snapshotrepository-controller.
+	ControllerName string
+	// KindName is the kind of the resources, for example SnapshotRepository.
+	KindName string
+	// NameField is the name of the log field holding the name of the reconciled resource, for example repository_name.
+	NameField string
+	// Finalizer is set on the resources of the kinds implementing Remover.
+	Finalizer string
+}
+
+// Reconciler reconciles the resources of a Kind.
+type Reconciler[T Resource, S any] struct {
+	k8s.Client
+	config   Config
+	kind     Kind[T, S]
+	recorder record.EventRecorder
+	tracer   *apm.Tracer
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+var _ reconcile.Reconciler = &Reconciler[Resource, any]{}
+
+// NewReconciler returns a Reconciler of the resources of the given kind.
+func NewReconciler[T Resource, S any](
+	c k8s.Client,
+	recorder record.EventRecorder,
+	params operator.Parameters,
+	config Config,
+	kind Kind[T, S],
+) *Reconciler[T, S] {
+	return &Reconciler[T, S]{
+		Client:   c,
+		config:   config,
+		kind:     kind,
+		recorder: recorder,
+		tracer:   params.Tracer,
+	}
+}
+
+// Add creates a controller running the given reconciler, watching the resources it reconciles and the given sources,
+// and adds it to the Manager.
+func Add[T Resource, S any](mgr manager.Manager, params operator.Parameters, r *Reconciler[T, S], sources ...source.Source) error {
+	c, err := common.NewController(mgr, r.config.ControllerName, r, params)
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(source.Kind(mgr.GetCache(), r.kind.NewObject(), queue.EnqueueRequestForObject[T]())); err != nil {
+		return err
+	}
+	for _, src := range sources {
+		if err := c.Watch(src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reconcile reads the state of the cluster for a resource and configures it in Elasticsearch or Kibana.
+func (r *Reconciler[T, S]) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.tracer, r.config.ControllerName, r.config.NameField, request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	obj := r.kind.NewObject()
+	if err := r.Client.Get(ctx, request.NamespacedName, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.forget(request.NamespacedName)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	// skip unmanaged resources
+	if common.IsUnmanaged(ctx, obj) {
+		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation")
+		return reconcile.Result{}, nil
+	}
+
+	if obj.IsMarkedForDeletion() {
+		r.forget(request.NamespacedName)
+		res, err := r.onDelete(ctx, obj)
+		return res, tracing.CaptureError(ctx, err)
+	}
+
+	if completer, ok := r.kind.(Completer[T]); ok && completer.IsCompleted(obj) {
+		return reconcile.Result{}, nil
+	}
+
+	// make sure the resource is removed from Elasticsearch or Kibana when it is deleted
+	if _, ok := r.kind.(Remover[T]); ok && controllerutil.AddFinalizer(obj, r.config.Finalizer) {
+		if err := r.Client.Update(ctx, obj); err != nil {
+			return reconcile.Result{}, tracing.CaptureError(ctx, err)
+		}
+	}
+
+	// main reconciliation logic
+	results, status := r.doReconcile(ctx, obj)
+
+	// update status
+	if err := r.updateStatus(ctx, obj, status); err != nil {
+		if apierrors.IsConflict(err) {
+			return results.WithResult(reconcile.Result{Requeue: true}).Aggregate()
+		}
+		results.WithError(err)
+	}
+
+	return results.Aggregate()
+}
+
+func (r *Reconciler[T, S]) doReconcile(ctx context.Context, obj T) (*reconciler.Results, S) {
+	ulog.FromContext(ctx).V(1).Info("Reconcile " + r.config.KindName)
+
+	// run validation in case the webhook is disabled
+	if err := r.validate(ctx, obj); err != nil {
+		return reconciler.NewResult(ctx).WithError(err), r.kind.InvalidStatus(obj, err)
+	}
+
+	return r.kind.Configure(ctx, obj)
+}
+
+// onDelete removes the resource from Elasticsearch or Kibana, then removes the finalizer to let the deletion of the
+// resource proceed.
+func (r *Reconciler[T, S]) onDelete(ctx context.Context, obj T) (reconcile.Result, error) {
+	remover, ok := r.kind.(Remover[T])
+	if !ok || !controllerutil.ContainsFinalizer(obj, r.config.Finalizer) {
+		return reconcile.Result{}, nil
+	}
+	if res, err := remover.Remove(ctx, obj); err != nil || !res.IsZero() {
+		return res, err
+	}
+	controllerutil.RemoveFinalizer(obj, r.config.Finalizer)
+	return reconcile.Result{}, r.Client.Update(ctx, obj)
+}
+
+func (r *Reconciler[T, S]) forget(resource types.NamespacedName) {
+	if forgetter, ok := r.kind.(Forgetter); ok {
+		forgetter.Forget(resource)
+	}
+}
+
+func (r *Reconciler[T, S]) validate(ctx context.Context, obj T) error {
+	span, vctx := apm.StartSpan(ctx, "validate", tracing.SpanTypeApp)
+	defer span.End()
+
+	if _, err := obj.ValidateCreate(); err != nil {
+		ulog.FromContext(ctx).Error(err, "Validation failed")
+		k8s.MaybeEmitErrorEvent(r.recorder, err, obj, events.EventReasonValidation, err.Error())
+		return tracing.CaptureError(vctx, err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler[T, S]) updateStatus(ctx context.Context, obj T, status S) error {
+	span, _ := apm.StartSpan(ctx, "update_status", tracing.SpanTypeApp)
+	defer span.End()
+
+	current := r.kind.GetStatus(obj)
+	if equality.Semantic.DeepEqual(status, current) {
+		return nil // nothing to do
+	}
+	if d, ok := any(status).(degradable[S]); ok && d.IsDegraded(current) {
+		r.recorder.Event(obj, corev1.EventTypeWarning, events.EventReasonUnhealthy, r.config.KindName+" health degraded")
+	}
+	ulog.FromContext(ctx).V(1).Info("Updating status",
+		"iteration", atomic.LoadUint64(&r.iteration),
+		"status", status,
+	)
+	r.kind.SetStatus(obj, status)
+	return common.UpdateStatus(ctx, r.Client, obj)
+}
@@ -0,0 +1,197 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package apiresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const testFinalizer = "test.k8s.elastic.co/finalizer"
+
+var testConfig = Config{
+	ControllerName: "test-controller",
+	KindName:       "Kibana",
+	NameField:      "kibana_name",
+	Finalizer:      testFinalizer,
+}
+
+// fakeKind records the calls made by the Reconciler.
+type fakeKind struct {
+	configured   int
+	removed      int
+	forgotten    []types.NamespacedName
+	removeResult reconcile.Result
+	completed    bool
+}
+
+func (k *fakeKind) NewObject() *kbv1.Kibana {
+	return &kbv1.Kibana{}
+}
+
+func (k *fakeKind) GetStatus(kb *kbv1.Kibana) kbv1.KibanaStatus {
+	return kb.Status
+}
+
+func (k *fakeKind) SetStatus(kb *kbv1.Kibana, status kbv1.KibanaStatus) {
+	kb.Status = status
+}
+
+func (k *fakeKind) InvalidStatus(kb *kbv1.Kibana, _ error) kbv1.KibanaStatus {
+	return kbv1.KibanaStatus{DeploymentStatus: commonv1.DeploymentStatus{Health: commonv1.RedHealth}, ObservedGeneration: kb.Generation}
+}
+
+func (k *fakeKind) Configure(ctx context.Context, kb *kbv1.Kibana) (*reconciler.Results, kbv1.KibanaStatus) {
+	k.configured++
+	status := kbv1.KibanaStatus{DeploymentStatus: commonv1.DeploymentStatus{Health: commonv1.GreenHealth}, ObservedGeneration: kb.Generation}
+	return reconciler.NewResult(ctx).WithResult(Requeue(true)), status
+}
+
+func (k *fakeKind) Remove(_ context.Context, _ *kbv1.Kibana) (reconcile.Result, error) {
+	k.removed++
+	return k.removeResult, nil
+}
+
+func (k *fakeKind) Forget(resource types.NamespacedName) {
+	k.forgotten = append(k.forgotten, resource)
+}
+
+func (k *fakeKind) IsCompleted(_ *kbv1.Kibana) bool {
+	return k.completed
+}
+
+var (
+	_ Kind[*kbv1.Kibana, kbv1.KibanaStatus] = &fakeKind{}
+	_ Remover[*kbv1.Kibana]                 = &fakeKind{}
+	_ Forgetter                             = &fakeKind{}
+	_ Completer[*kbv1.Kibana]               = &fakeKind{}
+)
+
+var kbName = types.NamespacedName{Namespace: "ns", Name: "kb"}
+
+func newKibana(mutate func(*kbv1.Kibana)) *kbv1.Kibana {
+	kb := &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: kbName.Namespace, Name: kbName.Name, Generation: 1},
+		Spec:       kbv1.KibanaSpec{Version: "8.15.0"},
+	}
+	if mutate != nil {
+		mutate(kb)
+	}
+	return kb
+}
+
+func reconcileOnce(t *testing.T, kind *fakeKind, objs ...*kbv1.Kibana) (k8s.Client, reconcile.Result) {
+	t.Helper()
+	initObjs := make([]client.Object, 0, len(objs))
+	for _, obj := range objs {
+		initObjs = append(initObjs, obj)
+	}
+	k8sClient := k8s.NewFakeClient(initObjs...)
+	r := NewReconciler(k8sClient, record.NewFakeRecorder(10), operator.Parameters{}, testConfig, kind)
+	res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: kbName})
+	require.NoError(t, err)
+	return k8sClient, res
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	kind := &fakeKind{}
+	k8sClient, res := reconcileOnce(t, kind, newKibana(nil))
+	require.Equal(t, DriftCheckRequeue, res)
+	require.Equal(t, 1, kind.configured)
+
+	var kb kbv1.Kibana
+	require.NoError(t, k8sClient.Get(context.Background(), kbName, &kb))
+	require.Equal(t, []string{testFinalizer}, kb.Finalizers)
+	require.Equal(t, commonv1.GreenHealth, kb.Status.Health)
+}
+
+func TestReconciler_Reconcile_Invalid(t *testing.T) {
+	kind := &fakeKind{}
+	k8sClient := k8s.NewFakeClient(newKibana(func(kb *kbv1.Kibana) { kb.Spec.Version = "1.0.0" }))
+	recorder := record.NewFakeRecorder(10)
+	r := NewReconciler(k8sClient, recorder, operator.Parameters{}, testConfig, kind)
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: kbName})
+	require.Error(t, err)
+	require.Equal(t, 0, kind.configured)
+	// a single validation event is emitted
+	require.Len(t, recorder.Events, 1)
+
+	var kb kbv1.Kibana
+	require.NoError(t, k8sClient.Get(context.Background(), kbName, &kb))
+	require.Equal(t, commonv1.RedHealth, kb.Status.Health)
+}
+
+func TestReconciler_Reconcile_NotFound(t *testing.T) {
+	kind := &fakeKind{}
+	_, res := reconcileOnce(t, kind)
+	require.Equal(t, reconcile.Result{}, res)
+	require.Equal(t, []types.NamespacedName{kbName}, kind.forgotten)
+}
+
+func TestReconciler_Reconcile_Unmanaged(t *testing.T) {
+	kind := &fakeKind{}
+	_, res := reconcileOnce(t, kind, newKibana(func(kb *kbv1.Kibana) {
+		kb.Annotations = map[string]string{common.ManagedAnnotation: "false"}
+	}))
+	require.Equal(t, reconcile.Result{}, res)
+	require.Equal(t, 0, kind.configured)
+}
+
+func TestReconciler_Reconcile_Completed(t *testing.T) {
+	kind := &fakeKind{completed: true}
+	k8sClient, res := reconcileOnce(t, kind, newKibana(nil))
+	require.Equal(t, reconcile.Result{}, res)
+	require.Equal(t, 0, kind.configured)
+
+	var kb kbv1.Kibana
+	require.NoError(t, k8sClient.Get(context.Background(), kbName, &kb))
+	require.Empty(t, kb.Finalizers)
+}
+
+func TestReconciler_Reconcile_Deleted(t *testing.T) {
+	deleted := func(kb *kbv1.Kibana) {
+		now := metav1.Now()
+		kb.DeletionTimestamp = &now
+		kb.Finalizers = []string{testFinalizer}
+	}
+
+	t.Run("the finalizer is removed once the resource is removed", func(t *testing.T) {
+		kind := &fakeKind{}
+		k8sClient, res := reconcileOnce(t, kind, newKibana(deleted))
+		require.Equal(t, reconcile.Result{}, res)
+		require.Equal(t, 1, kind.removed)
+		require.Equal(t, 0, kind.configured)
+		require.Equal(t, []types.NamespacedName{kbName}, kind.forgotten)
+
+		var kb kbv1.Kibana
+		require.True(t, apierrors.IsNotFound(k8sClient.Get(context.Background(), kbName, &kb)))
+	})
+
+	t.Run("the finalizer is kept while the removal is retried", func(t *testing.T) {
+		kind := &fakeKind{removeResult: DefaultRequeue}
+		k8sClient, res := reconcileOnce(t, kind, newKibana(deleted))
+		require.Equal(t, DefaultRequeue, res)
+		require.Equal(t, 1, kind.removed)
+
+		var kb kbv1.Kibana
+		require.NoError(t, k8sClient.Get(context.Background(), kbName, &kb))
+		require.Equal(t, []string{testFinalizer}, kb.Finalizers)
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package apiresource
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// RequestsForMatching returns the requests to reconcile the resources, listed with a copy of the given empty list in
+// the namespace of the watched object, that match it.
+func RequestsForMatching[T Resource](
+	clnt k8s.Client,
+	list client.ObjectList,
+	matches func(obj T, watched client.Object) bool,
+) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return handler.TypedEnqueueRequestsFromMapFunc[client.Object](func(ctx context.Context, watched client.Object) []reconcile.Request {
+		objs := list.DeepCopyObject().(client.ObjectList) //nolint:forcetypeassert
+		if err := clnt.List(ctx, objs, client.InNamespace(watched.GetNamespace())); err != nil {
+			ulog.Log.Error(err, "Fail to list resources while watching", "kind", watched.GetObjectKind().GroupVersionKind().Kind)
+			return nil
+		}
+		items, err := meta.ExtractList(objs)
+		if err != nil {
+			ulog.Log.Error(err, "Fail to extract resources while watching", "kind", watched.GetObjectKind().GroupVersionKind().Kind)
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, item := range items {
+			if obj, ok := item.(T); ok && matches(obj, watched) {
+				requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(obj)})
+			}
+		}
+		return requests
+	})
+}
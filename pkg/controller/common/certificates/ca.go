@@ -33,6 +33,20 @@ type CA struct {
 	PrivateKey crypto.Signer
 	// Cert is the certificate used to issue new certificates
 	Cert *x509.Certificate
+	// Rotation is the ongoing rotation of the CA, if any
+	Rotation *CARotation
+}
+
+// TrustedCertsPEM returns the PEM encoded certificates that must be trusted for the CA: the certificate used to issue
+// new certificates and, during a rotation, the certificate of the next or previous CA.
+func (c *CA) TrustedCertsPEM() []byte {
+	certs := [][]byte{c.Cert.Raw}
+	if c.Rotation != nil {
+		for _, cert := range c.Rotation.TrustedCerts {
+			certs = append(certs, cert.Raw)
+		}
+	}
+	return EncodePEMCert(certs...)
 }
 
 // ValidatedCertificateTemplate is a type alias used to convey that the certificate template has been validated and
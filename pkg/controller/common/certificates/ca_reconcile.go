@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/fs"
@@ -97,8 +98,8 @@ func ReconcileCAForOwner(
 		return renewCA(ctx, cl, namer, owner, labels, rotationParams.Validity, caType)
 	}
 
-	// reuse existing CA
-	return ca, nil
+	// reuse existing CA, unless a rotation is forced through the owner annotations
+	return reconcileCARotation(ctx, cl, namer, owner, labels, caType, caInternalSecret, ca, rotationParams.Validity, time.Now())
 }
 
 // renewCAFromExisting will attempt to renew, or rather create a new CA using the existing
@@ -231,11 +232,17 @@ func internalSecretForCA(
 	if err != nil {
 		return corev1.Secret{}, err
 	}
+	annotations := map[string]string{}
+	if token := owner.GetAnnotations()[commonv1.RotateCAAnnotation]; token != "" {
+		// a new CA does not need to be rotated for the current token
+		annotations[CARotationTokenAnnotation] = token
+	}
 	return corev1.Secret{
 		ObjectMeta: v1.ObjectMeta{
-			Namespace: owner.GetNamespace(),
-			Name:      CAInternalSecretName(namer, owner.GetName(), caType),
-			Labels:    labels,
+			Namespace:   owner.GetNamespace(),
+			Name:        CAInternalSecretName(namer, owner.GetName(), caType),
+			Labels:      labels,
+			Annotations: annotations,
 		},
 		Data: map[string][]byte{
 			CertFileName: EncodePEMCert(ca.Cert.Raw),
@@ -313,6 +320,9 @@ func BuildCAFromFile(path string) (*CA, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot parse private key from PEM file %s", privateKeyFile)
 	}
+	if err := validateCAPrivateKey(privateKey); err != nil {
+		return nil, errors.Wrapf(err, "invalid private key in PEM file %s", privateKeyFile)
+	}
 	return NewCA(privateKey, cert), nil
 }
 
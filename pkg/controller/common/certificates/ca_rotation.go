@@ -0,0 +1,168 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// CARotationTokenAnnotation is the value of the rotate-ca annotation of the owner for which the last CA rotation
+	// was started, stored in the internal CA secret.
+	CARotationTokenAnnotation = "certificates.k8s.elastic.co/ca-rotation-token"
+	// CARotationPhaseEndAnnotation is the time at which the current phase of a CA rotation ends, stored in the
+	// internal CA secret.
+	CARotationPhaseEndAnnotation = "certificates.k8s.elastic.co/ca-rotation-phase-end"
+
+	// CARotationPhaseDuration is the duration of each phase of a CA rotation. It leaves enough time for the updated
+	// CA certificates to be propagated to the Pods before they are used.
+	CARotationPhaseDuration = 10 * time.Minute
+
+	nextCACertFileName     = "next-ca.crt"
+	nextCAKeyFileName      = "next-ca.key"
+	previousCACertFileName = "previous-ca.crt"
+)
+
+// CARotation is an ongoing rotation of a CA generated by the operator.
+type CARotation struct {
+	// TrustedCerts are the certificates of the next or previous CA, trusted in addition to the current one.
+	TrustedCerts []*x509.Certificate
+	// NextPhase is the time at which the rotation moves to its next phase.
+	NextPhase time.Time
+}
+
+// reconcileCARotation moves forward the rotation of the given CA forced through the rotate-ca annotation of the owner.
+// A rotation has three phases:
+//   - a new CA is generated and trusted next to the current one, which keeps issuing certificates
+//   - the new CA replaces the current one, the certificates are issued again, the previous CA is still trusted
+//   - the previous CA is not trusted anymore
+//
+// It returns the CA to use to issue certificates, with the ongoing rotation if any.
+func reconcileCARotation(
+	ctx context.Context,
+	cl k8s.Client,
+	namer name.Namer,
+	owner client.Object,
+	labels map[string]string,
+	caType CAType,
+	caInternalSecret corev1.Secret,
+	ca *CA,
+	validity time.Duration,
+	now time.Time,
+) (*CA, error) {
+	log := ulog.FromContext(ctx)
+	phaseEnd, err := time.Parse(time.RFC3339, caInternalSecret.Annotations[CARotationPhaseEndAnnotation])
+	if err != nil {
+		// end the current phase, if any, right away
+		phaseEnd = now
+	}
+	next := buildNextCA(ctx, caInternalSecret)
+	previous := parseSingleCert(caInternalSecret.Data[previousCACertFileName])
+	token := owner.GetAnnotations()[commonv1.RotateCAAnnotation]
+
+	switch {
+	case next != nil && now.Before(phaseEnd):
+		ca.Rotation = &CARotation{TrustedCerts: []*x509.Certificate{next.Cert}, NextPhase: phaseEnd}
+		return ca, nil
+	case next != nil:
+		log.Info("Replacing CA by the new one", "owner_namespace", owner.GetNamespace(), "owner_name", owner.GetName(), "ca_type", caType)
+		next.Rotation = &CARotation{TrustedCerts: []*x509.Certificate{ca.Cert}, NextPhase: now.Add(CARotationPhaseDuration)}
+		err = storeCARotation(ctx, cl, namer, owner, labels, caType, next, map[string][]byte{
+			previousCACertFileName: EncodePEMCert(ca.Cert.Raw),
+		})
+		return next, err
+	case previous != nil && now.Before(phaseEnd):
+		ca.Rotation = &CARotation{TrustedCerts: []*x509.Certificate{previous}, NextPhase: phaseEnd}
+		return ca, nil
+	case previous != nil:
+		log.Info("Removing previous CA", "owner_namespace", owner.GetNamespace(), "owner_name", owner.GetName(), "ca_type", caType)
+		return ca, storeCARotation(ctx, cl, namer, owner, labels, caType, ca, nil)
+	case token != "" && token != caInternalSecret.Annotations[CARotationTokenAnnotation]:
+		log.Info("Starting CA rotation", "owner_namespace", owner.GetNamespace(), "owner_name", owner.GetName(), "ca_type", caType)
+		next, err := NewSelfSignedCA(CABuilderOptions{
+			Subject: pkix.Name{
+				CommonName:         owner.GetName() + "-" + string(caType),
+				OrganizationalUnit: []string{owner.GetName()},
+			},
+			ExpireIn: &validity,
+		})
+		if err != nil {
+			return nil, err
+		}
+		nextKey, err := EncodePEMPrivateKey(next.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		ca.Rotation = &CARotation{TrustedCerts: []*x509.Certificate{next.Cert}, NextPhase: now.Add(CARotationPhaseDuration)}
+		err = storeCARotation(ctx, cl, namer, owner, labels, caType, ca, map[string][]byte{
+			nextCACertFileName: EncodePEMCert(next.Cert.Raw),
+			nextCAKeyFileName:  nextKey,
+		})
+		return ca, err
+	}
+	return ca, nil
+}
+
+// storeCARotation stores the given CA and its ongoing rotation, with the given additional data, in the internal CA secret.
+func storeCARotation(
+	ctx context.Context,
+	cl k8s.Client,
+	namer name.Namer,
+	owner client.Object,
+	labels map[string]string,
+	caType CAType,
+	ca *CA,
+	rotationData map[string][]byte,
+) error {
+	secret, err := internalSecretForCA(ca, namer, owner, labels, caType)
+	if err != nil {
+		return err
+	}
+	for k, v := range rotationData {
+		secret.Data[k] = v
+	}
+	if ca.Rotation != nil {
+		secret.Annotations[CARotationPhaseEndAnnotation] = ca.Rotation.NextPhase.UTC().Format(time.RFC3339)
+	}
+	_, err = reconciler.ReconcileSecret(ctx, cl, secret, owner)
+	return err
+}
+
+// buildNextCA returns the next CA stored in the internal CA secret during the first phase of a rotation, if any.
+func buildNextCA(ctx context.Context, caInternalSecret corev1.Secret) *CA {
+	if len(caInternalSecret.Data[nextCACertFileName]) == 0 || len(caInternalSecret.Data[nextCAKeyFileName]) == 0 {
+		return nil
+	}
+	return BuildCAFromSecret(ctx, corev1.Secret{
+		ObjectMeta: caInternalSecret.ObjectMeta,
+		Data: map[string][]byte{
+			CertFileName: caInternalSecret.Data[nextCACertFileName],
+			KeyFileName:  caInternalSecret.Data[nextCAKeyFileName],
+		},
+	})
+}
+
+func parseSingleCert(data []byte) *x509.Certificate {
+	if len(data) == 0 {
+		return nil
+	}
+	certs, err := ParsePEMCerts(data)
+	if err != nil || len(certs) == 0 {
+		return nil
+	}
+	return certs[0]
+}
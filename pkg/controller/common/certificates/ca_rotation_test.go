@@ -0,0 +1,112 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_reconcileCARotation(t *testing.T) {
+	ctx := context.Background()
+	c := k8s.NewFakeClient()
+	owner := testCluster.DeepCopy()
+	validity := DefaultCertValidity
+
+	getSecret := func() corev1.Secret {
+		t.Helper()
+		var secret corev1.Secret
+		require.NoError(t, c.Get(ctx, types.NamespacedName{
+			Namespace: owner.Namespace,
+			Name:      CAInternalSecretName(testNamer, owner.Name, TransportCAType),
+		}, &secret))
+		return secret
+	}
+	reconcileAt := func(now time.Time) *CA {
+		t.Helper()
+		secret := getSecret()
+		ca := BuildCAFromSecret(ctx, secret)
+		require.NotNil(t, ca)
+		ca, err := reconcileCARotation(ctx, c, testNamer, owner, nil, TransportCAType, secret, ca, validity, now)
+		require.NoError(t, err)
+		return ca
+	}
+	trusted := func(ca *CA) []*x509.Certificate {
+		t.Helper()
+		certs, err := ParsePEMCerts(ca.TrustedCertsPEM())
+		require.NoError(t, err)
+		return certs
+	}
+
+	initial, err := ReconcileCAForOwner(ctx, c, testNamer, owner, nil, TransportCAType, RotationParams{Validity: validity, RotateBefore: DefaultRotateBefore})
+	require.NoError(t, err)
+	now := time.Now()
+
+	// no rotation without the annotation
+	ca := reconcileAt(now)
+	require.Nil(t, ca.Rotation)
+	require.True(t, ca.Cert.Equal(initial.Cert))
+
+	// the annotation starts a rotation: the next CA is trusted, the current one still issues certificates
+	owner.Annotations = map[string]string{commonv1.RotateCAAnnotation: "1"}
+	ca = reconcileAt(now)
+	require.NotNil(t, ca.Rotation)
+	require.True(t, ca.Cert.Equal(initial.Cert))
+	require.Len(t, ca.Rotation.TrustedCerts, 1)
+	next := ca.Rotation.TrustedCerts[0]
+	assert.False(t, next.Equal(initial.Cert))
+	assert.Len(t, trusted(ca), 2)
+	assert.Equal(t, "1", getSecret().Annotations[CARotationTokenAnnotation])
+
+	// nothing changes until the end of the phase
+	ca = reconcileAt(now.Add(CARotationPhaseDuration / 2))
+	require.True(t, ca.Cert.Equal(initial.Cert))
+	require.True(t, ca.Rotation.TrustedCerts[0].Equal(next))
+
+	// the next CA replaces the current one, which remains trusted
+	now = now.Add(CARotationPhaseDuration + time.Second)
+	ca = reconcileAt(now)
+	require.True(t, ca.Cert.Equal(next))
+	require.NotNil(t, ca.Rotation)
+	require.True(t, ca.Rotation.TrustedCerts[0].Equal(initial.Cert))
+	assert.Len(t, trusted(ca), 2)
+	require.True(t, BuildCAFromSecret(ctx, getSecret()).Cert.Equal(next))
+
+	// the previous CA is removed at the end of the second phase
+	now = now.Add(CARotationPhaseDuration + time.Second)
+	ca = reconcileAt(now)
+	require.True(t, ca.Cert.Equal(next))
+	require.Nil(t, ca.Rotation)
+	assert.Len(t, trusted(ca), 1)
+	secret := getSecret()
+	assert.NotContains(t, secret.Data, previousCACertFileName)
+	assert.NotContains(t, secret.Data, nextCACertFileName)
+
+	// the same annotation value does not start another rotation
+	ca = reconcileAt(now.Add(time.Hour))
+	require.Nil(t, ca.Rotation)
+	require.True(t, ca.Cert.Equal(next))
+}
+
+func Test_internalSecretForCA_rotationToken(t *testing.T) {
+	// a new CA does not need to be rotated again for the current annotation value
+	owner := testCluster.DeepCopy()
+	owner.Annotations = map[string]string{commonv1.RotateCAAnnotation: "1"}
+	ca, err := NewSelfSignedCA(CABuilderOptions{})
+	require.NoError(t, err)
+	secret, err := internalSecretForCA(ca, testNamer, owner, nil, HTTPCAType)
+	require.NoError(t, err)
+	assert.Equal(t, "1", secret.Annotations[CARotationTokenAnnotation])
+}
@@ -55,5 +55,8 @@ func parseCAFromSecret(s corev1.Secret, keyFileName string, crtFileName string)
 	if len(pubKeys) != 1 {
 		return nil, pkgerrors.Errorf("only expected one PEM formated CA certificate in %s/%s", s.Namespace, s.Name)
 	}
+	if err := validateCAPrivateKey(privateKey); err != nil {
+		return nil, pkgerrors.Wrapf(err, "invalid private key %s in %s/%s", keyFileName, s.Namespace, s.Name)
+	}
 	return NewCA(privateKey, pubKeys[0]), nil
 }
@@ -4,7 +4,14 @@
 
 package certificates
 
-import "time"
+import (
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
 
 const (
 	// DefaultCertValidity makes new certificates default to a 1 year expiration
@@ -22,6 +29,60 @@ type RotationParams struct {
 	RotateBefore time.Duration
 }
 
+// WithOverrides returns the rotation params overridden by the given validity and rotate before durations, if set.
+// The params are returned unchanged if the overrides would rotate certificates before they are issued.
+func (p RotationParams) WithOverrides(validity, rotateBefore *metav1.Duration) RotationParams {
+	overridden := p
+	if validity != nil {
+		overridden.Validity = validity.Duration
+	}
+	if rotateBefore != nil {
+		overridden.RotateBefore = rotateBefore.Duration
+	}
+	if overridden.Validity <= 0 || overridden.RotateBefore <= 0 || overridden.RotateBefore >= overridden.Validity {
+		return p
+	}
+	return overridden
+}
+
+// OverrideRotationParams returns the CA and certificates rotation params overridden by the rotation settings of a
+// resource, if any.
+func OverrideRotationParams(caRotation, certRotation RotationParams, overrides *commonv1.CertificateRotation) (RotationParams, RotationParams) {
+	if overrides == nil {
+		return caRotation, certRotation
+	}
+	return caRotation.WithOverrides(overrides.CAValidity, overrides.CARotateBefore),
+		certRotation.WithOverrides(overrides.CertificateValidity, overrides.CertificateRotateBefore)
+}
+
+// DynamicRotationParams holds rotation parameters which can be updated while the operator is running.
+type DynamicRotationParams struct {
+	params atomic.Pointer[RotationParams]
+}
+
+// NewDynamicRotationParams returns DynamicRotationParams initialized with the given parameters.
+func NewDynamicRotationParams(params RotationParams) *DynamicRotationParams {
+	d := &DynamicRotationParams{}
+	d.Set(params)
+	return d
+}
+
+// Get returns the current rotation parameters, or zero values if d is nil.
+func (d *DynamicRotationParams) Get() RotationParams {
+	if d == nil {
+		return RotationParams{}
+	}
+	if params := d.params.Load(); params != nil {
+		return *params
+	}
+	return RotationParams{}
+}
+
+// Set updates the rotation parameters, which apply from the next reconciliations.
+func (d *DynamicRotationParams) Set(params RotationParams) {
+	d.params.Store(&params)
+}
+
 // ShouldRotateIn computes the duration after which a certificate rotation should be scheduled
 // in order for the cert to be rotated before it expires.
 func ShouldRotateIn(now time.Time, certExpiration time.Time, certRotateBefore time.Duration) time.Duration {
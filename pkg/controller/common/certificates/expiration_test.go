@@ -7,6 +7,9 @@ package certificates
 import (
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestShouldRotateIn(t *testing.T) {
@@ -55,3 +58,50 @@ func TestShouldRotateIn(t *testing.T) {
 		})
 	}
 }
+
+func TestDynamicRotationParams(t *testing.T) {
+	var unset *DynamicRotationParams
+	require.Equal(t, RotationParams{}, unset.Get())
+
+	params := NewDynamicRotationParams(RotationParams{Validity: DefaultCertValidity, RotateBefore: DefaultRotateBefore})
+	require.Equal(t, RotationParams{Validity: DefaultCertValidity, RotateBefore: DefaultRotateBefore}, params.Get())
+
+	params.Set(RotationParams{Validity: 10 * time.Hour, RotateBefore: time.Hour})
+	require.Equal(t, RotationParams{Validity: 10 * time.Hour, RotateBefore: time.Hour}, params.Get())
+}
+
+func TestRotationParams_WithOverrides(t *testing.T) {
+	params := RotationParams{Validity: 365 * 24 * time.Hour, RotateBefore: 24 * time.Hour}
+	tests := []struct {
+		name         string
+		validity     *metav1.Duration
+		rotateBefore *metav1.Duration
+		want         RotationParams
+	}{
+		{
+			name: "no overrides",
+			want: params,
+		},
+		{
+			name:     "override validity",
+			validity: &metav1.Duration{Duration: 30 * 24 * time.Hour},
+			want:     RotationParams{Validity: 30 * 24 * time.Hour, RotateBefore: 24 * time.Hour},
+		},
+		{
+			name:         "override both",
+			validity:     &metav1.Duration{Duration: 10 * time.Hour},
+			rotateBefore: &metav1.Duration{Duration: time.Hour},
+			want:         RotationParams{Validity: 10 * time.Hour, RotateBefore: time.Hour},
+		},
+		{
+			name:     "ignore overrides rotating certificates before they are issued",
+			validity: &metav1.Duration{Duration: time.Hour},
+			want:     params,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, params.WithOverrides(tt.validity, tt.rotateBefore))
+		})
+	}
+}
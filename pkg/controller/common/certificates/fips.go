@@ -0,0 +1,46 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/cryptutil"
+)
+
+// minFIPSRSAKeySize is the minimum size in bits of the RSA keys approved for FIPS 140-2.
+const minFIPSRSAKeySize = 2048
+
+// validateCAPrivateKey returns an error if the operator runs with FIPS approved cryptography and the private key of a
+// user provided CA is not FIPS approved, since it would also be used to generate the keys of the certificates it signs.
+func validateCAPrivateKey(key crypto.Signer) error {
+	if !cryptutil.FIPSEnabled() {
+		return nil
+	}
+	return fipsApprovedPrivateKey(key)
+}
+
+func fipsApprovedPrivateKey(key crypto.Signer) error {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minFIPSRSAKeySize {
+			return fmt.Errorf("RSA private keys must be at least %d bits long in FIPS mode, got %d bits", minFIPSRSAKeySize, k.N.BitLen())
+		}
+		return nil
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+			return nil
+		default:
+			return fmt.Errorf("ECDSA private keys must use the P-256, P-384 or P-521 curve in FIPS mode, got %s", k.Curve.Params().Name)
+		}
+	default:
+		return fmt.Errorf("unsupported private key type in FIPS mode: %T", key)
+	}
+}
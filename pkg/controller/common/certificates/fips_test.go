@@ -0,0 +1,48 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fipsApprovedPrivateKey(t *testing.T) {
+	rsa2048, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+	require.NoError(t, err)
+	rsa1024, err := rsa.GenerateKey(cryptorand.Reader, 1024) //nolint:gosec
+	require.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), cryptorand.Reader)
+	require.NoError(t, err)
+	p224, err := ecdsa.GenerateKey(elliptic.P224(), cryptorand.Reader)
+	require.NoError(t, err)
+	_, ed, err := ed25519.GenerateKey(cryptorand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		key     crypto.Signer
+		wantErr bool
+	}{
+		{name: "RSA 2048", key: rsa2048},
+		{name: "RSA 1024", key: rsa1024, wantErr: true},
+		{name: "ECDSA P-384", key: p384},
+		{name: "ECDSA P-224", key: p224, wantErr: true},
+		{name: "Ed25519", key: ed, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fipsApprovedPrivateKey(tt.key)
+			require.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
@@ -157,7 +157,7 @@ func (r Reconciler) populateFromCustomCertificateContents(secret *corev1.Secret,
 		// Ensure that the CA certificate is never empty, otherwise Elasticsearch is not able to reload the certificates.
 		// Default to our self-signed (useless) CA if none is provided by the user.
 		// See https://github.com/elastic/cloud-on-k8s/issues/2243
-		expectedSecretData[CAFileName] = EncodePEMCert(ca.Cert.Raw)
+		expectedSecretData[CAFileName] = ca.TrustedCertsPEM()
 		// The CA has been set in the internal HTTP secret but it's only for convenience, in order to circumvent the
 		// aforementioned issue. We need to remove it later from the result.
 		caCertProvided = false
@@ -239,12 +239,12 @@ func ensureInternalSelfSignedCertificateSecretContents(
 
 		secretWasChanged = true
 		// store certificate and signed certificate in a secret mounted into the pod
-		secret.Data[CAFileName] = EncodePEMCert(ca.Cert.Raw)
+		secret.Data[CAFileName] = ca.TrustedCertsPEM()
 		secret.Data[CertFileName] = EncodePEMCert(certificate, ca.Cert.Raw)
 	}
 
 	// Ensure that the CA certificate is up-to-date.
-	expectedCaPem := EncodePEMCert(ca.Cert.Raw)
+	expectedCaPem := ca.TrustedCertsPEM()
 	expectedCertPem := EncodePEMCert(certificate, ca.Cert.Raw)
 	if !reflect.DeepEqual(secret.Data[CAFileName], expectedCaPem) || !reflect.DeepEqual(secret.Data[CertFileName], expectedCertPem) {
 		log.Info(
@@ -57,6 +57,9 @@ func (r Reconciler) ReconcileCAAndHTTPCerts(ctx context.Context) (*CertificatesS
 
 	results := reconciler.NewResult(ctx)
 
+	// the rotation settings of the object take precedence over the operator ones
+	r.CACertRotation, r.CertRotation = OverrideRotationParams(r.CACertRotation, r.CertRotation, r.TLSOptions.CertificateRotation)
+
 	if !r.TLSOptions.Enabled() && r.GarbageCollectSecrets {
 		return nil, results.WithError(r.removeCAAndHTTPCertsSecrets(ctx))
 	}
@@ -94,6 +97,12 @@ func (r Reconciler) ReconcileCAAndHTTPCerts(ctx context.Context) (*CertificatesS
 				RequeueAfter(ShouldRotateIn(time.Now(), httpCa.Cert.NotAfter, r.CACertRotation.RotateBefore)).
 				ReconciliationComplete(), // This reconciliation result should not prevent the reconciliation loop to be considered as completed in the status
 		)
+		// move a forced CA rotation forward
+		if httpCa.Rotation != nil {
+			results.WithReconciliationState(
+				reconciler.RequeueAfter(ShouldRotateIn(time.Now(), httpCa.Rotation.NextPhase, 0)).ReconciliationComplete(),
+			)
+		}
 	}
 
 	// reconcile http customCerts: either self-signed or user-provided
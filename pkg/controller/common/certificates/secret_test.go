@@ -11,6 +11,8 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/cryptutil"
 )
 
 func TestCertificatesSecret(t *testing.T) {
@@ -201,7 +203,8 @@ func TestCertificatesSecret_Validate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: false,
+			// the test private key is too short to be FIPS approved
+			wantErr: cryptutil.FIPSEnabled(),
 		},
 		{
 			name: "Mixed leaf and custom CA 1/2",
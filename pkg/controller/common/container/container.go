@@ -22,6 +22,8 @@ var (
 	containerRegistry   = DefaultContainerRegistry
 	containerRepository = ""
 	containerSuffix     = ""
+	imageMappings       = map[string]string{}
+	requireImageDigests = false
 
 	major7UbiSuffixMinVersion = version.MinFor(7, 17, 16) // min 7.x to use UBISuffix
 	major8UbiSuffixMinVersion = version.MinFor(8, 12, 0)  // min 8.x to use UBISuffix
@@ -41,6 +43,18 @@ func SetContainerSuffix(suffix string) {
 	containerSuffix = suffix
 }
 
+// SetImageMappings sets the global image mappings used to override the default image of an Elastic stack application.
+// Keys are either an image name (e.g. "elasticsearch"), mapped to a repository the version is appended to,
+// or an image name and a version (e.g. "elasticsearch:8.15.0"), mapped to a full image reference.
+func SetImageMappings(mappings map[string]string) {
+	imageMappings = mappings
+}
+
+// SetRequireImageDigests sets whether images must be referenced by digest to be deployed.
+func SetRequireImageDigests(require bool) {
+	requireImageDigests = require
+}
+
 type Image string
 
 func (i Image) Name() string {
@@ -70,7 +84,13 @@ const (
 // ImageRepository returns the full container image name by concatenating the current container registry and the image path with the given version.
 // A UBI suffix (-ubi8 or -ubi suffix depending on the version) is appended to the image name for the maps image,
 // or any image if the operator is configured with --ubi-only.
+// Image mappings take precedence over the container registry and repository: an image reference mapped to the image
+// name and version is returned as is, a repository mapped to the image name replaces the registry and image path.
 func ImageRepository(img Image, ver version.Version) string {
+	if ref, exists := imageMappings[fmt.Sprintf("%s:%s", img.Name(), ver)]; exists {
+		return ref
+	}
+
 	// replace repository if defined
 	image := img
 
@@ -89,9 +109,31 @@ func ImageRepository(img Image, ver version.Version) string {
 		suffix += containerSuffix
 	}
 
+	if repository, exists := imageMappings[img.Name()]; exists {
+		return fmt.Sprintf("%s%s:%s", repository, suffix, ver)
+	}
+
 	return fmt.Sprintf("%s/%s%s:%s", containerRegistry, image, suffix, ver)
 }
 
+// ResolveImage returns the image to deploy: the custom image if not empty, the default image for the given version otherwise.
+// An error is returned if the operator requires images to be referenced by digest and the resolved image is not.
+func ResolveImage(customImage string, img Image, ver version.Version) (string, error) {
+	image := customImage
+	if image == "" {
+		image = ImageRepository(img, ver)
+	}
+	if requireImageDigests && !HasDigest(image) {
+		return "", fmt.Errorf("image %s is not referenced by digest, which is required by the operator configuration", image)
+	}
+	return image, nil
+}
+
+// HasDigest returns true if the given image reference includes a digest (e.g. docker.elastic.co/kibana/kibana@sha256:...).
+func HasDigest(image string) bool {
+	return strings.Contains(image, "@")
+}
+
 // getUBISuffix returns the UBI suffix to use depending on the given version.
 func getUBISuffix(ver version.Version) string {
 	if ver.Major == 7 && ver.LT(major7UbiSuffixMinVersion) {
@@ -162,3 +162,113 @@ func TestImageRepository(t *testing.T) {
 		})
 	}
 }
+
+func TestImageRepository_ImageMappings(t *testing.T) {
+	defer SetImageMappings(map[string]string{})
+	SetImageMappings(map[string]string{
+		"elasticsearch":        "mirror.example.com:5000/elastic/elasticsearch",
+		"elasticsearch:8.15.0": "mirror.example.com:5000/elastic/elasticsearch@sha256:0123456789abcdef",
+	})
+
+	testCases := []struct {
+		name    string
+		image   Image
+		version string
+		want    string
+	}{
+		{
+			name:    "image mapped to the version",
+			image:   ElasticsearchImage,
+			version: "8.15.0",
+			want:    "mirror.example.com:5000/elastic/elasticsearch@sha256:0123456789abcdef",
+		},
+		{
+			name:    "image mapped to a repository",
+			image:   ElasticsearchImage,
+			version: "8.14.0",
+			want:    "mirror.example.com:5000/elastic/elasticsearch:8.14.0",
+		},
+		{
+			name:    "image not mapped",
+			image:   KibanaImage,
+			version: "8.15.0",
+			want:    DefaultContainerRegistry + "/kibana/kibana:8.15.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ImageRepository(tc.image, version.MustParse(tc.version)))
+		})
+	}
+}
+
+func TestResolveImage(t *testing.T) {
+	defer func() {
+		SetImageMappings(map[string]string{})
+		SetRequireImageDigests(false)
+	}()
+	SetImageMappings(map[string]string{
+		"kibana:8.15.0": "mirror.example.com/elastic/kibana@sha256:0123456789abcdef",
+	})
+
+	testCases := []struct {
+		name          string
+		requireDigest bool
+		customImage   string
+		version       string
+		want          string
+		wantErr       bool
+	}{
+		{
+			name:    "default image",
+			version: "8.14.0",
+			want:    DefaultContainerRegistry + "/kibana/kibana:8.14.0",
+		},
+		{
+			name:        "custom image",
+			customImage: "my.registry/kibana:8.14.0",
+			version:     "8.14.0",
+			want:        "my.registry/kibana:8.14.0",
+		},
+		{
+			name:          "digests required and mapped image referenced by digest",
+			requireDigest: true,
+			version:       "8.15.0",
+			want:          "mirror.example.com/elastic/kibana@sha256:0123456789abcdef",
+		},
+		{
+			name:          "digests required and custom image referenced by digest",
+			requireDigest: true,
+			customImage:   "my.registry/kibana@sha256:fedcba9876543210",
+			version:       "8.14.0",
+			want:          "my.registry/kibana@sha256:fedcba9876543210",
+		},
+		{
+			name:          "digests required and default image referenced by tag",
+			requireDigest: true,
+			version:       "8.14.0",
+			wantErr:       true,
+		},
+		{
+			name:          "digests required and custom image referenced by tag",
+			requireDigest: true,
+			customImage:   "my.registry/kibana:8.15.0",
+			version:       "8.15.0",
+			wantErr:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			SetRequireImageDigests(tc.requireDigest)
+			got, err := ResolveImage(tc.customImage, KibanaImage, version.MustParse(tc.version))
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
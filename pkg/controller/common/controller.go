@@ -8,21 +8,90 @@ import (
 	"context"
 	"strconv"
 	"sync/atomic"
+	"time"
 
 	"go.elastic.co/apm/module/apmzap/v2"
 	"go.elastic.co/apm/v2"
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/sharding"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	logconf "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
 
+// defaults of the controller-runtime rate limiter, see workqueue.DefaultTypedControllerRateLimiter
+const (
+	defaultRateLimiterBaseDelay = 5 * time.Millisecond
+	defaultRateLimiterMaxDelay  = 1000 * time.Second
+	defaultRateLimiterQPS       = float64(10)
+	defaultRateLimiterBurst     = 100
+)
+
 // NewController creates a new controller with the given name, reconciler and parameters and registers it with the manager.
+// Controllers of remote clusters are named after the cluster, but use the options configured for the given name.
 func NewController(mgr manager.Manager, name string, r reconcile.Reconciler, p operator.Parameters) (controller.Controller, error) {
-	return controller.New(name, mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: p.MaxConcurrentReconciles})
+	controllerName := name
+	if p.ClusterName != "" {
+		// controller names must be unique within the operator
+		controllerName = p.ClusterName + "-" + name
+	}
+	if p.ShutdownGracePeriod > 0 {
+		r = &gracefulReconciler{Reconciler: r, gracePeriod: p.ShutdownGracePeriod}
+	}
+	r = &pausableReconciler{Reconciler: r, controller: name}
+	r = &metricsReconciler{Reconciler: r, controller: controllerName}
+	options := controller.Options{Reconciler: r, MaxConcurrentReconciles: p.MaxConcurrentReconciles}
+	if overrides, exists := p.ControllerOptions[name]; exists {
+		if overrides.MaxConcurrentReconciles > 0 {
+			options.MaxConcurrentReconciles = overrides.MaxConcurrentReconciles
+		}
+		if overrides.HasRateLimiterSettings() {
+			options.RateLimiter = newRateLimiter(overrides)
+		}
+	}
+	if p.QueueMonitor != nil {
+		options.NewQueue = p.QueueMonitor.NewQueue
+	}
+	if p.Sharding == nil {
+		return controller.New(controllerName, mgr, options)
+	}
+	// with sharding enabled all the replicas are active, each one reconciling the resources it owns
+	sharded := sharding.NewReconciler(p.Sharding, r, p.CacheSyncPeriod)
+	options.Reconciler = sharded
+	options.NeedLeaderElection = ptr.To(false)
+	c, err := controller.New(controllerName, mgr, options)
+	if err != nil {
+		return nil, err
+	}
+	return c, c.Watch(sharded.Source())
+}
+
+// newRateLimiter returns a rate limiter similar to the controller-runtime default one, with the given settings
+// taking precedence over the default ones.
+func newRateLimiter(o operator.ControllerOptions) workqueue.TypedRateLimiter[reconcile.Request] {
+	baseDelay, maxDelay, qps, burst := defaultRateLimiterBaseDelay, defaultRateLimiterMaxDelay, defaultRateLimiterQPS, defaultRateLimiterBurst
+	if o.RateLimiterBaseDelay > 0 {
+		baseDelay = o.RateLimiterBaseDelay
+	}
+	if o.RateLimiterMaxDelay > 0 {
+		maxDelay = o.RateLimiterMaxDelay
+	}
+	if o.RateLimiterQPS > 0 {
+		qps = o.RateLimiterQPS
+	}
+	if o.RateLimiterBurst > 0 {
+		burst = o.RateLimiterBurst
+	}
+	return workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](baseDelay, maxDelay),
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
 }
 
 // NewReconciliationContext increments iteration, creates an apm transaction and initiates the logger. Returns context
@@ -0,0 +1,32 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+)
+
+func Test_newRateLimiter(t *testing.T) {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "es"}}
+
+	limiter := newRateLimiter(operator.ControllerOptions{RateLimiterBaseDelay: time.Second, RateLimiterMaxDelay: 3 * time.Second})
+	assert.Equal(t, time.Second, limiter.When(request))
+	assert.Equal(t, 2*time.Second, limiter.When(request))
+	assert.Equal(t, 3*time.Second, limiter.When(request))
+	assert.Equal(t, 3*time.Second, limiter.When(request))
+	limiter.Forget(request)
+	assert.Equal(t, time.Second, limiter.When(request))
+
+	// defaults apply to the settings which are not set
+	limiter = newRateLimiter(operator.ControllerOptions{RateLimiterBurst: 1000})
+	assert.Equal(t, defaultRateLimiterBaseDelay, limiter.When(request))
+}
@@ -66,9 +66,7 @@ func Reconcile(
 			// compare hash of the DaemonSet at the time it was built
 			return hash.GetTemplateHashLabel(reconciled.Labels) != hash.GetTemplateHashLabel(expected.Labels)
 		},
-		UpdateReconciled: func() {
-			expected.DeepCopyInto(reconciled)
-		},
+		ServerSideApply: true,
 	})
 	return *reconciled, err
 }
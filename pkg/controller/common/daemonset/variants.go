@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package daemonset
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+// VariantLabelName is set to the name of the variant on the DaemonSets of the DaemonSet variants and on their Pods.
+const VariantLabelName = "common.k8s.elastic.co/daemonset-variant"
+
+// NewVariants returns the DaemonSets built from the given parameters: a single DaemonSet if there is no variant, or one
+// DaemonSet per variant otherwise. The DaemonSet of a variant is named after the variant, selects its own Pods, and
+// runs on the nodes selected by the variant.
+func NewVariants(params Params, variants []commonv1.DaemonSetVariant) []appsv1.DaemonSet {
+	if len(variants) == 0 {
+		return []appsv1.DaemonSet{New(params)}
+	}
+	result := make([]appsv1.DaemonSet, 0, len(variants))
+	for _, variant := range variants {
+		variantParams := params
+		variantParams.Name = params.Name + "-" + variant.Name
+		variantParams.Labels = maps.Merge(map[string]string{VariantLabelName: variant.Name}, params.Labels)
+		variantParams.Selectors = maps.Merge(map[string]string{VariantLabelName: variant.Name}, params.Selectors)
+
+		template := params.PodTemplate.DeepCopy()
+		template.Labels = maps.Merge(template.Labels, map[string]string{VariantLabelName: variant.Name})
+		if len(variant.NodeSelector) > 0 {
+			template.Spec.NodeSelector = maps.Merge(template.Spec.NodeSelector, variant.NodeSelector)
+		}
+		template.Spec.Tolerations = append(template.Spec.Tolerations, variant.Tolerations...)
+		variantParams.PodTemplate = *template
+
+		result = append(result, New(variantParams))
+	}
+	return result
+}
+
+// GarbageCollect deletes the DaemonSets controlled by the owner and matching the given labels which are not expected,
+// for example the DaemonSets of the variants which have been removed.
+func GarbageCollect(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	owner client.Object,
+	matchLabels map[string]string,
+	expected []appsv1.DaemonSet,
+) error {
+	expectedNames := make(map[string]struct{}, len(expected))
+	for _, ds := range expected {
+		expectedNames[ds.Name] = struct{}{}
+	}
+	var actual appsv1.DaemonSetList
+	if err := k8sClient.List(ctx, &actual, client.InNamespace(owner.GetNamespace()), client.MatchingLabels(matchLabels)); err != nil {
+		return err
+	}
+	for i := range actual.Items {
+		ds := &actual.Items[i]
+		if _, exists := expectedNames[ds.Name]; exists || !metav1.IsControlledBy(ds, owner) {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Deleting DaemonSet", "namespace", ds.Namespace, "daemonset_name", ds.Name)
+		if err := k8sClient.Delete(ctx, ds); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -10,12 +10,12 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
 )
 
 // Params to specify a Deployment specification.
@@ -51,11 +51,15 @@ func New(params Params) appsv1.Deployment {
 }
 
 // Reconcile creates or updates the given deployment for the specified owner.
+// If enforceReplicas is true, replicas changed directly on the deployment are restored from the expected deployment:
+// this must only be set for owners whose CRD has a scale subresource, autoscalers being expected to scale the owner
+// rather than the deployment. Otherwise only a change of the expected deployment triggers an update.
 func Reconcile(
 	ctx context.Context,
 	k8sClient k8s.Client,
 	expected appsv1.Deployment,
 	owner client.Object,
+	enforceReplicas bool,
 ) (appsv1.Deployment, error) {
 	// label the deployment with a hash of itself
 	expected = WithTemplateHash(expected)
@@ -69,20 +73,25 @@ func Reconcile(
 		Reconciled: reconciled,
 		NeedsUpdate: func() bool {
 			// compare hash of the deployment at the time it was built
-			return hash.GetTemplateHashLabel(reconciled.Labels) != hash.GetTemplateHashLabel(expected.Labels)
-		},
-		UpdateReconciled: func() {
-			// set expected annotations and labels, but don't remove existing ones
-			// that may have been defaulted or set by a user/admin on the existing resource
-			reconciled.Labels = maps.Merge(reconciled.Labels, expected.Labels)
-			reconciled.Annotations = maps.Merge(reconciled.Annotations, expected.Annotations)
-			// overwrite the spec but leave the status intact
-			reconciled.Spec = expected.Spec
+			if hash.GetTemplateHashLabel(reconciled.Labels) != hash.GetTemplateHashLabel(expected.Labels) {
+				return true
+			}
+			// the count of the owner is the source of truth for the replicas which may have been changed directly
+			// on the deployment
+			return enforceReplicas && !replicasEqual(reconciled.Spec.Replicas, expected.Spec.Replicas)
 		},
+		// only apply the labels, annotations and spec fields set by the operator, the ones that may have been
+		// defaulted or set by a user/admin or another controller on the existing resource are left untouched
+		ServerSideApply: true,
 	})
 	return *reconciled, err
 }
 
+// replicasEqual returns true if both replicas are equal, a nil value being the default of one replica.
+func replicasEqual(a, b *int32) bool {
+	return ptr.Deref(a, 1) == ptr.Deref(b, 1)
+}
+
 // WithTemplateHash returns a new deployment with a hash of its template to ease comparisons.
 func WithTemplateHash(d appsv1.Deployment) appsv1.Deployment {
 	dCopy := *d.DeepCopy()
@@ -66,7 +66,7 @@ func TestReconcile(t *testing.T) {
 	owner := esv1.Elasticsearch{} // can be any type
 
 	// should create a new deployment
-	reconciled, err := Reconcile(context.Background(), k8sClient, expected, &owner)
+	reconciled, err := Reconcile(context.Background(), k8sClient, expected, &owner, true)
 	require.NoError(t, err)
 	// reconciled should match expected spec, and have the hash label set
 	require.Equal(t, ptr.To[int32](2), reconciled.Spec.Replicas)
@@ -84,13 +84,13 @@ func TestReconcile(t *testing.T) {
 	require.NoError(t, k8sClient.Status().Update(context.Background(), &withStatusUpdate))
 
 	// reconciling the same should be a no-op
-	reconciledAgain, err := Reconcile(context.Background(), k8sClient, expected, &owner)
+	reconciledAgain, err := Reconcile(context.Background(), k8sClient, expected, &owner, true)
 	require.NoError(t, err)
 	comparison.RequireEqual(t, &withStatusUpdate, &reconciledAgain)
 
 	// update with a new spec
 	expected.Spec.Replicas = ptr.To[int32](3)
-	reconciled, err = Reconcile(context.Background(), k8sClient, expected, &owner)
+	reconciled, err = Reconcile(context.Background(), k8sClient, expected, &owner, true)
 	require.NoError(t, err)
 	// both returned and retrieved should match that new spec
 	require.Equal(t, 3, int(*reconciled.Spec.Replicas))
@@ -100,4 +100,24 @@ func TestReconcile(t *testing.T) {
 	err = k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&expected), &retrieved)
 	require.NoError(t, err)
 	comparison.RequireEqual(t, &reconciled, &retrieved)
+
+	// replicas changed directly on the deployment, for example by an autoscaler targeting it, should be restored
+	retrieved.Spec.Replicas = ptr.To[int32](5)
+	require.NoError(t, k8sClient.Update(context.Background(), &retrieved))
+	reconciled, err = Reconcile(context.Background(), k8sClient, expected, &owner, true)
+	require.NoError(t, err)
+	require.Equal(t, 3, int(*reconciled.Spec.Replicas))
+	err = k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&expected), &retrieved)
+	require.NoError(t, err)
+	require.Equal(t, 3, int(*retrieved.Spec.Replicas))
+
+	// replicas changed directly on the deployment are left untouched if the owner cannot be scaled
+	retrieved.Spec.Replicas = ptr.To[int32](5)
+	require.NoError(t, k8sClient.Update(context.Background(), &retrieved))
+	reconciled, err = Reconcile(context.Background(), k8sClient, expected, &owner, false)
+	require.NoError(t, err)
+	require.Equal(t, 5, int(*reconciled.Spec.Replicas))
+	err = k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&expected), &retrieved)
+	require.NoError(t, err)
+	require.Equal(t, 5, int(*retrieved.Spec.Replicas))
 }
@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Watchdog detects the resources whose reconciliation has had pending changes for longer than a threshold.
+// The tracking is kept in memory: it starts over when the operator restarts, which only delays the detection.
+type Watchdog struct {
+	mutex        sync.Mutex
+	threshold    time.Duration
+	pendingSince map[types.NamespacedName]time.Time
+	now          func() time.Time
+}
+
+// NewWatchdog returns a Watchdog reporting as stalled the resources with changes pending for longer than the given
+// threshold. Nothing is ever reported as stalled if the threshold is not positive.
+func NewWatchdog(threshold time.Duration) *Watchdog {
+	return &Watchdog{
+		threshold:    threshold,
+		pendingSince: map[types.NamespacedName]time.Time{},
+		now:          time.Now,
+	}
+}
+
+// Observe records whether the reconciliation of the given resource left changes pending. It returns the time since
+// which the changes have been pending, and true if this is longer than the threshold.
+func (w *Watchdog) Observe(resource types.NamespacedName, pending bool) (time.Time, bool) {
+	if w == nil || w.threshold <= 0 {
+		return time.Time{}, false
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !pending {
+		delete(w.pendingSince, resource)
+		return time.Time{}, false
+	}
+	now := w.now()
+	since, exists := w.pendingSince[resource]
+	if !exists {
+		w.pendingSince[resource] = now
+		return now, false
+	}
+	return since, now.Sub(since) > w.threshold
+}
+
+// Forget stops tracking the given resource, for example once it is deleted.
+func (w *Watchdog) Forget(resource types.NamespacedName) {
+	if w == nil {
+		return
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	delete(w.pendingSince, resource)
+}
@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestWatchdog_Observe(t *testing.T) {
+	resource := types.NamespacedName{Namespace: "ns", Name: "es"}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := NewWatchdog(10 * time.Minute)
+	w.now = func() time.Time { return now }
+
+	// first observation of pending changes
+	since, stalled := w.Observe(resource, true)
+	assert.Equal(t, now, since)
+	assert.False(t, stalled)
+	start := now
+
+	// still within the threshold
+	now = now.Add(10 * time.Minute)
+	since, stalled = w.Observe(resource, true)
+	assert.Equal(t, start, since)
+	assert.False(t, stalled)
+
+	// over the threshold
+	now = now.Add(time.Second)
+	since, stalled = w.Observe(resource, true)
+	assert.Equal(t, start, since)
+	assert.True(t, stalled)
+
+	// changes applied
+	_, stalled = w.Observe(resource, false)
+	assert.False(t, stalled)
+	since, stalled = w.Observe(resource, true)
+	assert.Equal(t, now, since)
+	assert.False(t, stalled)
+
+	// forgotten resources start over
+	now = now.Add(time.Hour)
+	w.Forget(resource)
+	since, stalled = w.Observe(resource, true)
+	assert.Equal(t, now, since)
+	assert.False(t, stalled)
+}
+
+func TestWatchdog_Observe_disabled(t *testing.T) {
+	resource := types.NamespacedName{Namespace: "ns", Name: "es"}
+	for _, w := range []*Watchdog{nil, NewWatchdog(0)} {
+		w.Observe(resource, true)
+		_, stalled := w.Observe(resource, true)
+		assert.False(t, stalled)
+		w.Forget(resource)
+	}
+}
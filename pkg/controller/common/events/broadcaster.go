@@ -0,0 +1,147 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package events
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+// dedupCacheSize is the maximum number of distinct recently emitted events remembered for deduplication.
+const dedupCacheSize = 4096
+
+// EmissionOptions configures how the operator emits Kubernetes events.
+type EmissionOptions struct {
+	// DedupWindow is the duration during which an event identical to a previously emitted one, for the same object,
+	// is dropped. Zero disables deduplication.
+	DedupWindow time.Duration
+	// RateLimitQPS is the rate at which the per-object token bucket refills. Zero keeps the client-go default.
+	RateLimitQPS float32
+	// RateLimitBurst is the size of the per-object token bucket. Zero keeps the client-go default.
+	RateLimitBurst int
+	// MinSeverity is the minimum event type to emit: Normal emits all events, Warning only emits warnings.
+	MinSeverity string
+}
+
+// IsDefault returns true if the options do not alter the default behaviour of the event recorders.
+func (o EmissionOptions) IsDefault() bool {
+	return o.DedupWindow == 0 && o.RateLimitQPS == 0 && o.RateLimitBurst == 0 && o.MinSeverity == corev1.EventTypeNormal
+}
+
+// ParseSeverity validates the given minimum event severity, case-insensitively.
+func ParseSeverity(severity string) (string, error) {
+	switch strings.ToLower(severity) {
+	case "", strings.ToLower(corev1.EventTypeNormal):
+		return corev1.EventTypeNormal, nil
+	case strings.ToLower(corev1.EventTypeWarning):
+		return corev1.EventTypeWarning, nil
+	default:
+		return "", fmt.Errorf("event severity can be one of %s or %s, but was %s", corev1.EventTypeNormal, corev1.EventTypeWarning, severity)
+	}
+}
+
+// NewBroadcaster returns an event broadcaster whose recorders apply the given rate limiting, deduplication
+// and severity options.
+func NewBroadcaster(opts EmissionOptions) record.EventBroadcaster {
+	return &broadcaster{
+		EventBroadcaster: record.NewBroadcaster(record.WithCorrelatorOptions(record.CorrelatorOptions{
+			QPS:       opts.RateLimitQPS,
+			BurstSize: opts.RateLimitBurst,
+		})),
+		opts: opts,
+	}
+}
+
+type broadcaster struct {
+	record.EventBroadcaster
+	opts EmissionOptions
+}
+
+// NewRecorder implements record.EventBroadcaster.
+func (b *broadcaster) NewRecorder(scheme *runtime.Scheme, source corev1.EventSource) record.EventRecorderLogger {
+	cache, _ := lru.New[string, time.Time](dedupCacheSize) // only errors on negative size
+	return &filteringRecorder{
+		EventRecorderLogger: b.EventBroadcaster.NewRecorder(scheme, source),
+		opts:                b.opts,
+		recent:              &recentEvents{cache: cache},
+		now:                 time.Now,
+	}
+}
+
+// recentEvents remembers when events were last emitted.
+type recentEvents struct {
+	mutex sync.Mutex
+	cache *lru.Cache[string, time.Time]
+}
+
+// filteringRecorder drops events below the minimum severity and events identical to one emitted recently.
+type filteringRecorder struct {
+	record.EventRecorderLogger
+	opts   EmissionOptions
+	recent *recentEvents
+	now    func() time.Time
+}
+
+// WithLogger implements record.EventRecorderLogger.
+func (r *filteringRecorder) WithLogger(logger klog.Logger) record.EventRecorderLogger {
+	return &filteringRecorder{
+		EventRecorderLogger: r.EventRecorderLogger.WithLogger(logger),
+		opts:                r.opts,
+		recent:              r.recent,
+		now:                 r.now,
+	}
+}
+
+// shouldEmit returns true if the given event passes the severity and deduplication filters.
+func (r *filteringRecorder) shouldEmit(object runtime.Object, eventtype, reason, message string) bool {
+	if r.opts.MinSeverity == corev1.EventTypeWarning && eventtype != corev1.EventTypeWarning {
+		return false
+	}
+	if r.opts.DedupWindow <= 0 {
+		return true
+	}
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		// let the underlying recorder deal with the invalid object
+		return true
+	}
+	key := strings.Join([]string{string(accessor.GetUID()), accessor.GetNamespace(), accessor.GetName(), eventtype, reason, message}, "/")
+
+	r.recent.mutex.Lock()
+	defer r.recent.mutex.Unlock()
+	now := r.now()
+	if last, exists := r.recent.cache.Get(key); exists && now.Sub(last) < r.opts.DedupWindow {
+		return false
+	}
+	r.recent.cache.Add(key, now)
+	return true
+}
+
+func (r *filteringRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if r.shouldEmit(object, eventtype, reason, message) {
+		r.EventRecorderLogger.Event(object, eventtype, reason, message)
+	}
+}
+
+func (r *filteringRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.shouldEmit(object, eventtype, reason, fmt.Sprintf(messageFmt, args...)) {
+		r.EventRecorderLogger.Eventf(object, eventtype, reason, messageFmt, args...)
+	}
+}
+
+func (r *filteringRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.shouldEmit(object, eventtype, reason, fmt.Sprintf(messageFmt, args...)) {
+		r.EventRecorderLogger.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+func TestParseSeverity(t *testing.T) {
+	for input, expected := range map[string]string{"": "Normal", "normal": "Normal", "Warning": "Warning", "WARNING": "Warning"} {
+		actual, err := ParseSeverity(input)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+	_, err := ParseSeverity("error")
+	require.Error(t, err)
+}
+
+func drain(r *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case e := <-r.Events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func newTestRecorder(opts EmissionOptions, now *time.Time) (*filteringRecorder, *record.FakeRecorder) {
+	fake := record.NewFakeRecorder(100)
+	cache, _ := lru.New[string, time.Time](dedupCacheSize)
+	return &filteringRecorder{
+		EventRecorderLogger: fake,
+		opts:                opts,
+		recent:              &recentEvents{cache: cache},
+		now:                 func() time.Time { return *now },
+	}, fake
+}
+
+func TestFilteringRecorder(t *testing.T) {
+	es1 := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es1", UID: "1"}}
+	es2 := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es2", UID: "2"}}
+
+	t.Run("all events are emitted with default options", func(t *testing.T) {
+		now := time.Now()
+		r, fake := newTestRecorder(EmissionOptions{MinSeverity: corev1.EventTypeNormal}, &now)
+		r.Event(es1, corev1.EventTypeNormal, EventReasonUpgraded, "upgraded")
+		r.Event(es1, corev1.EventTypeNormal, EventReasonUpgraded, "upgraded")
+		r.Eventf(es1, corev1.EventTypeWarning, EventReasonUnhealthy, "health %s", "red")
+		assert.Len(t, drain(fake), 3)
+	})
+	t.Run("normal events are dropped with a minimum severity of warning", func(t *testing.T) {
+		now := time.Now()
+		r, fake := newTestRecorder(EmissionOptions{MinSeverity: corev1.EventTypeWarning}, &now)
+		r.Event(es1, corev1.EventTypeNormal, EventReasonUpgraded, "upgraded")
+		r.AnnotatedEventf(es1, nil, corev1.EventTypeNormal, EventReasonUpgraded, "upgraded")
+		r.Eventf(es1, corev1.EventTypeWarning, EventReasonUnhealthy, "health %s", "red")
+		assert.Equal(t, []string{"Warning Unhealthy health red"}, drain(fake))
+	})
+	t.Run("identical events are deduplicated per object during the window", func(t *testing.T) {
+		now := time.Now()
+		r, fake := newTestRecorder(EmissionOptions{MinSeverity: corev1.EventTypeNormal, DedupWindow: time.Minute}, &now)
+		r.Eventf(es1, corev1.EventTypeWarning, EventReasonUnhealthy, "health %s", "red")
+		r.Eventf(es1, corev1.EventTypeWarning, EventReasonUnhealthy, "health %s", "red")
+		r.Eventf(es1, corev1.EventTypeWarning, EventReasonUnhealthy, "health %s", "yellow")
+		r.Eventf(es2, corev1.EventTypeWarning, EventReasonUnhealthy, "health %s", "red")
+		assert.Equal(t, []string{"Warning Unhealthy health red", "Warning Unhealthy health yellow", "Warning Unhealthy health red"}, drain(fake))
+
+		// recorders derived with a different logger share the deduplication state
+		r.WithLogger(klog.Background()).Eventf(es1, corev1.EventTypeWarning, EventReasonUnhealthy, "health %s", "red")
+		assert.Empty(t, drain(fake))
+
+		now = now.Add(2 * time.Minute)
+		r.Eventf(es1, corev1.EventTypeWarning, EventReasonUnhealthy, "health %s", "red")
+		assert.Equal(t, []string{"Warning Unhealthy health red"}, drain(fake))
+	})
+}
+
+func TestEmissionOptions_IsDefault(t *testing.T) {
+	assert.True(t, EmissionOptions{MinSeverity: corev1.EventTypeNormal}.IsDefault())
+	assert.False(t, EmissionOptions{MinSeverity: corev1.EventTypeWarning}.IsDefault())
+	assert.False(t, EmissionOptions{MinSeverity: corev1.EventTypeNormal, RateLimitBurst: 5}.IsDefault())
+}
@@ -8,6 +8,11 @@ package events
 const (
 	// EventReasonDeprecated describes events that were due to a deprecated resource being submitted by the user.
 	EventReasonDeprecated = "Deprecated"
+	// EventReasonDataMigration describes events where data is migrated away from Elasticsearch nodes before they are
+	// removed from the cluster.
+	EventReasonDataMigration = "DataMigration"
+	// EventReasonDiagnostics describes events where diagnostics were collected on request of the user.
+	EventReasonDiagnostics = "Diagnostics"
 	// EventReasonDelayed describes events where a requested change was delayed e.g. to prevent data loss.
 	EventReasonDelayed = "Delayed"
 	// EventReasonInvalidLicense describes events where a user configured an invalid license for the operator.
@@ -56,6 +56,15 @@ func (e *ExpectedPodDeletions) PendingPodDeletions() ([]string, error) {
 	return pendingPodDeletions, nil
 }
 
+// ExpectedDeletions returns a copy of the registered Pods deletions, without checking them against the cache.
+func (e *ExpectedPodDeletions) ExpectedDeletions() map[types.NamespacedName]types.UID {
+	deletions := make(map[types.NamespacedName]types.UID, len(e.podDeletions))
+	for pod, uid := range e.podDeletions {
+		deletions[pod] = uid
+	}
+	return deletions
+}
+
 // podDeleted returns true if the pod with the given UID does not exist anymore.
 func podDeleted(client k8s.Client, pod types.NamespacedName, uid types.UID) (bool, error) {
 	var podInCache corev1.Pod
@@ -79,7 +79,8 @@ technically less efficient.
 
 */
 
-// Expectations stores expectations for a single cluster. It is not thread-safe.
+// Expectations stores expectations for a single cluster. Only StatefulSet generations can be registered concurrently,
+// the rest of it is not thread-safe.
 type Expectations struct {
 	*ExpectedStatefulSetUpdates
 	*ExpectedPodDeletions
@@ -6,6 +6,7 @@ package expectations
 
 import (
 	"context"
+	"sync"
 
 	appsv1 "k8s.io/api/apps/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -17,8 +18,10 @@ import (
 // ExpectedStatefulSetUpdates stores StatefulSets generations that are expected in the cache,
 // following a StatefulSet update. It allows making sure we're not working with an
 // out-of-date version of the StatefulSet resource we previously updated.
+// It is safe for concurrent use, so that several StatefulSets can be reconciled in parallel.
 type ExpectedStatefulSetUpdates struct {
 	client      k8s.Client
+	lock        sync.Mutex
 	generations map[types.NamespacedName]ResourceGeneration // per StatefulSet
 }
 
@@ -41,6 +44,8 @@ func NewExpectedStatefulSetUpdates(client k8s.Client) *ExpectedStatefulSetUpdate
 // We expect to see its generation (at least) in PendingGenerations().
 func (e *ExpectedStatefulSetUpdates) ExpectGeneration(statefulSet appsv1.StatefulSet) {
 	resource := types.NamespacedName{Namespace: statefulSet.Namespace, Name: statefulSet.Name}
+	e.lock.Lock()
+	defer e.lock.Unlock()
 	e.generations[resource] = ResourceGeneration{
 		UID:        statefulSet.UID,
 		Generation: statefulSet.Generation,
@@ -51,6 +56,8 @@ func (e *ExpectedStatefulSetUpdates) ExpectGeneration(statefulSet appsv1.Statefu
 // and returns the list of StatefulSets for which the generation has not been updated yet.
 // Expectations are cleared once they are matched.
 func (e *ExpectedStatefulSetUpdates) PendingGenerations() ([]string, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
 	var pendingStatefulSet []string
 	for statefulSet, expectedGen := range e.generations {
 		satisfied, err := e.generationSatisfied(statefulSet, expectedGen)
@@ -67,6 +74,17 @@ func (e *ExpectedStatefulSetUpdates) PendingGenerations() ([]string, error) {
 	return pendingStatefulSet, nil
 }
 
+// ExpectedGenerations returns a copy of the registered StatefulSets generations, without checking them against the cache.
+func (e *ExpectedStatefulSetUpdates) ExpectedGenerations() map[types.NamespacedName]ResourceGeneration {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	generations := make(map[types.NamespacedName]ResourceGeneration, len(e.generations))
+	for statefulSet, generation := range e.generations {
+		generations[statefulSet] = generation
+	}
+	return generations
+}
+
 // generationSatisfied returns true if the generation of the cached StatefulSet matches what is expected.
 func (e *ExpectedStatefulSetUpdates) generationSatisfied(statefulSet types.NamespacedName, expected ResourceGeneration) (bool, error) {
 	var ssetInCache appsv1.StatefulSet
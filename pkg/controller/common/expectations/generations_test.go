@@ -5,6 +5,8 @@
 package expectations
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -117,3 +119,18 @@ func TestExpectedStatefulSetUpdates_GenerationsSatisfied(t *testing.T) {
 		})
 	}
 }
+
+func TestExpectedStatefulSetUpdates_ExpectGenerationConcurrently(t *testing.T) {
+	e := NewExpectedStatefulSetUpdates(k8s.NewFakeClient())
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.ExpectGeneration(newStatefulSet(fmt.Sprintf("sset%d", i), uuid.NewUUID(), 1))
+			_ = e.ExpectedGenerations()
+		}()
+	}
+	wg.Wait()
+	require.Len(t, e.ExpectedGenerations(), 20)
+}
@@ -0,0 +1,39 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package externaldns
+
+import (
+	"strings"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+// HostnameAnnotation lists the DNS names external-dns publishes for a Service or an Ingress.
+const HostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+
+// ServiceAnnotations returns the annotations of the HTTP service: the annotations of the service template, and the
+// public hostnames unless they are published through the Ingress.
+func ServiceAnnotations(http commonv1.HTTPConfig) map[string]string {
+	annotations := http.Service.ObjectMeta.Annotations
+	if len(http.Hostnames) == 0 || http.Ingress != nil {
+		return annotations
+	}
+	// do not mutate the annotations of the template
+	return maps.MergePreservingExistingKeys(maps.Merge(nil, annotations), hostnameAnnotation(http.Hostnames))
+}
+
+// IngressAnnotations returns the annotations publishing the public hostnames through the Ingress, to merge with the
+// annotations of the Ingress template.
+func IngressAnnotations(http commonv1.HTTPConfig) map[string]string {
+	if len(http.Hostnames) == 0 {
+		return nil
+	}
+	return hostnameAnnotation(http.Hostnames)
+}
+
+func hostnameAnnotation(hostnames []string) map[string]string {
+	return map[string]string{HostnameAnnotation: strings.Join(hostnames, ",")}
+}
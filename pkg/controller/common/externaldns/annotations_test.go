@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package externaldns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+func TestServiceAnnotations(t *testing.T) {
+	serviceTemplate := commonv1.ServiceTemplate{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"a": "b"}}}
+	tests := []struct {
+		name string
+		http commonv1.HTTPConfig
+		want map[string]string
+	}{
+		{
+			name: "no hostnames",
+			http: commonv1.HTTPConfig{Service: serviceTemplate},
+			want: map[string]string{"a": "b"},
+		},
+		{
+			name: "hostnames published through the service",
+			http: commonv1.HTTPConfig{Service: serviceTemplate, Hostnames: []string{"kb.example.com", "kibana.example.com"}},
+			want: map[string]string{"a": "b", HostnameAnnotation: "kb.example.com,kibana.example.com"},
+		},
+		{
+			name: "hostnames published through the Ingress",
+			http: commonv1.HTTPConfig{Service: serviceTemplate, Hostnames: []string{"kb.example.com"}, Ingress: &commonv1.IngressTemplate{}},
+			want: map[string]string{"a": "b"},
+		},
+		{
+			name: "hostname annotation set by the user",
+			http: commonv1.HTTPConfig{
+				Service:   commonv1.ServiceTemplate{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{HostnameAnnotation: "kb.example.com"}}},
+				Hostnames: []string{"kibana.example.com"},
+			},
+			want: map[string]string{HostnameAnnotation: "kb.example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ServiceAnnotations(tt.http))
+		})
+	}
+	// the annotations of the template are not modified
+	require.Equal(t, map[string]string{"a": "b"}, serviceTemplate.ObjectMeta.Annotations)
+}
+
+func TestIngressAnnotations(t *testing.T) {
+	require.Nil(t, IngressAnnotations(commonv1.HTTPConfig{}))
+	require.Equal(t,
+		map[string]string{HostnameAnnotation: "kb.example.com,kibana.example.com"},
+		IngressAnnotations(commonv1.HTTPConfig{Hostnames: []string{"kb.example.com", "kibana.example.com"}}),
+	)
+}
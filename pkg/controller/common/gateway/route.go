@@ -0,0 +1,217 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/ingress"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+// Reconcile creates or updates the Gateway route specified in the HTTP configuration of the owner: an HTTPRoute with
+// the Terminate TLS mode, or a TLSRoute with the Passthrough TLS mode. The route previously created for the owner is
+// deleted if it is not specified anymore.
+func Reconcile(ctx context.Context, c k8s.Client, params ingress.Params) error {
+	var mode commonv1.GatewayTLSMode
+	if params.HTTP.GatewayRoute != nil {
+		mode = params.HTTP.GatewayRoute.TLSModeOrDefault()
+	}
+
+	if mode == commonv1.GatewayTLSTerminateMode {
+		if err := reconcileHTTPRoute(ctx, c, params); err != nil {
+			return err
+		}
+	} else if err := deleteIfExists(ctx, c, params, &gatewayv1.HTTPRoute{}); err != nil {
+		return err
+	}
+
+	if mode == commonv1.GatewayTLSPassthroughMode {
+		return reconcileTLSRoute(ctx, c, params)
+	}
+	return deleteIfExists(ctx, c, params, &gatewayv1alpha2.TLSRoute{})
+}
+
+// NewHTTPRoute returns the HTTPRoute routing the HTTP requests to the HTTP service.
+func NewHTTPRoute(params ingress.Params) (gatewayv1.HTTPRoute, error) {
+	backendRef, err := newBackendRef(params.Service)
+	if err != nil {
+		return gatewayv1.HTTPRoute{}, err
+	}
+	template := params.HTTP.GatewayRoute
+	route := gatewayv1.HTTPRoute{ObjectMeta: newObjectMeta(template, params)}
+	route.Spec = gatewayv1.HTTPRouteSpec{
+		CommonRouteSpec: newCommonRouteSpec(template),
+		Hostnames:       newHostnames(params.HTTP),
+		Rules: []gatewayv1.HTTPRouteRule{{
+			Matches: []gatewayv1.HTTPRouteMatch{{
+				Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")},
+			}},
+			BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: backendRef}},
+		}},
+	}
+	return route, nil
+}
+
+// NewTLSRoute returns the TLSRoute forwarding the TLS connections to the HTTP service.
+func NewTLSRoute(params ingress.Params) (gatewayv1alpha2.TLSRoute, error) {
+	backendRef, err := newBackendRef(params.Service)
+	if err != nil {
+		return gatewayv1alpha2.TLSRoute{}, err
+	}
+	template := params.HTTP.GatewayRoute
+	route := gatewayv1alpha2.TLSRoute{ObjectMeta: newObjectMeta(template, params)}
+	route.Spec = gatewayv1alpha2.TLSRouteSpec{
+		CommonRouteSpec: newCommonRouteSpec(template),
+		Hostnames:       newHostnames(params.HTTP),
+		Rules:           []gatewayv1alpha2.TLSRouteRule{{BackendRefs: []gatewayv1.BackendRef{backendRef}}},
+	}
+	return route, nil
+}
+
+func newObjectMeta(template *commonv1.GatewayRouteTemplate, params ingress.Params) metav1.ObjectMeta {
+	meta := *template.ObjectMeta.DeepCopy()
+	meta.Name = params.Service.Name
+	meta.Namespace = params.Service.Namespace
+	meta.Labels = maps.MergePreservingExistingKeys(meta.Labels, params.Labels)
+	return meta
+}
+
+// newCommonRouteSpec returns the references to the Gateways, with the fields defaulted by the API server set to
+// compare them with the existing routes.
+func newCommonRouteSpec(template *commonv1.GatewayRouteTemplate) gatewayv1.CommonRouteSpec {
+	parentRefs := make([]gatewayv1.ParentReference, 0, len(template.ParentRefs))
+	for _, ref := range template.ParentRefs {
+		parentRef := gatewayv1.ParentReference{
+			Group: ptr.To(gatewayv1.Group(gatewayv1.GroupName)),
+			Kind:  ptr.To(gatewayv1.Kind("Gateway")),
+			Name:  gatewayv1.ObjectName(ref.Name),
+		}
+		if ref.Namespace != "" {
+			parentRef.Namespace = ptr.To(gatewayv1.Namespace(ref.Namespace))
+		}
+		if ref.SectionName != "" {
+			parentRef.SectionName = ptr.To(gatewayv1.SectionName(ref.SectionName))
+		}
+		parentRefs = append(parentRefs, parentRef)
+	}
+	return gatewayv1.CommonRouteSpec{ParentRefs: parentRefs}
+}
+
+func newHostnames(http commonv1.HTTPConfig) []gatewayv1.Hostname {
+	var hostnames []gatewayv1.Hostname
+	for _, hostname := range http.GatewayRouteHostnames() {
+		hostnames = append(hostnames, gatewayv1.Hostname(hostname))
+	}
+	return hostnames
+}
+
+func newBackendRef(svc corev1.Service) (gatewayv1.BackendRef, error) {
+	if len(svc.Spec.Ports) == 0 {
+		return gatewayv1.BackendRef{}, fmt.Errorf("service %s has no port", svc.Name)
+	}
+	return gatewayv1.BackendRef{
+		BackendObjectReference: gatewayv1.BackendObjectReference{
+			Group: ptr.To(gatewayv1.Group("")),
+			Kind:  ptr.To(gatewayv1.Kind("Service")),
+			Name:  gatewayv1.ObjectName(svc.Name),
+			Port:  ptr.To(gatewayv1.PortNumber(svc.Spec.Ports[0].Port)),
+		},
+		Weight: ptr.To[int32](1),
+	}, nil
+}
+
+func reconcileHTTPRoute(ctx context.Context, c k8s.Client, params ingress.Params) error {
+	expected, err := NewHTTPRoute(params)
+	if err != nil {
+		return err
+	}
+	reconciled := &gatewayv1.HTTPRoute{}
+	return reconcileRoute(ctx, c, params.Owner, &expected, reconciled, func() bool {
+		return !reflect.DeepEqual(expected.Spec, reconciled.Spec)
+	}, func() {
+		reconciled.Spec = expected.Spec
+	})
+}
+
+func reconcileTLSRoute(ctx context.Context, c k8s.Client, params ingress.Params) error {
+	expected, err := NewTLSRoute(params)
+	if err != nil {
+		return err
+	}
+	reconciled := &gatewayv1alpha2.TLSRoute{}
+	return reconcileRoute(ctx, c, params.Owner, &expected, reconciled, func() bool {
+		return !reflect.DeepEqual(expected.Spec, reconciled.Spec)
+	}, func() {
+		reconciled.Spec = expected.Spec
+	})
+}
+
+func reconcileRoute(
+	ctx context.Context,
+	c k8s.Client,
+	owner client.Object,
+	expected, reconciled client.Object,
+	specNeedsUpdate func() bool,
+	updateSpec func(),
+) error {
+	err := reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Owner:      owner,
+		Expected:   expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return specNeedsUpdate() ||
+				!maps.IsSubset(expected.GetLabels(), reconciled.GetLabels()) ||
+				!maps.IsSubset(expected.GetAnnotations(), reconciled.GetAnnotations())
+		},
+		UpdateReconciled: func() {
+			// don't remove the annotations set by the Gateway controller
+			reconciled.SetLabels(maps.Merge(reconciled.GetLabels(), expected.GetLabels()))
+			reconciled.SetAnnotations(maps.Merge(reconciled.GetAnnotations(), expected.GetAnnotations()))
+			updateSpec()
+		},
+	})
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("the Gateway API CRDs must be installed to create the route %s: %w", expected.GetName(), err)
+	}
+	return err
+}
+
+func deleteIfExists(ctx context.Context, c k8s.Client, params ingress.Params, route client.Object) error {
+	err := c.Get(ctx, types.NamespacedName{Namespace: params.Service.Namespace, Name: params.Service.Name}, route)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		// no route, or the Gateway API CRDs are not installed
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !k8s.HasOwner(route, params.Owner) {
+		// not created by the operator
+		return nil
+	}
+	err = c.Delete(ctx, route, &client.DeleteOptions{Preconditions: &metav1.Preconditions{UID: ptr.To(route.GetUID())}})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,180 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/ingress"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func testParams(template *commonv1.GatewayRouteTemplate) ingress.Params {
+	kb := kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb", UID: "kb-uid"}}
+	return ingress.Params{
+		Owner: &kb,
+		HTTP:  commonv1.HTTPConfig{GatewayRoute: template},
+		Service: corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb-http"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "https", Port: 5601}}},
+		},
+		Labels: map[string]string{"kibana.k8s.elastic.co/name": "kb"},
+		Namer:  kbv1.KBNamer,
+	}
+}
+
+var (
+	testBackendRef = gatewayv1.BackendRef{
+		BackendObjectReference: gatewayv1.BackendObjectReference{
+			Group: ptr.To(gatewayv1.Group("")),
+			Kind:  ptr.To(gatewayv1.Kind("Service")),
+			Name:  "kb-kb-http",
+			Port:  ptr.To(gatewayv1.PortNumber(5601)),
+		},
+		Weight: ptr.To[int32](1),
+	}
+	testParentRefs = []gatewayv1.ParentReference{
+		{
+			Group: ptr.To(gatewayv1.Group("gateway.networking.k8s.io")),
+			Kind:  ptr.To(gatewayv1.Kind("Gateway")),
+			Name:  "gateway",
+		},
+		{
+			Group:       ptr.To(gatewayv1.Group("gateway.networking.k8s.io")),
+			Kind:        ptr.To(gatewayv1.Kind("Gateway")),
+			Name:        "shared-gateway",
+			Namespace:   ptr.To(gatewayv1.Namespace("infra")),
+			SectionName: ptr.To(gatewayv1.SectionName("https")),
+		},
+	}
+	testTemplate = commonv1.GatewayRouteTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{"team": "observability"},
+			Annotations: map[string]string{"a": "b"},
+		},
+		ParentRefs: []commonv1.GatewayParentRef{
+			{Name: "gateway"},
+			{Name: "shared-gateway", Namespace: "infra", SectionName: "https"},
+		},
+		Hostnames: []string{"kibana.example.com"},
+	}
+	testObjectMeta = metav1.ObjectMeta{
+		Namespace:   "ns",
+		Name:        "kb-kb-http",
+		Labels:      map[string]string{"kibana.k8s.elastic.co/name": "kb", "team": "observability"},
+		Annotations: map[string]string{"a": "b"},
+	}
+)
+
+func TestNewHTTPRoute(t *testing.T) {
+	got, err := NewHTTPRoute(testParams(&testTemplate))
+	require.NoError(t, err)
+	require.Equal(t, gatewayv1.HTTPRoute{
+		ObjectMeta: testObjectMeta,
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: testParentRefs},
+			Hostnames:       []gatewayv1.Hostname{"kibana.example.com"},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				Matches: []gatewayv1.HTTPRouteMatch{{
+					Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")},
+				}},
+				BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: testBackendRef}},
+			}},
+		},
+	}, got)
+
+	params := testParams(&testTemplate)
+	params.Service.Spec.Ports = nil
+	_, err = NewHTTPRoute(params)
+	require.ErrorContains(t, err, "service kb-kb-http has no port")
+}
+
+func TestNewTLSRoute(t *testing.T) {
+	template := *testTemplate.DeepCopy()
+	template.TLSMode = commonv1.GatewayTLSPassthroughMode
+	got, err := NewTLSRoute(testParams(&template))
+	require.NoError(t, err)
+	require.Equal(t, gatewayv1alpha2.TLSRoute{
+		ObjectMeta: testObjectMeta,
+		Spec: gatewayv1alpha2.TLSRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: testParentRefs},
+			Hostnames:       []gatewayv1.Hostname{"kibana.example.com"},
+			Rules:           []gatewayv1alpha2.TLSRouteRule{{BackendRefs: []gatewayv1.BackendRef{testBackendRef}}},
+		},
+	}, got)
+}
+
+func TestNewTLSRoute_PublicHostnames(t *testing.T) {
+	params := testParams(&commonv1.GatewayRouteTemplate{
+		ParentRefs: []commonv1.GatewayParentRef{{Name: "gateway"}},
+		TLSMode:    commonv1.GatewayTLSPassthroughMode,
+	})
+	params.HTTP.Hostnames = []string{"kibana.example.com"}
+	got, err := NewTLSRoute(params)
+	require.NoError(t, err)
+	require.Equal(t, []gatewayv1.Hostname{"kibana.example.com"}, got.Spec.Hostnames)
+}
+
+func TestReconcile(t *testing.T) {
+	scheme.SetupScheme()
+	ctx := context.Background()
+	c := k8s.NewFakeClient()
+	key := types.NamespacedName{Namespace: "ns", Name: "kb-kb-http"}
+
+	// create the HTTPRoute
+	params := testParams(&commonv1.GatewayRouteTemplate{ParentRefs: []commonv1.GatewayParentRef{{Name: "gateway"}}})
+	require.NoError(t, Reconcile(ctx, c, params))
+	var httpRoute gatewayv1.HTTPRoute
+	require.NoError(t, c.Get(ctx, key, &httpRoute))
+	require.True(t, k8s.HasOwner(&httpRoute, params.Owner))
+
+	// the annotations of the Gateway controller are kept, the spec follows the template
+	httpRoute.Annotations = map[string]string{"gateway.example.com/status": "ok"}
+	require.NoError(t, c.Update(ctx, &httpRoute))
+	params.HTTP.GatewayRoute.Hostnames = []string{"kibana.example.com"}
+	require.NoError(t, Reconcile(ctx, c, params))
+	require.NoError(t, c.Get(ctx, key, &httpRoute))
+	require.Equal(t, map[string]string{"gateway.example.com/status": "ok"}, httpRoute.Annotations)
+	require.Equal(t, []gatewayv1.Hostname{"kibana.example.com"}, httpRoute.Spec.Hostnames)
+
+	// switching to the Passthrough mode replaces the HTTPRoute with a TLSRoute
+	params.HTTP.GatewayRoute.TLSMode = commonv1.GatewayTLSPassthroughMode
+	require.NoError(t, Reconcile(ctx, c, params))
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, key, &httpRoute)))
+	var tlsRoute gatewayv1alpha2.TLSRoute
+	require.NoError(t, c.Get(ctx, key, &tlsRoute))
+	require.True(t, k8s.HasOwner(&tlsRoute, params.Owner))
+
+	// the route is deleted once removed from the specification
+	params.HTTP.GatewayRoute = nil
+	require.NoError(t, Reconcile(ctx, c, params))
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, key, &tlsRoute)))
+	// deleting again is a no-op
+	require.NoError(t, Reconcile(ctx, c, params))
+}
+
+func TestReconcile_RouteNotOwned(t *testing.T) {
+	scheme.SetupScheme()
+	ctx := context.Background()
+	userRoute := gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb-http"}}
+	c := k8s.NewFakeClient(&userRoute)
+
+	// a route not created by the operator is not deleted
+	require.NoError(t, Reconcile(ctx, c, testParams(nil)))
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(&userRoute), &gatewayv1.HTTPRoute{}))
+}
@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package gateway
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+var log = ulog.Log.WithName("gateway")
+
+// WatchRoutes watches the Gateway routes owned by the given type of owner. Routes whose CRD is not installed when the
+// operator starts are not watched.
+func WatchRoutes(mgr manager.Manager, c controller.Controller, owner client.Object) error {
+	for _, route := range []client.Object{&gatewayv1.HTTPRoute{}, &gatewayv1alpha2.TLSRoute{}} {
+		gvk, err := apiutil.GVKForObject(route, mgr.GetScheme())
+		if err != nil {
+			return err
+		}
+		if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			if meta.IsNoMatchError(err) {
+				log.V(1).Info("Gateway API CRD not installed, skipping watch", "kind", gvk.Kind, "version", gvk.Version)
+				continue
+			}
+			return err
+		}
+		if err := c.Watch(source.Kind(mgr.GetCache(), route, handler.EnqueueRequestForOwner(
+			mgr.GetScheme(), mgr.GetRESTMapper(), owner, handler.OnlyControllerOwner(),
+		))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -24,6 +24,14 @@ import (
 // match Kubernetes internal service name, but only the user-facing public endpoint
 // - set APM spans with each request
 func Client(dialer net.Dialer, caCerts []*x509.Certificate, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: Transport(dialer, caCerts),
+		Timeout:   timeout,
+	}
+}
+
+// Transport returns the http.Transport used by Client, which can be shared by several clients to reuse connections.
+func Transport(dialer net.Dialer, caCerts []*x509.Certificate) *http.Transport {
 	transportConfig := http.Transport{
 		TLSClientConfig: &tls.Config{
 			MinVersion: tls.VersionTLS12, // this is the default as of Go 1.18 we are just restating this here for clarity.
@@ -64,10 +72,7 @@ func Client(dialer net.Dialer, caCerts []*x509.Certificate, timeout time.Duratio
 		transportConfig.DialContext = dialer.DialContext
 	}
 
-	return &http.Client{
-		Transport: &transportConfig,
-		Timeout:   timeout,
-	}
+	return &transportConfig
 }
 
 // APIError to represent non-200 HTTP responses as Go errors.
@@ -0,0 +1,154 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/externaldns"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+// Params to specify the Ingress or the Gateway route exposing the HTTP service of a resource.
+type Params struct {
+	// Owner of the Ingress, for ex. Elasticsearch or Kibana.
+	Owner client.Object
+	// HTTP is the HTTP configuration of the owner, holding the Ingress and Gateway route templates.
+	HTTP commonv1.HTTPConfig
+	// Service is the reconciled HTTP service exposed by the Ingress.
+	Service corev1.Service
+	// Labels to set on the Ingress.
+	Labels map[string]string
+	// Namer of the owner, to name the Secret of the HTTP certificate.
+	Namer name.Namer
+}
+
+// New returns the Ingress specified by the Ingress template of the HTTP configuration, routing to the HTTP service.
+func New(params Params) (networkingv1.Ingress, error) {
+	template := params.HTTP.Ingress
+	if template == nil {
+		return networkingv1.Ingress{}, fmt.Errorf("no Ingress specified for %s", params.Service.Name)
+	}
+	if len(params.Service.Spec.Ports) == 0 {
+		return networkingv1.Ingress{}, fmt.Errorf("service %s has no port", params.Service.Name)
+	}
+
+	ingress := networkingv1.Ingress{
+		ObjectMeta: *template.ObjectMeta.DeepCopy(),
+	}
+	ingress.Name = params.Service.Name
+	ingress.Namespace = params.Service.Namespace
+	ingress.Labels = maps.MergePreservingExistingKeys(ingress.Labels, params.Labels)
+	ingress.Annotations = maps.MergePreservingExistingKeys(ingress.Annotations, externaldns.IngressAnnotations(params.HTTP))
+
+	pathType := networkingv1.PathTypePrefix
+	hosts := params.HTTP.IngressHosts()
+	rules := make([]networkingv1.IngressRule, 0, len(hosts))
+	for _, host := range hosts {
+		rules = append(rules, networkingv1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+				Paths: []networkingv1.HTTPIngressPath{{
+					Path:     template.PathOrDefault(),
+					PathType: &pathType,
+					Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+						Name: params.Service.Name,
+						Port: networkingv1.ServiceBackendPort{Number: params.Service.Spec.Ports[0].Port},
+					}},
+				}},
+			}},
+		})
+	}
+	ingress.Spec = networkingv1.IngressSpec{
+		IngressClassName: template.ClassName,
+		Rules:            rules,
+	}
+	if template.TLS != nil {
+		secretName := template.TLS.SecretName
+		if secretName == "" {
+			// present the HTTP certificate, whose internal Secret holds both the certificate and the private key
+			secretName = certificates.InternalCertsSecretName(params.Namer, params.Owner.GetName())
+		}
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: hosts, SecretName: secretName}}
+	}
+	return ingress, nil
+}
+
+// Reconcile creates or updates the Ingress specified in the HTTP configuration of the owner, or deletes the Ingress
+// previously created for the owner if none is specified anymore.
+func Reconcile(ctx context.Context, c k8s.Client, params Params) error {
+	if params.HTTP.Ingress == nil {
+		return deleteIfExists(ctx, c, params)
+	}
+
+	expected, err := New(params)
+	if err != nil {
+		return err
+	}
+	reconciled := &networkingv1.Ingress{}
+	// the hostnames published by external-dns are not merged with the previous ones
+	_, expectHostnames := expected.Annotations[externaldns.HostnameAnnotation]
+	return reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Owner:      params.Owner,
+		Expected:   &expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			if expected.Spec.IngressClassName == nil {
+				// keep the default class set by the API server at creation
+				expected.Spec.IngressClassName = reconciled.Spec.IngressClassName
+			}
+			_, hasHostnames := reconciled.Annotations[externaldns.HostnameAnnotation]
+			return !reflect.DeepEqual(expected.Spec, reconciled.Spec) ||
+				!maps.IsSubset(expected.Labels, reconciled.Labels) ||
+				!maps.IsSubset(expected.Annotations, reconciled.Annotations) ||
+				hasHostnames != expectHostnames
+		},
+		UpdateReconciled: func() {
+			// don't remove the annotations set by the Ingress controller
+			reconciled.Labels = maps.Merge(reconciled.Labels, expected.Labels)
+			reconciled.Annotations = maps.Merge(reconciled.Annotations, expected.Annotations)
+			if !expectHostnames {
+				delete(reconciled.Annotations, externaldns.HostnameAnnotation)
+			}
+			reconciled.Spec = expected.Spec
+		},
+	})
+}
+
+func deleteIfExists(ctx context.Context, c k8s.Client, params Params) error {
+	var ingress networkingv1.Ingress
+	err := c.Get(ctx, types.NamespacedName{Namespace: params.Service.Namespace, Name: params.Service.Name}, &ingress)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !k8s.HasOwner(&ingress, params.Owner) {
+		// not created by the operator
+		return nil
+	}
+	err = c.Delete(ctx, &ingress, &client.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &ingress.UID}})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
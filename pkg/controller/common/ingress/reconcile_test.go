@@ -0,0 +1,193 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ingress
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func testParams(template *commonv1.IngressTemplate) Params {
+	kb := kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb", UID: "kb-uid"}}
+	return Params{
+		Owner: &kb,
+		HTTP:  commonv1.HTTPConfig{Ingress: template},
+		Service: corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb-http"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "https", Port: 5601}}},
+		},
+		Labels: map[string]string{"kibana.k8s.elastic.co/name": "kb"},
+		Namer:  kbv1.KBNamer,
+	}
+}
+
+func TestNew(t *testing.T) {
+	pathType := networkingv1.PathTypePrefix
+	rules := func(path string) []networkingv1.IngressRule {
+		return []networkingv1.IngressRule{{
+			Host: "kibana.example.com",
+			IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+				Paths: []networkingv1.HTTPIngressPath{{
+					Path:     path,
+					PathType: &pathType,
+					Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+						Name: "kb-kb-http",
+						Port: networkingv1.ServiceBackendPort{Number: 5601},
+					}},
+				}},
+			}},
+		}}
+	}
+	tests := []struct {
+		name     string
+		template commonv1.IngressTemplate
+		want     networkingv1.Ingress
+	}{
+		{
+			name:     "without TLS",
+			template: commonv1.IngressTemplate{Host: "kibana.example.com"},
+			want: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb-http", Labels: map[string]string{"kibana.k8s.elastic.co/name": "kb"}},
+				Spec:       networkingv1.IngressSpec{Rules: rules("/")},
+			},
+		},
+		{
+			name: "with metadata, class, path and a custom certificate",
+			template: commonv1.IngressTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"team": "observability"},
+					Annotations: map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS"},
+				},
+				ClassName: ptr.To("nginx"),
+				Host:      "kibana.example.com",
+				Path:      "/kibana",
+				TLS:       &commonv1.IngressTLSOptions{SecretName: "kibana-cert"},
+			},
+			want: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "ns",
+					Name:        "kb-kb-http",
+					Labels:      map[string]string{"kibana.k8s.elastic.co/name": "kb", "team": "observability"},
+					Annotations: map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS"},
+				},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To("nginx"),
+					Rules:            rules("/kibana"),
+					TLS:              []networkingv1.IngressTLS{{Hosts: []string{"kibana.example.com"}, SecretName: "kibana-cert"}},
+				},
+			},
+		},
+		{
+			name:     "with the HTTP certificate",
+			template: commonv1.IngressTemplate{Host: "kibana.example.com", TLS: &commonv1.IngressTLSOptions{}},
+			want: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb-http", Labels: map[string]string{"kibana.k8s.elastic.co/name": "kb"}},
+				Spec: networkingv1.IngressSpec{
+					Rules: rules("/"),
+					TLS:   []networkingv1.IngressTLS{{Hosts: []string{"kibana.example.com"}, SecretName: "kb-kb-http-certs-internal"}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(testParams(&tt.template))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	c := k8s.NewFakeClient()
+	key := types.NamespacedName{Namespace: "ns", Name: "kb-kb-http"}
+
+	// create the Ingress
+	params := testParams(&commonv1.IngressTemplate{Host: "kibana.example.com"})
+	require.NoError(t, Reconcile(ctx, c, params))
+	var ingress networkingv1.Ingress
+	require.NoError(t, c.Get(ctx, key, &ingress))
+	require.True(t, k8s.HasOwner(&ingress, params.Owner))
+
+	// the default class set at creation and the annotations of the Ingress controller are kept
+	ingress.Spec.IngressClassName = ptr.To("default")
+	ingress.Annotations = map[string]string{"ingress.kubernetes.io/backends": "{}"}
+	require.NoError(t, c.Update(ctx, &ingress))
+	require.NoError(t, Reconcile(ctx, c, params))
+	require.NoError(t, c.Get(ctx, key, &ingress))
+	require.Equal(t, ptr.To("default"), ingress.Spec.IngressClassName)
+	require.Equal(t, map[string]string{"ingress.kubernetes.io/backends": "{}"}, ingress.Annotations)
+
+	// the backend follows the port of the service
+	params.Service.Spec.Ports[0].Port = 443
+	require.NoError(t, Reconcile(ctx, c, params))
+	require.NoError(t, c.Get(ctx, key, &ingress))
+	require.Equal(t, int32(443), ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number)
+
+	// the Ingress is deleted once removed from the specification
+	params.HTTP.Ingress = nil
+	require.NoError(t, Reconcile(ctx, c, params))
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, key, &ingress)))
+	// deleting again is a no-op
+	require.NoError(t, Reconcile(ctx, c, params))
+}
+
+func TestReconcile_IngressNotOwned(t *testing.T) {
+	ctx := context.Background()
+	userIngress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb-http"}}
+	c := k8s.NewFakeClient(&userIngress)
+
+	// an Ingress not created by the operator is not deleted
+	require.NoError(t, Reconcile(ctx, c, testParams(nil)))
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(&userIngress), &networkingv1.Ingress{}))
+}
+
+func TestNew_Hostnames(t *testing.T) {
+	params := testParams(&commonv1.IngressTemplate{Host: "kibana.example.com", TLS: &commonv1.IngressTLSOptions{}})
+	params.HTTP.Hostnames = []string{"kb.example.com", "kibana.example.com"}
+	got, err := New(params)
+	require.NoError(t, err)
+	// all the hosts are routed and published by external-dns
+	require.Equal(t, map[string]string{"external-dns.alpha.kubernetes.io/hostname": "kb.example.com,kibana.example.com"}, got.Annotations)
+	require.Len(t, got.Spec.Rules, 2)
+	require.Equal(t, "kibana.example.com", got.Spec.Rules[0].Host)
+	require.Equal(t, "kb.example.com", got.Spec.Rules[1].Host)
+	require.Equal(t, []string{"kibana.example.com", "kb.example.com"}, got.Spec.TLS[0].Hosts)
+}
+
+func TestReconcile_Hostnames(t *testing.T) {
+	ctx := context.Background()
+	c := k8s.NewFakeClient()
+	key := types.NamespacedName{Namespace: "ns", Name: "kb-kb-http"}
+
+	params := testParams(&commonv1.IngressTemplate{})
+	params.HTTP.Hostnames = []string{"kibana.example.com"}
+	require.NoError(t, Reconcile(ctx, c, params))
+	var ingress networkingv1.Ingress
+	require.NoError(t, c.Get(ctx, key, &ingress))
+	require.Equal(t, "kibana.example.com", ingress.Annotations["external-dns.alpha.kubernetes.io/hostname"])
+
+	// the hostnames are not published anymore once removed, other annotations are kept
+	ingress.Annotations["ingress.kubernetes.io/backends"] = "{}"
+	require.NoError(t, c.Update(ctx, &ingress))
+	params.HTTP.Hostnames = nil
+	params.HTTP.Ingress.Host = "kibana.example.com"
+	require.NoError(t, Reconcile(ctx, c, params))
+	require.NoError(t, c.Get(ctx, key, &ingress))
+	require.Equal(t, map[string]string{"ingress.kubernetes.io/backends": "{}"}, ingress.Annotations)
+}
@@ -0,0 +1,99 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kbclient
+
+import (
+	"context"
+	"net/url"
+)
+
+// Connector is a connector as expected by the /api/actions/connector API. Kibana never returns the secrets.
+type Connector struct {
+	Name            string                 `json:"name"`
+	ConnectorTypeID string                 `json:"connector_type_id,omitempty"`
+	Config          map[string]interface{} `json:"config,omitempty"`
+	Secrets         map[string]string      `json:"secrets,omitempty"`
+}
+
+type AlertingClient interface {
+	// GetRule returns the alerting rule of the given id.
+	GetRule(ctx context.Context, id string) (map[string]interface{}, error)
+	// CreateRule creates an alerting rule with the given id.
+	CreateRule(ctx context.Context, id string, rule map[string]interface{}) error
+	// UpdateRule updates an alerting rule. Kibana rejects the fields that cannot be updated, such as the type of the rule.
+	UpdateRule(ctx context.Context, id string, rule map[string]interface{}) error
+	// EnableRule enables an alerting rule.
+	EnableRule(ctx context.Context, id string) error
+	// DisableRule disables an alerting rule.
+	DisableRule(ctx context.Context, id string) error
+	// DeleteRule deletes an alerting rule.
+	DeleteRule(ctx context.Context, id string) error
+	// GetConnector returns the connector of the given id, without its secrets.
+	GetConnector(ctx context.Context, id string) (Connector, error)
+	// CreateConnector creates a connector with the given id.
+	CreateConnector(ctx context.Context, id string, connector Connector) error
+	// UpdateConnector updates the name, the config and the secrets of a connector.
+	UpdateConnector(ctx context.Context, id string, connector Connector) error
+	// DeleteConnector deletes a connector.
+	DeleteConnector(ctx context.Context, id string) error
+}
+
+func rulePath(id string) string {
+	return "/api/alerting/rule/" + url.PathEscape(id)
+}
+
+func connectorPath(id string) string {
+	return "/api/actions/connector/" + url.PathEscape(id)
+}
+
+func (c *baseClient) GetRule(ctx context.Context, id string) (map[string]interface{}, error) {
+	var rule map[string]interface{}
+	if err := c.get(ctx, rulePath(id), &rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (c *baseClient) CreateRule(ctx context.Context, id string, rule map[string]interface{}) error {
+	return c.post(ctx, rulePath(id), rule, nil)
+}
+
+func (c *baseClient) UpdateRule(ctx context.Context, id string, rule map[string]interface{}) error {
+	return c.put(ctx, rulePath(id), rule, nil)
+}
+
+func (c *baseClient) EnableRule(ctx context.Context, id string) error {
+	return c.post(ctx, rulePath(id)+"/_enable", nil, nil)
+}
+
+func (c *baseClient) DisableRule(ctx context.Context, id string) error {
+	return c.post(ctx, rulePath(id)+"/_disable", nil, nil)
+}
+
+func (c *baseClient) DeleteRule(ctx context.Context, id string) error {
+	return c.delete(ctx, rulePath(id))
+}
+
+func (c *baseClient) GetConnector(ctx context.Context, id string) (Connector, error) {
+	var connector Connector
+	if err := c.get(ctx, connectorPath(id), &connector); err != nil {
+		return Connector{}, err
+	}
+	return connector, nil
+}
+
+func (c *baseClient) CreateConnector(ctx context.Context, id string, connector Connector) error {
+	return c.post(ctx, connectorPath(id), connector, nil)
+}
+
+func (c *baseClient) UpdateConnector(ctx context.Context, id string, connector Connector) error {
+	// the type of a connector cannot be updated
+	connector.ConnectorTypeID = ""
+	return c.put(ctx, connectorPath(id), connector, nil)
+}
+
+func (c *baseClient) DeleteConnector(ctx context.Context, id string) error {
+	return c.delete(ctx, connectorPath(id))
+}
@@ -0,0 +1,108 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kbclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(req *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+func newMockClient(fn roundTripFunc) *baseClient {
+	return &baseClient{
+		HTTP:     &http.Client{Transport: fn},
+		Endpoint: "https://kb-kb-http.ns.svc:5601",
+		Username: "elastic-internal",
+		Password: "secret",
+	}
+}
+
+func mockResponse(statusCode int, req *http.Request, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}
+}
+
+func TestClient_GetRule(t *testing.T) {
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/api/alerting/rule/6f3b7a8e-2c2b-4bb3-9b0e-1e1e3a1f6c7d", req.URL.Path)
+		username, password, ok := req.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "elastic-internal", username)
+		require.Equal(t, "secret", password)
+		return mockResponse(200, req, `{"id":"6f3b7a8e-2c2b-4bb3-9b0e-1e1e3a1f6c7d","name":"cpu","enabled":true,"execution_status":{"status":"ok"}}`)
+	})
+	rule, err := client.GetRule(context.Background(), "6f3b7a8e-2c2b-4bb3-9b0e-1e1e3a1f6c7d")
+	require.NoError(t, err)
+	require.Equal(t, "cpu", rule["name"])
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		return mockResponse(404, req, `{"statusCode":404,"error":"Not Found","message":"Saved object [alert/6f3b7a8e] not found"}`)
+	})
+	_, err = client.GetRule(context.Background(), "6f3b7a8e")
+	require.True(t, IsNotFound(err))
+	require.Contains(t, err.Error(), "Saved object [alert/6f3b7a8e] not found")
+}
+
+func TestClient_CreateRule(t *testing.T) {
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/api/alerting/rule/6f3b7a8e", req.URL.Path)
+		require.Equal(t, "true", req.Header.Get("kbn-xsrf"))
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"cpu","rule_type_id":".index-threshold","consumer":"alerts","schedule":{"interval":"1m"}}`, string(body))
+		return mockResponse(200, req, `{"id":"6f3b7a8e"}`)
+	})
+	rule := map[string]interface{}{
+		"name":         "cpu",
+		"rule_type_id": ".index-threshold",
+		"consumer":     "alerts",
+		"schedule":     map[string]interface{}{"interval": "1m"},
+	}
+	require.NoError(t, client.CreateRule(context.Background(), "6f3b7a8e", rule))
+}
+
+func TestClient_DisableRule(t *testing.T) {
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/api/alerting/rule/6f3b7a8e/_disable", req.URL.Path)
+		return mockResponse(204, req, ``)
+	})
+	require.NoError(t, client.DisableRule(context.Background(), "6f3b7a8e"))
+}
+
+func TestClient_UpdateConnector(t *testing.T) {
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/api/actions/connector/0c5c3fa6", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		// the type of the connector is not sent
+		require.JSONEq(t, `{"name":"ops","config":{"hasAuth":false},"secrets":{"webhookUrl":"https://hooks.slack.com/services/T0"}}`, string(body))
+		return mockResponse(200, req, `{"id":"0c5c3fa6"}`)
+	})
+	connector := Connector{
+		Name:            "ops",
+		ConnectorTypeID: ".slack",
+		Config:          map[string]interface{}{"hasAuth": false},
+		Secrets:         map[string]string{"webhookUrl": "https://hooks.slack.com/services/T0"},
+	}
+	require.NoError(t, client.UpdateConnector(context.Background(), "0c5c3fa6", connector))
+}
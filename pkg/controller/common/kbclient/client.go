@@ -0,0 +1,198 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kbclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.elastic.co/apm/module/apmhttp/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// requestTimeout is the timeout of the requests to the Kibana API.
+const requestTimeout = 60 * time.Second
+
+// Client is a client for the Kibana APIs the operator uses.
+type Client interface {
+	AlertingClient
+	DataViewClient
+	FleetClient
+	SpaceClient
+	// Close idle connections in the underlying http client.
+	Close()
+}
+
+type Provider func(ctx context.Context, c k8s.Client, dialer net.Dialer, kb kbv1.Kibana) (Client, error)
+
+// NewClient returns a client for the given Kibana, which must be associated with an Elasticsearch cluster managed by
+// the operator. Requests are authenticated as the controller user of that cluster.
+func NewClient(
+	ctx context.Context,
+	c k8s.Client,
+	dialer net.Dialer,
+	kb kbv1.Kibana,
+) (Client, error) {
+	defer tracing.Span(&ctx)()
+	esRef := kb.EsAssociation().AssociationRef()
+	if !esRef.IsDefined() || esRef.IsExternal() {
+		return nil, fmt.Errorf("no Elasticsearch cluster managed by the operator is associated with Kibana %s", kb.Name)
+	}
+
+	// Get the controller user of the Elasticsearch cluster
+	var controllerUserSecret corev1.Secret
+	key := types.NamespacedName{
+		Namespace: esRef.Namespace,
+		Name:      esv1.InternalUsersSecret(esRef.Name),
+	}
+	if err := c.Get(ctx, key, &controllerUserSecret); err != nil {
+		return nil, err
+	}
+	password, ok := controllerUserSecret.Data[user.ControllerUserName]
+	if !ok {
+		return nil, fmt.Errorf("controller user %s not found in Secret %s/%s", user.ControllerUserName, key.Namespace, key.Name)
+	}
+
+	// Get public certs
+	var caCerts []*x509.Certificate
+	if kb.Spec.HTTP.TLS.Enabled() {
+		var caSecret corev1.Secret
+		key = types.NamespacedName{
+			Namespace: kb.Namespace,
+			Name:      certificates.PublicCertsSecretName(kbv1.KBNamer, kb.Name),
+		}
+		if err := c.Get(ctx, key, &caSecret); err != nil {
+			return nil, err
+		}
+		trustedCerts, ok := caSecret.Data[certificates.CertFileName]
+		if !ok {
+			return nil, fmt.Errorf("%s not found in Secret %s/%s", certificates.CertFileName, key.Namespace, key.Name)
+		}
+		var err error
+		if caCerts, err = certificates.ParsePEMCerts(trustedCerts); err != nil {
+			return nil, err
+		}
+	}
+
+	basePath, err := kibana.GetKibanaBasePath(kb)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := association.ServiceURL(c, types.NamespacedName{Namespace: kb.Namespace, Name: kbv1.HTTPService(kb.Name)}, kb.Spec.HTTP.Protocol(), basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &baseClient{
+		HTTP: apmhttp.WrapClient(
+			commonhttp.Client(dialer, caCerts, requestTimeout),
+			apmhttp.WithClientRequestName(tracing.RequestName),
+			apmhttp.WithClientSpanType("external.kibana"),
+		),
+		Endpoint: endpoint,
+		Username: user.ControllerUserName,
+		Password: string(password),
+	}, nil
+}
+
+type baseClient struct {
+	HTTP     *http.Client
+	Endpoint string
+	Username string
+	Password string
+}
+
+// apiErrorResponse is the body of the error responses of the Kibana APIs.
+type apiErrorResponse struct {
+	Message string `json:"message"`
+}
+
+func (c *baseClient) Close() {
+	if c.HTTP != nil {
+		c.HTTP.CloseIdleConnections()
+	}
+}
+
+func (c *baseClient) get(ctx context.Context, path string, out interface{}) error {
+	return c.request(ctx, http.MethodGet, path, nil, out)
+}
+
+func (c *baseClient) post(ctx context.Context, path string, in, out interface{}) error {
+	return c.request(ctx, http.MethodPost, path, in, out)
+}
+
+func (c *baseClient) put(ctx context.Context, path string, in, out interface{}) error { //nolint:unparam
+	return c.request(ctx, http.MethodPut, path, in, out)
+}
+
+func (c *baseClient) delete(ctx context.Context, path string) error {
+	return c.request(ctx, http.MethodDelete, path, nil, nil)
+}
+
+func (c *baseClient) request(ctx context.Context, method string, path string, requestObj, responseObj interface{}) error {
+	var body io.Reader = http.NoBody
+	if requestObj != nil {
+		outData, err := json.Marshal(requestObj)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewBuffer(outData)
+	}
+	return c.send(ctx, method, path, body, "application/json", responseObj)
+}
+
+// send sends a request with the given body and content type, and decodes the JSON response into responseObj if not nil.
+func (c *baseClient) send(ctx context.Context, method string, path string, body io.Reader, contentType string, responseObj interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, method, c.Endpoint+path, body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set(commonhttp.InternalProductRequestHeaderKey, commonhttp.InternalProductRequestHeaderValue)
+	request.Header.Set("kbn-xsrf", "true")
+	request.SetBasicAuth(c.Username, c.Password)
+
+	resp, err := c.HTTP.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if apiErr := commonhttp.MaybeAPIError(resp); apiErr != nil {
+		// surface the reason returned by Kibana, if any
+		var errResponse apiErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResponse); err == nil && errResponse.Message != "" {
+			return fmt.Errorf("%w: %s", apiErr, errResponse.Message)
+		}
+		return apiErr
+	}
+	if responseObj != nil {
+		return json.NewDecoder(resp.Body).Decode(responseObj)
+	}
+	return nil
+}
+
+// IsNotFound checks whether the error was an HTTP 404 error.
+func IsNotFound(err error) bool {
+	return commonhttp.IsNotFound(err)
+}
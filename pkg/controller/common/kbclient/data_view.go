@@ -0,0 +1,115 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kbclient
+
+import (
+	"context"
+	"net/url"
+)
+
+// defaultSpaceID is the id of the Kibana space whose APIs are not prefixed by a space path.
+const defaultSpaceID = "default"
+
+// DataView is a data view as expected by the /api/data_views API.
+type DataView struct {
+	ID              string                  `json:"id,omitempty"`
+	Title           string                  `json:"title"`
+	Name            string                  `json:"name,omitempty"`
+	TimeFieldName   string                  `json:"timeFieldName,omitempty"`
+	RuntimeFieldMap map[string]RuntimeField `json:"runtimeFieldMap,omitempty"`
+}
+
+// RuntimeField is a runtime field of a data view.
+type RuntimeField struct {
+	Type   string             `json:"type"`
+	Script RuntimeFieldScript `json:"script"`
+}
+
+// RuntimeFieldScript is the script of a runtime field.
+type RuntimeFieldScript struct {
+	Source string `json:"source"`
+}
+
+type dataViewRequest struct {
+	DataView DataView `json:"data_view"`
+}
+
+type defaultDataViewRequest struct {
+	DataViewID *string `json:"data_view_id"`
+	Force      bool    `json:"force"`
+}
+
+type defaultDataViewResponse struct {
+	DataViewID string `json:"data_view_id"`
+}
+
+type DataViewClient interface {
+	// GetDataView returns the data view of the given id in the given space.
+	GetDataView(ctx context.Context, spaceID, id string) (DataView, error)
+	// CreateDataView creates a data view in the given space. The id of the data view is taken from the DataView.
+	CreateDataView(ctx context.Context, spaceID string, dataView DataView) error
+	// UpdateDataView updates the data view of the given id in the given space.
+	UpdateDataView(ctx context.Context, spaceID, id string, dataView DataView) error
+	// DeleteDataView deletes a data view.
+	DeleteDataView(ctx context.Context, spaceID, id string) error
+	// GetDefaultDataView returns the id of the default data view of the given space, empty if there is none.
+	GetDefaultDataView(ctx context.Context, spaceID string) (string, error)
+	// SetDefaultDataView sets the default data view of the given space, or unsets it if the id is empty.
+	SetDefaultDataView(ctx context.Context, spaceID, id string) error
+}
+
+// spacePath prefixes the given API path with the path of the given Kibana space.
+func spacePath(spaceID, path string) string {
+	if spaceID == "" || spaceID == defaultSpaceID {
+		return path
+	}
+	return "/s/" + url.PathEscape(spaceID) + path
+}
+
+func dataViewPath(spaceID, id string) string {
+	path := "/api/data_views/data_view"
+	if id != "" {
+		path += "/" + url.PathEscape(id)
+	}
+	return spacePath(spaceID, path)
+}
+
+func (c *baseClient) GetDataView(ctx context.Context, spaceID, id string) (DataView, error) {
+	var response dataViewRequest
+	if err := c.get(ctx, dataViewPath(spaceID, id), &response); err != nil {
+		return DataView{}, err
+	}
+	return response.DataView, nil
+}
+
+func (c *baseClient) CreateDataView(ctx context.Context, spaceID string, dataView DataView) error {
+	return c.post(ctx, dataViewPath(spaceID, ""), dataViewRequest{DataView: dataView}, nil)
+}
+
+func (c *baseClient) UpdateDataView(ctx context.Context, spaceID, id string, dataView DataView) error {
+	// the id of a data view cannot be updated
+	dataView.ID = ""
+	return c.post(ctx, dataViewPath(spaceID, id), dataViewRequest{DataView: dataView}, nil)
+}
+
+func (c *baseClient) DeleteDataView(ctx context.Context, spaceID, id string) error {
+	return c.delete(ctx, dataViewPath(spaceID, id))
+}
+
+func (c *baseClient) GetDefaultDataView(ctx context.Context, spaceID string) (string, error) {
+	var response defaultDataViewResponse
+	if err := c.get(ctx, spacePath(spaceID, "/api/data_views/default"), &response); err != nil {
+		return "", err
+	}
+	return response.DataViewID, nil
+}
+
+func (c *baseClient) SetDefaultDataView(ctx context.Context, spaceID, id string) error {
+	request := defaultDataViewRequest{Force: true}
+	if id != "" {
+		request.DataViewID = &id
+	}
+	return c.post(ctx, spacePath(spaceID, "/api/data_views/default"), request, nil)
+}
@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kbclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetDataView(t *testing.T) {
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/s/team-a/api/data_views/data_view/logs", req.URL.Path)
+		return mockResponse(200, req, `{"data_view":{"id":"logs","title":"logs-*","name":"Logs","timeFieldName":"@timestamp",`+
+			`"runtimeFieldMap":{"day":{"type":"keyword","script":{"source":"emit('monday')"}}},"fields":{}}}`)
+	})
+	dataView, err := client.GetDataView(context.Background(), "team-a", "logs")
+	require.NoError(t, err)
+	require.Equal(t, DataView{
+		ID:            "logs",
+		Title:         "logs-*",
+		Name:          "Logs",
+		TimeFieldName: "@timestamp",
+		RuntimeFieldMap: map[string]RuntimeField{
+			"day": {Type: "keyword", Script: RuntimeFieldScript{Source: "emit('monday')"}},
+		},
+	}, dataView)
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, "/api/data_views/data_view/logs", req.URL.Path)
+		return mockResponse(404, req, `{"statusCode":404,"error":"Not Found","message":"Saved object [index-pattern/logs] not found"}`)
+	})
+	_, err = client.GetDataView(context.Background(), "default", "logs")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_CreateAndUpdateDataView(t *testing.T) {
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/api/data_views/data_view", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"data_view":{"id":"logs","title":"logs-*","name":"Logs"}}`, string(body))
+		return mockResponse(200, req, `{"data_view":{"id":"logs"}}`)
+	})
+	require.NoError(t, client.CreateDataView(context.Background(), "", DataView{ID: "logs", Title: "logs-*", Name: "Logs"}))
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/api/data_views/data_view/logs", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"data_view":{"title":"logs-*,metrics-*","name":"Logs"}}`, string(body))
+		return mockResponse(200, req, `{"data_view":{"id":"logs"}}`)
+	})
+	require.NoError(t, client.UpdateDataView(context.Background(), "default", "logs", DataView{ID: "logs", Title: "logs-*,metrics-*", Name: "Logs"}))
+}
+
+func TestClient_DefaultDataView(t *testing.T) {
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/s/team-a/api/data_views/default", req.URL.Path)
+		return mockResponse(200, req, `{"data_view_id":"logs"}`)
+	})
+	id, err := client.GetDefaultDataView(context.Background(), "team-a")
+	require.NoError(t, err)
+	require.Equal(t, "logs", id)
+
+	var bodies []string
+	client = newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/s/team-a/api/data_views/default", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+		return mockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, client.SetDefaultDataView(context.Background(), "team-a", "logs"))
+	require.NoError(t, client.SetDefaultDataView(context.Background(), "team-a", ""))
+	require.Len(t, bodies, 2)
+	require.JSONEq(t, `{"data_view_id":"logs","force":true}`, bodies[0])
+	require.JSONEq(t, `{"data_view_id":null,"force":true}`, bodies[1])
+}
@@ -0,0 +1,123 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kbclient
+
+import (
+	"context"
+	"net/url"
+)
+
+// PackageInstalledStatus is the status of a Fleet package installed in Kibana.
+const PackageInstalledStatus = "installed"
+
+type FleetClient interface {
+	// GetAgentPolicy returns the agent policy of the given id.
+	GetAgentPolicy(ctx context.Context, id string) (map[string]interface{}, error)
+	// CreateAgentPolicy creates an agent policy with the given id.
+	CreateAgentPolicy(ctx context.Context, id string, policy map[string]interface{}) error
+	// UpdateAgentPolicy updates an agent policy.
+	UpdateAgentPolicy(ctx context.Context, id string, policy map[string]interface{}) error
+	// DeleteAgentPolicy deletes an agent policy. Fleet refuses to delete a policy agents are still enrolled in.
+	DeleteAgentPolicy(ctx context.Context, id string) error
+	// GetPackagePolicy returns the package policy, or integration, of the given id in the simplified format, where
+	// inputs and streams are indexed by their ids.
+	GetPackagePolicy(ctx context.Context, id string) (map[string]interface{}, error)
+	// CreatePackagePolicy creates a package policy with the given id from its definition in the simplified format.
+	CreatePackagePolicy(ctx context.Context, id string, packagePolicy map[string]interface{}) error
+	// UpdatePackagePolicy updates a package policy from its definition in the simplified format.
+	UpdatePackagePolicy(ctx context.Context, id string, packagePolicy map[string]interface{}) error
+	// DeletePackagePolicy deletes a package policy.
+	DeletePackagePolicy(ctx context.Context, id string) error
+	// GetPackageStatus returns the installation status of the given version of a package, for example installed.
+	GetPackageStatus(ctx context.Context, name, version string) (string, error)
+	// InstallPackage installs the given version of a package from the package registry.
+	InstallPackage(ctx context.Context, name, version string) error
+}
+
+// fleetItem is the wrapper of the objects returned by the Fleet APIs.
+type fleetItem struct {
+	Item map[string]interface{} `json:"item"`
+}
+
+// fleetPackageResponse is the response of the Fleet get package API.
+type fleetPackageResponse struct {
+	Item struct {
+		Status string `json:"status"`
+	} `json:"item"`
+}
+
+func agentPolicyPath(id string) string {
+	return "/api/fleet/agent_policies/" + url.PathEscape(id)
+}
+
+func packagePolicyPath(id string) string {
+	return "/api/fleet/package_policies/" + url.PathEscape(id)
+}
+
+func packagePath(name, version string) string {
+	return "/api/fleet/epm/packages/" + url.PathEscape(name) + "/" + url.PathEscape(version)
+}
+
+// withID returns a copy of the given object with the given id, which the Fleet create APIs expect in the body.
+func withID(id string, obj map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(obj)+1)
+	for k, v := range obj {
+		result[k] = v
+	}
+	result["id"] = id
+	return result
+}
+
+func (c *baseClient) GetAgentPolicy(ctx context.Context, id string) (map[string]interface{}, error) {
+	var response fleetItem
+	if err := c.get(ctx, agentPolicyPath(id), &response); err != nil {
+		return nil, err
+	}
+	return response.Item, nil
+}
+
+func (c *baseClient) CreateAgentPolicy(ctx context.Context, id string, policy map[string]interface{}) error {
+	return c.post(ctx, "/api/fleet/agent_policies", withID(id, policy), nil)
+}
+
+func (c *baseClient) UpdateAgentPolicy(ctx context.Context, id string, policy map[string]interface{}) error {
+	return c.put(ctx, agentPolicyPath(id), policy, nil)
+}
+
+func (c *baseClient) DeleteAgentPolicy(ctx context.Context, id string) error {
+	return c.post(ctx, "/api/fleet/agent_policies/delete", map[string]string{"agentPolicyId": id}, nil)
+}
+
+func (c *baseClient) GetPackagePolicy(ctx context.Context, id string) (map[string]interface{}, error) {
+	var response fleetItem
+	if err := c.get(ctx, packagePolicyPath(id)+"?format=simplified", &response); err != nil {
+		return nil, err
+	}
+	return response.Item, nil
+}
+
+func (c *baseClient) CreatePackagePolicy(ctx context.Context, id string, packagePolicy map[string]interface{}) error {
+	return c.post(ctx, "/api/fleet/package_policies?format=simplified", withID(id, packagePolicy), nil)
+}
+
+func (c *baseClient) UpdatePackagePolicy(ctx context.Context, id string, packagePolicy map[string]interface{}) error {
+	return c.put(ctx, packagePolicyPath(id)+"?format=simplified", packagePolicy, nil)
+}
+
+func (c *baseClient) DeletePackagePolicy(ctx context.Context, id string) error {
+	return c.delete(ctx, packagePolicyPath(id))
+}
+
+func (c *baseClient) GetPackageStatus(ctx context.Context, name, version string) (string, error) {
+	var response fleetPackageResponse
+	if err := c.get(ctx, packagePath(name, version), &response); err != nil {
+		return "", err
+	}
+	return response.Item.Status, nil
+}
+
+func (c *baseClient) InstallPackage(ctx context.Context, name, version string) error {
+	return c.post(ctx, packagePath(name, version), nil, nil)
+}
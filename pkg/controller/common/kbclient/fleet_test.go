@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kbclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_AgentPolicy(t *testing.T) {
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/api/fleet/agent_policies/eck-agents", req.URL.Path)
+		return mockResponse(200, req, `{"item":{"id":"eck-agents","name":"ECK agents","namespace":"default","revision":3}}`)
+	})
+	policy, err := client.GetAgentPolicy(context.Background(), "eck-agents")
+	require.NoError(t, err)
+	require.Equal(t, "ECK agents", policy["name"])
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		return mockResponse(404, req, `{"statusCode":404,"error":"Not Found","message":"Agent policy eck-agents not found"}`)
+	})
+	_, err = client.GetAgentPolicy(context.Background(), "eck-agents")
+	require.True(t, IsNotFound(err))
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/api/fleet/agent_policies", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"id":"eck-agents","name":"ECK agents","namespace":"default"}`, string(body))
+		return mockResponse(200, req, `{"item":{"id":"eck-agents"}}`)
+	})
+	require.NoError(t, client.CreateAgentPolicy(context.Background(), "eck-agents", map[string]interface{}{"name": "ECK agents", "namespace": "default"}))
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/api/fleet/agent_policies/eck-agents", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"ECK agents","namespace":"prod"}`, string(body))
+		return mockResponse(200, req, `{"item":{"id":"eck-agents"}}`)
+	})
+	require.NoError(t, client.UpdateAgentPolicy(context.Background(), "eck-agents", map[string]interface{}{"name": "ECK agents", "namespace": "prod"}))
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/api/fleet/agent_policies/delete", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"agentPolicyId":"eck-agents"}`, string(body))
+		return mockResponse(200, req, `{"id":"eck-agents","name":"ECK agents"}`)
+	})
+	require.NoError(t, client.DeleteAgentPolicy(context.Background(), "eck-agents"))
+}
+
+func TestClient_PackagePolicy(t *testing.T) {
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/api/fleet/package_policies/eck-agents-system", req.URL.Path)
+		require.Equal(t, "simplified", req.URL.Query().Get("format"))
+		return mockResponse(200, req, `{"item":{"id":"eck-agents-system","name":"system","policy_id":"eck-agents","package":{"name":"system","version":"1.54.0"}}}`)
+	})
+	packagePolicy, err := client.GetPackagePolicy(context.Background(), "eck-agents-system")
+	require.NoError(t, err)
+	require.Equal(t, "eck-agents", packagePolicy["policy_id"])
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/api/fleet/package_policies", req.URL.Path)
+		require.Equal(t, "simplified", req.URL.Query().Get("format"))
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"id":"eck-agents-system","name":"system","policy_id":"eck-agents","package":{"name":"system","version":"1.54.0"}}`, string(body))
+		return mockResponse(200, req, `{"item":{"id":"eck-agents-system"}}`)
+	})
+	require.NoError(t, client.CreatePackagePolicy(context.Background(), "eck-agents-system", map[string]interface{}{
+		"name":      "system",
+		"policy_id": "eck-agents",
+		"package":   map[string]interface{}{"name": "system", "version": "1.54.0"},
+	}))
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/api/fleet/package_policies/eck-agents-system", req.URL.Path)
+		require.Equal(t, "simplified", req.URL.Query().Get("format"))
+		return mockResponse(200, req, `{"item":{"id":"eck-agents-system"}}`)
+	})
+	require.NoError(t, client.UpdatePackagePolicy(context.Background(), "eck-agents-system", map[string]interface{}{"name": "system"}))
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/api/fleet/package_policies/eck-agents-system", req.URL.Path)
+		return mockResponse(200, req, `{"id":"eck-agents-system"}`)
+	})
+	require.NoError(t, client.DeletePackagePolicy(context.Background(), "eck-agents-system"))
+}
+
+func TestClient_Package(t *testing.T) {
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/api/fleet/epm/packages/system/1.54.0", req.URL.Path)
+		return mockResponse(200, req, `{"item":{"name":"system","version":"1.54.0","status":"not_installed"}}`)
+	})
+	status, err := client.GetPackageStatus(context.Background(), "system", "1.54.0")
+	require.NoError(t, err)
+	require.Equal(t, "not_installed", status)
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/api/fleet/epm/packages/system/1.54.0", req.URL.Path)
+		return mockResponse(200, req, `{"items":[]}`)
+	})
+	require.NoError(t, client.InstallPackage(context.Background(), "system", "1.54.0"))
+}
@@ -0,0 +1,110 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kbclient
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+)
+
+// Space is a Kibana space as expected by the /api/spaces API.
+type Space struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description,omitempty"`
+	Color            string   `json:"color,omitempty"`
+	Initials         string   `json:"initials,omitempty"`
+	DisabledFeatures []string `json:"disabledFeatures,omitempty"`
+}
+
+// SavedObjectsImportResponse is the response of the saved objects import API.
+type SavedObjectsImportResponse struct {
+	Success      bool                      `json:"success"`
+	SuccessCount int                       `json:"successCount"`
+	Errors       []SavedObjectsImportError `json:"errors,omitempty"`
+}
+
+// SavedObjectsImportError is the error of a saved object that could not be imported.
+type SavedObjectsImportError struct {
+	ID    string                        `json:"id"`
+	Type  string                        `json:"type"`
+	Title string                        `json:"title,omitempty"`
+	Error SavedObjectsImportErrorReason `json:"error"`
+}
+
+// SavedObjectsImportErrorReason is the reason a saved object could not be imported, for example conflict or
+// missing_references.
+type SavedObjectsImportErrorReason struct {
+	Type    string `json:"type"`
+	Message string `json:"message,omitempty"`
+}
+
+type SpaceClient interface {
+	// GetSpace returns the space of the given id.
+	GetSpace(ctx context.Context, id string) (Space, error)
+	// CreateSpace creates a space.
+	CreateSpace(ctx context.Context, space Space) error
+	// UpdateSpace updates the space of the id of the given Space.
+	UpdateSpace(ctx context.Context, space Space) error
+	// DeleteSpace deletes a space and all the saved objects it contains.
+	DeleteSpace(ctx context.Context, id string) error
+	// ImportSavedObjects imports into the given space the saved objects of an NDJSON export file, overwriting the
+	// existing saved objects with the same type and id.
+	ImportSavedObjects(ctx context.Context, spaceID, fileName string, ndjson []byte) (SavedObjectsImportResponse, error)
+}
+
+func spaceAPIPath(id string) string {
+	path := "/api/spaces/space"
+	if id != "" {
+		path += "/" + url.PathEscape(id)
+	}
+	return path
+}
+
+func (c *baseClient) GetSpace(ctx context.Context, id string) (Space, error) {
+	var space Space
+	if err := c.get(ctx, spaceAPIPath(id), &space); err != nil {
+		return Space{}, err
+	}
+	return space, nil
+}
+
+func (c *baseClient) CreateSpace(ctx context.Context, space Space) error {
+	return c.post(ctx, spaceAPIPath(""), space, nil)
+}
+
+func (c *baseClient) UpdateSpace(ctx context.Context, space Space) error {
+	return c.put(ctx, spaceAPIPath(space.ID), space, nil)
+}
+
+func (c *baseClient) DeleteSpace(ctx context.Context, id string) error {
+	return c.delete(ctx, spaceAPIPath(id))
+}
+
+func (c *baseClient) ImportSavedObjects(ctx context.Context, spaceID, fileName string, ndjson []byte) (SavedObjectsImportResponse, error) {
+	// the import API expects the export file in a multipart form
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("file", fileName)
+	if err != nil {
+		return SavedObjectsImportResponse{}, err
+	}
+	if _, err := file.Write(ndjson); err != nil {
+		return SavedObjectsImportResponse{}, err
+	}
+	if err := form.Close(); err != nil {
+		return SavedObjectsImportResponse{}, err
+	}
+
+	var response SavedObjectsImportResponse
+	path := spacePath(spaceID, "/api/saved_objects/_import?overwrite=true")
+	if err := c.send(ctx, http.MethodPost, path, &body, form.FormDataContentType(), &response); err != nil {
+		return SavedObjectsImportResponse{}, err
+	}
+	return response, nil
+}
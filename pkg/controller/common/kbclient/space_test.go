@@ -0,0 +1,86 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kbclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetSpace(t *testing.T) {
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/api/spaces/space/team-a", req.URL.Path)
+		return mockResponse(200, req, `{"id":"team-a","name":"Team A","color":"#AABBCC","initials":"TA","disabledFeatures":["dev_tools"],"imageUrl":""}`)
+	})
+	space, err := client.GetSpace(context.Background(), "team-a")
+	require.NoError(t, err)
+	require.Equal(t, Space{ID: "team-a", Name: "Team A", Color: "#AABBCC", Initials: "TA", DisabledFeatures: []string{"dev_tools"}}, space)
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		return mockResponse(404, req, `{"statusCode":404,"error":"Not Found","message":"Not Found"}`)
+	})
+	_, err = client.GetSpace(context.Background(), "team-b")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_CreateAndUpdateSpace(t *testing.T) {
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/api/spaces/space", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"id":"team-a","name":"Team A","disabledFeatures":["dev_tools"]}`, string(body))
+		return mockResponse(200, req, `{"id":"team-a"}`)
+	})
+	require.NoError(t, client.CreateSpace(context.Background(), Space{ID: "team-a", Name: "Team A", DisabledFeatures: []string{"dev_tools"}}))
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/api/spaces/space/team-a", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"id":"team-a","name":"Team A","description":"Team A dashboards"}`, string(body))
+		return mockResponse(200, req, `{"id":"team-a"}`)
+	})
+	require.NoError(t, client.UpdateSpace(context.Background(), Space{ID: "team-a", Name: "Team A", Description: "Team A dashboards"}))
+}
+
+func TestClient_ImportSavedObjects(t *testing.T) {
+	ndjson := `{"type":"dashboard","id":"overview","attributes":{"title":"Overview"},"references":[]}`
+	client := newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/s/team-a/api/saved_objects/_import", req.URL.Path)
+		require.Equal(t, "true", req.URL.Query().Get("overwrite"))
+		require.Equal(t, "true", req.Header.Get("kbn-xsrf"))
+		file, header, err := req.FormFile("file")
+		require.NoError(t, err)
+		require.Equal(t, "dashboards.ndjson", header.Filename)
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		require.Equal(t, ndjson, string(content))
+		return mockResponse(200, req, `{"success":false,"successCount":1,"errors":[`+
+			`{"id":"logs","type":"visualization","title":"Logs","meta":{"title":"Logs"},"error":{"type":"missing_references","references":[{"type":"index-pattern","id":"logs-*"}]}}]}`)
+	})
+	response, err := client.ImportSavedObjects(context.Background(), "team-a", "dashboards.ndjson", []byte(ndjson))
+	require.NoError(t, err)
+	require.Equal(t, SavedObjectsImportResponse{
+		SuccessCount: 1,
+		Errors: []SavedObjectsImportError{
+			{ID: "logs", Type: "visualization", Title: "Logs", Error: SavedObjectsImportErrorReason{Type: "missing_references"}},
+		},
+	}, response)
+
+	client = newMockClient(func(req *http.Request) *http.Response {
+		require.Equal(t, "/api/saved_objects/_import", req.URL.Path)
+		return mockResponse(400, req, `{"statusCode":400,"error":"Bad Request","message":"Unexpected token in JSON"}`)
+	})
+	_, err = client.ImportSavedObjects(context.Background(), "default", "dashboards.ndjson", []byte("{"))
+	require.ErrorContains(t, err, "Unexpected token in JSON")
+}
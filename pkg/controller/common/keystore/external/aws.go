@@ -0,0 +1,242 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package external
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	awsAccessKeyIDEnvVar       = "AWS_ACCESS_KEY_ID"
+	awsSecretAccessKeyEnvVar   = "AWS_SECRET_ACCESS_KEY" //nolint:gosec
+	awsSessionTokenEnvVar      = "AWS_SESSION_TOKEN"     //nolint:gosec
+	awsRegionEnvVar            = "AWS_REGION"
+	awsDefaultRegionEnvVar     = "AWS_DEFAULT_REGION"
+	awsRoleARNEnvVar           = "AWS_ROLE_ARN"
+	awsWebIdentityTokenEnvVar  = "AWS_WEB_IDENTITY_TOKEN_FILE" //nolint:gosec
+	awsSigningAlgorithm        = "AWS4-HMAC-SHA256"
+	awsTimeFormat              = "20060102T150405Z"
+	awsCredentialsExpiryMargin = 5 * time.Minute
+)
+
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      time.Time
+}
+
+// awsProvider reads secrets from AWS Secrets Manager. It uses the credentials set in AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY or, with IAM roles for service accounts, the web identity token of the operator to assume
+// the role set in AWS_ROLE_ARN.
+type awsProvider struct {
+	httpClient *http.Client
+	region     string
+	endpoint   func(service, region string) string
+	now        func() time.Time
+
+	mu          sync.Mutex
+	credentials *awsCredentials
+}
+
+func newAWSProvider(httpClient *http.Client) *awsProvider {
+	region := os.Getenv(awsRegionEnvVar)
+	if region == "" {
+		region = os.Getenv(awsDefaultRegionEnvVar)
+	}
+	return &awsProvider{
+		httpClient: httpClient,
+		region:     region,
+		endpoint: func(service, region string) string {
+			return fmt.Sprintf("https://%s.%s.amazonaws.com/", service, region)
+		},
+		now: time.Now,
+	}
+}
+
+func (a *awsProvider) Fetch(ctx context.Context, path string) (map[string][]byte, error) {
+	region := a.region
+	// the region of a secret referenced by its ARN takes precedence: arn:aws:secretsmanager:<region>:<account>:secret:<name>
+	if arn := strings.Split(path, ":"); len(arn) > 3 && arn[0] == "arn" {
+		region = arn[3]
+	}
+	if region == "" {
+		return nil, fmt.Errorf("%s must be set to fetch secrets from AWS Secrets Manager", awsRegionEnvVar)
+	}
+	creds, err := a.getCredentials(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint("secretsmanager", region), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signV4(req, body, creds, region, "secretsmanager", a.now())
+
+	respBody, err := doRequest(a.httpClient, req)
+	if err != nil {
+		return nil, err
+	}
+	var secret struct {
+		SecretString *string `json:"SecretString"`
+		SecretBinary []byte  `json:"SecretBinary"`
+	}
+	if err := json.Unmarshal(respBody, &secret); err != nil {
+		return nil, err
+	}
+	if secret.SecretString != nil {
+		return toData([]byte(*secret.SecretString)), nil
+	}
+	return map[string][]byte{ValueKey: secret.SecretBinary}, nil
+}
+
+// getCredentials returns static credentials from the environment, or temporary credentials obtained by assuming
+// the role of the operator with its web identity token.
+func (a *awsProvider) getCredentials(ctx context.Context, region string) (awsCredentials, error) {
+	if id := os.Getenv(awsAccessKeyIDEnvVar); id != "" {
+		return awsCredentials{
+			AccessKeyID:     id,
+			SecretAccessKey: os.Getenv(awsSecretAccessKeyEnvVar),
+			SessionToken:    os.Getenv(awsSessionTokenEnvVar),
+		}, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.credentials != nil && a.now().Add(awsCredentialsExpiryMargin).Before(a.credentials.Expiration) {
+		return *a.credentials, nil
+	}
+	roleARN, tokenFile := os.Getenv(awsRoleARNEnvVar), os.Getenv(awsWebIdentityTokenEnvVar)
+	if roleARN == "" || tokenFile == "" {
+		return awsCredentials{}, fmt.Errorf("%s or %s and %s must be set to fetch secrets from AWS Secrets Manager",
+			awsAccessKeyIDEnvVar, awsRoleARNEnvVar, awsWebIdentityTokenEnvVar)
+	}
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("while reading web identity token: %w", err)
+	}
+	creds, err := a.assumeRoleWithWebIdentity(ctx, region, roleARN, strings.TrimSpace(string(token)))
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	a.credentials = &creds
+	return creds, nil
+}
+
+func (a *awsProvider) assumeRoleWithWebIdentity(ctx context.Context, region, roleARN, token string) (awsCredentials, error) {
+	query := url.Values{
+		"Action":           []string{"AssumeRoleWithWebIdentity"},
+		"Version":          []string{"2011-06-15"},
+		"RoleArn":          []string{roleARN},
+		"RoleSessionName":  []string{"eck-operator"},
+		"WebIdentityToken": []string{token},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.endpoint("sts", region)+"?"+query.Encode(), nil)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	body, err := doRequest(a.httpClient, req)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("while assuming role %s: %w", roleARN, err)
+	}
+	var resp struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return awsCredentials{}, err
+	}
+	if resp.Credentials.AccessKeyID == "" {
+		return awsCredentials{}, fmt.Errorf("while assuming role %s: no credentials in response", roleARN)
+	}
+	return awsCredentials(resp.Credentials), nil
+}
+
+// signV4 signs the request with the AWS Signature Version 4 signing process, all the headers of the request are signed.
+func signV4(req *http.Request, body []byte, creds awsCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format(awsTimeFormat)
+	scope := strings.Join([]string{amzDate[:8], region, service, "aws4_request"}, "/")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		values := make([]string, len(v))
+		for i := range v {
+			values[i] = strings.TrimSpace(v[i])
+		}
+		headers[strings.ToLower(k)] = strings.Join(values, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	uri := req.URL.EscapedPath()
+	if uri == "" {
+		uri = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uri,
+		strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+	stringToSign := strings.Join([]string{awsSigningAlgorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := []byte("AWS4" + creds.SecretAccessKey)
+	for _, part := range []string{amzDate[:8], region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsSigningAlgorithm, creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
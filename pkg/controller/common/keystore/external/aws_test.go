@@ -0,0 +1,133 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package external
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_signV4(t *testing.T) {
+	// example of the AWS Signature Version 4 documentation
+	req, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	creds := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signV4(req, nil, creds, "us-east-1", "iam", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	require.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	require.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, "+
+		"SignedHeaders=content-type;host;x-amz-date, "+
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7", req.Header.Get("Authorization"))
+}
+
+func Test_awsProvider_Fetch(t *testing.T) {
+	t.Setenv(awsAccessKeyIDEnvVar, "AKIDEXAMPLE")
+	t.Setenv(awsSecretAccessKeyEnvVar, "secret")
+	t.Setenv(awsSessionTokenEnvVar, "session")
+
+	var region string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		require.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+		require.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240102/"+region+"/secretsmanager/aws4_request"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		switch string(body) {
+		case `{"SecretId":"es-s3"}`, `{"SecretId":"arn:aws:secretsmanager:eu-west-1:123456789012:secret:es-s3"}`:
+			_, _ = w.Write([]byte(`{"Name":"es-s3","SecretString":"{\"s3.client.default.secret_key\":\"secret\"}"}`))
+		case `{"SecretId":"es-binary"}`:
+			_, _ = w.Write([]byte(`{"Name":"es-binary","SecretBinary":"YmluYXJ5"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","Message":"Secrets Manager can't find the specified secret."}`))
+		}
+	}))
+	defer server.Close()
+
+	p := newAWSProvider(server.Client())
+	p.region = "us-east-1"
+	p.endpoint = func(service, r string) string {
+		region = r
+		return server.URL + "/"
+	}
+	p.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	data, err := p.Fetch(context.Background(), "es-s3")
+	require.NoError(t, err)
+	require.Equal(t, "us-east-1", region)
+	require.Equal(t, map[string][]byte{"s3.client.default.secret_key": []byte("secret")}, data)
+
+	data, err = p.Fetch(context.Background(), "arn:aws:secretsmanager:eu-west-1:123456789012:secret:es-s3")
+	require.NoError(t, err)
+	require.Equal(t, "eu-west-1", region)
+	require.Equal(t, map[string][]byte{"s3.client.default.secret_key": []byte("secret")}, data)
+
+	data, err = p.Fetch(context.Background(), "es-binary")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{ValueKey: []byte("binary")}, data)
+
+	_, err = p.Fetch(context.Background(), "unknown")
+	require.ErrorContains(t, err, "ResourceNotFoundException")
+}
+
+func Test_awsProvider_getCredentials(t *testing.T) {
+	t.Setenv(awsAccessKeyIDEnvVar, "")
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("web-identity-token\n"), 0o600))
+	t.Setenv(awsRoleARNEnvVar, "arn:aws:iam::123456789012:role/eck-operator")
+	t.Setenv(awsWebIdentityTokenEnvVar, tokenFile)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		require.Equal(t, "AssumeRoleWithWebIdentity", r.URL.Query().Get("Action"))
+		require.Equal(t, "arn:aws:iam::123456789012:role/eck-operator", r.URL.Query().Get("RoleArn"))
+		require.Equal(t, "web-identity-token", r.URL.Query().Get("WebIdentityToken"))
+		_, _ = w.Write([]byte(`<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>ASIA</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session</SessionToken>
+      <Expiration>2024-01-02T04:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	p := newAWSProvider(server.Client())
+	p.endpoint = func(_, _ string) string { return server.URL + "/" }
+	p.now = func() time.Time { return now }
+
+	creds, err := p.getCredentials(context.Background(), "us-east-1")
+	require.NoError(t, err)
+	require.Equal(t, awsCredentials{
+		AccessKeyID:     "ASIA",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+		Expiration:      time.Date(2024, 1, 2, 4, 0, 0, 0, time.UTC),
+	}, creds)
+	// credentials are reused until they are about to expire
+	_, err = p.getCredentials(context.Background(), "us-east-1")
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+	now = now.Add(58 * time.Minute)
+	_, err = p.getCredentials(context.Background(), "us-east-1")
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+}
@@ -0,0 +1,111 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package external
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	gcpMetadataHostEnvVar  = "GCE_METADATA_HOST"
+	defaultGCPMetadataHost = "metadata.google.internal"
+	gcpSecretManagerURL    = "https://secretmanager.googleapis.com/v1/"
+	gcpTokenExpiryMargin   = time.Minute
+)
+
+// gcpProvider reads secrets from Google Cloud Secret Manager, with an access token for the service account of the
+// operator obtained from the metadata server, as provided by Workload Identity.
+type gcpProvider struct {
+	httpClient   *http.Client
+	metadataURL  string
+	secretsURL   string
+	now          func() time.Time
+	mu           sync.Mutex
+	accessToken  string
+	tokenExpires time.Time
+}
+
+func newGCPProvider(httpClient *http.Client) *gcpProvider {
+	host := os.Getenv(gcpMetadataHostEnvVar)
+	if host == "" {
+		host = defaultGCPMetadataHost
+	}
+	return &gcpProvider{
+		httpClient:  httpClient,
+		metadataURL: fmt.Sprintf("http://%s/computeMetadata/v1/instance/service-accounts/default/token", host),
+		secretsURL:  gcpSecretManagerURL,
+		now:         time.Now,
+	}
+}
+
+// Fetch returns the latest version of the secret with the given resource name, for example
+// projects/my-project/secrets/my-secret. A specific version can be selected with the versions/<version> suffix.
+func (g *gcpProvider) Fetch(ctx context.Context, path string) (map[string][]byte, error) {
+	token, err := g.getAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	path = strings.Trim(path, "/")
+	if !strings.Contains(path, "/versions/") {
+		path += "/versions/latest"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.secretsURL+path+":access", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	body, err := doRequest(g.httpClient, req)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	value, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	return toData(value), nil
+}
+
+func (g *gcpProvider) getAccessToken(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.accessToken != "" && g.now().Add(gcpTokenExpiryMargin).Before(g.tokenExpires) {
+		return g.accessToken, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.metadataURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	body, err := doRequest(g.httpClient, req)
+	if err != nil {
+		return "", fmt.Errorf("while getting an access token from the metadata server: %w", err)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", err
+	}
+	g.accessToken = token.AccessToken
+	g.tokenExpires = g.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return g.accessToken, nil
+}
@@ -0,0 +1,59 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package external
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_gcpProvider_Fetch(t *testing.T) {
+	tokens := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		require.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+		_, _ = w.Write([]byte(`{"access_token":"ya29.token","expires_in":3599,"token_type":"Bearer"}`))
+	})
+	mux.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer ya29.token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/v1/projects/my-project/secrets/es/versions/latest:access":
+			// {"xpack.notification.slack.account.monitoring.secure_url":"https://hooks.slack.com/services/T0"}
+			_, _ = w.Write([]byte(`{"name":"projects/1/secrets/es/versions/3","payload":{"data":"eyJ4cGFjay5ub3RpZmljYXRpb24uc2xhY2suYWNjb3VudC5tb25pdG9yaW5nLnNlY3VyZV91cmwiOiJodHRwczovL2hvb2tzLnNsYWNrLmNvbS9zZXJ2aWNlcy9UMCJ9"}}`))
+		case "/v1/projects/my-project/secrets/es/versions/2:access":
+			_, _ = w.Write([]byte(`{"name":"projects/1/secrets/es/versions/2","payload":{"data":"cGFzc3dvcmQ="}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Secret not found","status":"NOT_FOUND"}}`))
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newGCPProvider(server.Client())
+	p.metadataURL = server.URL + "/token"
+	p.secretsURL = server.URL + "/v1/"
+	p.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	data, err := p.Fetch(context.Background(), "projects/my-project/secrets/es")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"xpack.notification.slack.account.monitoring.secure_url": []byte("https://hooks.slack.com/services/T0")}, data)
+
+	data, err = p.Fetch(context.Background(), "/projects/my-project/secrets/es/versions/2")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{ValueKey: []byte("password")}, data)
+
+	_, err = p.Fetch(context.Background(), "projects/my-project/secrets/unknown")
+	require.ErrorContains(t, err, "Secret not found")
+
+	// the access token is reused until it expires
+	require.Equal(t, 1, tokens)
+}
@@ -0,0 +1,149 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// ValueKey is the key under which a secret that does not hold a JSON object is exposed.
+	ValueKey = "value"
+
+	httpTimeout = 30 * time.Second
+)
+
+// Provider fetches secrets from an external secret store.
+type Provider interface {
+	// Fetch returns the keys and values of the secret identified by the given path.
+	Fetch(ctx context.Context, path string) (map[string][]byte, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[commonv1.ExternalSecretProvider]Provider{}
+)
+
+// SetProviders enables the given external secret providers, configured from the environment of the operator.
+// External secrets referencing a provider that is not enabled cannot be fetched.
+func SetProviders(names []string) error {
+	enabled := make(map[commonv1.ExternalSecretProvider]Provider, len(names))
+	for _, name := range names {
+		p, err := newProvider(commonv1.ExternalSecretProvider(name))
+		if err != nil {
+			return err
+		}
+		enabled[commonv1.ExternalSecretProvider(name)] = p
+	}
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers = enabled
+	return nil
+}
+
+func newProvider(name commonv1.ExternalSecretProvider) (Provider, error) {
+	httpClient := &http.Client{Timeout: httpTimeout}
+	switch name {
+	case commonv1.VaultSecretProvider:
+		return newVaultProvider()
+	case commonv1.AWSSecretProvider:
+		return newAWSProvider(httpClient), nil
+	case commonv1.GCPSecretProvider:
+		return newGCPProvider(httpClient), nil
+	default:
+		return nil, fmt.Errorf("unknown external secret provider %q, supported providers are %s, %s and %s",
+			name, commonv1.VaultSecretProvider, commonv1.AWSSecretProvider, commonv1.GCPSecretProvider)
+	}
+}
+
+// getProvider returns the enabled provider with the given name.
+func getProvider(name commonv1.ExternalSecretProvider) (Provider, error) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	p, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("external secret provider %s is not enabled in the operator configuration", name)
+	}
+	return p, nil
+}
+
+// setProvider enables a single provider, for testing purposes.
+func setProvider(name commonv1.ExternalSecretProvider, p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = p
+}
+
+// fetch returns the keys and values of the given external secret, which must be within the path prefix of its provider
+// in the given namespace.
+func fetch(ctx context.Context, namespace string, source commonv1.ExternalSecretSource) (map[string][]byte, error) {
+	if err := source.CheckPathScope(namespace); err != nil {
+		return nil, err
+	}
+	p, err := getProvider(source.Provider)
+	if err != nil {
+		return nil, err
+	}
+	data, err := p.Fetch(ctx, source.Path)
+	if err != nil {
+		return nil, fmt.Errorf("while fetching secret %s from %s: %w", source.Path, source.Provider, err)
+	}
+	return data, nil
+}
+
+// refreshInterval returns the interval between two fetches of the given external secret, which cannot be less than
+// the minimum interval.
+func refreshInterval(source commonv1.ExternalSecretSource) time.Duration {
+	return max(source.RefreshIntervalOrDefault(), commonv1.MinExternalSecretRefreshInterval)
+}
+
+// toData converts a secret value into secret data: the fields of a JSON object are the keys of the secret,
+// any other value is available under the ValueKey key.
+func toData(value []byte) map[string][]byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(value, &fields); err != nil || fields == nil {
+		return map[string][]byte{ValueKey: value}
+	}
+	return fieldsToData(fields)
+}
+
+func fieldsToData(fields map[string]json.RawMessage) map[string][]byte {
+	data := make(map[string][]byte, len(fields))
+	for k, v := range fields {
+		var s string
+		if err := json.Unmarshal(v, &s); err == nil {
+			data[k] = []byte(s)
+			continue
+		}
+		data[k] = v
+	}
+	return data
+}
+
+// doRequest sends the request and returns the response body, or an error if the response status is not 2xx.
+func doRequest(c *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Host, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package external
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+// fakeProvider returns the secrets of a map, indexed by path.
+type fakeProvider struct {
+	secrets map[string]map[string][]byte
+	fetches int
+}
+
+func (f *fakeProvider) Fetch(_ context.Context, path string) (map[string][]byte, error) {
+	f.fetches++
+	data, ok := f.secrets[path]
+	if !ok {
+		return nil, errNotFound
+	}
+	return data, nil
+}
+
+var errNotFound = errors.New("secret not found")
+
+func TestSetProviders(t *testing.T) {
+	t.Setenv(gcpMetadataHostEnvVar, "metadata.test")
+	require.NoError(t, SetProviders([]string{"aws", "gcp"}))
+	_, err := getProvider(commonv1.AWSSecretProvider)
+	require.NoError(t, err)
+	_, err = getProvider(commonv1.GCPSecretProvider)
+	require.NoError(t, err)
+	_, err = getProvider(commonv1.VaultSecretProvider)
+	require.EqualError(t, err, "external secret provider vault is not enabled in the operator configuration")
+
+	require.EqualError(t, SetProviders([]string{"keepass"}), `unknown external secret provider "keepass", supported providers are vault, aws and gcp`)
+	t.Setenv(vaultAddrEnvVar, "")
+	require.EqualError(t, SetProviders([]string{"vault"}), "VAULT_ADDR must be set to enable the vault external secret provider")
+
+	require.NoError(t, SetProviders(nil))
+	_, err = getProvider(commonv1.AWSSecretProvider)
+	require.Error(t, err)
+}
+
+func Test_refreshInterval(t *testing.T) {
+	require.Equal(t, 5*time.Minute, refreshInterval(commonv1.ExternalSecretSource{}))
+	require.Equal(t, 10*time.Minute, refreshInterval(commonv1.ExternalSecretSource{RefreshInterval: &metav1.Duration{Duration: 10 * time.Minute}}))
+	require.Equal(t, time.Minute, refreshInterval(commonv1.ExternalSecretSource{RefreshInterval: &metav1.Duration{Duration: time.Second}}))
+}
+
+func Test_toData(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  map[string][]byte
+	}{
+		{
+			name:  "JSON object",
+			value: `{"s3.client.default.access_key":"AKIA","s3.client.default.secret_key":"secret","port":9200,"nested":{"a":"b"}}`,
+			want: map[string][]byte{
+				"s3.client.default.access_key": []byte("AKIA"),
+				"s3.client.default.secret_key": []byte("secret"),
+				"port":                         []byte("9200"),
+				"nested":                       []byte(`{"a":"b"}`),
+			},
+		},
+		{
+			name:  "plain value",
+			value: "my-password",
+			want:  map[string][]byte{ValueKey: []byte("my-password")},
+		},
+		{
+			name:  "JSON array",
+			value: `["a","b"]`,
+			want:  map[string][]byte{ValueKey: []byte(`["a","b"]`)},
+		},
+		{
+			name:  "JSON null",
+			value: `null`,
+			want:  map[string][]byte{ValueKey: []byte(`null`)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, toData([]byte(tt.value)))
+		})
+	}
+}
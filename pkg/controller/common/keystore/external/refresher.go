@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package external
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// DefaultRefreshPeriod is the default period at which the copies of external secrets are checked for a refresh.
+const DefaultRefreshPeriod = 30 * time.Second
+
+var log = ulog.Log.WithName("external-secrets")
+
+// Refresher periodically fetches the external secrets copied in Secrets, according to their refresh interval, and
+// updates the copies whose content changed. The resources using these copies are then reconciled through the watches
+// set on their secure settings.
+type Refresher struct {
+	client k8s.Client
+	period time.Duration
+	now    func() time.Time
+}
+
+// NewRefresher returns a Refresher checking the copies of external secrets at the given period.
+func NewRefresher(c k8s.Client, period time.Duration) *Refresher {
+	if period <= 0 {
+		period = DefaultRefreshPeriod
+	}
+	return &Refresher{client: c, period: period, now: time.Now}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable: only the leader refreshes the external secrets.
+func (r *Refresher) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable. It periodically refreshes the external secrets until the context is cancelled.
+func (r *Refresher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := r.refresh(ctx); err != nil {
+			log.Error(err, "Failed to list external secrets")
+		}
+	}
+}
+
+// refresh fetches the external secrets whose refresh interval elapsed. Only the metadata of the copies is listed, as
+// reading the full Secrets may not be served by the cache, the copies are then read when they must be updated.
+func (r *Refresher) refresh(ctx context.Context) error {
+	copies := k8s.NewMetadataList(corev1.SchemeGroupVersion.WithKind("Secret"))
+	if err := r.client.List(ctx, copies, client.MatchingLabels{SecretLabelName: "true"}); err != nil {
+		return err
+	}
+	for i := range copies.Items {
+		nsn := k8s.ExtractNamespacedName(&copies.Items[i])
+		source, err := sourceOf(copies.Items[i].ObjectMeta)
+		if err != nil {
+			log.Error(err, "Invalid external secret annotations", "namespace", nsn.Namespace, "secret_name", nsn.Name)
+			continue
+		}
+		if last, ok := lastFetch(nsn); ok && r.now().Before(last.Add(refreshInterval(source))) {
+			continue
+		}
+		var secret corev1.Secret
+		if err := r.client.Get(ctx, nsn, &secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				log.Error(err, "Failed to read external secret", "namespace", nsn.Namespace, "secret_name", nsn.Name)
+			}
+			continue
+		}
+		data, err := fetch(ctx, nsn.Namespace, source)
+		if err != nil {
+			log.Error(err, "Failed to refresh external secret", "namespace", nsn.Namespace, "secret_name", nsn.Name)
+			continue
+		}
+		recordFetch(nsn, r.now())
+		if reflect.DeepEqual(data, secret.Data) {
+			continue
+		}
+		log.Info("Updating external secret", "namespace", nsn.Namespace, "secret_name", nsn.Name)
+		secret.Data = data
+		if err := r.client.Update(ctx, &secret); err != nil {
+			log.Error(err, "Failed to update external secret", "namespace", nsn.Namespace, "secret_name", nsn.Name)
+		}
+	}
+	return nil
+}
+
+// sourceOf returns the external secret copied in the Secret with the given metadata.
+func sourceOf(secret metav1.ObjectMeta) (commonv1.ExternalSecretSource, error) {
+	source := commonv1.ExternalSecretSource{
+		Provider: commonv1.ExternalSecretProvider(secret.Annotations[ProviderAnnotationName]),
+		Path:     secret.Annotations[PathAnnotationName],
+	}
+	if interval, ok := secret.Annotations[RefreshIntervalAnnotationName]; ok {
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			return source, err
+		}
+		source.RefreshInterval = &metav1.Duration{Duration: d}
+	}
+	return source, nil
+}
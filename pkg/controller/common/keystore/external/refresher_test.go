@@ -0,0 +1,84 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package external
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestRefresher_refresh(t *testing.T) {
+	provider := &fakeProvider{secrets: map[string]map[string][]byte{
+		"eck/ns/es-s3": {"s3.client.default.secret_key": []byte("rotated")},
+	}}
+	setProvider(commonv1.AWSSecretProvider, provider)
+	defer func() { require.NoError(t, SetProviders(nil)) }()
+	require.NoError(t, commonv1.SetExternalSecretPathPrefixes(map[commonv1.ExternalSecretProvider]string{commonv1.AWSSecretProvider: "eck/{namespace}/"}))
+	defer func() { require.NoError(t, commonv1.SetExternalSecretPathPrefixes(nil)) }()
+
+	nsn := types.NamespacedName{Namespace: "ns", Name: "es-es-external-secret-1"}
+	defer lastFetches.Delete(nsn)
+	c := k8s.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nsn.Namespace,
+			Name:      nsn.Name,
+			Labels:    map[string]string{SecretLabelName: "true"},
+			Annotations: map[string]string{
+				ProviderAnnotationName:        "aws",
+				PathAnnotationName:            "eck/ns/es-s3",
+				RefreshIntervalAnnotationName: "10m0s",
+			},
+		},
+		Data: map[string][]byte{"s3.client.default.secret_key": []byte("initial")},
+	})
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	r := NewRefresher(c, 0)
+	r.now = func() time.Time { return now }
+	getData := func() map[string][]byte {
+		var secret corev1.Secret
+		require.NoError(t, c.Get(context.Background(), nsn, &secret))
+		return secret.Data
+	}
+
+	// the external secret is fetched if it was never fetched by this operator instance
+	require.NoError(t, r.refresh(context.Background()))
+	require.Equal(t, 1, provider.fetches)
+	require.Equal(t, map[string][]byte{"s3.client.default.secret_key": []byte("rotated")}, getData())
+
+	// and not fetched again before the refresh interval has elapsed
+	now = now.Add(9 * time.Minute)
+	require.NoError(t, r.refresh(context.Background()))
+	require.Equal(t, 1, provider.fetches)
+
+	now = now.Add(time.Minute)
+	provider.secrets["eck/ns/es-s3"] = map[string][]byte{"s3.client.default.secret_key": []byte("rotated-again")}
+	require.NoError(t, r.refresh(context.Background()))
+	require.Equal(t, 2, provider.fetches)
+	require.Equal(t, map[string][]byte{"s3.client.default.secret_key": []byte("rotated-again")}, getData())
+
+	// failures to fetch the external secret keep the current content
+	now = now.Add(10 * time.Minute)
+	delete(provider.secrets, "eck/ns/es-s3")
+	require.NoError(t, r.refresh(context.Background()))
+	require.Equal(t, 3, provider.fetches)
+	require.Equal(t, map[string][]byte{"s3.client.default.secret_key": []byte("rotated-again")}, getData())
+
+	// external secrets outside of the path prefix of the namespace are not fetched
+	require.NoError(t, commonv1.SetExternalSecretPathPrefixes(map[commonv1.ExternalSecretProvider]string{commonv1.AWSSecretProvider: "eck/{namespace}/restricted/"}))
+	now = now.Add(10 * time.Minute)
+	provider.secrets["eck/ns/es-s3"] = map[string][]byte{"s3.client.default.secret_key": []byte("out-of-scope")}
+	require.NoError(t, r.refresh(context.Background()))
+	require.Equal(t, 3, provider.fetches)
+	require.Equal(t, map[string][]byte{"s3.client.default.secret_key": []byte("rotated-again")}, getData())
+}
@@ -0,0 +1,166 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package external
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// SecretLabelName marks the Secrets holding a copy of an external secret.
+	SecretLabelName = "common.k8s.elastic.co/external-secret"
+	// ProviderAnnotationName is the provider of the external secret copied in a Secret.
+	ProviderAnnotationName = "common.k8s.elastic.co/external-secret-provider"
+	// PathAnnotationName is the path of the external secret copied in a Secret.
+	PathAnnotationName = "common.k8s.elastic.co/external-secret-path"
+	// RefreshIntervalAnnotationName is the interval between two fetches of the external secret copied in a Secret.
+	RefreshIntervalAnnotationName = "common.k8s.elastic.co/external-secret-refresh-interval"
+
+	secretSuffix = "external-secret"
+)
+
+// lastFetches records when the external secret copied in each Secret was last fetched.
+var lastFetches sync.Map
+
+func recordFetch(secret types.NamespacedName, t time.Time) {
+	lastFetches.Store(secret, t)
+}
+
+func lastFetch(secret types.NamespacedName) (time.Time, bool) {
+	t, ok := lastFetches.Load(secret)
+	if !ok {
+		return time.Time{}, false
+	}
+	return t.(time.Time), true //nolint:forcetypeassert
+}
+
+// SecretName returns the name of the Secret holding a copy of the given external secret for the given resource.
+func SecretName(namer name.Namer, ownerName string, source commonv1.ExternalSecretSource) string {
+	return namer.Suffix(ownerName, secretSuffix, hash.HashObject([]string{string(source.Provider), source.Path}))
+}
+
+// ReconcileSecrets copies the external secrets referenced in the given sources into Secrets owned by the given
+// resource, removes the copies that are not referenced anymore, and returns the sources referencing the copies in
+// place of the external secrets. Sources referencing Kubernetes Secrets are ignored.
+// External secrets are fetched if their copy does not exist yet, they are then periodically refreshed by the Refresher.
+func ReconcileSecrets(
+	ctx context.Context,
+	c k8s.Client,
+	recorder record.EventRecorder,
+	owner client.Object,
+	namer name.Namer,
+	labels map[string]string,
+	sources []commonv1.SecretSource,
+) ([]commonv1.NamespacedSecretSource, error) {
+	var result []commonv1.NamespacedSecretSource
+	expected := make(map[string]struct{})
+	for _, source := range sources {
+		if !source.IsExternal() {
+			continue
+		}
+		secretName := SecretName(namer, owner.GetName(), *source.External)
+		if err := reconcileSecret(ctx, c, owner, secretName, labels, *source.External); err != nil {
+			recorder.Event(owner, corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("Failed to fetch external secret: %s", err.Error()))
+			return nil, err
+		}
+		expected[secretName] = struct{}{}
+		result = append(result, commonv1.NamespacedSecretSource{
+			Namespace:  owner.GetNamespace(),
+			SecretName: secretName,
+			Entries:    source.Entries,
+		})
+	}
+	return result, garbageCollect(ctx, c, owner, expected)
+}
+
+func reconcileSecret(
+	ctx context.Context,
+	c k8s.Client,
+	owner client.Object,
+	secretName string,
+	labels map[string]string,
+	source commonv1.ExternalSecretSource,
+) error {
+	nsn := types.NamespacedName{Namespace: owner.GetNamespace(), Name: secretName}
+	// an existing copy is not used anymore if the path prefixes of the operator configuration no longer allow it
+	if err := source.CheckPathScope(nsn.Namespace); err != nil {
+		return err
+	}
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nsn.Name,
+			Namespace: nsn.Namespace,
+			Labels:    maps.Merge(map[string]string{SecretLabelName: "true"}, labels),
+			Annotations: map[string]string{
+				ProviderAnnotationName:        string(source.Provider),
+				PathAnnotationName:            source.Path,
+				RefreshIntervalAnnotationName: refreshInterval(source).String(),
+			},
+		},
+	}
+
+	var actual corev1.Secret
+	err := c.Get(ctx, nsn, &actual)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && actual.Annotations[ProviderAnnotationName] == string(source.Provider) && actual.Annotations[PathAnnotationName] == source.Path {
+		// the external secret is already copied, keep its content up to date with the refresh interval
+		expected.Data = actual.Data
+		_, err := reconciler.ReconcileSecret(ctx, c, expected, owner)
+		return err
+	}
+
+	data, err := fetch(ctx, nsn.Namespace, source)
+	if err != nil {
+		return err
+	}
+	recordFetch(nsn, time.Now())
+	expected.Data = data
+	_, err = reconciler.ReconcileSecret(ctx, c, expected, owner)
+	return err
+}
+
+// garbageCollect deletes the copies of external secrets owned by the given resource that are not expected anymore.
+func garbageCollect(ctx context.Context, c k8s.Client, owner client.Object, expected map[string]struct{}) error {
+	copies := k8s.NewMetadataList(corev1.SchemeGroupVersion.WithKind("Secret"))
+	if err := c.List(ctx, copies, client.InNamespace(owner.GetNamespace()), client.MatchingLabels{SecretLabelName: "true"}); err != nil {
+		return err
+	}
+	for i := range copies.Items {
+		secret := corev1.Secret{ObjectMeta: copies.Items[i].ObjectMeta}
+		if _, ok := expected[secret.Name]; ok || !isControlledBy(&secret, owner) {
+			continue
+		}
+		if err := c.Delete(ctx, &secret); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		lastFetches.Delete(k8s.ExtractNamespacedName(&secret))
+	}
+	return nil
+}
+
+func isControlledBy(secret metav1.Object, owner client.Object) bool {
+	ref := metav1.GetControllerOf(secret)
+	return ref != nil && ref.UID == owner.GetUID()
+}
@@ -0,0 +1,108 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package external
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+var kbNamer = name.NewNamer("kb")
+
+func TestReconcileSecrets(t *testing.T) {
+	provider := &fakeProvider{secrets: map[string]map[string][]byte{
+		"secret/data/ns/kibana": {"elasticsearch.password": []byte("changeme")},
+		"secret/data/ns/other":  {"xpack.security.encryptionKey": []byte("key")},
+	}}
+	setProvider(commonv1.VaultSecretProvider, provider)
+	defer func() { require.NoError(t, SetProviders(nil)) }()
+	require.NoError(t, commonv1.SetExternalSecretPathPrefixes(map[commonv1.ExternalSecretProvider]string{commonv1.VaultSecretProvider: "secret/data/{namespace}/"}))
+	defer func() { require.NoError(t, commonv1.SetExternalSecretPathPrefixes(nil)) }()
+
+	vault := commonv1.ExternalSecretSource{Provider: commonv1.VaultSecretProvider, Path: "secret/data/ns/kibana"}
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kibana", UID: "kb-uid"},
+		Spec: kbv1.KibanaSpec{SecureSettings: []commonv1.SecretSource{
+			{SecretName: "user-secret"},
+			{External: &vault, Entries: []commonv1.KeyToPath{{Key: "elasticsearch.password"}}},
+		}},
+	}
+	secretName := SecretName(kbNamer, kb.Name, vault)
+	otherOwner := metav1.OwnerReference{APIVersion: "kibana.k8s.elastic.co/v1", Kind: "Kibana", Name: "other", UID: "other-uid", Controller: ptr.To(true)}
+	c := k8s.NewFakeClient(
+		// copy of an external secret not referenced anymore
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns", Name: "kibana-kb-external-secret-1", Labels: map[string]string{SecretLabelName: "true"},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "kibana.k8s.elastic.co/v1", Kind: "Kibana", Name: "kibana", UID: "kb-uid", Controller: ptr.To(true)}},
+		}},
+		// copy of an external secret owned by another resource
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns", Name: "other-kb-external-secret-1", Labels: map[string]string{SecretLabelName: "true"},
+			OwnerReferences: []metav1.OwnerReference{otherOwner},
+		}},
+	)
+	recorder := record.NewFakeRecorder(10)
+
+	sources, err := ReconcileSecrets(context.Background(), c, recorder, &kb, kbNamer, map[string]string{"kibana.k8s.elastic.co/name": "kibana"}, kb.Spec.SecureSettings)
+	require.NoError(t, err)
+	require.Equal(t, []commonv1.NamespacedSecretSource{
+		{Namespace: "ns", SecretName: secretName, Entries: []commonv1.KeyToPath{{Key: "elasticsearch.password"}}},
+	}, sources)
+	require.Equal(t, 1, provider.fetches)
+
+	var secret corev1.Secret
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: secretName}, &secret))
+	require.Equal(t, map[string][]byte{"elasticsearch.password": []byte("changeme")}, secret.Data)
+	require.Equal(t, map[string]string{SecretLabelName: "true", "kibana.k8s.elastic.co/name": "kibana"}, secret.Labels)
+	require.Equal(t, map[string]string{
+		ProviderAnnotationName:        "vault",
+		PathAnnotationName:            "secret/data/ns/kibana",
+		RefreshIntervalAnnotationName: "5m0s",
+	}, secret.Annotations)
+	require.Equal(t, types.UID("kb-uid"), metav1.GetControllerOf(&secret).UID)
+
+	// the copy not referenced anymore is deleted, not the copy owned by another resource
+	require.Error(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "kibana-kb-external-secret-1"}, &corev1.Secret{}))
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "other-kb-external-secret-1"}, &corev1.Secret{}))
+
+	// the external secret is not fetched again if already copied
+	_, err = ReconcileSecrets(context.Background(), c, recorder, &kb, kbNamer, nil, kb.Spec.SecureSettings)
+	require.NoError(t, err)
+	require.Equal(t, 1, provider.fetches)
+
+	// the external secret is fetched if its path changes
+	kb.Spec.SecureSettings[1].External.Path = "secret/data/ns/other"
+	sources, err = ReconcileSecrets(context.Background(), c, recorder, &kb, kbNamer, nil, kb.Spec.SecureSettings)
+	require.NoError(t, err)
+	require.Equal(t, 2, provider.fetches)
+	require.Len(t, sources, 1)
+	require.NotEqual(t, secretName, sources[0].SecretName)
+	require.Error(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: secretName}, &corev1.Secret{}))
+
+	// a failure to fetch the external secret is reported
+	kb.Spec.SecureSettings[1].External.Path = "secret/data/ns/unknown"
+	_, err = ReconcileSecrets(context.Background(), c, recorder, &kb, kbNamer, nil, kb.Spec.SecureSettings)
+	require.EqualError(t, err, "while fetching secret secret/data/ns/unknown from vault: secret not found")
+	require.Equal(t, "Warning Unexpected Failed to fetch external secret: while fetching secret secret/data/ns/unknown from vault: secret not found", <-recorder.Events)
+
+	// external secrets outside of the path prefix of the namespace are not fetched
+	kb.Spec.SecureSettings[1].External.Path = "secret/data/other-ns/kibana"
+	provider.secrets["secret/data/other-ns/kibana"] = map[string][]byte{"elasticsearch.password": []byte("other")}
+	_, err = ReconcileSecrets(context.Background(), c, recorder, &kb, kbNamer, nil, kb.Spec.SecureSettings)
+	require.EqualError(t, err, "path secret/data/other-ns/kibana is not allowed in namespace ns, it must start with secret/data/ns/")
+	require.Equal(t, 3, provider.fetches)
+}
@@ -0,0 +1,132 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/hashicorp/vault/api"
+)
+
+const (
+	vaultAddrEnvVar           = "VAULT_ADDR"
+	vaultTokenEnvVar          = "VAULT_TOKEN"
+	vaultKubernetesRoleEnvVar = "VAULT_KUBERNETES_ROLE"
+	vaultKubernetesPathEnvVar = "VAULT_KUBERNETES_AUTH_PATH"
+
+	defaultVaultKubernetesPath = "kubernetes"
+	serviceAccountTokenPath    = "/var/run/secrets/kubernetes.io/serviceaccount/token" //nolint:gosec
+)
+
+// vaultProvider reads KV secrets from HashiCorp Vault. It authenticates with the token set in VAULT_TOKEN or, if
+// VAULT_KUBERNETES_ROLE is set, with the Kubernetes auth method and the service account token of the operator.
+type vaultProvider struct {
+	mu       sync.Mutex
+	client   *api.Client
+	role     string
+	authPath string
+	jwt      func() ([]byte, error)
+}
+
+func newVaultProvider() (*vaultProvider, error) {
+	if os.Getenv(vaultAddrEnvVar) == "" {
+		return nil, fmt.Errorf("%s must be set to enable the vault external secret provider", vaultAddrEnvVar)
+	}
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	role := os.Getenv(vaultKubernetesRoleEnvVar)
+	if role == "" && client.Token() == "" {
+		return nil, fmt.Errorf("%s or %s must be set to enable the vault external secret provider", vaultTokenEnvVar, vaultKubernetesRoleEnvVar)
+	}
+	authPath := os.Getenv(vaultKubernetesPathEnvVar)
+	if authPath == "" {
+		authPath = defaultVaultKubernetesPath
+	}
+	return &vaultProvider{
+		client:   client,
+		role:     role,
+		authPath: authPath,
+		jwt:      func() ([]byte, error) { return os.ReadFile(serviceAccountTokenPath) },
+	}, nil
+}
+
+func (v *vaultProvider) Fetch(ctx context.Context, path string) (map[string][]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.client.Token() == "" {
+		if err := v.login(ctx); err != nil {
+			return nil, err
+		}
+	}
+	secret, err := v.client.Logical().ReadWithContext(ctx, path)
+	if v.role != "" && isPermissionDenied(err) {
+		// the token obtained with the Kubernetes auth method may have expired, log in again
+		if err := v.login(ctx); err != nil {
+			return nil, err
+		}
+		secret, err = v.client.Logical().ReadWithContext(ctx, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("secret %s not found", path)
+	}
+	return vaultData(secret.Data)
+}
+
+func (v *vaultProvider) login(ctx context.Context) error {
+	if v.role == "" {
+		return fmt.Errorf("%s is not set", vaultTokenEnvVar)
+	}
+	jwt, err := v.jwt()
+	if err != nil {
+		return fmt.Errorf("while reading the operator service account token: %w", err)
+	}
+	resp, err := v.client.Logical().WriteWithContext(ctx, fmt.Sprintf("auth/%s/login", v.authPath), map[string]interface{}{
+		"role": v.role,
+		"jwt":  string(jwt),
+	})
+	if err != nil {
+		return fmt.Errorf("while logging into vault with role %s: %w", v.role, err)
+	}
+	if resp == nil || resp.Auth == nil {
+		return errors.New("while logging into vault: no auth info in response")
+	}
+	v.client.SetToken(resp.Auth.ClientToken)
+	return nil
+}
+
+func isPermissionDenied(err error) bool {
+	var respErr *api.ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusForbidden
+}
+
+// vaultData returns the fields of a secret read from Vault, unwrapping the data of KV version 2 secrets.
+func vaultData(data map[string]interface{}) (map[string][]byte, error) {
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, isKVv2 := data["metadata"]; isKVv2 {
+			data = nested
+		}
+	}
+	fields := make(map[string]json.RawMessage, len(data))
+	for k, v := range data {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		fields[k] = raw
+	}
+	return fieldsToData(fields), nil
+}
@@ -0,0 +1,75 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_vaultProvider_Fetch(t *testing.T) {
+	logins := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/kubernetes/login", func(w http.ResponseWriter, r *http.Request) {
+		logins++
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, map[string]string{"role": "eck-operator", "jwt": "service-account-token"}, body)
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"s.token"}}`))
+	})
+	mux.HandleFunc("/v1/secret/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/es":
+			_, _ = w.Write([]byte(`{"data":{"data":{"gcs.client.default.credentials_file":"{\"type\":\"service_account\"}"},"metadata":{"version":2}}}`))
+		case "/v1/secret/kv1/es":
+			_, _ = w.Write([]byte(`{"data":{"password":"changeme","port":9200}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL, HttpClient: server.Client()})
+	require.NoError(t, err)
+	client.ClearToken()
+	p := &vaultProvider{
+		client:   client,
+		role:     "eck-operator",
+		authPath: defaultVaultKubernetesPath,
+		jwt:      func() ([]byte, error) { return []byte("service-account-token"), nil },
+	}
+
+	data, err := p.Fetch(context.Background(), "secret/data/es")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"gcs.client.default.credentials_file": []byte(`{"type":"service_account"}`)}, data)
+	require.Equal(t, 1, logins)
+
+	data, err = p.Fetch(context.Background(), "secret/kv1/es")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"password": []byte("changeme"), "port": []byte("9200")}, data)
+	require.Equal(t, 1, logins)
+
+	// log in again if the token expired
+	client.SetToken("s.expired")
+	_, err = p.Fetch(context.Background(), "secret/data/es")
+	require.NoError(t, err)
+	require.Equal(t, 2, logins)
+
+	_, err = p.Fetch(context.Background(), "secret/data/unknown")
+	require.EqualError(t, err, "secret secret/data/unknown not found")
+}
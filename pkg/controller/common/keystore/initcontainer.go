@@ -6,6 +6,7 @@ package keystore
 
 import (
 	"bytes"
+	"regexp"
 	"text/template"
 
 	corev1 "k8s.io/api/core/v1"
@@ -36,6 +37,11 @@ type InitContainerParameters struct {
 	SkipInitializedFlag bool
 	// SecurityContext is the security context applied to the keystore container.
 	SecurityContext *corev1.SecurityContext
+	// KeystoreFile is the path of the keystore file, removed to recreate the keystore in place when secure settings are
+	// reloaded.
+	KeystoreFile string
+	// ReloadableSettings matches the secure settings the application can reload without being restarted.
+	ReloadableSettings []*regexp.Regexp
 }
 
 // script is a small bash script to create an Elastic Stack keystore,
@@ -0,0 +1,101 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package keystore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sort"
+	"text/template"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+)
+
+// updateScript is a small bash script to recreate an Elastic Stack keystore in place, from the secure settings
+// streamed on its standard input as one line per entry with the setting name followed by its base64 encoded value.
+// Unlike the init container script, it does not trace the commands, not to print the values of the settings.
+const updateScript = `#!/usr/bin/env bash
+
+set -eu
+
+secure_settings=$(mktemp -d)
+trap 'rm -rf "${secure_settings}"' EXIT
+
+# decode the secure settings entries from the standard input
+while read -r key value; do
+	[[ -n "$key" ]] || continue
+	base64 -d <<< "$value" > "${secure_settings}/${key}"
+done
+
+echo "Updating keystore."
+
+# recreate the keystore so that removed entries are removed from it as well
+rm -f {{ .KeystoreFile }}
+{{ .KeystoreCreateCommand }}
+
+for filename in "${secure_settings}"/*; do
+	[[ -e "$filename" ]] || continue # glob does not match
+	key=$(basename "$filename")
+	echo "Adding "$key" to the keystore."
+	{{ .KeystoreAddCommand }}
+done
+
+echo "Keystore update successful."
+`
+
+var updateScriptTemplate = template.Must(template.New("").Parse(updateScript))
+
+// UpdateCommand returns the command to run in the application container to recreate its keystore in place, from the
+// secure settings returned by UpdateInput.
+func UpdateCommand(parameters InitContainerParameters) ([]string, error) {
+	if parameters.KeystoreFile == "" {
+		return nil, fmt.Errorf("the keystore file is required to update the keystore in place")
+	}
+	tplBuffer := bytes.Buffer{}
+	if err := updateScriptTemplate.Execute(&tplBuffer, parameters); err != nil {
+		return nil, err
+	}
+	return []string{"/usr/bin/env", "bash", "-c", tplBuffer.String()}, nil
+}
+
+// UpdateInput returns the standard input of the command returned by UpdateCommand, to load the given secure settings.
+func UpdateInput(data map[string][]byte) io.Reader {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	input := bytes.Buffer{}
+	for _, k := range keys {
+		input.WriteString(k + " " + base64.StdEncoding.EncodeToString(data[k]) + "\n")
+	}
+	return &input
+}
+
+// IsReloadable returns true if the application can reload the given secure setting without being restarted.
+func (p InitContainerParameters) IsReloadable(setting string) bool {
+	for _, reloadable := range p.ReloadableSettings {
+		if reloadable.MatchString(setting) {
+			return true
+		}
+	}
+	return false
+}
+
+// restartHash returns the hash of the secure settings that cannot be reloaded by the application. It is equal to the
+// hash of all the secure settings if none of them can be reloaded.
+func restartHash(data map[string][]byte, parameters InitContainerParameters) string {
+	restartData := make(map[string][]byte, len(data))
+	for k, v := range data {
+		if parameters.IsReloadable(k) {
+			continue
+		}
+		restartData[k] = v
+	}
+	return hash.HashObject(restartData)
+}
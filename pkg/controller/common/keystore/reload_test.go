@@ -0,0 +1,69 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package keystore
+
+import (
+	"io"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateCommand(t *testing.T) {
+	params := fakeFlagInitContainersParameters(false)
+	_, err := UpdateCommand(params)
+	require.Error(t, err, "the keystore file is required")
+
+	params.KeystoreFile = "/bar/data/app.keystore"
+	command, err := UpdateCommand(params)
+	require.NoError(t, err)
+	require.Equal(t, []string{"/usr/bin/env", "bash", "-c", `#!/usr/bin/env bash
+
+set -eu
+
+secure_settings=$(mktemp -d)
+trap 'rm -rf "${secure_settings}"' EXIT
+
+# decode the secure settings entries from the standard input
+while read -r key value; do
+	[[ -n "$key" ]] || continue
+	base64 -d <<< "$value" > "${secure_settings}/${key}"
+done
+
+echo "Updating keystore."
+
+# recreate the keystore so that removed entries are removed from it as well
+rm -f /bar/data/app.keystore
+/keystore/bin/keystore create
+
+for filename in "${secure_settings}"/*; do
+	[[ -e "$filename" ]] || continue # glob does not match
+	key=$(basename "$filename")
+	echo "Adding "$key" to the keystore."
+	/keystore/bin/keystore add "$key" "$filename"
+done
+
+echo "Keystore update successful."
+`}, command)
+}
+
+func TestUpdateInput(t *testing.T) {
+	input, err := io.ReadAll(UpdateInput(map[string][]byte{
+		"s3.client.default.secret_key": []byte("secret"),
+		"s3.client.default.access_key": []byte("access"),
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "s3.client.default.access_key YWNjZXNz\ns3.client.default.secret_key c2VjcmV0\n", string(input))
+}
+
+func TestInitContainerParameters_IsReloadable(t *testing.T) {
+	params := InitContainerParameters{
+		ReloadableSettings: []*regexp.Regexp{regexp.MustCompile(`^s3\.client\.[^.]+\.(access_key|secret_key)$`)},
+	}
+	require.True(t, params.IsReloadable("s3.client.default.access_key"))
+	require.False(t, params.IsReloadable("s3.client.default.proxy.password"))
+	require.False(t, InitContainerParameters{}.IsReloadable("s3.client.default.access_key"))
+}
@@ -13,6 +13,7 @@ import (
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
 )
 
@@ -24,6 +25,10 @@ type Resources struct {
 	InitContainer corev1.Container
 	// hash of the secret data provided by the user
 	Hash string
+	// hash of the secret data provided by the user that cannot be reloaded without restarting the application
+	RestartHash string
+	// secret data provided by the user, to update the keystore in place when it can be reloaded
+	Data map[string][]byte
 }
 
 // HasKeystore interface represents an Elastic Stack application that offers a keystore which in ECK
@@ -35,9 +40,13 @@ type HasKeystore interface {
 }
 
 // WatchedSecretNames returns the name of all secure settings secrets to watch.
+// Secure settings held by an external secret store are not included.
 func WatchedSecretNames(hasKeystore HasKeystore) []commonv1.NamespacedSecretSource {
 	nsns := make([]commonv1.NamespacedSecretSource, 0, len(hasKeystore.SecureSettings()))
 	for _, s := range hasKeystore.SecureSettings() {
+		if s.IsExternal() {
+			continue
+		}
 		nsns = append(nsns, commonv1.NamespacedSecretSource{
 			Namespace:  hasKeystore.GetNamespace(),
 			SecretName: s.SecretName,
@@ -60,7 +69,7 @@ func ReconcileResources(
 	initContainerParams InitContainerParameters,
 ) (*Resources, error) {
 	// setup a volume from the user-provided secure settings secret
-	secretVolume, hash, err := secureSettingsVolume(ctx, r, hasKeystore, labels, namer)
+	secretVolume, data, err := secureSettingsVolume(ctx, r, hasKeystore, labels, namer)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +87,9 @@ func ReconcileResources(
 	return &Resources{
 		Volume:        secretVolume.Volume(),
 		InitContainer: initContainer,
-		Hash:          hash,
+		// secret data hash will be included in pod labels to recreate pods on any secret change
+		Hash:        hash.HashObject(data),
+		RestartHash: restartHash(data, initContainerParams),
+		Data:        data,
 	}, nil
 }
@@ -6,6 +6,7 @@ package keystore
 
 import (
 	"context"
+	"regexp"
 	"testing"
 
 	"github.com/magiconair/properties/assert"
@@ -116,6 +117,7 @@ func TestReconcileResources(t *testing.T) {
 		wantNil                 bool
 		wantContainers          *corev1.Container
 		wantHash                string
+		wantRestartHash         string
 	}{
 		{
 			name:                    "no secure settings specified: no resources",
@@ -159,8 +161,9 @@ touch /bar/data/elastic-internal-init-keystore.ok
 echo "Keystore initialization successful."
 `),
 			// since this will be created, it will be incremented
-			wantHash: "896069204",
-			wantNil:  false,
+			wantHash:        "896069204",
+			wantRestartHash: "896069204",
+			wantNil:         false,
 		},
 		{
 			name:                    "Skip create keystore flag",
@@ -187,8 +190,9 @@ done
 echo "Keystore initialization successful."
 `),
 			// since this will be created, it will be incremented
-			wantHash: "896069204",
-			wantNil:  false,
+			wantHash:        "896069204",
+			wantRestartHash: "896069204",
+			wantNil:         false,
 		},
 		{
 			name:           "secure settings specified but secret not there: no resources",
@@ -209,8 +213,23 @@ echo "Keystore initialization successful."
 			},
 			wantContainers: wantContainer(`echo "custom script"`),
 			// since this will be created, it will be incremented
-			wantHash: "896069204",
-			wantNil:  false,
+			wantHash:        "896069204",
+			wantRestartHash: "896069204",
+			wantNil:         false,
+		},
+		{
+			name:   "reloadable secure settings: do not restart on change",
+			client: k8s.NewFakeClient(&testSecureSettingsSecret),
+			kb:     testKibanaWithSecureSettings,
+			initContainerParameters: InitContainerParameters{
+				CustomScript:       `echo "custom script"`,
+				Resources:          testResourceRequirements,
+				ReloadableSettings: []*regexp.Regexp{regexp.MustCompile(`^key\d$`)},
+			},
+			wantContainers:  wantContainer(`echo "custom script"`),
+			wantHash:        "896069204",
+			wantRestartHash: "2523017803",
+			wantNil:         false,
 		},
 	}
 	for _, tt := range tests {
@@ -232,6 +251,8 @@ echo "Keystore initialization successful."
 				assert.Equal(t, resources.InitContainer.SecurityContext, tt.wantContainers.SecurityContext)
 				assert.Equal(t, resources.InitContainer.Resources, tt.wantContainers.Resources)
 				assert.Equal(t, resources.Hash, tt.wantHash)
+				assert.Equal(t, resources.RestartHash, tt.wantRestartHash)
+				assert.Equal(t, resources.Data, testSecureSettingsSecret.Data)
 			}
 		})
 	}
@@ -18,11 +18,13 @@ import (
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore/external"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/remoteclusterlink"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/snapshotrepository"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/stackconfigpolicy"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -36,26 +38,68 @@ const secureSettingsSecretSuffix = "secure-settings"
 // The user provided secrets are then aggregated into a single secret.
 // This secret is mounted into the pods for secure settings to be injected into a keystore.
 // The user-provided secrets are watched to reconcile on any change.
-// The user secret resource version is returned along with the volume, so that
-// any change in the user secret leads to pod rotation.
+// The aggregated secret data is returned along with the volume, so that
+// any change in the user secret leads to pod rotation or to a keystore reload.
 func secureSettingsVolume(
 	ctx context.Context,
 	r driver.Interface,
 	hasKeystore HasKeystore,
 	labels map[string]string,
 	namer name.Namer,
-) (*volume.SecretVolume, string, error) {
+) (*volume.SecretVolume, map[string][]byte, error) {
+	secureSettingsSecret, err := ReconcileSecureSettingsSecret(ctx, r, hasKeystore, labels, namer)
+	if err != nil || secureSettingsSecret == nil {
+		return nil, nil, err
+	}
+
+	// build a volume from that secret
+	secureSettingsVolume := volume.NewSecretVolumeWithMountPath(
+		secureSettingsSecret.Name,
+		SecureSettingsVolumeName,
+		SecureSettingsVolumeMountPath,
+	)
+
+	return &secureSettingsVolume, secureSettingsSecret.Data, nil
+}
+
+// ReconcileSecureSettingsSecret aggregates the user-provided secure settings secrets into a single secret, and returns
+// it, or nil if there are no secure settings. The user-provided secrets are watched to reconcile on any change.
+func ReconcileSecureSettingsSecret(
+	ctx context.Context,
+	r driver.Interface,
+	hasKeystore HasKeystore,
+	labels map[string]string,
+	namer name.Namer,
+) (*corev1.Secret, error) {
 	// setup (or remove) watches for the user-provided secret to reconcile on any change
 	watcher := k8s.ExtractNamespacedName(hasKeystore)
 
 	// user-provided Secrets referenced in the resource
 	secretSources := WatchedSecretNames(hasKeystore)
+	// operator-managed copies of the external secrets referenced in the resource
+	externalSecretSources, err := external.ReconcileSecrets(ctx, r.K8sClient(), r.Recorder(), hasKeystore, namer, labels, hasKeystore.SecureSettings())
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "fail to reconcile external secure settings")
+	}
+	secretSources = append(secretSources, externalSecretSources...)
 	// user-provided Secrets referenced in a StackConfigPolicy that configures the resource
 	policySecretSources, err := stackconfigpolicy.GetSecureSettingsSecretSourcesForResources(ctx, r.K8sClient(), hasKeystore, hasKeystore.GetObjectKind().GroupVersionKind().Kind)
 	if err != nil {
-		return nil, "", pkgerrors.Wrap(err, "fail to get secure settings secret sources")
+		return nil, pkgerrors.Wrap(err, "fail to get secure settings secret sources")
 	}
 	secretSources = append(secretSources, policySecretSources...)
+	// user-provided Secrets referenced in a SnapshotRepository registered on the resource
+	repositorySecretSources, err := snapshotrepository.GetSecureSettingsSecretSourcesForResources(ctx, r.K8sClient(), hasKeystore, hasKeystore.GetObjectKind().GroupVersionKind().Kind)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "fail to get snapshot repository secure settings secret sources")
+	}
+	secretSources = append(secretSources, repositorySecretSources...)
+	// exchange Secrets holding the cross-cluster API keys of the RemoteClusterLinks connecting the resource to a remote cluster
+	linkSecretSources, err := remoteclusterlink.GetSecureSettingsSecretSourcesForResources(ctx, r.K8sClient(), hasKeystore, hasKeystore.GetObjectKind().GroupVersionKind().Kind)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "fail to get remote cluster link secure settings secret sources")
+	}
+	secretSources = append(secretSources, linkSecretSources...)
 
 	if err := watches.WatchUserProvidedNamespacedSecrets(
 		watcher,
@@ -63,33 +107,15 @@ func secureSettingsVolume(
 		SecureSettingsWatchName(watcher),
 		secretSources,
 	); err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
 	userSecrets, err := retrieveUserSecrets(ctx, r.K8sClient(), r.Recorder(), hasKeystore, secretSources)
 	if err != nil {
-		return nil, "", err
-	}
-
-	secureSettingsSecret, err := reconcileSecureSettings(ctx, r.K8sClient(), hasKeystore, userSecrets, namer, labels)
-	if err != nil {
-		return nil, "", err
-	}
-	if secureSettingsSecret == nil {
-		return nil, "", nil
+		return nil, err
 	}
 
-	// build a volume from that secret
-	secureSettingsVolume := volume.NewSecretVolumeWithMountPath(
-		secureSettingsSecret.Name,
-		SecureSettingsVolumeName,
-		SecureSettingsVolumeMountPath,
-	)
-
-	// secret data hash will be included in pod labels to recreate pods on any secret change
-	secureSettingsSecretHash := hash.HashObject(secureSettingsSecret.Data)
-
-	return &secureSettingsVolume, secureSettingsSecretHash, nil
+	return reconcileSecureSettings(ctx, r.K8sClient(), hasKeystore, userSecrets, namer, labels)
 }
 
 func reconcileSecureSettings(
@@ -52,7 +52,7 @@ func Test_secureSettingsVolume(t *testing.T) {
 		w           watches.DynamicWatches
 		kb          kbv1.Kibana
 		wantVolume  *volume.SecretVolume
-		wantHash    string
+		wantData    map[string][]byte
 		wantWatches []string
 		wantEvent   string
 	}{
@@ -62,17 +62,15 @@ func Test_secureSettingsVolume(t *testing.T) {
 			w:           createWatches(""),
 			kb:          testKibana,
 			wantVolume:  nil,
-			wantHash:    "",
 			wantWatches: []string{},
 		},
 		{
-			name:       "valid secure settings specified: should add watch and return volume with version",
-			c:          k8s.NewFakeClient(&testSecureSettingsSecret),
-			w:          createWatches(""),
-			kb:         testKibanaWithSecureSettings,
-			wantVolume: &expectedSecretVolume,
-			// since this is being created the RV will increment
-			wantHash:    "896069204",
+			name:        "valid secure settings specified: should add watch and return volume with version",
+			c:           k8s.NewFakeClient(&testSecureSettingsSecret),
+			w:           createWatches(""),
+			kb:          testKibanaWithSecureSettings,
+			wantVolume:  &expectedSecretVolume,
+			wantData:    testSecureSettingsSecret.Data,
 			wantWatches: []string{SecureSettingsWatchName(k8s.ExtractNamespacedName(&testKibanaWithSecureSettings))},
 		},
 		{
@@ -81,7 +79,6 @@ func Test_secureSettingsVolume(t *testing.T) {
 			w:           createWatches(SecureSettingsWatchName(k8s.ExtractNamespacedName(&testKibanaWithSecureSettings))),
 			kb:          testKibanaWithSecureSettings,
 			wantVolume:  nil,
-			wantHash:    "",
 			wantWatches: []string{SecureSettingsWatchName(k8s.ExtractNamespacedName(&testKibanaWithSecureSettings))},
 			wantEvent:   "Warning Unexpected Secure settings secret not found: namespace/secure-settings-secret",
 		},
@@ -91,7 +88,6 @@ func Test_secureSettingsVolume(t *testing.T) {
 			w:           createWatches(SecureSettingsWatchName(k8s.ExtractNamespacedName(&testKibanaWithSecureSettings))),
 			kb:          testKibana,
 			wantVolume:  nil,
-			wantHash:    "",
 			wantWatches: []string{},
 		},
 	}
@@ -102,10 +98,10 @@ func Test_secureSettingsVolume(t *testing.T) {
 				Watches:      tt.w,
 				FakeRecorder: record.NewFakeRecorder(1000),
 			}
-			vol, hash, err := secureSettingsVolume(context.Background(), testDriver, &tt.kb, nil, kbNamer)
+			vol, data, err := secureSettingsVolume(context.Background(), testDriver, &tt.kb, nil, kbNamer)
 			require.NoError(t, err)
 			assert.Equal(t, tt.wantVolume, vol)
-			assert.Equal(t, tt.wantHash, hash)
+			assert.Equal(t, tt.wantData, data)
 
 			require.Equal(t, tt.wantWatches, tt.w.Secrets.Registrations())
 
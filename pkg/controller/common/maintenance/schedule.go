@@ -0,0 +1,150 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package maintenance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxScheduleSearch bounds the search of the next start of a schedule, to stop on schedules which never match such as
+// the 31st of February.
+const maxScheduleSearch = 5 * 366 * 24 * time.Hour
+
+// cronField is the range of values of a field of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteField     = cronField{name: "minute", min: 0, max: 59}
+	hourField       = cronField{name: "hour", min: 0, max: 23}
+	dayOfMonthField = cronField{name: "day of month", min: 1, max: 31}
+	monthField      = cronField{name: "month", min: 1, max: 12}
+	// both 0 and 7 are Sunday
+	dayOfWeekField = cronField{name: "day of week", min: 0, max: 7}
+)
+
+// schedule is a parsed cron expression, each field being the set of the values it matches.
+type schedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	// anyDayOfMonth and anyDayOfWeek record whether the day fields are restricted: if both are, a day matches if it
+	// matches either of them.
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+// parseSchedule parses a cron expression made of 5 fields: minute, hour, day of month, month and day of week. Each
+// field is a comma-separated list of values, ranges (1-5) or wildcards (*), optionally followed by a step (*/15).
+func parseSchedule(expr string) (schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return schedule{}, fmt.Errorf("expected 5 fields in cron expression %q, got %d", expr, len(fields))
+	}
+	var s schedule
+	var err error
+	for i, f := range []struct {
+		cronField
+		bits *uint64
+	}{
+		{minuteField, &s.minute},
+		{hourField, &s.hour},
+		{dayOfMonthField, &s.dayOfMonth},
+		{monthField, &s.month},
+		{dayOfWeekField, &s.dayOfWeek},
+	} {
+		if *f.bits, err = parseField(fields[i], f.cronField); err != nil {
+			return schedule{}, err
+		}
+	}
+	// Sunday can be written 0 or 7
+	if s.dayOfWeek&(1<<7) != 0 {
+		s.dayOfWeek |= 1
+	}
+	s.anyDayOfMonth = fields[2] == "*"
+	s.anyDayOfWeek = fields[4] == "*"
+	return s, nil
+}
+
+// parseField returns the set of the values matched by the given field of a cron expression.
+func parseField(expr string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field %q", stepExpr, f.name, expr)
+			}
+		}
+		low, high := f.min, f.max
+		if rangeExpr != "*" {
+			lowExpr, highExpr, isRange := strings.Cut(rangeExpr, "-")
+			var err error
+			if low, err = parseValue(lowExpr, f); err != nil {
+				return 0, err
+			}
+			high = low
+			if isRange {
+				if high, err = parseValue(highExpr, f); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// 5/15 is 5-59/15
+				high = f.max
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in %s field %q", rangeExpr, f.name, expr)
+			}
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(expr string, f cronField) (int, error) {
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field, must be between %d and %d", expr, f.name, f.min, f.max)
+	}
+	return v, nil
+}
+
+// next returns the first time matching the schedule strictly after the given time, in the location of the given
+// time. It returns false if the schedule does not match any time in the next 5 years.
+func (s schedule) next(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxScheduleSearch)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (s schedule) dayMatches(t time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
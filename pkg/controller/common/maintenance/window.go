@@ -0,0 +1,114 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	durationErrMsg      = "must be at least 1m"
+	neverMatchingErrMsg = "does not match any time"
+)
+
+// window is a parsed maintenance window.
+type window struct {
+	schedule schedule
+	duration time.Duration
+	location *time.Location
+}
+
+func parseWindow(w commonv1.MaintenanceWindow) (window, error) {
+	s, err := parseSchedule(w.Schedule)
+	if err != nil {
+		return window{}, err
+	}
+	location := time.UTC
+	if w.TimeZone != "" {
+		if location, err = time.LoadLocation(w.TimeZone); err != nil {
+			return window{}, err
+		}
+	}
+	return window{schedule: s, duration: w.Duration.Duration, location: location}, nil
+}
+
+// isOpen returns true if the window is open at the given time, otherwise the time at which it opens next.
+func (w window) isOpen(now time.Time) (bool, time.Time) {
+	now = now.In(w.location)
+	// the window is open if it started within its duration
+	if start, ok := w.schedule.next(now.Add(-w.duration)); ok && !start.After(now) {
+		return true, time.Time{}
+	}
+	next, _ := w.schedule.next(now)
+	return false, next
+}
+
+// Validate checks that the given maintenance windows can be parsed and open at some point.
+func Validate(path *field.Path, windows []commonv1.MaintenanceWindow) field.ErrorList {
+	var errs field.ErrorList
+	for i, w := range windows {
+		if w.Duration.Duration < time.Minute {
+			errs = append(errs, field.Invalid(path.Index(i).Child("duration"), w.Duration.Duration.String(), durationErrMsg))
+		}
+		if w.TimeZone != "" {
+			if _, err := time.LoadLocation(w.TimeZone); err != nil {
+				errs = append(errs, field.Invalid(path.Index(i).Child("timeZone"), w.TimeZone, err.Error()))
+			}
+		}
+		s, err := parseSchedule(w.Schedule)
+		if err != nil {
+			errs = append(errs, field.Invalid(path.Index(i).Child("schedule"), w.Schedule, err.Error()))
+			continue
+		}
+		if _, ok := s.next(time.Now().UTC()); !ok {
+			errs = append(errs, field.Invalid(path.Index(i).Child("schedule"), w.Schedule, neverMatchingErrMsg))
+		}
+	}
+	return errs
+}
+
+// IsOpen returns true if disruptive changes can be applied at the given time: if no maintenance window is set, or if
+// one of them is open. Otherwise, it returns the time at which the next window opens.
+func IsOpen(windows []commonv1.MaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	if len(windows) == 0 {
+		return true, time.Time{}, nil
+	}
+	var next time.Time
+	for _, w := range windows {
+		parsed, err := parseWindow(w)
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		open, start := parsed.isOpen(now)
+		if open {
+			return true, time.Time{}, nil
+		}
+		if !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return false, next, nil
+}
+
+// PendingReason describes the given change waiting for the maintenance window starting at the given time.
+func PendingReason(change string, next time.Time) string {
+	if next.IsZero() {
+		return fmt.Sprintf("%s pending, waiting for a maintenance window", change)
+	}
+	return fmt.Sprintf("%s pending, waiting for the maintenance window starting at %s", change, next.UTC().Format(time.RFC3339))
+}
+
+// RequeueAfter returns the delay until the next window opens, or the given default if it is unknown.
+func RequeueAfter(next, now time.Time, defaultDelay time.Duration) time.Duration {
+	if next.IsZero() || !next.After(now) {
+		return defaultDelay
+	}
+	return next.Sub(now)
+}
@@ -0,0 +1,123 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+func mustParse(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	return parsed
+}
+
+func Test_schedule_next(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		from     string
+		want     string
+	}{
+		{name: "every minute", schedule: "* * * * *", from: "2026-10-15T10:20:30Z", want: "2026-10-15T10:21:00Z"},
+		{name: "strictly after", schedule: "30 2 * * *", from: "2026-10-15T02:30:00Z", want: "2026-10-16T02:30:00Z"},
+		{name: "step", schedule: "*/15 * * * *", from: "2026-10-15T10:20:00Z", want: "2026-10-15T10:30:00Z"},
+		{name: "step from a value", schedule: "5/20 * * * *", from: "2026-10-15T10:26:00Z", want: "2026-10-15T10:45:00Z"},
+		{name: "list and range", schedule: "0 1,22-23 * * *", from: "2026-10-15T02:00:00Z", want: "2026-10-15T22:00:00Z"},
+		// 2026-10-15 is a Thursday
+		{name: "day of week", schedule: "0 2 * * 6", from: "2026-10-15T10:00:00Z", want: "2026-10-17T02:00:00Z"},
+		{name: "Sunday as 7", schedule: "0 2 * * 7", from: "2026-10-15T10:00:00Z", want: "2026-10-18T02:00:00Z"},
+		{name: "day of month or day of week", schedule: "0 0 20 * 1", from: "2026-10-15T10:00:00Z", want: "2026-10-19T00:00:00Z"},
+		{name: "next month", schedule: "0 0 1 * *", from: "2026-10-15T10:00:00Z", want: "2026-11-01T00:00:00Z"},
+		{name: "next year", schedule: "0 0 1 3 *", from: "2026-10-15T10:00:00Z", want: "2027-03-01T00:00:00Z"},
+		{name: "leap day", schedule: "0 0 29 2 *", from: "2026-10-15T10:00:00Z", want: "2028-02-29T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseSchedule(tt.schedule)
+			require.NoError(t, err)
+			got, ok := s.next(mustParse(t, tt.from))
+			require.True(t, ok)
+			require.Equal(t, mustParse(t, tt.want), got)
+		})
+	}
+}
+
+func Test_parseSchedule_errors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := parseSchedule(expr)
+		require.Error(t, err, expr)
+	}
+	s, err := parseSchedule("0 0 31 2 *")
+	require.NoError(t, err)
+	_, ok := s.next(time.Now())
+	require.False(t, ok)
+}
+
+func TestIsOpen(t *testing.T) {
+	saturdayNight := commonv1.MaintenanceWindow{Schedule: "0 22 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}}
+	tests := []struct {
+		name     string
+		windows  []commonv1.MaintenanceWindow
+		now      string
+		wantOpen bool
+		wantNext string
+	}{
+		{name: "no window", now: "2026-10-15T10:00:00Z", wantOpen: true},
+		{name: "before the window", windows: []commonv1.MaintenanceWindow{saturdayNight}, now: "2026-10-17T21:59:00Z", wantNext: "2026-10-17T22:00:00Z"},
+		{name: "start of the window", windows: []commonv1.MaintenanceWindow{saturdayNight}, now: "2026-10-17T22:00:00Z", wantOpen: true},
+		{name: "window across midnight", windows: []commonv1.MaintenanceWindow{saturdayNight}, now: "2026-10-18T01:59:00Z", wantOpen: true},
+		{name: "end of the window", windows: []commonv1.MaintenanceWindow{saturdayNight}, now: "2026-10-18T02:00:00Z", wantNext: "2026-10-24T22:00:00Z"},
+		{
+			name: "earliest of several windows",
+			windows: []commonv1.MaintenanceWindow{
+				saturdayNight,
+				{Schedule: "0 12 * * 1-5", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			now:      "2026-10-15T13:00:00Z",
+			wantNext: "2026-10-16T12:00:00Z",
+		},
+		{
+			name:     "time zone",
+			windows:  []commonv1.MaintenanceWindow{{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Europe/Paris"}},
+			now:      "2026-10-15T00:30:00Z",
+			wantOpen: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, next, err := IsOpen(tt.windows, mustParse(t, tt.now))
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOpen, open)
+			if tt.wantNext == "" {
+				require.True(t, next.IsZero())
+				return
+			}
+			require.True(t, mustParse(t, tt.wantNext).Equal(next), "expected %s, got %s", tt.wantNext, next)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	errs := Validate(field.NewPath("spec").Child("maintenanceWindows"), []commonv1.MaintenanceWindow{
+		{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}, TimeZone: "Europe/Paris"},
+		{Schedule: "0 2 * *", Duration: metav1.Duration{Duration: 30 * time.Second}, TimeZone: "Mars/Olympus"},
+		{Schedule: "0 0 30 2 *", Duration: metav1.Duration{Duration: time.Hour}},
+	})
+	require.Len(t, errs, 4)
+	require.Equal(t, "spec.maintenanceWindows[1].duration", errs[0].Field)
+	require.Equal(t, "spec.maintenanceWindows[1].timeZone", errs[1].Field)
+	require.Equal(t, "spec.maintenanceWindows[1].schedule", errs[2].Field)
+	require.Equal(t, "spec.maintenanceWindows[2].schedule", errs[3].Field)
+	require.Contains(t, errs[3].Detail, neverMatchingErrMsg)
+}
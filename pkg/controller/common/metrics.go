@@ -0,0 +1,29 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+// metricsReconciler reports the duration and the outcome of the reconciliation of each resource as Prometheus
+// metrics, in addition to the per controller metrics reported by controller-runtime.
+type metricsReconciler struct {
+	reconcile.Reconciler
+	controller string
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *metricsReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	result, err := r.Reconciler.Reconcile(ctx, request)
+	metrics.ObserveReconciliation(r.controller, request.NamespacedName, start, err)
+	return result, err
+}
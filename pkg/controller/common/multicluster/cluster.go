@@ -0,0 +1,83 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package multicluster
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+var log = ulog.Log.WithName("multicluster")
+
+// RestConfig returns the configuration to connect to the given managed cluster, with the client rate limits of the
+// cluster if set, or the given defaults.
+func RestConfig(c operator.ManagedCluster, defaultQPS float32, defaultBurst int) (*rest.Config, error) {
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: c.Kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: c.Context},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig %s: %w", c.Kubeconfig, err)
+	}
+	if defaultQPS > 0 {
+		cfg.QPS = defaultQPS
+		cfg.Burst = int(defaultQPS * 2)
+	}
+	if defaultBurst > 0 {
+		cfg.Burst = defaultBurst
+	}
+	if c.KubeClientQPS > 0 {
+		cfg.QPS = float32(c.KubeClientQPS)
+		cfg.Burst = int(c.KubeClientQPS * 2)
+	}
+	if c.KubeClientBurst > 0 {
+		cfg.Burst = c.KubeClientBurst
+	}
+	return cfg, nil
+}
+
+// Cluster runs the manager of the controllers of a remote cluster, as a runnable of the manager of the cluster the
+// operator runs in. A failure of the remote cluster manager is reported in the status of the managed clusters instead
+// of stopping the operator, so that the other clusters are still managed.
+type Cluster struct {
+	name               string
+	mgr                manager.Manager
+	needLeaderElection bool
+	status             *Status
+}
+
+var _ manager.LeaderElectionRunnable = &Cluster{}
+
+// NewCluster returns a Cluster running the given manager and reporting its state in the given status. If
+// needLeaderElection is true, the controllers of the remote cluster only run in the elected operator replica.
+func NewCluster(name string, mgr manager.Manager, needLeaderElection bool, status *Status) *Cluster {
+	status.set(name, StateStarting, nil)
+	return &Cluster{name: name, mgr: mgr, needLeaderElection: needLeaderElection, status: status}
+}
+
+// Start implements manager.Runnable.
+func (c *Cluster) Start(ctx context.Context) error {
+	log.Info("Starting to manage remote cluster", "cluster", c.name)
+	c.status.set(c.name, StateRunning, nil)
+	if err := c.mgr.Start(ctx); err != nil {
+		log.Error(err, "Stopped managing remote cluster", "cluster", c.name)
+		c.status.set(c.name, StateFailed, err)
+		return nil
+	}
+	c.status.set(c.name, StateStopped, nil)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (c *Cluster) NeedLeaderElection() bool {
+	return c.needLeaderElection
+}
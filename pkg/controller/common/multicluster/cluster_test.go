@@ -0,0 +1,67 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package multicluster
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: workload-1
+  cluster:
+    server: https://workload-1.example.com
+- name: workload-2
+  cluster:
+    server: https://workload-2.example.com
+contexts:
+- name: workload-1
+  context:
+    cluster: workload-1
+    user: eck
+- name: workload-2
+  context:
+    cluster: workload-2
+    user: eck
+current-context: workload-1
+users:
+- name: eck
+  user:
+    token: secret
+`
+
+func TestRestConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0o600))
+
+	// current context and default rate limits
+	cfg, err := RestConfig(operator.ManagedCluster{Kubeconfig: path}, 50, 0)
+	require.NoError(t, err)
+	require.Equal(t, "https://workload-1.example.com", cfg.Host)
+	require.Equal(t, "secret", cfg.BearerToken)
+	require.Equal(t, float32(50), cfg.QPS)
+	require.Equal(t, 100, cfg.Burst)
+
+	// explicit context and rate limits
+	cfg, err = RestConfig(operator.ManagedCluster{Kubeconfig: path, Context: "workload-2", KubeClientQPS: 10}, 50, 100)
+	require.NoError(t, err)
+	require.Equal(t, "https://workload-2.example.com", cfg.Host)
+	require.Equal(t, float32(10), cfg.QPS)
+	require.Equal(t, 20, cfg.Burst)
+
+	cfg, err = RestConfig(operator.ManagedCluster{Kubeconfig: path, KubeClientQPS: 10, KubeClientBurst: 15}, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, 15, cfg.Burst)
+
+	_, err = RestConfig(operator.ManagedCluster{Kubeconfig: filepath.Join(t.TempDir(), "missing")}, 0, 0)
+	require.Error(t, err)
+}
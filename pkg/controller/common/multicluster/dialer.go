@@ -0,0 +1,141 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package multicluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	utilsnet "github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// Dialer connects to the Services and Pods of a remote cluster through the IP addresses of its Pods, which must be
+// routable from the operator. The DNS names of the Services and Pods of a remote cluster resolve to the resources of
+// the cluster the operator runs in, if any, hence they are resolved with the Endpoints of the Services of the remote
+// cluster instead, and connecting to any other host name is refused.
+type Dialer struct {
+	cluster string
+	client  client.Reader
+	dialer  utilsnet.Dialer
+}
+
+var _ utilsnet.ClusterDialer = &Dialer{}
+
+// NewDialer returns a Dialer to the given remote cluster, reading its Services and Endpoints with the given client.
+func NewDialer(cluster string, c client.Reader) *Dialer {
+	return &Dialer{cluster: cluster, client: c, dialer: &net.Dialer{}}
+}
+
+// ClusterName implements net.ClusterDialer.
+func (d *Dialer) ClusterName() string {
+	return d.cluster
+}
+
+// DialContext connects to one of the ready Pods of the Service, or to the Pod, named by the host of the given address.
+func (d *Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port in address %s: %w", addr, err)
+	}
+	if net.ParseIP(host) != nil {
+		return d.dialer.DialContext(ctx, network, addr)
+	}
+	endpoints, err := d.resolve(ctx, host, port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s in cluster %s: %w", host, d.cluster, err)
+	}
+	var errs []error
+	for _, endpoint := range endpoints {
+		conn, err := d.dialer.DialContext(ctx, network, endpoint)
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// resolve returns the addresses of the endpoints of the given host, which must be the DNS name of a Service,
+// <service>.<namespace>.svc, or of a Pod of a headless Service, <pod>.<service>.<namespace>, optionally followed by
+// the cluster domain.
+func (d *Dialer) resolve(ctx context.Context, host string, port int) ([]string, error) {
+	labels := strings.Split(strings.TrimSuffix(host, "."), ".")
+	switch {
+	case len(labels) >= 3 && labels[2] == "svc":
+		return d.resolveService(ctx, types.NamespacedName{Namespace: labels[1], Name: labels[0]}, port)
+	case len(labels) == 3 || (len(labels) > 3 && labels[3] == "svc"):
+		return d.resolvePod(ctx, types.NamespacedName{Namespace: labels[2], Name: labels[1]}, labels[0], port)
+	}
+	return nil, fmt.Errorf("%s is not the name of a Service or Pod", host)
+}
+
+// resolveService returns the addresses of the ready endpoints of the given Service port. Endpoints are read rather than
+// Pods, which may not be cached.
+func (d *Dialer) resolveService(ctx context.Context, svcName types.NamespacedName, port int) ([]string, error) {
+	var svc corev1.Service
+	if err := d.client.Get(ctx, svcName, &svc); err != nil {
+		return nil, err
+	}
+	var portName *string
+	for _, p := range svc.Spec.Ports {
+		if int(p.Port) == port {
+			portName = &p.Name
+			break
+		}
+	}
+	if portName == nil {
+		return nil, fmt.Errorf("service %s has no port %d", svcName, port)
+	}
+	var svcEndpoints corev1.Endpoints
+	if err := d.client.Get(ctx, svcName, &svcEndpoints); err != nil {
+		return nil, err
+	}
+	var endpoints []string
+	for _, subset := range svcEndpoints.Subsets {
+		for _, p := range subset.Ports {
+			if p.Name != *portName {
+				continue
+			}
+			for _, address := range subset.Addresses {
+				endpoints = append(endpoints, net.JoinHostPort(address.IP, strconv.Itoa(int(p.Port))))
+			}
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("service %s has no ready endpoint", svcName)
+	}
+	// spread the connections between the Pods, as the Service would
+	rand.Shuffle(len(endpoints), func(i, j int) { endpoints[i], endpoints[j] = endpoints[j], endpoints[i] }) //nolint:gosec
+	return endpoints, nil
+}
+
+// resolvePod returns the address of the given Pod from the endpoints of its headless Service, whether it is ready or not.
+func (d *Dialer) resolvePod(ctx context.Context, svcName types.NamespacedName, podName string, port int) ([]string, error) {
+	var svcEndpoints corev1.Endpoints
+	if err := d.client.Get(ctx, svcName, &svcEndpoints); err != nil {
+		return nil, err
+	}
+	for _, subset := range svcEndpoints.Subsets {
+		for _, address := range append(subset.Addresses, subset.NotReadyAddresses...) {
+			if address.Hostname == podName || (address.TargetRef != nil && address.TargetRef.Name == podName) {
+				return []string{net.JoinHostPort(address.IP, strconv.Itoa(port))}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("pod %s has no endpoint in service %s", podName, svcName)
+}
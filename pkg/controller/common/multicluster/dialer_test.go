@@ -0,0 +1,127 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package multicluster
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	utilsnet "github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeCluster runs the Pod of an Elasticsearch cluster named es in namespace ns, listening on a local port, along
+// with the Endpoints of its Services.
+type fakeCluster struct {
+	listener net.Listener
+	client   k8s.Client
+}
+
+func newFakeCluster(t *testing.T) fakeCluster {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-es-internal-http"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "https", Port: 9200, TargetPort: intstr.FromString("https")}},
+		},
+	}
+	address := corev1.EndpointAddress{
+		IP:        "127.0.0.1",
+		Hostname:  "es-es-default-0",
+		TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "ns", Name: "es-es-default-0"},
+	}
+	svcEndpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-es-internal-http"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{address},
+			Ports:     []corev1.EndpointPort{{Name: "https", Port: int32(port)}},
+		}},
+	}
+	// the headless Service of the StatefulSet, whose Pod is not ready
+	headlessEndpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-es-default"},
+		Subsets: []corev1.EndpointSubset{{
+			NotReadyAddresses: []corev1.EndpointAddress{address},
+			Ports:             []corev1.EndpointPort{{Name: "https", Port: 9200}},
+		}},
+	}
+	return fakeCluster{listener: listener, client: k8s.NewFakeClient(svc, svcEndpoints, headlessEndpoints)}
+}
+
+// requireConnected checks that the given connection was accepted by the given cluster.
+func requireConnected(t *testing.T, cluster fakeCluster, conn net.Conn) {
+	t.Helper()
+	accepted, err := cluster.listener.Accept()
+	require.NoError(t, err)
+	defer accepted.Close()
+	require.Equal(t, conn.LocalAddr().String(), accepted.RemoteAddr().String())
+}
+
+func TestDialer_DialContext(t *testing.T) {
+	// two clusters with an Elasticsearch cluster of the same namespace and name
+	local, remote := newFakeCluster(t), newFakeCluster(t)
+	dialer := NewDialer("remote", remote.client)
+	require.Equal(t, "remote", utilsnet.ClusterName(dialer))
+
+	for _, addr := range []string{
+		"es-es-internal-http.ns.svc:9200",
+		"es-es-internal-http.ns.svc.cluster.local:9200",
+	} {
+		t.Run(addr, func(t *testing.T) {
+			conn, err := dialer.DialContext(context.Background(), "tcp", addr)
+			require.NoError(t, err)
+			defer conn.Close()
+			// the connection is to the Pod of the remote cluster, not to the local one
+			requireConnected(t, remote, conn)
+		})
+	}
+
+	t.Run("pod", func(t *testing.T) {
+		port := strconv.Itoa(remote.listener.Addr().(*net.TCPAddr).Port)
+		conn, err := dialer.DialContext(context.Background(), "tcp", "es-es-default-0.es-es-default.ns:"+port)
+		require.NoError(t, err)
+		defer conn.Close()
+		requireConnected(t, remote, conn)
+	})
+
+	t.Run("local cluster", func(t *testing.T) {
+		conn, err := NewDialer("local", local.client).DialContext(context.Background(), "tcp", "es-es-internal-http.ns.svc:9200")
+		require.NoError(t, err)
+		defer conn.Close()
+		requireConnected(t, local, conn)
+	})
+
+	t.Run("unknown service", func(t *testing.T) {
+		_, err := dialer.DialContext(context.Background(), "tcp", "other-es-internal-http.ns.svc:9200")
+		require.Error(t, err)
+	})
+
+	t.Run("unknown pod", func(t *testing.T) {
+		_, err := dialer.DialContext(context.Background(), "tcp", "es-es-default-1.es-es-default.ns:9200")
+		require.Error(t, err)
+	})
+
+	t.Run("unknown port", func(t *testing.T) {
+		_, err := dialer.DialContext(context.Background(), "tcp", "es-es-internal-http.ns.svc:9300")
+		require.Error(t, err)
+	})
+
+	t.Run("other host names are not resolved", func(t *testing.T) {
+		_, err := dialer.DialContext(context.Background(), "tcp", "localhost:9200")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package multicluster
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// State is the state of the manager of a remote cluster.
+type State string
+
+const (
+	StateStarting State = "starting"
+	StateRunning  State = "running"
+	StateFailed   State = "failed"
+	StateStopped  State = "stopped"
+)
+
+var clusterUpDesc = prometheus.NewDesc(
+	"elastic_managed_cluster_up",
+	"Whether the controllers of a remote Kubernetes cluster managed by the operator are running.",
+	[]string{"cluster", "state"},
+	nil,
+)
+
+// ClusterStatus is the status of a remote cluster.
+type ClusterStatus struct {
+	State State  `json:"state"`
+	Error string `json:"error,omitempty"`
+}
+
+// Status aggregates the status of the remote clusters managed by the operator. It can be used as a readiness check,
+// and registered as a Prometheus collector.
+type Status struct {
+	mutex     sync.RWMutex
+	byCluster map[string]ClusterStatus
+}
+
+var _ prometheus.Collector = &Status{}
+
+// NewStatus returns an empty Status.
+func NewStatus() *Status {
+	return &Status{byCluster: map[string]ClusterStatus{}}
+}
+
+func (s *Status) set(cluster string, state State, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	status := ClusterStatus{State: state}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	s.byCluster[cluster] = status
+}
+
+// Get returns a copy of the status of all the remote clusters, by cluster name.
+func (s *Status) Get() map[string]ClusterStatus {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	statuses := make(map[string]ClusterStatus, len(s.byCluster))
+	for name, status := range s.byCluster {
+		statuses[name] = status
+	}
+	return statuses
+}
+
+// Check is a healthz.Checker failing if the controllers of at least one remote cluster have stopped with an error.
+func (s *Status) Check(_ *http.Request) error {
+	var failed []string
+	for name, status := range s.Get() {
+		if status.State == StateFailed {
+			failed = append(failed, fmt.Sprintf("%s (%s)", name, status.Error))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	sort.Strings(failed)
+	return fmt.Errorf("failed to manage remote clusters: %s", strings.Join(failed, ", "))
+}
+
+// Describe implements prometheus.Collector.
+func (s *Status) Describe(ch chan<- *prometheus.Desc) {
+	ch <- clusterUpDesc
+}
+
+// Collect implements prometheus.Collector.
+func (s *Status) Collect(ch chan<- prometheus.Metric) {
+	for name, status := range s.Get() {
+		up := 0.0
+		if status.State == StateRunning {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(clusterUpDesc, prometheus.GaugeValue, up, name, string(status.State))
+	}
+}
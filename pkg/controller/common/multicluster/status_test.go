@@ -0,0 +1,38 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package multicluster
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus(t *testing.T) {
+	status := NewStatus()
+	require.NoError(t, status.Check(nil))
+
+	status.set("workload-1", StateRunning, nil)
+	status.set("workload-2", StateStarting, nil)
+	require.NoError(t, status.Check(nil))
+	require.Equal(t, map[string]ClusterStatus{
+		"workload-1": {State: StateRunning},
+		"workload-2": {State: StateStarting},
+	}, status.Get())
+
+	status.set("workload-2", StateFailed, errors.New("connection refused"))
+	require.EqualError(t, status.Check(nil), "failed to manage remote clusters: workload-2 (connection refused)")
+
+	expected := `
+# HELP elastic_managed_cluster_up Whether the controllers of a remote Kubernetes cluster managed by the operator are running.
+# TYPE elastic_managed_cluster_up gauge
+elastic_managed_cluster_up{cluster="workload-1",state="running"} 1
+elastic_managed_cluster_up{cluster="workload-2",state="failed"} 0
+`
+	require.NoError(t, testutil.CollectAndCompare(status, strings.NewReader(expected)))
+}
@@ -0,0 +1,435 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package namespaces
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+var log = ulog.Log.WithName("managed-namespaces")
+
+// NewCacheFunc returns a function creating a Cache for the namespaces matching the given label selector, in addition
+// to the given static namespaces which are always managed. It can be used as the manager NewCache option.
+func NewCacheFunc(selector labels.Selector, static ...string) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		return NewCache(config, opts, selector, static...)
+	}
+}
+
+// Cache is a cache.Cache for the namespaces matching a label selector. Namespaces are watched to start a dedicated
+// cache when a namespace starts matching the selector, and to stop it when the namespace stops matching the selector
+// or is deleted. Event handlers and indexes are registered in all the namespace caches, including the ones started
+// later on, so that the resources of a namespace which becomes managed are reconciled without restarting the operator.
+// Cluster-scoped resources are served by a single cluster-wide cache.
+type Cache struct {
+	scheme   *runtime.Scheme
+	mapper   apimeta.RESTMapper
+	selector labels.Selector
+	static   []string
+	cluster  cache.Cache
+	newCache func(namespace string) (cache.Cache, error)
+
+	mutex      sync.RWMutex
+	ctx        context.Context
+	namespaces map[string]*namespaceCache
+	informers  map[schema.GroupVersionKind]*informer
+	indexes    []index
+	watch      toolscache.ResourceEventHandlerRegistration
+}
+
+// namespaceCache is the cache of a single managed namespace.
+type namespaceCache struct {
+	cache.Cache
+	cancel context.CancelFunc
+}
+
+// index is a field index registered in the cache, to be registered in the caches of namespaces managed later on.
+type index struct {
+	obj          client.Object
+	field        string
+	extractValue client.IndexerFunc
+}
+
+var _ cache.Cache = &Cache{}
+
+// NewCache returns a Cache for the namespaces matching the given label selector and the given static namespaces.
+func NewCache(config *rest.Config, opts cache.Options, selector labels.Selector, static ...string) (*Cache, error) {
+	// cluster-scoped resources and the managed namespaces themselves are served by a cluster-wide cache
+	clusterOpts := opts
+	clusterOpts.DefaultNamespaces = nil
+	clusterOpts.ByObject = map[client.Object]cache.ByObject{}
+	for obj, byObject := range opts.ByObject {
+		clusterOpts.ByObject[obj] = byObject
+	}
+	clusterOpts.ByObject[&corev1.Namespace{}] = cache.ByObject{Label: selector}
+	cluster, err := cache.New(config, clusterOpts)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cache{
+		scheme:   opts.Scheme,
+		mapper:   opts.Mapper,
+		selector: selector,
+		static:   static,
+		cluster:  cluster,
+		newCache: func(namespace string) (cache.Cache, error) {
+			namespaceOpts := opts
+			namespaceOpts.DefaultNamespaces = map[string]cache.Config{namespace: {}}
+			namespaceOpts.ByObject = forNamespace(opts.ByObject, namespace)
+			return cache.New(config, namespaceOpts)
+		},
+		namespaces: map[string]*namespaceCache{},
+		informers:  map[schema.GroupVersionKind]*informer{},
+	}
+	return c, nil
+}
+
+// forNamespace restricts the per-namespace settings of the given objects to the given namespace.
+func forNamespace(byObjects map[client.Object]cache.ByObject, namespace string) map[client.Object]cache.ByObject {
+	restricted := make(map[client.Object]cache.ByObject, len(byObjects))
+	for obj, byObject := range byObjects {
+		if byObject.Namespaces != nil {
+			config, exists := byObject.Namespaces[namespace]
+			if !exists {
+				config = byObject.Namespaces[cache.AllNamespaces]
+			}
+			byObject.Namespaces = map[string]cache.Config{namespace: config}
+		}
+		restricted[obj] = byObject
+	}
+	return restricted
+}
+
+// Namespaces returns the names of the namespaces currently managed.
+func (c *Cache) Namespaces() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	namespaces := make([]string, 0, len(c.namespaces))
+	for ns := range c.namespaces {
+		namespaces = append(namespaces, ns)
+	}
+	slices.Sort(namespaces)
+	return namespaces
+}
+
+// Start starts the cluster-wide cache, the caches of the static namespaces, and watches namespaces to start and stop
+// the caches of the namespaces matching the label selector. It blocks until the context is cancelled.
+func (c *Cache) Start(ctx context.Context) error {
+	c.mutex.Lock()
+	c.ctx = ctx
+	for _, ns := range c.static {
+		if err := c.addNamespace(ns); err != nil {
+			c.mutex.Unlock()
+			return err
+		}
+	}
+	c.mutex.Unlock()
+
+	nsInformer, err := c.cluster.GetInformer(ctx, &corev1.Namespace{}, cache.BlockUntilSynced(false))
+	if err != nil {
+		return err
+	}
+	watch, err := nsInformer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.onNamespace(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.onNamespace(obj) },
+		DeleteFunc: func(obj interface{}) { c.onNamespaceDeleted(obj) },
+	})
+	if err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	c.watch = watch
+	c.mutex.Unlock()
+
+	// the namespace caches are stopped along with the cluster-wide cache when the context is cancelled
+	return c.cluster.Start(ctx)
+}
+
+// WaitForCacheSync waits until the namespaces to manage are known, and all their caches are synced.
+func (c *Cache) WaitForCacheSync(ctx context.Context) bool {
+	if !c.cluster.WaitForCacheSync(ctx) {
+		return false
+	}
+	// wait for the existing namespaces to be handled
+	if !toolscache.WaitForCacheSync(ctx.Done(), func() bool {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+		return c.watch != nil && c.watch.HasSynced()
+	}) {
+		return false
+	}
+	for _, nsCache := range c.snapshot() {
+		if !nsCache.WaitForCacheSync(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// onNamespace starts or stops the cache of the given namespace depending on whether it matches the label selector.
+func (c *Cache) onNamespace(obj interface{}) {
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.selector.Matches(labels.Set(ns.Labels)) && ns.Status.Phase != corev1.NamespaceTerminating {
+		if err := c.addNamespace(ns.Name); err != nil {
+			log.Error(err, "Failed to start managing namespace", "namespace", ns.Name)
+		}
+		return
+	}
+	c.removeNamespace(ns.Name)
+}
+
+func (c *Cache) onNamespaceDeleted(obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.removeNamespace(ns.Name)
+}
+
+// addNamespace starts a cache for the given namespace, with all the informers, event handlers and indexes registered
+// so far. It must be called with the mutex held.
+func (c *Cache) addNamespace(namespace string) error {
+	if _, exists := c.namespaces[namespace]; exists {
+		return nil
+	}
+	nsCache, err := c.newCache(namespace)
+	if err != nil {
+		return err
+	}
+	// informers and indexes are set up before the cache is started not to block
+	for _, idx := range c.indexes {
+		if err := nsCache.IndexField(c.ctx, idx.obj, idx.field, idx.extractValue); err != nil {
+			return err
+		}
+	}
+	for _, inf := range c.informers {
+		if err := inf.addNamespace(c.ctx, namespace, nsCache); err != nil {
+			for _, added := range c.informers {
+				added.removeNamespace(namespace)
+			}
+			return err
+		}
+	}
+	ctx, cancel := context.WithCancel(c.ctx)
+	c.namespaces[namespace] = &namespaceCache{Cache: nsCache, cancel: cancel}
+	go func() {
+		if err := nsCache.Start(ctx); err != nil {
+			log.Error(err, "Failed to start namespace cache", "namespace", namespace)
+		}
+	}()
+	log.Info("Managing namespace", "namespace", namespace)
+	return nil
+}
+
+// removeNamespace stops the cache of the given namespace, unless it is a static namespace. It must be called with the
+// mutex held.
+func (c *Cache) removeNamespace(namespace string) {
+	nsCache, exists := c.namespaces[namespace]
+	if !exists || slices.Contains(c.static, namespace) {
+		return
+	}
+	for _, inf := range c.informers {
+		inf.removeNamespace(namespace)
+	}
+	nsCache.cancel()
+	delete(c.namespaces, namespace)
+	log.Info("No longer managing namespace", "namespace", namespace)
+}
+
+func (c *Cache) snapshot() map[string]cache.Cache {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	caches := make(map[string]cache.Cache, len(c.namespaces))
+	for ns, nsCache := range c.namespaces {
+		caches[ns] = nsCache.Cache
+	}
+	return caches
+}
+
+// namespaceCacheFor returns the cache of the given namespace, or nil if it is not managed.
+func (c *Cache) namespaceCacheFor(namespace string) cache.Cache {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if nsCache, exists := c.namespaces[namespace]; exists {
+		return nsCache.Cache
+	}
+	return nil
+}
+
+func (c *Cache) isNamespaced(obj runtime.Object) (bool, error) {
+	return apiutil.IsObjectNamespaced(obj, c.scheme, c.mapper)
+}
+
+// Get implements client.Reader. Resources in namespaces which are not managed are reported as not found.
+func (c *Cache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	namespaced, err := c.isNamespaced(obj)
+	if err != nil {
+		return err
+	}
+	if !namespaced {
+		return c.cluster.Get(ctx, key, obj, opts...)
+	}
+	nsCache := c.namespaceCacheFor(key.Namespace)
+	if nsCache == nil {
+		gvk, err := apiutil.GVKForObject(obj, c.scheme)
+		if err != nil {
+			return err
+		}
+		return apierrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, key.Name)
+	}
+	return nsCache.Get(ctx, key, obj, opts...)
+}
+
+// List implements client.Reader. Listing resources in all namespaces lists the resources of the managed namespaces.
+func (c *Cache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	namespaced, err := c.isNamespaced(list)
+	if err != nil {
+		return err
+	}
+	if !namespaced {
+		return c.cluster.List(ctx, list, opts...)
+	}
+	if listOpts.Namespace != corev1.NamespaceAll {
+		nsCache := c.namespaceCacheFor(listOpts.Namespace)
+		if nsCache == nil {
+			return fmt.Errorf("unable to list in namespace %s: namespace not managed", listOpts.Namespace)
+		}
+		return nsCache.List(ctx, list, opts...)
+	}
+
+	var items []runtime.Object
+	var resourceVersion string
+	for _, nsCache := range c.snapshot() {
+		nsList, ok := list.DeepCopyObject().(client.ObjectList)
+		if !ok {
+			return fmt.Errorf("object %T must be a list type", list)
+		}
+		if err := nsCache.List(ctx, nsList, &listOpts); err != nil {
+			return err
+		}
+		nsItems, err := apimeta.ExtractList(nsList)
+		if err != nil {
+			return err
+		}
+		items = append(items, nsItems...)
+		resourceVersion = nsList.GetResourceVersion()
+	}
+	if listOpts.Limit > 0 && int64(len(items)) > listOpts.Limit {
+		items = items[:listOpts.Limit]
+	}
+	list.SetResourceVersion(resourceVersion)
+	return apimeta.SetList(list, items)
+}
+
+// GetInformer implements cache.Informers.
+func (c *Cache) GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetInformerForKind(ctx, gvk, opts...)
+}
+
+// GetInformerForKind implements cache.Informers. Informers of namespaced resources span all the managed namespaces,
+// including the namespaces managed after the informer is returned.
+func (c *Cache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	namespaced, err := apiutil.IsGVKNamespaced(gvk, c.mapper)
+	if err != nil {
+		return nil, err
+	}
+	if !namespaced {
+		return c.cluster.GetInformerForKind(ctx, gvk, opts...)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if inf, exists := c.informers[gvk]; exists {
+		return inf, nil
+	}
+	inf := newInformer(gvk)
+	for ns, nsCache := range c.namespaces {
+		if err := inf.addNamespace(ctx, ns, nsCache.Cache); err != nil {
+			return nil, err
+		}
+	}
+	c.informers[gvk] = inf
+	return inf, nil
+}
+
+// RemoveInformer implements cache.Informers.
+func (c *Cache) RemoveInformer(ctx context.Context, obj client.Object) error {
+	namespaced, err := c.isNamespaced(obj)
+	if err != nil {
+		return err
+	}
+	if !namespaced {
+		return c.cluster.RemoveInformer(ctx, obj)
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.informers, gvk)
+	for _, nsCache := range c.namespaces {
+		if err := nsCache.RemoveInformer(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexField implements client.FieldIndexer.
+func (c *Cache) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	namespaced, err := c.isNamespaced(obj)
+	if err != nil {
+		return err
+	}
+	if !namespaced {
+		return c.cluster.IndexField(ctx, obj, field, extractValue)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, nsCache := range c.namespaces {
+		if err := nsCache.IndexField(ctx, obj, field, extractValue); err != nil {
+			return err
+		}
+	}
+	c.indexes = append(c.indexes, index{obj: obj, field: field, extractValue: extractValue})
+	return nil
+}
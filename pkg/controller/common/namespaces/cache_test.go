@@ -0,0 +1,131 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package namespaces
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newTestCache(t *testing.T) (*Cache, map[string]*informertest.FakeInformers) {
+	t.Helper()
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	caches := map[string]*informertest.FakeInformers{}
+	c := &Cache{
+		scheme:   scheme.Scheme,
+		mapper:   mapper,
+		selector: labels.SelectorFromSet(labels.Set{"eck.k8s.elastic.co/managed": "true"}),
+		static:   []string{"elastic-system"},
+		cluster:  &informertest.FakeInformers{Scheme: scheme.Scheme},
+		newCache: func(namespace string) (cache.Cache, error) {
+			caches[namespace] = &informertest.FakeInformers{Scheme: scheme.Scheme}
+			return caches[namespace], nil
+		},
+		ctx:        context.Background(),
+		namespaces: map[string]*namespaceCache{},
+		informers:  map[schema.GroupVersionKind]*informer{},
+	}
+	require.NoError(t, c.addNamespace("elastic-system"))
+	return c, caches
+}
+
+func namespace(name string, managed bool) *corev1.Namespace {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if managed {
+		ns.Labels = map[string]string{"eck.k8s.elastic.co/managed": "true"}
+	}
+	return ns
+}
+
+func TestCache_ManagedNamespaces(t *testing.T) {
+	c, caches := newTestCache(t)
+
+	// watch secrets before any namespace matches the selector
+	inf, err := c.GetInformer(context.Background(), &corev1.Secret{})
+	require.NoError(t, err)
+	var added []string
+	_, err = inf.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { added = append(added, obj.(*corev1.Secret).Namespace) }, //nolint:forcetypeassert
+	})
+	require.NoError(t, err)
+
+	c.onNamespace(namespace("ns1", true))
+	c.onNamespace(namespace("ns2", false))
+	assert.Equal(t, []string{"elastic-system", "ns1"}, c.Namespaces())
+	assert.True(t, c.IsManaged("ns1"))
+	assert.False(t, c.IsManaged("ns2"))
+
+	// the event handler is registered in the informer of the namespace managed later on
+	nsInformer, err := caches["ns1"].FakeInformerFor(context.Background(), &corev1.Secret{})
+	require.NoError(t, err)
+	nsInformer.Add(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "s"}})
+	assert.Equal(t, []string{"ns1"}, added)
+
+	// resources in namespaces which are not managed are not found
+	err = c.Get(context.Background(), types.NamespacedName{Namespace: "ns2", Name: "s"}, &corev1.Secret{})
+	assert.True(t, apierrors.IsNotFound(err))
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns1", Name: "s"}, &corev1.Secret{}))
+	assert.Error(t, c.List(context.Background(), &corev1.SecretList{}, &client.ListOptions{Namespace: "ns2"}))
+
+	// namespaces stop being managed once unlabeled or deleted, except for the static ones
+	c.onNamespace(namespace("ns2", true))
+	c.onNamespace(namespace("ns1", false))
+	assert.Equal(t, []string{"elastic-system", "ns2"}, c.Namespaces())
+	c.onNamespaceDeleted(toolscache.DeletedFinalStateUnknown{Obj: namespace("ns2", true)})
+	c.onNamespaceDeleted(namespace("elastic-system", false))
+	assert.Equal(t, []string{"elastic-system"}, c.Namespaces())
+	assert.True(t, c.IsManaged("elastic-system"))
+	assert.False(t, c.IsManaged("ns2"))
+	assert.NotContains(t, c.informers[corev1.SchemeGroupVersion.WithKind("Secret")].perNamespace, "ns1")
+}
+
+func Test_forNamespace(t *testing.T) {
+	selector := labels.SelectorFromSet(labels.Set{"a": "b"})
+	byObjects := map[client.Object]cache.ByObject{
+		&corev1.Secret{}: {Namespaces: map[string]cache.Config{
+			"elastic-system":    {LabelSelector: labels.Everything()},
+			cache.AllNamespaces: {LabelSelector: selector},
+		}},
+		&corev1.Pod{}: {Label: selector},
+	}
+	for obj, byObject := range forNamespace(byObjects, "ns1") {
+		switch obj.(type) {
+		case *corev1.Secret:
+			assert.Equal(t, map[string]cache.Config{"ns1": {LabelSelector: selector}}, byObject.Namespaces)
+		case *corev1.Pod:
+			assert.Nil(t, byObject.Namespaces)
+			assert.Equal(t, selector, byObject.Label)
+		}
+	}
+	for obj, byObject := range forNamespace(byObjects, "elastic-system") {
+		if _, isSecret := obj.(*corev1.Secret); isSecret {
+			assert.Equal(t, map[string]cache.Config{"elastic-system": {LabelSelector: labels.Everything()}}, byObject.Namespaces)
+		}
+	}
+}
+
+func TestStatic_IsManaged(t *testing.T) {
+	assert.True(t, Static{}.IsManaged("ns1"))
+	assert.Empty(t, Static{}.Namespaces())
+	assert.True(t, Static{"ns1", "elastic-system"}.IsManaged("ns1"))
+	assert.False(t, Static{"ns1", "elastic-system"}.IsManaged("ns2"))
+}
@@ -0,0 +1,196 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package namespaces
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// informer is a cache.Informer spanning the managed namespaces. Event handlers and indexers are recorded to be
+// registered in the informers of the namespaces managed later on.
+type informer struct {
+	gvk schema.GroupVersionKind
+
+	mutex         sync.RWMutex
+	perNamespace  map[string]cache.Informer
+	registrations []*registration
+	indexers      []toolscache.Indexers
+}
+
+// registration is the registration of an event handler in the informers of all the managed namespaces.
+type registration struct {
+	handler      toolscache.ResourceEventHandler
+	resyncPeriod time.Duration
+
+	mutex   sync.RWMutex
+	handles map[string]toolscache.ResourceEventHandlerRegistration
+}
+
+var (
+	_ cache.Informer                              = &informer{}
+	_ toolscache.ResourceEventHandlerRegistration = &registration{}
+)
+
+func newInformer(gvk schema.GroupVersionKind) *informer {
+	return &informer{gvk: gvk, perNamespace: map[string]cache.Informer{}}
+}
+
+// addNamespace gets the informer of the given namespace cache, and registers the recorded indexers and event handlers.
+func (i *informer) addNamespace(ctx context.Context, namespace string, nsCache cache.Cache) error {
+	nsInformer, err := nsCache.GetInformerForKind(ctx, i.gvk, cache.BlockUntilSynced(false))
+	if err != nil {
+		return err
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	for _, indexers := range i.indexers {
+		if err := nsInformer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	for _, r := range i.registrations {
+		if err := r.add(namespace, nsInformer); err != nil {
+			return err
+		}
+	}
+	i.perNamespace[namespace] = nsInformer
+	return nil
+}
+
+// removeNamespace forgets the informer of the given namespace, which is stopped along with its cache.
+func (i *informer) removeNamespace(namespace string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	delete(i.perNamespace, namespace)
+	for _, r := range i.registrations {
+		r.remove(namespace)
+	}
+}
+
+// AddEventHandler implements cache.Informer.
+func (i *informer) AddEventHandler(handler toolscache.ResourceEventHandler) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.AddEventHandlerWithResyncPeriod(handler, 0)
+}
+
+// AddEventHandlerWithResyncPeriod implements cache.Informer.
+func (i *informer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) (toolscache.ResourceEventHandlerRegistration, error) {
+	r := &registration{
+		handler:      handler,
+		resyncPeriod: resyncPeriod,
+		handles:      map[string]toolscache.ResourceEventHandlerRegistration{},
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	for ns, nsInformer := range i.perNamespace {
+		if err := r.add(ns, nsInformer); err != nil {
+			return nil, err
+		}
+	}
+	i.registrations = append(i.registrations, r)
+	return r, nil
+}
+
+// RemoveEventHandler implements cache.Informer.
+func (i *informer) RemoveEventHandler(handle toolscache.ResourceEventHandlerRegistration) error {
+	r, ok := handle.(*registration)
+	if !ok {
+		return fmt.Errorf("registration %T was not returned by this informer", handle)
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	for idx, existing := range i.registrations {
+		if existing == r {
+			i.registrations = append(i.registrations[:idx], i.registrations[idx+1:]...)
+			break
+		}
+	}
+	for ns, nsInformer := range i.perNamespace {
+		r.mutex.RLock()
+		handle, exists := r.handles[ns]
+		r.mutex.RUnlock()
+		if !exists {
+			continue
+		}
+		if err := nsInformer.RemoveEventHandler(handle); err != nil {
+			return err
+		}
+		r.remove(ns)
+	}
+	return nil
+}
+
+// AddIndexers implements cache.Informer.
+func (i *informer) AddIndexers(indexers toolscache.Indexers) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	for _, nsInformer := range i.perNamespace {
+		if err := nsInformer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	i.indexers = append(i.indexers, indexers)
+	return nil
+}
+
+// HasSynced implements cache.Informer.
+func (i *informer) HasSynced() bool {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	for _, nsInformer := range i.perNamespace {
+		if !nsInformer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsStopped implements cache.Informer. The informer keeps running when no namespace is managed, as namespaces can be
+// managed later on.
+func (i *informer) IsStopped() bool {
+	return false
+}
+
+func (r *registration) add(namespace string, nsInformer cache.Informer) error {
+	var handle toolscache.ResourceEventHandlerRegistration
+	var err error
+	if r.resyncPeriod == 0 {
+		handle, err = nsInformer.AddEventHandler(r.handler)
+	} else {
+		handle, err = nsInformer.AddEventHandlerWithResyncPeriod(r.handler, r.resyncPeriod)
+	}
+	if err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.handles[namespace] = handle
+	return nil
+}
+
+func (r *registration) remove(namespace string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.handles, namespace)
+}
+
+// HasSynced implements toolscache.ResourceEventHandlerRegistration: it returns true once the handler has been called
+// for the initial state of all the managed namespaces.
+func (r *registration) HasSynced() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for _, handle := range r.handles {
+		if !handle.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
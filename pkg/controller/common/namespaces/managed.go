@@ -0,0 +1,44 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package namespaces
+
+import (
+	"slices"
+)
+
+// Managed gives the namespaces whose resources are managed by the operator.
+type Managed interface {
+	// Namespaces returns the namespaces currently managed, or an empty list if all namespaces are managed.
+	Namespaces() []string
+	// IsManaged returns true if the resources of the given namespace are managed.
+	IsManaged(namespace string) bool
+}
+
+// Static is a fixed list of managed namespaces. All namespaces are managed if the list is empty.
+type Static []string
+
+var (
+	_ Managed = Static{}
+	_ Managed = &Cache{}
+)
+
+// Namespaces returns the managed namespaces, or an empty list if all namespaces are managed.
+func (s Static) Namespaces() []string {
+	return slices.Clone(s)
+}
+
+// IsManaged returns true if the list is empty or contains the given namespace.
+func (s Static) IsManaged(namespace string) bool {
+	return len(s) == 0 || slices.Contains(s, namespace)
+}
+
+// IsManaged returns true if the cache of the given namespace is started, because it is one of the static namespaces
+// or it matches the label selector. No namespace is managed until the cache is started.
+func (c *Cache) IsManaged(namespace string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	_, exists := c.namespaces[namespace]
+	return exists
+}
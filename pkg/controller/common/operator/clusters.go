@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package operator
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ManagedClustersConfigKey is the key of the configuration file section listing the remote Kubernetes clusters in
+// which the operator manages resources in addition to the cluster it runs in, by cluster name. It can only be set
+// through the configuration file.
+const ManagedClustersConfigKey = "managed-clusters"
+
+// ManagedCluster is a remote Kubernetes cluster managed by the operator. The operator connects to the Elasticsearch and
+// Kibana endpoints of the cluster through the IP addresses of their Pods, which must be routable from the operator.
+type ManagedCluster struct {
+	// Kubeconfig is the path to the kubeconfig file used to connect to the cluster.
+	Kubeconfig string `mapstructure:"kubeconfig"`
+	// Context is the kubeconfig context to use, the current context of the kubeconfig file if empty.
+	Context string `mapstructure:"context"`
+	// KubeClientQPS is the maximum number of queries per second to the Kubernetes API of the cluster, overriding
+	// kube-client-qps.
+	KubeClientQPS float64 `mapstructure:"kube-client-qps"`
+	// KubeClientBurst is the maximum burst of queries to the Kubernetes API of the cluster, overriding
+	// kube-client-burst.
+	KubeClientBurst int `mapstructure:"kube-client-burst"`
+}
+
+// ValidateManagedCluster returns an error if the name or one of the settings of a managed cluster is invalid. Cluster
+// names are used in the names of the controllers, they must be valid DNS labels.
+func ValidateManagedCluster(name string, c ManagedCluster) error {
+	switch {
+	case len(validation.IsDNS1123Label(name)) > 0:
+		return fmt.Errorf("cluster name %q must be a valid DNS label", name)
+	case c.Kubeconfig == "":
+		return fmt.Errorf("kubeconfig must be set")
+	case c.KubeClientQPS < 0 || c.KubeClientBurst < 0:
+		return fmt.Errorf("kube-client-qps and kube-client-burst must not be negative")
+	}
+	return nil
+}
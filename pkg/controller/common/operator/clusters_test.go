@@ -0,0 +1,31 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateManagedCluster(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusterName string
+		cluster     ManagedCluster
+		wantErr     bool
+	}{
+		{name: "valid", clusterName: "workload-1", cluster: ManagedCluster{Kubeconfig: "/kubeconfig", KubeClientQPS: 10, KubeClientBurst: 20}},
+		{name: "valid with context", clusterName: "workload-1", cluster: ManagedCluster{Kubeconfig: "/kubeconfig", Context: "ctx"}},
+		{name: "invalid name", clusterName: "Workload_1", cluster: ManagedCluster{Kubeconfig: "/kubeconfig"}, wantErr: true},
+		{name: "missing kubeconfig", clusterName: "workload-1", cluster: ManagedCluster{}, wantErr: true},
+		{name: "negative qps", clusterName: "workload-1", cluster: ManagedCluster{Kubeconfig: "/kubeconfig", KubeClientQPS: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, ValidateManagedCluster(tt.clusterName, tt.cluster) != nil)
+		})
+	}
+}
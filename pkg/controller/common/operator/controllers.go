@@ -0,0 +1,50 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package operator
+
+import (
+	"fmt"
+	"time"
+)
+
+// ControllerOptionsConfigKey is the key of the configuration file section which overrides the concurrency and rate
+// limiting settings of specific controllers, by controller name. It can only be set through the configuration file.
+const ControllerOptionsConfigKey = "controller-options"
+
+// ControllerOptions overrides the concurrency and rate limiting settings of a controller. Zero values keep the
+// defaults.
+type ControllerOptions struct {
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciliations, overriding max-concurrent-reconciles.
+	MaxConcurrentReconciles int `mapstructure:"max-concurrent-reconciles"`
+	// RateLimiterBaseDelay is the delay before retrying a failed reconciliation for the first time, doubled on each
+	// subsequent failure.
+	RateLimiterBaseDelay time.Duration `mapstructure:"rate-limiter-base-delay"`
+	// RateLimiterMaxDelay is the maximum delay before retrying a failed reconciliation.
+	RateLimiterMaxDelay time.Duration `mapstructure:"rate-limiter-max-delay"`
+	// RateLimiterQPS is the overall rate at which requests can be requeued with rate limiting.
+	RateLimiterQPS float64 `mapstructure:"rate-limiter-qps"`
+	// RateLimiterBurst is the number of requests which can be requeued with rate limiting at once.
+	RateLimiterBurst int `mapstructure:"rate-limiter-burst"`
+}
+
+// HasRateLimiterSettings returns true if at least one of the rate limiter settings is set.
+func (o ControllerOptions) HasRateLimiterSettings() bool {
+	return o.RateLimiterBaseDelay != 0 || o.RateLimiterMaxDelay != 0 || o.RateLimiterQPS != 0 || o.RateLimiterBurst != 0
+}
+
+// Validate returns an error if one of the settings is invalid.
+func (o ControllerOptions) Validate() error {
+	switch {
+	case o.MaxConcurrentReconciles < 0:
+		return fmt.Errorf("max-concurrent-reconciles must not be negative, but was %d", o.MaxConcurrentReconciles)
+	case o.RateLimiterBaseDelay < 0 || o.RateLimiterMaxDelay < 0:
+		return fmt.Errorf("rate limiter delays must not be negative")
+	case o.RateLimiterMaxDelay != 0 && o.RateLimiterBaseDelay > o.RateLimiterMaxDelay:
+		return fmt.Errorf("rate-limiter-base-delay %s must not be greater than rate-limiter-max-delay %s", o.RateLimiterBaseDelay, o.RateLimiterMaxDelay)
+	case o.RateLimiterQPS < 0 || o.RateLimiterBurst < 0:
+		return fmt.Errorf("rate-limiter-qps and rate-limiter-burst must not be negative")
+	}
+	return nil
+}
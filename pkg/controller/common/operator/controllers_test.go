@@ -0,0 +1,35 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package operator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControllerOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ControllerOptions
+		wantErr bool
+	}{
+		{name: "empty", options: ControllerOptions{}},
+		{name: "valid", options: ControllerOptions{MaxConcurrentReconciles: 10, RateLimiterBaseDelay: time.Second, RateLimiterMaxDelay: time.Minute, RateLimiterQPS: 5, RateLimiterBurst: 50}},
+		{name: "only base delay", options: ControllerOptions{RateLimiterBaseDelay: time.Hour}},
+		{name: "negative concurrency", options: ControllerOptions{MaxConcurrentReconciles: -1}, wantErr: true},
+		{name: "negative delay", options: ControllerOptions{RateLimiterMaxDelay: -time.Second}, wantErr: true},
+		{name: "base delay greater than max delay", options: ControllerOptions{RateLimiterBaseDelay: time.Hour, RateLimiterMaxDelay: time.Minute}, wantErr: true},
+		{name: "negative qps", options: ControllerOptions{RateLimiterQPS: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.options.Validate() != nil)
+		})
+	}
+	assert.False(t, ControllerOptions{MaxConcurrentReconciles: 1}.HasRateLimiterSettings())
+	assert.True(t, ControllerOptions{RateLimiterQPS: 1}.HasRateLimiterSettings())
+}
@@ -5,43 +5,81 @@
 package operator
 
 const (
-	AutoPortForwardFlag                  = "auto-port-forward"
-	CADirFlag                            = "ca-dir"
-	CACertRotateBeforeFlag               = "ca-cert-rotate-before"
-	CACertValidityFlag                   = "ca-cert-validity"
-	CertRotateBeforeFlag                 = "cert-rotate-before"
-	CertValidityFlag                     = "cert-validity"
-	ConfigFlag                           = "config"
-	ContainerRegistryFlag                = "container-registry"
-	ContainerRepositoryFlag              = "container-repository"
-	ContainerSuffixFlag                  = "container-suffix"
-	DebugHTTPListenFlag                  = "debug-http-listen"
-	DisableConfigWatch                   = "disable-config-watch"
-	DisableTelemetryFlag                 = "disable-telemetry"
-	DistributionChannelFlag              = "distribution-channel"
-	ElasticsearchClientTimeout           = "elasticsearch-client-timeout"
-	ElasticsearchObservationIntervalFlag = "elasticsearch-observation-interval"
-	EnableLeaderElection                 = "enable-leader-election"
-	EnableTracingFlag                    = "enable-tracing"
-	EnableWebhookFlag                    = "enable-webhook"
-	EnforceRBACOnRefsFlag                = "enforce-rbac-on-refs"
-	ExposedNodeLabels                    = "exposed-node-labels"
-	PasswordHashCacheSize                = "password-hash-cache-size"
-	IPFamilyFlag                         = "ip-family"
-	KubeClientTimeout                    = "kube-client-timeout"
-	KubeClientQPS                        = "kube-client-qps"
-	ManageWebhookCertsFlag               = "manage-webhook-certs"
-	MaxConcurrentReconcilesFlag          = "max-concurrent-reconciles"
-	MetricsPortFlag                      = "metrics-port"
-	MetricsHostFlag                      = "metrics-host"
-	NamespacesFlag                       = "namespaces"
-	OperatorNamespaceFlag                = "operator-namespace"
-	SetDefaultSecurityContextFlag        = "set-default-security-context"
-	TelemetryIntervalFlag                = "telemetry-interval"
-	UBIOnlyFlag                          = "ubi-only"
-	ValidateStorageClassFlag             = "validate-storage-class"
-	WebhookCertDirFlag                   = "webhook-cert-dir"
-	WebhookNameFlag                      = "webhook-name"
-	WebhookSecretFlag                    = "webhook-secret"
-	WebhookPortFlag                      = "webhook-port"
+	AutoPortForwardFlag                    = "auto-port-forward"
+	BeatAutodiscoverClusterRoleFlag        = "beat-autodiscover-cluster-role"
+	CacheLabeledResourcesOnlyFlag          = "cache-labeled-resources-only"
+	CacheSyncPeriodFlag                    = "cache-sync-period"
+	CADirFlag                              = "ca-dir"
+	CACertRotateBeforeFlag                 = "ca-cert-rotate-before"
+	CACertValidityFlag                     = "ca-cert-validity"
+	CertRotateBeforeFlag                   = "cert-rotate-before"
+	CertValidityFlag                       = "cert-validity"
+	ConfigFlag                             = "config"
+	ControllerSaturationReadinessFlag      = "controller-saturation-readiness"
+	ControllerSaturationThresholdFlag      = "controller-saturation-threshold"
+	ContainerRegistryFlag                  = "container-registry"
+	ContainerRepositoryFlag                = "container-repository"
+	ContainerSuffixFlag                    = "container-suffix"
+	DebugHTTPListenFlag                    = "debug-http-listen"
+	DisableConfigWatch                     = "disable-config-watch"
+	DisableControllersFlag                 = "disable-controllers"
+	DisableTelemetryFlag                   = "disable-telemetry"
+	DistributionChannelFlag                = "distribution-channel"
+	ElasticsearchClientAuditLogFlag        = "elasticsearch-client-audit-log"
+	ElasticsearchClientBurst               = "elasticsearch-client-burst"
+	ElasticsearchClientGlobalBurst         = "elasticsearch-client-global-burst"
+	ElasticsearchClientGlobalQPS           = "elasticsearch-client-global-qps"
+	ElasticsearchClientIdleConnTimeout     = "elasticsearch-client-idle-conn-timeout"
+	ElasticsearchClientMaxConnsPerHost     = "elasticsearch-client-max-conns-per-host"
+	ElasticsearchClientMaxIdleConnsPerHost = "elasticsearch-client-max-idle-conns-per-host"
+	ElasticsearchClientQPS                 = "elasticsearch-client-qps"
+	ElasticsearchClientTimeout             = "elasticsearch-client-timeout"
+	ElasticsearchHealthBatchWindowFlag     = "elasticsearch-health-batch-window"
+	ElasticsearchObservationIntervalFlag   = "elasticsearch-observation-interval"
+	EnableLeaderElection                   = "enable-leader-election"
+	EnableShardingFlag                     = "enable-sharding"
+	EnableTracingFlag                      = "enable-tracing"
+	EnableWebhookFlag                      = "enable-webhook"
+	EnforceRBACOnRefsFlag                  = "enforce-rbac-on-refs"
+	EventsDedupWindowFlag                  = "events-dedup-window"
+	EventsMinSeverityFlag                  = "events-min-severity"
+	EventsRateLimitBurstFlag               = "events-rate-limit-burst"
+	EventsRateLimitQPSFlag                 = "events-rate-limit-qps"
+	ExposedNodeLabels                      = "exposed-node-labels"
+	ExternalSecretPathPrefixesFlag         = "external-secret-path-prefixes"
+	ExternalSecretProvidersFlag            = "external-secret-providers"
+	HealthProbeBindAddressFlag             = "health-probe-bind-address"
+	ImageMappingsFlag                      = "image-mappings"
+	PasswordHashCacheSize                  = "password-hash-cache-size"
+	ProfileFlag                            = "profile"
+	IPFamilyFlag                           = "ip-family"
+	IPFamilyPolicyFlag                     = "ip-family-policy"
+	KubeClientTimeout                      = "kube-client-timeout"
+	KubeClientQPS                          = "kube-client-qps"
+	KubeClientBurst                        = "kube-client-burst"
+	LocalVolumeFailurePolicyFlag           = "local-volume-failure-policy"
+	MaintenanceModeFlag                    = "maintenance-mode"
+	ManageWebhookCertsFlag                 = "manage-webhook-certs"
+	MaxConcurrentReconcilesFlag            = "max-concurrent-reconciles"
+	MetadataOnlyWatchesFlag                = "metadata-only-watches"
+	MetricsPortFlag                        = "metrics-port"
+	MetricsHostFlag                        = "metrics-host"
+	NamespaceLabelSelectorFlag             = "namespace-label-selector"
+	NamespacesFlag                         = "namespaces"
+	OperatorNamespaceFlag                  = "operator-namespace"
+	ReconciliationStalledRemediationFlag   = "reconciliation-stalled-remediation"
+	ReconciliationStalledThresholdFlag     = "reconciliation-stalled-threshold"
+	RequireImageDigestsFlag                = "require-image-digests"
+	ServiceAccountTokenRotationFlag        = "service-account-token-rotation"
+	SetDefaultSecurityContextFlag          = "set-default-security-context"
+	ShardingScopeFlag                      = "sharding-scope"
+	ShutdownGracePeriodFlag                = "shutdown-grace-period"
+	TelemetryIntervalFlag                  = "telemetry-interval"
+	TierStorageClassesFlag                 = "tier-storage-classes"
+	UBIOnlyFlag                            = "ubi-only"
+	ValidateStorageClassFlag               = "validate-storage-class"
+	WebhookCertDirFlag                     = "webhook-cert-dir"
+	WebhookNameFlag                        = "webhook-name"
+	WebhookSecretFlag                      = "webhook-secret"
+	WebhookPortFlag                        = "webhook-port"
 )
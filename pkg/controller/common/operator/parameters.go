@@ -11,18 +11,42 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/about"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/queue"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/sharding"
 	esvalidation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/validation"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/cryptutil"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
 )
 
+// LocalVolumeFailurePolicy defines how the operator handles the Pods of an Elasticsearch cluster that cannot be
+// scheduled because their volumes are bound to a Kubernetes node that has been removed from the cluster.
+type LocalVolumeFailurePolicy string
+
+const (
+	// LocalVolumeFailurePolicyWait leaves the Pods Pending until the Kubernetes node is back.
+	LocalVolumeFailurePolicyWait LocalVolumeFailurePolicy = "Wait"
+	// LocalVolumeFailurePolicyRecreate deletes the volume claims and the Pods, which are then recreated with new empty
+	// volumes, for Elasticsearch to recover the data from replicas.
+	LocalVolumeFailurePolicyRecreate LocalVolumeFailurePolicy = "Recreate"
+)
+
 // Parameters contain parameters to create new operators.
 type Parameters struct {
+	// BeatAutodiscoverClusterRole is the name of the ClusterRole bound to the ServiceAccount of the Beats using
+	// autodiscover. No RBAC resources are created for autodiscover if empty.
+	BeatAutodiscoverClusterRole string
 	// ElasticsearchObservationInterval is the interval between (asynchronous) observations of Elasticsearch health.
 	ElasticsearchObservationInterval time.Duration
+	// ElasticsearchHealthBatchWindow delays the reconciliations triggered by Elasticsearch health changes, so that the
+	// changes happening within this window are reported with a single status update.
+	ElasticsearchHealthBatchWindow time.Duration
 	// ExposedNodeLabels holds regular expressions of node labels which are allowed to be automatically set as annotations on Elasticsearch Pods.
 	ExposedNodeLabels esvalidation.NodeLabels
+	// ClusterName is the name of the remote Kubernetes cluster in which the resources are managed, empty for the
+	// cluster the operator runs in.
+	ClusterName string
 	// OperatorNamespace is the control plane namespace of the operator.
 	OperatorNamespace string
 	// OperatorInfo is information about the operator
@@ -33,17 +57,41 @@ type Parameters struct {
 	PasswordHasher cryptutil.PasswordHasher
 	// IPFamily represents the IP family to use when creating configuration and services.
 	IPFamily corev1.IPFamily
+	// LocalVolumeFailurePolicy defines how to handle Elasticsearch Pods whose local volumes are lost with their Kubernetes node.
+	LocalVolumeFailurePolicy LocalVolumeFailurePolicy
 	// GlobalCA is an optionally configured, globally shared CA to be used for all managed resources.
 	GlobalCA *certificates.CA
-	// CACertRotation defines the rotation params for CA certificates.
-	CACertRotation certificates.RotationParams
-	// CertRotation defines the rotation params for non-CA certificates.
-	CertRotation certificates.RotationParams
+	// CACertRotation defines the rotation params for CA certificates, which can be updated while the operator is running.
+	CACertRotation *certificates.DynamicRotationParams
+	// CertRotation defines the rotation params for non-CA certificates, which can be updated while the operator is running.
+	CertRotation *certificates.DynamicRotationParams
 	// MaxConcurrentReconciles controls the number of goroutines per controller.
 	MaxConcurrentReconciles int
+	// ControllerOptions overrides the concurrency and rate limiting settings of specific controllers, by name.
+	ControllerOptions map[string]ControllerOptions
+	// QueueMonitor optionally tracks the workqueues of the controllers to report their saturation.
+	QueueMonitor *queue.Monitor
+	// CacheSyncPeriod is the period after which all the resources watched by the operator are reconciled again, even
+	// if they did not change.
+	CacheSyncPeriod time.Duration
+	// Sharding optionally distributes the reconciliation of resources between the active operator replicas.
+	Sharding *sharding.Membership
+	// ReconciliationStalledThreshold is the duration after which a resource with changes still pending is reported as
+	// stalled. Stalled reconciliations are not detected if zero.
+	ReconciliationStalledThreshold time.Duration
+	// ReconciliationStalledRemediation enables the known remediations of stalled reconciliations.
+	ReconciliationStalledRemediation bool
+	// ShutdownGracePeriod is the maximum duration during which in-flight reconciliations can complete when the operator
+	// shuts down, before being interrupted.
+	ShutdownGracePeriod time.Duration
+	// ServiceAccountTokenRotation is the interval after which the Elasticsearch service account tokens created for the
+	// associations are replaced. The tokens are not rotated if zero.
+	ServiceAccountTokenRotation time.Duration
 	// SetDefaultSecurityContext enables setting the default security context
 	// with fsGroup=1000 for Elasticsearch 8.0+ Pods. Ignored pre-8.0
 	SetDefaultSecurityContext bool
+	// TierStorageClasses maps data tier roles to the storage class of the Elasticsearch volume claims that do not specify any.
+	TierStorageClasses map[esv1.NodeRole]string
 	// ValidateStorageClass specifies whether the operator should retrieve storage classes to verify volume expansion support.
 	// Can be disabled if cluster-wide storage class RBAC access is not available.
 	ValidateStorageClass bool
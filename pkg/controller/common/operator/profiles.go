@@ -0,0 +1,47 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package operator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultProfile keeps the default value of all the settings.
+	DefaultProfile = "default"
+	// ScaleProfile tunes the operator for installations managing hundreds of Elastic Stack resources: it allows more
+	// queries to the Kubernetes API, resyncs all the resources less frequently, and observes Elasticsearch clusters less
+	// frequently, reporting their health changes with fewer status updates.
+	ScaleProfile = "scale"
+)
+
+// profiles are the default values of the settings of each profile, by setting name. Settings which are explicitly
+// configured take precedence over the profile.
+var profiles = map[string]map[string]any{
+	DefaultProfile: {},
+	ScaleProfile: {
+		KubeClientQPS:                        50,
+		KubeClientBurst:                      100,
+		CacheSyncPeriodFlag:                  "24h",
+		ElasticsearchObservationIntervalFlag: "30s",
+		ElasticsearchHealthBatchWindowFlag:   "15s",
+	},
+}
+
+// ProfileSettings returns the default values of the settings of the given profile, by setting name.
+func ProfileSettings(profile string) (map[string]any, error) {
+	settings, exists := profiles[profile]
+	if !exists {
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("unknown profile %q, must be one of %s", profile, strings.Join(names, ", "))
+	}
+	return settings, nil
+}
@@ -0,0 +1,25 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileSettings(t *testing.T) {
+	settings, err := ProfileSettings(DefaultProfile)
+	require.NoError(t, err)
+	require.Empty(t, settings)
+
+	settings, err = ProfileSettings(ScaleProfile)
+	require.NoError(t, err)
+	require.Equal(t, 50, settings[KubeClientQPS])
+	require.Equal(t, "24h", settings[CacheSyncPeriodFlag])
+
+	_, err = ProfileSettings("unknown")
+	require.EqualError(t, err, `unknown profile "unknown", must be one of default, scale`)
+}
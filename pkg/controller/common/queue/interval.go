@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package queue
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ReconcileIntervalAnnotation can be set on a resource to reconcile it periodically at the given interval, for example
+// `30s` or `10m`, instead of only when a change is detected. A successful reconciliation requesting to be retried
+// earlier is retried earlier, failed reconciliations are retried according to the controller rate limiter.
+const ReconcileIntervalAnnotation = "eck.k8s.elastic.co/reconcile-interval"
+
+// Rescheduler is implemented by the workqueues which can reconcile items periodically.
+type Rescheduler interface {
+	// SetReconcileInterval sets the interval at which the given item is reconciled, 0 to disable periodic
+	// reconciliations.
+	SetReconcileInterval(item reconcile.Request, interval time.Duration)
+}
+
+// ReconcileInterval returns the interval at which the given resource must be reconciled from its annotations, or 0 if
+// it is not set or invalid.
+func ReconcileInterval(obj client.Object) time.Duration {
+	value, exists := obj.GetAnnotations()[ReconcileIntervalAnnotation]
+	if !exists {
+		return 0
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		return 0
+	}
+	return interval
+}
+
+// intervals holds the reconciliation intervals of the items of a workqueue.
+type intervals struct {
+	mutex sync.RWMutex
+	byKey map[reconcile.Request]time.Duration
+}
+
+func (i *intervals) SetReconcileInterval(item reconcile.Request, interval time.Duration) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if interval <= 0 {
+		delete(i.byKey, item)
+		return
+	}
+	i.byKey[item] = interval
+}
+
+func (i *intervals) interval(item reconcile.Request) time.Duration {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	return i.byKey[item]
+}
@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func withInterval(name, interval string) *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns", Name: name, Annotations: map[string]string{ReconcileIntervalAnnotation: interval},
+	}}
+}
+
+func TestReconcileInterval(t *testing.T) {
+	assert.Equal(t, time.Duration(0), ReconcileInterval(&corev1.Secret{}))
+	assert.Equal(t, 30*time.Second, ReconcileInterval(withInterval("a", "30s")))
+	assert.Equal(t, 10*time.Minute, ReconcileInterval(withInterval("a", "10m")))
+	assert.Equal(t, time.Duration(0), ReconcileInterval(withInterval("a", "-1m")))
+	assert.Equal(t, time.Duration(0), ReconcileInterval(withInterval("a", "often")))
+}
+
+func TestMonitor_NewQueue_ReconcileInterval(t *testing.T) {
+	q := NewMonitor(0).NewQueue("test", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	h := EnqueueRequestForObject[*corev1.Secret]()
+	h.Create(context.Background(), event.TypedCreateEvent[*corev1.Secret]{Object: withInterval("canary", "10ms")}, q)
+	h.Create(context.Background(), event.TypedCreateEvent[*corev1.Secret]{Object: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "stable"}}}, q)
+
+	// both reconciliations succeed
+	for i := 0; i < 2; i++ {
+		item, shutdown := q.Get()
+		require.False(t, shutdown)
+		q.Forget(item)
+		q.Done(item)
+	}
+	// only the resource with a reconciliation interval is reconciled again
+	require.Eventually(t, func() bool { return q.Len() == 1 }, 10*time.Second, 5*time.Millisecond)
+	item, _ := q.Get()
+	assert.Equal(t, request("canary"), item)
+	q.Done(item)
+
+	// deleted resources are no longer reconciled periodically
+	h.Delete(context.Background(), event.TypedDeleteEvent[*corev1.Secret]{Object: withInterval("canary", "10ms")}, q)
+	item, _ = q.Get()
+	q.Forget(item)
+	q.Done(item)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 0, q.Len())
+}
@@ -0,0 +1,283 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package queue
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultSaturationThreshold is the default duration after which a controller with items waiting to be processed
+// is considered wedged.
+const DefaultSaturationThreshold = 10 * time.Minute
+
+var (
+	longestWaitingDesc = prometheus.NewDesc(
+		"elastic_controller_queue_longest_waiting_seconds",
+		"Age in seconds of the item which has been waiting the longest to be processed by the controller",
+		[]string{"controller"}, nil,
+	)
+	saturatedDesc = prometheus.NewDesc(
+		"elastic_controller_queue_saturated",
+		"Whether the controller has had items waiting to be processed for longer than the saturation threshold (1) or not (0)",
+		[]string{"controller"}, nil,
+	)
+)
+
+// Stats describes the state of the workqueue of a controller.
+type Stats struct {
+	// Depth is the number of items waiting to be processed.
+	Depth int
+	// InFlight is the number of items currently being processed.
+	InFlight int
+	// Retries is the total number of items requeued with rate limiting since the controller started.
+	Retries int64
+	// LongestWaiting is the age of the item which has been waiting the longest to be processed.
+	LongestWaiting time.Duration
+}
+
+// Monitor keeps track of the workqueues of all the controllers, to report their saturation.
+type Monitor struct {
+	mutex     sync.RWMutex
+	queues    map[string]*monitoredQueue
+	threshold time.Duration
+	now       func() time.Time
+}
+
+// NewMonitor returns a new Monitor which considers a controller wedged if an item has been waiting to be processed
+// for longer than the given threshold.
+func NewMonitor(threshold time.Duration) *Monitor {
+	if threshold <= 0 {
+		threshold = DefaultSaturationThreshold
+	}
+	return &Monitor{
+		queues:    map[string]*monitoredQueue{},
+		threshold: threshold,
+		now:       time.Now,
+	}
+}
+
+// NewQueue is meant to be used as controller.Options.NewQueue. It creates a rate limiting queue processing items by
+// priority, see PriorityAnnotation, and tracks it under the name of the controller.
+func (m *Monitor) NewQueue(
+	controllerName string,
+	rateLimiter workqueue.TypedRateLimiter[reconcile.Request],
+) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	limiter := &recordingRateLimiter{TypedRateLimiter: rateLimiter, delays: map[reconcile.Request]time.Duration{}}
+	p := &priorities{byKey: map[reconcile.Request]int{}}
+	q := &monitoredQueue{
+		TypedRateLimitingInterface: workqueue.NewTypedRateLimitingQueueWithConfig(limiter, workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{
+			Name: controllerName,
+			DelayingQueue: workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[reconcile.Request]{
+				Name: controllerName,
+				Queue: workqueue.NewTypedWithConfig(workqueue.TypedQueueConfig[reconcile.Request]{
+					Name:  controllerName,
+					Queue: newPriorityStorage(p),
+				}),
+			}),
+		}),
+		priorities:  p,
+		intervals:   &intervals{byKey: map[reconcile.Request]time.Duration{}},
+		rateLimiter: limiter,
+		now:         m.now,
+		waiting:     map[reconcile.Request]time.Time{},
+		processing:  map[reconcile.Request]struct{}{},
+		succeeded:   map[reconcile.Request]struct{}{},
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.queues[controllerName] = q
+	return q
+}
+
+// Stats returns the current stats of all the tracked controllers, by controller name.
+func (m *Monitor) Stats() map[string]Stats {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	stats := make(map[string]Stats, len(m.queues))
+	for name, q := range m.queues {
+		stats[name] = q.stats()
+	}
+	return stats
+}
+
+// Check implements healthz.Checker. It returns an error describing all the controllers which have had items waiting
+// for longer than the saturation threshold. It is only meant to be used as a readiness check if a saturated operator
+// should stop receiving traffic, as it also makes the webhook endpoints served by the operator unavailable.
+func (m *Monitor) Check(_ *http.Request) error {
+	var wedged []string
+	for name, stats := range m.Stats() {
+		if stats.LongestWaiting > m.threshold {
+			wedged = append(wedged, fmt.Sprintf(
+				"%s (depth: %d, in flight: %d, retries: %d, longest waiting: %s)",
+				name, stats.Depth, stats.InFlight, stats.Retries, stats.LongestWaiting.Round(time.Second),
+			))
+		}
+	}
+	if len(wedged) == 0 {
+		return nil
+	}
+	sort.Strings(wedged)
+	return fmt.Errorf("controllers with items waiting for more than %s: %s", m.threshold, strings.Join(wedged, ", "))
+}
+
+// Describe implements prometheus.Collector.
+func (m *Monitor) Describe(ch chan<- *prometheus.Desc) {
+	ch <- longestWaitingDesc
+	ch <- saturatedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (m *Monitor) Collect(ch chan<- prometheus.Metric) {
+	for name, stats := range m.Stats() {
+		ch <- prometheus.MustNewConstMetric(longestWaitingDesc, prometheus.GaugeValue, stats.LongestWaiting.Seconds(), name)
+		saturated := 0.0
+		if stats.LongestWaiting > m.threshold {
+			saturated = 1
+		}
+		ch <- prometheus.MustNewConstMetric(saturatedDesc, prometheus.GaugeValue, saturated, name)
+	}
+}
+
+var (
+	_ prometheus.Collector = &Monitor{}
+	_ Prioritizer          = &monitoredQueue{}
+	_ Rescheduler          = &monitoredQueue{}
+)
+
+// monitoredQueue records when items are added to and retrieved from the underlying queue, and the priorities and
+// reconciliation intervals of the items.
+type monitoredQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	*priorities
+	*intervals
+
+	rateLimiter *recordingRateLimiter
+	now         func() time.Time
+	mutex       sync.Mutex
+	waiting     map[reconcile.Request]time.Time
+	processing  map[reconcile.Request]struct{}
+	// succeeded are the items being processed which have been forgotten by the rate limiter, meaning that their
+	// reconciliation succeeded
+	succeeded map[reconcile.Request]struct{}
+	retries   int64
+}
+
+// markWaiting records that the item is ready to be processed at the given time, unless it is already waiting.
+func (q *monitoredQueue) markWaiting(item reconcile.Request, readyAt time.Time) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if existing, exists := q.waiting[item]; !exists || readyAt.Before(existing) {
+		q.waiting[item] = readyAt
+	}
+}
+
+func (q *monitoredQueue) Add(item reconcile.Request) {
+	q.markWaiting(item, q.now())
+	q.TypedRateLimitingInterface.Add(item)
+}
+
+func (q *monitoredQueue) AddAfter(item reconcile.Request, duration time.Duration) {
+	q.markWaiting(item, q.now().Add(duration))
+	q.TypedRateLimitingInterface.AddAfter(item, duration)
+}
+
+func (q *monitoredQueue) AddRateLimited(item reconcile.Request) {
+	q.TypedRateLimitingInterface.AddRateLimited(item)
+	q.mutex.Lock()
+	q.retries++
+	q.mutex.Unlock()
+	// the item is considered waiting once the delay computed by the rate limiter has elapsed
+	q.markWaiting(item, q.now().Add(q.rateLimiter.lastDelay(item)))
+}
+
+func (q *monitoredQueue) Get() (reconcile.Request, bool) {
+	item, shutdown := q.TypedRateLimitingInterface.Get()
+	if shutdown {
+		return item, shutdown
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	delete(q.waiting, item)
+	q.processing[item] = struct{}{}
+	return item, shutdown
+}
+
+func (q *monitoredQueue) Forget(item reconcile.Request) {
+	q.mutex.Lock()
+	if _, processing := q.processing[item]; processing {
+		q.succeeded[item] = struct{}{}
+	}
+	q.mutex.Unlock()
+	q.TypedRateLimitingInterface.Forget(item)
+}
+
+func (q *monitoredQueue) Done(item reconcile.Request) {
+	q.mutex.Lock()
+	delete(q.processing, item)
+	_, succeeded := q.succeeded[item]
+	delete(q.succeeded, item)
+	q.mutex.Unlock()
+	q.TypedRateLimitingInterface.Done(item)
+	// schedule the next periodic reconciliation, unless an earlier one has been requested
+	if interval := q.interval(item); succeeded && interval > 0 {
+		q.AddAfter(item, interval)
+	}
+}
+
+func (q *monitoredQueue) stats() Stats {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	now := q.now()
+	var longest time.Duration
+	for _, readyAt := range q.waiting {
+		if age := now.Sub(readyAt); age > longest {
+			longest = age
+		}
+	}
+	return Stats{
+		Depth:          q.Len(),
+		InFlight:       len(q.processing),
+		Retries:        q.retries,
+		LongestWaiting: longest,
+	}
+}
+
+// recordingRateLimiter records the last delay computed for each item by the underlying rate limiter.
+type recordingRateLimiter struct {
+	workqueue.TypedRateLimiter[reconcile.Request]
+
+	mutex  sync.Mutex
+	delays map[reconcile.Request]time.Duration
+}
+
+func (r *recordingRateLimiter) When(item reconcile.Request) time.Duration {
+	delay := r.TypedRateLimiter.When(item)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.delays[item] = delay
+	return delay
+}
+
+func (r *recordingRateLimiter) Forget(item reconcile.Request) {
+	r.mutex.Lock()
+	delete(r.delays, item)
+	r.mutex.Unlock()
+	r.TypedRateLimiter.Forget(item)
+}
+
+func (r *recordingRateLimiter) lastDelay(item reconcile.Request) time.Duration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.delays[item]
+}
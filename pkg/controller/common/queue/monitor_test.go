@@ -0,0 +1,93 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package queue
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func request(name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: name}}
+}
+
+func TestMonitor(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMonitor(5 * time.Minute)
+	m.now = func() time.Time { return now }
+
+	es := m.NewQueue("elasticsearch", workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](0, 0))
+	kb := m.NewQueue("kibana", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer es.ShutDown()
+	defer kb.ShutDown()
+
+	es.Add(request("a"))
+	es.Add(request("b"))
+	es.AddRateLimited(request("c"))
+	kb.Add(request("d"))
+
+	// one item is processed
+	item, shutdown := kb.Get()
+	require.False(t, shutdown)
+	require.Equal(t, request("d"), item)
+
+	now = now.Add(time.Minute)
+	stats := m.Stats()
+	assert.Equal(t, Stats{Depth: 3, InFlight: 0, Retries: 1, LongestWaiting: time.Minute}, stats["elasticsearch"])
+	assert.Equal(t, Stats{Depth: 0, InFlight: 1, Retries: 0, LongestWaiting: 0}, stats["kibana"])
+	require.NoError(t, m.Check(nil))
+	assert.Equal(t, 4, testutil.CollectAndCount(m))
+
+	// items are waiting for too long in the elasticsearch queue
+	now = now.Add(10 * time.Minute)
+	err := m.Check(nil)
+	require.Error(t, err)
+	assert.Equal(t, "controllers with items waiting for more than 5m0s: elasticsearch (depth: 3, in flight: 0, retries: 1, longest waiting: 11m0s)", err.Error())
+	require.NoError(t, testutil.CollectAndCompare(m, strings.NewReader(`
+# HELP elastic_controller_queue_saturated Whether the controller has had items waiting to be processed for longer than the saturation threshold (1) or not (0)
+# TYPE elastic_controller_queue_saturated gauge
+elastic_controller_queue_saturated{controller="elasticsearch"} 1
+elastic_controller_queue_saturated{controller="kibana"} 0
+`), "elastic_controller_queue_saturated"))
+
+	// the queue is processed again
+	for i := 0; i < 3; i++ {
+		item, _ := es.Get()
+		es.Forget(item)
+		es.Done(item)
+	}
+	kb.Done(item)
+	assert.Equal(t, Stats{Retries: 1}, m.Stats()["elasticsearch"])
+	assert.Equal(t, Stats{}, m.Stats()["kibana"])
+	require.NoError(t, m.Check(nil))
+}
+
+func TestMonitor_AddAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMonitor(0)
+	m.now = func() time.Time { return now }
+	q := m.NewQueue("elasticsearch", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+
+	assert.Equal(t, DefaultSaturationThreshold, m.threshold)
+
+	// items requeued in the future are not considered waiting until they are due
+	q.AddAfter(request("a"), time.Hour)
+	now = now.Add(30 * time.Minute)
+	assert.Equal(t, time.Duration(0), m.Stats()["elasticsearch"].LongestWaiting)
+	now = now.Add(time.Hour)
+	assert.Equal(t, 30*time.Minute, m.Stats()["elasticsearch"].LongestWaiting)
+	// an earlier request for the same item takes precedence
+	q.Add(request("a"))
+	assert.Equal(t, 30*time.Minute, m.Stats()["elasticsearch"].LongestWaiting)
+}
@@ -0,0 +1,199 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package queue
+
+import (
+	"container/heap"
+	"context"
+	"strconv"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// PriorityAnnotation can be set on a resource to have its reconciliations processed before the ones of resources with
+// a lower priority, when the controller has several items waiting to be processed. Priorities are integers, resources
+// without this annotation or with an invalid value have a priority of 0.
+const PriorityAnnotation = "eck.k8s.elastic.co/reconcile-priority"
+
+// Prioritizer is implemented by the workqueues which process items by priority.
+type Prioritizer interface {
+	// SetPriority sets the priority of the given item, which applies from the next time it is added to the queue.
+	SetPriority(item reconcile.Request, priority int)
+	// ForgetPriority resets the priority of the given item to the default one.
+	ForgetPriority(item reconcile.Request)
+}
+
+// Priority returns the priority of the given resource from its annotations.
+func Priority(obj client.Object) int {
+	value, exists := obj.GetAnnotations()[PriorityAnnotation]
+	if !exists {
+		return 0
+	}
+	priority, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return priority
+}
+
+// EnqueueRequestForObject enqueues a request for the name and namespace of the resource, with the priority and the
+// reconciliation interval specified by its annotations if the queue supports them. It is meant to be used for the
+// resources reconciled by a controller, instead of handler.EnqueueRequestForObject.
+func EnqueueRequestForObject[T client.Object]() handler.TypedEventHandler[T, reconcile.Request] {
+	return handler.TypedFuncs[T, reconcile.Request]{
+		CreateFunc: func(_ context.Context, e event.TypedCreateEvent[T], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q, e.Object)
+		},
+		UpdateFunc: func(_ context.Context, e event.TypedUpdateEvent[T], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q, e.ObjectNew)
+		},
+		DeleteFunc: func(_ context.Context, e event.TypedDeleteEvent[T], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			item := requestFor(e.Object)
+			if p, ok := q.(Prioritizer); ok {
+				p.ForgetPriority(item)
+			}
+			if r, ok := q.(Rescheduler); ok {
+				r.SetReconcileInterval(item, 0)
+			}
+			q.Add(item)
+		},
+		GenericFunc: func(_ context.Context, e event.TypedGenericEvent[T], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q, e.Object)
+		},
+	}
+}
+
+func requestFor(obj client.Object) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}}
+}
+
+func enqueue(q workqueue.TypedRateLimitingInterface[reconcile.Request], obj client.Object) {
+	item := requestFor(obj)
+	if p, ok := q.(Prioritizer); ok {
+		p.SetPriority(item, Priority(obj))
+	}
+	if r, ok := q.(Rescheduler); ok {
+		r.SetReconcileInterval(item, ReconcileInterval(obj))
+	}
+	q.Add(item)
+}
+
+// priorities holds the priorities of the items of a workqueue.
+type priorities struct {
+	mutex sync.RWMutex
+	byKey map[reconcile.Request]int
+}
+
+func (p *priorities) SetPriority(item reconcile.Request, priority int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if priority == 0 {
+		delete(p.byKey, item)
+		return
+	}
+	p.byKey[item] = priority
+}
+
+func (p *priorities) ForgetPriority(item reconcile.Request) {
+	p.SetPriority(item, 0)
+}
+
+func (p *priorities) get(item reconcile.Request) int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.byKey[item]
+}
+
+// priorityStorage is a workqueue.Queue returning the items with the highest priority first, and the items with the
+// same priority in the order they were added.
+type priorityStorage struct {
+	priorities *priorities
+	items      priorityHeap
+	indexes    map[reconcile.Request]int
+	sequence   uint64
+}
+
+var _ workqueue.Queue[reconcile.Request] = &priorityStorage{}
+
+func newPriorityStorage(p *priorities) *priorityStorage {
+	s := &priorityStorage{priorities: p, indexes: map[reconcile.Request]int{}}
+	s.items.indexes = s.indexes
+	return s
+}
+
+// Touch is called when an item waiting to be processed is added again: its priority may have changed.
+func (s *priorityStorage) Touch(item reconcile.Request) {
+	index, exists := s.indexes[item]
+	if !exists {
+		return
+	}
+	if priority := s.priorities.get(item); priority != s.items.entries[index].priority {
+		s.items.entries[index].priority = priority
+		heap.Fix(&s.items, index)
+	}
+}
+
+func (s *priorityStorage) Push(item reconcile.Request) {
+	s.sequence++
+	heap.Push(&s.items, priorityEntry{item: item, priority: s.priorities.get(item), sequence: s.sequence})
+}
+
+func (s *priorityStorage) Len() int {
+	return s.items.Len()
+}
+
+func (s *priorityStorage) Pop() reconcile.Request {
+	entry := heap.Pop(&s.items).(priorityEntry) //nolint:forcetypeassert
+	return entry.item
+}
+
+type priorityEntry struct {
+	item     reconcile.Request
+	priority int
+	sequence uint64
+}
+
+// priorityHeap implements heap.Interface, keeping track of the index of each item.
+type priorityHeap struct {
+	entries []priorityEntry
+	indexes map[reconcile.Request]int
+}
+
+func (h priorityHeap) Len() int {
+	return len(h.entries)
+}
+
+func (h priorityHeap) Less(i, j int) bool {
+	if h.entries[i].priority != h.entries[j].priority {
+		return h.entries[i].priority > h.entries[j].priority
+	}
+	return h.entries[i].sequence < h.entries[j].sequence
+}
+
+func (h priorityHeap) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.indexes[h.entries[i].item] = i
+	h.indexes[h.entries[j].item] = j
+}
+
+func (h *priorityHeap) Push(x any) {
+	entry := x.(priorityEntry) //nolint:forcetypeassert
+	h.indexes[entry.item] = len(h.entries)
+	h.entries = append(h.entries, entry)
+}
+
+func (h *priorityHeap) Pop() any {
+	last := len(h.entries) - 1
+	entry := h.entries[last]
+	h.entries = h.entries[:last]
+	delete(h.indexes, entry.item)
+	return entry
+}
@@ -0,0 +1,76 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func withPriority(name, priority string) *corev1.Secret {
+	obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}}
+	if priority != "" {
+		obj.Annotations = map[string]string{PriorityAnnotation: priority}
+	}
+	return obj
+}
+
+func TestPriority(t *testing.T) {
+	assert.Equal(t, 0, Priority(withPriority("a", "")))
+	assert.Equal(t, 10, Priority(withPriority("a", "10")))
+	assert.Equal(t, -1, Priority(withPriority("a", "-1")))
+	assert.Equal(t, 0, Priority(withPriority("a", "high")))
+}
+
+func getAll(t *testing.T, q workqueue.TypedRateLimitingInterface[reconcile.Request]) []string {
+	t.Helper()
+	var names []string
+	for q.Len() > 0 {
+		item, shutdown := q.Get()
+		require.False(t, shutdown)
+		names = append(names, item.Name)
+		q.Done(item)
+	}
+	return names
+}
+
+func TestMonitor_NewQueue_Priorities(t *testing.T) {
+	q := NewMonitor(0).NewQueue("test", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	h := EnqueueRequestForObject[*corev1.Secret]()
+	ctx := context.Background()
+
+	// items with the same priority are processed in the order they were added
+	h.Create(ctx, event.TypedCreateEvent[*corev1.Secret]{Object: withPriority("dev-1", "")}, q)
+	h.Create(ctx, event.TypedCreateEvent[*corev1.Secret]{Object: withPriority("prod", "100")}, q)
+	h.Create(ctx, event.TypedCreateEvent[*corev1.Secret]{Object: withPriority("dev-2", "")}, q)
+	h.Create(ctx, event.TypedCreateEvent[*corev1.Secret]{Object: withPriority("canary", "10")}, q)
+	h.Create(ctx, event.TypedCreateEvent[*corev1.Secret]{Object: withPriority("test", "-1")}, q)
+	assert.Equal(t, []string{"prod", "canary", "dev-1", "dev-2", "test"}, getAll(t, q))
+
+	// the priority is remembered for items added without the handler, and can change while waiting
+	q.Add(request("dev-1"))
+	q.Add(request("prod"))
+	q.Add(request("test"))
+	h.Update(ctx, event.TypedUpdateEvent[*corev1.Secret]{ObjectOld: withPriority("test", "-1"), ObjectNew: withPriority("test", "1000")}, q)
+	assert.Equal(t, []string{"test", "prod", "dev-1"}, getAll(t, q))
+
+	// the priority is forgotten once the resource is deleted
+	h.Delete(ctx, event.TypedDeleteEvent[*corev1.Secret]{Object: withPriority("prod", "100")}, q)
+	q.Add(request("dev-1"))
+	q.Add(request("prod"))
+	assert.Equal(t, []string{"prod", "dev-1"}, getAll(t, q))
+	q.Add(request("dev-1"))
+	q.Add(request("prod"))
+	assert.Equal(t, []string{"dev-1", "prod"}, getAll(t, q))
+}
@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Dependencies describes everything the reconciliation of a resource depends on, as a map of unique keys
+// (such as the kind, namespace and name of a dependent Secret) to versions (such as its resourceVersion).
+type Dependencies map[string]string
+
+// AddObject adds the resource version of the given object, keyed by kind, namespace and name.
+func (d Dependencies) AddObject(kind string, obj client.Object) {
+	d[fmt.Sprintf("%s/%s/%s", kind, obj.GetNamespace(), obj.GetName())] = obj.GetResourceVersion()
+}
+
+// AddMissingObject records that the given object, which the reconciliation depends on, does not exist.
+func (d Dependencies) AddMissingObject(kind string, nsn types.NamespacedName) {
+	d[fmt.Sprintf("%s/%s/%s", kind, nsn.Namespace, nsn.Name)] = ""
+}
+
+// Hash returns a hash of all the dependencies, which does not depend on the order in which they were added.
+func (d Dependencies) Hash() string {
+	data := make(map[string][]byte, len(d))
+	for k, v := range d {
+		data[k] = []byte(v)
+	}
+	return contentHash(data)
+}
+
+// DependencyHashes records, for each resource, the hash of the dependencies of its last complete reconciliation.
+// A reconciliation can be skipped as long as the hash of its dependencies is unchanged and the recorded hash has not
+// expired, expiration being used to honour requeues requested by the last reconciliation.
+// It is safe for concurrent use.
+type DependencyHashes struct {
+	lock   sync.Mutex
+	hashes map[types.NamespacedName]dependencyHash
+	now    func() time.Time
+}
+
+type dependencyHash struct {
+	hash      string
+	expiresAt time.Time
+}
+
+// NewDependencyHashes returns an initialized DependencyHashes.
+func NewDependencyHashes() *DependencyHashes {
+	return &DependencyHashes{
+		hashes: make(map[types.NamespacedName]dependencyHash),
+		now:    time.Now,
+	}
+}
+
+// Unchanged returns true if the given hash is the one recorded for the resource and has not expired, along with the
+// time left before it expires.
+func (d *DependencyHashes) Unchanged(resource types.NamespacedName, hash string) (time.Duration, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	recorded, exists := d.hashes[resource]
+	if !exists || recorded.hash != hash {
+		return 0, false
+	}
+	remaining := recorded.expiresAt.Sub(d.now())
+	if remaining <= 0 {
+		delete(d.hashes, resource)
+		return 0, false
+	}
+	return remaining, true
+}
+
+// Record records the hash of the dependencies of a complete reconciliation of the resource, valid for the given duration.
+func (d *DependencyHashes) Record(resource types.NamespacedName, hash string, validity time.Duration) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.hashes[resource] = dependencyHash{hash: hash, expiresAt: d.now().Add(validity)}
+}
+
+// Forget removes the hash recorded for the resource, forcing its next reconciliation.
+func (d *DependencyHashes) Forget(resource types.NamespacedName) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.hashes, resource)
+}
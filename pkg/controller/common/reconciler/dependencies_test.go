@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDependencies_Hash(t *testing.T) {
+	secret := func(name, resourceVersion string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, ResourceVersion: resourceVersion}}
+	}
+	deps := Dependencies{"generation": "1"}
+	deps.AddObject("Secret", secret("a", "10"))
+	deps.AddObject("Secret", secret("b", "20"))
+	deps.AddMissingObject("ConfigMap", types.NamespacedName{Namespace: "ns", Name: "c"})
+
+	// insertion order does not matter
+	sameDeps := Dependencies{}
+	sameDeps.AddMissingObject("ConfigMap", types.NamespacedName{Namespace: "ns", Name: "c"})
+	sameDeps.AddObject("Secret", secret("b", "20"))
+	sameDeps.AddObject("Secret", secret("a", "10"))
+	sameDeps["generation"] = "1"
+	require.Equal(t, deps.Hash(), sameDeps.Hash())
+
+	// any change of version is detected
+	updatedDeps := Dependencies{"generation": "1"}
+	updatedDeps.AddObject("Secret", secret("a", "11"))
+	updatedDeps.AddObject("Secret", secret("b", "20"))
+	updatedDeps.AddMissingObject("ConfigMap", types.NamespacedName{Namespace: "ns", Name: "c"})
+	require.NotEqual(t, deps.Hash(), updatedDeps.Hash())
+
+	// as well as a created object
+	createdDeps := Dependencies{"generation": "1"}
+	createdDeps.AddObject("Secret", secret("a", "10"))
+	createdDeps.AddObject("Secret", secret("b", "20"))
+	createdDeps.AddObject("ConfigMap", &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "c", ResourceVersion: "1"}})
+	require.NotEqual(t, deps.Hash(), createdDeps.Hash())
+}
+
+func TestDependencyHashes(t *testing.T) {
+	es1 := types.NamespacedName{Namespace: "ns", Name: "es1"}
+	es2 := types.NamespacedName{Namespace: "ns", Name: "es2"}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	hashes := NewDependencyHashes()
+	hashes.now = func() time.Time { return now }
+
+	// nothing recorded yet
+	_, unchanged := hashes.Unchanged(es1, "hash")
+	require.False(t, unchanged)
+
+	hashes.Record(es1, "hash", 10*time.Minute)
+	remaining, unchanged := hashes.Unchanged(es1, "hash")
+	require.True(t, unchanged)
+	require.Equal(t, 10*time.Minute, remaining)
+	// different hash or different resource
+	_, unchanged = hashes.Unchanged(es1, "other-hash")
+	require.False(t, unchanged)
+	_, unchanged = hashes.Unchanged(es2, "hash")
+	require.False(t, unchanged)
+
+	// the hash is valid until it expires
+	now = now.Add(9 * time.Minute)
+	remaining, unchanged = hashes.Unchanged(es1, "hash")
+	require.True(t, unchanged)
+	require.Equal(t, time.Minute, remaining)
+	now = now.Add(time.Minute)
+	_, unchanged = hashes.Unchanged(es1, "hash")
+	require.False(t, unchanged)
+	require.Empty(t, hashes.hashes)
+
+	// forgotten hashes are not valid anymore
+	hashes.Record(es1, "hash", 10*time.Minute)
+	hashes.Forget(es1)
+	_, unchanged = hashes.Unchanged(es1, "hash")
+	require.False(t, unchanged)
+}
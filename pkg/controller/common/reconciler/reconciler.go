@@ -12,8 +12,11 @@ import (
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/csaupgrade"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -22,6 +25,11 @@ import (
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
 
+// FieldManager is the name of the field manager of the resources applied by the operator with server-side apply.
+// It matches the name of the operator binary, which is the field manager of the resources previously updated by the
+// operator, so that the ownership of their fields can be transferred to the apply operations.
+const FieldManager = "elastic-operator"
+
 // Params is a parameter object for the ReconcileResources function
 type Params struct {
 	// Context to be used in API requests
@@ -47,13 +55,18 @@ type Params struct {
 	PreUpdate func() error
 	// PostUpdate is called immediately after the resource is successfully updated.
 	PostUpdate func()
+	// ServerSideApply creates and updates the resource with a server-side apply patch of Expected instead of
+	// Create and Update calls. The operator then only owns the fields it sets: the fields set by other controllers,
+	// for example a service mesh injector or a vertical Pod autoscaler, are left untouched and do not lead to update
+	// conflicts. UpdateReconciled is not used in that case.
+	ServerSideApply bool
 }
 
 func (p Params) CheckNilValues() error {
 	if p.Reconciled == nil {
 		return errors.New("Reconciled must not be nil")
 	}
-	if p.UpdateReconciled == nil {
+	if p.UpdateReconciled == nil && !p.ServerSideApply {
 		return errors.New("UpdateReconciled must not be nil")
 	}
 	if p.NeedsUpdate == nil {
@@ -95,6 +108,10 @@ func ReconcileResource(params Params) error {
 			}
 		}
 
+		if params.ServerSideApply {
+			return apply(params, gvk)
+		}
+
 		// Copy the content of params.Expected into params.Reconciled.
 		// Unfortunately it's not straightforward to change the value of an interface underlying pointer,
 		// so we need a small bit of reflection here.
@@ -150,6 +167,19 @@ func ReconcileResource(params Params) error {
 				return err
 			}
 		}
+		if params.ServerSideApply {
+			if err := upgradeManagedFields(params); err != nil {
+				return err
+			}
+			if err := apply(params, gvk); err != nil {
+				return err
+			}
+			if params.PostUpdate != nil {
+				params.PostUpdate()
+			}
+			return nil
+		}
+
 		reconciledMeta, err := meta.Accessor(params.Reconciled)
 		if err != nil {
 			return err
@@ -182,3 +212,26 @@ func ReconcileResource(params Params) error {
 	}
 	return nil
 }
+
+// apply creates or updates the resource with a server-side apply patch of params.Expected, params.Reconciled contains
+// the resulting resource.
+func apply(params Params, gvk schema.GroupVersionKind) error {
+	// This will panic if params.Expected and params.Reconciled don't have the same underlying type, see create.
+	reflect.ValueOf(params.Reconciled).Elem().Set(reflect.ValueOf(params.Expected.DeepCopyObject()).Elem())
+	// the apply patch must specify the kind of the resource, but not the version of an existing resource so that it
+	// is applied regardless of the concurrent changes
+	params.Reconciled.GetObjectKind().SetGroupVersionKind(gvk)
+	params.Reconciled.SetResourceVersion("")
+	params.Reconciled.SetManagedFields(nil)
+	return params.Client.Patch(params.Context, params.Reconciled, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// upgradeManagedFields transfers the ownership of the fields of params.Reconciled previously updated by the operator
+// to its apply field manager, so that the fields not set anymore by the operator are removed by the next apply patch.
+func upgradeManagedFields(params Params) error {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(params.Reconciled, sets.New(FieldManager), FieldManager)
+	if err != nil || patch == nil {
+		return err
+	}
+	return params.Client.Patch(params.Context, params.Reconciled, client.RawPatch(types.JSONPatchType, patch))
+}
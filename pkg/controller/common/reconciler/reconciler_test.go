@@ -54,6 +54,7 @@ func TestReconcileResource(t *testing.T) {
 		argAssertion         func(args args)
 		exptectedErrorMsg    string
 		serverStateAssertion func(serverState corev1.Secret)
+		serverSideApply      bool
 	}{
 		{
 			name: "Error: Expected must not be nil",
@@ -98,6 +99,22 @@ func TestReconcileResource(t *testing.T) {
 			},
 			exptectedErrorMsg: "UpdateReconciled must not be nil",
 		},
+		{
+			name: "UpdateReconciled is not needed with server-side apply",
+			args: func() args {
+				return args{
+					Expected:   obj.DeepCopy(),
+					Reconciled: &corev1.Secret{},
+					NeedsUpdate: func() bool {
+						return false
+					},
+				}
+			},
+			serverSideApply: true,
+			serverStateAssertion: func(serverState corev1.Secret) {
+				require.Equal(t, obj.Name, serverState.Name)
+			},
+		},
 		{
 			name: "Create resource if not found",
 			args: func() args {
@@ -271,6 +288,7 @@ func TestReconcileResource(t *testing.T) {
 				Reconciled:       args.Reconciled,
 				NeedsUpdate:      args.NeedsUpdate,
 				UpdateReconciled: args.UpdateReconciled,
+				ServerSideApply:  tt.serverSideApply,
 			}
 
 			err := ReconcileResource(p)
@@ -292,3 +310,97 @@ func TestReconcileResource(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileResource_ServerSideApply(t *testing.T) {
+	ctx := context.Background()
+	c := k8s.NewFakeClient()
+	owner := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "owner", UID: "owner-uid"}}
+	reconcile := func(expected *corev1.ConfigMap, postUpdate func()) corev1.ConfigMap {
+		t.Helper()
+		var reconciled corev1.ConfigMap
+		require.NoError(t, ReconcileResource(Params{
+			Context:    ctx,
+			Client:     c,
+			Owner:      owner,
+			Expected:   expected,
+			Reconciled: &reconciled,
+			NeedsUpdate: func() bool {
+				return !reflect.DeepEqual(expected.Data, reconciled.Data)
+			},
+			PostUpdate:      postUpdate,
+			ServerSideApply: true,
+		}))
+		return reconciled
+	}
+	newConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"}, Data: data}
+	}
+
+	// create the resource
+	reconciled := reconcile(newConfigMap(map[string]string{"a": "b"}), nil)
+	require.Equal(t, map[string]string{"a": "b"}, reconciled.Data)
+	require.Equal(t, "owner", reconciled.OwnerReferences[0].Name)
+
+	// another controller sets an annotation
+	reconciled.Annotations = map[string]string{"injector": "true"}
+	require.NoError(t, c.Update(ctx, &reconciled))
+	resourceVersion := reconciled.ResourceVersion
+
+	// no update needed
+	updated := false
+	reconciled = reconcile(newConfigMap(map[string]string{"a": "b"}), func() { updated = true })
+	require.False(t, updated)
+	require.Equal(t, resourceVersion, reconciled.ResourceVersion)
+
+	// update the data, the annotation of the other controller is left untouched
+	reconciled = reconcile(newConfigMap(map[string]string{"a": "c"}), func() { updated = true })
+	require.True(t, updated)
+	var serverState corev1.ConfigMap
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(&reconciled), &serverState))
+	require.Equal(t, map[string]string{"a": "c"}, serverState.Data)
+	require.Equal(t, map[string]string{"injector": "true"}, serverState.Annotations)
+	require.Equal(t, serverState.ResourceVersion, reconciled.ResourceVersion)
+}
+
+func Test_upgradeManagedFields(t *testing.T) {
+	ctx := context.Background()
+	existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns",
+		Name:      "cm",
+		ManagedFields: []metav1.ManagedFieldsEntry{
+			{
+				Manager:    FieldManager,
+				Operation:  metav1.ManagedFieldsOperationUpdate,
+				APIVersion: "v1",
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:a":{}}}`)},
+			},
+			{
+				Manager:    "injector",
+				Operation:  metav1.ManagedFieldsOperationUpdate,
+				APIVersion: "v1",
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{"f:injector":{}}}}`)},
+			},
+		},
+	}}
+	c := k8s.NewFakeClient(existing)
+	var reconciled corev1.ConfigMap
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(existing), &reconciled))
+
+	require.NoError(t, upgradeManagedFields(Params{Context: ctx, Client: c, Reconciled: &reconciled}))
+	var serverState corev1.ConfigMap
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(existing), &serverState))
+	require.Len(t, serverState.ManagedFields, 2)
+	// the fields updated by the operator are now owned by its apply operations
+	require.Equal(t, FieldManager, serverState.ManagedFields[0].Manager)
+	require.Equal(t, metav1.ManagedFieldsOperationApply, serverState.ManagedFields[0].Operation)
+	// the fields of other managers are left untouched
+	require.Equal(t, existing.ManagedFields[1], serverState.ManagedFields[1])
+
+	// nothing to upgrade anymore
+	resourceVersion := serverState.ResourceVersion
+	require.NoError(t, upgradeManagedFields(Params{Context: ctx, Client: c, Reconciled: &serverState}))
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(existing), &serverState))
+	require.Equal(t, resourceVersion, serverState.ResourceVersion)
+}
@@ -6,7 +6,10 @@ package reconciler
 
 import (
 	"context"
-	"reflect"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -14,7 +17,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
@@ -38,39 +44,144 @@ func WithPostUpdate(f func()) func(p *Params) {
 
 // ReconcileSecret creates or updates the actual secret to match the expected one.
 // Existing annotations or labels that are not expected are preserved.
+// The Secret is written with a server-side apply patch, and only if its content differs from the expected one.
 func ReconcileSecret(ctx context.Context, c k8s.Client, expected corev1.Secret, owner client.Object, opts ...func(*Params)) (corev1.Secret, error) {
-	var reconciled corev1.Secret
-
-	params := Params{
-		Context:    ctx,
-		Client:     c,
-		Owner:      owner,
-		Expected:   &expected,
-		Reconciled: &reconciled,
-		NeedsUpdate: func() bool {
-			// update if expected labels and annotations are not there
-			return !maps.IsSubset(expected.Labels, reconciled.Labels) ||
-				!maps.IsSubset(expected.Annotations, reconciled.Annotations) ||
-				// or if secret data is not strictly equal
-				!reflect.DeepEqual(expected.Data, reconciled.Data)
-		},
-		UpdateReconciled: func() {
-			// set expected annotations and labels, but don't remove existing ones
-			// that may have been defaulted or set by the user on the existing resource
-			reconciled.Labels = maps.Merge(reconciled.Labels, expected.Labels)
-			reconciled.Annotations = maps.Merge(reconciled.Annotations, expected.Annotations)
-			reconciled.Data = expected.Data
-		},
+	// don't mutate expected (no side effects), make a copy
+	expected = *expected.DeepCopy()
+	if owner != nil {
+		if err := controllerutil.SetControllerReference(owner, &expected, scheme.Scheme); err != nil {
+			return corev1.Secret{}, err
+		}
 	}
+	return reconcileSecret(ctx, c, expected, nil, opts...)
+}
+
+// reconcileSecret applies the expected Secret if the actual one does not exist or does not contain the expected content.
+// The owner references of the actual Secret that match ownerToRemove are removed.
+func reconcileSecret(ctx context.Context, c k8s.Client, expected corev1.Secret, ownerToRemove metav1.Object, opts ...func(*Params)) (corev1.Secret, error) {
+	params := Params{Context: ctx, Client: c}
 	for _, opt := range opts {
 		opt(&params)
 	}
-	if err := ReconcileResource(params); err != nil {
-		return corev1.Secret{}, err
+	log := ulog.FromContext(ctx)
+
+	var reconciled corev1.Secret
+	err := c.Get(ctx, k8s.ExtractNamespacedName(&expected), &reconciled)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return corev1.Secret{}, fmt.Errorf("failed to get Secret %s/%s: %w", expected.Namespace, expected.Name, err)
+	}
+	if apierrors.IsNotFound(err) {
+		log.Info("Creating resource", "kind", "Secret", "namespace", expected.Namespace, "name", expected.Name)
+		applied := applyConfiguration(expected)
+		applied.Type = expected.Type
+		if err := c.Patch(ctx, &applied, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+			return corev1.Secret{}, err
+		}
+		return applied, nil
+	}
+
+	removeOwner := ownerToRemove != nil && k8s.HasOwner(&reconciled, ownerToRemove)
+	// skip the write if the expected content is already there
+	if !removeOwner &&
+		maps.IsSubset(expected.Labels, reconciled.Labels) &&
+		maps.IsSubset(expected.Annotations, reconciled.Annotations) &&
+		contentHash(expected.Data) == contentHash(reconciled.Data) {
+		return reconciled, nil
+	}
+
+	log.Info("Updating resource", "kind", "Secret", "namespace", expected.Namespace, "name", expected.Name)
+	if params.PreUpdate != nil {
+		if err := params.PreUpdate(); err != nil {
+			return corev1.Secret{}, err
+		}
+	}
+	if removeOwner || !hasOwnerReferences(reconciled, expected.OwnerReferences) || hasStaleKeys(reconciled.Data, expected.Data) {
+		// Entries which are not applied anymore are only removed by the apply patch if they were previously applied
+		// by the operator, not if they were written by an update of a previous operator version, and an applied
+		// controller reference would be added next to an existing one instead of replacing it: update the whole Secret.
+		reconciled.Labels = maps.Merge(reconciled.Labels, expected.Labels)
+		reconciled.Annotations = maps.Merge(reconciled.Annotations, expected.Annotations)
+		reconciled.Data = expected.Data
+		for _, ref := range expected.OwnerReferences {
+			k8s.OverrideControllerReference(&reconciled, ref)
+		}
+		if removeOwner {
+			k8s.RemoveOwner(&reconciled, ownerToRemove)
+		}
+		if err := c.Update(ctx, &reconciled); err != nil {
+			return corev1.Secret{}, err
+		}
+	} else {
+		reconciled = applyConfiguration(expected)
+		if err := c.Patch(ctx, &reconciled, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+			return corev1.Secret{}, err
+		}
+	}
+	if params.PostUpdate != nil {
+		params.PostUpdate()
 	}
 	return reconciled, nil
 }
 
+// applyConfiguration returns the fields of the expected Secret managed by the operator through server-side apply.
+func applyConfiguration(expected corev1.Secret) corev1.Secret {
+	return corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       expected.Namespace,
+			Name:            expected.Name,
+			Labels:          expected.Labels,
+			Annotations:     expected.Annotations,
+			OwnerReferences: expected.OwnerReferences,
+		},
+		Data: expected.Data,
+	}
+}
+
+// contentHash returns a hash of the Secret data, identical for nil and empty data.
+func contentHash(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		// length-prefix keys and values so that different entries cannot produce the same stream
+		_, _ = fmt.Fprintf(h, "%d:%s%d:", len(k), k, len(data[k]))
+		_, _ = h.Write(data[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hasStaleKeys returns true if the actual data contains keys that are not expected.
+func hasStaleKeys(actual, expected map[string][]byte) bool {
+	for k := range actual {
+		if _, exists := expected[k]; !exists {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOwnerReferences returns true if the Secret has all the given owner references.
+func hasOwnerReferences(secret corev1.Secret, refs []metav1.OwnerReference) bool {
+	for _, ref := range refs {
+		found := false
+		for _, existing := range secret.OwnerReferences {
+			if existing.UID == ref.UID && existing.Name == ref.Name && existing.Kind == ref.Kind &&
+				ptr.Deref(existing.Controller, false) == ptr.Deref(ref.Controller, false) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 type SoftOwnerRef struct {
 	Namespace string
 	Name      string
@@ -120,35 +231,7 @@ func ReconcileSecretNoOwnerRef(ctx context.Context, c k8s.Client, expected corev
 	expected.Labels[SoftOwnerNameLabel] = ownerMeta.GetName()
 	expected.Labels[SoftOwnerKindLabel] = softOwner.GetObjectKind().GroupVersionKind().Kind
 
-	var reconciled corev1.Secret
-	if err := ReconcileResource(Params{
-		Context:    ctx,
-		Client:     c,
-		Owner:      nil,
-		Expected:   &expected,
-		Reconciled: &reconciled,
-		NeedsUpdate: func() bool {
-			// update if expected labels and annotations are not there
-			return !maps.IsSubset(expected.Labels, reconciled.Labels) ||
-				!maps.IsSubset(expected.Annotations, reconciled.Annotations) ||
-				// or if secret data is not strictly equal
-				!reflect.DeepEqual(expected.Data, reconciled.Data) ||
-				// or if an existing owner should be removed
-				k8s.HasOwner(&reconciled, ownerMeta)
-		},
-		UpdateReconciled: func() {
-			// set expected annotations and labels, but don't remove existing ones
-			// that may have been defaulted or set by the user on the existing resource
-			reconciled.Labels = maps.Merge(reconciled.Labels, expected.Labels)
-			reconciled.Annotations = maps.Merge(reconciled.Annotations, expected.Annotations)
-			reconciled.Data = expected.Data
-			// remove existing owner
-			k8s.RemoveOwner(&reconciled, ownerMeta)
-		},
-	}); err != nil {
-		return corev1.Secret{}, err
-	}
-	return reconciled, nil
+	return reconcileSecret(ctx, c, expected, ownerMeta)
 }
 
 // GarbageCollectSoftOwnedSecrets deletes all secrets whose labels reference a soft owner.
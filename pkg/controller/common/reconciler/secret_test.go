@@ -128,6 +128,92 @@ func TestReconcileSecret(t *testing.T) {
 	}
 }
 
+func TestReconcileSecret_SkipNoOpWrites(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    *corev1.Secret
+		expected    *corev1.Secret
+		wantUpdated bool
+	}{
+		{
+			name:        "same content",
+			existing:    withOwnerRef(t, createSecret("s", sampleData, sampleLabels, sampleAnnotations)),
+			expected:    createSecret("s", sampleData, sampleLabels, sampleAnnotations),
+			wantUpdated: false,
+		},
+		{
+			name:        "nil and empty data have the same content",
+			existing:    withOwnerRef(t, createSecret("s", nil, sampleLabels, nil)),
+			expected:    createSecret("s", map[string][]byte{}, sampleLabels, nil),
+			wantUpdated: false,
+		},
+		{
+			name:        "data of an existing key changed",
+			existing:    withOwnerRef(t, createSecret("s", sampleData, sampleLabels, nil)),
+			expected:    createSecret("s", map[string][]byte{"key1": []byte("data1"), "key2": []byte("changed")}, sampleLabels, nil),
+			wantUpdated: true,
+		},
+		{
+			name:        "missing owner reference on a Secret with the expected content",
+			existing:    createSecret("s", sampleData, sampleLabels, nil),
+			expected:    createSecret("s", sampleData, sampleLabels, nil),
+			wantUpdated: false,
+		},
+		{
+			name:        "missing owner reference on a Secret to update",
+			existing:    createSecret("s", sampleData, nil, nil),
+			expected:    createSecret("s", sampleData, sampleLabels, nil),
+			wantUpdated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(tt.existing)
+			var before corev1.Secret
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(tt.existing), &before))
+
+			updated := false
+			got, err := ReconcileSecret(context.Background(), c, *tt.expected, owner, WithPostUpdate(func() { updated = true }))
+			require.NoError(t, err)
+			require.Equal(t, tt.wantUpdated, updated)
+
+			var after corev1.Secret
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(tt.existing), &after))
+			require.Equal(t, tt.wantUpdated, before.ResourceVersion != after.ResourceVersion)
+			require.Equal(t, after.ResourceVersion, got.ResourceVersion)
+			require.Equal(t, contentHash(tt.expected.Data), contentHash(after.Data))
+			require.Equal(t, tt.wantUpdated || k8s.HasOwner(tt.existing, owner), k8s.HasOwner(&after, owner))
+		})
+	}
+}
+
+func TestReconcileSecret_RemoveStaleKeys(t *testing.T) {
+	c := k8s.NewFakeClient(withOwnerRef(t, createSecret("s", sampleData, nil, nil)))
+	expected := createSecret("s", map[string][]byte{"key2": []byte("data2")}, nil, nil)
+	got, err := ReconcileSecret(context.Background(), c, *expected, owner)
+	require.NoError(t, err)
+	require.Equal(t, expected.Data, got.Data)
+
+	var retrieved corev1.Secret
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(expected), &retrieved))
+	require.Equal(t, expected.Data, retrieved.Data)
+}
+
+func Test_contentHash(t *testing.T) {
+	require.Equal(t, contentHash(nil), contentHash(map[string][]byte{}))
+	require.Equal(t, contentHash(sampleData), contentHash(map[string][]byte{"key2": []byte("data2"), "key1": []byte("data1")}))
+	require.NotEqual(t, contentHash(sampleData), contentHash(sampleDataUpdated))
+	// entries are delimited
+	require.NotEqual(t,
+		contentHash(map[string][]byte{"a": []byte("bc")}),
+		contentHash(map[string][]byte{"ab": []byte("c")}),
+	)
+	require.NotEqual(t,
+		contentHash(map[string][]byte{"a": nil, "b": nil}),
+		contentHash(map[string][]byte{"a": []byte("1:b0:")}),
+	)
+}
+
 func concatMaps(m1 map[string]string, m2 map[string]string) map[string]string {
 	newMap := map[string]string{}
 	maps.Merge(newMap, m1)
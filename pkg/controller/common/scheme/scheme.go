@@ -11,22 +11,36 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	alertingv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/alerting/v1alpha1"
 	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
 	apmv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1beta1"
+	associationv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/association/v1alpha1"
 	easv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/autoscaling/v1alpha1"
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	commonv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1beta1"
+	dataviewv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/dataview/v1alpha1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	esv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1beta1"
 	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
 	entv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1beta1"
+	fleetv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/fleet/v1alpha1"
+	ilmv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/ilm/v1alpha1"
+	indexv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/index/v1alpha1"
+	ingestv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/ingest/v1alpha1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	kbv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1beta1"
+	kibanaspacev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibanaspace/v1alpha1"
 	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
+	remoteclusterv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/remotecluster/v1alpha1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
 	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
+	transformv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/transform/v1alpha1"
 )
 
 var addToScheme sync.Once
@@ -47,6 +61,8 @@ func mustAddSchemeOnce(once *sync.Once, schemes []func(scheme *runtime.Scheme) e
 func SetupScheme() {
 	schemes := []func(scheme *runtime.Scheme) error{
 		clientgoscheme.AddToScheme,
+		gatewayv1.Install,
+		gatewayv1alpha2.Install,
 		apmv1.AddToScheme,
 		commonv1.AddToScheme,
 		esv1.AddToScheme,
@@ -58,6 +74,18 @@ func SetupScheme() {
 		emsv1alpha1.AddToScheme,
 		policyv1alpha1.AddToScheme,
 		logstashv1alpha1.AddToScheme,
+		snapshotv1alpha1.AddToScheme,
+		remoteclusterv1alpha1.AddToScheme,
+		kibanaspacev1alpha1.AddToScheme,
+		fleetv1alpha1.AddToScheme,
+		ilmv1alpha1.AddToScheme,
+		securityv1alpha1.AddToScheme,
+		indexv1alpha1.AddToScheme,
+		ingestv1alpha1.AddToScheme,
+		alertingv1alpha1.AddToScheme,
+		transformv1alpha1.AddToScheme,
+		dataviewv1alpha1.AddToScheme,
+		associationv1alpha1.AddToScheme,
 	}
 	mustAddSchemeOnce(&addToScheme, schemes)
 }
@@ -7,6 +7,7 @@ package common
 import (
 	"context"
 	"reflect"
+	"sync/atomic"
 
 	"go.elastic.co/apm/v2"
 	corev1 "k8s.io/api/core/v1"
@@ -19,6 +20,30 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
 )
 
+// serviceIPFamilyPolicy is the IP family policy of the services created by the operator, see SetServiceIPFamilyPolicy.
+var serviceIPFamilyPolicy atomic.Pointer[corev1.IPFamilyPolicy]
+
+// SetServiceIPFamilyPolicy sets the IP family policy applied to the services reconciled by the operator which do not
+// specify one, for example PreferDualStack to expose the Elastic Stack applications on both IP families of a
+// dual-stack cluster. An empty policy leaves the default of the Kubernetes cluster.
+func SetServiceIPFamilyPolicy(policy corev1.IPFamilyPolicy) {
+	if policy == "" {
+		serviceIPFamilyPolicy.Store(nil)
+		return
+	}
+	serviceIPFamilyPolicy.Store(&policy)
+}
+
+// applyIPFamilyPolicy sets the operator-wide IP family policy on the given service if it does not specify one.
+func applyIPFamilyPolicy(svc *corev1.Service) {
+	policy := serviceIPFamilyPolicy.Load()
+	// ExternalName services do not have IP addresses
+	if policy == nil || svc.Spec.IPFamilyPolicy != nil || svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return
+	}
+	svc.Spec.IPFamilyPolicy = policy
+}
+
 func ReconcileService(
 	ctx context.Context,
 	c k8s.Client,
@@ -28,6 +53,11 @@ func ReconcileService(
 	span, _ := apm.StartSpan(ctx, "reconcile_service", tracing.SpanTypeApp)
 	defer span.End()
 
+	applyIPFamilyPolicy(expected)
+	// the expected service is compared with the reconciled one once completed with the values defaulted or allocated
+	// by the API server, but only the values set by the operator are applied
+	comparable := expected.DeepCopy()
+
 	reconciled := &corev1.Service{}
 	err := reconciler.ReconcileResource(reconciler.Params{
 		Context:    ctx,
@@ -36,16 +66,12 @@ func ReconcileService(
 		Expected:   expected,
 		Reconciled: reconciled,
 		NeedsRecreate: func() bool {
-			return needsRecreate(expected, reconciled)
+			return needsRecreate(comparable, reconciled)
 		},
 		NeedsUpdate: func() bool {
-			return needsUpdate(expected, reconciled)
-		},
-		UpdateReconciled: func() {
-			reconciled.Annotations = expected.Annotations
-			reconciled.Labels = expected.Labels
-			reconciled.Spec = expected.Spec
+			return needsUpdate(comparable, reconciled)
 		},
+		ServerSideApply: true,
 	})
 	return reconciled, err
 }
@@ -133,6 +159,13 @@ func applyServerSideValues(expected, reconciled *corev1.Service) {
 	// IPFamily is immutable and cannot be modified so we should retain the existing value from the server if there's no explicit override.
 	if expected.Spec.IPFamilies == nil {
 		expected.Spec.IPFamilies = reconciled.Spec.IPFamilies
+		// only the primary IP family and cluster IP are kept when a dual-stack service becomes single-stack
+		if isSingleStack(expected.Spec.IPFamilyPolicy) && len(expected.Spec.IPFamilies) > 1 {
+			expected.Spec.IPFamilies = expected.Spec.IPFamilies[:1]
+			if len(expected.Spec.ClusterIPs) > 1 {
+				expected.Spec.ClusterIPs = expected.Spec.ClusterIPs[:1]
+			}
+		}
 	}
 
 	// IPFamilyPolicy is immutable and cannot be modified so we should retain the existing value from the server if there's no explicit override.
@@ -163,3 +196,7 @@ func applyServerSideValues(expected, reconciled *corev1.Service) {
 func hasNodePort(svcType corev1.ServiceType) bool {
 	return svcType == corev1.ServiceTypeNodePort || svcType == corev1.ServiceTypeLoadBalancer
 }
+
+func isSingleStack(policy *corev1.IPFamilyPolicy) bool {
+	return policy != nil && *policy == corev1.IPFamilyPolicySingleStack
+}
@@ -46,6 +46,39 @@ func TestReconcileService(t *testing.T) {
 	comparison.AssertEqual(t, wantSvc, haveSvc)
 }
 
+func TestReconcileService_IPFamilyPolicy(t *testing.T) {
+	SetServiceIPFamilyPolicy(corev1.IPFamilyPolicyPreferDualStack)
+	defer SetServiceIPFamilyPolicy("")
+
+	owner := &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "owner-obj",
+			Namespace: "test",
+		},
+	}
+	client := k8s.NewFakeClient(owner)
+
+	// the operator-wide policy applies to services which do not specify one
+	haveSvc, err := ReconcileService(context.Background(), client, mkService(owner), owner)
+	require.NoError(t, err)
+	require.Equal(t, ptr.To(corev1.IPFamilyPolicyPreferDualStack), haveSvc.Spec.IPFamilyPolicy)
+
+	// the policy of the service takes precedence
+	expectedSvc := mkService(owner)
+	expectedSvc.Spec.IPFamilyPolicy = ptr.To(corev1.IPFamilyPolicySingleStack)
+	haveSvc, err = ReconcileService(context.Background(), client, expectedSvc, owner)
+	require.NoError(t, err)
+	require.Equal(t, ptr.To(corev1.IPFamilyPolicySingleStack), haveSvc.Spec.IPFamilyPolicy)
+
+	// ExternalName services do not have IP families
+	externalNameSvc := mkService(owner)
+	externalNameSvc.Name = "external-name-svc"
+	externalNameSvc.Spec.Type = corev1.ServiceTypeExternalName
+	haveSvc, err = ReconcileService(context.Background(), client, externalNameSvc, owner)
+	require.NoError(t, err)
+	require.Nil(t, haveSvc.Spec.IPFamilyPolicy)
+}
+
 func mkService(owner *kbv1.Kibana) *corev1.Service {
 	trueVal := true
 	return &corev1.Service{
@@ -563,6 +596,30 @@ func Test_applyServerSideValues(t *testing.T) {
 				IPFamilies:      []corev1.IPFamily{corev1.IPv6Protocol},
 			}},
 		},
+		{
+			name: "Only the primary reconciled IPFamily and ClusterIP are used if the expected service is single-stack",
+			args: args{
+				expected: corev1.Service{Spec: corev1.ServiceSpec{
+					IPFamilyPolicy: ptr.To(corev1.IPFamilyPolicySingleStack),
+				}},
+				reconciled: corev1.Service{Spec: corev1.ServiceSpec{
+					Type:            corev1.ServiceTypeClusterIP,
+					ClusterIP:       "1.2.3.4",
+					ClusterIPs:      []string{"1.2.3.4", "2001:db8::1"},
+					SessionAffinity: corev1.ServiceAffinityClientIP,
+					IPFamilies:      []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+					IPFamilyPolicy:  ptr.To(corev1.IPFamilyPolicyPreferDualStack),
+				}},
+			},
+			want: corev1.Service{Spec: corev1.ServiceSpec{
+				Type:            corev1.ServiceTypeClusterIP,
+				ClusterIP:       "1.2.3.4",
+				ClusterIPs:      []string{"1.2.3.4"},
+				SessionAffinity: corev1.ServiceAffinityClientIP,
+				IPFamilies:      []corev1.IPFamily{corev1.IPv4Protocol},
+				IPFamilyPolicy:  ptr.To(corev1.IPFamilyPolicySingleStack),
+			}},
+		},
 		{
 			name: "Reconciled InternalTrafficPolicy/ExternalTrafficPolicy/AllocateLoadBalancerPorts are used if the expected one is empty",
 			args: args{
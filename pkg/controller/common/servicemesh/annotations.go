@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package servicemesh
+
+import (
+	"strconv"
+	"strings"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// IstioRewriteAppHTTPProbersAnnotation makes the Istio sidecar forward the HTTP probes of the kubelet, which would
+	// otherwise be rejected with strict mutual TLS.
+	IstioRewriteAppHTTPProbersAnnotation = "sidecar.istio.io/rewriteAppHTTPProbers"
+	// IstioProxyConfigAnnotation overrides the configuration of the Istio sidecar.
+	IstioProxyConfigAnnotation = "proxy.istio.io/config"
+	// IstioExcludeInboundPortsAnnotation lists the inbound ports not intercepted by the Istio sidecar.
+	IstioExcludeInboundPortsAnnotation = "traffic.sidecar.istio.io/excludeInboundPorts"
+	// IstioExcludeOutboundPortsAnnotation lists the outbound ports not intercepted by the Istio sidecar.
+	IstioExcludeOutboundPortsAnnotation = "traffic.sidecar.istio.io/excludeOutboundPorts"
+	// LinkerdSkipInboundPortsAnnotation lists the inbound ports not intercepted by the Linkerd sidecar.
+	LinkerdSkipInboundPortsAnnotation = "config.linkerd.io/skip-inbound-ports"
+	// LinkerdSkipOutboundPortsAnnotation lists the outbound ports not intercepted by the Linkerd sidecar.
+	LinkerdSkipOutboundPortsAnnotation = "config.linkerd.io/skip-outbound-ports"
+
+	// istioHoldApplicationConfig starts the application containers once the Istio sidecar is ready, so that they can
+	// connect to the associated resources right away.
+	istioHoldApplicationConfig = `{"holdApplicationUntilProxyStarts":true}`
+)
+
+// PodAnnotations returns the annotations configuring the sidecar of the service mesh providing TLS, if any.
+// The excluded ports are not intercepted by the sidecar, typically because they are already secured with TLS by the
+// application. Annotations set by the user in the Pod template take precedence.
+func PodAnnotations(tls commonv1.TLSOptions, excludedPorts ...int32) map[string]string {
+	if !tls.ServiceMeshEnabled() {
+		return nil
+	}
+	ports := make([]string, 0, len(excludedPorts))
+	for _, port := range excludedPorts {
+		ports = append(ports, strconv.Itoa(int(port)))
+	}
+	annotations := map[string]string{}
+	switch tls.ServiceMesh.Provider {
+	case commonv1.IstioServiceMeshProvider:
+		annotations[IstioRewriteAppHTTPProbersAnnotation] = "true"
+		annotations[IstioProxyConfigAnnotation] = istioHoldApplicationConfig
+		if len(ports) > 0 {
+			annotations[IstioExcludeInboundPortsAnnotation] = strings.Join(ports, ",")
+			annotations[IstioExcludeOutboundPortsAnnotation] = strings.Join(ports, ",")
+		}
+	case commonv1.LinkerdServiceMeshProvider:
+		if len(ports) > 0 {
+			annotations[LinkerdSkipInboundPortsAnnotation] = strings.Join(ports, ",")
+			annotations[LinkerdSkipOutboundPortsAnnotation] = strings.Join(ports, ",")
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package servicemesh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+func TestPodAnnotations(t *testing.T) {
+	istio := commonv1.TLSOptions{ServiceMesh: &commonv1.ServiceMeshOptions{Provider: commonv1.IstioServiceMeshProvider}}
+	linkerd := commonv1.TLSOptions{ServiceMesh: &commonv1.ServiceMeshOptions{Provider: commonv1.LinkerdServiceMeshProvider}}
+	tests := []struct {
+		name          string
+		tls           commonv1.TLSOptions
+		excludedPorts []int32
+		want          map[string]string
+	}{
+		{
+			name: "no service mesh",
+			tls:  commonv1.TLSOptions{},
+			want: nil,
+		},
+		{
+			name: "Istio",
+			tls:  istio,
+			want: map[string]string{
+				"sidecar.istio.io/rewriteAppHTTPProbers": "true",
+				"proxy.istio.io/config":                  `{"holdApplicationUntilProxyStarts":true}`,
+			},
+		},
+		{
+			name:          "Istio with excluded ports",
+			tls:           istio,
+			excludedPorts: []int32{9300, 9400},
+			want: map[string]string{
+				"sidecar.istio.io/rewriteAppHTTPProbers":        "true",
+				"proxy.istio.io/config":                         `{"holdApplicationUntilProxyStarts":true}`,
+				"traffic.sidecar.istio.io/excludeInboundPorts":  "9300,9400",
+				"traffic.sidecar.istio.io/excludeOutboundPorts": "9300,9400",
+			},
+		},
+		{
+			name: "Linkerd",
+			tls:  linkerd,
+			want: map[string]string{},
+		},
+		{
+			name:          "Linkerd with excluded ports",
+			tls:           linkerd,
+			excludedPorts: []int32{9300},
+			want: map[string]string{
+				"config.linkerd.io/skip-inbound-ports":  "9300",
+				"config.linkerd.io/skip-outbound-ports": "9300",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, PodAnnotations(tt.tls, tt.excludedPorts...))
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package servicemonitor
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// MetricsPortName is the name of the port of the metrics Service scraped by Prometheus.
+	MetricsPortName = "metrics"
+	// MetricsServiceLabelName distinguishes the metrics Service and the ServiceMonitor from the other resources of the owner.
+	MetricsServiceLabelName = "common.k8s.elastic.co/prometheus-metrics"
+)
+
+// GVK of the ServiceMonitor resource of the Prometheus Operator, managed as an unstructured object not to depend on
+// the Prometheus Operator API.
+var GVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+// Params to specify the ServiceMonitor of a resource.
+type Params struct {
+	// Owner of the ServiceMonitor, for ex. Elasticsearch or Kibana.
+	Owner client.Object
+	// Prometheus is the Prometheus monitoring configuration of the owner, nil if not specified.
+	Prometheus *commonv1.PrometheusMonitoring
+	// Name of the metrics Service and of the ServiceMonitor.
+	Name string
+	// Labels to set on the metrics Service and on the ServiceMonitor.
+	Labels map[string]string
+	// Selector of the Pods serving the metrics.
+	Selector map[string]string
+}
+
+// NewService returns the headless Service selecting the Pods serving the metrics.
+func NewService(params Params) corev1.Service {
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      params.Name,
+			Namespace: params.Owner.GetNamespace(),
+			Labels:    serviceLabels(params),
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  params.Selector,
+			Ports: []corev1.ServicePort{{
+				Name:       MetricsPortName,
+				Protocol:   corev1.ProtocolTCP,
+				Port:       params.Prometheus.Port,
+				TargetPort: intstr.FromInt32(params.Prometheus.Port),
+			}},
+			// scrape the Pods that are not ready yet, for ex. while Elasticsearch is starting
+			PublishNotReadyAddresses: true,
+		},
+	}
+}
+
+// NewServiceMonitor returns the ServiceMonitor scraping the metrics Service.
+func NewServiceMonitor(params Params) unstructured.Unstructured {
+	prometheus := params.Prometheus
+	endpoint := map[string]any{
+		"port":   MetricsPortName,
+		"path":   prometheus.PathOrDefault(),
+		"scheme": prometheus.SchemeOrDefault(),
+	}
+	if prometheus.Interval != "" {
+		endpoint["interval"] = prometheus.Interval
+	}
+	selector := map[string]any{}
+	for k, v := range serviceLabels(params) {
+		selector[k] = v
+	}
+
+	serviceMonitor := unstructured.Unstructured{}
+	serviceMonitor.SetGroupVersionKind(GVK)
+	serviceMonitor.SetName(params.Name)
+	serviceMonitor.SetNamespace(params.Owner.GetNamespace())
+	serviceMonitor.SetLabels(maps.Merge(maps.Merge(nil, prometheus.ObjectMeta.Labels), serviceLabels(params)))
+	serviceMonitor.SetAnnotations(maps.Merge(nil, prometheus.ObjectMeta.Annotations))
+	serviceMonitor.Object["spec"] = map[string]any{
+		"selector":          map[string]any{"matchLabels": selector},
+		"namespaceSelector": map[string]any{"matchNames": []any{params.Owner.GetNamespace()}},
+		"endpoints":         []any{endpoint},
+	}
+	return serviceMonitor
+}
+
+func serviceLabels(params Params) map[string]string {
+	return maps.Merge(map[string]string{MetricsServiceLabelName: "true"}, params.Labels)
+}
+
+// Reconcile creates or updates the metrics Service and the ServiceMonitor specified in the Prometheus monitoring
+// configuration of the owner, or deletes the ones previously created if none is specified anymore.
+// The ServiceMonitor is not created if the Prometheus Operator CRDs are not installed.
+func Reconcile(ctx context.Context, c k8s.Client, params Params) error {
+	if params.Prometheus == nil {
+		if err := deleteIfExists(ctx, c, params, newUnstructured()); err != nil {
+			return err
+		}
+		return deleteIfExists(ctx, c, params, &corev1.Service{})
+	}
+
+	service := NewService(params)
+	if _, err := common.ReconcileService(ctx, c, &service, params.Owner); err != nil {
+		return err
+	}
+
+	expected := NewServiceMonitor(params)
+	reconciled := newUnstructured()
+	err := reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Owner:      params.Owner,
+		Expected:   &expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return !equality.Semantic.DeepEqual(expected.Object["spec"], reconciled.Object["spec"]) ||
+				!maps.IsSubset(expected.GetLabels(), reconciled.GetLabels()) ||
+				!maps.IsSubset(expected.GetAnnotations(), reconciled.GetAnnotations())
+		},
+		UpdateReconciled: func() {
+			reconciled.SetLabels(maps.Merge(reconciled.GetLabels(), expected.GetLabels()))
+			reconciled.SetAnnotations(maps.Merge(reconciled.GetAnnotations(), expected.GetAnnotations()))
+			reconciled.Object["spec"] = expected.Object["spec"]
+		},
+	})
+	if meta.IsNoMatchError(err) {
+		ulog.FromContext(ctx).Info("Prometheus Operator CRDs not installed, skipping ServiceMonitor",
+			"namespace", expected.GetNamespace(), "name", expected.GetName())
+		return nil
+	}
+	return err
+}
+
+func newUnstructured() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(GVK)
+	return obj
+}
+
+func deleteIfExists(ctx context.Context, c k8s.Client, params Params, obj client.Object) error {
+	err := c.Get(ctx, types.NamespacedName{Namespace: params.Owner.GetNamespace(), Name: params.Name}, obj)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		// nothing to delete, or the Prometheus Operator CRDs are not installed
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !k8s.HasOwner(obj, params.Owner) || obj.GetLabels()[MetricsServiceLabelName] != "true" {
+		// not created by the operator for the metrics, for ex. a Service of an Elasticsearch node set with the same name
+		return nil
+	}
+	err = c.Delete(ctx, obj, &client.DeleteOptions{Preconditions: &metav1.Preconditions{UID: ptr.To(obj.GetUID())}})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,200 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package servicemonitor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func testParams(prometheus *commonv1.PrometheusMonitoring) Params {
+	kb := kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb", UID: "kb-uid"}}
+	return Params{
+		Owner:      &kb,
+		Prometheus: prometheus,
+		Name:       "kb-kb-metrics",
+		Labels:     map[string]string{"kibana.k8s.elastic.co/name": "kb"},
+		Selector:   map[string]string{"kibana.k8s.elastic.co/name": "kb"},
+	}
+}
+
+// newFakeClient returns a fake client aware of the ServiceMonitor resource, as if the Prometheus Operator CRDs were installed.
+func newFakeClient(t *testing.T, initObjs ...client.Object) k8s.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	s.AddKnownTypeWithName(GVK, &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(GVK.GroupVersion().WithKind(GVK.Kind+"List"), &unstructured.UnstructuredList{})
+	return k8s.NewFakeClientWithScheme(s, initObjs...)
+}
+
+func TestNewService(t *testing.T) {
+	got := NewService(testParams(&commonv1.PrometheusMonitoring{Port: 9114}))
+	require.Equal(t, corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "kb-kb-metrics",
+			Labels:    map[string]string{"kibana.k8s.elastic.co/name": "kb", MetricsServiceLabelName: "true"},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  map[string]string{"kibana.k8s.elastic.co/name": "kb"},
+			Ports: []corev1.ServicePort{{
+				Name:       MetricsPortName,
+				Protocol:   corev1.ProtocolTCP,
+				Port:       9114,
+				TargetPort: intstr.FromInt32(9114),
+			}},
+			PublishNotReadyAddresses: true,
+		},
+	}, got)
+}
+
+func TestNewServiceMonitor(t *testing.T) {
+	tests := []struct {
+		name       string
+		prometheus commonv1.PrometheusMonitoring
+		want       unstructured.Unstructured
+	}{
+		{
+			name:       "defaults",
+			prometheus: commonv1.PrometheusMonitoring{Port: 9114},
+			want: unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "monitoring.coreos.com/v1",
+				"kind":       "ServiceMonitor",
+				"metadata": map[string]any{
+					"namespace": "ns",
+					"name":      "kb-kb-metrics",
+					"labels":    map[string]any{"kibana.k8s.elastic.co/name": "kb", MetricsServiceLabelName: "true"},
+				},
+				"spec": map[string]any{
+					"selector":          map[string]any{"matchLabels": map[string]any{"kibana.k8s.elastic.co/name": "kb", MetricsServiceLabelName: "true"}},
+					"namespaceSelector": map[string]any{"matchNames": []any{"ns"}},
+					"endpoints":         []any{map[string]any{"port": MetricsPortName, "path": "/metrics", "scheme": "http"}},
+				},
+			}},
+		},
+		{
+			name: "custom endpoint and metadata",
+			prometheus: commonv1.PrometheusMonitoring{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"release": "prometheus", MetricsServiceLabelName: "false"},
+					Annotations: map[string]string{"a": "b"},
+				},
+				Port:     9114,
+				Path:     "/_prometheus/metrics",
+				Scheme:   "https",
+				Interval: "30s",
+			},
+			want: unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "monitoring.coreos.com/v1",
+				"kind":       "ServiceMonitor",
+				"metadata": map[string]any{
+					"namespace": "ns",
+					"name":      "kb-kb-metrics",
+					"labels": map[string]any{
+						"kibana.k8s.elastic.co/name": "kb",
+						MetricsServiceLabelName:      "true",
+						"release":                    "prometheus",
+					},
+					"annotations": map[string]any{"a": "b"},
+				},
+				"spec": map[string]any{
+					"selector":          map[string]any{"matchLabels": map[string]any{"kibana.k8s.elastic.co/name": "kb", MetricsServiceLabelName: "true"}},
+					"namespaceSelector": map[string]any{"matchNames": []any{"ns"}},
+					"endpoints": []any{map[string]any{
+						"port":     MetricsPortName,
+						"path":     "/_prometheus/metrics",
+						"scheme":   "https",
+						"interval": "30s",
+					}},
+				},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NewServiceMonitor(testParams(&tt.prometheus)))
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	scheme.SetupScheme()
+	ctx := context.Background()
+	c := newFakeClient(t)
+	key := types.NamespacedName{Namespace: "ns", Name: "kb-kb-metrics"}
+
+	// create the metrics Service and the ServiceMonitor
+	params := testParams(&commonv1.PrometheusMonitoring{Port: 9114})
+	require.NoError(t, Reconcile(ctx, c, params))
+	var service corev1.Service
+	require.NoError(t, c.Get(ctx, key, &service))
+	require.True(t, k8s.HasOwner(&service, params.Owner))
+	serviceMonitor := newUnstructured()
+	require.NoError(t, c.Get(ctx, key, serviceMonitor))
+	require.True(t, k8s.HasOwner(serviceMonitor, params.Owner))
+
+	// the annotations set by other controllers are kept, the spec follows the configuration
+	serviceMonitor.SetAnnotations(map[string]string{"prometheus.example.com/status": "ok"})
+	require.NoError(t, c.Update(ctx, serviceMonitor))
+	params.Prometheus.Interval = "1m"
+	require.NoError(t, Reconcile(ctx, c, params))
+	serviceMonitor = newUnstructured()
+	require.NoError(t, c.Get(ctx, key, serviceMonitor))
+	require.Equal(t, map[string]string{"prometheus.example.com/status": "ok"}, serviceMonitor.GetAnnotations())
+	endpoints, _, err := unstructured.NestedSlice(serviceMonitor.Object, "spec", "endpoints")
+	require.NoError(t, err)
+	require.Equal(t, "1m", endpoints[0].(map[string]any)["interval"])
+
+	// both are deleted once removed from the specification
+	params.Prometheus = nil
+	require.NoError(t, Reconcile(ctx, c, params))
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, key, &service)))
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, key, newUnstructured())))
+	// deleting again is a no-op
+	require.NoError(t, Reconcile(ctx, c, params))
+}
+
+func TestReconcile_NotOwnedService(t *testing.T) {
+	// a Service of the owner with the same name that is not the metrics Service, for ex. the one of a node set
+	service := corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb-metrics"}}
+	c := newFakeClient(t, &service)
+	require.NoError(t, Reconcile(context.Background(), c, testParams(nil)))
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&service), &service))
+}
+
+func TestReconcile_NoPrometheusOperator(t *testing.T) {
+	scheme.SetupScheme()
+	ctx := context.Background()
+	// the default fake client does not know about the ServiceMonitor resource
+	c := k8s.NewFakeClient()
+	key := types.NamespacedName{Namespace: "ns", Name: "kb-kb-metrics"}
+
+	params := testParams(&commonv1.PrometheusMonitoring{Port: 9114})
+	require.NoError(t, Reconcile(ctx, c, params))
+	var service corev1.Service
+	require.NoError(t, c.Get(ctx, key, &service))
+
+	params.Prometheus = nil
+	require.NoError(t, Reconcile(ctx, c, params))
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, key, &service)))
+}
@@ -0,0 +1,33 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package servicemonitor
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+var log = ulog.Log.WithName("servicemonitor")
+
+// Watch watches the ServiceMonitors owned by the given type of owner, if the Prometheus Operator CRDs are installed
+// when the operator starts.
+func Watch(mgr manager.Manager, c controller.Controller, owner client.Object) error {
+	if _, err := mgr.GetRESTMapper().RESTMapping(GVK.GroupKind(), GVK.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			log.V(1).Info("Prometheus Operator CRDs not installed, skipping watch", "kind", GVK.Kind, "version", GVK.Version)
+			return nil
+		}
+		return err
+	}
+	return c.Watch(source.Kind(mgr.GetCache(), client.Object(newUnstructured()), handler.EnqueueRequestForOwner(
+		mgr.GetScheme(), mgr.GetRESTMapper(), owner, handler.OnlyControllerOwner(),
+	)))
+}
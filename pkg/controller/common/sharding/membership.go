@@ -0,0 +1,411 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package sharding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// DefaultSyncPeriod is the default period at which the active operator replicas are listed and the shard leases
+	// renewed.
+	DefaultSyncPeriod = 10 * time.Second
+	// ShardCount is the number of shards the resources are distributed in. Each shard is guarded by a Lease, which must
+	// be held by an operator replica to reconcile the resources of the shard. The names of the leases are listed in the
+	// RBAC rules of the operator Helm chart.
+	ShardCount = 32
+	// LeaseNamePrefix is the prefix of the names of the shard leases, created in the operator namespace.
+	LeaseNamePrefix = "elastic-operator-shard-"
+	// leaseDurationPeriods is the number of sync periods after which a lease that is not renewed expires.
+	leaseDurationPeriods = 3
+)
+
+// errShardHeld is returned when the lease of a shard is held by another operator replica.
+var errShardHeld = errors.New("shard lease held by another operator replica")
+
+var log = ulog.Log.WithName("sharding")
+
+// Scope defines the unit of distribution of the resources between the operator replicas.
+type Scope string
+
+const (
+	// ResourceScope distributes each resource independently.
+	ResourceScope Scope = "resource"
+	// NamespaceScope assigns all the resources of a namespace to the same replica, so that the resources associated
+	// with each other in a namespace are reconciled by the same replica.
+	NamespaceScope Scope = "namespace"
+)
+
+// Membership keeps track of the active replicas of the operator, to distribute the reconciliation of resources between
+// them. Active replicas are the ready Pods controlled by the same workload (usually the operator StatefulSet) as the
+// Pod of this replica. Resources are spread in a fixed number of shards, and each shard is assigned to a single active
+// replica, chosen by rendezvous hashing so that only the shards of a replica that leaves, or a fair share of shards
+// for a replica that joins, change owner.
+// Ownership is fenced by a Lease per shard: a replica only reconciles the resources of the shards whose lease it
+// holds, and releases the lease of a shard assigned to another replica once its in-flight reconciliations of the
+// shard are complete. Until then, the new owner waits for the lease to be released or to expire.
+type Membership struct {
+	reader    client.Reader
+	writer    client.Writer
+	namespace string
+	identity  string
+	scope     Scope
+	period    time.Duration
+	now       func() time.Time
+
+	mutex   sync.RWMutex
+	members []string
+	shards  [ShardCount]shardState
+
+	listenerLock sync.RWMutex
+	listeners    []func()
+}
+
+// shardState is the state of a shard for this operator replica.
+type shardState struct {
+	// renewed is the last time the lease of the shard was acquired or renewed by this replica, zero if not held.
+	renewed time.Time
+	// inFlight is the number of ongoing reconciliations of resources of the shard.
+	inFlight int
+}
+
+// NewMembership returns a Membership for the operator replica running in the Pod with the given namespace and name,
+// distributing the resources with the given scope. The reader must not be backed by the cache of the manager.
+func NewMembership(reader client.Reader, writer client.Writer, namespace, identity string, scope Scope, period time.Duration) *Membership {
+	if period <= 0 {
+		period = DefaultSyncPeriod
+	}
+	return &Membership{
+		reader:    reader,
+		writer:    writer,
+		namespace: namespace,
+		identity:  identity,
+		scope:     scope,
+		period:    period,
+		now:       time.Now,
+	}
+}
+
+// Members returns the names of the active operator replicas.
+func (m *Membership) Members() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return slices.Clone(m.members)
+}
+
+// Owns returns true if the resource with the given name must be reconciled by this operator replica.
+func (m *Membership) Owns(resource types.NamespacedName) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.owns(m.shardOf(resource))
+}
+
+// Acquire returns true if the resource with the given name must be reconciled by this operator replica. The lease of
+// the shard of the resource is then not released until the returned function is called, at the end of the
+// reconciliation.
+func (m *Membership) Acquire(resource types.NamespacedName) (func(), bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	shard := m.shardOf(resource)
+	if !m.owns(shard) {
+		return nil, false
+	}
+	m.shards[shard].inFlight++
+	return func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		m.shards[shard].inFlight--
+	}, true
+}
+
+// owns returns true if the given shard is assigned to this replica, and its lease held by this replica and not about
+// to expire. It must be called with the mutex held.
+func (m *Membership) owns(shard int) bool {
+	renewed := m.shards[shard].renewed
+	return owner(m.members, LeaseName(shard)) == m.identity &&
+		!renewed.IsZero() && m.now().Sub(renewed) < m.leaseDuration()-m.period
+}
+
+// shardOf returns the shard of the given resource.
+func (m *Membership) shardOf(resource types.NamespacedName) int {
+	if m.scope == NamespaceScope {
+		resource = types.NamespacedName{Namespace: resource.Namespace}
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(resource.Namespace + "/" + resource.Name))
+	return int(h.Sum32() % ShardCount)
+}
+
+func (m *Membership) leaseDuration() time.Duration {
+	return leaseDurationPeriods * m.period
+}
+
+// ShutdownDeadline returns how long the leases of the shards being reconciled remain held, at least, once the replica
+// stops renewing them when it shuts down, for the given sync period. In-flight reconciliations must complete within
+// this duration, otherwise another replica can acquire the lease and reconcile the same resources concurrently.
+func ShutdownDeadline(period time.Duration) time.Duration {
+	if period <= 0 {
+		period = DefaultSyncPeriod
+	}
+	// leases are renewed every period, and expire leaseDurationPeriods periods after their last renewal
+	return (leaseDurationPeriods - 1) * period
+}
+
+// LeaseName returns the name of the lease of the given shard.
+func LeaseName(shard int) string {
+	return fmt.Sprintf("%s%d", LeaseNamePrefix, shard)
+}
+
+// AddListener registers a function called every time the active replicas change.
+func (m *Membership) AddListener(listener func()) {
+	m.listenerLock.Lock()
+	defer m.listenerLock.Unlock()
+	m.listeners = append(m.listeners, listener)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable: all the replicas must track the membership.
+func (m *Membership) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable. It periodically lists the active replicas and renews the shard leases until the
+// context is cancelled, then releases the leases of the shards which are not being reconciled.
+func (m *Membership) Start(ctx context.Context) error {
+	ticker := time.NewTicker(m.period)
+	defer ticker.Stop()
+	for {
+		if err := m.sync(ctx); err != nil {
+			log.Error(err, "Failed to list active operator replicas")
+		}
+		select {
+		case <-ctx.Done():
+			releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.period)
+			defer cancel()
+			m.releaseIdleShards(releaseCtx)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+var (
+	_ manager.Runnable               = &Membership{}
+	_ manager.LeaderElectionRunnable = &Membership{}
+)
+
+// sync updates the active replicas and the shard leases, and notifies the listeners if the active replicas changed or
+// shard leases were acquired.
+func (m *Membership) sync(ctx context.Context) error {
+	members, err := m.listMembers(ctx)
+	if err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	changed := !slices.Equal(m.members, members)
+	m.members = members
+	m.mutex.Unlock()
+	if changed {
+		log.Info("Active operator replicas changed", "members", members)
+	}
+
+	acquired := m.syncLeases(ctx)
+	if !changed && !acquired {
+		return nil
+	}
+	m.listenerLock.RLock()
+	defer m.listenerLock.RUnlock()
+	for _, listener := range m.listeners {
+		listener()
+	}
+	return nil
+}
+
+// syncLeases acquires or renews the leases of the shards assigned to this replica, renews the leases of the shards
+// assigned to another replica that are still being reconciled, and releases the other ones. It returns true if
+// leases were acquired.
+func (m *Membership) syncLeases(ctx context.Context) bool {
+	acquired := false
+	for shard := 0; shard < ShardCount; shard++ {
+		m.mutex.RLock()
+		assigned := owner(m.members, LeaseName(shard)) == m.identity
+		state := m.shards[shard]
+		m.mutex.RUnlock()
+
+		held := !state.renewed.IsZero()
+		switch {
+		case assigned || (held && state.inFlight > 0):
+			renewed := m.now()
+			err := m.acquireOrRenew(ctx, shard, renewed)
+			if errors.Is(err, errShardHeld) {
+				log.V(1).Info("Waiting for the shard lease to be released", "lease", LeaseName(shard))
+				continue
+			}
+			if err != nil {
+				log.Error(err, "Failed to acquire or renew shard lease", "lease", LeaseName(shard))
+				continue
+			}
+			m.mutex.Lock()
+			m.shards[shard].renewed = renewed
+			m.mutex.Unlock()
+			acquired = acquired || !held
+		case held:
+			m.release(ctx, shard)
+		}
+	}
+	return acquired
+}
+
+// releaseIdleShards releases the leases of the shards held by this replica which are not being reconciled.
+func (m *Membership) releaseIdleShards(ctx context.Context) {
+	for shard := 0; shard < ShardCount; shard++ {
+		m.mutex.RLock()
+		state := m.shards[shard]
+		m.mutex.RUnlock()
+		if !state.renewed.IsZero() && state.inFlight == 0 {
+			m.release(ctx, shard)
+		}
+	}
+}
+
+// release releases the lease of the given shard, so that it can be acquired by another replica without waiting for
+// its expiration. The shard is no longer owned by this replica, even if the release fails.
+func (m *Membership) release(ctx context.Context, shard int) {
+	m.mutex.Lock()
+	m.shards[shard].renewed = time.Time{}
+	m.mutex.Unlock()
+
+	var lease coordinationv1.Lease
+	if err := m.reader.Get(ctx, types.NamespacedName{Namespace: m.namespace, Name: LeaseName(shard)}, &lease); err != nil {
+		log.Error(err, "Failed to release shard lease", "lease", LeaseName(shard))
+		return
+	}
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != m.identity {
+		return
+	}
+	lease.Spec.HolderIdentity = nil
+	if err := m.writer.Update(ctx, &lease); err != nil {
+		log.Error(err, "Failed to release shard lease", "lease", LeaseName(shard))
+		return
+	}
+	log.V(1).Info("Released shard lease", "lease", LeaseName(shard))
+}
+
+// acquireOrRenew acquires or renews the lease of the given shard. It returns errShardHeld if the lease is held by
+// another replica and has not expired.
+func (m *Membership) acquireOrRenew(ctx context.Context, shard int, now time.Time) error {
+	var lease coordinationv1.Lease
+	err := m.reader.Get(ctx, types.NamespacedName{Namespace: m.namespace, Name: LeaseName(shard)}, &lease)
+	if apierrors.IsNotFound(err) {
+		lease = coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: m.namespace, Name: LeaseName(shard)},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(m.identity),
+				LeaseDurationSeconds: ptr.To(int32(m.leaseDuration().Seconds())),
+				AcquireTime:          &metav1.MicroTime{Time: now},
+				RenewTime:            &metav1.MicroTime{Time: now},
+			},
+		}
+		return m.writer.Create(ctx, &lease)
+	}
+	if err != nil {
+		return err
+	}
+
+	holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+	if holder != m.identity {
+		if holder != "" && !leaseExpired(lease, now) {
+			return errShardHeld
+		}
+		lease.Spec.HolderIdentity = ptr.To(m.identity)
+		lease.Spec.AcquireTime = &metav1.MicroTime{Time: now}
+		lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+	}
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(m.leaseDuration().Seconds()))
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+	// the update fails on conflict if another replica acquired the lease in the meantime
+	return m.writer.Update(ctx, &lease)
+}
+
+// leaseExpired returns true if the given lease has not been renewed for longer than its duration.
+func leaseExpired(lease coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	return now.After(lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second))
+}
+
+// listMembers returns the sorted names of the ready Pods controlled by the same owner as the Pod of this replica.
+func (m *Membership) listMembers(ctx context.Context) ([]string, error) {
+	var self corev1.Pod
+	err := m.reader.Get(ctx, types.NamespacedName{Namespace: m.namespace, Name: m.identity}, &self)
+	if apierrors.IsNotFound(err) {
+		// not running in a Pod, for example during development: this replica is on its own
+		return []string{m.identity}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	controllerRef := metav1.GetControllerOf(&self)
+	if controllerRef == nil {
+		return []string{m.identity}, nil
+	}
+
+	var pods corev1.PodList
+	if err := m.reader.List(ctx, &pods, client.InNamespace(m.namespace)); err != nil {
+		return nil, err
+	}
+	var members []string
+	for _, pod := range pods.Items {
+		if ref := metav1.GetControllerOf(&pod); ref == nil || ref.UID != controllerRef.UID {
+			continue
+		}
+		if pod.DeletionTimestamp.IsZero() && isReady(pod) {
+			members = append(members, pod.Name)
+		}
+	}
+	slices.Sort(members)
+	return members, nil
+}
+
+func isReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// owner returns the member with the highest score for the given key, or an empty string if there is no member.
+func owner(members []string, key string) string {
+	var selected string
+	var highest uint64
+	for _, member := range members {
+		sum := sha256.Sum256([]byte(member + "/" + key))
+		if score := binary.BigEndian.Uint64(sum[:8]); selected == "" || score > highest {
+			selected, highest = member, score
+		}
+	}
+	return selected
+}
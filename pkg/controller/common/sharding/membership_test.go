@@ -0,0 +1,249 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func operatorPod(name string, ownerUID types.UID, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "elastic-system",
+			Name:      name,
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "elastic-operator", UID: ownerUID, Controller: ptr.To(true)},
+			},
+		},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+	}
+}
+
+func Test_owner(t *testing.T) {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+	assert.Equal(t, "", owner(nil, keys[0]))
+
+	three := []string{"elastic-operator-0", "elastic-operator-1", "elastic-operator-2"}
+	owners := map[string]string{}
+	perMember := map[string]int{}
+	for _, k := range keys {
+		owners[k] = owner(three, k)
+		perMember[owners[k]]++
+	}
+	// keys are spread across all the members
+	for _, m := range three {
+		assert.Greater(t, perMember[m], 250, m)
+	}
+
+	// only the keys of the member leaving change owner
+	two := []string{"elastic-operator-0", "elastic-operator-2"}
+	for _, k := range keys {
+		if owners[k] != "elastic-operator-1" {
+			assert.Equal(t, owners[k], owner(two, k))
+		}
+	}
+}
+
+// holdAllShards marks the leases of all the shards as held by the given membership.
+func holdAllShards(m *Membership) {
+	for shard := range m.shards {
+		m.shards[shard].renewed = m.now()
+	}
+}
+
+func TestMembership(t *testing.T) {
+	c := k8s.NewFakeClient(
+		operatorPod("elastic-operator-1", "sset-uid", true),
+		operatorPod("elastic-operator-0", "sset-uid", true),
+		operatorPod("elastic-operator-2", "sset-uid", false),
+		operatorPod("other", "other-uid", true),
+	)
+	m := NewMembership(c, c, "elastic-system", "elastic-operator-0", ResourceScope, time.Minute)
+	notified := 0
+	m.AddListener(func() { notified++ })
+
+	require.NoError(t, m.sync(context.Background()))
+	assert.Equal(t, []string{"elastic-operator-0", "elastic-operator-1"}, m.Members())
+	assert.Equal(t, 1, notified)
+
+	// no change
+	require.NoError(t, m.sync(context.Background()))
+	assert.Equal(t, 1, notified)
+
+	// a replica becomes ready
+	var pod corev1.Pod
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "elastic-system", Name: "elastic-operator-2"}, &pod))
+	pod.Status = operatorPod("elastic-operator-2", "sset-uid", true).Status
+	require.NoError(t, c.Status().Update(context.Background(), &pod))
+	require.NoError(t, m.sync(context.Background()))
+	assert.Equal(t, []string{"elastic-operator-0", "elastic-operator-1", "elastic-operator-2"}, m.Members())
+	assert.Equal(t, 2, notified)
+
+	owned := 0
+	for i := 0; i < 300; i++ {
+		if m.Owns(types.NamespacedName{Namespace: "ns", Name: fmt.Sprintf("es-%d", i)}) {
+			owned++
+		}
+	}
+	assert.Greater(t, owned, 0)
+	assert.Less(t, owned, 300)
+}
+
+func TestMembership_NamespaceScope(t *testing.T) {
+	members := []string{"elastic-operator-0", "elastic-operator-1", "elastic-operator-2"}
+	owners := map[string]map[string]int{}
+	for _, member := range members {
+		c := k8s.NewFakeClient()
+		m := NewMembership(c, c, "elastic-system", member, NamespaceScope, time.Minute)
+		m.members = members
+		holdAllShards(m)
+		for i := 0; i < 30; i++ {
+			ns := fmt.Sprintf("ns-%d", i)
+			for _, name := range []string{"es", "kb", "apm"} {
+				if m.Owns(types.NamespacedName{Namespace: ns, Name: name}) {
+					if owners[ns] == nil {
+						owners[ns] = map[string]int{}
+					}
+					owners[ns][member]++
+				}
+			}
+		}
+	}
+	// all the resources of a namespace are owned by a single replica
+	require.Len(t, owners, 30)
+	perMember := map[string]int{}
+	for ns, owner := range owners {
+		require.Len(t, owner, 1, ns)
+		for member, count := range owner {
+			assert.Equal(t, 3, count, ns)
+			perMember[member]++
+		}
+	}
+	// namespaces are spread across all the replicas
+	assert.Len(t, perMember, 3)
+}
+
+func TestMembership_NotInAPod(t *testing.T) {
+	c := k8s.NewFakeClient()
+	m := NewMembership(c, c, "elastic-system", "laptop", ResourceScope, 0)
+	assert.False(t, m.Owns(types.NamespacedName{Namespace: "ns", Name: "es"}))
+	require.NoError(t, m.sync(context.Background()))
+	assert.Equal(t, []string{"laptop"}, m.Members())
+	assert.True(t, m.Owns(types.NamespacedName{Namespace: "ns", Name: "es"}))
+}
+
+func TestMembership_Handover(t *testing.T) {
+	ctx := context.Background()
+	c := k8s.NewFakeClient(operatorPod("elastic-operator-0", "sset-uid", true), operatorPod("elastic-operator-1", "sset-uid", false))
+	now := time.Now()
+	clock := func() time.Time { return now }
+	a := NewMembership(c, c, "elastic-system", "elastic-operator-0", ResourceScope, time.Minute)
+	a.now = clock
+	b := NewMembership(c, c, "elastic-system", "elastic-operator-1", ResourceScope, time.Minute)
+	b.now = clock
+
+	// the first replica is alone and owns all the resources
+	require.NoError(t, a.sync(ctx))
+	var moving, staying types.NamespacedName
+	for i := 0; moving.Name == "" || staying.Name == ""; i++ {
+		resource := types.NamespacedName{Namespace: "ns", Name: fmt.Sprintf("es-%d", i)}
+		require.True(t, a.Owns(resource))
+		if owner([]string{"elastic-operator-0", "elastic-operator-1"}, LeaseName(a.shardOf(resource))) == "elastic-operator-1" {
+			moving = resource
+		} else {
+			staying = resource
+		}
+	}
+	// the resource moving to the second replica is being reconciled
+	done, owned := a.Acquire(moving)
+	require.True(t, owned)
+
+	// the second replica joins but must wait for its shards to be released
+	var pod corev1.Pod
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "elastic-system", Name: "elastic-operator-1"}, &pod))
+	pod.Status = operatorPod("elastic-operator-1", "sset-uid", true).Status
+	require.NoError(t, c.Status().Update(ctx, &pod))
+	require.NoError(t, b.sync(ctx))
+	assert.False(t, b.Owns(moving))
+	assert.False(t, b.Owns(staying))
+
+	// the first replica stops reconciling the moving resource, but keeps its shard until the reconciliation is over
+	require.NoError(t, a.sync(ctx))
+	assert.False(t, a.Owns(moving))
+	assert.True(t, a.Owns(staying))
+	_, owned = a.Acquire(moving)
+	assert.False(t, owned)
+	require.NoError(t, b.sync(ctx))
+	assert.False(t, b.Owns(moving))
+	for shard := 0; shard < ShardCount; shard++ {
+		if shard != a.shardOf(moving) && owner(b.Members(), LeaseName(shard)) == "elastic-operator-1" {
+			assert.True(t, b.owns(shard), "shards not being reconciled are handed over")
+		}
+	}
+
+	// the reconciliation completes: the shard is released and acquired by the second replica
+	done()
+	require.NoError(t, a.sync(ctx))
+	require.NoError(t, b.sync(ctx))
+	assert.True(t, b.Owns(moving))
+	assert.False(t, b.Owns(staying))
+	var lease coordinationv1.Lease
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "elastic-system", Name: LeaseName(a.shardOf(moving))}, &lease))
+	assert.Equal(t, "elastic-operator-1", *lease.Spec.HolderIdentity)
+}
+
+func TestMembership_LeaseExpiration(t *testing.T) {
+	ctx := context.Background()
+	c := k8s.NewFakeClient(operatorPod("elastic-operator-0", "sset-uid", true), operatorPod("elastic-operator-1", "sset-uid", true))
+	now := time.Now()
+	clock := func() time.Time { return now }
+	a := NewMembership(c, c, "elastic-system", "elastic-operator-0", ResourceScope, time.Minute)
+	a.now = clock
+	b := NewMembership(c, c, "elastic-system", "elastic-operator-1", ResourceScope, time.Minute)
+	b.now = clock
+
+	// the first replica holds a shard assigned to the second one, for example after a partition
+	var shard int
+	for owner([]string{"elastic-operator-0", "elastic-operator-1"}, LeaseName(shard)) != "elastic-operator-1" {
+		shard++
+	}
+	a.members = []string{"elastic-operator-0"}
+	require.NoError(t, a.acquireOrRenew(ctx, shard, now))
+	a.shards[shard].renewed = now
+	assert.True(t, a.owns(shard))
+
+	require.NoError(t, b.sync(ctx))
+	assert.False(t, b.owns(shard))
+
+	// the first replica does not renew its lease anymore: it stops reconciling the shard before the lease expires
+	now = now.Add(2*time.Minute + time.Second)
+	assert.False(t, a.owns(shard))
+	require.NoError(t, b.sync(ctx))
+	assert.False(t, b.owns(shard))
+	// the lease expires and is acquired by the second replica
+	now = now.Add(time.Minute)
+	require.NoError(t, b.sync(ctx))
+	assert.True(t, b.owns(shard))
+}
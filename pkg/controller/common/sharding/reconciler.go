@@ -0,0 +1,113 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package sharding
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Reconciler only reconciles the resources owned by this operator replica. Requests for other resources are
+// remembered, to be enqueued again if this replica becomes their owner, once the previous owner released them.
+type Reconciler struct {
+	reconcile.Reconciler
+	membership *Membership
+	// skippedTTL is the duration after which a skipped request that was not received again is forgotten, or zero to
+	// never forget them.
+	skippedTTL time.Duration
+
+	mutex sync.Mutex
+	// skipped holds the time at which each skipped request was last received.
+	skipped  map[reconcile.Request]time.Time
+	prunedAt time.Time
+	events   chan event.TypedGenericEvent[reconcile.Request]
+}
+
+// NewReconciler wraps the given reconciler to only reconcile the resources owned by this operator replica.
+// All the resources are reconciled again at least once per cache sync period: the skipped requests that are not
+// received again for two periods are for deleted resources, and are forgotten.
+func NewReconciler(membership *Membership, r reconcile.Reconciler, cacheSyncPeriod time.Duration) *Reconciler {
+	sharded := &Reconciler{
+		Reconciler: r,
+		membership: membership,
+		skippedTTL: 2 * cacheSyncPeriod,
+		skipped:    map[reconcile.Request]time.Time{},
+		prunedAt:   time.Now(),
+		events:     make(chan event.TypedGenericEvent[reconcile.Request]),
+	}
+	membership.AddListener(sharded.requeueOwned)
+	return sharded
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	r.mutex.Lock()
+	done, owned := r.membership.Acquire(request.NamespacedName)
+	if !owned {
+		now := time.Now()
+		r.skipped[request] = now
+		r.pruneSkipped(now)
+		r.mutex.Unlock()
+		return reconcile.Result{}, nil
+	}
+	delete(r.skipped, request)
+	r.mutex.Unlock()
+	// the shard of the resource is not handed over to another replica before the end of the reconciliation
+	defer done()
+	return r.Reconciler.Reconcile(ctx, request)
+}
+
+// pruneSkipped forgets the skipped requests that were not received again for the TTL, at most once per TTL so that
+// the requests are not all iterated over for each skipped request. It must be called with the mutex held.
+func (r *Reconciler) pruneSkipped(now time.Time) {
+	if r.skippedTTL <= 0 || now.Sub(r.prunedAt) < r.skippedTTL {
+		return
+	}
+	for request, receivedAt := range r.skipped {
+		if now.Sub(receivedAt) >= r.skippedTTL {
+			delete(r.skipped, request)
+		}
+	}
+	r.prunedAt = now
+}
+
+// Source returns the source of the requests skipped so far for resources now owned by this replica. It must be
+// watched by the controller using this reconciler.
+func (r *Reconciler) Source() source.Source {
+	return source.TypedChannel[reconcile.Request, reconcile.Request](r.events, handler.TypedFuncs[reconcile.Request, reconcile.Request]{
+		GenericFunc: func(_ context.Context, e event.TypedGenericEvent[reconcile.Request], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			q.Add(e.Object)
+		},
+	})
+}
+
+// requeueOwned enqueues the skipped requests for resources now owned by this replica.
+func (r *Reconciler) requeueOwned() {
+	var owned []reconcile.Request
+	r.mutex.Lock()
+	for request := range r.skipped {
+		if r.membership.Owns(request.NamespacedName) {
+			owned = append(owned, request)
+			delete(r.skipped, request)
+		}
+	}
+	r.mutex.Unlock()
+	if len(owned) == 0 {
+		return
+	}
+	// do not block the membership updates while the controller is busy
+	go func() {
+		for _, request := range owned {
+			r.events <- event.TypedGenericEvent[reconcile.Request]{Object: request}
+		}
+	}()
+}
@@ -0,0 +1,93 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package sharding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReconciler(t *testing.T) {
+	c := k8s.NewFakeClient(operatorPod("elastic-operator-0", "sset-uid", true), operatorPod("elastic-operator-1", "sset-uid", true))
+	m := NewMembership(c, c, "elastic-system", "elastic-operator-0", ResourceScope, time.Minute)
+	require.NoError(t, m.sync(context.Background()))
+
+	var reconciled []reconcile.Request
+	r := NewReconciler(m, reconcile.Func(func(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+		reconciled = append(reconciled, request)
+		return reconcile.Result{}, nil
+	}), time.Hour)
+
+	// find a resource owned by each replica
+	var mine, theirs reconcile.Request
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: name}}
+		if m.Owns(request.NamespacedName) {
+			mine = request
+		} else {
+			theirs = request
+		}
+	}
+	require.NotEmpty(t, mine.Name)
+	require.NotEmpty(t, theirs.Name)
+
+	_, err := r.Reconcile(context.Background(), mine)
+	require.NoError(t, err)
+	_, err = r.Reconcile(context.Background(), theirs)
+	require.NoError(t, err)
+	assert.Equal(t, []reconcile.Request{mine}, reconciled)
+
+	// the other replica leaves: the skipped request is enqueued again
+	require.NoError(t, c.Delete(context.Background(), operatorPod("elastic-operator-1", "sset-uid", true)))
+	require.NoError(t, m.sync(context.Background()))
+	select {
+	case e := <-r.events:
+		assert.Equal(t, theirs, e.Object)
+	case <-time.After(10 * time.Second):
+		t.Fatal("skipped request was not enqueued again")
+	}
+}
+
+func TestReconciler_ForgetsSkippedRequests(t *testing.T) {
+	c := k8s.NewFakeClient(operatorPod("elastic-operator-0", "sset-uid", true), operatorPod("elastic-operator-1", "sset-uid", true))
+	m := NewMembership(c, c, "elastic-system", "elastic-operator-0", ResourceScope, time.Minute)
+	require.NoError(t, m.sync(context.Background()))
+
+	r := NewReconciler(m, reconcile.Func(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{}, nil
+	}), time.Hour)
+
+	// find two resources owned by the other replica
+	var theirs []reconcile.Request
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: name}}
+		if !m.Owns(request.NamespacedName) {
+			theirs = append(theirs, request)
+		}
+	}
+	require.GreaterOrEqual(t, len(theirs), 2)
+	deleted, existing := theirs[0], theirs[1]
+
+	_, err := r.Reconcile(context.Background(), deleted)
+	require.NoError(t, err)
+	assert.Contains(t, r.skipped, deleted)
+
+	// the deleted resource is not requested again for longer than two cache sync periods
+	r.skipped[deleted] = time.Now().Add(-3 * time.Hour)
+	r.prunedAt = time.Now().Add(-3 * time.Hour)
+
+	_, err = r.Reconcile(context.Background(), existing)
+	require.NoError(t, err)
+	assert.NotContains(t, r.skipped, deleted)
+	assert.Contains(t, r.skipped, existing)
+}
@@ -0,0 +1,37 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// gracefulReconciler lets in-flight reconciliations complete when the operator shuts down, for up to the given grace
+// period, instead of interrupting them as soon as the shutdown starts. This avoids leaving orchestration steps such
+// as node shutdowns or shard allocation changes half-applied. No new reconciliation is started during the shutdown.
+type gracefulReconciler struct {
+	reconcile.Reconciler
+	gracePeriod time.Duration
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *gracefulReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	graceful, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(r.gracePeriod)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-graceful.Done():
+		}
+	})
+	defer stop()
+	return r.Reconciler.Reconcile(graceful, request)
+}
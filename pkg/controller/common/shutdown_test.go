@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_gracefulReconciler(t *testing.T) {
+	tests := []struct {
+		name        string
+		gracePeriod time.Duration
+		duration    time.Duration
+		wantErr     error
+	}{
+		{
+			name:        "in-flight reconciliation completes during the grace period",
+			gracePeriod: time.Minute,
+			duration:    50 * time.Millisecond,
+		},
+		{
+			name:        "in-flight reconciliation is interrupted after the grace period",
+			gracePeriod: 10 * time.Millisecond,
+			duration:    time.Minute,
+			wantErr:     context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := make(chan struct{})
+			r := &gracefulReconciler{
+				gracePeriod: tt.gracePeriod,
+				Reconciler: reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+					close(started)
+					select {
+					case <-time.After(tt.duration):
+						return reconcile.Result{}, nil
+					case <-ctx.Done():
+						return reconcile.Result{}, ctx.Err()
+					}
+				}),
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			errs := make(chan error)
+			go func() {
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				errs <- err
+			}()
+			<-started
+			// the operator shuts down
+			cancel()
+			select {
+			case err := <-errs:
+				assert.Equal(t, tt.wantErr, err)
+			case <-time.After(10 * time.Second):
+				require.Fail(t, "reconciliation did not return")
+			}
+		})
+	}
+}
@@ -54,7 +54,10 @@ func NewMetricBeatSidecar(
 		return BeatSidecar{}, err
 	}
 
-	image := container.ImageRepository(container.MetricbeatImage, v)
+	image, err := container.ResolveImage("", container.MetricbeatImage, v)
+	if err != nil {
+		return BeatSidecar{}, err
+	}
 
 	// EmptyDir volume so that MetricBeat does not write in the container image, which allows ReadOnlyRootFilesystem: true
 	emptyDir := volume.NewEmptyDirVolume("metricbeat-data", "/usr/share/metricbeat/data")
@@ -66,7 +69,10 @@ func NewFileBeatSidecar(ctx context.Context, client k8s.Client, resource monitor
 	if err != nil {
 		return BeatSidecar{}, err // error unlikely and should have been caught during validation
 	}
-	image := container.ImageRepository(container.FilebeatImage, v)
+	image, err := container.ResolveImage("", container.FilebeatImage, v)
+	if err != nil {
+		return BeatSidecar{}, err
+	}
 	// EmptyDir volume so that FileBeat does not write in the container image, which allows ReadOnlyRootFilesystem: true
 	emptyDir := volume.NewEmptyDirVolume("filebeat-data", "/usr/share/filebeat/data")
 	return NewBeatSidecar(ctx, client, "filebeat", image, resource, monitoring.GetLogsAssociation(resource), baseConfig, additionalVolume, emptyDir)
@@ -15,7 +15,6 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
 )
 
 // Params to specify a StatefulSet specification.
@@ -87,14 +86,9 @@ func Reconcile(
 			// compare hash of the StatefulSet at the time it was built
 			return hash.GetTemplateHashLabel(reconciled.Labels) != hash.GetTemplateHashLabel(expected.Labels)
 		},
-		UpdateReconciled: func() {
-			// set expected annotations and labels, but don't remove existing ones
-			// that may have been defaulted or set by a user/admin on the existing resource
-			reconciled.Labels = maps.Merge(reconciled.Labels, expected.Labels)
-			reconciled.Annotations = maps.Merge(reconciled.Annotations, expected.Annotations)
-			// overwrite the spec but leave the status intact
-			reconciled.Spec = expected.Spec
-		},
+		// only apply the labels, annotations and spec fields set by the operator, the ones that may have been
+		// defaulted or set by a user/admin or another controller on the existing resource are left untouched
+		ServerSideApply: true,
 	})
 	return *reconciled, err
 }
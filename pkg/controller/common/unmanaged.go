@@ -7,8 +7,11 @@ package common
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
@@ -19,8 +22,71 @@ const (
 	ManagedAnnotation    = "eck.k8s.elastic.co/managed"
 )
 
-// IsUnmanaged checks if a given resource is currently unmanaged.
+// maintenanceMode is true when the operator must not modify any resource, see SetMaintenanceMode.
+var maintenanceMode atomic.Bool
+
+// SetMaintenanceMode enables or disables the operator-wide maintenance mode. While it is enabled, all the resources
+// are considered as unmanaged, as if they had the ManagedAnnotation set to false.
+func SetMaintenanceMode(enabled bool) {
+	maintenanceMode.Store(enabled)
+}
+
+// IsMaintenanceMode returns true if the operator-wide maintenance mode is enabled.
+func IsMaintenanceMode() bool {
+	return maintenanceMode.Load()
+}
+
+// disabledControllers holds the set of controllers which must not reconcile any resource, see SetDisabledControllers.
+var disabledControllers atomic.Pointer[map[string]bool]
+
+// SetDisabledControllers sets the names of the controllers which must not reconcile any resource, replacing the
+// previously disabled ones.
+func SetDisabledControllers(names []string) {
+	disabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		disabled[name] = true
+	}
+	disabledControllers.Store(&disabled)
+}
+
+// IsControllerDisabled returns true if the controller with the given name must not reconcile any resource.
+func IsControllerDisabled(name string) bool {
+	disabled := disabledControllers.Load()
+	return disabled != nil && (*disabled)[name]
+}
+
+// pausedRequeueInterval is the interval at which resources are reconciled again while their controller is disabled or
+// the maintenance mode is enabled, so that their reconciliation resumes once it is turned off without any change.
+const pausedRequeueInterval = time.Minute
+
+// pausableReconciler skips the reconciliations while the controller is disabled, and periodically requeues the
+// resources while the controller is disabled or the maintenance mode is enabled. Both can be changed at runtime.
+type pausableReconciler struct {
+	reconcile.Reconciler
+	controller string
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *pausableReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	if IsControllerDisabled(r.controller) {
+		ulog.FromContext(ctx).V(1).Info("Controller disabled, skipping reconciliation",
+			"controller", r.controller, "namespace", request.Namespace, "name", request.Name)
+		return reconcile.Result{RequeueAfter: pausedRequeueInterval}, nil
+	}
+	result, err := r.Reconciler.Reconcile(ctx, request)
+	if err == nil && result.IsZero() && IsMaintenanceMode() {
+		result.RequeueAfter = pausedRequeueInterval
+	}
+	return result, err
+}
+
+// IsUnmanaged checks if a given resource is currently unmanaged, either because of its annotations or because the
+// operator is in maintenance mode.
 func IsUnmanaged(ctx context.Context, object metav1.Object) bool {
+	if IsMaintenanceMode() {
+		return true
+	}
+
 	managed, exists := object.GetAnnotations()[ManagedAnnotation]
 	if exists && managed == "false" {
 		return true
@@ -9,8 +9,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 type testcase struct {
@@ -90,3 +93,60 @@ func TestUnmanagedCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmanagedInMaintenanceMode(t *testing.T) {
+	defer SetMaintenanceMode(false)
+
+	managed := corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: "bar", Namespace: "foo"}}
+	unmanaged := corev1.Secret{ObjectMeta: v1.ObjectMeta{
+		Name:        "bar",
+		Namespace:   "foo",
+		Annotations: map[string]string{ManagedAnnotation: "false"},
+	}}
+
+	SetMaintenanceMode(true)
+	assert.True(t, IsMaintenanceMode())
+	assert.True(t, IsUnmanaged(context.Background(), &managed))
+	assert.True(t, IsUnmanaged(context.Background(), &unmanaged))
+
+	SetMaintenanceMode(false)
+	assert.False(t, IsMaintenanceMode())
+	assert.False(t, IsUnmanaged(context.Background(), &managed))
+	assert.True(t, IsUnmanaged(context.Background(), &unmanaged))
+}
+
+func Test_pausableReconciler(t *testing.T) {
+	defer SetMaintenanceMode(false)
+	defer SetDisabledControllers(nil)
+
+	calls := 0
+	r := &pausableReconciler{
+		Reconciler: reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			calls++
+			return reconcile.Result{}, nil
+		}),
+		controller: "test-controller",
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "name"}}
+
+	// enabled controller
+	result, err := r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{}, result)
+	require.Equal(t, 1, calls)
+
+	// disabled controller: reconciliation skipped and retried later
+	SetDisabledControllers([]string{"other-controller", "test-controller"})
+	result, err = r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{RequeueAfter: pausedRequeueInterval}, result)
+	require.Equal(t, 1, calls)
+
+	// maintenance mode: reconciliation performed and retried later
+	SetDisabledControllers([]string{"other-controller"})
+	SetMaintenanceMode(true)
+	result, err = r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{RequeueAfter: pausedRequeueInterval}, result)
+	require.Equal(t, 2, calls)
+}
@@ -0,0 +1,63 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package volume
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ProjectedSecretsVolume projects the content of several secrets into the same directory.
+// The secrets must not contain the same keys.
+type ProjectedSecretsVolume struct {
+	name        string
+	mountPath   string
+	secretNames []string
+}
+
+// NewProjectedSecretsVolume creates a new ProjectedSecretsVolume.
+func NewProjectedSecretsVolume(secretNames []string, name, mountPath string) ProjectedSecretsVolume {
+	return ProjectedSecretsVolume{
+		name:        name,
+		mountPath:   mountPath,
+		secretNames: secretNames,
+	}
+}
+
+// VolumeMount returns the k8s volume mount.
+func (pv ProjectedSecretsVolume) VolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      pv.name,
+		MountPath: pv.mountPath,
+		ReadOnly:  true,
+	}
+}
+
+// Volume returns the k8s volume.
+func (pv ProjectedSecretsVolume) Volume() corev1.Volume {
+	sources := make([]corev1.VolumeProjection, len(pv.secretNames))
+	for i, secretName := range pv.secretNames {
+		sources[i] = corev1.VolumeProjection{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Optional:             &defaultOptional,
+			},
+		}
+	}
+	return corev1.Volume{
+		Name: pv.name,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: sources,
+			},
+		},
+	}
+}
+
+// Name returns the name of the volume
+func (pv ProjectedSecretsVolume) Name() string {
+	return pv.name
+}
+
+var _ VolumeLike = ProjectedSecretsVolume{}
@@ -0,0 +1,29 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package volume
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestProjectedSecretsVolume(t *testing.T) {
+	testVolume := NewProjectedSecretsVolume([]string{"secret-0", "secret-1"}, "secrets", "/mnt")
+	assert.Equal(t, "secrets", testVolume.Name())
+	assert.Equal(t, corev1.VolumeMount{Name: "secrets", MountPath: "/mnt", ReadOnly: true}, testVolume.VolumeMount())
+	assert.Equal(t, corev1.Volume{
+		Name: "secrets",
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "secret-0"}, Optional: &defaultOptional}},
+					{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "secret-1"}, Optional: &defaultOptional}},
+				},
+			},
+		},
+	}, testVolume.Volume())
+}
@@ -29,16 +29,17 @@ import (
 // handleVolumeExpansion works around the immutability of VolumeClaimTemplates in StatefulSets by:
 // 1. updating storage requests in PVCs whose storage class supports volume expansion
 // 2. scheduling the StatefulSet for recreation with the new storage spec
+// 3. once the StatefulSet is recreated, restarting its Pods if the storage driver can only resize the filesystem
+// offline (as opposed to a hot resize while the Pod is running), by setting the FileSystemResizeAnnotation on the
+// Pod template of the expected StatefulSet
 // It returns a boolean indicating whether the StatefulSet needs to be recreated.
-// Note that some storage drivers also require Pods to be deleted/recreated for the filesystem to be resized
-// (as opposed to a hot resize while the Pod is running). This is left to the responsibility of the user.
 // This should be handled differently once supported by the StatefulSet controller: https://github.com/kubernetes/kubernetes/issues/68737.
 func HandleVolumeExpansion(
 	ctx context.Context,
 	k8sClient k8s.Client,
 	owner client.Object,
 	ownerKind string,
-	expectedSset appsv1.StatefulSet,
+	expectedSset *appsv1.StatefulSet,
 	actualSset appsv1.StatefulSet,
 	validateStorageClass bool,
 ) (bool, error) {
@@ -53,16 +54,23 @@ func HandleVolumeExpansion(
 	}
 
 	// resize all PVCs that can be resized
-	err := resizePVCs(ctx, k8sClient, owner, expectedSset, actualSset)
+	err := resizePVCs(ctx, k8sClient, owner, *expectedSset, actualSset)
 	if err != nil {
 		return false, err
 	}
 
 	// schedule the StatefulSet for recreation if needed
-	if needsRecreate(expectedSset, actualSset) {
+	if needsRecreate(*expectedSset, actualSset) {
 		return true, annotateForRecreation(ctx, k8sClient, owner, ownerKind, actualSset, expectedSset.Spec.VolumeClaimTemplates)
 	}
 
+	// restart the Pods whose file systems can only be resized offline
+	progress, err := GetExpansionProgress(k8sClient, actualSset)
+	if err != nil {
+		return false, err
+	}
+	setFileSystemResizeAnnotation(expectedSset, actualSset, progress)
+
 	return false, nil
 }
 
@@ -98,7 +106,7 @@ func resizePVCs(
 
 			newSize := expectedClaim.Spec.Resources.Requests.Storage()
 			ulog.FromContext(ctx).Info("Resizing PVC storage requests. Depending on the volume provisioner, "+
-				"Pods may need to be manually deleted for the filesystem to be resized.",
+				"Pods may be restarted for the filesystem to be resized.",
 				"namespace", pvc.Namespace, "name", name, "pvc_name", pvc.Name,
 				"old_value", pvc.Spec.Resources.Requests.Storage().String(), "new_value", newSize.String())
 
@@ -0,0 +1,114 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package volume
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// FileSystemResizeAnnotation is set on the Pod template of a StatefulSet whose volumes have been expanded, but whose
+// file systems can only be resized by the storage driver when the volumes are mounted again. Its value is the storage
+// requests of the volume claims of the StatefulSet: it changes with each expansion, which restarts the Pods once
+// through the regular rolling upgrade process.
+const FileSystemResizeAnnotation = "eck.k8s.elastic.co/filesystem-resize"
+
+// ExpansionProgress is the progress of the expansion of the volumes of one or more StatefulSets.
+type ExpansionProgress struct {
+	// Resizing is the number of PVCs whose volume is being expanded by the storage driver.
+	Resizing int
+	// FileSystemResizePending is the number of PVCs whose file system is resized once their Pod is restarted.
+	FileSystemResizePending int
+}
+
+// InProgress returns true if at least one PVC does not have the requested capacity yet.
+func (p ExpansionProgress) InProgress() bool {
+	return p.Resizing > 0 || p.FileSystemResizePending > 0
+}
+
+// Add returns the sum of both progresses.
+func (p ExpansionProgress) Add(other ExpansionProgress) ExpansionProgress {
+	return ExpansionProgress{
+		Resizing:                p.Resizing + other.Resizing,
+		FileSystemResizePending: p.FileSystemResizePending + other.FileSystemResizePending,
+	}
+}
+
+func (p ExpansionProgress) String() string {
+	return fmt.Sprintf("%d volumes being expanded, %d file systems to be resized after a Pod restart",
+		p.Resizing, p.FileSystemResizePending)
+}
+
+// GetExpansionProgress returns the progress of the expansion of the PVCs of the given StatefulSet.
+func GetExpansionProgress(k8sClient k8s.Client, statefulSet appsv1.StatefulSet) (ExpansionProgress, error) {
+	var progress ExpansionProgress
+	actualPVCs, err := sset.RetrieveActualPVCs(k8sClient, statefulSet)
+	if err != nil {
+		return progress, err
+	}
+	for _, pvcs := range actualPVCs {
+		for _, pvc := range pvcs {
+			switch {
+			case hasPVCCondition(pvc, corev1.PersistentVolumeClaimFileSystemResizePending):
+				progress.FileSystemResizePending++
+			case isResizing(pvc):
+				progress.Resizing++
+			}
+		}
+	}
+	return progress, nil
+}
+
+func hasPVCCondition(pvc corev1.PersistentVolumeClaim, conditionType corev1.PersistentVolumeClaimConditionType) bool {
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// isResizing returns true if the capacity of a bound PVC is lower than its storage requests.
+func isResizing(pvc corev1.PersistentVolumeClaim) bool {
+	capacity, exists := pvc.Status.Capacity[corev1.ResourceStorage]
+	if !exists || capacity.IsZero() {
+		// not bound yet
+		return false
+	}
+	return pvc.Spec.Resources.Requests.Storage().Cmp(capacity) > 0
+}
+
+// setFileSystemResizeAnnotation sets the FileSystemResizeAnnotation on the Pod template of the expected StatefulSet,
+// updated to restart the Pods if file systems can only be resized offline, or kept as is from the actual StatefulSet.
+func setFileSystemResizeAnnotation(expected *appsv1.StatefulSet, actual appsv1.StatefulSet, progress ExpansionProgress) {
+	value, exists := actual.Spec.Template.Annotations[FileSystemResizeAnnotation]
+	if progress.FileSystemResizePending > 0 {
+		value, exists = storageRequests(expected.Spec.VolumeClaimTemplates), true
+	}
+	if !exists || expected.Spec.Template.Annotations[FileSystemResizeAnnotation] == value {
+		return
+	}
+	if expected.Spec.Template.Annotations == nil {
+		expected.Spec.Template.Annotations = map[string]string{}
+	}
+	expected.Spec.Template.Annotations[FileSystemResizeAnnotation] = value
+	expected.Labels = hash.SetTemplateHashLabel(expected.Labels, expected.Spec)
+}
+
+// storageRequests returns the storage requests of the given claims, formatted as `name=size` pairs.
+func storageRequests(claims []corev1.PersistentVolumeClaim) string {
+	requests := make([]string, 0, len(claims))
+	for _, claim := range claims {
+		requests = append(requests, fmt.Sprintf("%s=%s", claim.Name, claim.Spec.Resources.Requests.Storage().String()))
+	}
+	return strings.Join(requests, ",")
+}
@@ -0,0 +1,132 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package volume
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func withCapacity(claim corev1.PersistentVolumeClaim, name string, capacity string) *corev1.PersistentVolumeClaim {
+	c := claim.DeepCopy()
+	c.Name = name
+	c.Namespace = "ns"
+	c.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)}
+	return c
+}
+
+func withFileSystemResizePending(claim *corev1.PersistentVolumeClaim) *corev1.PersistentVolumeClaim {
+	claim.Status.Conditions = []corev1.PersistentVolumeClaimCondition{
+		{Type: corev1.PersistentVolumeClaimFileSystemResizePending, Status: corev1.ConditionTrue},
+	}
+	return claim
+}
+
+func TestGetExpansionProgress(t *testing.T) {
+	sset := withClaims(sampleSset, withStorageReq(sampleClaim, "3Gi"))
+	sset.Spec.Replicas = ptr.To[int32](3)
+	claim := withStorageReq(sampleClaim, "3Gi")
+
+	tests := []struct {
+		name string
+		pvcs []client.Object
+		want ExpansionProgress
+	}{
+		{
+			name: "no PVCs",
+			want: ExpansionProgress{},
+		},
+		{
+			name: "all PVCs have the requested capacity",
+			pvcs: []client.Object{
+				withCapacity(claim, "sample-claim-sample-sset-0", "3Gi"),
+				withCapacity(claim, "sample-claim-sample-sset-1", "3Gi"),
+			},
+			want: ExpansionProgress{},
+		},
+		{
+			name: "volumes being expanded or waiting for a file system resize",
+			pvcs: []client.Object{
+				withCapacity(claim, "sample-claim-sample-sset-0", "3Gi"),
+				withCapacity(claim, "sample-claim-sample-sset-1", "1Gi"),
+				withFileSystemResizePending(withCapacity(claim, "sample-claim-sample-sset-2", "1Gi")),
+			},
+			want: ExpansionProgress{Resizing: 1, FileSystemResizePending: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetExpansionProgress(k8s.NewFakeClient(tt.pvcs...), sset)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.want.Resizing+tt.want.FileSystemResizePending > 0, got.InProgress())
+		})
+	}
+}
+
+func Test_setFileSystemResizeAnnotation(t *testing.T) {
+	expectedSset := func() appsv1.StatefulSet {
+		sset := withClaims(sampleSset, withStorageReq(sampleClaim, "3Gi"))
+		sset.Labels = hash.SetTemplateHashLabel(sset.Labels, sset.Spec)
+		return sset
+	}
+	actualSset := func(annotation string) appsv1.StatefulSet {
+		sset := withClaims(sampleSset, withStorageReq(sampleClaim, "3Gi"))
+		if annotation != "" {
+			sset.Spec.Template.Annotations = map[string]string{FileSystemResizeAnnotation: annotation}
+		}
+		sset.Labels = hash.SetTemplateHashLabel(sset.Labels, sset.Spec)
+		return sset
+	}
+
+	tests := []struct {
+		name           string
+		actual         appsv1.StatefulSet
+		progress       ExpansionProgress
+		wantAnnotation string
+		wantSameHash   bool
+	}{
+		{
+			name:         "no file system resize",
+			actual:       actualSset(""),
+			wantSameHash: true,
+		},
+		{
+			name:           "file system resize pending: restart the Pods",
+			actual:         actualSset(""),
+			progress:       ExpansionProgress{FileSystemResizePending: 1},
+			wantAnnotation: "sample-claim=3Gi",
+		},
+		{
+			name:           "file system resize pending for a new size: restart the Pods again",
+			actual:         actualSset("sample-claim=1Gi"),
+			progress:       ExpansionProgress{FileSystemResizePending: 1},
+			wantAnnotation: "sample-claim=3Gi",
+		},
+		{
+			name:           "keep the annotation of a previous resize",
+			actual:         actualSset("sample-claim=3Gi"),
+			wantAnnotation: "sample-claim=3Gi",
+			wantSameHash:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := expectedSset()
+			setFileSystemResizeAnnotation(&expected, tt.actual, tt.progress)
+			require.Equal(t, tt.wantAnnotation, expected.Spec.Template.Annotations[FileSystemResizeAnnotation])
+			require.Equal(t, tt.wantSameHash, expected.Labels[hash.TemplateHashLabelName] == tt.actual.Labels[hash.TemplateHashLabelName])
+		})
+	}
+}
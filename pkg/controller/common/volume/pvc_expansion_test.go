@@ -199,7 +199,7 @@ func Test_handleVolumeExpansionElasticsearch(t *testing.T) {
 
 			k8sClient := k8s.NewFakeClient(append(tt.runtimeObjs, &es)...)
 			recreate, err := HandleVolumeExpansion(context.Background(), k8sClient, &es, es.Kind,
-				tt.args.expectedSset, tt.args.actualSset, tt.args.validateStorageClass)
+				&tt.args.expectedSset, tt.args.actualSset, tt.args.validateStorageClass)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("handleVolumeExpansion() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -366,7 +366,7 @@ func Test_handleVolumeExpansionLogstash(t *testing.T) {
 				TypeMeta:   metav1.TypeMeta{Kind: logstashv1alpha1.Kind}}
 			k8sClient := k8s.NewFakeClient(append(tt.runtimeObjs, &ls)...)
 			recreate, err := HandleVolumeExpansion(context.Background(), k8sClient, &ls, ls.Kind,
-				tt.args.expectedSset, tt.args.actualSset, tt.args.validateStorageClass)
+				&tt.args.expectedSset, tt.args.actualSset, tt.args.validateStorageClass)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("handleVolumeExpansion() error = %v, wantErr %v", err, tt.wantErr)
 			}
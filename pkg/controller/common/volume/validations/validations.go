@@ -12,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -62,6 +63,21 @@ func claimMatchingName(claims []corev1.PersistentVolumeClaim, name string) *core
 	return nil
 }
 
+// StorageClassChanged returns true if at least one of the updated claims specifies a different storage class than the
+// initial claim with the same name.
+func StorageClassChanged(initial []corev1.PersistentVolumeClaim, updated []corev1.PersistentVolumeClaim) bool {
+	for _, updatedClaim := range updated {
+		initialClaim := claimMatchingName(initial, updatedClaim.Name)
+		if initialClaim == nil {
+			continue
+		}
+		if ptr.Deref(initialClaim.Spec.StorageClassName, "") != ptr.Deref(updatedClaim.Spec.StorageClassName, "") {
+			return true
+		}
+	}
+	return false
+}
+
 // EnsureClaimSupportsExpansion inspects whether the storage class referenced by the claim
 // allows volume expansion, and returns an error if it doesn't.
 func EnsureClaimSupportsExpansion(ctx context.Context, k8sClient k8s.Client, claim corev1.PersistentVolumeClaim, validateStorageClass bool) error {
@@ -74,11 +90,28 @@ func EnsureClaimSupportsExpansion(ctx context.Context, k8sClient k8s.Client, cla
 		return err
 	}
 	if !allowsVolumeExpansion(sc) {
-		return fmt.Errorf("claim %s (storage class %s) does not support volume expansion", claim.Name, sc.Name)
+		return &ExpansionNotSupportedError{ClaimName: claim.Name, StorageClassName: sc.Name}
 	}
 	return nil
 }
 
+// ExpansionNotSupportedError is returned when the storage requests of a claim are increased but its storage class does
+// not allow volume expansion.
+type ExpansionNotSupportedError struct {
+	ClaimName        string
+	StorageClassName string
+}
+
+func (e *ExpansionNotSupportedError) Error() string {
+	return fmt.Sprintf("claim %s (storage class %s) does not support volume expansion", e.ClaimName, e.StorageClassName)
+}
+
+// IsExpansionNotSupported returns true if the given error is an ExpansionNotSupportedError.
+func IsExpansionNotSupported(err error) bool {
+	var expansionErr *ExpansionNotSupportedError
+	return errors.As(err, &expansionErr)
+}
+
 // getStorageClass returns the storage class specified by the given claim,
 // or the default storage class if the claim does not specify any.
 func getStorageClass(k8sClient k8s.Client, claim corev1.PersistentVolumeClaim) (storagev1.StorageClass, error) {
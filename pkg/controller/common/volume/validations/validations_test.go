@@ -103,9 +103,13 @@ func Test_ensureClaimSupportsExpansion(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := EnsureClaimSupportsExpansion(context.Background(), tt.k8sClient, tt.claim, tt.validateStoragClass); (err != nil) != tt.wantErr {
+			err := EnsureClaimSupportsExpansion(context.Background(), tt.k8sClient, tt.claim, tt.validateStoragClass)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("ensureClaimSupportsExpansion() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if tt.wantErr && !IsExpansionNotSupported(err) {
+				t.Errorf("ensureClaimSupportsExpansion() error = %v, want an ExpansionNotSupportedError", err)
+			}
 		})
 	}
 }
@@ -360,3 +364,49 @@ func TestValidateClaimsUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestStorageClassChanged(t *testing.T) {
+	withStorageClass := func(claim corev1.PersistentVolumeClaim, storageClass *string) corev1.PersistentVolumeClaim {
+		c := claim.DeepCopy()
+		c.Spec.StorageClassName = storageClass
+		return *c
+	}
+	tests := []struct {
+		name    string
+		initial []corev1.PersistentVolumeClaim
+		updated []corev1.PersistentVolumeClaim
+		want    bool
+	}{
+		{
+			name:    "same claims",
+			initial: []corev1.PersistentVolumeClaim{sampleClaim, sampleClaim2},
+			updated: []corev1.PersistentVolumeClaim{sampleClaim, withStorageReq(sampleClaim2, "3Gi")},
+			want:    false,
+		},
+		{
+			name:    "new claim",
+			initial: []corev1.PersistentVolumeClaim{sampleClaim},
+			updated: []corev1.PersistentVolumeClaim{sampleClaim, withStorageClass(sampleClaim2, ptr.To[string]("other-sc"))},
+			want:    false,
+		},
+		{
+			name:    "default storage class specified explicitly",
+			initial: []corev1.PersistentVolumeClaim{withStorageClass(sampleClaim, nil)},
+			updated: []corev1.PersistentVolumeClaim{withStorageClass(sampleClaim, ptr.To[string](""))},
+			want:    false,
+		},
+		{
+			name:    "storage class changed",
+			initial: []corev1.PersistentVolumeClaim{sampleClaim, sampleClaim2},
+			updated: []corev1.PersistentVolumeClaim{sampleClaim, withStorageClass(sampleClaim2, ptr.To[string]("other-sc"))},
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StorageClassChanged(tt.initial, tt.updated); got != tt.want {
+				t.Errorf("StorageClassChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
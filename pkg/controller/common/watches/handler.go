@@ -6,10 +6,14 @@ package watches
 
 import (
 	"context"
+	"reflect"
+	"slices"
+	"strings"
 	"sync"
 
 	"golang.org/x/exp/maps"
 
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -17,6 +21,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 var (
@@ -36,14 +41,27 @@ type HandlerRegistration[T client.Object] interface {
 func NewDynamicEnqueueRequest[T client.Object]() *DynamicEnqueueRequest[T] {
 	return &DynamicEnqueueRequest[T]{
 		registrations: make(map[string]HandlerRegistration[T]),
+		resource:      resourceName[T](),
 	}
 }
 
+// resourceName returns the lowercase plural name of the resource watched by a DynamicEnqueueRequest, to be used as
+// metric label. Handlers of any kind of resource are reported as "objects".
+func resourceName[T client.Object]() string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Ptr {
+		return "objects"
+	}
+	return strings.ToLower(t.Elem().Name()) + "s"
+}
+
 // DynamicEnqueueRequest is an EventHandler that allows addition and removal of
 // event handler registrations at runtime allowing dynamic reconciliation based on specific resources.
 type DynamicEnqueueRequest[T client.Object] struct {
 	mutex         sync.RWMutex
 	registrations map[string]HandlerRegistration[T]
+	// resource is the name of the watched resource, used to report the number of registrations.
+	resource string
 }
 
 // AddHandlers adds the new event handlers to this DynamicEnqueueRequest.
@@ -64,6 +82,7 @@ func (d *DynamicEnqueueRequest[T]) AddHandler(handler HandlerRegistration[T]) er
 	_, exists := d.registrations[handler.Key()]
 	if !exists {
 		log.V(1).Info("Adding new handler registration", "key", handler.Key(), "current_registrations_keys", maps.Keys(d.registrations))
+		metrics.DynamicWatchesGauge.WithLabelValues(d.resource).Inc()
 	}
 	d.registrations[handler.Key()] = handler
 	return nil
@@ -78,6 +97,9 @@ func (d *DynamicEnqueueRequest[T]) RemoveHandler(handler HandlerRegistration[T])
 func (d *DynamicEnqueueRequest[T]) RemoveHandlerForKey(key string) {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
+	if _, exists := d.registrations[key]; exists {
+		metrics.DynamicWatchesGauge.WithLabelValues(d.resource).Dec()
+	}
 	delete(d.registrations, key)
 }
 
@@ -92,6 +114,32 @@ func (d *DynamicEnqueueRequest[T]) Registrations() []string {
 	return keys
 }
 
+// WatchedBy returns the resources watched on behalf of the given watcher by the registered named watches, sorted by
+// namespace and name.
+func (d *DynamicEnqueueRequest[T]) WatchedBy(watcher types.NamespacedName) []types.NamespacedName {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	var watched []types.NamespacedName
+	for _, registration := range d.registrations {
+		var namedWatch NamedWatch[T]
+		switch w := registration.(type) {
+		case NamedWatch[T]:
+			namedWatch = w
+		case *NamedWatch[T]:
+			namedWatch = *w
+		default:
+			continue
+		}
+		if namedWatch.Watcher == watcher {
+			watched = append(watched, namedWatch.Watched...)
+		}
+	}
+	slices.SortFunc(watched, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return slices.Compact(watched)
+}
+
 // DynamicEnqueueRequest implements TypedEventHandler
 var _ handler.TypedEventHandler[client.Object, reconcile.Request] = &DynamicEnqueueRequest[client.Object]{}
 
@@ -8,6 +8,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -24,6 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 type fakeHandler[T client.Object] struct {
@@ -402,3 +404,56 @@ func getRESTMapper() meta.RESTMapper {
 
 	return restmapper.NewDiscoveryRESTMapper(resources)
 }
+
+func Test_resourceName(t *testing.T) {
+	assert.Equal(t, "secrets", resourceName[*corev1.Secret]())
+	assert.Equal(t, "configmaps", resourceName[*corev1.ConfigMap]())
+	assert.Equal(t, "objects", resourceName[client.Object]())
+}
+
+func TestDynamicEnqueueRequest_RegistrationsMetric(t *testing.T) {
+	d := NewDynamicEnqueueRequest[*corev1.Service]()
+	gauge := metrics.DynamicWatchesGauge.WithLabelValues("services")
+	initial := testutil.ToFloat64(gauge)
+
+	require.NoError(t, d.AddHandler(&fakeHandler[*corev1.Service]{name: "a"}))
+	require.NoError(t, d.AddHandler(&fakeHandler[*corev1.Service]{name: "b"}))
+	// registering the same key again does not count twice
+	require.NoError(t, d.AddHandler(&fakeHandler[*corev1.Service]{name: "b"}))
+	assert.Equal(t, initial+2, testutil.ToFloat64(gauge))
+
+	d.RemoveHandlerForKey("a")
+	d.RemoveHandlerForKey("unknown")
+	assert.Equal(t, initial+1, testutil.ToFloat64(gauge))
+}
+
+func TestDynamicEnqueueRequest_WatchedBy(t *testing.T) {
+	es1 := types.NamespacedName{Namespace: "ns", Name: "es1"}
+	es2 := types.NamespacedName{Namespace: "ns", Name: "es2"}
+	d := NewDynamicEnqueueRequest[*corev1.Secret]()
+	require.NoError(t, d.AddHandlers(
+		NamedWatch[*corev1.Secret]{
+			Name:    "es1-secure-settings",
+			Watched: []types.NamespacedName{{Namespace: "ns", Name: "b"}, {Namespace: "ns", Name: "a"}},
+			Watcher: es1,
+		},
+		&NamedWatch[*corev1.Secret]{
+			Name:    "es1-http-certificate",
+			Watched: []types.NamespacedName{{Namespace: "ns", Name: "a"}, {Namespace: "ns", Name: "c"}},
+			Watcher: es1,
+		},
+		NamedWatch[*corev1.Secret]{
+			Name:    "es2-secure-settings",
+			Watched: []types.NamespacedName{{Namespace: "ns", Name: "d"}},
+			Watcher: es2,
+		},
+		&fakeHandler[*corev1.Secret]{name: "not-a-named-watch"},
+	))
+	require.Equal(t, []types.NamespacedName{
+		{Namespace: "ns", Name: "a"},
+		{Namespace: "ns", Name: "b"},
+		{Namespace: "ns", Name: "c"},
+	}, d.WatchedBy(es1))
+	require.Equal(t, []types.NamespacedName{{Namespace: "ns", Name: "d"}}, d.WatchedBy(es2))
+	require.Empty(t, d.WatchedBy(types.NamespacedName{Namespace: "ns", Name: "es3"}))
+}
@@ -0,0 +1,94 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package watches
+
+import (
+	"context"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// metadataOnly is true if only the metadata of Pods and Secrets is watched.
+var metadataOnly atomic.Bool
+
+// SetMetadataOnly sets whether only the metadata of Pods and Secrets is watched, which considerably reduces the memory
+// used by the cache when there are many of them. The full objects must then be read from the API server, see
+// k8s.MetadataOnlyObjects. It must be called before the watches are set up.
+func SetMetadataOnly(enabled bool) {
+	metadataOnly.Store(enabled)
+}
+
+// metadataOnlyGVK returns the GroupVersionKind of the given object if only its metadata must be watched.
+func metadataOnlyGVK(obj client.Object) (schema.GroupVersionKind, bool) {
+	if !metadataOnly.Load() {
+		return schema.GroupVersionKind{}, false
+	}
+	switch obj.(type) {
+	case *corev1.Pod:
+		return corev1.SchemeGroupVersion.WithKind("Pod"), true
+	case *corev1.Secret:
+		return corev1.SchemeGroupVersion.WithKind("Secret"), true
+	default:
+		return schema.GroupVersionKind{}, false
+	}
+}
+
+// Kind returns a source of events for the objects of the kind of obj, like source.Kind does.
+// If only the metadata of this kind of objects is watched, the handler is given objects with only their metadata set.
+func Kind[T client.Object](c cache.Cache, obj T, h handler.TypedEventHandler[T, reconcile.Request]) source.SyncingSource {
+	gvk, isMetadataOnly := metadataOnlyGVK(obj)
+	if !isMetadataOnly {
+		return source.Kind(c, obj, h)
+	}
+	metadata := &metav1.PartialObjectMetadata{}
+	metadata.SetGroupVersionKind(gvk)
+	return source.Kind[*metav1.PartialObjectMetadata](c, metadata, &metadataHandler[T]{kind: gvk.Kind, handler: h})
+}
+
+// metadataHandler passes the events of metadata-only watches to a handler of typed objects.
+type metadataHandler[T client.Object] struct {
+	kind    string
+	handler handler.TypedEventHandler[T, reconcile.Request]
+}
+
+var _ handler.TypedEventHandler[*metav1.PartialObjectMetadata, reconcile.Request] = &metadataHandler[*corev1.Secret]{}
+
+// toObject returns an object of the handled type with the given metadata.
+func (h *metadataHandler[T]) toObject(metadata *metav1.PartialObjectMetadata) T {
+	var obj client.Object
+	switch h.kind {
+	case "Pod":
+		obj = &corev1.Pod{ObjectMeta: metadata.ObjectMeta}
+	default:
+		obj = &corev1.Secret{ObjectMeta: metadata.ObjectMeta}
+	}
+	return obj.(T) //nolint:forcetypeassert
+}
+
+func (h *metadataHandler[T]) Create(ctx context.Context, evt event.TypedCreateEvent[*metav1.PartialObjectMetadata], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.handler.Create(ctx, event.TypedCreateEvent[T]{Object: h.toObject(evt.Object)}, q)
+}
+
+func (h *metadataHandler[T]) Update(ctx context.Context, evt event.TypedUpdateEvent[*metav1.PartialObjectMetadata], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.handler.Update(ctx, event.TypedUpdateEvent[T]{ObjectOld: h.toObject(evt.ObjectOld), ObjectNew: h.toObject(evt.ObjectNew)}, q)
+}
+
+func (h *metadataHandler[T]) Delete(ctx context.Context, evt event.TypedDeleteEvent[*metav1.PartialObjectMetadata], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.handler.Delete(ctx, event.TypedDeleteEvent[T]{Object: h.toObject(evt.Object), DeleteStateUnknown: evt.DeleteStateUnknown}, q)
+}
+
+func (h *metadataHandler[T]) Generic(ctx context.Context, evt event.TypedGenericEvent[*metav1.PartialObjectMetadata], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.handler.Generic(ctx, event.TypedGenericEvent[T]{Object: h.toObject(evt.Object)}, q)
+}
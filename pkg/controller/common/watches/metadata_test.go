@@ -0,0 +1,81 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package watches
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_metadataOnlyGVK(t *testing.T) {
+	defer SetMetadataOnly(false)
+
+	// disabled by default
+	_, isMetadataOnly := metadataOnlyGVK(&corev1.Secret{})
+	require.False(t, isMetadataOnly)
+
+	SetMetadataOnly(true)
+	gvk, isMetadataOnly := metadataOnlyGVK(&corev1.Secret{})
+	require.True(t, isMetadataOnly)
+	require.Equal(t, corev1.SchemeGroupVersion.WithKind("Secret"), gvk)
+	gvk, isMetadataOnly = metadataOnlyGVK(&corev1.Pod{})
+	require.True(t, isMetadataOnly)
+	require.Equal(t, corev1.SchemeGroupVersion.WithKind("Pod"), gvk)
+	// other kinds are fully watched
+	_, isMetadataOnly = metadataOnlyGVK(&corev1.ConfigMap{})
+	require.False(t, isMetadataOnly)
+}
+
+func Test_metadataHandler(t *testing.T) {
+	metadata := func(name string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: map[string]string{"a": "b"}}}
+	}
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+
+	// typed handler
+	var secrets []*corev1.Secret
+	secretHandler := &metadataHandler[*corev1.Secret]{
+		kind: "Secret",
+		handler: handler.TypedFuncs[*corev1.Secret, reconcile.Request]{
+			CreateFunc: func(_ context.Context, evt event.TypedCreateEvent[*corev1.Secret], _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				secrets = append(secrets, evt.Object)
+			},
+			UpdateFunc: func(_ context.Context, evt event.TypedUpdateEvent[*corev1.Secret], _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				secrets = append(secrets, evt.ObjectOld, evt.ObjectNew)
+			},
+		},
+	}
+	secretHandler.Create(context.Background(), event.TypedCreateEvent[*metav1.PartialObjectMetadata]{Object: metadata("s1")}, q)
+	secretHandler.Update(context.Background(), event.TypedUpdateEvent[*metav1.PartialObjectMetadata]{ObjectOld: metadata("s2"), ObjectNew: metadata("s3")}, q)
+	require.Equal(t, []*corev1.Secret{
+		{ObjectMeta: metadata("s1").ObjectMeta},
+		{ObjectMeta: metadata("s2").ObjectMeta},
+		{ObjectMeta: metadata("s3").ObjectMeta},
+	}, secrets)
+
+	// untyped handler
+	var objects []client.Object
+	podHandler := &metadataHandler[client.Object]{
+		kind: "Pod",
+		handler: handler.TypedFuncs[client.Object, reconcile.Request]{
+			DeleteFunc: func(_ context.Context, evt event.TypedDeleteEvent[client.Object], _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				require.True(t, evt.DeleteStateUnknown)
+				objects = append(objects, evt.Object)
+			},
+		},
+	}
+	podHandler.Delete(context.Background(), event.TypedDeleteEvent[*metav1.PartialObjectMetadata]{Object: metadata("p1"), DeleteStateUnknown: true}, q)
+	require.Equal(t, []client.Object{&corev1.Pod{ObjectMeta: metadata("p1").ObjectMeta}}, objects)
+}
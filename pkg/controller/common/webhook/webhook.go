@@ -16,8 +16,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/namespaces"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 // validatableObject is the object to be validated, along with methods
@@ -39,22 +39,22 @@ func SetupValidatingWebhookWithConfig(config *Config) error {
 				decoder:           admission.NewDecoder(config.Manager.GetScheme()),
 				validator:         config.Validator,
 				licenseChecker:    config.LicenseChecker,
-				managedNamespaces: set.Make(config.ManagedNamespace...)}})
+				managedNamespaces: config.ManagedNamespaces}})
 	return nil
 }
 
 // Config is the configuration for setting up a webhook
 type Config struct {
-	Manager          ctrl.Manager
-	WebhookPath      string
-	ManagedNamespace []string
-	LicenseChecker   license.Checker
-	Validator        admission.Validator
+	Manager           ctrl.Manager
+	WebhookPath       string
+	ManagedNamespaces namespaces.Managed
+	LicenseChecker    license.Checker
+	Validator         admission.Validator
 }
 
 type validatingWebhook struct {
 	decoder           admission.Decoder
-	managedNamespaces set.StringSet
+	managedNamespaces namespaces.Managed
 	licenseChecker    license.Checker
 	validator         admission.Validator
 }
@@ -76,7 +76,7 @@ func (v *validatingWebhook) Handle(ctx context.Context, req admission.Request) a
 
 	// If this resource is not within the set of managed namespaces
 	// for this operator ignore this request.
-	if v.managedNamespaces.Count() > 0 && !v.managedNamespaces.Has(obj.GetNamespace()) {
+	if !v.managedNamespaces.IsManaged(obj.GetNamespace()) {
 		whlog.V(1).Info("Skip resource validation", "name", obj.GetName(), "namespace", obj.GetNamespace())
 		return admission.Allowed("")
 	}
@@ -16,8 +16,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/namespaces"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 func asJSON(obj interface{}) []byte {
@@ -30,7 +30,7 @@ func asJSON(obj interface{}) []byte {
 
 func Test_validatingWebhook_Handle(t *testing.T) {
 	type fields struct {
-		managedNamespaces set.StringSet
+		managedNamespaces namespaces.Managed
 		validator         admission.Validator
 	}
 	tests := []struct {
@@ -42,7 +42,7 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 		{
 			name: "create properly validates valid agent, and returns allowed.",
 			fields: fields{
-				set.Make("elastic"),
+				namespaces.Static{"elastic"},
 				&agentv1alpha1.Agent{},
 			},
 			req: admission.Request{
@@ -71,7 +71,7 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 		{
 			name: "no policy id when agent running in standalone mode should not return a warning",
 			fields: fields{
-				set.Make("elastic"),
+				namespaces.Static{"elastic"},
 				&agentv1alpha1.Agent{},
 			},
 			req: admission.Request{
@@ -99,7 +99,7 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 		{
 			name: "no policy id is allowed when agent running in fleet mode but it should return a warning",
 			fields: fields{
-				set.Make("elastic"),
+				namespaces.Static{"elastic"},
 				&agentv1alpha1.Agent{},
 			},
 			req: admission.Request{
@@ -128,7 +128,7 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 		{
 			name: "create agent is denied because of invalid version, and returns denied.",
 			fields: fields{
-				set.Make("elastic"),
+				namespaces.Static{"elastic"},
 				&agentv1alpha1.Agent{},
 			},
 			req: admission.Request{
@@ -157,7 +157,7 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 		{
 			name: "delete agent is always allowed",
 			fields: fields{
-				set.Make("elastic"),
+				namespaces.Static{"elastic"},
 				&agentv1alpha1.Agent{},
 			},
 			req: admission.Request{
@@ -186,7 +186,7 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 		{
 			name: "request from un-managed namespace is ignored, and just accepted",
 			fields: fields{
-				set.Make("elastic"),
+				namespaces.Static{"elastic"},
 				&agentv1alpha1.Agent{},
 			},
 			req: admission.Request{
@@ -214,7 +214,7 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 		{
 			name: "update agent is allowed when label is updated",
 			fields: fields{
-				set.Make("elastic"),
+				namespaces.Static{"elastic"},
 				&agentv1alpha1.Agent{},
 			},
 			req: admission.Request{
@@ -259,7 +259,7 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 		{
 			name: "update agent is denied when version downgrade is attempted",
 			fields: fields{
-				set.Make("elastic"),
+				namespaces.Static{"elastic"},
 				&agentv1alpha1.Agent{},
 			},
 			req: admission.Request{
@@ -0,0 +1,117 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package dataview
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	dataviewv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/dataview/v1alpha1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	controllerName = "dataview-controller"
+
+	// DataViewFinalizer lets the operator delete the data view from Kibana before the DataView resource is deleted.
+	DataViewFinalizer = "dataview.k8s.elastic.co/delete-data-view"
+)
+
+// config identifies the DataView controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "DataView",
+	NameField:      "dataview_name",
+	Finalizer:      DataViewFinalizer,
+}
+
+// Add creates a new DataView Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Kibana and reconcile the DataView resources referencing them
+		source.Kind[client.Object](mgr.GetCache(), &kbv1.Kibana{}, reconcileRequestForDataViews(r.Client)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of DataView.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*dataviewv1alpha1.DataView, dataviewv1alpha1.DataViewStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &dataViewKind{
+		Client:           c,
+		kbClientProvider: kbclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+// reconcileRequestForDataViews returns the requests to reconcile the DataView resources that reference the watched Kibana.
+func reconcileRequestForDataViews(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &dataviewv1alpha1.DataViewList{}, func(dataView *dataviewv1alpha1.DataView, obj client.Object) bool {
+		return dataView.References(k8s.ExtractNamespacedName(obj))
+	})
+}
+
+// dataViewKind configures DataView resources in Kibana.
+type dataViewKind struct {
+	k8s.Client
+	kbClientProvider kbclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*dataviewv1alpha1.DataView, dataviewv1alpha1.DataViewStatus] = &dataViewKind{}
+	_ apiresource.Remover[*dataviewv1alpha1.DataView]                               = &dataViewKind{}
+)
+
+func (r *dataViewKind) NewObject() *dataviewv1alpha1.DataView {
+	return &dataviewv1alpha1.DataView{}
+}
+
+func (r *dataViewKind) GetStatus(dataView *dataviewv1alpha1.DataView) dataviewv1alpha1.DataViewStatus {
+	return dataView.Status
+}
+
+func (r *dataViewKind) SetStatus(dataView *dataviewv1alpha1.DataView, status dataviewv1alpha1.DataViewStatus) {
+	dataView.Status = status
+}
+
+func (r *dataViewKind) InvalidStatus(dataView *dataviewv1alpha1.DataView, err error) dataviewv1alpha1.DataViewStatus {
+	status := dataView.Status
+	status.Phase = dataviewv1alpha1.InvalidPhase
+	status.Message = err.Error()
+	return status
+}
+
+// Configure configures the data view on the referenced Kibana.
+func (r *dataViewKind) Configure(ctx context.Context, obj *dataviewv1alpha1.DataView) (*reconciler.Results, dataviewv1alpha1.DataViewStatus) {
+	dataView := *obj
+	results := reconciler.NewResult(ctx)
+
+	// configure the data view on the referenced Kibana
+	status := r.reconcileDataView(ctx, dataView)
+
+	results.WithResult(apiresource.Requeue(status.Phase == dataviewv1alpha1.ReadyPhase))
+
+	return results, status
+}
+
+// Remove deletes the data view from Kibana.
+func (r *dataViewKind) Remove(ctx context.Context, obj *dataviewv1alpha1.DataView) (reconcile.Result, error) {
+	return reconcile.Result{}, r.deleteDataView(ctx, *obj)
+}
@@ -0,0 +1,242 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package dataview
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	dataviewv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/dataview/v1alpha1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeKbClient stores data views and the default data view of each space in memory.
+type fakeKbClient struct {
+	// Client is embedded for the APIs the controller does not use
+	kbclient.Client
+	kibana *fakeKibana
+}
+
+type fakeKibana struct {
+	// dataViews are indexed by space and id
+	dataViews map[string]map[string]kbclient.DataView
+	defaults  map[string]string
+	updates   int
+}
+
+func newFakeKibana() *fakeKibana {
+	return &fakeKibana{dataViews: map[string]map[string]kbclient.DataView{}, defaults: map[string]string{}}
+}
+
+func notFound() error {
+	return &commonhttp.APIError{StatusCode: http.StatusNotFound}
+}
+
+func (c fakeKbClient) GetDataView(_ context.Context, spaceID, id string) (kbclient.DataView, error) {
+	dataView, exists := c.kibana.dataViews[spaceID][id]
+	if !exists {
+		return kbclient.DataView{}, notFound()
+	}
+	return dataView, nil
+}
+
+func (c fakeKbClient) CreateDataView(_ context.Context, spaceID string, dataView kbclient.DataView) error {
+	if _, exists := c.kibana.dataViews[spaceID][dataView.ID]; exists {
+		return fmt.Errorf("data view %s already exists", dataView.ID)
+	}
+	if c.kibana.dataViews[spaceID] == nil {
+		c.kibana.dataViews[spaceID] = map[string]kbclient.DataView{}
+	}
+	c.kibana.dataViews[spaceID][dataView.ID] = dataView
+	return nil
+}
+
+func (c fakeKbClient) UpdateDataView(_ context.Context, spaceID, id string, dataView kbclient.DataView) error {
+	if _, exists := c.kibana.dataViews[spaceID][id]; !exists {
+		return notFound()
+	}
+	c.kibana.updates++
+	dataView.ID = id
+	c.kibana.dataViews[spaceID][id] = dataView
+	return nil
+}
+
+func (c fakeKbClient) DeleteDataView(_ context.Context, spaceID, id string) error {
+	if _, exists := c.kibana.dataViews[spaceID][id]; !exists {
+		return notFound()
+	}
+	delete(c.kibana.dataViews[spaceID], id)
+	return nil
+}
+
+func (c fakeKbClient) GetDefaultDataView(_ context.Context, spaceID string) (string, error) {
+	return c.kibana.defaults[spaceID], nil
+}
+
+func (c fakeKbClient) SetDefaultDataView(_ context.Context, spaceID, id string) error {
+	c.kibana.defaults[spaceID] = id
+	return nil
+}
+
+func (c fakeKbClient) Close() {}
+
+func fakeClientProvider(kibana *fakeKibana) kbclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ kbv1.Kibana) (kbclient.Client, error) {
+		return fakeKbClient{kibana: kibana}, nil
+	}
+}
+
+func mkDataView(name string, isDefault bool) *dataviewv1alpha1.DataView {
+	return &dataviewv1alpha1.DataView{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Spec: dataviewv1alpha1.DataViewSpec{
+			KibanaRef:     commonv1.LocalObjectSelector{Name: "kb"},
+			SpaceID:       "team-a",
+			IndexPattern:  name + "-*",
+			TimeFieldName: "@timestamp",
+			Default:       isDefault,
+		},
+	}
+}
+
+func TestReconcileDataView_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	dataView := mkDataView("logs", true)
+	dataView.Spec.Title = "Application logs"
+	dataView.Spec.RuntimeFields = []dataviewv1alpha1.RuntimeField{{Name: "day", Type: "keyword", Script: "emit('monday')"}}
+	kb := &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Status:     kbv1.KibanaStatus{DeploymentStatus: commonv1.DeploymentStatus{Health: commonv1.RedHealth}},
+	}
+	kibana := newFakeKibana()
+	k8sClient := k8s.NewFakeClient(dataView, kb)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &dataViewKind{
+		Client:           k8sClient,
+		kbClientProvider: fakeClientProvider(kibana),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "logs"}}
+	getDataView := func() dataviewv1alpha1.DataView {
+		var actual dataviewv1alpha1.DataView
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+
+	// nothing is configured until Kibana is ready
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	actual := getDataView()
+	require.Equal(t, dataviewv1alpha1.ApplyingChangesPhase, actual.Status.Phase)
+	require.Equal(t, []string{DataViewFinalizer}, actual.Finalizers)
+	require.Empty(t, kibana.dataViews)
+
+	// the data view is created in its space and set as default once Kibana is ready
+	kb.Status.Health = commonv1.GreenHealth
+	require.NoError(t, k8sClient.Status().Update(ctx, kb))
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	actual = getDataView()
+	require.Equal(t, dataviewv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, "logs", actual.Status.DataViewID)
+	require.Equal(t, "team-a", actual.Status.SpaceID)
+	require.True(t, actual.Status.Default)
+	require.Equal(t, kbclient.DataView{
+		ID:            "logs",
+		Title:         "logs-*",
+		Name:          "Application logs",
+		TimeFieldName: "@timestamp",
+		RuntimeFieldMap: map[string]kbclient.RuntimeField{
+			"day": {Type: "keyword", Script: kbclient.RuntimeFieldScript{Source: "emit('monday')"}},
+		},
+	}, kibana.dataViews["team-a"]["logs"])
+	require.Equal(t, "logs", kibana.defaults["team-a"])
+
+	// nothing is updated when the data view matches the specification
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 0, kibana.updates)
+	require.Empty(t, recorder.Events)
+
+	// the data view and the default data view are restored if they are modified in Kibana
+	modified := kibana.dataViews["team-a"]["logs"]
+	modified.TimeFieldName = "event.created"
+	kibana.dataViews["team-a"]["logs"] = modified
+	kibana.defaults["team-a"] = "other"
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "@timestamp", kibana.dataViews["team-a"]["logs"].TimeFieldName)
+	require.Equal(t, "logs", kibana.defaults["team-a"])
+	require.NotNil(t, getDataView().Status.LastDriftTime)
+	require.Contains(t, <-recorder.Events, "Data view logs was modified in Kibana kb")
+
+	// the default data view is unset when the data view is no longer the default one, which is not a drift
+	actual = getDataView()
+	actual.Generation++
+	actual.Spec.Default = false
+	actual.Spec.RuntimeFields = nil
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, kibana.defaults["team-a"])
+	require.Empty(t, kibana.dataViews["team-a"]["logs"].RuntimeFieldMap)
+	actual = getDataView()
+	require.False(t, actual.Status.Default)
+	require.Empty(t, recorder.Events)
+
+	// the data view is deleted from Kibana with the DataView
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, kibana.dataViews["team-a"])
+	err = k8sClient.Get(ctx, request.NamespacedName, &dataviewv1alpha1.DataView{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileDataView_DefaultConflict(t *testing.T) {
+	ctx := context.Background()
+	kb := &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Status:     kbv1.KibanaStatus{DeploymentStatus: commonv1.DeploymentStatus{Health: commonv1.GreenHealth}},
+	}
+	kibana := newFakeKibana()
+	k8sClient := k8s.NewFakeClient(mkDataView("logs", true), mkDataView("metrics", true), kb)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &dataViewKind{
+		Client:           k8sClient,
+		kbClientProvider: fakeClientProvider(kibana),
+		recorder:         recorder,
+	})
+
+	// only the first DataView by name is the default data view of the space
+	for _, name := range []string{"metrics", "logs"} {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: name}})
+		require.NoError(t, err)
+	}
+	var metrics dataviewv1alpha1.DataView
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "metrics"}, &metrics))
+	require.Equal(t, dataviewv1alpha1.ErrorPhase, metrics.Status.Phase)
+	require.Equal(t, "DataView logs is already the default data view of space team-a in Kibana kb", metrics.Status.Message)
+	require.NotContains(t, kibana.dataViews["team-a"], "metrics")
+	require.Equal(t, "logs", kibana.defaults["team-a"])
+}
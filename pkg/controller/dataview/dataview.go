@@ -0,0 +1,220 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package dataview
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	dataviewv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/dataview/v1alpha1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileDataView configures the data view on the referenced Kibana if it does not exist yet or if it differs from
+// the specification, and returns the status of the data view.
+func (r *dataViewKind) reconcileDataView(ctx context.Context, dataView dataviewv1alpha1.DataView) dataviewv1alpha1.DataViewStatus {
+	defer tracing.Span(&ctx)()
+	kbName := dataView.Spec.KibanaRef.Name
+	log := ulog.FromContext(ctx).WithValues("kibana_name", kbName)
+
+	previous := dataView.Status
+	status := dataviewv1alpha1.DataViewStatus{
+		Phase:              dataviewv1alpha1.ReadyPhase,
+		DataViewID:         dataView.DataViewIDOrDefault(),
+		SpaceID:            dataView.SpaceIDOrDefault(),
+		Default:            previous.Default,
+		LastDriftTime:      previous.LastDriftTime,
+		ObservedGeneration: dataView.Generation,
+	}
+	withPhase := func(phase dataviewv1alpha1.Phase, msg string) dataviewv1alpha1.DataViewStatus {
+		status.Phase = phase
+		status.Message = msg
+		return status
+	}
+	failed := func(msg string) dataviewv1alpha1.DataViewStatus {
+		r.recorder.Event(&dataView, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return withPhase(dataviewv1alpha1.ErrorPhase, msg)
+	}
+
+	var kb kbv1.Kibana
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: dataView.Namespace, Name: kbName}, &kb); err != nil {
+		if apierrors.IsNotFound(err) {
+			return withPhase(dataviewv1alpha1.ErrorPhase, fmt.Sprintf("Kibana %s not found", kbName))
+		}
+		return withPhase(dataviewv1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	if kb.Status.Health != commonv1.GreenHealth {
+		return withPhase(dataviewv1alpha1.ApplyingChangesPhase, "Waiting for Kibana to be ready")
+	}
+
+	if dataView.Spec.Default {
+		owner, err := r.defaultDataViewOwner(ctx, dataView)
+		if err != nil {
+			return withPhase(dataviewv1alpha1.ApplyingChangesPhase, err.Error())
+		}
+		if owner != dataView.Name {
+			return failed(fmt.Sprintf("DataView %s is already the default data view of space %s in Kibana %s", owner, status.SpaceID, kbName))
+		}
+	}
+
+	kbClient, err := r.kbClientProvider(ctx, r.Client, r.params.Dialer, kb)
+	if err != nil {
+		return withPhase(dataviewv1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	defer kbClient.Close()
+
+	// changes are drifts if the data view was already configured from the current specification
+	configured := previous.Phase == dataviewv1alpha1.ReadyPhase && previous.ObservedGeneration == dataView.Generation
+
+	changed, err := reconcileDefinition(ctx, kbClient, status.SpaceID, status.DataViewID, expectedDataView(dataView))
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to configure data view on Kibana %s: %s", kbName, err.Error()))
+	}
+
+	defaultChanged, err := reconcileDefault(ctx, kbClient, dataView, status.SpaceID, status.DataViewID)
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to configure default data view on Kibana %s: %s", kbName, err.Error()))
+	}
+	status.Default = dataView.Spec.Default
+
+	if configured && (changed || (defaultChanged && previous.Default)) {
+		msg := fmt.Sprintf("Data view %s was modified in Kibana %s, restoring it from the DataView specification", status.DataViewID, kbName)
+		status.LastDriftTime = apiresource.RecordDrift(log, r.recorder, &dataView, msg)
+	}
+	return status
+}
+
+// defaultDataViewOwner returns the name of the DataView that should be the default data view of the space of the given
+// DataView: the first one, by name, of the DataView resources configured as default for the same Kibana and space.
+func (r *dataViewKind) defaultDataViewOwner(ctx context.Context, dataView dataviewv1alpha1.DataView) (string, error) {
+	var dataViews dataviewv1alpha1.DataViewList
+	if err := r.Client.List(ctx, &dataViews, client.InNamespace(dataView.Namespace)); err != nil {
+		return "", err
+	}
+	kb := dataView.Spec.KibanaRef.WithDefaultNamespace(dataView.Namespace).NamespacedName()
+	var candidates []string
+	for _, other := range dataViews.Items {
+		if other.Spec.Default && !other.IsMarkedForDeletion() && other.References(kb) && other.SpaceIDOrDefault() == dataView.SpaceIDOrDefault() {
+			candidates = append(candidates, other.Name)
+		}
+	}
+	if len(candidates) == 0 {
+		return dataView.Name, nil
+	}
+	sort.Strings(candidates)
+	return candidates[0], nil
+}
+
+// reconcileDefinition creates the data view if it does not exist, and updates it if it differs from the expected
+// definition. It returns true if the data view was missing or different.
+func reconcileDefinition(ctx context.Context, kbClient kbclient.Client, spaceID, id string, expected kbclient.DataView) (bool, error) {
+	log := ulog.FromContext(ctx).WithValues("data_view_id", id, "space_id", spaceID)
+	actual, err := kbClient.GetDataView(ctx, spaceID, id)
+	if kbclient.IsNotFound(err) {
+		log.Info("Creating data view")
+		return true, kbClient.CreateDataView(ctx, spaceID, expected)
+	}
+	if err != nil {
+		return false, err
+	}
+	if sameDataView(expected, actual) {
+		return false, nil
+	}
+	log.Info("Updating data view")
+	return true, kbClient.UpdateDataView(ctx, spaceID, id, expected)
+}
+
+// reconcileDefault sets the data view as the default data view of its space if requested, or unsets it if the operator
+// made it the default data view and it no longer should be. It returns true if the default data view was changed.
+func reconcileDefault(ctx context.Context, kbClient kbclient.Client, dataView dataviewv1alpha1.DataView, spaceID, id string) (bool, error) {
+	if !dataView.Spec.Default && !dataView.Status.Default {
+		return false, nil
+	}
+	actual, err := kbClient.GetDefaultDataView(ctx, spaceID)
+	if err != nil {
+		return false, err
+	}
+	log := ulog.FromContext(ctx).WithValues("data_view_id", id, "space_id", spaceID)
+	switch {
+	case dataView.Spec.Default && actual != id:
+		log.Info("Setting default data view")
+		return true, kbClient.SetDefaultDataView(ctx, spaceID, id)
+	case !dataView.Spec.Default && actual == id:
+		log.Info("Unsetting default data view")
+		return true, kbClient.SetDefaultDataView(ctx, spaceID, "")
+	}
+	return false, nil
+}
+
+// deleteDataView deletes the data view from Kibana.
+func (r *dataViewKind) deleteDataView(ctx context.Context, dataView dataviewv1alpha1.DataView) error {
+	defer tracing.Span(&ctx)()
+
+	var kb kbv1.Kibana
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: dataView.Namespace, Name: dataView.Spec.KibanaRef.Name}, &kb); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	kbClient, err := r.kbClientProvider(ctx, r.Client, r.params.Dialer, kb)
+	if err != nil {
+		return err
+	}
+	defer kbClient.Close()
+
+	id := dataView.DataViewIDOrDefault()
+	ulog.FromContext(ctx).Info("Deleting data view", "data_view_id", id, "kibana_name", kb.Name)
+	if err := kbClient.DeleteDataView(ctx, dataView.SpaceIDOrDefault(), id); err != nil && !kbclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// expectedDataView returns the data view of the specification as expected by the Kibana data views API.
+func expectedDataView(dataView dataviewv1alpha1.DataView) kbclient.DataView {
+	expected := kbclient.DataView{
+		ID:            dataView.DataViewIDOrDefault(),
+		Title:         dataView.Spec.IndexPattern,
+		Name:          dataView.TitleOrDefault(),
+		TimeFieldName: dataView.Spec.TimeFieldName,
+	}
+	if len(dataView.Spec.RuntimeFields) > 0 {
+		expected.RuntimeFieldMap = make(map[string]kbclient.RuntimeField, len(dataView.Spec.RuntimeFields))
+		for _, field := range dataView.Spec.RuntimeFields {
+			expected.RuntimeFieldMap[field.Name] = kbclient.RuntimeField{
+				Type:   field.Type,
+				Script: kbclient.RuntimeFieldScript{Source: field.Script},
+			}
+		}
+	}
+	return expected
+}
+
+// sameDataView returns true if the data view returned by Kibana matches the expected one. The id is not compared since
+// the data view is retrieved by id.
+func sameDataView(expected, actual kbclient.DataView) bool {
+	if expected.Title != actual.Title || expected.Name != actual.Name || expected.TimeFieldName != actual.TimeFieldName {
+		return false
+	}
+	if len(expected.RuntimeFieldMap) == 0 && len(actual.RuntimeFieldMap) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(expected.RuntimeFieldMap, actual.RuntimeFieldMap)
+}
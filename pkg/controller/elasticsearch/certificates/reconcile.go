@@ -45,11 +45,13 @@ func ReconcileHTTP(
 	certsLabels := label.NewLabels(k8s.ExtractNamespacedName(&es))
 
 	// Create some additional SANs, mostly to be used in the context of client autodiscovery (a.k.a. sniffing).
-	extraHTTPSANs := make([]commonv1.SubjectAlternativeName, len(es.Spec.NodeSets))
-	for i, nodeSet := range es.Spec.NodeSets {
+	nodeSets := es.Spec.ExpandedNodeSets()
+	extraHTTPSANs := make([]commonv1.SubjectAlternativeName, len(nodeSets))
+	for i, nodeSet := range nodeSets {
 		extraHTTPSANs[i] =
-			commonv1.SubjectAlternativeName{DNS: "*." + nodespec.HeadlessServiceName(esv1.StatefulSet(es.Name, nodeSet.Name)) + "." + es.Namespace + ".svc"}
+			commonv1.SubjectAlternativeName{DNS: "*." + nodespec.HeadlessServiceName(es.StatefulSetName(nodeSet.Name)) + "." + es.Namespace + ".svc"}
 	}
+	extraHTTPSANs = append(extraHTTPSANs, es.Spec.HTTP.ExternalSANs()...)
 
 	// reconcile HTTP CA and cert
 	var httpCerts *certificates.CertificatesSecret
@@ -97,6 +99,9 @@ func ReconcileTransport(
 
 	results := reconciler.NewResult(ctx)
 
+	// the transport rotation settings of the cluster take precedence over the operator ones
+	caRotation, certRotation = certificates.OverrideRotationParams(caRotation, certRotation, es.Spec.Transport.TLS.CertificateRotation)
+
 	// label certificates secrets with the cluster name
 	certsLabels := label.NewLabels(k8s.ExtractNamespacedName(&es))
 
@@ -126,6 +131,12 @@ func ReconcileTransport(
 			RequeueAfter(certificates.ShouldRotateIn(time.Now(), transportCA.Cert.NotAfter, caRotation.RotateBefore)).
 			ReconciliationComplete(), // This reconciliation result should not prevent the reconciliation loop to be considered as completed in the status
 	)
+	// move a forced CA rotation forward
+	if transportCA.Rotation != nil {
+		results.WithReconciliationState(
+			reconciler.RequeueAfter(certificates.ShouldRotateIn(time.Now(), transportCA.Rotation.NextPhase, 0)).ReconciliationComplete(),
+		)
+	}
 
 	// reconcile transport public certs secret
 	if err := transport.ReconcileTransportCertsPublicSecret(ctx, driver.K8sClient(), es, transportCA, additionalCAs); err != nil {
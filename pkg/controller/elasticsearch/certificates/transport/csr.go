@@ -104,6 +104,18 @@ func buildGeneralNames(
 		{IPAddress: netutil.IPToRFCForm(netutil.LoopbackFor(netutil.ToIPFamily(podIP.String())))},
 	}
 
+	// on dual-stack clusters, also add the secondary IP of the Pod and the loopback address of its family
+	for _, ip := range pod.Status.PodIPs {
+		secondaryIP := net.ParseIP(ip.IP)
+		if secondaryIP == nil || secondaryIP.Equal(podIP) {
+			continue
+		}
+		generalNames = append(generalNames,
+			certificates.GeneralName{IPAddress: netutil.IPToRFCForm(secondaryIP)},
+			certificates.GeneralName{IPAddress: netutil.IPToRFCForm(netutil.LoopbackFor(netutil.ToIPFamily(secondaryIP.String())))},
+		)
+	}
+
 	for _, san := range cluster.Spec.Transport.TLS.SubjectAlternativeNames {
 		if san.DNS != "" {
 			generalNames = append(generalNames, certificates.GeneralName{DNSName: san.DNS})
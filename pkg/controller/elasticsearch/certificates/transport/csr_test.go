@@ -180,6 +180,21 @@ func Test_buildGeneralNames(t *testing.T) {
 				{DNSName: "my-custom-domain"},
 			}...),
 		},
+		{
+			name: "dual-stack Pod",
+			args: args{
+				cluster: testES,
+				pod: func() corev1.Pod {
+					pod := testPod
+					pod.Status.PodIPs = []corev1.PodIP{{IP: testIP}, {IP: "2001:db8::1"}}
+					return pod
+				}(),
+			},
+			want: append(append([]certificates.GeneralName{}, expectedGeneralNames...), []certificates.GeneralName{
+				{IPAddress: net.ParseIP("2001:db8::1")},
+				{IPAddress: net.IPv6loopback},
+			}...),
+		},
 		{
 			name: "custom name suffix",
 			args: args{
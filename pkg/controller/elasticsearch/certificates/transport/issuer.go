@@ -0,0 +1,220 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package transport
+
+import (
+	"context"
+	"crypto"
+	cryptorand "crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// pendingCSRRequeue is the delay before checking again a CertificateSigningRequest waiting for its certificate.
+	pendingCSRRequeue = 10 * time.Second
+	csrPEMBlockType   = "CERTIFICATE REQUEST"
+)
+
+// csrLabels returns the labels of the CertificateSigningRequests of the given Pod. CertificateSigningRequests are
+// cluster-scoped and cannot be owned by the Elasticsearch resource, the labels identify the cluster and the Pod.
+func csrLabels(es esv1.Elasticsearch, pod corev1.Pod) map[string]string {
+	return map[string]string{
+		label.ClusterNameLabelName:      es.Name,
+		label.ClusterNamespaceLabelName: es.Namespace,
+		label.PodNameLabelName:          pod.Name,
+	}
+}
+
+// csrName returns the name of the CertificateSigningRequest for the transport certificate of the given Pod. It
+// changes with the signer, the names and the key of the certificate, so that a request is never reused for a
+// different certificate.
+func csrName(es esv1.Elasticsearch, pod corev1.Pod, generalNames []certificates.GeneralName, publicKey []byte) string {
+	return fmt.Sprintf("%s-%s-transport-%s", pod.Namespace, pod.Name, hash.HashObject([]interface{}{
+		es.Spec.Transport.TLS.Issuer.SignerName,
+		generalNames,
+		publicKey,
+	}))
+}
+
+// ensureIssuedTransportCertificateForPod ensures that the transport certificates secret contains a certificate for
+// the given Pod issued by the external signer configured in the transport TLS options, trusted by the given roots.
+// New certificates are requested through a CertificateSigningRequest, which is deleted once the certificate is
+// stored. It returns false if the certificate of the Pod is waiting to be issued.
+func ensureIssuedTransportCertificateForPod(
+	ctx context.Context,
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	secret *corev1.Secret,
+	pod corev1.Pod,
+	ca *certificates.CA,
+	roots *x509.CertPool,
+	rotationParams certificates.RotationParams,
+) (bool, error) {
+	log := ulog.FromContext(ctx)
+	privateKey, err := ensurePodPrivateKey(ctx, secret, pod, ca)
+	if err != nil {
+		return false, err
+	}
+	generalNames, err := buildGeneralNames(es, pod)
+	if err != nil {
+		return false, err
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return false, err
+	}
+	name := csrName(es, pod, generalNames, publicKey)
+
+	if !shouldIssueNewCertificate(ctx, es, *secret, pod, privateKey, roots, rotationParams.RotateBefore) {
+		return true, deleteCSRs(ctx, c, es, pod, "")
+	}
+	// delete the requests for a previous key or different names that may still be pending
+	if err := deleteCSRs(ctx, c, es, pod, name); err != nil {
+		return false, err
+	}
+
+	var csr certificatesv1.CertificateSigningRequest
+	err = c.Get(ctx, types.NamespacedName{Name: name}, &csr)
+	if apierrors.IsNotFound(err) {
+		log.Info("Requesting new certificate", "namespace", pod.Namespace, "pod_name", pod.Name,
+			"csr_name", name, "signer_name", es.Spec.Transport.TLS.Issuer.SignerName)
+		return false, createCSR(ctx, c, es, pod, name, privateKey, generalNames, rotationParams.Validity)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, condition := range csr.Status.Conditions {
+		if (condition.Type == certificatesv1.CertificateDenied || condition.Type == certificatesv1.CertificateFailed) &&
+			condition.Status == corev1.ConditionTrue {
+			return false, fmt.Errorf("certificate signing request %s for pod %s/%s is %s: %s",
+				name, pod.Namespace, pod.Name, condition.Type, condition.Message)
+		}
+	}
+	if len(csr.Status.Certificate) == 0 {
+		log.V(1).Info("Waiting for certificate to be issued", "namespace", pod.Namespace, "pod_name", pod.Name, "csr_name", name)
+		return false, nil
+	}
+
+	certs, err := certificates.ParsePEMCerts(csr.Status.Certificate)
+	if err != nil || len(certs) == 0 {
+		return false, fmt.Errorf("certificate signing request %s contains an invalid certificate: %w", name, err)
+	}
+	if !certificates.PrivateMatchesPublicKey(ctx, certs[0].PublicKey, privateKey) {
+		return false, fmt.Errorf("certificate signing request %s contains a certificate for a different key", name)
+	}
+	log.Info("Storing issued certificate", "namespace", pod.Namespace, "pod_name", pod.Name, "csr_name", name)
+	secret.Data[PodCertFileName(pod.Name)] = csr.Status.Certificate
+	return true, deleteCSR(ctx, c, &csr)
+}
+
+// createCSR creates a CertificateSigningRequest for a transport certificate with the given names and key.
+func createCSR(
+	ctx context.Context,
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	pod corev1.Pod,
+	name string,
+	privateKey crypto.Signer,
+	generalNames []certificates.GeneralName,
+	validity time.Duration,
+) error {
+	generalNamesBytes, err := certificates.MarshalToSubjectAlternativeNamesData(generalNames)
+	if err != nil {
+		return err
+	}
+	request, err := x509.CreateCertificateRequest(cryptorand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:         buildCertificateCommonName(pod, es),
+			OrganizationalUnit: []string{es.Name},
+		},
+		ExtraExtensions: []pkix.Extension{
+			{Id: certificates.SubjectAlternativeNamesObjectIdentifier, Value: generalNamesBytes},
+		},
+	}, privateKey)
+	if err != nil {
+		return err
+	}
+	expirationSeconds := int32(validity.Seconds())
+	csr := certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: csrLabels(es, pod),
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:           pem.EncodeToMemory(&pem.Block{Type: csrPEMBlockType, Bytes: request}),
+			SignerName:        es.Spec.Transport.TLS.Issuer.SignerName,
+			ExpirationSeconds: &expirationSeconds,
+			Usages: []certificatesv1.KeyUsage{
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageKeyEncipherment,
+				certificatesv1.UsageServerAuth,
+				certificatesv1.UsageClientAuth,
+			},
+		},
+	}
+	if err := c.Create(ctx, &csr); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteCSR deletes the given CertificateSigningRequest. Requests left behind, for example when the cluster is deleted,
+// are garbage collected by Kubernetes.
+func deleteCSR(ctx context.Context, c k8s.Client, csr *certificatesv1.CertificateSigningRequest) error {
+	if err := c.Delete(ctx, csr); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteCSRs deletes the CertificateSigningRequests of the given Pod, except the one with the given name.
+func deleteCSRs(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, pod corev1.Pod, except string) error {
+	var csrs certificatesv1.CertificateSigningRequestList
+	if err := c.List(ctx, &csrs, client.MatchingLabels(csrLabels(es, pod))); err != nil {
+		return err
+	}
+	for i := range csrs.Items {
+		if csrs.Items[i].Name == except {
+			continue
+		}
+		if err := deleteCSR(ctx, c, &csrs.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issuerRoots returns a pool containing the given trusted CAs, which must include the CA of the external signer of
+// the transport certificates.
+func issuerRoots(additionalCAs []byte) (*x509.CertPool, error) {
+	certs, err := certificates.ParsePEMCerts(additionalCAs)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	for _, c := range certs {
+		pool.AddCert(c)
+	}
+	return pool, nil
+}
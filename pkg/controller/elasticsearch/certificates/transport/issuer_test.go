@@ -0,0 +1,154 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package transport
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const testSignerName = "clusterissuers.cert-manager.io/corporate-ca"
+
+// signCSR issues the certificate requested by the given CertificateSigningRequest with the given CA, as the external
+// signer would do.
+func signCSR(t *testing.T, c k8s.Client, name string, ca *certificates.CA) {
+	t.Helper()
+	var csr certificatesv1.CertificateSigningRequest
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: name}, &csr))
+	block, _ := pem.Decode(csr.Spec.Request)
+	require.NotNil(t, block)
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	require.NoError(t, err)
+	template, err := createValidatedCertificateTemplate(testPod, issuerES(), request, certificates.DefaultCertValidity)
+	require.NoError(t, err)
+	cert, err := ca.CreateCertificate(*template)
+	require.NoError(t, err)
+	csr.Status.Certificate = certificates.EncodePEMCert(cert)
+	require.NoError(t, c.Status().Update(context.Background(), &csr))
+}
+
+func issuerES() esv1.Elasticsearch {
+	es := testES.DeepCopy()
+	es.Spec.Transport.TLS.Issuer = &esv1.TransportCertificatesIssuer{SignerName: testSignerName}
+	return *es
+}
+
+func Test_ensureIssuedTransportCertificateForPod(t *testing.T) {
+	corporateCA, err := certificates.NewSelfSignedCA(certificates.CABuilderOptions{
+		Subject:    pkix.Name{CommonName: "corporate-ca"},
+		PrivateKey: testRSAPrivateKey,
+	})
+	require.NoError(t, err)
+	roots, err := issuerRoots(certificates.EncodePEMCert(corporateCA.Cert.Raw))
+	require.NoError(t, err)
+	rotationParams := certificates.RotationParams{
+		Validity:     certificates.DefaultCertValidity,
+		RotateBefore: certificates.DefaultRotateBefore,
+	}
+	es := issuerES()
+	ctx := context.Background()
+
+	t.Run("requests and stores a certificate issued by the signer", func(t *testing.T) {
+		c := k8s.NewFakeClient()
+		// the Pod still has a certificate issued by the operator CA
+		secret := &corev1.Secret{Data: map[string][]byte{
+			PodKeyFileName(testPod.Name):  testRSAPEMPrivateKey,
+			PodCertFileName(testPod.Name): rsaCert,
+		}}
+
+		issued, err := ensureIssuedTransportCertificateForPod(ctx, c, es, secret, testPod, testRSACA, roots, rotationParams)
+		require.NoError(t, err)
+		require.False(t, issued)
+		var csrs certificatesv1.CertificateSigningRequestList
+		require.NoError(t, c.List(ctx, &csrs))
+		require.Len(t, csrs.Items, 1)
+		csr := csrs.Items[0]
+		assert.Equal(t, testSignerName, csr.Spec.SignerName)
+		assert.Equal(t, map[string]string{
+			label.ClusterNameLabelName:      testES.Name,
+			label.ClusterNamespaceLabelName: testES.Namespace,
+			label.PodNameLabelName:          testPod.Name,
+		}, csr.Labels)
+		// the current certificate is kept while the new one is pending
+		assert.Equal(t, rsaCert, secret.Data[PodCertFileName(testPod.Name)])
+
+		// nothing changes until the certificate is issued
+		issued, err = ensureIssuedTransportCertificateForPod(ctx, c, es, secret, testPod, testRSACA, roots, rotationParams)
+		require.NoError(t, err)
+		require.False(t, issued)
+
+		signCSR(t, c, csr.Name, corporateCA)
+		issued, err = ensureIssuedTransportCertificateForPod(ctx, c, es, secret, testPod, testRSACA, roots, rotationParams)
+		require.NoError(t, err)
+		require.True(t, issued)
+		cert := extractTransportCert(ctx, *secret, testPod, buildCertificateCommonName(testPod, es))
+		require.NotNil(t, cert)
+		assert.Equal(t, "corporate-ca", cert.Issuer.CommonName)
+		// the request is deleted once the certificate is stored
+		err = c.Get(ctx, types.NamespacedName{Name: csr.Name}, &certificatesv1.CertificateSigningRequest{})
+		assert.True(t, apierrors.IsNotFound(err))
+
+		// the certificate is valid, no new request is created
+		issued, err = ensureIssuedTransportCertificateForPod(ctx, c, es, secret, testPod, testRSACA, roots, rotationParams)
+		require.NoError(t, err)
+		require.True(t, issued)
+		require.NoError(t, c.List(ctx, &csrs))
+		assert.Empty(t, csrs.Items)
+	})
+
+	t.Run("reports denied requests", func(t *testing.T) {
+		c := k8s.NewFakeClient()
+		secret := &corev1.Secret{Data: map[string][]byte{PodKeyFileName(testPod.Name): testRSAPEMPrivateKey}}
+		_, err := ensureIssuedTransportCertificateForPod(ctx, c, es, secret, testPod, testRSACA, roots, rotationParams)
+		require.NoError(t, err)
+		var csrs certificatesv1.CertificateSigningRequestList
+		require.NoError(t, c.List(ctx, &csrs))
+		require.Len(t, csrs.Items, 1)
+		csr := csrs.Items[0]
+		csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{
+			{Type: certificatesv1.CertificateDenied, Status: corev1.ConditionTrue, Message: "not allowed"},
+		}
+		require.NoError(t, c.Status().Update(ctx, &csr))
+
+		issued, err := ensureIssuedTransportCertificateForPod(ctx, c, es, secret, testPod, testRSACA, roots, rotationParams)
+		require.ErrorContains(t, err, "Denied: not allowed")
+		require.False(t, issued)
+		assert.NotContains(t, secret.Data, PodCertFileName(testPod.Name))
+	})
+
+	t.Run("deletes requests for a previous key", func(t *testing.T) {
+		c := k8s.NewFakeClient()
+		secret := &corev1.Secret{Data: map[string][]byte{}}
+		_, err := ensureIssuedTransportCertificateForPod(ctx, c, es, secret, testPod, testRSACA, roots, rotationParams)
+		require.NoError(t, err)
+		var csrs certificatesv1.CertificateSigningRequestList
+		require.NoError(t, c.List(ctx, &csrs))
+		require.Len(t, csrs.Items, 1)
+		previous := csrs.Items[0].Name
+
+		// a new key is generated
+		secret.Data = map[string][]byte{}
+		_, err = ensureIssuedTransportCertificateForPod(ctx, c, es, secret, testPod, testRSACA, roots, rotationParams)
+		require.NoError(t, err)
+		require.NoError(t, c.List(ctx, &csrs))
+		require.Len(t, csrs.Items, 1)
+		assert.NotEqual(t, previous, csrs.Items[0].Name)
+	})
+}
@@ -42,25 +42,12 @@ func ensureTransportCertificatesSecretContentsForPod(
 	rotationParams certificates.RotationParams,
 ) error {
 	log := ulog.FromContext(ctx)
-	// verify that the secret contains a parsable and compatible private key
-	privateKey := certificates.GetCompatiblePrivateKey(ctx, ca.PrivateKey, secret, PodKeyFileName(pod.Name))
-
-	// if we need a new private key, generate it
-	if privateKey == nil {
-		generatedPrivateKey, err := certificates.NewPrivateKey(ca.PrivateKey)
-		if err != nil {
-			return err
-		}
-
-		privateKey = generatedPrivateKey
-		pemPrivateKey, err := certificates.EncodePEMPrivateKey(privateKey)
-		if err != nil {
-			return err
-		}
-		secret.Data[PodKeyFileName(pod.Name)] = pemPrivateKey
+	privateKey, err := ensurePodPrivateKey(ctx, secret, pod, ca)
+	if err != nil {
+		return err
 	}
 
-	if shouldIssueNewCertificate(ctx, es, *secret, pod, privateKey, ca, rotationParams.RotateBefore) {
+	if shouldIssueNewCertificate(ctx, es, *secret, pod, privateKey, caPool(ca), rotationParams.RotateBefore) {
 		log.Info(
 			"Issuing new certificate",
 			"pod_name", pod.Name,
@@ -96,12 +83,41 @@ func ensureTransportCertificatesSecretContentsForPod(
 	return nil
 }
 
+// ensurePodPrivateKey returns the private key of the given Pod stored in the transport certificates secret, after
+// generating it if the secret does not contain a parsable private key compatible with the CA.
+func ensurePodPrivateKey(ctx context.Context, secret *corev1.Secret, pod corev1.Pod, ca *certificates.CA) (crypto.Signer, error) {
+	// verify that the secret contains a parsable and compatible private key
+	privateKey := certificates.GetCompatiblePrivateKey(ctx, ca.PrivateKey, secret, PodKeyFileName(pod.Name))
+	if privateKey != nil {
+		return privateKey, nil
+	}
+
+	// if we need a new private key, generate it
+	privateKey, err := certificates.NewPrivateKey(ca.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	pemPrivateKey, err := certificates.EncodePEMPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	secret.Data[PodKeyFileName(pod.Name)] = pemPrivateKey
+	return privateKey, nil
+}
+
+// caPool returns a pool containing the certificate of the given CA.
+func caPool(ca *certificates.CA) *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Cert)
+	return pool
+}
+
 // shouldIssueNewCertificate returns true if we should issue a new certificate.
 //
 // Reasons for reissuing a certificate:
 // - no certificate yet
 // - certificate has the wrong format
-// - certificate is invalid or expired
+// - certificate is invalid or expired, or not issued by one of the given roots
 // - certificate has no SAN extra extension
 // - certificate SAN and IP does not match pod SAN and IP
 func shouldIssueNewCertificate(
@@ -110,7 +126,7 @@ func shouldIssueNewCertificate(
 	secret corev1.Secret,
 	pod corev1.Pod,
 	privateKey crypto.Signer,
-	ca *certificates.CA,
+	roots *x509.CertPool,
 	certReconcileBefore time.Duration,
 ) bool {
 	log := ulog.FromContext(ctx)
@@ -134,18 +150,15 @@ func shouldIssueNewCertificate(
 			"namespace", pod.Namespace,
 			"subject", cert.Subject,
 			"issuer", cert.Issuer,
-			"current_ca_subject", ca.Cert.Subject,
 			"pod_name", pod.Name,
 		)
 		return true
 	}
 
-	pool := x509.NewCertPool()
-	pool.AddCert(ca.Cert)
 	verifyOpts := x509.VerifyOptions{
 		DNSName:       certCommonName,
-		Roots:         pool,
-		Intermediates: pool,
+		Roots:         roots,
+		Intermediates: intermediatesPool(secret, pod, roots),
 	}
 	if _, err := cert.Verify(verifyOpts); err != nil {
 		log.Info(
@@ -153,7 +166,6 @@ func shouldIssueNewCertificate(
 			"namespace", pod.Namespace,
 			"subject", cert.Subject,
 			"issuer", cert.Issuer,
-			"current_ca_subject", ca.Cert.Subject,
 			"pod", pod.Name,
 		)
 		return true
@@ -194,6 +206,20 @@ func shouldIssueNewCertificate(
 	return false
 }
 
+// intermediatesPool returns a pool containing the given roots and the certificates of the chain stored along with the
+// certificate of the Pod, which may be issued by an intermediate CA.
+func intermediatesPool(secret corev1.Secret, pod corev1.Pod, roots *x509.CertPool) *x509.CertPool {
+	pool := roots.Clone()
+	certs, err := certificates.ParsePEMCerts(secret.Data[PodCertFileName(pod.Name)])
+	if err != nil {
+		return pool
+	}
+	for _, c := range certs {
+		pool.AddCert(c)
+	}
+	return pool
+}
+
 // extractTransportCert extracts the transport certificate for the pod with the commonName from the Secret
 func extractTransportCert(ctx context.Context, secret corev1.Secret, pod corev1.Pod, commonName string) *x509.Certificate {
 	log := ulog.FromContext(ctx)
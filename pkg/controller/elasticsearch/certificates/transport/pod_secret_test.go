@@ -98,7 +98,7 @@ func Test_shouldIssueNewCertificate(t *testing.T) {
 				tt.args.secret,
 				*tt.args.pod,
 				testRSAPrivateKey,
-				testRSACA,
+				caPool(testRSACA),
 				tt.args.rotateBefore,
 			); got != tt.want {
 				t.Errorf("shouldIssueNewCertificate() = %v, want %v", got, tt.want)
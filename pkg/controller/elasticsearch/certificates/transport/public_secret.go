@@ -34,7 +34,7 @@ func ReconcileTransportCertsPublicSecret(
 	expected := corev1.Secret{
 		ObjectMeta: meta,
 		Data: map[string][]byte{
-			certificates.CAFileName: bytes.Join([][]byte{certificates.EncodePEMCert(ca.Cert.Raw), additionalCAs}, nil),
+			certificates.CAFileName: bytes.Join([][]byte{ca.TrustedCertsPEM(), additionalCAs}, nil),
 		},
 	}
 
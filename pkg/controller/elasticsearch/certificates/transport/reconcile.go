@@ -7,12 +7,16 @@ package transport
 import (
 	"bytes"
 	"context"
-	"reflect"
+	"crypto/x509"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -49,18 +53,36 @@ func ReconcileTransportCertificatesSecrets(
 		return results.WithError(err)
 	}
 	ssets := actualStatefulSets.Names()
-	for _, nodeSet := range es.Spec.NodeSets {
-		ssets.Add(esv1.StatefulSet(es.Name, nodeSet.Name))
+	for _, nodeSet := range es.Spec.ExpandedNodeSets() {
+		ssets.Add(es.StatefulSetName(nodeSet.Name))
 	}
 
+	// the Secrets of the different StatefulSets are independent, reconcile them in parallel
+	nodeSetResults := make([]*reconciler.Results, 0, len(ssets))
+	var lock sync.Mutex
+	group := errgroup.Group{}
+	group.SetLimit(sset.MaxConcurrentReconciles)
 	for ssetName := range ssets {
-		results.WithResults(reconcileNodeSetTransportCertificatesSecrets(ctx, c, ca, additionalCAs, es, ssetName, rotationParams))
+		group.Go(func() error {
+			res := reconcileNodeSetTransportCertificatesSecrets(ctx, c, ca, additionalCAs, es, ssetName, rotationParams)
+			lock.Lock()
+			defer lock.Unlock()
+			nodeSetResults = append(nodeSetResults, res)
+			return nil
+		})
+	}
+	_ = group.Wait() // errors are reported through the results
+	for _, res := range nodeSetResults {
+		results.WithResults(res)
 	}
 	return results
 }
 
-// DeleteStatefulSetTransportCertificate removes the Secret which contains the transport certificates of a given Statefulset.
+// DeleteStatefulSetTransportCertificate removes the Secrets which contain the transport certificates of a given Statefulset.
 func DeleteStatefulSetTransportCertificate(ctx context.Context, client k8s.Client, namespace string, ssetName string) error {
+	if err := deleteTransportCertificatesShards(ctx, client, namespace, ssetName, 1); err != nil {
+		return err
+	}
 	secret := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: namespace,
@@ -70,6 +92,25 @@ func DeleteStatefulSetTransportCertificate(ctx context.Context, client k8s.Clien
 	return client.Delete(ctx, &secret)
 }
 
+// deleteTransportCertificatesShards removes the Secrets which contain the transport certificates of a given StatefulSet,
+// starting at the given shard. Shards are always created from the first one, the deletion stops at the first missing one.
+func deleteTransportCertificatesShards(ctx context.Context, c k8s.Client, namespace string, ssetName string, fromShard int32) error {
+	for shard := fromShard; ; shard++ {
+		nsn := types.NamespacedName{Namespace: namespace, Name: esv1.StatefulSetTransportCertificatesSecretShard(ssetName, shard)}
+		var secret corev1.Secret
+		if err := c.Get(ctx, nsn, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		ulog.FromContext(ctx).Info("Deleting unused transport certificates secret", "namespace", namespace, "secret_name", nsn.Name)
+		if err := c.Delete(ctx, &secret); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+}
+
 // DeleteLegacyTransportCertificate ensures that the former Secret which used to contain the transport certificates is deleted.
 func DeleteLegacyTransportCertificate(ctx context.Context, client k8s.Client, es esv1.Elasticsearch) error {
 	nsn := types.NamespacedName{Namespace: es.Namespace, Name: esv1.LegacyTransportCertsSecretSuffix(es.Name)}
@@ -78,8 +119,9 @@ func DeleteLegacyTransportCertificate(ctx context.Context, client k8s.Client, es
 
 const disabledMarker = "transport.certs.disabled"
 
-// reconcileNodeSetTransportCertificatesSecrets reconciles the secret which contains the transport certificates for
-// a given StatefulSet.
+// reconcileNodeSetTransportCertificatesSecrets reconciles the secrets which contain the transport certificates for
+// a given StatefulSet. Certificates are spread across as many secrets as configured in the transport TLS options, each
+// Pod being assigned to a secret based on its ordinal. The CA file is only stored in the first one.
 func reconcileNodeSetTransportCertificatesSecrets(
 	ctx context.Context,
 	c k8s.Client,
@@ -90,7 +132,6 @@ func reconcileNodeSetTransportCertificatesSecrets(
 	rotationParams certificates.RotationParams,
 ) *reconciler.Results {
 	results := &reconciler.Results{}
-	log := ulog.FromContext(ctx)
 	// List all the existing Pods in the nodeSet
 	var pods corev1.PodList
 	matchLabels := label.NewLabelSelectorForStatefulSetName(es.Name, ssetName)
@@ -99,13 +140,111 @@ func reconcileNodeSetTransportCertificatesSecrets(
 		return results.WithError(errors.WithStack(err))
 	}
 
-	secret, err := ensureTransportCertificatesSecretExists(ctx, c, es, ssetName)
-	if err != nil {
+	shards := es.Spec.Transport.TLS.CertificatesSecretShardsCount()
+	podsByShard := make([][]corev1.Pod, shards)
+	for _, pod := range pods.Items {
+		shard := podShard(pod, shards)
+		podsByShard[shard] = append(podsByShard[shard], pod)
+	}
+
+	// reconcile the first shard last, to know whether Pods still use certificates stored in the other ones
+	var updatedKeys []string
+	podCertsInOtherShards := false
+	for shard := shards - 1; shard >= 0; shard-- {
+		secret, keys, err := reconcileTransportCertificatesSecretShard(
+			ctx, c, ca, additionalCAs, es, ssetName, shard, podsByShard[shard], podCertsInOtherShards, rotationParams, results,
+		)
+		if err != nil {
+			return results.WithError(err)
+		}
+		updatedKeys = append(updatedKeys, keys...)
+		podCertsInOtherShards = podCertsInOtherShards || containsPodCerts(secret)
+	}
+	if err := deleteTransportCertificatesShards(ctx, c, es.Namespace, ssetName, shards); err != nil {
 		return results.WithError(err)
 	}
+
+	// speed up the propagation of the updated certificates to the Pods that use them
+	for _, pod := range podsToMarkAsUpdated(pods.Items, updatedKeys) {
+		annotation.MarkPodAsUpdated(ctx, c, pod)
+	}
+	return results
+}
+
+// podShard returns the shard of the transport certificates secrets in which the certificate of the given Pod is stored.
+func podShard(pod corev1.Pod, shards int32) int32 {
+	if shards <= 1 {
+		return 0
+	}
+	_, ordinal, err := sset.StatefulSetName(pod.Name)
+	if err != nil {
+		return 0
+	}
+	return ordinal % shards
+}
+
+// containsPodCerts returns true if the given transport certificates secret contains certificates or keys of Pods.
+func containsPodCerts(secret *corev1.Secret) bool {
+	for key := range secret.Data {
+		if key != certificates.CAFileName && key != disabledMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// podsToMarkAsUpdated returns the Pods affected by the update of the given keys of the transport certificates secrets:
+// all of them if the CA file or the disabled marker were updated, only the ones whose certificate was updated otherwise.
+func podsToMarkAsUpdated(pods []corev1.Pod, updatedKeys []string) []corev1.Pod {
+	if len(updatedKeys) == 0 {
+		return nil
+	}
+	updatedPods := make(map[string]struct{}, len(updatedKeys))
+	for _, key := range updatedKeys {
+		if key == certificates.CAFileName || key == disabledMarker {
+			return pods
+		}
+		updatedPods[strings.SplitN(key, ".", 2)[0]] = struct{}{}
+	}
+	var result []corev1.Pod
+	for _, pod := range pods {
+		if _, updated := updatedPods[pod.Name]; updated {
+			result = append(result, pod)
+		}
+	}
+	return result
+}
+
+// reconcileTransportCertificatesSecretShard reconciles the given shard of the secrets which contain the transport
+// certificates of the given Pods. Only the updated keys are sent to the API server, they are returned along with the
+// reconciled secret.
+func reconcileTransportCertificatesSecretShard(
+	ctx context.Context,
+	c k8s.Client,
+	ca *certificates.CA,
+	additionalCAs []byte,
+	es esv1.Elasticsearch,
+	ssetName string,
+	shard int32,
+	pods []corev1.Pod,
+	podCertsInOtherShards bool,
+	rotationParams certificates.RotationParams,
+	results *reconciler.Results,
+) (*corev1.Secret, []string, error) {
+	log := ulog.FromContext(ctx)
+	secret, err := ensureTransportCertificatesSecretExists(ctx, c, es, ssetName, shard)
+	if err != nil {
+		return nil, nil, err
+	}
 	// defensive copy of the current secret so we can check whether we need to update later on
 	currentTransportCertificatesSecret := secret.DeepCopy()
-	for _, pod := range pods.Items {
+	var issuerRootsPool *x509.CertPool
+	if es.Spec.Transport.TLS.IssuerEnabled() {
+		if issuerRootsPool, err = issuerRoots(additionalCAs); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, pod := range pods {
 		if pod.Status.PodIP == "" {
 			log.Info("Skipping pod because it has no IP yet", "namespace", pod.Namespace, "pod_name", pod.Name)
 			continue
@@ -117,15 +256,27 @@ func reconcileNodeSetTransportCertificatesSecrets(
 			continue
 		}
 
-		if err := ensureTransportCertificatesSecretContentsForPod(
+		if es.Spec.Transport.TLS.IssuerEnabled() {
+			issued, err := ensureIssuedTransportCertificateForPod(ctx, c, es, secret, pod, ca, issuerRootsPool, rotationParams)
+			if err != nil {
+				// do not prevent the other Pods from getting their certificates
+				results.WithError(err)
+				continue
+			}
+			if !issued {
+				// the Pod keeps its current certificate, if any, until the new one is issued
+				results.WithReconciliationState(reconciler.RequeueAfter(pendingCSRRequeue).WithReason("Waiting for transport certificates to be issued"))
+				continue
+			}
+		} else if err := ensureTransportCertificatesSecretContentsForPod(
 			ctx, es, secret, pod, ca, rotationParams,
 		); err != nil {
-			return results.WithError(err)
+			return nil, nil, err
 		}
 		certCommonName := buildCertificateCommonName(pod, es)
 		cert := extractTransportCert(ctx, *secret, pod, certCommonName)
 		if cert == nil {
-			return results.WithError(errors.New("no certificate found for pod"))
+			return nil, nil, errors.New("no certificate found for pod")
 		}
 		// handle cert expiry via requeue
 		results.WithReconciliationState(
@@ -135,12 +286,12 @@ func reconcileNodeSetTransportCertificatesSecrets(
 		)
 	}
 
-	// remove certificates and keys for deleted pods
-	podsByName := k8s.PodsByName(pods.Items)
+	// remove certificates and keys for deleted pods, or pods now stored in another shard
+	podsByName := k8s.PodsByName(pods)
 	keysToPrune := make([]string, 0)
 	for secretDataKey := range secret.Data {
-		if secretDataKey == certificates.CAFileName {
-			// never remove the CA file
+		if secretDataKey == certificates.CAFileName || secretDataKey == disabledMarker {
+			// the CA file and the marker are handled below
 			continue
 		}
 
@@ -160,38 +311,60 @@ func reconcileNodeSetTransportCertificatesSecrets(
 		}
 	}
 
-	if es.Spec.Transport.TLS.SelfSignedEnabled() {
+	if shard > 0 {
+		// the CA file and the marker are only stored in the first shard, so that all shards can be projected
+		// into the same volume
 		delete(secret.Data, disabledMarker)
+		delete(secret.Data, certificates.CAFileName)
 	} else {
-		// add a marker but leave all the old certs that might exist in the secret in place to ease the transition
-		// to the disabled state.
-		secret.Data[disabledMarker] = []byte("true") // contents is irrelevant
+		if es.Spec.Transport.TLS.SelfSignedEnabled() {
+			delete(secret.Data, disabledMarker)
+		} else {
+			// add a marker but leave all the old certs that might exist in the secret in place to ease the transition
+			// to the disabled state.
+			secret.Data[disabledMarker] = []byte("true") // contents is irrelevant
+		}
+		mayBeUpdateCAFile(secret, ca, additionalCAs, podCertsInOtherShards)
 	}
 
-	mayBeUpdateCAFile(secret, ca, additionalCAs)
+	updatedKeys := updatedDataKeys(currentTransportCertificatesSecret.Data, secret.Data)
+	if len(updatedKeys) == 0 {
+		return secret, nil, nil
+	}
+	// only send the updated keys instead of rewriting the whole secret, which may contain the certificates of many Pods
+	if err := c.Patch(ctx, secret, client.MergeFromWithOptions(currentTransportCertificatesSecret, client.MergeFromWithOptimisticLock{})); err != nil {
+		return nil, nil, err
+	}
+	return secret, updatedKeys, nil
+}
 
-	if !reflect.DeepEqual(secret, currentTransportCertificatesSecret) {
-		if err := c.Update(ctx, secret); err != nil {
-			return results.WithError(err)
+// updatedDataKeys returns the keys whose value differs between the current and the expected secret data.
+func updatedDataKeys(current, expected map[string][]byte) []string {
+	var keys []string
+	for key, value := range expected {
+		if currentValue, exists := current[key]; !exists || !bytes.Equal(currentValue, value) {
+			keys = append(keys, key)
 		}
-		for _, pod := range pods.Items {
-			annotation.MarkPodAsUpdated(ctx, c, pod)
+	}
+	for key := range current {
+		if _, exists := expected[key]; !exists {
+			keys = append(keys, key)
 		}
 	}
-
-	return results
+	slices.Sort(keys)
+	return keys
 }
 
-func mayBeUpdateCAFile(secret *corev1.Secret, ca *certificates.CA, additionalCAs []byte) {
+func mayBeUpdateCAFile(secret *corev1.Secret, ca *certificates.CA, additionalCAs []byte, podCertsInOtherShards bool) {
 	var cas [][]byte
 
 	// if the secret contains only the marker file (and maybe an old CA) transport certs are disabled
 	// and no pod uses them anymore => we don't need the CA
 	_, transportCertsDisabled := secret.Data[disabledMarker]
-	secretContainsMarkerAndCAFile := len(secret.Data) <= 2 && transportCertsDisabled
+	secretContainsMarkerAndCAFile := len(secret.Data) <= 2 && transportCertsDisabled && !podCertsInOtherShards
 
 	if !secretContainsMarkerAndCAFile {
-		cas = append(cas, certificates.EncodePEMCert(ca.Cert.Raw))
+		cas = append(cas, ca.TrustedCertsPEM())
 	}
 
 	cas = append(cas, additionalCAs)
@@ -216,11 +389,12 @@ func ensureTransportCertificatesSecretExists(
 	c k8s.Client,
 	es esv1.Elasticsearch,
 	ssetName string,
+	shard int32,
 ) (*corev1.Secret, error) {
 	expected := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: es.Namespace,
-			Name:      esv1.StatefulSetTransportCertificatesSecret(ssetName),
+			Name:      esv1.StatefulSetTransportCertificatesSecretShard(ssetName, shard),
 			Labels: map[string]string{
 				// a label showing which es these certificates belongs to
 				label.ClusterNameLabelName: es.Name,
@@ -227,6 +227,73 @@ func TestReconcileTransportCertificatesSecrets(t *testing.T) {
 				assert.NotContains(t, transportCerts1.Data, "test-es-name-es-sset1-1.tls.key")
 			},
 		},
+		{
+			name: "Transport certs are spread across several Secrets",
+			args: args{
+				ca: testRSACA,
+				es: newEsBuilder().addNodeSet("sset1", 5).withTransportCertsSecretShards(2).build(),
+				initialObjects: []client.Object{
+					newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).withIP("1.1.1.2").build(),
+					newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(1).withIP("1.1.1.3").build(),
+					newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(2).withIP("1.1.1.4").build(),
+					newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(3).withIP("1.1.1.5").build(),
+					newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(4).withIP("1.1.1.6").build(),
+				},
+			},
+			wantRequeue: true,
+			wantErr:     false,
+			assertSecrets: func(t *testing.T, secrets corev1.SecretList) {
+				t.Helper()
+				assert.Equal(t, 2, len(secrets.Items))
+
+				// Pods with an even ordinal, and the CA, are in the first Secret
+				shard0 := getSecret(secrets, "test-es-name-es-sset1-es-transport-certs")
+				require.NotNil(t, shard0)
+				assert.Equal(t, 7, len(shard0.Data))
+				assert.Equal(t, testRSACABytes, shard0.Data["ca.crt"])
+				for _, pod := range []string{"test-es-name-es-sset1-0", "test-es-name-es-sset1-2", "test-es-name-es-sset1-4"} {
+					assert.Contains(t, shard0.Data, PodCertFileName(pod))
+					assert.Contains(t, shard0.Data, PodKeyFileName(pod))
+				}
+
+				// Pods with an odd ordinal are in the second one, which does not contain the CA
+				shard1 := getSecret(secrets, "test-es-name-es-sset1-es-transport-certs-1")
+				require.NotNil(t, shard1)
+				assert.Equal(t, 4, len(shard1.Data))
+				for _, pod := range []string{"test-es-name-es-sset1-1", "test-es-name-es-sset1-3"} {
+					assert.Contains(t, shard1.Data, PodCertFileName(pod))
+					assert.Contains(t, shard1.Data, PodKeyFileName(pod))
+				}
+				assert.Equal(t, testEsName, shard1.Labels["elasticsearch.k8s.elastic.co/cluster-name"])
+				assert.Equal(t, "test-es-name-es-sset1", shard1.Labels["elasticsearch.k8s.elastic.co/statefulset-name"])
+			},
+		},
+		{
+			name: "Transport certs are moved back to a single Secret when the number of shards is decreased",
+			args: args{
+				ca: testRSACA,
+				es: newEsBuilder().addNodeSet("sset1", 3).build(),
+				initialObjects: []client.Object{
+					newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).withIP("1.1.1.2").build(),
+					newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(1).withIP("1.1.1.3").build(),
+					newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(2).withIP("1.1.1.4").build(),
+					newtransportCertsSecretBuilder(testEsName, "sset1").forPodIndices(0).build(),
+					newtransportCertsSecretBuilder(testEsName, "sset1").inShard(1).forPodIndices(1).build(),
+					newtransportCertsSecretBuilder(testEsName, "sset1").inShard(2).forPodIndices(2).build(),
+				},
+			},
+			wantRequeue: true,
+			wantErr:     false,
+			assertSecrets: func(t *testing.T, secrets corev1.SecretList) {
+				t.Helper()
+				// unused shards have been deleted
+				assert.Equal(t, 1, len(secrets.Items))
+				shard0 := getSecret(secrets, "test-es-name-es-sset1-es-transport-certs")
+				require.NotNil(t, shard0)
+				// 7 items are expected in the Secret: the CA + 3 * (crt and private keys)
+				assert.Equal(t, 7, len(shard0.Data))
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -272,6 +339,22 @@ func TestDeleteStatefulSetTransportCertificate(t *testing.T) {
 				assert.Nil(t, err)
 			},
 		},
+		{
+			name: "StatefulSet transport Secrets spread across several shards exist",
+			args: args{
+				client: k8s.NewFakeClient(
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-es-name-es-sset1-es-transport-certs", Namespace: testNamespace}},
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-es-name-es-sset1-es-transport-certs-1", Namespace: testNamespace}},
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-es-name-es-sset1-es-transport-certs-2", Namespace: testNamespace}},
+				),
+				es:       testES,
+				ssetName: esv1.StatefulSet(testEsName, "sset1"),
+			},
+			assertErr: func(t *testing.T, err error) {
+				t.Helper()
+				assert.Nil(t, err)
+			},
+		},
 		{
 			name: "StatefulSet transport Secret does not exist",
 			args: args{
@@ -290,6 +373,9 @@ func TestDeleteStatefulSetTransportCertificate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			err := DeleteStatefulSetTransportCertificate(context.Background(), tt.args.client, tt.args.es.Namespace, tt.args.ssetName)
 			tt.assertErr(t, err)
+			var secrets corev1.SecretList
+			require.NoError(t, tt.args.client.List(context.Background(), &secrets))
+			require.Empty(t, secrets.Items)
 		})
 	}
 }
@@ -455,7 +541,7 @@ func Test_ensureTransportCertificateSecretExists(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ensureTransportCertificatesSecretExists(context.Background(), tt.args.c, tt.args.owner, esv1.StatefulSet(testES.Name, "sset1"))
+			got, err := ensureTransportCertificatesSecretExists(context.Background(), tt.args.c, tt.args.owner, esv1.StatefulSet(testES.Name, "sset1"), 0)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("EnsureTransportCertificateSecretExists() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -551,8 +637,58 @@ func Test_mayBeUpdateCAFile(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mayBeUpdateCAFile(tt.args.secret, tt.args.ca, tt.args.additionalCAs)
+			mayBeUpdateCAFile(tt.args.secret, tt.args.ca, tt.args.additionalCAs, false)
 			tt.assertSecrets(t, tt.args.secret.Data)
 		})
 	}
 }
+
+func Test_podsToMarkAsUpdated(t *testing.T) {
+	pods := []corev1.Pod{
+		*newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).build(),
+		*newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(1).build(),
+		*newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(2).build(),
+	}
+	tests := []struct {
+		name        string
+		updatedKeys []string
+		want        []string
+	}{
+		{
+			name:        "no update",
+			updatedKeys: nil,
+			want:        nil,
+		},
+		{
+			name:        "certificate of a single Pod updated",
+			updatedKeys: []string{"test-es-name-es-sset1-1.tls.crt", "test-es-name-es-sset1-1.tls.key"},
+			want:        []string{"test-es-name-es-sset1-1"},
+		},
+		{
+			name:        "CA updated",
+			updatedKeys: []string{"test-es-name-es-sset1-1.tls.crt", certificates.CAFileName},
+			want:        []string{"test-es-name-es-sset1-0", "test-es-name-es-sset1-1", "test-es-name-es-sset1-2"},
+		},
+		{
+			name:        "disabled marker updated",
+			updatedKeys: []string{disabledMarker},
+			want:        []string{"test-es-name-es-sset1-0", "test-es-name-es-sset1-1", "test-es-name-es-sset1-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, pod := range podsToMarkAsUpdated(pods, tt.updatedKeys) {
+				got = append(got, pod.Name)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_updatedDataKeys(t *testing.T) {
+	current := map[string][]byte{"unchanged": []byte("a"), "updated": []byte("b"), "removed": []byte("c")}
+	expected := map[string][]byte{"unchanged": []byte("a"), "updated": []byte("B"), "added": []byte("d")}
+	require.Equal(t, []string{"added", "removed", "updated"}, updatedDataKeys(current, expected))
+	require.Empty(t, updatedDataKeys(current, current))
+}
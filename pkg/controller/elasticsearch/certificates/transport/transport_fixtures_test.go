@@ -144,8 +144,9 @@ func init() {
 // -- Elasticsearch builder
 
 type esBuilder struct {
-	nodeSets               []esv1.NodeSet
-	transportCertsDisabled bool
+	nodeSets                   []esv1.NodeSet
+	transportCertsDisabled     bool
+	transportCertsSecretShards *int32
 }
 
 func newEsBuilder() *esBuilder {
@@ -165,10 +166,16 @@ func (eb *esBuilder) disableTransportCerts() *esBuilder {
 	return eb
 }
 
+func (eb *esBuilder) withTransportCertsSecretShards(shards int32) *esBuilder {
+	eb.transportCertsSecretShards = &shards
+	return eb
+}
+
 func (eb *esBuilder) build() *esv1.Elasticsearch {
 	es := testES.DeepCopy()
 	es.Spec.NodeSets = eb.nodeSets
 	es.Spec.Transport.TLS.SelfSignedCertificates = &esv1.SelfSignedTransportCertificates{Disabled: eb.transportCertsDisabled}
+	es.Spec.Transport.TLS.CertificatesSecretShards = eb.transportCertsSecretShards
 	return es
 }
 
@@ -176,6 +183,7 @@ func (eb *esBuilder) build() *esv1.Elasticsearch {
 
 type transportCertsSecretBuilder struct {
 	statefulset string
+	shard       int32
 	data        map[string][]byte
 }
 
@@ -199,11 +207,18 @@ func (tcb *transportCertsSecretBuilder) forPodIndices(indices ...int) *transport
 	return tcb
 }
 
+// inShard makes the Secret the given shard of the transport certs Secrets, which does not contain the CA
+func (tcb *transportCertsSecretBuilder) inShard(shard int32) *transportCertsSecretBuilder {
+	tcb.shard = shard
+	delete(tcb.data, certificates.CAFileName)
+	return tcb
+}
+
 func (tcb *transportCertsSecretBuilder) build() *corev1.Secret {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: testNamespace,
-			Name:      esv1.StatefulSetTransportCertificatesSecret(tcb.statefulset),
+			Name:      esv1.StatefulSetTransportCertificatesSecretShard(tcb.statefulset, tcb.shard),
 		},
 	}
 	secret.Data = tcb.data
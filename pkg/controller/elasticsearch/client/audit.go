@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	auditOutcomeSuccess = "success"
+	auditOutcomeFailure = "failure"
+)
+
+// auditLogger holds the optional logger used to record every Elasticsearch API call made by the operator.
+var auditLogger atomic.Pointer[logr.Logger]
+
+// SetAuditLogger enables the audit log of Elasticsearch API calls. Each request made by any Elasticsearch client
+// is recorded with the given logger, including the target cluster, the HTTP method and path, the user and the outcome.
+func SetAuditLogger(logger logr.Logger) {
+	auditLogger.Store(&logger)
+}
+
+// DisableAuditLog stops recording Elasticsearch API calls.
+func DisableAuditLog() {
+	auditLogger.Store(nil)
+}
+
+// audit records the given request and its outcome if the audit log is enabled.
+func (c *baseClient) audit(request *http.Request, response *http.Response, err error, duration time.Duration) {
+	logger := auditLogger.Load()
+	if logger == nil {
+		return
+	}
+
+	outcome := auditOutcomeSuccess
+	if err != nil {
+		outcome = auditOutcomeFailure
+	}
+	keysAndValues := []interface{}{
+		"event.outcome", outcome,
+		"event.duration", duration.Nanoseconds(),
+		"http.request.method", request.Method,
+		"url.path", request.URL.Path,
+		"user.name", c.User.Name,
+		"namespace", c.es.Namespace,
+		"es_name", c.es.Name,
+	}
+	if response != nil {
+		keysAndValues = append(keysAndValues, "http.response.status_code", response.StatusCode)
+	}
+	if err != nil {
+		keysAndValues = append(keysAndValues, "error.message", err.Error())
+	}
+	logger.Info("Elasticsearch API call", keysAndValues...)
+}
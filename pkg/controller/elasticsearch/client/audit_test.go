@@ -0,0 +1,60 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestAuditLog(t *testing.T) {
+	var entries []string
+	SetAuditLogger(funcr.New(func(_, args string) {
+		entries = append(entries, args)
+	}, funcr.Options{}))
+	defer DisableAuditLog()
+
+	statusCode := http.StatusOK
+	c := NewMockClientWithUser(version.MustParse("8.15.0"), BasicAuth{Name: "elastic-internal", Password: "secret"},
+		func(req *http.Request) *http.Response {
+			return NewMockResponse(statusCode, req, `{}`)
+		})
+	c.(*clientV8).es = clusterKey{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "es"}}
+
+	_, err := c.GetClusterHealth(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Contains(t, entries[0], `"event.outcome"="success"`)
+	assert.Contains(t, entries[0], `"http.request.method"="GET"`)
+	assert.Contains(t, entries[0], `"url.path"="/_cluster/health"`)
+	assert.Contains(t, entries[0], `"user.name"="elastic-internal"`)
+	assert.Contains(t, entries[0], `"namespace"="ns" "es_name"="es"`)
+	assert.Contains(t, entries[0], `"http.response.status_code"=200`)
+	assert.NotContains(t, entries[0], "secret")
+
+	statusCode = http.StatusForbidden
+	require.Error(t, c.EnableShardAllocation(context.Background()))
+	require.Len(t, entries, 2)
+	assert.Contains(t, entries[1], `"event.outcome"="failure"`)
+	assert.Contains(t, entries[1], `"http.request.method"="PUT"`)
+	assert.Contains(t, entries[1], `"url.path"="/_cluster/settings"`)
+	assert.Contains(t, entries[1], `"http.response.status_code"=403`)
+	assert.Contains(t, entries[1], `"error.message"=`)
+
+	// nothing is recorded once the audit log is disabled
+	DisableAuditLog()
+	statusCode = http.StatusOK
+	_, err = c.GetClusterHealth(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
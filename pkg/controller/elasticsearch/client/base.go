@@ -11,9 +11,9 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
-	"k8s.io/apimachinery/pkg/types"
 
 	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
@@ -25,16 +25,19 @@ type baseClient struct {
 	User        BasicAuth
 	HTTP        *http.Client
 	URLProvider URLProvider
-	es          types.NamespacedName
+	es          clusterKey
 	caCerts     []*x509.Certificate
 	version     version.Version
 	debug       bool
+	// sharedTransport is true if the transport of the http client is shared by all the clients of the cluster
+	sharedTransport bool
 }
 
-// Close idle connections in the underlying http client.
+// Close idle connections in the underlying http client, unless its transport is shared with the other clients of the
+// cluster, in which case they are closed by CloseIdleConnections.
 // Should be called once this client is not used anymore.
 func (c *baseClient) Close() {
-	if c.HTTP != nil {
+	if c.HTTP != nil && !c.sharedTransport {
 		// When the http transport goes out of scope, the underlying goroutines responsible
 		// for handling keep-alive connections are not closed automatically.
 		// Since this client gets recreated frequently we would effectively be leaking goroutines.
@@ -49,14 +52,9 @@ func (c *baseClient) equal(c2 *baseClient) bool {
 		return c == nil
 	}
 	// compare ca certs
-	if len(c.caCerts) != len(c2.caCerts) {
+	if !equalCerts(c.caCerts, c2.caCerts) {
 		return false
 	}
-	for i := range c.caCerts {
-		if !c.caCerts[i].Equal(c2.caCerts[i]) {
-			return false
-		}
-	}
 	// compare endpoint svc url and user creds. Service URL acts purely as an identifier here.
 	return c.URLProvider.Equals(c2.URLProvider) &&
 		c.User == c2.User
@@ -77,16 +75,27 @@ func (c *baseClient) doRequest(context context.Context, request *http.Request) (
 		"namespace", c.es.Namespace,
 		"es_name", c.es.Name,
 	)
+	start := time.Now()
+	if err := rateLimiters.wait(context, c.es); err != nil {
+		err = newDecoratedHTTPError(request, err)
+		c.instrument(request, nil, err, time.Since(start))
+		return nil, err
+	}
 	response, err := c.HTTP.Do(withContext)
 	if err != nil {
-		return response, newDecoratedHTTPError(request, err)
+		err = newDecoratedHTTPError(request, err)
+		c.instrument(request, response, err, time.Since(start))
+		return response, err
 	}
 
 	// Check HTTP code in Elasticsearch response.
 	if response.StatusCode < 200 || response.StatusCode >= 300 {
-		return response, newDecoratedHTTPError(request, newAPIError(context, response))
+		err = newDecoratedHTTPError(request, newAPIError(context, response))
+		c.instrument(request, response, err, time.Since(start))
+		return response, err
 	}
 
+	c.instrument(request, response, nil, time.Since(start))
 	return response, nil
 }
 
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"go.elastic.co/apm/module/apmelasticsearch/v2"
@@ -28,7 +29,25 @@ const (
 )
 
 // DefaultESClientTimeout is the default timeout value for Elasticsearch requests.
-var DefaultESClientTimeout = 3 * time.Minute
+const DefaultESClientTimeout = 3 * time.Minute
+
+// defaultTimeout is the timeout of Elasticsearch requests, DefaultESClientTimeout unless set with SetDefaultTimeout.
+var defaultTimeout atomic.Int64
+
+func init() {
+	defaultTimeout.Store(int64(DefaultESClientTimeout))
+}
+
+// SetDefaultTimeout sets the timeout of Elasticsearch requests for the clusters which do not specify one with the
+// ESClientTimeoutAnnotation. It can be updated while the operator is running.
+func SetDefaultTimeout(timeout time.Duration) {
+	defaultTimeout.Store(int64(timeout))
+}
+
+// DefaultTimeout returns the timeout of Elasticsearch requests for the clusters which do not specify one.
+func DefaultTimeout() time.Duration {
+	return time.Duration(defaultTimeout.Load())
+}
 
 // BasicAuth contains credentials for an Elasticsearch user.
 type BasicAuth struct {
@@ -37,9 +56,17 @@ type BasicAuth struct {
 }
 
 type IndexRole struct {
-	Names                  []string `json:"names,omitempty"`
-	Privileges             []string `json:",omitempty"`
-	AllowRestrictedIndices *bool    `json:"allow_restricted_indices,omitempty" yaml:"allow_restricted_indices,omitempty"`
+	Names                  []string       `json:"names,omitempty"`
+	Privileges             []string       `json:"privileges,omitempty"`
+	FieldSecurity          *FieldSecurity `json:"field_security,omitempty" yaml:"field_security,omitempty"`
+	Query                  string         `json:"query,omitempty" yaml:"query,omitempty"`
+	AllowRestrictedIndices *bool          `json:"allow_restricted_indices,omitempty" yaml:"allow_restricted_indices,omitempty"`
+}
+
+// FieldSecurity restricts the fields of the documents that can be read with an IndexRole.
+type FieldSecurity struct {
+	Grant  []string `json:"grant,omitempty"`
+	Except []string `json:"except,omitempty"`
 }
 
 type ApplicationRole struct {
@@ -53,6 +80,7 @@ type Role struct {
 	Cluster      []string          `json:"cluster,omitempty"`
 	Indices      []IndexRole       `json:"indices,omitempty"`
 	Applications []ApplicationRole `json:"applications,omitempty"`
+	RunAs        []string          `json:"run_as,omitempty" yaml:"run_as,omitempty"`
 	Metadata     map[string]any    `json:"metadata,omitempty"`
 }
 
@@ -64,6 +92,20 @@ type Client interface {
 	ShardLister
 	LicenseClient
 	SecurityClient
+	SnapshotClient
+	SnapshotRepositoryClient
+	SnapshotLifecyclePolicyClient
+	ILMPolicyClient
+	NativeUserClient
+	NativeRoleClient
+	RoleMappingClient
+	IndexTemplateClient
+	DataStreamClient
+	IngestPipelineClient
+	TransformClient
+	IndexClient
+	CrossClusterAPIKeyClient
+	DeprecationClient
 	// Close idle connections in the underlying http client.
 	Close()
 	// Equal returns true if other can be considered as the same client.
@@ -90,21 +132,34 @@ type Client interface {
 	GetClusterHealthWaitForAllEvents(ctx context.Context) (Health, error)
 	// GetClusterState calls the _cluster/state api.
 	GetClusterState(ctx context.Context) (ClusterState, error)
+	// GetClusterStateVersion calls the _cluster/state/version api to only retrieve the version of the cluster state.
+	GetClusterStateVersion(ctx context.Context) (ClusterStateVersion, error)
 	// SetMinimumMasterNodes sets the transient and persistent setting of the same name in cluster settings.
 	SetMinimumMasterNodes(ctx context.Context, n int) error
 	// ReloadSecureSettings will decrypt and re-read the entire keystore, on every cluster node,
-	// but only the reloadable secure settings will be applied
-	ReloadSecureSettings(ctx context.Context) error
+	// but only the reloadable secure settings will be applied. The password is only required if the keystore is
+	// protected by a password.
+	ReloadSecureSettings(ctx context.Context, password string) error
 	// GetNodes calls the _nodes api to return a map(nodeName -> Node)
 	GetNodes(ctx context.Context) (Nodes, error)
 	// GetNodesStats calls the _nodes/stats api to return a map(nodeName -> NodeStats)
 	GetNodesStats(ctx context.Context) (NodesStats, error)
+	// GetNodesFSStats calls the _nodes/stats/fs api to return a map(nodeName -> NodeStats) with file system statistics
+	GetNodesFSStats(ctx context.Context) (NodesStats, error)
+	// GetNodesMetrics calls the _nodes/stats api, restricted to the given metric groups, to return the raw statistics of
+	// each node.
+	GetNodesMetrics(ctx context.Context, groups ...string) (NodesMetrics, error)
+	// GetClusterSettingsWithDefaults calls the _cluster/settings api to return all the cluster settings in flat format,
+	// including the default values.
+	GetClusterSettingsWithDefaults(ctx context.Context) (ClusterSettingsWithDefaults, error)
 	// ClusterBootstrappedForZen2 returns true if the cluster is relying on zen2 orchestration.
 	ClusterBootstrappedForZen2(ctx context.Context) (bool, error)
 	// UpdateRemoteClusterSettings updates the remote clusters of a cluster.
 	UpdateRemoteClusterSettings(ctx context.Context, settings RemoteClustersSettings) error
 	// GetRemoteClusterSettings retrieves the remote clusters of a cluster.
 	GetRemoteClusterSettings(ctx context.Context) (RemoteClustersSettings, error)
+	// GetRemoteClusterInfo retrieves the connection state of the remote clusters of a cluster.
+	GetRemoteClusterInfo(ctx context.Context) (RemoteClustersInfo, error)
 	// AddVotingConfigExclusions sets the transient and persistent setting of the same name in cluster settings.
 	// Introduced in: Elasticsearch 7.0.0
 	AddVotingConfigExclusions(ctx context.Context, nodeNames []string) error
@@ -115,9 +170,10 @@ type Client interface {
 	// GetShutdown returns information about ongoing node shutdowns.
 	// Introduced in: Elasticsearch 7.14.0
 	GetShutdown(ctx context.Context, nodeID *string) (ShutdownResponse, error)
-	// PutShutdown initiates a node shutdown procedure for the given node.
+	// PutShutdown initiates a node shutdown procedure for the given node. If not nil, allocationDelay overrides the time
+	// Elasticsearch waits for a restarting node to rejoin the cluster before reallocating its shards.
 	// Introduced in: Elasticsearch 7.14.0
-	PutShutdown(ctx context.Context, nodeID string, shutdownType ShutdownType, reason string) error
+	PutShutdown(ctx context.Context, nodeID string, shutdownType ShutdownType, reason string, allocationDelay *time.Duration) error
 	// DeleteShutdown attempts to cancel an ongoing node shutdown.
 	// Introduced in: Elasticsearch 7.14.0
 	DeleteShutdown(ctx context.Context, nodeID string) error
@@ -134,7 +190,7 @@ type Client interface {
 
 // Timeout returns the Elasticsearch client timeout value for the given Elasticsearch resource.
 func Timeout(ctx context.Context, es esv1.Elasticsearch) time.Duration {
-	return annotation.ExtractTimeout(ctx, es.ObjectMeta, ESClientTimeoutAnnotation, DefaultESClientTimeout)
+	return annotation.ExtractTimeout(ctx, es.ObjectMeta, ESClientTimeoutAnnotation, DefaultTimeout())
 }
 
 func formatAsSeconds(d time.Duration) string {
@@ -142,6 +198,8 @@ func formatAsSeconds(d time.Duration) string {
 }
 
 // NewElasticsearchClient creates a new client for the target cluster.
+// Clients of the same cluster share their HTTP transport, hence their connections to the cluster, unless the cluster
+// is not named. Clusters are told apart by the Kubernetes cluster the dialer connects to, and by namespace and name.
 //
 // If dialer is not nil, it will be used to create new TCP connections
 func NewElasticsearchClient(
@@ -154,15 +212,23 @@ func NewElasticsearchClient(
 	timeout time.Duration,
 	debug bool,
 ) Client {
-	client := commonhttp.Client(dialer, caCerts, timeout)
+	sharedTransport := es != types.NamespacedName{}
+	key := clusterKey{k8sCluster: net.ClusterName(dialer), NamespacedName: es}
+	client := &http.Client{Timeout: timeout}
+	if sharedTransport {
+		client.Transport = transports.get(key, dialer, caCerts)
+	} else {
+		client.Transport = commonhttp.Transport(dialer, caCerts)
+	}
 	client.Transport = apmelasticsearch.WrapRoundTripper(client.Transport)
 	base := &baseClient{
-		URLProvider: esURL,
-		User:        esUser,
-		caCerts:     caCerts,
-		HTTP:        client,
-		es:          es,
-		debug:       debug,
+		URLProvider:     esURL,
+		User:            esUser,
+		caCerts:         caCerts,
+		HTTP:            client,
+		es:              key,
+		debug:           debug,
+		sharedTransport: sharedTransport,
 	}
 	return versioned(base, v)
 }
@@ -306,6 +306,32 @@ func TestAPIError_Error(t *testing.T) {
 	}
 }
 
+func TestErrorReason(t *testing.T) {
+	apiError := newAPIError(context.Background(), &http.Response{
+		StatusCode: 400,
+		Status:     "400 Bad Request",
+		Body:       io.NopCloser(bytes.NewBufferString(fixtures.ErrorSample)),
+	})
+	assert.Equal(t, "illegal value can't update [discovery.zen.minimum_master_nodes] from [1] to [6]: "+
+		"cannot set discovery.zen.minimum_master_nodes to more than the current master nodes count [1]", ErrorReason(apiError))
+
+	apiError = newAPIError(context.Background(), &http.Response{
+		StatusCode: 500,
+		Status:     "500 Internal Server Error",
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	})
+	assert.Equal(t, apiError.Error(), ErrorReason(apiError))
+	assert.Equal(t, "connection refused", ErrorReason(errors.New("connection refused")))
+}
+
+func TestIsUnavailable(t *testing.T) {
+	assert.True(t, IsUnavailable(errors.New("connection refused")))
+	assert.True(t, IsUnavailable(&APIError{StatusCode: http.StatusServiceUnavailable}))
+	assert.True(t, IsUnavailable(&APIError{StatusCode: http.StatusTooManyRequests}))
+	assert.False(t, IsUnavailable(&APIError{StatusCode: http.StatusInternalServerError}))
+	assert.False(t, IsUnavailable(&APIError{StatusCode: http.StatusBadRequest}))
+}
+
 func TestClientGetNodes(t *testing.T) {
 	expectedPath := "/_nodes/_all/no-metrics"
 	testClient := NewMockClient(version.MustParse("6.8.0"), func(req *http.Request) *http.Response {
@@ -342,6 +368,109 @@ func TestClientGetNodesStats(t *testing.T) {
 	require.Equal(t, "3221225472", resp.Nodes["Rt-o5-ZBQaq-Nkhhy0p7JA"].OS.CGroup.Memory.LimitInBytes)
 }
 
+func TestClientGetNodesFSStats(t *testing.T) {
+	expectedPath := "/_nodes/_all/stats/fs"
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, expectedPath, req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(fixtures.NodesFSStatsSample)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	resp, err := testClient.GetNodesFSStats(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(resp.Nodes))
+	require.Contains(t, resp.Nodes, "Rt-o5-ZBQaq-Nkhhy0p7JA")
+	require.Equal(t, int64(1073741824), resp.Nodes["Rt-o5-ZBQaq-Nkhhy0p7JA"].FS.Total.TotalInBytes)
+	require.Equal(t, int64(53687091), resp.Nodes["Rt-o5-ZBQaq-Nkhhy0p7JA"].FS.Total.AvailableInBytes)
+	require.Equal(t, []string{"data_frozen"}, resp.Nodes["pQHNt5rXTTWNvUgOrdynKg"].Roles)
+}
+
+func TestClientGetNodesMetrics(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, "/_nodes/_all/stats/thread_pool,indices", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"nodes":{"node-id":{"name":"es-es-default-0","thread_pool":{"write":{"queue":12}}}}}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	resp, err := testClient.GetNodesMetrics(context.Background(), "thread_pool", "indices")
+	require.NoError(t, err)
+	require.Equal(t, "es-es-default-0", resp.Nodes["node-id"].Name())
+	value, ok := resp.Nodes["node-id"].Value("thread_pool.write.queue")
+	require.True(t, ok)
+	require.Equal(t, 12.0, value)
+}
+
+func TestClientGetClusterSettingsWithDefaults(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, "/_cluster/settings", req.URL.Path)
+		require.Equal(t, "include_defaults=true&flat_settings=true", req.URL.RawQuery)
+		return &http.Response{
+			StatusCode: 200,
+			Body: io.NopCloser(strings.NewReader(`{
+				"persistent": {"cluster.routing.allocation.disk.watermark.high": "80%"},
+				"transient": {},
+				"defaults": {
+					"cluster.routing.allocation.disk.watermark.high": "90%",
+					"cluster.routing.allocation.disk.watermark.flood_stage": "95%",
+					"cluster.routing.allocation.awareness.attributes": []
+				}
+			}`)),
+			Header:  make(http.Header),
+			Request: req,
+		}
+	})
+	settings, err := testClient.GetClusterSettingsWithDefaults(context.Background())
+	require.NoError(t, err)
+	value, exists := settings.Get("cluster.routing.allocation.disk.watermark.high")
+	require.True(t, exists)
+	require.Equal(t, "80%", value)
+	value, exists = settings.Get("cluster.routing.allocation.disk.watermark.flood_stage")
+	require.True(t, exists)
+	require.Equal(t, "95%", value)
+	_, exists = settings.Get("cluster.routing.allocation.awareness.attributes")
+	require.False(t, exists)
+}
+
+func TestClientGetRemoteClusterInfo(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, "/_remote/info", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body: io.NopCloser(strings.NewReader(`{
+				"ns-remote": {
+					"connected": true,
+					"mode": "sniff",
+					"seeds": ["remote-es-transport.ns.svc:9300"],
+					"num_nodes_connected": 3,
+					"max_connections_per_cluster": 3,
+					"initial_connect_timeout": "30s",
+					"skip_unavailable": false
+				},
+				"ns-proxy": {
+					"connected": false,
+					"mode": "proxy",
+					"proxy_address": "proxy:9400",
+					"num_proxy_sockets_connected": 0
+				}
+			}`)),
+			Header:  make(http.Header),
+			Request: req,
+		}
+	})
+	info, err := testClient.GetRemoteClusterInfo(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, RemoteClustersInfo{
+		"ns-remote": {Connected: true, Mode: "sniff", NumNodesConnected: 3},
+		"ns-proxy":  {Connected: false, Mode: "proxy"},
+	}, info)
+}
+
 func TestGetInfo(t *testing.T) {
 	expectedPath := "/"
 	testClient := NewMockClient(version.MustParse("6.4.1"), func(req *http.Request) *http.Response {
@@ -567,6 +696,53 @@ func TestClient_SetMinimumMasterNodes(t *testing.T) {
 	}
 }
 
+func TestClient_ReloadSecureSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantBody string
+		response string
+		wantErr  string
+	}{
+		{
+			name:     "all nodes reloaded their secure settings",
+			response: `{"_nodes":{"total":2,"successful":2,"failed":0},"cluster_name":"es","nodes":{"a":{"name":"es-0"},"b":{"name":"es-1"}}}`,
+		},
+		{
+			name:     "some nodes failed to reload their secure settings",
+			response: `{"_nodes":{"total":2,"successful":2,"failed":0},"cluster_name":"es","nodes":{"a":{"name":"es-0"},"b":{"name":"es-1","reload_exception":{"type":"illegal_state_exception","reason":"Keystore is missing"}}}}`,
+			wantErr:  "failed to reload secure settings on nodes: es-1: Keystore is missing",
+		},
+		{
+			name:     "password protected keystore",
+			password: "changeme",
+			wantBody: `{"secure_settings_password":"changeme"}`,
+			response: `{"_nodes":{"total":1,"successful":1,"failed":0},"cluster_name":"es","nodes":{"a":{"name":"es-0"}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+				require.Equal(t, http.MethodPost, req.Method)
+				require.Equal(t, "/_nodes/reload_secure_settings", req.URL.Path)
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				require.Equal(t, tt.wantBody, string(body))
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(tt.response)),
+				}
+			})
+			err := client.ReloadSecureSettings(context.Background(), tt.password)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestAPIError_Types(t *testing.T) {
 	ctx := context.Background()
 	type args struct {
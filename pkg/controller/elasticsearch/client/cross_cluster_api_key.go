@@ -0,0 +1,64 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// CrossClusterAPIKeyAccess is the access granted to a cross-cluster API key, as expected by the cross-cluster API key API.
+type CrossClusterAPIKeyAccess struct {
+	Search      []CrossClusterIndices `json:"search,omitempty"`
+	Replication []CrossClusterIndices `json:"replication,omitempty"`
+}
+
+// CrossClusterIndices are the indices a cross-cluster API key grants access to.
+type CrossClusterIndices struct {
+	Names                  []string `json:"names"`
+	AllowRestrictedIndices *bool    `json:"allow_restricted_indices,omitempty"`
+}
+
+// CrossClusterAPIKeyRequest is the request to create a cross-cluster API key.
+type CrossClusterAPIKeyRequest struct {
+	Name     string                   `json:"name"`
+	Access   CrossClusterAPIKeyAccess `json:"access"`
+	Metadata map[string]interface{}   `json:"metadata,omitempty"`
+}
+
+// CrossClusterAPIKey is a cross-cluster API key as returned on creation by the cross-cluster API key API.
+type CrossClusterAPIKey struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	APIKey  string `json:"api_key"`
+	Encoded string `json:"encoded"`
+}
+
+type CrossClusterAPIKeyClient interface {
+	// CreateCrossClusterAPIKey creates a cross-cluster API key, used by remote clusters to connect to the cluster with the
+	// API key based security model. Available from Elasticsearch 8.10.0.
+	CreateCrossClusterAPIKey(ctx context.Context, request CrossClusterAPIKeyRequest) (CrossClusterAPIKey, error)
+	// UpdateCrossClusterAPIKey updates the access granted to an existing cross-cluster API key.
+	UpdateCrossClusterAPIKey(ctx context.Context, id string, access CrossClusterAPIKeyAccess) error
+	// InvalidateAPIKeys invalidates the API keys with the given IDs.
+	InvalidateAPIKeys(ctx context.Context, ids ...string) error
+}
+
+func (c *baseClient) CreateCrossClusterAPIKey(ctx context.Context, request CrossClusterAPIKeyRequest) (CrossClusterAPIKey, error) {
+	var apiKey CrossClusterAPIKey
+	err := c.post(ctx, "/_security/cross_cluster/api_key", request, &apiKey)
+	return apiKey, err
+}
+
+func (c *baseClient) UpdateCrossClusterAPIKey(ctx context.Context, id string, access CrossClusterAPIKeyAccess) error {
+	body := map[string]interface{}{"access": access}
+	return c.put(ctx, "/_security/cross_cluster/api_key/"+url.PathEscape(id), body, nil)
+}
+
+func (c *baseClient) InvalidateAPIKeys(ctx context.Context, ids ...string) error {
+	body := map[string]interface{}{"ids": ids}
+	return c.request(ctx, http.MethodDelete, "/_security/api_key", body, nil, nil)
+}
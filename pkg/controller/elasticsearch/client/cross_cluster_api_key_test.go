@@ -0,0 +1,64 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_CreateCrossClusterAPIKey(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_security/cross_cluster/api_key", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"eck-ns-link","access":{"search":[{"names":["logs-*"]}]}}`, string(body))
+		return NewMockResponse(200, req, `{"id":"VuaCfGcBCdbkQm-e5aOx","name":"eck-ns-link","api_key":"ui2lp2axTNmsyakw9tvNnw","encoded":"VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="}`)
+	})
+	apiKey, err := testClient.CreateCrossClusterAPIKey(context.Background(), CrossClusterAPIKeyRequest{
+		Name:   "eck-ns-link",
+		Access: CrossClusterAPIKeyAccess{Search: []CrossClusterIndices{{Names: []string{"logs-*"}}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, CrossClusterAPIKey{
+		ID:      "VuaCfGcBCdbkQm-e5aOx",
+		Name:    "eck-ns-link",
+		APIKey:  "ui2lp2axTNmsyakw9tvNnw",
+		Encoded: "VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw==",
+	}, apiKey)
+}
+
+func TestClient_UpdateCrossClusterAPIKey(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_security/cross_cluster/api_key/VuaCfGcBCdbkQm-e5aOx", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"access":{"replication":[{"names":["leader-*"]}]}}`, string(body))
+		return NewMockResponse(200, req, `{"updated":true}`)
+	})
+	require.NoError(t, testClient.UpdateCrossClusterAPIKey(context.Background(), "VuaCfGcBCdbkQm-e5aOx", CrossClusterAPIKeyAccess{
+		Replication: []CrossClusterIndices{{Names: []string{"leader-*"}}},
+	}))
+}
+
+func TestClient_InvalidateAPIKeys(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_security/api_key", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"ids":["VuaCfGcBCdbkQm-e5aOx"]}`, string(body))
+		return NewMockResponse(200, req, `{"invalidated_api_keys":["VuaCfGcBCdbkQm-e5aOx"],"previously_invalidated_api_keys":[],"error_count":0}`)
+	})
+	require.NoError(t, testClient.InvalidateAPIKeys(context.Background(), "VuaCfGcBCdbkQm-e5aOx"))
+}
@@ -0,0 +1,29 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+)
+
+// DataStream is a data stream as returned by the /_data_stream API.
+type DataStream struct {
+	Name string `json:"name"`
+	// Template is the name of the index template the data stream was created from.
+	Template string `json:"template"`
+}
+
+type DataStreamClient interface {
+	// GetDataStreams returns all the data streams of the cluster, including the hidden ones.
+	GetDataStreams(ctx context.Context) ([]DataStream, error)
+}
+
+func (c *baseClient) GetDataStreams(ctx context.Context) ([]DataStream, error) {
+	var response struct {
+		DataStreams []DataStream `json:"data_streams"`
+	}
+	err := c.get(ctx, "/_data_stream?expand_wildcards=all", &response)
+	return response.DataStreams, err
+}
@@ -0,0 +1,33 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetDataStreams(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_data_stream", req.URL.Path)
+		require.Equal(t, "expand_wildcards=all", req.URL.RawQuery)
+		return NewMockResponse(200, req, `{"data_streams":[
+  {"name":"logs-app-default","timestamp_field":{"name":"@timestamp"},"generation":2,"status":"GREEN","template":"logs-app","hidden":false},
+  {"name":".fleet-actions-results","generation":1,"status":"GREEN","template":".fleet-actions-results","hidden":true}
+]}`)
+	})
+	dataStreams, err := testClient.GetDataStreams(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []DataStream{
+		{Name: "logs-app-default", Template: "logs-app"},
+		{Name: ".fleet-actions-results", Template: ".fleet-actions-results"},
+	}, dataStreams)
+}
@@ -0,0 +1,76 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// DeprecationLevelCritical is the level of the deprecation issues which must be resolved before upgrading to the next
+// major version.
+const DeprecationLevelCritical = "critical"
+
+// Deprecation is a deprecation issue as returned by the /_migration/deprecations API.
+type Deprecation struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	URL     string `json:"url"`
+	Details string `json:"details,omitempty"`
+	// ResolveDuringRollingUpgrade is true if the issue is resolved by the rolling upgrade itself.
+	ResolveDuringRollingUpgrade bool `json:"resolve_during_rolling_upgrade"`
+}
+
+// Deprecations are the deprecation issues of a cluster, grouped by the resource they relate to.
+type Deprecations struct {
+	ClusterSettings []Deprecation            `json:"cluster_settings"`
+	NodeSettings    []Deprecation            `json:"node_settings"`
+	MLSettings      []Deprecation            `json:"ml_settings"`
+	IndexSettings   map[string][]Deprecation `json:"index_settings"`
+	DataStreams     map[string][]Deprecation `json:"data_streams"`
+	Templates       map[string][]Deprecation `json:"templates"`
+	ILMPolicies     map[string][]Deprecation `json:"ilm_policies"`
+}
+
+// Critical returns the sorted messages of the critical deprecation issues which are not resolved by the rolling
+// upgrade, prefixed with the resource they relate to.
+func (d Deprecations) Critical() []string {
+	var messages []string
+	appendCritical := func(resource string, deprecations []Deprecation) {
+		for _, deprecation := range deprecations {
+			if deprecation.Level != DeprecationLevelCritical || deprecation.ResolveDuringRollingUpgrade {
+				continue
+			}
+			messages = append(messages, fmt.Sprintf("%s: %s", resource, deprecation.Message))
+		}
+	}
+	appendCritical("cluster settings", d.ClusterSettings)
+	appendCritical("node settings", d.NodeSettings)
+	appendCritical("ML settings", d.MLSettings)
+	for kind, byName := range map[string]map[string][]Deprecation{
+		"index":       d.IndexSettings,
+		"data stream": d.DataStreams,
+		"template":    d.Templates,
+		"ILM policy":  d.ILMPolicies,
+	} {
+		for name, deprecations := range byName {
+			appendCritical(fmt.Sprintf("%s %s", kind, name), deprecations)
+		}
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+type DeprecationClient interface {
+	// GetDeprecations returns the deprecation issues to resolve before upgrading to the next major version.
+	GetDeprecations(ctx context.Context) (Deprecations, error)
+}
+
+func (c *baseClient) GetDeprecations(ctx context.Context) (Deprecations, error) {
+	var deprecations Deprecations
+	err := c.get(ctx, "/_migration/deprecations", &deprecations)
+	return deprecations, err
+}
@@ -0,0 +1,54 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetDeprecations(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("7.17.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_migration/deprecations", req.URL.Path)
+		return NewMockResponse(200, req, `{
+  "cluster_settings": [
+    {"level":"critical","message":"Cluster name cannot contain ':'","url":"https://ela.st/es-deprecation","details":"","resolve_during_rolling_upgrade":false}
+  ],
+  "node_settings": [
+    {"level":"critical","message":"Setting [node.data] is deprecated","url":"https://ela.st/es-deprecation","details":"","resolve_during_rolling_upgrade":true}
+  ],
+  "ml_settings": [],
+  "index_settings": {
+    "logs-2019": [
+      {"level":"critical","message":"Index created before 7.0","url":"https://ela.st/es-deprecation","details":"This index was created with version 6.8.0","resolve_during_rolling_upgrade":false},
+      {"level":"warning","message":"Translog retention settings are deprecated","url":"https://ela.st/es-deprecation","details":"","resolve_during_rolling_upgrade":false}
+    ]
+  },
+  "data_streams": {},
+  "templates": {},
+  "ilm_policies": {
+    "old-policy": [
+      {"level":"critical","message":"Policy uses the freeze action","url":"https://ela.st/es-deprecation","details":"","resolve_during_rolling_upgrade":false}
+    ]
+  }
+}`)
+	})
+	deprecations, err := testClient.GetDeprecations(context.Background())
+	require.NoError(t, err)
+	require.Len(t, deprecations.ClusterSettings, 1)
+	require.Len(t, deprecations.IndexSettings["logs-2019"], 2)
+	require.Equal(t, "This index was created with version 6.8.0", deprecations.IndexSettings["logs-2019"][0].Details)
+	require.Equal(t, []string{
+		"ILM policy old-policy: Policy uses the freeze action",
+		"cluster settings: Cluster name cannot contain ':'",
+		"index logs-2019: Index created before 7.0",
+	}, deprecations.Critical())
+}
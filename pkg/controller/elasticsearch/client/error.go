@@ -59,6 +59,20 @@ func (a *APIError) Error() string {
 	return fmt.Sprintf("%s: %+v", a.Status, a.ErrorResponse)
 }
 
+// ErrorReason returns the reason of an Elasticsearch API error, followed by the reason of its cause if any.
+// It returns the error message for other errors.
+func ErrorReason(err error) string {
+	apiErr := new(APIError)
+	if !errors.As(err, &apiErr) || apiErr.ErrorResponse.Error.Reason == "" {
+		return err.Error()
+	}
+	reason := apiErr.ErrorResponse.Error.Reason
+	if causedBy := apiErr.ErrorResponse.Error.CausedBy.Reason; causedBy != "" {
+		reason = fmt.Sprintf("%s: %s", reason, causedBy)
+	}
+	return reason
+}
+
 // IsUnauthorized checks whether the error was an HTTP 401 error.
 func IsUnauthorized(err error) bool {
 	return isHTTPError(err, http.StatusUnauthorized)
@@ -84,6 +98,20 @@ func IsConflict(err error) bool {
 	return isHTTPError(err, http.StatusConflict)
 }
 
+// IsUnavailable returns true if the request failed because Elasticsearch could not be reached or was temporarily
+// unavailable, rather than because it rejected the request.
+func IsUnavailable(err error) bool {
+	apiErr := new(APIError)
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	switch apiErr.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
 func Is4xx(err error) bool {
 	apiErr := new(APIError)
 	if errors.As(err, &apiErr) {
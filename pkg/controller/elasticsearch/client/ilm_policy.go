@@ -0,0 +1,60 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ILMPolicyInfo is an index lifecycle management (ILM) policy as returned by the /_ilm/policy API.
+type ILMPolicyInfo struct {
+	Version int64                  `json:"version"`
+	Policy  map[string]interface{} `json:"policy"`
+	InUseBy ILMPolicyUsage         `json:"in_use_by"`
+}
+
+// ILMPolicyUsage lists the indices, data streams and index templates using an ILM policy.
+type ILMPolicyUsage struct {
+	Indices             []string `json:"indices,omitempty"`
+	DataStreams         []string `json:"data_streams,omitempty"`
+	ComposableTemplates []string `json:"composable_templates,omitempty"`
+}
+
+// IsEmpty returns true if the policy is not used by any index, data stream or index template.
+func (u ILMPolicyUsage) IsEmpty() bool {
+	return len(u.Indices) == 0 && len(u.DataStreams) == 0 && len(u.ComposableTemplates) == 0
+}
+
+type ILMPolicyClient interface {
+	// GetILMPolicy returns the ILM policy of the given name, with the resources using it.
+	GetILMPolicy(ctx context.Context, name string) (ILMPolicyInfo, error)
+	// UpdateILMPolicy creates or updates an ILM policy.
+	UpdateILMPolicy(ctx context.Context, name string, policy map[string]interface{}) error
+	// DeleteILMPolicy deletes an ILM policy. Elasticsearch refuses to delete a policy used by indices.
+	DeleteILMPolicy(ctx context.Context, name string) error
+}
+
+func (c *baseClient) GetILMPolicy(ctx context.Context, name string) (ILMPolicyInfo, error) {
+	var policies map[string]ILMPolicyInfo
+	if err := c.get(ctx, "/_ilm/policy/"+url.PathEscape(name), &policies); err != nil {
+		return ILMPolicyInfo{}, err
+	}
+	policy, exists := policies[name]
+	if !exists {
+		return ILMPolicyInfo{}, fmt.Errorf("ILM policy %s not found in the response", name)
+	}
+	return policy, nil
+}
+
+func (c *baseClient) UpdateILMPolicy(ctx context.Context, name string, policy map[string]interface{}) error {
+	body := map[string]interface{}{"policy": policy}
+	return c.put(ctx, "/_ilm/policy/"+url.PathEscape(name), body, nil)
+}
+
+func (c *baseClient) DeleteILMPolicy(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_ilm/policy/"+url.PathEscape(name))
+}
@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetILMPolicy(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_ilm/policy/logs", req.URL.Path)
+		return NewMockResponse(200, req, `{
+  "logs": {
+    "version": 3,
+    "modified_date": "2024-01-02T10:00:00.000Z",
+    "policy": {
+      "phases": {
+        "hot": {"min_age": "0ms", "actions": {"rollover": {"max_age": "30d"}}},
+        "delete": {"min_age": "90d", "actions": {"delete": {}}}
+      }
+    },
+    "in_use_by": {"indices": [".ds-logs-2024.01.02-000001"], "data_streams": ["logs"], "composable_templates": []}
+  }
+}`)
+	})
+	policy, err := testClient.GetILMPolicy(context.Background(), "logs")
+	require.NoError(t, err)
+	require.Equal(t, ILMPolicyInfo{
+		Version: 3,
+		Policy: map[string]interface{}{
+			"phases": map[string]interface{}{
+				"hot":    map[string]interface{}{"min_age": "0ms", "actions": map[string]interface{}{"rollover": map[string]interface{}{"max_age": "30d"}}},
+				"delete": map[string]interface{}{"min_age": "90d", "actions": map[string]interface{}{"delete": map[string]interface{}{}}},
+			},
+		},
+		InUseBy: ILMPolicyUsage{
+			Indices:             []string{".ds-logs-2024.01.02-000001"},
+			DataStreams:         []string{"logs"},
+			ComposableTemplates: []string{},
+		},
+	}, policy)
+	require.False(t, policy.InUseBy.IsEmpty())
+
+	testClient = NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(404, req, `{"error":{"type":"resource_not_found_exception"},"status":404}`)
+	})
+	_, err = testClient.GetILMPolicy(context.Background(), "logs")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_UpdateILMPolicy(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_ilm/policy/logs", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"policy":{"phases":{"delete":{"min_age":"90d","actions":{"delete":{}}}}}}`, string(body))
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	policy := map[string]interface{}{
+		"phases": map[string]interface{}{
+			"delete": map[string]interface{}{"min_age": "90d", "actions": map[string]interface{}{"delete": map[string]interface{}{}}},
+		},
+	}
+	require.NoError(t, testClient.UpdateILMPolicy(context.Background(), "logs", policy))
+}
+
+func TestClient_DeleteILMPolicy(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_ilm/policy/logs", req.URL.Path)
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, testClient.DeleteILMPolicy(context.Background(), "logs"))
+}
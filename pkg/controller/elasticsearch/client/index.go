@@ -0,0 +1,106 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// Index is the definition of an index as returned by the /{index} API.
+type Index struct {
+	Aliases  map[string]interface{} `json:"aliases,omitempty"`
+	Mappings map[string]interface{} `json:"mappings,omitempty"`
+	// Settings are the flat settings of the index, for example index.number_of_replicas.
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// AliasAction is an action of the /_aliases API, either to add or to remove an alias.
+type AliasAction struct {
+	Add    *AliasActionParams `json:"add,omitempty"`
+	Remove *AliasActionParams `json:"remove,omitempty"`
+}
+
+// AliasActionParams are the parameters of an AliasAction.
+type AliasActionParams struct {
+	// Index is the name of the index or data stream the action applies to.
+	Index        string                 `json:"index"`
+	Alias        string                 `json:"alias"`
+	Filter       map[string]interface{} `json:"filter,omitempty"`
+	Routing      string                 `json:"routing,omitempty"`
+	IsWriteIndex *bool                  `json:"is_write_index,omitempty"`
+}
+
+// aliasesResponse is the response of the /{index}/_alias API.
+type aliasesResponse map[string]struct {
+	Aliases map[string]interface{} `json:"aliases"`
+}
+
+type IndexClient interface {
+	// GetIndex returns the aliases, mappings and flat settings of the index of the given name, or of the backing
+	// indices of the data stream of the given name, indexed by index name.
+	GetIndex(ctx context.Context, name string) (map[string]Index, error)
+	// GetAliases returns the aliases of the index or the data stream of the given name, indexed by alias name.
+	GetAliases(ctx context.Context, name string) (map[string]interface{}, error)
+	// CreateIndex creates an index with the given settings, mappings and aliases.
+	CreateIndex(ctx context.Context, name string, index map[string]interface{}) error
+	// CreateDataStream creates a data stream from the index template matching its name.
+	CreateDataStream(ctx context.Context, name string) error
+	// UpdateIndexSettings updates the dynamic settings of an index, or of all the backing indices of a data stream.
+	UpdateIndexSettings(ctx context.Context, name string, settings map[string]interface{}) error
+	// UpdateIndexMappings adds fields to the mappings of an index, or of all the backing indices of a data stream.
+	UpdateIndexMappings(ctx context.Context, name string, mappings map[string]interface{}) error
+	// UpdateAliases atomically applies the given actions on aliases.
+	UpdateAliases(ctx context.Context, actions []AliasAction) error
+	// DeleteIndex deletes an index and all its documents.
+	DeleteIndex(ctx context.Context, name string) error
+	// DeleteDataStream deletes a data stream, its backing indices and all their documents.
+	DeleteDataStream(ctx context.Context, name string) error
+}
+
+func (c *baseClient) GetIndex(ctx context.Context, name string) (map[string]Index, error) {
+	var response map[string]Index
+	err := c.get(ctx, "/"+url.PathEscape(name)+"?flat_settings=true", &response)
+	return response, err
+}
+
+func (c *baseClient) GetAliases(ctx context.Context, name string) (map[string]interface{}, error) {
+	var response aliasesResponse
+	if err := c.get(ctx, "/"+url.PathEscape(name)+"/_alias", &response); err != nil {
+		return nil, err
+	}
+	return response[name].Aliases, nil
+}
+
+func (c *baseClient) CreateIndex(ctx context.Context, name string, index map[string]interface{}) error {
+	return c.put(ctx, "/"+url.PathEscape(name), index, nil)
+}
+
+func (c *baseClient) CreateDataStream(ctx context.Context, name string) error {
+	return c.put(ctx, "/_data_stream/"+url.PathEscape(name), nil, nil)
+}
+
+func (c *baseClient) UpdateIndexSettings(ctx context.Context, name string, settings map[string]interface{}) error {
+	return c.put(ctx, "/"+url.PathEscape(name)+"/_settings", settings, nil)
+}
+
+func (c *baseClient) UpdateIndexMappings(ctx context.Context, name string, mappings map[string]interface{}) error {
+	return c.put(ctx, "/"+url.PathEscape(name)+"/_mapping", mappings, nil)
+}
+
+func (c *baseClient) UpdateAliases(ctx context.Context, actions []AliasAction) error {
+	request := struct {
+		Actions []AliasAction `json:"actions"`
+	}{Actions: actions}
+	return c.post(ctx, "/_aliases", request, nil)
+}
+
+func (c *baseClient) DeleteIndex(ctx context.Context, name string) error {
+	return c.delete(ctx, "/"+url.PathEscape(name))
+}
+
+func (c *baseClient) DeleteDataStream(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_data_stream/"+url.PathEscape(name))
+}
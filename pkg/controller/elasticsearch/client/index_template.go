@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// indexTemplatesResponse is the response of the /_index_template API.
+type indexTemplatesResponse struct {
+	IndexTemplates []struct {
+		Name          string                 `json:"name"`
+		IndexTemplate map[string]interface{} `json:"index_template"`
+	} `json:"index_templates"`
+}
+
+// componentTemplatesResponse is the response of the /_component_template API.
+type componentTemplatesResponse struct {
+	ComponentTemplates []struct {
+		Name              string                 `json:"name"`
+		ComponentTemplate map[string]interface{} `json:"component_template"`
+	} `json:"component_templates"`
+}
+
+type IndexTemplateClient interface {
+	// GetIndexTemplates returns all the composable index templates of the cluster, indexed by name, with flat settings.
+	GetIndexTemplates(ctx context.Context) (map[string]map[string]interface{}, error)
+	// GetIndexTemplate returns the composable index template of the given name, with flat settings.
+	GetIndexTemplate(ctx context.Context, name string) (map[string]interface{}, error)
+	// UpdateIndexTemplate creates or updates a composable index template.
+	UpdateIndexTemplate(ctx context.Context, name string, template map[string]interface{}) error
+	// DeleteIndexTemplate deletes a composable index template. Elasticsearch refuses to delete a template used by data streams.
+	DeleteIndexTemplate(ctx context.Context, name string) error
+	// GetComponentTemplate returns the component template of the given name, with flat settings.
+	GetComponentTemplate(ctx context.Context, name string) (map[string]interface{}, error)
+	// UpdateComponentTemplate creates or updates a component template.
+	UpdateComponentTemplate(ctx context.Context, name string, template map[string]interface{}) error
+	// DeleteComponentTemplate deletes a component template. Elasticsearch refuses to delete a template used by index templates.
+	DeleteComponentTemplate(ctx context.Context, name string) error
+}
+
+func (c *baseClient) GetIndexTemplates(ctx context.Context) (map[string]map[string]interface{}, error) {
+	var response indexTemplatesResponse
+	if err := c.get(ctx, "/_index_template?flat_settings=true", &response); err != nil {
+		return nil, err
+	}
+	templates := make(map[string]map[string]interface{}, len(response.IndexTemplates))
+	for _, template := range response.IndexTemplates {
+		templates[template.Name] = template.IndexTemplate
+	}
+	return templates, nil
+}
+
+func (c *baseClient) GetIndexTemplate(ctx context.Context, name string) (map[string]interface{}, error) {
+	var response indexTemplatesResponse
+	if err := c.get(ctx, fmt.Sprintf("/_index_template/%s?flat_settings=true", url.PathEscape(name)), &response); err != nil {
+		return nil, err
+	}
+	for _, template := range response.IndexTemplates {
+		if template.Name == name {
+			return template.IndexTemplate, nil
+		}
+	}
+	return nil, fmt.Errorf("index template %s not found in the response", name)
+}
+
+func (c *baseClient) UpdateIndexTemplate(ctx context.Context, name string, template map[string]interface{}) error {
+	return c.put(ctx, "/_index_template/"+url.PathEscape(name), template, nil)
+}
+
+func (c *baseClient) DeleteIndexTemplate(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_index_template/"+url.PathEscape(name))
+}
+
+func (c *baseClient) GetComponentTemplate(ctx context.Context, name string) (map[string]interface{}, error) {
+	var response componentTemplatesResponse
+	if err := c.get(ctx, fmt.Sprintf("/_component_template/%s?flat_settings=true", url.PathEscape(name)), &response); err != nil {
+		return nil, err
+	}
+	for _, template := range response.ComponentTemplates {
+		if template.Name == name {
+			return template.ComponentTemplate, nil
+		}
+	}
+	return nil, fmt.Errorf("component template %s not found in the response", name)
+}
+
+func (c *baseClient) UpdateComponentTemplate(ctx context.Context, name string, template map[string]interface{}) error {
+	return c.put(ctx, "/_component_template/"+url.PathEscape(name), template, nil)
+}
+
+func (c *baseClient) DeleteComponentTemplate(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_component_template/"+url.PathEscape(name))
+}
@@ -0,0 +1,123 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetIndexTemplates(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_index_template", req.URL.Path)
+		require.Equal(t, "flat_settings=true", req.URL.RawQuery)
+		return NewMockResponse(200, req, `{"index_templates":[
+  {"name":"logs-app","index_template":{"index_patterns":["logs-app-*"],"composed_of":["logs-mappings"],"data_stream":{"hidden":false,"allow_custom_routing":false}}},
+  {"name":"metrics-app","index_template":{"index_patterns":["metrics-app-*"],"composed_of":[]}}
+]}`)
+	})
+	templates, err := testClient.GetIndexTemplates(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]interface{}{
+		"logs-app": {
+			"index_patterns": []interface{}{"logs-app-*"},
+			"composed_of":    []interface{}{"logs-mappings"},
+			"data_stream":    map[string]interface{}{"hidden": false, "allow_custom_routing": false},
+		},
+		"metrics-app": {
+			"index_patterns": []interface{}{"metrics-app-*"},
+			"composed_of":    []interface{}{},
+		},
+	}, templates)
+}
+
+func TestClient_GetIndexTemplate(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_index_template/logs-app", req.URL.Path)
+		require.Equal(t, "flat_settings=true", req.URL.RawQuery)
+		return NewMockResponse(200, req, `{"index_templates":[{"name":"logs-app","index_template":{"index_patterns":["logs-app-*"],"template":{"settings":{"index.number_of_shards":"1"}},"composed_of":[]}}]}`)
+	})
+	template, err := testClient.GetIndexTemplate(context.Background(), "logs-app")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"index_patterns": []interface{}{"logs-app-*"},
+		"template":       map[string]interface{}{"settings": map[string]interface{}{"index.number_of_shards": "1"}},
+		"composed_of":    []interface{}{},
+	}, template)
+
+	testClient = NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(404, req, `{"error":{"type":"resource_not_found_exception"},"status":404}`)
+	})
+	_, err = testClient.GetIndexTemplate(context.Background(), "logs-app")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_UpdateIndexTemplate(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_index_template/logs-app", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"index_patterns":["logs-app-*"],"data_stream":{},"priority":500}`, string(body))
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	template := map[string]interface{}{"index_patterns": []string{"logs-app-*"}, "data_stream": map[string]interface{}{}, "priority": 500}
+	require.NoError(t, testClient.UpdateIndexTemplate(context.Background(), "logs-app", template))
+}
+
+func TestClient_DeleteIndexTemplate(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_index_template/logs-app", req.URL.Path)
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, testClient.DeleteIndexTemplate(context.Background(), "logs-app"))
+}
+
+func TestClient_GetComponentTemplate(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_component_template/logs-mappings", req.URL.Path)
+		require.Equal(t, "flat_settings=true", req.URL.RawQuery)
+		return NewMockResponse(200, req, `{"component_templates":[{"name":"logs-mappings","component_template":{"template":{"mappings":{"properties":{"message":{"type":"text"}}}}}}]}`)
+	})
+	template, err := testClient.GetComponentTemplate(context.Background(), "logs-mappings")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"template": map[string]interface{}{
+			"mappings": map[string]interface{}{"properties": map[string]interface{}{"message": map[string]interface{}{"type": "text"}}},
+		},
+	}, template)
+}
+
+func TestClient_UpdateComponentTemplate(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_component_template/logs-mappings", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"template":{"settings":{"number_of_shards":1}}}`, string(body))
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	template := map[string]interface{}{"template": map[string]interface{}{"settings": map[string]interface{}{"number_of_shards": 1}}}
+	require.NoError(t, testClient.UpdateComponentTemplate(context.Background(), "logs-mappings", template))
+}
+
+func TestClient_DeleteComponentTemplate(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_component_template/logs-mappings", req.URL.Path)
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, testClient.DeleteComponentTemplate(context.Background(), "logs-mappings"))
+}
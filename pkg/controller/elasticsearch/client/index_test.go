@@ -0,0 +1,74 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetIndex(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/orders", req.URL.Path)
+		require.Equal(t, "true", req.URL.Query().Get("flat_settings"))
+		return NewMockResponse(200, req, `{"orders":{"aliases":{"orders-current":{}},"mappings":{"properties":{"price":{"type":"float"}}},"settings":{"index.number_of_replicas":"1","index.number_of_shards":"3"}}}`)
+	})
+	indices, err := testClient.GetIndex(context.Background(), "orders")
+	require.NoError(t, err)
+	require.Equal(t, map[string]Index{"orders": {
+		Aliases:  map[string]interface{}{"orders-current": map[string]interface{}{}},
+		Mappings: map[string]interface{}{"properties": map[string]interface{}{"price": map[string]interface{}{"type": "float"}}},
+		Settings: map[string]interface{}{"index.number_of_replicas": "1", "index.number_of_shards": "3"},
+	}}, indices)
+
+	testClient = NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(404, req, `{}`)
+	})
+	_, err = testClient.GetIndex(context.Background(), "orders")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_GetAliases(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/logs-app-default/_alias", req.URL.Path)
+		return NewMockResponse(200, req, `{"logs-app-default":{"aliases":{"logs-app":{"is_write_index":true}}}}`)
+	})
+	aliases, err := testClient.GetAliases(context.Background(), "logs-app-default")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"logs-app": map[string]interface{}{"is_write_index": true}}, aliases)
+}
+
+func TestClient_UpdateAliases(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_aliases", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"actions":[{"add":{"index":"orders","alias":"orders-current","is_write_index":true}},{"remove":{"index":"orders","alias":"orders-old"}}]}`, string(body))
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, testClient.UpdateAliases(context.Background(), []AliasAction{
+		{Add: &AliasActionParams{Index: "orders", Alias: "orders-current", IsWriteIndex: ptr.To(true)}},
+		{Remove: &AliasActionParams{Index: "orders", Alias: "orders-old"}},
+	}))
+}
+
+func TestClient_CreateDataStream(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_data_stream/logs-app-default", req.URL.Path)
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, testClient.CreateDataStream(context.Background(), "logs-app-default"))
+}
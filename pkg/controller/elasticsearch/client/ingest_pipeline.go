@@ -0,0 +1,83 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// simulatePipelineRequest is the body of the /_ingest/pipeline/_simulate API.
+type simulatePipelineRequest struct {
+	Pipeline  map[string]interface{} `json:"pipeline"`
+	Documents []simulatedDocument    `json:"docs"`
+}
+
+type simulatedDocument struct {
+	Source map[string]interface{} `json:"_source"`
+}
+
+// simulatePipelineResponse is the response of the /_ingest/pipeline/_simulate API.
+type simulatePipelineResponse struct {
+	Documents []SimulatedDocumentResult `json:"docs"`
+}
+
+// SimulatedDocumentResult is the result of the simulation of a pipeline on one document: either the processed
+// document or the error raised while processing it.
+type SimulatedDocumentResult struct {
+	Doc   map[string]interface{}  `json:"doc,omitempty"`
+	Error *SimulatedDocumentError `json:"error,omitempty"`
+}
+
+// SimulatedDocumentError is the error raised by a pipeline while processing a simulated document.
+type SimulatedDocumentError struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+type IngestPipelineClient interface {
+	// GetIngestPipeline returns the ingest pipeline of the given name.
+	GetIngestPipeline(ctx context.Context, name string) (map[string]interface{}, error)
+	// UpdateIngestPipeline creates or updates an ingest pipeline.
+	UpdateIngestPipeline(ctx context.Context, name string, pipeline map[string]interface{}) error
+	// DeleteIngestPipeline deletes an ingest pipeline.
+	DeleteIngestPipeline(ctx context.Context, name string) error
+	// SimulateIngestPipeline runs the given documents sources through the given pipeline definition and returns the
+	// result for each of them, in the same order.
+	SimulateIngestPipeline(ctx context.Context, pipeline map[string]interface{}, sources []map[string]interface{}) ([]SimulatedDocumentResult, error)
+}
+
+func (c *baseClient) GetIngestPipeline(ctx context.Context, name string) (map[string]interface{}, error) {
+	var pipelines map[string]map[string]interface{}
+	if err := c.get(ctx, "/_ingest/pipeline/"+url.PathEscape(name), &pipelines); err != nil {
+		return nil, err
+	}
+	pipeline, exists := pipelines[name]
+	if !exists {
+		return nil, fmt.Errorf("ingest pipeline %s not found in the response", name)
+	}
+	return pipeline, nil
+}
+
+func (c *baseClient) UpdateIngestPipeline(ctx context.Context, name string, pipeline map[string]interface{}) error {
+	return c.put(ctx, "/_ingest/pipeline/"+url.PathEscape(name), pipeline, nil)
+}
+
+func (c *baseClient) DeleteIngestPipeline(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_ingest/pipeline/"+url.PathEscape(name))
+}
+
+func (c *baseClient) SimulateIngestPipeline(ctx context.Context, pipeline map[string]interface{}, sources []map[string]interface{}) ([]SimulatedDocumentResult, error) {
+	request := simulatePipelineRequest{Pipeline: pipeline, Documents: make([]simulatedDocument, 0, len(sources))}
+	for _, source := range sources {
+		request.Documents = append(request.Documents, simulatedDocument{Source: source})
+	}
+	var response simulatePipelineResponse
+	if err := c.post(ctx, "/_ingest/pipeline/_simulate", request, &response); err != nil {
+		return nil, err
+	}
+	return response.Documents, nil
+}
@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetIngestPipeline(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_ingest/pipeline/enrich-team", req.URL.Path)
+		return NewMockResponse(200, req, `{"enrich-team":{"description":"sets the team","processors":[{"set":{"field":"team","value":"payments"}}]}}`)
+	})
+	pipeline, err := testClient.GetIngestPipeline(context.Background(), "enrich-team")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"description": "sets the team",
+		"processors": []interface{}{
+			map[string]interface{}{"set": map[string]interface{}{"field": "team", "value": "payments"}},
+		},
+	}, pipeline)
+
+	testClient = NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(404, req, `{}`)
+	})
+	_, err = testClient.GetIngestPipeline(context.Background(), "enrich-team")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_UpdateIngestPipeline(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_ingest/pipeline/enrich-team", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"processors":[{"set":{"field":"team","value":"payments"}}]}`, string(body))
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	pipeline := map[string]interface{}{
+		"processors": []interface{}{
+			map[string]interface{}{"set": map[string]interface{}{"field": "team", "value": "payments"}},
+		},
+	}
+	require.NoError(t, testClient.UpdateIngestPipeline(context.Background(), "enrich-team", pipeline))
+}
+
+func TestClient_DeleteIngestPipeline(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_ingest/pipeline/enrich-team", req.URL.Path)
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, testClient.DeleteIngestPipeline(context.Background(), "enrich-team"))
+}
+
+func TestClient_SimulateIngestPipeline(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_ingest/pipeline/_simulate", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+  "pipeline":{"processors":[{"rename":{"field":"user","target_field":"user.name"}}]},
+  "docs":[{"_source":{"user":"alice"}},{"_source":{}}]
+}`, string(body))
+		return NewMockResponse(200, req, `{"docs":[
+  {"doc":{"_index":"_index","_id":"_id","_source":{"user":{"name":"alice"}}}},
+  {"error":{"root_cause":[{"type":"illegal_argument_exception","reason":"field [user] doesn't exist"}],"type":"illegal_argument_exception","reason":"field [user] doesn't exist"}}
+]}`)
+	})
+	pipeline := map[string]interface{}{
+		"processors": []interface{}{
+			map[string]interface{}{"rename": map[string]interface{}{"field": "user", "target_field": "user.name"}},
+		},
+	}
+	results, err := testClient.SimulateIngestPipeline(context.Background(), pipeline, []map[string]interface{}{{"user": "alice"}, {}})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Nil(t, results[0].Error)
+	require.Equal(t, map[string]interface{}{"user": map[string]interface{}{"name": "alice"}}, results[0].Doc["_source"])
+	require.Equal(t, &SimulatedDocumentError{Type: "illegal_argument_exception", Reason: "field [user] doesn't exist"}, results[1].Error)
+}
@@ -0,0 +1,59 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+const (
+	// endpointRoot is the endpoint group of requests to the root path.
+	endpointRoot = "root"
+	// endpointIndex is the endpoint group of requests to index level APIs, whose path starts with an index name.
+	endpointIndex = "index"
+)
+
+// instrument records the given request and its outcome in the audit log and in the Elasticsearch API metrics.
+func (c *baseClient) instrument(request *http.Request, response *http.Response, err error, duration time.Duration) {
+	c.audit(request, response, err, duration)
+	metrics.ElasticsearchClientRequestDuration.WithLabelValues(
+		c.es.Namespace, c.es.Name, endpointGroup(request.URL.Path), request.Method, responseCode(response, err),
+	).Observe(duration.Seconds())
+}
+
+// endpointGroup returns a low cardinality name for the API targeted by the given path: the first path segment for
+// APIs prefixed with an underscore (eg. _cluster), or a generic group otherwise to not report index names.
+func endpointGroup(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	switch {
+	case segment == "":
+		return endpointRoot
+	case strings.HasPrefix(segment, "_"):
+		return segment
+	default:
+		return endpointIndex
+	}
+}
+
+// responseCode returns the HTTP status code of the given response, or whether the request timed out or failed
+// before a response was received.
+func responseCode(response *http.Response, err error) string {
+	if response != nil {
+		return strconv.Itoa(response.StatusCode)
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return metrics.CodeTimeout
+	}
+	return metrics.CodeError
+}
@@ -0,0 +1,66 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+func Test_endpointGroup(t *testing.T) {
+	for path, expected := range map[string]string{
+		"":                          "root",
+		"/":                         "root",
+		"/_cluster/health":          "_cluster",
+		"/_nodes/_local/shutdown":   "_nodes",
+		"/_security/role_mapping/x": "_security",
+		"/my-index/_settings":       "index",
+	} {
+		assert.Equal(t, expected, endpointGroup(path), path)
+	}
+}
+
+func Test_responseCode(t *testing.T) {
+	assert.Equal(t, "503", responseCode(&http.Response{StatusCode: 503}, errors.New("unavailable")))
+	assert.Equal(t, "timeout", responseCode(nil, newDecoratedHTTPError(&http.Request{URL: nil}, context.DeadlineExceeded)))
+	assert.Equal(t, "error", responseCode(nil, errors.New("connection refused")))
+}
+
+func TestClientMetrics(t *testing.T) {
+	es := types.NamespacedName{Namespace: "ns", Name: "metrics-test"}
+	defer metrics.DeleteElasticsearchClientMetrics(es)
+	initial := testutil.CollectAndCount(metrics.ElasticsearchClientRequestDuration)
+
+	statusCode := http.StatusOK
+	c := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(statusCode, req, `{}`)
+	})
+	c.(*clientV8).es = clusterKey{NamespacedName: es}
+
+	_, err := c.GetClusterHealth(context.Background())
+	require.NoError(t, err)
+	_, err = c.GetClusterHealth(context.Background())
+	require.NoError(t, err)
+	statusCode = http.StatusInternalServerError
+	_, err = c.GetClusterHealth(context.Background())
+	require.Error(t, err)
+
+	// one series per response code
+	assert.Equal(t, initial+2, testutil.CollectAndCount(metrics.ElasticsearchClientRequestDuration))
+
+	// series are removed with the cluster
+	metrics.DeleteElasticsearchClientMetrics(es)
+	assert.Equal(t, initial, testutil.CollectAndCount(metrics.ElasticsearchClientRequestDuration))
+}
@@ -7,6 +7,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -109,10 +110,47 @@ type NodesStats struct {
 
 // NodeStats partially models an Elasticsearch node retrieved from /_nodes/stats
 type NodeStats struct {
-	Name string `json:"name"`
-	OS   struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+	OS    struct {
 		CGroup *CGroup `json:"cgroup"`
 	} `json:"os"`
+	FS struct {
+		Total struct {
+			TotalInBytes     int64 `json:"total_in_bytes"`
+			AvailableInBytes int64 `json:"available_in_bytes"`
+		} `json:"total"`
+	} `json:"fs"`
+}
+
+// NodesMetrics models the response from a request to /_nodes/stats, keeping the raw statistics of each node.
+type NodesMetrics struct {
+	Nodes map[string]NodeMetrics `json:"nodes"`
+}
+
+// NodeMetrics holds the raw statistics of an Elasticsearch node retrieved from /_nodes/stats.
+type NodeMetrics map[string]interface{}
+
+// Name returns the name of the node.
+func (m NodeMetrics) Name() string {
+	name, _ := m["name"].(string)
+	return name
+}
+
+// Value returns the numeric value at the given dot-separated path, and false if there is no numeric value at this path.
+func (m NodeMetrics) Value(path string) (float64, bool) {
+	var current interface{} = map[string]interface{}(m)
+	for _, key := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return 0, false
+		}
+		if current, ok = object[key]; !ok {
+			return 0, false
+		}
+	}
+	value, ok := current.(float64)
+	return value, ok
 }
 
 type CGroup struct {
@@ -149,6 +187,17 @@ type Shard struct {
 	State    ShardState `json:"state"`
 	NodeName string     `json:"node"`
 	Type     ShardType  `json:"prirep"`
+	// Store is the size of the shard in bytes, empty if the shard is not allocated.
+	Store string `json:"store"`
+}
+
+// StoreBytes returns the size of the shard in bytes, 0 if unknown.
+func (s Shard) StoreBytes() int64 {
+	size, err := strconv.ParseInt(s.Store, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return size
 }
 
 type RoutingTable struct {
@@ -168,6 +217,19 @@ func (s Shards) GetShardsByNode() map[string]Shards {
 	return result
 }
 
+// OnNode returns the number of shards allocated to, or relocating from, the given node, and their total size in bytes.
+func (s Shards) OnNode(nodeName string) (int, int64) {
+	var count int
+	var size int64
+	for _, shard := range s {
+		if shard.NodeName == nodeName {
+			count++
+			size += shard.StoreBytes()
+		}
+	}
+	return count, size
+}
+
 // Strip extra information from the nodeName field
 // eg. "cluster-node-2 -> 10.56.2.33 8DqGuLtrSNyMfE2EfKNDgg" becomes "cluster-node-2"
 // see https://github.com/elastic/cloud-on-k8s/issues/1796
@@ -248,6 +310,24 @@ type ClusterRoutingAllocation struct {
 	Transient AllocationSettings `json:"transient,omitempty"`
 }
 
+// ClusterSettingsWithDefaults models the cluster settings in flat format, including the default values.
+type ClusterSettingsWithDefaults struct {
+	Transient  map[string]interface{} `json:"transient"`
+	Persistent map[string]interface{} `json:"persistent"`
+	Defaults   map[string]interface{} `json:"defaults"`
+}
+
+// Get returns the effective value of the given string setting: transient settings take precedence over persistent
+// settings, which take precedence over the default values.
+func (s ClusterSettingsWithDefaults) Get(key string) (string, bool) {
+	for _, settings := range []map[string]interface{}{s.Transient, s.Persistent, s.Defaults} {
+		if value, ok := settings[key].(string); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
 // DiscoveryZen set minimum number of master eligible nodes that must be visible to form a cluster.
 type DiscoveryZen struct {
 	MinimumMasterNodes int `json:"discovery.zen.minimum_master_nodes"`
@@ -277,6 +357,23 @@ type ErrorResponse struct {
 	} `json:"error"`
 }
 
+// ReloadSecureSettingsRequest is the request of the _nodes/reload_secure_settings API for a password protected keystore.
+type ReloadSecureSettingsRequest struct {
+	SecureSettingsPassword string `json:"secure_settings_password"`
+}
+
+// ReloadSecureSettingsResponse is the response of the _nodes/reload_secure_settings API.
+type ReloadSecureSettingsResponse struct {
+	Nodes map[string]struct {
+		Name string `json:"name"`
+		// ReloadException is set if the node failed to reload its secure settings.
+		ReloadException *struct {
+			Reason string `json:"reason"`
+			Type   string `json:"type"`
+		} `json:"reload_exception,omitempty"`
+	} `json:"nodes"`
+}
+
 // ElasticsearchLicenseType the type of a license.
 type ElasticsearchLicenseType string
 
@@ -393,9 +490,36 @@ type RemoteClusters struct {
 	RemoteClusters map[string]RemoteCluster `json:"remote,omitempty"`
 }
 
-// RemoteCluster is the set of seeds to use in a remote cluster setting.
+// RemoteCluster is the set of seeds to use in a remote cluster setting, along with the optional settings of the
+// remote cluster.
 type RemoteCluster struct {
 	Seeds []string `json:"seeds"`
+	// RemoteClusterOptions are omitted if nil, otherwise the options which are not set are reset to their default value.
+	*RemoteClusterOptions
+}
+
+// RemoteClusterOptions are the optional settings of a remote cluster, supported from Elasticsearch 7.7.0.
+// Numbers and booleans are encoded as strings, as returned by the cluster settings API.
+type RemoteClusterOptions struct {
+	Mode                   *string `json:"mode"`
+	ProxyAddress           *string `json:"proxy_address"`
+	ServerName             *string `json:"server_name"`
+	ProxySocketConnections *int32  `json:"proxy_socket_connections,string"`
+	SkipUnavailable        *bool   `json:"skip_unavailable,string"`
+}
+
+// RemoteClustersInfo is the connection state of the remote clusters, indexed by alias, as returned by the remote
+// cluster info API.
+type RemoteClustersInfo map[string]RemoteClusterInfo
+
+// RemoteClusterInfo is the connection state of a remote cluster.
+type RemoteClusterInfo struct {
+	Connected bool   `json:"connected"`
+	Mode      string `json:"mode,omitempty"`
+	// NumNodesConnected is the number of nodes of the remote cluster connected to in sniff mode.
+	NumNodesConnected int `json:"num_nodes_connected,omitempty"`
+	// NumProxySocketsConnected is the number of sockets opened to the remote cluster in proxy mode.
+	NumProxySocketsConnected int `json:"num_proxy_sockets_connected,omitempty"`
 }
 
 // Hit represents a single search hit.
@@ -490,9 +614,9 @@ func (ns NodeShutdown) Is(t ShutdownType) bool {
 
 // ShutdownRequest is the body of a node shutdown request.
 type ShutdownRequest struct {
-	Type            ShutdownType  `json:"type"`
-	Reason          string        `json:"reason"`
-	AllocationDelay time.Duration `json:"allocation_delay,omitempty"`
+	Type            ShutdownType `json:"type"`
+	Reason          string       `json:"reason"`
+	AllocationDelay string       `json:"allocation_delay,omitempty"`
 }
 
 // ShutdownResponse is the response wrapper for retrieving the status of ongoing node shutdowns from Elasticsearch.
@@ -500,6 +624,14 @@ type ShutdownResponse struct {
 	Nodes []NodeShutdown `json:"nodes"`
 }
 
+// ClusterStateVersion identifies a version of the cluster state, which changes every time the cluster state is updated,
+// for example when a node joins or leaves the cluster or when shards are allocated.
+type ClusterStateVersion struct {
+	ClusterUUID string `json:"cluster_uuid"`
+	StateUUID   string `json:"state_uuid"`
+	Version     int64  `json:"version"`
+}
+
 // ClusterState models the internal representation of the cluster state
 type ClusterState struct {
 	Metadata Metadata `json:"metadata"`
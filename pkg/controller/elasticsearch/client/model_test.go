@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
 )
 
 func TestModel_RemoteCluster(t *testing.T) {
@@ -48,6 +49,26 @@ func TestModel_RemoteCluster(t *testing.T) {
 			},
 			want: `{"persistent":{"cluster":{"remote":{"leader":{"seeds":null}}}}}`,
 		},
+		{
+			name: "Remote cluster in proxy mode",
+			arg: RemoteClustersSettings{
+				PersistentSettings: &SettingsGroup{
+					Cluster: RemoteClusters{
+						RemoteClusters: map[string]RemoteCluster{
+							"leader": {
+								RemoteClusterOptions: &RemoteClusterOptions{
+									Mode:                   ptr.To("proxy"),
+									ProxyAddress:           ptr.To("leader.example.com:9400"),
+									ProxySocketConnections: ptr.To[int32](18),
+									SkipUnavailable:        ptr.To(true),
+								},
+							},
+						},
+					},
+				},
+			},
+			want: `{"persistent":{"cluster":{"remote":{"leader":{"seeds":null,"mode":"proxy","proxy_address":"leader.example.com:9400","server_name":null,"proxy_socket_connections":"18","skip_unavailable":"true"}}}}}`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -58,6 +79,25 @@ func TestModel_RemoteCluster(t *testing.T) {
 	}
 }
 
+func TestModel_RemoteClusterSettingsResponse(t *testing.T) {
+	// values are returned as strings by the cluster settings API
+	response := `{"persistent":{"cluster":{"remote":{
+		"leader":{"seeds":["127.0.0.1:9300"]},
+		"proxied":{"mode":"proxy","proxy_address":"leader.example.com:9400","proxy_socket_connections":"18","skip_unavailable":"true"}
+	}}}}`
+	var settings RemoteClustersSettings
+	require.NoError(t, json.Unmarshal([]byte(response), &settings))
+	require.Equal(t, map[string]RemoteCluster{
+		"leader": {Seeds: []string{"127.0.0.1:9300"}},
+		"proxied": {RemoteClusterOptions: &RemoteClusterOptions{
+			Mode:                   ptr.To("proxy"),
+			ProxyAddress:           ptr.To("leader.example.com:9400"),
+			ProxySocketConnections: ptr.To[int32](18),
+			SkipUnavailable:        ptr.To(true),
+		}},
+	}, settings.PersistentSettings.Cluster.RemoteClusters)
+}
+
 func TestModel_License(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -208,3 +248,48 @@ func TestShutdownResponse(t *testing.T) {
 	require.NoError(t, json.Unmarshal([]byte(nodeShudownSample), &actual))
 	require.Equal(t, expected, actual)
 }
+
+func TestNodeMetrics_Value(t *testing.T) {
+	var metrics NodeMetrics
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"name": "es-es-default-0",
+		"thread_pool": {"write": {"queue": 3, "rejected": 120}},
+		"indices": {"search": {"query_total": 10}}
+	}`), &metrics))
+	tests := []struct {
+		path      string
+		wantValue float64
+		wantOk    bool
+	}{
+		{path: "thread_pool.write.rejected", wantValue: 120, wantOk: true},
+		{path: "indices.search.query_total", wantValue: 10, wantOk: true},
+		{path: "thread_pool.write", wantOk: false},
+		{path: "thread_pool.search.queue", wantOk: false},
+		{path: "name", wantOk: false},
+		{path: "name.first", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, ok := metrics.Value(tt.path)
+			require.Equal(t, tt.wantOk, ok)
+			require.Equal(t, tt.wantValue, value)
+		})
+	}
+	require.Equal(t, "es-es-default-0", metrics.Name())
+}
+
+func TestShards_OnNode(t *testing.T) {
+	var shards Shards
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"index": "a", "shard": "0", "state": "STARTED", "prirep": "p", "node": "node-1", "store": "1024"},
+		{"index": "a", "shard": "1", "state": "RELOCATING", "prirep": "p", "node": "node-1 -> 10.56.2.33 8DqGuLtrSNyMfE2EfKNDgg node-2", "store": "2048"},
+		{"index": "a", "shard": "2", "state": "STARTED", "prirep": "r", "node": "node-2", "store": "4096"},
+		{"index": "a", "shard": "3", "state": "UNASSIGNED", "prirep": "r", "node": null, "store": null}
+	]`), &shards))
+	count, size := shards.OnNode("node-1")
+	require.Equal(t, 2, count)
+	require.Equal(t, int64(3072), size)
+	count, size = shards.OnNode("node-3")
+	require.Equal(t, 0, count)
+	require.Equal(t, int64(0), size)
+}
@@ -0,0 +1,40 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type NativeRoleClient interface {
+	// GetNativeRole returns the native realm role of the given name.
+	GetNativeRole(ctx context.Context, name string) (Role, error)
+	// UpdateNativeRole creates or updates a native realm role.
+	UpdateNativeRole(ctx context.Context, name string, role Role) error
+	// DeleteNativeRole deletes a native realm role.
+	DeleteNativeRole(ctx context.Context, name string) error
+}
+
+func (c *baseClient) GetNativeRole(ctx context.Context, name string) (Role, error) {
+	var roles map[string]Role
+	if err := c.get(ctx, "/_security/role/"+url.PathEscape(name), &roles); err != nil {
+		return Role{}, err
+	}
+	role, exists := roles[name]
+	if !exists {
+		return Role{}, fmt.Errorf("role %s not found in the response", name)
+	}
+	return role, nil
+}
+
+func (c *baseClient) UpdateNativeRole(ctx context.Context, name string, role Role) error {
+	return c.put(ctx, "/_security/role/"+url.PathEscape(name), role, nil)
+}
+
+func (c *baseClient) DeleteNativeRole(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_security/role/"+url.PathEscape(name))
+}
@@ -0,0 +1,88 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetNativeRole(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_security/role/logs_reader", req.URL.Path)
+		return NewMockResponse(200, req, `{
+  "logs_reader": {
+    "cluster": ["monitor"],
+    "indices": [
+      {
+        "names": ["logs-*"],
+        "privileges": ["read"],
+        "field_security": {"grant": ["*"], "except": ["secret"]},
+        "query": "{\"term\":{\"team\":\"a\"}}",
+        "allow_restricted_indices": false
+      }
+    ],
+    "applications": [],
+    "run_as": [],
+    "metadata": {"team": "a"},
+    "transient_metadata": {"enabled": true}
+  }
+}`)
+	})
+	role, err := testClient.GetNativeRole(context.Background(), "logs_reader")
+	require.NoError(t, err)
+	require.Equal(t, Role{
+		Cluster: []string{"monitor"},
+		Indices: []IndexRole{{
+			Names:                  []string{"logs-*"},
+			Privileges:             []string{"read"},
+			FieldSecurity:          &FieldSecurity{Grant: []string{"*"}, Except: []string{"secret"}},
+			Query:                  `{"term":{"team":"a"}}`,
+			AllowRestrictedIndices: ptr.To(false),
+		}},
+		Applications: []ApplicationRole{},
+		RunAs:        []string{},
+		Metadata:     map[string]any{"team": "a"},
+	}, role)
+
+	testClient = NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(404, req, `{}`)
+	})
+	_, err = testClient.GetNativeRole(context.Background(), "logs_reader")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_UpdateNativeRole(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_security/role/logs_reader", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"cluster":["monitor"],"indices":[{"names":["logs-*"],"privileges":["read"],"query":"{}"}]}`, string(body))
+		return NewMockResponse(200, req, `{"role":{"created":true}}`)
+	})
+	role := Role{
+		Cluster: []string{"monitor"},
+		Indices: []IndexRole{{Names: []string{"logs-*"}, Privileges: []string{"read"}, Query: "{}"}},
+	}
+	require.NoError(t, testClient.UpdateNativeRole(context.Background(), "logs_reader", role))
+}
+
+func TestClient_DeleteNativeRole(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_security/role/logs_reader", req.URL.Path)
+		return NewMockResponse(200, req, `{"found":true}`)
+	})
+	require.NoError(t, testClient.DeleteNativeRole(context.Background(), "logs_reader"))
+}
@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// NativeUser is a user of the native realm as expected by the /_security/user API. The password is never returned
+// by Elasticsearch.
+type NativeUser struct {
+	Password string                 `json:"password,omitempty"`
+	Roles    []string               `json:"roles"`
+	FullName string                 `json:"full_name,omitempty"`
+	Email    string                 `json:"email,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Enabled  bool                   `json:"enabled"`
+}
+
+type NativeUserClient interface {
+	// GetNativeUser returns the native realm user of the given name.
+	GetNativeUser(ctx context.Context, username string) (NativeUser, error)
+	// UpdateNativeUser creates or updates a native realm user. The password of an existing user is left unchanged
+	// if it is not set.
+	UpdateNativeUser(ctx context.Context, username string, user NativeUser) error
+	// DeleteNativeUser deletes a native realm user.
+	DeleteNativeUser(ctx context.Context, username string) error
+}
+
+func (c *baseClient) GetNativeUser(ctx context.Context, username string) (NativeUser, error) {
+	var users map[string]NativeUser
+	if err := c.get(ctx, "/_security/user/"+url.PathEscape(username), &users); err != nil {
+		return NativeUser{}, err
+	}
+	user, exists := users[username]
+	if !exists {
+		return NativeUser{}, fmt.Errorf("user %s not found in the response", username)
+	}
+	return user, nil
+}
+
+func (c *baseClient) UpdateNativeUser(ctx context.Context, username string, user NativeUser) error {
+	return c.put(ctx, "/_security/user/"+url.PathEscape(username), user, nil)
+}
+
+func (c *baseClient) DeleteNativeUser(ctx context.Context, username string) error {
+	return c.delete(ctx, "/_security/user/"+url.PathEscape(username))
+}
@@ -0,0 +1,59 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetNativeUser(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_security/user/app", req.URL.Path)
+		return NewMockResponse(200, req, `{"app":{"username":"app","roles":["viewer"],"full_name":null,"email":"app@example.com","metadata":{"team":"a"},"enabled":true}}`)
+	})
+	user, err := testClient.GetNativeUser(context.Background(), "app")
+	require.NoError(t, err)
+	require.Equal(t, NativeUser{
+		Roles:    []string{"viewer"},
+		Email:    "app@example.com",
+		Metadata: map[string]interface{}{"team": "a"},
+		Enabled:  true,
+	}, user)
+
+	testClient = NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(404, req, `{}`)
+	})
+	_, err = testClient.GetNativeUser(context.Background(), "app")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_UpdateNativeUser(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_security/user/app", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"password":"secret","roles":["viewer"],"enabled":true}`, string(body))
+		return NewMockResponse(200, req, `{"created":true}`)
+	})
+	require.NoError(t, testClient.UpdateNativeUser(context.Background(), "app", NativeUser{Password: "secret", Roles: []string{"viewer"}, Enabled: true}))
+}
+
+func TestClient_DeleteNativeUser(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_security/user/app", req.URL.Path)
+		return NewMockResponse(200, req, `{"found":true}`)
+	})
+	require.NoError(t, testClient.DeleteNativeUser(context.Background(), "app"))
+}
@@ -0,0 +1,146 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+// RateLimitSettings limits the rate of the requests made to the Elasticsearch API, to each cluster and to all of them.
+type RateLimitSettings struct {
+	// QPS is the maximum average number of requests per second to each cluster, zero meaning no limit.
+	QPS float64
+	// Burst is the maximum number of requests to each cluster sent at once above QPS, twice QPS if zero.
+	Burst int
+	// GlobalQPS is the maximum average number of requests per second to all the clusters, zero meaning no limit.
+	GlobalQPS float64
+	// GlobalBurst is the maximum number of requests to all the clusters sent at once above GlobalQPS, twice GlobalQPS
+	// if zero.
+	GlobalBurst int
+}
+
+// rateLimiters holds the rate limiters of the requests to the managed clusters.
+var rateLimiters = newRateLimiterCache(RateLimitSettings{})
+
+// SetRateLimitSettings sets the rate limits of the requests to the managed clusters. It can be updated while the
+// operator is running.
+func SetRateLimitSettings(settings RateLimitSettings) {
+	rateLimiters.setSettings(settings)
+}
+
+type rateLimiterCache struct {
+	lock     sync.Mutex
+	settings RateLimitSettings
+	global   *rate.Limiter
+	clusters map[clusterKey]*rate.Limiter
+}
+
+func newRateLimiterCache(settings RateLimitSettings) *rateLimiterCache {
+	c := &rateLimiterCache{}
+	c.setSettings(settings)
+	return c
+}
+
+func (c *rateLimiterCache) setSettings(settings RateLimitSettings) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.settings = settings
+	c.global = newLimiter(settings.GlobalQPS, settings.GlobalBurst)
+	// the limiters of the clusters are created again with the new settings when they are next used
+	c.clusters = make(map[clusterKey]*rate.Limiter)
+}
+
+// get returns the limiters of the requests to the given cluster and to all the clusters, nil if there is no limit.
+func (c *rateLimiterCache) get(es clusterKey) (*rate.Limiter, *rate.Limiter) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.settings.QPS <= 0 {
+		return nil, c.global
+	}
+	limiter, exists := c.clusters[es]
+	if !exists {
+		limiter = newLimiter(c.settings.QPS, c.settings.Burst)
+		c.clusters[es] = limiter
+	}
+	return limiter, c.global
+}
+
+func (c *rateLimiterCache) forget(es clusterKey) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.clusters, es)
+}
+
+// wait blocks until a request to the given cluster is allowed by the rate limits or the context is done, and records
+// the requests which were delayed.
+func (c *rateLimiterCache) wait(ctx context.Context, es clusterKey) error {
+	cluster, global := c.get(es)
+	waited, err := reserve(ctx, cluster, global)
+	if err != nil {
+		return err
+	}
+	if waited > 0 {
+		metrics.ElasticsearchClientThrottledRequests.WithLabelValues(es.Namespace, es.Name).Inc()
+		metrics.ElasticsearchClientThrottledSeconds.WithLabelValues(es.Namespace, es.Name).Add(waited.Seconds())
+	}
+	return nil
+}
+
+// reserve waits for all the given limiters to allow a request, and returns how long it waited. A request is reserved
+// on all the limiters before waiting for the longest delay, so that the reservations are all cancelled and no request
+// is lost if the context is done first.
+func reserve(ctx context.Context, limiters ...*rate.Limiter) (time.Duration, error) {
+	now := time.Now()
+	var reservations []*rate.Reservation
+	var delay time.Duration
+	for _, limiter := range limiters {
+		if limiter == nil {
+			continue
+		}
+		reservation := limiter.ReserveN(now, 1)
+		reservations = append(reservations, reservation)
+		delay = max(delay, reservation.DelayFrom(now))
+	}
+	cancel := func() {
+		// cancel as of the time of the reservations, to also give back the requests which were allowed immediately
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+	}
+	if delay == 0 {
+		return 0, nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		// the request would time out before being sent
+		cancel()
+		return 0, context.DeadlineExceeded
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		cancel()
+		return 0, ctx.Err()
+	}
+}
+
+func newLimiter(qps float64, burst int) *rate.Limiter {
+	if qps <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(2 * qps))
+	}
+	return rate.NewLimiter(rate.Limit(qps), burst)
+}
@@ -0,0 +1,83 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+func Test_rateLimiterCache_get(t *testing.T) {
+	es := clusterKey{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "es"}}
+	c := newRateLimiterCache(RateLimitSettings{})
+	cluster, global := c.get(es)
+	assert.Nil(t, cluster)
+	assert.Nil(t, global)
+
+	c.setSettings(RateLimitSettings{QPS: 2.5, GlobalQPS: 100, GlobalBurst: 10})
+	cluster, global = c.get(es)
+	require.NotNil(t, cluster)
+	require.NotNil(t, global)
+	// burst defaults to twice the QPS
+	assert.Equal(t, 5, cluster.Burst())
+	assert.Equal(t, 10, global.Burst())
+	// the limiter of a cluster is kept between requests
+	again, _ := c.get(es)
+	assert.Same(t, cluster, again)
+	other, _ := c.get(clusterKey{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "other"}})
+	assert.NotSame(t, cluster, other)
+	// clusters of different Kubernetes clusters have their own limiter
+	remote, _ := c.get(clusterKey{k8sCluster: "remote", NamespacedName: es.NamespacedName})
+	assert.NotSame(t, cluster, remote)
+
+	c.forget(es)
+	again, _ = c.get(es)
+	assert.NotSame(t, cluster, again)
+}
+
+func Test_rateLimiterCache_wait(t *testing.T) {
+	es := clusterKey{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "throttled"}}
+	defer metrics.DeleteElasticsearchClientMetrics(es.NamespacedName)
+	c := newRateLimiterCache(RateLimitSettings{QPS: 20, Burst: 1})
+
+	// the first request is within the burst
+	require.NoError(t, c.wait(context.Background(), es))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.ElasticsearchClientThrottledRequests.WithLabelValues(es.Namespace, es.Name)))
+
+	// the second one is delayed
+	start := time.Now()
+	require.NoError(t, c.wait(context.Background(), es))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ElasticsearchClientThrottledRequests.WithLabelValues(es.Namespace, es.Name)))
+	assert.Greater(t, testutil.ToFloat64(metrics.ElasticsearchClientThrottledSeconds.WithLabelValues(es.Namespace, es.Name)), float64(0))
+
+	// the third one would time out before being allowed
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, c.wait(ctx, es), context.DeadlineExceeded)
+}
+
+func Test_reserve(t *testing.T) {
+	cluster := rate.NewLimiter(1, 1)
+	global := rate.NewLimiter(1, 1)
+	require.True(t, global.Allow())
+
+	// the request to the cluster is not allowed by the global limit before the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err := reserve(ctx, cluster, nil, global)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	// the request reserved on the cluster limiter is given back
+	assert.True(t, cluster.Allow())
+}
@@ -0,0 +1,56 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// RoleMapping is a role mapping as expected by the /_security/role_mapping API.
+type RoleMapping struct {
+	Enabled       bool                   `json:"enabled"`
+	Roles         []string               `json:"roles,omitempty"`
+	RoleTemplates []RoleTemplate         `json:"role_templates,omitempty"`
+	Rules         map[string]interface{} `json:"rules"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// RoleTemplate is a template rendered into role names, as expected in the role_templates field of a role mapping.
+// Elasticsearch returns the template as a JSON string, for example {"source":"{{#tojson}}groups{{/tojson}}"}.
+type RoleTemplate struct {
+	Template string `json:"template"`
+	Format   string `json:"format"`
+}
+
+type RoleMappingClient interface {
+	// GetRoleMapping returns the role mapping of the given name.
+	GetRoleMapping(ctx context.Context, name string) (RoleMapping, error)
+	// UpdateRoleMapping creates or updates a role mapping.
+	UpdateRoleMapping(ctx context.Context, name string, mapping RoleMapping) error
+	// DeleteRoleMapping deletes a role mapping.
+	DeleteRoleMapping(ctx context.Context, name string) error
+}
+
+func (c *baseClient) GetRoleMapping(ctx context.Context, name string) (RoleMapping, error) {
+	var mappings map[string]RoleMapping
+	if err := c.get(ctx, "/_security/role_mapping/"+url.PathEscape(name), &mappings); err != nil {
+		return RoleMapping{}, err
+	}
+	mapping, exists := mappings[name]
+	if !exists {
+		return RoleMapping{}, fmt.Errorf("role mapping %s not found in the response", name)
+	}
+	return mapping, nil
+}
+
+func (c *baseClient) UpdateRoleMapping(ctx context.Context, name string, mapping RoleMapping) error {
+	return c.put(ctx, "/_security/role_mapping/"+url.PathEscape(name), mapping, nil)
+}
+
+func (c *baseClient) DeleteRoleMapping(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_security/role_mapping/"+url.PathEscape(name))
+}
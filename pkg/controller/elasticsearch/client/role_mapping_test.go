@@ -0,0 +1,71 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetRoleMapping(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_security/role_mapping/admins", req.URL.Path)
+		return NewMockResponse(200, req, `{"admins":{"enabled":true,"roles":["superuser"],"rules":{"field":{"groups":"admins"}},"metadata":{}}}`)
+	})
+	mapping, err := testClient.GetRoleMapping(context.Background(), "admins")
+	require.NoError(t, err)
+	require.Equal(t, RoleMapping{
+		Enabled:  true,
+		Roles:    []string{"superuser"},
+		Rules:    map[string]interface{}{"field": map[string]interface{}{"groups": "admins"}},
+		Metadata: map[string]interface{}{},
+	}, mapping)
+
+	testClient = NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(200, req, `{"groups":{"enabled":true,"role_templates":[{"template":"{\"source\":\"{{#tojson}}groups{{/tojson}}\"}","format":"json"}],"rules":{"field":{"realm.name":"oidc1"}},"metadata":{}}}`)
+	})
+	mapping, err = testClient.GetRoleMapping(context.Background(), "groups")
+	require.NoError(t, err)
+	require.Equal(t, []RoleTemplate{{Template: `{"source":"{{#tojson}}groups{{/tojson}}"}`, Format: "json"}}, mapping.RoleTemplates)
+
+	testClient = NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(404, req, `{}`)
+	})
+	_, err = testClient.GetRoleMapping(context.Background(), "admins")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_UpdateRoleMapping(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_security/role_mapping/admins", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"enabled":true,"roles":["superuser"],"rules":{"field":{"groups":"admins"}}}`, string(body))
+		return NewMockResponse(200, req, `{"role_mapping":{"created":true}}`)
+	})
+	mapping := RoleMapping{
+		Enabled: true,
+		Roles:   []string{"superuser"},
+		Rules:   map[string]interface{}{"field": map[string]interface{}{"groups": "admins"}},
+	}
+	require.NoError(t, testClient.UpdateRoleMapping(context.Background(), "admins", mapping))
+}
+
+func TestClient_DeleteRoleMapping(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_security/role_mapping/admins", req.URL.Path)
+		return NewMockResponse(200, req, `{"found":true}`)
+	})
+	require.NoError(t, testClient.DeleteRoleMapping(context.Background(), "admins"))
+}
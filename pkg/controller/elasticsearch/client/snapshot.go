@@ -0,0 +1,114 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// SnapshotSuccessState is the state of a snapshot that completed successfully.
+	SnapshotSuccessState = "SUCCESS"
+	// SnapshotInProgressState is the state of a snapshot that is not completed yet.
+	SnapshotInProgressState = "IN_PROGRESS"
+)
+
+// Snapshot is a snapshot as returned by the /_snapshot/<repository>/<snapshot> API.
+type Snapshot struct {
+	Snapshot string   `json:"snapshot"`
+	State    string   `json:"state"`
+	Indices  []string `json:"indices,omitempty"`
+	// StartTimeMillis is the time at which the snapshot started in milliseconds since epoch.
+	StartTimeMillis int64 `json:"start_time_in_millis"`
+}
+
+// Snapshots is the response of the /_snapshot/<repository>/<snapshot> API.
+type Snapshots struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+// SnapshotCreateRequest holds the options of a snapshot, as expected by the /_snapshot/<repository>/<snapshot> API.
+type SnapshotCreateRequest struct {
+	Indices            []string `json:"indices,omitempty"`
+	IncludeGlobalState bool     `json:"include_global_state"`
+}
+
+// SnapshotRestoreRequest holds the options of a snapshot restore, as expected by the
+// /_snapshot/<repository>/<snapshot>/_restore API.
+type SnapshotRestoreRequest struct {
+	Indices            []string `json:"indices,omitempty"`
+	RenamePattern      string   `json:"rename_pattern,omitempty"`
+	RenameReplacement  string   `json:"rename_replacement,omitempty"`
+	IncludeGlobalState bool     `json:"include_global_state"`
+}
+
+// SnapshotRestoreInProgress is a snapshot restore in progress, as reported in the cluster state.
+type SnapshotRestoreInProgress struct {
+	Snapshot   string `json:"snapshot"`
+	Repository string `json:"repository"`
+	State      string `json:"state"`
+}
+
+// SnapshotRestores is the restore section of the cluster state.
+type SnapshotRestores struct {
+	Restore struct {
+		Snapshots []SnapshotRestoreInProgress `json:"snapshots"`
+	} `json:"restore"`
+}
+
+// LatestSuccessfulSnapshot returns the name of the most recent successful snapshot.
+func LatestSuccessfulSnapshot(snapshots []Snapshot) (string, bool) {
+	var latest *Snapshot
+	for i, snapshot := range snapshots {
+		if snapshot.State != SnapshotSuccessState {
+			continue
+		}
+		if latest == nil || snapshot.StartTimeMillis > latest.StartTimeMillis {
+			latest = &snapshots[i]
+		}
+	}
+	if latest == nil {
+		return "", false
+	}
+	return latest.Snapshot, true
+}
+
+type SnapshotClient interface {
+	// GetSnapshots returns the snapshots of the given repository whose name matches the given pattern.
+	GetSnapshots(ctx context.Context, repository, pattern string) ([]Snapshot, error)
+	// CreateSnapshot starts a snapshot, without waiting for its completion.
+	CreateSnapshot(ctx context.Context, repository, snapshot string, request SnapshotCreateRequest) error
+	// RestoreSnapshot starts the restore of a snapshot, without waiting for its completion.
+	RestoreSnapshot(ctx context.Context, repository, snapshot string, request SnapshotRestoreRequest) error
+	// GetSnapshotRestoresInProgress returns the snapshot restores currently in progress.
+	GetSnapshotRestoresInProgress(ctx context.Context) ([]SnapshotRestoreInProgress, error)
+}
+
+func (c *baseClient) GetSnapshots(ctx context.Context, repository, pattern string) ([]Snapshot, error) {
+	var snapshots Snapshots
+	// do not fail if no snapshot matches an exact name
+	path := fmt.Sprintf("/_snapshot/%s/%s?ignore_unavailable=true", url.PathEscape(repository), url.PathEscape(pattern))
+	err := c.get(ctx, path, &snapshots)
+	return snapshots.Snapshots, err
+}
+
+func (c *baseClient) CreateSnapshot(ctx context.Context, repository, snapshot string, request SnapshotCreateRequest) error {
+	path := fmt.Sprintf("/_snapshot/%s/%s?wait_for_completion=false", url.PathEscape(repository), url.PathEscape(snapshot))
+	return c.put(ctx, path, request, nil)
+}
+
+func (c *baseClient) RestoreSnapshot(ctx context.Context, repository, snapshot string, request SnapshotRestoreRequest) error {
+	path := fmt.Sprintf("/_snapshot/%s/%s/_restore?wait_for_completion=false", url.PathEscape(repository), url.PathEscape(snapshot))
+	return c.post(ctx, path, request, nil)
+}
+
+func (c *baseClient) GetSnapshotRestoresInProgress(ctx context.Context) ([]SnapshotRestoreInProgress, error) {
+	var restores SnapshotRestores
+	// restores in progress are only exposed in the cluster state, filter it to not retrieve the whole state
+	err := c.get(ctx, "/_cluster/state?filter_path=restore", &restores)
+	return restores.Restore.Snapshots, err
+}
@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// SnapshotLifecyclePolicy is a snapshot lifecycle management (SLM) policy as expected by the /_slm/policy API.
+type SnapshotLifecyclePolicy struct {
+	Schedule   string                            `json:"schedule"`
+	Name       string                            `json:"name"`
+	Repository string                            `json:"repository"`
+	Config     map[string]interface{}            `json:"config,omitempty"`
+	Retention  *SnapshotLifecyclePolicyRetention `json:"retention,omitempty"`
+}
+
+// SnapshotLifecyclePolicyRetention holds the retention rules of an SLM policy.
+type SnapshotLifecyclePolicyRetention struct {
+	ExpireAfter string `json:"expire_after,omitempty"`
+	MinCount    *int32 `json:"min_count,omitempty"`
+	MaxCount    *int32 `json:"max_count,omitempty"`
+}
+
+// SnapshotLifecyclePolicyInfo is an SLM policy as returned by the /_slm/policy API, with the details of its executions.
+type SnapshotLifecyclePolicyInfo struct {
+	Policy              SnapshotLifecyclePolicy `json:"policy"`
+	LastSuccess         *SnapshotInvocation     `json:"last_success,omitempty"`
+	LastFailure         *SnapshotInvocation     `json:"last_failure,omitempty"`
+	NextExecutionMillis int64                   `json:"next_execution_millis"`
+}
+
+// SnapshotInvocation is an execution of an SLM policy.
+type SnapshotInvocation struct {
+	SnapshotName string `json:"snapshot_name"`
+	// TimeMillis is the time of the execution in milliseconds since epoch.
+	TimeMillis int64 `json:"time"`
+	// Details holds the reason of a failed execution.
+	Details string `json:"details,omitempty"`
+}
+
+// SnapshotLifecycleStatus is the status of the snapshot lifecycle management, as returned by the /_slm/status API.
+type SnapshotLifecycleStatus struct {
+	// OperationMode is one of RUNNING, STOPPING or STOPPED.
+	OperationMode string `json:"operation_mode"`
+}
+
+// SnapshotLifecycleRunningMode is the operation mode of a running snapshot lifecycle management.
+const SnapshotLifecycleRunningMode = "RUNNING"
+
+type SnapshotLifecyclePolicyClient interface {
+	// GetSnapshotLifecycleStatus returns the status of the snapshot lifecycle management.
+	GetSnapshotLifecycleStatus(ctx context.Context) (SnapshotLifecycleStatus, error)
+	// GetSnapshotLifecyclePolicies returns all the SLM policies of the cluster, indexed by id.
+	GetSnapshotLifecyclePolicies(ctx context.Context) (map[string]SnapshotLifecyclePolicyInfo, error)
+	// GetSnapshotLifecyclePolicy returns the SLM policy of the given id.
+	GetSnapshotLifecyclePolicy(ctx context.Context, id string) (SnapshotLifecyclePolicyInfo, error)
+	// UpdateSnapshotLifecyclePolicy creates or updates an SLM policy.
+	UpdateSnapshotLifecyclePolicy(ctx context.Context, id string, policy SnapshotLifecyclePolicy) error
+	// DeleteSnapshotLifecyclePolicy deletes an SLM policy. The snapshots it took are not deleted.
+	DeleteSnapshotLifecyclePolicy(ctx context.Context, id string) error
+}
+
+func (c *baseClient) GetSnapshotLifecycleStatus(ctx context.Context) (SnapshotLifecycleStatus, error) {
+	var status SnapshotLifecycleStatus
+	err := c.get(ctx, "/_slm/status", &status)
+	return status, err
+}
+
+func (c *baseClient) GetSnapshotLifecyclePolicies(ctx context.Context) (map[string]SnapshotLifecyclePolicyInfo, error) {
+	var policies map[string]SnapshotLifecyclePolicyInfo
+	err := c.get(ctx, "/_slm/policy", &policies)
+	return policies, err
+}
+
+func (c *baseClient) GetSnapshotLifecyclePolicy(ctx context.Context, id string) (SnapshotLifecyclePolicyInfo, error) {
+	var policies map[string]SnapshotLifecyclePolicyInfo
+	if err := c.get(ctx, "/_slm/policy/"+url.PathEscape(id), &policies); err != nil {
+		return SnapshotLifecyclePolicyInfo{}, err
+	}
+	policy, exists := policies[id]
+	if !exists {
+		return SnapshotLifecyclePolicyInfo{}, fmt.Errorf("snapshot lifecycle policy %s not found in the response", id)
+	}
+	return policy, nil
+}
+
+func (c *baseClient) UpdateSnapshotLifecyclePolicy(ctx context.Context, id string, policy SnapshotLifecyclePolicy) error {
+	return c.put(ctx, "/_slm/policy/"+url.PathEscape(id), policy, nil)
+}
+
+func (c *baseClient) DeleteSnapshotLifecyclePolicy(ctx context.Context, id string) error {
+	return c.delete(ctx, "/_slm/policy/"+url.PathEscape(id))
+}
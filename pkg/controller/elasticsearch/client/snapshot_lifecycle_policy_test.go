@@ -0,0 +1,115 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetSnapshotLifecycleStatus(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_slm/status", req.URL.Path)
+		return NewMockResponse(200, req, `{"operation_mode":"STOPPED"}`)
+	})
+	status, err := testClient.GetSnapshotLifecycleStatus(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, SnapshotLifecycleStatus{OperationMode: "STOPPED"}, status)
+}
+
+func TestClient_GetSnapshotLifecyclePolicies(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_slm/policy", req.URL.Path)
+		return NewMockResponse(200, req, `{"daily":{"policy":{"name":"<daily-{now/d}>","schedule":"0 30 1 * * ?","repository":"backups"},"next_execution_millis":1704245400000}}`)
+	})
+	policies, err := testClient.GetSnapshotLifecyclePolicies(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]SnapshotLifecyclePolicyInfo{
+		"daily": {
+			Policy:              SnapshotLifecyclePolicy{Name: "<daily-{now/d}>", Schedule: "0 30 1 * * ?", Repository: "backups"},
+			NextExecutionMillis: 1704245400000,
+		},
+	}, policies)
+}
+
+func TestClient_GetSnapshotLifecyclePolicy(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_slm/policy/daily", req.URL.Path)
+		return NewMockResponse(200, req, `{
+  "daily": {
+    "version": 2,
+    "modified_date_millis": 1704067200000,
+    "policy": {
+      "name": "<daily-{now/d}>",
+      "schedule": "0 30 1 * * ?",
+      "repository": "backups",
+      "config": {"indices": ["*"]},
+      "retention": {"expire_after": "30d", "min_count": 5}
+    },
+    "last_success": {"snapshot_name": "daily-2024.01.02-abc", "start_time": 1704159000000, "time": 1704159060000},
+    "last_failure": {"snapshot_name": "daily-2024.01.01-def", "time": 1704072660000, "details": "{\"type\":\"snapshot_exception\"}"},
+    "next_execution_millis": 1704245400000,
+    "stats": {"policy": "daily", "snapshots_taken": 1}
+  }
+}`)
+	})
+	policy, err := testClient.GetSnapshotLifecyclePolicy(context.Background(), "daily")
+	require.NoError(t, err)
+	require.Equal(t, SnapshotLifecyclePolicyInfo{
+		Policy: SnapshotLifecyclePolicy{
+			Name:       "<daily-{now/d}>",
+			Schedule:   "0 30 1 * * ?",
+			Repository: "backups",
+			Config:     map[string]interface{}{"indices": []interface{}{"*"}},
+			Retention:  &SnapshotLifecyclePolicyRetention{ExpireAfter: "30d", MinCount: ptr.To[int32](5)},
+		},
+		LastSuccess:         &SnapshotInvocation{SnapshotName: "daily-2024.01.02-abc", TimeMillis: 1704159060000},
+		LastFailure:         &SnapshotInvocation{SnapshotName: "daily-2024.01.01-def", TimeMillis: 1704072660000, Details: `{"type":"snapshot_exception"}`},
+		NextExecutionMillis: 1704245400000,
+	}, policy)
+
+	testClient = NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(404, req, `{"error":{"type":"resource_not_found_exception"},"status":404}`)
+	})
+	_, err = testClient.GetSnapshotLifecyclePolicy(context.Background(), "daily")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_UpdateSnapshotLifecyclePolicy(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_slm/policy/daily", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"<daily-{now/d}>","schedule":"0 30 1 * * ?","repository":"backups","retention":{"max_count":10}}`, string(body))
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	policy := SnapshotLifecyclePolicy{
+		Name:       "<daily-{now/d}>",
+		Schedule:   "0 30 1 * * ?",
+		Repository: "backups",
+		Retention:  &SnapshotLifecyclePolicyRetention{MaxCount: ptr.To[int32](10)},
+	}
+	require.NoError(t, testClient.UpdateSnapshotLifecyclePolicy(context.Background(), "daily", policy))
+}
+
+func TestClient_DeleteSnapshotLifecyclePolicy(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_slm/policy/daily", req.URL.Path)
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, testClient.DeleteSnapshotLifecyclePolicy(context.Background(), "daily"))
+}
@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// SnapshotRepository is a snapshot repository as registered with the /_snapshot API.
+type SnapshotRepository struct {
+	Type     string                 `json:"type"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+type SnapshotRepositoryClient interface {
+	// GetSnapshotRepositories returns all the snapshot repositories registered in the cluster, indexed by name.
+	GetSnapshotRepositories(ctx context.Context) (map[string]SnapshotRepository, error)
+	// GetSnapshotRepository returns the snapshot repository of the given name.
+	GetSnapshotRepository(ctx context.Context, name string) (SnapshotRepository, error)
+	// UpdateSnapshotRepository registers or updates a snapshot repository. If verify is true, the repository is
+	// verified on all the nodes of the cluster before being registered.
+	UpdateSnapshotRepository(ctx context.Context, name string, repository SnapshotRepository, verify bool) error
+	// DeleteSnapshotRepository unregisters a snapshot repository. The snapshots it holds are not deleted.
+	DeleteSnapshotRepository(ctx context.Context, name string) error
+	// VerifySnapshotRepository verifies that a registered snapshot repository is accessible from all the nodes of the cluster.
+	VerifySnapshotRepository(ctx context.Context, name string) error
+}
+
+func (c *baseClient) GetSnapshotRepositories(ctx context.Context) (map[string]SnapshotRepository, error) {
+	var repositories map[string]SnapshotRepository
+	err := c.get(ctx, "/_snapshot", &repositories)
+	return repositories, err
+}
+
+func (c *baseClient) GetSnapshotRepository(ctx context.Context, name string) (SnapshotRepository, error) {
+	var repositories map[string]SnapshotRepository
+	if err := c.get(ctx, "/_snapshot/"+url.PathEscape(name), &repositories); err != nil {
+		return SnapshotRepository{}, err
+	}
+	repository, exists := repositories[name]
+	if !exists {
+		return SnapshotRepository{}, fmt.Errorf("snapshot repository %s not found in the response", name)
+	}
+	return repository, nil
+}
+
+func (c *baseClient) UpdateSnapshotRepository(ctx context.Context, name string, repository SnapshotRepository, verify bool) error {
+	return c.put(ctx, fmt.Sprintf("/_snapshot/%s?verify=%t", url.PathEscape(name), verify), repository, nil)
+}
+
+func (c *baseClient) DeleteSnapshotRepository(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_snapshot/"+url.PathEscape(name))
+}
+
+func (c *baseClient) VerifySnapshotRepository(ctx context.Context, name string) error {
+	return c.post(ctx, fmt.Sprintf("/_snapshot/%s/_verify", url.PathEscape(name)), nil, nil)
+}
@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetSnapshotRepositories(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_snapshot", req.URL.Path)
+		return NewMockResponse(200, req, `{"repo1":{"type":"s3","settings":{"bucket":"my-bucket"}},"repo2":{"type":"fs","settings":{"location":"/backups"}}}`)
+	})
+	repositories, err := testClient.GetSnapshotRepositories(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]SnapshotRepository{
+		"repo1": {Type: "s3", Settings: map[string]interface{}{"bucket": "my-bucket"}},
+		"repo2": {Type: "fs", Settings: map[string]interface{}{"location": "/backups"}},
+	}, repositories)
+}
+
+func TestClient_GetSnapshotRepository(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_snapshot/my-repository", req.URL.Path)
+		return NewMockResponse(200, req, `{"my-repository":{"type":"s3","settings":{"bucket":"my-bucket","compress":"true"}}}`)
+	})
+	repository, err := testClient.GetSnapshotRepository(context.Background(), "my-repository")
+	require.NoError(t, err)
+	require.Equal(t, SnapshotRepository{Type: "s3", Settings: map[string]interface{}{"bucket": "my-bucket", "compress": "true"}}, repository)
+
+	testClient = NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(404, req, `{"error":{"type":"repository_missing_exception"},"status":404}`)
+	})
+	_, err = testClient.GetSnapshotRepository(context.Background(), "my-repository")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_UpdateSnapshotRepository(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_snapshot/my-repository", req.URL.Path)
+		require.Equal(t, "verify=false", req.URL.RawQuery)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"type":"fs","settings":{"location":"/backups"}}`, string(body))
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	repository := SnapshotRepository{Type: "fs", Settings: map[string]interface{}{"location": "/backups"}}
+	require.NoError(t, testClient.UpdateSnapshotRepository(context.Background(), "my-repository", repository, false))
+}
+
+func TestClient_DeleteSnapshotRepository(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_snapshot/my-repository", req.URL.Path)
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, testClient.DeleteSnapshotRepository(context.Background(), "my-repository"))
+}
+
+func TestClient_VerifySnapshotRepository(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_snapshot/my-repository/_verify", req.URL.Path)
+		return NewMockResponse(200, req, `{"nodes":{"node-id":{"name":"es-es-default-0"}}}`)
+	})
+	require.NoError(t, testClient.VerifySnapshotRepository(context.Background(), "my-repository"))
+}
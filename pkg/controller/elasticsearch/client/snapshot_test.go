@@ -0,0 +1,95 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetSnapshots(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_snapshot/backups/nightly-*", req.URL.Path)
+		require.Equal(t, "true", req.URL.Query().Get("ignore_unavailable"))
+		return NewMockResponse(200, req, `{"snapshots":[
+  {"snapshot":"nightly-1","uuid":"a","state":"SUCCESS","indices":["logs"],"start_time_in_millis":1704159000000,"end_time_in_millis":1704159060000},
+  {"snapshot":"nightly-2","uuid":"b","state":"FAILED","indices":[],"start_time_in_millis":1704245400000,"end_time_in_millis":1704245460000}
+],"total":2,"remaining":0}`)
+	})
+	snapshots, err := testClient.GetSnapshots(context.Background(), "backups", "nightly-*")
+	require.NoError(t, err)
+	require.Equal(t, []Snapshot{
+		{Snapshot: "nightly-1", State: "SUCCESS", Indices: []string{"logs"}, StartTimeMillis: 1704159000000},
+		{Snapshot: "nightly-2", State: "FAILED", Indices: []string{}, StartTimeMillis: 1704245400000},
+	}, snapshots)
+}
+
+func TestLatestSuccessfulSnapshot(t *testing.T) {
+	snapshots := []Snapshot{
+		{Snapshot: "nightly-1", State: SnapshotSuccessState, StartTimeMillis: 1704159000000},
+		{Snapshot: "nightly-3", State: "FAILED", StartTimeMillis: 1704331800000},
+		{Snapshot: "nightly-2", State: SnapshotSuccessState, StartTimeMillis: 1704245400000},
+	}
+	latest, found := LatestSuccessfulSnapshot(snapshots)
+	require.True(t, found)
+	require.Equal(t, "nightly-2", latest)
+
+	_, found = LatestSuccessfulSnapshot([]Snapshot{{Snapshot: "nightly-1", State: "PARTIAL"}})
+	require.False(t, found)
+}
+
+func TestClient_CreateSnapshot(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_snapshot/backups/final-1", req.URL.Path)
+		require.Equal(t, "false", req.URL.Query().Get("wait_for_completion"))
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"include_global_state":true}`, string(body))
+		return NewMockResponse(200, req, `{"accepted":true}`)
+	})
+	require.NoError(t, testClient.CreateSnapshot(context.Background(), "backups", "final-1", SnapshotCreateRequest{IncludeGlobalState: true}))
+}
+
+func TestClient_RestoreSnapshot(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_snapshot/backups/nightly-1/_restore", req.URL.Path)
+		require.Equal(t, "false", req.URL.Query().Get("wait_for_completion"))
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"indices":["logs-*"],"rename_pattern":"(.+)","rename_replacement":"restored-$1","include_global_state":false}`, string(body))
+		return NewMockResponse(200, req, `{"accepted":true}`)
+	})
+	request := SnapshotRestoreRequest{Indices: []string{"logs-*"}, RenamePattern: "(.+)", RenameReplacement: "restored-$1"}
+	require.NoError(t, testClient.RestoreSnapshot(context.Background(), "backups", "nightly-1", request))
+}
+
+func TestClient_GetSnapshotRestoresInProgress(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_cluster/state", req.URL.Path)
+		require.Equal(t, "restore", req.URL.Query().Get("filter_path"))
+		return NewMockResponse(200, req, `{"restore":{"snapshots":[{"snapshot":"nightly-1","repository":"backups","uuid":"a","state":"STARTED","indices":["logs"],"shards":[]}]}}`)
+	})
+	restores, err := testClient.GetSnapshotRestoresInProgress(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []SnapshotRestoreInProgress{{Snapshot: "nightly-1", Repository: "backups", State: "STARTED"}}, restores)
+
+	// no restore in progress
+	testClient = NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(200, req, `{}`)
+	})
+	restores, err = testClient.GetSnapshotRestoresInProgress(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, restores)
+}
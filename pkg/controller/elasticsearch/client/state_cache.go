@@ -0,0 +1,132 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ClustersStateCache stores a StateCache for several clusters. It is thread-safe.
+type ClustersStateCache struct {
+	clusters map[types.NamespacedName]*StateCache
+	lock     sync.RWMutex
+}
+
+// NewClustersStateCache returns an initialized ClustersStateCache.
+func NewClustersStateCache() *ClustersStateCache {
+	return &ClustersStateCache{clusters: map[types.NamespacedName]*StateCache{}}
+}
+
+// ForCluster returns the StateCache of the given cluster.
+func (c *ClustersStateCache) ForCluster(cluster types.NamespacedName) *StateCache {
+	c.lock.RLock()
+	cache, exists := c.clusters[cluster]
+	c.lock.RUnlock()
+	if exists {
+		return cache
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if cache, exists := c.clusters[cluster]; exists {
+		return cache
+	}
+	cache = &StateCache{}
+	c.clusters[cluster] = cache
+	return cache
+}
+
+// RemoveCluster removes the StateCache of the given cluster.
+func (c *ClustersStateCache) RemoveCluster(cluster types.NamespacedName) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.clusters, cluster)
+}
+
+// StateCache caches the responses of the Elasticsearch API calls that can be expensive on large clusters, the shards
+// and the nodes, as long as the version of the cluster state they derive from does not change. It is thread-safe.
+type StateCache struct {
+	lock   sync.Mutex
+	shards *cachedResponse[Shards]
+	nodes  *cachedResponse[Nodes]
+}
+
+type cachedResponse[T any] struct {
+	stateVersion ClusterStateVersion
+	response     T
+}
+
+// Client returns a Client which serves the shards and the nodes from the cache if the cluster state has not changed
+// since they were retrieved.
+func (s *StateCache) Client(c Client) Client {
+	return &stateCachingClient{Client: c, cache: s}
+}
+
+type stateCachingClient struct {
+	Client
+	cache *StateCache
+}
+
+func (c *stateCachingClient) GetShards(ctx context.Context) (Shards, error) {
+	return getCached(ctx, c, &c.cache.shards, c.Client.GetShards, copyShards)
+}
+
+func (c *stateCachingClient) GetNodes(ctx context.Context) (Nodes, error) {
+	return getCached(ctx, c, &c.cache.nodes, c.Client.GetNodes, copyNodes)
+}
+
+// getCached returns a copy of the cached response if it was retrieved for the current version of the cluster state,
+// or retrieves and caches the response otherwise.
+func getCached[T any](
+	ctx context.Context,
+	c *stateCachingClient,
+	cached **cachedResponse[T],
+	get func(context.Context) (T, error),
+	deepCopy func(T) T,
+) (T, error) {
+	// the version is retrieved before the response: if the cluster state changes in between, the response is more
+	// recent than the version it is cached for and is retrieved again on the next call
+	stateVersion, err := c.Client.GetClusterStateVersion(ctx)
+	if err != nil {
+		return get(ctx)
+	}
+	c.cache.lock.Lock()
+	if *cached != nil && (*cached).stateVersion == stateVersion {
+		response := deepCopy((*cached).response)
+		c.cache.lock.Unlock()
+		return response, nil
+	}
+	c.cache.lock.Unlock()
+
+	response, err := get(ctx)
+	if err != nil {
+		return response, err
+	}
+	c.cache.lock.Lock()
+	defer c.cache.lock.Unlock()
+	*cached = &cachedResponse[T]{stateVersion: stateVersion, response: deepCopy(response)}
+	return response, nil
+}
+
+func copyShards(shards Shards) Shards {
+	if shards == nil {
+		return nil
+	}
+	return append(Shards{}, shards...)
+}
+
+func copyNodes(nodes Nodes) Nodes {
+	if nodes.Nodes == nil {
+		return nodes
+	}
+	copied := Nodes{Nodes: make(map[string]Node, len(nodes.Nodes))}
+	for id, node := range nodes.Nodes {
+		node.Roles = append([]string(nil), node.Roles...)
+		copied.Nodes[id] = node
+	}
+	return copied
+}
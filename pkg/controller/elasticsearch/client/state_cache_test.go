@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestStateCache(t *testing.T) {
+	ctx := context.Background()
+	stateVersion := 1
+	versionErr := false
+	requests := map[string]int{}
+	cache := NewClustersStateCache().ForCluster(types.NamespacedName{Namespace: "ns", Name: "es"})
+	roundTrip := func(req *http.Request) *http.Response {
+		requests[req.URL.Path]++
+		switch req.URL.Path {
+		case "/_cluster/state/version":
+			if versionErr {
+				return NewMockResponse(500, req, "{}")
+			}
+			return NewMockResponse(200, req,
+				fmt.Sprintf(`{"cluster_name":"es","cluster_uuid":"uuid","version":%d,"state_uuid":"state-%d"}`, stateVersion, stateVersion))
+		case "/_cat/shards":
+			return NewMockResponse(200, req, fmt.Sprintf(`[{"index":"index-%d","shard":"0","prirep":"p","state":"STARTED","node":"es-0"}]`, stateVersion))
+		case "/_nodes/_all/no-metrics":
+			return NewMockResponse(200, req, fmt.Sprintf(`{"nodes":{"id":{"name":"es-%d","version":"8.15.0","roles":["master"]}}}`, stateVersion))
+		}
+		return NewMockResponse(404, req, "{}")
+	}
+	c := cache.Client(NewMockClient(version.MustParse("8.15.0"), roundTrip))
+
+	// first calls retrieve the shards and nodes
+	shards, err := c.GetShards(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "index-1", shards[0].Index)
+	nodes, err := c.GetNodes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"es-1"}, nodes.Names())
+	require.Equal(t, 1, requests["/_cat/shards"])
+	require.Equal(t, 1, requests["/_nodes/_all/no-metrics"])
+
+	// same cluster state: served from the cache, also to a new client
+	c = cache.Client(NewMockClient(version.MustParse("8.15.0"), roundTrip))
+	shards[0].Index = "modified by the caller"
+	nodes.Nodes["id"].Roles[0] = "modified by the caller"
+	shards, err = c.GetShards(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "index-1", shards[0].Index)
+	nodes, err = c.GetNodes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"master"}, nodes.Nodes["id"].Roles)
+	require.Equal(t, 1, requests["/_cat/shards"])
+	require.Equal(t, 1, requests["/_nodes/_all/no-metrics"])
+
+	// new cluster state: retrieved again
+	stateVersion = 2
+	shards, err = c.GetShards(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "index-2", shards[0].Index)
+	nodes, err = c.GetNodes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"es-2"}, nodes.Names())
+	require.Equal(t, 2, requests["/_cat/shards"])
+	require.Equal(t, 2, requests["/_nodes/_all/no-metrics"])
+
+	// unknown cluster state version: not served from the cache
+	versionErr = true
+	_, err = c.GetShards(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, requests["/_cat/shards"])
+}
+
+func TestClustersStateCache(t *testing.T) {
+	caches := NewClustersStateCache()
+	es := types.NamespacedName{Namespace: "ns", Name: "es"}
+	cache := caches.ForCluster(es)
+	require.Same(t, cache, caches.ForCluster(es))
+	require.NotSame(t, cache, caches.ForCluster(types.NamespacedName{Namespace: "ns", Name: "other"}))
+	caches.RemoveCluster(es)
+	require.NotSame(t, cache, caches.ForCluster(es))
+}
@@ -77,4 +77,43 @@ const (
     }
   }
 }`
+
+	NodesFSStatsSample = `
+{
+  "_nodes" : {
+    "total" : 2,
+    "successful" : 2,
+    "failed" : 0
+  },
+  "cluster_name" : "elasticsearch-sample",
+  "nodes" : {
+    "Rt-o5-ZBQaq-Nkhhy0p7JA" : {
+      "timestamp" : 1560016895151,
+      "name" : "elasticsearch-sample-es-default-0",
+      "roles" : [ "data", "ingest", "master" ],
+      "fs" : {
+        "timestamp" : 1560016895151,
+        "total" : {
+          "total_in_bytes" : 1073741824,
+          "free_in_bytes" : 107374182,
+          "available_in_bytes" : 53687091
+        }
+      }
+    },
+    "pQHNt5rXTTWNvUgOrdynKg" : {
+      "timestamp" : 1560016895151,
+      "name" : "elasticsearch-sample-es-frozen-0",
+      "roles" : [ "data_frozen" ],
+      "fs" : {
+        "timestamp" : 1560016895151,
+        "total" : {
+          "total_in_bytes" : 1073741824,
+          "free_in_bytes" : 536870912,
+          "available_in_bytes" : 536870912
+        }
+      }
+    }
+  }
+}
+`
 )
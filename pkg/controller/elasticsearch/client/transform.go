@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Transform states, as returned by the /_transform/{id}/_stats API.
+const (
+	TransformStarted  = "started"
+	TransformIndexing = "indexing"
+	TransformStopping = "stopping"
+	TransformStopped  = "stopped"
+	TransformAborting = "aborting"
+	TransformFailed   = "failed"
+)
+
+// transformsResponse is the response of the /_transform/{id} API.
+type transformsResponse struct {
+	Transforms []map[string]interface{} `json:"transforms"`
+}
+
+// transformStatsResponse is the response of the /_transform/{id}/_stats API.
+type transformStatsResponse struct {
+	Transforms []TransformStats `json:"transforms"`
+}
+
+// TransformStats is the state and the progress of a transform as returned by the /_transform/{id}/_stats API.
+type TransformStats struct {
+	ID    string `json:"id"`
+	State string `json:"state"`
+	// Reason is the reason of the failure of a failed transform.
+	Reason        string                 `json:"reason,omitempty"`
+	Stats         TransformIndexerStats  `json:"stats"`
+	Checkpointing TransformCheckpointing `json:"checkpointing"`
+}
+
+// TransformIndexerStats are the statistics of the indexer of a transform.
+type TransformIndexerStats struct {
+	DocumentsProcessed int64 `json:"documents_processed"`
+	DocumentsIndexed   int64 `json:"documents_indexed"`
+}
+
+// TransformCheckpointing is the progress of a transform through its checkpoints.
+type TransformCheckpointing struct {
+	Last TransformCheckpoint `json:"last"`
+	// ChangesLastDetectedAt is the time, in milliseconds since epoch, changes were last detected in the source indices.
+	ChangesLastDetectedAt int64 `json:"changes_last_detected_at,omitempty"`
+	OperationsBehind      int64 `json:"operations_behind,omitempty"`
+}
+
+// TransformCheckpoint is a checkpoint of a transform.
+type TransformCheckpoint struct {
+	Checkpoint      int64 `json:"checkpoint"`
+	TimestampMillis int64 `json:"timestamp_millis,omitempty"`
+}
+
+type TransformClient interface {
+	// GetTransform returns the configuration of the transform of the given id.
+	GetTransform(ctx context.Context, id string) (map[string]interface{}, error)
+	// CreateTransform creates a transform, which is not started.
+	CreateTransform(ctx context.Context, id string, transform map[string]interface{}) error
+	// UpdateTransform updates the updatable fields of a transform, the changes are applied from its next checkpoint.
+	UpdateTransform(ctx context.Context, id string, transform map[string]interface{}) error
+	// DeleteTransform deletes a transform, stopping it first if needed. Its destination index is not deleted.
+	DeleteTransform(ctx context.Context, id string) error
+	// StartTransform starts a transform.
+	StartTransform(ctx context.Context, id string) error
+	// StopTransform stops a transform. A failed transform can only be stopped with force.
+	StopTransform(ctx context.Context, id string, force bool) error
+	// GetTransformStats returns the state and the progress of a transform.
+	GetTransformStats(ctx context.Context, id string) (TransformStats, error)
+}
+
+func (c *baseClient) GetTransform(ctx context.Context, id string) (map[string]interface{}, error) {
+	var response transformsResponse
+	if err := c.get(ctx, "/_transform/"+url.PathEscape(id), &response); err != nil {
+		return nil, err
+	}
+	if len(response.Transforms) == 0 {
+		return nil, fmt.Errorf("transform %s not found in the response", id)
+	}
+	return response.Transforms[0], nil
+}
+
+func (c *baseClient) CreateTransform(ctx context.Context, id string, transform map[string]interface{}) error {
+	return c.put(ctx, "/_transform/"+url.PathEscape(id), transform, nil)
+}
+
+func (c *baseClient) UpdateTransform(ctx context.Context, id string, transform map[string]interface{}) error {
+	return c.post(ctx, "/_transform/"+url.PathEscape(id)+"/_update", transform, nil)
+}
+
+func (c *baseClient) DeleteTransform(ctx context.Context, id string) error {
+	return c.delete(ctx, "/_transform/"+url.PathEscape(id)+"?force=true")
+}
+
+func (c *baseClient) StartTransform(ctx context.Context, id string) error {
+	return c.post(ctx, "/_transform/"+url.PathEscape(id)+"/_start", nil, nil)
+}
+
+func (c *baseClient) StopTransform(ctx context.Context, id string, force bool) error {
+	return c.post(ctx, "/_transform/"+url.PathEscape(id)+"/_stop?force="+strconv.FormatBool(force), nil, nil)
+}
+
+func (c *baseClient) GetTransformStats(ctx context.Context, id string) (TransformStats, error) {
+	var response transformStatsResponse
+	if err := c.get(ctx, "/_transform/"+url.PathEscape(id)+"/_stats", &response); err != nil {
+		return TransformStats{}, err
+	}
+	if len(response.Transforms) == 0 {
+		return TransformStats{}, fmt.Errorf("transform %s not found in the response", id)
+	}
+	return response.Transforms[0], nil
+}
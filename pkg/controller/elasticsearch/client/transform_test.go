@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetTransform(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_transform/orders-by-customer", req.URL.Path)
+		return NewMockResponse(200, req, `{"count":1,"transforms":[{"id":"orders-by-customer","source":{"index":["orders"]},"dest":{"index":"orders-by-customer"},"version":"10.0.0"}]}`)
+	})
+	transform, err := testClient.GetTransform(context.Background(), "orders-by-customer")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"id":      "orders-by-customer",
+		"source":  map[string]interface{}{"index": []interface{}{"orders"}},
+		"dest":    map[string]interface{}{"index": "orders-by-customer"},
+		"version": "10.0.0",
+	}, transform)
+
+	testClient = NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(404, req, `{}`)
+	})
+	_, err = testClient.GetTransform(context.Background(), "orders-by-customer")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_UpdateTransform(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_transform/orders-by-customer/_update", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"frequency":"5m"}`, string(body))
+		return NewMockResponse(200, req, `{"id":"orders-by-customer","frequency":"5m"}`)
+	})
+	require.NoError(t, testClient.UpdateTransform(context.Background(), "orders-by-customer", map[string]interface{}{"frequency": "5m"}))
+}
+
+func TestClient_StopTransform(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_transform/orders-by-customer/_stop", req.URL.Path)
+		require.Equal(t, "true", req.URL.Query().Get("force"))
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, testClient.StopTransform(context.Background(), "orders-by-customer", true))
+}
+
+func TestClient_DeleteTransform(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_transform/orders-by-customer", req.URL.Path)
+		require.Equal(t, "true", req.URL.Query().Get("force"))
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, testClient.DeleteTransform(context.Background(), "orders-by-customer"))
+}
+
+func TestClient_GetTransformStats(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_transform/orders-by-customer/_stats", req.URL.Path)
+		return NewMockResponse(200, req, `{"count":1,"transforms":[{
+  "id":"orders-by-customer",
+  "state":"failed",
+  "reason":"task encountered irrecoverable failure: no such index [orders]",
+  "stats":{"pages_processed":12,"documents_processed":1200,"documents_indexed":40},
+  "checkpointing":{"last":{"checkpoint":3,"timestamp_millis":1760000000000},"operations_behind":7,"changes_last_detected_at":1760000000000},
+  "health":{"status":"red"}
+}]}`)
+	})
+	stats, err := testClient.GetTransformStats(context.Background(), "orders-by-customer")
+	require.NoError(t, err)
+	require.Equal(t, TransformStats{
+		ID:     "orders-by-customer",
+		State:  TransformFailed,
+		Reason: "task encountered irrecoverable failure: no such index [orders]",
+		Stats:  TransformIndexerStats{DocumentsProcessed: 1200, DocumentsIndexed: 40},
+		Checkpointing: TransformCheckpointing{
+			Last:                  TransformCheckpoint{Checkpoint: 3, TimestampMillis: 1760000000000},
+			ChangesLastDetectedAt: 1760000000000,
+			OperationsBehind:      7,
+		},
+	}, stats)
+}
@@ -0,0 +1,134 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"crypto/x509"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// ConnectionPoolSettings configures the HTTP connections kept open to each managed cluster.
+type ConnectionPoolSettings struct {
+	// MaxIdleConnsPerHost is the maximum number of idle connections kept open to each Elasticsearch endpoint.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost is the maximum number of connections to each Elasticsearch endpoint, zero meaning no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout is the duration after which idle connections are closed, zero meaning no limit.
+	IdleConnTimeout time.Duration
+}
+
+// DefaultConnectionPoolSettings are the connection pool settings used unless set with SetConnectionPoolSettings.
+var DefaultConnectionPoolSettings = ConnectionPoolSettings{
+	MaxIdleConnsPerHost: 4,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+// transports holds the HTTP transport shared by all the clients of each managed cluster, so that connections and TLS
+// sessions are reused across clients instead of being established again by every reconciliation.
+var transports = newTransportCache(DefaultConnectionPoolSettings)
+
+// SetConnectionPoolSettings sets the settings of the connections to the managed clusters. It can be updated while the
+// operator is running: the transports of the clusters are replaced the next time a client is created for them.
+func SetConnectionPoolSettings(settings ConnectionPoolSettings) {
+	transports.setSettings(settings)
+}
+
+// CloseIdleConnections closes the idle connections to the given cluster of the given Kubernetes cluster, empty for the
+// one the operator runs in, and forgets its transport and rate limiter. It should be called once the cluster is not
+// managed anymore.
+func CloseIdleConnections(k8sCluster string, es types.NamespacedName) {
+	key := clusterKey{k8sCluster: k8sCluster, NamespacedName: es}
+	transports.forget(key)
+	rateLimiters.forget(key)
+}
+
+// clusterKey identifies an Elasticsearch cluster among the clusters of all the Kubernetes clusters managed by the
+// operator, in which the same namespace and name can be used by different clusters.
+type clusterKey struct {
+	// k8sCluster is the name of the remote Kubernetes cluster, empty for the cluster the operator runs in.
+	k8sCluster string
+	types.NamespacedName
+}
+
+type sharedTransport struct {
+	transport *http.Transport
+	caCerts   []*x509.Certificate
+	settings  ConnectionPoolSettings
+}
+
+type transportCache struct {
+	lock       sync.Mutex
+	settings   ConnectionPoolSettings
+	transports map[clusterKey]sharedTransport
+}
+
+func newTransportCache(settings ConnectionPoolSettings) *transportCache {
+	return &transportCache{
+		settings:   settings,
+		transports: make(map[clusterKey]sharedTransport),
+	}
+}
+
+func (c *transportCache) setSettings(settings ConnectionPoolSettings) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.settings = settings
+}
+
+// get returns the transport of the given cluster. The transport is created, or replaced if the CA certificates or the
+// settings changed since its creation. The dialer is expected to be the same for all the clusters of a Kubernetes
+// cluster.
+func (c *transportCache) get(es clusterKey, dialer net.Dialer, caCerts []*x509.Certificate) *http.Transport {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	existing, exists := c.transports[es]
+	if exists && equalCerts(existing.caCerts, caCerts) && existing.settings == c.settings {
+		return existing.transport
+	}
+	if exists {
+		existing.transport.CloseIdleConnections()
+	}
+	transport := newTransport(dialer, caCerts, c.settings)
+	c.transports[es] = sharedTransport{transport: transport, caCerts: caCerts, settings: c.settings}
+	return transport
+}
+
+func (c *transportCache) forget(es clusterKey) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if existing, exists := c.transports[es]; exists {
+		existing.transport.CloseIdleConnections()
+		delete(c.transports, es)
+	}
+}
+
+func newTransport(dialer net.Dialer, caCerts []*x509.Certificate, settings ConnectionPoolSettings) *http.Transport {
+	transport := commonhttp.Transport(dialer, caCerts)
+	// all the idle connections are to the endpoints of a single cluster
+	transport.MaxIdleConns = 0
+	transport.MaxIdleConnsPerHost = settings.MaxIdleConnsPerHost
+	transport.MaxConnsPerHost = settings.MaxConnsPerHost
+	transport.IdleConnTimeout = settings.IdleConnTimeout
+	return transport
+}
+
+func equalCerts(certs1, certs2 []*x509.Certificate) bool {
+	if len(certs1) != len(certs2) {
+		return false
+	}
+	for i := range certs1 {
+		if !certs1[i].Equal(certs2[i]) {
+			return false
+		}
+	}
+	return true
+}
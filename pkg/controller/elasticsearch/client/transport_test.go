@@ -0,0 +1,124 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	utilsnet "github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+func createCACerts(t *testing.T) []*x509.Certificate {
+	t.Helper()
+	ca, err := certificates.NewSelfSignedCA(certificates.CABuilderOptions{})
+	require.NoError(t, err)
+	return []*x509.Certificate{ca.Cert}
+}
+
+func Test_transportCache_get(t *testing.T) {
+	es1 := clusterKey{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "es1"}}
+	es2 := clusterKey{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "es2"}}
+	caCerts := createCACerts(t)
+	cache := newTransportCache(ConnectionPoolSettings{MaxIdleConnsPerHost: 2, MaxConnsPerHost: 10, IdleConnTimeout: time.Minute})
+
+	transport := cache.get(es1, nil, caCerts)
+	require.Equal(t, 2, transport.MaxIdleConnsPerHost)
+	require.Equal(t, 10, transport.MaxConnsPerHost)
+	require.Equal(t, time.Minute, transport.IdleConnTimeout)
+	// the transport is reused by the following clients of the same cluster
+	require.Same(t, transport, cache.get(es1, nil, caCerts))
+	// but not by the clients of other clusters
+	require.NotSame(t, transport, cache.get(es2, nil, caCerts))
+	// including the clusters with the same namespace and name in other Kubernetes clusters
+	require.NotSame(t, transport, cache.get(clusterKey{k8sCluster: "remote", NamespacedName: es1.NamespacedName}, nil, caCerts))
+
+	// the transport is replaced when the CA certificates change
+	updated := cache.get(es1, nil, createCACerts(t))
+	require.NotSame(t, transport, updated)
+	transport = updated
+
+	// or when the settings change
+	cache.setSettings(ConnectionPoolSettings{MaxIdleConnsPerHost: 5})
+	updated = cache.get(es1, nil, nil)
+	require.NotSame(t, transport, updated)
+	require.Equal(t, 5, updated.MaxIdleConnsPerHost)
+	transport = updated
+
+	// forgotten transports are recreated
+	cache.forget(es1)
+	require.NotContains(t, cache.transports, es1)
+	require.NotSame(t, transport, cache.get(es1, nil, nil))
+}
+
+func TestNewElasticsearchClient_sharedTransport(t *testing.T) {
+	es := types.NamespacedName{Namespace: "ns", Name: "shared-transport"}
+	defer CloseIdleConnections("", es)
+	caCerts := createCACerts(t)
+	newClient := func(es types.NamespacedName) *baseClient {
+		c := NewElasticsearchClient(nil, es, NewStaticURLProvider("https://es-url"), BasicAuth{}, version.MustParse("8.15.0"), caCerts, DefaultESClientTimeout, false)
+		return &c.(*clientV8).baseClient
+	}
+
+	c1 := newClient(es)
+	require.True(t, c1.sharedTransport)
+	transport := transports.transports[clusterKey{NamespacedName: es}].transport
+	require.NotNil(t, transport)
+	// the following clients of the cluster reuse the same transport
+	c2 := newClient(es)
+	require.True(t, c2.sharedTransport)
+	require.Same(t, transport, transports.transports[clusterKey{NamespacedName: es}].transport)
+	// which is not closed with the clients
+	c1.Close()
+	c2.Close()
+	require.Same(t, transport, transports.transports[clusterKey{NamespacedName: es}].transport)
+
+	// clients of unnamed clusters have their own transport
+	c3 := newClient(types.NamespacedName{})
+	require.False(t, c3.sharedTransport)
+	require.NotContains(t, transports.transports, clusterKey{})
+}
+
+// remoteDialer is a dialer of a remote Kubernetes cluster.
+type remoteDialer struct {
+	utilsnet.Dialer
+	cluster string
+}
+
+func (d remoteDialer) ClusterName() string {
+	return d.cluster
+}
+
+func TestNewElasticsearchClient_remoteClusters(t *testing.T) {
+	// a cluster with the same namespace and name in the cluster the operator runs in and in a remote cluster
+	es := types.NamespacedName{Namespace: "ns", Name: "same-name"}
+	local := clusterKey{NamespacedName: es}
+	remote := clusterKey{k8sCluster: "remote", NamespacedName: es}
+	defer CloseIdleConnections("", es)
+	defer CloseIdleConnections("remote", es)
+	caCerts := createCACerts(t)
+	newClient := func(dialer utilsnet.Dialer) *baseClient {
+		c := NewElasticsearchClient(dialer, es, NewStaticURLProvider("https://es-url"), BasicAuth{}, version.MustParse("8.15.0"), caCerts, DefaultESClientTimeout, false)
+		return &c.(*clientV8).baseClient
+	}
+
+	localClient := newClient(nil)
+	remoteClient := newClient(remoteDialer{cluster: "remote"})
+	require.Equal(t, local, localClient.es)
+	require.Equal(t, remote, remoteClient.es)
+	// the clusters do not share their connections
+	require.NotSame(t, transports.transports[local].transport, transports.transports[remote].transport)
+
+	// forgetting the remote cluster does not affect the local one
+	CloseIdleConnections("remote", es)
+	require.NotContains(t, transports.transports, remote)
+	require.Contains(t, transports.transports, local)
+}
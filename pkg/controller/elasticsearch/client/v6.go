@@ -6,8 +6,12 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -104,8 +108,27 @@ func (c *clientV6) SetMinimumMasterNodes(ctx context.Context, n int) error {
 	return c.put(ctx, "/_cluster/settings", &zenSettings, nil)
 }
 
-func (c *clientV6) ReloadSecureSettings(ctx context.Context) error {
-	return c.post(ctx, "/_nodes/reload_secure_settings", nil, nil)
+func (c *clientV6) ReloadSecureSettings(ctx context.Context, password string) error {
+	var request interface{}
+	if password != "" {
+		request = ReloadSecureSettingsRequest{SecureSettingsPassword: password}
+	}
+	var response ReloadSecureSettingsResponse
+	if err := c.post(ctx, "/_nodes/reload_secure_settings", request, &response); err != nil {
+		return err
+	}
+	// the API succeeds even if some nodes fail to reload their secure settings
+	failures := make([]string, 0, len(response.Nodes))
+	for _, node := range response.Nodes {
+		if node.ReloadException != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", node.Name, node.ReloadException.Reason))
+		}
+	}
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return errors.Errorf("failed to reload secure settings on nodes: %s", strings.Join(failures, ", "))
+	}
+	return nil
 }
 
 func (c *clientV6) GetNodes(ctx context.Context) (Nodes, error) {
@@ -122,6 +145,28 @@ func (c *clientV6) GetNodesStats(ctx context.Context) (NodesStats, error) {
 	return nodesStats, err
 }
 
+func (c *clientV6) GetNodesFSStats(ctx context.Context) (NodesStats, error) {
+	var nodesStats NodesStats
+	err := c.get(ctx, "/_nodes/_all/stats/fs", &nodesStats)
+	return nodesStats, err
+}
+
+func (c *clientV6) GetNodesMetrics(ctx context.Context, groups ...string) (NodesMetrics, error) {
+	var nodesMetrics NodesMetrics
+	path := "/_nodes/_all/stats"
+	if len(groups) > 0 {
+		path += "/" + strings.Join(groups, ",")
+	}
+	err := c.get(ctx, path, &nodesMetrics)
+	return nodesMetrics, err
+}
+
+func (c *clientV6) GetClusterSettingsWithDefaults(ctx context.Context) (ClusterSettingsWithDefaults, error) {
+	var settings ClusterSettingsWithDefaults
+	err := c.get(ctx, "/_cluster/settings?include_defaults=true&flat_settings=true", &settings)
+	return settings, err
+}
+
 func (c *clientV6) UpdateRemoteClusterSettings(ctx context.Context, settings RemoteClustersSettings) error {
 	return c.put(ctx, "/_cluster/settings", &settings, nil)
 }
@@ -132,6 +177,12 @@ func (c *clientV6) GetRemoteClusterSettings(ctx context.Context) (RemoteClusters
 	return remoteClustersSettings, err
 }
 
+func (c *clientV6) GetRemoteClusterInfo(ctx context.Context) (RemoteClustersInfo, error) {
+	var info RemoteClustersInfo
+	err := c.get(ctx, "/_remote/info", &info)
+	return info, err
+}
+
 func (c *clientV6) GetLicense(ctx context.Context) (License, error) {
 	var license LicenseResponse
 	err := c.get(ctx, "/_xpack/license", &license)
@@ -184,7 +235,7 @@ func (c *clientV6) GetShutdown(context.Context, *string) (ShutdownResponse, erro
 	return ShutdownResponse{}, errNotSupportedInEs6x
 }
 
-func (c *clientV6) PutShutdown(context.Context, string, ShutdownType, string) error {
+func (c *clientV6) PutShutdown(context.Context, string, ShutdownType, string, *time.Duration) error {
 	return errNotSupportedInEs6x
 }
 
@@ -211,6 +262,12 @@ func (c *clientV6) ClusterBootstrappedForZen2(ctx context.Context) (bool, error)
 	return false, errors.New("no master found in ClusterBootstrappedForZen2")
 }
 
+func (c *clientV6) GetClusterStateVersion(ctx context.Context) (ClusterStateVersion, error) {
+	var stateVersion ClusterStateVersion
+	err := c.get(ctx, "/_cluster/state/version", &stateVersion)
+	return stateVersion, err
+}
+
 func (c *clientV6) GetClusterState(_ context.Context) (ClusterState, error) {
 	return ClusterState{}, errors.New("cluster state is not supported in Elasticsearch 6.x")
 }
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -68,11 +69,15 @@ func (c *clientV7) GetShutdown(ctx context.Context, nodeID *string) (ShutdownRes
 	return r, err
 }
 
-func (c *clientV7) PutShutdown(ctx context.Context, nodeID string, shutdownType ShutdownType, reason string) error {
+func (c *clientV7) PutShutdown(ctx context.Context, nodeID string, shutdownType ShutdownType, reason string, allocationDelay *time.Duration) error {
 	request := ShutdownRequest{
 		Type:   shutdownType,
 		Reason: reason,
 	}
+	if allocationDelay != nil {
+		// Elasticsearch does not parse compound durations such as 5m0s
+		request.AllocationDelay = fmt.Sprintf("%ds", int64(allocationDelay.Seconds()))
+	}
 	return c.put(ctx, fmt.Sprintf("/_nodes/%s/shutdown", nodeID), request, nil)
 }
 
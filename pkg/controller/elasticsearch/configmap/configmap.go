@@ -44,7 +44,11 @@ func ReconcileScriptsConfigMap(ctx context.Context, c k8s.Client, es esv1.Elasti
 		return err
 	}
 
-	preStopScript, err := nodespec.RenderPreStopHookScript(services.InternalServiceURL(es))
+	preStopScript, err := nodespec.RenderPreStopHookScript(
+		services.InternalServiceURL(es),
+		// the service mesh sidecar may not route the requests of the hook once the Pod is terminating
+		es.Spec.HTTP.TLS.ServiceMeshEnabled(),
+	)
 	if err != nil {
 		return err
 	}
@@ -33,9 +33,7 @@ func ReconcileConfigMap(
 			NeedsUpdate: func() bool {
 				return !reflect.DeepEqual(expected.Data, reconciled.Data)
 			},
-			UpdateReconciled: func() {
-				reconciled.Data = expected.Data
-			},
+			ServerSideApply: true,
 		},
 	)
 }
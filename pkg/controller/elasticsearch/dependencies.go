@@ -0,0 +1,194 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearch
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	remoteclusterv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/remotecluster/v1alpha1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// maxSkippedReconciliationPeriod bounds the period during which the reconciliations of a cluster whose dependencies are
+// unchanged are skipped, so that changes to resources not tracked as dependencies (such as Gateway routes or
+// ServiceMonitors) are eventually reconciled.
+const maxSkippedReconciliationPeriod = 10 * time.Minute
+
+var secretGVK = corev1.SchemeGroupVersion.WithKind("Secret")
+
+// dependencies returns everything the reconciliation of the given cluster depends on: the Elasticsearch resource itself,
+// the resources created for it, the Secrets and ConfigMaps it references, and its last observed state.
+func (r *ReconcileElasticsearch) dependencies(ctx context.Context, es esv1.Elasticsearch) (reconciler.Dependencies, error) {
+	nsn := k8s.ExtractNamespacedName(&es)
+	deps := reconciler.Dependencies{
+		"uid":        string(es.UID),
+		"generation": strconv.FormatInt(es.Generation, 10),
+	}
+	for k, v := range es.Labels {
+		deps["label/"+k] = v
+	}
+	for k, v := range es.Annotations {
+		deps["annotation/"+k] = v
+	}
+
+	// resources created for the cluster, only the metadata of Secrets and Pods is read as it may be the only thing cached
+	inNamespace := client.InNamespace(es.Namespace)
+	matchLabels := label.NewLabelSelectorForElasticsearch(es)
+	secrets := k8s.NewMetadataList(secretGVK)
+	if err := r.Client.List(ctx, secrets, inNamespace, matchLabels); err != nil {
+		return nil, err
+	}
+	for i := range secrets.Items {
+		deps.AddObject("Secret", &secrets.Items[i])
+	}
+	var configMaps corev1.ConfigMapList
+	if err := r.Client.List(ctx, &configMaps, inNamespace, matchLabels); err != nil {
+		return nil, err
+	}
+	for i := range configMaps.Items {
+		deps.AddObject("ConfigMap", &configMaps.Items[i])
+	}
+	pods := k8s.NewMetadataList(corev1.SchemeGroupVersion.WithKind("Pod"))
+	if err := r.Client.List(ctx, pods, inNamespace, matchLabels); err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		deps.AddObject("Pod", &pods.Items[i])
+	}
+	var statefulSets appsv1.StatefulSetList
+	if err := r.Client.List(ctx, &statefulSets, inNamespace, matchLabels); err != nil {
+		return nil, err
+	}
+	for i := range statefulSets.Items {
+		deps.AddObject("StatefulSet", &statefulSets.Items[i])
+	}
+	var services corev1.ServiceList
+	if err := r.Client.List(ctx, &services, inNamespace, matchLabels); err != nil {
+		return nil, err
+	}
+	for i := range services.Items {
+		deps.AddObject("Service", &services.Items[i])
+	}
+	var ingresses networkingv1.IngressList
+	if err := r.Client.List(ctx, &ingresses, inNamespace); err != nil {
+		return nil, err
+	}
+	for i := range ingresses.Items {
+		if k8s.HasOwner(&ingresses.Items[i], &es) {
+			deps.AddObject("Ingress", &ingresses.Items[i])
+		}
+	}
+
+	// Secrets soft-owned by the cluster, possibly in other namespaces
+	softOwnedSecrets := k8s.NewMetadataList(secretGVK)
+	if err := r.Client.List(ctx, softOwnedSecrets, client.MatchingLabels{
+		reconciler.SoftOwnerNamespaceLabel: es.Namespace,
+		reconciler.SoftOwnerNameLabel:      es.Name,
+		reconciler.SoftOwnerKindLabel:      esv1.Kind,
+	}); err != nil {
+		return nil, err
+	}
+	for i := range softOwnedSecrets.Items {
+		deps.AddObject("Secret", &softOwnedSecrets.Items[i])
+	}
+
+	// Secrets and ConfigMaps referenced by the cluster
+	for _, watched := range r.dynamicWatches.Secrets.WatchedBy(nsn) {
+		secret := &metav1.PartialObjectMetadata{}
+		secret.SetGroupVersionKind(secretGVK)
+		if err := addWatchedObject(ctx, r.Client, deps, "Secret", watched, secret); err != nil {
+			return nil, err
+		}
+	}
+	for _, watched := range r.dynamicWatches.ConfigMaps.WatchedBy(nsn) {
+		if err := addWatchedObject(ctx, r.Client, deps, "ConfigMap", watched, &corev1.ConfigMap{}); err != nil {
+			return nil, err
+		}
+	}
+
+	// SnapshotRepositories registered on the cluster, which must be in the same namespace
+	var repositories snapshotv1alpha1.SnapshotRepositoryList
+	if err := r.Client.List(ctx, &repositories, inNamespace); err != nil {
+		return nil, err
+	}
+	for i := range repositories.Items {
+		if repositories.Items[i].References(nsn) {
+			deps.AddObject("SnapshotRepository", &repositories.Items[i])
+		}
+	}
+
+	// RemoteClusterLinks connecting the cluster to a remote cluster
+	var links remoteclusterv1alpha1.RemoteClusterLinkList
+	if err := r.Client.List(ctx, &links, client.InNamespace(nsn.Namespace)); err != nil {
+		return nil, err
+	}
+	for i, link := range links.Items {
+		if link.Spec.Client != nil && link.References(nsn) {
+			deps.AddObject("RemoteClusterLink", &links.Items[i])
+		}
+	}
+
+	// certificate rotation parameters, which can be updated while the operator is running
+	deps["caCertRotation"] = rotationParams(r.CACertRotation.Get())
+	deps["certRotation"] = rotationParams(r.CertRotation.Get())
+
+	// last observed state of the cluster
+	if health, observed := r.esObservers.LastHealth(nsn); observed {
+		deps["health"] = string(health)
+	}
+	if stateVersion, observed := r.esObservers.LastStateVersion(nsn); observed {
+		deps["state"] = stateVersion.ClusterUUID + "/" + stateVersion.StateUUID + "/" + strconv.FormatInt(stateVersion.Version, 10)
+	}
+	return deps, nil
+}
+
+// rotationParams identifies the given rotation parameters.
+func rotationParams(params certificates.RotationParams) string {
+	return params.Validity.String() + "/" + params.RotateBefore.String()
+}
+
+// addWatchedObject adds the resource version of the watched object to the dependencies, or records it as missing.
+func addWatchedObject(ctx context.Context, c k8s.Client, deps reconciler.Dependencies, kind string, watched types.NamespacedName, obj client.Object) error {
+	if err := c.Get(ctx, watched, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			deps.AddMissingObject(kind, watched)
+			return nil
+		}
+		return err
+	}
+	deps.AddObject(kind, obj)
+	return nil
+}
+
+// recordDependencies records the hash of the dependencies of a complete reconciliation so that the following
+// reconciliations can be skipped until the dependencies change or a requeue is due. It forgets the hash of an
+// incomplete one.
+func (r *ReconcileElasticsearch) recordDependencies(es types.NamespacedName, dependencyHash string, result reconcile.Result, err error, reconciled bool) {
+	if err != nil || !reconciled || result.Requeue {
+		r.dependencyHashes.Forget(es)
+		return
+	}
+	validity := maxSkippedReconciliationPeriod
+	if result.RequeueAfter > 0 && result.RequeueAfter < validity {
+		validity = result.RequeueAfter
+	}
+	r.dependencyHashes.Record(es, dependencyHash, validity)
+}
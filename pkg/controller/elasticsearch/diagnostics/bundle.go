@@ -0,0 +1,300 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// BundleAnnotation is the annotation used to request a diagnostics bundle for an Elasticsearch cluster. A new bundle
+	// is collected every time the value of the annotation changes.
+	BundleAnnotation = "eck.k8s.elastic.co/diagnostics"
+
+	// Keys of the entries of the diagnostics bundle Secret.
+	ElasticsearchKey     = "elasticsearch.json"
+	OperatorStateKey     = "operator-state.json"
+	StatefulSetsKey      = "statefulsets.json"
+	PodsKey              = "pods.json"
+	EventsKey            = "events.json"
+	ClusterHealthKey     = "api-cluster-health.json"
+	PendingTasksKey      = "api-pending-tasks.json"
+	AllocationExplainKey = "api-allocation-explain.json"
+	ErrorsKey            = "errors.json"
+
+	// maxBundleSize keeps the bundle under the 1MiB size limit of Secrets, with some room for the metadata.
+	maxBundleSize = 900 * 1024
+	// maxAPIResponseSize is the maximum number of bytes kept from each Elasticsearch API response.
+	maxAPIResponseSize = 128 * 1024
+	// logsTailLines is the maximum number of lines of logs collected for each Pod.
+	logsTailLines = 2000
+
+	redacted = "[REDACTED]"
+	// lastAppliedConfigAnnotation is set by kubectl apply and contains the full manifest including the secure fields.
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+var (
+	// sensitiveName matches the names of the settings and environment variables whose value must not be collected.
+	sensitiveName = regexp.MustCompile(`(?i)(password|passphrase|secret|token|api_?key|credentials|private_?key)`)
+	// sensitiveLogValue matches sensitive key/value pairs in log lines, in the key=value or "key": "value" forms.
+	sensitiveLogValue = regexp.MustCompile(`(?i)("?[\w.-]*(?:password|passphrase|secret|token|api_?key|credentials)[\w.-]*"?\s*[:=]\s*)("[^"]*"|[^\s,}]+)`)
+	// authorizationHeader matches credentials passed in HTTP Authorization headers.
+	authorizationHeader = regexp.MustCompile(`(?i)(authorization"?\s*[:=]\s*"?\s*(?:basic|bearer|apikey)\s+)[^\s",}]+`)
+)
+
+// BundleRequested returns the value of BundleAnnotation on the given cluster, and false if no bundle is requested.
+func BundleRequested(es esv1.Elasticsearch) (string, bool) {
+	request, exists := es.Annotations[BundleAnnotation]
+	return request, exists && request != ""
+}
+
+// BundleCollected returns true if the bundle Secret of the cluster already contains the bundle for the given request.
+func BundleCollected(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, request string) (bool, error) {
+	var secret corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: esv1.DiagnosticsBundleSecret(es.Name)}, &secret)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return secret.Annotations[dumpRequestAnnotation] == request, nil
+}
+
+// BundleCollector gathers the diagnostics of an Elasticsearch cluster.
+type BundleCollector struct {
+	// Client reads the resources of the cluster from the operator cache.
+	Client k8s.Client
+	// Reader reads the events and logs of the cluster from the Kubernetes API.
+	Reader KubernetesReader
+	// ESClient calls the Elasticsearch API, nil if no client could be created.
+	ESClient esclient.Client
+}
+
+// bundle accumulates the entries of a diagnostics bundle along with the errors met while collecting them.
+type bundle struct {
+	data   map[string][]byte
+	errors map[string]string
+}
+
+func (b *bundle) size() int {
+	size := 0
+	for k, v := range b.data {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+func (b *bundle) addJSON(key string, obj interface{}) {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		b.errors[key] = err.Error()
+		return
+	}
+	b.data[key] = data
+}
+
+// Collect returns the entries of the diagnostics bundle of the given cluster. Failing to collect an entry does not
+// prevent the collection of the other ones, the errors are reported in the ErrorsKey entry instead.
+// The values of the settings, environment variables and log fields that look like credentials are redacted.
+func (b BundleCollector) Collect(ctx context.Context, es esv1.Elasticsearch, state State) map[string][]byte {
+	result := bundle{data: map[string][]byte{}, errors: map[string]string{}}
+
+	result.addJSON(ElasticsearchKey, redactElasticsearch(es))
+	result.addJSON(OperatorStateKey, state)
+
+	statefulSets, err := sset.RetrieveActualStatefulSets(b.Client, k8s.ExtractNamespacedName(&es))
+	if err != nil {
+		result.errors[StatefulSetsKey] = err.Error()
+	}
+	for i := range statefulSets {
+		redactObjectMeta(&statefulSets[i].ObjectMeta)
+		redactPodSpec(&statefulSets[i].Spec.Template.Spec)
+	}
+	result.addJSON(StatefulSetsKey, statefulSets)
+
+	pods, err := sset.GetActualPodsForCluster(b.Client, es)
+	if err != nil {
+		result.errors[PodsKey] = err.Error()
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	for i := range pods {
+		redactObjectMeta(&pods[i].ObjectMeta)
+		redactPodSpec(&pods[i].Spec)
+	}
+	result.addJSON(PodsKey, pods)
+
+	events, err := b.Reader.Events(ctx, es.Namespace)
+	if err != nil {
+		result.errors[EventsKey] = err.Error()
+	}
+	result.addJSON(EventsKey, clusterEvents(es, events))
+
+	b.collectAPI(ctx, &result, ClusterHealthKey, "/_cluster/health")
+	b.collectAPI(ctx, &result, PendingTasksKey, "/_cluster/pending_tasks")
+	b.collectAPI(ctx, &result, AllocationExplainKey, "/_cluster/allocation/explain")
+
+	// logs are collected last to share the remaining space evenly between the Pods
+	if len(pods) > 0 {
+		remaining := maxBundleSize - result.size() - len(result.errors)*256
+		budget := int64(remaining / len(pods))
+		for _, pod := range pods {
+			key := fmt.Sprintf("logs-%s.log", pod.Name)
+			if budget <= 0 {
+				result.errors[key] = "bundle size limit reached"
+				continue
+			}
+			logs, err := b.Reader.Logs(ctx, pod, esv1.ElasticsearchContainerName, budget)
+			if err != nil {
+				result.errors[key] = err.Error()
+				continue
+			}
+			result.data[key] = RedactLogs(logs)
+		}
+	}
+
+	if len(result.errors) > 0 {
+		result.addJSON(ErrorsKey, result.errors)
+	}
+	return result.data
+}
+
+// collectAPI stores in the bundle the response of the Elasticsearch API at the given path.
+func (b BundleCollector) collectAPI(ctx context.Context, result *bundle, key string, path string) {
+	if b.ESClient == nil {
+		result.errors[key] = "no Elasticsearch client available"
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		result.errors[key] = err.Error()
+		return
+	}
+	resp, err := b.ESClient.Request(ctx, req)
+	if err != nil {
+		result.errors[key] = err.Error()
+		return
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAPIResponseSize))
+	if err != nil {
+		result.errors[key] = err.Error()
+		return
+	}
+	result.data[key] = data
+}
+
+// WriteBundle writes the given bundle into the diagnostics bundle Secret of the cluster, replacing any previous bundle.
+func WriteBundle(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, request string, data map[string][]byte) error {
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        esv1.DiagnosticsBundleSecret(es.Name),
+			Namespace:   es.Namespace,
+			Labels:      label.NewLabels(k8s.ExtractNamespacedName(&es)),
+			Annotations: map[string]string{dumpRequestAnnotation: request},
+		},
+		Data: data,
+	}
+	reconciled := &corev1.Secret{}
+	return reconciler.ReconcileResource(
+		reconciler.Params{
+			Context:    ctx,
+			Client:     c,
+			Owner:      &es,
+			Expected:   &expected,
+			Reconciled: reconciled,
+			NeedsUpdate: func() bool {
+				return reconciled.Annotations[dumpRequestAnnotation] != request
+			},
+			ServerSideApply: true,
+		},
+	)
+}
+
+// RedactLogs masks the credentials that may appear in the given logs.
+func RedactLogs(logs []byte) []byte {
+	logs = sensitiveLogValue.ReplaceAll(logs, []byte("${1}"+redacted))
+	return authorizationHeader.ReplaceAll(logs, []byte("${1}"+redacted))
+}
+
+// clusterEvents returns the events involving the given cluster or one of its resources, sorted by time.
+func clusterEvents(es esv1.Elasticsearch, events []corev1.Event) []corev1.Event {
+	// all the resources of the cluster are named <cluster name>-es-<suffix>
+	prefix := es.Name + "-es-"
+	result := make([]corev1.Event, 0, len(events))
+	for _, e := range events {
+		if e.InvolvedObject.Name != es.Name && !strings.HasPrefix(e.InvolvedObject.Name, prefix) {
+			continue
+		}
+		e.ManagedFields = nil
+		result = append(result, e)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].LastTimestamp.Before(&result[j].LastTimestamp) })
+	return result
+}
+
+// redactElasticsearch returns a copy of the given cluster without its credentials.
+func redactElasticsearch(es esv1.Elasticsearch) esv1.Elasticsearch {
+	result := *es.DeepCopy()
+	redactObjectMeta(&result.ObjectMeta)
+	for i := range result.Spec.NodeSets {
+		if result.Spec.NodeSets[i].Config != nil {
+			redactConfig(result.Spec.NodeSets[i].Config.Data)
+		}
+		redactPodSpec(&result.Spec.NodeSets[i].PodTemplate.Spec)
+	}
+	return result
+}
+
+func redactObjectMeta(meta *metav1.ObjectMeta) {
+	meta.ManagedFields = nil
+	delete(meta.Annotations, lastAppliedConfigAnnotation)
+}
+
+// redactConfig replaces the values of the sensitive settings of the given configuration, recursively.
+func redactConfig(config map[string]interface{}) {
+	for k, v := range config {
+		if sensitiveName.MatchString(k) {
+			config[k] = redacted
+			continue
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			redactConfig(nested)
+		}
+	}
+}
+
+// redactPodSpec replaces the values of the sensitive environment variables of the containers of the given spec.
+func redactPodSpec(spec *corev1.PodSpec) {
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			for j, env := range containers[i].Env {
+				if env.Value != "" && sensitiveName.MatchString(env.Name) {
+					containers[i].Env[j].Value = redacted
+				}
+			}
+		}
+	}
+}
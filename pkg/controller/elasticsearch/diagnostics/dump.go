@@ -0,0 +1,188 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/observer"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// DumpAnnotation is the annotation used to request a dump of the internal state of the operator for an
+	// Elasticsearch cluster. A new dump is written every time the value of the annotation changes.
+	DumpAnnotation = "eck.k8s.elastic.co/dump-diagnostics"
+	// dumpRequestAnnotation records on the diagnostics ConfigMap the value of DumpAnnotation that triggered the dump.
+	dumpRequestAnnotation = "eck.k8s.elastic.co/diagnostics-request"
+	// StateKey is the key of the ConfigMap entry which contains the dumped state.
+	StateKey = "state.json"
+)
+
+// State is the internal view of the operator for an Elasticsearch cluster.
+type State struct {
+	// GeneratedAt is the time at which the state was dumped.
+	GeneratedAt metav1.Time `json:"generatedAt"`
+	// Phase is the phase computed during the reconciliation which produced the dump.
+	Phase esv1.ElasticsearchOrchestrationPhase `json:"phase,omitempty"`
+	// PendingChanges describes why the reconciliation is not complete, if it is not.
+	PendingChanges string `json:"pendingChanges,omitempty"`
+	// ObservedHealth is the last health returned by the Elasticsearch API to the cluster observer.
+	ObservedHealth esv1.ElasticsearchHealth `json:"observedHealth,omitempty"`
+	// LastObservation holds the last responses of the Elasticsearch API to the cluster observer.
+	LastObservation *observer.Observation `json:"lastObservation,omitempty"`
+	// OrchestrationHints are the hints computed during the reconciliation.
+	OrchestrationHints hints.OrchestrationsHints `json:"orchestrationHints"`
+	// Expectations are the in-memory expectations registered for the cluster.
+	Expectations ExpectationsState `json:"expectations"`
+	// StatefulSets describes the StatefulSets of the cluster as seen in the operator cache.
+	StatefulSets []StatefulSetState `json:"statefulSets"`
+	// Pods describes the Pods of the cluster as seen in the operator cache.
+	Pods []PodState `json:"pods"`
+}
+
+// ExpectationsState describes the expectations registered for a cluster.
+type ExpectationsState struct {
+	// StatefulSetGenerations are the StatefulSets generations expected in the cache, by StatefulSet name.
+	StatefulSetGenerations map[string]int64 `json:"statefulSetGenerations"`
+	// PodDeletions are the UIDs of the Pods expected to be deleted, by Pod name.
+	PodDeletions map[string]types.UID `json:"podDeletions"`
+}
+
+// StatefulSetState describes a StatefulSet as seen in the operator cache.
+type StatefulSetState struct {
+	Name            string `json:"name"`
+	TemplateHash    string `json:"templateHash"`
+	ConfigHash      string `json:"configHash"`
+	Generation      int64  `json:"generation"`
+	Replicas        int32  `json:"replicas"`
+	ReadyReplicas   int32  `json:"readyReplicas"`
+	UpdatedReplicas int32  `json:"updatedReplicas"`
+	CurrentRevision string `json:"currentRevision"`
+	UpdateRevision  string `json:"updateRevision"`
+}
+
+// PodState describes a Pod as seen in the operator cache.
+type PodState struct {
+	Name         string          `json:"name"`
+	TemplateHash string          `json:"templateHash"`
+	ConfigHash   string          `json:"configHash"`
+	Revision     string          `json:"revision"`
+	Phase        corev1.PodPhase `json:"phase"`
+	Terminating  bool            `json:"terminating"`
+}
+
+// Requested returns the value of DumpAnnotation on the given cluster, and false if no dump is requested.
+func Requested(es esv1.Elasticsearch) (string, bool) {
+	request, exists := es.Annotations[DumpAnnotation]
+	return request, exists && request != ""
+}
+
+// NewState captures the given expectations along with the StatefulSets and Pods of the cluster in the cache.
+func NewState(c k8s.Client, es esv1.Elasticsearch, exp *expectations.Expectations) (State, error) {
+	state := State{
+		GeneratedAt: metav1.NewTime(time.Now()),
+		Expectations: ExpectationsState{
+			StatefulSetGenerations: map[string]int64{},
+			PodDeletions:           map[string]types.UID{},
+		},
+	}
+	for statefulSet, generation := range exp.ExpectedGenerations() {
+		state.Expectations.StatefulSetGenerations[statefulSet.Name] = generation.Generation
+	}
+	for pod, uid := range exp.ExpectedDeletions() {
+		state.Expectations.PodDeletions[pod.Name] = uid
+	}
+
+	statefulSets, err := sset.RetrieveActualStatefulSets(c, k8s.ExtractNamespacedName(&es))
+	if err != nil {
+		return State{}, err
+	}
+	state.StatefulSets = make([]StatefulSetState, 0, len(statefulSets))
+	for _, s := range statefulSets {
+		state.StatefulSets = append(state.StatefulSets, newStatefulSetState(s))
+	}
+
+	pods, err := sset.GetActualPodsForCluster(c, es)
+	if err != nil {
+		return State{}, err
+	}
+	state.Pods = make([]PodState, 0, len(pods))
+	for _, p := range pods {
+		state.Pods = append(state.Pods, PodState{
+			Name:         p.Name,
+			TemplateHash: p.Labels[hash.TemplateHashLabelName],
+			ConfigHash:   p.Annotations[nodespec.ConfigHashAnnotationName],
+			Revision:     p.Labels[appsv1.StatefulSetRevisionLabel],
+			Phase:        p.Status.Phase,
+			Terminating:  p.DeletionTimestamp != nil,
+		})
+	}
+	sort.Slice(state.Pods, func(i, j int) bool { return state.Pods[i].Name < state.Pods[j].Name })
+	return state, nil
+}
+
+func newStatefulSetState(s appsv1.StatefulSet) StatefulSetState {
+	return StatefulSetState{
+		Name:            s.Name,
+		TemplateHash:    s.Labels[hash.TemplateHashLabelName],
+		ConfigHash:      s.Spec.Template.Annotations[nodespec.ConfigHashAnnotationName],
+		Generation:      s.Generation,
+		Replicas:        statefulset.GetReplicas(s),
+		ReadyReplicas:   s.Status.ReadyReplicas,
+		UpdatedReplicas: s.Status.UpdatedReplicas,
+		CurrentRevision: s.Status.CurrentRevision,
+		UpdateRevision:  s.Status.UpdateRevision,
+	}
+}
+
+// Dump writes the given state into the diagnostics ConfigMap of the cluster, unless the given dump request has
+// already been processed.
+func Dump(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, request string, state State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	expected := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        esv1.DiagnosticsConfigMap(es.Name),
+			Namespace:   es.Namespace,
+			Labels:      label.NewLabels(k8s.ExtractNamespacedName(&es)),
+			Annotations: map[string]string{dumpRequestAnnotation: request},
+		},
+		Data: map[string]string{StateKey: string(data)},
+	}
+	reconciled := &corev1.ConfigMap{}
+	return reconciler.ReconcileResource(
+		reconciler.Params{
+			Context:    ctx,
+			Client:     c,
+			Owner:      &es,
+			Expected:   &expected,
+			Reconciled: reconciled,
+			NeedsUpdate: func() bool {
+				return reconciled.Annotations[dumpRequestAnnotation] != request
+			},
+			ServerSideApply: true,
+		},
+	)
+}
@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestRequested(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "es"}}
+	_, requested := Requested(es)
+	assert.False(t, requested)
+
+	es.Annotations = map[string]string{DumpAnnotation: ""}
+	_, requested = Requested(es)
+	assert.False(t, requested)
+
+	es.Annotations[DumpAnnotation] = "1"
+	request, requested := Requested(es)
+	assert.True(t, requested)
+	assert.Equal(t, "1", request)
+}
+
+func TestDump(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", UID: "es-uid"}}
+	sset := statefulset.TestSset{Namespace: "ns", Name: "es-es-default", ClusterName: "es", Replicas: 2}
+	statefulSet := sset.BuildPtr()
+	statefulSet.Spec.Template.Annotations = map[string]string{nodespec.ConfigHashAnnotationName: "config-hash"}
+	c := k8s.NewFakeClient(append(sset.Pods(), &es, statefulSet)...)
+
+	exp := expectations.NewExpectations(c)
+	exp.ExpectGeneration(sset.Build())
+	exp.ExpectDeletion(corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-es-default-1", UID: "pod-uid"}})
+
+	state, err := NewState(c, es, exp)
+	require.NoError(t, err)
+	state.Phase = esv1.ElasticsearchApplyingChangesPhase
+	assert.Equal(t, map[string]int64{"es-es-default": 0}, state.Expectations.StatefulSetGenerations)
+	assert.Equal(t, map[string]types.UID{"es-es-default-1": "pod-uid"}, state.Expectations.PodDeletions)
+	require.Len(t, state.StatefulSets, 1)
+	assert.Equal(t, "es-es-default", state.StatefulSets[0].Name)
+	assert.Equal(t, int32(2), state.StatefulSets[0].Replicas)
+	assert.NotEmpty(t, state.StatefulSets[0].TemplateHash)
+	assert.Equal(t, "config-hash", state.StatefulSets[0].ConfigHash)
+	require.Len(t, state.Pods, 2)
+	assert.Equal(t, "es-es-default-0", state.Pods[0].Name)
+	// expectations are left untouched
+	assert.Len(t, exp.ExpectedGenerations(), 1)
+	assert.Len(t, exp.ExpectedDeletions(), 1)
+
+	dumped := func() (string, State) {
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "es-es-diagnostics"}, &cm))
+		var s State
+		require.NoError(t, json.Unmarshal([]byte(cm.Data[StateKey]), &s))
+		return cm.Annotations[dumpRequestAnnotation], s
+	}
+
+	require.NoError(t, Dump(context.Background(), c, es, "1", state))
+	request, s := dumped()
+	assert.Equal(t, "1", request)
+	assert.Equal(t, esv1.ElasticsearchApplyingChangesPhase, s.Phase)
+	assert.Len(t, s.Pods, 2)
+
+	// the same request is not processed twice
+	state.Phase = esv1.ElasticsearchReadyPhase
+	require.NoError(t, Dump(context.Background(), c, es, "1", state))
+	_, s = dumped()
+	assert.Equal(t, esv1.ElasticsearchApplyingChangesPhase, s.Phase)
+
+	// a new request overwrites the previous dump
+	require.NoError(t, Dump(context.Background(), c, es, "2", state))
+	request, s = dumped()
+	assert.Equal(t, "2", request)
+	assert.Equal(t, esv1.ElasticsearchReadyPhase, s.Phase)
+}
@@ -0,0 +1,60 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package diagnostics
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+)
+
+// KubernetesReader reads from the Kubernetes API server the diagnostics data which is not available in the operator
+// cache.
+type KubernetesReader interface {
+	// Events returns the events of the given namespace.
+	Events(ctx context.Context, namespace string) ([]corev1.Event, error)
+	// Logs returns the last lines of the logs of the given container of the Pod, up to limitBytes.
+	Logs(ctx context.Context, pod corev1.Pod, container string, limitBytes int64) ([]byte, error)
+}
+
+// NewKubernetesReader returns a KubernetesReader relying on the Kubernetes API server reached with the given
+// configuration.
+func NewKubernetesReader(cfg *rest.Config) KubernetesReader {
+	return &kubernetesReader{cfg: cfg}
+}
+
+type kubernetesReader struct {
+	cfg *rest.Config
+}
+
+var _ KubernetesReader = &kubernetesReader{}
+
+func (r *kubernetesReader) Events(ctx context.Context, namespace string) ([]corev1.Event, error) {
+	client, err := corev1client.NewForConfig(r.cfg)
+	if err != nil {
+		return nil, err
+	}
+	events, err := client.Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return events.Items, nil
+}
+
+func (r *kubernetesReader) Logs(ctx context.Context, pod corev1.Pod, container string, limitBytes int64) ([]byte, error) {
+	client, err := corev1client.NewForConfig(r.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  container,
+		TailLines:  ptr.To[int64](logsTailLines),
+		LimitBytes: ptr.To(limitBytes),
+	}).DoRaw(ctx)
+}
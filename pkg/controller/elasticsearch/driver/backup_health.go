@@ -0,0 +1,112 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+const (
+	// backupHealthCheckInterval is the interval at which the snapshot lifecycle policies are checked again, since
+	// a failed snapshot does not trigger any reconciliation.
+	backupHealthCheckInterval = 5 * time.Minute
+)
+
+// slmMinVersion is the first version of Elasticsearch with snapshot lifecycle management.
+var slmMinVersion = version.MinFor(7, 4, 0)
+
+// reportBackupHealth checks the snapshot lifecycle management (SLM) policies of clusters with a registered writable
+// snapshot repository, and reports failing or missing backups in the BackupHealthy condition, in events, and in metrics.
+func reportBackupHealth(ctx context.Context, esClient esclient.Client, es esv1.Elasticsearch, reconcileState *reconcile.State) error {
+	if esClient.Version().LT(slmMinVersion) {
+		return nil
+	}
+	repositories, err := esClient.GetSnapshotRepositories(ctx)
+	if err != nil {
+		return err
+	}
+
+	// reset the metrics of the cluster to not report policies that do not exist anymore
+	metrics.DeleteElasticsearchSnapshotMetrics(k8s.ExtractNamespacedName(&es))
+	if !hasWritableRepository(repositories) {
+		// only clusters that were backed up at some point get the condition
+		if es.Status.Conditions.Index(esv1.BackupHealthy) >= 0 {
+			reconcileState.ReportCondition(esv1.BackupHealthy, corev1.ConditionUnknown, "No writable snapshot repository is registered")
+		}
+		return nil
+	}
+
+	slmStatus, err := esClient.GetSnapshotLifecycleStatus(ctx)
+	if err != nil {
+		return err
+	}
+	policies, err := esClient.GetSnapshotLifecyclePolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	var messages []string
+	if slmStatus.OperationMode != esclient.SnapshotLifecycleRunningMode {
+		messages = append(messages, fmt.Sprintf("Snapshot lifecycle management is %s", slmStatus.OperationMode))
+	}
+	if len(policies) == 0 {
+		messages = append(messages, "No snapshot lifecycle policy is configured")
+	}
+	ids := make([]string, 0, len(policies))
+	for id := range policies {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	now := time.Now()
+	var failedPolicies []string
+	for _, id := range ids {
+		policy := policies[id]
+		if policy.LastSuccess != nil {
+			age := now.Sub(time.UnixMilli(policy.LastSuccess.TimeMillis))
+			metrics.ElasticsearchSnapshotLastSuccessAge.WithLabelValues(es.Namespace, es.Name, id).Set(age.Seconds())
+		}
+		if policy.LastFailure != nil && (policy.LastSuccess == nil || policy.LastFailure.TimeMillis > policy.LastSuccess.TimeMillis) {
+			failedPolicies = append(failedPolicies, id)
+		}
+	}
+	if len(failedPolicies) > 0 {
+		messages = append(messages, fmt.Sprintf("Last snapshot failed for snapshot lifecycle policies %s", strings.Join(failedPolicies, ", ")))
+	}
+
+	metrics.ElasticsearchBackupHealthy.WithLabelValues(es.Namespace, es.Name).Set(boolToFloat(len(messages) == 0))
+	if len(messages) > 0 {
+		msg := strings.Join(messages, ". ")
+		reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnhealthy, "Backups are unhealthy: "+msg)
+		reconcileState.ReportCondition(esv1.BackupHealthy, corev1.ConditionFalse, msg)
+	} else {
+		reconcileState.ReportCondition(esv1.BackupHealthy, corev1.ConditionTrue, "No snapshot lifecycle policy failed its last snapshot")
+	}
+	return nil
+}
+
+// hasWritableRepository returns true if snapshots can be taken into one of the given repositories, read-only
+// repositories being only used to restore snapshots.
+func hasWritableRepository(repositories map[string]esclient.SnapshotRepository) bool {
+	for _, repository := range repositories {
+		if fmt.Sprintf("%v", repository.Settings["readonly"]) != "true" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+type backupHealthESClient struct {
+	esclient.Client
+	version      version.Version
+	repositories map[string]esclient.SnapshotRepository
+	slmStatus    esclient.SnapshotLifecycleStatus
+	policies     map[string]esclient.SnapshotLifecyclePolicyInfo
+}
+
+func (c *backupHealthESClient) Version() version.Version {
+	return c.version
+}
+
+func (c *backupHealthESClient) GetSnapshotRepositories(_ context.Context) (map[string]esclient.SnapshotRepository, error) {
+	return c.repositories, nil
+}
+
+func (c *backupHealthESClient) GetSnapshotLifecycleStatus(_ context.Context) (esclient.SnapshotLifecycleStatus, error) {
+	return c.slmStatus, nil
+}
+
+func (c *backupHealthESClient) GetSnapshotLifecyclePolicies(_ context.Context) (map[string]esclient.SnapshotLifecyclePolicyInfo, error) {
+	return c.policies, nil
+}
+
+func snapshotInvocation(ago time.Duration) *esclient.SnapshotInvocation {
+	return &esclient.SnapshotInvocation{TimeMillis: time.Now().Add(-ago).UnixMilli()}
+}
+
+func Test_reportBackupHealth(t *testing.T) {
+	repositories := map[string]esclient.SnapshotRepository{"backups": {Type: "fs"}}
+	running := esclient.SnapshotLifecycleStatus{OperationMode: esclient.SnapshotLifecycleRunningMode}
+	tests := []struct {
+		name          string
+		version       string
+		conditions    commonv1alpha1.Conditions
+		repositories  map[string]esclient.SnapshotRepository
+		slmStatus     esclient.SnapshotLifecycleStatus
+		policies      map[string]esclient.SnapshotLifecyclePolicyInfo
+		wantCondition corev1.ConditionStatus
+		wantMessage   string
+		wantEvents    int
+		wantHealthy   float64
+		wantAges      map[string]float64
+	}{
+		{
+			name:    "version without snapshot lifecycle management",
+			version: "7.3.0",
+		},
+		{
+			name:    "no snapshot repository",
+			version: "8.15.0",
+		},
+		{
+			name:          "snapshot repository removed",
+			version:       "8.15.0",
+			conditions:    commonv1alpha1.Conditions{{Type: esv1.BackupHealthy, Status: corev1.ConditionTrue}},
+			wantCondition: corev1.ConditionUnknown,
+			wantMessage:   "No writable snapshot repository is registered",
+		},
+		{
+			name:         "read-only snapshot repository",
+			version:      "8.15.0",
+			repositories: map[string]esclient.SnapshotRepository{"backups": {Type: "fs", Settings: map[string]interface{}{"readonly": "true"}}},
+		},
+		{
+			name:         "policies succeeded",
+			version:      "8.15.0",
+			repositories: repositories,
+			slmStatus:    running,
+			policies: map[string]esclient.SnapshotLifecyclePolicyInfo{
+				"daily":  {LastSuccess: snapshotInvocation(time.Hour), LastFailure: snapshotInvocation(48 * time.Hour)},
+				"hourly": {LastSuccess: snapshotInvocation(time.Minute)},
+			},
+			wantCondition: corev1.ConditionTrue,
+			wantMessage:   "No snapshot lifecycle policy failed its last snapshot",
+			wantHealthy:   1,
+			wantAges:      map[string]float64{"daily": time.Hour.Seconds(), "hourly": time.Minute.Seconds()},
+		},
+		{
+			name:         "policies failed",
+			version:      "8.15.0",
+			repositories: repositories,
+			slmStatus:    running,
+			policies: map[string]esclient.SnapshotLifecyclePolicyInfo{
+				"daily":  {LastSuccess: snapshotInvocation(48 * time.Hour), LastFailure: snapshotInvocation(time.Hour)},
+				"hourly": {LastSuccess: snapshotInvocation(time.Minute)},
+				"weekly": {LastFailure: snapshotInvocation(time.Hour)},
+			},
+			wantCondition: corev1.ConditionFalse,
+			wantMessage:   "Last snapshot failed for snapshot lifecycle policies daily, weekly",
+			wantEvents:    1,
+			wantAges:      map[string]float64{"daily": (48 * time.Hour).Seconds(), "hourly": time.Minute.Seconds()},
+		},
+		{
+			name:          "snapshot lifecycle management stopped without policy",
+			version:       "8.15.0",
+			repositories:  repositories,
+			slmStatus:     esclient.SnapshotLifecycleStatus{OperationMode: "STOPPED"},
+			wantCondition: corev1.ConditionFalse,
+			wantMessage:   "Snapshot lifecycle management is STOPPED. No snapshot lifecycle policy is configured",
+			wantEvents:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+				Status:     esv1.ElasticsearchStatus{Conditions: tt.conditions},
+			}
+			defer metrics.DeleteElasticsearchSnapshotMetrics(k8s.ExtractNamespacedName(&es))
+			reconcileState := reconcile.MustNewState(es)
+			esClient := &backupHealthESClient{
+				version:      version.MustParse(tt.version),
+				repositories: tt.repositories,
+				slmStatus:    tt.slmStatus,
+				policies:     tt.policies,
+			}
+
+			require.NoError(t, reportBackupHealth(context.Background(), esClient, es, reconcileState))
+
+			index := reconcileState.Conditions.Index(esv1.BackupHealthy)
+			if tt.wantCondition == "" {
+				require.Equal(t, -1, index)
+				require.Equal(t, 0, testutil.CollectAndCount(metrics.ElasticsearchBackupHealthy))
+				return
+			}
+			require.GreaterOrEqual(t, index, 0)
+			require.Equal(t, tt.wantCondition, reconcileState.Conditions[index].Status)
+			require.Equal(t, tt.wantMessage, reconcileState.Conditions[index].Message)
+			require.Len(t, reconcileState.Events(), tt.wantEvents)
+
+			if tt.repositories == nil {
+				return
+			}
+			require.Equal(t, tt.wantHealthy, testutil.ToFloat64(metrics.ElasticsearchBackupHealthy.WithLabelValues("ns", "es")))
+			require.Equal(t, len(tt.wantAges), testutil.CollectAndCount(metrics.ElasticsearchSnapshotLastSuccessAge))
+			for policy, age := range tt.wantAges {
+				value := testutil.ToFloat64(metrics.ElasticsearchSnapshotLastSuccessAge.WithLabelValues("ns", "es", policy))
+				require.InDelta(t, age, value, 60, policy)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+const (
+	// diskUsageCheckInterval is the interval at which the disk usage of the nodes is checked again, since running out
+	// of disk space does not trigger any reconciliation.
+	diskUsageCheckInterval = 5 * time.Minute
+
+	diskWatermarkHighSetting             = "cluster.routing.allocation.disk.watermark.high"
+	diskWatermarkFloodStageSetting       = "cluster.routing.allocation.disk.watermark.flood_stage"
+	diskWatermarkFloodStageFrozenSetting = "cluster.routing.allocation.disk.watermark.flood_stage.frozen"
+	diskWatermarkMaxHeadroomSuffix       = ".max_headroom"
+
+	highWatermark       = "high"
+	floodStageWatermark = "flood_stage"
+)
+
+// defaultDiskWatermarks are the Elasticsearch default values of the disk watermarks, used if they cannot be found in
+// the cluster settings.
+var defaultDiskWatermarks = map[string]string{
+	diskWatermarkHighSetting:             "90%",
+	diskWatermarkFloodStageSetting:       "95%",
+	diskWatermarkFloodStageFrozenSetting: "95%",
+}
+
+// reportDiskUsage retrieves the disk usage of the Elasticsearch nodes, and reports the nodes exceeding the high or
+// the flood stage disk watermarks in the DiskSpaceAvailable condition, in events, and in metrics.
+func reportDiskUsage(ctx context.Context, esClient esclient.Client, es esv1.Elasticsearch, reconcileState *reconcile.State) error {
+	settings, err := esClient.GetClusterSettingsWithDefaults(ctx)
+	if err != nil {
+		return err
+	}
+	nodesStats, err := esClient.GetNodesFSStats(ctx)
+	if err != nil {
+		return err
+	}
+	watermarks := map[string]diskWatermark{}
+	for _, setting := range []string{diskWatermarkHighSetting, diskWatermarkFloodStageSetting, diskWatermarkFloodStageFrozenSetting} {
+		watermark, err := parseDiskWatermark(settings, setting)
+		if err != nil {
+			return err
+		}
+		watermarks[setting] = watermark
+	}
+
+	// reset the metrics of the cluster to not report nodes that do not exist anymore
+	metrics.DeleteElasticsearchDiskMetrics(k8s.ExtractNamespacedName(&es))
+	var highNodes, floodStageNodes []string
+	for _, node := range nodesStats.Nodes {
+		total, available := node.FS.Total.TotalInBytes, node.FS.Total.AvailableInBytes
+		if total <= 0 {
+			continue
+		}
+		metrics.ElasticsearchDiskUsageRatio.WithLabelValues(es.Namespace, es.Name, node.Name).Set(float64(total-available) / float64(total))
+
+		// dedicated frozen nodes fill their disk with the shared cache, only the frozen flood stage watermark applies
+		high, floodStage := watermarks[diskWatermarkHighSetting], watermarks[diskWatermarkFloodStageSetting]
+		if isDedicatedFrozenNode(node) {
+			high, floodStage = diskWatermark{}, watermarks[diskWatermarkFloodStageFrozenSetting]
+		}
+		highExceeded := high.exceeded(total, available)
+		floodStageExceeded := floodStage.exceeded(total, available)
+		metrics.ElasticsearchDiskWatermarkExceeded.WithLabelValues(es.Namespace, es.Name, node.Name, highWatermark).Set(boolToFloat(highExceeded))
+		metrics.ElasticsearchDiskWatermarkExceeded.WithLabelValues(es.Namespace, es.Name, node.Name, floodStageWatermark).Set(boolToFloat(floodStageExceeded))
+		switch {
+		case floodStageExceeded:
+			floodStageNodes = append(floodStageNodes, node.Name)
+		case highExceeded:
+			highNodes = append(highNodes, node.Name)
+		}
+	}
+	sort.Strings(highNodes)
+	sort.Strings(floodStageNodes)
+
+	var messages []string
+	if len(floodStageNodes) > 0 {
+		msg := fmt.Sprintf("Flood stage disk watermark exceeded on nodes %s: indices with a shard on these nodes are read-only",
+			strings.Join(floodStageNodes, ", "))
+		reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnhealthy, msg)
+		messages = append(messages, msg)
+	}
+	if len(highNodes) > 0 {
+		messages = append(messages, fmt.Sprintf("High disk watermark exceeded on nodes %s: shards are relocated away from these nodes",
+			strings.Join(highNodes, ", ")))
+	}
+	if len(messages) > 0 {
+		reconcileState.ReportCondition(esv1.DiskSpaceAvailable, corev1.ConditionFalse, strings.Join(messages, ". "))
+	} else {
+		reconcileState.ReportCondition(esv1.DiskSpaceAvailable, corev1.ConditionTrue, "All nodes are below the high disk watermark")
+	}
+	return nil
+}
+
+// diskWatermark is a disk watermark, expressed either as a ratio of the disk space used, optionally capped by a
+// maximum headroom of free space, or as a minimum amount of free space.
+type diskWatermark struct {
+	// usedRatio is the ratio of the disk space used above which the watermark is exceeded, or 0 if not set.
+	usedRatio float64
+	// maxHeadroom is the maximum amount of free space required by a watermark expressed as a ratio, or -1 if not set.
+	maxHeadroom int64
+	// minFreeBytes is the free space below which the watermark is exceeded, if not expressed as a ratio.
+	minFreeBytes int64
+}
+
+// exceeded returns true if a disk with the given total and available space exceeds the watermark.
+func (w diskWatermark) exceeded(total, available int64) bool {
+	if w.usedRatio == 0 {
+		return w.minFreeBytes > 0 && available < w.minFreeBytes
+	}
+	minFree := int64(math.Ceil((1 - w.usedRatio) * float64(total)))
+	if w.maxHeadroom >= 0 && w.maxHeadroom < minFree {
+		minFree = w.maxHeadroom
+	}
+	return available < minFree
+}
+
+// parseDiskWatermark parses the given disk watermark setting and its maximum headroom.
+func parseDiskWatermark(settings esclient.ClusterSettingsWithDefaults, setting string) (diskWatermark, error) {
+	value, exists := settings.Get(setting)
+	if !exists {
+		value = defaultDiskWatermarks[setting]
+	}
+	watermark := diskWatermark{maxHeadroom: -1}
+	if ratio, ok := parseRatio(value); ok {
+		watermark.usedRatio = ratio
+		if headroom, exists := settings.Get(setting + diskWatermarkMaxHeadroomSuffix); exists {
+			bytes, err := parseByteSize(headroom)
+			if err != nil {
+				return diskWatermark{}, fmt.Errorf("while parsing %s: %w", setting+diskWatermarkMaxHeadroomSuffix, err)
+			}
+			watermark.maxHeadroom = bytes
+		}
+		return watermark, nil
+	}
+	bytes, err := parseByteSize(value)
+	if err != nil {
+		return diskWatermark{}, fmt.Errorf("while parsing %s: %w", setting, err)
+	}
+	watermark.minFreeBytes = bytes
+	return watermark, nil
+}
+
+// parseRatio parses a ratio expressed as a percentage (eg. 90%) or as a fraction (eg. 0.9).
+func parseRatio(value string) (float64, bool) {
+	if percent, isPercent := strings.CutSuffix(value, "%"); isPercent {
+		ratio, err := strconv.ParseFloat(percent, 64)
+		return ratio / 100, err == nil
+	}
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	if ratio > 1 {
+		// a number with no unit is interpreted as a percentage by Elasticsearch
+		ratio /= 100
+	}
+	return ratio, true
+}
+
+// byteSizeUnits are the units of the byte size values in the Elasticsearch settings, from the largest to the smallest.
+var byteSizeUnits = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"pb", 1 << 50}, {"p", 1 << 50},
+	{"tb", 1 << 40}, {"t", 1 << 40},
+	{"gb", 1 << 30}, {"g", 1 << 30},
+	{"mb", 1 << 20}, {"m", 1 << 20},
+	{"kb", 1 << 10}, {"k", 1 << 10},
+	{"b", 1},
+}
+
+// parseByteSize parses a byte size value of the Elasticsearch settings (eg. 150gb), -1 meaning an unset value.
+func parseByteSize(value string) (int64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "-1" {
+		return -1, nil
+	}
+	for _, unit := range byteSizeUnits {
+		if number, found := strings.CutSuffix(value, unit.suffix); found {
+			size, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid byte size %q: %w", value, err)
+			}
+			return int64(size * unit.multiplier), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid byte size %q: unknown unit", value)
+}
+
+// isDedicatedFrozenNode returns true if the node only holds data of the frozen tier.
+func isDedicatedFrozenNode(node esclient.NodeStats) bool {
+	isFrozen := false
+	for _, role := range node.Roles {
+		switch esv1.NodeRole(role) {
+		case esv1.DataFrozenRole:
+			isFrozen = true
+		case esv1.DataRole, esv1.DataHotRole, esv1.DataWarmRole, esv1.DataColdRole, esv1.DataContentRole:
+			return false
+		}
+	}
+	return isFrozen
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
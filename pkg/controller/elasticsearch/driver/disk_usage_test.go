@@ -0,0 +1,181 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+type diskUsageESClient struct {
+	esclient.Client
+	stats    esclient.NodesStats
+	settings esclient.ClusterSettingsWithDefaults
+}
+
+func (c *diskUsageESClient) GetNodesFSStats(_ context.Context) (esclient.NodesStats, error) {
+	return c.stats, nil
+}
+
+func (c *diskUsageESClient) GetClusterSettingsWithDefaults(_ context.Context) (esclient.ClusterSettingsWithDefaults, error) {
+	return c.settings, nil
+}
+
+func nodeFSStats(name string, total, available int64, roles ...string) esclient.NodeStats {
+	stats := esclient.NodeStats{Name: name, Roles: roles}
+	stats.FS.Total.TotalInBytes = total
+	stats.FS.Total.AvailableInBytes = available
+	return stats
+}
+
+func Test_reportDiskUsage(t *testing.T) {
+	const gb = 1 << 30
+	tests := []struct {
+		name             string
+		nodes            []esclient.NodeStats
+		settings         esclient.ClusterSettingsWithDefaults
+		wantCondition    corev1.ConditionStatus
+		wantMessage      string
+		wantEvents       int
+		wantExceeded     map[string]float64
+		wantErrSubstring string
+	}{
+		{
+			name:          "all nodes below the default watermarks",
+			nodes:         []esclient.NodeStats{nodeFSStats("node-0", 100*gb, 50*gb, "master", "data")},
+			wantCondition: corev1.ConditionTrue,
+			wantMessage:   "All nodes are below the high disk watermark",
+			wantExceeded:  map[string]float64{"node-0/high": 0, "node-0/flood_stage": 0},
+		},
+		{
+			name: "nodes above the default watermarks",
+			nodes: []esclient.NodeStats{
+				nodeFSStats("node-0", 100*gb, 8*gb, "data"),
+				nodeFSStats("node-1", 100*gb, 2*gb, "data"),
+				nodeFSStats("node-2", 100*gb, 50*gb, "data"),
+			},
+			wantCondition: corev1.ConditionFalse,
+			wantMessage: "Flood stage disk watermark exceeded on nodes node-1: indices with a shard on these nodes are read-only. " +
+				"High disk watermark exceeded on nodes node-0: shards are relocated away from these nodes",
+			wantEvents: 1,
+			wantExceeded: map[string]float64{
+				"node-0/high": 1, "node-0/flood_stage": 0,
+				"node-1/high": 1, "node-1/flood_stage": 1,
+				"node-2/high": 0, "node-2/flood_stage": 0,
+			},
+		},
+		{
+			name:  "dedicated frozen node only checked against the frozen flood stage watermark",
+			nodes: []esclient.NodeStats{nodeFSStats("frozen-0", 100*gb, 8*gb, "data_frozen")},
+			settings: esclient.ClusterSettingsWithDefaults{Defaults: map[string]interface{}{
+				diskWatermarkFloodStageFrozenSetting:                                  "95%",
+				diskWatermarkFloodStageFrozenSetting + diskWatermarkMaxHeadroomSuffix: "20gb",
+			}},
+			wantCondition: corev1.ConditionTrue,
+			wantMessage:   "All nodes are below the high disk watermark",
+			wantExceeded:  map[string]float64{"frozen-0/high": 0, "frozen-0/flood_stage": 0},
+		},
+		{
+			name:  "watermarks expressed in bytes and overridden in the persistent settings",
+			nodes: []esclient.NodeStats{nodeFSStats("node-0", 100*gb, 40*gb, "data")},
+			settings: esclient.ClusterSettingsWithDefaults{
+				Persistent: map[string]interface{}{diskWatermarkHighSetting: "50gb", diskWatermarkFloodStageSetting: "10gb"},
+				Defaults:   map[string]interface{}{diskWatermarkHighSetting: "90%", diskWatermarkFloodStageSetting: "95%"},
+			},
+			wantCondition: corev1.ConditionFalse,
+			wantMessage:   "High disk watermark exceeded on nodes node-0: shards are relocated away from these nodes",
+			wantExceeded:  map[string]float64{"node-0/high": 1, "node-0/flood_stage": 0},
+		},
+		{
+			name:  "max headroom caps the free space required by ratio watermarks",
+			nodes: []esclient.NodeStats{nodeFSStats("node-0", 1000*gb, 40*gb, "data")},
+			settings: esclient.ClusterSettingsWithDefaults{Defaults: map[string]interface{}{
+				diskWatermarkHighSetting:                                        "90%",
+				diskWatermarkHighSetting + diskWatermarkMaxHeadroomSuffix:       "50gb",
+				diskWatermarkFloodStageSetting:                                  "95%",
+				diskWatermarkFloodStageSetting + diskWatermarkMaxHeadroomSuffix: "20gb",
+			}},
+			wantCondition: corev1.ConditionFalse,
+			wantMessage:   "High disk watermark exceeded on nodes node-0: shards are relocated away from these nodes",
+			wantExceeded:  map[string]float64{"node-0/high": 1, "node-0/flood_stage": 0},
+		},
+		{
+			name:             "invalid watermark",
+			nodes:            []esclient.NodeStats{nodeFSStats("node-0", 100*gb, 50*gb, "data")},
+			settings:         esclient.ClusterSettingsWithDefaults{Transient: map[string]interface{}{diskWatermarkHighSetting: "lots"}},
+			wantErrSubstring: "while parsing " + diskWatermarkHighSetting,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+			defer metrics.DeleteElasticsearchDiskMetrics(k8s.ExtractNamespacedName(&es))
+			stats := esclient.NodesStats{Nodes: map[string]esclient.NodeStats{}}
+			for i, node := range tt.nodes {
+				stats.Nodes[string(rune('a'+i))] = node
+			}
+			reconcileState := reconcile.MustNewState(es)
+
+			err := reportDiskUsage(context.Background(), &diskUsageESClient{stats: stats, settings: tt.settings}, es, reconcileState)
+			if tt.wantErrSubstring != "" {
+				require.ErrorContains(t, err, tt.wantErrSubstring)
+				return
+			}
+			require.NoError(t, err)
+
+			index := reconcileState.Conditions.Index(esv1.DiskSpaceAvailable)
+			require.GreaterOrEqual(t, index, 0)
+			require.Equal(t, tt.wantCondition, reconcileState.Conditions[index].Status)
+			require.Equal(t, tt.wantMessage, reconcileState.Conditions[index].Message)
+			require.Len(t, reconcileState.Events(), tt.wantEvents)
+
+			for _, node := range tt.nodes {
+				for _, watermark := range []string{highWatermark, floodStageWatermark} {
+					value := testutil.ToFloat64(metrics.ElasticsearchDiskWatermarkExceeded.WithLabelValues("ns", "es", node.Name, watermark))
+					require.Equal(t, tt.wantExceeded[node.Name+"/"+watermark], value, "%s/%s", node.Name, watermark)
+				}
+			}
+		})
+	}
+}
+
+func Test_parseByteSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "-1", want: -1},
+		{value: "100b", want: 100},
+		{value: "1kb", want: 1024},
+		{value: "1.5gb", want: 3 << 29},
+		{value: "20GB", want: 20 << 30},
+		{value: "2t", want: 2 << 40},
+		{value: "100", wantErr: true},
+		{value: "gb", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseByteSize(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
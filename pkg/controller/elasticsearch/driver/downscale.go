@@ -13,6 +13,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
@@ -24,10 +25,12 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/shutdown"
 	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version/zen1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version/zen2"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
 )
 
@@ -71,6 +74,7 @@ func HandleDownscale(
 	if err := downscaleCtx.nodeShutdown.ReconcileShutdowns(downscaleCtx.parentCtx, leavingNodes, terminatingNodes); err != nil {
 		return results.WithError(err)
 	}
+	reportExcludedNodes(downscaleCtx, leavingNodes)
 
 	for _, downscale := range downscales {
 		// attempt the StatefulSet downscale (may or may not remove nodes)
@@ -261,7 +265,7 @@ func calculatePerformableDownscale(
 				AddEvent(
 					corev1.EventTypeNormal,
 					events.EventReasonDelayed,
-					"Requested topology change delayed by data migration. Ensure index settings allow node removal.",
+					fmt.Sprintf("Requested topology change delayed by data migration%s. Ensure index settings allow node removal.", remainingData(node, response)),
 				)
 			// no need to check other nodes since we remove them in order and this one isn't ready anyway
 			return performableDownscale, nil
@@ -273,6 +277,35 @@ func calculatePerformableDownscale(
 	return performableDownscale, nil
 }
 
+// reportExcludedNodes emits an event for each node from which data starts to be migrated away.
+func reportExcludedNodes(downscaleCtx downscaleContext, leavingNodes []string) {
+	previouslyExcluded := set.Make(downscaleCtx.es.Status.DownscaleOperation.ExcludedNodes...)
+	for _, node := range leavingNodes {
+		if previouslyExcluded.Has(node) {
+			continue
+		}
+		downscaleCtx.reconcileState.AddEvent(
+			corev1.EventTypeNormal,
+			events.EventReasonDataMigration,
+			fmt.Sprintf("Migrating data away from node %s before removing it from the cluster", node),
+		)
+	}
+}
+
+// remainingData describes the data remaining on a node being shut down, if known.
+func remainingData(node string, status shutdown.NodeShutdownStatus) string {
+	if status.RemainingShards == nil {
+		return ""
+	}
+	if status.RemainingBytes == nil {
+		return fmt.Sprintf(", %d shards remaining on node %s", *status.RemainingShards, node)
+	}
+	return fmt.Sprintf(
+		", %d shards (%s) remaining on node %s",
+		*status.RemainingShards, resource.NewQuantity(*status.RemainingBytes, resource.BinarySI).String(), node,
+	)
+}
+
 // doDownscale schedules nodes removal for the given downscale, and updates zen settings accordingly.
 func doDownscale(downscaleCtx downscaleContext, downscale ssetDownscale, actualStatefulSets es_sset.StatefulSetList) error {
 	ssetLogger(downscaleCtx.parentCtx, downscale.statefulSet).Info(
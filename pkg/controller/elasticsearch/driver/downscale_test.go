@@ -20,6 +20,7 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/comparison"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
@@ -199,15 +200,27 @@ func TestHandleDownscale(t *testing.T) {
 	require.Equal(t, "ssetMaster1Replicas-0,ssetData4Replicas-3,ssetData4Replicas-2", esClient.ExcludeFromShardAllocationCalledWith)
 
 	// status should reflect the in progress operations
+	downscaleOperation := reconcileState.MergeStatusReportingWith(esv1.ElasticsearchStatus{}).DownscaleOperation
 	require.Equal(t,
 		[]esv1.DownscaledNode{
-			{Name: "ssetData4Replicas-2", ShutdownStatus: "IN_PROGRESS"},
+			{Name: "ssetData4Replicas-2", ShutdownStatus: "IN_PROGRESS", RemainingShards: ptr.To(1), RemainingBytes: ptr.To[int64](0)},
 			{Name: "ssetData4Replicas-3", ShutdownStatus: "COMPLETE"},
 			{Name: "ssetMaster1Replicas-0", ShutdownStatus: "COMPLETE"},
 			{Name: "ssetMaster3Replicas-1", ShutdownStatus: "NOT_STARTED"},
 			{Name: "ssetMaster3Replicas-2", ShutdownStatus: "NOT_STARTED"},
 		},
-		reconcileState.MergeStatusReportingWith(esv1.ElasticsearchStatus{}).DownscaleOperation.Nodes,
+		downscaleOperation.Nodes,
+	)
+	require.Equal(t, []string{"ssetData4Replicas-2", "ssetData4Replicas-3", "ssetMaster1Replicas-0"}, downscaleOperation.ExcludedNodes)
+	// an event should be emitted for each node from which data starts to be migrated, and for the delayed downscale
+	require.Equal(t,
+		[]events.Event{
+			{EventType: corev1.EventTypeNormal, Reason: events.EventReasonDataMigration, Message: "Migrating data away from node ssetMaster1Replicas-0 before removing it from the cluster"},
+			{EventType: corev1.EventTypeNormal, Reason: events.EventReasonDataMigration, Message: "Migrating data away from node ssetData4Replicas-3 before removing it from the cluster"},
+			{EventType: corev1.EventTypeNormal, Reason: events.EventReasonDataMigration, Message: "Migrating data away from node ssetData4Replicas-2 before removing it from the cluster"},
+			{EventType: corev1.EventTypeNormal, Reason: events.EventReasonDelayed, Message: "Requested topology change delayed by data migration, 1 shards (0) remaining on node ssetData4Replicas-2. Ensure index settings allow node removal."},
+		},
+		reconcileState.Events(),
 	)
 
 	// only part of the expected replicas of ssetMaster1Replicas should be updated,
@@ -251,7 +264,7 @@ func TestHandleDownscale(t *testing.T) {
 	// status should reflect the in progress operations
 	require.Equal(t,
 		[]esv1.DownscaledNode{
-			{Name: "ssetData4Replicas-2", ShutdownStatus: "IN_PROGRESS"},
+			{Name: "ssetData4Replicas-2", ShutdownStatus: "IN_PROGRESS", RemainingShards: ptr.To(1), RemainingBytes: ptr.To[int64](0)},
 			{Name: "ssetData4Replicas-3", ShutdownStatus: "COMPLETE"},
 			{Name: "ssetMaster1Replicas-0", ShutdownStatus: "COMPLETE"},
 			{Name: "ssetMaster3Replicas-1", ShutdownStatus: "NOT_STARTED"},
@@ -282,7 +295,7 @@ func TestHandleDownscale(t *testing.T) {
 	// status should reflect the in progress operations
 	require.Equal(t,
 		[]esv1.DownscaledNode{
-			{Name: "ssetData4Replicas-2", ShutdownStatus: "IN_PROGRESS"},
+			{Name: "ssetData4Replicas-2", ShutdownStatus: "IN_PROGRESS", RemainingShards: ptr.To(1), RemainingBytes: ptr.To[int64](0)},
 			{Name: "ssetData4Replicas-3", ShutdownStatus: "COMPLETE"},
 			{Name: "ssetMaster1Replicas-0", ShutdownStatus: "COMPLETE"},
 			{Name: "ssetMaster3Replicas-1", ShutdownStatus: "COMPLETE"},
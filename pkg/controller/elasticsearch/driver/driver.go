@@ -23,10 +23,13 @@ import (
 	commondriver "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/ingress"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	commonlicense "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemonitor"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/bootstrap"
@@ -85,6 +88,8 @@ type DefaultDriverParameters struct {
 	// Client is used to access the Kubernetes API.
 	Client   k8s.Client
 	Recorder record.EventRecorder
+	// PodExecutor is used to update the keystore of the Elasticsearch nodes in place.
+	PodExecutor k8s.PodExecutor
 
 	// LicenseChecker is used for some features to check if an appropriate license is setup
 	LicenseChecker commonlicense.Checker
@@ -98,6 +103,8 @@ type DefaultDriverParameters struct {
 	// Expectations control some expectations set on resources in the cache, in order to
 	// avoid doing certain operations if the cache hasn't seen an up-to-date resource yet.
 	Expectations *expectations.Expectations
+	// StateCache caches the shards and nodes of the cluster between reconciliations, as long as its state does not change.
+	StateCache *esclient.StateCache
 }
 
 // defaultDriver is the default Driver implementation
@@ -149,6 +156,11 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		return results.WithError(err)
 	}
 
+	profileServices, err := reconcileServiceProfileServices(ctx, d.Client, d.ES)
+	if err != nil {
+		return results.WithError(err)
+	}
+
 	resourcesState, err := reconcile.NewResourcesStateFromAPI(d.Client, d.ES)
 	if err != nil {
 		return results.WithError(err)
@@ -165,16 +177,39 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		ctx,
 		d,
 		d.ES,
-		[]corev1.Service{*externalService, *internalService},
+		append([]corev1.Service{*externalService, *internalService}, profileServices...),
 		d.OperatorParameters.GlobalCA,
-		d.OperatorParameters.CACertRotation,
-		d.OperatorParameters.CertRotation,
+		d.OperatorParameters.CACertRotation.Get(),
+		d.OperatorParameters.CertRotation.Get(),
 	)
 	results.WithResults(res)
 	if res != nil && res.HasError() {
 		return results
 	}
 
+	exposureParams := ingress.Params{
+		Owner:   &d.ES,
+		HTTP:    d.ES.Spec.HTTP,
+		Service: *externalService,
+		Labels:  label.NewLabels(k8s.ExtractNamespacedName(&d.ES)),
+		Namer:   esv1.ESNamer,
+	}
+	if err := ingress.Reconcile(ctx, d.Client, exposureParams); err != nil {
+		return results.WithError(err)
+	}
+	if err := gateway.Reconcile(ctx, d.Client, exposureParams); err != nil {
+		return results.WithError(err)
+	}
+	if err := servicemonitor.Reconcile(ctx, d.Client, servicemonitor.Params{
+		Owner:      &d.ES,
+		Prometheus: d.ES.Spec.Monitoring.Prometheus,
+		Name:       esv1.ESNamer.Suffix(d.ES.Name, "metrics"),
+		Labels:     label.NewLabels(k8s.ExtractNamespacedName(&d.ES)),
+		Selector:   label.NewLabels(k8s.ExtractNamespacedName(&d.ES)),
+	}); err != nil {
+		return results.WithError(err)
+	}
+
 	// start the ES observer
 	minVersion, err := version.MinInPods(resourcesState.CurrentPods, label.VersionLabelName)
 	if err != nil {
@@ -211,8 +246,8 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		d,
 		d.ES,
 		d.OperatorParameters.GlobalCA,
-		d.OperatorParameters.CACertRotation,
-		d.OperatorParameters.CertRotation,
+		d.OperatorParameters.CACertRotation.Get(),
+		d.OperatorParameters.CertRotation.Get(),
 	)
 	results.WithResults(res)
 	if res != nil && res.HasError() {
@@ -223,6 +258,10 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 	// reconciliation loop as we don't want to prevent other updates from being applied to the cluster.
 	results.WithResults(annotatePodsWithNodeLabels(ctx, d.Client, d.ES))
 
+	// Patch the Pods to target them with the Services of the service profiles they match. As above, errors do not stop
+	// the reconciliation loop.
+	results.WithResults(labelPodsWithServiceProfiles(ctx, d.Client, d.ES))
+
 	if err := d.verifySupportsExistingPods(resourcesState.CurrentPods); err != nil {
 		if !d.ES.IsConfiguredToAllowDowngrades() {
 			return results.WithError(err)
@@ -239,6 +278,9 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		trustedHTTPCertificates,
 	)
 	defer esClient.Close()
+	if d.StateCache != nil {
+		esClient = d.StateCache.Client(esClient)
+	}
 
 	// use unknown health as a proxy for a cluster not responding to requests
 	hasKnownHealthState := observedState() != esv1.ElasticsearchUnknownHealth
@@ -308,6 +350,43 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		}
 	}
 
+	// report the state of the connections to the remote clusters, checked periodically since a lost connection does not
+	// trigger any reconciliation
+	if esReachable {
+		if err := remotecluster.ReportStatus(ctx, esClient, d.ES, d.ReconcileState); err != nil {
+			log.Info("Could not report the state of the remote cluster connections", "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		}
+		if len(d.ES.Spec.RemoteClusters) > 0 {
+			results.WithReconciliationState(reconciler.RequeueAfter(remotecluster.StatusCheckInterval).ReconciliationComplete())
+		}
+	}
+
+	// report the disk usage of the nodes, checked periodically since it does not trigger any reconciliation
+	if esReachable {
+		if err := reportDiskUsage(ctx, esClient, d.ES, d.ReconcileState); err != nil {
+			log.Info("Could not report the disk usage of the Elasticsearch nodes", "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		}
+		results.WithReconciliationState(reconciler.RequeueAfter(diskUsageCheckInterval).ReconciliationComplete())
+	}
+
+	// report the health of the backups, checked periodically since failed snapshots do not trigger any reconciliation
+	if esReachable {
+		if err := reportBackupHealth(ctx, esClient, d.ES, d.ReconcileState); err != nil {
+			log.Info("Could not report the health of the Elasticsearch backups", "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		}
+		results.WithReconciliationState(reconciler.RequeueAfter(backupHealthCheckInterval).ReconciliationComplete())
+	}
+
+	// attempt the known remediations of a stalled reconciliation, if enabled
+	if esReachable && d.OperatorParameters.ReconciliationStalledRemediation {
+		if err := remediateStalledReconciliation(ctx, esClient, d.ES, d.ReconcileState); err != nil {
+			log.Info("Could not remediate the stalled reconciliation", "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		}
+	}
+
+	// restore the initial snapshot into a new cluster before reporting it as ready
+	results.WithResults(reconcileInitialRestore(ctx, esClient, d.ES, esReachable, d.ReconcileState))
+
 	// Compute seed hosts based on current masters with a podIP
 	if err := settings.UpdateSeedHostsConfigMap(ctx, d.Client, d.ES, resourcesState.AllPods); err != nil {
 		return results.WithError(err)
@@ -321,7 +400,7 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		}
 	}
 
-	keystoreParams := initcontainer.KeystoreParams
+	keystoreParams := initcontainer.KeystoreParamsFor(d.ES)
 	keystoreSecurityContext := securitycontext.For(d.Version, true)
 	keystoreParams.SecurityContext = &keystoreSecurityContext
 
@@ -338,6 +417,13 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		return results.WithError(err)
 	}
 
+	// register the snapshot repositories of the specification, once their credentials are loaded by all the nodes
+	secureSettingsLoaded := keystoreResources == nil || d.ReconcileState.OrchestrationHints().SecureSettingsHash == keystoreResources.Hash
+	results.WithResults(reconcileSnapshotRepositories(ctx, esClient, d.ES, esReachable, secureSettingsLoaded, d.ReconcileState))
+
+	// configure the snapshot lifecycle policies of the specification, and report their executions
+	results.WithResults(reconcileSnapshotLifecyclePolicies(ctx, esClient, d.ES, esReachable, d.ReconcileState))
+
 	// set an annotation with the ClusterUUID, if bootstrapped
 	requeue, err := bootstrap.ReconcileClusterUUID(ctx, d.Client, &d.ES, esClient, esReachable)
 	if err != nil {
@@ -0,0 +1,161 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/bootstrap"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileInitialRestore restores the snapshot specified in spec.initialRestore into a new cluster. The reconciliation
+// is reported as incomplete until the restore completed, so that the cluster is not reported as ready before its data
+// is restored.
+func reconcileInitialRestore(
+	ctx context.Context,
+	esClient esclient.Client,
+	es esv1.Elasticsearch,
+	esReachable bool,
+	reconcileState *reconcile.State,
+) *reconciler.Results {
+	results := reconciler.NewResult(ctx)
+	if es.Spec.InitialRestore == nil {
+		return results
+	}
+
+	status := es.Status.InitialRestore.DeepCopy()
+	if status == nil {
+		// the snapshot is only restored into new clusters, which are not bootstrapped yet
+		if bootstrap.AnnotatedForBootstrap(es) {
+			return results
+		}
+		status = &esv1.InitialRestoreStatus{Phase: esv1.InitialRestorePendingPhase}
+	}
+
+	switch {
+	case status.Phase == esv1.InitialRestoreCompletedPhase, status.Phase == esv1.InitialRestoreFailedPhase:
+		// nothing to do
+	case !esReachable:
+		status.Message = "Waiting for Elasticsearch to be reachable"
+	case status.Phase == esv1.InitialRestoreInProgressPhase:
+		checkInitialRestoreProgress(ctx, esClient, es, status)
+	default:
+		startInitialRestore(ctx, esClient, es, status, reconcileState)
+	}
+
+	switch status.Phase {
+	case esv1.InitialRestorePendingPhase:
+		results.WithReconciliationState(defaultRequeue.WithReason("Initial snapshot restore pending: " + status.Message))
+	case esv1.InitialRestoreInProgressPhase:
+		results.WithReconciliationState(defaultRequeue.WithReason(fmt.Sprintf("Restoring initial snapshot %s", status.Snapshot)))
+	case esv1.InitialRestoreFailedPhase:
+		// keep the cluster not ready until the restore is removed from the specification
+		results.WithReconciliationState(defaultRequeue.WithReason("Initial snapshot restore failed: " + status.Message))
+	}
+	reconcileState.UpdateInitialRestore(status)
+	return results
+}
+
+// startInitialRestore registers the repository and starts the restore of the most recent snapshot matching the
+// specification, updating the given status accordingly.
+func startInitialRestore(
+	ctx context.Context,
+	esClient esclient.Client,
+	es esv1.Elasticsearch,
+	status *esv1.InitialRestoreStatus,
+	reconcileState *reconcile.State,
+) {
+	spec := es.Spec.InitialRestore
+	repository := initialRestoreRepository(spec.Repository)
+	if err := esClient.UpdateSnapshotRepository(ctx, spec.Repository.Name, repository, true); err != nil {
+		status.Message = fmt.Sprintf("Failed to register snapshot repository %s: %s", spec.Repository.Name, esclient.ErrorReason(err))
+		return
+	}
+
+	snapshots, err := esClient.GetSnapshots(ctx, spec.Repository.Name, spec.Snapshot)
+	if err != nil {
+		status.Message = esclient.ErrorReason(err)
+		return
+	}
+	snapshot, found := esclient.LatestSuccessfulSnapshot(snapshots)
+	if !found {
+		status.Message = fmt.Sprintf("No successful snapshot matching %s in repository %s", spec.Snapshot, spec.Repository.Name)
+		return
+	}
+
+	// the restore may have been started by a previous reconciliation which failed to update the status
+	inProgress, err := isSnapshotRestoreInProgress(ctx, esClient, snapshot)
+	if err != nil {
+		status.Message = esclient.ErrorReason(err)
+		return
+	}
+	if !inProgress {
+		ulog.FromContext(ctx).Info("Restoring initial snapshot", "namespace", es.Namespace, "es_name", es.Name, "snapshot", snapshot)
+		request := esclient.SnapshotRestoreRequest{Indices: spec.Indices, IncludeGlobalState: spec.IncludeGlobalState}
+		if err := esClient.RestoreSnapshot(ctx, spec.Repository.Name, snapshot, request); err != nil {
+			status.Message = esclient.ErrorReason(err)
+			if !esclient.IsUnavailable(err) {
+				status.Phase = esv1.InitialRestoreFailedPhase
+				status.Snapshot = snapshot
+				reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("Failed to restore initial snapshot %s: %s", snapshot, status.Message))
+			}
+			return
+		}
+	}
+
+	status.Phase = esv1.InitialRestoreInProgressPhase
+	status.Snapshot = snapshot
+	status.Message = ""
+}
+
+// checkInitialRestoreProgress marks the restore in progress as completed once Elasticsearch does not report it anymore.
+func checkInitialRestoreProgress(ctx context.Context, esClient esclient.Client, es esv1.Elasticsearch, status *esv1.InitialRestoreStatus) {
+	inProgress, err := isSnapshotRestoreInProgress(ctx, esClient, status.Snapshot)
+	if err != nil {
+		status.Message = esclient.ErrorReason(err)
+		return
+	}
+	status.Message = ""
+	if inProgress {
+		return
+	}
+	ulog.FromContext(ctx).Info("Initial snapshot restored", "namespace", es.Namespace, "es_name", es.Name, "snapshot", status.Snapshot)
+	status.Phase = esv1.InitialRestoreCompletedPhase
+}
+
+// isSnapshotRestoreInProgress returns true if a restore of the given snapshot is in progress.
+func isSnapshotRestoreInProgress(ctx context.Context, esClient esclient.Client, snapshot string) (bool, error) {
+	restores, err := esClient.GetSnapshotRestoresInProgress(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, restore := range restores {
+		if restore.Snapshot == snapshot {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// initialRestoreRepository returns the repository to register, read-only by default to not write into a repository
+// that may be used by another cluster.
+func initialRestoreRepository(repository esv1.InitialRestoreRepository) esclient.SnapshotRepository {
+	settings := map[string]interface{}{"readonly": true}
+	if repository.Settings != nil {
+		for k, v := range repository.Settings.Data {
+			settings[k] = v
+		}
+	}
+	return esclient.SnapshotRepository{Type: repository.Type, Settings: settings}
+}
@@ -0,0 +1,196 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/bootstrap"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+)
+
+type initialRestoreESClient struct {
+	esclient.Client
+	repositories map[string]esclient.SnapshotRepository
+	snapshots    []esclient.Snapshot
+	inProgress   []esclient.SnapshotRestoreInProgress
+	restoreErr   error
+	restores     []esclient.SnapshotRestoreRequest
+}
+
+func (c *initialRestoreESClient) UpdateSnapshotRepository(_ context.Context, name string, repository esclient.SnapshotRepository, _ bool) error {
+	c.repositories[name] = repository
+	return nil
+}
+
+func (c *initialRestoreESClient) GetSnapshots(_ context.Context, _, _ string) ([]esclient.Snapshot, error) {
+	return c.snapshots, nil
+}
+
+func (c *initialRestoreESClient) GetSnapshotRestoresInProgress(_ context.Context) ([]esclient.SnapshotRestoreInProgress, error) {
+	return c.inProgress, nil
+}
+
+func (c *initialRestoreESClient) RestoreSnapshot(_ context.Context, repository, snapshot string, request esclient.SnapshotRestoreRequest) error {
+	if c.restoreErr != nil {
+		return c.restoreErr
+	}
+	c.restores = append(c.restores, request)
+	c.inProgress = append(c.inProgress, esclient.SnapshotRestoreInProgress{Snapshot: snapshot, Repository: repository})
+	return nil
+}
+
+func initialRestoreCluster() esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			Version: "8.15.0",
+			InitialRestore: &esv1.InitialRestore{
+				Repository: esv1.InitialRestoreRepository{
+					Name:     "backups",
+					Type:     "s3",
+					Settings: &commonv1.Config{Data: map[string]interface{}{"bucket": "my-bucket"}},
+				},
+				Snapshot: "nightly-*",
+				Indices:  []string{"logs-*"},
+			},
+		},
+	}
+}
+
+// runInitialRestore reconciles the initial restore of the given cluster and returns the cluster with its updated
+// status, whether the reconciliation is complete and the number of events.
+func runInitialRestore(t *testing.T, esClient esclient.Client, es esv1.Elasticsearch, esReachable bool) (esv1.Elasticsearch, bool, int) {
+	t.Helper()
+	reconcileState := reconcile.MustNewState(es)
+	results := reconcileInitialRestore(context.Background(), esClient, es, esReachable, reconcileState)
+	reconciled, _ := results.IsReconciled()
+	events, updated := reconcileState.Apply()
+	if updated == nil {
+		// the status did not change
+		return es, reconciled, len(events)
+	}
+	return *updated, reconciled, len(events)
+}
+
+func Test_reconcileInitialRestore(t *testing.T) {
+	esClient := &initialRestoreESClient{
+		repositories: map[string]esclient.SnapshotRepository{},
+		snapshots: []esclient.Snapshot{
+			{Snapshot: "nightly-1", State: esclient.SnapshotSuccessState, StartTimeMillis: 1704159000000},
+			{Snapshot: "nightly-2", State: esclient.SnapshotSuccessState, StartTimeMillis: 1704245400000},
+		},
+	}
+	es := initialRestoreCluster()
+
+	// the restore is pending until Elasticsearch is reachable
+	es, reconciled, _ := runInitialRestore(t, esClient, es, false)
+	require.False(t, reconciled)
+	require.Equal(t, &esv1.InitialRestoreStatus{Phase: esv1.InitialRestorePendingPhase, Message: "Waiting for Elasticsearch to be reachable"}, es.Status.InitialRestore)
+
+	// the repository is registered as read-only and the most recent snapshot restored, once the cluster is bootstrapped
+	es.Annotations = map[string]string{bootstrap.ClusterUUIDAnnotationName: "uuid"}
+	es, reconciled, _ = runInitialRestore(t, esClient, es, true)
+	require.False(t, reconciled)
+	require.Equal(t, &esv1.InitialRestoreStatus{Phase: esv1.InitialRestoreInProgressPhase, Snapshot: "nightly-2"}, es.Status.InitialRestore)
+	require.Equal(t, map[string]esclient.SnapshotRepository{
+		"backups": {Type: "s3", Settings: map[string]interface{}{"bucket": "my-bucket", "readonly": true}},
+	}, esClient.repositories)
+	require.Equal(t, []esclient.SnapshotRestoreRequest{{Indices: []string{"logs-*"}}}, esClient.restores)
+
+	// the cluster is not reconciled while the restore is in progress
+	es, reconciled, _ = runInitialRestore(t, esClient, es, true)
+	require.False(t, reconciled)
+	require.Equal(t, esv1.InitialRestoreInProgressPhase, es.Status.InitialRestore.Phase)
+
+	// the restore completed
+	esClient.inProgress = nil
+	es, reconciled, _ = runInitialRestore(t, esClient, es, true)
+	require.True(t, reconciled)
+	require.Equal(t, &esv1.InitialRestoreStatus{Phase: esv1.InitialRestoreCompletedPhase, Snapshot: "nightly-2"}, es.Status.InitialRestore)
+
+	// the snapshot is not restored again
+	_, reconciled, _ = runInitialRestore(t, esClient, es, true)
+	require.True(t, reconciled)
+	require.Len(t, esClient.restores, 1)
+}
+
+func Test_reconcileInitialRestore_Errors(t *testing.T) {
+	restoreException := &esclient.APIError{StatusCode: http.StatusInternalServerError}
+	restoreException.ErrorResponse.Error.Reason = "cannot restore index [logs] because an open index with same name already exists in the cluster"
+	tests := []struct {
+		name        string
+		esClient    *initialRestoreESClient
+		wantStatus  *esv1.InitialRestoreStatus
+		wantEvents  int
+		wantRestore bool
+	}{
+		{
+			name:     "no successful snapshot",
+			esClient: &initialRestoreESClient{snapshots: []esclient.Snapshot{{Snapshot: "nightly-1", State: "PARTIAL"}}},
+			wantStatus: &esv1.InitialRestoreStatus{
+				Phase:   esv1.InitialRestorePendingPhase,
+				Message: "No successful snapshot matching nightly-* in repository backups",
+			},
+		},
+		{
+			name: "restore rejected",
+			esClient: &initialRestoreESClient{
+				snapshots:  []esclient.Snapshot{{Snapshot: "nightly-1", State: esclient.SnapshotSuccessState}},
+				restoreErr: restoreException,
+			},
+			wantStatus: &esv1.InitialRestoreStatus{
+				Phase:    esv1.InitialRestoreFailedPhase,
+				Snapshot: "nightly-1",
+				Message:  "cannot restore index [logs] because an open index with same name already exists in the cluster",
+			},
+			wantEvents: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.esClient.repositories = map[string]esclient.SnapshotRepository{}
+			es, reconciled, events := runInitialRestore(t, tt.esClient, initialRestoreCluster(), true)
+			require.False(t, reconciled)
+			require.Equal(t, tt.wantStatus, es.Status.InitialRestore)
+			require.Equal(t, tt.wantEvents, events)
+			require.Empty(t, tt.esClient.restores)
+		})
+	}
+}
+
+func Test_reconcileInitialRestore_ExistingCluster(t *testing.T) {
+	// the initial restore is ignored on a cluster bootstrapped before it was specified
+	es := initialRestoreCluster()
+	es.Annotations = map[string]string{bootstrap.ClusterUUIDAnnotationName: "uuid"}
+	esClient := &initialRestoreESClient{repositories: map[string]esclient.SnapshotRepository{}}
+	es, reconciled, _ := runInitialRestore(t, esClient, es, true)
+	require.True(t, reconciled)
+	require.Nil(t, es.Status.InitialRestore)
+	require.Empty(t, esClient.repositories)
+}
+
+func Test_reconcileInitialRestore_Failed(t *testing.T) {
+	// a failed restore is not retried and the cluster is not reconciled until the restore is removed
+	es := initialRestoreCluster()
+	es.Status.InitialRestore = &esv1.InitialRestoreStatus{Phase: esv1.InitialRestoreFailedPhase, Snapshot: "nightly-1", Message: "failure"}
+	esClient := &initialRestoreESClient{repositories: map[string]esclient.SnapshotRepository{}}
+	es, reconciled, _ := runInitialRestore(t, esClient, es, true)
+	require.False(t, reconciled)
+	require.Equal(t, esv1.InitialRestoreFailedPhase, es.Status.InitialRestore.Phase)
+	require.Empty(t, esClient.repositories)
+
+	es.Spec.InitialRestore = nil
+	_, reconciled, _ = runInitialRestore(t, esClient, es, true)
+	require.True(t, reconciled)
+}
@@ -0,0 +1,203 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// maybeRecreateLostLocalVolumes deletes the volume claims and the Pods that cannot be scheduled because their volumes
+// are bound to a Kubernetes node that does not exist anymore, if allowed by the local volume failure policy.
+// The Pods are recreated by the StatefulSet controller with new empty volumes, and Elasticsearch recovers the data
+// from replicas. This is only done if the cluster health is not red: the lost volumes may hold the only copy of some
+// primary shards, in which case we wait for the Kubernetes node to come back.
+// It returns true if some Pods have been deleted.
+func (d *defaultDriver) maybeRecreateLostLocalVolumes(ctx context.Context, esState ESState, statefulSets sset.StatefulSetList) (bool, error) {
+	if d.OperatorParameters.LocalVolumeFailurePolicy != operator.LocalVolumeFailurePolicyRecreate {
+		return false, nil
+	}
+	pods, err := statefulSets.GetActualPods(d.Client)
+	if err != nil {
+		return false, err
+	}
+	lostClaimsByPod := map[string][]corev1.PersistentVolumeClaim{}
+	var nodes *corev1.NodeList
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodPending || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if nodes == nil {
+			nodes = &corev1.NodeList{}
+			if err := d.Client.List(ctx, nodes); err != nil {
+				return false, err
+			}
+			if len(nodes.Items) == 0 {
+				// no node can be observed, which does not mean the volumes are lost
+				return false, nil
+			}
+		}
+		lostClaims, err := lostLocalVolumeClaims(ctx, d.Client, pod, nodes.Items)
+		if err != nil {
+			return false, err
+		}
+		if len(lostClaims) > 0 {
+			lostClaimsByPod[pod.Name] = lostClaims
+		}
+	}
+	if len(lostClaimsByPod) == 0 {
+		return false, nil
+	}
+
+	health, err := esState.Health()
+	if err != nil {
+		return false, err
+	}
+	if health.Status == esv1.ElasticsearchRedHealth {
+		msg := "Not recreating Pods whose local volumes are lost while the cluster health is red"
+		ulog.FromContext(ctx).Info(msg, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonDelayed, msg)
+		return false, nil
+	}
+
+	for _, pod := range pods {
+		lostClaims, exists := lostClaimsByPod[pod.Name]
+		if !exists {
+			continue
+		}
+		d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnhealthy,
+			fmt.Sprintf("Recreating Pod %s whose local volumes are lost with their Kubernetes node", pod.Name))
+		for _, claim := range lostClaims {
+			ulog.FromContext(ctx).Info("Deleting lost local volume claim",
+				"namespace", claim.Namespace, "es_name", d.ES.Name, "pvc_name", claim.Name, "pv_name", claim.Spec.VolumeName)
+			if err := d.Client.Delete(ctx, &claim, client.Preconditions{UID: &claim.UID}); err != nil && !apierrors.IsNotFound(err) {
+				return false, err
+			}
+		}
+		if err := deletePod(ctx, d.Client, d.ES, pod, d.Expectations, d.ReconcileState, "Deleting Pod whose local volumes are lost"); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// lostLocalVolumeClaims returns the claims of the given Pod that are bound to a local volume which can only be accessed
+// from Kubernetes nodes that do not exist anymore.
+func lostLocalVolumeClaims(ctx context.Context, c k8s.Client, pod corev1.Pod, nodes []corev1.Node) ([]corev1.PersistentVolumeClaim, error) {
+	var lostClaims []corev1.PersistentVolumeClaim
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		var claim corev1.PersistentVolumeClaim
+		err := c.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: volume.PersistentVolumeClaim.ClaimName}, &claim)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if claim.Spec.VolumeName == "" {
+			// not bound
+			continue
+		}
+		var pv corev1.PersistentVolume
+		err = c.Get(ctx, types.NamespacedName{Name: claim.Spec.VolumeName}, &pv)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !isLocalVolume(pv) {
+			// zonal volumes also have a node affinity but remain available when the nodes of their zone are gone
+			continue
+		}
+		if !anyNodeMatches(pv.Spec.NodeAffinity.Required, nodes) {
+			lostClaims = append(lostClaims, claim)
+		}
+	}
+	return lostClaims, nil
+}
+
+// isLocalVolume returns true if the given volume is a local or host path volume restricted to some Kubernetes nodes.
+func isLocalVolume(pv corev1.PersistentVolume) bool {
+	if pv.Spec.Local == nil && pv.Spec.HostPath == nil {
+		return false
+	}
+	return pv.Spec.NodeAffinity != nil && pv.Spec.NodeAffinity.Required != nil
+}
+
+// anyNodeMatches returns true if at least one of the given nodes matches the node selector.
+func anyNodeMatches(selector *corev1.NodeSelector, nodes []corev1.Node) bool {
+	for _, node := range nodes {
+		for _, term := range selector.NodeSelectorTerms {
+			if nodeMatchesTerm(term, node) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nodeMatchesTerm returns true if the node matches all the requirements of the node selector term.
+func nodeMatchesTerm(term corev1.NodeSelectorTerm, node corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		// an empty term matches no node
+		return false
+	}
+	for _, expr := range term.MatchExpressions {
+		if !requirementMatches(expr, labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	for _, expr := range term.MatchFields {
+		// metadata.name is the only field supported by Kubernetes
+		if expr.Key != "metadata.name" || !requirementMatches(expr, labels.Set{expr.Key: node.Name}) {
+			return false
+		}
+	}
+	return true
+}
+
+func requirementMatches(expr corev1.NodeSelectorRequirement, values labels.Set) bool {
+	var op selection.Operator
+	switch expr.Operator {
+	case corev1.NodeSelectorOpIn:
+		op = selection.In
+	case corev1.NodeSelectorOpNotIn:
+		op = selection.NotIn
+	case corev1.NodeSelectorOpExists:
+		op = selection.Exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		op = selection.DoesNotExist
+	case corev1.NodeSelectorOpGt:
+		op = selection.GreaterThan
+	case corev1.NodeSelectorOpLt:
+		op = selection.LessThan
+	default:
+		// in doubt, consider the volume can still be accessed
+		return true
+	}
+	requirement, err := labels.NewRequirement(expr.Key, op, expr.Values)
+	if err != nil {
+		return true
+	}
+	return requirement.Matches(values)
+}
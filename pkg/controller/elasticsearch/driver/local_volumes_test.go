@@ -0,0 +1,180 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_defaultDriver_maybeRecreateLostLocalVolumes(t *testing.T) {
+	statefulSet := appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sset"}}
+	node := func(name string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"kubernetes.io/hostname": name}}}
+	}
+	pod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: map[string]string{label.StatefulSetNameLabelName: "sset"}},
+			Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name:         "elasticsearch-data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "elasticsearch-data-" + name}},
+			}}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	claim := func(podName string, volumeName string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "elasticsearch-data-" + podName},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: volumeName},
+		}
+	}
+	nodeAffinity := func(key string, value string) *corev1.VolumeNodeAffinity {
+		return &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{
+				Key: key, Operator: corev1.NodeSelectorOpIn, Values: []string{value},
+			}}}},
+		}}
+	}
+	localVolume := func(name string, nodeName string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{Local: &corev1.LocalVolumeSource{Path: "/mnt/disks/ssd1"}},
+				NodeAffinity:           nodeAffinity("kubernetes.io/hostname", nodeName),
+			},
+		}
+	}
+	zonalVolume := func(name string, zone string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{
+					Driver: "ebs.csi.aws.com", VolumeHandle: "vol-0123456789abcdef0",
+				}},
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+				NodeAffinity:                  nodeAffinity("topology.ebs.csi.aws.com/zone", zone),
+			},
+		}
+	}
+	networkVolume := func(name string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	tests := []struct {
+		name              string
+		policy            operator.LocalVolumeFailurePolicy
+		health            esv1.ElasticsearchHealth
+		resources         []crclient.Object
+		wantRecreated     bool
+		wantRemainingPods []string
+	}{
+		{
+			name:   "Wait policy",
+			policy: operator.LocalVolumeFailurePolicyWait,
+			health: esv1.ElasticsearchYellowHealth,
+			resources: []crclient.Object{
+				node("node-a"), pod("sset-0", corev1.PodRunning), pod("sset-1", corev1.PodPending),
+				claim("sset-1", "pv-1"), localVolume("pv-1", "node-b"),
+			},
+			wantRemainingPods: []string{"sset-0", "sset-1"},
+		},
+		{
+			name:   "Pending Pod with a local volume on an existing node",
+			policy: operator.LocalVolumeFailurePolicyRecreate,
+			health: esv1.ElasticsearchYellowHealth,
+			resources: []crclient.Object{
+				node("node-a"), node("node-b"), pod("sset-0", corev1.PodRunning), pod("sset-1", corev1.PodPending),
+				claim("sset-1", "pv-1"), localVolume("pv-1", "node-b"),
+			},
+			wantRemainingPods: []string{"sset-0", "sset-1"},
+		},
+		{
+			name:   "Pending Pod with a network volume",
+			policy: operator.LocalVolumeFailurePolicyRecreate,
+			health: esv1.ElasticsearchYellowHealth,
+			resources: []crclient.Object{
+				node("node-a"), pod("sset-0", corev1.PodRunning), pod("sset-1", corev1.PodPending),
+				claim("sset-1", "pv-1"), networkVolume("pv-1"),
+			},
+			wantRemainingPods: []string{"sset-0", "sset-1"},
+		},
+		{
+			name:   "Pending Pod with a zonal volume and no node in its zone",
+			policy: operator.LocalVolumeFailurePolicyRecreate,
+			health: esv1.ElasticsearchYellowHealth,
+			resources: []crclient.Object{
+				node("node-a"), pod("sset-0", corev1.PodRunning), pod("sset-1", corev1.PodPending),
+				claim("sset-1", "pv-1"), zonalVolume("pv-1", "us-east-1b"),
+			},
+			wantRemainingPods: []string{"sset-0", "sset-1"},
+		},
+		{
+			name:   "Pending Pod with a lost local volume, red health",
+			policy: operator.LocalVolumeFailurePolicyRecreate,
+			health: esv1.ElasticsearchRedHealth,
+			resources: []crclient.Object{
+				node("node-a"), pod("sset-0", corev1.PodRunning), pod("sset-1", corev1.PodPending),
+				claim("sset-1", "pv-1"), localVolume("pv-1", "node-b"),
+			},
+			wantRemainingPods: []string{"sset-0", "sset-1"},
+		},
+		{
+			name:   "Pending Pod with a lost local volume",
+			policy: operator.LocalVolumeFailurePolicyRecreate,
+			health: esv1.ElasticsearchYellowHealth,
+			resources: []crclient.Object{
+				node("node-a"), pod("sset-0", corev1.PodRunning), pod("sset-1", corev1.PodPending),
+				claim("sset-1", "pv-1"), localVolume("pv-1", "node-b"),
+			},
+			wantRecreated:     true,
+			wantRemainingPods: []string{"sset-0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := k8s.NewFakeClient(tt.resources...)
+			d := &defaultDriver{
+				DefaultDriverParameters: DefaultDriverParameters{
+					OperatorParameters: operator.Parameters{LocalVolumeFailurePolicy: tt.policy},
+					Client:             k8sClient,
+					Expectations:       expectations.NewExpectations(k8sClient),
+					ReconcileState:     reconcile.MustNewState(esv1.Elasticsearch{}),
+				},
+			}
+			esState := &testESState{health: client.Health{Status: tt.health}}
+
+			recreated, err := d.maybeRecreateLostLocalVolumes(context.Background(), esState, sset.StatefulSetList{statefulSet})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRecreated, recreated)
+
+			var pods corev1.PodList
+			require.NoError(t, k8sClient.List(context.Background(), &pods))
+			podNames := make([]string, 0, len(pods.Items))
+			for _, p := range pods.Items {
+				podNames = append(podNames, p.Name)
+			}
+			require.ElementsMatch(t, tt.wantRemainingPods, podNames)
+
+			var claims corev1.PersistentVolumeClaimList
+			require.NoError(t, k8sClient.List(context.Background(), &claims))
+			require.Equal(t, tt.wantRecreated, len(claims.Items) == 0)
+		})
+	}
+}
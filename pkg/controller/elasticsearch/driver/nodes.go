@@ -74,6 +74,7 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		// the sset doesn't exist (was just deleted), but the Pods do actually exist.
 		log.V(1).Info("StatefulSets recreation in progress, re-queueing.",
 			"namespace", d.ES.Namespace, "es_name", d.ES.Name, "recreations", recreations)
+		reconcileState.ReportCondition(esv1.VolumeExpansionComplete, corev1.ConditionFalse, "StatefulSets are being recreated to expand volumes")
 		return results.WithReconciliationState(defaultRequeue.WithReason("StatefulSets recreation in progress"))
 	}
 
@@ -82,11 +83,43 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results.WithError(err)
 	}
 
-	expectedResources, err := nodespec.BuildExpectedResources(ctx, d.Client, d.ES, keystoreResources, actualStatefulSets, d.OperatorParameters.IPFamily, d.OperatorParameters.SetDefaultSecurityContext)
+	expectedResources, err := nodespec.BuildExpectedResources(ctx, d.Client, d.ES, keystoreResources, actualStatefulSets, d.OperatorParameters.IPFamily, d.OperatorParameters.SetDefaultSecurityContext, d.OperatorParameters.TierStorageClasses)
 	if err != nil {
 		return results.WithError(err)
 	}
 
+	migrating, err := startVolumeMigrations(ctx, d.Client, &d.ES, actualStatefulSets, expectedResources, d.OperatorParameters.ValidateStorageClass, reconcileState)
+	if err != nil {
+		return results.WithError(err)
+	}
+	if migrating {
+		// StatefulSet names have changed, all the resources that depend on them must be reconciled again.
+		return results.WithReconciliationState(defaultRequeue.WithReason("Volume migration started"))
+	}
+
+	// create the missing volumes from snapshots before the StatefulSet controller creates them empty
+	if err := prepopulateVolumesFromSnapshots(ctx, d.Client, d.ES, expectedResources, reconcileState); err != nil {
+		return results.WithError(err)
+	}
+
+	// check the cluster has no critical deprecation issue before upgrading it to the next major version
+	waitingForDeprecations, err := checkUpgradeDeprecations(ctx, esClient, d.ES, esReachable, actualStatefulSets, reconcileState)
+	if err != nil {
+		return results.WithError(err)
+	}
+	if waitingForDeprecations {
+		return results.WithReconciliationState(defaultRequeue.WithReason("Waiting for the critical deprecation issues to be resolved before upgrading"))
+	}
+
+	// snapshot the volumes before any node is upgraded to a new version, if requested
+	waitingForSnapshots, err := reconcilePreUpgradeVolumeSnapshots(ctx, d.Client, d.ES, expectedResources, reconcileState)
+	if err != nil {
+		return results.WithError(err)
+	}
+	if waitingForSnapshots {
+		return results.WithReconciliationState(defaultRequeue.WithReason("Waiting for VolumeSnapshots to be taken before upgrading"))
+	}
+
 	if esClient.IsDesiredNodesSupported() {
 		results.WithResults(d.updateDesiredNodes(ctx, esClient, esReachable, expectedResources))
 		if results.HasError() {
@@ -124,6 +157,14 @@ func (d *defaultDriver) reconcileNodeSpecs(
 	}
 	actualStatefulSets = upscaleResults.ActualStatefulSets
 
+	expanding, err := reportVolumeExpansion(d.K8sClient(), d.ES, actualStatefulSets, reconcileState.StatusReporter)
+	if err != nil {
+		return results.WithError(err)
+	}
+	if expanding {
+		results.WithReconciliationState(defaultRequeue.WithReason("Volume expansion in progress"))
+	}
+
 	// Once all the StatefulSets have been updated we can ensure that the former version of the transport certificates Secret is deleted.
 	if err := transport.DeleteLegacyTransportCertificate(ctx, d.Client, d.ES); err != nil {
 		results.WithError(err)
@@ -160,6 +201,16 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results.WithReconciliationState(defaultRequeue.WithReason(msg))
 	}
 
+	// Recreate the Pods whose local volumes are lost with their Kubernetes node, if allowed by the operator configuration.
+	recreated, err := d.maybeRecreateLostLocalVolumes(ctx, esState, actualStatefulSets)
+	if err != nil {
+		return results.WithError(err)
+	}
+	if recreated {
+		reconcileState.UpdateWithPhase(esv1.ElasticsearchApplyingChangesPhase)
+		return results.WithReconciliationState(defaultRequeue.WithReason("Recreating Pods whose local volumes are lost"))
+	}
+
 	// Maybe update Zen1 minimum master nodes through the API, corresponding to the current nodes we have.
 	requeue, err := zen1.UpdateMinimumMasterNodes(ctx, d.Client, d.ES, esClient, actualStatefulSets)
 	if err != nil {
@@ -237,6 +288,9 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		d.ReconcileState.UpdateOrchestrationHints(
 			d.ReconcileState.OrchestrationHints().Merge(hints.OrchestrationsHints{ServiceAccounts: optional.NewBool(allNodesRunningServiceAccounts)}),
 		)
+		// All the nodes have been restarted if some secure settings that cannot be reloaded changed. Update the keystore
+		// of the nodes in place for the other changes, and reload the secure settings.
+		results.WithResults(reloadSecureSettings(ctx, d.Client, d.PodExecutor, esClient, d.ES, esReachable, keystoreResources, d.ReconcileState))
 	}
 
 	return results
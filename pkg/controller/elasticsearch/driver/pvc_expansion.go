@@ -6,11 +6,18 @@ package driver
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -18,8 +25,77 @@ func recreateStatefulSets(ctx context.Context, k8sclient k8s.Client, es esv1.Ela
 	return volume.RecreateStatefulSets(ctx, k8sclient, &es, es.Kind)
 }
 
-func handleVolumeExpansion(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, expectedSset appsv1.StatefulSet,
+func handleVolumeExpansion(ctx context.Context, k8sClient k8s.Client, es *esv1.Elasticsearch, expectedSset *appsv1.StatefulSet,
 	actualSset appsv1.StatefulSet, validateStorageClass bool) (bool, error) {
-	return volume.HandleVolumeExpansion(ctx, k8sClient, &es, es.Kind, expectedSset, actualSset,
+	return volume.HandleVolumeExpansion(ctx, k8sClient, es, es.Kind, expectedSset, actualSset,
 		validateStorageClass)
 }
+
+// reportVolumeExpansion reports the progress of the expansion of the volumes of the given StatefulSets, and of the
+// migration of the nodeSets moved to larger volumes, in the VolumeExpansionComplete condition, once an expansion has
+// started. It returns true if an expansion is in progress.
+func reportVolumeExpansion(k8sClient k8s.Client, es esv1.Elasticsearch, statefulSets es_sset.StatefulSetList, reporter *reconcile.StatusReporter) (bool, error) {
+	var progress volume.ExpansionProgress
+	for _, statefulSet := range statefulSets {
+		ssetProgress, err := volume.GetExpansionProgress(k8sClient, statefulSet)
+		if err != nil {
+			return false, err
+		}
+		progress = progress.Add(ssetProgress)
+	}
+	messages := volumeResizeMigrations(es, statefulSets)
+	if progress.InProgress() {
+		messages = append([]string{progress.String()}, messages...)
+	}
+	switch {
+	case len(messages) > 0:
+		reporter.ReportCondition(esv1.VolumeExpansionComplete, corev1.ConditionFalse, strings.Join(messages, ". "))
+	case isConditionFalse(es, esv1.VolumeExpansionComplete):
+		reporter.ReportCondition(esv1.VolumeExpansionComplete, corev1.ConditionTrue, "All volumes have the requested capacity")
+	}
+	return len(messages) > 0, nil
+}
+
+// volumeResizeMigrations describes the progress of the nodeSets moved to larger volumes whose former StatefulSets
+// still exist.
+func volumeResizeMigrations(es esv1.Elasticsearch, statefulSets es_sset.StatefulSetList) []string {
+	migrations := es.VolumeResizeMigrationNodeSets()
+	if len(migrations) == 0 {
+		return nil
+	}
+	expectedNames := make(map[string]struct{}, len(es.Spec.NodeSets))
+	for _, nodeSet := range es.Spec.NodeSets {
+		expectedNames[es.StatefulSetName(nodeSet.Name)] = struct{}{}
+	}
+	var messages []string
+	for _, nodeSet := range es.Spec.NodeSets {
+		if !migrations.Has(nodeSet.Name) {
+			continue
+		}
+		var former []string
+		var formerPods int32
+		for _, statefulSet := range statefulSets {
+			if _, expected := expectedNames[statefulSet.Name]; expected || !esv1.IsStatefulSetOfNodeSet(es.Name, nodeSet.Name, statefulSet.Name) {
+				continue
+			}
+			former = append(former, statefulSet.Name)
+			formerPods += sset.GetReplicas(statefulSet)
+		}
+		if len(former) == 0 {
+			continue
+		}
+		name := es.StatefulSetName(nodeSet.Name)
+		var ready int32
+		if statefulSet, exists := statefulSets.GetByName(name); exists {
+			ready = statefulSet.Status.ReadyReplicas
+		}
+		messages = append(messages, fmt.Sprintf("Migrating nodeSet %s to new volumes: %d/%d Pods of StatefulSet %s ready, %d Pods of former StatefulSet %s to remove",
+			nodeSet.Name, ready, nodeSet.Count, name, formerPods, strings.Join(former, ", ")))
+	}
+	return messages
+}
+
+func isConditionFalse(es esv1.Elasticsearch, conditionType commonv1alpha1.ConditionType) bool {
+	index := es.Status.Conditions.Index(conditionType)
+	return index >= 0 && es.Status.Conditions[index].Status == corev1.ConditionFalse
+}
@@ -0,0 +1,132 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_reportVolumeExpansion(t *testing.T) {
+	claim := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("2Gi"),
+			}},
+		},
+	}
+	statefulSet := appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-default"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:             ptr.To[int32](1),
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claim},
+		},
+	}
+	pvc := func(capacity string) *corev1.PersistentVolumeClaim {
+		pvc := claim.DeepCopy()
+		pvc.Namespace = "ns"
+		pvc.Name = "elasticsearch-data-es-default-0"
+		pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)}
+		return pvc
+	}
+	esWithCondition := func(status corev1.ConditionStatus) esv1.Elasticsearch {
+		es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+		if status != "" {
+			es.Status.Conditions = commonv1alpha1.Conditions{{Type: esv1.VolumeExpansionComplete, Status: status}}
+		}
+		return es
+	}
+
+	tests := []struct {
+		name          string
+		es            esv1.Elasticsearch
+		pvc           client.Object
+		wantExpanding bool
+		wantCondition corev1.ConditionStatus
+	}{
+		{
+			name: "no expansion",
+			es:   esWithCondition(""),
+			pvc:  pvc("2Gi"),
+		},
+		{
+			name:          "expansion in progress",
+			es:            esWithCondition(""),
+			pvc:           pvc("1Gi"),
+			wantExpanding: true,
+			wantCondition: corev1.ConditionFalse,
+		},
+		{
+			name:          "expansion complete",
+			es:            esWithCondition(corev1.ConditionFalse),
+			pvc:           pvc("2Gi"),
+			wantCondition: corev1.ConditionTrue,
+		},
+		{
+			name: "expansion already reported as complete",
+			es:   esWithCondition(corev1.ConditionTrue),
+			pvc:  pvc("2Gi"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := &reconcile.StatusReporter{}
+			expanding, err := reportVolumeExpansion(k8s.NewFakeClient(tt.pvc), tt.es, es_sset.StatefulSetList{statefulSet}, reporter)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantExpanding, expanding)
+			index := reporter.Conditions.Index(esv1.VolumeExpansionComplete)
+			if tt.wantCondition == "" {
+				require.Equal(t, -1, index)
+				return
+			}
+			require.GreaterOrEqual(t, index, 0)
+			require.Equal(t, tt.wantCondition, reporter.Conditions[index].Status)
+		})
+	}
+}
+
+func Test_volumeResizeMigrations(t *testing.T) {
+	statefulSet := func(name string, replicas, ready int32) appsv1.StatefulSet {
+		return appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(replicas)},
+			Status:     appsv1.StatefulSetStatus{ReadyReplicas: ready},
+		}
+	}
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: map[string]string{
+			esv1.VolumeResizeMigrationAnnotation:   "data",
+			esv1.StatefulSetNameSuffixesAnnotation: `{"data":"a1b2c3"}`,
+		}},
+		Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{{Name: "data", Count: 3}, {Name: "data-hot", Count: 2}}},
+	}
+
+	require.Equal(t, []string{
+		"Migrating nodeSet data to new volumes: 1/3 Pods of StatefulSet es-es-data-a1b2c3 ready, 2 Pods of former StatefulSet es-es-data to remove",
+	}, volumeResizeMigrations(es, es_sset.StatefulSetList{
+		statefulSet("es-es-data", 2, 2),
+		statefulSet("es-es-data-a1b2c3", 3, 1),
+		statefulSet("es-es-data-hot", 2, 2),
+	}))
+	// the former StatefulSet has been removed
+	require.Empty(t, volumeResizeMigrations(es, es_sset.StatefulSetList{
+		statefulSet("es-es-data-a1b2c3", 3, 3),
+		statefulSet("es-es-data-hot", 2, 2),
+	}))
+}
@@ -41,6 +41,10 @@ func GarbageCollectPVCs(
 	}
 	for _, pvc := range pvcsToRemove(pvcs.Items, actualStatefulSets, expectedStatefulSets) {
 		pvc := pvc
+		if !volumeClaimDeletePolicy(es, pvc).DeleteOnScaledown() {
+			// retained on scale down, reused if the StatefulSet is scaled up again
+			continue
+		}
 		ulog.FromContext(ctx).Info("Deleting PVC", "namespace", pvc.Namespace, "pvc_name", pvc.Name)
 		if err := k8sClient.Delete(ctx, &pvc); err != nil {
 			return err
@@ -65,6 +65,19 @@ func buildPVCPtr(name string, ownerRefs ...string) *corev1.PersistentVolumeClaim
 	return &pvc
 }
 
+func withStatefulSetLabel(pvc *corev1.PersistentVolumeClaim, ssetName string) *corev1.PersistentVolumeClaim {
+	pvc.Labels[label.StatefulSetNameLabelName] = ssetName
+	return pvc
+}
+
+func withDeletePolicy(pvc *corev1.PersistentVolumeClaim, policy esv1.VolumeClaimDeletePolicy) *corev1.PersistentVolumeClaim {
+	if pvc.Annotations == nil {
+		pvc.Annotations = map[string]string{}
+	}
+	pvc.Annotations[volumeClaimDeletePolicyAnnotationName] = string(policy)
+	return pvc
+}
+
 func Test_pvcsToRemove(t *testing.T) {
 	type args struct {
 		pvcs                 []corev1.PersistentVolumeClaim
@@ -174,6 +187,59 @@ func TestGarbageCollectPVCs(t *testing.T) {
 			wantErr:  false,
 			wantPVCs: 1,
 		},
+		{
+			name: "Retain on a cluster policy that retains PVCs on scale down",
+			args: args{
+				k8sClient: k8s.NewFakeClient(existingPVCS...),
+				es: esv1.Elasticsearch{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+					Spec: esv1.ElasticsearchSpec{
+						VolumeClaimDeletePolicy: esv1.RetainPolicy,
+					}},
+				actualStatefulSets:   sset.StatefulSetList{buildSsetWithClaims("sset1", 1, "claim1")},
+				expectedStatefulSets: sset.StatefulSetList{buildSsetWithClaims("sset2", 1, "claim1")},
+			},
+			wantErr:  false,
+			wantPVCs: 2,
+		},
+		{
+			name: "Retain on a NodeSet policy that retains PVCs on scale down",
+			args: args{
+				k8sClient: k8s.NewFakeClient(
+					buildPVCPtr("claim1-es-es-warm-0"),
+					withStatefulSetLabel(buildPVCPtr("claim1-es-es-warm-1"), "es-es-warm"),
+					withStatefulSetLabel(buildPVCPtr("claim1-es-es-hot-1"), "es-es-hot"),
+				),
+				es: esv1.Elasticsearch{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+					Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{
+						{Name: "warm", VolumeClaimDeletePolicy: esv1.DeleteOnClusterDeletionOnlyPolicy},
+						{Name: "hot"},
+					}}},
+				actualStatefulSets:   sset.StatefulSetList{buildSsetWithClaims("es-es-warm", 1, "claim1"), buildSsetWithClaims("es-es-hot", 1, "claim1")},
+				expectedStatefulSets: sset.StatefulSetList{buildSsetWithClaims("es-es-warm", 1, "claim1"), buildSsetWithClaims("es-es-hot", 1, "claim1")},
+			},
+			wantErr:  false,
+			wantPVCs: 2,
+		},
+		{
+			name: "Retain on the recorded policy of a removed NodeSet",
+			args: args{
+				k8sClient: k8s.NewFakeClient(
+					withDeletePolicy(withStatefulSetLabel(buildPVCPtr("claim1-es-es-warm-0"), "es-es-warm"), esv1.RetainPolicy),
+					withStatefulSetLabel(buildPVCPtr("claim1-es-es-hot-0"), "es-es-hot"),
+				),
+				es: esv1.Elasticsearch{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+					Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{
+						{Name: "hot"},
+					}}},
+				actualStatefulSets:   sset.StatefulSetList{buildSsetWithClaims("es-es-hot", 1, "claim1")},
+				expectedStatefulSets: sset.StatefulSetList{buildSsetWithClaims("es-es-hot", 1, "claim1")},
+			},
+			wantErr:  false,
+			wantPVCs: 2,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -18,10 +18,14 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
+// volumeClaimDeletePolicyAnnotationName records on each PVC the delete policy of its NodeSet, which still applies once
+// the NodeSet is removed from the specification.
+const volumeClaimDeletePolicyAnnotationName = "elasticsearch.k8s.elastic.co/volume-claim-delete-policy"
+
 // reconcilePVCOwnerRefs sets or removes an owner reference into each PVC for the given Elasticsearch cluster depending
-// on the VolumeClaimDeletePolicy.
-// The intent behind this approach is to allow users to specify per cluster whether they want to retain or remove
-// the related PVCs. We rely on Kubernetes garbage collection for the cleanup once a cluster has been deleted and
+// on the VolumeClaimDeletePolicy of its NodeSet, and records that policy on the PVC.
+// The intent behind this approach is to allow users to specify per cluster or per NodeSet whether they want to retain
+// or remove the related PVCs. We rely on Kubernetes garbage collection for the cleanup once a cluster has been deleted and
 // the operator separately deletes PVCs on scale down if so desired (see GarbageCollectPVCs)
 func reconcilePVCOwnerRefs(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) error {
 	var pvcs corev1.PersistentVolumeClaimList
@@ -33,20 +37,28 @@ func reconcilePVCOwnerRefs(ctx context.Context, c k8s.Client, es esv1.Elasticsea
 
 	for _, pvc := range pvcs.Items {
 		pvc := pvc
+		updated := false
+		if policy, exists := nodeSetVolumeClaimDeletePolicy(es, pvc); exists && pvc.Annotations[volumeClaimDeletePolicyAnnotationName] != string(policy) {
+			if pvc.Annotations == nil {
+				pvc.Annotations = map[string]string{}
+			}
+			pvc.Annotations[volumeClaimDeletePolicyAnnotationName] = string(policy)
+			updated = true
+		}
 		hasOwner := k8s.HasOwner(&pvc, &es)
-		switch es.Spec.VolumeClaimDeletePolicyOrDefault() {
-		case esv1.DeleteOnScaledownOnlyPolicy:
+		if volumeClaimDeletePolicy(es, pvc).DeleteOnClusterDeletion() {
 			if !hasOwner {
-				continue
+				if err := controllerutil.SetOwnerReference(&es, &pvc, scheme.Scheme); err != nil {
+					return fmt.Errorf("while setting owner during owner ref reconciliation: %w", err)
+				}
+				updated = true
 			}
+		} else if hasOwner {
 			k8s.RemoveOwner(&pvc, &es)
-		case esv1.DeleteOnScaledownAndClusterDeletionPolicy:
-			if hasOwner {
-				continue
-			}
-			if err := controllerutil.SetOwnerReference(&es, &pvc, scheme.Scheme); err != nil {
-				return fmt.Errorf("while setting owner during owner ref reconciliation: %w", err)
-			}
+			updated = true
+		}
+		if !updated {
+			continue
 		}
 		if err := c.Update(ctx, &pvc); err != nil {
 			return fmt.Errorf("while updating pvc during owner ref reconciliation: %w", err)
@@ -54,3 +66,27 @@ func reconcilePVCOwnerRefs(ctx context.Context, c k8s.Client, es esv1.Elasticsea
 	}
 	return nil
 }
+
+// volumeClaimDeletePolicy returns the delete policy of the NodeSet the given PVC belongs to. If the NodeSet does not
+// exist anymore, it returns the policy recorded on the PVC while the NodeSet existed, or the delete policy of the cluster.
+func volumeClaimDeletePolicy(es esv1.Elasticsearch, pvc corev1.PersistentVolumeClaim) esv1.VolumeClaimDeletePolicy {
+	if policy, exists := nodeSetVolumeClaimDeletePolicy(es, pvc); exists {
+		return policy
+	}
+	if policy := pvc.Annotations[volumeClaimDeletePolicyAnnotationName]; policy != "" {
+		return esv1.VolumeClaimDeletePolicy(policy)
+	}
+	return es.Spec.VolumeClaimDeletePolicyOrDefault()
+}
+
+// nodeSetVolumeClaimDeletePolicy returns the delete policy of the NodeSet the given PVC belongs to, and false if the
+// NodeSet does not exist.
+func nodeSetVolumeClaimDeletePolicy(es esv1.Elasticsearch, pvc corev1.PersistentVolumeClaim) (esv1.VolumeClaimDeletePolicy, bool) {
+	ssetName := pvc.Labels[label.StatefulSetNameLabelName]
+	for _, nodeSet := range es.Spec.ExpandedNodeSets() {
+		if es.StatefulSetName(nodeSet.Name) == ssetName {
+			return es.Spec.NodeSetVolumeClaimDeletePolicy(nodeSet), true
+		}
+	}
+	return "", false
+}
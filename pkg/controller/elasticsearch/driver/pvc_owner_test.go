@@ -124,6 +124,64 @@ func Test_reconcilePVCOwnerRefs(t *testing.T) {
 			wantErr:    false,
 			wantUpdate: true,
 		},
+		{
+			name: "remove references on RetainPolicy",
+			args: args{
+				c:  k8s.NewFakeClient(pvcFixturePtr("es-data-0", "es")),
+				es: esFixture(esv1.RetainPolicy),
+			},
+			want:       []corev1.PersistentVolumeClaim{pvcFixture("es-data-0")},
+			wantErr:    false,
+			wantUpdate: true,
+		},
+		{
+			name: "add references for DeleteOnClusterDeletionOnlyPolicy",
+			args: args{
+				c:  k8s.NewFakeClient(pvcFixturePtr("es-data-0")),
+				es: esFixture(esv1.DeleteOnClusterDeletionOnlyPolicy),
+			},
+			want:       []corev1.PersistentVolumeClaim{pvcFixture("es-data-0", "es")},
+			wantErr:    false,
+			wantUpdate: true,
+		},
+		{
+			name: "remove references on a NodeSet policy overriding the cluster policy",
+			args: args{
+				c: k8s.NewFakeClient(withStatefulSetLabel(pvcFixturePtr("es-data-0", "es"), "es-es-data")),
+				es: func() esv1.Elasticsearch {
+					es := esFixture(esv1.DeleteOnScaledownAndClusterDeletionPolicy)
+					es.Spec.NodeSets = []esv1.NodeSet{{Name: "data", VolumeClaimDeletePolicy: esv1.DeleteOnScaledownOnlyPolicy}}
+					return es
+				}(),
+			},
+			want:       []corev1.PersistentVolumeClaim{*withDeletePolicy(withStatefulSetLabel(pvcFixturePtr("es-data-0"), "es-es-data"), esv1.DeleteOnScaledownOnlyPolicy)},
+			wantErr:    false,
+			wantUpdate: true,
+		},
+		{
+			name: "record the NodeSet policy",
+			args: args{
+				c: k8s.NewFakeClient(withStatefulSetLabel(pvcFixturePtr("es-data-0"), "es-es-data")),
+				es: func() esv1.Elasticsearch {
+					es := esFixture(esv1.DeleteOnScaledownOnlyPolicy)
+					es.Spec.NodeSets = []esv1.NodeSet{{Name: "data"}}
+					return es
+				}(),
+			},
+			want:       []corev1.PersistentVolumeClaim{*withDeletePolicy(withStatefulSetLabel(pvcFixturePtr("es-data-0"), "es-es-data"), esv1.DeleteOnScaledownOnlyPolicy)},
+			wantErr:    false,
+			wantUpdate: true,
+		},
+		{
+			name: "keep the recorded policy of a removed NodeSet",
+			args: args{
+				c:  k8s.NewFakeClient(withDeletePolicy(withStatefulSetLabel(pvcFixturePtr("es-data-0"), "es-es-data"), esv1.RetainPolicy)),
+				es: esFixture(esv1.DeleteOnScaledownAndClusterDeletionPolicy),
+			},
+			want:       []corev1.PersistentVolumeClaim{*withDeletePolicy(withStatefulSetLabel(pvcFixturePtr("es-data-0"), "es-es-data"), esv1.RetainPolicy)},
+			wantErr:    false,
+			wantUpdate: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,102 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reloadSecureSettings updates the keystore of all the Elasticsearch nodes in place with the current secure settings,
+// then reloads them with the _nodes/reload_secure_settings API. Changes to the secure settings that cannot be reloaded
+// rotate the Pods instead, this must then only be called once all the Pods run with the expected specification.
+// The hash of the secure settings loaded by all the nodes is recorded in the orchestration hints, so that the keystores
+// are only updated once per change.
+func reloadSecureSettings(
+	ctx context.Context,
+	c k8s.Client,
+	executor k8s.PodExecutor,
+	esClient esclient.Client,
+	es esv1.Elasticsearch,
+	esReachable bool,
+	keystoreResources *keystore.Resources,
+	reconcileState *reconcile.State,
+) *reconciler.Results {
+	results := reconciler.NewResult(ctx)
+	if keystoreResources == nil || reconcileState.OrchestrationHints().SecureSettingsHash == keystoreResources.Hash {
+		return results
+	}
+	if !esReachable {
+		return results.WithReconciliationState(defaultRequeue.WithReason("Waiting for Elasticsearch to be reachable to reload secure settings"))
+	}
+
+	pods, err := sset.GetActualPodsForCluster(c, es)
+	if err != nil {
+		return results.WithError(err)
+	}
+	for _, pod := range pods {
+		if !k8s.IsPodRunning(pod) {
+			return results.WithReconciliationState(defaultRequeue.WithReason(fmt.Sprintf("Waiting for Pod %s to be running to reload secure settings", pod.Name)))
+		}
+	}
+
+	command, err := keystore.UpdateCommand(initcontainer.KeystoreParamsFor(es))
+	if err != nil {
+		return results.WithError(err)
+	}
+	for _, pod := range pods {
+		if err := executor.Exec(ctx, pod, esv1.ElasticsearchContainerName, command, keystore.UpdateInput(keystoreResources.Data)); err != nil {
+			return results.WithError(fmt.Errorf("while updating the keystore of Pod %s: %w", pod.Name, err))
+		}
+	}
+	password, err := keystorePassword(ctx, c, es)
+	if err != nil {
+		return results.WithError(err)
+	}
+	if err := esClient.ReloadSecureSettings(ctx, password); err != nil {
+		return results.WithError(err)
+	}
+	ulog.FromContext(ctx).Info("Secure settings reloaded", "namespace", es.Namespace, "es_name", es.Name)
+
+	// All the nodes loaded the current secure settings in their keystore. Surface it to let the snapshot repository
+	// controller verify repositories once their rotated credentials are loaded.
+	reconcileState.UpdateOrchestrationHints(
+		reconcileState.OrchestrationHints().Merge(hints.OrchestrationsHints{SecureSettingsHash: keystoreResources.Hash}),
+	)
+	return results
+}
+
+// keystorePassword returns the password of the keystore of the given cluster, or an empty string if the keystore is not
+// protected by a password.
+func keystorePassword(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) (string, error) {
+	if es.Spec.KeystorePassword == nil {
+		return "", nil
+	}
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: es.Spec.KeystorePassword.SecretName}, &secret); err != nil {
+		return "", err
+	}
+	password, exists := secret.Data[es.Spec.KeystorePassword.Key]
+	if !exists {
+		return "", fmt.Errorf("key %s not found in keystore password Secret %s", es.Spec.KeystorePassword.Key, es.Spec.KeystorePassword.SecretName)
+	}
+	// like the keystore tools, ignore the trailing line break of the password file
+	return strings.TrimRight(string(password), "\r\n"), nil
+}
@@ -0,0 +1,213 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+type fakePodExecutor struct {
+	err    error
+	inputs map[string]string
+}
+
+func (e *fakePodExecutor) Exec(_ context.Context, pod corev1.Pod, container string, _ []string, stdin io.Reader) error {
+	if e.err != nil {
+		return e.err
+	}
+	input, err := io.ReadAll(stdin)
+	if err != nil {
+		return err
+	}
+	e.inputs[pod.Name+"/"+container] = string(input)
+	return nil
+}
+
+type reloadSecureSettingsESClient struct {
+	esclient.Client
+	reloadErr error
+	reloaded  bool
+	password  string
+}
+
+func (c *reloadSecureSettingsESClient) ReloadSecureSettings(_ context.Context, password string) error {
+	if c.reloadErr != nil {
+		return c.reloadErr
+	}
+	c.reloaded = true
+	c.password = password
+	return nil
+}
+
+func Test_reloadSecureSettings(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	pod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: map[string]string{label.ClusterNameLabelName: "es"}},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	keystoreResources := &keystore.Resources{
+		Hash: "h2",
+		Data: map[string][]byte{"s3.client.default.secret_key": []byte("secret")},
+	}
+	tests := []struct {
+		name              string
+		pods              []client.Object
+		keystorePassword  *esv1.SecretKeyRef
+		keystoreResources *keystore.Resources
+		loadedHash        string
+		esReachable       bool
+		execErr           error
+		reloadErr         error
+		wantInputs        map[string]string
+		wantReloaded      bool
+		wantPassword      string
+		wantRequeue       bool
+		wantErr           bool
+		wantLoadedHash    string
+	}{
+		{
+			name:        "no secure settings",
+			pods:        []client.Object{pod("es-0", corev1.PodRunning)},
+			esReachable: true,
+			wantInputs:  map[string]string{},
+		},
+		{
+			name:              "secure settings already loaded",
+			pods:              []client.Object{pod("es-0", corev1.PodRunning)},
+			keystoreResources: keystoreResources,
+			loadedHash:        "h2",
+			esReachable:       true,
+			wantInputs:        map[string]string{},
+			wantLoadedHash:    "h2",
+		},
+		{
+			name:              "Elasticsearch not reachable",
+			pods:              []client.Object{pod("es-0", corev1.PodRunning)},
+			keystoreResources: keystoreResources,
+			loadedHash:        "h1",
+			wantInputs:        map[string]string{},
+			wantRequeue:       true,
+			wantLoadedHash:    "h1",
+		},
+		{
+			name:              "Pod not running",
+			pods:              []client.Object{pod("es-0", corev1.PodRunning), pod("es-1", corev1.PodPending)},
+			keystoreResources: keystoreResources,
+			loadedHash:        "h1",
+			esReachable:       true,
+			wantInputs:        map[string]string{},
+			wantRequeue:       true,
+			wantLoadedHash:    "h1",
+		},
+		{
+			name:              "update the keystores and reload the secure settings",
+			pods:              []client.Object{pod("es-0", corev1.PodRunning), pod("es-1", corev1.PodRunning)},
+			keystoreResources: keystoreResources,
+			loadedHash:        "h1",
+			esReachable:       true,
+			wantInputs: map[string]string{
+				"es-0/elasticsearch": "s3.client.default.secret_key c2VjcmV0\n",
+				"es-1/elasticsearch": "s3.client.default.secret_key c2VjcmV0\n",
+			},
+			wantReloaded:   true,
+			wantLoadedHash: "h2",
+		},
+		{
+			name: "reload the secure settings of a password protected keystore",
+			pods: []client.Object{
+				pod("es-0", corev1.PodRunning),
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "keystore-password"},
+					Data:       map[string][]byte{"password": []byte("changeme\n")},
+				},
+			},
+			keystorePassword:  &esv1.SecretKeyRef{SecretName: "keystore-password", Key: "password"},
+			keystoreResources: keystoreResources,
+			loadedHash:        "h1",
+			esReachable:       true,
+			wantInputs:        map[string]string{"es-0/elasticsearch": "s3.client.default.secret_key c2VjcmV0\n"},
+			wantReloaded:      true,
+			wantPassword:      "changeme",
+			wantLoadedHash:    "h2",
+		},
+		{
+			name:              "keystore password Secret not found",
+			pods:              []client.Object{pod("es-0", corev1.PodRunning)},
+			keystorePassword:  &esv1.SecretKeyRef{SecretName: "keystore-password", Key: "password"},
+			keystoreResources: keystoreResources,
+			loadedHash:        "h1",
+			esReachable:       true,
+			wantInputs:        map[string]string{"es-0/elasticsearch": "s3.client.default.secret_key c2VjcmV0\n"},
+			wantErr:           true,
+			wantLoadedHash:    "h1",
+		},
+		{
+			name:              "failed to update a keystore",
+			pods:              []client.Object{pod("es-0", corev1.PodRunning)},
+			keystoreResources: keystoreResources,
+			loadedHash:        "h1",
+			esReachable:       true,
+			execErr:           errors.New("command terminated with exit code 1"),
+			wantInputs:        map[string]string{},
+			wantErr:           true,
+			wantLoadedHash:    "h1",
+		},
+		{
+			name:              "failed to reload the secure settings",
+			pods:              []client.Object{pod("es-0", corev1.PodRunning)},
+			keystoreResources: keystoreResources,
+			loadedHash:        "h1",
+			esReachable:       true,
+			reloadErr:         errors.New("failed to reload secure settings on nodes: es-0: Keystore is missing"),
+			wantInputs:        map[string]string{"es-0/elasticsearch": "s3.client.default.secret_key c2VjcmV0\n"},
+			wantErr:           true,
+			wantLoadedHash:    "h1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := *es.DeepCopy()
+			es.Spec.KeystorePassword = tt.keystorePassword
+			if tt.loadedHash != "" {
+				annotations, err := hints.OrchestrationsHints{SecureSettingsHash: tt.loadedHash}.AsAnnotation()
+				require.NoError(t, err)
+				es.Annotations = annotations
+			}
+			reconcileState := reconcile.MustNewState(es)
+			executor := &fakePodExecutor{err: tt.execErr, inputs: map[string]string{}}
+			esClient := &reloadSecureSettingsESClient{reloadErr: tt.reloadErr}
+
+			results := reloadSecureSettings(context.Background(), k8s.NewFakeClient(tt.pods...), executor, esClient, es, tt.esReachable, tt.keystoreResources, reconcileState)
+
+			require.Equal(t, tt.wantErr, results.HasError())
+			if !tt.wantErr {
+				reconciled, _ := results.IsReconciled()
+				require.Equal(t, tt.wantRequeue, !reconciled)
+			}
+			require.Equal(t, tt.wantInputs, executor.inputs)
+			require.Equal(t, tt.wantReloaded, esClient.reloaded)
+			require.Equal(t, tt.wantPassword, esClient.password)
+			require.Equal(t, tt.wantLoadedHash, reconcileState.OrchestrationHints().SecureSettingsHash)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/services"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileServiceProfileServices reconciles the Services of the service profiles of the cluster, and deletes the
+// Services of the profiles which have been removed from the specification.
+func reconcileServiceProfileServices(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) ([]corev1.Service, error) {
+	expected := make(map[string]struct{}, len(es.Spec.HTTPServiceProfiles))
+	reconciled := make([]corev1.Service, 0, len(es.Spec.HTTPServiceProfiles))
+	for _, profile := range es.Spec.HTTPServiceProfiles {
+		svc, err := common.ReconcileService(ctx, c, services.NewServiceProfileService(es, profile), &es)
+		if err != nil {
+			return nil, err
+		}
+		expected[svc.Name] = struct{}{}
+		reconciled = append(reconciled, *svc)
+	}
+
+	var actual corev1.ServiceList
+	if err := c.List(ctx, &actual,
+		client.InNamespace(es.Namespace),
+		label.NewLabelSelectorForElasticsearch(es),
+		client.HasLabels{label.ServiceProfileLabelName},
+	); err != nil {
+		return nil, err
+	}
+	for i := range actual.Items {
+		if _, exists := expected[actual.Items[i].Name]; exists {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Deleting service profile Service", "namespace", es.Namespace, "es_name", es.Name, "service", actual.Items[i].Name)
+		if err := c.Delete(ctx, &actual.Items[i]); err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return reconciled, nil
+}
+
+// labelPodsWithServiceProfiles labels the Pods with the service profiles they match, so that they are targeted by the
+// Services of the profiles. The Pods are patched directly rather than through the StatefulSets to not restart them.
+func labelPodsWithServiceProfiles(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) *reconciler.Results {
+	span, ctx := apm.StartSpan(ctx, "label_pods_with_service_profiles", tracing.SpanTypeApp)
+	defer span.End()
+	results := reconciler.NewResult(ctx)
+	actualPods, err := sset.GetActualPodsForCluster(c, es)
+	if err != nil {
+		return results.WithError(err)
+	}
+	for _, pod := range actualPods {
+		results.WithError(labelPodWithServiceProfiles(ctx, c, pod, es))
+	}
+	return results
+}
+
+func labelPodWithServiceProfiles(ctx context.Context, c k8s.Client, pod corev1.Pod, es esv1.Elasticsearch) error {
+	podLabels := serviceProfileLabelsPatch(pod, es.Spec.HTTPServiceProfiles)
+	// Stop early if the labels are already up to date.
+	if len(podLabels) == 0 {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Updating Pod service profile labels", "namespace", es.Namespace, "es_name", es.Name, "pod", pod.Name, "labels", podLabels)
+	mergePatch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": podLabels,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.Patch(ctx, &pod, client.RawPatch(types.StrategicMergePatchType, mergePatch)); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// serviceProfileLabelsPatch returns the service profile labels to update on the given Pod: the labels of the matching
+// profiles which are missing are set to true, the labels of the other profiles are set to nil to be removed.
+func serviceProfileLabelsPatch(pod corev1.Pod, profiles []esv1.ServiceProfile) map[string]interface{} {
+	expected := make(map[string]struct{}, len(profiles))
+	for _, profile := range profiles {
+		if label.MatchesServiceProfile(pod, profile) {
+			expected[label.ServiceProfilePodLabel(profile.Name)] = struct{}{}
+		}
+	}
+	patch := map[string]interface{}{}
+	for podLabel := range expected {
+		if pod.Labels[podLabel] != "true" {
+			patch[podLabel] = "true"
+		}
+	}
+	for podLabel := range pod.Labels {
+		if _, exists := expected[podLabel]; !exists && strings.HasPrefix(podLabel, label.ServiceProfilePodLabelPrefix) {
+			patch[podLabel] = nil
+		}
+	}
+	return patch
+}
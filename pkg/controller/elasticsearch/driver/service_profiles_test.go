@@ -0,0 +1,119 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func serviceProfilesES(profiles ...esv1.ServiceProfile) esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: esName, UID: "es-uid"},
+		Spec:       esv1.ElasticsearchSpec{HTTPServiceProfiles: profiles},
+	}
+}
+
+func Test_reconcileServiceProfileServices(t *testing.T) {
+	es := serviceProfilesES(
+		esv1.ServiceProfile{Name: "ingest", Roles: []string{"ingest"}},
+		esv1.ServiceProfile{Name: "coordinating", Roles: []string{esv1.CoordinatingOnlyProfileRole}},
+	)
+	removed := corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns",
+		Name:      esv1.ServiceProfileService(esName, "removed"),
+		Labels:    map[string]string{label.ClusterNameLabelName: esName, label.ServiceProfileLabelName: "removed"},
+	}}
+	external := corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns",
+		Name:      esv1.HTTPService(esName),
+		Labels:    map[string]string{label.ClusterNameLabelName: esName},
+	}}
+	c := k8s.NewFakeClient(&es, &removed, &external)
+
+	reconciled, err := reconcileServiceProfileServices(context.Background(), c, es)
+	require.NoError(t, err)
+	require.Len(t, reconciled, 2)
+	assert.Equal(t, "elasticsearch-sample-es-ingest-http", reconciled[0].Name)
+	assert.Equal(t, "elasticsearch-sample-es-coordinating-http", reconciled[1].Name)
+
+	var actual corev1.ServiceList
+	require.NoError(t, c.List(context.Background(), &actual))
+	names := make([]string, 0, len(actual.Items))
+	for _, svc := range actual.Items {
+		names = append(names, svc.Name)
+	}
+	// the Service of the removed profile is deleted, the other Services are left untouched
+	assert.ElementsMatch(t, []string{
+		"elasticsearch-sample-es-ingest-http",
+		"elasticsearch-sample-es-coordinating-http",
+		"elasticsearch-sample-es-http",
+	}, names)
+	err = c.Get(context.Background(), k8s.ExtractNamespacedName(&removed), &corev1.Service{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func Test_labelPodsWithServiceProfiles(t *testing.T) {
+	es := serviceProfilesES(
+		esv1.ServiceProfile{Name: "ingest", Roles: []string{"ingest"}},
+		esv1.ServiceProfile{Name: "coordinating", Roles: []string{esv1.CoordinatingOnlyProfileRole}},
+	)
+	pod := func(name string, podLabels map[string]string) *corev1.Pod {
+		podLabels[label.ClusterNameLabelName] = esName
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: podLabels}}
+	}
+	c := k8s.NewFakeClient(
+		&es,
+		pod("ingest", map[string]string{
+			string(label.NodeTypesIngestLabelName): "true",
+			string(label.NodeTypesMasterLabelName): "false",
+		}),
+		pod("master", map[string]string{
+			string(label.NodeTypesIngestLabelName):    "false",
+			string(label.NodeTypesMasterLabelName):    "true",
+			label.ServiceProfilePodLabel("removed"):   "true",
+			label.ServiceProfilePodLabel("ingest"):    "true",
+			label.ServiceProfilePodLabel("unchanged"): "false",
+		}),
+		pod("coordinating", map[string]string{
+			string(label.NodeTypesIngestLabelName):       "false",
+			string(label.NodeTypesMasterLabelName):       "false",
+			label.ServiceProfilePodLabel("coordinating"): "true",
+		}),
+	)
+
+	results := labelPodsWithServiceProfiles(context.Background(), c, es)
+	require.False(t, results.HasError())
+
+	expected := map[string][]string{
+		"ingest":       {label.ServiceProfilePodLabel("ingest")},
+		"master":       nil,
+		"coordinating": {label.ServiceProfilePodLabel("coordinating")},
+	}
+	for name, expectedLabels := range expected {
+		var actual corev1.Pod
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: name}, &actual))
+		var actualLabels []string
+		for k, v := range actual.Labels {
+			if strings.HasPrefix(k, label.ServiceProfilePodLabelPrefix) {
+				assert.Equal(t, "true", v)
+				actualLabels = append(actualLabels, k)
+			}
+		}
+		assert.ElementsMatch(t, expectedLabels, actualLabels, "Pod %s", name)
+	}
+}
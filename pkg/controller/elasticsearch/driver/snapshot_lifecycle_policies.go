@@ -0,0 +1,209 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/snapshotlifecyclepolicy"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileSnapshotLifecyclePolicies creates or updates the snapshot lifecycle policies of the specification, deletes
+// the policies removed from the specification, and reports their executions in the status. Policies that did not take
+// any successful snapshot for longer than their failure threshold are reported in the SnapshotLifecyclePoliciesReady
+// condition and in warning events. The policies configured by the operator are tracked in the status, in order to only
+// delete those.
+func reconcileSnapshotLifecyclePolicies(
+	ctx context.Context,
+	esClient esclient.Client,
+	es esv1.Elasticsearch,
+	esReachable bool,
+	reconcileState *reconcile.State,
+) *reconciler.Results {
+	results := reconciler.NewResult(ctx)
+	if len(es.Spec.SnapshotLifecyclePolicies) == 0 && len(es.Status.SnapshotLifecyclePolicies) == 0 {
+		return results
+	}
+	if !esReachable {
+		if len(es.Spec.SnapshotLifecyclePolicies) > 0 {
+			reconcileState.ReportCondition(esv1.SnapshotLifecyclePoliciesReady, corev1.ConditionUnknown, "Waiting for Elasticsearch to be reachable")
+		}
+		return results
+	}
+
+	actual, err := esClient.GetSnapshotLifecyclePolicies(ctx)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to get snapshot lifecycle policies: %s", esclient.ErrorReason(err))
+		reconcileState.ReportCondition(esv1.SnapshotLifecyclePoliciesReady, corev1.ConditionUnknown, msg)
+		return results.WithReconciliationState(defaultRequeue.WithReason(msg))
+	}
+
+	var messages []string
+	var pendingDeletions []esv1.SnapshotLifecyclePolicyStatus
+
+	// delete the policies removed from the specification, keeping track of those that could not be deleted yet
+	for _, previous := range es.Status.SnapshotLifecyclePolicies {
+		if hasSnapshotLifecyclePolicy(es.Spec.SnapshotLifecyclePolicies, previous.Name) {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Deleting snapshot lifecycle policy", "policy", previous.Name)
+		if err := esClient.DeleteSnapshotLifecyclePolicy(ctx, previous.Name); err != nil && !esclient.IsNotFound(err) {
+			msg := fmt.Sprintf("Failed to delete snapshot lifecycle policy %s: %s", previous.Name, esclient.ErrorReason(err))
+			reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReconciliationError, msg)
+			messages = append(messages, msg)
+			pendingDeletions = append(pendingDeletions, previous)
+		}
+	}
+
+	// create or update the policies of the specification
+	updated := false
+	for _, policy := range es.Spec.SnapshotLifecyclePolicies {
+		expected := expectedSnapshotLifecyclePolicy(policy)
+		if info, exists := actual[policy.Name]; exists && snapshotlifecyclepolicy.SamePolicy(expected, info.Policy) {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Updating snapshot lifecycle policy", "policy", policy.Name)
+		if err := esClient.UpdateSnapshotLifecyclePolicy(ctx, policy.Name, expected); err != nil {
+			msg := fmt.Sprintf("Failed to update snapshot lifecycle policy %s: %s", policy.Name, esclient.ErrorReason(err))
+			reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReconciliationError, msg)
+			messages = append(messages, msg)
+			continue
+		}
+		updated = true
+	}
+	if updated {
+		// retrieve the next executions of the updated policies
+		if actual, err = esClient.GetSnapshotLifecyclePolicies(ctx); err != nil {
+			msg := fmt.Sprintf("Failed to get snapshot lifecycle policies: %s", esclient.ErrorReason(err))
+			reconcileState.ReportCondition(esv1.SnapshotLifecyclePoliciesReady, corev1.ConditionUnknown, msg)
+			return results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+	}
+
+	// report the executions of the configured policies
+	now := time.Now()
+	var failingPolicies []string
+	var policyStatuses []esv1.SnapshotLifecyclePolicyStatus //nolint:prealloc
+	for _, policy := range es.Spec.SnapshotLifecyclePolicies {
+		info, exists := actual[policy.Name]
+		if !exists {
+			continue
+		}
+		previous, _ := findSnapshotLifecyclePolicyStatus(es.Status.SnapshotLifecyclePolicies, policy.Name)
+		status := snapshotLifecyclePolicyStatus(policy.Name, info, previous)
+		policyStatuses = append(policyStatuses, status)
+		if status.FailingSince != nil && now.Sub(status.FailingSince.Time) >= policy.FailureThresholdOrDefault() {
+			failingPolicies = append(failingPolicies, fmt.Sprintf("%s (since %s: %s)", policy.Name, status.FailingSince.UTC().Format(time.RFC3339), status.LastFailure))
+		}
+	}
+	reconcileState.UpdateSnapshotLifecyclePolicies(append(policyStatuses, pendingDeletions...))
+
+	if len(failingPolicies) > 0 {
+		msg := fmt.Sprintf("Snapshot lifecycle policies failing for longer than their failure threshold: %s", strings.Join(failingPolicies, ", "))
+		reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnhealthy, msg)
+		messages = append(messages, msg)
+	}
+
+	if len(es.Spec.SnapshotLifecyclePolicies) == 0 && len(messages) == 0 {
+		// all the policies are deleted
+		return results
+	}
+	if len(messages) > 0 {
+		msg := strings.Join(messages, ". ")
+		reconcileState.ReportCondition(esv1.SnapshotLifecyclePoliciesReady, corev1.ConditionFalse, msg)
+		if len(failingPolicies) < len(messages) {
+			// only requeue to retry failed API calls, failing snapshots being checked periodically
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+		return results
+	}
+	reconcileState.ReportCondition(esv1.SnapshotLifecyclePoliciesReady, corev1.ConditionTrue, "All snapshot lifecycle policies are configured")
+	return results
+}
+
+func expectedSnapshotLifecyclePolicy(policy esv1.SnapshotLifecyclePolicy) esclient.SnapshotLifecyclePolicy {
+	expected := esclient.SnapshotLifecyclePolicy{
+		Schedule:   policy.Schedule,
+		Name:       policy.SnapshotNameOrDefault(),
+		Repository: policy.Repository,
+	}
+	if policy.Config != nil {
+		expected.Config = policy.Config.Data
+	}
+	if retention := policy.Retention; retention != nil {
+		expected.Retention = &esclient.SnapshotLifecyclePolicyRetention{
+			ExpireAfter: retention.ExpireAfter,
+			MinCount:    retention.MinCount,
+			MaxCount:    retention.MaxCount,
+		}
+	}
+	return expected
+}
+
+// snapshotLifecyclePolicyStatus returns the status of a policy from its executions, carrying over the time of the
+// first failure following the last successful snapshot from the previous status.
+func snapshotLifecyclePolicyStatus(
+	name string,
+	info esclient.SnapshotLifecyclePolicyInfo,
+	previous esv1.SnapshotLifecyclePolicyStatus,
+) esv1.SnapshotLifecyclePolicyStatus {
+	status := esv1.SnapshotLifecyclePolicyStatus{Name: name}
+	if info.LastSuccess != nil {
+		status.LastSuccessTime = millisToTime(info.LastSuccess.TimeMillis)
+	}
+	if info.LastFailure != nil {
+		status.LastFailureTime = millisToTime(info.LastFailure.TimeMillis)
+		status.LastFailure = info.LastFailure.Details
+	}
+	if info.NextExecutionMillis > 0 {
+		status.NextExecutionTime = millisToTime(info.NextExecutionMillis)
+	}
+
+	failing := info.LastFailure != nil && (info.LastSuccess == nil || info.LastFailure.TimeMillis > info.LastSuccess.TimeMillis)
+	if !failing {
+		return status
+	}
+	status.FailingSince = status.LastFailureTime
+	if previous.FailingSince != nil && (status.LastSuccessTime == nil || previous.FailingSince.After(status.LastSuccessTime.Time)) {
+		// same sequence of failures
+		status.FailingSince = previous.FailingSince
+	}
+	return status
+}
+
+func millisToTime(millis int64) *metav1.Time {
+	t := metav1.NewTime(time.UnixMilli(millis))
+	return &t
+}
+
+func hasSnapshotLifecyclePolicy(policies []esv1.SnapshotLifecyclePolicy, name string) bool {
+	for _, policy := range policies {
+		if policy.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func findSnapshotLifecyclePolicyStatus(statuses []esv1.SnapshotLifecyclePolicyStatus, name string) (esv1.SnapshotLifecyclePolicyStatus, bool) {
+	for _, status := range statuses {
+		if status.Name == name {
+			return status, true
+		}
+	}
+	return esv1.SnapshotLifecyclePolicyStatus{}, false
+}
@@ -0,0 +1,216 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+)
+
+var nextExecution = time.Date(2024, 1, 2, 1, 30, 0, 0, time.UTC)
+
+type snapshotLifecyclePoliciesESClient struct {
+	esclient.Client
+	policies  map[string]esclient.SnapshotLifecyclePolicyInfo
+	updateErr error
+	updated   []string
+	deleted   []string
+}
+
+func (c *snapshotLifecyclePoliciesESClient) GetSnapshotLifecyclePolicies(_ context.Context) (map[string]esclient.SnapshotLifecyclePolicyInfo, error) {
+	return c.policies, nil
+}
+
+func (c *snapshotLifecyclePoliciesESClient) UpdateSnapshotLifecyclePolicy(_ context.Context, id string, policy esclient.SnapshotLifecyclePolicy) error {
+	if c.updateErr != nil {
+		return c.updateErr
+	}
+	c.updated = append(c.updated, id)
+	info := c.policies[id]
+	info.Policy = policy
+	info.NextExecutionMillis = nextExecution.UnixMilli()
+	c.policies[id] = info
+	return nil
+}
+
+func (c *snapshotLifecyclePoliciesESClient) DeleteSnapshotLifecyclePolicy(_ context.Context, id string) error {
+	if _, exists := c.policies[id]; !exists {
+		return &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	c.deleted = append(c.deleted, id)
+	delete(c.policies, id)
+	return nil
+}
+
+func Test_reconcileSnapshotLifecyclePolicies(t *testing.T) {
+	repositoryErr := &esclient.APIError{StatusCode: http.StatusBadRequest}
+	repositoryErr.ErrorResponse.Error.Reason = "no such repository [backups]"
+	nightly := esv1.SnapshotLifecyclePolicy{Name: "nightly", Schedule: "0 30 1 * * ?", Repository: "backups"}
+	hourly := esv1.SnapshotLifecyclePolicy{
+		Name:             "hourly",
+		Schedule:         "0 0 * * * ?",
+		Repository:       "backups",
+		FailureThreshold: &metav1.Duration{Duration: time.Hour},
+	}
+	now := time.Now()
+	info := func(policy esv1.SnapshotLifecyclePolicy, lastSuccess, lastFailure time.Time) esclient.SnapshotLifecyclePolicyInfo {
+		info := esclient.SnapshotLifecyclePolicyInfo{
+			Policy:              expectedSnapshotLifecyclePolicy(policy),
+			NextExecutionMillis: nextExecution.UnixMilli(),
+		}
+		if !lastSuccess.IsZero() {
+			info.LastSuccess = &esclient.SnapshotInvocation{SnapshotName: "snapshot", TimeMillis: lastSuccess.UnixMilli()}
+		}
+		if !lastFailure.IsZero() {
+			info.LastFailure = &esclient.SnapshotInvocation{SnapshotName: "snapshot", TimeMillis: lastFailure.UnixMilli(), Details: "repository is missing"}
+		}
+		return info
+	}
+	timePtr := func(t time.Time) *metav1.Time {
+		mt := metav1.NewTime(time.UnixMilli(t.UnixMilli()))
+		return &mt
+	}
+	tests := []struct {
+		name           string
+		policies       []esv1.SnapshotLifecyclePolicy
+		previousStatus []esv1.SnapshotLifecyclePolicyStatus
+		configured     map[string]esclient.SnapshotLifecyclePolicyInfo
+		updateErr      error
+		esReachable    bool
+		wantUpdated    []string
+		wantDeleted    []string
+		wantStatus     []esv1.SnapshotLifecyclePolicyStatus
+		wantCondition  corev1.ConditionStatus
+		wantEvents     int
+		wantRequeue    bool
+	}{
+		{
+			name:        "no snapshot lifecycle policy",
+			esReachable: true,
+		},
+		{
+			name:          "Elasticsearch not reachable",
+			policies:      []esv1.SnapshotLifecyclePolicy{nightly},
+			wantCondition: corev1.ConditionUnknown,
+		},
+		{
+			name:          "create the policies",
+			policies:      []esv1.SnapshotLifecyclePolicy{nightly, hourly},
+			esReachable:   true,
+			wantUpdated:   []string{"nightly", "hourly"},
+			wantStatus:    []esv1.SnapshotLifecyclePolicyStatus{{Name: "nightly", NextExecutionTime: timePtr(nextExecution)}, {Name: "hourly", NextExecutionTime: timePtr(nextExecution)}},
+			wantCondition: corev1.ConditionTrue,
+		},
+		{
+			name:     "update a changed policy",
+			policies: []esv1.SnapshotLifecyclePolicy{nightly},
+			configured: map[string]esclient.SnapshotLifecyclePolicyInfo{
+				"nightly": info(esv1.SnapshotLifecyclePolicy{Name: "nightly", Schedule: "0 30 2 * * ?", Repository: "backups"}, now.Add(-time.Hour), time.Time{}),
+			},
+			esReachable:   true,
+			wantUpdated:   []string{"nightly"},
+			wantStatus:    []esv1.SnapshotLifecyclePolicyStatus{{Name: "nightly", LastSuccessTime: timePtr(now.Add(-time.Hour)), NextExecutionTime: timePtr(nextExecution)}},
+			wantCondition: corev1.ConditionTrue,
+		},
+		{
+			name:           "delete a policy removed from the specification",
+			previousStatus: []esv1.SnapshotLifecyclePolicyStatus{{Name: "nightly"}},
+			configured: map[string]esclient.SnapshotLifecyclePolicyInfo{
+				"nightly": info(nightly, now.Add(-time.Hour), time.Time{}),
+			},
+			esReachable: true,
+			wantDeleted: []string{"nightly"},
+		},
+		{
+			name:          "update failure",
+			policies:      []esv1.SnapshotLifecyclePolicy{nightly},
+			updateErr:     repositoryErr,
+			esReachable:   true,
+			wantCondition: corev1.ConditionFalse,
+			wantEvents:    1,
+			wantRequeue:   true,
+		},
+		{
+			name:     "failing within the failure threshold",
+			policies: []esv1.SnapshotLifecyclePolicy{nightly},
+			configured: map[string]esclient.SnapshotLifecyclePolicyInfo{
+				"nightly": info(nightly, now.Add(-25*time.Hour), now.Add(-time.Hour)),
+			},
+			esReachable: true,
+			wantStatus: []esv1.SnapshotLifecyclePolicyStatus{{
+				Name:              "nightly",
+				LastSuccessTime:   timePtr(now.Add(-25 * time.Hour)),
+				LastFailureTime:   timePtr(now.Add(-time.Hour)),
+				LastFailure:       "repository is missing",
+				FailingSince:      timePtr(now.Add(-time.Hour)),
+				NextExecutionTime: timePtr(nextExecution),
+			}},
+			wantCondition: corev1.ConditionTrue,
+		},
+		{
+			name:           "failing for longer than the failure threshold",
+			policies:       []esv1.SnapshotLifecyclePolicy{hourly},
+			previousStatus: []esv1.SnapshotLifecyclePolicyStatus{{Name: "hourly", FailingSince: timePtr(now.Add(-2 * time.Hour))}},
+			configured: map[string]esclient.SnapshotLifecyclePolicyInfo{
+				"hourly": info(hourly, time.Time{}, now.Add(-time.Minute)),
+			},
+			esReachable: true,
+			wantStatus: []esv1.SnapshotLifecyclePolicyStatus{{
+				Name:              "hourly",
+				LastFailureTime:   timePtr(now.Add(-time.Minute)),
+				LastFailure:       "repository is missing",
+				FailingSince:      timePtr(now.Add(-2 * time.Hour)),
+				NextExecutionTime: timePtr(nextExecution),
+			}},
+			wantCondition: corev1.ConditionFalse,
+			wantEvents:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+				Spec:       esv1.ElasticsearchSpec{SnapshotLifecyclePolicies: tt.policies},
+				Status:     esv1.ElasticsearchStatus{SnapshotLifecyclePolicies: tt.previousStatus},
+			}
+			configured := tt.configured
+			if configured == nil {
+				configured = map[string]esclient.SnapshotLifecyclePolicyInfo{}
+			}
+			esClient := &snapshotLifecyclePoliciesESClient{policies: configured, updateErr: tt.updateErr}
+			reconcileState := reconcile.MustNewState(es)
+
+			results := reconcileSnapshotLifecyclePolicies(context.Background(), esClient, es, tt.esReachable, reconcileState)
+
+			require.Equal(t, tt.wantUpdated, esClient.updated)
+			require.Equal(t, tt.wantDeleted, esClient.deleted)
+			reconciled, _ := results.IsReconciled()
+			require.Equal(t, tt.wantRequeue, !reconciled)
+			require.Len(t, reconcileState.Events(), tt.wantEvents)
+			if tt.esReachable {
+				_, updated := reconcileState.Apply()
+				require.NotNil(t, updated)
+				require.Equal(t, tt.wantStatus, updated.Status.SnapshotLifecyclePolicies)
+			}
+			index := reconcileState.Conditions.Index(esv1.SnapshotLifecyclePoliciesReady)
+			if tt.wantCondition == "" {
+				require.Equal(t, -1, index)
+				return
+			}
+			require.GreaterOrEqual(t, index, 0)
+			require.Equal(t, tt.wantCondition, reconcileState.Conditions[index].Status)
+		})
+	}
+}
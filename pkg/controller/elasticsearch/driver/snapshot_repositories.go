@@ -0,0 +1,86 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/snapshotrepository"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileSnapshotRepositories registers the snapshot repositories of the specification that are not registered yet,
+// or whose settings changed, and reports failures in the SnapshotRepositoriesReady condition. Repositories with secure
+// settings are only registered once all the nodes loaded the current secure settings in their keystore, since they
+// are verified on all the nodes.
+func reconcileSnapshotRepositories(
+	ctx context.Context,
+	esClient esclient.Client,
+	es esv1.Elasticsearch,
+	esReachable bool,
+	secureSettingsLoaded bool,
+	reconcileState *reconcile.State,
+) *reconciler.Results {
+	results := reconciler.NewResult(ctx)
+	if len(es.Spec.SnapshotRepositories) == 0 {
+		return results
+	}
+	if !esReachable {
+		reconcileState.ReportCondition(esv1.SnapshotRepositoriesReady, corev1.ConditionUnknown, "Waiting for Elasticsearch to be reachable")
+		return results
+	}
+
+	var messages []string
+	for _, repository := range es.Spec.SnapshotRepositories {
+		if len(repository.SecureSettings) > 0 && !secureSettingsLoaded {
+			messages = append(messages, fmt.Sprintf("Snapshot repository %s is waiting for the nodes to load its secure settings", repository.Name))
+			continue
+		}
+		if msg := reconcileSnapshotRepository(ctx, esClient, repository); msg != "" {
+			reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReconciliationError, msg)
+			messages = append(messages, msg)
+		}
+	}
+
+	if len(messages) > 0 {
+		msg := strings.Join(messages, ". ")
+		reconcileState.ReportCondition(esv1.SnapshotRepositoriesReady, corev1.ConditionFalse, msg)
+		results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		return results
+	}
+	reconcileState.ReportCondition(esv1.SnapshotRepositoriesReady, corev1.ConditionTrue, "All snapshot repositories are registered")
+	return results
+}
+
+// reconcileSnapshotRepository registers the given repository if it is not registered with the same settings yet, and
+// returns a message describing the failure to register or verify it, if any.
+func reconcileSnapshotRepository(ctx context.Context, esClient esclient.Client, repository esv1.SnapshotRepository) string {
+	expected := esclient.SnapshotRepository{Type: repository.Type}
+	if repository.Settings != nil {
+		expected.Settings = repository.Settings.Data
+	}
+	actual, err := esClient.GetSnapshotRepository(ctx, repository.Name)
+	switch {
+	case err == nil && snapshotrepository.SameRepository(expected, actual):
+		return ""
+	case err != nil && !esclient.IsNotFound(err):
+		return fmt.Sprintf("Failed to get snapshot repository %s: %s", repository.Name, esclient.ErrorReason(err))
+	}
+
+	ulog.FromContext(ctx).Info("Registering snapshot repository", "repository", repository.Name)
+	if err := esClient.UpdateSnapshotRepository(ctx, repository.Name, expected, repository.VerifyOrDefault()); err != nil {
+		return fmt.Sprintf("Failed to register snapshot repository %s: %s", repository.Name, esclient.ErrorReason(err))
+	}
+	return ""
+}
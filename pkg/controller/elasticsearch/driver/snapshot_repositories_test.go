@@ -0,0 +1,148 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+)
+
+type snapshotRepositoriesESClient struct {
+	esclient.Client
+	repositories map[string]esclient.SnapshotRepository
+	updateErr    error
+	updated      []string
+}
+
+func (c *snapshotRepositoriesESClient) GetSnapshotRepository(_ context.Context, name string) (esclient.SnapshotRepository, error) {
+	repository, exists := c.repositories[name]
+	if !exists {
+		return esclient.SnapshotRepository{}, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return repository, nil
+}
+
+func (c *snapshotRepositoriesESClient) UpdateSnapshotRepository(_ context.Context, name string, repository esclient.SnapshotRepository, _ bool) error {
+	if c.updateErr != nil {
+		return c.updateErr
+	}
+	c.updated = append(c.updated, name)
+	c.repositories[name] = repository
+	return nil
+}
+
+func Test_reconcileSnapshotRepositories(t *testing.T) {
+	verificationErr := &esclient.APIError{StatusCode: http.StatusInternalServerError}
+	verificationErr.ErrorResponse.Error.Reason = "[backups] path is not accessible on master node"
+	s3 := esv1.SnapshotRepository{
+		Name:           "backups",
+		Type:           "s3",
+		Settings:       &commonv1.Config{Data: map[string]interface{}{"bucket": "es-backups"}},
+		SecureSettings: []commonv1.SecretSource{{SecretName: "s3-credentials"}},
+	}
+	fs := esv1.SnapshotRepository{
+		Name:     "local",
+		Type:     "fs",
+		Settings: &commonv1.Config{Data: map[string]interface{}{"location": "/backups"}},
+	}
+	tests := []struct {
+		name                 string
+		repositories         []esv1.SnapshotRepository
+		registered           map[string]esclient.SnapshotRepository
+		updateErr            error
+		esReachable          bool
+		secureSettingsLoaded bool
+		wantUpdated          []string
+		wantCondition        corev1.ConditionStatus
+		wantEvents           int
+		wantRequeue          bool
+	}{
+		{
+			name:        "no snapshot repository",
+			esReachable: true,
+		},
+		{
+			name:          "Elasticsearch not reachable",
+			repositories:  []esv1.SnapshotRepository{fs},
+			wantCondition: corev1.ConditionUnknown,
+		},
+		{
+			name:                 "register the repositories",
+			repositories:         []esv1.SnapshotRepository{s3, fs},
+			esReachable:          true,
+			secureSettingsLoaded: true,
+			wantUpdated:          []string{"backups", "local"},
+			wantCondition:        corev1.ConditionTrue,
+		},
+		{
+			name:         "repositories already registered",
+			repositories: []esv1.SnapshotRepository{s3, fs},
+			registered: map[string]esclient.SnapshotRepository{
+				// settings are returned as strings
+				"backups": {Type: "s3", Settings: map[string]interface{}{"bucket": "es-backups"}},
+				"local":   {Type: "fs", Settings: map[string]interface{}{"location": "/backups"}},
+			},
+			esReachable:          true,
+			secureSettingsLoaded: true,
+			wantCondition:        corev1.ConditionTrue,
+		},
+		{
+			name:          "wait for the secure settings to be loaded",
+			repositories:  []esv1.SnapshotRepository{s3, fs},
+			esReachable:   true,
+			wantUpdated:   []string{"local"},
+			wantCondition: corev1.ConditionFalse,
+			wantRequeue:   true,
+		},
+		{
+			name:                 "verification failure",
+			repositories:         []esv1.SnapshotRepository{fs},
+			updateErr:            verificationErr,
+			esReachable:          true,
+			secureSettingsLoaded: true,
+			wantCondition:        corev1.ConditionFalse,
+			wantEvents:           1,
+			wantRequeue:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+				Spec:       esv1.ElasticsearchSpec{SnapshotRepositories: tt.repositories},
+			}
+			registered := tt.registered
+			if registered == nil {
+				registered = map[string]esclient.SnapshotRepository{}
+			}
+			esClient := &snapshotRepositoriesESClient{repositories: registered, updateErr: tt.updateErr}
+			reconcileState := reconcile.MustNewState(es)
+
+			results := reconcileSnapshotRepositories(context.Background(), esClient, es, tt.esReachable, tt.secureSettingsLoaded, reconcileState)
+
+			require.Equal(t, tt.wantUpdated, esClient.updated)
+			reconciled, _ := results.IsReconciled()
+			require.Equal(t, tt.wantRequeue, !reconciled)
+			require.Len(t, reconcileState.Events(), tt.wantEvents)
+			index := reconcileState.Conditions.Index(esv1.SnapshotRepositoriesReady)
+			if tt.wantCondition == "" {
+				require.Equal(t, -1, index)
+				return
+			}
+			require.GreaterOrEqual(t, index, 0)
+			require.Equal(t, tt.wantCondition, reconcileState.Conditions[index].Status)
+		})
+	}
+}
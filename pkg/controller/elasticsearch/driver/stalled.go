@@ -0,0 +1,50 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// noExclusion is the allocation exclusion value set by the operator when no node is excluded.
+const noExclusion = "none_excluded"
+
+// remediateStalledReconciliation attempts the known remediations of a reconciliation reported as stalled:
+// the shard allocation exclusions left behind by the data migration of earlier versions are removed, since they are
+// never set by the operator once Elasticsearch supports the node shutdown API.
+func remediateStalledReconciliation(ctx context.Context, esClient esclient.Client, es esv1.Elasticsearch, reconcileState *reconcile.State) error {
+	index := es.Status.Conditions.Index(esv1.ReconciliationStalled)
+	if index < 0 || es.Status.Conditions[index].Status != corev1.ConditionTrue {
+		return nil
+	}
+	if !supportsNodeShutdown(esClient.Version()) {
+		// exclusions may be legitimately used to migrate the data of the nodes to remove
+		return nil
+	}
+	allocation, err := esClient.GetClusterRoutingAllocation(ctx)
+	if err != nil {
+		return err
+	}
+	excluded := allocation.Transient.Cluster.Routing.Allocation.Exclude.Name
+	if excluded == "" || excluded == noExclusion {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Removing shard allocation exclusions from stalled cluster", "namespace", es.Namespace, "es_name", es.Name, "excluded", excluded)
+	if err := esClient.ExcludeFromShardAllocation(ctx, noExclusion); err != nil {
+		return err
+	}
+	reconcileState.AddEvent(corev1.EventTypeNormal, events.EventReasonStalled,
+		fmt.Sprintf("Removed shard allocation exclusions left behind for nodes %s", excluded))
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+)
+
+func Test_remediateStalledReconciliation(t *testing.T) {
+	stalled := commonv1alpha1.Conditions{{Type: esv1.ReconciliationStalled, Status: corev1.ConditionTrue}}
+	notStalled := commonv1alpha1.Conditions{{Type: esv1.ReconciliationStalled, Status: corev1.ConditionFalse}}
+	excluded := func(nodes string) esclient.ClusterRoutingAllocation {
+		var allocation esclient.ClusterRoutingAllocation
+		allocation.Transient.Cluster.Routing.Allocation.Exclude.Name = nodes
+		return allocation
+	}
+	tests := []struct {
+		name        string
+		conditions  commonv1alpha1.Conditions
+		version     string
+		allocation  esclient.ClusterRoutingAllocation
+		wantCleared bool
+	}{
+		{
+			name:       "not stalled",
+			conditions: notStalled,
+			version:    "8.15.0",
+			allocation: excluded("es-es-default-2"),
+		},
+		{
+			name:       "exclusions used for data migration",
+			conditions: stalled,
+			version:    "7.10.0",
+			allocation: excluded("es-es-default-2"),
+		},
+		{
+			name:       "no exclusion",
+			conditions: stalled,
+			version:    "8.15.0",
+			allocation: excluded(noExclusion),
+		},
+		{
+			name:        "exclusions left behind",
+			conditions:  stalled,
+			version:     "8.15.0",
+			allocation:  excluded("es-es-default-2"),
+			wantCleared: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Conditions: tt.conditions}}
+			esClient := &fakeESClient{version: version.MustParse(tt.version), clusterRoutingAllocation: tt.allocation}
+			state := reconcile.MustNewState(es)
+			require.NoError(t, remediateStalledReconciliation(context.Background(), esClient, es, state))
+			assert.Equal(t, tt.wantCleared, esClient.ExcludeFromShardAllocationCalled)
+			if tt.wantCleared {
+				assert.Equal(t, noExclusion, esClient.ExcludeFromShardAllocationCalledWith)
+				assert.Len(t, state.Events(), 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume/validations"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// startVolumeMigrations records a new StatefulSet name for each nodeSet whose volume claim templates have been moved
+// to a new storage class, if the user confirmed the migration with the StorageClassMigrationAnnotation, or whose
+// storage requests have been increased in a storage class which does not allow volume expansion, if the user confirmed
+// the migration with the VolumeResizeMigrationAnnotation.
+// The new StatefulSet is then created alongside the existing one, which is removed once its data has been migrated
+// to the new Pods, as is done for a renamed nodeSet. It returns true if the Elasticsearch resource has been updated.
+func startVolumeMigrations(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	es *esv1.Elasticsearch,
+	actualStatefulSets es_sset.StatefulSetList,
+	expectedResources nodespec.ResourcesList,
+	validateStorageClass bool,
+	reconcileState *reconcile.State,
+) (bool, error) {
+	storageClassMigrations := es.StorageClassMigrationNodeSets()
+	volumeResizeMigrations := es.VolumeResizeMigrationNodeSets()
+	if len(storageClassMigrations) == 0 && len(volumeResizeMigrations) == 0 {
+		return false, nil
+	}
+	suffixes := es.StatefulSetNameSuffixes()
+	updated := false
+	for _, resources := range expectedResources {
+		expected := resources.StatefulSet
+		actual, exists := actualStatefulSets.GetByName(expected.Name)
+		if !exists {
+			continue
+		}
+		var reason string
+		switch {
+		case storageClassMigrations.Has(resources.NodeSet) &&
+			validations.StorageClassChanged(actual.Spec.VolumeClaimTemplates, expected.Spec.VolumeClaimTemplates):
+			reason = "a new storage class"
+		case volumeResizeMigrations.Has(resources.NodeSet):
+			err := validations.ValidateClaimsStorageUpdate(ctx, k8sClient, actual.Spec.VolumeClaimTemplates, expected.Spec.VolumeClaimTemplates, validateStorageClass)
+			if !validations.IsExpansionNotSupported(err) {
+				// the volumes are expanded in place, or the change is invalid and reported when the StatefulSet is updated
+				continue
+			}
+			reason = "larger volumes in a storage class which does not allow volume expansion"
+		default:
+			continue
+		}
+		suffix := esv1.NewStatefulSetNameSuffix(expected.Spec.VolumeClaimTemplates)
+		suffixes[resources.NodeSet] = suffix
+		ulog.FromContext(ctx).Info("Migrating nodeSet to new volumes",
+			"namespace", es.Namespace, "es_name", es.Name, "nodeset", resources.NodeSet, "reason", reason,
+			"from_statefulset", expected.Name, "to_statefulset", esv1.ESNamer.Suffix(es.Name, resources.NodeSet, suffix))
+		reconcileState.AddEvent(corev1.EventTypeNormal, events.EventReasonUpgraded,
+			fmt.Sprintf("Migrating nodeSet %s to %s", resources.NodeSet, reason))
+		updated = true
+	}
+	if !updated {
+		return false, nil
+	}
+	value, err := json.Marshal(suffixes)
+	if err != nil {
+		return false, err
+	}
+	if es.Annotations == nil {
+		es.Annotations = map[string]string{}
+	}
+	es.Annotations[esv1.StatefulSetNameSuffixesAnnotation] = string(value)
+	// update the resource immediately: the new StatefulSet names must be known to all the reconciliation steps
+	return true, k8sClient.Update(ctx, es)
+}
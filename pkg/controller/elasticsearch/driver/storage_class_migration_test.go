@@ -0,0 +1,161 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_startVolumeMigrations(t *testing.T) {
+	ssetWithStorageClass := func(name string, storageClass string) appsv1.StatefulSet {
+		return appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Spec: appsv1.StatefulSetSpec{VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: ptr.To(storageClass)},
+			}}},
+		}
+	}
+	newSuffix := esv1.NewStatefulSetNameSuffix(ssetWithStorageClass("", "new-sc").Spec.VolumeClaimTemplates)
+
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		actual       es_sset.StatefulSetList
+		expected     nodespec.ResourcesList
+		wantUpdated  bool
+		wantSsetName string
+	}{
+		{
+			name:         "no migration confirmed",
+			actual:       es_sset.StatefulSetList{ssetWithStorageClass("es-es-data", "old-sc")},
+			expected:     nodespec.ResourcesList{{NodeSet: "data", StatefulSet: ssetWithStorageClass("es-es-data", "new-sc")}},
+			wantSsetName: "es-es-data",
+		},
+		{
+			name:         "migration confirmed, same storage class",
+			annotations:  map[string]string{esv1.StorageClassMigrationAnnotation: "data"},
+			actual:       es_sset.StatefulSetList{ssetWithStorageClass("es-es-data", "old-sc")},
+			expected:     nodespec.ResourcesList{{NodeSet: "data", StatefulSet: ssetWithStorageClass("es-es-data", "old-sc")}},
+			wantSsetName: "es-es-data",
+		},
+		{
+			name:         "migration confirmed, StatefulSet does not exist yet",
+			annotations:  map[string]string{esv1.StorageClassMigrationAnnotation: "data"},
+			expected:     nodespec.ResourcesList{{NodeSet: "data", StatefulSet: ssetWithStorageClass("es-es-data", "new-sc")}},
+			wantSsetName: "es-es-data",
+		},
+		{
+			name:         "migration confirmed, new storage class",
+			annotations:  map[string]string{esv1.StorageClassMigrationAnnotation: "data"},
+			actual:       es_sset.StatefulSetList{ssetWithStorageClass("es-es-data", "old-sc")},
+			expected:     nodespec.ResourcesList{{NodeSet: "data", StatefulSet: ssetWithStorageClass("es-es-data", "new-sc")}},
+			wantUpdated:  true,
+			wantSsetName: "es-es-data-" + newSuffix,
+		},
+		{
+			name: "migration confirmed, nodeSet already migrated once",
+			annotations: map[string]string{
+				esv1.StorageClassMigrationAnnotation:   "data",
+				esv1.StatefulSetNameSuffixesAnnotation: `{"data":"123456"}`,
+			},
+			actual:       es_sset.StatefulSetList{ssetWithStorageClass("es-es-data-123456", "old-sc")},
+			expected:     nodespec.ResourcesList{{NodeSet: "data", StatefulSet: ssetWithStorageClass("es-es-data-123456", "new-sc")}},
+			wantUpdated:  true,
+			wantSsetName: "es-es-data-" + newSuffix,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: tt.annotations}}
+			k8sClient := k8s.NewFakeClient(&es)
+			updated, err := startVolumeMigrations(context.Background(), k8sClient, &es, tt.actual, tt.expected, true, reconcile.MustNewState(es))
+			require.NoError(t, err)
+			require.Equal(t, tt.wantUpdated, updated)
+
+			var actualES esv1.Elasticsearch
+			require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &actualES))
+			require.Equal(t, tt.wantSsetName, actualES.StatefulSetName("data"))
+		})
+	}
+}
+
+func Test_startVolumeMigrations_volumeResize(t *testing.T) {
+	ssetWithStorage := func(name string, storage string) appsv1.StatefulSet {
+		return appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Spec: appsv1.StatefulSetSpec{VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: ptr.To("standard"),
+					Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse(storage),
+					}},
+				},
+			}}},
+		}
+	}
+	newSuffix := esv1.NewStatefulSetNameSuffix(ssetWithStorage("", "2Gi").Spec.VolumeClaimTemplates)
+
+	tests := []struct {
+		name                 string
+		allowVolumeExpansion bool
+		annotations          map[string]string
+		wantUpdated          bool
+		wantSsetName         string
+	}{
+		{
+			name:         "no migration confirmed",
+			wantSsetName: "es-es-data",
+		},
+		{
+			name:                 "migration confirmed, storage class allows volume expansion",
+			allowVolumeExpansion: true,
+			annotations:          map[string]string{esv1.VolumeResizeMigrationAnnotation: "data"},
+			wantSsetName:         "es-es-data",
+		},
+		{
+			name:         "migration confirmed, storage class does not allow volume expansion",
+			annotations:  map[string]string{esv1.VolumeResizeMigrationAnnotation: "data"},
+			wantUpdated:  true,
+			wantSsetName: "es-es-data-" + newSuffix,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: tt.annotations}}
+			storageClass := storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: "standard"},
+				AllowVolumeExpansion: ptr.To(tt.allowVolumeExpansion),
+			}
+			k8sClient := k8s.NewFakeClient(&es, &storageClass)
+			actual := es_sset.StatefulSetList{ssetWithStorage("es-es-data", "1Gi")}
+			expected := nodespec.ResourcesList{{NodeSet: "data", StatefulSet: ssetWithStorage("es-es-data", "2Gi")}}
+
+			updated, err := startVolumeMigrations(context.Background(), k8sClient, &es, actual, expected, true, reconcile.MustNewState(es))
+			require.NoError(t, err)
+			require.Equal(t, tt.wantUpdated, updated)
+
+			var actualES esv1.Elasticsearch
+			require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &actualES))
+			require.Equal(t, tt.wantSsetName, actualES.StatefulSetName("data"))
+		})
+	}
+}
@@ -7,6 +7,7 @@ package driver
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -14,6 +15,7 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/maintenance"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
@@ -23,6 +25,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/shutdown"
 	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
@@ -69,12 +72,30 @@ func (d *defaultDriver) handleUpgrades(
 	}
 	logger := log.WithValues("namespace", d.ES.Namespace, "es_name", d.ES.Name)
 	nodeShutdown := shutdown.NewNodeShutdown(esClient, nodeNameToID, esclient.Restart, d.ES.ResourceVersion, logger)
+	// Nodes using ephemeral storage rejoin the cluster without their data and under a new node ID: there is no point in
+	// waiting for them before reallocating their shards.
+	nodeShutdown.WithAllocationDelay(0, k8s.PodNames(ephemeralStoragePods(podsToUpgrade))...)
 
 	// Maybe re-enable shards allocation and delete shutdowns if upgraded nodes are back into the cluster.
 	if results.WithResults(d.maybeCompleteNodeUpgrades(ctx, esClient, esState, nodeShutdown)).HasError() {
 		return results
 	}
 
+	// Pods are only restarted during the maintenance windows, if any.
+	if len(podsToUpgrade) > 0 {
+		now := time.Now()
+		open, next, err := maintenance.IsOpen(d.ES.Spec.MaintenanceWindows, now)
+		if err != nil {
+			return results.WithError(err)
+		}
+		if !open {
+			reason := maintenance.PendingReason(fmt.Sprintf("Restart of %d nodes", len(podsToUpgrade)), next)
+			log.V(1).Info(reason, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			return results.WithReconciliationState(
+				reconciler.RequeueAfter(maintenance.RequeueAfter(next, now, defaultRequeue.RequeueAfter)).WithReason(reason))
+		}
+	}
+
 	// Get the list of pods currently existing in the StatefulSetList
 	currentPods, err := statefulSets.GetActualPods(d.Client)
 	if err != nil {
@@ -276,6 +297,20 @@ func podsToUpgrade(
 	return toUpgrade, nil
 }
 
+// ephemeralStoragePods returns the Pods whose data volume is not backed by a PersistentVolumeClaim, and is therefore
+// lost when the Pod restarts.
+func ephemeralStoragePods(pods []corev1.Pod) []corev1.Pod {
+	var ephemeral []corev1.Pod
+	for _, pod := range pods {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.Name == esvolume.ElasticsearchDataVolumeName && volume.PersistentVolumeClaim == nil {
+				ephemeral = append(ephemeral, pod)
+			}
+		}
+	}
+	return ephemeral
+}
+
 func terminatingPodNames(client k8s.Client, statefulSets es_sset.StatefulSetList) ([]string, error) {
 	pods, err := statefulSets.GetActualPods(client)
 	if err != nil {
@@ -450,6 +485,10 @@ func (ctx *upgradeCtx) prepareClusterForNodeRestart(podsToUpgrade []corev1.Pod)
 	if supportsNodeShutdown(ctx.esClient.Version()) {
 		return ctx.requestNodeRestarts(podsToUpgrade)
 	}
+	if len(ephemeralStoragePods(podsToUpgrade)) == len(podsToUpgrade) {
+		// the restarted nodes lose their data, their shards must be allocated to other nodes in the meantime
+		return nil
+	}
 	// Disable shard allocations to avoid shards moving around while the node is temporarily down
 	shardsAllocationEnabled, err := ctx.esState.ShardAllocationsEnabled()
 	if err != nil {
@@ -0,0 +1,120 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// maxReportedDeprecations bounds the number of deprecation issues listed in the condition message and events.
+const maxReportedDeprecations = 5
+
+// checkUpgradeDeprecations retrieves the critical deprecation issues of the cluster before it is upgraded to the next
+// major version, and reports them in the UpgradePreflightChecksPassed condition. It returns true if the upgrade must
+// wait for the issues to be resolved. The checks only run until the first StatefulSet is updated to the new version,
+// the deprecation API being meaningless once the upgrade started.
+func checkUpgradeDeprecations(
+	ctx context.Context,
+	esClient esclient.Client,
+	es esv1.Elasticsearch,
+	esReachable bool,
+	actualStatefulSets es_sset.StatefulSetList,
+	reconcileState *reconcile.State,
+) (bool, error) {
+	mode := es.Spec.UpgradeDeprecationChecks
+	if mode == "" {
+		mode = esv1.UpgradeDeprecationChecksBlock
+	}
+	if mode == esv1.UpgradeDeprecationChecksDisabled {
+		return false, nil
+	}
+	upgrading, err := isMajorVersionUpgrade(ctx, es, actualStatefulSets)
+	if err != nil {
+		return false, err
+	}
+	if !upgrading {
+		// the upgrade may have been reverted while it was blocked
+		if idx := es.Status.Conditions.Index(esv1.UpgradePreflightChecksPassed); idx >= 0 && es.Status.Conditions[idx].Status == corev1.ConditionFalse {
+			reconcileState.ReportCondition(esv1.UpgradePreflightChecksPassed, corev1.ConditionTrue, "No major version upgrade pending")
+		}
+		return false, nil
+	}
+	block := mode == esv1.UpgradeDeprecationChecksBlock
+
+	if !esReachable {
+		reconcileState.ReportCondition(esv1.UpgradePreflightChecksPassed, corev1.ConditionUnknown, "Waiting for Elasticsearch to be reachable to check the deprecation issues")
+		return block, nil
+	}
+	deprecations, err := esClient.GetDeprecations(ctx)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to get the deprecation issues: %s", esclient.ErrorReason(err))
+		reconcileState.ReportCondition(esv1.UpgradePreflightChecksPassed, corev1.ConditionUnknown, msg)
+		if block {
+			return true, err
+		}
+		ulog.FromContext(ctx).Info(msg, "namespace", es.Namespace, "es_name", es.Name)
+		return false, nil
+	}
+
+	critical := deprecations.Critical()
+	if len(critical) == 0 {
+		reconcileState.ReportCondition(esv1.UpgradePreflightChecksPassed, corev1.ConditionTrue,
+			fmt.Sprintf("No critical deprecation issue prevents the upgrade to version %s", es.Spec.Version))
+		return false, nil
+	}
+	msg := fmt.Sprintf("%d critical deprecation issues must be resolved before the upgrade to version %s: %s",
+		len(critical), es.Spec.Version, summarizeDeprecations(critical))
+	reconcileState.ReportCondition(esv1.UpgradePreflightChecksPassed, corev1.ConditionFalse, msg)
+	if block {
+		reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonDelayed, "Upgrade blocked: "+msg)
+		return true, nil
+	}
+	reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUpgraded, "Upgrading despite "+msg)
+	return false, nil
+}
+
+// isMajorVersionUpgrade returns true if the spec version is a major version above the version running in the
+// cluster, and no StatefulSet has been updated to the spec major version yet.
+func isMajorVersionUpgrade(ctx context.Context, es esv1.Elasticsearch, actualStatefulSets es_sset.StatefulSetList) (bool, error) {
+	if es.Status.Version == "" {
+		// new cluster
+		return false, nil
+	}
+	specVersion, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return false, err
+	}
+	statusVersion, err := version.Parse(es.Status.Version)
+	if err != nil {
+		return false, err
+	}
+	if specVersion.Major <= statusVersion.Major {
+		return false, nil
+	}
+	started := actualStatefulSets.AtLeastOneESVersionMatch(ctx, func(v version.Version) bool {
+		return v.Major >= specVersion.Major
+	})
+	return !started, nil
+}
+
+// summarizeDeprecations joins the first deprecation issues of the given list.
+func summarizeDeprecations(messages []string) string {
+	if len(messages) <= maxReportedDeprecations {
+		return strings.Join(messages, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(messages[:maxReportedDeprecations], "; "), len(messages)-maxReportedDeprecations)
+}
@@ -0,0 +1,200 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+)
+
+type deprecationsESClient struct {
+	esclient.Client
+	deprecations esclient.Deprecations
+	err          error
+	called       bool
+}
+
+func (c *deprecationsESClient) GetDeprecations(_ context.Context) (esclient.Deprecations, error) {
+	c.called = true
+	return c.deprecations, c.err
+}
+
+func Test_checkUpgradeDeprecations(t *testing.T) {
+	critical := esclient.Deprecations{
+		IndexSettings: map[string][]esclient.Deprecation{
+			"logs-2019": {{Level: esclient.DeprecationLevelCritical, Message: "Index created before 7.0"}},
+		},
+	}
+	warningOnly := esclient.Deprecations{
+		ClusterSettings: []esclient.Deprecation{{Level: "warning", Message: "Setting is deprecated"}},
+		NodeSettings:    []esclient.Deprecation{{Level: esclient.DeprecationLevelCritical, Message: "Resolved by the upgrade", ResolveDuringRollingUpgrade: true}},
+	}
+	statefulSets := func(v string) es_sset.StatefulSetList {
+		return es_sset.StatefulSetList{sset.TestSset{Namespace: "ns", Name: "default", ClusterName: "es", Version: v, Replicas: 3}.Build()}
+	}
+	tests := []struct {
+		name          string
+		mode          esv1.UpgradeDeprecationChecksMode
+		specVersion   string
+		statusVersion string
+		statefulSets  es_sset.StatefulSetList
+		conditions    commonv1alpha1.Conditions
+		deprecations  esclient.Deprecations
+		apiErr        error
+		esReachable   bool
+		wantCalled    bool
+		wantWait      bool
+		wantErr       bool
+		wantCondition corev1.ConditionStatus
+		wantEvents    int
+	}{
+		{
+			name:        "new cluster",
+			specVersion: "8.15.0",
+			esReachable: true,
+		},
+		{
+			name:          "minor version upgrade",
+			specVersion:   "8.15.0",
+			statusVersion: "8.14.0",
+			statefulSets:  statefulSets("8.14.0"),
+			deprecations:  critical,
+			esReachable:   true,
+		},
+		{
+			name:          "major version upgrade without critical issue",
+			specVersion:   "8.15.0",
+			statusVersion: "7.17.0",
+			statefulSets:  statefulSets("7.17.0"),
+			deprecations:  warningOnly,
+			esReachable:   true,
+			wantCalled:    true,
+			wantCondition: corev1.ConditionTrue,
+		},
+		{
+			name:          "major version upgrade blocked",
+			specVersion:   "8.15.0",
+			statusVersion: "7.17.0",
+			statefulSets:  statefulSets("7.17.0"),
+			deprecations:  critical,
+			esReachable:   true,
+			wantCalled:    true,
+			wantWait:      true,
+			wantCondition: corev1.ConditionFalse,
+			wantEvents:    1,
+		},
+		{
+			name:          "major version upgrade with warnings only",
+			mode:          esv1.UpgradeDeprecationChecksWarn,
+			specVersion:   "8.15.0",
+			statusVersion: "7.17.0",
+			statefulSets:  statefulSets("7.17.0"),
+			deprecations:  critical,
+			esReachable:   true,
+			wantCalled:    true,
+			wantCondition: corev1.ConditionFalse,
+			wantEvents:    1,
+		},
+		{
+			name:          "checks disabled",
+			mode:          esv1.UpgradeDeprecationChecksDisabled,
+			specVersion:   "8.15.0",
+			statusVersion: "7.17.0",
+			statefulSets:  statefulSets("7.17.0"),
+			deprecations:  critical,
+			esReachable:   true,
+		},
+		{
+			name:          "upgrade already started",
+			specVersion:   "8.15.0",
+			statusVersion: "7.17.0",
+			statefulSets:  statefulSets("8.15.0"),
+			deprecations:  critical,
+			esReachable:   true,
+		},
+		{
+			name:          "Elasticsearch not reachable",
+			specVersion:   "8.15.0",
+			statusVersion: "7.17.0",
+			statefulSets:  statefulSets("7.17.0"),
+			wantWait:      true,
+			wantCondition: corev1.ConditionUnknown,
+		},
+		{
+			name:          "deprecation API failure",
+			specVersion:   "8.15.0",
+			statusVersion: "7.17.0",
+			statefulSets:  statefulSets("7.17.0"),
+			apiErr:        errors.New("boom"),
+			esReachable:   true,
+			wantCalled:    true,
+			wantWait:      true,
+			wantErr:       true,
+			wantCondition: corev1.ConditionUnknown,
+		},
+		{
+			name:          "deprecation API failure with warnings only",
+			mode:          esv1.UpgradeDeprecationChecksWarn,
+			specVersion:   "8.15.0",
+			statusVersion: "7.17.0",
+			statefulSets:  statefulSets("7.17.0"),
+			apiErr:        errors.New("boom"),
+			esReachable:   true,
+			wantCalled:    true,
+			wantCondition: corev1.ConditionUnknown,
+		},
+		{
+			name:          "blocked upgrade reverted",
+			specVersion:   "7.17.0",
+			statusVersion: "7.17.0",
+			statefulSets:  statefulSets("7.17.0"),
+			conditions:    commonv1alpha1.Conditions{{Type: esv1.UpgradePreflightChecksPassed, Status: corev1.ConditionFalse}},
+			esReachable:   true,
+			wantCondition: corev1.ConditionTrue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+				Spec:       esv1.ElasticsearchSpec{Version: tt.specVersion, UpgradeDeprecationChecks: tt.mode},
+				Status:     esv1.ElasticsearchStatus{Version: tt.statusVersion, Conditions: tt.conditions},
+			}
+			esClient := &deprecationsESClient{deprecations: tt.deprecations, err: tt.apiErr}
+			reconcileState := reconcile.MustNewState(es)
+
+			wait, err := checkUpgradeDeprecations(context.Background(), esClient, es, tt.esReachable, tt.statefulSets, reconcileState)
+
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantWait, wait)
+			require.Equal(t, tt.wantCalled, esClient.called)
+			require.Len(t, reconcileState.Events(), tt.wantEvents)
+			index := reconcileState.Conditions.Index(esv1.UpgradePreflightChecksPassed)
+			if tt.wantCondition == "" {
+				require.Equal(t, -1, index)
+				return
+			}
+			require.GreaterOrEqual(t, index, 0)
+			require.Equal(t, tt.wantCondition, reconcileState.Conditions[index].Status)
+		})
+	}
+}
+
+func Test_summarizeDeprecations(t *testing.T) {
+	require.Equal(t, "a; b", summarizeDeprecations([]string{"a", "b"}))
+	require.Equal(t, "a; b; c; d; e; and 2 more", summarizeDeprecations([]string{"a", "b", "c", "d", "e", "f", "g"}))
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/shutdown"
 	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -316,6 +317,21 @@ func Test_isNonHACluster(t *testing.T) {
 	}
 }
 
+func Test_ephemeralStoragePods(t *testing.T) {
+	podWithDataVolume := func(name string, source corev1.VolumeSource) corev1.Pod {
+		pod := sset.TestPod{Name: name}.Build()
+		pod.Spec.Volumes = []corev1.Volume{{Name: esvolume.ElasticsearchDataVolumeName, VolumeSource: source}}
+		return pod
+	}
+	pods := []corev1.Pod{
+		podWithDataVolume("persistent", corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}),
+		podWithDataVolume("empty-dir", corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}),
+		podWithDataVolume("csi", corev1.VolumeSource{CSI: &corev1.CSIVolumeSource{Driver: "local.csi.example.com"}}),
+		sset.TestPod{Name: "no-data-volume"}.Build(),
+	}
+	assert.Equal(t, []string{"empty-dir", "csi"}, k8s.PodNames(ephemeralStoragePods(pods)))
+}
+
 func Test_isVersionUpgrade(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,195 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// preUpgradeSnapshotVersionLabelName is set on the VolumeSnapshots taken before an upgrade, to the target version.
+	preUpgradeSnapshotVersionLabelName = "elasticsearch.k8s.elastic.co/pre-upgrade-version"
+	// preUpgradeSnapshotFromVersionAnnotationName holds the version running when the VolumeSnapshot was taken.
+	preUpgradeSnapshotFromVersionAnnotationName = "elasticsearch.k8s.elastic.co/pre-upgrade-from-version"
+)
+
+var volumeSnapshotGVK = schema.GroupVersionKind{Group: volumeSnapshotAPIGroup, Version: "v1", Kind: "VolumeSnapshot"}
+
+// reconcilePreUpgradeVolumeSnapshots takes VolumeSnapshots of the existing volume claims of the selected nodeSets
+// before a version upgrade starts, records them in the status, and deletes the snapshots of the upgrades beyond the
+// retained ones. It returns true while the upgrade must wait for the snapshots to be taken.
+// The snapshots are not owned by the Elasticsearch resource: they outlive the cluster on purpose.
+func reconcilePreUpgradeVolumeSnapshots(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	es esv1.Elasticsearch,
+	expectedResources nodespec.ResourcesList,
+	reconcileState *reconcile.State,
+) (bool, error) {
+	spec := es.Spec.PreUpgradeVolumeSnapshots
+	if spec == nil {
+		return false, nil
+	}
+	upgrades := slices.Clone(es.Status.PreUpgradeVolumeSnapshots)
+
+	upgrading := false
+	if es.Status.Version != "" {
+		var err error
+		if upgrading, err = isVersionUpgrade(es); err != nil {
+			return false, err
+		}
+	}
+	alreadyTaken := slices.ContainsFunc(upgrades, func(upgrade esv1.UpgradeVolumeSnapshots) bool {
+		return upgrade.ToVersion == es.Spec.Version
+	})
+	if upgrading && !alreadyTaken {
+		taken, names, err := snapshotVolumesBeforeUpgrade(ctx, k8sClient, es, expectedResources, reconcileState)
+		if err != nil || !taken {
+			return !taken, err
+		}
+		reconcileState.AddEvent(corev1.EventTypeNormal, events.EventReasonUpgraded,
+			fmt.Sprintf("Took %d VolumeSnapshots before the upgrade from version %s to %s", len(names), es.Status.Version, es.Spec.Version))
+		upgrades = append(upgrades, esv1.UpgradeVolumeSnapshots{
+			FromVersion:     es.Status.Version,
+			ToVersion:       es.Spec.Version,
+			VolumeSnapshots: names,
+		})
+	}
+
+	if spec.RetainedUpgrades != nil {
+		for len(upgrades) > int(*spec.RetainedUpgrades) {
+			if err := deleteVolumeSnapshots(ctx, k8sClient, es.Namespace, upgrades[0].VolumeSnapshots); err != nil {
+				return false, err
+			}
+			upgrades = upgrades[1:]
+		}
+	}
+	reconcileState.UpdatePreUpgradeVolumeSnapshots(upgrades)
+	return false, nil
+}
+
+// snapshotVolumesBeforeUpgrade creates the missing VolumeSnapshots of the upgrade to the spec version. It returns true
+// with the names of the snapshots once they have all been taken, which happens before they are ready to be used.
+func snapshotVolumesBeforeUpgrade(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	es esv1.Elasticsearch,
+	expectedResources nodespec.ResourcesList,
+	reconcileState *reconcile.State,
+) (bool, []string, error) {
+	spec := es.Spec.PreUpgradeVolumeSnapshots
+	taken := true
+	var names []string
+	for _, resources := range expectedResources {
+		if len(spec.NodeSets) > 0 && !slices.Contains(spec.NodeSets, resources.NodeSet) {
+			continue
+		}
+		for _, podName := range sset.PodNames(resources.StatefulSet) {
+			for _, claimTemplate := range resources.StatefulSet.Spec.VolumeClaimTemplates {
+				claimName := fmt.Sprintf("%s-%s", claimTemplate.Name, podName)
+				var claim corev1.PersistentVolumeClaim
+				if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: claimName}, &claim); err != nil {
+					if apierrors.IsNotFound(err) {
+						// nothing to snapshot for a node that does not exist yet
+						continue
+					}
+					return false, nil, err
+				}
+
+				snapshot, err := getOrCreatePreUpgradeVolumeSnapshot(ctx, k8sClient, es, claimName)
+				if err != nil {
+					if meta.IsNoMatchError(err) {
+						reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected,
+							"VolumeSnapshot API not available: the upgrade cannot proceed until spec.preUpgradeVolumeSnapshots is removed")
+						return false, nil, nil
+					}
+					return false, nil, err
+				}
+				names = append(names, snapshot.GetName())
+
+				if message, exists, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); exists {
+					reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected,
+						fmt.Sprintf("Failed to take VolumeSnapshot %s: %s", snapshot.GetName(), message))
+				}
+				if _, exists, _ := unstructured.NestedString(snapshot.Object, "status", "creationTime"); !exists {
+					taken = false
+				}
+			}
+		}
+	}
+	if !taken {
+		ulog.FromContext(ctx).Info("Waiting for VolumeSnapshots to be taken before upgrading",
+			"namespace", es.Namespace, "es_name", es.Name, "version", es.Spec.Version)
+	}
+	return taken, names, nil
+}
+
+// getOrCreatePreUpgradeVolumeSnapshot returns the VolumeSnapshot of the given claim for the upgrade to the spec
+// version, creating it if it does not exist yet.
+func getOrCreatePreUpgradeVolumeSnapshot(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, claimName string) (*unstructured.Unstructured, error) {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	name := fmt.Sprintf("%s-pre-upgrade-%s", claimName, strings.ToLower(es.Spec.Version))
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: name}, snapshot)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return snapshot, err
+	}
+
+	snapshot = &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"source": map[string]interface{}{"persistentVolumeClaimName": claimName},
+		},
+	}}
+	if className := es.Spec.PreUpgradeVolumeSnapshots.VolumeSnapshotClassName; className != nil {
+		if err := unstructured.SetNestedField(snapshot.Object, *className, "spec", "volumeSnapshotClassName"); err != nil {
+			return nil, err
+		}
+	}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetNamespace(es.Namespace)
+	snapshot.SetName(name)
+	labels := label.NewLabels(k8s.ExtractNamespacedName(&es))
+	labels[preUpgradeSnapshotVersionLabelName] = es.Spec.Version
+	snapshot.SetLabels(labels)
+	snapshot.SetAnnotations(map[string]string{preUpgradeSnapshotFromVersionAnnotationName: es.Status.Version})
+
+	ulog.FromContext(ctx).Info("Creating VolumeSnapshot before upgrade",
+		"namespace", es.Namespace, "es_name", es.Name, "pvc_name", claimName, "volume_snapshot", name)
+	return snapshot, k8sClient.Create(ctx, snapshot)
+}
+
+// deleteVolumeSnapshots deletes the given VolumeSnapshots, ignoring the ones that do not exist anymore.
+func deleteVolumeSnapshots(ctx context.Context, k8sClient k8s.Client, namespace string, names []string) error {
+	for _, name := range names {
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+		snapshot.SetNamespace(namespace)
+		snapshot.SetName(name)
+		ulog.FromContext(ctx).Info("Deleting pre-upgrade VolumeSnapshot", "namespace", namespace, "volume_snapshot", name)
+		if err := k8sClient.Delete(ctx, snapshot); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_reconcilePreUpgradeVolumeSnapshots(t *testing.T) {
+	ctx := context.Background()
+	nodeSetResources := func(nodeSet string, replicas int32) nodespec.Resources {
+		statefulSet := sset.TestSset{Namespace: "ns", Name: "es-es-" + nodeSet, ClusterName: "es", Replicas: replicas}.Build()
+		statefulSet.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"}}}
+		return nodespec.Resources{NodeSet: nodeSet, StatefulSet: statefulSet}
+	}
+	// the third master node does not exist yet
+	expectedResources := nodespec.ResourcesList{nodeSetResources("master", 3), nodeSetResources("data", 1)}
+	claim := func(name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}}
+	}
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			Version: "8.15.0",
+			PreUpgradeVolumeSnapshots: &esv1.PreUpgradeVolumeSnapshots{
+				VolumeSnapshotClassName: ptr.To("csi-snapshots"),
+				NodeSets:                []string{"master"},
+				RetainedUpgrades:        ptr.To[int32](1),
+			},
+		},
+		Status: esv1.ElasticsearchStatus{
+			Version: "8.14.0",
+			PreUpgradeVolumeSnapshots: []esv1.UpgradeVolumeSnapshots{
+				{FromVersion: "8.13.0", ToVersion: "8.14.0", VolumeSnapshots: []string{"previous-upgrade"}},
+			},
+		},
+	}
+	k8sClient := k8s.NewFakeClient(
+		claim("elasticsearch-data-es-es-master-0"),
+		claim("elasticsearch-data-es-es-master-1"),
+		claim("elasticsearch-data-es-es-data-0"),
+		volumeSnapshot("previous-upgrade", "elasticsearch-data-es-es-master-0", true, "2024-01-01T00:00:00Z", "1Gi"),
+	)
+	getSnapshot := func(name string) (*unstructured.Unstructured, error) {
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+		return snapshot, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: name}, snapshot)
+	}
+	wantSnapshots := []string{
+		"elasticsearch-data-es-es-master-0-pre-upgrade-8.15.0",
+		"elasticsearch-data-es-es-master-1-pre-upgrade-8.15.0",
+	}
+
+	// the snapshots are created, the upgrade waits for them to be taken
+	reconcileState := reconcile.MustNewState(es)
+	waiting, err := reconcilePreUpgradeVolumeSnapshots(ctx, k8sClient, es, expectedResources, reconcileState)
+	require.NoError(t, err)
+	require.True(t, waiting)
+	for _, name := range wantSnapshots {
+		snapshot, err := getSnapshot(name)
+		require.NoError(t, err)
+		require.Equal(t, "8.15.0", snapshot.GetLabels()[preUpgradeSnapshotVersionLabelName])
+		require.Equal(t, "es", snapshot.GetLabels()[label.ClusterNameLabelName])
+		require.Equal(t, "8.14.0", snapshot.GetAnnotations()[preUpgradeSnapshotFromVersionAnnotationName])
+		className, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+		require.Equal(t, "csi-snapshots", className)
+		require.Empty(t, snapshot.GetOwnerReferences())
+
+		// the CSI snapshotter takes the snapshot
+		require.NoError(t, unstructured.SetNestedField(snapshot.Object, "2024-02-01T00:00:00Z", "status", "creationTime"))
+		require.NoError(t, k8sClient.Status().Update(ctx, snapshot))
+	}
+	_, err = getSnapshot("elasticsearch-data-es-es-data-0-pre-upgrade-8.15.0")
+	require.True(t, apierrors.IsNotFound(err))
+	_, err = getSnapshot("previous-upgrade")
+	require.NoError(t, err)
+
+	// once taken, the snapshots are recorded in the status and the snapshots of the previous upgrade are deleted
+	reconcileState = reconcile.MustNewState(es)
+	waiting, err = reconcilePreUpgradeVolumeSnapshots(ctx, k8sClient, es, expectedResources, reconcileState)
+	require.NoError(t, err)
+	require.False(t, waiting)
+	require.Len(t, reconcileState.Events(), 1)
+	_, err = getSnapshot("previous-upgrade")
+	require.True(t, apierrors.IsNotFound(err))
+	_, updated := reconcileState.Apply()
+	require.NotNil(t, updated)
+	es.Status = updated.Status
+	require.Equal(t, []esv1.UpgradeVolumeSnapshots{
+		{FromVersion: "8.14.0", ToVersion: "8.15.0", VolumeSnapshots: wantSnapshots},
+	}, es.Status.PreUpgradeVolumeSnapshots)
+
+	// no new snapshot is taken while the upgrade is in progress
+	reconcileState = reconcile.MustNewState(es)
+	waiting, err = reconcilePreUpgradeVolumeSnapshots(ctx, k8sClient, es, expectedResources, reconcileState)
+	require.NoError(t, err)
+	require.False(t, waiting)
+	require.Empty(t, reconcileState.Events())
+}
+
+func Test_reconcilePreUpgradeVolumeSnapshots_NoUpgrade(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{Version: "8.15.0", PreUpgradeVolumeSnapshots: &esv1.PreUpgradeVolumeSnapshots{}},
+	}
+	statefulSet := sset.TestSset{Namespace: "ns", Name: "es-es-default", ClusterName: "es", Replicas: 1}.Build()
+	statefulSet.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"}}}
+	expectedResources := nodespec.ResourcesList{{NodeSet: "default", StatefulSet: statefulSet}}
+	k8sClient := k8s.NewFakeClient(&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "elasticsearch-data-es-es-default-0"}})
+
+	for _, statusVersion := range []string{"", "8.15.0"} {
+		es.Status.Version = statusVersion
+		waiting, err := reconcilePreUpgradeVolumeSnapshots(context.Background(), k8sClient, es, expectedResources, reconcile.MustNewState(es))
+		require.NoError(t, err)
+		require.False(t, waiting)
+
+		snapshots := unstructured.UnstructuredList{}
+		snapshots.SetGroupVersionKind(volumeSnapshotListGVK)
+		require.NoError(t, k8sClient.List(context.Background(), &snapshots))
+		require.Empty(t, snapshots.Items)
+	}
+}
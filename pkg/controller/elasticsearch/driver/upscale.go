@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 
+	"golang.org/x/sync/errgroup"
 	appsv1 "k8s.io/api/apps/v1"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -64,37 +65,81 @@ func HandleUpscaleAndSpecChanges(
 	if err != nil {
 		return results, fmt.Errorf("adjust resources: %w", err)
 	}
-	// reconcile all resources
-	for _, res := range adjusted {
-		res := res
-		if err := settings.ReconcileConfig(ctx.parentCtx, ctx.k8sClient, ctx.es, res.StatefulSet.Name, res.Config); err != nil {
-			return results, fmt.Errorf("reconcile config: %w", err)
-		}
-		if _, err := common.ReconcileService(ctx.parentCtx, ctx.k8sClient, &res.HeadlessService, &ctx.es); err != nil {
-			return results, fmt.Errorf("reconcile service: %w", err)
-		}
-		if actualSset, exists := actualStatefulSets.GetByName(res.StatefulSet.Name); exists {
-			recreateSset, err := handleVolumeExpansion(ctx.parentCtx, ctx.k8sClient, ctx.es, res.StatefulSet, actualSset, ctx.validateStorageClass)
-			if err != nil {
-				return results, fmt.Errorf("handle volume expansion: %w", err)
-			}
-			if recreateSset {
-				// The StatefulSet is scheduled for recreation: let's requeue before attempting any further spec change.
-				results.Requeue = true
-				continue
-			}
-		}
-		reconciled, err := es_sset.ReconcileStatefulSet(ctx.parentCtx, ctx.k8sClient, ctx.es, res.StatefulSet, ctx.expectations)
-		if err != nil {
-			return results, fmt.Errorf("reconcile StatefulSet: %w", err)
+	// handle volume expansion one nodeSet at a time, as it may annotate and update the Elasticsearch resource
+	recreations, err := handleVolumeExpansions(ctx, actualStatefulSets, adjusted)
+	if err != nil {
+		return results, err
+	}
+	// reconcile the resources of each nodeSet in parallel, they do not depend on each other
+	reconciled := make([]*appsv1.StatefulSet, len(adjusted))
+	group := errgroup.Group{}
+	group.SetLimit(es_sset.MaxConcurrentReconciles)
+	for i, res := range adjusted {
+		group.Go(func() error {
+			var err error
+			reconciled[i], err = reconcileNodeSetResources(ctx, res, recreations[i])
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return results, err
+	}
+	for _, statefulSet := range reconciled {
+		if statefulSet == nil {
+			// The StatefulSet is scheduled for recreation: let's requeue before attempting any further spec change.
+			results.Requeue = true
+			continue
 		}
 		// update actual with the reconciled ones for next steps to work with up-to-date information
-		actualStatefulSets = actualStatefulSets.WithStatefulSet(reconciled)
+		actualStatefulSets = actualStatefulSets.WithStatefulSet(*statefulSet)
 	}
 	results.ActualStatefulSets = actualStatefulSets
 	return results, nil
 }
 
+// handleVolumeExpansions resizes the volumes of the existing StatefulSets of the given resources, whose expected
+// StatefulSets may be updated in place. It returns, for each resource, whether its StatefulSet is scheduled for recreation.
+// StatefulSets scheduled for recreation are annotated on a single copy of the Elasticsearch resource, updated in turn.
+func handleVolumeExpansions(
+	ctx upscaleCtx,
+	actualStatefulSets es_sset.StatefulSetList,
+	resources nodespec.ResourcesList,
+) ([]bool, error) {
+	es := ctx.es.DeepCopy()
+	recreations := make([]bool, len(resources))
+	for i := range resources {
+		actualSset, exists := actualStatefulSets.GetByName(resources[i].StatefulSet.Name)
+		if !exists {
+			continue
+		}
+		recreateSset, err := handleVolumeExpansion(ctx.parentCtx, ctx.k8sClient, es, &resources[i].StatefulSet, actualSset, ctx.validateStorageClass)
+		if err != nil {
+			return nil, fmt.Errorf("handle volume expansion: %w", err)
+		}
+		recreations[i] = recreateSset
+	}
+	return recreations, nil
+}
+
+// reconcileNodeSetResources reconciles the config, the headless service and the StatefulSet of a single nodeSet.
+// It returns the reconciled StatefulSet, or nil if the StatefulSet is scheduled for recreation following a volume expansion.
+func reconcileNodeSetResources(ctx upscaleCtx, res nodespec.Resources, recreateSset bool) (*appsv1.StatefulSet, error) {
+	if err := settings.ReconcileConfig(ctx.parentCtx, ctx.k8sClient, ctx.es, res.StatefulSet.Name, res.Config); err != nil {
+		return nil, fmt.Errorf("reconcile config: %w", err)
+	}
+	if _, err := common.ReconcileService(ctx.parentCtx, ctx.k8sClient, &res.HeadlessService, &ctx.es); err != nil {
+		return nil, fmt.Errorf("reconcile service: %w", err)
+	}
+	if recreateSset {
+		return nil, nil
+	}
+	reconciled, err := es_sset.ReconcileStatefulSet(ctx.parentCtx, ctx.k8sClient, ctx.es, res.StatefulSet, ctx.expectations)
+	if err != nil {
+		return nil, fmt.Errorf("reconcile StatefulSet: %w", err)
+	}
+	return &reconciled, nil
+}
+
 func podsToCreate(
 	actualStatefulSets, expectedStatefulSets es_sset.StatefulSetList,
 ) []string {
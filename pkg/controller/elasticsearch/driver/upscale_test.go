@@ -6,6 +6,7 @@ package driver
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"sort"
 	"sync"
@@ -223,6 +224,65 @@ func TestHandleUpscaleAndSpecChanges(t *testing.T) {
 	comparison.RequireEqual(t, &res.ActualStatefulSets[1], &sset2)
 }
 
+func TestHandleUpscaleAndSpecChanges_ManyNodeSets(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{Version: "7.5.0"},
+	}
+	k8sClient := k8s.NewFakeClient(&es)
+	ctx := upscaleCtx{
+		k8sClient:    k8sClient,
+		es:           es,
+		expectations: expectations.NewExpectations(k8sClient),
+		parentCtx:    context.Background(),
+	}
+	// more nodeSets than the number of nodeSets reconciled in parallel
+	nodeSetCount := 3 * es_sset.MaxConcurrentReconciles
+	expectedResources := make(nodespec.ResourcesList, 0, nodeSetCount)
+	for i := 0; i < nodeSetCount; i++ {
+		name := fmt.Sprintf("sset%d", i)
+		statefulSet := appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](2)},
+		}
+		if i == 0 {
+			statefulSet.Spec.Template.Labels = map[string]string{string(label.NodeTypesMasterLabelName): "true"}
+		}
+		statefulSet.Labels = hash.SetTemplateHashLabel(statefulSet.Labels, statefulSet.Spec)
+		expectedResources = append(expectedResources, nodespec.Resources{
+			StatefulSet:     statefulSet,
+			HeadlessService: corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}},
+			Config:          settings.CanonicalConfig{},
+		})
+	}
+
+	res, err := HandleUpscaleAndSpecChanges(ctx, es_sset.StatefulSetList{}, expectedResources)
+	require.NoError(t, err)
+	require.False(t, res.Requeue)
+	// all StatefulSets are created, and returned in the order of the expected resources
+	require.Len(t, res.ActualStatefulSets, nodeSetCount)
+	for i, expected := range expectedResources {
+		require.Equal(t, expected.StatefulSet.Name, res.ActualStatefulSets[i].Name)
+		var actual appsv1.StatefulSet
+		require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&expected.StatefulSet), &actual))
+		require.Equal(t, ptr.To[int32](2), actual.Spec.Replicas)
+		require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: esv1.ConfigSecret(expected.StatefulSet.Name)}, &corev1.Secret{}))
+	}
+
+	// upscale all data nodeSets at once: an expectation is registered for each of them
+	for i := 1; i < nodeSetCount; i++ {
+		expectedResources[i].StatefulSet.Spec.Replicas = ptr.To[int32](3)
+	}
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &ctx.es))
+	res, err = HandleUpscaleAndSpecChanges(ctx, res.ActualStatefulSets, expectedResources)
+	require.NoError(t, err)
+	require.Len(t, res.ActualStatefulSets, nodeSetCount)
+	require.Len(t, ctx.expectations.GetGenerations(), nodeSetCount-1)
+	for i := 1; i < nodeSetCount; i++ {
+		require.Equal(t, ptr.To[int32](3), res.ActualStatefulSets[i].Spec.Replicas)
+	}
+}
+
 func TestHandleUpscaleAndSpecChanges_PVCResize(t *testing.T) {
 	// focus on the special case of handling PVC resize
 	es := esv1.Elasticsearch{
@@ -325,6 +385,76 @@ func TestHandleUpscaleAndSpecChanges_PVCResize(t *testing.T) {
 	require.Len(t, es.Annotations, 2) // initial master nodes + sset to recreate
 }
 
+func TestHandleUpscaleAndSpecChanges_PVCResizeManyNodeSets(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: map[string]string{
+			"elasticsearch.k8s.elastic.co/initial-master-nodes": "master-0",
+		}},
+		Spec: esv1.ElasticsearchSpec{Version: "7.5.0"},
+	}
+	storageClass := storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "resizeable"},
+		AllowVolumeExpansion: ptr.To(true),
+	}
+	newStatefulSet := func(name string, storage string) appsv1.StatefulSet {
+		return appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: ptr.To[int32](1),
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+						Spec: corev1.PersistentVolumeClaimSpec{
+							Resources: corev1.VolumeResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+							},
+							StorageClassName: &storageClass.Name,
+						},
+					},
+				},
+			},
+		}
+	}
+	// resize the volumes of more nodeSets than the number of nodeSets reconciled in parallel
+	nodeSetCount := 2 * es_sset.MaxConcurrentReconciles
+	actualStatefulSets := make(es_sset.StatefulSetList, 0, nodeSetCount)
+	expectedResources := make(nodespec.ResourcesList, 0, nodeSetCount)
+	objects := []client.Object{&es, &storageClass}
+	for i := 0; i < nodeSetCount; i++ {
+		name := fmt.Sprintf("data%d", i)
+		actual := newStatefulSet(name, "1Gi")
+		actualStatefulSets = append(actualStatefulSets, actual)
+		objects = append(objects, &actual)
+		expectedResources = append(expectedResources, nodespec.Resources{
+			StatefulSet:     newStatefulSet(name, "3Gi"),
+			HeadlessService: corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}},
+			Config:          settings.CanonicalConfig{},
+		})
+	}
+	k8sClient := k8s.NewFakeClient(objects...)
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &es))
+	ctx := upscaleCtx{
+		k8sClient:    k8sClient,
+		es:           es,
+		expectations: expectations.NewExpectations(k8sClient),
+		parentCtx:    context.Background(),
+	}
+
+	// all StatefulSets are scheduled for recreation in the same Elasticsearch resource
+	res, err := HandleUpscaleAndSpecChanges(ctx, actualStatefulSets, expectedResources)
+	require.NoError(t, err)
+	require.True(t, res.Requeue)
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &es))
+	require.Len(t, es.Annotations, nodeSetCount+1)
+	// the Elasticsearch resource of the context is left untouched
+	require.Len(t, ctx.es.Annotations, 1)
+	// the config and the headless service of every nodeSet are reconciled nonetheless
+	for _, expected := range expectedResources {
+		require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: esv1.ConfigSecret(expected.StatefulSet.Name)}, &corev1.Secret{}))
+		require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&expected.HeadlessService), &corev1.Service{}))
+	}
+}
+
 func Test_adjustStatefulSetReplicas(t *testing.T) {
 	type args struct {
 		state              *upscaleState
@@ -0,0 +1,159 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const volumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
+
+var volumeSnapshotListGVK = schema.GroupVersionKind{Group: volumeSnapshotAPIGroup, Version: "v1", Kind: "VolumeSnapshotList"}
+
+// prepopulateVolumesFromSnapshots creates the missing volume claims of the nodeSets listed in the
+// VolumeSnapshotPrepopulationAnnotation from the most recent ready VolumeSnapshot of the same claim, before the
+// StatefulSet controller creates them empty. Pods replacing a former node then start with most of its data, and
+// Elasticsearch only has to recover the changes made since the snapshot was taken.
+// Only snapshots of the same claim are considered: the data path of another node holds the identity of that node.
+func prepopulateVolumesFromSnapshots(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	es esv1.Elasticsearch,
+	expectedResources nodespec.ResourcesList,
+	reconcileState *reconcile.State,
+) error {
+	nodeSets := es.VolumeSnapshotPrepopulationNodeSets()
+	if len(nodeSets) == 0 {
+		return nil
+	}
+	snapshots := unstructured.UnstructuredList{}
+	snapshots.SetGroupVersionKind(volumeSnapshotListGVK)
+	if err := k8sClient.List(ctx, &snapshots, client.InNamespace(es.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			// the VolumeSnapshot API is not available in this Kubernetes cluster
+			ulog.FromContext(ctx).V(1).Info("VolumeSnapshot API not available, skipping volume prepopulation",
+				"namespace", es.Namespace, "es_name", es.Name)
+			return nil
+		}
+		return err
+	}
+	if len(snapshots.Items) == 0 {
+		return nil
+	}
+
+	for _, resources := range expectedResources {
+		if !nodeSets.Has(resources.NodeSet) {
+			continue
+		}
+		statefulSet := resources.StatefulSet
+		for _, podName := range sset.PodNames(statefulSet) {
+			for _, claimTemplate := range statefulSet.Spec.VolumeClaimTemplates {
+				claimName := fmt.Sprintf("%s-%s", claimTemplate.Name, podName)
+				var existing corev1.PersistentVolumeClaim
+				err := k8sClient.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: claimName}, &existing)
+				if err == nil {
+					continue
+				}
+				if !apierrors.IsNotFound(err) {
+					return err
+				}
+				snapshotName := latestReadySnapshot(snapshots.Items, claimName, claimTemplate.Spec.Resources.Requests[corev1.ResourceStorage])
+				if snapshotName == "" {
+					continue
+				}
+				claim := claimFromSnapshot(claimTemplate, statefulSet.Spec.Selector, es.Namespace, claimName, snapshotName)
+				ulog.FromContext(ctx).Info("Creating volume claim from VolumeSnapshot",
+					"namespace", es.Namespace, "es_name", es.Name, "pvc_name", claimName, "volume_snapshot", snapshotName)
+				if err := k8sClient.Create(ctx, &claim); err != nil && !apierrors.IsAlreadyExists(err) {
+					return err
+				}
+				reconcileState.AddEvent(corev1.EventTypeNormal, events.EventReasonUpgraded,
+					fmt.Sprintf("Creating volume claim %s from VolumeSnapshot %s", claimName, snapshotName))
+			}
+		}
+	}
+	return nil
+}
+
+// latestReadySnapshot returns the name of the most recent VolumeSnapshot of the given claim that is ready to be used,
+// and fits in the requested storage. It returns an empty string if there is none.
+func latestReadySnapshot(snapshots []unstructured.Unstructured, claimName string, storageRequest resource.Quantity) string {
+	var latestName string
+	var latestTime time.Time
+	for _, snapshot := range snapshots {
+		source, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+		ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+		if source != claimName || !ready || !snapshot.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if restoreSize, exists, _ := unstructured.NestedString(snapshot.Object, "status", "restoreSize"); exists {
+			if size, err := resource.ParseQuantity(restoreSize); err == nil && size.Cmp(storageRequest) > 0 {
+				// a volume cannot be restored into a smaller one
+				continue
+			}
+		}
+		creationTime := snapshot.GetCreationTimestamp().Time
+		if value, exists, _ := unstructured.NestedString(snapshot.Object, "status", "creationTime"); exists {
+			if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+				creationTime = parsed
+			}
+		}
+		if latestName == "" || creationTime.After(latestTime) {
+			latestName = snapshot.GetName()
+			latestTime = creationTime
+		}
+	}
+	return latestName
+}
+
+// claimFromSnapshot returns the claim the StatefulSet controller would create from the given template, restored from
+// the given VolumeSnapshot.
+func claimFromSnapshot(
+	template corev1.PersistentVolumeClaim,
+	selector *metav1.LabelSelector,
+	namespace string,
+	claimName string,
+	snapshotName string,
+) corev1.PersistentVolumeClaim {
+	claim := *template.DeepCopy()
+	claim.Namespace = namespace
+	claim.Name = claimName
+	if claim.Labels == nil {
+		claim.Labels = map[string]string{}
+	}
+	// like the StatefulSet controller, use the StatefulSet selector labels so the claim is matched to the cluster
+	if selector != nil {
+		for k, v := range selector.MatchLabels {
+			claim.Labels[k] = v
+		}
+	}
+	claim.Spec.DataSource = &corev1.TypedLocalObjectReference{
+		APIGroup: ptr.To(volumeSnapshotAPIGroup),
+		Kind:     "VolumeSnapshot",
+		Name:     snapshotName,
+	}
+	return claim
+}
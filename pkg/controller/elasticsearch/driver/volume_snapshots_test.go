@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func volumeSnapshot(name, claimName string, ready bool, creationTime string, restoreSize string) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"source": map[string]interface{}{"persistentVolumeClaimName": claimName},
+		},
+		"status": map[string]interface{}{
+			"readyToUse":   ready,
+			"creationTime": creationTime,
+			"restoreSize":  restoreSize,
+		},
+	}}
+	snapshot.SetAPIVersion("snapshot.storage.k8s.io/v1")
+	snapshot.SetKind("VolumeSnapshot")
+	snapshot.SetNamespace("ns")
+	snapshot.SetName(name)
+	return snapshot
+}
+
+func Test_latestReadySnapshot(t *testing.T) {
+	snapshots := []unstructured.Unstructured{
+		*volumeSnapshot("old", "elasticsearch-data-es-es-warm-0", true, "2024-01-01T00:00:00Z", "1Gi"),
+		*volumeSnapshot("recent", "elasticsearch-data-es-es-warm-0", true, "2024-01-02T00:00:00Z", "1Gi"),
+		*volumeSnapshot("not-ready", "elasticsearch-data-es-es-warm-0", false, "2024-01-03T00:00:00Z", "1Gi"),
+		*volumeSnapshot("too-big", "elasticsearch-data-es-es-warm-0", true, "2024-01-04T00:00:00Z", "2Gi"),
+		*volumeSnapshot("other-claim", "elasticsearch-data-es-es-warm-1", true, "2024-01-05T00:00:00Z", "1Gi"),
+	}
+	require.Equal(t, "recent", latestReadySnapshot(snapshots, "elasticsearch-data-es-es-warm-0", resource.MustParse("1Gi")))
+	require.Equal(t, "too-big", latestReadySnapshot(snapshots, "elasticsearch-data-es-es-warm-0", resource.MustParse("2Gi")))
+	require.Equal(t, "other-claim", latestReadySnapshot(snapshots, "elasticsearch-data-es-es-warm-1", resource.MustParse("1Gi")))
+	require.Equal(t, "", latestReadySnapshot(snapshots, "elasticsearch-data-es-es-warm-2", resource.MustParse("1Gi")))
+}
+
+func Test_prepopulateVolumesFromSnapshots(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "ns",
+		Name:        "es",
+		Annotations: map[string]string{esv1.VolumeSnapshotPrepopulationAnnotation: "warm"},
+	}}
+	claimTemplate := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+		Spec: corev1.PersistentVolumeClaimSpec{Resources: corev1.VolumeResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+		}},
+	}
+	expectedResources := func(nodeSet string, replicas int32) nodespec.ResourcesList {
+		statefulSet := sset.TestSset{Namespace: "ns", Name: "es-es-" + nodeSet, ClusterName: "es", Replicas: replicas}.Build()
+		statefulSet.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{label.ClusterNameLabelName: "es"}}
+		statefulSet.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{claimTemplate}
+		return nodespec.ResourcesList{{NodeSet: nodeSet, StatefulSet: statefulSet}}
+	}
+	existingClaim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "elasticsearch-data-es-es-warm-0"}}
+
+	tests := []struct {
+		name            string
+		resources       nodespec.ResourcesList
+		objects         []crclient.Object
+		wantDataSources map[string]string
+	}{
+		{
+			name:      "nodeSet not listed in the annotation",
+			resources: expectedResources("hot", 2),
+			objects: []crclient.Object{
+				volumeSnapshot("snapshot", "elasticsearch-data-es-es-hot-1", true, "2024-01-01T00:00:00Z", "1Gi"),
+			},
+			wantDataSources: map[string]string{},
+		},
+		{
+			name:      "existing claims are left untouched",
+			resources: expectedResources("warm", 1),
+			objects: []crclient.Object{
+				existingClaim,
+				volumeSnapshot("snapshot", "elasticsearch-data-es-es-warm-0", true, "2024-01-01T00:00:00Z", "1Gi"),
+			},
+			wantDataSources: map[string]string{"elasticsearch-data-es-es-warm-0": ""},
+		},
+		{
+			name:      "missing claims are created from their latest snapshot",
+			resources: expectedResources("warm", 3),
+			objects: []crclient.Object{
+				existingClaim,
+				volumeSnapshot("snapshot-1", "elasticsearch-data-es-es-warm-1", true, "2024-01-01T00:00:00Z", "1Gi"),
+				volumeSnapshot("snapshot-2", "elasticsearch-data-es-es-warm-1", true, "2024-01-02T00:00:00Z", "1Gi"),
+			},
+			wantDataSources: map[string]string{
+				"elasticsearch-data-es-es-warm-0": "",
+				"elasticsearch-data-es-es-warm-1": "snapshot-2",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := k8s.NewFakeClient(tt.objects...)
+			err := prepopulateVolumesFromSnapshots(context.Background(), k8sClient, es, tt.resources, reconcile.MustNewState(es))
+			require.NoError(t, err)
+
+			var claims corev1.PersistentVolumeClaimList
+			require.NoError(t, k8sClient.List(context.Background(), &claims))
+			dataSources := map[string]string{}
+			for _, claim := range claims.Items {
+				dataSources[claim.Name] = ""
+				if claim.Spec.DataSource != nil {
+					require.Equal(t, ptr.To("snapshot.storage.k8s.io"), claim.Spec.DataSource.APIGroup)
+					require.Equal(t, "VolumeSnapshot", claim.Spec.DataSource.Kind)
+					require.Equal(t, "es", claim.Labels[label.ClusterNameLabelName])
+					dataSources[claim.Name] = claim.Spec.DataSource.Name
+				}
+			}
+			require.Equal(t, tt.wantDataSources, dataSources)
+		})
+	}
+}
@@ -13,10 +13,12 @@ import (
 	"go.elastic.co/apm/v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -24,29 +26,40 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	remoteclusterv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/remotecluster/v1alpha1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	commondriver "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/finalizer"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/queue"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemonitor"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	commonversion "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/transport"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/diagnostics"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/observer"
 	esreconcile "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/validation"
 	esversion "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 const name = "elasticsearch-controller"
@@ -67,13 +80,19 @@ func Add(mgr manager.Manager, params operator.Parameters) error {
 func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileElasticsearch {
 	client := mgr.GetClient()
 	return &ReconcileElasticsearch{
-		Client:         client,
-		recorder:       mgr.GetEventRecorderFor(name),
-		licenseChecker: license.NewLicenseChecker(client, params.OperatorNamespace),
-		esObservers:    observer.NewManager(params.ElasticsearchObservationInterval, params.Tracer),
-
-		dynamicWatches: watches.NewDynamicWatches(),
-		expectations:   expectations.NewClustersExpectations(client),
+		Client:           client,
+		recorder:         mgr.GetEventRecorderFor(name),
+		podExecutor:      k8s.NewPodExecutor(mgr.GetConfig()),
+		k8sReader:        diagnostics.NewKubernetesReader(mgr.GetConfig()),
+		licenseChecker:   license.NewLicenseChecker(client, params.OperatorNamespace),
+		esClientProvider: commonesclient.NewClient,
+		esObservers:      observer.NewManager(params.ElasticsearchObservationInterval, params.Tracer),
+
+		dynamicWatches:   watches.NewDynamicWatches(),
+		expectations:     expectations.NewClustersExpectations(client),
+		stateCaches:      esclient.NewClustersStateCache(),
+		dependencyHashes: reconciler.NewDependencyHashes(),
+		stalledWatchdog:  commondriver.NewWatchdog(params.ReconciliationStalledThreshold),
 
 		Parameters: params,
 	}
@@ -82,7 +101,7 @@ func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileEl
 func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileElasticsearch) error {
 	// Watch for changes to Elasticsearch
 	if err := c.Watch(
-		source.Kind(mgr.GetCache(), &esv1.Elasticsearch{}, &handler.TypedEnqueueRequestForObject[*esv1.Elasticsearch]{})); err != nil {
+		source.Kind(mgr.GetCache(), &esv1.Elasticsearch{}, queue.EnqueueRequestForObject[*esv1.Elasticsearch]())); err != nil {
 		return err
 	}
 
@@ -103,13 +122,31 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileElasti
 		return err
 	}
 
+	// Watch ingresses
+	if err := c.Watch(source.Kind(mgr.GetCache(), &networkingv1.Ingress{}, handler.TypedEnqueueRequestForOwner[*networkingv1.Ingress](
+		mgr.GetScheme(), mgr.GetRESTMapper(),
+		&esv1.Elasticsearch{}, handler.OnlyControllerOwner(),
+	))); err != nil {
+		return err
+	}
+
+	// Watch Gateway routes
+	if err := gateway.WatchRoutes(mgr, c, &esv1.Elasticsearch{}); err != nil {
+		return err
+	}
+
+	// Watch ServiceMonitors
+	if err := servicemonitor.Watch(mgr, c, &esv1.Elasticsearch{}); err != nil {
+		return err
+	}
+
 	// Watch config maps for dynamic watches (currently used for additional CAs trust)
 	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.ConfigMap{}, r.dynamicWatches.ConfigMaps)); err != nil {
 		return err
 	}
 
 	// Watch owned and soft-owned secrets
-	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets)); err != nil {
+	if err := c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets)); err != nil {
 		return err
 	}
 	if err := r.dynamicWatches.Secrets.AddHandler(&watches.OwnerWatch[*corev1.Secret]{
@@ -125,8 +162,41 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileElasti
 		return err
 	}
 
+	// Watch SnapshotRepository resources to update the keystore with their secure settings
+	if err := c.Watch(source.Kind(mgr.GetCache(), &snapshotv1alpha1.SnapshotRepository{}, reconcileRequestForReferencedElasticsearch())); err != nil {
+		return err
+	}
+
+	// Watch client RemoteClusterLink resources to update the keystore with their cross-cluster API key
+	if err := c.Watch(source.Kind(mgr.GetCache(), &remoteclusterv1alpha1.RemoteClusterLink{}, reconcileRequestForLinkedElasticsearch())); err != nil {
+		return err
+	}
+
 	// Trigger a reconciliation when observers report a cluster health change
-	return c.Watch(observer.WatchClusterHealthChange(r.esObservers))
+	return c.Watch(observer.WatchClusterHealthChange(r.esObservers, r.ElasticsearchHealthBatchWindow))
+}
+
+// reconcileRequestForReferencedElasticsearch returns the requests to reconcile the Elasticsearch clusters a
+// SnapshotRepository is registered on.
+func reconcileRequestForReferencedElasticsearch() handler.TypedEventHandler[*snapshotv1alpha1.SnapshotRepository, reconcile.Request] {
+	return handler.TypedEnqueueRequestsFromMapFunc[*snapshotv1alpha1.SnapshotRepository](func(ctx context.Context, repository *snapshotv1alpha1.SnapshotRepository) []reconcile.Request {
+		requests := make([]reconcile.Request, 0, len(repository.Spec.ElasticsearchRefs))
+		for _, ref := range repository.Spec.ElasticsearchRefs {
+			requests = append(requests, reconcile.Request{NamespacedName: ref.WithDefaultNamespace(repository.Namespace).NamespacedName()})
+		}
+		return requests
+	})
+}
+
+// reconcileRequestForLinkedElasticsearch returns the request to reconcile the Elasticsearch cluster a
+// RemoteClusterLink connects to a remote cluster.
+func reconcileRequestForLinkedElasticsearch() handler.TypedEventHandler[*remoteclusterv1alpha1.RemoteClusterLink, reconcile.Request] {
+	return handler.TypedEnqueueRequestsFromMapFunc[*remoteclusterv1alpha1.RemoteClusterLink](func(ctx context.Context, link *remoteclusterv1alpha1.RemoteClusterLink) []reconcile.Request {
+		if link.Spec.Client == nil {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: link.Spec.ElasticsearchRef.WithDefaultNamespace(link.Namespace).NamespacedName()}}
+	})
 }
 
 var _ reconcile.Reconciler = &ReconcileElasticsearch{}
@@ -137,6 +207,12 @@ type ReconcileElasticsearch struct {
 	operator.Parameters
 	recorder       record.EventRecorder
 	licenseChecker license.Checker
+	// esClientProvider creates the Elasticsearch client used to take the final snapshot of a deleted cluster
+	esClientProvider commonesclient.Provider
+	// podExecutor runs commands in the Elasticsearch containers to update their keystore in place
+	podExecutor k8s.PodExecutor
+	// k8sReader reads the events and logs collected in the diagnostics bundles
+	k8sReader diagnostics.KubernetesReader
 
 	esObservers *observer.Manager
 
@@ -146,6 +222,16 @@ type ReconcileElasticsearch struct {
 	// by marking resources updates as expected, and skipping some operations if the cache is not up-to-date.
 	expectations *expectations.ClustersExpectation
 
+	// stateCaches cache the responses of expensive Elasticsearch API calls of each cluster between reconciliations.
+	stateCaches *esclient.ClustersStateCache
+
+	// dependencyHashes allow skipping the reconciliation of clusters whose dependencies did not change since their last
+	// complete reconciliation.
+	dependencyHashes *reconciler.DependencyHashes
+
+	// stalledWatchdog detects the clusters whose changes have been pending for too long.
+	stalledWatchdog *commondriver.Watchdog
+
 	// iteration is the number of times this controller has run its Reconcile method
 	iteration uint64
 }
@@ -167,7 +253,7 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 
 	if common.IsUnmanaged(ctx, &es) {
 		log.Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", es.Namespace, "es_name", es.Name)
-		return reconcile.Result{}, nil
+		return r.reportUnmanagedHealth(ctx, es)
 	}
 
 	// Remove any previous Finalizers
@@ -175,6 +261,27 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
+	// Protect the cluster from being deleted before its final snapshot is taken
+	if !es.IsMarkedForDeletion() {
+		if err := r.reconcileFinalSnapshotFinalizer(ctx, &es); err != nil {
+			return reconcile.Result{}, tracing.CaptureError(ctx, err)
+		}
+	} else if controllerutil.ContainsFinalizer(&es, FinalSnapshotFinalizer) {
+		res, err := r.reconcileFinalSnapshot(ctx, es)
+		return res, tracing.CaptureError(ctx, err)
+	}
+
+	// Skip the reconciliation if nothing it depends on changed since the last complete one
+	dependencies, err := r.dependencies(ctx, es)
+	if err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+	dependencyHash := dependencies.Hash()
+	if remaining, unchanged := r.dependencyHashes.Unchanged(request.NamespacedName, dependencyHash); unchanged && !es.IsMarkedForDeletion() {
+		log.V(1).Info("Dependencies unchanged since the last complete reconciliation, skipping", "namespace", es.Namespace, "es_name", es.Name)
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
 	state, err := esreconcile.NewState(es)
 	if err != nil {
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
@@ -197,23 +304,40 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 		}
 	}
 
-	if isReconciled, message := results.IsReconciled(); !isReconciled {
+	isReconciled, message := results.IsReconciled()
+	if !isReconciled {
 		state.UpdateWithPhase(esv1.ElasticsearchApplyingChangesPhase)
 		state.ReportCondition(esv1.ReconciliationComplete, corev1.ConditionFalse, message)
 	} else {
 		state.UpdateWithPhase(esv1.ElasticsearchReadyPhase)
 	}
+	r.reportStalledReconciliation(es, state, isReconciled, message)
+
+	// Dump the internal state of the operator if requested, failing to do so must not prevent the status update
+	if request, requested := diagnostics.Requested(es); requested {
+		if err := r.dumpDiagnostics(ctx, es, state, request, message); err != nil {
+			log.Error(err, "Error while dumping diagnostics", "namespace", es.Namespace, "es_name", es.Name)
+		}
+	}
+	if request, requested := diagnostics.BundleRequested(es); requested {
+		if err := r.collectDiagnosticsBundle(ctx, es, state, request, message); err != nil {
+			log.Error(err, "Error while collecting the diagnostics bundle", "namespace", es.Namespace, "es_name", es.Name)
+		}
+	}
 
 	// Last step of the reconciliation loop is always to update the Elasticsearch resource status.
 	err = r.updateStatus(ctx, es, state)
 	if err != nil {
 		if apierrors.IsConflict(err) {
 			log.V(1).Info("Conflict while updating status", "namespace", es.Namespace, "es_name", es.Name)
+			r.dependencyHashes.Forget(request.NamespacedName)
 			return reconcile.Result{Requeue: true}, nil
 		}
 		k8s.MaybeEmitErrorEvent(r.recorder, err, &es, events.EventReconciliationError, "Reconciliation error: %v", err)
 	}
-	return results.WithError(err).Aggregate()
+	result, err := results.WithError(err).Aggregate()
+	r.recordDependencies(request.NamespacedName, dependencyHash, result, err, isReconciled)
+	return result, err
 }
 
 func (r *ReconcileElasticsearch) fetchElasticsearchWithAssociations(ctx context.Context, request reconcile.Request, es *esv1.Elasticsearch) (bool, error) {
@@ -289,8 +413,10 @@ func (r *ReconcileElasticsearch) internalReconcile(
 		ReconcileState:     reconcileState,
 		Client:             r.Client,
 		Recorder:           r.recorder,
+		PodExecutor:        r.podExecutor,
 		Version:            ver,
 		Expectations:       r.expectations.ForCluster(k8s.ExtractNamespacedName(&es)),
+		StateCache:         r.stateCaches.ForCluster(k8s.ExtractNamespacedName(&es)),
 		Observers:          r.esObservers,
 		DynamicWatches:     r.dynamicWatches,
 		SupportedVersions:  *supported,
@@ -323,6 +449,123 @@ func (r *ReconcileElasticsearch) updateStatus(
 	return common.UpdateStatus(ctx, r.Client, cluster)
 }
 
+// reportUnmanagedHealth updates the health in the status of a cluster which is not managed, from the last observation
+// of the cluster, so that the status keeps reflecting the state of the cluster while it is not modified. The cluster
+// starts being observed if it was not already, for example when the operator restarts in maintenance mode.
+func (r *ReconcileElasticsearch) reportUnmanagedHealth(ctx context.Context, es esv1.Elasticsearch) (reconcile.Result, error) {
+	health, observed := r.esObservers.LastHealth(k8s.ExtractNamespacedName(&es))
+	if !observed {
+		var err error
+		health, observed, err = r.observeUnmanaged(ctx, es)
+		if err != nil {
+			return reconcile.Result{}, tracing.CaptureError(ctx, err)
+		}
+	}
+	if !observed || health == es.Status.Health {
+		return reconcile.Result{}, nil
+	}
+	es.Status.Health = health
+	if err := common.UpdateStatus(ctx, r.Client, &es); err != nil {
+		if apierrors.IsConflict(err) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+	return reconcile.Result{}, nil
+}
+
+// observeUnmanaged starts observing a cluster which is not managed, with the credentials and certificates previously
+// reconciled by the operator, without modifying them. It returns the health of a first synchronous observation, and
+// false if the cluster cannot be observed as it has no running Pods or was never reconciled.
+func (r *ReconcileElasticsearch) observeUnmanaged(ctx context.Context, es esv1.Elasticsearch) (esv1.ElasticsearchHealth, bool, error) {
+	if !services.NewElasticsearchURLProvider(es, r.Client).HasEndpoints() {
+		return "", false, nil
+	}
+	esClient, err := r.esClientProvider(ctx, r.Client, r.Dialer, es)
+	if apierrors.IsNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	esObserver := r.esObservers.Observe(ctx, es, func(esclient.Client) esclient.Client { return esClient }, true)
+	return esObserver.LastHealth(), true, nil
+}
+
+// dumpDiagnostics writes the internal view of the operator for the given cluster into its diagnostics ConfigMap.
+func (r *ReconcileElasticsearch) dumpDiagnostics(
+	ctx context.Context,
+	es esv1.Elasticsearch,
+	reconcileState *esreconcile.State,
+	request string,
+	pendingChanges string,
+) error {
+	defer tracing.Span(&ctx)()
+	dump, err := r.diagnosticsState(es, reconcileState, pendingChanges)
+	if err != nil {
+		return err
+	}
+	return diagnostics.Dump(ctx, r.Client, es, request, dump)
+}
+
+// collectDiagnosticsBundle collects the diagnostics of the given cluster into its diagnostics bundle Secret, unless the
+// bundle for the given request has already been collected.
+func (r *ReconcileElasticsearch) collectDiagnosticsBundle(
+	ctx context.Context,
+	es esv1.Elasticsearch,
+	reconcileState *esreconcile.State,
+	request string,
+	pendingChanges string,
+) error {
+	defer tracing.Span(&ctx)()
+	collected, err := diagnostics.BundleCollected(ctx, r.Client, es, request)
+	if err != nil || collected {
+		return err
+	}
+	state, err := r.diagnosticsState(es, reconcileState, pendingChanges)
+	if err != nil {
+		return err
+	}
+	collector := diagnostics.BundleCollector{Client: r.Client, Reader: r.k8sReader}
+	// the bundle is still useful without the Elasticsearch API, the missing entries are reported in the bundle
+	esClient, err := r.esClientProvider(ctx, r.Client, r.Dialer, es)
+	if err != nil {
+		ulog.FromContext(ctx).V(1).Info("Collecting diagnostics without Elasticsearch client", "namespace", es.Namespace, "es_name", es.Name, "error", err.Error())
+	} else {
+		defer esClient.Close()
+		collector.ESClient = esClient
+	}
+	if err := diagnostics.WriteBundle(ctx, r.Client, es, request, collector.Collect(ctx, es, state)); err != nil {
+		return err
+	}
+	r.recorder.Eventf(&es, corev1.EventTypeNormal, events.EventReasonDiagnostics,
+		"Diagnostics bundle collected into Secret %s", esv1.DiagnosticsBundleSecret(es.Name))
+	return nil
+}
+
+// diagnosticsState returns the internal view of the operator for the given cluster.
+func (r *ReconcileElasticsearch) diagnosticsState(
+	es esv1.Elasticsearch,
+	reconcileState *esreconcile.State,
+	pendingChanges string,
+) (diagnostics.State, error) {
+	nsn := k8s.ExtractNamespacedName(&es)
+	state, err := diagnostics.NewState(r.Client, es, r.expectations.ForCluster(nsn))
+	if err != nil {
+		return diagnostics.State{}, err
+	}
+	state.Phase = reconcileState.Phase()
+	state.PendingChanges = pendingChanges
+	state.OrchestrationHints = reconcileState.OrchestrationHints()
+	if health, observed := r.esObservers.LastHealth(nsn); observed {
+		state.ObservedHealth = health
+	}
+	if observation, observed := r.esObservers.LastObservation(nsn); observed {
+		state.LastObservation = &observation
+	}
+	return state, nil
+}
+
 // annotateResource adds the orchestration hints annotation to the Elasticsearch resource. The purpose of this annotation
 // is to capture additional state about aspects of the operator's orchestration of Elasticsearch resources. Currently,
 // it captures whether transient settings are in use.  Future expansion is possible if deemed necessary.
@@ -355,7 +598,14 @@ func (r *ReconcileElasticsearch) annotateResource(
 // onDelete garbage collect resources when an Elasticsearch cluster is deleted
 func (r *ReconcileElasticsearch) onDelete(ctx context.Context, es types.NamespacedName) error {
 	r.expectations.RemoveCluster(es)
+	r.stateCaches.RemoveCluster(es)
+	r.dependencyHashes.Forget(es)
+	r.stalledWatchdog.Forget(es)
 	r.esObservers.StopObserving(es)
+	esclient.CloseIdleConnections(r.ClusterName, es)
+	metrics.DeleteElasticsearchClientMetrics(es)
+	metrics.DeleteElasticsearchDiskMetrics(es)
+	metrics.DeleteElasticsearchSnapshotMetrics(es)
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(certificates.CertificateWatchKey(esv1.ESNamer, es.Name))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(transport.CustomTransportCertsWatchKey(es))
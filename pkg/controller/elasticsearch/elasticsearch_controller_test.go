@@ -4,29 +4,52 @@
 package elasticsearch
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/comparison"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/observer"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
 )
 
 // newTestReconciler returns a ReconcileElasticsearch struct, allowing the internal k8s client to
 // contain certain runtime objects.
 func newTestReconciler(objects ...client.Object) *ReconcileElasticsearch {
 	r := &ReconcileElasticsearch{
-		Client:   k8s.NewFakeClient(objects...),
-		recorder: record.NewFakeRecorder(100),
+		Client:           k8s.NewFakeClient(objects...),
+		recorder:         record.NewFakeRecorder(100),
+		esObservers:      observer.NewManager(0, nil),
+		esClientProvider: commonesclient.NewClient,
+		dynamicWatches:   watches.NewDynamicWatches(),
+		dependencyHashes: reconciler.NewDependencyHashes(),
 	}
 	return r
 }
@@ -251,3 +274,216 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileElasticsearch_Reconcile_MaintenanceMode(t *testing.T) {
+	common.SetMaintenanceMode(true)
+	defer common.SetMaintenanceMode(false)
+
+	es := newBuilder("testES", "test").
+		WithGeneration(2).
+		WithStatus(esv1.ElasticsearchStatus{ObservedGeneration: 1}).BuildAndCopy()
+	r := newTestReconciler(es.DeepCopy())
+	request := reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&es)}
+	if _, err := r.Reconcile(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+
+	var actualES esv1.Elasticsearch
+	if err := r.Client.Get(context.Background(), request.NamespacedName, &actualES); err != nil {
+		t.Fatal(err)
+	}
+	// neither the resource nor its status have been updated
+	comparison.AssertEqual(t, &actualES, &es)
+}
+
+func TestReconcileElasticsearch_Reconcile_MaintenanceModeHealth(t *testing.T) {
+	common.SetMaintenanceMode(true)
+	defer common.SetMaintenanceMode(false)
+
+	es := newBuilder("testES", "test").
+		WithGeneration(2).
+		WithStatus(esv1.ElasticsearchStatus{ObservedGeneration: 1, Health: esv1.ElasticsearchYellowHealth}).BuildAndCopy()
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "testES-es-default-0", Labels: label.NewLabels(k8s.ExtractNamespacedName(&es))},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	// the operator restarted in maintenance mode: the cluster is not observed yet
+	r := newTestReconciler(es.DeepCopy(), &pod)
+	r.esClientProvider = func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return esclient.NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"status":"green"}`)),
+				Header:     make(http.Header),
+				Request:    req,
+			}
+		}), nil
+	}
+	request := reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&es)}
+	_, err := r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+
+	// the cluster is observed and its health reported, without any other change
+	_, observed := r.esObservers.LastHealth(request.NamespacedName)
+	require.True(t, observed)
+	var actualES esv1.Elasticsearch
+	require.NoError(t, r.Client.Get(context.Background(), request.NamespacedName, &actualES))
+	require.Equal(t, esv1.ElasticsearchGreenHealth, actualES.Status.Health)
+	require.Equal(t, int64(1), actualES.Status.ObservedGeneration)
+	require.Empty(t, actualES.Finalizers)
+}
+
+func TestReconcileElasticsearch_Reconcile_UnchangedDependencies(t *testing.T) {
+	// an invalid cluster is completely reconciled as soon as its status is updated
+	es := newBuilder("testeswithtoolongofanamereallylongname", "test").
+		WithGeneration(2).
+		WithAnnotations(map[string]string{hints.OrchestrationsHintsAnnotation: `{"no_transient_settings":false}`}).
+		WithStatus(esv1.ElasticsearchStatus{ObservedGeneration: 1}).BuildAndCopy()
+	r := newTestReconciler(es.DeepCopy())
+	r.expectations = expectations.NewClustersExpectations(r.Client)
+	r.stateCaches = esclient.NewClustersStateCache()
+	request := reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&es)}
+
+	reconcileAndGetStatus := func() (reconcile.Result, esv1.ElasticsearchStatus) {
+		t.Helper()
+		result, err := r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		var actualES esv1.Elasticsearch
+		require.NoError(t, r.Client.Get(context.Background(), request.NamespacedName, &actualES))
+		return result, actualES.Status
+	}
+	resetStatus := func() {
+		t.Helper()
+		var actualES esv1.Elasticsearch
+		require.NoError(t, r.Client.Get(context.Background(), request.NamespacedName, &actualES))
+		actualES.Status = esv1.ElasticsearchStatus{ObservedGeneration: 1}
+		require.NoError(t, r.Client.Status().Update(context.Background(), &actualES))
+	}
+
+	// first reconciliation: the status is updated
+	result, status := reconcileAndGetStatus()
+	require.Equal(t, reconcile.Result{}, result)
+	require.Equal(t, int64(2), status.ObservedGeneration)
+
+	// nothing changed: the next reconciliation is skipped until the dependency hash expires
+	resetStatus()
+	result, status = reconcileAndGetStatus()
+	require.Positive(t, result.RequeueAfter)
+	require.LessOrEqual(t, result.RequeueAfter, maxSkippedReconciliationPeriod)
+	require.Equal(t, int64(1), status.ObservedGeneration)
+
+	// a Secret of the cluster changed: the next reconciliation is not skipped
+	require.NoError(t, r.Client.Create(context.Background(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: es.Namespace,
+		Name:      "secret",
+		Labels:    map[string]string{label.ClusterNameLabelName: es.Name},
+	}}))
+	result, status = reconcileAndGetStatus()
+	require.Equal(t, reconcile.Result{}, result)
+	require.Equal(t, int64(2), status.ObservedGeneration)
+
+	// the cluster is deleted: its dependency hash is forgotten
+	resetStatus()
+	require.NoError(t, r.onDelete(context.Background(), request.NamespacedName))
+	result, status = reconcileAndGetStatus()
+	require.Equal(t, reconcile.Result{}, result)
+	require.Equal(t, int64(2), status.ObservedGeneration)
+}
+
+func TestReconcileElasticsearch_dependencies(t *testing.T) {
+	es := newBuilder("es", "ns").BuildAndCopy()
+	repository := func(namespace, name string) *snapshotv1alpha1.SnapshotRepository {
+		return &snapshotv1alpha1.SnapshotRepository{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: snapshotv1alpha1.SnapshotRepositorySpec{
+				ElasticsearchRefs: []commonv1.LocalObjectSelector{{Namespace: "ns", Name: "es"}},
+			},
+		}
+	}
+	r := newTestReconciler(&es, repository("ns", "registered"), repository("other", "ignored"))
+
+	deps, err := r.dependencies(context.Background(), es)
+	require.NoError(t, err)
+	// only the repositories in the namespace of the cluster are registered on it
+	require.Contains(t, deps, "SnapshotRepository/ns/registered")
+	require.NotContains(t, deps, "SnapshotRepository/other/ignored")
+	initialHash := deps.Hash()
+
+	// the certificate rotation parameters are part of the dependencies
+	r.CertRotation = certificates.NewDynamicRotationParams(certificates.RotationParams{Validity: 24 * time.Hour, RotateBefore: time.Hour})
+	deps, err = r.dependencies(context.Background(), es)
+	require.NoError(t, err)
+	updatedHash := deps.Hash()
+	require.NotEqual(t, initialHash, updatedHash)
+
+	// as well as the CA rotation parameters
+	r.CACertRotation = certificates.NewDynamicRotationParams(certificates.RotationParams{Validity: 48 * time.Hour, RotateBefore: time.Hour})
+	deps, err = r.dependencies(context.Background(), es)
+	require.NoError(t, err)
+	require.NotEqual(t, updatedHash, deps.Hash())
+}
+
+func TestReconcileElasticsearch_recordDependencies(t *testing.T) {
+	es := types.NamespacedName{Namespace: "ns", Name: "es"}
+	tests := []struct {
+		name         string
+		result       reconcile.Result
+		err          error
+		reconciled   bool
+		wantRecorded bool
+		wantValidity time.Duration
+	}{
+		{
+			name:         "complete reconciliation",
+			result:       reconcile.Result{},
+			reconciled:   true,
+			wantRecorded: true,
+			wantValidity: maxSkippedReconciliationPeriod,
+		},
+		{
+			name:         "complete reconciliation with a requeue before the maximum period",
+			result:       reconcile.Result{RequeueAfter: time.Minute},
+			reconciled:   true,
+			wantRecorded: true,
+			wantValidity: time.Minute,
+		},
+		{
+			name:         "complete reconciliation with a requeue after the maximum period",
+			result:       reconcile.Result{RequeueAfter: 10 * time.Hour},
+			reconciled:   true,
+			wantRecorded: true,
+			wantValidity: maxSkippedReconciliationPeriod,
+		},
+		{
+			name:       "incomplete reconciliation",
+			result:     reconcile.Result{RequeueAfter: time.Minute},
+			reconciled: false,
+		},
+		{
+			name:       "immediate requeue",
+			result:     reconcile.Result{Requeue: true},
+			reconciled: true,
+		},
+		{
+			name:       "error",
+			err:        errors.New("boom"),
+			reconciled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReconcileElasticsearch{dependencyHashes: reconciler.NewDependencyHashes()}
+			// a hash recorded by a previous reconciliation is replaced or forgotten
+			r.dependencyHashes.Record(es, "previous-hash", time.Hour)
+			r.recordDependencies(es, "hash", tt.result, tt.err, tt.reconciled)
+			_, unchanged := r.dependencyHashes.Unchanged(es, "previous-hash")
+			require.False(t, unchanged)
+			remaining, unchanged := r.dependencyHashes.Unchanged(es, "hash")
+			require.Equal(t, tt.wantRecorded, unchanged)
+			if tt.wantRecorded {
+				require.LessOrEqual(t, remaining, tt.wantValidity)
+				require.Greater(t, remaining, tt.wantValidity-time.Minute)
+			}
+		})
+	}
+}
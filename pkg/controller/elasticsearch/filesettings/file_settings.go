@@ -82,11 +82,12 @@ func newEmptySettingsState() SettingsState {
 
 // updateState updates the Settings state from a StackConfigPolicy for a given Elasticsearch.
 func (s *Settings) updateState(es types.NamespacedName, policy policyv1alpha1.StackConfigPolicy) error {
-	p := policy.DeepCopy() // be sure to not mutate the original policy
+	// copy of the settings of the policy, with the override of the Elasticsearch namespace
+	spec := policy.ElasticsearchSpecFor(es.Namespace)
 	state := newEmptySettingsState()
 	// mutate Snapshot Repositories
-	if p.Spec.Elasticsearch.SnapshotRepositories != nil {
-		for name, untypedDefinition := range p.Spec.Elasticsearch.SnapshotRepositories.Data {
+	if spec.SnapshotRepositories != nil {
+		for name, untypedDefinition := range spec.SnapshotRepositories.Data {
 			definition, ok := untypedDefinition.(map[string]interface{})
 			if !ok {
 				return fmt.Errorf(`invalid type (%T) for definition of snapshot repository %q of Elasticsearch "%s/%s"`, untypedDefinition, name, es.Namespace, es.Name)
@@ -95,31 +96,31 @@ func (s *Settings) updateState(es types.NamespacedName, policy policyv1alpha1.St
 			if err != nil {
 				return err
 			}
-			p.Spec.Elasticsearch.SnapshotRepositories.Data[name] = repoSettings
+			spec.SnapshotRepositories.Data[name] = repoSettings
 		}
-		state.SnapshotRepositories = p.Spec.Elasticsearch.SnapshotRepositories
+		state.SnapshotRepositories = spec.SnapshotRepositories
 	}
 	// just copy other settings
-	if p.Spec.Elasticsearch.ClusterSettings != nil {
-		state.ClusterSettings = p.Spec.Elasticsearch.ClusterSettings
+	if spec.ClusterSettings != nil {
+		state.ClusterSettings = spec.ClusterSettings
 	}
-	if p.Spec.Elasticsearch.SnapshotLifecyclePolicies != nil {
-		state.SLM = p.Spec.Elasticsearch.SnapshotLifecyclePolicies
+	if spec.SnapshotLifecyclePolicies != nil {
+		state.SLM = spec.SnapshotLifecyclePolicies
 	}
-	if p.Spec.Elasticsearch.SecurityRoleMappings != nil {
-		state.RoleMappings = p.Spec.Elasticsearch.SecurityRoleMappings
+	if spec.SecurityRoleMappings != nil {
+		state.RoleMappings = spec.SecurityRoleMappings
 	}
-	if p.Spec.Elasticsearch.IndexLifecyclePolicies != nil {
-		state.IndexLifecyclePolicies = p.Spec.Elasticsearch.IndexLifecyclePolicies
+	if spec.IndexLifecyclePolicies != nil {
+		state.IndexLifecyclePolicies = spec.IndexLifecyclePolicies
 	}
-	if p.Spec.Elasticsearch.IngestPipelines != nil {
-		state.IngestPipelines = p.Spec.Elasticsearch.IngestPipelines
+	if spec.IngestPipelines != nil {
+		state.IngestPipelines = spec.IngestPipelines
 	}
-	if p.Spec.Elasticsearch.IndexTemplates.ComposableIndexTemplates != nil {
-		state.IndexTemplates.ComposableIndexTemplates = p.Spec.Elasticsearch.IndexTemplates.ComposableIndexTemplates
+	if spec.IndexTemplates.ComposableIndexTemplates != nil {
+		state.IndexTemplates.ComposableIndexTemplates = spec.IndexTemplates.ComposableIndexTemplates
 	}
-	if p.Spec.Elasticsearch.IndexTemplates.ComponentTemplates != nil {
-		state.IndexTemplates.ComponentTemplates = p.Spec.Elasticsearch.IndexTemplates.ComponentTemplates
+	if spec.IndexTemplates.ComponentTemplates != nil {
+		state.IndexTemplates.ComponentTemplates = spec.IndexTemplates.ComponentTemplates
 	}
 	s.State = state
 	return nil
@@ -431,6 +431,78 @@ func Test_updateState(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "namespace overrides: merged into the settings of the policy",
+			args: args{policy: policyv1alpha1.StackConfigPolicy{Spec: policyv1alpha1.StackConfigPolicySpec{
+				Elasticsearch: policyv1alpha1.ElasticsearchConfigPolicySpec{
+					IndexLifecyclePolicies: indexLifecyclePolicies,
+					IngestPipelines:        ingestPipelines,
+				},
+				NamespaceOverrides: []policyv1alpha1.NamespaceOverride{
+					{
+						Namespaces: []string{"otherNs"},
+						Elasticsearch: policyv1alpha1.ElasticsearchSettingsOverride{
+							ClusterSettings: clusterSettings,
+						},
+					},
+					{
+						Namespaces: []string{"tenantNs", "esNs"},
+						Elasticsearch: policyv1alpha1.ElasticsearchSettingsOverride{
+							IndexLifecyclePolicies: &commonv1.Config{Data: map[string]any{
+								"test-policy": map[string]any{
+									"phases": map[string]any{
+										"delete": map[string]any{"min_age": "7d"},
+									},
+								},
+							}},
+							IngestPipelines: &commonv1.Config{Data: map[string]any{
+								"test-ingest-pipeline": map[string]any{
+									"processors": []any{},
+								},
+							}},
+							IndexTemplates: policyv1alpha1.IndexTemplates{
+								ComponentTemplates: componentTemplates,
+							},
+						},
+					},
+				},
+			}}},
+			want: SettingsState{
+				ClusterSettings:      &commonv1.Config{Data: map[string]any{}},
+				SnapshotRepositories: &commonv1.Config{Data: map[string]any{}},
+				SLM:                  &commonv1.Config{Data: map[string]any{}},
+				RoleMappings:         &commonv1.Config{Data: map[string]any{}},
+				IndexLifecyclePolicies: &commonv1.Config{Data: map[string]any{
+					"test-policy": map[string]any{
+						"phases": map[string]any{
+							"delete": map[string]any{
+								"actions": map[string]any{
+									"delete": map[string]any{},
+								},
+								"min_age": "7d",
+							},
+							"warm": map[string]any{
+								"actions": map[string]any{
+									"forcemerge": map[string]any{
+										"max_num_segments": float64(1),
+									},
+								},
+								"min_age": "10d",
+							},
+						},
+					},
+				}},
+				IngestPipelines: &commonv1.Config{Data: map[string]any{
+					"test-ingest-pipeline": map[string]any{
+						"processors": []any{},
+					},
+				}},
+				IndexTemplates: &IndexTemplates{
+					ComponentTemplates:       componentTemplates,
+					ComposableIndexTemplates: &commonv1.Config{Data: map[string]any{}},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
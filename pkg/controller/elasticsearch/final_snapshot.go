@@ -0,0 +1,146 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// FinalSnapshotFinalizer prevents the deletion of an Elasticsearch resource until its final snapshot is completed.
+// It does not match the pattern of the legacy finalizers removed by the controller.
+const FinalSnapshotFinalizer = "elasticsearch.k8s.elastic.co/final-snapshot"
+
+var (
+	// finalSnapshotRequeue is the interval at which the completion of a final snapshot is checked.
+	finalSnapshotRequeue = reconcile.Result{RequeueAfter: 10 * time.Second}
+	// finalSnapshotBlockedRequeue is the interval at which a final snapshot that cannot be taken without a change of
+	// the user is checked again.
+	finalSnapshotBlockedRequeue = reconcile.Result{RequeueAfter: 5 * time.Minute}
+)
+
+// finalSnapshotMaxAttempts is the number of times a final snapshot is taken before giving up when it does not succeed.
+const finalSnapshotMaxAttempts = 3
+
+// finalSnapshotName returns the name of the given attempt of the snapshot taken before the deletion of the given
+// cluster. It includes the UID of the resource to not collide with the final snapshot of a previous cluster with the
+// same name.
+func finalSnapshotName(es esv1.Elasticsearch, attempt int) string {
+	name := fmt.Sprintf("%s-final-%s", es.Name, es.UID)
+	if attempt > 0 {
+		name = fmt.Sprintf("%s-%d", name, attempt)
+	}
+	return name
+}
+
+// reconcileFinalSnapshotFinalizer adds the final snapshot finalizer to the Elasticsearch resource if a final snapshot
+// is requested, and removes it otherwise.
+func (r *ReconcileElasticsearch) reconcileFinalSnapshotFinalizer(ctx context.Context, es *esv1.Elasticsearch) error {
+	var updated bool
+	if es.Spec.FinalSnapshot != nil {
+		updated = controllerutil.AddFinalizer(es, FinalSnapshotFinalizer)
+	} else {
+		updated = controllerutil.RemoveFinalizer(es, FinalSnapshotFinalizer)
+	}
+	if !updated {
+		return nil
+	}
+	return r.Client.Update(ctx, es)
+}
+
+// reconcileFinalSnapshot takes the final snapshot of an Elasticsearch cluster marked for deletion, and removes the
+// final snapshot finalizer to let the deletion proceed once the snapshot is successfully completed. A failed snapshot
+// is taken again up to finalSnapshotMaxAttempts times.
+func (r *ReconcileElasticsearch) reconcileFinalSnapshot(ctx context.Context, es esv1.Elasticsearch) (reconcile.Result, error) {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx)
+
+	// the final snapshot was disabled after the deletion started
+	if es.Spec.FinalSnapshot == nil {
+		controllerutil.RemoveFinalizer(&es, FinalSnapshotFinalizer)
+		return reconcile.Result{}, r.Client.Update(ctx, &es)
+	}
+
+	repository := es.Spec.FinalSnapshot.Repository
+	esClient, err := r.esClientProvider(ctx, r.Client, r.Dialer, es)
+	if err != nil {
+		return r.finalSnapshotUnreachable(ctx, es, err)
+	}
+	defer esClient.Close()
+
+	snapshots, err := esClient.GetSnapshots(ctx, repository, finalSnapshotName(es, 0)+"*")
+	if err != nil {
+		return r.finalSnapshotUnreachable(ctx, es, err)
+	}
+	// a failed or partial snapshot is not usable, take it again under a new name as the name of a snapshot cannot be
+	// reused in a repository
+	failed := 0
+	for _, snapshot := range snapshots {
+		switch snapshot.State {
+		case esclient.SnapshotSuccessState:
+			log.Info("Final snapshot completed", "namespace", es.Namespace, "es_name", es.Name, "repository", repository, "snapshot", snapshot.Snapshot)
+			controllerutil.RemoveFinalizer(&es, FinalSnapshotFinalizer)
+			return reconcile.Result{}, r.Client.Update(ctx, &es)
+		case esclient.SnapshotInProgressState:
+			return finalSnapshotRequeue, nil
+		default:
+			failed++
+		}
+	}
+
+	if failed >= finalSnapshotMaxAttempts {
+		// do not let the deletion proceed without a usable snapshot, the final snapshot can be skipped by removing it
+		// from the specification
+		r.recorder.Eventf(&es, corev1.EventTypeWarning, events.EventReconciliationError,
+			"Final snapshot in repository %s failed %d times, remove spec.finalSnapshot to delete the cluster without it", repository, failed)
+		return finalSnapshotBlockedRequeue, nil
+	}
+
+	name := finalSnapshotName(es, failed)
+	log.Info("Taking final snapshot", "namespace", es.Namespace, "es_name", es.Name, "repository", repository, "snapshot", name, "attempt", failed+1)
+	if err := esClient.CreateSnapshot(ctx, repository, name, esclient.SnapshotCreateRequest{IncludeGlobalState: true}); err != nil {
+		return r.finalSnapshotUnreachable(ctx, es, err)
+	}
+	if failed > 0 {
+		r.recorder.Eventf(&es, corev1.EventTypeWarning, events.EventReasonDelayed,
+			"Final snapshot in repository %s failed, taking it again as %s (attempt %d/%d)", repository, name, failed+1, finalSnapshotMaxAttempts)
+	} else {
+		r.recorder.Eventf(&es, corev1.EventTypeNormal, events.EventReasonDelayed,
+			"Deletion delayed until the final snapshot %s in repository %s is completed", name, repository)
+	}
+	return finalSnapshotRequeue, nil
+}
+
+// finalSnapshotUnreachable reports that the final snapshot cannot be taken because the Elasticsearch API returned the
+// given error. If no Pod of the cluster is running, for example because the namespace of the cluster is being deleted,
+// the deletion is reported as blocked until the user removes the final snapshot from the specification.
+func (r *ReconcileElasticsearch) finalSnapshotUnreachable(ctx context.Context, es esv1.Elasticsearch, err error) (reconcile.Result, error) {
+	pods, podsErr := sset.GetActualPodsForCluster(r.Client, es)
+	if podsErr != nil {
+		return reconcile.Result{}, podsErr
+	}
+	if len(k8s.RunningPods(pods)) == 0 {
+		ulog.FromContext(ctx).Info("No running Pod to take the final snapshot", "namespace", es.Namespace, "es_name", es.Name, "error", err.Error())
+		r.recorder.Eventf(&es, corev1.EventTypeWarning, events.EventReconciliationError,
+			"Final snapshot cannot be taken as no Pod of the cluster is running, remove spec.finalSnapshot to delete the cluster without it")
+		return finalSnapshotBlockedRequeue, nil
+	}
+	r.recorder.Eventf(&es, corev1.EventTypeWarning, events.EventReconciliationError,
+		"Final snapshot cannot be taken as Elasticsearch is unreachable: %s", err.Error())
+	return reconcile.Result{}, err
+}
@@ -0,0 +1,196 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeSnapshotsClient stores snapshots in memory, indexed by repository and name.
+type fakeSnapshotsClient struct {
+	esclient.Client
+	snapshots map[string]map[string]esclient.Snapshot
+}
+
+func (c fakeSnapshotsClient) GetSnapshots(_ context.Context, repository, pattern string) ([]esclient.Snapshot, error) {
+	var snapshots []esclient.Snapshot
+	for name, snapshot := range c.snapshots[repository] {
+		if strings.HasPrefix(name, strings.TrimSuffix(pattern, "*")) {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+func (c fakeSnapshotsClient) CreateSnapshot(_ context.Context, repository, name string, _ esclient.SnapshotCreateRequest) error {
+	if c.snapshots[repository] == nil {
+		c.snapshots[repository] = map[string]esclient.Snapshot{}
+	}
+	c.snapshots[repository][name] = esclient.Snapshot{Snapshot: name, State: esclient.SnapshotInProgressState}
+	return nil
+}
+
+func (c fakeSnapshotsClient) Close() {}
+
+func TestReconcileElasticsearch_reconcileFinalSnapshotFinalizer(t *testing.T) {
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{FinalSnapshot: &esv1.FinalSnapshot{Repository: "backups"}},
+	}
+	r := newTestReconciler(es)
+
+	require.NoError(t, r.reconcileFinalSnapshotFinalizer(context.Background(), es))
+	require.Equal(t, []string{FinalSnapshotFinalizer}, es.Finalizers)
+
+	es.Spec.FinalSnapshot = nil
+	require.NoError(t, r.reconcileFinalSnapshotFinalizer(context.Background(), es))
+	require.Empty(t, es.Finalizers)
+}
+
+func TestReconcileElasticsearch_reconcileFinalSnapshot(t *testing.T) {
+	ctx := context.Background()
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", UID: "uid", Finalizers: []string{FinalSnapshotFinalizer}},
+		Spec:       esv1.ElasticsearchSpec{FinalSnapshot: &esv1.FinalSnapshot{Repository: "backups"}},
+	}
+	snapshots := map[string]map[string]esclient.Snapshot{}
+	recorder := record.NewFakeRecorder(10)
+	r := newTestReconciler(es)
+	r.recorder = recorder
+	r.esClientProvider = func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return fakeSnapshotsClient{snapshots: snapshots}, nil
+	}
+	require.NoError(t, r.Client.Delete(ctx, es))
+	getElasticsearch := func() esv1.Elasticsearch {
+		var actual esv1.Elasticsearch
+		require.NoError(t, r.Client.Get(ctx, k8s.ExtractNamespacedName(es), &actual))
+		return actual
+	}
+
+	// the final snapshot is taken
+	res, err := r.reconcileFinalSnapshot(ctx, getElasticsearch())
+	require.NoError(t, err)
+	require.Equal(t, finalSnapshotRequeue, res)
+	require.Equal(t, esclient.SnapshotInProgressState, snapshots["backups"]["es-final-uid"].State)
+	require.Len(t, recorder.Events, 1)
+
+	// the deletion is blocked while the snapshot is in progress
+	res, err = r.reconcileFinalSnapshot(ctx, getElasticsearch())
+	require.NoError(t, err)
+	require.Equal(t, finalSnapshotRequeue, res)
+	require.Contains(t, getElasticsearch().Finalizers, FinalSnapshotFinalizer)
+
+	// a failed snapshot is taken again under a new name
+	snapshots["backups"]["es-final-uid"] = esclient.Snapshot{Snapshot: "es-final-uid", State: "FAILED"}
+	res, err = r.reconcileFinalSnapshot(ctx, getElasticsearch())
+	require.NoError(t, err)
+	require.Equal(t, finalSnapshotRequeue, res)
+	require.Equal(t, esclient.SnapshotInProgressState, snapshots["backups"]["es-final-uid-1"].State)
+	require.Len(t, recorder.Events, 2)
+	require.Contains(t, getElasticsearch().Finalizers, FinalSnapshotFinalizer)
+
+	// the deletion proceeds once a snapshot succeeded
+	snapshots["backups"]["es-final-uid-1"] = esclient.Snapshot{Snapshot: "es-final-uid-1", State: esclient.SnapshotSuccessState}
+	_, err = r.reconcileFinalSnapshot(ctx, getElasticsearch())
+	require.NoError(t, err)
+	err = r.Client.Get(ctx, k8s.ExtractNamespacedName(es), &esv1.Elasticsearch{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileElasticsearch_reconcileFinalSnapshot_Disabled(t *testing.T) {
+	ctx := context.Background()
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Finalizers: []string{FinalSnapshotFinalizer}},
+	}
+	r := newTestReconciler(es)
+	require.NoError(t, r.Client.Delete(ctx, es))
+	var actual esv1.Elasticsearch
+	require.NoError(t, r.Client.Get(ctx, k8s.ExtractNamespacedName(es), &actual))
+
+	// the deletion proceeds without snapshot if the final snapshot is removed from the specification
+	_, err := r.reconcileFinalSnapshot(ctx, actual)
+	require.NoError(t, err)
+	err = r.Client.Get(ctx, k8s.ExtractNamespacedName(es), &esv1.Elasticsearch{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileElasticsearch_reconcileFinalSnapshot_Failed(t *testing.T) {
+	ctx := context.Background()
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", UID: "uid", Finalizers: []string{FinalSnapshotFinalizer}},
+		Spec:       esv1.ElasticsearchSpec{FinalSnapshot: &esv1.FinalSnapshot{Repository: "backups"}},
+	}
+	snapshots := map[string]map[string]esclient.Snapshot{"backups": {
+		"es-final-uid":   {Snapshot: "es-final-uid", State: "FAILED"},
+		"es-final-uid-1": {Snapshot: "es-final-uid-1", State: "PARTIAL"},
+		"es-final-uid-2": {Snapshot: "es-final-uid-2", State: "FAILED"},
+	}}
+	recorder := record.NewFakeRecorder(10)
+	r := newTestReconciler(es)
+	r.recorder = recorder
+	r.esClientProvider = func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return fakeSnapshotsClient{snapshots: snapshots}, nil
+	}
+	require.NoError(t, r.Client.Delete(ctx, es))
+	var actual esv1.Elasticsearch
+	require.NoError(t, r.Client.Get(ctx, k8s.ExtractNamespacedName(es), &actual))
+
+	// the deletion is blocked once all the attempts failed
+	res, err := r.reconcileFinalSnapshot(ctx, actual)
+	require.NoError(t, err)
+	require.Equal(t, finalSnapshotBlockedRequeue, res)
+	require.Len(t, snapshots["backups"], 3)
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, "failed 3 times")
+}
+
+func TestReconcileElasticsearch_reconcileFinalSnapshot_Unreachable(t *testing.T) {
+	ctx := context.Background()
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", UID: "uid", Finalizers: []string{FinalSnapshotFinalizer}},
+		Spec:       esv1.ElasticsearchSpec{FinalSnapshot: &esv1.FinalSnapshot{Repository: "backups"}},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-es-default-0", Labels: map[string]string{label.ClusterNameLabelName: "es"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := newTestReconciler(es, pod)
+	r.recorder = recorder
+	r.esClientProvider = func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return nil, errors.New("elastic user secret not found")
+	}
+	require.NoError(t, r.Client.Delete(ctx, es))
+	var actual esv1.Elasticsearch
+	require.NoError(t, r.Client.Get(ctx, k8s.ExtractNamespacedName(es), &actual))
+
+	// the error is retried while Pods of the cluster are running
+	_, err := r.reconcileFinalSnapshot(ctx, actual)
+	require.Error(t, err)
+	require.Contains(t, <-recorder.Events, "Elasticsearch is unreachable")
+
+	// the deletion is reported as blocked once the Pods are gone, for example when the namespace is deleted
+	require.NoError(t, r.Client.Delete(ctx, pod))
+	res, err := r.reconcileFinalSnapshot(ctx, actual)
+	require.NoError(t, err)
+	require.Equal(t, finalSnapshotBlockedRequeue, res)
+	require.Contains(t, <-recorder.Events, "no Pod of the cluster is running")
+	require.Contains(t, actual.Finalizers, FinalSnapshotFinalizer)
+}
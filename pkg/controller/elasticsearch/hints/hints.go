@@ -26,15 +26,25 @@ type OrchestrationsHints struct {
 	// controllers should then rely on regular users until the value is true.
 	ServiceAccounts *optional.Bool    `json:"service_accounts,omitempty"`
 	DesiredNodes    *DesiredNodesHint `json:"desired_nodes,omitempty"`
+
+	// SecureSettingsHash is the hash of the data of the operator-managed secure settings Secret loaded in the keystore
+	// of all the nodes of the Elasticsearch cluster. It lets the SnapshotRepository controller know when rotated
+	// repository credentials can be verified.
+	SecureSettingsHash string `json:"secure_settings_hash,omitempty"`
 }
 
 // Merge merges the hints in other into the receiver.
 func (oh OrchestrationsHints) Merge(other OrchestrationsHints) OrchestrationsHints {
-	return OrchestrationsHints{
+	merged := OrchestrationsHints{
 		NoTransientSettings: oh.NoTransientSettings || other.NoTransientSettings,
 		ServiceAccounts:     oh.ServiceAccounts.Or(other.ServiceAccounts),
 		DesiredNodes:        oh.DesiredNodes.ReplaceWith(other.DesiredNodes),
+		SecureSettingsHash:  oh.SecureSettingsHash,
+	}
+	if other.SecureSettingsHash != "" {
+		merged.SecureSettingsHash = other.SecureSettingsHash
 	}
+	return merged
 }
 
 // AsAnnotation returns a representation of orchestration hints that can be used as an annotation on the
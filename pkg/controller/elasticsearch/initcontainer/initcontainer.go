@@ -20,7 +20,7 @@ const (
 
 // NewInitContainers creates init containers according to the given parameters
 func NewInitContainers(
-	transportCertificatesVolume volume.SecretVolume,
+	transportCertificatesVolume volume.VolumeLike,
 	keystoreResources *keystore.Resources,
 	nodeLabelsAsAnnotations []string,
 ) ([]corev1.Container, error) {
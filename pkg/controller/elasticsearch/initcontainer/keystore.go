@@ -5,10 +5,14 @@
 package initcontainer
 
 import (
+	"regexp"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 )
 
@@ -16,12 +20,25 @@ const (
 	KeystoreBinPath = "/usr/share/elasticsearch/bin/elasticsearch-keystore"
 )
 
+// ReloadableSecureSettings matches the secure settings Elasticsearch reloads with the _nodes/reload_secure_settings API.
+// Changing them only requires to update the keystore of the nodes in place, instead of restarting them.
+var ReloadableSecureSettings = []*regexp.Regexp{
+	regexp.MustCompile(`^azure\.client\.[^.]+\.(account|key|sas_token)$`),
+	regexp.MustCompile(`^gcs\.client\.[^.]+\.credentials_file$`),
+	regexp.MustCompile(`^s3\.client\.[^.]+\.(access_key|secret_key|session_token)$`),
+	regexp.MustCompile(`^cluster\.remote\.[^.]+\.credentials$`),
+	regexp.MustCompile(`^xpack\.notification\.[^.]+\.account\.[^.]+\.(secure_[a-z_]+|smtp\.secure_password)$`),
+	regexp.MustCompile(`^xpack\.security\.authc\.realms\.jwt\.[^.]+\.(client_authentication\.shared_secret|hmac_key|hmac_jwkset)$`),
+}
+
 // KeystoreParams is used to generate the init container that will load the secure settings into a keystore.
 var KeystoreParams = keystore.InitContainerParameters{
 	KeystoreCreateCommand:         KeystoreBinPath + " create",
 	KeystoreAddCommand:            KeystoreBinPath + ` add-file "$key" "$filename"`,
 	SecureSettingsVolumeMountPath: keystore.SecureSettingsVolumeMountPath,
 	KeystoreVolumePath:            esvolume.ConfigVolumeMountPath,
+	KeystoreFile:                  esvolume.ConfigVolumeMountPath + "/elasticsearch.keystore",
+	ReloadableSettings:            ReloadableSecureSettings,
 	Resources: corev1.ResourceRequirements{
 		Requests: map[corev1.ResourceName]resource.Quantity{
 			corev1.ResourceMemory: resource.MustParse("196Mi"),
@@ -33,3 +50,18 @@ var KeystoreParams = keystore.InitContainerParameters{
 		},
 	},
 }
+
+// KeystoreParamsFor returns the parameters to load the secure settings in the keystore of the given cluster. If a
+// keystore password is specified, the keystore is encrypted with the password read from the file referenced by the
+// ES_KEYSTORE_PASSPHRASE_FILE environment variable, inherited from the Elasticsearch container. The password is written
+// to the standard input of the keystore tool, once more to confirm it on creation.
+func KeystoreParamsFor(es esv1.Elasticsearch) keystore.InitContainerParameters {
+	params := KeystoreParams
+	if es.Spec.KeystorePassword == nil {
+		return params
+	}
+	passwordFile := `"${` + settings.EnvKeystorePassphraseFile + `}"`
+	params.KeystoreCreateCommand = "awk 1 " + passwordFile + " " + passwordFile + " | " + KeystoreBinPath + " create -p"
+	params.KeystoreAddCommand = "awk 1 " + passwordFile + " | " + KeystoreBinPath + ` add-file "$key" "$filename"`
+	return params
+}
@@ -0,0 +1,31 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package initcontainer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func TestKeystoreParamsFor(t *testing.T) {
+	es := esv1.Elasticsearch{}
+	assert.Equal(t, KeystoreParams, KeystoreParamsFor(es))
+
+	es.Spec.KeystorePassword = &esv1.SecretKeyRef{SecretName: "keystore-password", Key: "password"}
+	params := KeystoreParamsFor(es)
+	assert.Equal(t,
+		`awk 1 "${ES_KEYSTORE_PASSPHRASE_FILE}" "${ES_KEYSTORE_PASSPHRASE_FILE}" | /usr/share/elasticsearch/bin/elasticsearch-keystore create -p`,
+		params.KeystoreCreateCommand,
+	)
+	assert.Equal(t,
+		`awk 1 "${ES_KEYSTORE_PASSPHRASE_FILE}" | /usr/share/elasticsearch/bin/elasticsearch-keystore add-file "$key" "$filename"`,
+		params.KeystoreAddCommand,
+	)
+	// the default parameters are left untouched
+	assert.Equal(t, KeystoreBinPath+" create", KeystoreParams.KeystoreCreateCommand)
+}
@@ -55,6 +55,11 @@ const (
 
 	HTTPSchemeLabelName = "elasticsearch.k8s.elastic.co/http-scheme"
 
+	// ServiceProfileLabelName is set on the Services of the service profiles to the name of the profile
+	ServiceProfileLabelName = "elasticsearch.k8s.elastic.co/service-profile"
+	// ServiceProfilePodLabelPrefix prefixes the labels set to true on the Pods targeted by a service profile
+	ServiceProfilePodLabelPrefix = ServiceProfileLabelName + "-"
+
 	// Type represents the Elasticsearch type
 	Type = "elasticsearch"
 )
@@ -73,6 +78,50 @@ var NonMasterRoles = []labels.TrueFalseLabel{
 	NodeTypesTransformLabelName,
 }
 
+// NodeRoleLabels maps the node roles to the labels set to true on the Pods of the nodes with the role.
+var NodeRoleLabels = map[esv1.NodeRole]labels.TrueFalseLabel{
+	esv1.MasterRole:              NodeTypesMasterLabelName,
+	esv1.DataRole:                NodeTypesDataLabelName,
+	esv1.DataHotRole:             NodeTypesDataHotLabelName,
+	esv1.DataColdRole:            NodeTypesDataColdLabelName,
+	esv1.DataFrozenRole:          NodeTypesDataFrozenLabelName,
+	esv1.DataContentRole:         NodeTypesDataContentLabelName,
+	esv1.DataWarmRole:            NodeTypesDataWarmLabelName,
+	esv1.IngestRole:              NodeTypesIngestLabelName,
+	esv1.MLRole:                  NodeTypesMLLabelName,
+	esv1.RemoteClusterClientRole: NodeTypesRemoteClusterClientLabelName,
+	esv1.TransformRole:           NodeTypesTransformLabelName,
+	esv1.VotingOnlyRole:          NodeTypesVotingOnlyLabelName,
+}
+
+// ServiceProfilePodLabel returns the label set to true on the Pods targeted by the given service profile.
+func ServiceProfilePodLabel(profileName string) string {
+	return ServiceProfilePodLabelPrefix + profileName
+}
+
+// MatchesServiceProfile returns true if the Pod has at least one of the roles of the given service profile.
+func MatchesServiceProfile(pod corev1.Pod, profile esv1.ServiceProfile) bool {
+	for _, role := range profile.Roles {
+		if role == esv1.CoordinatingOnlyProfileRole && isCoordinatingOnly(pod) {
+			return true
+		}
+		if roleLabel, known := NodeRoleLabels[esv1.NodeRole(role)]; known && roleLabel.HasValue(true, pod.Labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCoordinatingOnly returns true if the Pod has no role.
+func isCoordinatingOnly(pod corev1.Pod) bool {
+	for _, roleLabel := range NodeRoleLabels {
+		if roleLabel.HasValue(true, pod.Labels) {
+			return false
+		}
+	}
+	return true
+}
+
 // IsMasterNode returns true if the pod has the master node label
 func IsMasterNode(pod corev1.Pod) bool {
 	return NodeTypesMasterLabelName.HasValue(true, pod.Labels)
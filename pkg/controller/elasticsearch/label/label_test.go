@@ -18,6 +18,7 @@ import (
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	v1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 )
 
@@ -264,3 +265,65 @@ func TestNewPodLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchesServiceProfile(t *testing.T) {
+	podWithRoles := func(roles ...labels.TrueFalseLabel) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{}}}
+		for _, roleLabel := range NodeRoleLabels {
+			roleLabel.Set(false, pod.Labels)
+		}
+		for _, role := range roles {
+			role.Set(true, pod.Labels)
+		}
+		return pod
+	}
+	tests := []struct {
+		name  string
+		pod   corev1.Pod
+		roles []string
+		want  bool
+	}{
+		{
+			name:  "one matching role",
+			pod:   podWithRoles(NodeTypesDataHotLabelName, NodeTypesIngestLabelName),
+			roles: []string{"ml", "ingest"},
+			want:  true,
+		},
+		{
+			name:  "no matching role",
+			pod:   podWithRoles(NodeTypesMasterLabelName),
+			roles: []string{"data", "ingest"},
+			want:  false,
+		},
+		{
+			name:  "coordinating-only node",
+			pod:   podWithRoles(),
+			roles: []string{v1.CoordinatingOnlyProfileRole},
+			want:  true,
+		},
+		{
+			name:  "coordinating-only nodes without role labels",
+			pod:   corev1.Pod{},
+			roles: []string{v1.CoordinatingOnlyProfileRole},
+			want:  true,
+		},
+		{
+			name:  "node with roles is not coordinating-only",
+			pod:   podWithRoles(NodeTypesRemoteClusterClientLabelName),
+			roles: []string{v1.CoordinatingOnlyProfileRole},
+			want:  false,
+		},
+		{
+			name:  "unknown roles are ignored",
+			pod:   podWithRoles(NodeTypesDataLabelName),
+			roles: []string{"unknown"},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := v1.ServiceProfile{Name: "profile", Roles: tt.roles}
+			require.Equal(t, tt.want, MatchesServiceProfile(tt.pod, profile))
+		})
+	}
+}
@@ -48,18 +48,32 @@ func (sm *ShardMigration) ShutdownStatus(ctx context.Context, podName string) (s
 	if shardActivity {
 		ulog.FromContext(ctx).Info("Delaying node shutdown because of shard activity",
 			"namespace", sm.es.Namespace, "es_name", sm.es.Name, "pod_name", podName)
-		return shutdown.NodeShutdownStatus{Status: esclient.ShutdownInProgress}, nil
+		return sm.inProgress(ctx, podName)
 	}
 	migrating, err := nodeMayHaveShard(ctx, sm.es, sm.s, podName)
 	if err != nil {
 		return shutdown.NodeShutdownStatus{}, err
 	}
 	if migrating {
-		return shutdown.NodeShutdownStatus{Status: esclient.ShutdownInProgress}, nil
+		return sm.inProgress(ctx, podName)
 	}
 	return shutdown.NodeShutdownStatus{Status: esclient.ShutdownComplete}, nil
 }
 
+// inProgress returns an in progress shutdown status with the shards remaining on the given node.
+func (sm *ShardMigration) inProgress(ctx context.Context, podName string) (shutdown.NodeShutdownStatus, error) {
+	shards, err := sm.s.GetShards(ctx)
+	if err != nil {
+		return shutdown.NodeShutdownStatus{}, err
+	}
+	count, size := shards.OnNode(podName)
+	return shutdown.NodeShutdownStatus{
+		Status:          esclient.ShutdownInProgress,
+		RemainingShards: &count,
+		RemainingBytes:  &size,
+	}, nil
+}
+
 // nodeMayHaveShard returns true if one of those conditions is met:
 // - the given ES Pod is holding at least one shard (primary or replica)
 // - some shards in the cluster don't have a node assigned, in which case we can't be sure about the 1st condition
@@ -73,6 +73,17 @@ func DefaultEnvVars(v version.Version, httpCfg commonv1.HTTPConfig, headlessServ
 	return defaults.ExtendPodDownwardEnvVars(vars...)
 }
 
+// keystorePasswordEnvVars returns the environment variable referencing the file holding the password of the keystore,
+// if the keystore is protected by a password.
+func keystorePasswordEnvVars(es esv1.Elasticsearch) []corev1.EnvVar {
+	if es.Spec.KeystorePassword == nil {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{Name: settings.EnvKeystorePassphraseFile, Value: esvolume.KeystorePasswordFile(*es.Spec.KeystorePassword)},
+	}
+}
+
 // DefaultAffinity returns the default affinity for pods in a cluster.
 func DefaultAffinity(esName string) *corev1.Affinity {
 	return &corev1.Affinity{
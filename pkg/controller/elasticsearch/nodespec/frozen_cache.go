@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+// nodeSetConfig returns the user configuration of the nodeSet, with the size of the shared cache of its frozen tier
+// nodes if specified.
+func nodeSetConfig(nodeSet esv1.NodeSet) (commonv1.Config, error) {
+	cfg := commonv1.Config{}
+	if nodeSet.Config != nil {
+		cfg = *nodeSet.Config.DeepCopy()
+	}
+	if nodeSet.FrozenCache == nil || nodeSet.FrozenCache.Size == "" {
+		return cfg, nil
+	}
+	size, err := nodeSet.FrozenCache.SharedCacheSize()
+	if err != nil {
+		return commonv1.Config{}, err
+	}
+	if cfg.Data == nil {
+		cfg.Data = map[string]interface{}{}
+	}
+	cfg.Data[esv1.SearchableSnapshotSharedCacheSize] = size
+	return cfg, nil
+}
+
+// withFrozenCacheVolume returns the nodeSet with its data volume replaced by the volume dedicated to the shared cache
+// of its frozen tier nodes, if specified.
+func withFrozenCacheVolume(nodeSet esv1.NodeSet) esv1.NodeSet {
+	if nodeSet.FrozenCache == nil || nodeSet.FrozenCache.Volume == nil {
+		return nodeSet
+	}
+	cacheVolume := nodeSet.FrozenCache.Volume
+	if cacheVolume.Ephemeral {
+		nodeSet.EphemeralStorage = &esv1.EphemeralStorage{SizeLimit: &cacheVolume.Storage}
+		return nodeSet
+	}
+	claim := esvolume.DefaultDataVolumeClaim.DeepCopy()
+	claim.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: cacheVolume.Storage}
+	claim.Spec.StorageClassName = cacheVolume.StorageClassName
+	nodeSet.VolumeClaimTemplates = append(append([]corev1.PersistentVolumeClaim{}, nodeSet.VolumeClaimTemplates...), *claim)
+	return nodeSet
+}
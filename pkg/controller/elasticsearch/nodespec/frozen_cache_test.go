@@ -0,0 +1,63 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_nodeSetConfig(t *testing.T) {
+	userConfig := &commonv1.Config{Data: map[string]interface{}{"node.roles": []interface{}{"data_frozen"}}}
+
+	cfg, err := nodeSetConfig(esv1.NodeSet{Config: userConfig})
+	require.NoError(t, err)
+	require.Equal(t, *userConfig, cfg)
+
+	cfg, err = nodeSetConfig(esv1.NodeSet{Config: userConfig, FrozenCache: &esv1.FrozenCache{Size: "1Gi"}})
+	require.NoError(t, err)
+	require.Equal(t, commonv1.Config{Data: map[string]interface{}{
+		"node.roles": []interface{}{"data_frozen"},
+		"xpack.searchable.snapshot.shared_cache.size": "1073741824b",
+	}}, cfg)
+	// the user configuration is not modified
+	require.NotContains(t, userConfig.Data, "xpack.searchable.snapshot.shared_cache.size")
+
+	cfg, err = nodeSetConfig(esv1.NodeSet{FrozenCache: &esv1.FrozenCache{Size: "90%"}})
+	require.NoError(t, err)
+	require.Equal(t, commonv1.Config{Data: map[string]interface{}{"xpack.searchable.snapshot.shared_cache.size": "90%"}}, cfg)
+}
+
+func Test_withFrozenCacheVolume(t *testing.T) {
+	storage := resource.MustParse("100Gi")
+
+	nodeSet := withFrozenCacheVolume(esv1.NodeSet{Name: "frozen", FrozenCache: &esv1.FrozenCache{Size: "90%"}})
+	require.Empty(t, nodeSet.VolumeClaimTemplates)
+	require.Nil(t, nodeSet.EphemeralStorage)
+
+	nodeSet = withFrozenCacheVolume(esv1.NodeSet{Name: "frozen", FrozenCache: &esv1.FrozenCache{
+		Volume: &esv1.FrozenCacheVolume{Storage: storage, StorageClassName: ptr.To("local")},
+	}})
+	require.Nil(t, nodeSet.EphemeralStorage)
+	require.Len(t, nodeSet.VolumeClaimTemplates, 1)
+	claim := nodeSet.VolumeClaimTemplates[0]
+	require.Equal(t, "elasticsearch-data", claim.Name)
+	require.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, claim.Spec.AccessModes)
+	require.Equal(t, corev1.ResourceList{corev1.ResourceStorage: storage}, claim.Spec.Resources.Requests)
+	require.Equal(t, ptr.To("local"), claim.Spec.StorageClassName)
+
+	nodeSet = withFrozenCacheVolume(esv1.NodeSet{Name: "frozen", FrozenCache: &esv1.FrozenCache{
+		Volume: &esv1.FrozenCacheVolume{Storage: storage, Ephemeral: true},
+	}})
+	require.Empty(t, nodeSet.VolumeClaimTemplates)
+	require.Equal(t, &esv1.EphemeralStorage{SizeLimit: &storage}, nodeSet.EphemeralStorage)
+}
@@ -0,0 +1,164 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+const (
+	// JVMDiagnosticsUploaderContainerName is the name of the sidecar container uploading the heap dumps to a bucket.
+	JVMDiagnosticsUploaderContainerName = "jvm-diagnostics-uploader"
+	// JVMDiagnosticsBucketKey is the entry of the upload Secret holding the URL of the bucket.
+	JVMDiagnosticsBucketKey = "bucket"
+
+	jvmDiagnosticsBucketEnvVar = "DIAGNOSTICS_BUCKET"
+	// elasticsearchUserID is the ID of the user running Elasticsearch in the official images.
+	elasticsearchUserID int64 = 1000
+)
+
+var (
+	// jvmDiagnosticsOptions make the JVM write its heap dumps on OutOfMemoryError and its GC logs to the diagnostics
+	// volume, in addition to the GC logs written to the logs directory by default.
+	jvmDiagnosticsOptions = []string{
+		"-XX:+HeapDumpOnOutOfMemoryError",
+		"-XX:HeapDumpPath=" + esvolume.JVMDiagnosticsMountPath,
+		fmt.Sprintf(
+			"-Xlog:gc*,gc+age=trace,safepoint:file=%s:utctime,level,pid,tags:filecount=32,filesize=64m",
+			path.Join(esvolume.JVMDiagnosticsMountPath, "gc.log"),
+		),
+	}
+
+	// jvmDiagnosticsUploadScript periodically uploads the complete heap dumps to the bucket, under a prefix named after
+	// the Pod, and removes them from the volume once uploaded.
+	jvmDiagnosticsUploadScript = fmt.Sprintf(`case "${%[1]s}" in
+  s3://*) upload() { aws s3 cp --only-show-errors "$1" "$2"; } ;;
+  gs://*) upload() { gsutil -q cp "$1" "$2"; } ;;
+  *) echo "Unsupported bucket URL ${%[1]s}, expected s3:// or gs://" >&2; exit 1 ;;
+esac
+while true; do
+  for dump in %[2]s/*.hprof; do
+    [ -f "${dump}" ] || continue
+    # wait for the JVM to be done writing the heap dump
+    size=$(stat -c %%s "${dump}"); sleep 10
+    [ "$(stat -c %%s "${dump}")" = "${size}" ] || continue
+    upload "${dump}" "${%[1]s%%/}/${POD_NAME}/$(date -u +%%Y%%m%%dT%%H%%M%%SZ)-$(basename "${dump}")" && rm -f "${dump}"
+  done
+  sleep 30
+done
+`, jvmDiagnosticsBucketEnvVar, esvolume.JVMDiagnosticsMountPath)
+
+	jvmDiagnosticsUploaderResources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+		},
+	}
+)
+
+// withJVMDiagnosticsVolume mounts the volume storing the heap dumps and GC logs of the JVM: the volume of the given
+// volume claim template, or an emptyDir volume by default.
+func withJVMDiagnosticsVolume(
+	volumes []corev1.Volume,
+	volumeMounts []corev1.VolumeMount,
+	diagnostics esv1.JVMDiagnostics,
+) ([]corev1.Volume, []corev1.VolumeMount) {
+	volumeName := diagnostics.VolumeClaimTemplate
+	if volumeName == "" {
+		volumeName = esvolume.JVMDiagnosticsVolumeName
+		volumes = append(volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: diagnostics.SizeLimit},
+			},
+		})
+	}
+	return volumes, append(volumeMounts, corev1.VolumeMount{Name: volumeName, MountPath: esvolume.JVMDiagnosticsMountPath})
+}
+
+// withJVMDiagnosticsOptions configures the JVM of the Elasticsearch container to write its heap dumps and GC logs to
+// the diagnostics volume.
+func withJVMDiagnosticsOptions(builder *defaults.PodTemplateBuilder, nodeSet esv1.NodeSet) {
+	if nodeSet.JVMDiagnostics == nil {
+		return
+	}
+	prependJavaOpts(builder, strings.Join(jvmDiagnosticsOptions, " "))
+}
+
+// jvmDiagnosticsContainers returns the sidecar container uploading the heap dumps to a bucket, if requested.
+func jvmDiagnosticsContainers(nodeSet esv1.NodeSet) []corev1.Container {
+	if nodeSet.JVMDiagnostics == nil || nodeSet.JVMDiagnostics.Upload == nil {
+		return nil
+	}
+	return []corev1.Container{jvmDiagnosticsUploader(*nodeSet.JVMDiagnostics, *nodeSet.JVMDiagnostics.Upload)}
+}
+
+// prependJavaOpts prepends the given options to the ES_JAVA_OPTS environment variable of the Elasticsearch container,
+// so that the options set by the user take precedence.
+func prependJavaOpts(builder *defaults.PodTemplateBuilder, opts string) {
+	esContainer := builder.MainContainer()
+	for i, envVar := range esContainer.Env {
+		if envVar.Name != settings.EnvEsJavaOpts {
+			continue
+		}
+		if envVar.ValueFrom == nil {
+			esContainer.Env[i].Value = strings.TrimSpace(opts + " " + envVar.Value)
+		}
+		return
+	}
+	esContainer.Env = append(esContainer.Env, corev1.EnvVar{Name: settings.EnvEsJavaOpts, Value: opts})
+}
+
+// jvmDiagnosticsUploader returns the sidecar container uploading the heap dumps to the bucket. It runs with the user
+// of Elasticsearch to be able to read and remove the heap dumps.
+func jvmDiagnosticsUploader(diagnostics esv1.JVMDiagnostics, upload esv1.JVMDiagnosticsUpload) corev1.Container {
+	volumeName := diagnostics.VolumeClaimTemplate
+	if volumeName == "" {
+		volumeName = esvolume.JVMDiagnosticsVolumeName
+	}
+	return corev1.Container{
+		Name:    JVMDiagnosticsUploaderContainerName,
+		Image:   upload.Image,
+		Command: []string{"/bin/sh", "-c", jvmDiagnosticsUploadScript},
+		Env: defaults.ExtendPodDownwardEnvVars(
+			// the CLIs write their configuration and caches to the home directory
+			corev1.EnvVar{Name: "HOME", Value: "/tmp"},
+			corev1.EnvVar{Name: jvmDiagnosticsBucketEnvVar, ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: upload.SecretName},
+					Key:                  JVMDiagnosticsBucketKey,
+				},
+			}},
+		),
+		EnvFrom: []corev1.EnvFromSource{
+			{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: upload.SecretName}}},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: volumeName, MountPath: esvolume.JVMDiagnosticsMountPath},
+		},
+		Resources: jvmDiagnosticsUploaderResources,
+		SecurityContext: &corev1.SecurityContext{
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			Privileged:               ptr.To(false),
+			AllowPrivilegeEscalation: ptr.To(false),
+			RunAsUser:                ptr.To(elasticsearchUserID),
+			RunAsNonRoot:             ptr.To(true),
+		},
+	}
+}
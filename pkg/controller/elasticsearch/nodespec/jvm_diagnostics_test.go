@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+)
+
+func Test_withJVMDiagnosticsOptions(t *testing.T) {
+	diagnosticsOpts := "-XX:+HeapDumpOnOutOfMemoryError -XX:HeapDumpPath=/usr/share/elasticsearch/jvm-diagnostics " +
+		"-Xlog:gc*,gc+age=trace,safepoint:file=/usr/share/elasticsearch/jvm-diagnostics/gc.log:utctime,level,pid,tags:filecount=32,filesize=64m"
+	tests := []struct {
+		name        string
+		diagnostics *esv1.JVMDiagnostics
+		env         []corev1.EnvVar
+		want        []corev1.EnvVar
+	}{
+		{
+			name: "no JVM diagnostics",
+			env:  []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: "-Xms2g -Xmx2g"}},
+			want: []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: "-Xms2g -Xmx2g"}},
+		},
+		{
+			name:        "no ES_JAVA_OPTS",
+			diagnostics: &esv1.JVMDiagnostics{},
+			want:        []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: diagnosticsOpts}},
+		},
+		{
+			name:        "prepended to the user ES_JAVA_OPTS",
+			diagnostics: &esv1.JVMDiagnostics{},
+			env:         []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: "-Xms2g -Xmx2g"}},
+			want:        []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: diagnosticsOpts + " -Xms2g -Xmx2g"}},
+		},
+		{
+			name:        "ES_JAVA_OPTS from a ConfigMap is left untouched",
+			diagnostics: &esv1.JVMDiagnostics{},
+			env: []corev1.EnvVar{{Name: "ES_JAVA_OPTS", ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "opts"},
+			}}},
+			want: []corev1.EnvVar{{Name: "ES_JAVA_OPTS", ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "opts"},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podTemplate := corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: esv1.ElasticsearchContainerName, Env: tt.env},
+			}}}
+			builder := defaults.NewPodTemplateBuilder(podTemplate, esv1.ElasticsearchContainerName)
+			withJVMDiagnosticsOptions(builder, esv1.NodeSet{JVMDiagnostics: tt.diagnostics})
+			assert.Equal(t, tt.want, builder.MainContainer().Env)
+		})
+	}
+}
+
+func Test_jvmDiagnosticsContainers(t *testing.T) {
+	require.Empty(t, jvmDiagnosticsContainers(esv1.NodeSet{}))
+	require.Empty(t, jvmDiagnosticsContainers(esv1.NodeSet{JVMDiagnostics: &esv1.JVMDiagnostics{}}))
+
+	containers := jvmDiagnosticsContainers(esv1.NodeSet{JVMDiagnostics: &esv1.JVMDiagnostics{
+		VolumeClaimTemplate: "dumps",
+		Upload:              &esv1.JVMDiagnosticsUpload{SecretName: "dumps-bucket", Image: "amazon/aws-cli:2.17.0"},
+	}})
+	require.Len(t, containers, 1)
+	uploader := containers[0]
+	assert.Equal(t, "jvm-diagnostics-uploader", uploader.Name)
+	assert.Equal(t, "amazon/aws-cli:2.17.0", uploader.Image)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "dumps", MountPath: "/usr/share/elasticsearch/jvm-diagnostics"}}, uploader.VolumeMounts)
+	assert.Equal(t, []corev1.EnvFromSource{
+		{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "dumps-bucket"}}},
+	}, uploader.EnvFrom)
+	assert.Contains(t, uploader.Env, corev1.EnvVar{Name: "DIAGNOSTICS_BUCKET", ValueFrom: &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "dumps-bucket"}, Key: "bucket"},
+	}})
+}
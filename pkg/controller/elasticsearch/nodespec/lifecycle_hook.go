@@ -13,6 +13,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 )
@@ -169,7 +170,16 @@ else
   delayed_exit
 fi
 
+{{ if .LocalNode -}}
+# the sidecar container of the service mesh may stop before this script completes, call the local node directly
+if [[ $POD_IP =~ .*:.* ]]; then
+  ES_URL="http://[::1]:{{.HTTPPort}}"
+else
+  ES_URL="http://127.0.0.1:{{.HTTPPort}}"
+fi
+{{- else -}}
 ES_URL="{{.ServiceURL}}"
+{{- end }}
 
 log "retrieving node ID"
 if ! retry "$retries_count" request -X GET "${ES_URL}/_cat/nodes?full_id=true&h=id,name" "${BASIC_AUTH[@]}"
@@ -217,8 +227,10 @@ done
 delayed_exit
 `))
 
-func RenderPreStopHookScript(svcURL string) (string, error) {
-	vars := map[string]string{
+// RenderPreStopHookScript renders the pre-stop hook script calling Elasticsearch through the given service URL, or
+// through the loopback interface if localNode is true.
+func RenderPreStopHookScript(svcURL string, localNode bool) (string, error) {
+	vars := map[string]any{
 		"PreStopUserName":         user.PreStopUserName,
 		"PreStopUserPasswordPath": filepath.Join(volume.PodMountedUsersSecretMountPath, user.PreStopUserName),
 		// edge case: protocol change (http/https) combined with external node shutdown might not work out well due to
@@ -227,6 +239,8 @@ func RenderPreStopHookScript(svcURL string) (string, error) {
 		"ServiceURL":       svcURL,
 		"LabelsFile":       filepath.Join(volume.DownwardAPIMountPath, volume.LabelsFile),
 		"VersionLabelName": label.VersionLabelName,
+		"LocalNode":        localNode,
+		"HTTPPort":         network.HTTPPort,
 	}
 	var script bytes.Buffer
 	err := preStopHookScriptTemplate.Execute(&script, vars)
@@ -21,6 +21,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemesh"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
@@ -38,7 +39,10 @@ const (
 	defaultFsGroup                    = 1000
 	log4j2FormatMsgNoLookupsParamName = "-Dlog4j2.formatMsgNoLookups"
 	// ConfigHashAnnotationName is an annotation used to store a hash of the Elasticsearch configuration.
-	configHashAnnotationName = "elasticsearch.k8s.elastic.co/config-hash"
+	ConfigHashAnnotationName = "elasticsearch.k8s.elastic.co/config-hash"
+	// restartTriggerAnnotationName is an annotation used to store the value of the restart trigger annotation of the
+	// Elasticsearch resource, in order to rotate the pods when it changes.
+	restartTriggerAnnotationName = "elasticsearch.k8s.elastic.co/restart-trigger"
 )
 
 // Starting 8.0.0, the Elasticsearch container does not run with the root user anymore. As a result,
@@ -65,9 +69,15 @@ func BuildPodTemplateSpec(
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
 	}
+	image, err := container.ResolveImage(es.Spec.Image, container.ElasticsearchImage, ver)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	downwardAPIVolume := volume.DownwardAPI{}.WithAnnotations(es.HasDownwardNodeLabels())
-	volumes, volumeMounts := buildVolumes(es.Name, ver, nodeSet, keystoreResources, downwardAPIVolume, policyConfig.AdditionalVolumes)
+	ssetName := es.StatefulSetName(nodeSet.Name)
+	transportCertificatesShards := es.Spec.Transport.TLS.CertificatesSecretShardsCount()
+	volumes, volumeMounts := buildVolumes(es.Name, ssetName, transportCertificatesShards, ver, nodeSet, es.Spec.Auth, keystoreResources, es.Spec.KeystorePassword, downwardAPIVolume, policyConfig.AdditionalVolumes)
 
 	labels, err := buildLabels(es, cfg, nodeSet)
 	if err != nil {
@@ -78,7 +88,7 @@ func BuildPodTemplateSpec(
 
 	// now build the initContainers using the effective main container resources as an input
 	initContainers, err := initcontainer.NewInitContainers(
-		transportCertificatesVolume(esv1.StatefulSet(es.Name, nodeSet.Name)),
+		transportCertificatesVolume(ssetName, transportCertificatesShards),
 		keystoreResources,
 		es.DownwardNodeLabels(),
 	)
@@ -94,7 +104,7 @@ func BuildPodTemplateSpec(
 		})
 	}
 
-	headlessServiceName := HeadlessServiceName(esv1.StatefulSet(es.Name, nodeSet.Name))
+	headlessServiceName := HeadlessServiceName(ssetName)
 
 	// We retrieve the ConfigMap that holds the scripts to trigger a Pod restart if it is updated.
 	esScripts := &corev1.ConfigMap{}
@@ -118,27 +128,38 @@ func BuildPodTemplateSpec(
 	builder = builder.
 		WithLabels(labels).
 		WithAnnotations(annotations).
-		WithDockerImage(es.Spec.Image, container.ImageRepository(container.ElasticsearchImage, ver)).
+		WithAnnotations(servicemesh.PodAnnotations(es.Spec.HTTP.TLS, network.TransportPort)).
+		WithDockerImage(es.Spec.Image, image).
 		WithResources(DefaultResources).
 		WithTerminationGracePeriod(DefaultTerminationGracePeriodSeconds).
 		WithPorts(defaultContainerPorts).
 		WithReadinessProbe(*NewReadinessProbe(ver)).
 		WithAffinity(DefaultAffinity(es.Name)).
 		WithEnv(DefaultEnvVars(ver, es.Spec.HTTP, headlessServiceName)...).
+		WithEnv(keystorePasswordEnvVars(es)...).
 		WithVolumes(volumes...).
 		WithVolumeMounts(volumeMounts...).
 		WithInitContainers(initContainers...).
+		WithContainers(jvmDiagnosticsContainers(nodeSet)...).
+		WithContainers(prometheusExporterContainers(es)...).
 		// inherit all env vars from main containers to allow Elasticsearch tools that read ES config to work in initContainers
 		WithInitContainerDefaults(builder.MainContainer().Env...).
 		// set a default security context for both the Containers and the InitContainers
 		WithContainersSecurityContext(securitycontext.For(ver, enableReadOnlyRootFilesystem)).
 		WithPreStopHook(*NewPreStopHook())
 
+	withJVMDiagnosticsOptions(builder, nodeSet)
+
 	builder, err = stackmon.WithMonitoring(ctx, client, builder, es)
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
 	}
 
+	builder, err = withWorkloadIdentity(ctx, client, builder, es)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
 	if ver.LT(version.From(7, 2, 0)) {
 		// mitigate CVE-2021-44228
 		enableLog4JFormatMsgNoLookups(builder)
@@ -154,9 +175,22 @@ func getDefaultContainerPorts(es esv1.Elasticsearch) []corev1.ContainerPort {
 	}
 }
 
-func transportCertificatesVolume(ssetName string) volume.SecretVolume {
-	return volume.NewSecretVolumeWithMountPath(
-		esv1.StatefulSetTransportCertificatesSecret(ssetName),
+// transportCertificatesVolume returns the volume containing the transport certificates of the StatefulSet, which
+// projects all the Secrets the certificates are spread across if there are several of them.
+func transportCertificatesVolume(ssetName string, shards int32) volume.VolumeLike {
+	if shards <= 1 {
+		return volume.NewSecretVolumeWithMountPath(
+			esv1.StatefulSetTransportCertificatesSecret(ssetName),
+			esvolume.TransportCertificatesSecretVolumeName,
+			esvolume.TransportCertificatesSecretVolumeMountPath,
+		)
+	}
+	secretNames := make([]string, shards)
+	for shard := range shards {
+		secretNames[shard] = esv1.StatefulSetTransportCertificatesSecretShard(ssetName, shard)
+	}
+	return volume.NewProjectedSecretsVolume(
+		secretNames,
 		esvolume.TransportCertificatesSecretVolumeName,
 		esvolume.TransportCertificatesSecretVolumeMountPath,
 	)
@@ -181,7 +215,7 @@ func buildLabels(
 	node := unpackedCfg.Node
 	podLabels := label.NewPodLabels(
 		k8s.ExtractNamespacedName(&es),
-		esv1.StatefulSet(es.Name, nodeSet.Name),
+		es.StatefulSetName(nodeSet.Name),
 		ver, node, es.Spec.HTTP.Protocol(),
 	)
 
@@ -212,16 +246,22 @@ func buildAnnotations(
 	}
 
 	if keystoreResources != nil {
-		// resource version of the secure settings secret to rotate the pod on secure settings change
-		_, _ = configHash.Write([]byte(keystoreResources.Hash))
+		// hash of the secure settings that cannot be reloaded to rotate the pod on secure settings change, the other
+		// ones are updated in place in the keystore
+		_, _ = configHash.Write([]byte(keystoreResources.RestartHash))
 	}
 
 	if !es.Spec.Transport.TLS.SelfSignedEnabled() {
 		annotations[esv1.TransportCertDisabledAnnotationName] = "true"
 	}
 
+	if trigger := es.Annotations[esv1.RestartTriggerAnnotation]; trigger != "" {
+		// rotate the pods when a new restart is requested
+		annotations[restartTriggerAnnotationName] = trigger
+	}
+
 	// set the annotation in place
-	annotations[configHashAnnotationName] = fmt.Sprint(configHash.Sum32())
+	annotations[ConfigHashAnnotationName] = fmt.Sprint(configHash.Sum32())
 
 	// set policy annotations
 	maps.Merge(annotations, policyAnnotations)
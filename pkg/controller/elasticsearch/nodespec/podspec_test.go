@@ -225,7 +225,7 @@ func TestBuildPodTemplateSpecWithDefaultSecurityContext(t *testing.T) {
 			es.Spec.Version = tt.version.String()
 			es.Spec.NodeSets[0].PodTemplate.Spec.SecurityContext = tt.userSecurityContext
 
-			cfg, err := settings.NewMergedESConfig(es.Name, tt.version, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config, nil)
+			cfg, err := settings.NewMergedESConfig(es.Name, tt.version, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.Auth, es.Spec.Auditing, *es.Spec.NodeSets[0].Config, nil, "", false, nil)
 			require.NoError(t, err)
 
 			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
@@ -308,7 +308,7 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 			ver, err := version.Parse(es.Spec.Version)
 			require.NoError(t, err)
 
-			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, *nodeSet.Config, tt.args.policyConfig.ElasticsearchConfig)
+			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.Auth, es.Spec.Auditing, *nodeSet.Config, nil, "", false, tt.args.policyConfig.ElasticsearchConfig)
 			require.NoError(t, err)
 
 			actual, err := BuildPodTemplateSpec(context.Background(), tt.args.client, es, es.Spec.NodeSets[0], cfg, tt.args.keystoreResources, tt.args.setDefaultSecurityContext, tt.args.policyConfig)
@@ -376,11 +376,22 @@ func Test_buildAnnotations(t *testing.T) {
 				"elasticsearch.k8s.elastic.co/config-hash": "909392898",
 			},
 		},
+		{
+			name: "With a restart trigger",
+			args: args{
+				esAnnotations: map[string]string{"eck.k8s.elastic.co/restart": "2024-01-01T00:00:00Z"},
+			},
+			expectedAnnotations: map[string]string{
+				"elasticsearch.k8s.elastic.co/config-hash":     "3089472956",
+				"elasticsearch.k8s.elastic.co/restart-trigger": "2024-01-01T00:00:00Z",
+			},
+		},
 		{
 			name: "With keystore and scripts content",
 			args: args{
 				keystoreResources: &keystore.Resources{
-					Hash: "42",
+					Hash:        "42",
+					RestartHash: "42",
 				},
 				scriptsContent: "scripts content",
 			},
@@ -392,7 +403,8 @@ func Test_buildAnnotations(t *testing.T) {
 			name: "With another keystore version",
 			args: args{
 				keystoreResources: &keystore.Resources{
-					Hash: "43",
+					Hash:        "43",
+					RestartHash: "43",
 				},
 				scriptsContent: "scripts content",
 			},
@@ -400,11 +412,25 @@ func Test_buildAnnotations(t *testing.T) {
 				"elasticsearch.k8s.elastic.co/config-hash": "4016898420",
 			},
 		},
+		{
+			name: "With another reloadable keystore version",
+			args: args{
+				keystoreResources: &keystore.Resources{
+					Hash:        "44",
+					RestartHash: "42",
+				},
+				scriptsContent: "scripts content",
+			},
+			expectedAnnotations: map[string]string{
+				"elasticsearch.k8s.elastic.co/config-hash": "4033676039",
+			},
+		},
 		{
 			name: "With another script version",
 			args: args{
 				keystoreResources: &keystore.Resources{
-					Hash: "42",
+					Hash:        "42",
+					RestartHash: "42",
 				},
 				scriptsContent: "another scripts content",
 			},
@@ -444,7 +470,7 @@ func Test_buildAnnotations(t *testing.T) {
 				build()
 			ver, err := version.Parse(sampleES.Spec.Version)
 			require.NoError(t, err)
-			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config, nil)
+			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.Auth, es.Spec.Auditing, *es.Spec.NodeSets[0].Config, nil, "", false, nil)
 			require.NoError(t, err)
 			got := buildAnnotations(es, cfg, tt.args.keystoreResources, tt.args.scriptsContent, tt.args.policyAnnotations)
 
@@ -541,7 +567,7 @@ func Test_enableLog4JFormatMsgNoLookups(t *testing.T) {
 
 			ver, err := version.Parse(sampleES.Spec.Version)
 			require.NoError(t, err)
-			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, *sampleES.Spec.NodeSets[0].Config, nil)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Auth, sampleES.Spec.Auditing, *sampleES.Spec.NodeSets[0].Config, nil, "", false, nil)
 			require.NoError(t, err)
 			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
 			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, PolicyConfig{})
@@ -0,0 +1,71 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+)
+
+const (
+	// PrometheusExporterContainerName is the name of the sidecar container serving the Elasticsearch metrics to
+	// Prometheus.
+	PrometheusExporterContainerName = "elasticsearch-exporter"
+	// DefaultPrometheusExporterImage is the image of the exporter sidecar if none is specified.
+	DefaultPrometheusExporterImage = "quay.io/prometheuscommunity/elasticsearch-exporter:v1.8.0"
+
+	// environment variables read by the exporter to authenticate to Elasticsearch
+	exporterUsernameEnvVar = "ES_USERNAME"
+	exporterPasswordEnvVar = "ES_PASSWORD"
+)
+
+func prometheusExporterContainers(es esv1.Elasticsearch) []corev1.Container {
+	prometheus := es.Spec.Monitoring.Prometheus
+	if prometheus == nil || prometheus.Exporter == nil {
+		return nil
+	}
+	return []corev1.Container{prometheusExporter(es)}
+}
+
+// prometheusExporter returns the sidecar container exposing the metrics of the local Elasticsearch node in the
+// Prometheus format, on the port scraped through the ServiceMonitor. It authenticates with the monitoring user.
+func prometheusExporter(es esv1.Elasticsearch) corev1.Container {
+	prometheus := es.Spec.Monitoring.Prometheus
+	image := prometheus.Exporter.Image
+	if image == "" {
+		image = DefaultPrometheusExporterImage
+	}
+	args := []string{
+		fmt.Sprintf("--es.uri=%s://localhost:%d", es.Spec.HTTP.Protocol(), network.HTTPPort),
+		fmt.Sprintf("--web.listen-address=:%d", prometheus.Port),
+		fmt.Sprintf("--web.telemetry-path=%s", prometheus.PathOrDefault()),
+	}
+	if es.Spec.HTTP.TLS.Enabled() {
+		// the certificate is not issued for localhost, the connection does not leave the Pod
+		args = append(args, "--es.ssl-skip-verify")
+	}
+	return corev1.Container{
+		Name:  PrometheusExporterContainerName,
+		Image: image,
+		Args:  args,
+		Env: []corev1.EnvVar{
+			{Name: exporterUsernameEnvVar, Value: user.MonitoringUserName},
+			{Name: exporterPasswordEnvVar, ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: esv1.InternalUsersSecret(es.Name)},
+					Key:                  user.MonitoringUserName,
+				},
+			}},
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "metrics", ContainerPort: prometheus.Port, Protocol: corev1.ProtocolTCP},
+		},
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_prometheusExporterContainers(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	require.Empty(t, prometheusExporterContainers(es))
+	// a ServiceMonitor without exporter scrapes a container provided by the user
+	es.Spec.Monitoring.Prometheus = &commonv1.PrometheusMonitoring{Port: 9114}
+	require.Empty(t, prometheusExporterContainers(es))
+
+	es.Spec.Monitoring.Prometheus.Exporter = &commonv1.PrometheusExporter{}
+	containers := prometheusExporterContainers(es)
+	require.Len(t, containers, 1)
+	exporter := containers[0]
+	assert.Equal(t, "elasticsearch-exporter", exporter.Name)
+	assert.Equal(t, DefaultPrometheusExporterImage, exporter.Image)
+	assert.Equal(t, []string{
+		"--es.uri=https://localhost:9200",
+		"--web.listen-address=:9114",
+		"--web.telemetry-path=/metrics",
+		"--es.ssl-skip-verify",
+	}, exporter.Args)
+	assert.Equal(t, []corev1.ContainerPort{{Name: "metrics", ContainerPort: 9114, Protocol: corev1.ProtocolTCP}}, exporter.Ports)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "ES_USERNAME", Value: "elastic-internal-monitoring"},
+		{Name: "ES_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "es-es-internal-users"},
+			Key:                  "elastic-internal-monitoring",
+		}}},
+	}, exporter.Env)
+
+	// custom image and path, TLS disabled
+	es.Spec.HTTP.TLS.SelfSignedCertificate = &commonv1.SelfSignedCertificate{Disabled: true}
+	es.Spec.Monitoring.Prometheus = &commonv1.PrometheusMonitoring{
+		Port:     9108,
+		Path:     "/custom",
+		Exporter: &commonv1.PrometheusExporter{Image: "my-registry/elasticsearch-exporter:latest"},
+	}
+	exporter = prometheusExporterContainers(es)[0]
+	assert.Equal(t, "my-registry/elasticsearch-exporter:latest", exporter.Image)
+	assert.Equal(t, []string{
+		"--es.uri=http://localhost:9200",
+		"--web.listen-address=:9108",
+		"--web.telemetry-path=/custom",
+	}, exporter.Args)
+}
@@ -23,7 +23,13 @@ const (
 	ReadinessPortProbeScriptConfigKey = "readiness-port-script.sh"
 	// ReadinessPortProbeScript is the simplified readiness check for ES >= 8.2.0 which supports a dedicated TCP check
 	ReadinessPortProbeScript = `#!/usr/bin/env bash
-nc -z -v -w5 127.0.0.1 8080
+# Check if we are using IPv6
+if [[ $POD_IP =~ .*:.* ]]; then
+  LOOPBACK=::1
+else
+  LOOPBACK=127.0.0.1
+fi
+nc -z -v -w5 ${LOOPBACK} 8080
 `
 )
 
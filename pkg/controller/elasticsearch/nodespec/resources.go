@@ -11,7 +11,6 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 
-	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
@@ -61,6 +60,7 @@ func BuildExpectedResources(
 	existingStatefulSets es_sset.StatefulSetList,
 	ipFamily corev1.IPFamily,
 	setDefaultSecurityContext bool,
+	tierStorageClasses map[esv1.NodeRole]string,
 ) (ResourcesList, error) {
 	nodesResources := make(ResourcesList, 0, len(es.Spec.NodeSets))
 
@@ -75,30 +75,34 @@ func BuildExpectedResources(
 		return nil, err
 	}
 
-	for _, nodeSpec := range es.Spec.NodeSets {
-		// build es config
-		userCfg := commonv1.Config{}
-		if nodeSpec.Config != nil {
-			userCfg = *nodeSpec.Config
-		}
-		cfg, err := settings.NewMergedESConfig(es.Name, ver, ipFamily, es.Spec.HTTP, userCfg, policyConfig.ElasticsearchConfig)
-		if err != nil {
-			return nil, err
-		}
+	zoneAware := es.Spec.ZoneAware()
+	for _, specNodeSet := range es.Spec.NodeSets {
+		// zone-aware NodeSets are made of one StatefulSet per zone
+		for _, nodeSpec := range specNodeSet.ExpandZones() {
+			// build es config
+			userCfg, err := nodeSetConfig(nodeSpec)
+			if err != nil {
+				return nil, err
+			}
+			cfg, err := settings.NewMergedESConfig(es.Name, ver, ipFamily, es.Spec.HTTP, es.Spec.Auth, es.Spec.Auditing, userCfg, nodeSpec.AdditionalVolumes, nodeSpec.Zone(), zoneAware, policyConfig.ElasticsearchConfig)
+			if err != nil {
+				return nil, err
+			}
 
-		// build stateful set and associated headless service
-		statefulSet, err := BuildStatefulSet(ctx, client, es, nodeSpec, cfg, keystoreResources, existingStatefulSets, setDefaultSecurityContext, policyConfig)
-		if err != nil {
-			return nil, err
+			// build stateful set and associated headless service
+			statefulSet, err := BuildStatefulSet(ctx, client, es, nodeSpec, cfg, keystoreResources, existingStatefulSets, setDefaultSecurityContext, tierStorageClasses, policyConfig)
+			if err != nil {
+				return nil, err
+			}
+			headlessSvc := HeadlessService(&es, statefulSet.Name)
+
+			nodesResources = append(nodesResources, Resources{
+				NodeSet:         specNodeSet.Name,
+				StatefulSet:     statefulSet,
+				HeadlessService: headlessSvc,
+				Config:          cfg,
+			})
 		}
-		headlessSvc := HeadlessService(&es, statefulSet.Name)
-
-		nodesResources = append(nodesResources, Resources{
-			NodeSet:         nodeSpec.Name,
-			StatefulSet:     statefulSet,
-			HeadlessService: headlessSvc,
-			Config:          cfg,
-		})
 	}
 
 	return nodesResources, nil
@@ -10,12 +10,14 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
@@ -66,19 +68,25 @@ func BuildStatefulSet(
 	keystoreResources *keystore.Resources,
 	existingStatefulSets es_sset.StatefulSetList,
 	setDefaultSecurityContext bool,
+	tierStorageClasses map[esv1.NodeRole]string,
 	policyConfig PolicyConfig,
 ) (appsv1.StatefulSet, error) {
-	statefulSetName := esv1.StatefulSet(es.Name, nodeSet.Name)
+	statefulSetName := es.StatefulSetName(nodeSet.Name)
 
 	// ssetSelector is used to match the sset pods
 	ssetSelector := label.NewStatefulSetLabels(k8s.ExtractNamespacedName(&es), statefulSetName)
 
-	// add default PVCs to the node spec only if no user defined PVCs exist
-	nodeSet.VolumeClaimTemplates = defaults.AppendDefaultPVCs(
-		nodeSet.VolumeClaimTemplates,
-		nodeSet.PodTemplate.Spec,
-		esvolume.DefaultVolumeClaimTemplates...,
-	)
+	nodeSet = withFrozenCacheVolume(nodeSet)
+
+	// add default PVCs to the node spec only if no user defined PVCs exist,
+	// and the data is not stored in an emptyDir volume
+	if nodeSet.EphemeralStorage == nil {
+		nodeSet.VolumeClaimTemplates = defaults.AppendDefaultPVCs(
+			nodeSet.VolumeClaimTemplates,
+			nodeSet.PodTemplate.Spec,
+			esvolume.DefaultVolumeClaimTemplates...,
+		)
+	}
 
 	// build pod template
 	podTemplate, err := BuildPodTemplateSpec(ctx, client, es, nodeSet, cfg, keystoreResources, setDefaultSecurityContext, policyConfig)
@@ -99,6 +107,17 @@ func BuildStatefulSet(
 		existingClaims = existingSset.Spec.VolumeClaimTemplates
 	}
 	claims := preserveExistingVolumeClaimsOwnerRefs(nodeSet.VolumeClaimTemplates, existingClaims)
+	if len(tierStorageClasses) > 0 {
+		ver, err := version.Parse(es.Spec.Version)
+		if err != nil {
+			return appsv1.StatefulSet{}, err
+		}
+		unpackedCfg, err := cfg.Unpack(ver)
+		if err != nil {
+			return appsv1.StatefulSet{}, err
+		}
+		claims = withTierStorageClass(claims, existingClaims, unpackedCfg.Node, tierStorageClasses)
+	}
 
 	sset := appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -157,6 +176,51 @@ func preserveExistingVolumeClaimsOwnerRefs(
 	return claims
 }
 
+// tierStorageClassOrder is the order in which the storage classes of the data tiers apply to nodes holding several
+// tiers: the storage class of the hottest tier is used.
+var tierStorageClassOrder = []esv1.NodeRole{
+	esv1.DataHotRole, esv1.DataContentRole, esv1.DataWarmRole, esv1.DataColdRole, esv1.DataFrozenRole,
+}
+
+// withTierStorageClass sets the storage class mapped to the data tier of the nodes on the claims that do not specify
+// any. Claims already existing in the StatefulSet keep their storage class, since the volume claim templates of a
+// StatefulSet cannot be updated: changes to the mapping only apply to new nodeSets.
+func withTierStorageClass(
+	claims []corev1.PersistentVolumeClaim,
+	existingClaims []corev1.PersistentVolumeClaim,
+	node *esv1.Node,
+	tierStorageClasses map[esv1.NodeRole]string,
+) []corev1.PersistentVolumeClaim {
+	storageClass := tierStorageClass(node, tierStorageClasses)
+	if storageClass == "" {
+		return claims
+	}
+	result := make([]corev1.PersistentVolumeClaim, 0, len(claims))
+	for _, claim := range claims {
+		if claim.Spec.StorageClassName == nil {
+			if existingClaim := sset.GetClaim(existingClaims, claim.Name); existingClaim != nil {
+				claim.Spec.StorageClassName = existingClaim.Spec.StorageClassName
+			} else {
+				claim.Spec.StorageClassName = ptr.To(storageClass)
+			}
+		}
+		result = append(result, claim)
+	}
+	return result
+}
+
+// tierStorageClass returns the storage class mapped to the hottest data tier of the given node, or an empty string if
+// there is none. Nodes with the generic data role hold all the tiers.
+func tierStorageClass(node *esv1.Node, tierStorageClasses map[esv1.NodeRole]string) string {
+	allTiers := node.IsConfiguredWithRole(esv1.DataRole)
+	for _, role := range tierStorageClassOrder {
+		if storageClass, exists := tierStorageClasses[role]; exists && (allTiers || node.IsConfiguredWithRole(role)) {
+			return storageClass
+		}
+	}
+	return ""
+}
+
 // UpdateReplicas updates the given StatefulSet with the given replicas,
 // and modifies the template hash label accordingly.
 func UpdateReplicas(statefulSet *appsv1.StatefulSet, replicas *int32) {
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
@@ -133,3 +134,76 @@ func Test_setVolumeClaimsControllerReference(t *testing.T) {
 		})
 	}
 }
+
+func Test_withTierStorageClass(t *testing.T) {
+	tierStorageClasses := map[esv1.NodeRole]string{
+		esv1.DataHotRole:    "nvme",
+		esv1.DataWarmRole:   "standard",
+		esv1.DataFrozenRole: "cheap",
+	}
+	claim := func(name string, storageClass *string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: storageClass},
+		}
+	}
+	withRoles := func(roles ...string) *esv1.Node {
+		return &esv1.Node{Roles: roles}
+	}
+	tests := []struct {
+		name           string
+		claims         []corev1.PersistentVolumeClaim
+		existingClaims []corev1.PersistentVolumeClaim
+		node           *esv1.Node
+		want           []corev1.PersistentVolumeClaim
+	}{
+		{
+			name:   "warm nodes",
+			claims: []corev1.PersistentVolumeClaim{claim("elasticsearch-data", nil)},
+			node:   withRoles("data_warm"),
+			want:   []corev1.PersistentVolumeClaim{claim("elasticsearch-data", ptr.To("standard"))},
+		},
+		{
+			name:   "hottest tier wins",
+			claims: []corev1.PersistentVolumeClaim{claim("elasticsearch-data", nil)},
+			node:   withRoles("data_frozen", "data_warm"),
+			want:   []corev1.PersistentVolumeClaim{claim("elasticsearch-data", ptr.To("standard"))},
+		},
+		{
+			name:   "nodes with the default roles hold all the tiers",
+			claims: []corev1.PersistentVolumeClaim{claim("elasticsearch-data", nil)},
+			node:   nil,
+			want:   []corev1.PersistentVolumeClaim{claim("elasticsearch-data", ptr.To("nvme"))},
+		},
+		{
+			name:   "nodes with the generic data role hold all the tiers",
+			claims: []corev1.PersistentVolumeClaim{claim("elasticsearch-data", nil)},
+			node:   withRoles("master", "data"),
+			want:   []corev1.PersistentVolumeClaim{claim("elasticsearch-data", ptr.To("nvme"))},
+		},
+		{
+			name:   "unmapped tier",
+			claims: []corev1.PersistentVolumeClaim{claim("elasticsearch-data", nil)},
+			node:   withRoles("data_cold"),
+			want:   []corev1.PersistentVolumeClaim{claim("elasticsearch-data", nil)},
+		},
+		{
+			name:   "storage class set by the user",
+			claims: []corev1.PersistentVolumeClaim{claim("elasticsearch-data", ptr.To("custom"))},
+			node:   withRoles("data_hot"),
+			want:   []corev1.PersistentVolumeClaim{claim("elasticsearch-data", ptr.To("custom"))},
+		},
+		{
+			name:           "existing claims keep their storage class",
+			claims:         []corev1.PersistentVolumeClaim{claim("elasticsearch-data", nil), claim("disk-2", nil)},
+			existingClaims: []corev1.PersistentVolumeClaim{claim("elasticsearch-data", nil)},
+			node:           withRoles("data_hot"),
+			want:           []corev1.PersistentVolumeClaim{claim("elasticsearch-data", nil), claim("disk-2", ptr.To("nvme"))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, withTierStorageClass(tt.claims, tt.existingClaims, tt.node, tierStorageClasses))
+		})
+	}
+}
@@ -5,6 +5,8 @@
 package nodespec
 
 import (
+	"slices"
+
 	corev1 "k8s.io/api/core/v1"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -21,13 +23,17 @@ import (
 
 func buildVolumes(
 	esName string,
+	ssetName string,
+	transportCertificatesShards int32,
 	version version.Version,
 	nodeSpec esv1.NodeSet,
+	auth esv1.Auth,
 	keystoreResources *keystore.Resources,
+	keystorePassword *esv1.SecretKeyRef,
 	downwardAPIVolume volume.DownwardAPI,
 	additionalMountsFromPolicy []volume.VolumeLike,
 ) ([]corev1.Volume, []corev1.VolumeMount) {
-	configVolume := settings.ConfigSecretVolume(esv1.StatefulSet(esName, nodeSpec.Name))
+	configVolume := settings.ConfigSecretVolume(ssetName)
 	probeSecret := volume.NewSelectiveSecretVolumeWithMountPath(
 		esv1.InternalUsersSecret(esName), esvolume.ProbeUserVolumeName,
 		esvolume.PodMountedUsersSecretMountPath, []string{user.ProbeUserName, user.PreStopUserName},
@@ -37,7 +43,7 @@ func buildVolumes(
 		esvolume.HTTPCertificatesSecretVolumeName,
 		esvolume.HTTPCertificatesSecretVolumeMountPath,
 	)
-	transportCertificatesVolume := transportCertificatesVolume(esv1.StatefulSet(esName, nodeSpec.Name))
+	transportCertificatesVolume := transportCertificatesVolume(ssetName, transportCertificatesShards)
 	remoteCertificateAuthoritiesVolume := volume.NewSecretVolumeWithMountPath(
 		esv1.RemoteCaSecretName(esName),
 		esvolume.RemoteCertificateAuthoritiesSecretVolumeName,
@@ -78,6 +84,10 @@ func buildVolumes(
 			},
 		})
 	}
+	// or store the data in an emptyDir or CSI ephemeral volume for nodeSets using ephemeral storage
+	if nodeSpec.EphemeralStorage != nil {
+		persistentVolumes = append(persistentVolumes, ephemeralDataVolume(*nodeSpec.EphemeralStorage))
+	}
 
 	volumes := persistentVolumes
 	volumes = append(
@@ -121,14 +131,104 @@ func buildVolumes(
 		volumeMounts = append(volumeMounts, fileSettingsVolume.VolumeMount())
 	}
 
+	// password of the keystore
+	if keystorePassword != nil {
+		keystorePasswordVolume := keystorePasswordVolume(*keystorePassword)
+		volumes = append(volumes, keystorePasswordVolume.Volume())
+		volumeMounts = append(volumeMounts, keystorePasswordVolume.VolumeMount())
+	}
+
+	// metadata of the identity providers of the SAML realms
+	for _, volume := range samlMetadataVolumes(auth) {
+		volumes = append(volumes, volume.Volume())
+		volumeMounts = append(volumeMounts, volume.VolumeMount())
+	}
+
+	// heap dumps and GC logs of the JVM
+	if nodeSpec.JVMDiagnostics != nil {
+		volumes, volumeMounts = withJVMDiagnosticsVolume(volumes, volumeMounts, *nodeSpec.JVMDiagnostics)
+	}
+
 	// additional volumes from stack config policy
 	for _, volume := range additionalMountsFromPolicy {
 		volumes = append(volumes, volume.Volume())
 		volumeMounts = append(volumeMounts, volume.VolumeMount())
 	}
 
+	// mount the additional volumes of the nodeSet, in place of the default volumes mounted at the same path
+	volumes, volumeMounts = withAdditionalVolumes(volumes, volumeMounts, nodeSpec.AdditionalVolumes)
+
 	// include the user-provided PodTemplate volumes as the user may have defined the data volume there (e.g.: emptyDir or hostpath volume)
 	volumeMounts = esvolume.AppendDefaultDataVolumeMount(volumeMounts, append(volumes, nodeSpec.PodTemplate.Spec.Volumes...))
 
 	return volumes, volumeMounts
 }
+
+// keystorePasswordVolume returns the volume of the Secret holding the password of the keystore.
+func keystorePasswordVolume(password esv1.SecretKeyRef) volume.SecretVolume {
+	return volume.NewSelectiveSecretVolumeWithMountPath(
+		password.SecretName,
+		esvolume.KeystorePasswordVolumeName,
+		esvolume.KeystorePasswordVolumeMountPath,
+		[]string{password.Key},
+	)
+}
+
+// samlMetadataVolumes returns the volumes of the Secrets holding the metadata of the identity providers of the SAML
+// realms that do not download it from a URL.
+func samlMetadataVolumes(auth esv1.Auth) []volume.SecretVolume {
+	var volumes []volume.SecretVolume
+	for _, realm := range auth.SAML {
+		if realm.IdPMetadata.Secret == nil {
+			continue
+		}
+		volumes = append(volumes, volume.NewSelectiveSecretVolumeWithMountPath(
+			realm.IdPMetadata.Secret.SecretName,
+			esvolume.SAMLMetadataVolumeNamePrefix+realm.Name,
+			esvolume.SAMLMetadataMountPath(realm.Name),
+			[]string{realm.IdPMetadata.Secret.Key},
+		))
+	}
+	return volumes
+}
+
+// ephemeralDataVolume returns the emptyDir or CSI ephemeral data volume of a nodeSet using ephemeral storage.
+func ephemeralDataVolume(storage esv1.EphemeralStorage) corev1.Volume {
+	if storage.CSI != nil {
+		return corev1.Volume{
+			Name:         esvolume.ElasticsearchDataVolumeName,
+			VolumeSource: corev1.VolumeSource{CSI: storage.CSI.DeepCopy()},
+		}
+	}
+	return corev1.Volume{
+		Name: esvolume.ElasticsearchDataVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium:    storage.Medium,
+				SizeLimit: storage.SizeLimit,
+			},
+		},
+	}
+}
+
+// withAdditionalVolumes mounts the given additional volumes, backed by the volumes of the volume claim templates, and
+// removes the default volumes they replace.
+func withAdditionalVolumes(
+	volumes []corev1.Volume,
+	volumeMounts []corev1.VolumeMount,
+	additionalVolumes []esv1.AdditionalVolume,
+) ([]corev1.Volume, []corev1.VolumeMount) {
+	for _, additionalVolume := range additionalVolumes {
+		mountPath := esvolume.AdditionalVolumeMountPath(additionalVolume)
+		for _, mount := range volumeMounts {
+			if mount.MountPath == mountPath {
+				volumes = slices.DeleteFunc(volumes, func(v corev1.Volume) bool {
+					return v.Name == mount.Name && v.PersistentVolumeClaim == nil
+				})
+			}
+		}
+		volumeMounts = slices.DeleteFunc(volumeMounts, func(m corev1.VolumeMount) bool { return m.MountPath == mountPath })
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: additionalVolume.Name, MountPath: mountPath})
+	}
+	return volumes, volumeMounts
+}
@@ -5,9 +5,11 @@
 package nodespec
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -86,16 +88,146 @@ func Test_BuildVolumes_DataVolumeMountPath(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with ephemeral storage",
+			nodeSpec: esv1.NodeSet{
+				EphemeralStorage: &esv1.EphemeralStorage{},
+			},
+		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			_, volumeMounts := buildVolumes("esname", version.MustParse("8.8.0"), tc.nodeSpec, nil, volume.DownwardAPI{}, []volume.VolumeLike{})
+			_, volumeMounts := buildVolumes("esname", "esname-es-default", 1, version.MustParse("8.8.0"), tc.nodeSpec, esv1.Auth{}, nil, nil, volume.DownwardAPI{}, []volume.VolumeLike{})
 			assert.True(t, contains(volumeMounts, "elasticsearch-data", "/usr/share/elasticsearch/data"))
 		})
 	}
 }
 
+func Test_BuildVolumes_EphemeralStorage(t *testing.T) {
+	sizeLimit := resource.MustParse("10Gi")
+	nodeSpec := esv1.NodeSet{
+		EphemeralStorage: &esv1.EphemeralStorage{SizeLimit: &sizeLimit, Medium: corev1.StorageMediumMemory},
+	}
+	volumes, _ := buildVolumes("esname", "esname-es-default", 1, version.MustParse("8.8.0"), nodeSpec, esv1.Auth{}, nil, nil, volume.DownwardAPI{}, []volume.VolumeLike{})
+	var dataVolumes []corev1.Volume
+	for _, v := range volumes {
+		if v.Name == esvolume.ElasticsearchDataVolumeName {
+			dataVolumes = append(dataVolumes, v)
+		}
+	}
+	assert.Equal(t, []corev1.Volume{{
+		Name: esvolume.ElasticsearchDataVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory, SizeLimit: &sizeLimit},
+		},
+	}}, dataVolumes)
+}
+
+func Test_BuildVolumes_EphemeralStorageCSI(t *testing.T) {
+	csi := &corev1.CSIVolumeSource{Driver: "local.csi.example.com", VolumeAttributes: map[string]string{"size": "100Gi"}}
+	nodeSpec := esv1.NodeSet{EphemeralStorage: &esv1.EphemeralStorage{CSI: csi}}
+	volumes, volumeMounts := buildVolumes("esname", "esname-es-default", 1, version.MustParse("8.8.0"), nodeSpec, esv1.Auth{}, nil, nil, volume.DownwardAPI{}, []volume.VolumeLike{})
+	var dataVolumes []corev1.Volume
+	for _, v := range volumes {
+		if v.Name == esvolume.ElasticsearchDataVolumeName {
+			dataVolumes = append(dataVolumes, v)
+		}
+	}
+	assert.Equal(t, []corev1.Volume{{
+		Name:         esvolume.ElasticsearchDataVolumeName,
+		VolumeSource: corev1.VolumeSource{CSI: csi},
+	}}, dataVolumes)
+	assert.True(t, contains(volumeMounts, "elasticsearch-data", "/usr/share/elasticsearch/data"))
+}
+
+func Test_BuildVolumes_AdditionalVolumes(t *testing.T) {
+	claim := func(name string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	nodeSpec := esv1.NodeSet{
+		VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claim("elasticsearch-data"), claim("disk-1"), claim("logs"), claim("tmp")},
+		AdditionalVolumes: []esv1.AdditionalVolume{
+			{Name: "disk-1", Usage: esv1.DataVolumeUsage},
+			{Name: "logs", Usage: esv1.LogsVolumeUsage},
+			{Name: "tmp", Usage: esv1.TempVolumeUsage},
+		},
+	}
+	volumes, volumeMounts := buildVolumes("esname", "esname-es-default", 1, version.MustParse("7.17.0"), nodeSpec, esv1.Auth{}, nil, nil, volume.DownwardAPI{}, []volume.VolumeLike{})
+
+	assert.True(t, contains(volumeMounts, "elasticsearch-data", "/usr/share/elasticsearch/data"))
+	assert.True(t, contains(volumeMounts, "disk-1", "/usr/share/elasticsearch/data-volumes/disk-1"))
+	assert.True(t, contains(volumeMounts, "logs", "/usr/share/elasticsearch/logs"))
+	assert.True(t, contains(volumeMounts, "tmp", "/tmp"))
+	// the default logs and temp volumes are replaced
+	for _, m := range volumeMounts {
+		assert.NotContains(t, []string{esvolume.ElasticsearchLogsVolumeName, esvolume.TempVolumeName}, m.Name)
+	}
+	for _, v := range volumes {
+		assert.NotContains(t, []string{esvolume.ElasticsearchLogsVolumeName, esvolume.TempVolumeName}, v.Name)
+	}
+}
+
+func Test_BuildVolumes_SAMLMetadata(t *testing.T) {
+	auth := esv1.Auth{SAML: []esv1.SAMLRealm{
+		{SSORealm: esv1.SSORealm{Name: "okta"}, IdPMetadata: esv1.SAMLMetadataSource{Secret: &esv1.SecretKeyRef{SecretName: "okta-metadata", Key: "metadata.xml"}}},
+		{SSORealm: esv1.SSORealm{Name: "azure"}, IdPMetadata: esv1.SAMLMetadataSource{URL: "https://login.microsoftonline.com/metadata.xml"}},
+	}}
+	volumes, volumeMounts := buildVolumes("esname", "esname-es-default", 1, version.MustParse("8.15.0"), esv1.NodeSet{}, auth, nil, nil, volume.DownwardAPI{}, []volume.VolumeLike{})
+
+	// only the metadata read from a Secret is mounted
+	assert.True(t, contains(volumeMounts, "elastic-internal-saml-okta", "/usr/share/elasticsearch/config/saml/okta"))
+	var samlVolumes []corev1.Volume
+	for _, v := range volumes {
+		if strings.HasPrefix(v.Name, esvolume.SAMLMetadataVolumeNamePrefix) {
+			samlVolumes = append(samlVolumes, v)
+		}
+	}
+	require.Len(t, samlVolumes, 1)
+	assert.Equal(t, "okta-metadata", samlVolumes[0].Secret.SecretName)
+	assert.Equal(t, []corev1.KeyToPath{{Key: "metadata.xml", Path: "metadata.xml"}}, samlVolumes[0].Secret.Items)
+}
+
+func Test_BuildVolumes_KeystorePassword(t *testing.T) {
+	password := &esv1.SecretKeyRef{SecretName: "keystore-password", Key: "password"}
+	volumes, volumeMounts := buildVolumes("esname", "esname-es-default", 1, version.MustParse("8.15.0"), esv1.NodeSet{}, esv1.Auth{}, nil, password, volume.DownwardAPI{}, []volume.VolumeLike{})
+
+	assert.True(t, contains(volumeMounts, "elastic-internal-keystore-password", "/mnt/elastic-internal/keystore-password"))
+	var passwordVolumes []corev1.Volume
+	for _, v := range volumes {
+		if v.Name == esvolume.KeystorePasswordVolumeName {
+			passwordVolumes = append(passwordVolumes, v)
+		}
+	}
+	require.Len(t, passwordVolumes, 1)
+	assert.Equal(t, "keystore-password", passwordVolumes[0].Secret.SecretName)
+	assert.Equal(t, []corev1.KeyToPath{{Key: "password", Path: "password"}}, passwordVolumes[0].Secret.Items)
+}
+
+func Test_BuildVolumes_JVMDiagnostics(t *testing.T) {
+	sizeLimit := resource.MustParse("4Gi")
+	nodeSpec := esv1.NodeSet{JVMDiagnostics: &esv1.JVMDiagnostics{SizeLimit: &sizeLimit}}
+	volumes, volumeMounts := buildVolumes("esname", "esname-es-default", 1, version.MustParse("8.15.0"), nodeSpec, esv1.Auth{}, nil, nil, volume.DownwardAPI{}, []volume.VolumeLike{})
+
+	assert.True(t, contains(volumeMounts, "jvm-diagnostics", "/usr/share/elasticsearch/jvm-diagnostics"))
+	assert.Contains(t, volumes, corev1.Volume{
+		Name:         esvolume.JVMDiagnosticsVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &sizeLimit}},
+	})
+
+	// the volume of a claim is mounted in place of the emptyDir volume
+	nodeSpec = esv1.NodeSet{
+		VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "dumps"}}},
+		JVMDiagnostics:       &esv1.JVMDiagnostics{VolumeClaimTemplate: "dumps"},
+	}
+	volumes, volumeMounts = buildVolumes("esname", "esname-es-default", 1, version.MustParse("8.15.0"), nodeSpec, esv1.Auth{}, nil, nil, volume.DownwardAPI{}, []volume.VolumeLike{})
+
+	assert.True(t, contains(volumeMounts, "dumps", "/usr/share/elasticsearch/jvm-diagnostics"))
+	for _, v := range volumes {
+		assert.NotEqual(t, esvolume.JVMDiagnosticsVolumeName, v.Name)
+	}
+}
+
 func contains(volumeMounts []corev1.VolumeMount, volumeMountName, volumeMountPath string) bool {
 	for _, vm := range volumeMounts {
 		if vm.Name == volumeMountName && vm.MountPath == volumeMountPath {
@@ -104,3 +236,37 @@ func contains(volumeMounts []corev1.VolumeMount, volumeMountName, volumeMountPat
 	}
 	return false
 }
+
+func Test_BuildVolumes_TransportCertificatesShards(t *testing.T) {
+	transportCertsVolume := func(volumes []corev1.Volume) corev1.Volume {
+		for _, v := range volumes {
+			if v.Name == esvolume.TransportCertificatesSecretVolumeName {
+				return v
+			}
+		}
+		t.Fatalf("transport certificates volume not found")
+		return corev1.Volume{}
+	}
+
+	// a single Secret is mounted by default
+	volumes, volumeMounts := buildVolumes("esname", "esname-es-default", 1, version.MustParse("8.15.0"), esv1.NodeSet{}, esv1.Auth{}, nil, nil, volume.DownwardAPI{}, []volume.VolumeLike{})
+	assert.True(t, contains(volumeMounts, esvolume.TransportCertificatesSecretVolumeName, esvolume.TransportCertificatesSecretVolumeMountPath))
+	v := transportCertsVolume(volumes)
+	require.NotNil(t, v.Secret)
+	assert.Equal(t, "esname-es-default-es-transport-certs", v.Secret.SecretName)
+
+	// all the shards are projected into the same volume otherwise
+	volumes, volumeMounts = buildVolumes("esname", "esname-es-default", 3, version.MustParse("8.15.0"), esv1.NodeSet{}, esv1.Auth{}, nil, nil, volume.DownwardAPI{}, []volume.VolumeLike{})
+	assert.True(t, contains(volumeMounts, esvolume.TransportCertificatesSecretVolumeName, esvolume.TransportCertificatesSecretVolumeMountPath))
+	v = transportCertsVolume(volumes)
+	require.NotNil(t, v.Projected)
+	var secretNames []string
+	for _, source := range v.Projected.Sources {
+		secretNames = append(secretNames, source.Secret.Name)
+	}
+	assert.Equal(t, []string{
+		"esname-es-default-es-transport-certs",
+		"esname-es-default-es-transport-certs-1",
+		"esname-es-default-es-transport-certs-2",
+	}, secretNames)
+}
@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"context"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/snapshotrepository"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	awsWebIdentityTokenVolumeName = "elastic-internal-aws-web-identity-token"
+	awsWebIdentityTokenAudience   = "sts.amazonaws.com"
+	// the repository-s3 module only reads the token from its own directory in the Elasticsearch configuration
+	awsWebIdentityTokenMountPath = esvolume.ConfigVolumeMountPath + "/repository-s3"
+	awsWebIdentityTokenFile      = "aws-web-identity-token-file"
+
+	azureIdentityTokenVolumeName = "elastic-internal-azure-identity-token"
+	azureIdentityTokenAudience   = "api://AzureADTokenExchange"
+	azureIdentityTokenMountPath  = esvolume.ConfigVolumeMountPath + "/azure/tokens"
+	azureIdentityTokenFile       = "azure-identity-token"
+
+	identityTokenExpirationSeconds = 86400
+)
+
+// withWorkloadIdentity runs the Elasticsearch pods with the service account of the workload identity of the
+// SnapshotRepository resources registered on the cluster, and mounts the tokens the repository plugins exchange
+// for cloud credentials. On GKE the service account annotation is enough, credentials come from the metadata server.
+func withWorkloadIdentity(ctx context.Context, client k8s.Client, builder *defaults.PodTemplateBuilder, es esv1.Elasticsearch) (*defaults.PodTemplateBuilder, error) {
+	identity, err := snapshotrepository.WorkloadIdentityFor(ctx, client, es)
+	if err != nil {
+		return nil, err
+	}
+	if identity == nil {
+		return builder, nil
+	}
+
+	builder = builder.WithServiceAccount(identity.ServiceAccountName)
+	if identity.AWSRoleARN != "" {
+		builder = builder.
+			WithVolumes(identityTokenVolume(awsWebIdentityTokenVolumeName, awsWebIdentityTokenAudience, awsWebIdentityTokenFile)).
+			WithVolumeMounts(corev1.VolumeMount{Name: awsWebIdentityTokenVolumeName, MountPath: awsWebIdentityTokenMountPath, ReadOnly: true}).
+			WithEnv(
+				corev1.EnvVar{Name: "AWS_ROLE_ARN", Value: identity.AWSRoleARN},
+				corev1.EnvVar{Name: "AWS_WEB_IDENTITY_TOKEN_FILE", Value: filepath.Join(awsWebIdentityTokenMountPath, awsWebIdentityTokenFile)},
+			)
+	}
+	if identity.AzureClientID != "" {
+		builder = builder.
+			WithVolumes(identityTokenVolume(azureIdentityTokenVolumeName, azureIdentityTokenAudience, azureIdentityTokenFile)).
+			WithVolumeMounts(corev1.VolumeMount{Name: azureIdentityTokenVolumeName, MountPath: azureIdentityTokenMountPath, ReadOnly: true}).
+			WithEnv(
+				corev1.EnvVar{Name: "AZURE_CLIENT_ID", Value: identity.AzureClientID},
+				corev1.EnvVar{Name: "AZURE_TENANT_ID", Value: identity.AzureTenantID},
+				corev1.EnvVar{Name: "AZURE_FEDERATED_TOKEN_FILE", Value: filepath.Join(azureIdentityTokenMountPath, azureIdentityTokenFile)},
+			)
+	}
+	return builder, nil
+}
+
+// identityTokenVolume is a projected service account token for the given audience.
+func identityTokenVolume(name, audience, path string) corev1.Volume {
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+						Audience:          audience,
+						ExpirationSeconds: ptr.To[int64](identityTokenExpirationSeconds),
+						Path:              path,
+					},
+				}},
+			},
+		},
+	}
+}
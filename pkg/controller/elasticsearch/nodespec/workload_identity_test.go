@@ -0,0 +1,86 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_withWorkloadIdentity(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	repository := func(identity snapshotv1alpha1.WorkloadIdentity) *snapshotv1alpha1.SnapshotRepository {
+		return &snapshotv1alpha1.SnapshotRepository{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "repository"},
+			Spec: snapshotv1alpha1.SnapshotRepositorySpec{
+				ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+				WorkloadIdentity:  &identity,
+			},
+		}
+	}
+	tests := []struct {
+		name               string
+		client             k8s.Client
+		wantServiceAccount string
+		wantEnv            []corev1.EnvVar
+		wantVolumeMounts   []corev1.VolumeMount
+	}{
+		{
+			name:   "no workload identity",
+			client: k8s.NewFakeClient(),
+		},
+		{
+			name:               "GKE",
+			client:             k8s.NewFakeClient(repository(snapshotv1alpha1.WorkloadIdentity{ServiceAccountName: "snapshots", GCPServiceAccount: "snapshots@project.iam.gserviceaccount.com"})),
+			wantServiceAccount: "snapshots",
+		},
+		{
+			name:               "EKS",
+			client:             k8s.NewFakeClient(repository(snapshotv1alpha1.WorkloadIdentity{ServiceAccountName: "snapshots", AWSRoleARN: "arn:aws:iam::123456789012:role/snapshots"})),
+			wantServiceAccount: "snapshots",
+			wantEnv: []corev1.EnvVar{
+				{Name: "AWS_ROLE_ARN", Value: "arn:aws:iam::123456789012:role/snapshots"},
+				{Name: "AWS_WEB_IDENTITY_TOKEN_FILE", Value: "/usr/share/elasticsearch/config/repository-s3/aws-web-identity-token-file"},
+			},
+			wantVolumeMounts: []corev1.VolumeMount{
+				{Name: awsWebIdentityTokenVolumeName, MountPath: "/usr/share/elasticsearch/config/repository-s3", ReadOnly: true},
+			},
+		},
+		{
+			name:               "AKS",
+			client:             k8s.NewFakeClient(repository(snapshotv1alpha1.WorkloadIdentity{ServiceAccountName: "snapshots", AzureClientID: "client", AzureTenantID: "tenant"})),
+			wantServiceAccount: "snapshots",
+			wantEnv: []corev1.EnvVar{
+				{Name: "AZURE_CLIENT_ID", Value: "client"},
+				{Name: "AZURE_TENANT_ID", Value: "tenant"},
+				{Name: "AZURE_FEDERATED_TOKEN_FILE", Value: "/usr/share/elasticsearch/config/azure/tokens/azure-identity-token"},
+			},
+			wantVolumeMounts: []corev1.VolumeMount{
+				{Name: azureIdentityTokenVolumeName, MountPath: "/usr/share/elasticsearch/config/azure/tokens", ReadOnly: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := defaults.NewPodTemplateBuilder(corev1.PodTemplateSpec{}, esv1.ElasticsearchContainerName)
+			builder, err := withWorkloadIdentity(context.Background(), tt.client, builder, es)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantServiceAccount, builder.PodTemplate.Spec.ServiceAccountName)
+			require.Equal(t, tt.wantEnv, builder.MainContainer().Env)
+			require.Equal(t, tt.wantVolumeMounts, builder.MainContainer().VolumeMounts)
+			require.Len(t, builder.PodTemplate.Spec.Volumes, len(tt.wantVolumeMounts))
+		})
+	}
+}
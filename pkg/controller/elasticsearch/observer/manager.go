@@ -22,6 +22,13 @@ import (
 const (
 	// ObserverIntervalAnnotation is the name of the annotation used to set the observation interval for a cluster.
 	ObserverIntervalAnnotation = "eck.k8s.elastic.co/es-observer-interval"
+	// ObserverMaxIntervalAnnotation is the name of the annotation used to set the observation interval for a cluster
+	// whose health and state are stable.
+	ObserverMaxIntervalAnnotation = "eck.k8s.elastic.co/es-observer-max-interval"
+
+	// defaultMaxIntervalFactor is the factor applied to the observation interval of a cluster to get the default
+	// observation interval when it is stable.
+	defaultMaxIntervalFactor = 3
 )
 
 // Manager for a set of observers
@@ -32,6 +39,7 @@ type Manager struct {
 	listenerLock    sync.RWMutex
 	listeners       []OnObservation // invoked on each observation event
 	tracer          *apm.Tracer
+	pool            *workerPool // runs the asynchronous observations of all the clusters
 }
 
 // NewManager returns a new manager
@@ -40,6 +48,7 @@ func NewManager(defaultInterval time.Duration, tracer *apm.Tracer) *Manager {
 		defaultInterval: defaultInterval,
 		observers:       make(map[types.NamespacedName]*Observer),
 		tracer:          tracer,
+		pool:            newWorkerPool(maxConcurrentObservations),
 	}
 }
 
@@ -107,9 +116,11 @@ func (m *Manager) Observe(ctx context.Context, cluster esv1.Elasticsearch, esCli
 
 // extractObserverSettings extracts observer settings from the annotations on the Elasticsearch resource.
 func (m *Manager) extractObserverSettings(ctx context.Context, cluster esv1.Elasticsearch) Settings {
+	interval := annotation.ExtractTimeout(ctx, cluster.ObjectMeta, ObserverIntervalAnnotation, m.defaultInterval)
 	return Settings{
-		ObservationInterval: annotation.ExtractTimeout(ctx, cluster.ObjectMeta, ObserverIntervalAnnotation, m.defaultInterval),
-		Tracer:              m.tracer,
+		ObservationInterval:    interval,
+		MaxObservationInterval: annotation.ExtractTimeout(ctx, cluster.ObjectMeta, ObserverMaxIntervalAnnotation, defaultMaxIntervalFactor*interval),
+		Tracer:                 m.tracer,
 	}
 }
 
@@ -127,6 +138,7 @@ func (m *Manager) createOrReplaceObserver(ctx context.Context, cluster types.Nam
 		delete(m.observers, cluster)
 	}
 	observer = NewObserver(cluster, esClient, settings, m.notifyListeners)
+	observer.pool = m.pool
 	m.observers[cluster] = observer
 	return observer
 }
@@ -144,6 +156,35 @@ func (m *Manager) getAndObserveSynchronously(ctx context.Context, cluster types.
 	return observer
 }
 
+// LastHealth returns the last health observed for the given cluster, and false if the cluster is not observed.
+func (m *Manager) LastHealth(key types.NamespacedName) (esv1.ElasticsearchHealth, bool) {
+	observer, exists := m.getObserver(key)
+	if !exists {
+		return "", false
+	}
+	return observer.LastHealth(), true
+}
+
+// LastStateVersion returns the last cluster state version observed for the given cluster, and false if the cluster
+// is not observed.
+func (m *Manager) LastStateVersion(key types.NamespacedName) (client.ClusterStateVersion, bool) {
+	observer, exists := m.getObserver(key)
+	if !exists {
+		return client.ClusterStateVersion{}, false
+	}
+	return observer.LastStateVersion(), true
+}
+
+// LastObservation returns the responses of Elasticsearch to the last observation of the given cluster, and false if
+// the cluster is not observed.
+func (m *Manager) LastObservation(key types.NamespacedName) (Observation, bool) {
+	observer, exists := m.getObserver(key)
+	if !exists {
+		return Observation{}, false
+	}
+	return observer.LastObservation(), true
+}
+
 // List returns the names of clusters currently observed
 func (m *Manager) List() []types.NamespacedName {
 	m.observerLock.RLock()
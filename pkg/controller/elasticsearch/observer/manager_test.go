@@ -60,7 +60,8 @@ func TestManager_Observe(t *testing.T) {
 	fakeClient := fakeEsClient200(client.BasicAuth{})
 	fakeClientWithDifferentUser := fakeEsClient200(client.BasicAuth{Name: "name", Password: "another-one"})
 	defaultSettings := Settings{
-		ObservationInterval: defaultObservationTimeout,
+		ObservationInterval:    defaultObservationTimeout,
+		MaxObservationInterval: defaultMaxIntervalFactor * defaultObservationTimeout,
 	}
 
 	tests := []struct {
@@ -116,6 +117,10 @@ func TestManager_Observe(t *testing.T) {
 			observer := m.Observe(context.Background(), esObject(tt.clusterToObserve), esClientProvider, true)
 			// returned observer should be the correct one
 			require.Equal(t, tt.clusterToObserve, observer.cluster)
+			if tt.expectNewObserver || initialCreationTime.IsZero() {
+				// new observers run their observations in the manager pool
+				require.Equal(t, m.pool, observer.pool)
+			}
 			// list of observers should have been updated
 			require.ElementsMatch(t, tt.expectedObservers, m.List())
 
@@ -133,6 +138,14 @@ func flappingEsClient() client.Client {
 	return client.NewMockClientWithUser(version.MustParse("8.3.0"),
 		client.BasicAuth{},
 		func(req *http.Request) *http.Response {
+			if req.URL.Path == "/_cluster/state/version" {
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"cluster_uuid":"uuid","state_uuid":"state","version":1}`)),
+					Header:     make(http.Header),
+					Request:    req,
+				}
+			}
 			if retErr {
 				retErr = false
 				return &http.Response{
@@ -306,13 +319,19 @@ func TestExtractSettings(t *testing.T) {
 		{
 			name:           "no annotations",
 			globalInterval: 1 * time.Minute,
-			want:           Settings{ObservationInterval: 1 * time.Minute},
+			want:           Settings{ObservationInterval: 1 * time.Minute, MaxObservationInterval: 3 * time.Minute},
 		},
 		{
 			name:           "with annotations",
 			globalInterval: 1 * time.Second,
 			annotations:    map[string]string{ObserverIntervalAnnotation: "42s"},
-			want:           Settings{ObservationInterval: 42 * time.Second},
+			want:           Settings{ObservationInterval: 42 * time.Second, MaxObservationInterval: 126 * time.Second},
+		},
+		{
+			name:           "with max interval annotation",
+			globalInterval: 1 * time.Second,
+			annotations:    map[string]string{ObserverIntervalAnnotation: "5s", ObserverMaxIntervalAnnotation: "2m"},
+			want:           Settings{ObservationInterval: 5 * time.Second, MaxObservationInterval: 2 * time.Minute},
 		},
 	}
 
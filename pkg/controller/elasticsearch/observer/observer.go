@@ -24,14 +24,41 @@ var log = ulog.Log.WithName(name)
 
 // Settings for the Observer configuration
 type Settings struct {
+	// ObservationInterval is the interval between two observations while the cluster is changing, and the timeout of
+	// an observation.
 	ObservationInterval time.Duration
-	Tracer              *apm.Tracer
+	// MaxObservationInterval is the interval the observations are progressively slowed down to while the cluster is
+	// stable. It is ignored if lower than ObservationInterval.
+	MaxObservationInterval time.Duration
+	Tracer                 *apm.Tracer
+}
+
+// minInterval returns the interval between two observations while the cluster is changing.
+func (s Settings) minInterval() time.Duration {
+	return s.ObservationInterval
+}
+
+// maxInterval returns the interval between two observations while the cluster is stable.
+func (s Settings) maxInterval() time.Duration {
+	return max(s.ObservationInterval, s.MaxObservationInterval)
 }
 
 // defaultObservationTimeout is the default timeout for an observation. The observer uses the observation interval as a timeout.
 // The default applies if the observation interval is not positive to allow at least one successful observation.
 const defaultObservationTimeout = 10 * time.Second
 
+// Observation holds the responses of the Elasticsearch API to the requests of an observation.
+type Observation struct {
+	// Time is the time at which the observation started.
+	Time time.Time `json:"time"`
+	// Health is the cluster health returned by Elasticsearch, nil if the request failed.
+	Health *esclient.Health `json:"health,omitempty"`
+	// StateVersion is the cluster state version returned by Elasticsearch, nil if the request failed.
+	StateVersion *esclient.ClusterStateVersion `json:"stateVersion,omitempty"`
+	// Errors are the errors of the failed requests.
+	Errors []string `json:"errors,omitempty"`
+}
+
 // OnObservation is a function that gets executed when a new state is observed
 type OnObservation func(cluster types.NamespacedName, previousHealth, newHealth esv1.ElasticsearchHealth)
 
@@ -46,7 +73,15 @@ type Observer struct {
 	stopOnce      sync.Once
 	onObservation OnObservation
 	lastHealth    esv1.ElasticsearchHealth
-	mutex         sync.RWMutex
+	// lastStateVersion is the version of the cluster state at the last observation, zero if unknown
+	lastStateVersion esclient.ClusterStateVersion
+	// lastObservation holds the responses of Elasticsearch to the last observation
+	lastObservation Observation
+	// interval is the current interval between two asynchronous observations, which is adapted after each observation
+	interval time.Duration
+	// pool runs the asynchronous observations, they are run in the observer goroutine if nil
+	pool  *workerPool
+	mutex sync.RWMutex
 }
 
 // NewObserver creates and starts an Observer
@@ -69,20 +104,23 @@ func NewObserver(cluster types.NamespacedName, esClient esclient.Client, setting
 
 // Start starts the Observer in a separate goroutine after a first synchronous observation.
 // The first observation is synchronous to allow to retrieve the cluster state immediately after the start.
-// Then, observations are performed periodically in a separate goroutine until the observer stop channel is closed.
+// Then, observations are performed periodically in the worker pool until the observer stop channel is closed.
+// The interval between two observations is reset to its minimum when the health or the state of the cluster changes,
+// and is doubled up to its maximum otherwise.
 func (o *Observer) Start() {
 	if o.settings.ObservationInterval <= 0 {
 		return // asynchronous observations are effectively disabled
 	}
 	// periodic asynchronous observations
 	go func() {
-		ticker := time.NewTicker(o.settings.ObservationInterval)
-		defer ticker.Stop()
+		timer := time.NewTimer(o.currentInterval())
+		defer timer.Stop()
 
 		for {
 			select {
-			case <-ticker.C:
-				o.observe(context.Background())
+			case <-timer.C:
+				o.observeAsync()
+				timer.Reset(o.currentInterval())
 			case <-o.stopChan:
 				log.Info("Stopping observer for cluster", "namespace", o.cluster.Namespace, "es_name", o.cluster.Name)
 				return
@@ -106,6 +144,41 @@ func (o *Observer) LastHealth() esv1.ElasticsearchHealth {
 	return o.lastHealth
 }
 
+// LastStateVersion returns the last observed cluster state version, which is zero if unknown
+func (o *Observer) LastStateVersion() esclient.ClusterStateVersion {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	return o.lastStateVersion
+}
+
+// LastObservation returns the responses of Elasticsearch to the last observation
+func (o *Observer) LastObservation() Observation {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	return o.lastObservation
+}
+
+// currentInterval returns the interval until the next asynchronous observation.
+func (o *Observer) currentInterval() time.Duration {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	if o.interval <= 0 {
+		return o.settings.minInterval()
+	}
+	return o.interval
+}
+
+// observeAsync runs an asynchronous observation in the worker pool, if any.
+func (o *Observer) observeAsync() {
+	if o.pool == nil {
+		o.observe(context.Background())
+		return
+	}
+	o.pool.run(o.stopChan, func() {
+		o.observe(context.Background())
+	})
+}
+
 // observe retrieves the current ES state, executes onObservation,
 // and stores the new state
 func (o *Observer) observe(ctx context.Context) {
@@ -122,11 +195,30 @@ func (o *Observer) observe(ctx context.Context) {
 	ctx = ulog.InitInContext(ctx, name)
 	ulog.FromContext(ctx).V(1).Info("Retrieving cluster health", "es_name", o.cluster.Name, "namespace", o.cluster.Namespace)
 
-	newHealth := retrieveHealth(ctx, o.cluster, o.esClient)
+	previousHealth, previousStateVersion := o.LastHealth(), o.LastStateVersion()
+	observation := Observation{Time: time.Now()}
+	newHealth := retrieveHealth(ctx, o.cluster, o.esClient, &observation)
 	if o.onObservation != nil {
-		o.onObservation(o.cluster, o.LastHealth(), newHealth)
+		o.onObservation(o.cluster, previousHealth, newHealth)
 	}
 	o.updateHealth(newHealth)
+	newStateVersion := retrieveStateVersion(ctx, o.cluster, o.esClient, &observation)
+	o.updateStateVersion(newStateVersion)
+	o.updateObservation(observation)
+	o.adaptInterval(newHealth != previousHealth || newStateVersion != previousStateVersion)
+}
+
+// adaptInterval resets the interval between two observations to its minimum if the cluster changed since the last
+// observation, and doubles it up to its maximum otherwise.
+func (o *Observer) adaptInterval(changed bool) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	minInterval, maxInterval := o.settings.minInterval(), o.settings.maxInterval()
+	if changed || o.interval < minInterval {
+		o.interval = minInterval
+		return
+	}
+	o.interval = min(2*o.interval, maxInterval)
 }
 
 func (o *Observer) updateHealth(newHealth esv1.ElasticsearchHealth) {
@@ -135,6 +227,18 @@ func (o *Observer) updateHealth(newHealth esv1.ElasticsearchHealth) {
 	o.lastHealth = newHealth
 }
 
+func (o *Observer) updateStateVersion(newStateVersion esclient.ClusterStateVersion) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.lastStateVersion = newStateVersion
+}
+
+func (o *Observer) updateObservation(observation Observation) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.lastObservation = observation
+}
+
 func nonNegativeTimeout(observationInterval time.Duration) time.Duration {
 	// if the observation interval is not positive async observations are disabled
 	if observationInterval <= 0 {
@@ -145,11 +249,12 @@ func nonNegativeTimeout(observationInterval time.Duration) time.Duration {
 	return observationInterval
 }
 
-// retrieveHealth returns the current Elasticsearch cluster health
-func retrieveHealth(ctx context.Context, cluster types.NamespacedName, esClient esclient.Client) esv1.ElasticsearchHealth {
+// retrieveHealth returns the current Elasticsearch cluster health, and records the response in the given observation
+func retrieveHealth(ctx context.Context, cluster types.NamespacedName, esClient esclient.Client, observation *Observation) esv1.ElasticsearchHealth {
 	log := ulog.FromContext(ctx)
 	health, err := esClient.GetClusterHealth(ctx)
 	if err != nil {
+		observation.Errors = append(observation.Errors, err.Error())
 		log.V(1).Info(
 			"Unable to retrieve cluster health",
 			"error", err,
@@ -158,5 +263,24 @@ func retrieveHealth(ctx context.Context, cluster types.NamespacedName, esClient
 		)
 		return esv1.ElasticsearchUnknownHealth
 	}
+	observation.Health = &health
 	return health.Status
 }
+
+// retrieveStateVersion returns the current Elasticsearch cluster state version, or a zero value if it cannot be
+// retrieved, and records the response in the given observation
+func retrieveStateVersion(ctx context.Context, cluster types.NamespacedName, esClient esclient.Client, observation *Observation) esclient.ClusterStateVersion {
+	version, err := esClient.GetClusterStateVersion(ctx)
+	if err != nil {
+		observation.Errors = append(observation.Errors, err.Error())
+		ulog.FromContext(ctx).V(1).Info(
+			"Unable to retrieve cluster state version",
+			"error", err,
+			"namespace", cluster.Namespace,
+			"es_name", cluster.Name,
+		)
+		return esclient.ClusterStateVersion{}
+	}
+	observation.StateVersion = &version
+	return version
+}
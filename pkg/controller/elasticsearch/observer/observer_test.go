@@ -157,8 +157,54 @@ func TestRetrieveHealth(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			cluster := types.NamespacedName{Namespace: "ns1", Name: "es1"}
 			esClient := fakeEsClient(tt.healthRespErr)
-			health := retrieveHealth(context.Background(), cluster, esClient)
+			var observation Observation
+			health := retrieveHealth(context.Background(), cluster, esClient, &observation)
 			require.Equal(t, tt.expected, health)
+			// the response is recorded, or the error if the request failed
+			require.Equal(t, tt.healthRespErr, observation.Health == nil)
+			require.Equal(t, tt.healthRespErr, len(observation.Errors) == 1)
+		})
+	}
+}
+
+func TestRetrieveStateVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		expected   client.ClusterStateVersion
+	}{
+		{
+			name:       "state version ok",
+			statusCode: 200,
+			expected:   client.ClusterStateVersion{ClusterUUID: "uuid", StateUUID: "state", Version: 42},
+		},
+		{
+			name:       "unknown state version",
+			statusCode: 500,
+			expected:   client.ClusterStateVersion{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esClient := client.NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+				require.Equal(t, "/_cluster/state/version", req.URL.Path)
+				return &http.Response{
+					StatusCode: tt.statusCode,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"cluster_uuid":"uuid","state_uuid":"state","version":42}`)),
+					Header:     make(http.Header),
+					Request:    req,
+				}
+			})
+			var observation Observation
+			stateVersion := retrieveStateVersion(context.Background(), cluster("es1"), esClient, &observation)
+			require.Equal(t, tt.expected, stateVersion)
+			if tt.statusCode == 200 {
+				require.Equal(t, &tt.expected, observation.StateVersion)
+			} else {
+				require.Nil(t, observation.StateVersion)
+				require.Len(t, observation.Errors, 1)
+			}
 		})
 	}
 }
@@ -202,3 +248,46 @@ func Test_nonNegativeTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestObserver_adaptInterval(t *testing.T) {
+	observer := Observer{settings: Settings{ObservationInterval: 10 * time.Second, MaxObservationInterval: time.Minute}}
+	// first interval is the minimum
+	require.Equal(t, 10*time.Second, observer.currentInterval())
+
+	// the interval is doubled while the cluster is stable, up to the maximum
+	var intervals []time.Duration
+	for range 4 {
+		observer.adaptInterval(false)
+		intervals = append(intervals, observer.currentInterval())
+	}
+	require.Equal(t, []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute}, intervals)
+
+	// and reset to the minimum as soon as the cluster changes
+	observer.adaptInterval(true)
+	require.Equal(t, 10*time.Second, observer.currentInterval())
+
+	// a maximum lower than the minimum is ignored
+	observer = Observer{settings: Settings{ObservationInterval: 10 * time.Second, MaxObservationInterval: time.Second}}
+	observer.adaptInterval(false)
+	observer.adaptInterval(false)
+	require.Equal(t, 10*time.Second, observer.currentInterval())
+}
+
+func TestObserver_observe_adaptsInterval(t *testing.T) {
+	observer := Observer{
+		esClient: fakeEsClient(false),
+		settings: Settings{ObservationInterval: 10 * time.Second, MaxObservationInterval: time.Minute},
+	}
+	// the first observation changes the health
+	observer.observe(context.Background())
+	require.Equal(t, 10*time.Second, observer.currentInterval())
+	// the following ones observe a stable cluster
+	observer.observe(context.Background())
+	require.Equal(t, 20*time.Second, observer.currentInterval())
+	observer.observe(context.Background())
+	require.Equal(t, 40*time.Second, observer.currentInterval())
+	// until the health changes
+	observer.esClient = fakeEsClient(true)
+	observer.observe(context.Background())
+	require.Equal(t, 10*time.Second, observer.currentInterval())
+}
@@ -0,0 +1,55 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package observer
+
+import (
+	"sync"
+)
+
+// maxConcurrentObservations is the number of workers running the asynchronous observations of all the clusters.
+const maxConcurrentObservations = 32
+
+// workerPool runs tasks with a bounded number of workers, started on the first submitted task.
+// It bounds the number of concurrent requests made to Elasticsearch clusters by the observers, regardless of the
+// number of observed clusters.
+type workerPool struct {
+	workers   int
+	tasks     chan func()
+	startOnce sync.Once
+}
+
+func newWorkerPool(workers int) *workerPool {
+	return &workerPool{
+		workers: workers,
+		tasks:   make(chan func()),
+	}
+}
+
+func (p *workerPool) start() {
+	for range p.workers {
+		go func() {
+			for task := range p.tasks {
+				task()
+			}
+		}()
+	}
+}
+
+// run runs the given task in the pool and waits for its completion. It returns false without running the task if
+// the stop channel is closed before a worker is available.
+func (p *workerPool) run(stop <-chan struct{}, task func()) bool {
+	p.startOnce.Do(p.start)
+	done := make(chan struct{})
+	select {
+	case p.tasks <- func() {
+		defer close(done)
+		task()
+	}:
+	case <-stop:
+		return false
+	}
+	<-done
+	return true
+}
@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package observer
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_workerPool_run(t *testing.T) {
+	pool := newWorkerPool(2)
+	stop := make(chan struct{})
+
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.True(t, pool.run(stop, func() {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					observed := atomic.LoadInt32(&maxRunning)
+					if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+			}))
+		}()
+	}
+	wg.Wait()
+	// no more tasks than workers ran concurrently
+	require.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+}
+
+func Test_workerPool_run_stopped(t *testing.T) {
+	pool := newWorkerPool(1)
+	// occupy the only worker
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go pool.run(make(chan struct{}), func() {
+		close(started)
+		<-release
+	})
+	<-started
+	defer close(release)
+
+	// tasks waiting for a worker are not run once stopped
+	stop := make(chan struct{})
+	close(stop)
+	require.False(t, pool.run(stop, func() {
+		t.Error("task should not run")
+	}))
+}
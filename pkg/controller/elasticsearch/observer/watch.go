@@ -5,10 +5,15 @@
 package observer
 
 import (
+	"context"
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -17,7 +22,9 @@ import (
 // WatchClusterHealthChange returns a Source fed with generic events targeting clusters
 // whose health has changed between 2 observations.
 // Aimed to be used for triggering a reconciliation.
-func WatchClusterHealthChange(m *Manager) source.Source {
+// If batchWindow is positive, reconciliations are delayed by batchWindow so that the health changes happening within
+// this window trigger a single reconciliation, hence a single status update.
+func WatchClusterHealthChange(m *Manager, batchWindow time.Duration) source.Source {
 	evtChan := make(chan event.TypedGenericEvent[*esv1.Elasticsearch])
 	m.AddObservationListener(healthChangeListener(evtChan))
 	// Each event in Source will be consumed and turned into
@@ -26,7 +33,21 @@ func WatchClusterHealthChange(m *Manager) source.Source {
 	// DestBufferSize is kept at the default value (1024).
 	// This means we can enqueue a maximum of 1024 requests
 	// before blocking observers from moving on.
-	return source.Channel(evtChan, &handler.TypedEnqueueRequestForObject[*esv1.Elasticsearch]{})
+	return source.Channel(evtChan, healthChangeHandler(batchWindow))
+}
+
+// healthChangeHandler returns the handler enqueuing reconciliation requests for the clusters whose health changed,
+// after batchWindow if positive.
+func healthChangeHandler(batchWindow time.Duration) handler.TypedEventHandler[*esv1.Elasticsearch, reconcile.Request] {
+	if batchWindow <= 0 {
+		return &handler.TypedEnqueueRequestForObject[*esv1.Elasticsearch]{}
+	}
+	return handler.TypedFuncs[*esv1.Elasticsearch, reconcile.Request]{
+		GenericFunc: func(_ context.Context, evt event.TypedGenericEvent[*esv1.Elasticsearch], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			// requests added again before the end of the window are deduplicated by the queue
+			q.AddAfter(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: evt.Object.Namespace, Name: evt.Object.Name}}, batchWindow)
+		},
+	}
 }
 
 // healthChangeListener returns an OnObservation listener that feeds a generic
@@ -0,0 +1,44 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package observer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_healthChangeHandler(t *testing.T) {
+	evt := event.TypedGenericEvent[*esv1.Elasticsearch]{
+		Object: &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster"}},
+	}
+	request := reconcile.Request{NamespacedName: cluster("cluster")}
+
+	// requests are enqueued immediately without batch window
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	healthChangeHandler(0).Generic(context.Background(), evt, q)
+	require.Equal(t, 1, q.Len())
+	item, _ := q.Get()
+	require.Equal(t, request, item)
+	q.Done(item)
+
+	// health changes within the batch window are reconciled once at the end of the window
+	h := healthChangeHandler(100 * time.Millisecond)
+	h.Generic(context.Background(), evt, q)
+	h.Generic(context.Background(), evt, q)
+	require.Equal(t, 0, q.Len())
+	require.Eventually(t, func() bool { return q.Len() == 1 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	require.Equal(t, 1, q.Len())
+}
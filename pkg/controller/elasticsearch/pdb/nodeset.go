@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package pdb
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+// nodeSetLabelName is set on the PDBs overriding the default one for a NodeSet, to the name of the NodeSet.
+const nodeSetLabelName = "elasticsearch.k8s.elastic.co/pdb-node-set"
+
+// nodeSetStatefulSets returns the names of the StatefulSets of the given NodeSet, one per zone for zone-aware NodeSets.
+func nodeSetStatefulSets(es esv1.Elasticsearch, nodeSet esv1.NodeSet) []string {
+	var names []string
+	for _, expanded := range nodeSet.ExpandZones() {
+		names = append(names, es.StatefulSetName(expanded.Name))
+	}
+	return names
+}
+
+// overriddenStatefulSets returns the names of the StatefulSets of the NodeSets overriding the default PDB, which must
+// not be covered by it since the eviction API rejects the Pods covered by several PDBs.
+func overriddenStatefulSets(es esv1.Elasticsearch) []string {
+	var names []string
+	for _, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.PodDisruptionBudget != nil {
+			names = append(names, nodeSetStatefulSets(es, nodeSet)...)
+		}
+	}
+	return names
+}
+
+// expectedNodeSetPDBs returns the PDBs of the NodeSets overriding the default PDB, except the ones which are disabled.
+func expectedNodeSetPDBs(es esv1.Elasticsearch) ([]*policyv1.PodDisruptionBudget, error) {
+	var pdbs []*policyv1.PodDisruptionBudget
+	for _, nodeSet := range es.Spec.NodeSets {
+		template := nodeSet.PodDisruptionBudget.DeepCopy()
+		if template == nil || template.IsDisabled() {
+			continue
+		}
+		expected := policyv1.PodDisruptionBudget{
+			ObjectMeta: template.ObjectMeta,
+			Spec:       template.Spec,
+		}
+		// inherit user-provided ObjectMeta, but set our own name & namespace
+		expected.Name = esv1.NodeSetPodDisruptionBudget(es.Name, nodeSet.Name)
+		expected.Namespace = es.Namespace
+		// and append our labels
+		expected.Labels = maps.MergePreservingExistingKeys(expected.Labels, label.NewLabels(k8s.ExtractNamespacedName(&es)))
+		expected.Labels[nodeSetLabelName] = nodeSet.Name
+		// set owner reference for deletion upon ES resource deletion
+		if err := controllerutil.SetControllerReference(&es, &expected, scheme.Scheme); err != nil {
+			return nil, err
+		}
+
+		if expected.Spec.Selector == nil {
+			// match the Pods of the NodeSet
+			expected.Spec.Selector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					label.ClusterNameLabelName: es.Name,
+				},
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      label.StatefulSetNameLabelName,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   nodeSetStatefulSets(es, nodeSet),
+				}},
+			}
+		}
+		if expected.Spec.MinAvailable == nil && expected.Spec.MaxUnavailable == nil {
+			maxUnavailable := commonv1.DefaultPodDisruptionBudgetMaxUnavailable
+			expected.Spec.MaxUnavailable = &maxUnavailable
+		}
+		pdbs = append(pdbs, &expected)
+	}
+	return pdbs, nil
+}
+
+// deleteUnexpectedNodeSetPDBs deletes the PDBs of the NodeSets which were removed or do not override the default PDB
+// anymore.
+func deleteUnexpectedNodeSetPDBs(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, expected []*policyv1.PodDisruptionBudget) error {
+	v1Available, err := isPDBV1Available(k8sClient)
+	if err != nil {
+		return err
+	}
+	opts := []client.ListOption{
+		client.InNamespace(es.Namespace),
+		client.MatchingLabels{label.ClusterNameLabelName: es.Name},
+		client.HasLabels{nodeSetLabelName},
+	}
+	var names []string
+	if v1Available {
+		var pdbs policyv1.PodDisruptionBudgetList
+		if err := k8sClient.List(ctx, &pdbs, opts...); err != nil {
+			return err
+		}
+		for _, pdb := range pdbs.Items {
+			names = append(names, pdb.Name)
+		}
+	} else {
+		var pdbs policyv1beta1.PodDisruptionBudgetList
+		if err := k8sClient.List(ctx, &pdbs, opts...); err != nil {
+			return err
+		}
+		for _, pdb := range pdbs.Items {
+			names = append(names, pdb.Name)
+		}
+	}
+
+	expectedNames := make(map[string]struct{}, len(expected))
+	for _, pdb := range expected {
+		expectedNames[pdb.Name] = struct{}{}
+	}
+	for _, name := range names {
+		if _, exists := expectedNames[name]; exists {
+			continue
+		}
+		if err := deletePDB(ctx, k8sClient, es.Namespace, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
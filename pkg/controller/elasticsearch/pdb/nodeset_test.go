@@ -0,0 +1,111 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package pdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+)
+
+func TestReconcile_NodeSetOverrides(t *testing.T) {
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Group: "policy", Version: "v1"}})
+	restMapper.Add(schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}, meta.RESTScopeNamespace)
+
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"},
+		Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{
+			{Name: "master", Count: 3},
+			{Name: "hot", Count: 6, PodDisruptionBudget: &commonv1.PodDisruptionBudgetTemplate{
+				Spec: policyv1.PodDisruptionBudgetSpec{MaxUnavailable: intStrPtr(intstr.FromInt(2))},
+			}},
+			{Name: "warm", Count: 4, PodDisruptionBudget: &commonv1.PodDisruptionBudgetTemplate{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"a": "b"}},
+			}},
+			{Name: "coordinating", Count: 2, PodDisruptionBudget: &commonv1.PodDisruptionBudgetTemplate{}},
+		}},
+		Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth},
+	}
+	statefulSets := es_sset.StatefulSetList{
+		sset.TestSset{Namespace: "ns", Name: "cluster-es-master", ClusterName: "cluster", Replicas: 3, Master: true}.Build(),
+		sset.TestSset{Namespace: "ns", Name: "cluster-es-hot", ClusterName: "cluster", Replicas: 6, Data: true, Ingest: true}.Build(),
+		sset.TestSset{Namespace: "ns", Name: "cluster-es-warm", ClusterName: "cluster", Replicas: 4, Data: true}.Build(),
+		sset.TestSset{Namespace: "ns", Name: "cluster-es-coordinating", ClusterName: "cluster", Replicas: 2}.Build(),
+	}
+	// PDB of a NodeSet that does not override the default PDB anymore
+	stale := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns",
+		Name:      esv1.NodeSetPodDisruptionBudget("cluster", "cold"),
+		Labels:    map[string]string{label.ClusterNameLabelName: "cluster", nodeSetLabelName: "cold"},
+	}}
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithRESTMapper(restMapper).
+		WithObjects(stale).Build()
+
+	require.NoError(t, Reconcile(context.Background(), k8sClient, es, statefulSets))
+
+	var pdbs policyv1.PodDisruptionBudgetList
+	require.NoError(t, k8sClient.List(context.Background(), &pdbs, client.InNamespace("ns")))
+	byName := map[string]policyv1.PodDisruptionBudget{}
+	for _, pdb := range pdbs.Items {
+		byName[pdb.Name] = pdb
+	}
+	require.Len(t, byName, 3)
+
+	// the default PDB covers the master nodes only
+	defaultPDB := byName[esv1.DefaultPodDisruptionBudget("cluster")]
+	require.Equal(t, intStrPtr(intstr.FromInt(2)), defaultPDB.Spec.MinAvailable)
+	require.Equal(t, []metav1.LabelSelectorRequirement{{
+		Key:      label.StatefulSetNameLabelName,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   []string{"cluster-es-hot", "cluster-es-warm", "cluster-es-coordinating"},
+	}}, defaultPDB.Spec.Selector.MatchExpressions)
+
+	hot := byName[esv1.NodeSetPodDisruptionBudget("cluster", "hot")]
+	require.Equal(t, intStrPtr(intstr.FromInt(2)), hot.Spec.MaxUnavailable)
+	require.Nil(t, hot.Spec.MinAvailable)
+	require.Equal(t, "hot", hot.Labels[nodeSetLabelName])
+	require.Equal(t, &metav1.LabelSelector{
+		MatchLabels: map[string]string{label.ClusterNameLabelName: "cluster"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      label.StatefulSetNameLabelName,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{"cluster-es-hot"},
+		}},
+	}, hot.Spec.Selector)
+
+	// metadata only: default maxUnavailable
+	warm := byName[esv1.NodeSetPodDisruptionBudget("cluster", "warm")]
+	require.Equal(t, intStrPtr(commonv1.DefaultPodDisruptionBudgetMaxUnavailable), warm.Spec.MaxUnavailable)
+	require.Equal(t, "b", warm.Labels["a"])
+}
+
+func Test_nodeSetStatefulSets(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	require.Equal(t, []string{"cluster-es-data"}, nodeSetStatefulSets(es, esv1.NodeSet{Name: "data"}))
+	zoneAware := esv1.NodeSet{Name: "data", ZoneAwareness: &esv1.ZoneAwareness{Zones: []string{"a", "b"}}}
+	expected := make([]string, 0, 2)
+	for _, nodeSet := range zoneAware.ExpandZones() {
+		expected = append(expected, esv1.StatefulSet("cluster", nodeSet.Name))
+	}
+	require.Len(t, expected, 2)
+	require.Equal(t, expected, nodeSetStatefulSets(es, zoneAware))
+}
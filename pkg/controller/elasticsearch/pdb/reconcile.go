@@ -6,6 +6,7 @@ package pdb
 
 import (
 	"context"
+	"slices"
 
 	policyv1 "k8s.io/api/policy/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
@@ -29,15 +30,33 @@ import (
 // Reconcile ensures that a PodDisruptionBudget exists for this cluster, inheriting the spec content.
 // The default PDB we setup dynamically adapts MinAvailable to the number of nodes in the cluster.
 // If the spec has disabled the default PDB, it will ensure none exist.
+// NodeSets overriding the default PDB get their own PDB, and are excluded from the default one.
 func Reconcile(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, statefulSets sset.StatefulSetList) error {
+	nodeSetPDBs, err := expectedNodeSetPDBs(es)
+	if err != nil {
+		return err
+	}
+	for _, expected := range nodeSetPDBs {
+		if err := reconcilePDB(ctx, k8sClient, es, expected); err != nil {
+			return err
+		}
+	}
+	if err := deleteUnexpectedNodeSetPDBs(ctx, k8sClient, es, nodeSetPDBs); err != nil {
+		return err
+	}
+
 	expected, err := expectedPDB(es, statefulSets)
 	if err != nil {
 		return err
 	}
 	if expected == nil {
-		return deleteDefaultPDB(ctx, k8sClient, es)
+		return deletePDB(ctx, k8sClient, es.Namespace, esv1.DefaultPodDisruptionBudget(es.Name))
 	}
+	return reconcilePDB(ctx, k8sClient, es, expected)
+}
 
+// reconcilePDB creates or updates the given PDB, falling back to the v1beta1 API if v1 is not available.
+func reconcilePDB(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, expected *policyv1.PodDisruptionBudget) error {
 	// label the PDB with a hash of its content, for comparison purposes
 	expected.Labels = hash.SetTemplateHashLabel(expected.Labels, expected)
 
@@ -85,8 +104,8 @@ func Reconcile(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch,
 	)
 }
 
-// deleteDefaultPDB deletes the default pdb if it exists.
-func deleteDefaultPDB(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch) error {
+// deletePDB deletes the pdb with the given name if it exists.
+func deletePDB(ctx context.Context, k8sClient k8s.Client, namespace, name string) error {
 	// we do this by getting first because that is a local cache read,
 	// versus a Delete call, which would hit the API.
 
@@ -98,15 +117,15 @@ func deleteDefaultPDB(ctx context.Context, k8sClient k8s.Client, es esv1.Elastic
 	if v1Available {
 		pdb = &policyv1.PodDisruptionBudget{
 			ObjectMeta: metav1.ObjectMeta{
-				Namespace: es.Namespace,
-				Name:      esv1.DefaultPodDisruptionBudget(es.Name),
+				Namespace: namespace,
+				Name:      name,
 			},
 		}
 	} else {
 		pdb = &policyv1beta1.PodDisruptionBudget{
 			ObjectMeta: metav1.ObjectMeta{
-				Namespace: es.Namespace,
-				Name:      esv1.DefaultPodDisruptionBudget(es.Name),
+				Namespace: namespace,
+				Name:      name,
 			},
 		}
 	}
@@ -153,29 +172,44 @@ func expectedPDB(es esv1.Elasticsearch, statefulSets sset.StatefulSetList) (*pol
 		expected.Spec = template.Spec
 	} else {
 		// set our default spec
-		expected.Spec = buildPDBSpec(es, statefulSets)
+		expected.Spec = buildPDBSpec(es, statefulSets, overriddenStatefulSets(es))
 	}
 
 	return &expected, nil
 }
 
 // buildPDBSpec returns a PDBSpec computed from the current StatefulSets,
-// considering the cluster health and topology.
-func buildPDBSpec(es esv1.Elasticsearch, statefulSets sset.StatefulSetList) policyv1.PodDisruptionBudgetSpec {
+// considering the cluster health and topology. The Pods of the excluded StatefulSets are not covered by the PDB.
+func buildPDBSpec(es esv1.Elasticsearch, statefulSets sset.StatefulSetList, excluded []string) policyv1.PodDisruptionBudgetSpec {
 	// compute MinAvailable based on the maximum number of Pods we're supposed to have
-	nodeCount := statefulSets.ExpectedNodeCount()
+	covered := make(sset.StatefulSetList, 0, len(statefulSets))
+	for _, statefulSet := range statefulSets {
+		if !slices.Contains(excluded, statefulSet.Name) {
+			covered = append(covered, statefulSet)
+		}
+	}
+	nodeCount := covered.ExpectedNodeCount()
 	// maybe allow some Pods to be disrupted
-	minAvailable := nodeCount - allowedDisruptions(es, statefulSets)
+	minAvailable := max(nodeCount-allowedDisruptions(es, statefulSets), 0)
 
 	minAvailableIntStr := intstr.IntOrString{Type: intstr.Int, IntVal: minAvailable}
 
-	return policyv1.PodDisruptionBudgetSpec{
+	selector := &metav1.LabelSelector{
 		// match all pods for this cluster
-		Selector: &metav1.LabelSelector{
-			MatchLabels: map[string]string{
-				label.ClusterNameLabelName: es.Name,
-			},
+		MatchLabels: map[string]string{
+			label.ClusterNameLabelName: es.Name,
 		},
+	}
+	if len(excluded) > 0 {
+		selector.MatchExpressions = []metav1.LabelSelectorRequirement{{
+			Key:      label.StatefulSetNameLabelName,
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values:   excluded,
+		}}
+	}
+
+	return policyv1.PodDisruptionBudgetSpec{
+		Selector:     selector,
 		MinAvailable: &minAvailableIntStr,
 		// MaxUnavailable can only be used if the selector matches a builtin controller selector
 		// (eg. Deployments, StatefulSets, etc.). We cannot use it with our own cluster-name selector.
@@ -116,6 +116,11 @@ func (s *State) UpdateWithPhase(
 	return s
 }
 
+// Phase returns the phase of the Elasticsearch resource as computed so far during the reconciliation.
+func (s *State) Phase() esv1.ElasticsearchOrchestrationPhase {
+	return s.status.Phase
+}
+
 func (s *State) UpdateAvailableNodes(
 	resourcesState ResourcesState,
 ) *State {
@@ -123,6 +128,11 @@ func (s *State) UpdateAvailableNodes(
 	return s
 }
 
+// AvailableNodes returns the number of available nodes as computed so far during the reconciliation.
+func (s *State) AvailableNodes() int32 {
+	return s.status.AvailableNodes
+}
+
 func (s *State) UpdateMinRunningVersion(
 	ctx context.Context,
 	resourcesState ResourcesState,
@@ -186,6 +196,30 @@ func (s *State) Apply() ([]events.Event, *esv1.Elasticsearch) {
 	return s.Events(), &s.cluster
 }
 
+// UpdatePreUpgradeVolumeSnapshots updates the VolumeSnapshots taken before the version upgrades of the cluster.
+func (s *State) UpdatePreUpgradeVolumeSnapshots(snapshots []esv1.UpgradeVolumeSnapshots) *State {
+	s.status.PreUpgradeVolumeSnapshots = snapshots
+	return s
+}
+
+// UpdateInitialRestore updates the status of the restore of the initial snapshot of the cluster.
+func (s *State) UpdateInitialRestore(status *esv1.InitialRestoreStatus) *State {
+	s.status.InitialRestore = status
+	return s
+}
+
+// UpdateRemoteClusters updates the state of the connections to the remote clusters.
+func (s *State) UpdateRemoteClusters(remoteClusters []esv1.RemoteClusterStatus) *State {
+	s.status.RemoteClusters = remoteClusters
+	return s
+}
+
+// UpdateSnapshotLifecyclePolicies updates the status of the snapshot lifecycle policies configured on the cluster.
+func (s *State) UpdateSnapshotLifecyclePolicies(policies []esv1.SnapshotLifecyclePolicyStatus) *State {
+	s.status.SnapshotLifecyclePolicies = policies
+	return s
+}
+
 // UpdateOrchestrationHints updates the orchestration hints collected so far with the hints in hint.
 func (s *State) UpdateOrchestrationHints(hint hints.OrchestrationsHints) {
 	s.hints = s.hints.Merge(hint)
@@ -7,6 +7,7 @@ package reconcile
 import (
 	"reflect"
 	"sort"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -55,6 +56,21 @@ func (s *StatusReporter) ReportCondition(
 	})
 }
 
+// ReportConditionWithReason records the given condition along with a machine-readable reason.
+func (s *StatusReporter) ReportConditionWithReason(
+	conditionType commonv1alpha1.ConditionType,
+	status corev1.ConditionStatus,
+	reason string,
+	message string) {
+	s.Conditions = s.Conditions.MergeWith(commonv1alpha1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Message:            message,
+		Reason:             reason,
+	})
+}
+
 // -- Upscale status
 
 type UpscaleReporter struct {
@@ -222,8 +238,10 @@ func (u *UpgradeReporter) Merge(other esv1.UpgradeOperation) esv1.UpgradeOperati
 
 type DownscaleReporter struct {
 	// Expected nodes to be downscaled, key is node name
-	nodes   map[string]esv1.DownscaledNode
-	stalled *bool
+	nodes map[string]esv1.DownscaledNode
+	// Nodes being drained of their data, sorted by name
+	excludedNodes []string
+	stalled       *bool
 }
 
 // RecordNodesToBeRemoved records nodes expected to be eventually removed from the cluster.
@@ -250,14 +268,18 @@ func (d *DownscaleReporter) Merge(other esv1.DownscaleOperation) esv1.DownscaleO
 	if d == nil {
 		return other
 	}
+	now := metav1.Now()
 	var nodes []esv1.DownscaledNode
 	if len(d.nodes) != 0 {
 		nodes = make([]esv1.DownscaledNode, 0, len(d.nodes))
 		for _, node := range d.nodes {
 			nodes = append(nodes, esv1.DownscaledNode{
-				Name:           node.Name,
-				ShutdownStatus: node.ShutdownStatus,
-				Explanation:    node.Explanation,
+				Name:                    node.Name,
+				ShutdownStatus:          node.ShutdownStatus,
+				Explanation:             node.Explanation,
+				RemainingShards:         node.RemainingShards,
+				RemainingBytes:          node.RemainingBytes,
+				EstimatedCompletionTime: estimatedCompletionTime(node, other, now),
 			})
 		}
 		// Sort for stable comparison
@@ -265,9 +287,11 @@ func (d *DownscaleReporter) Merge(other esv1.DownscaleOperation) esv1.DownscaleO
 			return nodes[i].Name < nodes[j].Name
 		})
 	}
-	if (d.nodes != nil && !reflect.DeepEqual(nodes, other.Nodes)) || downscaleOperation.LastUpdatedTime.IsZero() {
+	if (d.nodes != nil && (!reflect.DeepEqual(nodes, other.Nodes) || !reflect.DeepEqual(d.excludedNodes, other.ExcludedNodes))) ||
+		downscaleOperation.LastUpdatedTime.IsZero() {
 		downscaleOperation.Nodes = nodes
-		downscaleOperation.LastUpdatedTime = metav1.Now()
+		downscaleOperation.ExcludedNodes = d.excludedNodes
+		downscaleOperation.LastUpdatedTime = now
 	}
 
 	if !reflect.DeepEqual(d.stalled, other.Stalled) {
@@ -294,6 +318,8 @@ func (d *DownscaleReporter) OnShutdownStatus(
 	if len(nodeShutdownStatus.Explanation) > 0 {
 		node.Explanation = ptr.To[string](nodeShutdownStatus.Explanation)
 	}
+	node.RemainingShards = nodeShutdownStatus.RemainingShards
+	node.RemainingBytes = nodeShutdownStatus.RemainingBytes
 	d.nodes[podName] = node
 	if nodeShutdownStatus.Status == esclient.ShutdownStalled {
 		d.stalled = ptr.To[bool](true)
@@ -314,4 +340,42 @@ func (d *DownscaleReporter) OnReconcileShutdowns(leavingNodes []string) {
 		node.ShutdownStatus = string(esclient.ShutdownInProgress)
 		d.nodes[nodeName] = node
 	}
+	d.excludedNodes = nil
+	if len(leavingNodes) > 0 {
+		d.excludedNodes = append([]string{}, leavingNodes...)
+		sort.Strings(d.excludedNodes)
+	}
+}
+
+// estimatedCompletionTime estimates when the data migration away from a node is complete, extrapolating the rate at which
+// data has been migrated since the previous update of the downscale status. It returns nil if no progress can be measured.
+func estimatedCompletionTime(node esv1.DownscaledNode, previous esv1.DownscaleOperation, now metav1.Time) *metav1.Time {
+	if node.RemainingBytes == nil || previous.LastUpdatedTime.IsZero() {
+		return nil
+	}
+	var previousNode *esv1.DownscaledNode
+	for i := range previous.Nodes {
+		if previous.Nodes[i].Name == node.Name {
+			previousNode = &previous.Nodes[i]
+			break
+		}
+	}
+	if previousNode == nil || previousNode.RemainingBytes == nil {
+		return nil
+	}
+	remaining, previousRemaining := *node.RemainingBytes, *previousNode.RemainingBytes
+	switch {
+	case remaining == previousRemaining:
+		// no progress since the previous update, an estimation in the past hints at a stuck data migration
+		return previousNode.EstimatedCompletionTime
+	case remaining > previousRemaining:
+		return nil
+	}
+	elapsed := now.Sub(previous.LastUpdatedTime.Time)
+	if elapsed <= 0 {
+		return nil
+	}
+	remainingDuration := time.Duration(float64(elapsed) * float64(remaining) / float64(previousRemaining-remaining))
+	// the status is serialized with a precision of one second
+	return &metav1.Time{Time: now.Add(remainingDuration).Round(time.Second)}
 }
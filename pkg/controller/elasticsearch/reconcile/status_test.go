@@ -6,6 +6,7 @@ package reconcile
 
 import (
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -99,7 +100,8 @@ func TestStatusReporter_MergeStatusReportingWith(t *testing.T) {
 								Explanation:    nil,
 							},
 						},
-						Stalled: ptr.To[bool](true),
+						ExcludedNodes: []string{"removed-1", "removed-2", "removed-3"},
+						Stalled:       ptr.To[bool](true),
 					},
 					UpgradeOperation: esv1.UpgradeOperation{
 						LastUpdatedTime: metav1.Time{},
@@ -231,7 +233,8 @@ func TestStatusReporter_MergeStatusReportingWith(t *testing.T) {
 								Explanation:    nil,
 							},
 						},
-						Stalled: nil,
+						ExcludedNodes: []string{"removed-1", "removed-2"},
+						Stalled:       nil,
 					},
 					UpgradeOperation: esv1.UpgradeOperation{
 						LastUpdatedTime: metav1.Time{},
@@ -453,3 +456,64 @@ func TestStatusReporter_MergeStatusReportingWith(t *testing.T) {
 		})
 	}
 }
+
+func Test_estimatedCompletionTime(t *testing.T) {
+	now := metav1.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	previousEstimation := metav1.NewTime(now.Add(5 * time.Minute))
+	previous := esv1.DownscaleOperation{
+		LastUpdatedTime: metav1.NewTime(now.Add(-time.Minute)),
+		Nodes: []esv1.DownscaledNode{
+			{Name: "node-1", RemainingBytes: ptr.To[int64](3000), EstimatedCompletionTime: &previousEstimation},
+			{Name: "node-2"},
+		},
+	}
+	tests := []struct {
+		name     string
+		node     esv1.DownscaledNode
+		previous esv1.DownscaleOperation
+		want     *metav1.Time
+	}{
+		{
+			name:     "Extrapolate the migration rate",
+			node:     esv1.DownscaledNode{Name: "node-1", RemainingBytes: ptr.To[int64](2000)},
+			previous: previous,
+			// 1000 bytes migrated per minute
+			want: ptr.To(metav1.NewTime(now.Add(2 * time.Minute))),
+		},
+		{
+			name:     "No progress keeps the previous estimation",
+			node:     esv1.DownscaledNode{Name: "node-1", RemainingBytes: ptr.To[int64](3000)},
+			previous: previous,
+			want:     &previousEstimation,
+		},
+		{
+			name:     "More data to migrate than previously",
+			node:     esv1.DownscaledNode{Name: "node-1", RemainingBytes: ptr.To[int64](4000)},
+			previous: previous,
+			want:     nil,
+		},
+		{
+			name:     "No previous size",
+			node:     esv1.DownscaledNode{Name: "node-2", RemainingBytes: ptr.To[int64](4000)},
+			previous: previous,
+			want:     nil,
+		},
+		{
+			name:     "No size",
+			node:     esv1.DownscaledNode{Name: "node-1"},
+			previous: previous,
+			want:     nil,
+		},
+		{
+			name:     "New node",
+			node:     esv1.DownscaledNode{Name: "node-3", RemainingBytes: ptr.To[int64](4000)},
+			previous: previous,
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, estimatedCompletionTime(tt.node, tt.previous, now))
+		})
+	}
+}
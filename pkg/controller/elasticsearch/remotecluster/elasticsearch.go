@@ -11,6 +11,7 @@ import (
 	"go.elastic.co/apm/v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
@@ -114,15 +115,14 @@ func updateSettingsInternal(
 	for name, remoteCluster := range remoteClustersInSpec {
 		remoteClustersToUpdate = append(remoteClustersToUpdate, name)
 		// Declare remote cluster in ES
-		seedHosts := []string{services.ExternalTransportServiceHost(remoteCluster.ElasticsearchRef.NamespacedName())}
-		remoteClustersToApply[name] = esclient.RemoteCluster{Seeds: seedHosts}
+		remoteClustersToApply[name] = expectedSettings(remoteCluster, remoteClustersInEs[name])
 		// Ensure this cluster is tracked in the annotation
 		remoteClustersInAnnotation[name] = struct{}{}
 	}
 
 	// RemoteClusters to remove from Elasticsearch
 	for _, name := range remoteClustersToDelete {
-		remoteClustersToApply[name] = esclient.RemoteCluster{Seeds: nil}
+		remoteClustersToApply[name] = esclient.RemoteCluster{Seeds: nil, RemoteClusterOptions: resetOptions(remoteClustersInEs[name])}
 	}
 
 	// Update the annotation
@@ -149,14 +149,14 @@ func updateSettingsInternal(
 }
 
 // getRemoteClustersInElasticsearch returns all the remote clusters currently declared in Elasticsearch
-func getRemoteClustersInElasticsearch(ctx context.Context, esClient esclient.Client) (map[string]struct{}, error) {
-	remoteClustersInEs := make(map[string]struct{})
+func getRemoteClustersInElasticsearch(ctx context.Context, esClient esclient.Client) (map[string]esclient.RemoteCluster, error) {
+	remoteClustersInEs := make(map[string]esclient.RemoteCluster)
 	remoteClusterSettings, err := esClient.GetRemoteClusterSettings(ctx)
 	if err != nil {
 		return remoteClustersInEs, err
 	}
-	for remoteClusterName := range remoteClusterSettings.PersistentSettings.Cluster.RemoteClusters {
-		remoteClustersInEs[remoteClusterName] = struct{}{}
+	for remoteClusterName, remoteCluster := range remoteClusterSettings.PersistentSettings.Cluster.RemoteClusters {
+		remoteClustersInEs[remoteClusterName] = remoteCluster
 	}
 	return remoteClustersInEs, nil
 }
@@ -166,6 +166,11 @@ func getRemoteClustersInElasticsearch(ctx context.Context, esClient esclient.Cli
 func getRemoteClustersInSpec(es esv1.Elasticsearch) map[string]esv1.RemoteCluster {
 	remoteClusters := make(map[string]esv1.RemoteCluster)
 	for _, remoteCluster := range es.Spec.RemoteClusters {
+		if len(remoteCluster.Seeds) > 0 || remoteCluster.ProxyAddress != "" {
+			// remote cluster not managed by the operator
+			remoteClusters[remoteCluster.Name] = remoteCluster
+			continue
+		}
 		if !remoteCluster.ElasticsearchRef.IsDefined() {
 			continue
 		}
@@ -175,6 +180,64 @@ func getRemoteClustersInSpec(es esv1.Elasticsearch) map[string]esv1.RemoteCluste
 	return remoteClusters
 }
 
+// expectedSettings returns the settings of the remote cluster in Elasticsearch. The optional settings are only set if
+// they are specified, or to reset the ones currently set in Elasticsearch.
+func expectedSettings(remoteCluster esv1.RemoteCluster, current esclient.RemoteCluster) esclient.RemoteCluster {
+	options := remoteClusterOptions(remoteCluster)
+	if options == nil {
+		options = resetOptions(current)
+	}
+	if remoteCluster.IsProxyMode() {
+		// seeds cannot be set in proxy mode
+		return esclient.RemoteCluster{Seeds: nil, RemoteClusterOptions: options}
+	}
+	return esclient.RemoteCluster{Seeds: seedHosts(remoteCluster), RemoteClusterOptions: options}
+}
+
+// remoteClusterOptions returns the optional settings specified for the remote cluster, or nil if none is specified.
+func remoteClusterOptions(remoteCluster esv1.RemoteCluster) *esclient.RemoteClusterOptions {
+	if remoteCluster.Mode == "" && remoteCluster.ProxyAddress == "" && remoteCluster.ServerName == "" &&
+		remoteCluster.NumSocketConnections == nil && remoteCluster.SkipUnavailable == nil {
+		return nil
+	}
+	options := esclient.RemoteClusterOptions{
+		SkipUnavailable: remoteCluster.SkipUnavailable,
+	}
+	if remoteCluster.Mode != "" {
+		options.Mode = ptr.To(string(remoteCluster.Mode))
+	}
+	if remoteCluster.IsProxyMode() {
+		proxyAddress := remoteCluster.ProxyAddress
+		if proxyAddress == "" {
+			proxyAddress = services.ExternalTransportServiceHost(remoteCluster.ElasticsearchRef.NamespacedName())
+		}
+		options.ProxyAddress = ptr.To(proxyAddress)
+		if remoteCluster.ServerName != "" {
+			options.ServerName = ptr.To(remoteCluster.ServerName)
+		}
+		options.ProxySocketConnections = remoteCluster.NumSocketConnections
+	}
+	return &options
+}
+
+// resetOptions returns empty options to reset the optional settings of the remote cluster if some are currently set in
+// Elasticsearch, or nil otherwise.
+func resetOptions(current esclient.RemoteCluster) *esclient.RemoteClusterOptions {
+	if current.RemoteClusterOptions == nil {
+		return nil
+	}
+	return &esclient.RemoteClusterOptions{}
+}
+
+// seedHosts returns the seeds of the remote cluster: the seeds declared for a remote cluster not managed by the operator,
+// or the transport service of the referenced Elasticsearch cluster.
+func seedHosts(remoteCluster esv1.RemoteCluster) []string {
+	if len(remoteCluster.Seeds) > 0 {
+		return remoteCluster.Seeds
+	}
+	return []string{services.ExternalTransportServiceHost(remoteCluster.ElasticsearchRef.NamespacedName())}
+}
+
 // updateSettings makes a call to an Elasticsearch cluster to apply a persistent setting.
 func updateSettings(ctx context.Context, esClient esclient.Client, remoteClusters map[string]esclient.RemoteCluster) error {
 	return esClient.UpdateRemoteClusterSettings(ctx, esclient.RemoteClustersSettings{
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -172,6 +173,121 @@ func TestUpdateSettings(t *testing.T) {
 			wantGetRemoteClusterSettingsCalled:    true,
 			wantUpdateRemoteClusterSettingsCalled: false,
 		},
+		{
+			name: "Create a remote cluster not managed by the operator",
+			args: args{
+				esClient:       &fakeESClient{existingSettings: emptySettings},
+				licenseChecker: &license.MockLicenseChecker{EnterpriseEnabled: true},
+				es: newEsWithRemoteClusters(
+					"ns1",
+					"es1",
+					nil,
+					esv1.RemoteCluster{
+						Name:   "external",
+						Seeds:  []string{"es.example.com:9443"},
+						APIKey: &esv1.SecretKeyRef{SecretName: "remote-keys", Key: "external"},
+					},
+				),
+			},
+			wantAnnotation:                        "external",
+			wantGetRemoteClusterSettingsCalled:    true,
+			wantUpdateRemoteClusterSettingsCalled: true,
+			wantSettings: esclient.RemoteClustersSettings{
+				PersistentSettings: &esclient.SettingsGroup{
+					Cluster: esclient.RemoteClusters{
+						RemoteClusters: map[string]esclient.RemoteCluster{
+							"external": {Seeds: []string{"es.example.com:9443"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Create a remote cluster in proxy mode",
+			args: args{
+				esClient:       &fakeESClient{existingSettings: emptySettings},
+				licenseChecker: &license.MockLicenseChecker{EnterpriseEnabled: true},
+				es: newEsWithRemoteClusters(
+					"ns1",
+					"es1",
+					nil,
+					esv1.RemoteCluster{
+						Name:                 "ns2-es2",
+						ElasticsearchRef:     commonv1.LocalObjectSelector{Name: "es2", Namespace: "ns2"},
+						Mode:                 esv1.RemoteClusterProxyMode,
+						ServerName:           "es2.example.com",
+						NumSocketConnections: ptr.To[int32](6),
+						SkipUnavailable:      ptr.To(true),
+					},
+				),
+			},
+			wantAnnotation:                        "ns2-es2",
+			wantGetRemoteClusterSettingsCalled:    true,
+			wantUpdateRemoteClusterSettingsCalled: true,
+			wantSettings: esclient.RemoteClustersSettings{
+				PersistentSettings: &esclient.SettingsGroup{
+					Cluster: esclient.RemoteClusters{
+						RemoteClusters: map[string]esclient.RemoteCluster{
+							"ns2-es2": {RemoteClusterOptions: &esclient.RemoteClusterOptions{
+								Mode:                   ptr.To("proxy"),
+								ProxyAddress:           ptr.To("es2-es-transport.ns2.svc:9300"),
+								ServerName:             ptr.To("es2.example.com"),
+								ProxySocketConnections: ptr.To[int32](6),
+								SkipUnavailable:        ptr.To(true),
+							}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Reset the options of a remote cluster back to sniff mode, delete a remote cluster in proxy mode",
+			args: args{
+				esClient: &fakeESClient{
+					existingSettings: esclient.RemoteClustersSettings{
+						PersistentSettings: &esclient.SettingsGroup{
+							Cluster: esclient.RemoteClusters{
+								RemoteClusters: map[string]esclient.RemoteCluster{
+									"ns2-es2": {RemoteClusterOptions: &esclient.RemoteClusterOptions{
+										Mode:         ptr.To("proxy"),
+										ProxyAddress: ptr.To("es2-es-transport.ns2.svc:9300"),
+									}},
+									"ns2-es3": {RemoteClusterOptions: &esclient.RemoteClusterOptions{
+										Mode:            ptr.To("proxy"),
+										ProxyAddress:    ptr.To("es3-es-transport.ns2.svc:9300"),
+										SkipUnavailable: ptr.To(true),
+									}},
+								},
+							},
+						},
+					},
+				},
+				licenseChecker: &license.MockLicenseChecker{EnterpriseEnabled: true},
+				es: newEsWithRemoteClusters(
+					"ns1",
+					"es1",
+					map[string]string{ManagedRemoteClustersAnnotationName: "ns2-es2,ns2-es3"},
+					esv1.RemoteCluster{
+						Name:             "ns2-es2",
+						ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es2", Namespace: "ns2"},
+					},
+				),
+			},
+			wantAnnotation:                        "ns2-es2,ns2-es3",
+			wantRequeue:                           true,
+			wantGetRemoteClusterSettingsCalled:    true,
+			wantUpdateRemoteClusterSettingsCalled: true,
+			wantSettings: esclient.RemoteClustersSettings{
+				PersistentSettings: &esclient.SettingsGroup{
+					Cluster: esclient.RemoteClusters{
+						RemoteClusters: map[string]esclient.RemoteCluster{
+							"ns2-es2": {Seeds: []string{"es2-es-transport.ns2.svc:9300"}, RemoteClusterOptions: &esclient.RemoteClusterOptions{}},
+							"ns2-es3": {Seeds: nil, RemoteClusterOptions: &esclient.RemoteClusterOptions{}},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "Create a new remote cluster",
 			args: args{
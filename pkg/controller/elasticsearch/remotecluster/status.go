@@ -0,0 +1,103 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package remotecluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+)
+
+const (
+	// StatusCheckInterval is the interval at which the connections to the remote clusters are checked again, since
+	// a lost connection does not trigger any reconciliation.
+	StatusCheckInterval = 5 * time.Minute
+
+	notConfiguredMsg = "Remote cluster is not configured in Elasticsearch"
+	notConnectedMsg  = "No node of the remote cluster is connected"
+)
+
+// ReportStatus reports the state of the connections to the remote clusters declared in the specification, as returned
+// by the remote cluster info API, in the status of the cluster. A warning event is emitted for each remote cluster
+// whose connection was lost since the last check.
+func ReportStatus(ctx context.Context, esClient esclient.Client, es esv1.Elasticsearch, reconcileState *reconcile.State) error {
+	remoteClustersInSpec := getRemoteClustersInSpec(es)
+	if len(remoteClustersInSpec) == 0 {
+		reconcileState.UpdateRemoteClusters(nil)
+		return nil
+	}
+	info, err := esClient.GetRemoteClusterInfo(ctx)
+	if err != nil {
+		return err
+	}
+	statuses := remoteClustersStatus(es.Status.RemoteClusters, remoteClustersInSpec, info, metav1.Now())
+	for _, status := range statuses {
+		if !status.Connected && wasConnected(es.Status.RemoteClusters, status.Name) {
+			reconcileState.AddEvent(
+				corev1.EventTypeWarning,
+				events.EventReasonUnhealthy,
+				fmt.Sprintf("Remote cluster %s is disconnected: %s", status.Name, status.LastError),
+			)
+		}
+	}
+	reconcileState.UpdateRemoteClusters(statuses)
+	return nil
+}
+
+// remoteClustersStatus returns the status of the remote clusters in the specification, sorted by name. The time of the
+// last transition is preserved from the previous status if the connection state did not change.
+func remoteClustersStatus(
+	previous []esv1.RemoteClusterStatus,
+	remoteClustersInSpec map[string]esv1.RemoteCluster,
+	info esclient.RemoteClustersInfo,
+	now metav1.Time,
+) []esv1.RemoteClusterStatus {
+	statuses := make([]esv1.RemoteClusterStatus, 0, len(remoteClustersInSpec))
+	for name := range remoteClustersInSpec {
+		status := esv1.RemoteClusterStatus{Name: name}
+		remoteClusterInfo, exists := info[name]
+		switch {
+		case !exists:
+			status.LastError = notConfiguredMsg
+		case remoteClusterInfo.Connected:
+			status.Connected = true
+			status.ConnectedNodes = int32(remoteClusterInfo.NumNodesConnected)
+		default:
+			status.LastError = notConnectedMsg
+		}
+		status.LastTransitionTime = now
+		if previousStatus := findStatus(previous, name); previousStatus != nil && previousStatus.Connected == status.Connected {
+			status.LastTransitionTime = previousStatus.LastTransitionTime
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+func findStatus(statuses []esv1.RemoteClusterStatus, name string) *esv1.RemoteClusterStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+func wasConnected(statuses []esv1.RemoteClusterStatus, name string) bool {
+	status := findStatus(statuses, name)
+	return status != nil && status.Connected
+}
@@ -0,0 +1,136 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package remotecluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+)
+
+type remoteInfoESClient struct {
+	esclient.Client
+	info   esclient.RemoteClustersInfo
+	err    error
+	called bool
+}
+
+func (c *remoteInfoESClient) GetRemoteClusterInfo(_ context.Context) (esclient.RemoteClustersInfo, error) {
+	c.called = true
+	return c.info, c.err
+}
+
+func TestReportStatus(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	remoteClusters := []esv1.RemoteCluster{
+		{Name: "ns2-es2", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es2", Namespace: "ns2"}},
+		{Name: "ns1-es3", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es3"}},
+	}
+	tests := []struct {
+		name           string
+		remoteClusters []esv1.RemoteCluster
+		previous       []esv1.RemoteClusterStatus
+		info           esclient.RemoteClustersInfo
+		err            error
+		wantCalled     bool
+		wantErr        bool
+		want           []esv1.RemoteClusterStatus
+		wantEvents     int
+	}{
+		{
+			name: "no remote cluster",
+		},
+		{
+			name:     "remote clusters removed from the specification",
+			previous: []esv1.RemoteClusterStatus{{Name: "ns2-es2", Connected: true, ConnectedNodes: 3, LastTransitionTime: earlier}},
+		},
+		{
+			name:           "connected and not configured remote clusters",
+			remoteClusters: remoteClusters,
+			info: esclient.RemoteClustersInfo{
+				"ns2-es2":      {Connected: true, Mode: "sniff", NumNodesConnected: 3},
+				"user-defined": {Connected: true, Mode: "sniff", NumNodesConnected: 1},
+			},
+			wantCalled: true,
+			want: []esv1.RemoteClusterStatus{
+				{Name: "ns1-es3", LastError: notConfiguredMsg},
+				{Name: "ns2-es2", Connected: true, ConnectedNodes: 3},
+			},
+		},
+		{
+			name:           "connection lost",
+			remoteClusters: remoteClusters,
+			previous: []esv1.RemoteClusterStatus{
+				{Name: "ns1-es3", Connected: true, ConnectedNodes: 1, LastTransitionTime: earlier},
+				{Name: "ns2-es2", Connected: true, ConnectedNodes: 3, LastTransitionTime: earlier},
+			},
+			info: esclient.RemoteClustersInfo{
+				"ns1-es3": {Connected: true, Mode: "sniff", NumNodesConnected: 2},
+				"ns2-es2": {Connected: false, Mode: "sniff"},
+			},
+			wantCalled: true,
+			want: []esv1.RemoteClusterStatus{
+				{Name: "ns1-es3", Connected: true, ConnectedNodes: 2, LastTransitionTime: earlier},
+				{Name: "ns2-es2", LastError: notConnectedMsg},
+			},
+			wantEvents: 1,
+		},
+		{
+			name:           "still disconnected",
+			remoteClusters: remoteClusters[:1],
+			previous:       []esv1.RemoteClusterStatus{{Name: "ns2-es2", LastError: notConnectedMsg, LastTransitionTime: earlier}},
+			info:           esclient.RemoteClustersInfo{"ns2-es2": {Connected: false, Mode: "sniff"}},
+			wantCalled:     true,
+			want:           []esv1.RemoteClusterStatus{{Name: "ns2-es2", LastError: notConnectedMsg, LastTransitionTime: earlier}},
+		},
+		{
+			name:           "remote cluster info error keeps the previous status",
+			remoteClusters: remoteClusters[:1],
+			previous:       []esv1.RemoteClusterStatus{{Name: "ns2-es2", Connected: true, ConnectedNodes: 3, LastTransitionTime: earlier}},
+			err:            errors.New("boom"),
+			wantCalled:     true,
+			wantErr:        true,
+			want:           []esv1.RemoteClusterStatus{{Name: "ns2-es2", Connected: true, ConnectedNodes: 3, LastTransitionTime: earlier}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "es1"},
+				Spec:       esv1.ElasticsearchSpec{RemoteClusters: tt.remoteClusters},
+				Status:     esv1.ElasticsearchStatus{RemoteClusters: tt.previous},
+			}
+			esClient := &remoteInfoESClient{info: tt.info, err: tt.err}
+			reconcileState := reconcile.MustNewState(es)
+			err := ReportStatus(context.Background(), esClient, es, reconcileState)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantCalled, esClient.called)
+
+			events, updated := reconcileState.Apply()
+			require.Len(t, events, tt.wantEvents)
+			got := es.Status.RemoteClusters
+			if updated != nil {
+				got = updated.Status.RemoteClusters
+			}
+			// new transitions are recorded at the time of the check
+			for i := range got {
+				if !got[i].LastTransitionTime.Equal(&earlier) {
+					require.False(t, got[i].LastTransitionTime.IsZero())
+					got[i].LastTransitionTime = metav1.Time{}
+				}
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/externaldns"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
@@ -102,6 +103,7 @@ func NewExternalService(es esv1.Elasticsearch) *corev1.Service {
 
 	svc.ObjectMeta.Namespace = es.Namespace
 	svc.ObjectMeta.Name = ExternalServiceName(es.Name)
+	svc.ObjectMeta.Annotations = externaldns.ServiceAnnotations(es.Spec.HTTP)
 
 	labels := label.NewLabels(nsn)
 	ports := []corev1.ServicePort{
@@ -115,6 +117,36 @@ func NewExternalService(es esv1.Elasticsearch) *corev1.Service {
 	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
 }
 
+// NewServiceProfileService returns the HTTP Service of the given service profile.
+// It targets the Pods labeled by the operator as matching the roles of the profile.
+func NewServiceProfileService(es esv1.Elasticsearch, profile esv1.ServiceProfile) *corev1.Service {
+	nsn := k8s.ExtractNamespacedName(&es)
+
+	svc := corev1.Service{
+		ObjectMeta: *profile.Service.ObjectMeta.DeepCopy(),
+		Spec:       *profile.Service.Spec.DeepCopy(),
+	}
+
+	svc.ObjectMeta.Namespace = es.Namespace
+	svc.ObjectMeta.Name = esv1.ServiceProfileService(es.Name, profile.Name)
+
+	labels := label.NewLabels(nsn)
+	ports := []corev1.ServicePort{
+		{
+			Name:     es.Spec.HTTP.Protocol(),
+			Protocol: corev1.ProtocolTCP,
+			Port:     network.HTTPPort,
+		},
+	}
+	defaults.SetServiceDefaults(&svc, labels, nil, ports)
+
+	// the profile label and the selector cannot be overridden, they are used to manage the Service
+	svc.Labels[label.ServiceProfileLabelName] = profile.Name
+	svc.Spec.Selector = label.NewLabels(nsn)
+	svc.Spec.Selector[label.ServiceProfilePodLabel(profile.Name)] = "true"
+	return &svc
+}
+
 // NewInternalService returns the internal service associated to the given cluster.
 // It is used by the operator to perform requests against the Elasticsearch cluster nodes,
 // and does not inherit the spec defined within the Elasticsearch custom resource,
@@ -160,6 +160,36 @@ func TestNewInternalService(t *testing.T) {
 	}
 }
 
+func TestNewServiceProfileService(t *testing.T) {
+	es := mkElasticsearch(commonv1.HTTPConfig{})
+	profile := esv1.ServiceProfile{
+		Name:  "coordinating",
+		Roles: []string{esv1.CoordinatingOnlyProfileRole},
+		Service: commonv1.ServiceTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"custom": "label", label.ServiceProfileLabelName: "other"},
+				Annotations: map[string]string{"custom": "annotation"},
+			},
+			Spec: corev1.ServiceSpec{
+				Type:     corev1.ServiceTypeLoadBalancer,
+				Selector: map[string]string{"custom": "selector"},
+			},
+		},
+	}
+	want := mkHTTPService()
+	want.Name = "elasticsearch-test-es-coordinating-http"
+	want.Labels["custom"] = "label"
+	want.Labels[label.ServiceProfileLabelName] = "coordinating"
+	want.Annotations = map[string]string{"custom": "annotation"}
+	want.Spec.Type = corev1.ServiceTypeLoadBalancer
+	want.Spec.Ports[0].Name = "https"
+	want.Spec.Selector[label.ServiceProfilePodLabel("coordinating")] = "true"
+
+	compare.JSONEqual(t, want, NewServiceProfileService(es, profile))
+	// the template of the profile is left untouched
+	require.Equal(t, map[string]string{"custom": "selector"}, profile.Service.Spec.Selector)
+}
+
 func mkHTTPService() corev1.Service {
 	return corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,41 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+)
+
+// auditingConfig returns the configuration enabling the audit log of the given auditing specification. It is empty if
+// auditing is not set, leaving the audit log settings of the user configuration untouched.
+func auditingConfig(auditing *esv1.Auditing) *CanonicalConfig {
+	cfg := map[string]interface{}{}
+	if auditing == nil {
+		return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
+	}
+
+	cfg[esv1.XPackSecurityAuditEnabled] = true
+	if len(auditing.IncludeEvents) > 0 {
+		cfg[esv1.XPackSecurityAuditLogfileEventsInclude] = auditing.IncludeEvents
+	}
+	if len(auditing.ExcludeEvents) > 0 {
+		cfg[esv1.XPackSecurityAuditLogfileEventsExclude] = auditing.ExcludeEvents
+	}
+	for _, filter := range auditing.IgnoreFilters {
+		setting := func(name string, values []string) {
+			if len(values) > 0 {
+				cfg[esv1.XPackSecurityAuditLogfileEventsIgnoreFilters+"."+filter.Name+"."+name] = values
+			}
+		}
+		setting("users", filter.Users)
+		setting("realms", filter.Realms)
+		setting("actions", filter.Actions)
+		setting("roles", filter.Roles)
+		setting("indices", filter.Indices)
+	}
+
+	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
+}
@@ -0,0 +1,44 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+)
+
+func Test_auditingConfig(t *testing.T) {
+	auditing := &esv1.Auditing{
+		IncludeEvents: []string{"access_denied", "authentication_failed"},
+		ExcludeEvents: []string{"anonymous_access_denied"},
+		IgnoreFilters: []esv1.AuditIgnoreFilter{
+			{Name: "monitoring", Users: []string{"elastic-internal-monitoring"}, Realms: []string{"file1"}},
+			{Name: "logs", Indices: []string{"logs-*"}, Actions: []string{"indices:data/write/*"}, Roles: []string{"writer"}},
+		},
+	}
+	expected := common.MustCanonicalConfig(map[string]interface{}{
+		"xpack.security.audit.enabled":                                         true,
+		"xpack.security.audit.logfile.events.include":                          []string{"access_denied", "authentication_failed"},
+		"xpack.security.audit.logfile.events.exclude":                          []string{"anonymous_access_denied"},
+		"xpack.security.audit.logfile.events.ignore_filters.monitoring.users":  []string{"elastic-internal-monitoring"},
+		"xpack.security.audit.logfile.events.ignore_filters.monitoring.realms": []string{"file1"},
+		"xpack.security.audit.logfile.events.ignore_filters.logs.indices":      []string{"logs-*"},
+		"xpack.security.audit.logfile.events.ignore_filters.logs.actions":      []string{"indices:data/write/*"},
+		"xpack.security.audit.logfile.events.ignore_filters.logs.roles":        []string{"writer"},
+	})
+	require.Empty(t, auditingConfig(auditing).Diff(expected, nil))
+
+	// only the audit log is enabled with the default events
+	require.Empty(t, auditingConfig(&esv1.Auditing{}).Diff(common.MustCanonicalConfig(map[string]interface{}{
+		"xpack.security.audit.enabled": true,
+	}), nil))
+
+	// no auditing, no setting
+	require.Empty(t, auditingConfig(nil).Diff(common.NewCanonicalConfig(), nil))
+}
@@ -13,6 +13,10 @@ const (
 	EnvReadinessProbeProtocol = "READINESS_PROBE_PROTOCOL"
 	HeadlessServiceName       = "HEADLESS_SERVICE_NAME"
 
+	// EnvKeystorePassphraseFile is the path of the file holding the password of the keystore, read by Elasticsearch
+	// and by the keystore init container.
+	EnvKeystorePassphraseFile = "ES_KEYSTORE_PASSPHRASE_FILE"
+
 	// These are injected as env var into the ES pod at runtime,
 	// to be referenced in ES configuration file
 	EnvPodName   = "POD_NAME"
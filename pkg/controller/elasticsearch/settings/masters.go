@@ -95,9 +95,7 @@ func UpdateSeedHostsConfigMap(
 			NeedsUpdate: func() bool {
 				return !reflect.DeepEqual(expected.Data, reconciled.Data)
 			},
-			UpdateReconciled: func() {
-				reconciled.Data = expected.Data
-			},
+			ServerSideApply: true,
 			PreCreate: func() error {
 				log.Info("Creating seed hosts", "namespace", es.Namespace, "es_name", es.Name, "hosts", seedHosts)
 				return nil
@@ -32,7 +32,12 @@ func NewMergedESConfig(
 	ver version.Version,
 	ipFamily corev1.IPFamily,
 	httpConfig commonv1.HTTPConfig,
+	auth esv1.Auth,
+	auditing *esv1.Auditing,
 	userConfig commonv1.Config,
+	additionalVolumes []esv1.AdditionalVolume,
+	zone string,
+	clusterZoneAware bool,
 	esConfigFromStackConfigPolicy *common.CanonicalConfig,
 ) (CanonicalConfig, error) {
 	userCfg, err := common.NewCanonicalConfigFrom(userConfig.Data)
@@ -40,9 +45,12 @@ func NewMergedESConfig(
 		return CanonicalConfig{}, err
 	}
 
-	config := baseConfig(clusterName, ver, ipFamily).CanonicalConfig
+	config := baseConfig(clusterName, ver, ipFamily, additionalVolumes).CanonicalConfig
 	err = config.MergeWith(
 		xpackConfig(ver, httpConfig).CanonicalConfig,
+		ssoRealmsConfig(auth).CanonicalConfig,
+		auditingConfig(auditing).CanonicalConfig,
+		zoneConfig(zone, clusterZoneAware).CanonicalConfig,
 		userCfg,
 		esConfigFromStackConfigPolicy,
 	)
@@ -53,7 +61,7 @@ func NewMergedESConfig(
 }
 
 // baseConfig returns the base ES configuration to apply for the given cluster
-func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamily) *CanonicalConfig {
+func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamily, additionalVolumes []esv1.AdditionalVolume) *CanonicalConfig {
 	cfg := map[string]interface{}{
 		// derive node name dynamically from the pod name, injected as env var
 		esv1.NodeName:    "${" + EnvPodName + "}",
@@ -72,6 +80,11 @@ func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamil
 		esv1.PathLogs: volume.ElasticsearchLogsMountPath,
 	}
 
+	// list all the data paths only if the nodeSet has additional data volumes
+	if dataPaths := volume.DataPaths(additionalVolumes); len(dataPaths) > 1 {
+		cfg[esv1.PathData] = dataPaths
+	}
+
 	// seed hosts setting name changed starting ES 7.X
 	fileProvider := "file"
 	if ver.Major < 7 {
@@ -90,6 +103,19 @@ func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamil
 	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
 }
 
+// zoneConfig returns the zone node attribute of the nodes of a zone-aware NodeSet, and the allocation awareness
+// settings taking the zones into account if all the NodeSets of the cluster are zone-aware.
+func zoneConfig(zone string, clusterZoneAware bool) *CanonicalConfig {
+	cfg := map[string]interface{}{}
+	if zone != "" {
+		cfg[esv1.NodeAttr+"."+esv1.ZoneAttr] = zone
+	}
+	if clusterZoneAware {
+		cfg[esv1.ShardAwarenessAttributes] = nodeAttrK8sNodeName + "," + esv1.ZoneAttr
+	}
+	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
+}
+
 // xpackConfig returns the configuration bit related to XPack settings
 func xpackConfig(ver version.Version, httpCfg commonv1.HTTPConfig) *CanonicalConfig {
 	// enable x-pack security, including TLS
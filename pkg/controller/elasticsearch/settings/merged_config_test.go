@@ -35,6 +35,9 @@ func TestNewMergedESConfig(t *testing.T) {
 		Network struct {
 			PublishHost string `yaml:"publish_host"`
 		} `yaml:"network"`
+		Path struct {
+			Data interface{} `yaml:"data"`
+		} `yaml:"path"`
 	}
 
 	policyCfg := common.MustCanonicalConfig(map[string]interface{}{
@@ -47,7 +50,9 @@ func TestNewMergedESConfig(t *testing.T) {
 		ipFamily      corev1.IPFamily
 		cfgData       map[string]interface{}
 		policyCfgData *common.CanonicalConfig
-		assert        func(cfg CanonicalConfig)
+		// additionalVolumes of the nodeSet
+		additionalVolumes []esv1.AdditionalVolume
+		assert            func(cfg CanonicalConfig)
 	}{
 		{
 			name:     "in 6.x, empty config should have the default file and native realm settings configured",
@@ -254,14 +259,70 @@ func TestNewMergedESConfig(t *testing.T) {
 				require.Equal(t, "[${POD_IP}]", esCfg.Network.PublishHost)
 			},
 		},
+		{
+			name:     "additional data volumes are appended to the data paths",
+			version:  "7.17.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData:  map[string]interface{}{},
+			additionalVolumes: []esv1.AdditionalVolume{
+				{Name: "disk-1", Usage: esv1.DataVolumeUsage},
+				{Name: "logs", Usage: esv1.LogsVolumeUsage},
+				{Name: "disk-2", Usage: esv1.DataVolumeUsage},
+			},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &elasticsearchCfg{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.Equal(t, []interface{}{
+					"/usr/share/elasticsearch/data",
+					"/usr/share/elasticsearch/data-volumes/disk-1",
+					"/usr/share/elasticsearch/data-volumes/disk-2",
+				}, esCfg.Path.Data)
+			},
+		},
+		{
+			name:              "a single data path is kept without additional data volumes",
+			version:           "7.17.0",
+			ipFamily:          corev1.IPv4Protocol,
+			cfgData:           map[string]interface{}{},
+			additionalVolumes: []esv1.AdditionalVolume{{Name: "tmp", Usage: esv1.TempVolumeUsage}},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &elasticsearchCfg{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.Equal(t, "/usr/share/elasticsearch/data", esCfg.Path.Data)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ver, err := version.Parse(tt.version)
 			require.NoError(t, err)
-			cfg, err := NewMergedESConfig("clusterName", ver, tt.ipFamily, commonv1.HTTPConfig{}, commonv1.Config{Data: tt.cfgData}, tt.policyCfgData)
+			cfg, err := NewMergedESConfig("clusterName", ver, tt.ipFamily, commonv1.HTTPConfig{}, esv1.Auth{}, nil, commonv1.Config{Data: tt.cfgData}, tt.additionalVolumes, "", false, tt.policyCfgData)
 			require.NoError(t, err)
 			tt.assert(cfg)
 		})
 	}
 }
+
+func Test_zoneConfig(t *testing.T) {
+	ver := version.MustParse("8.15.0")
+	cfg, err := NewMergedESConfig("clusterName", ver, corev1.IPv4Protocol, commonv1.HTTPConfig{}, esv1.Auth{}, nil, commonv1.Config{}, nil, "us-east-1a", true, nil)
+	require.NoError(t, err)
+	zone, err := cfg.String(esv1.NodeAttr + "." + esv1.ZoneAttr)
+	require.NoError(t, err)
+	require.Equal(t, "us-east-1a", zone)
+	awareness, err := cfg.String(esv1.ShardAwarenessAttributes)
+	require.NoError(t, err)
+	require.Equal(t, "k8s_node_name,zone", awareness)
+
+	// the user configuration takes precedence
+	cfg, err = NewMergedESConfig("clusterName", ver, corev1.IPv4Protocol, commonv1.HTTPConfig{}, esv1.Auth{}, nil,
+		commonv1.Config{Data: map[string]interface{}{esv1.ShardAwarenessAttributes: "zone"}}, nil, "us-east-1a", true, nil)
+	require.NoError(t, err)
+	awareness, err = cfg.String(esv1.ShardAwarenessAttributes)
+	require.NoError(t, err)
+	require.Equal(t, "zone", awareness)
+}
@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"strings"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+// Paths of the Kibana endpoints the identity providers redirect the users to.
+const (
+	kibanaOIDCCallbackPath   = "/api/security/oidc/callback"
+	kibanaOIDCLoggedOutPath  = "/security/logged_out"
+	kibanaSAMLCallbackPath   = "/api/security/saml/callback"
+	kibanaSAMLLogoutPath     = "/logout"
+	defaultOIDCPrincipal     = "sub"
+	defaultSAMLPrincipal     = "nameid:persistent"
+	defaultOIDCRequestScopes = "openid"
+)
+
+// ssoRealmsConfig returns the configuration of the OpenID Connect and SAML realms of the given auth specification,
+// with the redirect URLs of the Kibana users log in to. The client secrets of the OpenID Connect realms are loaded in
+// the keystore and the metadata of the SAML identity providers are mounted from their Secret, if any.
+func ssoRealmsConfig(auth esv1.Auth) *CanonicalConfig {
+	cfg := map[string]interface{}{}
+
+	for i, realm := range auth.OIDC {
+		setting := func(name string, value interface{}) {
+			cfg[esv1.SSORealmSetting(esv1.OIDCRealmType, realm.Name, name)] = value
+		}
+		kibanaURL := strings.TrimSuffix(realm.KibanaURL, "/")
+		setting("order", realm.OrderOrDefault(i))
+		setting("rp.client_id", realm.ClientID)
+		setting("rp.response_type", "code")
+		setting("rp.redirect_uri", kibanaURL+kibanaOIDCCallbackPath)
+		setting("rp.post_logout_redirect_uri", kibanaURL+kibanaOIDCLoggedOutPath)
+		scopes := realm.RequestedScopes
+		if len(scopes) == 0 {
+			scopes = []string{defaultOIDCRequestScopes}
+		}
+		setting("rp.requested_scopes", scopes)
+		setting("op.issuer", realm.Issuer)
+		setting("op.authorization_endpoint", realm.AuthorizationEndpoint)
+		setting("op.token_endpoint", realm.TokenEndpoint)
+		setting("op.jwkset_path", realm.JWKSetURL)
+		if realm.UserinfoEndpoint != "" {
+			setting("op.userinfo_endpoint", realm.UserinfoEndpoint)
+		}
+		if realm.EndSessionEndpoint != "" {
+			setting("op.endsession_endpoint", realm.EndSessionEndpoint)
+		}
+		principal := realm.PrincipalClaim
+		if principal == "" {
+			principal = defaultOIDCPrincipal
+		}
+		setting("claims.principal", principal)
+		if realm.GroupsClaim != "" {
+			setting("claims.groups", realm.GroupsClaim)
+		}
+	}
+
+	for i, realm := range auth.SAML {
+		setting := func(name string, value interface{}) {
+			cfg[esv1.SSORealmSetting(esv1.SAMLRealmType, realm.Name, name)] = value
+		}
+		kibanaURL := strings.TrimSuffix(realm.KibanaURL, "/")
+		setting("order", realm.OrderOrDefault(len(auth.OIDC)+i))
+		setting("idp.metadata.path", volume.SAMLMetadataPath(realm))
+		setting("idp.entity_id", realm.IdPEntityID)
+		setting("sp.entity_id", kibanaURL)
+		setting("sp.acs", kibanaURL+kibanaSAMLCallbackPath)
+		setting("sp.logout", kibanaURL+kibanaSAMLLogoutPath)
+		principal := realm.PrincipalAttribute
+		if principal == "" {
+			principal = defaultSAMLPrincipal
+		}
+		setting("attributes.principal", principal)
+		if realm.GroupsAttribute != "" {
+			setting("attributes.groups", realm.GroupsAttribute)
+		}
+	}
+
+	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
+}
@@ -0,0 +1,78 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+)
+
+func Test_ssoRealmsConfig(t *testing.T) {
+	auth := esv1.Auth{
+		OIDC: []esv1.OIDCRealm{{
+			SSORealm:              esv1.SSORealm{Name: "okta", KibanaRef: commonv1.LocalObjectSelector{Name: "kb"}, KibanaURL: "https://kibana.example.com/"},
+			Issuer:                "https://example.okta.com",
+			AuthorizationEndpoint: "https://example.okta.com/oauth2/v1/authorize",
+			TokenEndpoint:         "https://example.okta.com/oauth2/v1/token",
+			JWKSetURL:             "https://example.okta.com/oauth2/v1/keys",
+			ClientID:              "eck",
+			ClientSecret:          esv1.SecretKeyRef{SecretName: "okta-client", Key: "secret"},
+			GroupsClaim:           "groups",
+		}},
+		SAML: []esv1.SAMLRealm{
+			{
+				SSORealm:    esv1.SSORealm{Name: "azure", KibanaRef: commonv1.LocalObjectSelector{Name: "kb"}, KibanaURL: "https://kibana.example.com"},
+				IdPMetadata: esv1.SAMLMetadataSource{URL: "https://login.microsoftonline.com/metadata.xml"},
+				IdPEntityID: "https://sts.windows.net/tenant/",
+			},
+			{
+				SSORealm:           esv1.SSORealm{Name: "adfs", Order: ptr.To[int32](10), KibanaRef: commonv1.LocalObjectSelector{Name: "kb"}, KibanaURL: "https://kibana.example.com"},
+				IdPMetadata:        esv1.SAMLMetadataSource{Secret: &esv1.SecretKeyRef{SecretName: "adfs-metadata", Key: "idp.xml"}},
+				IdPEntityID:        "http://adfs.example.com/adfs/services/trust",
+				PrincipalAttribute: "nameid",
+			},
+		},
+	}
+	expected := common.MustCanonicalConfig(map[string]interface{}{
+		"xpack.security.authc.realms.oidc.okta.order":                       1,
+		"xpack.security.authc.realms.oidc.okta.rp.client_id":                "eck",
+		"xpack.security.authc.realms.oidc.okta.rp.response_type":            "code",
+		"xpack.security.authc.realms.oidc.okta.rp.redirect_uri":             "https://kibana.example.com/api/security/oidc/callback",
+		"xpack.security.authc.realms.oidc.okta.rp.post_logout_redirect_uri": "https://kibana.example.com/security/logged_out",
+		"xpack.security.authc.realms.oidc.okta.rp.requested_scopes":         []string{"openid"},
+		"xpack.security.authc.realms.oidc.okta.op.issuer":                   "https://example.okta.com",
+		"xpack.security.authc.realms.oidc.okta.op.authorization_endpoint":   "https://example.okta.com/oauth2/v1/authorize",
+		"xpack.security.authc.realms.oidc.okta.op.token_endpoint":           "https://example.okta.com/oauth2/v1/token",
+		"xpack.security.authc.realms.oidc.okta.op.jwkset_path":              "https://example.okta.com/oauth2/v1/keys",
+		"xpack.security.authc.realms.oidc.okta.claims.principal":            "sub",
+		"xpack.security.authc.realms.oidc.okta.claims.groups":               "groups",
+
+		"xpack.security.authc.realms.saml.azure.order":                2,
+		"xpack.security.authc.realms.saml.azure.idp.metadata.path":    "https://login.microsoftonline.com/metadata.xml",
+		"xpack.security.authc.realms.saml.azure.idp.entity_id":        "https://sts.windows.net/tenant/",
+		"xpack.security.authc.realms.saml.azure.sp.entity_id":         "https://kibana.example.com",
+		"xpack.security.authc.realms.saml.azure.sp.acs":               "https://kibana.example.com/api/security/saml/callback",
+		"xpack.security.authc.realms.saml.azure.sp.logout":            "https://kibana.example.com/logout",
+		"xpack.security.authc.realms.saml.azure.attributes.principal": "nameid:persistent",
+
+		"xpack.security.authc.realms.saml.adfs.order":                10,
+		"xpack.security.authc.realms.saml.adfs.idp.metadata.path":    "/usr/share/elasticsearch/config/saml/adfs/idp.xml",
+		"xpack.security.authc.realms.saml.adfs.idp.entity_id":        "http://adfs.example.com/adfs/services/trust",
+		"xpack.security.authc.realms.saml.adfs.sp.entity_id":         "https://kibana.example.com",
+		"xpack.security.authc.realms.saml.adfs.sp.acs":               "https://kibana.example.com/api/security/saml/callback",
+		"xpack.security.authc.realms.saml.adfs.sp.logout":            "https://kibana.example.com/logout",
+		"xpack.security.authc.realms.saml.adfs.attributes.principal": "nameid",
+	})
+	require.Empty(t, ssoRealmsConfig(auth).Diff(expected, nil))
+
+	// no realm, no setting
+	require.Empty(t, ssoRealmsConfig(esv1.Auth{}).Diff(common.NewCanonicalConfig(), nil))
+}
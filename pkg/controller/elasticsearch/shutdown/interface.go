@@ -18,6 +18,10 @@ var MinVersion = version.MinFor(7, 15, 2)
 type NodeShutdownStatus struct {
 	Status      esclient.ShutdownStatus
 	Explanation string
+	// RemainingShards is the number of shards still to be migrated away from the node, if known.
+	RemainingShards *int
+	// RemainingBytes is the size in bytes of the shards still to be migrated away from the node, if known.
+	RemainingBytes *int64
 }
 
 // Interface defines methods that both legacy shard migration based shutdown and new API based shutdowns implement to
@@ -8,8 +8,10 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/utils/ptr"
 
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 )
@@ -22,8 +24,11 @@ type NodeShutdown struct {
 	reason      string
 	podToNodeID map[string]string
 	shutdowns   map[string]esclient.NodeShutdown
-	once        sync.Once
-	log         logr.Logger
+	// allocationDelays overrides per Pod name the time Elasticsearch waits for a restarting node before reallocating
+	// its shards.
+	allocationDelays map[string]time.Duration
+	once             sync.Once
+	log              logr.Logger
 }
 
 var _ Interface = &NodeShutdown{}
@@ -42,6 +47,17 @@ func NewNodeShutdown(c esclient.Client, podToNodeID map[string]string, typ escli
 	}
 }
 
+// WithAllocationDelay sets the time Elasticsearch waits for the given nodes to rejoin the cluster after a restart before
+// reallocating their shards, instead of the default delay. Only relevant for shutdowns of type restart.
+func (ns *NodeShutdown) WithAllocationDelay(delay time.Duration, podNames ...string) {
+	if ns.allocationDelays == nil {
+		ns.allocationDelays = make(map[string]time.Duration, len(podNames))
+	}
+	for _, podName := range podNames {
+		ns.allocationDelays[podName] = delay
+	}
+}
+
 func (ns *NodeShutdown) initOnce(ctx context.Context) error {
 	var err error
 	ns.once.Do(func() {
@@ -98,9 +114,13 @@ func (ns *NodeShutdown) ReconcileShutdowns(ctx context.Context, leavingNodes []s
 			continue
 		}
 		ns.log.V(1).Info("Requesting shutdown", "type", ns.typ, "node", node, "node_id", nodeID)
-		// in case of type=restart we are relying on the default allocation_delay of 5 min see
+		// in case of type=restart we are relying on the default allocation_delay of 5 min, unless overridden, see
 		// https://www.elastic.co/guide/en/elasticsearch/reference/7.15/put-shutdown.html
-		if err := ns.c.PutShutdown(ctx, nodeID, ns.typ, ns.reason); err != nil {
+		var allocationDelay *time.Duration
+		if delay, exists := ns.allocationDelays[node]; exists {
+			allocationDelay = &delay
+		}
+		if err := ns.c.PutShutdown(ctx, nodeID, ns.typ, ns.reason, allocationDelay); err != nil {
 			return fmt.Errorf("on put shutdown (type: %s) for node %s: %w", ns.typ, node, err)
 		}
 		// update the internal cache with the information about the new shutdown
@@ -131,10 +151,29 @@ func (ns *NodeShutdown) ShutdownStatus(ctx context.Context, podName string) (Nod
 		return NodeShutdownStatus{}, fmt.Errorf("no shutdown in progress for %s", podName)
 	}
 	logStatus(ns.log, podName, shutdown)
-	return NodeShutdownStatus{
+	status := NodeShutdownStatus{
 		Status:      shutdown.Status,
 		Explanation: shutdown.ShardMigration.Explanation,
-	}, nil
+	}
+	if shutdown.Is(esclient.Remove) && shutdown.Status != esclient.ShutdownComplete {
+		status.RemainingShards = ptr.To(shutdown.ShardMigration.ShardMigrationsRemaining)
+		if shutdown.ShardMigration.ShardMigrationsRemaining > 0 {
+			status.RemainingBytes = ns.remainingBytes(ctx, podName)
+		}
+	}
+	return status, nil
+}
+
+// remainingBytes returns the size of the shards still allocated to the given node, or nil if it cannot be retrieved.
+// The shutdown API only reports the number of shards remaining, the size is reported on a best effort basis.
+func (ns *NodeShutdown) remainingBytes(ctx context.Context, podName string) *int64 {
+	shards, err := ns.c.GetShards(ctx)
+	if err != nil {
+		ns.log.V(1).Info("Failed to retrieve the size of the shards remaining on node", "node", podName, "error", err.Error())
+		return nil
+	}
+	_, size := shards.OnNode(podName)
+	return &size
 }
 
 func logStatus(logger logr.Logger, podName string, shutdown esclient.NodeShutdown) {
@@ -12,6 +12,8 @@ import (
 	"reflect"
 	"testing"
 
+	"k8s.io/utils/ptr"
+
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -79,6 +81,11 @@ var (
   ]
 }
 `
+	shardsFixture = `[
+  {"index": "elasticlogs_q-000001", "shard": "0", "state": "STARTED", "prirep": "r", "node": "pod-1", "store": "1024"},
+  {"index": "elasticlogs_q-000001", "shard": "1", "state": "STARTED", "prirep": "p", "node": "pod-1", "store": "2048"},
+  {"index": "elasticlogs_q-000001", "shard": "1", "state": "STARTED", "prirep": "r", "node": "pod-2", "store": "2048"}
+]`
 	stalledShutdownFixture = `{
   "nodes": [
     {
@@ -282,8 +289,10 @@ func TestNodeShutdown_ShutdownStatus(t *testing.T) {
 				podName: "pod-1",
 			},
 			want: NodeShutdownStatus{
-				Status:      esclient.ShutdownStalled,
-				Explanation: "shard [1] [primary] of index [elasticlogs_q-000001] cannot move, use the Cluster Allocation Explain API on this shard for details",
+				Status:          esclient.ShutdownStalled,
+				Explanation:     "shard [1] [primary] of index [elasticlogs_q-000001] cannot move, use the Cluster Allocation Explain API on this shard for details",
+				RemainingShards: ptr.To(4),
+				RemainingBytes:  ptr.To[int64](3072),
 			},
 			wantErr: false,
 		},
@@ -310,9 +319,13 @@ func TestNodeShutdown_ShutdownStatus(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			client := esclient.NewMockClient(version.MustParse("7.15.2"), func(req *http.Request) *http.Response {
+				fixture := tt.fixture
+				if req.URL.Path == "/_cat/shards" {
+					fixture = shardsFixture
+				}
 				return &http.Response{
 					StatusCode: 200,
-					Body:       io.NopCloser(bytes.NewBuffer([]byte(tt.fixture))),
+					Body:       io.NopCloser(bytes.NewBuffer([]byte(fixture))),
 					Header:     make(http.Header),
 					Request:    req,
 				}
@@ -495,3 +508,37 @@ func TestNodeShutdown_ReconcileShutdowns(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeShutdown_ReconcileShutdowns_allocationDelay(t *testing.T) {
+	fixtures := []string{noShutdownFixture, ackFixture, singleRestartShutdownFixture, ackFixture, singleRestartShutdownFixture}
+	var requests []string
+	client := esclient.NewMockClient(version.MustParse("7.15.2"), func(req *http.Request) *http.Response {
+		if req.Method == http.MethodPut {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			requests = append(requests, string(body))
+		}
+		fixture := fixtures[0]
+		fixtures = fixtures[1:]
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBuffer([]byte(fixture))),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	ns := NewNodeShutdown(client, map[string]string{"pod-1": "txXw-Kd2Q6K0PbYMAPzH-Q", "pod-2": "sh013PAoQFqkF92fBv1fzg"}, esclient.Restart, "42", log.Log.WithName("test"))
+	ns.WithAllocationDelay(0, "pod-2")
+	if err := ns.ReconcileShutdowns(context.Background(), []string{"pod-1", "pod-2"}, nil); err != nil {
+		t.Fatalf("ReconcileShutdowns() error = %v", err)
+	}
+	want := []string{
+		`{"type":"restart","reason":"42"}`,
+		`{"type":"restart","reason":"42","allocation_delay":"0s"}`,
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("ReconcileShutdowns() requests = %v, want %v", requests, want)
+	}
+}
@@ -18,6 +18,9 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
 )
 
+// MaxConcurrentReconciles is the maximum number of nodeSets of a single cluster whose resources are reconciled in parallel.
+const MaxConcurrentReconciles = 4
+
 // ReconcileStatefulSet creates or updates the expected StatefulSet.
 func ReconcileStatefulSet(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, expected appsv1.StatefulSet, expectations *expectations.Expectations) (appsv1.StatefulSet, error) {
 	podTemplateValidator := statefulset.NewPodTemplateValidator(ctx, c, &es, expected)
@@ -35,16 +38,11 @@ func ReconcileStatefulSet(ctx context.Context, c k8s.Client, es esv1.Elasticsear
 				// different spec
 				!EqualTemplateHashLabels(expected, reconciled)
 		},
-		UpdateReconciled: func() {
-			// override annotations and labels with expected ones
-			// don't remove additional values in reconciled that may have been defaulted or
-			// manually set by the user on the existing resource
-			reconciled.Labels = maps.Merge(reconciled.Labels, expected.Labels)
-			reconciled.Annotations = maps.Merge(reconciled.Annotations, expected.Annotations)
-			reconciled.Spec = expected.Spec
-		},
-		PreCreate: podTemplateValidator,
-		PreUpdate: podTemplateValidator,
+		// only apply the labels, annotations and spec fields set by the operator, the ones that may have been
+		// defaulted or manually set by the user or another controller on the existing resource are left untouched
+		ServerSideApply: true,
+		PreCreate:       podTemplateValidator,
+		PreUpdate:       podTemplateValidator,
 		PostUpdate: func() {
 			if expectations != nil {
 				// expect the reconciled StatefulSet to be there in the cache for next reconciliations,
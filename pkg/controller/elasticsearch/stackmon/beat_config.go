@@ -22,6 +22,10 @@ var (
 	// filebeatConfig is a static configuration for Filebeat to collect Elasticsearch logs
 	//go:embed filebeat.yml
 	filebeatConfig string
+
+	// filebeatAuditConfig is a static configuration for Filebeat to only collect the Elasticsearch audit log
+	//go:embed filebeat_audit.yml
+	filebeatAuditConfig string
 )
 
 // ReconcileConfigSecrets reconciles the secrets holding beats configuration
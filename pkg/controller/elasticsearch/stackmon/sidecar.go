@@ -60,7 +60,11 @@ func Metricbeat(ctx context.Context, client k8s.Client, es esv1.Elasticsearch) (
 }
 
 func Filebeat(ctx context.Context, client k8s.Client, es esv1.Elasticsearch) (stackmon.BeatSidecar, error) {
-	fileBeat, err := stackmon.NewFileBeatSidecar(ctx, client, &es, es.Spec.Version, filebeatConfig, nil)
+	config := filebeatConfig
+	if es.AuditLogsOnly() {
+		config = filebeatAuditConfig
+	}
+	fileBeat, err := stackmon.NewFileBeatSidecar(ctx, client, &es, es.Spec.Version, config, nil)
 	if err != nil {
 		return stackmon.BeatSidecar{}, err
 	}
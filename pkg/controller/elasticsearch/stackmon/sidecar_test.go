@@ -108,6 +108,20 @@ func TestWithMonitoring(t *testing.T) {
 			podVolumesLength:       3,
 			beatVolumeMountsLength: 4,
 		},
+		{
+			name: "with audit log shipping",
+			es: func() esv1.Elasticsearch {
+				sampleEs.Spec.Monitoring.Metrics.ElasticsearchRefs = nil
+				sampleEs.Spec.Monitoring.Logs.ElasticsearchRefs = nil
+				sampleEs.Spec.Auditing = &esv1.Auditing{ElasticsearchRefs: monitoringEsRef}
+				monitoring.GetLogsAssociation(&sampleEs)[0].SetAssociationConf(&monitoringAssocConf)
+				return sampleEs
+			},
+			containersLength:       2,
+			esEnvVarsLength:        1,
+			podVolumesLength:       3,
+			beatVolumeMountsLength: 4,
+		},
 		{
 			name: "with metrics and logs monitoring",
 			es: func() esv1.Elasticsearch {
@@ -169,6 +183,48 @@ func TestWithMonitoring(t *testing.T) {
 	}
 }
 
+func TestFilebeat_auditLogsOnly(t *testing.T) {
+	ref := []commonv1.ObjectSelector{{Name: "logs", Namespace: "observability"}}
+	assocConf := commonv1.AssociationConf{
+		AuthSecretName: "sample-observability-logs-beat-es-mon-user",
+		AuthSecretKey:  "aerospace-sample-observability-logs-beat-es-mon-user",
+		CACertProvided: true,
+		CASecretName:   "sample-es-logs-observability-monitoring-ca",
+		URL:            "https://logs-es-http.observability.svc:9200",
+		Version:        "7.14.0",
+	}
+	fakeClient := k8s.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample-observability-logs-beat-es-mon-user", Namespace: "aerospace"},
+		Data:       map[string][]byte{"aerospace-sample-observability-logs-beat-es-mon-user": []byte("1234567890")},
+	})
+	es := func(monitoringLogs, auditing []commonv1.ObjectSelector) esv1.Elasticsearch {
+		es := esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: "aerospace"},
+			Spec: esv1.ElasticsearchSpec{
+				Version:    "7.14.0",
+				Monitoring: commonv1.Monitoring{Logs: commonv1.LogsMonitoring{ElasticsearchRefs: monitoringLogs}},
+				Auditing:   &esv1.Auditing{ElasticsearchRefs: auditing},
+			},
+		}
+		monitoring.GetLogsAssociation(&es)[0].SetAssociationConf(&assocConf)
+		return es
+	}
+
+	// only the audit log is collected
+	b, err := Filebeat(context.Background(), fakeClient, es(nil, ref))
+	require.NoError(t, err)
+	config := string(b.ConfigSecret.Data["filebeat.yml"])
+	require.Contains(t, config, "*_audit.json")
+	require.NotContains(t, config, "*_server.json")
+
+	// all the logs, including the audit log, are collected with logs monitoring
+	b, err = Filebeat(context.Background(), fakeClient, es(ref, nil))
+	require.NoError(t, err)
+	config = string(b.ConfigSecret.Data["filebeat.yml"])
+	require.Contains(t, config, "*_audit.json")
+	require.Contains(t, config, "*_server.json")
+}
+
 func assertSecurityContext(t *testing.T, securityContext *corev1.SecurityContext) {
 	t.Helper()
 	require.NotNil(t, securityContext)
@@ -0,0 +1,57 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearch
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/bootstrap"
+	esreconcile "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// reportStalledReconciliation reports with the ReconciliationStalled condition whether the changes of the given cluster
+// have been pending for longer than the configured threshold, and what is not progressing.
+func (r *ReconcileElasticsearch) reportStalledReconciliation(
+	es esv1.Elasticsearch,
+	reconcileState *esreconcile.State,
+	isReconciled bool,
+	pendingChanges string,
+) {
+	if r.ReconciliationStalledThreshold <= 0 {
+		return
+	}
+	since, stalled := r.stalledWatchdog.Observe(k8s.ExtractNamespacedName(&es), !isReconciled)
+	if !stalled {
+		reconcileState.ReportConditionWithReason(esv1.ReconciliationStalled, corev1.ConditionFalse, esv1.NotStalled,
+			fmt.Sprintf("No change pending for longer than %s", r.ReconciliationStalledThreshold))
+		return
+	}
+	reason := stalledReason(es, reconcileState)
+	message := fmt.Sprintf("Changes pending since %s: %s", since.UTC().Format(time.RFC3339), pendingChanges)
+	if index := es.Status.Conditions.Index(esv1.ReconciliationStalled); index < 0 || es.Status.Conditions[index].Status != corev1.ConditionTrue {
+		reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonStalled, fmt.Sprintf("Reconciliation stalled (%s). %s", reason, message))
+	}
+	reconcileState.ReportConditionWithReason(esv1.ReconciliationStalled, corev1.ConditionTrue, reason, message)
+}
+
+// stalledReason returns the most likely reason why the changes of the given cluster are not progressing.
+func stalledReason(es esv1.Elasticsearch, reconcileState *esreconcile.State) string {
+	switch {
+	case !bootstrap.AnnotatedForBootstrap(es):
+		return esv1.StalledBootstrapNotComplete
+	case reconcileState.Phase() == esv1.ElasticsearchMigratingDataPhase:
+		return esv1.StalledDataMigrationNotProgressing
+	case reconcileState.AvailableNodes() < es.Spec.NodeCount():
+		return esv1.StalledNodesNotJoining
+	default:
+		return esv1.StalledChangesPending
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	commondriver "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/bootstrap"
+	esreconcile "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+)
+
+func stalledCondition(t *testing.T, state *esreconcile.State) (commonv1alpha1.Condition, int) {
+	t.Helper()
+	events, es := state.Apply()
+	require.NotNil(t, es)
+	index := es.Status.Conditions.Index(esv1.ReconciliationStalled)
+	require.GreaterOrEqual(t, index, 0)
+	return es.Status.Conditions[index], len(events)
+}
+
+func TestReconcileElasticsearch_reportStalledReconciliation(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: map[string]string{bootstrap.ClusterUUIDAnnotationName: "uuid"}},
+		Spec:       esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{{Name: "default", Count: 3}}},
+	}
+	r := newTestReconciler()
+	r.ReconciliationStalledThreshold = time.Nanosecond
+	r.stalledWatchdog = commondriver.NewWatchdog(r.ReconciliationStalledThreshold)
+
+	// first observation of the pending changes
+	state := esreconcile.MustNewState(es)
+	r.reportStalledReconciliation(es, state, false, "pending")
+	condition, _ := stalledCondition(t, state)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, esv1.NotStalled, condition.Reason)
+
+	// changes still pending after the threshold, no node is available
+	time.Sleep(time.Millisecond)
+	state = esreconcile.MustNewState(es)
+	r.reportStalledReconciliation(es, state, false, "pending")
+	condition, events := stalledCondition(t, state)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, esv1.StalledNodesNotJoining, condition.Reason)
+	assert.Contains(t, condition.Message, "pending")
+	assert.Equal(t, 1, events)
+
+	// no new event while the reconciliation remains stalled
+	es.Status.Conditions = commonv1alpha1.Conditions{condition}
+	state = esreconcile.MustNewState(es)
+	r.reportStalledReconciliation(es, state, false, "pending")
+	emitted, _ := state.Apply()
+	assert.Empty(t, emitted)
+
+	// changes applied
+	state = esreconcile.MustNewState(es)
+	r.reportStalledReconciliation(es, state, true, "")
+	condition, _ = stalledCondition(t, state)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+
+	// not reported when disabled
+	r.ReconciliationStalledThreshold = 0
+	es.Status.Conditions = nil
+	state = esreconcile.MustNewState(es)
+	r.reportStalledReconciliation(es, state, false, "pending")
+	_, updated := state.Apply()
+	require.NotNil(t, updated)
+	assert.Equal(t, -1, updated.Status.Conditions.Index(esv1.ReconciliationStalled))
+}
+
+func Test_stalledReason(t *testing.T) {
+	bootstrapped := map[string]string{bootstrap.ClusterUUIDAnnotationName: "uuid"}
+	spec := esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{{Name: "default", Count: 1}}}
+	tests := []struct {
+		name  string
+		es    esv1.Elasticsearch
+		phase esv1.ElasticsearchOrchestrationPhase
+		want  string
+	}{
+		{
+			name: "not bootstrapped",
+			es:   esv1.Elasticsearch{Spec: spec},
+			want: esv1.StalledBootstrapNotComplete,
+		},
+		{
+			name:  "migrating data",
+			es:    esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Annotations: bootstrapped}, Spec: spec},
+			phase: esv1.ElasticsearchMigratingDataPhase,
+			want:  esv1.StalledDataMigrationNotProgressing,
+		},
+		{
+			name: "nodes not available",
+			es:   esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Annotations: bootstrapped}, Spec: spec},
+			want: esv1.StalledNodesNotJoining,
+		},
+		{
+			name: "other changes",
+			es:   esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Annotations: bootstrapped}},
+			want: esv1.StalledChangesPending,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := esreconcile.MustNewState(tt.es)
+			if tt.phase != "" {
+				state.UpdateWithPhase(tt.phase)
+			}
+			assert.Equal(t, tt.want, stalledReason(tt.es, state))
+		})
+	}
+}
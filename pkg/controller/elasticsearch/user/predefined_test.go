@@ -17,10 +17,14 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user/filerealm"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/cryptutil"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
 func Test_reconcileElasticUser(t *testing.T) {
+	if cryptutil.FIPSEnabled() {
+		t.Skip("fixtures use bcrypt password hashes, replaced with PBKDF2 with FIPS validated cryptography")
+	}
 	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
 	tests := []struct {
 		name              string
@@ -168,6 +172,9 @@ func Test_reconcileElasticUser_conditionalCreation(t *testing.T) {
 }
 
 func Test_reconcileInternalUsers(t *testing.T) {
+	if cryptutil.FIPSEnabled() {
+		t.Skip("fixtures use bcrypt password hashes, replaced with PBKDF2 with FIPS validated cryptography")
+	}
 	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}, Spec: esv1.ElasticsearchSpec{Version: "8.10.0"}}
 	tests := []struct {
 		name              string
@@ -32,6 +32,10 @@ const (
 
 	ServiceAccountTokenNameField = "name"
 	ServiceAccountHashField      = "hash"
+	// ServiceAccountPreviousTokenNameField and ServiceAccountPreviousHashField hold the token replaced by a rotation,
+	// which remains valid until the association controller removes them.
+	ServiceAccountPreviousTokenNameField = "previousName"
+	ServiceAccountPreviousHashField      = "previousHash"
 )
 
 // NamespacedServices extracts the namespaced service accounts.
@@ -72,6 +76,9 @@ func GetServiceAccountTokens(c k8s.Client, es esv1.Elasticsearch) (ServiceAccoun
 			return nil, err
 		}
 		tokens = tokens.Add(token)
+		if previous, exists := getPreviousServiceAccountToken(secret); exists {
+			tokens = tokens.Add(previous)
+		}
 	}
 	return tokens, nil
 }
@@ -98,6 +105,15 @@ func getServiceAccountToken(secret corev1.Secret) (ServiceAccountToken, error) {
 	return token, nil
 }
 
+// getPreviousServiceAccountToken reads from a secret the service account token replaced by a rotation, if any.
+func getPreviousServiceAccountToken(secret corev1.Secret) (ServiceAccountToken, bool) {
+	name, hash := secret.Data[ServiceAccountPreviousTokenNameField], secret.Data[ServiceAccountPreviousHashField]
+	if len(name) == 0 || len(hash) == 0 {
+		return ServiceAccountToken{}, false
+	}
+	return ServiceAccountToken{FullyQualifiedServiceAccountName: string(name), HashedSecret: string(hash)}, true
+}
+
 func (s ServiceAccountTokens) Add(serviceAccountToken ServiceAccountToken) ServiceAccountTokens {
 	return append(s, serviceAccountToken)
 }
@@ -0,0 +1,55 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestGetServiceAccountTokens(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	tokenSecret := func(name string, data map[string]string) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: map[string]string{
+				label.ClusterNameLabelName: "es",
+				commonv1.TypeLabelName:     ServiceAccountTokenType,
+			}},
+			Data: map[string][]byte{},
+		}
+		for k, v := range data {
+			secret.Data[k] = []byte(v)
+		}
+		return secret
+	}
+	c := k8s.NewFakeClient(
+		tokenSecret("kibana", map[string]string{
+			ServiceAccountTokenNameField: "elastic/kibana/ns_kb_uid",
+			ServiceAccountHashField:      "kb-hash",
+		}),
+		tokenSecret("fleet-server", map[string]string{
+			ServiceAccountTokenNameField:         "elastic/fleet-server/ns_fleet_uid_1700000000",
+			ServiceAccountHashField:              "fleet-hash",
+			ServiceAccountPreviousTokenNameField: "elastic/fleet-server/ns_fleet_uid",
+			ServiceAccountPreviousHashField:      "previous-fleet-hash",
+		}),
+	)
+	tokens, err := GetServiceAccountTokens(c, es)
+	require.NoError(t, err)
+	require.Equal(t,
+		"elastic/fleet-server/ns_fleet_uid:previous-fleet-hash\n"+
+			"elastic/fleet-server/ns_fleet_uid_1700000000:fleet-hash\n"+
+			"elastic/kibana/ns_kb_uid:kb-hash\n",
+		string(tokens.ToBytes()),
+	)
+}
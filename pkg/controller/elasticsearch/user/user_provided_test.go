@@ -22,6 +22,7 @@ import (
 	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user/filerealm"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/cryptutil"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -250,6 +251,9 @@ func TestReconcileUserProvidedRoles(t *testing.T) {
 }
 
 func Test_realmFromBasicAuthSecret(t *testing.T) {
+	if cryptutil.FIPSEnabled() {
+		t.Skip("fixtures use bcrypt password hashes, replaced with PBKDF2 with FIPS validated cryptography")
+	}
 	realmPtr := func(r filerealm.Realm) *filerealm.Realm {
 		return &r
 	}
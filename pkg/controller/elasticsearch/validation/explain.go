@@ -0,0 +1,197 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	volumevalidations "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume/validations"
+)
+
+const explainPrefix = "dry-run explain: "
+
+// explainCreate describes the resources created by the operator for the given Elasticsearch cluster.
+func explainCreate(proposed esv1.Elasticsearch) []string {
+	var explanations []string
+	for _, nodeSet := range expandedNodeSets(proposed) {
+		explanations = append(explanations, fmt.Sprintf("StatefulSet %s is created with %d Pods", proposed.StatefulSetName(nodeSet.Name), nodeSet.Count))
+	}
+	return prefixed(explanations)
+}
+
+// explainUpdate describes the changes made by the operator to the Pods and StatefulSets of the Elasticsearch cluster
+// to move from the current to the proposed specification. It relies on the specifications only: the explanation does
+// not account for changes still in progress, nor for the changes made by the operator to the cluster in the meantime.
+func explainUpdate(current, proposed esv1.Elasticsearch) []string {
+	var explanations []string
+	upgrade, fullRestart := explainVersion(current, proposed)
+	if upgrade != "" {
+		explanations = append(explanations, upgrade)
+	}
+	restartReason := clusterRestartReason(current, proposed)
+
+	currentNodeSets := map[string]esv1.NodeSet{}
+	for _, nodeSet := range expandedNodeSets(current) {
+		currentNodeSets[nodeSet.Name] = nodeSet
+	}
+	migrateStorageClass := proposed.StorageClassMigrationNodeSets()
+	migrateVolumeResize := proposed.VolumeResizeMigrationNodeSets()
+	for _, nodeSet := range expandedNodeSets(proposed) {
+		name := proposed.StatefulSetName(nodeSet.Name)
+		currentNodeSet, exists := currentNodeSets[nodeSet.Name]
+		if !exists {
+			explanations = append(explanations, fmt.Sprintf("StatefulSet %s is created with %d Pods", name, nodeSet.Count))
+			continue
+		}
+		delete(currentNodeSets, nodeSet.Name)
+
+		if migrateStorageClass.Has(nodeSet.Name) &&
+			volumevalidations.StorageClassChanged(currentNodeSet.VolumeClaimTemplates, nodeSet.VolumeClaimTemplates) {
+			explanations = append(explanations, fmt.Sprintf(
+				"StatefulSet %s is replaced by a new StatefulSet with volumes in the new storage class, the data of its Pods is migrated to the new Pods before it is deleted", name))
+			continue
+		}
+		switch {
+		case nodeSet.Count > currentNodeSet.Count:
+			explanations = append(explanations, fmt.Sprintf("StatefulSet %s is scaled up from %d to %d Pods", name, currentNodeSet.Count, nodeSet.Count))
+		case nodeSet.Count < currentNodeSet.Count:
+			explanation := fmt.Sprintf("StatefulSet %s is scaled down from %d to %d Pods", name, currentNodeSet.Count, nodeSet.Count)
+			if canContainData(current, currentNodeSet) {
+				explanation += ", the data of the removed Pods is migrated to the other nodes first"
+			}
+			explanations = append(explanations, explanation)
+		}
+		if !apiequality.Semantic.DeepEqual(currentNodeSet.VolumeClaimTemplates, nodeSet.VolumeClaimTemplates) {
+			explanation := fmt.Sprintf(
+				"StatefulSet %s is recreated with the new volume claim templates without restarting its Pods, and its volumes are expanded", name)
+			if migrateVolumeResize.Has(nodeSet.Name) {
+				explanation += ", or, if the storage class does not allow volume expansion, it is replaced by a new StatefulSet with larger volumes and the data of its Pods is migrated to the new Pods before it is deleted"
+			}
+			explanations = append(explanations, explanation)
+		}
+
+		reason := restartReason
+		switch {
+		case reason != "":
+		case !apiequality.Semantic.DeepEqual(currentNodeSet.Config, nodeSet.Config):
+			reason = "the configuration of the nodeSet changed"
+		case !apiequality.Semantic.DeepEqual(currentNodeSet.PodTemplate, nodeSet.PodTemplate):
+			reason = "the Pod template of the nodeSet changed"
+		default:
+			continue
+		}
+		if fullRestart {
+			explanations = append(explanations, fmt.Sprintf("StatefulSet %s: all its Pods are restarted at once because %s", name, reason))
+		} else {
+			explanations = append(explanations, fmt.Sprintf("StatefulSet %s: its Pods are restarted one at a time because %s", name, reason))
+		}
+	}
+
+	for _, nodeSet := range expandedNodeSets(current) {
+		if _, removed := currentNodeSets[nodeSet.Name]; !removed {
+			continue
+		}
+		explanation := fmt.Sprintf("StatefulSet %s is deleted", current.StatefulSetName(nodeSet.Name))
+		if canContainData(current, nodeSet) {
+			explanation += fmt.Sprintf(", the data of its %d Pods is migrated to the other nodes first", nodeSet.Count)
+		}
+		explanations = append(explanations, explanation)
+	}
+
+	if len(explanations) == 0 {
+		explanations = append(explanations, "no change to the Pods and StatefulSets of the cluster")
+	}
+	return prefixed(explanations)
+}
+
+// explainVersion describes the version upgrade from the current to the proposed specification, if any, and returns
+// whether it is performed with a full cluster restart, as done for clusters with less than 3 master nodes.
+func explainVersion(current, proposed esv1.Elasticsearch) (string, bool) {
+	currentVer, ferr := currentVersion(current)
+	if ferr != nil {
+		return "", false
+	}
+	proposedVer, err := version.Parse(proposed.Spec.Version)
+	if err != nil || !proposedVer.GT(currentVer) {
+		return "", false
+	}
+	if masterNodesCount(current) <= 2 && masterNodesCount(proposed) <= 2 {
+		return fmt.Sprintf("version upgrade from %s to %s: supported upgrade path, all the nodes are restarted at once because the cluster has less than 3 master nodes",
+			currentVer, proposedVer), true
+	}
+	return fmt.Sprintf("version upgrade from %s to %s: supported upgrade path, the nodes are upgraded one at a time, master nodes last",
+		currentVer, proposedVer), false
+}
+
+// clusterRestartReason returns why all the Pods of the cluster are restarted, or an empty string if the changes
+// to the specification do not apply to all the nodeSets.
+func clusterRestartReason(current, proposed esv1.Elasticsearch) string {
+	switch {
+	case current.Spec.Version != proposed.Spec.Version:
+		return "the version changed"
+	case current.Spec.Image != proposed.Spec.Image:
+		return "the image changed"
+	case !apiequality.Semantic.DeepEqual(current.Spec.HTTP, proposed.Spec.HTTP):
+		return "the HTTP configuration changed"
+	case !apiequality.Semantic.DeepEqual(current.Spec.Transport, proposed.Spec.Transport):
+		return "the transport configuration changed"
+	case !apiequality.Semantic.DeepEqual(current.Spec.Monitoring, proposed.Spec.Monitoring):
+		return "the monitoring configuration changed"
+	case !apiequality.Semantic.DeepEqual(current.Spec.Auditing, proposed.Spec.Auditing):
+		return "the auditing configuration changed"
+	case current.Annotations[esv1.RestartTriggerAnnotation] != proposed.Annotations[esv1.RestartTriggerAnnotation]:
+		return "a restart was requested"
+	}
+	return ""
+}
+
+func expandedNodeSets(es esv1.Elasticsearch) []esv1.NodeSet {
+	var nodeSets []esv1.NodeSet
+	for _, nodeSet := range es.Spec.NodeSets {
+		nodeSets = append(nodeSets, nodeSet.ExpandZones()...)
+	}
+	return nodeSets
+}
+
+// canContainData returns true if the nodes of the given nodeSet may hold data. It errs on the side of data
+// migration if the configuration cannot be parsed.
+func canContainData(es esv1.Elasticsearch, nodeSet esv1.NodeSet) bool {
+	cfg, err := nodeSetSettings(es, nodeSet)
+	if err != nil {
+		return true
+	}
+	return cfg.Node.CanContainData()
+}
+
+func masterNodesCount(es esv1.Elasticsearch) int32 {
+	var count int32
+	for _, nodeSet := range es.Spec.NodeSets {
+		cfg, err := nodeSetSettings(es, nodeSet)
+		if err != nil || cfg.Node.HasRole(esv1.MasterRole) {
+			count += nodeSet.Count
+		}
+	}
+	return count
+}
+
+func nodeSetSettings(es esv1.Elasticsearch, nodeSet esv1.NodeSet) (esv1.ElasticsearchSettings, error) {
+	cfg := esv1.ElasticsearchSettings{}
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, esv1.UnpackConfig(nodeSet.Config, v, &cfg)
+}
+
+func prefixed(explanations []string) []string {
+	for i := range explanations {
+		explanations[i] = explainPrefix + explanations[i]
+	}
+	return explanations
+}
@@ -0,0 +1,191 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/namespaces"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func explainES(version string, nodeSets ...esv1.NodeSet) esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{Version: version, NodeSets: nodeSets},
+	}
+}
+
+func dataNodeSet(name string, count int32) esv1.NodeSet {
+	return esv1.NodeSet{Name: name, Count: count, Config: &commonv1.Config{Data: map[string]interface{}{"node.roles": []interface{}{"data"}}}}
+}
+
+func masterNodeSet(name string, count int32) esv1.NodeSet {
+	return esv1.NodeSet{Name: name, Count: count, Config: &commonv1.Config{Data: map[string]interface{}{"node.roles": []interface{}{"master"}}}}
+}
+
+func Test_explainUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  esv1.Elasticsearch
+		proposed esv1.Elasticsearch
+		want     []string
+	}{
+		{
+			name:     "no change",
+			current:  explainES("8.15.0", masterNodeSet("master", 3), dataNodeSet("data", 3)),
+			proposed: explainES("8.15.0", masterNodeSet("master", 3), dataNodeSet("data", 3)),
+			want:     []string{"dry-run explain: no change to the Pods and StatefulSets of the cluster"},
+		},
+		{
+			name:     "rolling upgrade",
+			current:  explainES("8.14.0", masterNodeSet("master", 3), dataNodeSet("data", 3)),
+			proposed: explainES("8.15.0", masterNodeSet("master", 3), dataNodeSet("data", 3)),
+			want: []string{
+				"dry-run explain: version upgrade from 8.14.0 to 8.15.0: supported upgrade path, the nodes are upgraded one at a time, master nodes last",
+				"dry-run explain: StatefulSet es-es-master: its Pods are restarted one at a time because the version changed",
+				"dry-run explain: StatefulSet es-es-data: its Pods are restarted one at a time because the version changed",
+			},
+		},
+		{
+			name:     "full restart upgrade of a non-HA cluster",
+			current:  explainES("8.14.0", esv1.NodeSet{Name: "default", Count: 1}),
+			proposed: explainES("8.15.0", esv1.NodeSet{Name: "default", Count: 1}),
+			want: []string{
+				"dry-run explain: version upgrade from 8.14.0 to 8.15.0: supported upgrade path, all the nodes are restarted at once because the cluster has less than 3 master nodes",
+				"dry-run explain: StatefulSet es-es-default: all its Pods are restarted at once because the version changed",
+			},
+		},
+		{
+			name:    "scale and rename nodeSets",
+			current: explainES("8.15.0", masterNodeSet("master", 3), dataNodeSet("data", 3), dataNodeSet("hot", 2)),
+			proposed: explainES("8.15.0", masterNodeSet("master", 5), dataNodeSet("data", 2),
+				esv1.NodeSet{Name: "ml", Count: 1, Config: &commonv1.Config{Data: map[string]interface{}{"node.roles": []interface{}{"ml"}}}}),
+			want: []string{
+				"dry-run explain: StatefulSet es-es-master is scaled up from 3 to 5 Pods",
+				"dry-run explain: StatefulSet es-es-data is scaled down from 3 to 2 Pods, the data of the removed Pods is migrated to the other nodes first",
+				"dry-run explain: StatefulSet es-es-ml is created with 1 Pods",
+				"dry-run explain: StatefulSet es-es-hot is deleted, the data of its 2 Pods is migrated to the other nodes first",
+			},
+		},
+		{
+			name:     "scale down nodes without data",
+			current:  explainES("8.15.0", masterNodeSet("master", 5)),
+			proposed: explainES("8.15.0", masterNodeSet("master", 3)),
+			want:     []string{"dry-run explain: StatefulSet es-es-master is scaled down from 5 to 3 Pods"},
+		},
+		{
+			name:    "nodeSet configuration and Pod template changes",
+			current: explainES("8.15.0", masterNodeSet("master", 3), dataNodeSet("data", 3)),
+			proposed: func() esv1.Elasticsearch {
+				es := explainES("8.15.0", masterNodeSet("master", 3), dataNodeSet("data", 3))
+				es.Spec.NodeSets[0].Config.Data["xpack.ml.enabled"] = false
+				es.Spec.NodeSets[1].PodTemplate.Labels = map[string]string{"team": "search"}
+				return es
+			}(),
+			want: []string{
+				"dry-run explain: StatefulSet es-es-master: its Pods are restarted one at a time because the configuration of the nodeSet changed",
+				"dry-run explain: StatefulSet es-es-data: its Pods are restarted one at a time because the Pod template of the nodeSet changed",
+			},
+		},
+		{
+			name: "volume expansion",
+			current: func() esv1.Elasticsearch {
+				ns := dataNodeSet("data", 3)
+				ns.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{claim("1Gi")}
+				return explainES("8.15.0", masterNodeSet("master", 3), ns)
+			}(),
+			proposed: func() esv1.Elasticsearch {
+				ns := dataNodeSet("data", 3)
+				ns.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{claim("2Gi")}
+				return explainES("8.15.0", masterNodeSet("master", 3), ns)
+			}(),
+			want: []string{
+				"dry-run explain: StatefulSet es-es-data is recreated with the new volume claim templates without restarting its Pods, and its volumes are expanded",
+			},
+		},
+		{
+			name:    "auditing enabled",
+			current: explainES("8.15.0", masterNodeSet("master", 3)),
+			proposed: func() esv1.Elasticsearch {
+				es := explainES("8.15.0", masterNodeSet("master", 3))
+				es.Spec.Auditing = &esv1.Auditing{}
+				return es
+			}(),
+			want: []string{
+				"dry-run explain: StatefulSet es-es-master: its Pods are restarted one at a time because the auditing configuration changed",
+			},
+		},
+		{
+			name:    "restart requested",
+			current: explainES("8.15.0", masterNodeSet("master", 3)),
+			proposed: func() esv1.Elasticsearch {
+				es := explainES("8.15.0", masterNodeSet("master", 3))
+				es.Annotations = map[string]string{esv1.RestartTriggerAnnotation: "2024-10-15"}
+				return es
+			}(),
+			want: []string{
+				"dry-run explain: StatefulSet es-es-master: its Pods are restarted one at a time because a restart was requested",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, explainUpdate(tt.current, tt.proposed))
+		})
+	}
+}
+
+func claim(size string) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)}},
+		},
+	}
+}
+
+func Test_validatingWebhook_Handle_dryRunExplain(t *testing.T) {
+	wh := &validatingWebhook{
+		client:            k8s.NewFakeClient(),
+		decoder:           admission.NewDecoder(k8s.Scheme()),
+		managedNamespaces: namespaces.Static{"ns"},
+	}
+	current := explainES("8.15.0", esv1.NodeSet{Name: "default", Count: 3})
+	proposed := explainES("8.15.0", esv1.NodeSet{Name: "default", Count: 4})
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Update,
+		OldObject: runtime.RawExtension{Raw: asJSON(&current)},
+		Object:    runtime.RawExtension{Raw: asJSON(&proposed)},
+	}}
+
+	// no explanation without the annotation
+	got := wh.Handle(context.Background(), req)
+	require.True(t, got.Allowed)
+	require.Empty(t, got.Warnings)
+
+	proposed.Annotations = map[string]string{esv1.DryRunExplainAnnotation: "true"}
+	req.Object = runtime.RawExtension{Raw: asJSON(&proposed)}
+	got = wh.Handle(context.Background(), req)
+	require.True(t, got.Allowed)
+	require.Equal(t, []string{"dry-run explain: StatefulSet es-es-default is scaled up from 3 to 4 Pods"}, got.Warnings)
+
+	req.Operation = admissionv1.Create
+	req.OldObject = runtime.RawExtension{}
+	got = wh.Handle(context.Background(), req)
+	require.True(t, got.Allowed)
+	require.Equal(t, []string{"dry-run explain: StatefulSet es-es-default is created with 4 Pods"}, got.Warnings)
+}
@@ -0,0 +1,107 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+const (
+	remoteClusterAPIKeyTargetErrMsg   = "API keys can only be used with the seeds or the proxy address of a remote cluster not managed by the operator"
+	remoteClusterAPIKeyVersionErrMsg  = "API key based remote clusters require Elasticsearch %s or later"
+	remoteClusterOptionsVersionErrMsg = "remote cluster options require Elasticsearch %s or later"
+	remoteClusterProxyModeErrMsg      = "can only be set in proxy mode"
+	remoteClusterSniffModeErrMsg      = "cannot be set in proxy mode"
+	remoteClusterTargetErrMsg         = "elasticsearchRef, seeds and proxyAddress are mutually exclusive"
+)
+
+var (
+	// remoteClusterOptionsMinVersion is the first version of Elasticsearch supporting the proxy mode.
+	remoteClusterOptionsMinVersion = version.MustParse("7.7.0")
+	// remoteClusterAPIKeyMinVersion is the first version of Elasticsearch supporting the API key based security model
+	// for remote clusters.
+	remoteClusterAPIKeyMinVersion = version.MustParse("8.10.0")
+)
+
+// validRemoteClusters checks that remote clusters target a single cluster, that their options match their connection
+// mode, and that API keys are only used for remote clusters not managed by the operator, on versions of Elasticsearch
+// supporting them.
+func validRemoteClusters(es esv1.Elasticsearch) field.ErrorList {
+	if len(es.Spec.RemoteClusters) == 0 {
+		return nil
+	}
+	var errs field.ErrorList
+	ver, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		// reported by the version validation
+		return nil
+	}
+	for i, remoteCluster := range es.Spec.RemoteClusters {
+		path := field.NewPath("spec").Child("remoteClusters").Index(i)
+		targets := 0
+		for _, defined := range []bool{remoteCluster.ElasticsearchRef.IsDefined(), len(remoteCluster.Seeds) > 0, remoteCluster.ProxyAddress != ""} {
+			if defined {
+				targets++
+			}
+		}
+		if targets > 1 {
+			errs = append(errs, field.Invalid(path, remoteCluster.Name, remoteClusterTargetErrMsg))
+		}
+		errs = append(errs, validRemoteClusterOptions(path, remoteCluster, ver)...)
+
+		if remoteCluster.APIKey == nil {
+			continue
+		}
+		apiKeyPath := path.Child("apiKey")
+		if remoteCluster.ElasticsearchRef.IsDefined() || targets == 0 {
+			errs = append(errs, field.Forbidden(apiKeyPath, remoteClusterAPIKeyTargetErrMsg))
+		}
+		if !ver.GTE(remoteClusterAPIKeyMinVersion) {
+			errs = append(errs, field.Forbidden(apiKeyPath, fmt.Sprintf(remoteClusterAPIKeyVersionErrMsg, remoteClusterAPIKeyMinVersion)))
+		}
+		if remoteCluster.APIKey.SecretName == "" {
+			errs = append(errs, field.Required(apiKeyPath.Child("secretName"), "secret name is mandatory"))
+		}
+		if remoteCluster.APIKey.Key == "" {
+			errs = append(errs, field.Required(apiKeyPath.Child("key"), "secret key is mandatory"))
+		}
+	}
+	return errs
+}
+
+// validRemoteClusterOptions checks that the optional settings of the remote cluster are supported by the version of
+// Elasticsearch, and match the connection mode.
+func validRemoteClusterOptions(path *field.Path, remoteCluster esv1.RemoteCluster, ver version.Version) field.ErrorList {
+	var errs field.ErrorList
+	hasOptions := remoteCluster.Mode != "" || remoteCluster.ProxyAddress != "" || remoteCluster.ServerName != "" ||
+		remoteCluster.NumSocketConnections != nil || remoteCluster.SkipUnavailable != nil
+	if hasOptions && !ver.GTE(remoteClusterOptionsMinVersion) {
+		errs = append(errs, field.Forbidden(path, fmt.Sprintf(remoteClusterOptionsVersionErrMsg, remoteClusterOptionsMinVersion)))
+	}
+	if remoteCluster.IsProxyMode() {
+		if len(remoteCluster.Seeds) > 0 {
+			errs = append(errs, field.Forbidden(path.Child("seeds"), remoteClusterSniffModeErrMsg))
+		}
+		if remoteCluster.ProxyAddress == "" && !remoteCluster.ElasticsearchRef.IsDefined() {
+			errs = append(errs, field.Required(path.Child("proxyAddress"), "proxy address is mandatory without elasticsearchRef"))
+		}
+		return errs
+	}
+	if remoteCluster.ProxyAddress != "" {
+		errs = append(errs, field.Forbidden(path.Child("proxyAddress"), remoteClusterProxyModeErrMsg))
+	}
+	if remoteCluster.ServerName != "" {
+		errs = append(errs, field.Forbidden(path.Child("serverName"), remoteClusterProxyModeErrMsg))
+	}
+	if remoteCluster.NumSocketConnections != nil {
+		errs = append(errs, field.Forbidden(path.Child("numSocketConnections"), remoteClusterProxyModeErrMsg))
+	}
+	return errs
+}
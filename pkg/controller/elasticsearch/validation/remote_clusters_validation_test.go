@@ -0,0 +1,111 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_validRemoteClusters(t *testing.T) {
+	apiKey := &esv1.SecretKeyRef{SecretName: "remote-keys", Key: "external"}
+	tests := []struct {
+		name           string
+		version        string
+		remoteClusters []esv1.RemoteCluster
+		expectErrors   int
+	}{
+		{
+			name:    "no remote cluster",
+			version: "8.15.0",
+		},
+		{
+			name:    "remote clusters managed by the operator or not",
+			version: "8.15.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "internal", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es2"}},
+				{Name: "external", Seeds: []string{"es.example.com:9300"}},
+				{Name: "external-api-key", Seeds: []string{"es.example.com:9443"}, APIKey: apiKey},
+			},
+		},
+		{
+			name:    "both elasticsearchRef and seeds",
+			version: "8.15.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "both", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es2"}, Seeds: []string{"es.example.com:9300"}},
+			},
+			expectErrors: 1,
+		},
+		{
+			name:    "API key with elasticsearchRef",
+			version: "8.15.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "internal", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es2"}, APIKey: apiKey},
+			},
+			expectErrors: 1,
+		},
+		{
+			name:    "API key before 8.10.0",
+			version: "8.9.2",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "external", Seeds: []string{"es.example.com:9443"}, APIKey: apiKey},
+			},
+			expectErrors: 1,
+		},
+		{
+			name:    "proxy mode",
+			version: "8.15.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "internal", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es2"}, Mode: esv1.RemoteClusterProxyMode, NumSocketConnections: ptr.To[int32](6)},
+				{Name: "external", Mode: esv1.RemoteClusterProxyMode, ProxyAddress: "es.example.com:9443", ServerName: "es.example.com", APIKey: apiKey},
+				{Name: "sniff", Seeds: []string{"es.example.com:9300"}, SkipUnavailable: ptr.To(true)},
+			},
+		},
+		{
+			name:    "proxy mode without proxy address",
+			version: "8.15.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "external", Mode: esv1.RemoteClusterProxyMode, Seeds: []string{"es.example.com:9300"}},
+			},
+			expectErrors: 2,
+		},
+		{
+			name:    "proxy settings in sniff mode",
+			version: "8.15.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "external", ProxyAddress: "es.example.com:9300", ServerName: "es.example.com", NumSocketConnections: ptr.To[int32](6)},
+			},
+			expectErrors: 3,
+		},
+		{
+			name:    "options before 7.7.0",
+			version: "7.6.2",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "internal", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es2"}, SkipUnavailable: ptr.To(true)},
+			},
+			expectErrors: 1,
+		},
+		{
+			name:    "incomplete API key reference",
+			version: "8.15.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "external", Seeds: []string{"es.example.com:9443"}, APIKey: &esv1.SecretKeyRef{}},
+			},
+			expectErrors: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proposed := es(tt.version)
+			proposed.Spec.RemoteClusters = tt.remoteClusters
+			assert.Len(t, validRemoteClusters(proposed), tt.expectErrors)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+const (
+	ssoRealmDuplicateOrderErrMsg = "the order of the realm is already used by realm %s"
+	ssoRealmKibanaURLErrMsg      = "must be an absolute http or https URL"
+	ssoRealmNameErrMsg           = "must consist of at most 40 lowercase alphanumeric characters or hyphens, and start and end with an alphanumeric character"
+	ssoRealmVersionErrMsg        = "%s realms require Elasticsearch %s or later"
+	samlMetadataSourceErrMsg     = "exactly one of the url or the secret of the metadata must be set"
+)
+
+var (
+	// ssoRealmNameRegexp restricts the names of the realms to the names that can be used in volume names.
+	ssoRealmNameRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,38}[a-z0-9])?$`)
+
+	oidcRealmsMinVersion = version.MustParse("7.2.0")
+	samlRealmsMinVersion = version.MustParse("7.0.0")
+)
+
+// validSSORealms checks the OpenID Connect and SAML realms, whose settings are generated by the operator.
+func validSSORealms(es esv1.Elasticsearch) field.ErrorList {
+	auth := es.Spec.Auth
+	if len(auth.OIDC) == 0 && len(auth.SAML) == 0 {
+		return nil
+	}
+	var errs field.ErrorList
+	authPath := field.NewPath("spec").Child("auth")
+
+	ver, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		// reported by the version validation
+		return nil
+	}
+	if len(auth.OIDC) > 0 && !ver.GTE(oidcRealmsMinVersion) {
+		errs = append(errs, field.Forbidden(authPath.Child("oidc"), fmt.Sprintf(ssoRealmVersionErrMsg, "OpenID Connect", oidcRealmsMinVersion)))
+	}
+	if len(auth.SAML) > 0 && !ver.GTE(samlRealmsMinVersion) {
+		errs = append(errs, field.Forbidden(authPath.Child("saml"), fmt.Sprintf(ssoRealmVersionErrMsg, "SAML", samlRealmsMinVersion)))
+	}
+
+	names := map[string]struct{}{}
+	orders := map[int32]string{}
+	checkRealm := func(path *field.Path, realm esv1.SSORealm, position int) {
+		if !ssoRealmNameRegexp.MatchString(realm.Name) {
+			errs = append(errs, field.Invalid(path.Child("name"), realm.Name, ssoRealmNameErrMsg))
+		} else if _, exists := names[realm.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), realm.Name))
+		}
+		names[realm.Name] = struct{}{}
+		order := realm.OrderOrDefault(position)
+		if other, exists := orders[order]; exists {
+			errs = append(errs, field.Invalid(path.Child("order"), order, fmt.Sprintf(ssoRealmDuplicateOrderErrMsg, other)))
+		}
+		orders[order] = realm.Name
+		if realm.KibanaRef.Name == "" {
+			errs = append(errs, field.Required(path.Child("kibanaRef", "name"), "Kibana name is mandatory"))
+		}
+		if !isHTTPURL(realm.KibanaURL) {
+			errs = append(errs, field.Invalid(path.Child("kibanaURL"), realm.KibanaURL, ssoRealmKibanaURLErrMsg))
+		}
+	}
+	required := func(path *field.Path, value string, description string) {
+		if value == "" {
+			errs = append(errs, field.Required(path, description+" is mandatory"))
+		}
+	}
+
+	for i, realm := range auth.OIDC {
+		path := authPath.Child("oidc").Index(i)
+		checkRealm(path, realm.SSORealm, i)
+		required(path.Child("issuer"), realm.Issuer, "issuer")
+		required(path.Child("authorizationEndpoint"), realm.AuthorizationEndpoint, "authorization endpoint")
+		required(path.Child("tokenEndpoint"), realm.TokenEndpoint, "token endpoint")
+		required(path.Child("jwkSetURL"), realm.JWKSetURL, "JSON Web Key Set URL")
+		required(path.Child("clientID"), realm.ClientID, "client id")
+		required(path.Child("clientSecret", "secretName"), realm.ClientSecret.SecretName, "client secret name")
+		required(path.Child("clientSecret", "key"), realm.ClientSecret.Key, "client secret key")
+	}
+	for i, realm := range auth.SAML {
+		path := authPath.Child("saml").Index(i)
+		checkRealm(path, realm.SSORealm, len(auth.OIDC)+i)
+		required(path.Child("idpEntityID"), realm.IdPEntityID, "identity provider entity id")
+		metadata := realm.IdPMetadata
+		switch {
+		case (metadata.URL == "") == (metadata.Secret == nil):
+			errs = append(errs, field.Invalid(path.Child("idpMetadata"), metadata, samlMetadataSourceErrMsg))
+		case metadata.Secret != nil:
+			required(path.Child("idpMetadata", "secret", "secretName"), metadata.Secret.SecretName, "metadata secret name")
+			required(path.Child("idpMetadata", "secret", "key"), metadata.Secret.Key, "metadata secret key")
+		}
+	}
+	return errs
+}
+
+// isHTTPURL returns true if the given value is an absolute http or https URL.
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,144 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_validSSORealms(t *testing.T) {
+	esWithAuth := func(version string, auth esv1.Auth) esv1.Elasticsearch {
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: version, Auth: auth}}
+	}
+	ssoRealm := func(name string) esv1.SSORealm {
+		return esv1.SSORealm{Name: name, KibanaRef: commonv1.LocalObjectSelector{Name: "kb"}, KibanaURL: "https://kibana.example.com"}
+	}
+	oidc := func(mutate func(*esv1.OIDCRealm)) esv1.OIDCRealm {
+		realm := esv1.OIDCRealm{
+			SSORealm:              ssoRealm("okta"),
+			Issuer:                "https://example.okta.com",
+			AuthorizationEndpoint: "https://example.okta.com/oauth2/v1/authorize",
+			TokenEndpoint:         "https://example.okta.com/oauth2/v1/token",
+			JWKSetURL:             "https://example.okta.com/oauth2/v1/keys",
+			ClientID:              "eck",
+			ClientSecret:          esv1.SecretKeyRef{SecretName: "okta-client", Key: "secret"},
+		}
+		if mutate != nil {
+			mutate(&realm)
+		}
+		return realm
+	}
+	saml := func(mutate func(*esv1.SAMLRealm)) esv1.SAMLRealm {
+		realm := esv1.SAMLRealm{
+			SSORealm:    ssoRealm("azure"),
+			IdPMetadata: esv1.SAMLMetadataSource{URL: "https://login.microsoftonline.com/metadata.xml"},
+			IdPEntityID: "https://sts.windows.net/tenant/",
+		}
+		if mutate != nil {
+			mutate(&realm)
+		}
+		return realm
+	}
+	tests := []struct {
+		name    string
+		es      esv1.Elasticsearch
+		wantErr string
+	}{
+		{
+			name: "no realm is OK",
+			es:   esWithAuth("7.0.0", esv1.Auth{}),
+		},
+		{
+			name: "OIDC and SAML realms are OK",
+			es:   esWithAuth("8.15.0", esv1.Auth{OIDC: []esv1.OIDCRealm{oidc(nil)}, SAML: []esv1.SAMLRealm{saml(nil)}}),
+		},
+		{
+			name: "SAML metadata from a secret is OK",
+			es: esWithAuth("8.15.0", esv1.Auth{SAML: []esv1.SAMLRealm{saml(func(r *esv1.SAMLRealm) {
+				r.IdPMetadata = esv1.SAMLMetadataSource{Secret: &esv1.SecretKeyRef{SecretName: "metadata", Key: "idp.xml"}}
+			})}}),
+		},
+		{
+			name:    "OIDC realm before 7.2.0 is NOK",
+			es:      esWithAuth("7.1.0", esv1.Auth{OIDC: []esv1.OIDCRealm{oidc(nil)}}),
+			wantErr: "OpenID Connect realms require Elasticsearch 7.2.0 or later",
+		},
+		{
+			name: "invalid realm name is NOK",
+			es: esWithAuth("8.15.0", esv1.Auth{OIDC: []esv1.OIDCRealm{oidc(func(r *esv1.OIDCRealm) {
+				r.Name = "Okta_Realm"
+			})}}),
+			wantErr: ssoRealmNameErrMsg,
+		},
+		{
+			name: "duplicate realm name is NOK",
+			es: esWithAuth("8.15.0", esv1.Auth{
+				OIDC: []esv1.OIDCRealm{oidc(nil)},
+				SAML: []esv1.SAMLRealm{saml(func(r *esv1.SAMLRealm) { r.Name = "okta" })},
+			}),
+			wantErr: `Duplicate value: "okta"`,
+		},
+		{
+			name: "duplicate realm order is NOK",
+			es: esWithAuth("8.15.0", esv1.Auth{
+				OIDC: []esv1.OIDCRealm{oidc(nil)},
+				SAML: []esv1.SAMLRealm{saml(func(r *esv1.SAMLRealm) { r.Order = ptr.To[int32](1) })},
+			}),
+			wantErr: "the order of the realm is already used by realm okta",
+		},
+		{
+			name: "missing Kibana reference is NOK",
+			es: esWithAuth("8.15.0", esv1.Auth{SAML: []esv1.SAMLRealm{saml(func(r *esv1.SAMLRealm) {
+				r.KibanaRef = commonv1.LocalObjectSelector{}
+			})}}),
+			wantErr: "Kibana name is mandatory",
+		},
+		{
+			name: "relative Kibana URL is NOK",
+			es: esWithAuth("8.15.0", esv1.Auth{SAML: []esv1.SAMLRealm{saml(func(r *esv1.SAMLRealm) {
+				r.KibanaURL = "kibana.example.com"
+			})}}),
+			wantErr: ssoRealmKibanaURLErrMsg,
+		},
+		{
+			name: "missing OIDC client secret is NOK",
+			es: esWithAuth("8.15.0", esv1.Auth{OIDC: []esv1.OIDCRealm{oidc(func(r *esv1.OIDCRealm) {
+				r.ClientSecret.Key = ""
+			})}}),
+			wantErr: "client secret key is mandatory",
+		},
+		{
+			name: "SAML metadata from both a URL and a secret is NOK",
+			es: esWithAuth("8.15.0", esv1.Auth{SAML: []esv1.SAMLRealm{saml(func(r *esv1.SAMLRealm) {
+				r.IdPMetadata.Secret = &esv1.SecretKeyRef{SecretName: "metadata", Key: "idp.xml"}
+			})}}),
+			wantErr: samlMetadataSourceErrMsg,
+		},
+		{
+			name: "missing SAML metadata is NOK",
+			es: esWithAuth("8.15.0", esv1.Auth{SAML: []esv1.SAMLRealm{saml(func(r *esv1.SAMLRealm) {
+				r.IdPMetadata = esv1.SAMLMetadataSource{}
+			})}}),
+			wantErr: samlMetadataSourceErrMsg,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validSSORealms(tt.es)
+			if tt.wantErr == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Contains(t, got[0].Error(), tt.wantErr)
+		})
+	}
+}
@@ -8,15 +8,20 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/maintenance"
 	stackmon "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
 	esversion "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -24,23 +29,56 @@ import (
 )
 
 const (
-	cfgInvalidMsg                          = "Configuration invalid"
-	duplicateNodeSets                      = "NodeSet names must be unique"
-	invalidNamesErrMsg                     = "Elasticsearch configuration would generate resources with invalid names"
-	invalidSanIPErrMsg                     = "Invalid SAN IP address. Must be a valid IPv4 address"
-	masterRequiredMsg                      = "Elasticsearch needs to have at least one master node"
-	mixedRoleConfigMsg                     = "Detected a combination of node.roles and %s. Use only node.roles"
-	noDowngradesMsg                        = "Downgrades are not supported"
-	nodeRolesInOldVersionMsg               = "node.roles setting is not available in this version of Elasticsearch"
-	parseStoredVersionErrMsg               = "Cannot parse current Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
-	parseVersionErrMsg                     = "Cannot parse Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
-	pvcNotMountedErrMsg                    = "volume claim declared but volume not mounted in any container. Note that the Elasticsearch data volume should be named 'elasticsearch-data'"
-	unsupportedConfigErrMsg                = "Configuration setting is reserved for internal use. User-configured use is unsupported"
-	unsupportedUpgradeMsg                  = "Unsupported version upgrade path. Check the Elasticsearch documentation for supported upgrade paths."
-	unsupportedVersionMsg                  = "Unsupported version"
-	notAllowedNodesLabelMsg                = "Node label not in the exposed node labels list"
-	unsupportedClientAuthenticationMsg     = "Mandatory client authentication is not supported"
-	autoscalingAnnotationUnsupportedErrMsg = "autoscaling annotation is no longer supported"
+	additionalVolumeIsDataVolumeErrMsg          = "the Elasticsearch data volume cannot be declared as an additional volume"
+	additionalVolumeNotClaimedErrMsg            = "additional volume does not match any volume claim template of the nodeSet"
+	auditIgnoreFilterCriteriaErrMsg             = "at least one of users, realms, actions, roles or indices must be set"
+	auditIgnoreFilterNameErrMsg                 = "must not contain dots"
+	auditLogsWithLogsMonitoringErrMsg           = "the audit log is already shipped with the other logs to the clusters of spec.monitoring.logs.elasticsearchRefs"
+	cfgInvalidMsg                               = "Configuration invalid"
+	duplicateNodeSets                           = "NodeSet names must be unique"
+	ephemeralStorageChangeErrMsg                = "ephemeral storage cannot be enabled or disabled on an existing nodeSet, rename the nodeSet instead"
+	ephemeralStorageRoleErrMsg                  = "ephemeral storage is not supported for nodes with the %s role"
+	ephemeralStorageWithClaimsErrMsg            = "ephemeral storage cannot be combined with volume claim templates"
+	ephemeralStorageCSIErrMsg                   = "sizeLimit and medium cannot be combined with a CSI ephemeral volume"
+	ephemeralStorageCSIReadOnlyErrMsg           = "the CSI ephemeral volume storing the Elasticsearch data cannot be read-only"
+	frozenCacheRoleErrMsg                       = "the shared cache can only be configured for nodes with the data_frozen role"
+	frozenCacheSizeConfiguredErrMsg             = "the shared cache size cannot also be set in the nodeSet configuration"
+	frozenCacheSizeErrMsg                       = "the shared cache size must be a percentage between 0 and 100%, or a positive quantity"
+	frozenCacheSizeExceedsVolumeErrMsg          = "the shared cache size exceeds the size of the data volume (%s)"
+	frozenCacheVersionErrMsg                    = "the frozen tier requires Elasticsearch 7.12.0 or later"
+	frozenCacheVolumeChangeErrMsg               = "the shared cache volume cannot be added, removed, or moved to another storage on an existing nodeSet, rename the nodeSet instead"
+	frozenCacheVolumeDecreaseErrMsg             = "the shared cache volume storage cannot be decreased"
+	frozenCacheVolumeStorageErrMsg              = "the shared cache volume storage must be positive"
+	frozenCacheVolumeWithDataClaimErrMsg        = "the shared cache volume cannot be combined with a volume claim template for the data volume"
+	frozenCacheVolumeWithEphemeralStorageErrMsg = "the shared cache volume cannot be combined with ephemeral storage"
+	initialRestoreAddedErrMsg                   = "initialRestore can only be set when the cluster is created"
+	invalidNamesErrMsg                          = "Elasticsearch configuration would generate resources with invalid names"
+	invalidSanIPErrMsg                          = "Invalid SAN IP address. Must be a valid IPv4 address"
+	jvmDiagnosticsVolumeInUseErrMsg             = "the JVM diagnostics volume cannot be the data volume or an additional volume"
+	jvmDiagnosticsVolumeNotClaimedErrMsg        = "the JVM diagnostics volume does not match any volume claim template of the nodeSet"
+	keystorePasswordVersionErrMsg               = "password protected keystores require Elasticsearch 7.9.0 or later"
+	masterRequiredMsg                           = "Elasticsearch needs to have at least one master node"
+	mixedRoleConfigMsg                          = "Detected a combination of node.roles and %s. Use only node.roles"
+	noDowngradesMsg                             = "Downgrades are not supported"
+	multipleDataPathsErrMsg                     = "multiple data paths are not supported from Elasticsearch 8.0.0"
+	nodeRolesInOldVersionMsg                    = "node.roles setting is not available in this version of Elasticsearch"
+	parseStoredVersionErrMsg                    = "Cannot parse current Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
+	parseVersionErrMsg                          = "Cannot parse Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
+	preUpgradeSnapshotNodeSetErrMsg             = "nodeSet does not exist"
+	prometheusExporterPortErrMsg                = "the exporter port must not be one of the Elasticsearch ports"
+	pvcNotMountedErrMsg                         = "volume claim declared but volume not mounted in any container. Note that the Elasticsearch data volume should be named 'elasticsearch-data'"
+	serviceProfileNameReservedErrMsg            = "the Service of the profile would clash with the internal HTTP Service"
+	slmFailureThresholdErrMsg                   = "the failure threshold must be positive"
+	slmVersionErrMsg                            = "snapshot lifecycle policies require Elasticsearch 7.4.0 or later"
+	transportIssuerCAErrMsg                     = "certificateAuthorities must be set to trust the CA of the transport certificates issuer"
+	transportIssuerWithCAErrMsg                 = "transport certificates cannot be both issued by an external signer and by a user-provided CA"
+	transportIssuerWithDisabledErrMsg           = "transport certificates cannot be issued by an external signer when self-signed certificates are disabled"
+	unsupportedConfigErrMsg                     = "Configuration setting is reserved for internal use. User-configured use is unsupported"
+	unsupportedUpgradeMsg                       = "Unsupported version upgrade path. Check the Elasticsearch documentation for supported upgrade paths."
+	unsupportedVersionMsg                       = "Unsupported version"
+	notAllowedNodesLabelMsg                     = "Node label not in the exposed node labels list"
+	unsupportedClientAuthenticationMsg          = "Mandatory client authentication is not supported"
+	autoscalingAnnotationUnsupportedErrMsg      = "autoscaling annotation is no longer supported"
 )
 
 type validation func(esv1.Elasticsearch) field.ErrorList
@@ -52,6 +90,9 @@ func updateValidations(ctx context.Context, k8sClient k8s.Client, validateStorag
 	return []updateValidation{
 		noDowngrades,
 		validUpgradePath,
+		noEphemeralStorageChange,
+		noFrozenCacheVolumeChange,
+		noInitialRestoreAdded,
 		func(current esv1.Elasticsearch, proposed esv1.Elasticsearch) field.ErrorList {
 			return validPVCModification(ctx, current, proposed, k8sClient, validateStorageClass)
 		},
@@ -69,9 +110,31 @@ func validations(ctx context.Context, checker license.Checker, exposedNodeLabels
 		hasCorrectNodeRoles,
 		supportedVersion,
 		validSanIP,
+		validTransportIssuer,
+		validIngress,
+		validGatewayRoute,
+		validServiceMesh,
+		validCertificateRotation,
+		validHostnames,
+		validServiceProfiles,
 		validAutoscalingConfiguration,
 		validPVCNaming,
+		validEphemeralStorage,
+		validFrozenCache,
+		validAdditionalVolumes,
+		validJVMDiagnostics,
+		validZoneAwareness,
+		validPreUpgradeVolumeSnapshots,
+		validInitialRestore,
+		validSnapshotRepositories,
+		validSnapshotLifecyclePolicies,
+		validKeystorePassword,
+		validSecureSettings,
+		validSSORealms,
+		validAuditing,
+		validRemoteClusters,
 		validMonitoring,
+		validMaintenanceWindows,
 		validAssociations,
 		func(proposed esv1.Elasticsearch) field.ErrorList {
 			return validLicenseLevel(ctx, proposed, checker)
@@ -237,9 +300,93 @@ func validSanIP(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// validTransportIssuer ensures an external signer of the transport certificates is not combined with another source of
+// transport certificates, and that its CA is trusted by the nodes.
+func validTransportIssuer(es esv1.Elasticsearch) field.ErrorList {
+	tls := es.Spec.Transport.TLS
+	if !tls.IssuerEnabled() {
+		return nil
+	}
+	path := field.NewPath("spec").Child("transport", "tls")
+	var errs field.ErrorList
+	if tls.UserDefinedCA() {
+		errs = append(errs, field.Forbidden(path.Child("issuer"), transportIssuerWithCAErrMsg))
+	}
+	if !tls.SelfSignedEnabled() {
+		errs = append(errs, field.Forbidden(path.Child("issuer"), transportIssuerWithDisabledErrMsg))
+	}
+	if tls.CertificateAuthorities.ConfigMapName == "" {
+		errs = append(errs, field.Required(path.Child("certificateAuthorities"), transportIssuerCAErrMsg))
+	}
+	return errs
+}
+
+func validIngress(es esv1.Elasticsearch) field.ErrorList {
+	return commonv1.CheckIngress(field.NewPath("spec").Child("http", "ingress"), es.Spec.HTTP)
+}
+
+func validGatewayRoute(es esv1.Elasticsearch) field.ErrorList {
+	return commonv1.CheckGatewayRoute(field.NewPath("spec").Child("http", "gatewayRoute"), es.Spec.HTTP)
+}
+
+func validServiceMesh(es esv1.Elasticsearch) field.ErrorList {
+	return commonv1.CheckServiceMesh(field.NewPath("spec").Child("http", "tls"), es.Spec.HTTP.TLS)
+}
+
+func validCertificateRotation(es esv1.Elasticsearch) field.ErrorList {
+	return append(
+		commonv1.CheckCertificateRotation(field.NewPath("spec").Child("http", "tls", "certificateRotation"), es.Spec.HTTP.TLS.CertificateRotation),
+		commonv1.CheckCertificateRotation(field.NewPath("spec").Child("transport", "tls", "certificateRotation"), es.Spec.Transport.TLS.CertificateRotation)...,
+	)
+}
+
+func validHostnames(es esv1.Elasticsearch) field.ErrorList {
+	return commonv1.CheckHostnames(field.NewPath("spec").Child("http", "hostnames"), es.Spec.HTTP)
+}
+
+// validServiceProfiles checks that the service profiles have unique names which can be used in the names of Services
+// and labels, and that they target known roles.
+func validServiceProfiles(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(es.Spec.HTTPServiceProfiles))
+	for i, profile := range es.Spec.HTTPServiceProfiles {
+		path := field.NewPath("spec").Child("httpServiceProfiles").Index(i)
+		if _, exists := names[profile.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), profile.Name))
+		}
+		names[profile.Name] = struct{}{}
+		for _, msg := range utilvalidation.IsDNS1123Label(profile.Name) {
+			errs = append(errs, field.Invalid(path.Child("name"), profile.Name, msg))
+		}
+		if profile.Name == "internal" {
+			errs = append(errs, field.Invalid(path.Child("name"), profile.Name, serviceProfileNameReservedErrMsg))
+		}
+		if _, err := esv1.ESNamer.SafeSuffix(es.Name, profile.Name, "http"); err != nil {
+			errs = append(errs, field.Invalid(path.Child("name"), profile.Name, err.Error()))
+		}
+		for j, role := range profile.Roles {
+			if _, known := label.NodeRoleLabels[esv1.NodeRole(role)]; !known && role != esv1.CoordinatingOnlyProfileRole {
+				errs = append(errs, field.NotSupported(path.Child("roles").Index(j), role, serviceProfileRoles()))
+			}
+		}
+	}
+	return errs
+}
+
+// serviceProfileRoles returns the sorted roles supported by the service profiles.
+func serviceProfileRoles() []string {
+	roles := []string{esv1.CoordinatingOnlyProfileRole}
+	for role := range label.NodeRoleLabels {
+		roles = append(roles, string(role))
+	}
+	sort.Strings(roles)
+	return roles
+}
+
 func checkNodeSetNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
 	var errs field.ErrorList
-	nodeSets := es.Spec.NodeSets
+	// zone-aware NodeSets are expanded into one NodeSet per zone, whose names must not clash with other NodeSets
+	nodeSets := es.Spec.ExpandedNodeSets()
 	names := make(map[string]struct{})
 	duplicates := make(map[string]struct{})
 	for _, nodeSet := range nodeSets {
@@ -248,7 +395,7 @@ func checkNodeSetNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
 		}
 		names[nodeSet.Name] = struct{}{}
 	}
-	for _, dupe := range duplicates {
+	for dupe := range duplicates {
 		errs = append(errs, field.Invalid(field.NewPath("spec").Child("nodeSets"), dupe, duplicateNodeSets))
 	}
 	return errs
@@ -328,15 +475,153 @@ func currentVersion(current esv1.Elasticsearch) (version.Version, *field.Error)
 	return currentVer, nil
 }
 
+// validInitialRestore ensures the repository and the snapshot to restore into a new cluster are specified.
+func validInitialRestore(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	restore := es.Spec.InitialRestore
+	if restore == nil {
+		return errs
+	}
+	path := field.NewPath("spec").Child("initialRestore")
+	if restore.Repository.Name == "" {
+		errs = append(errs, field.Required(path.Child("repository", "name"), "repository name is mandatory"))
+	}
+	if restore.Repository.Type == "" {
+		errs = append(errs, field.Required(path.Child("repository", "type"), "repository type is mandatory"))
+	}
+	if restore.Snapshot == "" {
+		errs = append(errs, field.Required(path.Child("snapshot"), "snapshot name or pattern is mandatory"))
+	}
+	return errs
+}
+
+// validSnapshotRepositories ensures the snapshot repositories of the specification have unique names.
+func validSnapshotRepositories(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := map[string]struct{}{}
+	for i, repository := range es.Spec.SnapshotRepositories {
+		if _, exists := names[repository.Name]; exists {
+			errs = append(errs, field.Duplicate(field.NewPath("spec").Child("snapshotRepositories").Index(i).Child("name"), repository.Name))
+		}
+		names[repository.Name] = struct{}{}
+	}
+	return errs
+}
+
+// slmMinVersion is the first version of Elasticsearch with snapshot lifecycle management.
+var slmMinVersion = version.From(7, 4, 0)
+
+// validSnapshotLifecyclePolicies ensures the snapshot lifecycle policies of the specification are supported by the
+// version of Elasticsearch, have unique names, and have a positive failure threshold.
+func validSnapshotLifecyclePolicies(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	if len(es.Spec.SnapshotLifecyclePolicies) == 0 {
+		return errs
+	}
+	path := field.NewPath("spec").Child("snapshotLifecyclePolicies")
+	if v, err := version.Parse(es.Spec.Version); err == nil && v.LT(slmMinVersion) {
+		// version parsing errors are already reported by the version validation
+		errs = append(errs, field.Forbidden(path, slmVersionErrMsg))
+	}
+	names := map[string]struct{}{}
+	for i, policy := range es.Spec.SnapshotLifecyclePolicies {
+		if _, exists := names[policy.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Index(i).Child("name"), policy.Name))
+		}
+		names[policy.Name] = struct{}{}
+		if policy.FailureThreshold != nil && policy.FailureThreshold.Duration <= 0 {
+			errs = append(errs, field.Invalid(path.Index(i).Child("failureThreshold"), policy.FailureThreshold.Duration.String(), slmFailureThresholdErrMsg))
+		}
+	}
+	return errs
+}
+
+// validAuditing checks the ignore filters of the audit log, whose names are used in the setting keys, and that the
+// audit log is shipped either with the other logs or on its own.
+func validAuditing(es esv1.Elasticsearch) field.ErrorList {
+	auditing := es.Spec.Auditing
+	if auditing == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("auditing")
+	names := map[string]struct{}{}
+	for i, filter := range auditing.IgnoreFilters {
+		filterPath := path.Child("ignoreFilters").Index(i)
+		if strings.Contains(filter.Name, ".") {
+			errs = append(errs, field.Invalid(filterPath.Child("name"), filter.Name, auditIgnoreFilterNameErrMsg))
+		} else if _, exists := names[filter.Name]; exists {
+			errs = append(errs, field.Duplicate(filterPath.Child("name"), filter.Name))
+		}
+		names[filter.Name] = struct{}{}
+		if len(filter.Users)+len(filter.Realms)+len(filter.Actions)+len(filter.Roles)+len(filter.Indices) == 0 {
+			errs = append(errs, field.Required(filterPath, auditIgnoreFilterCriteriaErrMsg))
+		}
+	}
+	if len(auditing.ElasticsearchRefs) > 0 && len(es.Spec.Monitoring.Logs.ElasticsearchRefs) > 0 {
+		errs = append(errs, field.Forbidden(path.Child("elasticsearchRefs"), auditLogsWithLogsMonitoringErrMsg))
+	}
+	return errs
+}
+
+// keystorePasswordMinVersion is the first version of Elasticsearch reading the password of the keystore from the file
+// referenced by the ES_KEYSTORE_PASSPHRASE_FILE environment variable.
+var keystorePasswordMinVersion = version.From(7, 9, 0)
+
+// validKeystorePassword ensures a keystore password is only specified for versions of Elasticsearch that can read it.
+func validKeystorePassword(es esv1.Elasticsearch) field.ErrorList {
+	if es.Spec.KeystorePassword == nil {
+		return nil
+	}
+	if v, err := version.Parse(es.Spec.Version); err == nil && v.LT(keystorePasswordMinVersion) {
+		// version parsing errors are already reported by the version validation
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("keystorePassword"), keystorePasswordVersionErrMsg)}
+	}
+	return nil
+}
+
+// validSecureSettings ensures the secure settings of the cluster and of its snapshot repositories reference either a
+// Secret or an external secret.
+func validSecureSettings(es esv1.Elasticsearch) field.ErrorList {
+	errs := commonv1.CheckSecretSources(field.NewPath("spec").Child("secureSettings"), es.Spec.SecureSettings, es.Namespace, true)
+	for i, repository := range es.Spec.SnapshotRepositories {
+		errs = append(errs, commonv1.CheckSecretSources(field.NewPath("spec").Child("snapshotRepositories").Index(i).Child("secureSettings"), repository.SecureSettings, es.Namespace, true)...)
+	}
+	return errs
+}
+
+// noInitialRestoreAdded ensures an initial snapshot restore is not added to an existing cluster, which may already
+// hold the indices to restore.
+func noInitialRestoreAdded(current, proposed esv1.Elasticsearch) field.ErrorList {
+	if current.Spec.InitialRestore == nil && proposed.Spec.InitialRestore != nil {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("initialRestore"), initialRestoreAddedErrMsg)}
+	}
+	return nil
+}
+
+func validMaintenanceWindows(es esv1.Elasticsearch) field.ErrorList {
+	return maintenance.Validate(field.NewPath("spec").Child("maintenanceWindows"), es.Spec.MaintenanceWindows)
+}
+
 func validMonitoring(es esv1.Elasticsearch) field.ErrorList {
-	return stackmon.Validate(&es, es.Spec.Version, stackmon.MinStackVersion)
+	errs := stackmon.Validate(&es, es.Spec.Version, stackmon.MinStackVersion)
+	if prometheus := es.Spec.Monitoring.Prometheus; prometheus != nil && prometheus.Exporter != nil &&
+		(prometheus.Port == network.HTTPPort || prometheus.Port == network.TransportPort) {
+		errs = append(errs, field.Invalid(field.NewPath("spec").Child("monitoring").Child("prometheus").Child("port"),
+			prometheus.Port, prometheusExporterPortErrMsg))
+	}
+	return errs
 }
 
 func validAssociations(es esv1.Elasticsearch) field.ErrorList {
 	monitoringPath := field.NewPath("spec").Child("monitoring")
 	err1 := commonv1.CheckAssociationRefs(monitoringPath.Child("metrics"), es.GetMonitoringMetricsRefs()...)
-	err2 := commonv1.CheckAssociationRefs(monitoringPath.Child("logs"), es.GetMonitoringLogsRefs()...)
-	return append(err1, err2...)
+	err2 := commonv1.CheckAssociationRefs(monitoringPath.Child("logs"), es.Spec.Monitoring.Logs.ElasticsearchRefs...)
+	errs := append(err1, err2...)
+	if es.Spec.Auditing != nil {
+		errs = append(errs, commonv1.CheckAssociationRefs(field.NewPath("spec").Child("auditing"), es.Spec.Auditing.ElasticsearchRefs...)...)
+	}
+	return errs
 }
 
 func validLicenseLevel(ctx context.Context, es esv1.Elasticsearch, checker license.Checker) field.ErrorList {
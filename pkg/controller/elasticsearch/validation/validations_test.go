@@ -6,6 +6,7 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -318,6 +319,118 @@ func Test_validSanIP(t *testing.T) {
 	}
 }
 
+func Test_validTransportIssuer(t *testing.T) {
+	issuer := &esv1.TransportCertificatesIssuer{SignerName: "clusterissuers.cert-manager.io/corporate-ca"}
+	cas := commonv1.ConfigMapRef{ConfigMapName: "corporate-ca"}
+	tests := []struct {
+		name    string
+		tls     esv1.TransportTLSOptions
+		wantErr []string
+	}{
+		{
+			name: "no issuer: OK",
+			tls:  esv1.TransportTLSOptions{},
+		},
+		{
+			name: "issuer with trusted CAs: OK",
+			tls:  esv1.TransportTLSOptions{Issuer: issuer, CertificateAuthorities: cas},
+		},
+		{
+			name:    "issuer without trusted CAs: NOT OK",
+			tls:     esv1.TransportTLSOptions{Issuer: issuer},
+			wantErr: []string{transportIssuerCAErrMsg},
+		},
+		{
+			name: "issuer with a user-provided CA: NOT OK",
+			tls: esv1.TransportTLSOptions{
+				Issuer:                 issuer,
+				CertificateAuthorities: cas,
+				Certificate:            commonv1.SecretRef{SecretName: "my-ca"},
+			},
+			wantErr: []string{transportIssuerWithCAErrMsg},
+		},
+		{
+			name: "issuer with self-signed certificates disabled: NOT OK",
+			tls: esv1.TransportTLSOptions{
+				Issuer:                 issuer,
+				CertificateAuthorities: cas,
+				SelfSignedCertificates: &esv1.SelfSignedTransportCertificates{Disabled: true},
+			},
+			wantErr: []string{transportIssuerWithDisabledErrMsg},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Transport: esv1.TransportConfig{TLS: tt.tls}}}
+			errs := validTransportIssuer(es)
+			details := make([]string, 0, len(errs))
+			for _, err := range errs {
+				details = append(details, err.Detail)
+			}
+			assert.ElementsMatch(t, tt.wantErr, details)
+		})
+	}
+}
+
+func Test_validServiceProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []esv1.ServiceProfile
+		wantErr  []string
+	}{
+		{
+			name: "no profile: OK",
+		},
+		{
+			name: "valid profiles: OK",
+			profiles: []esv1.ServiceProfile{
+				{Name: "coordinating", Roles: []string{esv1.CoordinatingOnlyProfileRole}},
+				{Name: "ingest", Roles: []string{"ingest", "data_hot"}},
+			},
+		},
+		{
+			name: "duplicate names: NOT OK",
+			profiles: []esv1.ServiceProfile{
+				{Name: "ingest", Roles: []string{"ingest"}},
+				{Name: "ingest", Roles: []string{"data"}},
+			},
+			wantErr: []string{"spec.httpServiceProfiles[1].name"},
+		},
+		{
+			name:     "invalid name: NOT OK",
+			profiles: []esv1.ServiceProfile{{Name: "Ingest_Nodes", Roles: []string{"ingest"}}},
+			wantErr:  []string{"spec.httpServiceProfiles[0].name"},
+		},
+		{
+			name:     "reserved name: NOT OK",
+			profiles: []esv1.ServiceProfile{{Name: "internal", Roles: []string{"ingest"}}},
+			wantErr:  []string{"spec.httpServiceProfiles[0].name"},
+		},
+		{
+			name:     "name too long: NOT OK",
+			profiles: []esv1.ServiceProfile{{Name: "a-very-long-service-profile-name", Roles: []string{"ingest"}}},
+			wantErr:  []string{"spec.httpServiceProfiles[0].name"},
+		},
+		{
+			name:     "unknown role: NOT OK",
+			profiles: []esv1.ServiceProfile{{Name: "ingest", Roles: []string{"ingest", "client"}}},
+			wantErr:  []string{"spec.httpServiceProfiles[0].roles[1]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := es("8.15.0")
+			es.Spec.HTTPServiceProfiles = tt.profiles
+			errs := validServiceProfiles(es)
+			fields := make([]string, 0, len(errs))
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			assert.ElementsMatch(t, tt.wantErr, fields)
+		})
+	}
+}
+
 func TestValidation_noDowngrades(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -699,6 +812,260 @@ func Test_validAssociations(t *testing.T) {
 	}
 }
 
+func Test_validInitialRestore(t *testing.T) {
+	tests := []struct {
+		name         string
+		restore      *esv1.InitialRestore
+		expectErrors int
+	}{
+		{
+			name: "no initial restore",
+		},
+		{
+			name:    "valid initial restore",
+			restore: &esv1.InitialRestore{Repository: esv1.InitialRestoreRepository{Name: "backups", Type: "s3"}, Snapshot: "nightly-*"},
+		},
+		{
+			name:         "missing repository and snapshot",
+			restore:      &esv1.InitialRestore{},
+			expectErrors: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proposed := es("8.15.0")
+			proposed.Spec.InitialRestore = tt.restore
+			assert.Len(t, validInitialRestore(proposed), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validMonitoring(t *testing.T) {
+	tests := []struct {
+		name         string
+		prometheus   *commonv1.PrometheusMonitoring
+		expectErrors int
+	}{
+		{
+			name: "no prometheus monitoring",
+		},
+		{
+			name:       "exporter on a dedicated port",
+			prometheus: &commonv1.PrometheusMonitoring{Port: 9114, Exporter: &commonv1.PrometheusExporter{}},
+		},
+		{
+			name:       "user provided exporter",
+			prometheus: &commonv1.PrometheusMonitoring{Port: 9200},
+		},
+		{
+			name:         "exporter on the HTTP port",
+			prometheus:   &commonv1.PrometheusMonitoring{Port: 9200, Exporter: &commonv1.PrometheusExporter{}},
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proposed := es("8.15.0")
+			proposed.Spec.Monitoring.Prometheus = tt.prometheus
+			assert.Len(t, validMonitoring(proposed), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validSnapshotRepositories(t *testing.T) {
+	tests := []struct {
+		name         string
+		repositories []esv1.SnapshotRepository
+		expectErrors int
+	}{
+		{
+			name: "no snapshot repository",
+		},
+		{
+			name:         "unique names",
+			repositories: []esv1.SnapshotRepository{{Name: "backups", Type: "s3"}, {Name: "local", Type: "fs"}},
+		},
+		{
+			name:         "duplicate names",
+			repositories: []esv1.SnapshotRepository{{Name: "backups", Type: "s3"}, {Name: "backups", Type: "fs"}},
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proposed := es("8.15.0")
+			proposed.Spec.SnapshotRepositories = tt.repositories
+			assert.Len(t, validSnapshotRepositories(proposed), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validSnapshotLifecyclePolicies(t *testing.T) {
+	nightly := esv1.SnapshotLifecyclePolicy{Name: "nightly", Schedule: "0 30 1 * * ?", Repository: "backups"}
+	withThreshold := func(policy esv1.SnapshotLifecyclePolicy, threshold time.Duration) esv1.SnapshotLifecyclePolicy {
+		policy.FailureThreshold = &metav1.Duration{Duration: threshold}
+		return policy
+	}
+	tests := []struct {
+		name         string
+		version      string
+		policies     []esv1.SnapshotLifecyclePolicy
+		expectErrors int
+	}{
+		{
+			name:    "no snapshot lifecycle policy",
+			version: "7.3.0",
+		},
+		{
+			name:     "valid policies",
+			version:  "8.15.0",
+			policies: []esv1.SnapshotLifecyclePolicy{nightly, withThreshold(esv1.SnapshotLifecyclePolicy{Name: "hourly", Schedule: "0 0 * * * ?", Repository: "backups"}, time.Hour)},
+		},
+		{
+			name:         "version before 7.4.0",
+			version:      "7.3.0",
+			policies:     []esv1.SnapshotLifecyclePolicy{nightly},
+			expectErrors: 1,
+		},
+		{
+			name:         "duplicate names",
+			version:      "8.15.0",
+			policies:     []esv1.SnapshotLifecyclePolicy{nightly, nightly},
+			expectErrors: 1,
+		},
+		{
+			name:         "negative failure threshold",
+			version:      "8.15.0",
+			policies:     []esv1.SnapshotLifecyclePolicy{withThreshold(nightly, -time.Hour)},
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proposed := es(tt.version)
+			proposed.Spec.SnapshotLifecyclePolicies = tt.policies
+			assert.Len(t, validSnapshotLifecyclePolicies(proposed), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validAuditing(t *testing.T) {
+	ref := []commonv1.ObjectSelector{{Name: "monitoring", Namespace: "observability"}}
+	tests := []struct {
+		name           string
+		auditing       *esv1.Auditing
+		monitoringLogs []commonv1.ObjectSelector
+		expectErrors   int
+	}{
+		{
+			name: "no auditing",
+		},
+		{
+			name: "valid auditing",
+			auditing: &esv1.Auditing{
+				IncludeEvents:     []string{"access_denied"},
+				IgnoreFilters:     []esv1.AuditIgnoreFilter{{Name: "system", Realms: []string{"__attach", "reserved"}}, {Name: "kibana", Users: []string{"kibana_system"}}},
+				ElasticsearchRefs: ref,
+			},
+		},
+		{
+			name:           "audit log shipped with logs monitoring",
+			auditing:       &esv1.Auditing{},
+			monitoringLogs: ref,
+		},
+		{
+			name:         "invalid ignore filters",
+			auditing:     &esv1.Auditing{IgnoreFilters: []esv1.AuditIgnoreFilter{{Name: "a.b", Users: []string{"*"}}, {Name: "c"}, {Name: "c", Roles: []string{"*"}}}},
+			expectErrors: 3,
+		},
+		{
+			name:           "audit log references with logs monitoring",
+			auditing:       &esv1.Auditing{ElasticsearchRefs: ref},
+			monitoringLogs: ref,
+			expectErrors:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proposed := es("8.15.0")
+			proposed.Spec.Auditing = tt.auditing
+			proposed.Spec.Monitoring.Logs.ElasticsearchRefs = tt.monitoringLogs
+			assert.Len(t, validAuditing(proposed), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validKeystorePassword(t *testing.T) {
+	password := &esv1.SecretKeyRef{SecretName: "keystore-password", Key: "password"}
+	tests := []struct {
+		name         string
+		version      string
+		password     *esv1.SecretKeyRef
+		expectErrors int
+	}{
+		{
+			name:    "no keystore password",
+			version: "7.8.0",
+		},
+		{
+			name:     "keystore password",
+			version:  "8.15.0",
+			password: password,
+		},
+		{
+			name:         "version before 7.9.0",
+			version:      "7.8.0",
+			password:     password,
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proposed := es(tt.version)
+			proposed.Spec.KeystorePassword = tt.password
+			assert.Len(t, validKeystorePassword(proposed), tt.expectErrors)
+		})
+	}
+}
+
+func Test_noInitialRestoreAdded(t *testing.T) {
+	restore := &esv1.InitialRestore{Repository: esv1.InitialRestoreRepository{Name: "backups", Type: "s3"}, Snapshot: "nightly-*"}
+	withRestore := func(restore *esv1.InitialRestore) esv1.Elasticsearch {
+		cluster := es("8.15.0")
+		cluster.Spec.InitialRestore = restore
+		return cluster
+	}
+	tests := []struct {
+		name         string
+		current      esv1.Elasticsearch
+		proposed     esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name:     "initial restore unchanged",
+			current:  withRestore(restore),
+			proposed: withRestore(restore),
+		},
+		{
+			name:     "initial restore removed",
+			current:  withRestore(restore),
+			proposed: withRestore(nil),
+		},
+		{
+			name:         "initial restore added",
+			current:      withRestore(nil),
+			proposed:     withRestore(restore),
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := noInitialRestoreAdded(tt.current, tt.proposed)
+			assert.Equal(t, tt.expectErrors, len(actual) > 0)
+		})
+	}
+}
+
 // es returns an es fixture at a given version
 func es(v string) esv1.Elasticsearch {
 	return esv1.Elasticsearch{
@@ -6,6 +6,8 @@ package validation
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/autoscaling"
 	volumevalidations "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume/validations"
@@ -17,11 +19,15 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 func validPVCNaming(proposed esv1.Elasticsearch) field.ErrorList {
@@ -44,14 +50,23 @@ func validPVCNaming(proposed esv1.Elasticsearch) field.ErrorList {
 }
 
 func unmountedClaims(ns esv1.NodeSet) []corev1.PersistentVolumeClaim {
-	templates := ns.VolumeClaimTemplates
+	mounted := set.Make()
 	for _, c := range ns.PodTemplate.Spec.Containers {
 		for _, vm := range c.VolumeMounts {
-			for i := len(templates) - 1; i >= 0; i-- {
-				if templates[i].Name == vm.Name {
-					templates = append(templates[:i], templates[i+1:]...)
-				}
-			}
+			mounted.Add(vm.Name)
+		}
+	}
+	// additional volumes are mounted by the operator
+	for _, v := range ns.AdditionalVolumes {
+		mounted.Add(v.Name)
+	}
+	if ns.JVMDiagnostics != nil && ns.JVMDiagnostics.VolumeClaimTemplate != "" {
+		mounted.Add(ns.JVMDiagnostics.VolumeClaimTemplate)
+	}
+	var templates []corev1.PersistentVolumeClaim
+	for _, t := range ns.VolumeClaimTemplates {
+		if !mounted.Has(t.Name) {
+			templates = append(templates, t)
 		}
 	}
 	return templates
@@ -66,9 +81,276 @@ func hasDefaultClaim(templates []corev1.PersistentVolumeClaim) bool {
 	return false
 }
 
+// validEphemeralStorage ensures ephemeral storage is only used without volume claim templates, with a writable CSI volume if
+// any, and for nodes that do not hold any data that cannot be recovered: master nodes and data nodes other than frozen
+// tier nodes must persist their data.
+func validEphemeralStorage(proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	v, err := version.Parse(proposed.Spec.Version)
+	if err != nil {
+		// already reported by the version validation
+		return errs
+	}
+	for i, ns := range proposed.Spec.NodeSets {
+		if ns.EphemeralStorage == nil {
+			continue
+		}
+		path := field.NewPath("spec").Child("nodeSets").Index(i).Child("ephemeralStorage")
+		if len(ns.VolumeClaimTemplates) > 0 {
+			errs = append(errs, field.Forbidden(path, ephemeralStorageWithClaimsErrMsg))
+			continue
+		}
+		if csi := ns.EphemeralStorage.CSI; csi != nil {
+			if ns.EphemeralStorage.SizeLimit != nil || ns.EphemeralStorage.Medium != "" {
+				errs = append(errs, field.Forbidden(path.Child("csi"), ephemeralStorageCSIErrMsg))
+			}
+			if csi.ReadOnly != nil && *csi.ReadOnly {
+				errs = append(errs, field.Invalid(path.Child("csi", "readOnly"), *csi.ReadOnly, ephemeralStorageCSIReadOnlyErrMsg))
+			}
+		}
+		cfg := esv1.ElasticsearchSettings{}
+		if err := esv1.UnpackConfig(ns.Config, v, &cfg); err != nil {
+			// already reported by the node roles validation
+			continue
+		}
+		errs = append(errs, ephemeralStorageRoles(path, cfg)...)
+	}
+	return errs
+}
+
+// ephemeralStorageRoles returns an error if the node has a role requiring its data to be persisted.
+func ephemeralStorageRoles(path *field.Path, cfg esv1.ElasticsearchSettings) field.ErrorList {
+	for _, role := range []esv1.NodeRole{
+		esv1.MasterRole, esv1.DataRole, esv1.DataHotRole, esv1.DataWarmRole, esv1.DataColdRole, esv1.DataContentRole,
+	} {
+		if cfg.Node.IsConfiguredWithRole(role) {
+			return field.ErrorList{field.Forbidden(path, fmt.Sprintf(ephemeralStorageRoleErrMsg, role))}
+		}
+	}
+	return nil
+}
+
+// frozenTierMinVersion is the first version of Elasticsearch with the frozen tier.
+var frozenTierMinVersion = version.From(7, 12, 0)
+
+// validFrozenCache ensures the shared cache is only configured for frozen tier nodes, that its size is valid and fits
+// in the data volume, and that its dedicated volume does not conflict with another data volume.
+func validFrozenCache(proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	v, err := version.Parse(proposed.Spec.Version)
+	if err != nil {
+		// already reported by the version validation
+		return errs
+	}
+	for i, ns := range proposed.Spec.NodeSets {
+		if ns.FrozenCache == nil {
+			continue
+		}
+		path := field.NewPath("spec").Child("nodeSets").Index(i).Child("frozenCache")
+		if v.LT(frozenTierMinVersion) {
+			errs = append(errs, field.Forbidden(path, frozenCacheVersionErrMsg))
+			continue
+		}
+		cfg := esv1.ElasticsearchSettings{}
+		if err := esv1.UnpackConfig(ns.Config, v, &cfg); err != nil {
+			// already reported by the node roles validation
+			continue
+		}
+		if !cfg.Node.IsConfiguredWithRole(esv1.DataFrozenRole) {
+			errs = append(errs, field.Forbidden(path, frozenCacheRoleErrMsg))
+			continue
+		}
+
+		if ns.FrozenCache.Size != "" {
+			errs = append(errs, validFrozenCacheSize(path.Child("size"), ns)...)
+		}
+
+		cacheVolume := ns.FrozenCache.Volume
+		if cacheVolume == nil {
+			continue
+		}
+		switch {
+		case ns.EphemeralStorage != nil:
+			errs = append(errs, field.Forbidden(path.Child("volume"), frozenCacheVolumeWithEphemeralStorageErrMsg))
+		case hasDefaultClaim(ns.VolumeClaimTemplates):
+			errs = append(errs, field.Forbidden(path.Child("volume"), frozenCacheVolumeWithDataClaimErrMsg))
+		case cacheVolume.Storage.Sign() <= 0:
+			errs = append(errs, field.Invalid(path.Child("volume", "storage"), cacheVolume.Storage.String(), frozenCacheVolumeStorageErrMsg))
+		case cacheVolume.Ephemeral:
+			errs = append(errs, ephemeralStorageRoles(path.Child("volume", "ephemeral"), cfg)...)
+		}
+	}
+	return errs
+}
+
+// validFrozenCacheSize ensures the size of the shared cache is a valid percentage or quantity, is not also set in the
+// configuration of the nodeSet, and does not exceed the size of the data volume if known.
+func validFrozenCacheSize(path *field.Path, ns esv1.NodeSet) field.ErrorList {
+	size := ns.FrozenCache.Size
+	if _, err := ns.FrozenCache.SharedCacheSize(); err != nil {
+		return field.ErrorList{field.Invalid(path, size, frozenCacheSizeErrMsg)}
+	}
+	if ns.Config != nil {
+		if cfg, err := common.NewCanonicalConfigFrom(ns.Config.Data); err == nil && len(cfg.HasKeys([]string{esv1.SearchableSnapshotSharedCacheSize})) > 0 {
+			return field.ErrorList{field.Forbidden(path, frozenCacheSizeConfiguredErrMsg)}
+		}
+	}
+	if strings.HasSuffix(size, "%") {
+		return nil
+	}
+	quantity := resource.MustParse(size)
+	if volumeSize := dataVolumeSize(ns); volumeSize != nil && quantity.Cmp(*volumeSize) > 0 {
+		return field.ErrorList{field.Invalid(path, size, fmt.Sprintf(frozenCacheSizeExceedsVolumeErrMsg, volumeSize.String()))}
+	}
+	return nil
+}
+
+// dataVolumeSize returns the size of the data volume of the nodeSet, or nil if it is not known.
+func dataVolumeSize(ns esv1.NodeSet) *resource.Quantity {
+	switch {
+	case ns.FrozenCache != nil && ns.FrozenCache.Volume != nil:
+		return &ns.FrozenCache.Volume.Storage
+	case ns.EphemeralStorage != nil:
+		return ns.EphemeralStorage.SizeLimit
+	}
+	for _, claim := range ns.VolumeClaimTemplates {
+		if claim.Name != volume.ElasticsearchDataVolumeName {
+			continue
+		}
+		if storage, exists := claim.Spec.Resources.Requests[corev1.ResourceStorage]; exists {
+			return &storage
+		}
+	}
+	return nil
+}
+
+// validAdditionalVolumes ensures additional volumes are backed by a volume claim template of their nodeSet, are not
+// mounted at the same path twice, and only add data paths to versions of Elasticsearch supporting multiple data paths.
+func validAdditionalVolumes(proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	v, err := version.Parse(proposed.Spec.Version)
+	if err != nil {
+		// already reported by the version validation
+		return errs
+	}
+	for i, ns := range proposed.Spec.NodeSets {
+		claims := set.Make()
+		for _, claim := range ns.VolumeClaimTemplates {
+			claims.Add(claim.Name)
+		}
+		seenNames, seenUsages := set.Make(), set.Make()
+		for j, additionalVolume := range ns.AdditionalVolumes {
+			path := field.NewPath("spec").Child("nodeSets").Index(i).Child("additionalVolumes").Index(j)
+			switch {
+			case additionalVolume.Name == volume.ElasticsearchDataVolumeName:
+				errs = append(errs, field.Invalid(path.Child("name"), additionalVolume.Name, additionalVolumeIsDataVolumeErrMsg))
+			case !claims.Has(additionalVolume.Name):
+				errs = append(errs, field.Invalid(path.Child("name"), additionalVolume.Name, additionalVolumeNotClaimedErrMsg))
+			case seenNames.Has(additionalVolume.Name):
+				errs = append(errs, field.Duplicate(path.Child("name"), additionalVolume.Name))
+			}
+			seenNames.Add(additionalVolume.Name)
+
+			switch additionalVolume.Usage {
+			case esv1.DataVolumeUsage:
+				if v.GTE(esv1.MultipleDataPathsRemovedVersion) {
+					errs = append(errs, field.Forbidden(path.Child("usage"), multipleDataPathsErrMsg))
+				}
+			case esv1.LogsVolumeUsage, esv1.TempVolumeUsage:
+				if seenUsages.Has(string(additionalVolume.Usage)) {
+					errs = append(errs, field.Duplicate(path.Child("usage"), additionalVolume.Usage))
+				}
+				seenUsages.Add(string(additionalVolume.Usage))
+			}
+		}
+	}
+	return errs
+}
+
+// validJVMDiagnostics ensures the volume storing the JVM heap dumps and GC logs is backed by a volume claim template of
+// its nodeSet that is not already used by Elasticsearch.
+func validJVMDiagnostics(proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, ns := range proposed.Spec.NodeSets {
+		if ns.JVMDiagnostics == nil || ns.JVMDiagnostics.VolumeClaimTemplate == "" {
+			continue
+		}
+		claimName := ns.JVMDiagnostics.VolumeClaimTemplate
+		path := field.NewPath("spec").Child("nodeSets").Index(i).Child("jvmDiagnostics", "volumeClaimTemplate")
+		inUse := claimName == volume.ElasticsearchDataVolumeName
+		for _, additionalVolume := range ns.AdditionalVolumes {
+			inUse = inUse || additionalVolume.Name == claimName
+		}
+		claimed := false
+		for _, claim := range ns.VolumeClaimTemplates {
+			claimed = claimed || claim.Name == claimName
+		}
+		switch {
+		case inUse:
+			errs = append(errs, field.Invalid(path, claimName, jvmDiagnosticsVolumeInUseErrMsg))
+		case !claimed:
+			errs = append(errs, field.Invalid(path, claimName, jvmDiagnosticsVolumeNotClaimedErrMsg))
+		}
+	}
+	return errs
+}
+
+// noEphemeralStorageChange ensures existing nodeSets are not moved from persistent volumes to ephemeral storage, or the
+// other way around: the volume claim templates of a StatefulSet cannot be updated. The nodeSet must be renamed instead.
+func noEphemeralStorageChange(current esv1.Elasticsearch, proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, proposedNodeSet := range proposed.Spec.NodeSets {
+		currentNodeSet := getNodeSet(proposedNodeSet.Name, current)
+		if currentNodeSet == nil {
+			continue
+		}
+		if (currentNodeSet.EphemeralStorage == nil) != (proposedNodeSet.EphemeralStorage == nil) {
+			errs = append(errs, field.Forbidden(
+				field.NewPath("spec").Child("nodeSets").Index(i).Child("ephemeralStorage"),
+				ephemeralStorageChangeErrMsg,
+			))
+		}
+	}
+	return errs
+}
+
+// noFrozenCacheVolumeChange ensures the volume dedicated to the shared cache of an existing nodeSet is not added,
+// removed, or moved to another kind of storage, and that its persistent storage is not decreased.
+func noFrozenCacheVolumeChange(current esv1.Elasticsearch, proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, proposedNodeSet := range proposed.Spec.NodeSets {
+		currentNodeSet := getNodeSet(proposedNodeSet.Name, current)
+		if currentNodeSet == nil {
+			continue
+		}
+		currentVolume, proposedVolume := frozenCacheVolume(*currentNodeSet), frozenCacheVolume(proposedNodeSet)
+		if currentVolume == nil && proposedVolume == nil {
+			continue
+		}
+		path := field.NewPath("spec").Child("nodeSets").Index(i).Child("frozenCache", "volume")
+		switch {
+		case currentVolume == nil || proposedVolume == nil ||
+			currentVolume.Ephemeral != proposedVolume.Ephemeral ||
+			ptr.Deref(currentVolume.StorageClassName, "") != ptr.Deref(proposedVolume.StorageClassName, ""):
+			errs = append(errs, field.Forbidden(path, frozenCacheVolumeChangeErrMsg))
+		case !proposedVolume.Ephemeral && proposedVolume.Storage.Cmp(currentVolume.Storage) < 0:
+			errs = append(errs, field.Forbidden(path.Child("storage"), frozenCacheVolumeDecreaseErrMsg))
+		}
+	}
+	return errs
+}
+
+func frozenCacheVolume(ns esv1.NodeSet) *esv1.FrozenCacheVolume {
+	if ns.FrozenCache == nil {
+		return nil
+	}
+	return ns.FrozenCache.Volume
+}
+
 // validPVCModification ensures the only part of volume claim templates that can be changed is storage requests.
 // Storage increase is allowed as long as the storage class supports volume expansion.
 // Storage decrease is not supported if the corresponding StatefulSet has been resized already.
+// The storage class can also be changed if the user confirmed the migration of the nodeSet to a new storage class.
 func validPVCModification(ctx context.Context, current esv1.Elasticsearch, proposed esv1.Elasticsearch, k8sClient k8s.Client, validateStorageClass bool) field.ErrorList {
 	log := ulog.FromContext(ctx)
 	var errs field.ErrorList
@@ -102,10 +384,12 @@ func validPVCModification(ctx context.Context, current esv1.Elasticsearch, propo
 			continue
 		}
 
-		// Check that no modification was made to the claims, except on storage requests.
+		// Check that no modification was made to the claims, except on storage requests, or on the storage class
+		// if the migration of the nodeSet to a new storage class has been confirmed.
+		migrateStorageClass := proposed.StorageClassMigrationNodeSets().Has(proposedNodeSet.Name)
 		if !apiequality.Semantic.DeepEqual(
-			claimsWithoutStorageReq(currentNodeSet.VolumeClaimTemplates),
-			claimsWithoutStorageReq(proposedNodeSet.VolumeClaimTemplates),
+			comparableClaims(currentNodeSet.VolumeClaimTemplates, migrateStorageClass),
+			comparableClaims(proposedNodeSet.VolumeClaimTemplates, migrateStorageClass),
 		) {
 			errs = append(errs, field.Invalid(
 				field.NewPath("spec").Child("nodeSet").Index(i).Child("volumeClaimTemplates"),
@@ -121,7 +405,7 @@ func validPVCModification(ctx context.Context, current esv1.Elasticsearch, propo
 		// errors out for some reasons, then reverts the storage size to a correct 1GB. In that case the StatefulSet
 		// claim is still configured with 1GB even though the current Elasticsearch specifies 2GB.
 		// Hence here we compare proposed claims with **current StatefulSet** claims.
-		matchingSsetName := esv1.StatefulSet(proposed.Name, proposedNodeSet.Name)
+		matchingSsetName := proposed.StatefulSetName(proposedNodeSet.Name)
 		var matchingSset appsv1.StatefulSet
 		err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: proposed.Namespace, Name: matchingSsetName}, &matchingSset)
 		if err != nil && apierrors.IsNotFound(err) {
@@ -135,7 +419,17 @@ func validPVCModification(ctx context.Context, current esv1.Elasticsearch, propo
 			continue
 		}
 
-		if err := volumevalidations.ValidateClaimsStorageUpdate(ctx, k8sClient, matchingSset.Spec.VolumeClaimTemplates, proposedNodeSet.VolumeClaimTemplates, validateStorageClass); err != nil {
+		if migrateStorageClass && volumevalidations.StorageClassChanged(matchingSset.Spec.VolumeClaimTemplates, proposedNodeSet.VolumeClaimTemplates) {
+			// new volumes are created in the new storage class, with no restriction on their size
+			continue
+		}
+
+		err = volumevalidations.ValidateClaimsStorageUpdate(ctx, k8sClient, matchingSset.Spec.VolumeClaimTemplates, proposedNodeSet.VolumeClaimTemplates, validateStorageClass)
+		if err != nil && volumevalidations.IsExpansionNotSupported(err) && proposed.VolumeResizeMigrationNodeSets().Has(proposedNodeSet.Name) {
+			// the nodeSet is moved to new volumes of the requested size
+			continue
+		}
+		if err != nil {
 			errs = append(errs, field.Invalid(
 				field.NewPath("spec").Child("nodeSet").Index(i).Child("volumeClaimTemplates"),
 				proposedNodeSet.VolumeClaimTemplates,
@@ -155,13 +449,36 @@ func getNodeSet(name string, es esv1.Elasticsearch) *esv1.NodeSet {
 	return nil
 }
 
-// claimsWithoutStorageReq returns a copy of the given claims, with all storage requests set to the empty quantity.
-func claimsWithoutStorageReq(claims []corev1.PersistentVolumeClaim) []corev1.PersistentVolumeClaim {
+// comparableClaims returns a copy of the given claims, with all storage requests set to the empty quantity, and
+// without storage class if withoutStorageClass is true.
+func comparableClaims(claims []corev1.PersistentVolumeClaim, withoutStorageClass bool) []corev1.PersistentVolumeClaim {
 	result := make([]corev1.PersistentVolumeClaim, 0, len(claims))
 	for _, claim := range claims {
 		patchedClaim := *claim.DeepCopy()
 		patchedClaim.Spec.Resources.Requests[corev1.ResourceStorage] = resource.Quantity{}
+		if withoutStorageClass {
+			patchedClaim.Spec.StorageClassName = nil
+		}
 		result = append(result, patchedClaim)
 	}
 	return result
 }
+
+// validPreUpgradeVolumeSnapshots checks that the nodeSets whose volumes are snapshotted before upgrades exist.
+func validPreUpgradeVolumeSnapshots(proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	if proposed.Spec.PreUpgradeVolumeSnapshots == nil {
+		return errs
+	}
+	nodeSets := set.Make()
+	for _, ns := range proposed.Spec.NodeSets {
+		nodeSets.Add(ns.Name)
+	}
+	for i, name := range proposed.Spec.PreUpgradeVolumeSnapshots.NodeSets {
+		if !nodeSets.Has(name) {
+			path := field.NewPath("spec").Child("preUpgradeVolumeSnapshots", "nodeSets").Index(i)
+			errs = append(errs, field.Invalid(path, name, preUpgradeSnapshotNodeSetErrMsg))
+		}
+	}
+	return errs
+}
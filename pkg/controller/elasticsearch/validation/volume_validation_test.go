@@ -16,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
@@ -40,6 +41,12 @@ var (
 			}}}}
 )
 
+func withStorageClass(claim corev1.PersistentVolumeClaim, storageClass string) corev1.PersistentVolumeClaim {
+	c := claim.DeepCopy()
+	c.Spec.StorageClassName = ptr.To[string](storageClass)
+	return *c
+}
+
 func withStorageReq(claim corev1.PersistentVolumeClaim, size string) corev1.PersistentVolumeClaim {
 	c := claim.DeepCopy()
 	c.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(size)
@@ -53,6 +60,14 @@ func Test_validPVCModification(t *testing.T) {
 			Spec:       esv1.ElasticsearchSpec{NodeSets: nodeSets},
 		}
 	}
+	withStorageClassMigration := func(es esv1.Elasticsearch, nodeSets string) esv1.Elasticsearch {
+		es.Annotations = map[string]string{esv1.StorageClassMigrationAnnotation: nodeSets}
+		return es
+	}
+	withVolumeResizeMigration := func(es esv1.Elasticsearch, nodeSets string) esv1.Elasticsearch {
+		es.Annotations = map[string]string{esv1.VolumeResizeMigrationAnnotation: nodeSets}
+		return es
+	}
 	type args struct {
 		current              esv1.Elasticsearch
 		proposed             esv1.Elasticsearch
@@ -189,6 +204,153 @@ func Test_validPVCModification(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "storage class change in the proposed Elasticsearch: error",
+			args: args{
+				current: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+				}),
+				proposed: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{withStorageClass(sampleClaim, "new-sc")}},
+				}),
+				k8sClient: k8s.NewFakeClient(
+					&appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster-es-set1"},
+						Spec:       appsv1.StatefulSetSpec{VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+					}),
+				validateStorageClass: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "storage class change confirmed for another nodeSet: error",
+			args: args{
+				current: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+				}),
+				proposed: withStorageClassMigration(es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{withStorageClass(sampleClaim, "new-sc")}},
+				}), "set2"),
+				k8sClient: k8s.NewFakeClient(
+					&appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster-es-set1"},
+						Spec:       appsv1.StatefulSetSpec{VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+					}),
+				validateStorageClass: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "storage class change confirmed, with a storage decrease: ok",
+			args: args{
+				current: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+				}),
+				proposed: withStorageClassMigration(es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{withStorageReq(withStorageClass(sampleClaim, "new-sc"), "0.5Gi")}},
+				}), "set1"),
+				k8sClient: k8s.NewFakeClient(
+					&appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster-es-set1"},
+						Spec:       appsv1.StatefulSetSpec{VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+					}),
+				validateStorageClass: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "storage class change confirmed, with another change in the claims: error",
+			args: args{
+				current: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim, sampleClaim2}},
+				}),
+				proposed: withStorageClassMigration(es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{withStorageClass(sampleClaim, "new-sc")}},
+				}), "set1"),
+				k8sClient: k8s.NewFakeClient(
+					&appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster-es-set1"},
+						Spec:       appsv1.StatefulSetSpec{VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim, sampleClaim2}},
+					}),
+				validateStorageClass: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "storage increase without volume expansion support: error",
+			args: args{
+				current: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+				}),
+				proposed: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{withStorageReq(sampleClaim, "2Gi")}},
+				}),
+				k8sClient: k8s.NewFakeClient(
+					&sampleStorageClass,
+					&appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster-es-set1"},
+						Spec:       appsv1.StatefulSetSpec{VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+					}),
+				validateStorageClass: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "storage increase without volume expansion support, volume resize migration confirmed: ok",
+			args: args{
+				current: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+				}),
+				proposed: withVolumeResizeMigration(es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{withStorageReq(sampleClaim, "2Gi")}},
+				}), "set1"),
+				k8sClient: k8s.NewFakeClient(
+					&sampleStorageClass,
+					&appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster-es-set1"},
+						Spec:       appsv1.StatefulSetSpec{VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+					}),
+				validateStorageClass: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "storage decrease, volume resize migration confirmed: error",
+			args: args{
+				current: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+				}),
+				proposed: withVolumeResizeMigration(es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{withStorageReq(sampleClaim, "0.5Gi")}},
+				}), "set1"),
+				k8sClient: k8s.NewFakeClient(
+					&sampleStorageClass,
+					&appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster-es-set1"},
+						Spec:       appsv1.StatefulSetSpec{VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+					}),
+				validateStorageClass: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "storage decrease after a storage class migration: error",
+			args: args{
+				current: withStorageClassMigration(es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{withStorageClass(sampleClaim, "new-sc")}},
+				}), "set1"),
+				proposed: withStorageClassMigration(es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{withStorageReq(withStorageClass(sampleClaim, "new-sc"), "0.5Gi")}},
+				}), "set1"),
+				k8sClient: k8s.NewFakeClient(
+					&appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster-es-set1"},
+						Spec:       appsv1.StatefulSetSpec{VolumeClaimTemplates: []corev1.PersistentVolumeClaim{withStorageClass(sampleClaim, "new-sc")}},
+					}),
+				validateStorageClass: true,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -286,6 +448,24 @@ func Test_validPVCNaming(t *testing.T) {
 			es:      esWithClaim("my-data", esWithSidecar),
 			wantErr: false,
 		},
+		{
+			name: "custom claim declared as an additional volume is OK",
+			es: func() esv1.Elasticsearch {
+				es := esWithVolumeMount("my-data", esWithClaim("disk-1", esWithClaim("my-data", esFixture())))
+				es.Spec.NodeSets[0].AdditionalVolumes = []esv1.AdditionalVolume{{Name: "disk-1", Usage: esv1.DataVolumeUsage}}
+				return es
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "custom claim declared as the JVM diagnostics volume is OK",
+			es: func() esv1.Elasticsearch {
+				es := esWithVolumeMount("my-data", esWithClaim("dumps", esWithClaim("my-data", esFixture())))
+				es.Spec.NodeSets[0].JVMDiagnostics = &esv1.JVMDiagnostics{VolumeClaimTemplate: "dumps"}
+				return es
+			}(),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -298,3 +478,548 @@ func Test_validPVCNaming(t *testing.T) {
 		})
 	}
 }
+
+func Test_validEphemeralStorage(t *testing.T) {
+	esWithNodeSet := func(nodeSet esv1.NodeSet) esv1.Elasticsearch {
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.15.0", NodeSets: []esv1.NodeSet{nodeSet}}}
+	}
+	withRoles := func(roles ...string) *commonv1.Config {
+		return &commonv1.Config{Data: map[string]interface{}{"node.roles": roles}}
+	}
+	tests := []struct {
+		name    string
+		es      esv1.Elasticsearch
+		wantErr bool
+	}{
+		{
+			name:    "no ephemeral storage is OK",
+			es:      esWithNodeSet(esv1.NodeSet{Name: "default"}),
+			wantErr: false,
+		},
+		{
+			name:    "coordinating nodes are OK",
+			es:      esWithNodeSet(esv1.NodeSet{Name: "coord", Config: withRoles(), EphemeralStorage: &esv1.EphemeralStorage{}}),
+			wantErr: false,
+		},
+		{
+			name:    "ML nodes are OK",
+			es:      esWithNodeSet(esv1.NodeSet{Name: "ml", Config: withRoles("ml", "remote_cluster_client"), EphemeralStorage: &esv1.EphemeralStorage{}}),
+			wantErr: false,
+		},
+		{
+			name:    "frozen tier nodes are OK",
+			es:      esWithNodeSet(esv1.NodeSet{Name: "frozen", Config: withRoles("data_frozen"), EphemeralStorage: &esv1.EphemeralStorage{}}),
+			wantErr: false,
+		},
+		{
+			name:    "nodes with all the default roles are NOK",
+			es:      esWithNodeSet(esv1.NodeSet{Name: "default", EphemeralStorage: &esv1.EphemeralStorage{}}),
+			wantErr: true,
+		},
+		{
+			name:    "master nodes are NOK",
+			es:      esWithNodeSet(esv1.NodeSet{Name: "master", Config: withRoles("master"), EphemeralStorage: &esv1.EphemeralStorage{}}),
+			wantErr: true,
+		},
+		{
+			name:    "hot tier nodes are NOK",
+			es:      esWithNodeSet(esv1.NodeSet{Name: "hot", Config: withRoles("data_hot"), EphemeralStorage: &esv1.EphemeralStorage{}}),
+			wantErr: true,
+		},
+		{
+			name: "ephemeral storage with volume claim templates is NOK",
+			es: esWithNodeSet(esv1.NodeSet{
+				Name: "coord", Config: withRoles(), EphemeralStorage: &esv1.EphemeralStorage{},
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "CSI ephemeral volume is OK",
+			es: esWithNodeSet(esv1.NodeSet{
+				Name: "frozen", Config: withRoles("data_frozen"),
+				EphemeralStorage: &esv1.EphemeralStorage{CSI: &corev1.CSIVolumeSource{Driver: "local.csi.example.com"}},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "CSI ephemeral volume with a size limit is NOK",
+			es: esWithNodeSet(esv1.NodeSet{
+				Name: "frozen", Config: withRoles("data_frozen"),
+				EphemeralStorage: &esv1.EphemeralStorage{SizeLimit: ptr.To(resource.MustParse("10Gi")), CSI: &corev1.CSIVolumeSource{Driver: "local.csi.example.com"}},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "read-only CSI ephemeral volume is NOK",
+			es: esWithNodeSet(esv1.NodeSet{
+				Name: "frozen", Config: withRoles("data_frozen"),
+				EphemeralStorage: &esv1.EphemeralStorage{CSI: &corev1.CSIVolumeSource{Driver: "local.csi.example.com", ReadOnly: ptr.To(true)}},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validEphemeralStorage(tt.es)
+			if tt.wantErr {
+				require.NotEmpty(t, got)
+			} else {
+				require.Empty(t, got)
+			}
+		})
+	}
+}
+
+func Test_validAdditionalVolumes(t *testing.T) {
+	claim := func(name string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	esWithAdditionalVolumes := func(version string, additionalVolumes ...esv1.AdditionalVolume) esv1.Elasticsearch {
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: version, NodeSets: []esv1.NodeSet{{
+			Name:                 "default",
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claim("elasticsearch-data"), claim("disk-1"), claim("disk-2")},
+			AdditionalVolumes:    additionalVolumes,
+		}}}}
+	}
+	tests := []struct {
+		name    string
+		es      esv1.Elasticsearch
+		wantErr bool
+	}{
+		{
+			name:    "no additional volumes is OK",
+			es:      esWithAdditionalVolumes("8.15.0"),
+			wantErr: false,
+		},
+		{
+			name: "additional data and logs volumes before 8.0.0 are OK",
+			es: esWithAdditionalVolumes("7.17.0",
+				esv1.AdditionalVolume{Name: "disk-1", Usage: esv1.DataVolumeUsage},
+				esv1.AdditionalVolume{Name: "disk-2", Usage: esv1.LogsVolumeUsage},
+			),
+			wantErr: false,
+		},
+		{
+			name:    "additional logs volume from 8.0.0 is OK",
+			es:      esWithAdditionalVolumes("8.15.0", esv1.AdditionalVolume{Name: "disk-1", Usage: esv1.LogsVolumeUsage}),
+			wantErr: false,
+		},
+		{
+			name:    "additional data volume from 8.0.0 is NOK",
+			es:      esWithAdditionalVolumes("8.0.0", esv1.AdditionalVolume{Name: "disk-1", Usage: esv1.DataVolumeUsage}),
+			wantErr: true,
+		},
+		{
+			name:    "additional volume without a claim is NOK",
+			es:      esWithAdditionalVolumes("7.17.0", esv1.AdditionalVolume{Name: "disk-3", Usage: esv1.DataVolumeUsage}),
+			wantErr: true,
+		},
+		{
+			name:    "default data volume as an additional volume is NOK",
+			es:      esWithAdditionalVolumes("7.17.0", esv1.AdditionalVolume{Name: "elasticsearch-data", Usage: esv1.DataVolumeUsage}),
+			wantErr: true,
+		},
+		{
+			name: "same volume declared twice is NOK",
+			es: esWithAdditionalVolumes("7.17.0",
+				esv1.AdditionalVolume{Name: "disk-1", Usage: esv1.DataVolumeUsage},
+				esv1.AdditionalVolume{Name: "disk-1", Usage: esv1.TempVolumeUsage},
+			),
+			wantErr: true,
+		},
+		{
+			name: "two logs volumes are NOK",
+			es: esWithAdditionalVolumes("8.15.0",
+				esv1.AdditionalVolume{Name: "disk-1", Usage: esv1.LogsVolumeUsage},
+				esv1.AdditionalVolume{Name: "disk-2", Usage: esv1.LogsVolumeUsage},
+			),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validAdditionalVolumes(tt.es)
+			if tt.wantErr {
+				require.NotEmpty(t, got)
+			} else {
+				require.Empty(t, got)
+			}
+		})
+	}
+}
+
+func Test_validJVMDiagnostics(t *testing.T) {
+	claim := func(name string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	esWithJVMDiagnostics := func(diagnostics *esv1.JVMDiagnostics) esv1.Elasticsearch {
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.15.0", NodeSets: []esv1.NodeSet{{
+			Name:                 "default",
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claim("elasticsearch-data"), claim("logs"), claim("dumps")},
+			AdditionalVolumes:    []esv1.AdditionalVolume{{Name: "logs", Usage: esv1.LogsVolumeUsage}},
+			JVMDiagnostics:       diagnostics,
+		}}}}
+	}
+	tests := []struct {
+		name    string
+		es      esv1.Elasticsearch
+		wantErr bool
+	}{
+		{
+			name:    "no JVM diagnostics is OK",
+			es:      esWithJVMDiagnostics(nil),
+			wantErr: false,
+		},
+		{
+			name:    "default emptyDir volume is OK",
+			es:      esWithJVMDiagnostics(&esv1.JVMDiagnostics{}),
+			wantErr: false,
+		},
+		{
+			name:    "dedicated claim is OK",
+			es:      esWithJVMDiagnostics(&esv1.JVMDiagnostics{VolumeClaimTemplate: "dumps"}),
+			wantErr: false,
+		},
+		{
+			name:    "unknown claim is NOK",
+			es:      esWithJVMDiagnostics(&esv1.JVMDiagnostics{VolumeClaimTemplate: "unknown"}),
+			wantErr: true,
+		},
+		{
+			name:    "data volume is NOK",
+			es:      esWithJVMDiagnostics(&esv1.JVMDiagnostics{VolumeClaimTemplate: "elasticsearch-data"}),
+			wantErr: true,
+		},
+		{
+			name:    "additional volume is NOK",
+			es:      esWithJVMDiagnostics(&esv1.JVMDiagnostics{VolumeClaimTemplate: "logs"}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validJVMDiagnostics(tt.es)
+			if tt.wantErr {
+				require.NotEmpty(t, got)
+			} else {
+				require.Empty(t, got)
+			}
+		})
+	}
+}
+
+func Test_validPreUpgradeVolumeSnapshots(t *testing.T) {
+	esWithSnapshotNodeSets := func(snapshots *esv1.PreUpgradeVolumeSnapshots) esv1.Elasticsearch {
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+			NodeSets:                  []esv1.NodeSet{{Name: "master"}, {Name: "data"}},
+			PreUpgradeVolumeSnapshots: snapshots,
+		}}
+	}
+	tests := []struct {
+		name    string
+		es      esv1.Elasticsearch
+		wantErr bool
+	}{
+		{
+			name:    "no pre-upgrade snapshots is OK",
+			es:      esWithSnapshotNodeSets(nil),
+			wantErr: false,
+		},
+		{
+			name:    "all nodeSets is OK",
+			es:      esWithSnapshotNodeSets(&esv1.PreUpgradeVolumeSnapshots{}),
+			wantErr: false,
+		},
+		{
+			name:    "existing nodeSet is OK",
+			es:      esWithSnapshotNodeSets(&esv1.PreUpgradeVolumeSnapshots{NodeSets: []string{"master"}}),
+			wantErr: false,
+		},
+		{
+			name:    "unknown nodeSet is NOK",
+			es:      esWithSnapshotNodeSets(&esv1.PreUpgradeVolumeSnapshots{NodeSets: []string{"master", "masters"}}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validPreUpgradeVolumeSnapshots(tt.es)
+			if tt.wantErr {
+				require.NotEmpty(t, got)
+			} else {
+				require.Empty(t, got)
+			}
+		})
+	}
+}
+
+func Test_noEphemeralStorageChange(t *testing.T) {
+	esWithNodeSet := func(nodeSet esv1.NodeSet) esv1.Elasticsearch {
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{nodeSet}}}
+	}
+	sizeLimit := resource.MustParse("10Gi")
+	tests := []struct {
+		name     string
+		current  esv1.Elasticsearch
+		proposed esv1.Elasticsearch
+		wantErr  bool
+	}{
+		{
+			name:     "no ephemeral storage is OK",
+			current:  esWithNodeSet(esv1.NodeSet{Name: "default"}),
+			proposed: esWithNodeSet(esv1.NodeSet{Name: "default"}),
+			wantErr:  false,
+		},
+		{
+			name:     "ephemeral storage size limit change is OK",
+			current:  esWithNodeSet(esv1.NodeSet{Name: "coord", EphemeralStorage: &esv1.EphemeralStorage{}}),
+			proposed: esWithNodeSet(esv1.NodeSet{Name: "coord", EphemeralStorage: &esv1.EphemeralStorage{SizeLimit: &sizeLimit}}),
+			wantErr:  false,
+		},
+		{
+			name:     "ephemeral storage on a new nodeSet is OK",
+			current:  esWithNodeSet(esv1.NodeSet{Name: "default"}),
+			proposed: esWithNodeSet(esv1.NodeSet{Name: "coord", EphemeralStorage: &esv1.EphemeralStorage{}}),
+			wantErr:  false,
+		},
+		{
+			name:     "enabling ephemeral storage on an existing nodeSet is NOK",
+			current:  esWithNodeSet(esv1.NodeSet{Name: "coord"}),
+			proposed: esWithNodeSet(esv1.NodeSet{Name: "coord", EphemeralStorage: &esv1.EphemeralStorage{}}),
+			wantErr:  true,
+		},
+		{
+			name:     "disabling ephemeral storage on an existing nodeSet is NOK",
+			current:  esWithNodeSet(esv1.NodeSet{Name: "coord", EphemeralStorage: &esv1.EphemeralStorage{}}),
+			proposed: esWithNodeSet(esv1.NodeSet{Name: "coord"}),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := noEphemeralStorageChange(tt.current, tt.proposed)
+			if tt.wantErr {
+				require.NotEmpty(t, got)
+			} else {
+				require.Empty(t, got)
+			}
+		})
+	}
+}
+
+func Test_validFrozenCache(t *testing.T) {
+	esWithNodeSet := func(version string, nodeSet esv1.NodeSet) esv1.Elasticsearch {
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: version, NodeSets: []esv1.NodeSet{nodeSet}}}
+	}
+	frozen := func(frozenCache esv1.FrozenCache) esv1.NodeSet {
+		return esv1.NodeSet{
+			Name:        "frozen",
+			Config:      &commonv1.Config{Data: map[string]interface{}{"node.roles": []string{"data_frozen"}}},
+			FrozenCache: &frozenCache,
+		}
+	}
+	dataClaim := func(storage string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+			Spec: corev1.PersistentVolumeClaimSpec{Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+			}},
+		}
+	}
+	tests := []struct {
+		name    string
+		es      esv1.Elasticsearch
+		wantErr string
+	}{
+		{
+			name: "no frozen cache is OK",
+			es:   esWithNodeSet("8.15.0", esv1.NodeSet{Name: "default"}),
+		},
+		{
+			name: "percentage is OK",
+			es:   esWithNodeSet("8.15.0", frozen(esv1.FrozenCache{Size: "90%"})),
+		},
+		{
+			name: "quantity fitting in the data volume is OK",
+			es: esWithNodeSet("8.15.0", func() esv1.NodeSet {
+				ns := frozen(esv1.FrozenCache{Size: "90Gi"})
+				ns.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{dataClaim("100Gi")}
+				return ns
+			}()),
+		},
+		{
+			name: "persistent cache volume is OK",
+			es:   esWithNodeSet("8.15.0", frozen(esv1.FrozenCache{Size: "90Gi", Volume: &esv1.FrozenCacheVolume{Storage: resource.MustParse("100Gi")}})),
+		},
+		{
+			name: "ephemeral cache volume is OK",
+			es:   esWithNodeSet("8.15.0", frozen(esv1.FrozenCache{Volume: &esv1.FrozenCacheVolume{Storage: resource.MustParse("100Gi"), Ephemeral: true}})),
+		},
+		{
+			name:    "frozen cache before 7.12.0 is NOK",
+			es:      esWithNodeSet("7.11.0", frozen(esv1.FrozenCache{Size: "90%"})),
+			wantErr: frozenCacheVersionErrMsg,
+		},
+		{
+			name: "frozen cache without the data_frozen role is NOK",
+			es: esWithNodeSet("8.15.0", esv1.NodeSet{
+				Name:        "hot",
+				Config:      &commonv1.Config{Data: map[string]interface{}{"node.roles": []string{"data_hot"}}},
+				FrozenCache: &esv1.FrozenCache{Size: "90%"},
+			}),
+			wantErr: frozenCacheRoleErrMsg,
+		},
+		{
+			name:    "invalid percentage is NOK",
+			es:      esWithNodeSet("8.15.0", frozen(esv1.FrozenCache{Size: "120%"})),
+			wantErr: frozenCacheSizeErrMsg,
+		},
+		{
+			name:    "invalid quantity is NOK",
+			es:      esWithNodeSet("8.15.0", frozen(esv1.FrozenCache{Size: "lots"})),
+			wantErr: frozenCacheSizeErrMsg,
+		},
+		{
+			name: "size also set in the configuration is NOK",
+			es: esWithNodeSet("8.15.0", func() esv1.NodeSet {
+				ns := frozen(esv1.FrozenCache{Size: "90%"})
+				ns.Config.Data["xpack.searchable.snapshot.shared_cache.size"] = "50%"
+				return ns
+			}()),
+			wantErr: frozenCacheSizeConfiguredErrMsg,
+		},
+		{
+			name:    "quantity exceeding the cache volume is NOK",
+			es:      esWithNodeSet("8.15.0", frozen(esv1.FrozenCache{Size: "200Gi", Volume: &esv1.FrozenCacheVolume{Storage: resource.MustParse("100Gi")}})),
+			wantErr: "the shared cache size exceeds the size of the data volume (100Gi)",
+		},
+		{
+			name: "quantity exceeding the data volume claim is NOK",
+			es: esWithNodeSet("8.15.0", func() esv1.NodeSet {
+				ns := frozen(esv1.FrozenCache{Size: "200Gi"})
+				ns.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{dataClaim("100Gi")}
+				return ns
+			}()),
+			wantErr: "the shared cache size exceeds the size of the data volume (100Gi)",
+		},
+		{
+			name: "cache volume with a data volume claim is NOK",
+			es: esWithNodeSet("8.15.0", func() esv1.NodeSet {
+				ns := frozen(esv1.FrozenCache{Volume: &esv1.FrozenCacheVolume{Storage: resource.MustParse("100Gi")}})
+				ns.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{dataClaim("100Gi")}
+				return ns
+			}()),
+			wantErr: frozenCacheVolumeWithDataClaimErrMsg,
+		},
+		{
+			name: "cache volume with ephemeral storage is NOK",
+			es: esWithNodeSet("8.15.0", func() esv1.NodeSet {
+				ns := frozen(esv1.FrozenCache{Volume: &esv1.FrozenCacheVolume{Storage: resource.MustParse("100Gi")}})
+				ns.EphemeralStorage = &esv1.EphemeralStorage{}
+				return ns
+			}()),
+			wantErr: frozenCacheVolumeWithEphemeralStorageErrMsg,
+		},
+		{
+			name:    "empty cache volume is NOK",
+			es:      esWithNodeSet("8.15.0", frozen(esv1.FrozenCache{Volume: &esv1.FrozenCacheVolume{}})),
+			wantErr: frozenCacheVolumeStorageErrMsg,
+		},
+		{
+			name: "ephemeral cache volume for master nodes is NOK",
+			es: esWithNodeSet("8.15.0", func() esv1.NodeSet {
+				ns := frozen(esv1.FrozenCache{Volume: &esv1.FrozenCacheVolume{Storage: resource.MustParse("100Gi"), Ephemeral: true}})
+				ns.Config.Data["node.roles"] = []string{"master", "data_frozen"}
+				return ns
+			}()),
+			wantErr: "ephemeral storage is not supported for nodes with the master role",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validFrozenCache(tt.es)
+			if tt.wantErr == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Contains(t, got[0].Error(), tt.wantErr)
+		})
+	}
+}
+
+func Test_noFrozenCacheVolumeChange(t *testing.T) {
+	esWithVolume := func(cacheVolume *esv1.FrozenCacheVolume) esv1.Elasticsearch {
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{
+			{Name: "frozen", FrozenCache: &esv1.FrozenCache{Volume: cacheVolume}},
+		}}}
+	}
+	persistent := func(storage string, storageClass *string) *esv1.FrozenCacheVolume {
+		return &esv1.FrozenCacheVolume{Storage: resource.MustParse(storage), StorageClassName: storageClass}
+	}
+	ephemeral := func(storage string) *esv1.FrozenCacheVolume {
+		return &esv1.FrozenCacheVolume{Storage: resource.MustParse(storage), Ephemeral: true}
+	}
+	tests := []struct {
+		name     string
+		current  esv1.Elasticsearch
+		proposed esv1.Elasticsearch
+		wantErr  bool
+	}{
+		{
+			name:     "no cache volume is OK",
+			current:  esWithVolume(nil),
+			proposed: esWithVolume(nil),
+		},
+		{
+			name:     "persistent storage increase is OK",
+			current:  esWithVolume(persistent("100Gi", nil)),
+			proposed: esWithVolume(persistent("200Gi", nil)),
+		},
+		{
+			name:     "ephemeral storage decrease is OK",
+			current:  esWithVolume(ephemeral("100Gi")),
+			proposed: esWithVolume(ephemeral("50Gi")),
+		},
+		{
+			name:     "cache volume on a new nodeSet is OK",
+			current:  esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{{Name: "default"}}}},
+			proposed: esWithVolume(persistent("100Gi", nil)),
+		},
+		{
+			name:     "adding a cache volume is NOK",
+			current:  esWithVolume(nil),
+			proposed: esWithVolume(persistent("100Gi", nil)),
+			wantErr:  true,
+		},
+		{
+			name:     "moving the cache volume to ephemeral storage is NOK",
+			current:  esWithVolume(persistent("100Gi", nil)),
+			proposed: esWithVolume(ephemeral("100Gi")),
+			wantErr:  true,
+		},
+		{
+			name:     "changing the storage class is NOK",
+			current:  esWithVolume(persistent("100Gi", nil)),
+			proposed: esWithVolume(persistent("100Gi", ptr.To("fast"))),
+			wantErr:  true,
+		},
+		{
+			name:     "persistent storage decrease is NOK",
+			current:  esWithVolume(persistent("100Gi", nil)),
+			proposed: esWithVolume(persistent("50Gi", nil)),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := noFrozenCacheVolumeChange(tt.current, tt.proposed)
+			if tt.wantErr {
+				require.NotEmpty(t, got)
+			} else {
+				require.Empty(t, got)
+			}
+		})
+	}
+}
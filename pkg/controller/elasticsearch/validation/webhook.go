@@ -18,9 +18,9 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/namespaces"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 // +kubebuilder:webhook:path=/validate-elasticsearch-k8s-elastic-co-v1-elasticsearch,mutating=false,failurePolicy=ignore,groups=elasticsearch.k8s.elastic.co,resources=elasticsearches,verbs=create;update,versions=v1,name=elastic-es-validation-v1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
@@ -32,14 +32,14 @@ const (
 var eslog = ulog.Log.WithName("es-validation")
 
 // RegisterWebhook will register the Elasticsearch validating webhook.
-func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, exposedNodeLabels NodeLabels, licenseChecker license.Checker, managedNamespaces []string) {
+func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, exposedNodeLabels NodeLabels, licenseChecker license.Checker, managedNamespaces namespaces.Managed) {
 	wh := &validatingWebhook{
 		client:               mgr.GetClient(),
 		decoder:              admission.NewDecoder(mgr.GetScheme()),
 		validateStorageClass: validateStorageClass,
 		exposedNodeLabels:    exposedNodeLabels,
 		licenseChecker:       licenseChecker,
-		managedNamespaces:    set.Make(managedNamespaces...),
+		managedNamespaces:    managedNamespaces,
 	}
 	eslog.Info("Registering Elasticsearch validating webhook", "path", webhookPath)
 	mgr.GetWebhookServer().Register(webhookPath, &webhook.Admission{Handler: wh})
@@ -51,7 +51,7 @@ type validatingWebhook struct {
 	validateStorageClass bool
 	exposedNodeLabels    NodeLabels
 	licenseChecker       license.Checker
-	managedNamespaces    set.StringSet
+	managedNamespaces    namespaces.Managed
 }
 
 func (wh *validatingWebhook) validateCreate(ctx context.Context, es esv1.Elasticsearch) error {
@@ -85,16 +85,20 @@ func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request)
 
 	// If this Elasticsearch instance is not within the set of managed namespaces
 	// for this operator ignore this request.
-	if wh.managedNamespaces.Count() > 0 && !wh.managedNamespaces.Has(es.Namespace) {
+	if !wh.managedNamespaces.IsManaged(es.Namespace) {
 		eslog.V(1).Info("Skip Elasticsearch resource validation", "name", es.Name, "namespace", es.Namespace)
 		return admission.Allowed("")
 	}
 
+	var explanations []string
 	if req.Operation == admissionv1.Create {
 		err = wh.validateCreate(ctx, *es)
 		if err != nil {
 			return admission.Denied(err.Error())
 		}
+		if es.IsDryRunExplainRequested() {
+			explanations = explainCreate(*es)
+		}
 	}
 
 	if req.Operation == admissionv1.Update {
@@ -108,9 +112,13 @@ func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request)
 		if err != nil {
 			return admission.Denied(err.Error())
 		}
+		if es.IsDryRunExplainRequested() {
+			explanations = explainUpdate(*oldObj, *es)
+		}
 	}
 
-	return admission.Allowed("")
+	// the explanation is returned in the warnings, displayed by kubectl, to review the change before it is applied
+	return admission.Allowed("").WithWarnings(explanations...)
 }
 
 // ValidateElasticsearch validates an Elasticsearch instance against a set of validation funcs.
@@ -16,8 +16,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/namespaces"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 func asJSON(obj interface{}) []byte {
@@ -152,7 +152,7 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 				client:               tt.fields.client,
 				decoder:              decoder,
 				validateStorageClass: tt.fields.validateStorageClass,
-				managedNamespaces:    set.Make("ns"),
+				managedNamespaces:    namespaces.Static{"ns"},
 			}
 			got := wh.Handle(context.Background(), tt.args.req)
 			require.Equal(t, tt.want.Allowed, got.Allowed)
@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+const (
+	zoneAwarenessZoneNameErrMsg           = "must contain at least one alphanumeric character"
+	zoneAwarenessDuplicateZoneErrMsg      = "zone results in the same StatefulSet as zone %s"
+	zoneAwarenessStorageClassMigrationMsg = "the storage class of a zone-aware nodeSet cannot be migrated, the nodeSet must be renamed instead"
+	zoneAwarenessVolumeResizeMigrationMsg = "the volumes of a zone-aware nodeSet cannot be migrated, the nodeSet must be renamed instead"
+)
+
+// validZoneAwareness checks that each zone of the zone-aware nodeSets results in a distinct StatefulSet.
+func validZoneAwareness(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	storageClassMigration := es.StorageClassMigrationNodeSets()
+	volumeResizeMigration := es.VolumeResizeMigrationNodeSets()
+	for i, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.ZoneAwareness == nil {
+			continue
+		}
+		path := field.NewPath("spec").Child("nodeSets").Index(i).Child("zoneAwareness")
+		if storageClassMigration.Has(nodeSet.Name) {
+			errs = append(errs, field.Forbidden(path, zoneAwarenessStorageClassMigrationMsg))
+		}
+		if volumeResizeMigration.Has(nodeSet.Name) {
+			errs = append(errs, field.Forbidden(path, zoneAwarenessVolumeResizeMigrationMsg))
+		}
+		zones := make(map[string]string, len(nodeSet.ZoneAwareness.Zones))
+		for j, zone := range nodeSet.ZoneAwareness.Zones {
+			name := esv1.ZoneNodeSetName(nodeSet.Name, zone)
+			switch previous, exists := zones[name]; {
+			case name == nodeSet.Name+"-":
+				errs = append(errs, field.Invalid(path.Child("zones").Index(j), zone, zoneAwarenessZoneNameErrMsg))
+			case exists:
+				errs = append(errs, field.Invalid(path.Child("zones").Index(j), zone, fmt.Sprintf(zoneAwarenessDuplicateZoneErrMsg, previous)))
+			default:
+				zones[name] = zone
+			}
+		}
+	}
+	return errs
+}
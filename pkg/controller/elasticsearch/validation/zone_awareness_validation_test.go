@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_validZoneAwareness(t *testing.T) {
+	zoneAware := func(zones ...string) esv1.NodeSet {
+		return esv1.NodeSet{Name: "data", Count: 3, ZoneAwareness: &esv1.ZoneAwareness{Zones: zones}}
+	}
+	tests := []struct {
+		name        string
+		es          esv1.Elasticsearch
+		wantErrMsgs []string
+	}{
+		{
+			name: "no zone awareness",
+			es:   esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{{Name: "data"}}}},
+		},
+		{
+			name: "valid zones",
+			es:   esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{zoneAware("us-east-1a", "us-east-1b")}}},
+		},
+		{
+			name:        "zones resulting in the same StatefulSet",
+			es:          esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{zoneAware("zone_a", "Zone-A")}}},
+			wantErrMsgs: []string{"zone results in the same StatefulSet as zone zone_a"},
+		},
+		{
+			name:        "zone without alphanumeric characters",
+			es:          esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{zoneAware("a", "__")}}},
+			wantErrMsgs: []string{zoneAwarenessZoneNameErrMsg},
+		},
+		{
+			name: "storage class migration",
+			es: esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{esv1.StorageClassMigrationAnnotation: "data"}},
+				Spec:       esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{zoneAware("a", "b")}},
+			},
+			wantErrMsgs: []string{zoneAwarenessStorageClassMigrationMsg},
+		},
+		{
+			name: "volume resize migration",
+			es: esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{esv1.VolumeResizeMigrationAnnotation: "data"}},
+				Spec:       esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{zoneAware("a", "b")}},
+			},
+			wantErrMsgs: []string{zoneAwarenessVolumeResizeMigrationMsg},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validZoneAwareness(tt.es)
+			assert.Len(t, errs, len(tt.wantErrMsgs))
+			for i, msg := range tt.wantErrMsgs {
+				assert.Contains(t, errs[i].Error(), msg)
+			}
+		})
+	}
+}
+
+func Test_checkNodeSetNameUniqueness_zoneAwareness(t *testing.T) {
+	es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{
+		{Name: "data", ZoneAwareness: &esv1.ZoneAwareness{Zones: []string{"a", "b"}}},
+		{Name: "data-b"},
+	}}}
+	errs := checkNodeSetNameUniqueness(es)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "data-b")
+}
@@ -5,9 +5,13 @@
 package volume
 
 import (
+	"path"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 )
 
 var (
@@ -61,3 +65,47 @@ func AppendDefaultDataVolumeMount(mounts []corev1.VolumeMount, volumes []corev1.
 	}
 	return mounts
 }
+
+// AdditionalVolumeMountPath returns the path at which the given additional volume is mounted in the Elasticsearch container.
+func AdditionalVolumeMountPath(v esv1.AdditionalVolume) string {
+	switch v.Usage {
+	case esv1.LogsVolumeUsage:
+		return ElasticsearchLogsMountPath
+	case esv1.TempVolumeUsage:
+		return TempVolumeMountPath
+	default:
+		return path.Join(AdditionalDataVolumesMountPath, v.Name)
+	}
+}
+
+// DataPaths returns the data paths of Elasticsearch, including the mount paths of the given additional data volumes.
+func DataPaths(additionalVolumes []esv1.AdditionalVolume) []string {
+	paths := []string{ElasticsearchDataMountPath}
+	for _, v := range additionalVolumes {
+		if v.Usage == esv1.DataVolumeUsage {
+			paths = append(paths, AdditionalVolumeMountPath(v))
+		}
+	}
+	return paths
+}
+
+// KeystorePasswordFile returns the path of the file holding the password of the keystore, read from the given key of
+// the mounted Secret.
+func KeystorePasswordFile(password esv1.SecretKeyRef) string {
+	return path.Join(KeystorePasswordVolumeMountPath, password.Key)
+}
+
+// SAMLMetadataMountPath returns the directory in which the Secret holding the metadata of the identity provider of the
+// given SAML realm is mounted.
+func SAMLMetadataMountPath(realmName string) string {
+	return path.Join(SAMLMetadataVolumesMountPath, realmName)
+}
+
+// SAMLMetadataPath returns the path of the metadata of the identity provider of the given SAML realm, downloaded from
+// its URL or read from the mounted Secret.
+func SAMLMetadataPath(realm esv1.SAMLRealm) string {
+	if realm.IdPMetadata.Secret == nil {
+		return realm.IdPMetadata.URL
+	}
+	return path.Join(SAMLMetadataMountPath(realm.Name), realm.IdPMetadata.Secret.Key)
+}
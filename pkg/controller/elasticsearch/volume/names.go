@@ -23,6 +23,16 @@ const (
 	XPackFileRealmVolumeName      = "elastic-internal-xpack-file-realm"
 	XPackFileRealmVolumeMountPath = "/mnt/elastic-internal/xpack-file-realm"
 
+	// SAMLMetadataVolumeNamePrefix and SAMLMetadataVolumesMountPath are the prefix of the names of the volumes holding
+	// the metadata of the identity providers of the SAML realms, and the directory they are mounted in.
+	SAMLMetadataVolumeNamePrefix = "elastic-internal-saml-"
+	SAMLMetadataVolumesMountPath = "/usr/share/elasticsearch/config/saml"
+
+	// KeystorePasswordVolumeName and KeystorePasswordVolumeMountPath are the name of the volume holding the password
+	// of the keystore, and the directory it is mounted in.
+	KeystorePasswordVolumeName      = "elastic-internal-keystore-password"
+	KeystorePasswordVolumeMountPath = "/mnt/elastic-internal/keystore-password"
+
 	UnicastHostsVolumeName      = "elastic-internal-unicast-hosts"
 	UnicastHostsVolumeMountPath = "/mnt/elastic-internal/unicast-hosts"
 	UnicastHostsFile            = "unicast_hosts.txt"
@@ -30,9 +40,17 @@ const (
 	ElasticsearchDataVolumeName = "elasticsearch-data"
 	ElasticsearchDataMountPath  = "/usr/share/elasticsearch/data"
 
+	// AdditionalDataVolumesMountPath is the directory in which the additional data volumes of a nodeSet are mounted.
+	AdditionalDataVolumesMountPath = "/usr/share/elasticsearch/data-volumes"
+
 	ElasticsearchLogsVolumeName = "elasticsearch-logs"
 	ElasticsearchLogsMountPath  = "/usr/share/elasticsearch/logs"
 
+	// JVMDiagnosticsVolumeName and JVMDiagnosticsMountPath are the name of the default volume storing the heap dumps
+	// and GC logs of the JVM, and the directory the diagnostics volume is mounted in.
+	JVMDiagnosticsVolumeName = "jvm-diagnostics"
+	JVMDiagnosticsMountPath  = "/usr/share/elasticsearch/jvm-diagnostics"
+
 	ScriptsVolumeName      = "elastic-internal-scripts"
 	ScriptsVolumeMountPath = "/mnt/elastic-internal/scripts"
 
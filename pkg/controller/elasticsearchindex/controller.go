@@ -0,0 +1,119 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchindex
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	indexv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/index/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	controllerName = "elasticsearchindex-controller"
+
+	// IndexFinalizer lets the operator apply the deletion policy of the index before the ElasticsearchIndex resource is deleted.
+	IndexFinalizer = "index.k8s.elastic.co/deletion-policy"
+)
+
+// config identifies the ElasticsearchIndex controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "ElasticsearchIndex",
+	NameField:      "index_name",
+	Finalizer:      IndexFinalizer,
+}
+
+// Add creates a new ElasticsearchIndex Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Elasticsearch and reconcile the ElasticsearchIndex resources referencing them
+		source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestForIndices(r.Client)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of ElasticsearchIndex.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*indexv1alpha1.ElasticsearchIndex, indexv1alpha1.ElasticsearchIndexStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &indexKind{
+		Client:           c,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+// reconcileRequestForIndices returns the requests to reconcile the ElasticsearchIndex resources, in the namespace of the
+// watched Elasticsearch cluster, referencing it.
+func reconcileRequestForIndices(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &indexv1alpha1.ElasticsearchIndexList{}, func(index *indexv1alpha1.ElasticsearchIndex, obj client.Object) bool {
+		return index.References(k8s.ExtractNamespacedName(obj))
+	})
+}
+
+// indexKind creates ElasticsearchIndex resources in Elasticsearch.
+type indexKind struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*indexv1alpha1.ElasticsearchIndex, indexv1alpha1.ElasticsearchIndexStatus] = &indexKind{}
+	_ apiresource.Remover[*indexv1alpha1.ElasticsearchIndex]                                      = &indexKind{}
+)
+
+func (r *indexKind) NewObject() *indexv1alpha1.ElasticsearchIndex {
+	return &indexv1alpha1.ElasticsearchIndex{}
+}
+
+func (r *indexKind) GetStatus(index *indexv1alpha1.ElasticsearchIndex) indexv1alpha1.ElasticsearchIndexStatus {
+	return index.Status
+}
+
+func (r *indexKind) SetStatus(index *indexv1alpha1.ElasticsearchIndex, status indexv1alpha1.ElasticsearchIndexStatus) {
+	index.Status = status
+}
+
+func (r *indexKind) InvalidStatus(index *indexv1alpha1.ElasticsearchIndex, _ error) indexv1alpha1.ElasticsearchIndexStatus {
+	return indexv1alpha1.ElasticsearchIndexStatus{
+		Phase:              indexv1alpha1.InvalidPhase,
+		ObservedGeneration: index.Generation,
+	}
+}
+
+// Configure creates the index on the referenced Elasticsearch cluster and updates its settings and mappings.
+func (r *indexKind) Configure(ctx context.Context, obj *indexv1alpha1.ElasticsearchIndex) (*reconciler.Results, indexv1alpha1.ElasticsearchIndexStatus) {
+	index := *obj
+	results := reconciler.NewResult(ctx)
+
+	status := r.reconcileIndex(ctx, index)
+	status.IndexName = index.IndexNameOrDefault()
+	status.ObservedGeneration = index.Generation
+
+	results.WithResult(apiresource.Requeue(status.Phase == indexv1alpha1.ReadyPhase))
+
+	return results, status
+}
+
+// Remove applies the deletion policy of the index.
+func (r *indexKind) Remove(ctx context.Context, obj *indexv1alpha1.ElasticsearchIndex) (reconcile.Result, error) {
+	return reconcile.Result{}, r.applyDeletionPolicy(ctx, *obj)
+}
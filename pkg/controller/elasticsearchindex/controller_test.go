@@ -0,0 +1,405 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchindex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	indexv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/index/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeEsClient stores indices and data streams in memory, adding default settings and dynamically mapped fields to
+// indices like Elasticsearch.
+type fakeEsClient struct {
+	esclient.Client
+
+	indices map[string]*esclient.Index
+	// dataStreams are the backing indices of the data streams
+	dataStreams map[string][]string
+	// dataStreamAliases are the aliases of the data streams
+	dataStreamAliases map[string]map[string]interface{}
+	// templates are the data streams names matching an index template
+	templates map[string]bool
+	updates   map[string]int
+}
+
+func newFakeEsClient() fakeEsClient {
+	return fakeEsClient{
+		indices:           map[string]*esclient.Index{},
+		dataStreams:       map[string][]string{},
+		dataStreamAliases: map[string]map[string]interface{}{},
+		templates:         map[string]bool{},
+		updates:           map[string]int{},
+	}
+}
+
+func notFound() error {
+	return &esclient.APIError{StatusCode: http.StatusNotFound}
+}
+
+func badRequest(reason string) error {
+	apiErr := &esclient.APIError{StatusCode: http.StatusBadRequest}
+	apiErr.ErrorResponse.Error.Reason = reason
+	return apiErr
+}
+
+func (c fakeEsClient) GetIndex(_ context.Context, name string) (map[string]esclient.Index, error) {
+	names := []string{name}
+	if backingIndices, exists := c.dataStreams[name]; exists {
+		names = backingIndices
+	}
+	indices := map[string]esclient.Index{}
+	for _, n := range names {
+		index, exists := c.indices[n]
+		if !exists {
+			return nil, notFound()
+		}
+		indices[n] = *index
+	}
+	return indices, nil
+}
+
+func (c fakeEsClient) GetAliases(_ context.Context, name string) (map[string]interface{}, error) {
+	if aliases, exists := c.dataStreamAliases[name]; exists {
+		return aliases, nil
+	}
+	index, exists := c.indices[name]
+	if !exists {
+		return nil, notFound()
+	}
+	return index.Aliases, nil
+}
+
+func (c fakeEsClient) CreateIndex(_ context.Context, name string, body map[string]interface{}) error {
+	spec := indexv1alpha1.ElasticsearchIndex{}
+	if settings, ok := body["settings"].(map[string]interface{}); ok {
+		spec.Spec.Settings = &commonv1.Config{Data: settings}
+	}
+	settings := map[string]interface{}{"index.number_of_shards": "1", "index.number_of_replicas": "1", "index.uuid": "x1Y2z3"}
+	for k, v := range spec.FlatSettings() {
+		settings[k] = v
+	}
+	mappings, _ := body["mappings"].(map[string]interface{})
+	c.indices[name] = &esclient.Index{Aliases: map[string]interface{}{}, Mappings: merge(map[string]interface{}{}, mappings), Settings: settings}
+	return nil
+}
+
+func (c fakeEsClient) CreateDataStream(ctx context.Context, name string) error {
+	if !c.templates[name] {
+		return badRequest(fmt.Sprintf("no matching index template found for data stream [%s]", name))
+	}
+	backingIndex := ".ds-" + name + "-2026.10.15-000001"
+	c.dataStreams[name] = []string{backingIndex}
+	c.dataStreamAliases[name] = map[string]interface{}{}
+	return c.CreateIndex(ctx, backingIndex, map[string]interface{}{
+		"mappings": map[string]interface{}{"properties": map[string]interface{}{"@timestamp": map[string]interface{}{"type": "date"}}},
+	})
+}
+
+func (c fakeEsClient) UpdateIndexSettings(_ context.Context, name string, settings map[string]interface{}) error {
+	indices, err := c.GetIndex(context.Background(), name)
+	if err != nil {
+		return err
+	}
+	for key := range settings {
+		if indexv1alpha1.IsStaticSetting(key) {
+			return badRequest(fmt.Sprintf("Can't update non dynamic settings [[%s]] for open indices", key))
+		}
+	}
+	c.updates["settings"]++
+	for n := range indices {
+		for k, v := range settings {
+			c.indices[n].Settings[k] = v
+		}
+	}
+	return nil
+}
+
+func (c fakeEsClient) UpdateIndexMappings(_ context.Context, name string, mappings map[string]interface{}) error {
+	indices, err := c.GetIndex(context.Background(), name)
+	if err != nil {
+		return err
+	}
+	c.updates["mappings"]++
+	for n := range indices {
+		c.indices[n].Mappings = merge(c.indices[n].Mappings, mappings)
+	}
+	return nil
+}
+
+func (c fakeEsClient) UpdateAliases(_ context.Context, actions []esclient.AliasAction) error {
+	c.updates["aliases"]++
+	for _, action := range actions {
+		params := action.Add
+		if params == nil {
+			params = action.Remove
+		}
+		aliases, err := c.GetAliases(context.Background(), params.Index)
+		if err != nil {
+			return err
+		}
+		if action.Remove != nil {
+			delete(aliases, params.Alias)
+			continue
+		}
+		alias := map[string]interface{}{}
+		if params.Filter != nil {
+			alias["filter"] = params.Filter
+		}
+		if params.Routing != "" {
+			alias["index_routing"] = params.Routing
+			alias["search_routing"] = params.Routing
+		}
+		if params.IsWriteIndex != nil {
+			alias["is_write_index"] = *params.IsWriteIndex
+		}
+		aliases[params.Alias] = alias
+	}
+	return nil
+}
+
+func (c fakeEsClient) DeleteIndex(_ context.Context, name string) error {
+	if _, exists := c.indices[name]; !exists {
+		return notFound()
+	}
+	delete(c.indices, name)
+	return nil
+}
+
+func (c fakeEsClient) DeleteDataStream(_ context.Context, name string) error {
+	backingIndices, exists := c.dataStreams[name]
+	if !exists {
+		return notFound()
+	}
+	for _, n := range backingIndices {
+		delete(c.indices, n)
+	}
+	delete(c.dataStreams, name)
+	delete(c.dataStreamAliases, name)
+	return nil
+}
+
+func (c fakeEsClient) Close() {}
+
+// merge merges the source mappings into the destination mappings and returns the destination.
+func merge(dst, src map[string]interface{}) map[string]interface{} {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			dst[k] = merge(dstMap, srcMap)
+			continue
+		}
+		if srcIsMap {
+			dst[k] = merge(map[string]interface{}{}, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+func fakeClientProvider(esClient esclient.Client) commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return esClient, nil
+	}
+}
+
+func ordersMappings(fields ...string) map[string]interface{} {
+	properties := map[string]interface{}{"price": map[string]interface{}{"type": "float"}}
+	for _, f := range fields {
+		properties[f] = map[string]interface{}{"type": "keyword"}
+	}
+	return map[string]interface{}{"dynamic": false, "properties": properties}
+}
+
+func TestReconcileElasticsearchIndex_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	index := &indexv1alpha1.ElasticsearchIndex{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "orders", Generation: 1},
+		Spec: indexv1alpha1.ElasticsearchIndexSpec{
+			ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es"},
+			Settings:         &commonv1.Config{Data: map[string]interface{}{"number_of_shards": 3, "index": map[string]interface{}{"number_of_replicas": 1}}},
+			Mappings:         &commonv1.Config{Data: ordersMappings()},
+			Aliases:          []indexv1alpha1.IndexAlias{{Name: "orders-current", IsWriteIndex: ptr.To(true)}},
+		},
+	}
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	esClient := newFakeEsClient()
+	k8sClient := k8s.NewFakeClient(index, es)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &indexKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(esClient),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "orders"}}
+	getIndex := func() indexv1alpha1.ElasticsearchIndex {
+		var actual indexv1alpha1.ElasticsearchIndex
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+	updateSpec := func(update func(*indexv1alpha1.ElasticsearchIndex)) {
+		actual := getIndex()
+		update(&actual)
+		actual.Generation++
+		require.NoError(t, k8sClient.Update(ctx, &actual))
+	}
+
+	// the index is created with its settings, mappings and aliases
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	actual := getIndex()
+	require.Equal(t, indexv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, "orders", actual.Status.IndexName)
+	require.Equal(t, []string{IndexFinalizer}, actual.Finalizers)
+	require.Equal(t, []string{"orders-current"}, actual.Status.Aliases)
+	require.Equal(t, "3", esClient.indices["orders"].Settings["index.number_of_shards"])
+	require.Equal(t, "1", esClient.indices["orders"].Settings["index.number_of_replicas"])
+	require.Equal(t, map[string]interface{}{"is_write_index": true}, esClient.indices["orders"].Aliases["orders-current"])
+
+	// nothing is updated when the index returned by Elasticsearch matches the specification
+	esClient.indices["orders"].Mappings["dynamic"] = "false"
+	esClient.indices["orders"].Aliases["orders-manual"] = map[string]interface{}{}
+	clear(esClient.updates)
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, esClient.updates)
+	require.Empty(t, recorder.Events)
+
+	// the index is restored if it is modified in Elasticsearch
+	esClient.indices["orders"].Settings["index.number_of_replicas"] = "0"
+	delete(esClient.indices["orders"].Aliases, "orders-current")
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "1", esClient.indices["orders"].Settings["index.number_of_replicas"])
+	require.Contains(t, esClient.indices["orders"].Aliases, "orders-current")
+	require.NotNil(t, getIndex().Status.LastDriftTime)
+	require.Contains(t, <-recorder.Events, "Index orders was modified in Elasticsearch es")
+
+	// dynamic settings, new fields and aliases are applied, the removed aliases are removed, which is not a drift
+	updateSpec(func(i *indexv1alpha1.ElasticsearchIndex) {
+		i.Spec.Settings.Data["index"] = map[string]interface{}{"number_of_replicas": 2, "refresh_interval": "30s"}
+		i.Spec.Mappings = &commonv1.Config{Data: ordersMappings("customer_id")}
+		i.Spec.Aliases = []indexv1alpha1.IndexAlias{{Name: "orders-eu", Filter: &commonv1.Config{Data: map[string]interface{}{"term": map[string]interface{}{"region": "eu"}}}}}
+	})
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	actual = getIndex()
+	require.Equal(t, indexv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, []string{"orders-eu"}, actual.Status.Aliases)
+	require.Equal(t, "2", esClient.indices["orders"].Settings["index.number_of_replicas"])
+	require.Equal(t, "30s", esClient.indices["orders"].Settings["index.refresh_interval"])
+	require.Contains(t, esClient.indices["orders"].Mappings["properties"], "customer_id")
+	require.NotContains(t, esClient.indices["orders"].Aliases, "orders-current")
+	require.Contains(t, esClient.indices["orders"].Aliases, "orders-eu")
+	require.Contains(t, esClient.indices["orders"].Aliases, "orders-manual")
+	require.Empty(t, recorder.Events)
+
+	// static settings are not changed on the existing index
+	updateSpec(func(i *indexv1alpha1.ElasticsearchIndex) { i.Spec.Settings.Data["number_of_shards"] = 6 })
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	actual = getIndex()
+	require.Equal(t, indexv1alpha1.ErrorPhase, actual.Status.Phase)
+	require.Equal(t, "Failed to configure index orders on Elasticsearch es: static settings index.number_of_shards cannot be changed once the index is created", actual.Status.Message)
+	require.Equal(t, "3", esClient.indices["orders"].Settings["index.number_of_shards"])
+	require.Contains(t, <-recorder.Events, "Failed to configure index orders")
+	require.Contains(t, <-recorder.Events, "ElasticsearchIndex health degraded")
+
+	// the index is retained in Elasticsearch by default when the ElasticsearchIndex is deleted
+	actual = getIndex()
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Contains(t, esClient.indices, "orders")
+	err = k8sClient.Get(ctx, request.NamespacedName, &indexv1alpha1.ElasticsearchIndex{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileElasticsearchIndex_DataStream(t *testing.T) {
+	ctx := context.Background()
+	index := &indexv1alpha1.ElasticsearchIndex{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "app-logs", Generation: 1},
+		Spec: indexv1alpha1.ElasticsearchIndexSpec{
+			ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es"},
+			IndexName:        "logs-app-default",
+			Type:             indexv1alpha1.DataStreamIndexType,
+			Settings:         &commonv1.Config{Data: map[string]interface{}{"number_of_replicas": 0}},
+			Aliases:          []indexv1alpha1.IndexAlias{{Name: "logs-app"}},
+			DeletionPolicy:   indexv1alpha1.DeleteDeletionPolicy,
+		},
+	}
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	esClient := newFakeEsClient()
+	k8sClient := k8s.NewFakeClient(index, es)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &indexKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(esClient),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "app-logs"}}
+	getIndex := func() indexv1alpha1.ElasticsearchIndex {
+		var actual indexv1alpha1.ElasticsearchIndex
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+
+	// the data stream cannot be created without a matching index template
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	actual := getIndex()
+	require.Equal(t, indexv1alpha1.ErrorPhase, actual.Status.Phase)
+	require.Equal(t, "Failed to configure index logs-app-default on Elasticsearch es: no matching index template found for data stream [logs-app-default]", actual.Status.Message)
+
+	// the data stream is created from its index template, the settings are applied to its backing index
+	esClient.templates["logs-app-default"] = true
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	actual = getIndex()
+	require.Equal(t, indexv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, "logs-app-default", actual.Status.IndexName)
+	backingIndex := esClient.dataStreams["logs-app-default"][0]
+	require.Equal(t, "0", esClient.indices[backingIndex].Settings["index.number_of_replicas"])
+	require.Contains(t, esClient.dataStreamAliases["logs-app-default"], "logs-app")
+
+	// the data stream is deleted with the ElasticsearchIndex
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, esClient.dataStreams)
+	require.Empty(t, esClient.indices)
+	err = k8sClient.Get(ctx, request.NamespacedName, &indexv1alpha1.ElasticsearchIndex{})
+	require.True(t, apierrors.IsNotFound(err))
+}
@@ -0,0 +1,310 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchindex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	indexv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/index/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+// reconcileIndex creates the index or data stream in Elasticsearch if it does not exist, updates its dynamic settings,
+// adds the missing fields to its mappings and reconciles its aliases, and returns the status of the ElasticsearchIndex.
+// An existing index is never deleted or recreated to apply a change.
+func (r *indexKind) reconcileIndex(ctx context.Context, index indexv1alpha1.ElasticsearchIndex) indexv1alpha1.ElasticsearchIndexStatus {
+	defer tracing.Span(&ctx)()
+	esName := index.Spec.ElasticsearchRef.Name
+	name := index.IndexNameOrDefault()
+	log := ulog.FromContext(ctx).WithValues("es_name", esName, "index", name)
+
+	// keep track of the aliases to remove until they are removed
+	status := indexv1alpha1.ElasticsearchIndexStatus{
+		Phase:         indexv1alpha1.ReadyPhase,
+		Aliases:       index.Status.Aliases,
+		LastDriftTime: index.Status.LastDriftTime,
+	}
+
+	es, phase, msg := apiresource.ReadyElasticsearch(ctx, r.Client, types.NamespacedName{Namespace: index.Namespace, Name: esName})
+	if phase != indexv1alpha1.ReadyPhase {
+		return withPhase(status, phase, msg)
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return withPhase(status, indexv1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	defer esClient.Close()
+
+	// the index was configured from the current specification, any difference was made outside of the operator
+	configured := index.Status.Phase == indexv1alpha1.ReadyPhase && index.Status.ObservedGeneration == index.Generation
+
+	changed, err := reconcileDefinition(ctx, esClient, index)
+	if err == nil {
+		var aliasesChanged bool
+		aliasesChanged, err = reconcileAliases(ctx, esClient, index)
+		changed = changed || aliasesChanged
+	}
+	if err != nil {
+		var staticErr *staticSettingsError
+		if esclient.IsUnavailable(err) && !errors.As(err, &staticErr) {
+			return withPhase(status, indexv1alpha1.ApplyingChangesPhase, err.Error())
+		}
+		msg := fmt.Sprintf("Failed to configure index %s on Elasticsearch %s: %s", name, esName, esclient.ErrorReason(err))
+		r.recorder.Event(&index, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return withPhase(status, indexv1alpha1.ErrorPhase, msg)
+	}
+	status.Aliases = aliasNames(index)
+
+	if configured && changed {
+		msg := fmt.Sprintf("Index %s was modified in Elasticsearch %s, restoring it from the ElasticsearchIndex specification", name, esName)
+		status.LastDriftTime = apiresource.RecordDrift(log, r.recorder, &index, msg)
+	}
+	return status
+}
+
+// reconcileDefinition creates the index or the data stream if it does not exist, then updates the dynamic settings
+// and the mappings that differ from the specification. It returns true if the index was changed.
+func reconcileDefinition(ctx context.Context, esClient esclient.Client, index indexv1alpha1.ElasticsearchIndex) (bool, error) {
+	log := ulog.FromContext(ctx)
+	name := index.IndexNameOrDefault()
+	indices, err := esClient.GetIndex(ctx, name)
+	if err != nil && !esclient.IsNotFound(err) {
+		return false, err
+	}
+	created := false
+	if esclient.IsNotFound(err) {
+		if err := createIndex(ctx, esClient, index); err != nil {
+			return false, err
+		}
+		created = true
+		// the backing indices of a data stream are created from its index template
+		if indices, err = esClient.GetIndex(ctx, name); err != nil {
+			return created, err
+		}
+	}
+
+	expectedSettings := index.FlatSettings()
+	settingsUpdate := map[string]interface{}{}
+	var staticSettings []string
+	updateMappings := false
+	for _, actual := range indices {
+		for key, value := range expectedSettings {
+			if value == fmt.Sprintf("%v", actual.Settings[key]) {
+				continue
+			}
+			if indexv1alpha1.IsStaticSetting(key) {
+				staticSettings = append(staticSettings, key)
+				continue
+			}
+			settingsUpdate[key] = value
+		}
+		if index.Spec.Mappings != nil && !contains(index.Spec.Mappings.Data, actual.Mappings) {
+			updateMappings = true
+		}
+	}
+
+	if len(staticSettings) > 0 {
+		return created, &staticSettingsError{settings: set.Make(staticSettings...).AsSortedSlice()}
+	}
+	if len(settingsUpdate) > 0 {
+		log.Info("Updating index settings")
+		if err := esClient.UpdateIndexSettings(ctx, name, settingsUpdate); err != nil {
+			return created, err
+		}
+	}
+	if updateMappings {
+		// Elasticsearch merges the mappings, fields can be added but not removed or changed
+		log.Info("Updating index mappings")
+		if err := esClient.UpdateIndexMappings(ctx, name, index.Spec.Mappings.Data); err != nil {
+			return created, err
+		}
+	}
+	return created || len(settingsUpdate) > 0 || updateMappings, nil
+}
+
+// staticSettingsError is returned when static settings of the specification differ from the settings of the existing
+// index. Static settings can only be set when the index is created, the index must be reindexed to change them.
+type staticSettingsError struct {
+	settings []string
+}
+
+func (e *staticSettingsError) Error() string {
+	return fmt.Sprintf("static settings %s cannot be changed once the index is created", strings.Join(e.settings, ", "))
+}
+
+// createIndex creates the index with its settings and mappings, or the data stream from its index template.
+func createIndex(ctx context.Context, esClient esclient.Client, index indexv1alpha1.ElasticsearchIndex) error {
+	name := index.IndexNameOrDefault()
+	if index.TypeOrDefault() == indexv1alpha1.DataStreamIndexType {
+		ulog.FromContext(ctx).Info("Creating data stream")
+		return esClient.CreateDataStream(ctx, name)
+	}
+	body := map[string]interface{}{}
+	if index.Spec.Settings != nil {
+		body["settings"] = index.Spec.Settings.Data
+	}
+	if index.Spec.Mappings != nil {
+		body["mappings"] = index.Spec.Mappings.Data
+	}
+	ulog.FromContext(ctx).Info("Creating index")
+	return esClient.CreateIndex(ctx, name, body)
+}
+
+// reconcileAliases adds the aliases of the specification missing or different in Elasticsearch, and removes the aliases
+// previously configured by the operator that are no longer in the specification. It returns true if the aliases were
+// changed.
+func reconcileAliases(ctx context.Context, esClient esclient.Client, index indexv1alpha1.ElasticsearchIndex) (bool, error) {
+	name := index.IndexNameOrDefault()
+	actual, err := esClient.GetAliases(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	var actions []esclient.AliasAction
+	for _, alias := range index.Spec.Aliases {
+		if actualAlias, exists := actual[alias.Name]; exists && aliasMatches(alias, actualAlias) {
+			continue
+		}
+		params := &esclient.AliasActionParams{Index: name, Alias: alias.Name, Routing: alias.Routing, IsWriteIndex: alias.IsWriteIndex}
+		if alias.Filter != nil {
+			params.Filter = alias.Filter.Data
+		}
+		actions = append(actions, esclient.AliasAction{Add: params})
+	}
+	expected := set.Make(aliasNames(index)...)
+	for _, alias := range index.Status.Aliases {
+		if _, exists := actual[alias]; exists && !expected.Has(alias) {
+			actions = append(actions, esclient.AliasAction{Remove: &esclient.AliasActionParams{Index: name, Alias: alias}})
+		}
+	}
+
+	if len(actions) == 0 {
+		return false, nil
+	}
+	ulog.FromContext(ctx).Info("Updating index aliases")
+	return true, esClient.UpdateAliases(ctx, actions)
+}
+
+// aliasMatches returns true if the alias returned by Elasticsearch matches its specification.
+func aliasMatches(alias indexv1alpha1.IndexAlias, actual interface{}) bool {
+	actualAlias, _ := actual.(map[string]interface{})
+	switch {
+	case alias.Filter == nil && actualAlias["filter"] != nil:
+		return false
+	case alias.Filter != nil && !contains(alias.Filter.Data, actualAlias["filter"]):
+		return false
+	}
+	// the routing is returned as both the index and the search routing
+	for _, key := range []string{"index_routing", "search_routing"} {
+		routing, _ := actualAlias[key].(string)
+		if routing != alias.Routing {
+			return false
+		}
+	}
+	if alias.IsWriteIndex != nil {
+		isWriteIndex, _ := actualAlias["is_write_index"].(bool)
+		return isWriteIndex == *alias.IsWriteIndex
+	}
+	return true
+}
+
+// aliasNames returns the sorted names of the aliases of the specification.
+func aliasNames(index indexv1alpha1.ElasticsearchIndex) []string {
+	var names []string
+	for _, alias := range index.Spec.Aliases {
+		names = append(names, alias.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyDeletionPolicy deletes the index or data stream, and all their documents, from the referenced Elasticsearch
+// cluster if the deletion policy of the ElasticsearchIndex is Delete. They are retained otherwise.
+func (r *indexKind) applyDeletionPolicy(ctx context.Context, index indexv1alpha1.ElasticsearchIndex) error {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx).WithValues("es_name", index.Spec.ElasticsearchRef.Name, "index", index.IndexNameOrDefault())
+	if index.DeletionPolicyOrDefault() == indexv1alpha1.RetainDeletionPolicy {
+		log.Info("Retaining index in Elasticsearch")
+		return nil
+	}
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: index.Namespace, Name: index.Spec.ElasticsearchRef.Name}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+
+	log.Info("Deleting index from Elasticsearch")
+	if index.TypeOrDefault() == indexv1alpha1.DataStreamIndexType {
+		err = esClient.DeleteDataStream(ctx, index.IndexNameOrDefault())
+	} else {
+		err = esClient.DeleteIndex(ctx, index.IndexNameOrDefault())
+	}
+	if err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// contains returns true if the expected value is a subset of the actual value returned by Elasticsearch, which adds
+// defaults and the fields added by dynamic mapping.
+func contains(expected, actual interface{}) bool {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualMap, ok := actual.(map[string]interface{})
+		if !ok {
+			return len(expectedValue) == 0 && actual == nil
+		}
+		for k, v := range expectedValue {
+			if !contains(v, actualMap[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		actualSlice, ok := actual.([]interface{})
+		if !ok || len(actualSlice) != len(expectedValue) {
+			return len(expectedValue) == 0 && actual == nil
+		}
+		for i := range expectedValue {
+			if !contains(expectedValue[i], actualSlice[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		// Elasticsearch returns some mapping parameters as strings, for example "dynamic": "false"
+		return fmt.Sprintf("%v", expected) == fmt.Sprintf("%v", actual)
+	}
+}
+
+func withPhase(status indexv1alpha1.ElasticsearchIndexStatus, phase indexv1alpha1.Phase, msg string) indexv1alpha1.ElasticsearchIndexStatus {
+	status.Phase = phase
+	status.Message = msg
+	return status
+}
@@ -0,0 +1,139 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchrestore
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	controllerName = "elasticsearchrestore-controller"
+)
+
+// progressRequeue is used to check whether a restore in progress completed.
+var progressRequeue = reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+
+// config identifies the ElasticsearchRestore controller. A restore in progress is not cancelled when its resource is
+// deleted.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "ElasticsearchRestore",
+	NameField:      "restore_name",
+}
+
+// Add creates a new ElasticsearchRestore Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Elasticsearch and reconcile the ElasticsearchRestore resources targeting them
+		source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestForRestores(r.Client, func(restore *snapshotv1alpha1.ElasticsearchRestore, obj client.Object) bool {
+			return restore.References(k8s.ExtractNamespacedName(obj))
+		})),
+		// watch for changes to SnapshotRepository and reconcile the ElasticsearchRestore resources restoring from them
+		source.Kind[client.Object](mgr.GetCache(), &snapshotv1alpha1.SnapshotRepository{}, reconcileRequestForRestores(r.Client, func(restore *snapshotv1alpha1.ElasticsearchRestore, obj client.Object) bool {
+			return restore.Spec.RepositoryRef.Name == obj.GetName()
+		})),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of ElasticsearchRestore.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*snapshotv1alpha1.ElasticsearchRestore, snapshotv1alpha1.ElasticsearchRestoreStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &restoreKind{
+		Client:           c,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+// reconcileRequestForRestores returns the requests to reconcile the ElasticsearchRestore resources not done yet, in
+// the namespace of the watched object, that match the given predicate.
+func reconcileRequestForRestores(
+	clnt k8s.Client,
+	matches func(restore *snapshotv1alpha1.ElasticsearchRestore, obj client.Object) bool,
+) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &snapshotv1alpha1.ElasticsearchRestoreList{}, func(restore *snapshotv1alpha1.ElasticsearchRestore, obj client.Object) bool {
+		return !restore.Status.IsDone() && matches(restore, obj)
+	})
+}
+
+// restoreKind restores ElasticsearchRestore resources in Elasticsearch.
+type restoreKind struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*snapshotv1alpha1.ElasticsearchRestore, snapshotv1alpha1.ElasticsearchRestoreStatus] = &restoreKind{}
+	_ apiresource.Completer[*snapshotv1alpha1.ElasticsearchRestore]                                         = &restoreKind{}
+)
+
+func (r *restoreKind) NewObject() *snapshotv1alpha1.ElasticsearchRestore {
+	return &snapshotv1alpha1.ElasticsearchRestore{}
+}
+
+func (r *restoreKind) GetStatus(restore *snapshotv1alpha1.ElasticsearchRestore) snapshotv1alpha1.ElasticsearchRestoreStatus {
+	return restore.Status
+}
+
+func (r *restoreKind) SetStatus(restore *snapshotv1alpha1.ElasticsearchRestore, status snapshotv1alpha1.ElasticsearchRestoreStatus) {
+	restore.Status = status
+}
+
+func (r *restoreKind) InvalidStatus(restore *snapshotv1alpha1.ElasticsearchRestore, err error) snapshotv1alpha1.ElasticsearchRestoreStatus {
+	status := restore.Status
+	status.ObservedGeneration = restore.Generation
+	status.Phase = snapshotv1alpha1.RestoreInvalidPhase
+	status.Message = err.Error()
+	return status
+}
+
+// IsCompleted returns true once the restore is done, a restore is only run once.
+func (r *restoreKind) IsCompleted(restore *snapshotv1alpha1.ElasticsearchRestore) bool {
+	return restore.Status.IsDone()
+}
+
+// Configure starts the restore in the target Elasticsearch cluster, or checks the progress of the restore started in a
+// previous reconciliation.
+func (r *restoreKind) Configure(ctx context.Context, obj *snapshotv1alpha1.ElasticsearchRestore) (*reconciler.Results, snapshotv1alpha1.ElasticsearchRestoreStatus) {
+	restore := *obj
+	results := reconciler.NewResult(ctx)
+	status := restore.Status
+	status.ObservedGeneration = restore.Generation
+
+	if status.Phase == snapshotv1alpha1.RestoreInProgressPhase {
+		status = r.checkProgress(ctx, restore, status)
+	} else {
+		status = r.start(ctx, restore, status)
+	}
+
+	switch status.Phase {
+	case snapshotv1alpha1.RestoreInProgressPhase:
+		results.WithResult(progressRequeue)
+	case snapshotv1alpha1.RestorePendingPhase:
+		results.WithResult(apiresource.DefaultRequeue)
+	}
+	return results, status
+}
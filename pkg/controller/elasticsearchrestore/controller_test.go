@@ -0,0 +1,226 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchrestore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeElasticsearch holds the snapshots and restores of a cluster.
+type fakeElasticsearch struct {
+	snapshots  []esclient.Snapshot
+	inProgress []esclient.SnapshotRestoreInProgress
+	restoreErr error
+	restores   []esclient.SnapshotRestoreRequest
+}
+
+type fakeEsClient struct {
+	esclient.Client
+	cluster *fakeElasticsearch
+}
+
+func (c fakeEsClient) GetSnapshots(_ context.Context, repository, _ string) ([]esclient.Snapshot, error) {
+	if repository != "my_repository" {
+		return nil, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return c.cluster.snapshots, nil
+}
+
+func (c fakeEsClient) RestoreSnapshot(_ context.Context, repository, snapshot string, request esclient.SnapshotRestoreRequest) error {
+	if c.cluster.restoreErr != nil {
+		return c.cluster.restoreErr
+	}
+	c.cluster.restores = append(c.cluster.restores, request)
+	c.cluster.inProgress = append(c.cluster.inProgress, esclient.SnapshotRestoreInProgress{Snapshot: snapshot, Repository: repository, State: "STARTED"})
+	return nil
+}
+
+func (c fakeEsClient) GetSnapshotRestoresInProgress(_ context.Context) ([]esclient.SnapshotRestoreInProgress, error) {
+	return c.cluster.inProgress, nil
+}
+
+func (c fakeEsClient) Close() {}
+
+func fakeClientProvider(cluster *fakeElasticsearch) commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return fakeEsClient{cluster: cluster}, nil
+	}
+}
+
+func testResources() (*snapshotv1alpha1.ElasticsearchRestore, *snapshotv1alpha1.SnapshotRepository, *esv1.Elasticsearch) {
+	restore := &snapshotv1alpha1.ElasticsearchRestore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "disaster-recovery"},
+		Spec: snapshotv1alpha1.ElasticsearchRestoreSpec{
+			ElasticsearchRef:  commonv1.LocalObjectSelector{Name: "es"},
+			RepositoryRef:     snapshotv1alpha1.RepositoryReference{Name: "backups"},
+			Snapshot:          "nightly-*",
+			Indices:           []string{"logs-*"},
+			RenamePattern:     "(.+)",
+			RenameReplacement: "restored-$1",
+		},
+	}
+	repository := &snapshotv1alpha1.SnapshotRepository{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backups"},
+		Spec: snapshotv1alpha1.SnapshotRepositorySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			RepositoryName:    "my_repository",
+			Type:              snapshotv1alpha1.S3RepositoryType,
+		},
+		Status: snapshotv1alpha1.SnapshotRepositoryStatus{
+			Details: map[string]snapshotv1alpha1.ElasticsearchRepositoryStatus{"es": {Phase: snapshotv1alpha1.ReadyPhase}},
+		},
+	}
+	es := &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	return restore, repository, es
+}
+
+func TestReconcileElasticsearchRestore_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	restore, repository, es := testResources()
+	cluster := &fakeElasticsearch{snapshots: []esclient.Snapshot{
+		{Snapshot: "nightly-1", State: esclient.SnapshotSuccessState, StartTimeMillis: 1704159000000},
+		{Snapshot: "nightly-2", State: esclient.SnapshotSuccessState, StartTimeMillis: 1704245400000},
+		{Snapshot: "nightly-3", State: "FAILED", StartTimeMillis: 1704331800000},
+	}}
+	k8sClient := k8s.NewFakeClient(restore, repository, es)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &restoreKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(cluster),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "disaster-recovery"}}
+	getStatus := func() snapshotv1alpha1.ElasticsearchRestoreStatus {
+		var actual snapshotv1alpha1.ElasticsearchRestore
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual.Status
+	}
+
+	// wait for Elasticsearch to be ready
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	require.Equal(t, snapshotv1alpha1.RestorePendingPhase, getStatus().Phase)
+	require.Equal(t, "Waiting for Elasticsearch to be ready", getStatus().Message)
+	require.Empty(t, cluster.restores)
+
+	// restore the most recent successful snapshot matching the pattern
+	es.Status.Phase = esv1.ElasticsearchReadyPhase
+	require.NoError(t, k8sClient.Status().Update(ctx, es))
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, progressRequeue, res)
+	status := getStatus()
+	require.Equal(t, snapshotv1alpha1.RestoreInProgressPhase, status.Phase)
+	require.Equal(t, "nightly-2", status.Snapshot)
+	require.NotNil(t, status.StartTime)
+	require.Equal(t, []esclient.SnapshotRestoreRequest{{Indices: []string{"logs-*"}, RenamePattern: "(.+)", RenameReplacement: "restored-$1"}}, cluster.restores)
+
+	// the restore is still in progress
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, progressRequeue, res)
+	require.Equal(t, snapshotv1alpha1.RestoreInProgressPhase, getStatus().Phase)
+
+	// the restore completed
+	cluster.inProgress = nil
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{}, res)
+	status = getStatus()
+	require.Equal(t, snapshotv1alpha1.RestoreCompletedPhase, status.Phase)
+	require.NotNil(t, status.CompletionTime)
+
+	// the snapshot is not restored again
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{}, res)
+	require.Len(t, cluster.restores, 1)
+}
+
+func restoreException(reason string) error {
+	err := &esclient.APIError{StatusCode: http.StatusInternalServerError}
+	err.ErrorResponse.Error.Reason = reason
+	return err
+}
+
+func TestReconcileElasticsearchRestore_Reconcile_Errors(t *testing.T) {
+	ctx := context.Background()
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "disaster-recovery"}}
+	tests := []struct {
+		name        string
+		cluster     *fakeElasticsearch
+		wantPhase   snapshotv1alpha1.RestorePhase
+		wantMessage string
+		wantEvents  int
+	}{
+		{
+			name:        "no successful snapshot",
+			cluster:     &fakeElasticsearch{snapshots: []esclient.Snapshot{{Snapshot: "nightly-1", State: "PARTIAL"}}},
+			wantPhase:   snapshotv1alpha1.RestorePendingPhase,
+			wantMessage: "No successful snapshot matching nightly-* in repository my_repository",
+		},
+		{
+			name: "restore rejected",
+			cluster: &fakeElasticsearch{
+				snapshots:  []esclient.Snapshot{{Snapshot: "nightly-1", State: esclient.SnapshotSuccessState}},
+				restoreErr: restoreException("[my_repository:nightly-1] cannot restore index [restored-logs] because an open index with same name already exists in the cluster"),
+			},
+			wantPhase:   snapshotv1alpha1.RestoreFailedPhase,
+			wantMessage: "Failed to restore snapshot nightly-1 on Elasticsearch es: [my_repository:nightly-1] cannot restore index [restored-logs] because an open index with same name already exists in the cluster",
+			wantEvents:  1,
+		},
+		{
+			name: "Elasticsearch unreachable",
+			cluster: &fakeElasticsearch{
+				snapshots:  []esclient.Snapshot{{Snapshot: "nightly-1", State: esclient.SnapshotSuccessState}},
+				restoreErr: errors.New("connection refused"),
+			},
+			wantPhase:   snapshotv1alpha1.RestorePendingPhase,
+			wantMessage: "connection refused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore, repository, es := testResources()
+			es.Status.Phase = esv1.ElasticsearchReadyPhase
+			k8sClient := k8s.NewFakeClient(restore, repository, es)
+			recorder := record.NewFakeRecorder(10)
+			r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &restoreKind{
+				Client:           k8sClient,
+				esClientProvider: fakeClientProvider(tt.cluster),
+				recorder:         recorder,
+			})
+
+			_, err := r.Reconcile(ctx, request)
+			require.NoError(t, err)
+			var actual snapshotv1alpha1.ElasticsearchRestore
+			require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+			require.Equal(t, tt.wantPhase, actual.Status.Phase)
+			require.Equal(t, tt.wantMessage, actual.Status.Message)
+			require.Len(t, recorder.Events, tt.wantEvents)
+			require.Empty(t, tt.cluster.restores)
+		})
+	}
+}
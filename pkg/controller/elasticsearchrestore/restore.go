@@ -0,0 +1,172 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchrestore
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// start starts the restore once the Elasticsearch cluster is ready and the repository registered on it, and returns
+// the updated status of the restore.
+func (r *restoreKind) start(
+	ctx context.Context,
+	restore snapshotv1alpha1.ElasticsearchRestore,
+	status snapshotv1alpha1.ElasticsearchRestoreStatus,
+) snapshotv1alpha1.ElasticsearchRestoreStatus {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx)
+	esName := restore.Spec.ElasticsearchRef.Name
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: esName}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return pendingStatus(status, fmt.Sprintf("Elasticsearch %s not found", esName))
+		}
+		return pendingStatus(status, err.Error())
+	}
+
+	var repository snapshotv1alpha1.SnapshotRepository
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.RepositoryRef.Name}, &repository); err != nil {
+		if apierrors.IsNotFound(err) {
+			return pendingStatus(status, fmt.Sprintf("SnapshotRepository %s not found", restore.Spec.RepositoryRef.Name))
+		}
+		return pendingStatus(status, err.Error())
+	}
+
+	// the repository must be registered on the cluster to restore snapshots from it
+	switch {
+	case !repository.References(types.NamespacedName{Namespace: es.Namespace, Name: es.Name}):
+		return pendingStatus(status, fmt.Sprintf("SnapshotRepository %s is not registered on Elasticsearch %s", repository.Name, esName))
+	case repository.Status.Details[esName].Phase != snapshotv1alpha1.ReadyPhase:
+		return pendingStatus(status, fmt.Sprintf("Waiting for SnapshotRepository %s to be registered", repository.Name))
+	case es.Status.Phase != esv1.ElasticsearchReadyPhase:
+		return pendingStatus(status, "Waiting for Elasticsearch to be ready")
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return pendingStatus(status, err.Error())
+	}
+	defer esClient.Close()
+
+	repositoryName := repository.RepositoryNameOrDefault()
+	snapshots, err := esClient.GetSnapshots(ctx, repositoryName, restore.Spec.Snapshot)
+	if err != nil {
+		return pendingStatus(status, err.Error())
+	}
+	snapshot, found := esclient.LatestSuccessfulSnapshot(snapshots)
+	if !found {
+		// the snapshot may not be taken yet
+		return pendingStatus(status, fmt.Sprintf("No successful snapshot matching %s in repository %s", restore.Spec.Snapshot, repositoryName))
+	}
+
+	// the restore may have been started by a previous reconciliation which failed to update the status
+	inProgress, err := isRestoreInProgress(ctx, esClient, snapshot)
+	if err != nil {
+		return pendingStatus(status, err.Error())
+	}
+	if !inProgress {
+		log.Info("Restoring snapshot", "es_name", esName, "repository", repositoryName, "snapshot", snapshot)
+		if err := esClient.RestoreSnapshot(ctx, repositoryName, snapshot, restoreRequest(restore)); err != nil {
+			if !esclient.IsUnavailable(err) {
+				msg := fmt.Sprintf("Failed to restore snapshot %s on Elasticsearch %s: %s", snapshot, esName, esclient.ErrorReason(err))
+				r.recorder.Event(&restore, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+				status.Phase = snapshotv1alpha1.RestoreFailedPhase
+				status.Message = msg
+				status.Snapshot = snapshot
+				return status
+			}
+			return pendingStatus(status, err.Error())
+		}
+	}
+
+	now := metav1.Now()
+	status.Phase = snapshotv1alpha1.RestoreInProgressPhase
+	status.Message = ""
+	status.Snapshot = snapshot
+	status.StartTime = &now
+	return status
+}
+
+// checkProgress checks whether the restore in progress completed, and returns the updated status of the restore.
+func (r *restoreKind) checkProgress(
+	ctx context.Context,
+	restore snapshotv1alpha1.ElasticsearchRestore,
+	status snapshotv1alpha1.ElasticsearchRestoreStatus,
+) snapshotv1alpha1.ElasticsearchRestoreStatus {
+	defer tracing.Span(&ctx)()
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.ElasticsearchRef.Name}, &es); err != nil {
+		status.Message = err.Error()
+		return status
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+	defer esClient.Close()
+
+	inProgress, err := isRestoreInProgress(ctx, esClient, status.Snapshot)
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+	status.Message = ""
+	if inProgress {
+		return status
+	}
+
+	ulog.FromContext(ctx).Info("Snapshot restored", "es_name", es.Name, "snapshot", status.Snapshot)
+	now := metav1.Now()
+	status.Phase = snapshotv1alpha1.RestoreCompletedPhase
+	status.CompletionTime = &now
+	return status
+}
+
+// isRestoreInProgress returns true if a restore of the given snapshot is in progress. Completed restores are removed
+// from the restores in progress by Elasticsearch.
+func isRestoreInProgress(ctx context.Context, esClient esclient.Client, snapshot string) (bool, error) {
+	restores, err := esClient.GetSnapshotRestoresInProgress(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, restore := range restores {
+		if restore.Snapshot == snapshot {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func restoreRequest(restore snapshotv1alpha1.ElasticsearchRestore) esclient.SnapshotRestoreRequest {
+	return esclient.SnapshotRestoreRequest{
+		Indices:            restore.Spec.Indices,
+		RenamePattern:      restore.Spec.RenamePattern,
+		RenameReplacement:  restore.Spec.RenameReplacement,
+		IncludeGlobalState: restore.Spec.IncludeGlobalState,
+	}
+}
+
+func pendingStatus(status snapshotv1alpha1.ElasticsearchRestoreStatus, msg string) snapshotv1alpha1.ElasticsearchRestoreStatus {
+	status.Phase = snapshotv1alpha1.RestorePendingPhase
+	status.Message = msg
+	return status
+}
@@ -0,0 +1,175 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchrole
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	controllerName = "elasticsearchrole-controller"
+
+	// RoleFinalizer lets the operator delete the role from Elasticsearch before the ElasticsearchRole resource is deleted.
+	RoleFinalizer = "security.k8s.elastic.co/delete-role"
+)
+
+// config identifies the ElasticsearchRole controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "ElasticsearchRole",
+	NameField:      "role_name",
+	Finalizer:      RoleFinalizer,
+}
+
+// Add creates a new ElasticsearchRole Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Elasticsearch and reconcile the ElasticsearchRole resources selecting them
+		source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestForRoles(r.Client)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of ElasticsearchRole.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*securityv1alpha1.ElasticsearchRole, securityv1alpha1.ElasticsearchRoleStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &roleKind{
+		Client:           c,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+// reconcileRequestForRoles returns the requests to reconcile the ElasticsearchRole resources selecting the watched
+// Elasticsearch cluster, or configured on it before its labels changed.
+func reconcileRequestForRoles(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &securityv1alpha1.ElasticsearchRoleList{}, func(role *securityv1alpha1.ElasticsearchRole, obj client.Object) bool {
+		_, configured := role.Status.Details[obj.GetName()]
+		return configured || role.Selects(k8s.ExtractNamespacedName(obj), obj.GetLabels())
+	})
+}
+
+// roleKind configures ElasticsearchRole resources in Elasticsearch.
+type roleKind struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*securityv1alpha1.ElasticsearchRole, securityv1alpha1.ElasticsearchRoleStatus] = &roleKind{}
+	_ apiresource.Remover[*securityv1alpha1.ElasticsearchRole]                                        = &roleKind{}
+)
+
+func (r *roleKind) NewObject() *securityv1alpha1.ElasticsearchRole {
+	return &securityv1alpha1.ElasticsearchRole{}
+}
+
+func (r *roleKind) GetStatus(role *securityv1alpha1.ElasticsearchRole) securityv1alpha1.ElasticsearchRoleStatus {
+	return role.Status
+}
+
+func (r *roleKind) SetStatus(role *securityv1alpha1.ElasticsearchRole, status securityv1alpha1.ElasticsearchRoleStatus) {
+	role.Status = status
+}
+
+func (r *roleKind) InvalidStatus(role *securityv1alpha1.ElasticsearchRole, _ error) securityv1alpha1.ElasticsearchRoleStatus {
+	status := securityv1alpha1.NewRoleStatus(*role)
+	status.Phase = securityv1alpha1.InvalidPhase
+	return status
+}
+
+// Configure configures the role on the selected Elasticsearch clusters, and deletes it from the clusters that are no
+// longer selected.
+func (r *roleKind) Configure(ctx context.Context, obj *securityv1alpha1.ElasticsearchRole) (*reconciler.Results, securityv1alpha1.ElasticsearchRoleStatus) {
+	role := *obj
+	log := ulog.FromContext(ctx)
+	results := reconciler.NewResult(ctx)
+	status := securityv1alpha1.NewRoleStatus(role)
+
+	esNames, err := r.selectedClusters(ctx, role)
+	if err != nil {
+		return results.WithError(err), role.Status
+	}
+
+	// configure the role on the referenced and selected Elasticsearch clusters
+	for _, esName := range esNames {
+		status.SetElasticsearchStatus(esName, r.reconcileElasticsearch(ctx, role, esName))
+	}
+
+	// delete the role from the clusters that are no longer referenced or selected
+	selected := set.Make(esNames...)
+	for esName := range role.Status.Details {
+		if selected.Has(esName) {
+			continue
+		}
+		if err := r.deleteRole(ctx, role, esName); err != nil {
+			log.Error(err, "Failed to delete the role", "es_name", esName)
+			status.SetElasticsearchStatus(esName, securityv1alpha1.ElasticsearchClusterStatus{
+				Phase:   securityv1alpha1.ApplyingChangesPhase,
+				Message: "Failed to delete the role: " + err.Error(),
+			})
+		}
+	}
+
+	results.WithResult(apiresource.Requeue(status.Phase == securityv1alpha1.ReadyPhase))
+
+	return results, status
+}
+
+// Remove deletes the role from all the clusters it is configured on.
+func (r *roleKind) Remove(ctx context.Context, obj *securityv1alpha1.ElasticsearchRole) (reconcile.Result, error) {
+	role := *obj
+	for esName := range role.Status.Details {
+		if err := r.deleteRole(ctx, role, esName); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// selectedClusters returns the sorted names of the Elasticsearch clusters referenced by the role, whether they exist
+// or not, and of the existing clusters matching its selector.
+func (r *roleKind) selectedClusters(ctx context.Context, role securityv1alpha1.ElasticsearchRole) ([]string, error) {
+	names := set.Make()
+	for _, ref := range role.Spec.ElasticsearchRefs {
+		names.Add(ref.Name)
+	}
+	if role.Spec.ElasticsearchSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(role.Spec.ElasticsearchSelector)
+		if err != nil {
+			return nil, err
+		}
+		var clusters esv1.ElasticsearchList
+		if err := r.Client.List(ctx, &clusters, client.InNamespace(role.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+		for _, es := range clusters.Items {
+			names.Add(es.Name)
+		}
+	}
+	return names.AsSortedSlice(), nil
+}
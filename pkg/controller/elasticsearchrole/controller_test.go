@@ -0,0 +1,179 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchrole
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeEsClient stores roles in memory, as Elasticsearch returns them with all their fields.
+type fakeEsClient struct {
+	esclient.Client
+
+	roles   map[string]esclient.Role
+	updates *int
+}
+
+func (c fakeEsClient) GetNativeRole(_ context.Context, name string) (esclient.Role, error) {
+	role, exists := c.roles[name]
+	if !exists {
+		return esclient.Role{}, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return role, nil
+}
+
+func (c fakeEsClient) UpdateNativeRole(_ context.Context, name string, role esclient.Role) error {
+	*c.updates++
+	c.roles[name] = withDefaults(role)
+	return nil
+}
+
+func (c fakeEsClient) DeleteNativeRole(_ context.Context, name string) error {
+	if _, exists := c.roles[name]; !exists {
+		return &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	delete(c.roles, name)
+	return nil
+}
+
+func (c fakeEsClient) Close() {}
+
+type fakeElasticsearch struct {
+	roles   map[string]esclient.Role
+	updates int
+}
+
+func fakeClientProvider(clusters map[string]*fakeElasticsearch) commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, es esv1.Elasticsearch) (esclient.Client, error) {
+		cluster := clusters[es.Name]
+		return fakeEsClient{roles: cluster.roles, updates: &cluster.updates}, nil
+	}
+}
+
+func TestReconcileElasticsearchRole_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	role := &securityv1alpha1.ElasticsearchRole{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "logs-reader"},
+		Spec: securityv1alpha1.ElasticsearchRoleSpec{
+			ElasticsearchRefs:     []commonv1.LocalObjectSelector{{Name: "es1"}},
+			ElasticsearchSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			Cluster:               []string{"monitor"},
+			Indices: []securityv1alpha1.IndicesPrivileges{{
+				Names:         []string{"logs-*"},
+				Privileges:    []string{"read"},
+				FieldSecurity: &securityv1alpha1.FieldSecurity{Grant: []string{"*"}, Except: []string{"secret"}},
+			}},
+		},
+	}
+	es1 := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es1"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	es2 := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es2", Labels: map[string]string{"env": "prod"}},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	es3 := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es3", Labels: map[string]string{"env": "dev"}},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	clusters := map[string]*fakeElasticsearch{
+		"es1": {roles: map[string]esclient.Role{}},
+		"es2": {roles: map[string]esclient.Role{}},
+		"es3": {roles: map[string]esclient.Role{}},
+	}
+	k8sClient := k8s.NewFakeClient(role, es1, es2, es3)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &roleKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(clusters),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "logs-reader"}}
+	getRole := func() securityv1alpha1.ElasticsearchRole {
+		var actual securityv1alpha1.ElasticsearchRole
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+
+	// the role is configured on the referenced and selected clusters
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	actual := getRole()
+	require.Equal(t, securityv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, "2/2", actual.Status.ReadyCount)
+	require.Equal(t, []string{RoleFinalizer}, actual.Finalizers)
+	require.Equal(t, 1, clusters["es1"].updates)
+	require.Equal(t, 1, clusters["es2"].updates)
+	require.Empty(t, clusters["es3"].roles)
+
+	// the role is not updated if it did not change, even if Elasticsearch returns the empty fields
+	var fromES esclient.Role
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "cluster": ["monitor"],
+  "indices": [{"names": ["logs-*"], "privileges": ["read"], "field_security": {"grant": ["*"], "except": ["secret"]}, "allow_restricted_indices": false}],
+  "applications": [],
+  "run_as": [],
+  "metadata": {},
+  "transient_metadata": {"enabled": true}
+}`), &fromES))
+	clusters["es1"].roles["logs-reader"] = fromES
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 1, clusters["es1"].updates)
+	require.Empty(t, recorder.Events)
+
+	// the role is restored if it is modified in Elasticsearch
+	fromES.Cluster = []string{"all"}
+	clusters["es1"].roles["logs-reader"] = fromES
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 2, clusters["es1"].updates)
+	require.Equal(t, []string{"monitor"}, clusters["es1"].roles["logs-reader"].Cluster)
+	status := getRole().Status
+	require.NotNil(t, status.Details["es1"].LastDriftTime)
+	require.Nil(t, status.Details["es2"].LastDriftTime)
+	require.Contains(t, <-recorder.Events, "Role logs-reader was modified in Elasticsearch es1")
+
+	// the role is deleted from the clusters that are no longer selected
+	es2.Labels = map[string]string{"env": "dev"}
+	require.NoError(t, k8sClient.Update(ctx, es2))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	status = getRole().Status
+	require.Equal(t, "1/1", status.ReadyCount)
+	require.NotContains(t, status.Details, "es2")
+	require.Empty(t, clusters["es2"].roles)
+
+	// the role is deleted from Elasticsearch with the ElasticsearchRole
+	actual = getRole()
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, clusters["es1"].roles)
+	err = k8sClient.Get(ctx, request.NamespacedName, &securityv1alpha1.ElasticsearchRole{})
+	require.True(t, apierrors.IsNotFound(err))
+}
@@ -0,0 +1,150 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchrole
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileElasticsearch configures the role on the given Elasticsearch cluster if it does not exist yet or if it
+// differs from the specification, and returns the status of the role for this cluster.
+func (r *roleKind) reconcileElasticsearch(ctx context.Context, role securityv1alpha1.ElasticsearchRole, esName string) securityv1alpha1.ElasticsearchClusterStatus {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx).WithValues("es_name", esName)
+
+	es, phase, msg := apiresource.ReadyElasticsearch(ctx, r.Client, types.NamespacedName{Namespace: role.Namespace, Name: esName})
+	if phase != securityv1alpha1.ReadyPhase {
+		return securityv1alpha1.ElasticsearchClusterStatus{Phase: phase, Message: msg}
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	defer esClient.Close()
+
+	name := role.RoleNameOrDefault()
+	previous := role.Status.Details[esName]
+	status := securityv1alpha1.ElasticsearchClusterStatus{Phase: securityv1alpha1.ReadyPhase, LastDriftTime: previous.LastDriftTime}
+	expected := expectedRole(role)
+	actual, err := esClient.GetNativeRole(ctx, name)
+	if err != nil && !esclient.IsNotFound(err) {
+		return applyingChangesStatus(err.Error())
+	}
+	if err == nil && sameRole(expected, actual) {
+		return status
+	}
+	// the role was already configured from the current specification, it was modified or deleted outside of the operator
+	if previous.Phase == securityv1alpha1.ReadyPhase && role.Status.ObservedGeneration == role.Generation {
+		msg := fmt.Sprintf("Role %s was modified in Elasticsearch %s, restoring it from the ElasticsearchRole specification", name, esName)
+		status.LastDriftTime = apiresource.RecordDrift(log, r.recorder, &role, msg)
+	}
+
+	log.Info("Updating role", "role", name)
+	if err := esClient.UpdateNativeRole(ctx, name, expected); err != nil {
+		msg := fmt.Sprintf("Failed to update role %s on Elasticsearch %s: %s", name, esName, esclient.ErrorReason(err))
+		r.recorder.Event(&role, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return errorStatus(msg)
+	}
+	return status
+}
+
+// deleteRole deletes the role from the given Elasticsearch cluster.
+func (r *roleKind) deleteRole(ctx context.Context, role securityv1alpha1.ElasticsearchRole, esName string) error {
+	defer tracing.Span(&ctx)()
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: role.Namespace, Name: esName}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+
+	ulog.FromContext(ctx).Info("Deleting role", "es_name", esName, "role", role.RoleNameOrDefault())
+	if err := esClient.DeleteNativeRole(ctx, role.RoleNameOrDefault()); err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func expectedRole(role securityv1alpha1.ElasticsearchRole) esclient.Role {
+	expected := esclient.Role{
+		Cluster: role.Spec.Cluster,
+		RunAs:   role.Spec.RunAs,
+	}
+	for _, indices := range role.Spec.Indices {
+		indexRole := esclient.IndexRole{
+			Names:                  indices.Names,
+			Privileges:             indices.Privileges,
+			Query:                  indices.Query,
+			AllowRestrictedIndices: ptr.To(indices.AllowRestrictedIndices),
+		}
+		if indices.FieldSecurity != nil {
+			indexRole.FieldSecurity = &esclient.FieldSecurity{Grant: indices.FieldSecurity.Grant, Except: indices.FieldSecurity.Except}
+		}
+		expected.Indices = append(expected.Indices, indexRole)
+	}
+	if role.Spec.Metadata != nil {
+		expected.Metadata = role.Spec.Metadata.Data
+	}
+	return expected
+}
+
+// sameRole compares the JSON representations of the expected role and of the role in Elasticsearch, which returns
+// all the fields of the role, including the empty ones.
+func sameRole(expected, actual esclient.Role) bool {
+	expectedBytes, err := json.Marshal(withDefaults(expected))
+	if err != nil {
+		return false
+	}
+	actualBytes, err := json.Marshal(withDefaults(actual))
+	if err != nil {
+		return false
+	}
+	return string(expectedBytes) == string(actualBytes)
+}
+
+// withDefaults returns a copy of the given role with the allow_restricted_indices default set on its indices privileges.
+func withDefaults(role esclient.Role) esclient.Role {
+	indices := make([]esclient.IndexRole, 0, len(role.Indices))
+	for _, indexRole := range role.Indices {
+		if indexRole.AllowRestrictedIndices == nil {
+			indexRole.AllowRestrictedIndices = ptr.To(false)
+		}
+		indices = append(indices, indexRole)
+	}
+	role.Indices = indices
+	return role
+}
+
+func applyingChangesStatus(msg string) securityv1alpha1.ElasticsearchClusterStatus {
+	return securityv1alpha1.ElasticsearchClusterStatus{Phase: securityv1alpha1.ApplyingChangesPhase, Message: msg}
+}
+
+func errorStatus(msg string) securityv1alpha1.ElasticsearchClusterStatus {
+	return securityv1alpha1.ElasticsearchClusterStatus{Phase: securityv1alpha1.ErrorPhase, Message: msg}
+}
@@ -0,0 +1,175 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchrolemapping
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	controllerName = "elasticsearchrolemapping-controller"
+
+	// RoleMappingFinalizer lets the operator delete the role mapping from Elasticsearch before the ElasticsearchRoleMapping resource is deleted.
+	RoleMappingFinalizer = "security.k8s.elastic.co/delete-role-mapping"
+)
+
+// config identifies the ElasticsearchRoleMapping controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "ElasticsearchRoleMapping",
+	NameField:      "mapping_name",
+	Finalizer:      RoleMappingFinalizer,
+}
+
+// Add creates a new ElasticsearchRoleMapping Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Elasticsearch and reconcile the ElasticsearchRoleMapping resources selecting them
+		source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestForRoleMappings(r.Client)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of ElasticsearchRoleMapping.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*securityv1alpha1.ElasticsearchRoleMapping, securityv1alpha1.ElasticsearchRoleMappingStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &mappingKind{
+		Client:           c,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+// reconcileRequestForRoleMappings returns the requests to reconcile the ElasticsearchRoleMapping resources selecting the watched
+// Elasticsearch cluster, or configured on it before its labels changed.
+func reconcileRequestForRoleMappings(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &securityv1alpha1.ElasticsearchRoleMappingList{}, func(mapping *securityv1alpha1.ElasticsearchRoleMapping, obj client.Object) bool {
+		_, configured := mapping.Status.Details[obj.GetName()]
+		return configured || mapping.Selects(k8s.ExtractNamespacedName(obj), obj.GetLabels())
+	})
+}
+
+// mappingKind configures ElasticsearchRoleMapping resources in Elasticsearch.
+type mappingKind struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*securityv1alpha1.ElasticsearchRoleMapping, securityv1alpha1.ElasticsearchRoleMappingStatus] = &mappingKind{}
+	_ apiresource.Remover[*securityv1alpha1.ElasticsearchRoleMapping]                                               = &mappingKind{}
+)
+
+func (r *mappingKind) NewObject() *securityv1alpha1.ElasticsearchRoleMapping {
+	return &securityv1alpha1.ElasticsearchRoleMapping{}
+}
+
+func (r *mappingKind) GetStatus(mapping *securityv1alpha1.ElasticsearchRoleMapping) securityv1alpha1.ElasticsearchRoleMappingStatus {
+	return mapping.Status
+}
+
+func (r *mappingKind) SetStatus(mapping *securityv1alpha1.ElasticsearchRoleMapping, status securityv1alpha1.ElasticsearchRoleMappingStatus) {
+	mapping.Status = status
+}
+
+func (r *mappingKind) InvalidStatus(mapping *securityv1alpha1.ElasticsearchRoleMapping, _ error) securityv1alpha1.ElasticsearchRoleMappingStatus {
+	status := securityv1alpha1.NewRoleMappingStatus(*mapping)
+	status.Phase = securityv1alpha1.InvalidPhase
+	return status
+}
+
+// Configure configures the role mapping on the selected Elasticsearch clusters, and deletes it from the clusters that
+// are no longer selected.
+func (r *mappingKind) Configure(ctx context.Context, obj *securityv1alpha1.ElasticsearchRoleMapping) (*reconciler.Results, securityv1alpha1.ElasticsearchRoleMappingStatus) {
+	mapping := *obj
+	log := ulog.FromContext(ctx)
+	results := reconciler.NewResult(ctx)
+	status := securityv1alpha1.NewRoleMappingStatus(mapping)
+
+	esNames, err := r.selectedClusters(ctx, mapping)
+	if err != nil {
+		return results.WithError(err), mapping.Status
+	}
+
+	// configure the role mapping on the referenced and selected Elasticsearch clusters
+	for _, esName := range esNames {
+		status.SetElasticsearchStatus(esName, r.reconcileElasticsearch(ctx, mapping, esName))
+	}
+
+	// delete the role mapping from the clusters that are no longer referenced or selected
+	selected := set.Make(esNames...)
+	for esName := range mapping.Status.Details {
+		if selected.Has(esName) {
+			continue
+		}
+		if err := r.deleteMapping(ctx, mapping, esName); err != nil {
+			log.Error(err, "Failed to delete the role mapping", "es_name", esName)
+			status.SetElasticsearchStatus(esName, securityv1alpha1.ElasticsearchClusterStatus{
+				Phase:   securityv1alpha1.ApplyingChangesPhase,
+				Message: "Failed to delete the role mapping: " + err.Error(),
+			})
+		}
+	}
+
+	results.WithResult(apiresource.Requeue(status.Phase == securityv1alpha1.ReadyPhase))
+
+	return results, status
+}
+
+// Remove deletes the role mapping from all the clusters it is configured on.
+func (r *mappingKind) Remove(ctx context.Context, obj *securityv1alpha1.ElasticsearchRoleMapping) (reconcile.Result, error) {
+	mapping := *obj
+	for esName := range mapping.Status.Details {
+		if err := r.deleteMapping(ctx, mapping, esName); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// selectedClusters returns the sorted names of the Elasticsearch clusters referenced by the role mapping, whether they exist
+// or not, and of the existing clusters matching its selector.
+func (r *mappingKind) selectedClusters(ctx context.Context, mapping securityv1alpha1.ElasticsearchRoleMapping) ([]string, error) {
+	names := set.Make()
+	for _, ref := range mapping.Spec.ElasticsearchRefs {
+		names.Add(ref.Name)
+	}
+	if mapping.Spec.ElasticsearchSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(mapping.Spec.ElasticsearchSelector)
+		if err != nil {
+			return nil, err
+		}
+		var clusters esv1.ElasticsearchList
+		if err := r.Client.List(ctx, &clusters, client.InNamespace(mapping.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+		for _, es := range clusters.Items {
+			names.Add(es.Name)
+		}
+	}
+	return names.AsSortedSlice(), nil
+}
@@ -0,0 +1,179 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchrolemapping
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeEsClient stores role mappings in memory.
+type fakeEsClient struct {
+	esclient.Client
+
+	mappings map[string]esclient.RoleMapping
+	updates  *int
+}
+
+func (c fakeEsClient) GetRoleMapping(_ context.Context, name string) (esclient.RoleMapping, error) {
+	mapping, exists := c.mappings[name]
+	if !exists {
+		return esclient.RoleMapping{}, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return mapping, nil
+}
+
+func (c fakeEsClient) UpdateRoleMapping(_ context.Context, name string, mapping esclient.RoleMapping) error {
+	*c.updates++
+	c.mappings[name] = mapping
+	return nil
+}
+
+func (c fakeEsClient) DeleteRoleMapping(_ context.Context, name string) error {
+	if _, exists := c.mappings[name]; !exists {
+		return &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	delete(c.mappings, name)
+	return nil
+}
+
+func (c fakeEsClient) Close() {}
+
+type fakeElasticsearch struct {
+	mappings map[string]esclient.RoleMapping
+	updates  int
+}
+
+func fakeClientProvider(clusters map[string]*fakeElasticsearch) commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, es esv1.Elasticsearch) (esclient.Client, error) {
+		cluster := clusters[es.Name]
+		return fakeEsClient{mappings: cluster.mappings, updates: &cluster.updates}, nil
+	}
+}
+
+func adminsRules(group string) map[string]interface{} {
+	return map[string]interface{}{"field": map[string]interface{}{"groups": group}}
+}
+
+func TestReconcileElasticsearchRoleMapping_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	mapping := &securityv1alpha1.ElasticsearchRoleMapping{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "admins"},
+		Spec: securityv1alpha1.ElasticsearchRoleMappingSpec{
+			ElasticsearchRefs:     []commonv1.LocalObjectSelector{{Name: "es1"}},
+			ElasticsearchSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			Roles:                 []string{"superuser"},
+			Rules:                 &commonv1.Config{Data: adminsRules("admins")},
+		},
+	}
+	es1 := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es1"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	es2 := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es2", Labels: map[string]string{"env": "prod"}},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	clusters := map[string]*fakeElasticsearch{
+		"es1": {mappings: map[string]esclient.RoleMapping{}},
+		"es2": {mappings: map[string]esclient.RoleMapping{}},
+	}
+	k8sClient := k8s.NewFakeClient(mapping, es1, es2)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &mappingKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(clusters),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "admins"}}
+	getMapping := func() securityv1alpha1.ElasticsearchRoleMapping {
+		var actual securityv1alpha1.ElasticsearchRoleMapping
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+
+	// the role mapping is configured on the referenced and selected clusters
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	actual := getMapping()
+	require.Equal(t, "2/2", actual.Status.ReadyCount)
+	require.Equal(t, []string{RoleMappingFinalizer}, actual.Finalizers)
+	require.Equal(t, esclient.RoleMapping{Enabled: true, Roles: []string{"superuser"}, Rules: adminsRules("admins")}, clusters["es1"].mappings["admins"])
+	require.Equal(t, 1, clusters["es1"].updates)
+	require.Equal(t, 1, clusters["es2"].updates)
+
+	// the role mapping is restored if it is modified in Elasticsearch
+	clusters["es2"].mappings["admins"] = esclient.RoleMapping{Enabled: true, Roles: []string{"superuser"}, Rules: adminsRules("everyone")}
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, adminsRules("admins"), clusters["es2"].mappings["admins"].Rules)
+	require.NotNil(t, getMapping().Status.Details["es2"].LastDriftTime)
+	require.Contains(t, <-recorder.Events, "Role mapping admins was modified in Elasticsearch es2")
+
+	// the role mapping is updated when its specification changes
+	actual = getMapping()
+	actual.Generation++
+	actual.Spec.Enabled = ptr.To(false)
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.False(t, clusters["es1"].mappings["admins"].Enabled)
+	require.Empty(t, recorder.Events)
+
+	// the role mapping is deleted from Elasticsearch with the ElasticsearchRoleMapping
+	actual = getMapping()
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, clusters["es1"].mappings)
+	require.Empty(t, clusters["es2"].mappings)
+	err = k8sClient.Get(ctx, request.NamespacedName, &securityv1alpha1.ElasticsearchRoleMapping{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func Test_expectedMapping_RoleTemplates(t *testing.T) {
+	mapping := securityv1alpha1.ElasticsearchRoleMapping{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "groups"},
+		Spec: securityv1alpha1.ElasticsearchRoleMappingSpec{
+			RoleTemplates: []securityv1alpha1.RoleTemplate{
+				{Source: "{{#tojson}}groups{{/tojson}}", Format: securityv1alpha1.JSONRoleTemplateFormat},
+				{Source: `{{metadata.department}}_user`},
+			},
+			Rules: &commonv1.Config{Data: adminsRules("admins")},
+		},
+	}
+	expected := expectedMapping(mapping)
+	require.Equal(t, []esclient.RoleTemplate{
+		{Template: `{"source":"{{#tojson}}groups{{/tojson}}"}`, Format: "json"},
+		{Template: `{"source":"{{metadata.department}}_user"}`, Format: "string"},
+	}, expected.RoleTemplates)
+
+	// the role mapping returned by Elasticsearch, with empty roles, matches the expected one
+	actual := expected
+	actual.Roles = []string{}
+	require.True(t, sameMapping(expected, actual))
+	actual.RoleTemplates = []esclient.RoleTemplate{{Template: `{"source":"{{username}}"}`, Format: "string"}}
+	require.False(t, sameMapping(expected, actual))
+}
@@ -0,0 +1,139 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchrolemapping
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileElasticsearch configures the role mapping on the given Elasticsearch cluster if it does not exist yet or
+// if it differs from the specification, and returns the status of the role mapping for this cluster.
+func (r *mappingKind) reconcileElasticsearch(ctx context.Context, mapping securityv1alpha1.ElasticsearchRoleMapping, esName string) securityv1alpha1.ElasticsearchClusterStatus {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx).WithValues("es_name", esName)
+
+	es, phase, msg := apiresource.ReadyElasticsearch(ctx, r.Client, types.NamespacedName{Namespace: mapping.Namespace, Name: esName})
+	if phase != securityv1alpha1.ReadyPhase {
+		return securityv1alpha1.ElasticsearchClusterStatus{Phase: phase, Message: msg}
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	defer esClient.Close()
+
+	name := mapping.MappingNameOrDefault()
+	previous := mapping.Status.Details[esName]
+	status := securityv1alpha1.ElasticsearchClusterStatus{Phase: securityv1alpha1.ReadyPhase, LastDriftTime: previous.LastDriftTime}
+	expected := expectedMapping(mapping)
+	actual, err := esClient.GetRoleMapping(ctx, name)
+	if err != nil && !esclient.IsNotFound(err) {
+		return applyingChangesStatus(err.Error())
+	}
+	if err == nil && sameMapping(expected, actual) {
+		return status
+	}
+	// the role mapping was already configured from the current specification, it was modified or deleted outside of the operator
+	if previous.Phase == securityv1alpha1.ReadyPhase && mapping.Status.ObservedGeneration == mapping.Generation {
+		msg := fmt.Sprintf("Role mapping %s was modified in Elasticsearch %s, restoring it from the ElasticsearchRoleMapping specification", name, esName)
+		status.LastDriftTime = apiresource.RecordDrift(log, r.recorder, &mapping, msg)
+	}
+
+	log.Info("Updating role mapping", "role_mapping", name)
+	if err := esClient.UpdateRoleMapping(ctx, name, expected); err != nil {
+		msg := fmt.Sprintf("Failed to update role mapping %s on Elasticsearch %s: %s", name, esName, esclient.ErrorReason(err))
+		r.recorder.Event(&mapping, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return errorStatus(msg)
+	}
+	return status
+}
+
+// deleteMapping deletes the role mapping from the given Elasticsearch cluster.
+func (r *mappingKind) deleteMapping(ctx context.Context, mapping securityv1alpha1.ElasticsearchRoleMapping, esName string) error {
+	defer tracing.Span(&ctx)()
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: mapping.Namespace, Name: esName}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+
+	ulog.FromContext(ctx).Info("Deleting role mapping", "es_name", esName, "role_mapping", mapping.MappingNameOrDefault())
+	if err := esClient.DeleteRoleMapping(ctx, mapping.MappingNameOrDefault()); err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func expectedMapping(mapping securityv1alpha1.ElasticsearchRoleMapping) esclient.RoleMapping {
+	expected := esclient.RoleMapping{
+		Enabled: mapping.EnabledOrDefault(),
+		Roles:   mapping.Spec.Roles,
+	}
+	for _, template := range mapping.Spec.RoleTemplates {
+		expected.RoleTemplates = append(expected.RoleTemplates, expectedRoleTemplate(template))
+	}
+	if mapping.Spec.Rules != nil {
+		expected.Rules = mapping.Spec.Rules.Data
+	}
+	if mapping.Spec.Metadata != nil {
+		expected.Metadata = mapping.Spec.Metadata.Data
+	}
+	return expected
+}
+
+// expectedRoleTemplate returns the role template with its source serialized as Elasticsearch returns it, so that
+// role mappings can be compared.
+func expectedRoleTemplate(template securityv1alpha1.RoleTemplate) esclient.RoleTemplate {
+	// marshalling a struct with a single string field cannot fail
+	source, _ := json.Marshal(struct {
+		Source string `json:"source"`
+	}{Source: template.Source})
+	return esclient.RoleTemplate{Template: string(source), Format: string(template.FormatOrDefault())}
+}
+
+// sameMapping compares the JSON representations of the expected role mapping and of the role mapping in Elasticsearch.
+func sameMapping(expected, actual esclient.RoleMapping) bool {
+	expectedBytes, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	actualBytes, err := json.Marshal(actual)
+	if err != nil {
+		return false
+	}
+	return string(expectedBytes) == string(actualBytes)
+}
+
+func applyingChangesStatus(msg string) securityv1alpha1.ElasticsearchClusterStatus {
+	return securityv1alpha1.ElasticsearchClusterStatus{Phase: securityv1alpha1.ApplyingChangesPhase, Message: msg}
+}
+
+func errorStatus(msg string) securityv1alpha1.ElasticsearchClusterStatus {
+	return securityv1alpha1.ElasticsearchClusterStatus{Phase: securityv1alpha1.ErrorPhase, Message: msg}
+}
@@ -0,0 +1,139 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchuser
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	controllerName = "elasticsearchuser-controller"
+
+	// UserFinalizer lets the operator delete the user from Elasticsearch before the ElasticsearchUser resource is deleted.
+	UserFinalizer = "security.k8s.elastic.co/delete-user"
+)
+
+// config identifies the ElasticsearchUser controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "ElasticsearchUser",
+	NameField:      "user_name",
+	Finalizer:      UserFinalizer,
+}
+
+// Add creates a new ElasticsearchUser Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Elasticsearch and reconcile the ElasticsearchUser resources referencing them
+		source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestForUsers(r.Client, referencesElasticsearch)),
+		// watch for changes to the password Secrets and reconcile the ElasticsearchUser resources referencing them
+		watches.Kind[client.Object](mgr.GetCache(), &corev1.Secret{}, reconcileRequestForUsers(r.Client, referencesPasswordSecret)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of ElasticsearchUser.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*securityv1alpha1.ElasticsearchUser, securityv1alpha1.ElasticsearchUserStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &userKind{
+		Client:           c,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+func referencesElasticsearch(user *securityv1alpha1.ElasticsearchUser, obj client.Object) bool {
+	return user.References(k8s.ExtractNamespacedName(obj))
+}
+
+func referencesPasswordSecret(user *securityv1alpha1.ElasticsearchUser, obj client.Object) bool {
+	return user.Spec.PasswordSecretRef.SecretName == obj.GetName()
+}
+
+// reconcileRequestForUsers returns the requests to reconcile the ElasticsearchUser resources, in the namespace of the
+// watched object, referencing it.
+func reconcileRequestForUsers(
+	clnt k8s.Client,
+	references func(*securityv1alpha1.ElasticsearchUser, client.Object) bool,
+) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &securityv1alpha1.ElasticsearchUserList{}, references)
+}
+
+// userKind configures ElasticsearchUser resources in Elasticsearch.
+type userKind struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*securityv1alpha1.ElasticsearchUser, securityv1alpha1.ElasticsearchUserStatus] = &userKind{}
+	_ apiresource.Remover[*securityv1alpha1.ElasticsearchUser]                                        = &userKind{}
+)
+
+func (r *userKind) NewObject() *securityv1alpha1.ElasticsearchUser {
+	return &securityv1alpha1.ElasticsearchUser{}
+}
+
+func (r *userKind) GetStatus(user *securityv1alpha1.ElasticsearchUser) securityv1alpha1.ElasticsearchUserStatus {
+	return user.Status
+}
+
+func (r *userKind) SetStatus(user *securityv1alpha1.ElasticsearchUser, status securityv1alpha1.ElasticsearchUserStatus) {
+	user.Status = status
+}
+
+func (r *userKind) InvalidStatus(user *securityv1alpha1.ElasticsearchUser, _ error) securityv1alpha1.ElasticsearchUserStatus {
+	return securityv1alpha1.ElasticsearchUserStatus{
+		Phase:              securityv1alpha1.InvalidPhase,
+		ObservedGeneration: user.Generation,
+	}
+}
+
+// Configure creates or updates the user in the referenced Elasticsearch cluster.
+func (r *userKind) Configure(ctx context.Context, obj *securityv1alpha1.ElasticsearchUser) (*reconciler.Results, securityv1alpha1.ElasticsearchUserStatus) {
+	user := *obj
+	results := reconciler.NewResult(ctx)
+
+	status := r.reconcileUser(ctx, user)
+	status.Username = user.UsernameOrDefault()
+	status.ObservedGeneration = user.Generation
+
+	results.WithResult(apiresource.Requeue(status.Phase == securityv1alpha1.ReadyPhase))
+
+	return results, status
+}
+
+// Remove deletes the user from Elasticsearch, including from the cluster it was created in if the reference changed
+// since then.
+func (r *userKind) Remove(ctx context.Context, obj *securityv1alpha1.ElasticsearchUser) (reconcile.Result, error) {
+	user := *obj
+	if previous := user.Status.ElasticsearchName; previous != "" && previous != user.Spec.ElasticsearchRef.Name {
+		if err := r.deleteUser(ctx, user, previous); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, r.deleteUser(ctx, user, user.Spec.ElasticsearchRef.Name)
+}
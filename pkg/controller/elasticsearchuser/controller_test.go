@@ -0,0 +1,276 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchuser
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeEsClient stores native users in memory, without their password as Elasticsearch does not return it.
+type fakeEsClient struct {
+	esclient.Client
+
+	users     map[string]esclient.NativeUser
+	passwords map[string]string
+}
+
+func (c fakeEsClient) GetNativeUser(_ context.Context, username string) (esclient.NativeUser, error) {
+	user, exists := c.users[username]
+	if !exists {
+		return esclient.NativeUser{}, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return user, nil
+}
+
+func (c fakeEsClient) UpdateNativeUser(_ context.Context, username string, user esclient.NativeUser) error {
+	if user.Password != "" {
+		c.passwords[username] = user.Password
+	}
+	user.Password = ""
+	c.users[username] = user
+	return nil
+}
+
+func (c fakeEsClient) DeleteNativeUser(_ context.Context, username string) error {
+	if _, exists := c.users[username]; !exists {
+		return &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	delete(c.users, username)
+	delete(c.passwords, username)
+	return nil
+}
+
+func (c fakeEsClient) Close() {}
+
+func fakeClientProvider(esClient fakeEsClient) commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return esClient, nil
+	}
+}
+
+func TestReconcileElasticsearchUser_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	user := &securityv1alpha1.ElasticsearchUser{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "jacknich"},
+		Spec: securityv1alpha1.ElasticsearchUserSpec{
+			ElasticsearchRef:  commonv1.LocalObjectSelector{Name: "es"},
+			PasswordSecretRef: securityv1alpha1.PasswordSecretRef{SecretName: "jacknich-password"},
+			Roles:             []string{"superuser", "admin"},
+			FullName:          "Jack Nicholson",
+		},
+	}
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "jacknich-password"},
+		Data:       map[string][]byte{"password": []byte("l0ng-r4nd0m-p@ssw0rd")},
+	}
+	esClient := fakeEsClient{users: map[string]esclient.NativeUser{}, passwords: map[string]string{}}
+	k8sClient := k8s.NewFakeClient(user, es, secret)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &userKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(esClient),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "jacknich"}}
+	getUser := func() securityv1alpha1.ElasticsearchUser {
+		var actual securityv1alpha1.ElasticsearchUser
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+
+	// the user is created in Elasticsearch
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	actual := getUser()
+	require.Equal(t, securityv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, "jacknich", actual.Status.Username)
+	require.Equal(t, []string{UserFinalizer}, actual.Finalizers)
+	require.Equal(t, esclient.NativeUser{Roles: []string{"superuser", "admin"}, FullName: "Jack Nicholson", Enabled: true}, esClient.users["jacknich"])
+	require.Equal(t, "l0ng-r4nd0m-p@ssw0rd", esClient.passwords["jacknich"])
+	// the password is not part of the hash stored in the status
+	withPassword := expectedUser(getUser(), "l0ng-r4nd0m-p@ssw0rd")
+	require.NotEqual(t, hash.HashObject(withPassword), getUser().Status.Hash)
+	withPassword.Password = ""
+	require.NotEqual(t, hash.HashObject(withPassword), getUser().Status.Hash)
+
+	// the user is not updated if it did not change, independently of the order of its roles
+	esClient.users["jacknich"] = esclient.NativeUser{Roles: []string{"admin", "superuser"}, FullName: "Jack Nicholson", Enabled: true}
+	esClient.passwords["jacknich"] = "unchanged"
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "unchanged", esClient.passwords["jacknich"])
+	require.Empty(t, recorder.Events)
+
+	// the user is restored if it is modified in Elasticsearch
+	esClient.users["jacknich"] = esclient.NativeUser{Roles: []string{"admin"}, FullName: "Jack Nicholson", Enabled: false}
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, esclient.NativeUser{Roles: []string{"superuser", "admin"}, FullName: "Jack Nicholson", Enabled: true}, esClient.users["jacknich"])
+	require.NotNil(t, getUser().Status.LastDriftTime)
+	require.Contains(t, <-recorder.Events, "User jacknich was modified in Elasticsearch es")
+
+	// the password is updated when it changes in the Secret
+	secret.Data["password"] = []byte("n3w-p@ssw0rd")
+	require.NoError(t, k8sClient.Update(ctx, secret))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "n3w-p@ssw0rd", esClient.passwords["jacknich"])
+	require.Empty(t, recorder.Events)
+
+	// the user is deleted from Elasticsearch with the ElasticsearchUser
+	actual = getUser()
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, esClient.users)
+	err = k8sClient.Get(ctx, request.NamespacedName, &securityv1alpha1.ElasticsearchUser{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileElasticsearchUser_Reconcile_MissingPassword(t *testing.T) {
+	ctx := context.Background()
+	user := &securityv1alpha1.ElasticsearchUser{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "jacknich"},
+		Spec: securityv1alpha1.ElasticsearchUserSpec{
+			ElasticsearchRef:  commonv1.LocalObjectSelector{Name: "es"},
+			PasswordSecretRef: securityv1alpha1.PasswordSecretRef{SecretName: "jacknich-password", Key: "pwd"},
+		},
+	}
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	esClient := fakeEsClient{users: map[string]esclient.NativeUser{}, passwords: map[string]string{}}
+	k8sClient := k8s.NewFakeClient(user, es)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &userKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(esClient),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "jacknich"}}
+	getStatus := func() securityv1alpha1.ElasticsearchUserStatus {
+		var actual securityv1alpha1.ElasticsearchUser
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual.Status
+	}
+
+	// the Secret does not exist
+	_, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, securityv1alpha1.ErrorPhase, getStatus().Phase)
+	require.Equal(t, "Secret jacknich-password not found", getStatus().Message)
+
+	// the Secret does not hold the password
+	require.NoError(t, k8sClient.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "jacknich-password"},
+		Data:       map[string][]byte{"password": []byte("l0ng-r4nd0m-p@ssw0rd")},
+	}))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "Key pwd not found in Secret jacknich-password", getStatus().Message)
+	require.Empty(t, esClient.users)
+}
+
+func TestReconcileElasticsearchUser_Reconcile_ElasticsearchRefChange(t *testing.T) {
+	ctx := context.Background()
+	user := &securityv1alpha1.ElasticsearchUser{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "jacknich"},
+		Spec: securityv1alpha1.ElasticsearchUserSpec{
+			ElasticsearchRef:  commonv1.LocalObjectSelector{Name: "es1"},
+			PasswordSecretRef: securityv1alpha1.PasswordSecretRef{SecretName: "jacknich-password"},
+			Roles:             []string{"superuser"},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "jacknich-password"},
+		Data:       map[string][]byte{"password": []byte("l0ng-r4nd0m-p@ssw0rd")},
+	}
+	objects := []client.Object{user, secret}
+	esClients := map[string]fakeEsClient{}
+	for _, name := range []string{"es1", "es2"} {
+		objects = append(objects, &esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+		})
+		esClients[name] = fakeEsClient{users: map[string]esclient.NativeUser{}, passwords: map[string]string{}}
+	}
+	k8sClient := k8s.NewFakeClient(objects...)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &userKind{
+		Client: k8sClient,
+		esClientProvider: func(_ context.Context, _ k8s.Client, _ net.Dialer, es esv1.Elasticsearch) (esclient.Client, error) {
+			return esClients[es.Name], nil
+		},
+		recorder: recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "jacknich"}}
+	getUser := func() securityv1alpha1.ElasticsearchUser {
+		var actual securityv1alpha1.ElasticsearchUser
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+
+	// the user is created in the first cluster
+	_, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "es1", getUser().Status.ElasticsearchName)
+	require.Contains(t, esClients["es1"].users, "jacknich")
+
+	// the user is moved to the second cluster
+	actual := getUser()
+	actual.Spec.ElasticsearchRef.Name = "es2"
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, esClients["es1"].users)
+	require.Contains(t, esClients["es2"].users, "jacknich")
+	require.Equal(t, "l0ng-r4nd0m-p@ssw0rd", esClients["es2"].passwords["jacknich"])
+	actual = getUser()
+	require.Equal(t, securityv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, "es2", actual.Status.ElasticsearchName)
+	// the creation in the new cluster is not reported as a drift
+	require.Nil(t, actual.Status.LastDriftTime)
+	require.Empty(t, recorder.Events)
+
+	// the user is moved back to the first cluster, then deleted before being created there
+	esClients["es1"].users["jacknich"] = esclient.NativeUser{}
+	actual.Spec.ElasticsearchRef.Name = "es1"
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, esClients["es1"].users)
+	require.Empty(t, esClients["es2"].users)
+	err = k8sClient.Get(ctx, request.NamespacedName, &securityv1alpha1.ElasticsearchUser{})
+	require.True(t, apierrors.IsNotFound(err))
+}
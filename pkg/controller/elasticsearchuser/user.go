@@ -0,0 +1,201 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearchuser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileUser creates or updates the user in Elasticsearch if it does not exist yet or if it differs from the
+// specification, and returns the status of the ElasticsearchUser.
+func (r *userKind) reconcileUser(ctx context.Context, user securityv1alpha1.ElasticsearchUser) (status securityv1alpha1.ElasticsearchUserStatus) {
+	defer tracing.Span(&ctx)()
+	esName := user.Spec.ElasticsearchRef.Name
+	log := ulog.FromContext(ctx).WithValues("es_name", esName)
+
+	// keep track of the last update of the user until the current specification is applied
+	defer func() {
+		if status.Phase != securityv1alpha1.ReadyPhase {
+			status.Hash = user.Status.Hash
+			status.LastDriftTime = user.Status.LastDriftTime
+			status.ElasticsearchName = user.Status.ElasticsearchName
+		}
+	}()
+
+	// the user was created in another cluster before the reference changed, delete it from there first
+	if previous := user.Status.ElasticsearchName; previous != "" && previous != esName {
+		if err := r.deleteUser(ctx, user, previous); err != nil {
+			return applyingChangesStatus(fmt.Sprintf("Failed to delete user %s from Elasticsearch %s: %s",
+				user.UsernameOrDefault(), previous, esclient.ErrorReason(err)))
+		}
+		// the user must be created from scratch in the new cluster
+		user.Status.ElasticsearchName = ""
+		user.Status.Hash = ""
+		user.Status.LastDriftTime = nil
+	}
+
+	es, phase, msg := apiresource.ReadyElasticsearch(ctx, r.Client, types.NamespacedName{Namespace: user.Namespace, Name: esName})
+	if phase != securityv1alpha1.ReadyPhase {
+		return securityv1alpha1.ElasticsearchUserStatus{Phase: phase, Message: msg}
+	}
+
+	password, secretVersion, msg, err := r.password(ctx, user)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	if msg != "" {
+		return errorStatus(msg)
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	defer esClient.Close()
+
+	name := user.UsernameOrDefault()
+	expected := expectedUser(user, password)
+	// the password is never returned by Elasticsearch, the hash tells whether it changed since the last update through
+	// the version of the Secret holding it, so that the password itself is not hashed into the status
+	withoutPassword := expected
+	withoutPassword.Password = ""
+	expectedHash := hash.HashObject([]interface{}{withoutPassword, secretVersion})
+	status = securityv1alpha1.ElasticsearchUserStatus{
+		Phase:             securityv1alpha1.ReadyPhase,
+		ElasticsearchName: esName,
+		Hash:              expectedHash,
+		LastDriftTime:     user.Status.LastDriftTime,
+	}
+	actual, err := esClient.GetNativeUser(ctx, name)
+	if err != nil && !esclient.IsNotFound(err) {
+		return applyingChangesStatus(err.Error())
+	}
+	if user.Status.Hash == expectedHash {
+		if err == nil && sameUser(expected, actual) {
+			return status
+		}
+		// the user was already created from the current specification, it was modified or deleted outside of the operator
+		msg := fmt.Sprintf("User %s was modified in Elasticsearch %s, restoring it from the ElasticsearchUser specification", name, esName)
+		status.LastDriftTime = apiresource.RecordDrift(log, r.recorder, &user, msg)
+	}
+
+	log.Info("Updating user", "username", name)
+	if err := esClient.UpdateNativeUser(ctx, name, expected); err != nil {
+		msg := fmt.Sprintf("Failed to update user %s on Elasticsearch %s: %s", name, esName, esclient.ErrorReason(err))
+		r.recorder.Event(&user, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return errorStatus(msg)
+	}
+	return status
+}
+
+// password returns the password of the user read from the referenced Secret along with the UID and resource version of
+// the Secret, or a message explaining why it cannot be read.
+func (r *userKind) password(ctx context.Context, user securityv1alpha1.ElasticsearchUser) (string, string, string, error) {
+	ref := user.Spec.PasswordSecretRef
+	var secret corev1.Secret
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: user.Namespace, Name: ref.SecretName}, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", "", fmt.Sprintf("Secret %s not found", ref.SecretName), nil
+		}
+		return "", "", "", err
+	}
+	password, exists := secret.Data[ref.KeyOrDefault()]
+	if !exists || len(password) == 0 {
+		return "", "", fmt.Sprintf("Key %s not found in Secret %s", ref.KeyOrDefault(), ref.SecretName), nil
+	}
+	return string(password), fmt.Sprintf("%s/%s", secret.UID, secret.ResourceVersion), "", nil
+}
+
+// deleteUser deletes the user from the given Elasticsearch cluster, in the namespace of the user.
+func (r *userKind) deleteUser(ctx context.Context, user securityv1alpha1.ElasticsearchUser, esName string) error {
+	defer tracing.Span(&ctx)()
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: user.Namespace, Name: esName}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+
+	ulog.FromContext(ctx).Info("Deleting user", "es_name", es.Name, "username", user.UsernameOrDefault())
+	if err := esClient.DeleteNativeUser(ctx, user.UsernameOrDefault()); err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func expectedUser(user securityv1alpha1.ElasticsearchUser, password string) esclient.NativeUser {
+	expected := esclient.NativeUser{
+		Password: password,
+		// roles are mandatory in the Elasticsearch API
+		Roles:    []string{},
+		FullName: user.Spec.FullName,
+		Email:    user.Spec.Email,
+		Enabled:  user.EnabledOrDefault(),
+	}
+	expected.Roles = append(expected.Roles, user.Spec.Roles...)
+	if user.Spec.Metadata != nil {
+		expected.Metadata = user.Spec.Metadata.Data
+	}
+	return expected
+}
+
+// sameUser compares the JSON representations of the expected user, without its password, and of the user in
+// Elasticsearch, independently of the order of the roles.
+func sameUser(expected, actual esclient.NativeUser) bool {
+	expected.Password = ""
+	actual.Password = ""
+	expected.Roles = sorted(expected.Roles)
+	actual.Roles = sorted(actual.Roles)
+	if actual.Roles == nil {
+		actual.Roles = []string{}
+	}
+	expectedBytes, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	actualBytes, err := json.Marshal(actual)
+	if err != nil {
+		return false
+	}
+	return string(expectedBytes) == string(actualBytes)
+}
+
+func sorted(roles []string) []string {
+	roles = slices.Clone(roles)
+	slices.Sort(roles)
+	return roles
+}
+
+func applyingChangesStatus(msg string) securityv1alpha1.ElasticsearchUserStatus {
+	return securityv1alpha1.ElasticsearchUserStatus{Phase: securityv1alpha1.ApplyingChangesPhase, Message: msg}
+}
+
+func errorStatus(msg string) securityv1alpha1.ElasticsearchUserStatus {
+	return securityv1alpha1.ElasticsearchUserStatus{Phase: securityv1alpha1.ErrorPhase, Message: msg}
+}
@@ -29,7 +29,7 @@ func (r *ReconcileEnterpriseSearch) reconcileDeployment(
 		return appsv1.Deployment{}, err
 	}
 	deploy := deployment.New(deployParams)
-	return deployment.Reconcile(ctx, r.K8sClient(), deploy, &ent)
+	return deployment.Reconcile(ctx, r.K8sClient(), deploy, &ent, true)
 }
 
 func (r *ReconcileEnterpriseSearch) deploymentParams(ent entv1.EnterpriseSearch, configHash string) (deployment.Params, error) {
@@ -14,6 +14,7 @@ import (
 	"go.elastic.co/apm/v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -31,7 +32,11 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/externaldns"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/ingress"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/queue"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
@@ -68,7 +73,7 @@ func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileEn
 
 func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileEnterpriseSearch) error {
 	// Watch for changes to EnterpriseSearch
-	err := c.Watch(source.Kind(mgr.GetCache(), &entv1.EnterpriseSearch{}, &handler.TypedEnqueueRequestForObject[*entv1.EnterpriseSearch]{}))
+	err := c.Watch(source.Kind(mgr.GetCache(), &entv1.EnterpriseSearch{}, queue.EnqueueRequestForObject[*entv1.EnterpriseSearch]()))
 	if err != nil {
 		return err
 	}
@@ -95,8 +100,21 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileEnterp
 		return err
 	}
 
+	// Watch ingresses
+	if err := c.Watch(source.Kind(mgr.GetCache(), &networkingv1.Ingress{}, handler.TypedEnqueueRequestForOwner[*networkingv1.Ingress](
+		mgr.GetScheme(), mgr.GetRESTMapper(),
+		&entv1.EnterpriseSearch{}, handler.OnlyControllerOwner(),
+	))); err != nil {
+		return err
+	}
+
+	// Watch Gateway routes
+	if err := gateway.WatchRoutes(mgr, c, &entv1.EnterpriseSearch{}); err != nil {
+		return err
+	}
+
 	// Watch owned and soft-owned secrets
-	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
+	if err := c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
 		mgr.GetScheme(), mgr.GetRESTMapper(),
 		&entv1.EnterpriseSearch{}, handler.OnlyControllerOwner(),
 	))); err != nil {
@@ -107,7 +125,7 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileEnterp
 	}
 
 	// Dynamically watch referenced secrets to connect to Elasticsearch
-	return c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets))
+	return c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets))
 }
 
 var _ reconcile.Reconciler = &ReconcileEnterpriseSearch{}
@@ -205,12 +223,13 @@ func (r *ReconcileEnterpriseSearch) doReconcile(ctx context.Context, ent entv1.E
 		DynamicWatches:        r.DynamicWatches(),
 		Owner:                 &ent,
 		TLSOptions:            ent.Spec.HTTP.TLS,
+		ExtraHTTPSANs:         ent.Spec.HTTP.ExternalSANs(),
 		Namer:                 entv1.Namer,
 		Labels:                ent.GetIdentityLabels(),
 		Services:              []corev1.Service{*svc},
 		GlobalCA:              r.GlobalCA,
-		CACertRotation:        r.CACertRotation,
-		CertRotation:          r.CertRotation,
+		CACertRotation:        r.CACertRotation.Get(),
+		CertRotation:          r.CertRotation.Get(),
 		GarbageCollectSecrets: true,
 	}.ReconcileCAAndHTTPCerts(ctx)
 	if results.HasError() {
@@ -219,6 +238,20 @@ func (r *ReconcileEnterpriseSearch) doReconcile(ctx context.Context, ent entv1.E
 		return results, status
 	}
 
+	exposureParams := ingress.Params{
+		Owner:   &ent,
+		HTTP:    ent.Spec.HTTP,
+		Service: *svc,
+		Labels:  ent.GetIdentityLabels(),
+		Namer:   entv1.Namer,
+	}
+	if err := ingress.Reconcile(ctx, r.Client, exposureParams); err != nil {
+		return results.WithError(err), status
+	}
+	if err := gateway.Reconcile(ctx, r.Client, exposureParams); err != nil {
+		return results.WithError(err), status
+	}
+
 	entVersion, err := version.Parse(ent.Spec.Version)
 	if err != nil {
 		return results.WithError(err), status
@@ -322,6 +355,7 @@ func NewService(ent entv1.EnterpriseSearch) *corev1.Service {
 
 	svc.ObjectMeta.Namespace = ent.Namespace
 	svc.ObjectMeta.Name = HTTPServiceName(ent.Name)
+	svc.ObjectMeta.Annotations = externaldns.ServiceAnnotations(ent.Spec.HTTP)
 
 	labels := ent.GetIdentityLabels()
 	ports := []corev1.ServicePort{
@@ -14,6 +14,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemesh"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 )
@@ -69,11 +70,16 @@ func newPodSpec(ent entv1.EnterpriseSearch, configHash string) (corev1.PodTempla
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	image, err := container.ResolveImage(ent.Spec.Image, container.EnterpriseSearchImage, v)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	builder := defaults.NewPodTemplateBuilder(ent.Spec.PodTemplate, entv1.EnterpriseSearchContainerName).
 		WithAnnotations(annotations).
+		WithAnnotations(servicemesh.PodAnnotations(ent.Spec.HTTP.TLS)).
 		WithResources(DefaultResources).
-		WithDockerImage(ent.Spec.Image, container.ImageRepository(container.EnterpriseSearchImage, v)).
+		WithDockerImage(ent.Spec.Image, image).
 		WithPorts(defaultContainerPorts).
 		WithReadinessProbe(ReadinessProbe).
 		WithEnv(DefaultEnv...).
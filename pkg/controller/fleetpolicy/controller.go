@@ -0,0 +1,166 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package fleetpolicy
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	fleetv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/fleet/v1alpha1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	controllerName = "fleetpolicy-controller"
+
+	// PolicyFinalizer lets the operator delete the agent policy from Fleet before the FleetPolicy resource is deleted.
+	PolicyFinalizer = "fleet.k8s.elastic.co/delete-policy"
+)
+
+// config identifies the FleetPolicy controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "FleetPolicy",
+	NameField:      "policy_name",
+	Finalizer:      PolicyFinalizer,
+}
+
+// Add creates a new FleetPolicy Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Kibana and reconcile the FleetPolicy resources referencing them
+		source.Kind[client.Object](mgr.GetCache(), &kbv1.Kibana{}, reconcileRequestForPolicies(r.Client, kibanaPolicies)),
+		// watch for changes to Agents and reconcile the FleetPolicy resources they are enrolled in
+		source.Kind[client.Object](mgr.GetCache(), &agentv1alpha1.Agent{}, reconcileRequestForPolicies(r.Client, agentPolicies)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of FleetPolicy.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*fleetv1alpha1.FleetPolicy, fleetv1alpha1.FleetPolicyStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &policyKind{
+		Client:           c,
+		kbClientProvider: kbclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+// kibanaPolicies returns the Kibana of the watched Kibana, and a filter accepting all its policies.
+func kibanaPolicies(obj client.Object) (types.NamespacedName, func(fleetv1alpha1.FleetPolicy) bool) {
+	return k8s.ExtractNamespacedName(obj), func(fleetv1alpha1.FleetPolicy) bool { return true }
+}
+
+// agentPolicies returns the Kibana the watched Agent is enrolled through, and a filter accepting the policy it is
+// enrolled in.
+func agentPolicies(obj client.Object) (types.NamespacedName, func(fleetv1alpha1.FleetPolicy) bool) {
+	agent, ok := obj.(*agentv1alpha1.Agent)
+	if !ok || !agent.Spec.FleetModeEnabled() || !agent.Spec.KibanaRef.IsDefined() {
+		return types.NamespacedName{}, func(fleetv1alpha1.FleetPolicy) bool { return false }
+	}
+	return agent.Spec.KibanaRef.WithDefaultNamespace(agent.Namespace).NamespacedName(), func(policy fleetv1alpha1.FleetPolicy) bool {
+		return policy.PolicyIDOrDefault() == agent.Spec.PolicyID
+	}
+}
+
+// reconcileRequestForPolicies returns the requests to reconcile the FleetPolicy resources configured on the Kibana
+// related to the watched object and accepted by the filter.
+func reconcileRequestForPolicies(
+	clnt k8s.Client,
+	policies func(client.Object) (types.NamespacedName, func(fleetv1alpha1.FleetPolicy) bool),
+) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return handler.TypedEnqueueRequestsFromMapFunc[client.Object](func(ctx context.Context, obj client.Object) []reconcile.Request {
+		kb, accept := policies(obj)
+		if kb.Name == "" {
+			return nil
+		}
+		var list fleetv1alpha1.FleetPolicyList
+		if err := clnt.List(ctx, &list, client.InNamespace(kb.Namespace)); err != nil {
+			ulog.Log.Error(err, "Fail to list FleetPolicyList while watching", "kind", obj.GetObjectKind().GroupVersionKind().Kind)
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, policy := range list.Items {
+			policy := policy
+			if policy.References(kb) && accept(policy) {
+				requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&policy)})
+			}
+		}
+		return requests
+	})
+}
+
+// policyKind configures FleetPolicy resources in Fleet.
+type policyKind struct {
+	k8s.Client
+	kbClientProvider kbclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*fleetv1alpha1.FleetPolicy, fleetv1alpha1.FleetPolicyStatus] = &policyKind{}
+	_ apiresource.Remover[*fleetv1alpha1.FleetPolicy]                               = &policyKind{}
+)
+
+func (r *policyKind) NewObject() *fleetv1alpha1.FleetPolicy {
+	return &fleetv1alpha1.FleetPolicy{}
+}
+
+func (r *policyKind) GetStatus(policy *fleetv1alpha1.FleetPolicy) fleetv1alpha1.FleetPolicyStatus {
+	return policy.Status
+}
+
+func (r *policyKind) SetStatus(policy *fleetv1alpha1.FleetPolicy, status fleetv1alpha1.FleetPolicyStatus) {
+	policy.Status = status
+}
+
+func (r *policyKind) InvalidStatus(policy *fleetv1alpha1.FleetPolicy, err error) fleetv1alpha1.FleetPolicyStatus {
+	status := policy.Status
+	status.Phase = fleetv1alpha1.InvalidPhase
+	status.Message = err.Error()
+	return status
+}
+
+// Configure configures the agent policy and its integrations in Fleet, and reports the Agents enrolled in it.
+func (r *policyKind) Configure(ctx context.Context, obj *fleetv1alpha1.FleetPolicy) (*reconciler.Results, fleetv1alpha1.FleetPolicyStatus) {
+	policy := *obj
+	results := reconciler.NewResult(ctx)
+
+	// configure the agent policy and its integrations in Fleet
+	status := r.reconcileFleet(ctx, policy)
+
+	// report the Agents enrolled in the policy
+	agents, err := r.enrolledAgents(ctx, policy)
+	if err != nil {
+		return results.WithError(err), status
+	}
+	status.Agents = agents
+
+	results.WithResult(apiresource.Requeue(status.Phase == fleetv1alpha1.ReadyPhase))
+
+	return results, status
+}
+
+// Remove deletes the agent policy from Fleet.
+func (r *policyKind) Remove(ctx context.Context, obj *fleetv1alpha1.FleetPolicy) (reconcile.Result, error) {
+	return reconcile.Result{}, r.deletePolicy(ctx, *obj)
+}
@@ -0,0 +1,407 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package fleetpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	fleetv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/fleet/v1alpha1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeKbClient stores agent policies, integrations and installed packages in memory.
+type fakeKbClient struct {
+	// Client is embedded for the APIs the controller does not use
+	kbclient.Client
+	fleet *fakeFleet
+	t     *testing.T
+}
+
+type fakeFleet struct {
+	policies        map[string]map[string]interface{}
+	packagePolicies map[string]map[string]interface{}
+	// packages are the installed packages, as name-version
+	packages []string
+	updates  int
+}
+
+func newFakeFleet() *fakeFleet {
+	return &fakeFleet{
+		policies:        map[string]map[string]interface{}{},
+		packagePolicies: map[string]map[string]interface{}{},
+	}
+}
+
+var notFound = &commonhttp.APIError{StatusCode: http.StatusNotFound}
+
+// stored returns the object as Fleet stores it: decoded from JSON, with the given id and a revision added.
+func stored(t *testing.T, id string, obj map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	bytes, err := json.Marshal(obj)
+	require.NoError(t, err)
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(bytes, &result))
+	result["id"] = id
+	result["revision"] = 1
+	return result
+}
+
+func (c fakeKbClient) GetAgentPolicy(_ context.Context, id string) (map[string]interface{}, error) {
+	policy, exists := c.fleet.policies[id]
+	if !exists {
+		return nil, notFound
+	}
+	return policy, nil
+}
+
+func (c fakeKbClient) CreateAgentPolicy(_ context.Context, id string, policy map[string]interface{}) error {
+	if _, exists := c.fleet.policies[id]; exists {
+		return fmt.Errorf("agent policy %s already exists", id)
+	}
+	c.fleet.policies[id] = stored(c.t, id, policy)
+	return nil
+}
+
+func (c fakeKbClient) UpdateAgentPolicy(_ context.Context, id string, policy map[string]interface{}) error {
+	if _, exists := c.fleet.policies[id]; !exists {
+		return notFound
+	}
+	c.fleet.updates++
+	c.fleet.policies[id] = stored(c.t, id, policy)
+	return nil
+}
+
+func (c fakeKbClient) DeleteAgentPolicy(_ context.Context, id string) error {
+	if _, exists := c.fleet.policies[id]; !exists {
+		return notFound
+	}
+	delete(c.fleet.policies, id)
+	// the integrations of the policy are deleted with it
+	for packagePolicyID, packagePolicy := range c.fleet.packagePolicies {
+		if packagePolicy["policy_id"] == id {
+			delete(c.fleet.packagePolicies, packagePolicyID)
+		}
+	}
+	return nil
+}
+
+func (c fakeKbClient) GetPackagePolicy(_ context.Context, id string) (map[string]interface{}, error) {
+	packagePolicy, exists := c.fleet.packagePolicies[id]
+	if !exists {
+		return nil, notFound
+	}
+	return packagePolicy, nil
+}
+
+func (c fakeKbClient) CreatePackagePolicy(_ context.Context, id string, packagePolicy map[string]interface{}) error {
+	if _, exists := c.fleet.packagePolicies[id]; exists {
+		return fmt.Errorf("package policy %s already exists", id)
+	}
+	c.fleet.packagePolicies[id] = stored(c.t, id, packagePolicy)
+	return nil
+}
+
+func (c fakeKbClient) UpdatePackagePolicy(_ context.Context, id string, packagePolicy map[string]interface{}) error {
+	if _, exists := c.fleet.packagePolicies[id]; !exists {
+		return notFound
+	}
+	c.fleet.updates++
+	c.fleet.packagePolicies[id] = stored(c.t, id, packagePolicy)
+	return nil
+}
+
+func (c fakeKbClient) DeletePackagePolicy(_ context.Context, id string) error {
+	if _, exists := c.fleet.packagePolicies[id]; !exists {
+		return notFound
+	}
+	delete(c.fleet.packagePolicies, id)
+	return nil
+}
+
+func (c fakeKbClient) GetPackageStatus(_ context.Context, name, version string) (string, error) {
+	for _, pkg := range c.fleet.packages {
+		if pkg == name+"-"+version {
+			return kbclient.PackageInstalledStatus, nil
+		}
+	}
+	return "not_installed", nil
+}
+
+func (c fakeKbClient) InstallPackage(_ context.Context, name, version string) error {
+	c.fleet.packages = append(c.fleet.packages, name+"-"+version)
+	return nil
+}
+
+func (c fakeKbClient) Close() {}
+
+func fakeClientProvider(t *testing.T, fleet *fakeFleet) kbclient.Provider {
+	t.Helper()
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ kbv1.Kibana) (kbclient.Client, error) {
+		return fakeKbClient{fleet: fleet, t: t}, nil
+	}
+}
+
+func mkFleetPolicy(name string) *fleetv1alpha1.FleetPolicy {
+	return &fleetv1alpha1.FleetPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Generation: 1},
+		Spec: fleetv1alpha1.FleetPolicySpec{
+			KibanaRef: commonv1.LocalObjectSelector{Name: "kb"},
+			Policy: &commonv1.Config{Data: map[string]interface{}{
+				"name":               "ECK agents",
+				"monitoring_enabled": []interface{}{"logs", "metrics"},
+			}},
+			PackagePolicies: []fleetv1alpha1.PackagePolicy{
+				{Name: "system", Package: fleetv1alpha1.Package{Name: "system", Version: "1.54.0"}},
+				{
+					Name:    "kubernetes",
+					Package: fleetv1alpha1.Package{Name: "kubernetes", Version: "1.62.0"},
+					Config: &commonv1.Config{Data: map[string]interface{}{
+						"inputs": map[string]interface{}{"kubernetes-kubelet/metrics": map[string]interface{}{"enabled": true}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func mkKibana(health commonv1.DeploymentHealth) *kbv1.Kibana {
+	return &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Status:     kbv1.KibanaStatus{DeploymentStatus: commonv1.DeploymentStatus{Health: health}},
+	}
+}
+
+func mkAgent(name, kibana, policyID string) *agentv1alpha1.Agent {
+	return &agentv1alpha1.Agent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Spec: agentv1alpha1.AgentSpec{
+			Mode:      agentv1alpha1.AgentFleetMode,
+			KibanaRef: commonv1.ObjectSelector{Name: kibana},
+			PolicyID:  policyID,
+		},
+	}
+}
+
+func newTestReconciler(
+	t *testing.T,
+	fleet *fakeFleet,
+	recorder record.EventRecorder,
+	objs ...client.Object,
+) *apiresource.Reconciler[*fleetv1alpha1.FleetPolicy, fleetv1alpha1.FleetPolicyStatus] {
+	t.Helper()
+	c := k8s.NewFakeClient(objs...)
+	return apiresource.NewReconciler(c, recorder, operator.Parameters{}, config, &policyKind{
+		Client:           c,
+		kbClientProvider: fakeClientProvider(t, fleet),
+		recorder:         recorder,
+	})
+}
+
+func TestReconcileFleetPolicy_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	kb := mkKibana(commonv1.RedHealth)
+	fleet := newFakeFleet()
+	recorder := record.NewFakeRecorder(10)
+	r := newTestReconciler(t, fleet, recorder,
+		mkFleetPolicy("eck-agents"), kb,
+		mkAgent("agent-b", "kb", "eck-agents"),
+		mkAgent("agent-a", "kb", "eck-agents"),
+		mkAgent("other-policy", "kb", "other"),
+		mkAgent("other-kibana", "kb2", "eck-agents"),
+	)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "eck-agents"}}
+	getPolicy := func() fleetv1alpha1.FleetPolicy {
+		var actual fleetv1alpha1.FleetPolicy
+		require.NoError(t, r.Client.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+
+	// nothing is configured until Kibana is ready
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	actual := getPolicy()
+	require.Equal(t, fleetv1alpha1.ApplyingChangesPhase, actual.Status.Phase)
+	require.Equal(t, []string{PolicyFinalizer}, actual.Finalizers)
+	require.Empty(t, fleet.policies)
+
+	// the packages are installed, and the policy and its integrations created, once Kibana is ready
+	kb.Status.Health = commonv1.GreenHealth
+	require.NoError(t, r.Client.Status().Update(ctx, kb))
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	actual = getPolicy()
+	require.Equal(t, fleetv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, "eck-agents", actual.Status.PolicyID)
+	require.Equal(t, map[string]string{"system": "eck-agents-system", "kubernetes": "eck-agents-kubernetes"}, actual.Status.PackagePolicyIDs)
+	require.Equal(t, []string{"ns/agent-a", "ns/agent-b"}, actual.Status.Agents)
+	require.Equal(t, []string{"system-1.54.0", "kubernetes-1.62.0"}, fleet.packages)
+	require.Equal(t, "ECK agents", fleet.policies["eck-agents"]["name"])
+	require.Equal(t, "default", fleet.policies["eck-agents"]["namespace"])
+	kubernetes := fleet.packagePolicies["eck-agents-kubernetes"]
+	require.Equal(t, "eck-agents-kubernetes", kubernetes["name"])
+	require.Equal(t, "eck-agents", kubernetes["policy_id"])
+	require.Equal(t, map[string]interface{}{"name": "kubernetes", "version": "1.62.0"}, kubernetes["package"])
+
+	// nothing is updated when Fleet only adds fields to the policy and its integrations
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 0, fleet.updates)
+	require.Empty(t, recorder.Events)
+
+	// the policy and its integrations are restored if they are modified or deleted in Fleet
+	fleet.policies["eck-agents"]["name"] = "Renamed"
+	delete(fleet.packagePolicies, "eck-agents-system")
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "ECK agents", fleet.policies["eck-agents"]["name"])
+	require.Contains(t, fleet.packagePolicies, "eck-agents-system")
+	require.NotNil(t, getPolicy().Status.LastDriftTime)
+	require.Contains(t, <-recorder.Events, "Agent policy eck-agents was modified in Kibana kb")
+
+	// integrations removed from the specification are deleted, and upgraded integrations recreated
+	actual = getPolicy()
+	actual.Spec.PackagePolicies = []fleetv1alpha1.PackagePolicy{
+		{Name: "system", Package: fleetv1alpha1.Package{Name: "system", Version: "1.55.0"}},
+	}
+	actual.Generation++
+	require.NoError(t, r.Client.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	actual = getPolicy()
+	require.Equal(t, fleetv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, map[string]string{"system": "eck-agents-system"}, actual.Status.PackagePolicyIDs)
+	require.NotContains(t, fleet.packagePolicies, "eck-agents-kubernetes")
+	require.Equal(t, map[string]interface{}{"name": "system", "version": "1.55.0"}, fleet.packagePolicies["eck-agents-system"]["package"])
+	require.Contains(t, fleet.packages, "system-1.55.0")
+	require.Empty(t, recorder.Events)
+
+	// the policy is deleted from Fleet with the FleetPolicy
+	require.NoError(t, r.Client.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, fleet.policies)
+	require.Empty(t, fleet.packagePolicies)
+	err = r.Client.Get(ctx, request.NamespacedName, &fleetv1alpha1.FleetPolicy{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileFleetPolicy_MissingKibana(t *testing.T) {
+	ctx := context.Background()
+	fleet := newFakeFleet()
+	r := newTestReconciler(t, fleet, record.NewFakeRecorder(10), mkFleetPolicy("eck-agents"))
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "eck-agents"}}
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	var actual fleetv1alpha1.FleetPolicy
+	require.NoError(t, r.Client.Get(ctx, request.NamespacedName, &actual))
+	require.Equal(t, fleetv1alpha1.ErrorPhase, actual.Status.Phase)
+	require.Equal(t, "Kibana kb not found", actual.Status.Message)
+
+	// the FleetPolicy can be deleted without its Kibana
+	require.NoError(t, r.Client.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	err = r.Client.Get(ctx, request.NamespacedName, &fleetv1alpha1.FleetPolicy{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileFleetPolicy_PolicyConflict(t *testing.T) {
+	ctx := context.Background()
+	duplicate := mkFleetPolicy("eck-agents-copy")
+	duplicate.Spec.PolicyID = "eck-agents"
+	fleet := newFakeFleet()
+	r := newTestReconciler(t, fleet, record.NewFakeRecorder(10), mkFleetPolicy("eck-agents"), duplicate, mkKibana(commonv1.GreenHealth))
+
+	// only the first FleetPolicy by name configures the agent policy
+	for _, name := range []string{"eck-agents-copy", "eck-agents"} {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: name}})
+		require.NoError(t, err)
+	}
+	require.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "eck-agents-copy"}, duplicate))
+	require.Equal(t, fleetv1alpha1.ErrorPhase, duplicate.Status.Phase)
+	require.Equal(t, "FleetPolicy eck-agents already configures agent policy eck-agents in Kibana kb", duplicate.Status.Message)
+	require.Contains(t, fleet.policies, "eck-agents")
+
+	// deleting the duplicate leaves the agent policy in Fleet
+	require.NoError(t, r.Client.Delete(ctx, duplicate))
+	_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "eck-agents-copy"}})
+	require.NoError(t, err)
+	require.Contains(t, fleet.policies, "eck-agents")
+}
+
+func Test_contains(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected interface{}
+		actual   interface{}
+		want     bool
+	}{
+		{
+			name:     "fields added by Fleet are ignored",
+			expected: map[string]interface{}{"name": "a", "vars": map[string]interface{}{"period": "10s"}},
+			actual:   map[string]interface{}{"name": "a", "revision": float64(2), "vars": map[string]interface{}{"period": "10s", "hosts": []interface{}{}}},
+			want:     true,
+		},
+		{
+			name:     "numbers are compared independently of their type",
+			expected: map[string]interface{}{"inactivity_timeout": int64(1209600)},
+			actual:   map[string]interface{}{"inactivity_timeout": float64(1209600)},
+			want:     true,
+		},
+		{
+			name:     "modified value",
+			expected: map[string]interface{}{"vars": map[string]interface{}{"period": "10s"}},
+			actual:   map[string]interface{}{"vars": map[string]interface{}{"period": "30s"}},
+			want:     false,
+		},
+		{
+			name:     "missing field",
+			expected: map[string]interface{}{"description": "agents"},
+			actual:   map[string]interface{}{},
+			want:     false,
+		},
+		{
+			name:     "lists of different length",
+			expected: []interface{}{"logs", "metrics"},
+			actual:   []interface{}{"logs"},
+			want:     false,
+		},
+		{
+			name:     "empty list not returned",
+			expected: []interface{}{},
+			actual:   nil,
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, contains(tt.expected, tt.actual))
+		})
+	}
+}
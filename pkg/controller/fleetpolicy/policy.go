@@ -0,0 +1,349 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package fleetpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	fleetv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/fleet/v1alpha1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileFleet configures the agent policy and its integrations in Fleet if they do not exist yet or if they differ
+// from the specification, and returns the status of the policy.
+func (r *policyKind) reconcileFleet(ctx context.Context, policy fleetv1alpha1.FleetPolicy) fleetv1alpha1.FleetPolicyStatus {
+	defer tracing.Span(&ctx)()
+	kbName := policy.Spec.KibanaRef.Name
+	log := ulog.FromContext(ctx).WithValues("kibana_name", kbName)
+
+	previous := policy.Status
+	status := fleetv1alpha1.FleetPolicyStatus{
+		Phase:              fleetv1alpha1.ReadyPhase,
+		PolicyID:           policy.PolicyIDOrDefault(),
+		PackagePolicyIDs:   previous.PackagePolicyIDs,
+		Agents:             previous.Agents,
+		LastDriftTime:      previous.LastDriftTime,
+		ObservedGeneration: policy.Generation,
+	}
+	withPhase := func(phase fleetv1alpha1.Phase, msg string) fleetv1alpha1.FleetPolicyStatus {
+		status.Phase = phase
+		status.Message = msg
+		return status
+	}
+	failed := func(msg string) fleetv1alpha1.FleetPolicyStatus {
+		r.recorder.Event(&policy, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return withPhase(fleetv1alpha1.ErrorPhase, msg)
+	}
+
+	var kb kbv1.Kibana
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: policy.Namespace, Name: kbName}, &kb); err != nil {
+		if apierrors.IsNotFound(err) {
+			return withPhase(fleetv1alpha1.ErrorPhase, fmt.Sprintf("Kibana %s not found", kbName))
+		}
+		return withPhase(fleetv1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	if kb.Status.Health != commonv1.GreenHealth {
+		return withPhase(fleetv1alpha1.ApplyingChangesPhase, "Waiting for Kibana to be ready")
+	}
+
+	owner, err := r.policyOwner(ctx, policy)
+	if err != nil {
+		return withPhase(fleetv1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	if owner != policy.Name {
+		return failed(fmt.Sprintf("FleetPolicy %s already configures agent policy %s in Kibana %s", owner, status.PolicyID, kbName))
+	}
+
+	kbClient, err := r.kbClientProvider(ctx, r.Client, r.params.Dialer, kb)
+	if err != nil {
+		return withPhase(fleetv1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	defer kbClient.Close()
+
+	// changes are drifts if the policy was already configured from the current specification
+	configured := previous.Phase == fleetv1alpha1.ReadyPhase && previous.ObservedGeneration == policy.Generation
+
+	if err := installPackages(ctx, kbClient, policy.Spec.PackagePolicies); err != nil {
+		return failed(fmt.Sprintf("Failed to install packages on Kibana %s: %s", kbName, err.Error()))
+	}
+
+	policyChanged, err := reconcileAgentPolicy(ctx, kbClient, status.PolicyID, agentPolicyDefinition(policy))
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to configure agent policy on Kibana %s: %s", kbName, err.Error()))
+	}
+
+	// keep track of the previous and current integrations to be able to delete them
+	status.PackagePolicyIDs = make(map[string]string, len(policy.Spec.PackagePolicies))
+	for name, id := range previous.PackagePolicyIDs {
+		status.PackagePolicyIDs[name] = id
+	}
+	expectedIDs := make(map[string]string, len(policy.Spec.PackagePolicies))
+	packagePoliciesChanged := false
+	for _, packagePolicy := range policy.Spec.PackagePolicies {
+		id := policy.PackagePolicyID(packagePolicy.Name)
+		status.PackagePolicyIDs[packagePolicy.Name] = id
+		expectedIDs[packagePolicy.Name] = id
+		changed, err := reconcilePackagePolicy(ctx, kbClient, id, packagePolicyDefinition(policy, packagePolicy))
+		if err != nil {
+			return failed(fmt.Sprintf("Failed to configure integration %s on Kibana %s: %s", packagePolicy.Name, kbName, err.Error()))
+		}
+		packagePoliciesChanged = packagePoliciesChanged || changed
+	}
+
+	if configured && (policyChanged || packagePoliciesChanged) {
+		msg := fmt.Sprintf("Agent policy %s was modified in Kibana %s, restoring it from the FleetPolicy specification", status.PolicyID, kbName)
+		status.LastDriftTime = apiresource.RecordDrift(log, r.recorder, &policy, msg)
+	}
+
+	// delete the integrations removed from the specification
+	for name, id := range previous.PackagePolicyIDs {
+		if _, exists := expectedIDs[name]; exists {
+			continue
+		}
+		log.Info("Deleting integration", "integration", name)
+		if err := kbClient.DeletePackagePolicy(ctx, id); err != nil && !kbclient.IsNotFound(err) {
+			return withPhase(fleetv1alpha1.ApplyingChangesPhase, fmt.Sprintf("Failed to delete integration %s: %s", name, err.Error()))
+		}
+		delete(status.PackagePolicyIDs, name)
+	}
+	if len(status.PackagePolicyIDs) == 0 {
+		status.PackagePolicyIDs = nil
+	}
+	return status
+}
+
+// policyOwner returns the name of the FleetPolicy configuring the agent policy: the first one by name among the
+// FleetPolicy resources, not being deleted, with the same policy id on the same Kibana.
+func (r *policyKind) policyOwner(ctx context.Context, policy fleetv1alpha1.FleetPolicy) (string, error) {
+	var policies fleetv1alpha1.FleetPolicyList
+	if err := r.Client.List(ctx, &policies, client.InNamespace(policy.Namespace)); err != nil {
+		return "", err
+	}
+	kb := policy.Spec.KibanaRef.WithDefaultNamespace(policy.Namespace).NamespacedName()
+	var candidates []string
+	for _, other := range policies.Items {
+		if !other.IsMarkedForDeletion() && other.References(kb) && other.PolicyIDOrDefault() == policy.PolicyIDOrDefault() {
+			candidates = append(candidates, other.Name)
+		}
+	}
+	if len(candidates) == 0 {
+		return policy.Name, nil
+	}
+	sort.Strings(candidates)
+	return candidates[0], nil
+}
+
+// installPackages installs the packages of the integrations that are not installed yet.
+func installPackages(ctx context.Context, kbClient kbclient.Client, packagePolicies []fleetv1alpha1.PackagePolicy) error {
+	installed := make(map[fleetv1alpha1.Package]bool, len(packagePolicies))
+	for _, packagePolicy := range packagePolicies {
+		pkg := packagePolicy.Package
+		if installed[pkg] {
+			continue
+		}
+		status, err := kbClient.GetPackageStatus(ctx, pkg.Name, pkg.Version)
+		if err != nil {
+			return err
+		}
+		if status != kbclient.PackageInstalledStatus {
+			ulog.FromContext(ctx).Info("Installing package", "package", pkg.Name, "version", pkg.Version)
+			if err := kbClient.InstallPackage(ctx, pkg.Name, pkg.Version); err != nil {
+				return err
+			}
+		}
+		installed[pkg] = true
+	}
+	return nil
+}
+
+// reconcileAgentPolicy creates the agent policy if it does not exist, and updates it if it differs from the expected
+// definition. It returns true if the policy was missing or different.
+func reconcileAgentPolicy(ctx context.Context, kbClient kbclient.Client, id string, expected map[string]interface{}) (bool, error) {
+	log := ulog.FromContext(ctx)
+	actual, err := kbClient.GetAgentPolicy(ctx, id)
+	if kbclient.IsNotFound(err) {
+		log.Info("Creating agent policy", "policy_id", id)
+		return true, kbClient.CreateAgentPolicy(ctx, id, expected)
+	}
+	if err != nil {
+		return false, err
+	}
+	if contains(expected, actual) {
+		return false, nil
+	}
+	log.Info("Updating agent policy", "policy_id", id)
+	return true, kbClient.UpdateAgentPolicy(ctx, id, expected)
+}
+
+// reconcilePackagePolicy creates the integration if it does not exist, recreates it if its package changed, and updates
+// it if it differs from the expected definition. It returns true if the integration was missing or different.
+func reconcilePackagePolicy(ctx context.Context, kbClient kbclient.Client, id string, expected map[string]interface{}) (bool, error) {
+	log := ulog.FromContext(ctx)
+	actual, err := kbClient.GetPackagePolicy(ctx, id)
+	if kbclient.IsNotFound(err) {
+		log.Info("Creating integration", "package_policy_id", id)
+		return true, kbClient.CreatePackagePolicy(ctx, id, expected)
+	}
+	if err != nil {
+		return false, err
+	}
+	if !contains(expected["package"], actual["package"]) {
+		// integrations are upgraded to another version of their package by recreating them
+		log.Info("Recreating integration", "package_policy_id", id)
+		if err := kbClient.DeletePackagePolicy(ctx, id); err != nil {
+			return false, err
+		}
+		return true, kbClient.CreatePackagePolicy(ctx, id, expected)
+	}
+	if contains(expected, actual) {
+		return false, nil
+	}
+	log.Info("Updating integration", "package_policy_id", id)
+	return true, kbClient.UpdatePackagePolicy(ctx, id, expected)
+}
+
+// deletePolicy deletes the agent policy, and with it its integrations, from Fleet. A policy configured by another
+// FleetPolicy is left untouched.
+func (r *policyKind) deletePolicy(ctx context.Context, policy fleetv1alpha1.FleetPolicy) error {
+	defer tracing.Span(&ctx)()
+
+	owner, err := r.policyOwner(ctx, policy)
+	if err != nil {
+		return err
+	}
+	if owner != policy.Name {
+		return nil
+	}
+
+	var kb kbv1.Kibana
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: policy.Namespace, Name: policy.Spec.KibanaRef.Name}, &kb); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	kbClient, err := r.kbClientProvider(ctx, r.Client, r.params.Dialer, kb)
+	if err != nil {
+		return err
+	}
+	defer kbClient.Close()
+
+	id := policy.PolicyIDOrDefault()
+	ulog.FromContext(ctx).Info("Deleting agent policy", "policy_id", id, "kibana_name", kb.Name)
+	if err := kbClient.DeleteAgentPolicy(ctx, id); err != nil && !kbclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// enrolledAgents returns the sorted namespaced names of the Agents in Fleet mode enrolled in the policy through its
+// Kibana.
+func (r *policyKind) enrolledAgents(ctx context.Context, policy fleetv1alpha1.FleetPolicy) ([]string, error) {
+	var agents agentv1alpha1.AgentList
+	if err := r.Client.List(ctx, &agents); err != nil {
+		return nil, err
+	}
+	kb := policy.Spec.KibanaRef.WithDefaultNamespace(policy.Namespace).NamespacedName()
+	var names []string
+	for _, agent := range agents.Items {
+		if agent.Spec.FleetModeEnabled() && agent.Spec.KibanaRef.IsDefined() &&
+			agent.Spec.KibanaRef.WithDefaultNamespace(agent.Namespace).NamespacedName() == kb &&
+			agent.Spec.PolicyID == policy.PolicyIDOrDefault() {
+			names = append(names, types.NamespacedName{Namespace: agent.Namespace, Name: agent.Name}.String())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// agentPolicyDefinition returns the definition of the agent policy of the specification, with its default name and
+// namespace.
+func agentPolicyDefinition(policy fleetv1alpha1.FleetPolicy) map[string]interface{} {
+	definition := map[string]interface{}{
+		"name":      policy.Name,
+		"namespace": fleetv1alpha1.DefaultPolicyNamespace,
+	}
+	if policy.Spec.Policy != nil {
+		for k, v := range policy.Spec.Policy.Data {
+			definition[k] = v
+		}
+	}
+	return definition
+}
+
+// packagePolicyDefinition returns the definition of the integration of the specification, added to the agent policy.
+// Its name defaults to a name unique in Fleet, derived from the policy id.
+func packagePolicyDefinition(policy fleetv1alpha1.FleetPolicy, packagePolicy fleetv1alpha1.PackagePolicy) map[string]interface{} {
+	definition := map[string]interface{}{
+		"name": policy.PackagePolicyID(packagePolicy.Name),
+	}
+	if packagePolicy.Config != nil {
+		for k, v := range packagePolicy.Config.Data {
+			definition[k] = v
+		}
+	}
+	definition["policy_id"] = policy.PolicyIDOrDefault()
+	definition["package"] = map[string]interface{}{
+		"name":    packagePolicy.Package.Name,
+		"version": packagePolicy.Package.Version,
+	}
+	return definition
+}
+
+// contains returns true if the expected value is a subset of the actual value returned by Fleet, which adds defaults
+// and server-side fields to agent policies and integrations.
+func contains(expected, actual interface{}) bool {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualMap, ok := actual.(map[string]interface{})
+		if !ok {
+			return len(expectedValue) == 0 && actual == nil
+		}
+		for k, v := range expectedValue {
+			if !contains(v, actualMap[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		actualSlice, ok := actual.([]interface{})
+		if !ok || len(actualSlice) != len(expectedValue) {
+			return len(expectedValue) == 0 && actual == nil
+		}
+		for i := range expectedValue {
+			if !contains(expectedValue[i], actualSlice[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		// compare the JSON representations of the values to ignore the differences of numeric types
+		expectedBytes, err := json.Marshal(expected)
+		if err != nil {
+			return false
+		}
+		actualBytes, err := json.Marshal(actual)
+		if err != nil {
+			return false
+		}
+		return string(expectedBytes) == string(actualBytes)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ilmpolicy
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	ilmv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/ilm/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	controllerName = "ilmpolicy-controller"
+)
+
+// config identifies the ILMPolicy controller. Deleted policies stay configured in Elasticsearch, as indices may still
+// use them.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "ILMPolicy",
+	NameField:      "policy_name",
+}
+
+// Add creates a new ILMPolicy Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Elasticsearch and reconcile the ILMPolicy resources referencing them
+		source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestForPolicies(r.Client)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of ILMPolicy.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*ilmv1alpha1.ILMPolicy, ilmv1alpha1.ILMPolicyStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &policyKind{
+		Client:           c,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+// reconcileRequestForPolicies returns the requests to reconcile the ILMPolicy resources referencing the watched
+// Elasticsearch cluster.
+func reconcileRequestForPolicies(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &ilmv1alpha1.ILMPolicyList{}, func(policy *ilmv1alpha1.ILMPolicy, obj client.Object) bool {
+		return policy.References(k8s.ExtractNamespacedName(obj))
+	})
+}
+
+// policyKind configures ILMPolicy resources in Elasticsearch.
+type policyKind struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var _ apiresource.Kind[*ilmv1alpha1.ILMPolicy, ilmv1alpha1.ILMPolicyStatus] = &policyKind{}
+
+func (r *policyKind) NewObject() *ilmv1alpha1.ILMPolicy {
+	return &ilmv1alpha1.ILMPolicy{}
+}
+
+func (r *policyKind) GetStatus(policy *ilmv1alpha1.ILMPolicy) ilmv1alpha1.ILMPolicyStatus {
+	return policy.Status
+}
+
+func (r *policyKind) SetStatus(policy *ilmv1alpha1.ILMPolicy, status ilmv1alpha1.ILMPolicyStatus) {
+	policy.Status = status
+}
+
+func (r *policyKind) InvalidStatus(policy *ilmv1alpha1.ILMPolicy, _ error) ilmv1alpha1.ILMPolicyStatus {
+	status := ilmv1alpha1.NewStatus(*policy)
+	status.Phase = ilmv1alpha1.InvalidPhase
+	return status
+}
+
+// Configure configures the policy on the referenced Elasticsearch clusters, and deletes it from the clusters that are
+// no longer referenced.
+func (r *policyKind) Configure(ctx context.Context, obj *ilmv1alpha1.ILMPolicy) (*reconciler.Results, ilmv1alpha1.ILMPolicyStatus) {
+	policy := *obj
+	log := ulog.FromContext(ctx)
+	results := reconciler.NewResult(ctx)
+	status := ilmv1alpha1.NewStatus(policy)
+
+	// configure the policy on the referenced Elasticsearch clusters
+	referenced := make(map[string]struct{}, len(policy.Spec.ElasticsearchRefs))
+	for _, ref := range policy.Spec.ElasticsearchRefs {
+		referenced[ref.Name] = struct{}{}
+		status.SetElasticsearchStatus(ref.Name, r.reconcileElasticsearch(ctx, policy, ref.Name))
+	}
+
+	// delete the policy from the clusters that are no longer referenced
+	for esName := range policy.Status.Details {
+		if _, exists := referenced[esName]; exists {
+			continue
+		}
+		if err := r.deletePolicy(ctx, policy, esName); err != nil {
+			log.Error(err, "Failed to delete the ILM policy", "es_name", esName)
+			status.SetElasticsearchStatus(esName, ilmv1alpha1.ElasticsearchPolicyStatus{
+				Phase:   ilmv1alpha1.ApplyingChangesPhase,
+				Message: "Failed to delete the policy: " + err.Error(),
+			})
+		}
+	}
+
+	results.WithResult(apiresource.Requeue(status.Phase == ilmv1alpha1.ReadyPhase))
+
+	return results, status
+}
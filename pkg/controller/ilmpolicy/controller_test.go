@@ -0,0 +1,201 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ilmpolicy
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	ilmv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/ilm/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeEsClient stores ILM policies in memory, setting the defaults Elasticsearch sets.
+type fakeEsClient struct {
+	esclient.Client
+
+	policies map[string]esclient.ILMPolicyInfo
+	updates  *int
+}
+
+func (c fakeEsClient) GetILMPolicy(_ context.Context, name string) (esclient.ILMPolicyInfo, error) {
+	policy, exists := c.policies[name]
+	if !exists {
+		return esclient.ILMPolicyInfo{}, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return policy, nil
+}
+
+func (c fakeEsClient) UpdateILMPolicy(_ context.Context, name string, policy map[string]interface{}) error {
+	*c.updates++
+	info := c.policies[name]
+	info.Version++
+	info.Policy = withDefaults(policy)
+	c.policies[name] = info
+	return nil
+}
+
+func (c fakeEsClient) DeleteILMPolicy(_ context.Context, name string) error {
+	if _, exists := c.policies[name]; !exists {
+		return &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	delete(c.policies, name)
+	return nil
+}
+
+func (c fakeEsClient) Close() {}
+
+type fakeElasticsearch struct {
+	policies map[string]esclient.ILMPolicyInfo
+	updates  int
+}
+
+func fakeClientProvider(clusters map[string]*fakeElasticsearch) commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, es esv1.Elasticsearch) (esclient.Client, error) {
+		cluster := clusters[es.Name]
+		return fakeEsClient{policies: cluster.policies, updates: &cluster.updates}, nil
+	}
+}
+
+func logsPolicy(deleteAfter string) map[string]interface{} {
+	return map[string]interface{}{
+		"phases": map[string]interface{}{
+			"hot":    map[string]interface{}{"actions": map[string]interface{}{"rollover": map[string]interface{}{"max_age": "1d"}}},
+			"delete": map[string]interface{}{"min_age": deleteAfter, "actions": map[string]interface{}{"delete": map[string]interface{}{}}},
+		},
+	}
+}
+
+func TestReconcileILMPolicy_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	policy := &ilmv1alpha1.ILMPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "logs"},
+		Spec: ilmv1alpha1.ILMPolicySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es1"}, {Name: "es2"}},
+			Policy:            &commonv1.Config{Data: logsPolicy("30d")},
+		},
+	}
+	es1 := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es1"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	es2 := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es2"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	clusters := map[string]*fakeElasticsearch{
+		"es1": {policies: map[string]esclient.ILMPolicyInfo{}},
+		"es2": {policies: map[string]esclient.ILMPolicyInfo{}},
+	}
+	k8sClient := k8s.NewFakeClient(policy, es1, es2)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &policyKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(clusters),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "logs"}}
+	getPolicy := func() ilmv1alpha1.ILMPolicy {
+		var actual ilmv1alpha1.ILMPolicy
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+
+	// the policy is configured on both clusters
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	status := getPolicy().Status
+	require.Equal(t, ilmv1alpha1.ReadyPhase, status.Phase)
+	require.Equal(t, "2/2", status.ReadyCount)
+	require.Equal(t, 1, clusters["es1"].updates)
+	require.Equal(t, 1, clusters["es2"].updates)
+
+	// the policy is not updated if it did not change, its usage is reported
+	info := clusters["es1"].policies["logs"]
+	info.InUseBy = esclient.ILMPolicyUsage{Indices: []string{".ds-logs-000001", ".ds-logs-000002"}, DataStreams: []string{"logs"}}
+	clusters["es1"].policies["logs"] = info
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 1, clusters["es1"].updates)
+	require.Equal(t, &ilmv1alpha1.PolicyUsage{Indices: 2, DataStreams: 1}, getPolicy().Status.Details["es1"].InUseBy)
+
+	// the policy is restored if it is modified in Elasticsearch
+	info = clusters["es1"].policies["logs"]
+	info.Policy = withDefaults(logsPolicy("7d"))
+	clusters["es1"].policies["logs"] = info
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 2, clusters["es1"].updates)
+	require.Equal(t, withDefaults(logsPolicy("30d")), clusters["es1"].policies["logs"].Policy)
+	status = getPolicy().Status
+	require.NotNil(t, status.Details["es1"].LastDriftTime)
+	require.Nil(t, status.Details["es2"].LastDriftTime)
+	require.Contains(t, <-recorder.Events, "ILM policy logs was modified in Elasticsearch es1")
+
+	// the policy is updated when its specification changes
+	actual := getPolicy()
+	actual.Generation++
+	actual.Spec.Policy = &commonv1.Config{Data: logsPolicy("60d")}
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 3, clusters["es1"].updates)
+	require.Equal(t, 2, clusters["es2"].updates)
+	require.Empty(t, recorder.Events)
+
+	// the policy is not deleted from the clusters that are no longer referenced while it is in use
+	actual = getPolicy()
+	actual.Spec.ElasticsearchRefs = []commonv1.LocalObjectSelector{{Name: "es1"}}
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	info = clusters["es2"].policies["logs"]
+	info.InUseBy = esclient.ILMPolicyUsage{ComposableTemplates: []string{"logs"}}
+	clusters["es2"].policies["logs"] = info
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	status = getPolicy().Status
+	require.Equal(t, "1/1", status.ReadyCount)
+	require.NotContains(t, status.Details, "es2")
+	require.Contains(t, clusters["es2"].policies, "logs")
+	require.Contains(t, <-recorder.Events, "ILM policy logs is not deleted from Elasticsearch es2 as it is used by 0 indices, 0 data streams and 1 index templates")
+
+	// the policy is deleted from the clusters that are no longer referenced if it is not in use
+	info = clusters["es1"].policies["logs"]
+	info.InUseBy = esclient.ILMPolicyUsage{}
+	clusters["es1"].policies["logs"] = info
+	actual = getPolicy()
+	actual.Spec.ElasticsearchRefs = []commonv1.LocalObjectSelector{{Name: "es2"}}
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, clusters["es1"].policies)
+}
+
+func Test_samePolicy(t *testing.T) {
+	// Elasticsearch sets a default min_age on the phases that do not specify it
+	require.True(t, samePolicy(logsPolicy("30d"), map[string]interface{}{
+		"phases": map[string]interface{}{
+			"hot":    map[string]interface{}{"min_age": "0ms", "actions": map[string]interface{}{"rollover": map[string]interface{}{"max_age": "1d"}}},
+			"delete": map[string]interface{}{"min_age": "30d", "actions": map[string]interface{}{"delete": map[string]interface{}{}}},
+		},
+	}))
+	require.False(t, samePolicy(logsPolicy("30d"), withDefaults(logsPolicy("7d"))))
+	// the given policy is not modified
+	require.NotContains(t, logsPolicy("30d")["phases"].(map[string]interface{})["hot"], "min_age")
+}
@@ -0,0 +1,124 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ilmpolicy
+
+import (
+	"fmt"
+	"sort"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+var (
+	// phaseMinVersions are the phases of a policy with the first version of Elasticsearch supporting them.
+	phaseMinVersions = map[string]version.Version{
+		"hot":    version.MinFor(6, 6, 0),
+		"warm":   version.MinFor(6, 6, 0),
+		"cold":   version.MinFor(6, 6, 0),
+		"frozen": version.MinFor(7, 12, 0),
+		"delete": version.MinFor(6, 6, 0),
+	}
+	// phaseActions are the ILM actions allowed in each phase of a policy with the first version of Elasticsearch
+	// supporting them. Actions unknown to all phases are not rejected, to let Elasticsearch validate actions introduced
+	// by newer versions.
+	phaseActions = map[string]map[string]version.Version{
+		"hot": {
+			"set_priority":        version.MinFor(6, 7, 0),
+			"unfollow":            version.MinFor(6, 7, 0),
+			"rollover":            version.MinFor(6, 6, 0),
+			"read_only":           version.MinFor(6, 6, 0),
+			"shrink":              version.MinFor(6, 6, 0),
+			"forcemerge":          version.MinFor(6, 6, 0),
+			"searchable_snapshot": version.MinFor(7, 10, 0),
+			"downsample":          version.MinFor(8, 5, 0),
+		},
+		"warm": {
+			"set_priority": version.MinFor(6, 7, 0),
+			"unfollow":     version.MinFor(6, 7, 0),
+			"read_only":    version.MinFor(6, 6, 0),
+			"downsample":   version.MinFor(8, 5, 0),
+			"allocate":     version.MinFor(6, 6, 0),
+			"migrate":      version.MinFor(7, 10, 0),
+			"shrink":       version.MinFor(6, 6, 0),
+			"forcemerge":   version.MinFor(6, 6, 0),
+		},
+		"cold": {
+			"set_priority":        version.MinFor(6, 7, 0),
+			"unfollow":            version.MinFor(6, 7, 0),
+			"read_only":           version.MinFor(6, 6, 0),
+			"downsample":          version.MinFor(8, 5, 0),
+			"searchable_snapshot": version.MinFor(7, 10, 0),
+			"allocate":            version.MinFor(6, 6, 0),
+			"migrate":             version.MinFor(7, 10, 0),
+			"freeze":              version.MinFor(6, 6, 0),
+		},
+		"frozen": {
+			"unfollow":            version.MinFor(7, 12, 0),
+			"searchable_snapshot": version.MinFor(7, 12, 0),
+		},
+		"delete": {
+			"wait_for_snapshot": version.MinFor(7, 6, 0),
+			"delete":            version.MinFor(6, 6, 0),
+		},
+	}
+	knownActions = func() set.StringSet {
+		actions := set.Make()
+		for _, phase := range phaseActions {
+			actions.MergeWith(set.Make(sortedKeys(phase)...))
+		}
+		return actions
+	}()
+)
+
+// checkPhases returns a message explaining why the given policy cannot be configured on the Elasticsearch cluster,
+// because of a phase or an action the version of the cluster does not support, or an empty string if it can. Nothing
+// is checked while the version of the cluster is not known.
+func checkPhases(policy map[string]interface{}, es esv1.Elasticsearch) (string, error) {
+	esVersion := es.Status.Version
+	if esVersion == "" {
+		esVersion = es.Spec.Version
+	}
+	if esVersion == "" {
+		return "", nil
+	}
+	ver, err := version.Parse(esVersion)
+	if err != nil {
+		return "", err
+	}
+
+	phases, _ := policy["phases"].(map[string]interface{})
+	for _, name := range sortedKeys(phases) {
+		minVersion, known := phaseMinVersions[name]
+		if !known {
+			return fmt.Sprintf("Phase %s is not supported, supported phases are %v", name, sortedKeys(phaseMinVersions)), nil
+		}
+		if ver.LT(minVersion) {
+			return fmt.Sprintf("Phase %s requires Elasticsearch %s or later, Elasticsearch %s runs %s", name, version.WithoutPre(minVersion), es.Name, ver), nil
+		}
+		phase, _ := phases[name].(map[string]interface{})
+		actions, _ := phase["actions"].(map[string]interface{})
+		for _, action := range sortedKeys(actions) {
+			minVersion, allowed := phaseActions[name][action]
+			switch {
+			case !allowed && knownActions.Has(action):
+				return fmt.Sprintf("Action %s is not allowed in the %s phase", action, name), nil
+			case allowed && ver.LT(minVersion):
+				return fmt.Sprintf("Action %s of the %s phase requires Elasticsearch %s or later, Elasticsearch %s runs %s", action, name, version.WithoutPre(minVersion), es.Name, ver), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
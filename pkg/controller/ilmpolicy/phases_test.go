@@ -0,0 +1,86 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ilmpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_checkPhases(t *testing.T) {
+	phases := func(phases map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"phases": phases}
+	}
+	withActions := func(actions ...string) map[string]interface{} {
+		phase := map[string]interface{}{}
+		for _, action := range actions {
+			phase[action] = map[string]interface{}{}
+		}
+		return map[string]interface{}{"actions": phase}
+	}
+	tests := []struct {
+		name          string
+		policy        map[string]interface{}
+		specVersion   string
+		statusVersion string
+		want          string
+	}{
+		{
+			name:   "version not known yet",
+			policy: phases(map[string]interface{}{"lukewarm": withActions("delete")}),
+		},
+		{
+			name:        "supported phases and actions",
+			policy:      logsPolicy("30d"),
+			specVersion: "8.15.0",
+		},
+		{
+			name:        "unknown phase",
+			policy:      phases(map[string]interface{}{"lukewarm": withActions("delete")}),
+			specVersion: "8.15.0",
+			want:        "Phase lukewarm is not supported, supported phases are [cold delete frozen hot warm]",
+		},
+		{
+			name:        "action not allowed in the phase",
+			policy:      phases(map[string]interface{}{"hot": withActions("rollover", "delete")}),
+			specVersion: "8.15.0",
+			want:        "Action delete is not allowed in the hot phase",
+		},
+		{
+			name:        "action unknown to all phases left to Elasticsearch",
+			policy:      phases(map[string]interface{}{"hot": withActions("rollover", "future_action")}),
+			specVersion: "8.15.0",
+		},
+		{
+			name:        "phase not supported by the version",
+			policy:      phases(map[string]interface{}{"frozen": withActions("searchable_snapshot")}),
+			specVersion: "7.11.2",
+			want:        "Phase frozen requires Elasticsearch 7.12.0 or later, Elasticsearch es runs 7.11.2",
+		},
+		{
+			name:          "action not supported by the lowest running version",
+			policy:        phases(map[string]interface{}{"warm": withActions("downsample")}),
+			specVersion:   "8.5.0",
+			statusVersion: "8.4.3",
+			want:          "Action downsample of the warm phase requires Elasticsearch 8.5.0 or later, Elasticsearch es runs 8.4.3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+				Spec:       esv1.ElasticsearchSpec{Version: tt.specVersion},
+				Status:     esv1.ElasticsearchStatus{Version: tt.statusVersion},
+			}
+			got, err := checkPhases(tt.policy, es)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ilmpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	ilmv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/ilm/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// defaultMinAge is the min_age Elasticsearch sets on the phases of a policy that do not specify it.
+const defaultMinAge = "0ms"
+
+// reconcileElasticsearch configures the policy on the given Elasticsearch cluster if it does not exist yet or if it
+// differs from the specification, and returns the status of the policy for this cluster.
+func (r *policyKind) reconcileElasticsearch(ctx context.Context, policy ilmv1alpha1.ILMPolicy, esName string) ilmv1alpha1.ElasticsearchPolicyStatus {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx).WithValues("es_name", esName)
+
+	es, phase, msg := apiresource.ReadyElasticsearch(ctx, r.Client, types.NamespacedName{Namespace: policy.Namespace, Name: esName})
+	if phase != ilmv1alpha1.ReadyPhase {
+		return ilmv1alpha1.ElasticsearchPolicyStatus{Phase: phase, Message: msg}
+	}
+	// the phases and actions supported by Elasticsearch depend on its version
+	msg, err := checkPhases(policy.Spec.Policy.Data, es)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	if msg != "" {
+		r.recorder.Event(&policy, corev1.EventTypeWarning, events.EventReasonValidation, msg)
+		return ilmv1alpha1.ElasticsearchPolicyStatus{Phase: ilmv1alpha1.InvalidPhase, Message: msg}
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	defer esClient.Close()
+
+	name := policy.PolicyNameOrDefault()
+	previous := policy.Status.Details[esName]
+	status := ilmv1alpha1.ElasticsearchPolicyStatus{Phase: ilmv1alpha1.ReadyPhase, LastDriftTime: previous.LastDriftTime}
+	expected := policy.Spec.Policy.Data
+	actual, err := esClient.GetILMPolicy(ctx, name)
+	if err != nil && !esclient.IsNotFound(err) {
+		return applyingChangesStatus(err.Error())
+	}
+	if err == nil {
+		status.InUseBy = policyUsage(actual.InUseBy)
+		if samePolicy(expected, actual.Policy) {
+			return status
+		}
+		// the policy was already configured from the current specification, it was modified outside of the operator
+		if previous.Phase == ilmv1alpha1.ReadyPhase && policy.Status.ObservedGeneration == policy.Generation {
+			msg := fmt.Sprintf("ILM policy %s was modified in Elasticsearch %s, restoring it from the ILMPolicy specification", name, esName)
+			status.LastDriftTime = apiresource.RecordDrift(log, r.recorder, &policy, msg)
+		}
+	}
+
+	log.Info("Updating ILM policy", "policy", name)
+	if err := esClient.UpdateILMPolicy(ctx, name, expected); err != nil {
+		msg := fmt.Sprintf("Failed to update ILM policy %s on Elasticsearch %s: %s", name, esName, esclient.ErrorReason(err))
+		r.recorder.Event(&policy, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return errorStatus(msg)
+	}
+	return status
+}
+
+// deletePolicy deletes the policy from the given Elasticsearch cluster, unless indices, data streams or index templates
+// still use it in which case it is left in Elasticsearch.
+func (r *policyKind) deletePolicy(ctx context.Context, policy ilmv1alpha1.ILMPolicy, esName string) error {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx).WithValues("es_name", esName)
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: policy.Namespace, Name: esName}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+
+	name := policy.PolicyNameOrDefault()
+	actual, err := esClient.GetILMPolicy(ctx, name)
+	if err != nil {
+		if esclient.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !actual.InUseBy.IsEmpty() {
+		usage := policyUsage(actual.InUseBy)
+		msg := fmt.Sprintf(
+			"ILM policy %s is not deleted from Elasticsearch %s as it is used by %d indices, %d data streams and %d index templates",
+			name, esName, usage.Indices, usage.DataStreams, usage.ComposableTemplates,
+		)
+		log.Info(msg)
+		r.recorder.Event(&policy, corev1.EventTypeWarning, events.EventReasonDelayed, msg)
+		return nil
+	}
+
+	log.Info("Deleting ILM policy", "policy", name)
+	if err := esClient.DeleteILMPolicy(ctx, name); err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// samePolicy compares the JSON representations of the expected policy, completed with the defaults set by
+// Elasticsearch, and of the policy configured in Elasticsearch.
+func samePolicy(expected, actual map[string]interface{}) bool {
+	expectedBytes, err := json.Marshal(withDefaults(expected))
+	if err != nil {
+		return false
+	}
+	actualBytes, err := json.Marshal(actual)
+	if err != nil {
+		return false
+	}
+	return string(expectedBytes) == string(actualBytes)
+}
+
+// withDefaults returns a copy of the given policy with the default min_age set on the phases that do not specify it.
+func withDefaults(policy map[string]interface{}) map[string]interface{} {
+	phases, ok := policy["phases"].(map[string]interface{})
+	if !ok {
+		return policy
+	}
+	withDefaults := make(map[string]interface{}, len(policy))
+	for k, v := range policy {
+		withDefaults[k] = v
+	}
+	phasesWithDefaults := make(map[string]interface{}, len(phases))
+	for name, phase := range phases {
+		phaseWithDefaults := map[string]interface{}{"min_age": defaultMinAge}
+		if phase, ok := phase.(map[string]interface{}); ok {
+			for k, v := range phase {
+				phaseWithDefaults[k] = v
+			}
+		}
+		phasesWithDefaults[name] = phaseWithDefaults
+	}
+	withDefaults["phases"] = phasesWithDefaults
+	return withDefaults
+}
+
+func policyUsage(inUseBy esclient.ILMPolicyUsage) *ilmv1alpha1.PolicyUsage {
+	if inUseBy.IsEmpty() {
+		return nil
+	}
+	return &ilmv1alpha1.PolicyUsage{
+		Indices:             len(inUseBy.Indices),
+		DataStreams:         len(inUseBy.DataStreams),
+		ComposableTemplates: len(inUseBy.ComposableTemplates),
+	}
+}
+
+func applyingChangesStatus(msg string) ilmv1alpha1.ElasticsearchPolicyStatus {
+	return ilmv1alpha1.ElasticsearchPolicyStatus{Phase: ilmv1alpha1.ApplyingChangesPhase, Message: msg}
+}
+
+func errorStatus(msg string) ilmv1alpha1.ElasticsearchPolicyStatus {
+	return ilmv1alpha1.ElasticsearchPolicyStatus{Phase: ilmv1alpha1.ErrorPhase, Message: msg}
+}
@@ -0,0 +1,176 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package indextemplate
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	indexv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/index/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	controllerName = "indextemplate-controller"
+
+	// TemplateFinalizer lets the operator delete the template from Elasticsearch before the IndexTemplate resource is
+	// deleted, unless it is still in use.
+	TemplateFinalizer = "index.k8s.elastic.co/delete-template"
+)
+
+// config identifies the IndexTemplate controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "IndexTemplate",
+	NameField:      "template_name",
+	Finalizer:      TemplateFinalizer,
+}
+
+// Add creates a new IndexTemplate Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Elasticsearch and reconcile the IndexTemplate resources selecting them
+		source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestForTemplates(r.Client)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of IndexTemplate.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*indexv1alpha1.IndexTemplate, indexv1alpha1.IndexTemplateStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &templateKind{
+		Client:           c,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+// reconcileRequestForTemplates returns the requests to reconcile the IndexTemplate resources selecting the watched
+// Elasticsearch cluster, or configured on it before its labels changed.
+func reconcileRequestForTemplates(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &indexv1alpha1.IndexTemplateList{}, func(template *indexv1alpha1.IndexTemplate, obj client.Object) bool {
+		_, configured := template.Status.Details[obj.GetName()]
+		return configured || template.Selects(k8s.ExtractNamespacedName(obj), obj.GetLabels())
+	})
+}
+
+// templateKind configures IndexTemplate resources in Elasticsearch.
+type templateKind struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*indexv1alpha1.IndexTemplate, indexv1alpha1.IndexTemplateStatus] = &templateKind{}
+	_ apiresource.Remover[*indexv1alpha1.IndexTemplate]                                 = &templateKind{}
+)
+
+func (r *templateKind) NewObject() *indexv1alpha1.IndexTemplate {
+	return &indexv1alpha1.IndexTemplate{}
+}
+
+func (r *templateKind) GetStatus(template *indexv1alpha1.IndexTemplate) indexv1alpha1.IndexTemplateStatus {
+	return template.Status
+}
+
+func (r *templateKind) SetStatus(template *indexv1alpha1.IndexTemplate, status indexv1alpha1.IndexTemplateStatus) {
+	template.Status = status
+}
+
+func (r *templateKind) InvalidStatus(template *indexv1alpha1.IndexTemplate, _ error) indexv1alpha1.IndexTemplateStatus {
+	status := indexv1alpha1.NewStatus(*template)
+	status.Phase = indexv1alpha1.InvalidPhase
+	return status
+}
+
+// Configure configures the template on the selected Elasticsearch clusters, and deletes it from the clusters that are
+// no longer selected.
+func (r *templateKind) Configure(ctx context.Context, obj *indexv1alpha1.IndexTemplate) (*reconciler.Results, indexv1alpha1.IndexTemplateStatus) {
+	template := *obj
+	log := ulog.FromContext(ctx)
+	results := reconciler.NewResult(ctx)
+	status := indexv1alpha1.NewStatus(template)
+
+	esNames, err := r.selectedClusters(ctx, template)
+	if err != nil {
+		return results.WithError(err), template.Status
+	}
+
+	// configure the template on the referenced and selected Elasticsearch clusters
+	for _, esName := range esNames {
+		status.SetElasticsearchStatus(esName, r.reconcileElasticsearch(ctx, template, esName))
+	}
+
+	// delete the template from the clusters that are no longer referenced or selected
+	selected := set.Make(esNames...)
+	for esName := range template.Status.Details {
+		if selected.Has(esName) {
+			continue
+		}
+		if err := r.deleteTemplate(ctx, template, esName); err != nil {
+			log.Error(err, "Failed to delete the template", "es_name", esName)
+			status.SetElasticsearchStatus(esName, indexv1alpha1.ElasticsearchTemplateStatus{
+				Phase:   indexv1alpha1.ApplyingChangesPhase,
+				Message: "Failed to delete the template: " + err.Error(),
+			})
+		}
+	}
+
+	results.WithResult(apiresource.Requeue(status.Phase == indexv1alpha1.ReadyPhase))
+
+	return results, status
+}
+
+// Remove deletes the template from all the clusters it is configured on, except the ones where it is still in use.
+func (r *templateKind) Remove(ctx context.Context, obj *indexv1alpha1.IndexTemplate) (reconcile.Result, error) {
+	template := *obj
+	for esName := range template.Status.Details {
+		if err := r.deleteTemplate(ctx, template, esName); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// selectedClusters returns the sorted names of the Elasticsearch clusters referenced by the template, whether they exist
+// or not, and of the existing clusters matching its selector.
+func (r *templateKind) selectedClusters(ctx context.Context, template indexv1alpha1.IndexTemplate) ([]string, error) {
+	names := set.Make()
+	for _, ref := range template.Spec.ElasticsearchRefs {
+		names.Add(ref.Name)
+	}
+	if template.Spec.ElasticsearchSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(template.Spec.ElasticsearchSelector)
+		if err != nil {
+			return nil, err
+		}
+		var clusters esv1.ElasticsearchList
+		if err := r.Client.List(ctx, &clusters, client.InNamespace(template.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+		for _, es := range clusters.Items {
+			names.Add(es.Name)
+		}
+	}
+	return names.AsSortedSlice(), nil
+}
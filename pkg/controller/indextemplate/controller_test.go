@@ -0,0 +1,312 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package indextemplate
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	indexv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/index/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeEsClient stores index templates, component templates and data streams in memory.
+type fakeEsClient struct {
+	esclient.Client
+	cluster *fakeElasticsearch
+}
+
+func (c fakeEsClient) GetIndexTemplates(_ context.Context) (map[string]map[string]interface{}, error) {
+	return c.cluster.indexTemplates, nil
+}
+
+func (c fakeEsClient) GetIndexTemplate(_ context.Context, name string) (map[string]interface{}, error) {
+	template, exists := c.cluster.indexTemplates[name]
+	if !exists {
+		return nil, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return template, nil
+}
+
+func (c fakeEsClient) UpdateIndexTemplate(_ context.Context, name string, template map[string]interface{}) error {
+	c.cluster.updates++
+	c.cluster.indexTemplates[name] = template
+	return nil
+}
+
+func (c fakeEsClient) DeleteIndexTemplate(_ context.Context, name string) error {
+	if _, exists := c.cluster.indexTemplates[name]; !exists {
+		return &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	delete(c.cluster.indexTemplates, name)
+	return nil
+}
+
+func (c fakeEsClient) GetComponentTemplate(_ context.Context, name string) (map[string]interface{}, error) {
+	template, exists := c.cluster.componentTemplates[name]
+	if !exists {
+		return nil, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return template, nil
+}
+
+func (c fakeEsClient) UpdateComponentTemplate(_ context.Context, name string, template map[string]interface{}) error {
+	c.cluster.updates++
+	c.cluster.componentTemplates[name] = template
+	return nil
+}
+
+func (c fakeEsClient) DeleteComponentTemplate(_ context.Context, name string) error {
+	if _, exists := c.cluster.componentTemplates[name]; !exists {
+		return &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	delete(c.cluster.componentTemplates, name)
+	return nil
+}
+
+func (c fakeEsClient) GetDataStreams(_ context.Context) ([]esclient.DataStream, error) {
+	return c.cluster.dataStreams, nil
+}
+
+func (c fakeEsClient) Close() {}
+
+type fakeElasticsearch struct {
+	indexTemplates     map[string]map[string]interface{}
+	componentTemplates map[string]map[string]interface{}
+	dataStreams        []esclient.DataStream
+	updates            int
+}
+
+func newFakeElasticsearch() *fakeElasticsearch {
+	return &fakeElasticsearch{
+		indexTemplates:     map[string]map[string]interface{}{},
+		componentTemplates: map[string]map[string]interface{}{},
+	}
+}
+
+func fakeClientProvider(clusters map[string]*fakeElasticsearch) commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, es esv1.Elasticsearch) (esclient.Client, error) {
+		return fakeEsClient{cluster: clusters[es.Name]}, nil
+	}
+}
+
+func logsTemplate(priority float64) map[string]interface{} {
+	return map[string]interface{}{
+		"index_patterns": []interface{}{"logs-app-*"},
+		"data_stream":    map[string]interface{}{},
+		"priority":       priority,
+		"template": map[string]interface{}{
+			"settings": map[string]interface{}{"number_of_shards": float64(1), "index": map[string]interface{}{"lifecycle": map[string]interface{}{"name": "logs"}}},
+		},
+	}
+}
+
+func TestReconcileIndexTemplate_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	template := &indexv1alpha1.IndexTemplate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "logs-app"},
+		Spec: indexv1alpha1.IndexTemplateSpec{
+			ElasticsearchRefs:     []commonv1.LocalObjectSelector{{Name: "es1"}},
+			ElasticsearchSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			Template:              &commonv1.Config{Data: logsTemplate(500)},
+		},
+	}
+	es1 := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es1"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	es2 := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es2", Labels: map[string]string{"env": "prod"}},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	clusters := map[string]*fakeElasticsearch{"es1": newFakeElasticsearch(), "es2": newFakeElasticsearch()}
+	k8sClient := k8s.NewFakeClient(template, es1, es2)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &templateKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(clusters),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "logs-app"}}
+	getTemplate := func() indexv1alpha1.IndexTemplate {
+		var actual indexv1alpha1.IndexTemplate
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+
+	// the template is configured on the referenced and selected clusters
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	actual := getTemplate()
+	require.Equal(t, "2/2", actual.Status.ReadyCount)
+	require.Equal(t, []string{TemplateFinalizer}, actual.Finalizers)
+	require.Equal(t, logsTemplate(500), clusters["es1"].indexTemplates["logs-app"])
+	require.Equal(t, 1, clusters["es1"].updates)
+	require.Equal(t, 1, clusters["es2"].updates)
+
+	// the template returned by Elasticsearch with its defaults and flat settings is not updated, its usage is reported
+	clusters["es1"].indexTemplates["logs-app"] = map[string]interface{}{
+		"index_patterns": []interface{}{"logs-app-*"},
+		"composed_of":    []interface{}{},
+		"data_stream":    map[string]interface{}{"hidden": false, "allow_custom_routing": false},
+		"priority":       float64(500),
+		"template": map[string]interface{}{
+			"settings": map[string]interface{}{"index.number_of_shards": "1", "index.lifecycle.name": "logs"},
+		},
+	}
+	clusters["es1"].dataStreams = []esclient.DataStream{{Name: "logs-app-default", Template: "logs-app"}, {Name: "logs-other", Template: "logs"}}
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 1, clusters["es1"].updates)
+	require.Equal(t, &indexv1alpha1.TemplateUsage{DataStreams: 1}, getTemplate().Status.Details["es1"].InUseBy)
+	require.Empty(t, recorder.Events)
+
+	// the template is restored if it is modified in Elasticsearch
+	clusters["es2"].indexTemplates["logs-app"] = logsTemplate(100)
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, logsTemplate(500), clusters["es2"].indexTemplates["logs-app"])
+	require.NotNil(t, getTemplate().Status.Details["es2"].LastDriftTime)
+	require.Contains(t, <-recorder.Events, "Composable template logs-app was modified in Elasticsearch es2")
+
+	// the template is updated when its specification changes
+	actual = getTemplate()
+	actual.Generation++
+	actual.Spec.Template = &commonv1.Config{Data: logsTemplate(200)}
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, logsTemplate(200), clusters["es1"].indexTemplates["logs-app"])
+	require.Empty(t, recorder.Events)
+
+	// the template is deleted from Elasticsearch with the IndexTemplate, unless data streams still use it
+	actual = getTemplate()
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Contains(t, clusters["es1"].indexTemplates, "logs-app")
+	require.Empty(t, clusters["es2"].indexTemplates)
+	require.Contains(t, <-recorder.Events, "Composable template logs-app is not deleted from Elasticsearch es1 as it is used by 1 data streams")
+	err = k8sClient.Get(ctx, request.NamespacedName, &indexv1alpha1.IndexTemplate{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileIndexTemplate_ComponentTemplate(t *testing.T) {
+	ctx := context.Background()
+	template := &indexv1alpha1.IndexTemplate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "logs-mappings"},
+		Spec: indexv1alpha1.IndexTemplateSpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			Type:              indexv1alpha1.ComponentTemplateType,
+			Template: &commonv1.Config{Data: map[string]interface{}{
+				"template": map[string]interface{}{"mappings": map[string]interface{}{"properties": map[string]interface{}{"message": map[string]interface{}{"type": "text"}}}},
+			}},
+		},
+	}
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	cluster := newFakeElasticsearch()
+	k8sClient := k8s.NewFakeClient(template, es)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &templateKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(map[string]*fakeElasticsearch{"es": cluster}),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "logs-mappings"}}
+
+	// the component template is configured
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	require.Equal(t, template.Spec.Template.Data, cluster.componentTemplates["logs-mappings"])
+	require.Empty(t, cluster.indexTemplates)
+
+	// the usage of the component template through index templates is reported
+	cluster.indexTemplates["logs-app"] = map[string]interface{}{"index_patterns": []interface{}{"logs-app-*"}, "composed_of": []interface{}{"logs-mappings"}}
+	cluster.dataStreams = []esclient.DataStream{{Name: "logs-app-default", Template: "logs-app"}}
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	var actual indexv1alpha1.IndexTemplate
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+	require.Equal(t, &indexv1alpha1.TemplateUsage{DataStreams: 1, IndexTemplates: 1}, actual.Status.Details["es"].InUseBy)
+
+	// the component template is not deleted while index templates are composed of it
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Contains(t, cluster.componentTemplates, "logs-mappings")
+	require.Contains(t, <-recorder.Events, "Component template logs-mappings is not deleted from Elasticsearch es as it is used by 1 data streams and 1 index templates")
+}
+
+func Test_sameTemplate(t *testing.T) {
+	tests := []struct {
+		name         string
+		templateType indexv1alpha1.TemplateType
+		expected     map[string]interface{}
+		actual       map[string]interface{}
+		want         bool
+	}{
+		{
+			name:         "index template with defaults",
+			templateType: indexv1alpha1.ComposableTemplateType,
+			expected:     map[string]interface{}{"index_patterns": []interface{}{"logs-*"}, "data_stream": map[string]interface{}{"hidden": true}},
+			actual: map[string]interface{}{
+				"index_patterns": []interface{}{"logs-*"},
+				"composed_of":    []interface{}{},
+				"data_stream":    map[string]interface{}{"hidden": true, "allow_custom_routing": false},
+			},
+			want: true,
+		},
+		{
+			name:         "component template with nested and flat settings",
+			templateType: indexv1alpha1.ComponentTemplateType,
+			expected: map[string]interface{}{"template": map[string]interface{}{
+				"settings": map[string]interface{}{"index.refresh_interval": "5s", "number_of_replicas": float64(2)},
+			}},
+			actual: map[string]interface{}{"template": map[string]interface{}{
+				"settings": map[string]interface{}{"index.refresh_interval": "5s", "index.number_of_replicas": "2"},
+			}},
+			want: true,
+		},
+		{
+			name:         "different settings",
+			templateType: indexv1alpha1.ComponentTemplateType,
+			expected:     map[string]interface{}{"template": map[string]interface{}{"settings": map[string]interface{}{"number_of_replicas": float64(2)}}},
+			actual:       map[string]interface{}{"template": map[string]interface{}{"settings": map[string]interface{}{"index.number_of_replicas": "1"}}},
+			want:         false,
+		},
+		{
+			name:         "component template does not get index template defaults",
+			templateType: indexv1alpha1.ComponentTemplateType,
+			expected:     map[string]interface{}{"template": map[string]interface{}{}},
+			actual:       map[string]interface{}{"template": map[string]interface{}{}, "composed_of": []interface{}{}},
+			want:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, sameTemplate(tt.templateType, tt.expected, tt.actual))
+		})
+	}
+}
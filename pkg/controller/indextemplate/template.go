@@ -0,0 +1,257 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package indextemplate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	indexv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/index/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+// indexSettingsPrefix is the prefix Elasticsearch adds to the settings of a template that do not specify it.
+const indexSettingsPrefix = "index."
+
+// reconcileElasticsearch configures the template on the given Elasticsearch cluster if it does not exist yet or if it
+// differs from the specification, and returns the status of the template for this cluster.
+func (r *templateKind) reconcileElasticsearch(ctx context.Context, template indexv1alpha1.IndexTemplate, esName string) indexv1alpha1.ElasticsearchTemplateStatus {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx).WithValues("es_name", esName)
+
+	es, phase, msg := apiresource.ReadyElasticsearch(ctx, r.Client, types.NamespacedName{Namespace: template.Namespace, Name: esName})
+	if phase != indexv1alpha1.ReadyPhase {
+		return indexv1alpha1.ElasticsearchTemplateStatus{Phase: phase, Message: msg}
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	defer esClient.Close()
+
+	name := template.TemplateNameOrDefault()
+	templateType := template.TypeOrDefault()
+	previous := template.Status.Details[esName]
+	status := indexv1alpha1.ElasticsearchTemplateStatus{Phase: indexv1alpha1.ReadyPhase, LastDriftTime: previous.LastDriftTime}
+	expected := template.Spec.Template.Data
+	actual, err := getTemplate(ctx, esClient, templateType, name)
+	if err != nil && !esclient.IsNotFound(err) {
+		return applyingChangesStatus(err.Error())
+	}
+	if err == nil {
+		status.InUseBy, err = templateUsage(ctx, esClient, templateType, name)
+		if err != nil {
+			return applyingChangesStatus(err.Error())
+		}
+		if sameTemplate(templateType, expected, actual) {
+			return status
+		}
+		// the template was already configured from the current specification, it was modified outside of the operator
+		if previous.Phase == indexv1alpha1.ReadyPhase && template.Status.ObservedGeneration == template.Generation {
+			msg := fmt.Sprintf("%s template %s was modified in Elasticsearch %s, restoring it from the IndexTemplate specification", templateType, name, esName)
+			status.LastDriftTime = apiresource.RecordDrift(log, r.recorder, &template, msg)
+		}
+	}
+
+	log.Info("Updating template", "template", name, "type", templateType)
+	if err := updateTemplate(ctx, esClient, templateType, name, expected); err != nil {
+		msg := fmt.Sprintf("Failed to update %s template %s on Elasticsearch %s: %s", strings.ToLower(string(templateType)), name, esName, esclient.ErrorReason(err))
+		r.recorder.Event(&template, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return errorStatus(msg)
+	}
+	return status
+}
+
+// deleteTemplate deletes the template from the given Elasticsearch cluster, unless data streams or index templates
+// still use it in which case it is left in Elasticsearch.
+func (r *templateKind) deleteTemplate(ctx context.Context, template indexv1alpha1.IndexTemplate, esName string) error {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx).WithValues("es_name", esName)
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: template.Namespace, Name: esName}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+
+	name := template.TemplateNameOrDefault()
+	templateType := template.TypeOrDefault()
+	usage, err := templateUsage(ctx, esClient, templateType, name)
+	if err != nil {
+		return err
+	}
+	if usage != nil {
+		msg := fmt.Sprintf(
+			"%s template %s is not deleted from Elasticsearch %s as it is used by %d data streams and %d index templates",
+			templateType, name, esName, usage.DataStreams, usage.IndexTemplates,
+		)
+		log.Info(msg)
+		r.recorder.Event(&template, corev1.EventTypeWarning, events.EventReasonDelayed, msg)
+		return nil
+	}
+
+	log.Info("Deleting template", "template", name, "type", templateType)
+	if err := deleteTemplate(ctx, esClient, templateType, name); err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func getTemplate(ctx context.Context, esClient esclient.Client, templateType indexv1alpha1.TemplateType, name string) (map[string]interface{}, error) {
+	if templateType == indexv1alpha1.ComponentTemplateType {
+		return esClient.GetComponentTemplate(ctx, name)
+	}
+	return esClient.GetIndexTemplate(ctx, name)
+}
+
+func updateTemplate(ctx context.Context, esClient esclient.Client, templateType indexv1alpha1.TemplateType, name string, template map[string]interface{}) error {
+	if templateType == indexv1alpha1.ComponentTemplateType {
+		return esClient.UpdateComponentTemplate(ctx, name, template)
+	}
+	return esClient.UpdateIndexTemplate(ctx, name, template)
+}
+
+func deleteTemplate(ctx context.Context, esClient esclient.Client, templateType indexv1alpha1.TemplateType, name string) error {
+	if templateType == indexv1alpha1.ComponentTemplateType {
+		return esClient.DeleteComponentTemplate(ctx, name)
+	}
+	return esClient.DeleteIndexTemplate(ctx, name)
+}
+
+// templateUsage returns the number of data streams created from an index template, or for a component template the
+// number of index templates composed of it and of the data streams created from these index templates. It returns nil
+// if the template is not used.
+func templateUsage(ctx context.Context, esClient esclient.Client, templateType indexv1alpha1.TemplateType, name string) (*indexv1alpha1.TemplateUsage, error) {
+	indexTemplates := set.Make(name)
+	if templateType == indexv1alpha1.ComponentTemplateType {
+		templates, err := esClient.GetIndexTemplates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		indexTemplates = set.Make()
+		for templateName, template := range templates {
+			composedOf, _ := template["composed_of"].([]interface{})
+			if slices.Contains(composedOf, interface{}(name)) {
+				indexTemplates.Add(templateName)
+			}
+		}
+	}
+	dataStreams, err := esClient.GetDataStreams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var usage indexv1alpha1.TemplateUsage
+	for _, dataStream := range dataStreams {
+		if indexTemplates.Has(dataStream.Template) {
+			usage.DataStreams++
+		}
+	}
+	if templateType == indexv1alpha1.ComponentTemplateType {
+		usage.IndexTemplates = indexTemplates.Count()
+	}
+	if usage == (indexv1alpha1.TemplateUsage{}) {
+		return nil, nil
+	}
+	return &usage, nil
+}
+
+// sameTemplate compares the JSON representations of the expected template and of the template configured in
+// Elasticsearch, both normalized with the defaults and the settings format of Elasticsearch.
+func sameTemplate(templateType indexv1alpha1.TemplateType, expected, actual map[string]interface{}) bool {
+	expectedBytes, err := json.Marshal(normalize(templateType, expected))
+	if err != nil {
+		return false
+	}
+	actualBytes, err := json.Marshal(normalize(templateType, actual))
+	if err != nil {
+		return false
+	}
+	return string(expectedBytes) == string(actualBytes)
+}
+
+// normalize returns a copy of the given template with the defaults set by Elasticsearch on index templates and with
+// flat index settings with string values, as returned by Elasticsearch with flat_settings.
+func normalize(templateType indexv1alpha1.TemplateType, template map[string]interface{}) map[string]interface{} {
+	normalized := make(map[string]interface{}, len(template))
+	for k, v := range template {
+		normalized[k] = v
+	}
+	if templateType == indexv1alpha1.ComposableTemplateType {
+		if _, exists := normalized["composed_of"]; !exists {
+			normalized["composed_of"] = []interface{}{}
+		}
+		if dataStream, ok := normalized["data_stream"].(map[string]interface{}); ok {
+			dataStreamWithDefaults := map[string]interface{}{"hidden": false, "allow_custom_routing": false}
+			for k, v := range dataStream {
+				dataStreamWithDefaults[k] = v
+			}
+			normalized["data_stream"] = dataStreamWithDefaults
+		}
+	}
+	inner, ok := normalized["template"].(map[string]interface{})
+	if !ok {
+		return normalized
+	}
+	innerNormalized := make(map[string]interface{}, len(inner))
+	for k, v := range inner {
+		innerNormalized[k] = v
+	}
+	if settings, ok := inner["settings"].(map[string]interface{}); ok {
+		innerNormalized["settings"] = flattenSettings(settings)
+	}
+	normalized["template"] = innerNormalized
+	return normalized
+}
+
+// flattenSettings returns the given index settings indexed by their dotted key prefixed with index., with string values.
+func flattenSettings(settings map[string]interface{}) map[string]string {
+	flattened := map[string]string{}
+	var flattenInto func(prefix string, settings map[string]interface{})
+	flattenInto = func(prefix string, settings map[string]interface{}) {
+		for k, v := range settings {
+			if nested, ok := v.(map[string]interface{}); ok {
+				flattenInto(prefix+k+".", nested)
+				continue
+			}
+			key := prefix + k
+			if !strings.HasPrefix(key, indexSettingsPrefix) {
+				key = indexSettingsPrefix + key
+			}
+			flattened[key] = fmt.Sprintf("%v", v)
+		}
+	}
+	flattenInto("", settings)
+	return flattened
+}
+
+func applyingChangesStatus(msg string) indexv1alpha1.ElasticsearchTemplateStatus {
+	return indexv1alpha1.ElasticsearchTemplateStatus{Phase: indexv1alpha1.ApplyingChangesPhase, Message: msg}
+}
+
+func errorStatus(msg string) indexv1alpha1.ElasticsearchTemplateStatus {
+	return indexv1alpha1.ElasticsearchTemplateStatus{Phase: indexv1alpha1.ErrorPhase, Message: msg}
+}
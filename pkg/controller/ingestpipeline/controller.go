@@ -0,0 +1,196 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ingestpipeline
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	ingestv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/ingest/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	controllerName = "ingestpipeline-controller"
+
+	// PipelineFinalizer lets the operator delete the pipeline from Elasticsearch before the IngestPipeline resource is
+	// deleted.
+	PipelineFinalizer = "ingest.k8s.elastic.co/delete-pipeline"
+)
+
+// config identifies the IngestPipeline controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "IngestPipeline",
+	NameField:      "pipeline_name",
+	Finalizer:      PipelineFinalizer,
+}
+
+// Add creates a new IngestPipeline Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Elasticsearch and reconcile the IngestPipeline resources selecting them
+		source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestForPipelines(r.Client)),
+		// watch for changes to ConfigMaps and reconcile the IngestPipeline resources referencing them
+		source.Kind[client.Object](mgr.GetCache(), &corev1.ConfigMap{}, reconcileRequestForConfigMapPipelines(r.Client)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of IngestPipeline.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*ingestv1alpha1.IngestPipeline, ingestv1alpha1.IngestPipelineStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &pipelineKind{
+		Client:           c,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+// reconcileRequestForPipelines returns the requests to reconcile the IngestPipeline resources selecting the watched
+// Elasticsearch cluster, or configured on it before its labels changed.
+func reconcileRequestForPipelines(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &ingestv1alpha1.IngestPipelineList{}, func(pipeline *ingestv1alpha1.IngestPipeline, obj client.Object) bool {
+		_, configured := pipeline.Status.Details[obj.GetName()]
+		return configured || pipeline.Selects(k8s.ExtractNamespacedName(obj), obj.GetLabels())
+	})
+}
+
+// reconcileRequestForConfigMapPipelines returns the requests to reconcile the IngestPipeline resources whose definition
+// is held by the watched ConfigMap.
+func reconcileRequestForConfigMapPipelines(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &ingestv1alpha1.IngestPipelineList{}, func(pipeline *ingestv1alpha1.IngestPipeline, obj client.Object) bool {
+		return pipeline.Spec.PipelineRef != nil && pipeline.Spec.PipelineRef.ConfigMapName == obj.GetName()
+	})
+}
+
+// pipelineKind configures IngestPipeline resources in Elasticsearch.
+type pipelineKind struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*ingestv1alpha1.IngestPipeline, ingestv1alpha1.IngestPipelineStatus] = &pipelineKind{}
+	_ apiresource.Remover[*ingestv1alpha1.IngestPipeline]                                   = &pipelineKind{}
+)
+
+func (r *pipelineKind) NewObject() *ingestv1alpha1.IngestPipeline {
+	return &ingestv1alpha1.IngestPipeline{}
+}
+
+func (r *pipelineKind) GetStatus(pipeline *ingestv1alpha1.IngestPipeline) ingestv1alpha1.IngestPipelineStatus {
+	return pipeline.Status
+}
+
+func (r *pipelineKind) SetStatus(pipeline *ingestv1alpha1.IngestPipeline, status ingestv1alpha1.IngestPipelineStatus) {
+	pipeline.Status = status
+}
+
+func (r *pipelineKind) InvalidStatus(pipeline *ingestv1alpha1.IngestPipeline, _ error) ingestv1alpha1.IngestPipelineStatus {
+	status := ingestv1alpha1.NewStatus(*pipeline)
+	status.Phase = ingestv1alpha1.InvalidPhase
+	return status
+}
+
+// Configure configures the pipeline on the selected Elasticsearch clusters, and deletes it from the clusters that are
+// no longer selected.
+func (r *pipelineKind) Configure(ctx context.Context, obj *ingestv1alpha1.IngestPipeline) (*reconciler.Results, ingestv1alpha1.IngestPipelineStatus) {
+	pipeline := *obj
+	log := ulog.FromContext(ctx)
+	results := reconciler.NewResult(ctx)
+	status := ingestv1alpha1.NewStatus(pipeline)
+
+	esNames, err := r.selectedClusters(ctx, pipeline)
+	if err != nil {
+		return results.WithError(err), pipeline.Status
+	}
+
+	// configure the pipeline on the referenced and selected Elasticsearch clusters
+	definition, err := r.pipelineDefinition(ctx, pipeline)
+	if err != nil {
+		r.recorder.Event(&pipeline, corev1.EventTypeWarning, events.EventReconciliationError, err.Error())
+	}
+	for _, esName := range esNames {
+		if err != nil {
+			status.SetElasticsearchStatus(esName, errorStatus(err.Error()))
+			continue
+		}
+		status.SetElasticsearchStatus(esName, r.reconcileElasticsearch(ctx, pipeline, definition, esName))
+	}
+
+	// delete the pipeline from the clusters that are no longer referenced or selected
+	selected := set.Make(esNames...)
+	for esName := range pipeline.Status.Details {
+		if selected.Has(esName) {
+			continue
+		}
+		if err := r.deletePipeline(ctx, pipeline, esName); err != nil {
+			log.Error(err, "Failed to delete the pipeline", "es_name", esName)
+			status.SetElasticsearchStatus(esName, ingestv1alpha1.ElasticsearchPipelineStatus{
+				Phase:   ingestv1alpha1.ApplyingChangesPhase,
+				Message: "Failed to delete the pipeline: " + err.Error(),
+			})
+		}
+	}
+
+	results.WithResult(apiresource.Requeue(status.Phase == ingestv1alpha1.ReadyPhase))
+
+	return results, status
+}
+
+// Remove deletes the pipeline from all the clusters it is configured on.
+func (r *pipelineKind) Remove(ctx context.Context, obj *ingestv1alpha1.IngestPipeline) (reconcile.Result, error) {
+	pipeline := *obj
+	for esName := range pipeline.Status.Details {
+		if err := r.deletePipeline(ctx, pipeline, esName); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// selectedClusters returns the sorted names of the Elasticsearch clusters referenced by the pipeline, whether they exist
+// or not, and of the existing clusters matching its selector.
+func (r *pipelineKind) selectedClusters(ctx context.Context, pipeline ingestv1alpha1.IngestPipeline) ([]string, error) {
+	names := set.Make()
+	for _, ref := range pipeline.Spec.ElasticsearchRefs {
+		names.Add(ref.Name)
+	}
+	if pipeline.Spec.ElasticsearchSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(pipeline.Spec.ElasticsearchSelector)
+		if err != nil {
+			return nil, err
+		}
+		var clusters esv1.ElasticsearchList
+		if err := r.Client.List(ctx, &clusters, client.InNamespace(pipeline.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+		for _, es := range clusters.Items {
+			names.Add(es.Name)
+		}
+	}
+	return names.AsSortedSlice(), nil
+}
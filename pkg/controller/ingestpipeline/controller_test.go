@@ -0,0 +1,294 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ingestpipeline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	ingestv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/ingest/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeEsClient stores ingest pipelines in memory and simulates pipelines by requiring the documents to have a message.
+type fakeEsClient struct {
+	esclient.Client
+	cluster *fakeElasticsearch
+}
+
+func (c fakeEsClient) GetIngestPipeline(_ context.Context, name string) (map[string]interface{}, error) {
+	pipeline, exists := c.cluster.pipelines[name]
+	if !exists {
+		return nil, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return pipeline, nil
+}
+
+func (c fakeEsClient) UpdateIngestPipeline(_ context.Context, name string, pipeline map[string]interface{}) error {
+	c.cluster.updates++
+	c.cluster.pipelines[name] = pipeline
+	return nil
+}
+
+func (c fakeEsClient) DeleteIngestPipeline(_ context.Context, name string) error {
+	if _, exists := c.cluster.pipelines[name]; !exists {
+		return &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	delete(c.cluster.pipelines, name)
+	return nil
+}
+
+func (c fakeEsClient) SimulateIngestPipeline(_ context.Context, _ map[string]interface{}, sources []map[string]interface{}) ([]esclient.SimulatedDocumentResult, error) {
+	if c.cluster.simulateErr != nil {
+		return nil, c.cluster.simulateErr
+	}
+	results := make([]esclient.SimulatedDocumentResult, 0, len(sources))
+	for _, source := range sources {
+		if _, exists := source["message"]; !exists {
+			results = append(results, esclient.SimulatedDocumentResult{
+				Error: &esclient.SimulatedDocumentError{Type: "illegal_argument_exception", Reason: "field [message] not present as part of path [message]"},
+			})
+			continue
+		}
+		results = append(results, esclient.SimulatedDocumentResult{Doc: map[string]interface{}{"_source": source}})
+	}
+	return results, nil
+}
+
+func (c fakeEsClient) Close() {}
+
+type fakeElasticsearch struct {
+	pipelines   map[string]map[string]interface{}
+	simulateErr error
+	updates     int
+}
+
+func newFakeElasticsearch() *fakeElasticsearch {
+	return &fakeElasticsearch{pipelines: map[string]map[string]interface{}{}}
+}
+
+func fakeClientProvider(clusters map[string]*fakeElasticsearch) commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, es esv1.Elasticsearch) (esclient.Client, error) {
+		return fakeEsClient{cluster: clusters[es.Name]}, nil
+	}
+}
+
+func grokPipeline(pattern string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": "parses access logs",
+		"processors": []interface{}{
+			map[string]interface{}{"grok": map[string]interface{}{"field": "message", "patterns": []interface{}{pattern}}},
+		},
+	}
+}
+
+func TestReconcileIngestPipeline_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	pipeline := &ingestv1alpha1.IngestPipeline{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "access-logs"},
+		Spec: ingestv1alpha1.IngestPipelineSpec{
+			ElasticsearchRefs:     []commonv1.LocalObjectSelector{{Name: "es1"}},
+			ElasticsearchSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			Pipeline:              &commonv1.Config{Data: grokPipeline("%{COMMONAPACHELOG}")},
+		},
+	}
+	es1 := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es1"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	es2 := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es2", Labels: map[string]string{"env": "prod"}},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	clusters := map[string]*fakeElasticsearch{"es1": newFakeElasticsearch(), "es2": newFakeElasticsearch()}
+	k8sClient := k8s.NewFakeClient(pipeline, es1, es2)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &pipelineKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(clusters),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "access-logs"}}
+	getPipeline := func() ingestv1alpha1.IngestPipeline {
+		var actual ingestv1alpha1.IngestPipeline
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+
+	// the pipeline is configured on the referenced and selected clusters, the processing error of the empty document is ignored
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	actual := getPipeline()
+	require.Equal(t, "2/2", actual.Status.ReadyCount)
+	require.Equal(t, []string{PipelineFinalizer}, actual.Finalizers)
+	require.Equal(t, grokPipeline("%{COMMONAPACHELOG}"), clusters["es1"].pipelines["access-logs"])
+	require.Equal(t, 1, clusters["es1"].updates)
+	require.Equal(t, 1, clusters["es2"].updates)
+	require.Empty(t, recorder.Events)
+
+	// the pipeline is restored if it is modified in Elasticsearch
+	clusters["es2"].pipelines["access-logs"] = grokPipeline("%{COMBINEDAPACHELOG}")
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, grokPipeline("%{COMMONAPACHELOG}"), clusters["es2"].pipelines["access-logs"])
+	require.Equal(t, 1, clusters["es1"].updates)
+	require.NotNil(t, getPipeline().Status.Details["es2"].LastDriftTime)
+	require.Contains(t, <-recorder.Events, "Ingest pipeline access-logs was modified in Elasticsearch es2")
+
+	// the pipeline is not updated if its simulation fails
+	clusters["es1"].simulateErr = errors.New("connection refused")
+	actual = getPipeline()
+	actual.Generation++
+	actual.Spec.Pipeline = &commonv1.Config{Data: grokPipeline("%{COMBINEDAPACHELOG}")}
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	actual = getPipeline()
+	require.Equal(t, ingestv1alpha1.ErrorPhase, actual.Status.Phase)
+	require.Equal(t, "Simulation of ingest pipeline access-logs failed on Elasticsearch es1: connection refused", actual.Status.Details["es1"].Message)
+	require.Equal(t, grokPipeline("%{COMMONAPACHELOG}"), clusters["es1"].pipelines["access-logs"])
+	require.Equal(t, grokPipeline("%{COMBINEDAPACHELOG}"), clusters["es2"].pipelines["access-logs"])
+	require.Contains(t, <-recorder.Events, "Simulation of ingest pipeline access-logs failed on Elasticsearch es1")
+
+	// the pipeline is deleted from Elasticsearch with the IngestPipeline
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, clusters["es1"].pipelines)
+	require.Empty(t, clusters["es2"].pipelines)
+	err = k8sClient.Get(ctx, request.NamespacedName, &ingestv1alpha1.IngestPipeline{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileIngestPipeline_SimulateDocuments(t *testing.T) {
+	ctx := context.Background()
+	pipeline := &ingestv1alpha1.IngestPipeline{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "access-logs"},
+		Spec: ingestv1alpha1.IngestPipelineSpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			Pipeline:          &commonv1.Config{Data: grokPipeline("%{COMMONAPACHELOG}")},
+			SimulateDocuments: []commonv1.Config{
+				{Data: map[string]interface{}{"message": `127.0.0.1 - - [10/Oct/2026:13:55:36 +0000] "GET /index.html HTTP/1.1" 200 2326`}},
+				{Data: map[string]interface{}{"msg": "no message"}},
+			},
+		},
+	}
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	cluster := newFakeElasticsearch()
+	k8sClient := k8s.NewFakeClient(pipeline, es)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &pipelineKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(map[string]*fakeElasticsearch{"es": cluster}),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "access-logs"}}
+
+	// the pipeline is not configured as one of the sample documents fails to be processed
+	_, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	var actual ingestv1alpha1.IngestPipeline
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+	require.Equal(t, ingestv1alpha1.ErrorPhase, actual.Status.Phase)
+	require.Equal(t,
+		"Simulation of ingest pipeline access-logs failed on Elasticsearch es: document 1: illegal_argument_exception: field [message] not present as part of path [message]",
+		actual.Status.Details["es"].Message,
+	)
+	require.Empty(t, cluster.pipelines)
+
+	// the pipeline is configured once all the sample documents are processed
+	actual.Spec.SimulateDocuments = actual.Spec.SimulateDocuments[:1]
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+	require.Equal(t, ingestv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Contains(t, cluster.pipelines, "access-logs")
+}
+
+func TestReconcileIngestPipeline_ConfigMap(t *testing.T) {
+	ctx := context.Background()
+	pipeline := &ingestv1alpha1.IngestPipeline{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "access-logs"},
+		Spec: ingestv1alpha1.IngestPipelineSpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			PipelineName:      "access-logs-v1",
+			PipelineRef:       &ingestv1alpha1.PipelineConfigMapRef{ConfigMapName: "pipelines", Key: "access-logs.yaml"},
+		},
+	}
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	cluster := newFakeElasticsearch()
+	k8sClient := k8s.NewFakeClient(pipeline, es)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &pipelineKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(map[string]*fakeElasticsearch{"es": cluster}),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "access-logs"}}
+	getStatus := func() ingestv1alpha1.ElasticsearchPipelineStatus {
+		var actual ingestv1alpha1.IngestPipeline
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual.Status.Details["es"]
+	}
+
+	// the ConfigMap does not exist yet
+	_, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, ingestv1alpha1.ElasticsearchPipelineStatus{Phase: ingestv1alpha1.ErrorPhase, Message: "ConfigMap pipelines not found"}, getStatus())
+
+	// the definition in the ConfigMap has no processors
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pipelines"},
+		Data:       map[string]string{"access-logs.yaml": "description: parses access logs\n"},
+	}
+	require.NoError(t, k8sClient.Create(ctx, configMap))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, ingestv1alpha1.ElasticsearchPipelineStatus{
+		Phase:   ingestv1alpha1.ErrorPhase,
+		Message: "invalid pipeline definition in ConfigMap pipelines: pipelines[access-logs.yaml].processors: Required value: the processors of the pipeline are mandatory",
+	}, getStatus())
+	require.Empty(t, cluster.pipelines)
+
+	// the YAML definition is configured
+	configMap.Data["access-logs.yaml"] = `
+description: parses access logs
+processors:
+- grok:
+    field: message
+    patterns: ["%{COMMONAPACHELOG}"]
+`
+	require.NoError(t, k8sClient.Update(ctx, configMap))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, ingestv1alpha1.ElasticsearchPipelineStatus{Phase: ingestv1alpha1.ReadyPhase}, getStatus())
+	require.Equal(t, grokPipeline("%{COMMONAPACHELOG}"), cluster.pipelines["access-logs-v1"])
+}
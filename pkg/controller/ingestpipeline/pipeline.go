@@ -0,0 +1,186 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ingestpipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	ingestv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/ingest/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// pipelineDefinition returns the definition of the pipeline, either inlined in the specification or read from the
+// referenced ConfigMap, in which case it is validated as the webhook cannot do it.
+func (r *pipelineKind) pipelineDefinition(ctx context.Context, pipeline ingestv1alpha1.IngestPipeline) (map[string]interface{}, error) {
+	ref := pipeline.Spec.PipelineRef
+	if ref == nil {
+		return pipeline.Spec.Pipeline.Data, nil
+	}
+	var configMap corev1.ConfigMap
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: pipeline.Namespace, Name: ref.ConfigMapName}, &configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("ConfigMap %s not found", ref.ConfigMapName)
+		}
+		return nil, err
+	}
+	raw, exists := configMap.Data[ref.KeyOrDefault()]
+	if !exists {
+		return nil, fmt.Errorf("key %s not found in ConfigMap %s", ref.KeyOrDefault(), ref.ConfigMapName)
+	}
+	var definition map[string]interface{}
+	// YAML being a superset of JSON, the definition can be written in either format
+	if err := yaml.Unmarshal([]byte(raw), &definition); err != nil {
+		return nil, fmt.Errorf("invalid pipeline definition in ConfigMap %s: %w", ref.ConfigMapName, err)
+	}
+	if errs := ingestv1alpha1.ValidatePipelineDefinition(field.NewPath(ref.ConfigMapName).Key(ref.KeyOrDefault()), definition); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid pipeline definition in ConfigMap %s: %w", ref.ConfigMapName, errs.ToAggregate())
+	}
+	return definition, nil
+}
+
+// reconcileElasticsearch configures the pipeline on the given Elasticsearch cluster if it does not exist yet or if it
+// differs from the definition, after a successful simulation, and returns the status of the pipeline for this cluster.
+func (r *pipelineKind) reconcileElasticsearch(ctx context.Context, pipeline ingestv1alpha1.IngestPipeline, definition map[string]interface{}, esName string) ingestv1alpha1.ElasticsearchPipelineStatus {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx).WithValues("es_name", esName)
+
+	es, phase, msg := apiresource.ReadyElasticsearch(ctx, r.Client, types.NamespacedName{Namespace: pipeline.Namespace, Name: esName})
+	if phase != ingestv1alpha1.ReadyPhase {
+		return ingestv1alpha1.ElasticsearchPipelineStatus{Phase: phase, Message: msg}
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	defer esClient.Close()
+
+	name := pipeline.PipelineNameOrDefault()
+	previous := pipeline.Status.Details[esName]
+	status := ingestv1alpha1.ElasticsearchPipelineStatus{Phase: ingestv1alpha1.ReadyPhase, LastDriftTime: previous.LastDriftTime}
+	actual, err := esClient.GetIngestPipeline(ctx, name)
+	if err != nil && !esclient.IsNotFound(err) {
+		return applyingChangesStatus(err.Error())
+	}
+	if err == nil {
+		if samePipeline(definition, actual) {
+			return status
+		}
+		// the pipeline was already configured from the current specification, it was modified outside of the operator
+		if previous.Phase == ingestv1alpha1.ReadyPhase && pipeline.Status.ObservedGeneration == pipeline.Generation {
+			msg := fmt.Sprintf("Ingest pipeline %s was modified in Elasticsearch %s, restoring it from the IngestPipeline specification", name, esName)
+			status.LastDriftTime = apiresource.RecordDrift(log, r.recorder, &pipeline, msg)
+		}
+	}
+
+	if err := simulate(ctx, esClient, pipeline, definition); err != nil {
+		msg := fmt.Sprintf("Simulation of ingest pipeline %s failed on Elasticsearch %s: %s", name, esName, err.Error())
+		r.recorder.Event(&pipeline, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return errorStatus(msg)
+	}
+
+	log.Info("Updating ingest pipeline", "pipeline", name)
+	if err := esClient.UpdateIngestPipeline(ctx, name, definition); err != nil {
+		msg := fmt.Sprintf("Failed to update ingest pipeline %s on Elasticsearch %s: %s", name, esName, esclient.ErrorReason(err))
+		r.recorder.Event(&pipeline, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return errorStatus(msg)
+	}
+	return status
+}
+
+// simulate runs the sample documents of the specification through the pipeline definition and returns an error if the
+// simulation cannot run or if one of the documents fails to be processed. Without sample documents, the pipeline is
+// simulated on an empty document to let Elasticsearch parse it, ignoring processing errors that are expected for
+// processors working on fields that the empty document does not have.
+func simulate(ctx context.Context, esClient esclient.Client, pipeline ingestv1alpha1.IngestPipeline, definition map[string]interface{}) error {
+	sources := make([]map[string]interface{}, 0, len(pipeline.Spec.SimulateDocuments))
+	for _, doc := range pipeline.Spec.SimulateDocuments {
+		sources = append(sources, doc.Data)
+	}
+	userDocuments := len(sources) > 0
+	if !userDocuments {
+		sources = append(sources, map[string]interface{}{})
+	}
+	results, err := esClient.SimulateIngestPipeline(ctx, definition, sources)
+	if err != nil {
+		return errors.New(esclient.ErrorReason(err))
+	}
+	if !userDocuments {
+		return nil
+	}
+	var failures []string
+	for i, result := range results {
+		if result.Error != nil {
+			failures = append(failures, fmt.Sprintf("document %d: %s: %s", i, result.Error.Type, result.Error.Reason))
+		}
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+// samePipeline compares the JSON representations of the expected pipeline and of the pipeline configured in
+// Elasticsearch.
+func samePipeline(expected, actual map[string]interface{}) bool {
+	expectedBytes, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	actualBytes, err := json.Marshal(actual)
+	if err != nil {
+		return false
+	}
+	return string(expectedBytes) == string(actualBytes)
+}
+
+// deletePipeline deletes the pipeline from the given Elasticsearch cluster.
+func (r *pipelineKind) deletePipeline(ctx context.Context, pipeline ingestv1alpha1.IngestPipeline, esName string) error {
+	defer tracing.Span(&ctx)()
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: pipeline.Namespace, Name: esName}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+
+	name := pipeline.PipelineNameOrDefault()
+	ulog.FromContext(ctx).Info("Deleting ingest pipeline", "pipeline", name, "es_name", esName)
+	if err := esClient.DeleteIngestPipeline(ctx, name); err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func applyingChangesStatus(msg string) ingestv1alpha1.ElasticsearchPipelineStatus {
+	return ingestv1alpha1.ElasticsearchPipelineStatus{Phase: ingestv1alpha1.ApplyingChangesPhase, Message: msg}
+}
+
+func errorStatus(msg string) ingestv1alpha1.ElasticsearchPipelineStatus {
+	return ingestv1alpha1.ElasticsearchPipelineStatus{Phase: ingestv1alpha1.ErrorPhase, Message: msg}
+}
@@ -152,7 +152,11 @@ func NewConfigSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v
 		}
 		credentialsCfg := settings.MustCanonicalConfig(esCreds)
 		esAssocCfg := settings.MustCanonicalConfig(elasticsearchTLSSettings(*esAssocConf))
-		if err = cfg.MergeWith(esAssocCfg, credentialsCfg); err != nil {
+		ssoProvidersCfg, err := ssoProvidersSettings(ctx, client, kb, v, userSettings)
+		if err != nil {
+			return CanonicalConfig{}, err
+		}
+		if err = cfg.MergeWith(esAssocCfg, credentialsCfg, ssoProvidersCfg); err != nil {
 			return CanonicalConfig{}, err
 		}
 	}
@@ -13,6 +13,7 @@ import (
 	"go.elastic.co/apm/v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -22,14 +23,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/finalizer"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/queue"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemonitor"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	kblabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/label"
@@ -65,7 +70,7 @@ func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileKi
 
 func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileKibana) error {
 	// Watch for changes to Kibana
-	if err := c.Watch(source.Kind(mgr.GetCache(), &kbv1.Kibana{}, &handler.TypedEnqueueRequestForObject[*kbv1.Kibana]{})); err != nil {
+	if err := c.Watch(source.Kind(mgr.GetCache(), &kbv1.Kibana{}, queue.EnqueueRequestForObject[*kbv1.Kibana]())); err != nil {
 		return err
 	}
 
@@ -90,8 +95,27 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileKibana
 	))); err != nil {
 		return err
 	}
+
+	// Watch ingresses
+	if err := c.Watch(source.Kind(mgr.GetCache(), &networkingv1.Ingress{}, handler.TypedEnqueueRequestForOwner[*networkingv1.Ingress](
+		mgr.GetScheme(), mgr.GetRESTMapper(),
+		&kbv1.Kibana{}, handler.OnlyControllerOwner(),
+	))); err != nil {
+		return err
+	}
+
+	// Watch Gateway routes
+	if err := gateway.WatchRoutes(mgr, c, &kbv1.Kibana{}); err != nil {
+		return err
+	}
+
+	// Watch ServiceMonitors
+	if err := servicemonitor.Watch(mgr, c, &kbv1.Kibana{}); err != nil {
+		return err
+	}
+
 	// Watch owned and soft-owned secrets
-	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
+	if err := c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
 		mgr.GetScheme(), mgr.GetRESTMapper(),
 		&kbv1.Kibana{}, handler.OnlyControllerOwner(),
 	))); err != nil {
@@ -101,8 +125,27 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileKibana
 		return err
 	}
 
+	// Watch Elasticsearch clusters to configure the authentication providers of the Kibana their single sign-on realms
+	// are used by
+	if err := c.Watch(source.Kind(mgr.GetCache(), &esv1.Elasticsearch{}, handler.TypedEnqueueRequestsFromMapFunc[*esv1.Elasticsearch](
+		reconcileRequestsForSSORealms,
+	))); err != nil {
+		return err
+	}
+
 	// dynamically watch referenced secrets to connect to Elasticsearch
-	return c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets))
+	return c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets))
+}
+
+// reconcileRequestsForSSORealms returns the requests to reconcile the Kibana instances the OpenID Connect and SAML realms
+// of the given Elasticsearch cluster are used by.
+func reconcileRequestsForSSORealms(_ context.Context, es *esv1.Elasticsearch) []reconcile.Request {
+	realms := es.Spec.Auth.SSORealms()
+	requests := make([]reconcile.Request, 0, len(realms))
+	for _, realm := range realms {
+		requests = append(requests, reconcile.Request{NamespacedName: realm.KibanaRef.WithDefaultNamespace(es.Namespace).NamespacedName()})
+	}
+	return requests
 }
 
 var _ reconcile.Reconciler = &ReconcileKibana{}
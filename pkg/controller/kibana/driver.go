@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"hash/fnv"
+	"time"
 
 	pkgerrors "github.com/pkg/errors"
 	"go.elastic.co/apm/v2"
@@ -26,9 +27,13 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/deployment"
 	driver2 "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/externaldns"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/ingress"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemonitor"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	commonvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
@@ -127,12 +132,13 @@ func (d *driver) Reconcile(
 		DynamicWatches:        d.DynamicWatches(),
 		Owner:                 kb,
 		TLSOptions:            kb.Spec.HTTP.TLS,
+		ExtraHTTPSANs:         kb.Spec.HTTP.ExternalSANs(),
 		Namer:                 kbv1.KBNamer,
 		Labels:                kb.GetIdentityLabels(),
 		Services:              []corev1.Service{*svc},
 		GlobalCA:              params.GlobalCA,
-		CACertRotation:        params.CACertRotation,
-		CertRotation:          params.CertRotation,
+		CACertRotation:        params.CACertRotation.Get(),
+		CertRotation:          params.CertRotation.Get(),
 		GarbageCollectSecrets: true,
 	}.ReconcileCAAndHTTPCerts(ctx)
 	if results.HasError() {
@@ -141,6 +147,29 @@ func (d *driver) Reconcile(
 		return results
 	}
 
+	exposureParams := ingress.Params{
+		Owner:   kb,
+		HTTP:    kb.Spec.HTTP,
+		Service: *svc,
+		Labels:  kb.GetIdentityLabels(),
+		Namer:   kbv1.KBNamer,
+	}
+	if err := ingress.Reconcile(ctx, d.client, exposureParams); err != nil {
+		return results.WithError(err)
+	}
+	if err := gateway.Reconcile(ctx, d.client, exposureParams); err != nil {
+		return results.WithError(err)
+	}
+	if err := servicemonitor.Reconcile(ctx, d.client, servicemonitor.Params{
+		Owner:      kb,
+		Prometheus: kb.Spec.Monitoring.Prometheus,
+		Name:       kbv1.KBNamer.Suffix(kb.Name, "metrics"),
+		Labels:     kb.GetIdentityLabels(),
+		Selector:   kb.GetIdentityLabels(),
+	}); err != nil {
+		return results.WithError(err)
+	}
+
 	logger := ulog.FromContext(ctx)
 	assocAllowed, err := association.AllowVersion(d.version, kb, logger, d.Recorder())
 	if err != nil {
@@ -179,10 +208,18 @@ func (d *driver) Reconcile(
 	}
 
 	expectedDp := deployment.New(deploymentParams)
-	reconciledDp, err := deployment.Reconcile(ctx, d.client, expectedDp, kb)
+	pendingChange, requeueAfter, err := d.withMaintenanceWindows(ctx, kb, &expectedDp, time.Now())
+	if err != nil {
+		return results.WithError(err)
+	}
+	reconciledDp, err := deployment.Reconcile(ctx, d.client, expectedDp, kb, true)
 	if err != nil {
 		return results.WithError(err)
 	}
+	state.Kibana.Status.PendingChange = pendingChange
+	if pendingChange != "" {
+		results.WithReconciliationState(reconciler.RequeueAfter(requeueAfter).WithReason(pendingChange))
+	}
 
 	existingPods, err := k8s.PodsMatchingLabels(d.K8sClient(), kb.Namespace, map[string]string{kblabel.KibanaNameLabelName: kb.Name})
 	if err != nil {
@@ -344,6 +381,7 @@ func NewService(kb kbv1.Kibana) *corev1.Service {
 
 	svc.ObjectMeta.Namespace = kb.Namespace
 	svc.ObjectMeta.Name = kbv1.HTTPService(kb.Name)
+	svc.ObjectMeta.Annotations = externaldns.ServiceAnnotations(kb.Spec.HTTP)
 
 	labels := kb.GetIdentityLabels()
 	ports := []corev1.ServicePort{
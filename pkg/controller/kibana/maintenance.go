@@ -0,0 +1,54 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/maintenance"
+)
+
+// podTemplateHashAnnotationName records on the Deployment the hash of the Pod template built by the operator, to
+// detect the updates of the Pod template, which restart the Kibana Pods.
+const podTemplateHashAnnotationName = "kibana.k8s.elastic.co/pod-template-hash"
+
+// withMaintenanceWindows keeps the Pod template of the existing Deployment in the expected one if the Pod template
+// changed while no maintenance window is open. It returns the description of the pending change and the delay until
+// the next window in that case. The other changes of the Deployment, such as the number of replicas, are applied.
+func (d *driver) withMaintenanceWindows(ctx context.Context, kb *kbv1.Kibana, expected *appsv1.Deployment, now time.Time) (string, time.Duration, error) {
+	templateHash := hash.HashObject(expected.Spec.Template)
+	if expected.Annotations == nil {
+		expected.Annotations = map[string]string{}
+	}
+	expected.Annotations[podTemplateHashAnnotationName] = templateHash
+
+	var actual appsv1.Deployment
+	err := d.client.Get(ctx, types.NamespacedName{Namespace: expected.Namespace, Name: expected.Name}, &actual)
+	if apierrors.IsNotFound(err) {
+		// the Pods of a new Deployment are created right away
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	actualHash, exists := actual.Annotations[podTemplateHashAnnotationName]
+	if !exists || actualHash == templateHash {
+		return "", 0, nil
+	}
+	open, next, err := maintenance.IsOpen(kb.Spec.MaintenanceWindows, now)
+	if err != nil || open {
+		return "", 0, err
+	}
+	expected.Spec.Template = actual.Spec.Template
+	expected.Annotations[podTemplateHashAnnotationName] = actualHash
+	return maintenance.PendingReason("Update of the Kibana Pods", next), maintenance.RequeueAfter(next, now, time.Minute), nil
+}
@@ -0,0 +1,104 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_driver_withMaintenanceWindows(t *testing.T) {
+	// 2026-10-15 is a Thursday
+	now := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	saturdayNight := []commonv1.MaintenanceWindow{{Schedule: "0 22 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}}}
+	thursdayMorning := []commonv1.MaintenanceWindow{{Schedule: "0 9 * * 4", Duration: metav1.Duration{Duration: 2 * time.Hour}}}
+
+	deploymentWith := func(image string, annotations map[string]string) appsv1.Deployment {
+		return appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb", Annotations: annotations},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "kibana", Image: image}},
+			}}},
+		}
+	}
+	oldTemplateHash := hash.HashObject(deploymentWith("kibana:8.14.0", nil).Spec.Template)
+	newTemplateHash := hash.HashObject(deploymentWith("kibana:8.15.0", nil).Spec.Template)
+
+	tests := []struct {
+		name        string
+		windows     []commonv1.MaintenanceWindow
+		existing    []appsv1.Deployment
+		wantImage   string
+		wantHash    string
+		wantPending string
+		wantRequeue time.Duration
+	}{
+		{
+			name:      "new Deployment",
+			windows:   saturdayNight,
+			wantImage: "kibana:8.15.0",
+			wantHash:  newTemplateHash,
+		},
+		{
+			name:      "no maintenance window",
+			existing:  []appsv1.Deployment{deploymentWith("kibana:8.14.0", map[string]string{podTemplateHashAnnotationName: oldTemplateHash})},
+			wantImage: "kibana:8.15.0",
+			wantHash:  newTemplateHash,
+		},
+		{
+			name:      "Deployment created by a previous operator version",
+			windows:   saturdayNight,
+			existing:  []appsv1.Deployment{deploymentWith("kibana:8.14.0", nil)},
+			wantImage: "kibana:8.15.0",
+			wantHash:  newTemplateHash,
+		},
+		{
+			name:      "maintenance window open",
+			windows:   thursdayMorning,
+			existing:  []appsv1.Deployment{deploymentWith("kibana:8.14.0", map[string]string{podTemplateHashAnnotationName: oldTemplateHash})},
+			wantImage: "kibana:8.15.0",
+			wantHash:  newTemplateHash,
+		},
+		{
+			name:        "Pod template update held until the next window",
+			windows:     saturdayNight,
+			existing:    []appsv1.Deployment{deploymentWith("kibana:8.14.0", map[string]string{podTemplateHashAnnotationName: oldTemplateHash})},
+			wantImage:   "kibana:8.14.0",
+			wantHash:    oldTemplateHash,
+			wantPending: "Update of the Kibana Pods pending, waiting for the maintenance window starting at 2026-10-17T22:00:00Z",
+			wantRequeue: 60 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objects []client.Object
+			for i := range tt.existing {
+				objects = append(objects, &tt.existing[i])
+			}
+			d := &driver{client: k8s.NewFakeClient(objects...)}
+			kb := &kbv1.Kibana{Spec: kbv1.KibanaSpec{MaintenanceWindows: tt.windows}}
+			expected := deploymentWith("kibana:8.15.0", nil)
+
+			pending, requeueAfter, err := d.withMaintenanceWindows(context.Background(), kb, &expected, now)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPending, pending)
+			require.Equal(t, tt.wantRequeue, requeueAfter)
+			require.Equal(t, tt.wantImage, expected.Spec.Template.Spec.Containers[0].Image)
+			require.Equal(t, tt.wantHash, expected.Annotations[podTemplateHashAnnotationName])
+		})
+	}
+}
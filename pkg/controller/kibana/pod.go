@@ -22,6 +22,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/pod"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemesh"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
@@ -98,6 +99,10 @@ func NewPodTemplateSpec(ctx context.Context, client k8sclient.Client, kb kbv1.Ki
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	image, err := container.ResolveImage(kb.Spec.Image, container.KibanaImage, v)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	kibanaBasePath, err := GetKibanaBasePath(kb)
 	if err != nil {
@@ -107,7 +112,8 @@ func NewPodTemplateSpec(ctx context.Context, client k8sclient.Client, kb kbv1.Ki
 		WithResources(DefaultResources).
 		WithLabels(labels).
 		WithAnnotations(DefaultAnnotations).
-		WithDockerImage(kb.Spec.Image, container.ImageRepository(container.KibanaImage, v)).
+		WithAnnotations(servicemesh.PodAnnotations(kb.Spec.HTTP.TLS)).
+		WithDockerImage(kb.Spec.Image, image).
 		WithReadinessProbe(readinessProbe(kb.Spec.HTTP.TLS.Enabled(), kibanaBasePath)).
 		WithPorts(ports).
 		WithInitContainers(initConfigContainer(kb))
@@ -364,6 +364,25 @@ func TestNewPodTemplateSpec(t *testing.T) {
 				assert.Equal(t, kbContainer.ReadinessProbe.ProbeHandler.HTTPGet.Path, "/monitoring/kibana/login")
 			},
 		},
+		{
+			name: "with TLS provided by an Istio service mesh",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version: "8.12.0",
+				HTTP: commonv1.HTTPConfig{TLS: commonv1.TLSOptions{
+					ServiceMesh: &commonv1.ServiceMeshOptions{Provider: commonv1.IstioServiceMeshProvider},
+				}},
+				PodTemplate: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"proxy.istio.io/config": "{}"},
+				}},
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				kbContainer := GetKibanaContainer(pod.Spec)
+				assert.Equal(t, corev1.URISchemeHTTP, kbContainer.ReadinessProbe.ProbeHandler.HTTPGet.Scheme)
+				assert.Equal(t, "true", pod.Annotations["sidecar.istio.io/rewriteAppHTTPProbers"])
+				// user-provided annotations take precedence
+				assert.Equal(t, "{}", pod.Annotations["proxy.istio.io/config"])
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
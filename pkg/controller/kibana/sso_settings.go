@@ -0,0 +1,77 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	XpackSecurityAuthcProviders = "xpack.security.authc.providers"
+
+	// basicProviderName is the name of the basic authentication provider kept next to the single sign-on providers.
+	basicProviderName = "basic1"
+)
+
+// ssoProvidersMinVersion is the version from which Kibana supports several authentication providers of the same type.
+var ssoProvidersMinVersion = version.From(7, 7, 0)
+
+// ssoProvidersSettings returns the authentication providers of the OpenID Connect and SAML realms of the associated
+// Elasticsearch cluster used by the given Kibana, followed by the basic provider to keep the users of the other realms,
+// such as the elastic user, able to log in. Nothing is returned if the user configures the providers.
+func ssoProvidersSettings(
+	ctx context.Context,
+	client k8s.Client,
+	kb kbv1.Kibana,
+	v version.Version,
+	userSettings *settings.CanonicalConfig,
+) (*settings.CanonicalConfig, error) {
+	if !v.GTE(ssoProvidersMinVersion) || userSettings.HasChildConfig(XpackSecurityAuthcProviders) {
+		return settings.NewCanonicalConfig(), nil
+	}
+	esRef := kb.EsAssociation().AssociationRef()
+	if !esRef.IsDefined() || esRef.IsExternal() {
+		return settings.NewCanonicalConfig(), nil
+	}
+	var es esv1.Elasticsearch
+	if err := client.Get(ctx, esRef.NamespacedName(), &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return settings.NewCanonicalConfig(), nil
+		}
+		return nil, err
+	}
+	realms := es.KibanaSSORealms(k8s.ExtractNamespacedName(&kb))
+	if len(realms) == 0 {
+		return settings.NewCanonicalConfig(), nil
+	}
+
+	// list the providers in the order of their realms in the realm chain
+	sort.SliceStable(realms, func(i, j int) bool { return realms[i].Order < realms[j].Order })
+	providers := map[string]interface{}{}
+	for i, realm := range realms {
+		provider := map[string]interface{}{"order": i, "realm": realm.Name}
+		if realm.Description != "" {
+			provider["description"] = realm.Description
+		}
+		typeProviders, _ := providers[realm.Type].(map[string]interface{})
+		if typeProviders == nil {
+			typeProviders = map[string]interface{}{}
+			providers[realm.Type] = typeProviders
+		}
+		typeProviders[realm.Name] = provider
+	}
+	providers["basic"] = map[string]interface{}{basicProviderName: map[string]interface{}{"order": len(realms)}}
+
+	return settings.MustCanonicalConfig(map[string]interface{}{XpackSecurityAuthcProviders: providers}), nil
+}
@@ -0,0 +1,93 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_ssoProvidersSettings(t *testing.T) {
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Spec:       kbv1.KibanaSpec{ElasticsearchRef: commonv1.ObjectSelector{Name: "es"}},
+	}
+	kibanaRef := commonv1.LocalObjectSelector{Name: "kb"}
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{Auth: esv1.Auth{
+			OIDC: []esv1.OIDCRealm{
+				{SSORealm: esv1.SSORealm{Name: "okta", Order: ptr.To[int32](5), KibanaRef: kibanaRef, Description: "Log in with Okta"}},
+				{SSORealm: esv1.SSORealm{Name: "other-kibana", KibanaRef: commonv1.LocalObjectSelector{Name: "other"}}},
+			},
+			SAML: []esv1.SAMLRealm{
+				{SSORealm: esv1.SSORealm{Name: "azure", Order: ptr.To[int32](1), KibanaRef: kibanaRef}},
+			},
+		}},
+	}
+	providers := settings.MustCanonicalConfig(map[string]interface{}{
+		"xpack.security.authc.providers": map[string]interface{}{
+			"saml":  map[string]interface{}{"azure": map[string]interface{}{"order": 0, "realm": "azure"}},
+			"oidc":  map[string]interface{}{"okta": map[string]interface{}{"order": 1, "realm": "okta", "description": "Log in with Okta"}},
+			"basic": map[string]interface{}{"basic1": map[string]interface{}{"order": 2}},
+		},
+	})
+	tests := []struct {
+		name         string
+		client       k8s.Client
+		version      version.Version
+		userSettings *settings.CanonicalConfig
+		want         *settings.CanonicalConfig
+	}{
+		{
+			name:         "providers of the realms using this Kibana",
+			client:       k8s.NewFakeClient(&es),
+			version:      version.From(8, 15, 0),
+			userSettings: settings.NewCanonicalConfig(),
+			want:         providers,
+		},
+		{
+			name:         "no provider before 7.7.0",
+			client:       k8s.NewFakeClient(&es),
+			version:      version.From(7, 6, 0),
+			userSettings: settings.NewCanonicalConfig(),
+			want:         settings.NewCanonicalConfig(),
+		},
+		{
+			name:    "no provider if configured by the user",
+			client:  k8s.NewFakeClient(&es),
+			version: version.From(8, 15, 0),
+			userSettings: settings.MustCanonicalConfig(map[string]interface{}{
+				"xpack.security.authc.providers.basic.basic1.order": 0,
+			}),
+			want: settings.NewCanonicalConfig(),
+		},
+		{
+			name:         "no provider if Elasticsearch does not exist",
+			client:       k8s.NewFakeClient(),
+			version:      version.From(8, 15, 0),
+			userSettings: settings.NewCanonicalConfig(),
+			want:         settings.NewCanonicalConfig(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ssoProvidersSettings(context.Background(), tt.client, kb, tt.version, tt.userSettings)
+			require.NoError(t, err)
+			require.Empty(t, got.Diff(tt.want, nil))
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibanaspace
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	kibanaspacev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibanaspace/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	controllerName = "kibanaspace-controller"
+
+	// KibanaSpaceFinalizer lets the operator delete the space from Kibana before the KibanaSpace resource is deleted.
+	KibanaSpaceFinalizer = "kibanaspace.k8s.elastic.co/delete-space"
+)
+
+// config identifies the KibanaSpace controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "KibanaSpace",
+	NameField:      "kibanaspace_name",
+	Finalizer:      KibanaSpaceFinalizer,
+}
+
+// Add creates a new KibanaSpace Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	dynamicWatches := watches.NewDynamicWatches()
+	r := newReconciler(mgr, params, dynamicWatches)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Kibana and reconcile the KibanaSpace resources referencing them
+		source.Kind[client.Object](mgr.GetCache(), &kbv1.Kibana{}, reconcileRequestForKibanaSpaces(r.Client)),
+		// watch dynamically referenced ConfigMaps holding saved objects
+		watches.Kind(mgr.GetCache(), &corev1.ConfigMap{}, dynamicWatches.ConfigMaps),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of KibanaSpace, registering the watches of saved objects in the
+// given dynamic watches.
+func newReconciler(
+	mgr manager.Manager,
+	params operator.Parameters,
+	dynamicWatches watches.DynamicWatches,
+) *apiresource.Reconciler[*kibanaspacev1alpha1.KibanaSpace, kibanaspacev1alpha1.KibanaSpaceStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &spaceKind{
+		Client:           c,
+		kbClientProvider: kbclient.NewClient,
+		recorder:         recorder,
+		dynamicWatches:   dynamicWatches,
+		params:           params,
+	})
+}
+
+// reconcileRequestForKibanaSpaces returns the requests to reconcile the KibanaSpace resources that reference the watched Kibana.
+func reconcileRequestForKibanaSpaces(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &kibanaspacev1alpha1.KibanaSpaceList{}, func(space *kibanaspacev1alpha1.KibanaSpace, obj client.Object) bool {
+		return space.References(k8s.ExtractNamespacedName(obj))
+	})
+}
+
+func savedObjectsWatchName(watcher types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-saved-objects", watcher.Namespace, watcher.Name)
+}
+
+// spaceKind configures KibanaSpace resources in Kibana.
+type spaceKind struct {
+	k8s.Client
+	kbClientProvider kbclient.Provider
+	recorder         record.EventRecorder
+	dynamicWatches   watches.DynamicWatches
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*kibanaspacev1alpha1.KibanaSpace, kibanaspacev1alpha1.KibanaSpaceStatus] = &spaceKind{}
+	_ apiresource.Remover[*kibanaspacev1alpha1.KibanaSpace]                                     = &spaceKind{}
+	_ apiresource.Forgetter                                                                     = &spaceKind{}
+)
+
+func (r *spaceKind) NewObject() *kibanaspacev1alpha1.KibanaSpace {
+	return &kibanaspacev1alpha1.KibanaSpace{}
+}
+
+func (r *spaceKind) GetStatus(space *kibanaspacev1alpha1.KibanaSpace) kibanaspacev1alpha1.KibanaSpaceStatus {
+	return space.Status
+}
+
+func (r *spaceKind) SetStatus(space *kibanaspacev1alpha1.KibanaSpace, status kibanaspacev1alpha1.KibanaSpaceStatus) {
+	space.Status = status
+}
+
+func (r *spaceKind) InvalidStatus(space *kibanaspacev1alpha1.KibanaSpace, err error) kibanaspacev1alpha1.KibanaSpaceStatus {
+	status := space.Status
+	status.Phase = kibanaspacev1alpha1.InvalidPhase
+	status.Message = err.Error()
+	return status
+}
+
+// Configure configures the space on the referenced Kibana, and watches the ConfigMaps holding its saved objects.
+func (r *spaceKind) Configure(ctx context.Context, obj *kibanaspacev1alpha1.KibanaSpace) (*reconciler.Results, kibanaspacev1alpha1.KibanaSpaceStatus) {
+	space := *obj
+	results := reconciler.NewResult(ctx)
+
+	// import the saved objects again when their ConfigMaps change
+	if err := r.watchSavedObjects(space); err != nil {
+		return results.WithError(err), space.Status
+	}
+
+	// configure the space on the referenced Kibana
+	status := r.reconcileSpace(ctx, space)
+
+	results.WithResult(apiresource.Requeue(status.Phase == kibanaspacev1alpha1.ReadyPhase))
+
+	return results, status
+}
+
+// Remove deletes the space from Kibana.
+func (r *spaceKind) Remove(ctx context.Context, obj *kibanaspacev1alpha1.KibanaSpace) (reconcile.Result, error) {
+	return reconcile.Result{}, r.deleteSpace(ctx, *obj)
+}
+
+// Forget stops watching the ConfigMaps holding the saved objects of a deleted space.
+func (r *spaceKind) Forget(space types.NamespacedName) {
+	r.dynamicWatches.ConfigMaps.RemoveHandlerForKey(savedObjectsWatchName(space))
+}
+
+// watchSavedObjects registers a watch on the ConfigMaps holding the saved objects of the space.
+func (r *spaceKind) watchSavedObjects(space kibanaspacev1alpha1.KibanaSpace) error {
+	watcher := k8s.ExtractNamespacedName(&space)
+	watchName := savedObjectsWatchName(watcher)
+	if len(space.Spec.SavedObjects) == 0 {
+		r.dynamicWatches.ConfigMaps.RemoveHandlerForKey(watchName)
+		return nil
+	}
+	watched := make([]types.NamespacedName, 0, len(space.Spec.SavedObjects))
+	for _, name := range space.ConfigMapNames() {
+		watched = append(watched, types.NamespacedName{Namespace: space.Namespace, Name: name})
+	}
+	return r.dynamicWatches.ConfigMaps.AddHandler(watches.NamedWatch[*corev1.ConfigMap]{
+		Name:    watchName,
+		Watched: watched,
+		Watcher: watcher,
+	})
+}
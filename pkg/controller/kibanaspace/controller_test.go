@@ -0,0 +1,281 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibanaspace
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	kibanaspacev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibanaspace/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeKbClient stores spaces in memory, and records the imported saved objects files.
+type fakeKbClient struct {
+	// Client is embedded for the APIs the controller does not use
+	kbclient.Client
+	kibana *fakeKibana
+}
+
+type fakeKibana struct {
+	spaces  map[string]kbclient.Space
+	updates int
+	// imports are the contents of the imported files, indexed by space
+	imports map[string][]string
+	// importErrors are returned for the files of the given content
+	importErrors map[string][]kbclient.SavedObjectsImportError
+}
+
+func newFakeKibana() *fakeKibana {
+	return &fakeKibana{
+		spaces:       map[string]kbclient.Space{},
+		imports:      map[string][]string{},
+		importErrors: map[string][]kbclient.SavedObjectsImportError{},
+	}
+}
+
+func (c fakeKbClient) GetSpace(_ context.Context, id string) (kbclient.Space, error) {
+	space, exists := c.kibana.spaces[id]
+	if !exists {
+		return kbclient.Space{}, &commonhttp.APIError{StatusCode: http.StatusNotFound}
+	}
+	return space, nil
+}
+
+func (c fakeKbClient) CreateSpace(_ context.Context, space kbclient.Space) error {
+	if _, exists := c.kibana.spaces[space.ID]; exists {
+		return fmt.Errorf("space %s already exists", space.ID)
+	}
+	// Kibana chooses a color if none is given
+	if space.Color == "" {
+		space.Color = "#E7664C"
+	}
+	c.kibana.spaces[space.ID] = space
+	return nil
+}
+
+func (c fakeKbClient) UpdateSpace(_ context.Context, space kbclient.Space) error {
+	if _, exists := c.kibana.spaces[space.ID]; !exists {
+		return &commonhttp.APIError{StatusCode: http.StatusNotFound}
+	}
+	c.kibana.updates++
+	c.kibana.spaces[space.ID] = space
+	return nil
+}
+
+func (c fakeKbClient) DeleteSpace(_ context.Context, id string) error {
+	if _, exists := c.kibana.spaces[id]; !exists {
+		return &commonhttp.APIError{StatusCode: http.StatusNotFound}
+	}
+	delete(c.kibana.spaces, id)
+	return nil
+}
+
+func (c fakeKbClient) ImportSavedObjects(_ context.Context, spaceID, _ string, ndjson []byte) (kbclient.SavedObjectsImportResponse, error) {
+	c.kibana.imports[spaceID] = append(c.kibana.imports[spaceID], string(ndjson))
+	importErrors := c.kibana.importErrors[string(ndjson)]
+	return kbclient.SavedObjectsImportResponse{Success: len(importErrors) == 0, SuccessCount: 1, Errors: importErrors}, nil
+}
+
+func (c fakeKbClient) Close() {}
+
+func fakeClientProvider(kibana *fakeKibana) kbclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ kbv1.Kibana) (kbclient.Client, error) {
+		return fakeKbClient{kibana: kibana}, nil
+	}
+}
+
+func mkKibanaSpace(name, spaceID string) *kibanaspacev1alpha1.KibanaSpace {
+	return &kibanaspacev1alpha1.KibanaSpace{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Spec: kibanaspacev1alpha1.KibanaSpaceSpec{
+			KibanaRef:        commonv1.LocalObjectSelector{Name: "kb"},
+			SpaceID:          spaceID,
+			DisplayName:      "Team A",
+			DisabledFeatures: []string{"dev_tools", "canvas"},
+		},
+	}
+}
+
+func mkKibana(health commonv1.DeploymentHealth) *kbv1.Kibana {
+	return &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Status:     kbv1.KibanaStatus{DeploymentStatus: commonv1.DeploymentStatus{Health: health}},
+	}
+}
+
+func newTestReconciler(
+	kibana *fakeKibana,
+	recorder record.EventRecorder,
+	objs ...client.Object,
+) (*apiresource.Reconciler[*kibanaspacev1alpha1.KibanaSpace, kibanaspacev1alpha1.KibanaSpaceStatus], *spaceKind) {
+	c := k8s.NewFakeClient(objs...)
+	kind := &spaceKind{
+		Client:           c,
+		kbClientProvider: fakeClientProvider(kibana),
+		recorder:         recorder,
+		dynamicWatches:   watches.NewDynamicWatches(),
+	}
+	return apiresource.NewReconciler(c, recorder, operator.Parameters{}, config, kind), kind
+}
+
+func TestReconcileKibanaSpace_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	space := mkKibanaSpace("team-a", "")
+	space.Spec.SavedObjects = []kibanaspacev1alpha1.SavedObjectsSource{{ConfigMapName: "dashboards"}}
+	dashboards := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "dashboards"},
+		Data:       map[string]string{"overview.ndjson": "overview", "errors.ndjson": "errors"},
+	}
+	kb := mkKibana(commonv1.RedHealth)
+	kibana := newFakeKibana()
+	recorder := record.NewFakeRecorder(10)
+	r, kind := newTestReconciler(kibana, recorder, space, dashboards, kb)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "team-a"}}
+	getSpace := func() kibanaspacev1alpha1.KibanaSpace {
+		var actual kibanaspacev1alpha1.KibanaSpace
+		require.NoError(t, r.Client.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+
+	// nothing is configured until Kibana is ready
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	actual := getSpace()
+	require.Equal(t, kibanaspacev1alpha1.ApplyingChangesPhase, actual.Status.Phase)
+	require.Equal(t, []string{KibanaSpaceFinalizer}, actual.Finalizers)
+	require.Empty(t, kibana.spaces)
+	require.Equal(t, []string{"ns-team-a-saved-objects"}, kind.dynamicWatches.ConfigMaps.Registrations())
+
+	// the space is created and the saved objects imported, in the order of the keys, once Kibana is ready
+	kb.Status.Health = commonv1.GreenHealth
+	require.NoError(t, r.Client.Status().Update(ctx, kb))
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	actual = getSpace()
+	require.Equal(t, kibanaspacev1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, "team-a", actual.Status.SpaceID)
+	require.NotEmpty(t, actual.Status.SavedObjectsHash)
+	require.Equal(t, kbclient.Space{ID: "team-a", Name: "Team A", Color: "#E7664C", DisabledFeatures: []string{"dev_tools", "canvas"}}, kibana.spaces["team-a"])
+	require.Equal(t, []string{"errors", "overview"}, kibana.imports["team-a"])
+
+	// nothing is updated nor imported again when the space and the saved objects are unchanged, the color chosen by
+	// Kibana and the order of the disabled features are not drifts
+	modified := kibana.spaces["team-a"]
+	modified.DisabledFeatures = []string{"canvas", "dev_tools"}
+	kibana.spaces["team-a"] = modified
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 0, kibana.updates)
+	require.Len(t, kibana.imports["team-a"], 2)
+	require.Empty(t, recorder.Events)
+
+	// the space is restored if it is modified in Kibana, without importing the saved objects again
+	modified.Name = "Renamed"
+	kibana.spaces["team-a"] = modified
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "Team A", kibana.spaces["team-a"].Name)
+	require.Len(t, kibana.imports["team-a"], 2)
+	require.NotNil(t, getSpace().Status.LastDriftTime)
+	require.Contains(t, <-recorder.Events, "Space team-a was modified in Kibana kb")
+
+	// the saved objects are imported again when the ConfigMap changes, and the import errors are reported
+	dashboards.Data["overview.ndjson"] = "overview-v2"
+	require.NoError(t, r.Client.Update(ctx, dashboards))
+	kibana.importErrors["overview-v2"] = []kbclient.SavedObjectsImportError{
+		{ID: "overview", Type: "dashboard", Error: kbclient.SavedObjectsImportErrorReason{Type: "missing_references"}},
+	}
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	require.Equal(t, []string{"errors", "overview", "errors", "overview-v2"}, kibana.imports["team-a"])
+	actual = getSpace()
+	require.Equal(t, kibanaspacev1alpha1.ErrorPhase, actual.Status.Phase)
+	require.Equal(t, "1 saved objects could not be imported into space team-a on Kibana kb", actual.Status.Message)
+	require.Equal(t, []kibanaspacev1alpha1.SavedObjectImportError{
+		{ConfigMapName: "dashboards", Key: "overview.ndjson", Type: "dashboard", ID: "overview", Error: "missing_references"},
+	}, actual.Status.ImportErrors)
+	require.Contains(t, <-recorder.Events, "1 saved objects could not be imported")
+
+	// the import errors are cleared once the saved objects are imported successfully
+	delete(kibana.importErrors, "overview-v2")
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	actual = getSpace()
+	require.Equal(t, kibanaspacev1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Empty(t, actual.Status.ImportErrors)
+
+	// the space is deleted from Kibana with the KibanaSpace
+	require.NoError(t, r.Client.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, kibana.spaces)
+	require.Empty(t, kind.dynamicWatches.ConfigMaps.Registrations())
+	err = r.Client.Get(ctx, request.NamespacedName, &kibanaspacev1alpha1.KibanaSpace{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileKibanaSpace_MissingConfigMap(t *testing.T) {
+	ctx := context.Background()
+	space := mkKibanaSpace("team-a", "")
+	space.Spec.SavedObjects = []kibanaspacev1alpha1.SavedObjectsSource{{ConfigMapName: "dashboards"}}
+	kibana := newFakeKibana()
+	r, _ := newTestReconciler(kibana, record.NewFakeRecorder(10), space, mkKibana(commonv1.GreenHealth))
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "team-a"}}
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	var actual kibanaspacev1alpha1.KibanaSpace
+	require.NoError(t, r.Client.Get(ctx, request.NamespacedName, &actual))
+	require.Equal(t, kibanaspacev1alpha1.ErrorPhase, actual.Status.Phase)
+	require.Equal(t, `configmaps "dashboards" not found`, actual.Status.Message)
+	require.Empty(t, kibana.spaces)
+}
+
+func TestReconcileKibanaSpace_SpaceConflict(t *testing.T) {
+	ctx := context.Background()
+	kibana := newFakeKibana()
+	r, _ := newTestReconciler(kibana, record.NewFakeRecorder(10), mkKibanaSpace("team-a", "team-a"), mkKibanaSpace("team-a-copy", "team-a"), mkKibana(commonv1.GreenHealth))
+
+	// only the first KibanaSpace by name configures the space
+	for _, name := range []string{"team-a-copy", "team-a"} {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: name}})
+		require.NoError(t, err)
+	}
+	var duplicate kibanaspacev1alpha1.KibanaSpace
+	require.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "team-a-copy"}, &duplicate))
+	require.Equal(t, kibanaspacev1alpha1.ErrorPhase, duplicate.Status.Phase)
+	require.Equal(t, "KibanaSpace team-a already configures space team-a in Kibana kb", duplicate.Status.Message)
+	require.Contains(t, kibana.spaces, "team-a")
+
+	// deleting the duplicate leaves the space in Kibana
+	require.NoError(t, r.Client.Delete(ctx, &duplicate))
+	_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "team-a-copy"}})
+	require.NoError(t, err)
+	require.Contains(t, kibana.spaces, "team-a")
+}
@@ -0,0 +1,90 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibanaspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	kibanaspacev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibanaspace/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// savedObjectsFile is an NDJSON export file of saved objects, held by an entry of a ConfigMap.
+type savedObjectsFile struct {
+	ConfigMapName string
+	Key           string
+	Content       string
+}
+
+// savedObjectsFiles returns the saved objects files of the space, in the order of the ConfigMaps of the specification
+// and of their keys.
+func (r *spaceKind) savedObjectsFiles(ctx context.Context, space kibanaspacev1alpha1.KibanaSpace) ([]savedObjectsFile, error) {
+	var files []savedObjectsFile
+	for _, name := range space.ConfigMapNames() {
+		var configMap corev1.ConfigMap
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: space.Namespace, Name: name}, &configMap); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, apierrors.NewNotFound(corev1.Resource("configmaps"), name)
+			}
+			return nil, err
+		}
+		keys := make([]string, 0, len(configMap.Data))
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			files = append(files, savedObjectsFile{ConfigMapName: name, Key: key, Content: configMap.Data[key]})
+		}
+	}
+	return files, nil
+}
+
+// savedObjectsHash returns a hash of the saved objects files imported into the given space, empty if there is none.
+func savedObjectsHash(spaceID string, files []savedObjectsFile) string {
+	if len(files) == 0 {
+		return ""
+	}
+	return hash.HashObject(struct {
+		SpaceID string
+		Files   []savedObjectsFile
+	}{SpaceID: spaceID, Files: files})
+}
+
+// importSavedObjects imports the saved objects files into the space, and returns the errors reported by Kibana for
+// the saved objects that could not be imported.
+func importSavedObjects(ctx context.Context, kbClient kbclient.Client, spaceID string, files []savedObjectsFile) ([]kibanaspacev1alpha1.SavedObjectImportError, error) {
+	log := ulog.FromContext(ctx).WithValues("space_id", spaceID)
+	var importErrors []kibanaspacev1alpha1.SavedObjectImportError
+	for _, file := range files {
+		log.Info("Importing saved objects", "configmap_name", file.ConfigMapName, "key", file.Key)
+		response, err := kbClient.ImportSavedObjects(ctx, spaceID, file.Key, []byte(file.Content))
+		if err != nil {
+			return nil, fmt.Errorf("ConfigMap %s, key %s: %w", file.ConfigMapName, file.Key, err)
+		}
+		for _, importErr := range response.Errors {
+			reason := importErr.Error.Type
+			if importErr.Error.Message != "" {
+				reason += ": " + importErr.Error.Message
+			}
+			importErrors = append(importErrors, kibanaspacev1alpha1.SavedObjectImportError{
+				ConfigMapName: file.ConfigMapName,
+				Key:           file.Key,
+				Type:          importErr.Type,
+				ID:            importErr.ID,
+				Error:         reason,
+			})
+		}
+	}
+	return importErrors, nil
+}
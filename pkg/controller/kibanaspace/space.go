@@ -0,0 +1,229 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibanaspace
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	kibanaspacev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibanaspace/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/kbclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileSpace configures the space on the referenced Kibana if it does not exist yet or if it differs from the
+// specification, imports its saved objects if they changed, and returns the status of the space.
+func (r *spaceKind) reconcileSpace(ctx context.Context, space kibanaspacev1alpha1.KibanaSpace) kibanaspacev1alpha1.KibanaSpaceStatus {
+	defer tracing.Span(&ctx)()
+	kbName := space.Spec.KibanaRef.Name
+	log := ulog.FromContext(ctx).WithValues("kibana_name", kbName)
+
+	previous := space.Status
+	status := kibanaspacev1alpha1.KibanaSpaceStatus{
+		Phase:              kibanaspacev1alpha1.ReadyPhase,
+		SpaceID:            space.SpaceIDOrDefault(),
+		SavedObjectsHash:   previous.SavedObjectsHash,
+		ImportErrors:       previous.ImportErrors,
+		LastDriftTime:      previous.LastDriftTime,
+		ObservedGeneration: space.Generation,
+	}
+	withPhase := func(phase kibanaspacev1alpha1.Phase, msg string) kibanaspacev1alpha1.KibanaSpaceStatus {
+		status.Phase = phase
+		status.Message = msg
+		return status
+	}
+	failed := func(msg string) kibanaspacev1alpha1.KibanaSpaceStatus {
+		r.recorder.Event(&space, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return withPhase(kibanaspacev1alpha1.ErrorPhase, msg)
+	}
+
+	var kb kbv1.Kibana
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: space.Namespace, Name: kbName}, &kb); err != nil {
+		if apierrors.IsNotFound(err) {
+			return withPhase(kibanaspacev1alpha1.ErrorPhase, fmt.Sprintf("Kibana %s not found", kbName))
+		}
+		return withPhase(kibanaspacev1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	if kb.Status.Health != commonv1.GreenHealth {
+		return withPhase(kibanaspacev1alpha1.ApplyingChangesPhase, "Waiting for Kibana to be ready")
+	}
+
+	owner, err := r.spaceOwner(ctx, space)
+	if err != nil {
+		return withPhase(kibanaspacev1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	if owner != space.Name {
+		return failed(fmt.Sprintf("KibanaSpace %s already configures space %s in Kibana %s", owner, status.SpaceID, kbName))
+	}
+
+	files, err := r.savedObjectsFiles(ctx, space)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return withPhase(kibanaspacev1alpha1.ErrorPhase, err.Error())
+		}
+		return withPhase(kibanaspacev1alpha1.ApplyingChangesPhase, err.Error())
+	}
+
+	kbClient, err := r.kbClientProvider(ctx, r.Client, r.params.Dialer, kb)
+	if err != nil {
+		return withPhase(kibanaspacev1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	defer kbClient.Close()
+
+	// changes are drifts if the space was already configured from the current specification
+	configured := previous.Phase == kibanaspacev1alpha1.ReadyPhase && previous.ObservedGeneration == space.Generation
+
+	created, changed, err := reconcileDefinition(ctx, kbClient, expectedSpace(space))
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to configure space on Kibana %s: %s", kbName, err.Error()))
+	}
+	if configured && (created || changed) {
+		msg := fmt.Sprintf("Space %s was modified in Kibana %s, restoring it from the KibanaSpace specification", status.SpaceID, kbName)
+		status.LastDriftTime = apiresource.RecordDrift(log, r.recorder, &space, msg)
+	}
+
+	// import the saved objects if they changed, or if the space was (re)created
+	filesHash := savedObjectsHash(status.SpaceID, files)
+	if !created && filesHash == previous.SavedObjectsHash {
+		return status
+	}
+	importErrors, err := importSavedObjects(ctx, kbClient, status.SpaceID, files)
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to import saved objects into space %s on Kibana %s: %s", status.SpaceID, kbName, err.Error()))
+	}
+	status.ImportErrors = importErrors
+	if len(importErrors) > 0 {
+		return failed(fmt.Sprintf("%d saved objects could not be imported into space %s on Kibana %s", len(importErrors), status.SpaceID, kbName))
+	}
+	status.SavedObjectsHash = filesHash
+	return status
+}
+
+// spaceOwner returns the name of the KibanaSpace that should configure the space of the given KibanaSpace: the first
+// one, by name, of the KibanaSpace resources configuring the same space on the same Kibana.
+func (r *spaceKind) spaceOwner(ctx context.Context, space kibanaspacev1alpha1.KibanaSpace) (string, error) {
+	var spaces kibanaspacev1alpha1.KibanaSpaceList
+	if err := r.Client.List(ctx, &spaces, client.InNamespace(space.Namespace)); err != nil {
+		return "", err
+	}
+	kb := space.Spec.KibanaRef.WithDefaultNamespace(space.Namespace).NamespacedName()
+	var candidates []string
+	for _, other := range spaces.Items {
+		if !other.IsMarkedForDeletion() && other.References(kb) && other.SpaceIDOrDefault() == space.SpaceIDOrDefault() {
+			candidates = append(candidates, other.Name)
+		}
+	}
+	if len(candidates) == 0 {
+		return space.Name, nil
+	}
+	sort.Strings(candidates)
+	return candidates[0], nil
+}
+
+// reconcileDefinition creates the space if it does not exist, and updates it if it differs from the expected
+// definition. It returns whether the space was created, and whether it was updated.
+func reconcileDefinition(ctx context.Context, kbClient kbclient.Client, expected kbclient.Space) (bool, bool, error) {
+	log := ulog.FromContext(ctx).WithValues("space_id", expected.ID)
+	actual, err := kbClient.GetSpace(ctx, expected.ID)
+	if kbclient.IsNotFound(err) {
+		log.Info("Creating space")
+		return true, false, kbClient.CreateSpace(ctx, expected)
+	}
+	if err != nil {
+		return false, false, err
+	}
+	if sameSpace(expected, actual) {
+		return false, false, nil
+	}
+	log.Info("Updating space")
+	return false, true, kbClient.UpdateSpace(ctx, expected)
+}
+
+// deleteSpace deletes the space, and the saved objects it contains, from Kibana. The default space cannot be deleted,
+// and a space configured by another KibanaSpace is left untouched.
+func (r *spaceKind) deleteSpace(ctx context.Context, space kibanaspacev1alpha1.KibanaSpace) error {
+	defer tracing.Span(&ctx)()
+
+	if space.IsDefaultSpace() {
+		return nil
+	}
+	owner, err := r.spaceOwner(ctx, space)
+	if err != nil {
+		return err
+	}
+	if owner != space.Name {
+		return nil
+	}
+
+	var kb kbv1.Kibana
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: space.Namespace, Name: space.Spec.KibanaRef.Name}, &kb); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	kbClient, err := r.kbClientProvider(ctx, r.Client, r.params.Dialer, kb)
+	if err != nil {
+		return err
+	}
+	defer kbClient.Close()
+
+	id := space.SpaceIDOrDefault()
+	ulog.FromContext(ctx).Info("Deleting space", "space_id", id, "kibana_name", kb.Name)
+	if err := kbClient.DeleteSpace(ctx, id); err != nil && !kbclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// expectedSpace returns the space of the specification as expected by the Kibana spaces API.
+func expectedSpace(space kibanaspacev1alpha1.KibanaSpace) kbclient.Space {
+	return kbclient.Space{
+		ID:               space.SpaceIDOrDefault(),
+		Name:             space.DisplayNameOrDefault(),
+		Description:      space.Spec.Description,
+		Color:            space.Spec.Color,
+		Initials:         space.Spec.Initials,
+		DisabledFeatures: space.Spec.DisabledFeatures,
+	}
+}
+
+// sameSpace returns true if the space returned by Kibana matches the expected one. The color and the initials are
+// only compared if they are specified, since Kibana chooses them otherwise.
+func sameSpace(expected, actual kbclient.Space) bool {
+	if expected.Name != actual.Name || expected.Description != actual.Description {
+		return false
+	}
+	if expected.Color != "" && !strings.EqualFold(expected.Color, actual.Color) {
+		return false
+	}
+	if expected.Initials != "" && expected.Initials != actual.Initials {
+		return false
+	}
+	if len(expected.DisabledFeatures) == 0 && len(actual.DisabledFeatures) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(sortedCopy(expected.DisabledFeatures), sortedCopy(actual.DisabledFeatures))
+}
+
+func sortedCopy(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
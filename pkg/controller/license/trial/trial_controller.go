@@ -20,13 +20,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	licensing "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
@@ -77,6 +77,11 @@ func (r *ReconcileTrials) Reconcile(ctx context.Context, request reconcile.Reque
 		return reconcile.Result{}, nil
 	}
 
+	if common.IsUnmanaged(ctx, &secret) {
+		log.Info("Object is currently not managed by this controller. Skipping reconciliation")
+		return reconcile.Result{}, nil
+	}
+
 	validationMsg := validateEULA(secret)
 	if validationMsg != "" {
 		return reconcile.Result{}, r.invalidOperation(ctx, secret, validationMsg)
@@ -235,7 +240,7 @@ func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileTr
 
 func addWatches(mgr manager.Manager, c controller.Controller) error {
 	// Watch the trial status secret and the enterprise trial licenses as well
-	return c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{},
+	return c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{},
 		handler.TypedEnqueueRequestsFromMapFunc[*corev1.Secret](func(ctx context.Context, secret *corev1.Secret) []reconcile.Request {
 			if licensing.IsEnterpriseTrial(*secret) {
 				return []reconcile.Request{
@@ -11,11 +11,13 @@ import (
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore/external"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/pod"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
@@ -23,6 +25,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/configs"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
 )
 
 const (
@@ -87,7 +90,7 @@ func buildConfig(params Params, useTLS bool) (*settings.CanonicalConfig, error)
 		return nil, err
 	}
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(params.OperatorParams.IPFamily)
 	tls := tlsConfig(useTLS)
 
 	// merge with user settings last so they take precedence
@@ -107,10 +110,10 @@ func getUserConfig(params Params) (*settings.CanonicalConfig, error) {
 	return common.ParseConfigRef(params, &params.Logstash, params.Logstash.Spec.ConfigRef, ConfigFileName)
 }
 
-func defaultConfig() *settings.CanonicalConfig {
+func defaultConfig(ipFamily corev1.IPFamily) *settings.CanonicalConfig {
 	settingsMap := map[string]interface{}{
-		// Set 'api.http.host' by default to `0.0.0.0` for readiness probe to work.
-		"api.http.host": "0.0.0.0",
+		// Set 'api.http.host' by default to `0.0.0.0` or `::` for readiness probe to work.
+		"api.http.host": net.InAddrAnyFor(ipFamily).String(),
 		// Set `config.reload.automatic` to `true` to enable pipeline reloads by default
 		"config.reload.automatic": true,
 	}
@@ -215,7 +218,14 @@ func getKeystoreEnvKeyValues(params Params) (map[string]string, error) {
 	for _, ss := range params.Logstash.SecureSettings() {
 		secret := corev1.Secret{}
 		nsn := types.NamespacedName{Name: ss.SecretName, Namespace: params.Logstash.Namespace}
+		if ss.IsExternal() {
+			// operator-managed copy of the external secret, created with the keystore
+			nsn.Name = external.SecretName(logstashv1alpha1.Namer, params.Logstash.Name, *ss.External)
+		}
 		if err := params.Client.Get(params.Context, nsn, &secret); err != nil {
+			if ss.IsExternal() && apierrors.IsNotFound(err) {
+				continue
+			}
 			return nil, err
 		}
 
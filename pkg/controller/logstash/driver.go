@@ -22,6 +22,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemonitor"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
@@ -100,6 +101,16 @@ func internalReconcile(params Params) (*reconciler.Results, logstashv1alpha1.Log
 		return results.WithError(err), params.Status
 	}
 
+	if err := servicemonitor.Reconcile(params.Context, params.Client, servicemonitor.Params{
+		Owner:      &params.Logstash,
+		Prometheus: params.Logstash.Spec.Monitoring.Prometheus,
+		Name:       logstashv1alpha1.Namer.Suffix(params.Logstash.Name, "metrics"),
+		Labels:     labels.NewLabels(params.Logstash),
+		Selector:   params.Logstash.GetPodIdentityLabels(),
+	}); err != nil {
+		return results.WithError(err), params.Status
+	}
+
 	apiSvcTLS := params.Logstash.APIServerTLSOptions()
 
 	_, results = certificates.Reconciler{
@@ -111,8 +122,8 @@ func internalReconcile(params Params) (*reconciler.Results, logstashv1alpha1.Log
 		Labels:                labels.NewLabels(params.Logstash),
 		Services:              []corev1.Service{apiSvc},
 		GlobalCA:              params.OperatorParams.GlobalCA,
-		CACertRotation:        params.OperatorParams.CACertRotation,
-		CertRotation:          params.OperatorParams.CertRotation,
+		CACertRotation:        params.OperatorParams.CACertRotation.Get(),
+		CertRotation:          params.OperatorParams.CertRotation.Get(),
 		GarbageCollectSecrets: true,
 	}.ReconcileCAAndHTTPCerts(params.Context)
 	if results.HasError() {
@@ -25,7 +25,9 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/queue"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemonitor"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
@@ -66,7 +68,7 @@ func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileLo
 // addWatches adds watches for all resources this controller cares about
 func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileLogstash) error {
 	// Watch for changes to Logstash
-	if err := c.Watch(source.Kind(mgr.GetCache(), &logstashv1alpha1.Logstash{}, &handler.TypedEnqueueRequestForObject[*logstashv1alpha1.Logstash]{})); err != nil {
+	if err := c.Watch(source.Kind(mgr.GetCache(), &logstashv1alpha1.Logstash{}, queue.EnqueueRequestForObject[*logstashv1alpha1.Logstash]())); err != nil {
 		return err
 	}
 
@@ -93,8 +95,13 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileLogsta
 		return err
 	}
 
+	// Watch ServiceMonitors
+	if err := servicemonitor.Watch(mgr, c, &logstashv1alpha1.Logstash{}); err != nil {
+		return err
+	}
+
 	// Watch owned and soft-owned secrets
-	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
+	if err := c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
 		mgr.GetScheme(), mgr.GetRESTMapper(),
 		&logstashv1alpha1.Logstash{}, handler.OnlyControllerOwner(),
 	))); err != nil {
@@ -105,7 +112,12 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileLogsta
 	}
 
 	// Watch dynamically referenced Secrets
-	return c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets))
+	if err := c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets)); err != nil {
+		return err
+	}
+
+	// Watch dynamically referenced ConfigMaps holding pipelines
+	return c.Watch(watches.Kind(mgr.GetCache(), &corev1.ConfigMap{}, r.dynamicWatches.ConfigMaps))
 }
 
 var _ reconcile.Reconciler = &ReconcileLogstash{}
@@ -225,5 +237,7 @@ func (r *ReconcileLogstash) onDelete(ctx context.Context, obj types.NamespacedNa
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(obj))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(common.ConfigRefWatchName(obj))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(pipelines.RefWatchName(obj))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(pipelines.RefsWatchName(obj))
+	r.dynamicWatches.ConfigMaps.RemoveHandlerForKey(pipelines.RefsWatchName(obj))
 	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, obj, logstashv1alpha1.Kind)
 }
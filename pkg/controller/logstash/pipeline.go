@@ -12,6 +12,7 @@ import (
 
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
@@ -70,8 +71,28 @@ func buildPipeline(params Params) ([]byte, error) {
 }
 
 // getUserPipeline extracts the pipeline either from the spec `pipeline` field or from the Secret referenced by spec
-// `pipelineRef` field.
+// `pipelineRef` field, and appends the pipelines of the Secrets and ConfigMaps referenced by spec `pipelinesRefs` field.
 func getUserPipeline(params Params) (*pipelines.Config, error) {
+	cfg, err := getSpecPipeline(params)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := pipelines.ParsePipelinesSources(params, &params.Logstash, params.Logstash.Spec.PipelinesRefs, PipelineFileName)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return cfg, nil
+	}
+	merged, err := pipelines.Merge(append([]*pipelines.Config{cfg}, refs...)...)
+	if err != nil {
+		params.Recorder().Event(&params.Logstash, corev1.EventTypeWarning, events.EventReasonUnexpected, err.Error())
+		return nil, err
+	}
+	return merged, nil
+}
+
+func getSpecPipeline(params Params) (*pipelines.Config, error) {
 	if params.Logstash.Spec.Pipelines != nil {
 		pipes := make([]map[string]interface{}, 0, len(params.Logstash.Spec.Pipelines))
 		for _, p := range params.Logstash.Spec.Pipelines {
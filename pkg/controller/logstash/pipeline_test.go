@@ -22,12 +22,13 @@ import (
 
 func Test_buildPipeline(t *testing.T) {
 	for _, tt := range []struct {
-		name         string
-		pipelines    []commonv1.Config
-		pipelinesRef *commonv1.ConfigSource
-		client       k8s.Client
-		want         *pipelines.Config
-		wantErr      bool
+		name          string
+		pipelines     []commonv1.Config
+		pipelinesRef  *commonv1.ConfigSource
+		pipelinesRefs []logstashv1alpha1.PipelinesSource
+		client        k8s.Client
+		want          *pipelines.Config
+		wantErr       bool
 	}{
 		{
 			name: "no user pipeline",
@@ -97,6 +98,55 @@ func Test_buildPipeline(t *testing.T) {
 			}),
 			want: pipelines.MustParse([]byte(`- "pipeline.id": "main"`)),
 		},
+		{
+			name: "pipelinesrefs populated - appended to pipelines",
+			pipelines: []commonv1.Config{
+				{Data: map[string]interface{}{"pipeline.id": "main"}},
+			},
+			pipelinesRefs: []logstashv1alpha1.PipelinesSource{
+				{ConfigMapName: "my-configmap-pipeline"},
+				{SecretName: "my-secret-pipeline"},
+			},
+			client: k8s.NewFakeClient(
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-configmap-pipeline"},
+					Data:       map[string]string{"pipelines.yml": `- "pipeline.id": "from-configmap"`},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-secret-pipeline"},
+					Data:       map[string][]byte{"pipelines.yml": []byte(`- "pipeline.id": "from-secret"`)},
+				},
+			),
+			want: pipelines.MustParse([]byte(`- "pipeline.id": "main"
+- "pipeline.id": "from-configmap"
+- "pipeline.id": "from-secret"`)),
+		},
+		{
+			name: "pipelinesrefs populated - replaces the default pipeline",
+			pipelinesRefs: []logstashv1alpha1.PipelinesSource{
+				{ConfigMapName: "my-configmap-pipeline"},
+			},
+			client: k8s.NewFakeClient(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-configmap-pipeline"},
+				Data:       map[string]string{"pipelines.yml": `- "pipeline.id": "from-configmap"`},
+			}),
+			want: pipelines.MustParse([]byte(`- "pipeline.id": "from-configmap"`)),
+		},
+		{
+			name: "pipelinesrefs populated - duplicate pipeline id",
+			pipelines: []commonv1.Config{
+				{Data: map[string]interface{}{"pipeline.id": "main"}},
+			},
+			pipelinesRefs: []logstashv1alpha1.PipelinesSource{
+				{ConfigMapName: "my-configmap-pipeline"},
+			},
+			client: k8s.NewFakeClient(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-configmap-pipeline"},
+				Data:       map[string]string{"pipelines.yml": `- "pipeline.id": "main"`},
+			}),
+			want:    pipelines.EmptyConfig(),
+			wantErr: true,
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			params := Params{
@@ -106,8 +156,9 @@ func Test_buildPipeline(t *testing.T) {
 				Watches:       watches.NewDynamicWatches(),
 				Logstash: logstashv1alpha1.Logstash{
 					Spec: logstashv1alpha1.LogstashSpec{
-						Pipelines:    tt.pipelines,
-						PipelinesRef: tt.pipelinesRef,
+						Pipelines:     tt.pipelines,
+						PipelinesRef:  tt.pipelinesRef,
+						PipelinesRefs: tt.pipelinesRefs,
 					},
 				},
 			}
@@ -0,0 +1,136 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package pipelines
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+)
+
+const pipelineIDKey = "pipeline.id"
+
+// RefsWatchName returns the name of the watches registered on the Secrets and ConfigMaps referenced in `pipelinesRefs`.
+func RefsWatchName(resource types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-pipelinesrefs", resource.Namespace, resource.Name)
+}
+
+// ParsePipelinesSources retrieves the content of the Secrets and ConfigMaps referenced in `pipelinesRefs`, sets up
+// dynamic watches for them, and parses their content into one Config per source.
+func ParsePipelinesSources(
+	driver driver.Interface,
+	resource client.Object,
+	sources []lsv1alpha1.PipelinesSource,
+	key string, // retrieve pipelines from that entry in the Secrets and ConfigMaps
+) ([]*Config, error) {
+	if err := watchPipelinesSources(driver.DynamicWatches(), resource, sources); err != nil {
+		return nil, err
+	}
+
+	configs := make([]*Config, 0, len(sources))
+	for _, source := range sources {
+		kind, name, data, err := getPipelinesSource(driver.K8sClient(), resource.GetNamespace(), source, key)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			msg := fmt.Sprintf("unable to parse pipelinesRefs %s %s/%s: missing key %s", kind, resource.GetNamespace(), name, key)
+			driver.Recorder().Event(resource, corev1.EventTypeWarning, events.EventReasonUnexpected, msg)
+			return nil, errors.New(msg)
+		}
+		parsed, err := Parse(data)
+		if err != nil {
+			msg := fmt.Sprintf("unable to parse %s in pipelinesRefs %s %s/%s", key, kind, resource.GetNamespace(), name)
+			driver.Recorder().Event(resource, corev1.EventTypeWarning, events.EventReasonUnexpected, msg)
+			return nil, errors.Wrap(err, msg)
+		}
+		configs = append(configs, parsed)
+	}
+	return configs, nil
+}
+
+// watchPipelinesSources ensures the dynamic watches match the Secrets and ConfigMaps referenced in `pipelinesRefs`.
+func watchPipelinesSources(watched watches.DynamicWatches, resource client.Object, sources []lsv1alpha1.PipelinesSource) error {
+	watcher := types.NamespacedName{Namespace: resource.GetNamespace(), Name: resource.GetName()}
+	watchName := RefsWatchName(watcher)
+	var secretNames []string
+	var configMaps []types.NamespacedName
+	for _, source := range sources {
+		if source.SecretName != "" {
+			secretNames = append(secretNames, source.SecretName)
+		}
+		if source.ConfigMapName != "" {
+			configMaps = append(configMaps, types.NamespacedName{Namespace: watcher.Namespace, Name: source.ConfigMapName})
+		}
+	}
+	if err := watches.WatchUserProvidedSecrets(watcher, watched, watchName, secretNames); err != nil {
+		return err
+	}
+	if len(configMaps) == 0 {
+		watched.ConfigMaps.RemoveHandlerForKey(watchName)
+		return nil
+	}
+	return watched.ConfigMaps.AddHandler(watches.NamedWatch[*corev1.ConfigMap]{
+		Name:    watchName,
+		Watched: configMaps,
+		Watcher: watcher,
+	})
+}
+
+// getPipelinesSource returns the kind and the name of the given source, and the content of its given key, or nil if
+// the key does not exist.
+func getPipelinesSource(c client.Client, namespace string, source lsv1alpha1.PipelinesSource, key string) (string, string, []byte, error) {
+	// the Secrets and ConfigMaps may not exist (yet) in the cache, let's explicitly error out and retry later
+	if source.SecretName != "" {
+		var secret corev1.Secret
+		if err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: source.SecretName}, &secret); err != nil {
+			return "", "", nil, err
+		}
+		return "secret", source.SecretName, secret.Data[key], nil
+	}
+	var configMap corev1.ConfigMap
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: source.ConfigMapName}, &configMap); err != nil {
+		return "", "", nil, err
+	}
+	data, exists := configMap.Data[key]
+	if !exists {
+		return "configmap", source.ConfigMapName, nil, nil
+	}
+	return "configmap", source.ConfigMapName, []byte(data), nil
+}
+
+// Merge returns the pipelines of all the given configs, in order. Nil configs are ignored. It returns an error if
+// several pipelines have the same ID.
+func Merge(configs ...*Config) (*Config, error) {
+	var merged []map[string]interface{}
+	ids := make(map[string]struct{})
+	for _, c := range configs {
+		if c == nil {
+			continue
+		}
+		var pipelines []map[string]interface{}
+		if err := c.asUCfg().Unpack(&pipelines, Options...); err != nil {
+			return nil, err
+		}
+		for _, p := range pipelines {
+			id := fmt.Sprintf("%v", p[pipelineIDKey])
+			if _, exists := ids[id]; exists {
+				return nil, fmt.Errorf("duplicate pipeline.id %s", id)
+			}
+			ids[id] = struct{}{}
+		}
+		merged = append(merged, pipelines...)
+	}
+	return FromSpec(merged)
+}
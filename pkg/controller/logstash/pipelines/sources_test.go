@@ -0,0 +1,157 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package pipelines
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestParsePipelinesSources(t *testing.T) {
+	resNsn := types.NamespacedName{Namespace: "ns", Name: "resource"}
+	res := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: resNsn.Namespace, Name: resNsn.Name}}
+	watchName := RefsWatchName(resNsn)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "my-secret"},
+		Data: map[string][]byte{
+			"pipelines.yml": []byte(`- "pipeline.id": "a"`),
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "my-configmap"},
+		Data: map[string]string{
+			"pipelines.yml": `- "pipeline.id": "b"`,
+		},
+	}
+
+	tests := []struct {
+		name                  string
+		sources               []lsv1alpha1.PipelinesSource
+		runtimeObjs           []client.Object
+		want                  []*Config
+		wantErr               bool
+		existingWatches       []string
+		wantSecretWatches     []string
+		wantConfigMapsWatches []string
+		wantEvent             string
+	}{
+		{
+			name: "secret and configmap",
+			sources: []lsv1alpha1.PipelinesSource{
+				{SecretName: "my-secret"},
+				{ConfigMapName: "my-configmap"},
+			},
+			runtimeObjs: []client.Object{secret, configMap},
+			want: []*Config{
+				MustParse([]byte(`- "pipeline.id": "a"`)),
+				MustParse([]byte(`- "pipeline.id": "b"`)),
+			},
+			wantSecretWatches:     []string{watchName},
+			wantConfigMapsWatches: []string{watchName},
+		},
+		{
+			name:                  "no sources: clear existing watches",
+			runtimeObjs:           []client.Object{secret, configMap},
+			want:                  []*Config{},
+			existingWatches:       []string{watchName},
+			wantSecretWatches:     []string{},
+			wantConfigMapsWatches: []string{},
+		},
+		{
+			name:                  "configmap not found: error out but watch the future configmap",
+			sources:               []lsv1alpha1.PipelinesSource{{ConfigMapName: "my-configmap"}},
+			wantErr:               true,
+			wantSecretWatches:     []string{},
+			wantConfigMapsWatches: []string{watchName},
+		},
+		{
+			name:                  "missing key in the referenced configmap: error out and emit an event",
+			sources:               []lsv1alpha1.PipelinesSource{{ConfigMapName: "my-configmap"}},
+			runtimeObjs:           []client.Object{&corev1.ConfigMap{ObjectMeta: configMap.ObjectMeta}},
+			wantErr:               true,
+			wantSecretWatches:     []string{},
+			wantConfigMapsWatches: []string{watchName},
+			wantEvent:             "Warning Unexpected unable to parse pipelinesRefs configmap ns/my-configmap: missing key pipelines.yml",
+		},
+		{
+			name:    "invalid pipelines in the referenced secret: error out and emit an event",
+			sources: []lsv1alpha1.PipelinesSource{{SecretName: "my-secret"}},
+			runtimeObjs: []client.Object{&corev1.Secret{
+				ObjectMeta: secret.ObjectMeta,
+				Data:       map[string][]byte{"pipelines.yml": []byte("this.is invalid config")},
+			}},
+			wantErr:               true,
+			wantSecretWatches:     []string{watchName},
+			wantConfigMapsWatches: []string{},
+			wantEvent:             "Warning Unexpected unable to parse pipelines.yml in pipelinesRefs secret ns/my-secret",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeRecorder := record.NewFakeRecorder(10)
+			w := watches.NewDynamicWatches()
+			for _, existingWatch := range tt.existingWatches {
+				require.NoError(t, w.Secrets.AddHandler(watches.NamedWatch[*corev1.Secret]{Name: existingWatch}))
+				require.NoError(t, w.ConfigMaps.AddHandler(watches.NamedWatch[*corev1.ConfigMap]{Name: existingWatch}))
+			}
+			d := fakeDriver{
+				client:   k8s.NewFakeClient(tt.runtimeObjs...),
+				watches:  w,
+				recorder: fakeRecorder,
+			}
+			got, err := ParsePipelinesSources(d, &res, tt.sources, "pipelines.yml")
+			require.Equal(t, tt.wantErr, err != nil, err)
+			if !tt.wantErr {
+				require.Len(t, got, len(tt.want))
+				for i := range tt.want {
+					diff, err := tt.want[i].Diff(got[i])
+					require.False(t, diff, err)
+				}
+			}
+			require.Equal(t, tt.wantSecretWatches, d.watches.Secrets.Registrations())
+			require.Equal(t, tt.wantConfigMapsWatches, d.watches.ConfigMaps.Registrations())
+
+			if tt.wantEvent != "" {
+				require.Equal(t, tt.wantEvent, <-fakeRecorder.Events)
+			} else {
+				select {
+				case e := <-fakeRecorder.Events:
+					require.Fail(t, "no event expected but got one", "event", e)
+				default:
+				}
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := MustParse([]byte(`- "pipeline.id": "a"
+  "config.string": "input { stdin {} }"`))
+	b := MustParse([]byte(`- "pipeline.id": "b"
+  "path.config": "/usr/share/logstash/b"
+- "pipeline.id": "c"`))
+
+	merged, err := Merge(nil, a, b)
+	require.NoError(t, err)
+	diff, err := merged.Diff(MustParse([]byte(`- "pipeline.id": "a"
+  "config.string": "input { stdin {} }"
+- "pipeline.id": "b"
+  "path.config": "/usr/share/logstash/b"
+- "pipeline.id": "c"`)))
+	require.False(t, diff, err)
+
+	_, err = Merge(a, b, MustParse([]byte(`- "pipeline.id": "b"`)))
+	require.EqualError(t, err, "duplicate pipeline.id b")
+}
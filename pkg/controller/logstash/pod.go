@@ -22,6 +22,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemesh"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/network"
@@ -104,12 +105,17 @@ func buildPodTemplate(params Params, configHash hash.Hash32) (corev1.PodTemplate
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	image, err := container.ResolveImage(spec.Image, container.LogstashImage, v)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	builder = builder.
 		WithResources(DefaultResources).
 		WithLabels(labels).
 		WithAnnotations(annotations).
-		WithDockerImage(spec.Image, container.ImageRepository(container.LogstashImage, v)).
+		WithAnnotations(servicemesh.PodAnnotations(params.Logstash.APIServerTLSOptions())).
+		WithDockerImage(spec.Image, image).
 		WithAutomountServiceAccountToken().
 		WithPorts(ports).
 		WithReadinessProbe(readinessProbe(params)).
@@ -88,7 +88,7 @@ func reconcileStatefulSet(params Params, podTemplate corev1.PodTemplateSpec) (*r
 	}
 
 	if !notFound {
-		recreateSset, err := volume.HandleVolumeExpansion(params.Context, params.Client, params.Logstash, expected, actualStatefulSet, true)
+		recreateSset, err := volume.HandleVolumeExpansion(params.Context, params.Client, params.Logstash, &expected, actualStatefulSet, true)
 		if err != nil {
 			return results.WithError(err), params.Status
 		}
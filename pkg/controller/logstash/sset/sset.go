@@ -81,16 +81,11 @@ func Reconcile(ctx context.Context, c k8s.Client, expected appsv1.StatefulSet, l
 				// different spec
 				!EqualTemplateHashLabels(expected, reconciled)
 		},
-		UpdateReconciled: func() {
-			// override annotations and labels with expected ones
-			// don't remove additional values in reconciled that may have been defaulted or
-			// manually set by the user on the existing resource
-			reconciled.Labels = maps.Merge(reconciled.Labels, expected.Labels)
-			reconciled.Annotations = maps.Merge(reconciled.Annotations, expected.Annotations)
-			reconciled.Spec = expected.Spec
-		},
-		PreCreate: podTemplateValidator,
-		PreUpdate: podTemplateValidator,
+		// only apply the labels, annotations and spec fields set by the operator, the ones that may have been
+		// defaulted or manually set by the user or another controller on the existing resource are left untouched
+		ServerSideApply: true,
+		PreCreate:       podTemplateValidator,
+		PreUpdate:       podTemplateValidator,
 		PostUpdate: func() {
 			if expectations != nil {
 				// expect the reconciled StatefulSet to be there in the cache for next reconciliations,
@@ -48,6 +48,11 @@ func validations() []validation {
 		checkESRefsNamed,
 		checkAssociations,
 		checkSinglePipelineSource,
+		checkPipelinesRefs,
+		checkPrometheusExporter,
+		checkServiceMesh,
+		checkCertificateRotation,
+		checkSecureSettings,
 	}
 }
 
@@ -102,6 +107,27 @@ func checkSinglePipelineSource(a *lsv1alpha1.Logstash) field.ErrorList {
 	return nil
 }
 
+func checkPipelinesRefs(l *lsv1alpha1.Logstash) field.ErrorList {
+	var errorList field.ErrorList
+	for i, ref := range l.Spec.PipelinesRefs {
+		if (ref.SecretName == "") == (ref.ConfigMapName == "") {
+			errorList = append(errorList, field.Invalid(
+				field.NewPath("spec").Child("pipelinesRefs").Index(i), ref,
+				"Specify exactly one of [`secretName`, `configMapName`]",
+			))
+		}
+	}
+	return errorList
+}
+
+func checkPrometheusExporter(l *lsv1alpha1.Logstash) field.ErrorList {
+	if l.Spec.Monitoring.Prometheus != nil && l.Spec.Monitoring.Prometheus.Exporter != nil {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("monitoring").Child("prometheus").Child("exporter"),
+			"the Prometheus exporter sidecar is only supported for Elasticsearch")}
+	}
+	return nil
+}
+
 func checkESRefsNamed(l *lsv1alpha1.Logstash) field.ErrorList {
 	var errorList field.ErrorList
 	for i, esRef := range l.Spec.ElasticsearchRefs {
@@ -117,6 +143,26 @@ func checkESRefsNamed(l *lsv1alpha1.Logstash) field.ErrorList {
 	return errorList
 }
 
+func checkSecureSettings(l *lsv1alpha1.Logstash) field.ErrorList {
+	return commonv1.CheckSecretSources(field.NewPath("spec").Child("secureSettings"), l.Spec.SecureSettings, l.Namespace, true)
+}
+
+func checkServiceMesh(l *lsv1alpha1.Logstash) field.ErrorList {
+	var errorList field.ErrorList
+	for i, service := range l.Spec.Services {
+		errorList = append(errorList, commonv1.CheckServiceMesh(field.NewPath("spec").Child("services").Index(i).Child("tls"), service.TLS)...)
+	}
+	return errorList
+}
+
+func checkCertificateRotation(l *lsv1alpha1.Logstash) field.ErrorList {
+	var errorList field.ErrorList
+	for i, service := range l.Spec.Services {
+		errorList = append(errorList, commonv1.CheckCertificateRotation(field.NewPath("spec").Child("services").Index(i).Child("tls", "certificateRotation"), service.TLS.CertificateRotation)...)
+	}
+	return errorList
+}
+
 // checkPVCchanges ensures no PVCs are changed, as volume claim templates are immutable in StatefulSets.
 func checkPVCchanges(ctx context.Context, current *lsv1alpha1.Logstash, proposed *lsv1alpha1.Logstash, k8sClient k8s.Client, validateStorageClass bool) field.ErrorList {
 	var errs field.ErrorList
@@ -199,6 +199,53 @@ func Test_checkSinglePipelineSource(t *testing.T) {
 	}
 }
 
+func Test_checkPipelinesRefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		refs    []lsv1alpha1.PipelinesSource
+		wantErr bool
+	}{
+		{
+			name:    "no refs",
+			wantErr: false,
+		},
+		{
+			name: "secrets and configmaps",
+			refs: []lsv1alpha1.PipelinesSource{
+				{SecretName: "pipelines-a"},
+				{ConfigMapName: "pipelines-b"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "neither secretName nor configMapName",
+			refs:    []lsv1alpha1.PipelinesSource{{SecretName: "pipelines-a"}, {}},
+			wantErr: true,
+		},
+		{
+			name:    "both secretName and configMapName",
+			refs:    []lsv1alpha1.PipelinesSource{{SecretName: "pipelines-a", ConfigMapName: "pipelines-b"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := checkPipelinesRefs(&lsv1alpha1.Logstash{Spec: lsv1alpha1.LogstashSpec{PipelinesRefs: tc.refs}})
+			assert.Equal(t, tc.wantErr, len(got) > 0)
+		})
+	}
+}
+
+func Test_checkPrometheusExporter(t *testing.T) {
+	ls := lsv1alpha1.Logstash{}
+	assert.Empty(t, checkPrometheusExporter(&ls))
+	ls.Spec.Monitoring.Prometheus = &commonv1.PrometheusMonitoring{Port: 9198}
+	assert.Empty(t, checkPrometheusExporter(&ls))
+	ls.Spec.Monitoring.Prometheus.Exporter = &commonv1.PrometheusExporter{}
+	assert.Len(t, checkPrometheusExporter(&ls), 1)
+}
+
 func Test_checkSupportedVersion(t *testing.T) {
 	for _, tt := range []struct {
 		name    string
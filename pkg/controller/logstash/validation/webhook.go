@@ -17,9 +17,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/namespaces"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 // +kubebuilder:webhook:path=/validate-logstash-k8s-elastic-co-v1alpha1-logstash,mutating=false,failurePolicy=ignore,groups=logstash.k8s.elastic.co,resources=logstashes,verbs=create;update,versions=v1alpha1,name=elastic-logstash-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
@@ -31,12 +31,12 @@ const (
 var lslog = ulog.Log.WithName("ls-validation")
 
 // RegisterWebhook will register the Logstash validating webhook.
-func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, managedNamespaces []string) {
+func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, managedNamespaces namespaces.Managed) {
 	wh := &validatingWebhook{
 		client:               mgr.GetClient(),
 		decoder:              admission.NewDecoder(mgr.GetScheme()),
 		validateStorageClass: validateStorageClass,
-		managedNamespaces:    set.Make(managedNamespaces...),
+		managedNamespaces:    managedNamespaces,
 	}
 	lslog.Info("Registering Logstash validating webhook", "path", webhookPath)
 	mgr.GetWebhookServer().Register(webhookPath, &webhook.Admission{Handler: wh})
@@ -46,7 +46,7 @@ type validatingWebhook struct {
 	client               k8s.Client
 	decoder              admission.Decoder
 	validateStorageClass bool
-	managedNamespaces    set.StringSet
+	managedNamespaces    namespaces.Managed
 }
 
 func (wh *validatingWebhook) ValidateCreate(ls *lsv1alpha1.Logstash) error {
@@ -80,7 +80,7 @@ func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request)
 
 	// If this Logstash instance is not within the set of managed namespaces
 	// for this operator ignore this request.
-	if wh.managedNamespaces.Count() > 0 && !wh.managedNamespaces.Has(ls.Namespace) {
+	if !wh.managedNamespaces.IsManaged(ls.Namespace) {
 		lslog.V(1).Info("Skip Logstash resource validation", "name", ls.Name, "namespace", ls.Namespace)
 		return admission.Allowed("")
 	}
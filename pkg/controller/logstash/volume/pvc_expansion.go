@@ -19,7 +19,7 @@ func RecreateStatefulSets(ctx context.Context, k8sclient k8s.Client, ls logstash
 }
 
 func HandleVolumeExpansion(ctx context.Context, k8sClient k8s.Client, ls logstashv1alpha1.Logstash,
-	expectedSset appsv1.StatefulSet, actualSset appsv1.StatefulSet,
+	expectedSset *appsv1.StatefulSet, actualSset appsv1.StatefulSet,
 	validateStorageClass bool) (bool, error) {
 	return commonvolume.HandleVolumeExpansion(ctx, k8sClient, &ls, ls.Kind, expectedSset,
 		actualSset, validateStorageClass)
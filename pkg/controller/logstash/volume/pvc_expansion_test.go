@@ -175,7 +175,7 @@ func Test_handleVolumeExpansion(t *testing.T) {
 				TypeMeta:   metav1.TypeMeta{Kind: logstashv1alpha1.Kind}}
 			k8sClient := k8s.NewFakeClient(append(tt.runtimeObjs, &ls)...)
 			recreate, err := HandleVolumeExpansion(context.Background(), k8sClient, ls,
-				tt.args.expectedSset, tt.args.actualSset, tt.args.validateStorageClass)
+				&tt.args.expectedSset, tt.args.actualSset, tt.args.validateStorageClass)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("handleVolumeExpansion() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -15,6 +15,7 @@ import (
 	"go.elastic.co/apm/v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -33,8 +34,12 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/deployment"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/externaldns"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/ingress"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/queue"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
@@ -73,7 +78,7 @@ func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileMa
 
 func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileMapsServer) error {
 	// Watch for changes to MapsServer
-	if err := c.Watch(source.Kind(mgr.GetCache(), &emsv1alpha1.ElasticMapsServer{}, &handler.TypedEnqueueRequestForObject[*emsv1alpha1.ElasticMapsServer]{})); err != nil {
+	if err := c.Watch(source.Kind(mgr.GetCache(), &emsv1alpha1.ElasticMapsServer{}, queue.EnqueueRequestForObject[*emsv1alpha1.ElasticMapsServer]())); err != nil {
 		return err
 	}
 
@@ -99,8 +104,21 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileMapsSe
 		return err
 	}
 
+	// Watch ingresses
+	if err := c.Watch(source.Kind(mgr.GetCache(), &networkingv1.Ingress{}, handler.TypedEnqueueRequestForOwner[*networkingv1.Ingress](
+		mgr.GetScheme(), mgr.GetRESTMapper(),
+		&emsv1alpha1.ElasticMapsServer{}, handler.OnlyControllerOwner(),
+	))); err != nil {
+		return err
+	}
+
+	// Watch Gateway routes
+	if err := gateway.WatchRoutes(mgr, c, &emsv1alpha1.ElasticMapsServer{}); err != nil {
+		return err
+	}
+
 	// Watch owned and soft-owned secrets
-	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
+	if err := c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
 		mgr.GetScheme(), mgr.GetRESTMapper(),
 		&emsv1alpha1.ElasticMapsServer{}, handler.OnlyControllerOwner(),
 	))); err != nil {
@@ -111,7 +129,7 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileMapsSe
 	}
 
 	// Dynamically watch referenced secrets to connect to Elasticsearch
-	return c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets))
+	return c.Watch(watches.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets))
 }
 
 var _ reconcile.Reconciler = &ReconcileMapsServer{}
@@ -223,12 +241,13 @@ func (r *ReconcileMapsServer) doReconcile(ctx context.Context, ems emsv1alpha1.E
 		DynamicWatches:        r.DynamicWatches(),
 		Owner:                 &ems,
 		TLSOptions:            ems.Spec.HTTP.TLS,
+		ExtraHTTPSANs:         ems.Spec.HTTP.ExternalSANs(),
 		Namer:                 EMSNamer,
 		Labels:                ems.GetIdentityLabels(),
 		Services:              []corev1.Service{*svc},
 		GlobalCA:              r.GlobalCA,
-		CACertRotation:        r.CACertRotation,
-		CertRotation:          r.CertRotation,
+		CACertRotation:        r.CACertRotation.Get(),
+		CertRotation:          r.CertRotation.Get(),
 		GarbageCollectSecrets: true,
 	}.ReconcileCAAndHTTPCerts(ctx)
 	if results.HasError() {
@@ -237,6 +256,20 @@ func (r *ReconcileMapsServer) doReconcile(ctx context.Context, ems emsv1alpha1.E
 		return results, status
 	}
 
+	exposureParams := ingress.Params{
+		Owner:   &ems,
+		HTTP:    ems.Spec.HTTP,
+		Service: *svc,
+		Labels:  ems.GetIdentityLabels(),
+		Namer:   EMSNamer,
+	}
+	if err := ingress.Reconcile(ctx, r.Client, exposureParams); err != nil {
+		return results.WithError(err), status
+	}
+	if err := gateway.Reconcile(ctx, r.Client, exposureParams); err != nil {
+		return results.WithError(err), status
+	}
+
 	emsVersion, err := version.Parse(ems.Spec.Version)
 	if err != nil {
 		return results.WithError(err), status
@@ -302,6 +335,7 @@ func NewService(ems emsv1alpha1.ElasticMapsServer) *corev1.Service {
 
 	svc.ObjectMeta.Namespace = ems.Namespace
 	svc.ObjectMeta.Name = HTTPService(ems.Name)
+	svc.ObjectMeta.Annotations = externaldns.ServiceAnnotations(ems.Spec.HTTP)
 
 	labels := ems.GetIdentityLabels()
 	ports := []corev1.ServicePort{
@@ -355,7 +389,7 @@ func (r *ReconcileMapsServer) reconcileDeployment(
 		return appsv1.Deployment{}, err
 	}
 	deploy := deployment.New(deployParams)
-	return deployment.Reconcile(ctx, r.K8sClient(), deploy, &ems)
+	return deployment.Reconcile(ctx, r.K8sClient(), deploy, &ems, true)
 }
 
 func (r *ReconcileMapsServer) deploymentParams(ems emsv1alpha1.ElasticMapsServer, configHash string) (deployment.Params, error) {
@@ -13,6 +13,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemesh"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 )
@@ -72,11 +73,16 @@ func newPodSpec(ems emsv1alpha1.ElasticMapsServer, configHash string) (corev1.Po
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	image, err := container.ResolveImage(ems.Spec.Image, container.MapsImage, v)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	builder := defaults.NewPodTemplateBuilder(ems.Spec.PodTemplate, emsv1alpha1.MapsContainerName).
 		WithAnnotations(annotations).
+		WithAnnotations(servicemesh.PodAnnotations(ems.Spec.HTTP.TLS)).
 		WithResources(DefaultResources).
-		WithDockerImage(ems.Spec.Image, container.ImageRepository(container.MapsImage, v)).
+		WithDockerImage(ems.Spec.Image, image).
 		WithReadinessProbe(readinessProbe(ems.Spec.HTTP.TLS.Enabled())).
 		WithPorts(defaultContainerPorts).
 		WithVolumes(cfgVolume.Volume(), logsVolume.Volume()).
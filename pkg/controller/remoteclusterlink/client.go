@@ -0,0 +1,174 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package remoteclusterlink
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	remoteclusterv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/remotecluster/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/remoteca"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// remoteCASecretSuffix is the suffix of the Secret holding the transport CA of the server cluster on the client side.
+const remoteCASecretSuffix = "remote-link-ca"
+
+// reconcileClient trusts the transport CA of the server cluster published in the exchange Secret, and declares the
+// server cluster in the remote cluster settings of the local cluster. The cross-cluster API key of the exchange Secret
+// is loaded in the keystore of the local cluster by the Elasticsearch controller.
+func (r *linkKind) reconcileClient(ctx context.Context, link remoteclusterv1alpha1.RemoteClusterLink, es esv1.Elasticsearch, status *remoteclusterv1alpha1.RemoteClusterLinkStatus) {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx).WithValues("es_name", es.Name)
+
+	alias := link.AliasOrDefault()
+	for _, remoteCluster := range es.Spec.RemoteClusters {
+		if remoteCluster.Name == alias {
+			setError(status, fmt.Sprintf("Remote cluster %s is already declared in the specification of Elasticsearch %s", alias, es.Name))
+			return
+		}
+	}
+
+	var exchangeSecret corev1.Secret
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: link.Namespace, Name: link.Spec.ExchangeSecretName}, &exchangeSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			setApplyingChanges(status, fmt.Sprintf("Waiting for the exchange Secret %s to be copied from the server side", link.Spec.ExchangeSecretName))
+			return
+		}
+		setApplyingChanges(status, err.Error())
+		return
+	}
+	for _, key := range []string{
+		remoteclusterv1alpha1.ExchangeSecretCAKey,
+		remoteclusterv1alpha1.ExchangeSecretAPIKeyKey,
+		remoteclusterv1alpha1.ExchangeSecretAddressKey,
+	} {
+		if len(exchangeSecret.Data[key]) == 0 {
+			setError(status, fmt.Sprintf("Key %s is missing in the exchange Secret %s", key, link.Spec.ExchangeSecretName))
+			return
+		}
+	}
+
+	// trust the transport CA of the server cluster
+	expectedCA := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: link.Namespace,
+			Name:      remoteCASecretName(es.Name, link.Name),
+			Labels:    remoteca.Labels(es.Name),
+		},
+		Data: map[string][]byte{
+			certificates.CAFileName: exchangeSecret.Data[remoteclusterv1alpha1.ExchangeSecretCAKey],
+		},
+	}
+	if _, err := reconciler.ReconcileSecret(ctx, r.Client, expectedCA, &link); err != nil {
+		setApplyingChanges(status, err.Error())
+		return
+	}
+
+	if es.Status.Phase != esv1.ElasticsearchReadyPhase {
+		setApplyingChanges(status, "Waiting for Elasticsearch to be ready")
+		return
+	}
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		setApplyingChanges(status, err.Error())
+		return
+	}
+	defer esClient.Close()
+
+	settings, err := esClient.GetRemoteClusterSettings(ctx)
+	if err != nil {
+		setApplyingChanges(status, err.Error())
+		return
+	}
+	expected := expectedRemoteCluster(link, exchangeSecret)
+	if current, exists := settings.PersistentSettings.Cluster.RemoteClusters[alias]; !exists || !reflect.DeepEqual(current, expected) {
+		log.Info("Updating remote cluster settings", "alias", alias)
+		if err := updateRemoteCluster(ctx, esClient, alias, expected); err != nil {
+			msg := fmt.Sprintf("Failed to declare remote cluster %s on Elasticsearch %s: %s", alias, es.Name, esclient.ErrorReason(err))
+			r.recorder.Event(&link, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+			setError(status, msg)
+			return
+		}
+	}
+
+	info, err := esClient.GetRemoteClusterInfo(ctx)
+	if err != nil {
+		setApplyingChanges(status, err.Error())
+		return
+	}
+	status.Connected = info[alias].Connected
+	if !status.Connected {
+		setApplyingChanges(status, fmt.Sprintf("Waiting for Elasticsearch %s to connect to remote cluster %s", es.Name, alias))
+	}
+}
+
+// deleteClient removes the remote cluster from the settings of the local cluster, the Secret holding the CA of the
+// server cluster being garbage collected with the RemoteClusterLink.
+func (r *linkKind) deleteClient(ctx context.Context, link remoteclusterv1alpha1.RemoteClusterLink, es esv1.Elasticsearch) error {
+	defer tracing.Span(&ctx)()
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+
+	alias := link.AliasOrDefault()
+	settings, err := esClient.GetRemoteClusterSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if _, exists := settings.PersistentSettings.Cluster.RemoteClusters[alias]; !exists {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Removing remote cluster settings", "es_name", es.Name, "alias", alias)
+	// reset all the settings of the remote cluster to remove it
+	return updateRemoteCluster(ctx, esClient, alias, esclient.RemoteCluster{RemoteClusterOptions: &esclient.RemoteClusterOptions{}})
+}
+
+// expectedRemoteCluster returns the settings of the remote cluster, connected to in proxy mode through the address
+// published by the server side.
+func expectedRemoteCluster(link remoteclusterv1alpha1.RemoteClusterLink, exchangeSecret corev1.Secret) esclient.RemoteCluster {
+	options := esclient.RemoteClusterOptions{
+		Mode:         ptr.To(string(esv1.RemoteClusterProxyMode)),
+		ProxyAddress: ptr.To(string(exchangeSecret.Data[remoteclusterv1alpha1.ExchangeSecretAddressKey])),
+	}
+	if serverName := exchangeSecret.Data[remoteclusterv1alpha1.ExchangeSecretServerNameKey]; len(serverName) > 0 {
+		options.ServerName = ptr.To(string(serverName))
+	}
+	if link.Spec.Client != nil {
+		options.SkipUnavailable = link.Spec.Client.SkipUnavailable
+	}
+	return esclient.RemoteCluster{RemoteClusterOptions: &options}
+}
+
+func updateRemoteCluster(ctx context.Context, esClient esclient.Client, alias string, remoteCluster esclient.RemoteCluster) error {
+	return esClient.UpdateRemoteClusterSettings(ctx, esclient.RemoteClustersSettings{
+		PersistentSettings: &esclient.SettingsGroup{
+			Cluster: esclient.RemoteClusters{
+				RemoteClusters: map[string]esclient.RemoteCluster{alias: remoteCluster},
+			},
+		},
+	})
+}
+
+// remoteCASecretName returns the name of the Secret holding the transport CA of the server cluster of the given link.
+func remoteCASecretName(esName, linkName string) string {
+	return esv1.ESNamer.Suffix(esName+"-"+linkName, remoteCASecretSuffix)
+}
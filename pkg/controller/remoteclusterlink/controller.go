@@ -0,0 +1,245 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package remoteclusterlink
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	remoteclusterv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/remotecluster/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	controllerName = "remoteclusterlink-controller"
+
+	// LinkFinalizer lets the operator invalidate the cross-cluster API key, or remove the remote cluster from the
+	// Elasticsearch settings, before the RemoteClusterLink resource is deleted.
+	LinkFinalizer = "remotecluster.k8s.elastic.co/unlink"
+
+	enterpriseFeaturesDisabledMsg = "Remote cluster links are an enterprise feature. Enterprise features are disabled"
+)
+
+var (
+	// connectionCheckRequeue is used to periodically refresh the connection state of the client side.
+	connectionCheckRequeue = reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Minute}
+	// crossClusterAPIKeyMinVersion is the first version of Elasticsearch supporting cross-cluster API keys.
+	crossClusterAPIKeyMinVersion = version.MustParse("8.10.0")
+)
+
+// config identifies the RemoteClusterLink controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "RemoteClusterLink",
+	NameField:      "link_name",
+	Finalizer:      LinkFinalizer,
+}
+
+// Add creates a new RemoteClusterLink Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Elasticsearch and reconcile the RemoteClusterLink resources referencing them
+		source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestForLinks(r.Client, referencesElasticsearch)),
+		// watch for changes to the exchange Secrets, and to the transport CA published on the server side
+		source.Kind[client.Object](mgr.GetCache(), &corev1.Secret{}, reconcileRequestForLinks(r.Client, usesSecret)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of RemoteClusterLink.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*remoteclusterv1alpha1.RemoteClusterLink, remoteclusterv1alpha1.RemoteClusterLinkStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &linkKind{
+		Client:           c,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         recorder,
+		licenseChecker:   license.NewLicenseChecker(c, params.OperatorNamespace),
+		params:           params,
+	})
+}
+
+func referencesElasticsearch(link remoteclusterv1alpha1.RemoteClusterLink, obj client.Object) bool {
+	return link.References(k8s.ExtractNamespacedName(obj))
+}
+
+func usesSecret(link remoteclusterv1alpha1.RemoteClusterLink, obj client.Object) bool {
+	if link.Spec.ExchangeSecretName == obj.GetName() {
+		return true
+	}
+	esRef := link.Spec.ElasticsearchRef.WithDefaultNamespace(link.Namespace).NamespacedName()
+	return link.Spec.Server != nil && certificates.PublicTransportCertsSecretName(esv1.ESNamer, esRef.Name) == obj.GetName()
+}
+
+// reconcileRequestForLinks returns the requests to reconcile the RemoteClusterLink resources, in the namespace of the
+// watched object, matching it.
+func reconcileRequestForLinks(clnt k8s.Client, matches func(remoteclusterv1alpha1.RemoteClusterLink, client.Object) bool) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return handler.TypedEnqueueRequestsFromMapFunc[client.Object](func(ctx context.Context, obj client.Object) []reconcile.Request {
+		var links remoteclusterv1alpha1.RemoteClusterLinkList
+		if err := clnt.List(ctx, &links, client.InNamespace(obj.GetNamespace())); err != nil {
+			ulog.Log.Error(err, "Fail to list RemoteClusterLinkList while watching", "kind", obj.GetObjectKind().GroupVersionKind().Kind)
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, link := range links.Items {
+			link := link
+			if matches(link, obj) {
+				requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&link)})
+			}
+		}
+		return requests
+	})
+}
+
+// linkKind links the Elasticsearch clusters of RemoteClusterLink resources.
+type linkKind struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	licenseChecker   license.Checker
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*remoteclusterv1alpha1.RemoteClusterLink, remoteclusterv1alpha1.RemoteClusterLinkStatus] = &linkKind{}
+	_ apiresource.Remover[*remoteclusterv1alpha1.RemoteClusterLink]                                             = &linkKind{}
+)
+
+func (r *linkKind) NewObject() *remoteclusterv1alpha1.RemoteClusterLink {
+	return &remoteclusterv1alpha1.RemoteClusterLink{}
+}
+
+func (r *linkKind) GetStatus(link *remoteclusterv1alpha1.RemoteClusterLink) remoteclusterv1alpha1.RemoteClusterLinkStatus {
+	return link.Status
+}
+
+func (r *linkKind) SetStatus(link *remoteclusterv1alpha1.RemoteClusterLink, status remoteclusterv1alpha1.RemoteClusterLinkStatus) {
+	link.Status = status
+}
+
+func (r *linkKind) InvalidStatus(link *remoteclusterv1alpha1.RemoteClusterLink, err error) remoteclusterv1alpha1.RemoteClusterLinkStatus {
+	status := newStatus(*link)
+	status.Phase = remoteclusterv1alpha1.InvalidPhase
+	status.Message = err.Error()
+	return status
+}
+
+// Configure creates the cross-cluster API key on the server side, or configures the remote cluster on the client side.
+func (r *linkKind) Configure(ctx context.Context, obj *remoteclusterv1alpha1.RemoteClusterLink) (*reconciler.Results, remoteclusterv1alpha1.RemoteClusterLinkStatus) {
+	link := *obj
+	log := ulog.FromContext(ctx)
+	results := reconciler.NewResult(ctx)
+	status := newStatus(link)
+
+	enabled, err := r.licenseChecker.EnterpriseFeaturesEnabled(ctx)
+	if err != nil {
+		return results.WithError(err), link.Status
+	}
+	if !enabled {
+		log.Info(enterpriseFeaturesDisabledMsg)
+		r.recorder.Event(&link, corev1.EventTypeWarning, events.EventReconciliationError, enterpriseFeaturesDisabledMsg)
+		status.Phase = remoteclusterv1alpha1.ErrorPhase
+		status.Message = enterpriseFeaturesDisabledMsg
+		return results, status
+	}
+
+	es, err := r.getElasticsearch(ctx, link)
+	switch {
+	case apierrors.IsNotFound(err):
+		status.Phase = remoteclusterv1alpha1.ApplyingChangesPhase
+		status.Message = fmt.Sprintf("Waiting for Elasticsearch %s to be created", link.Spec.ElasticsearchRef.Name)
+		return results.WithResult(apiresource.DefaultRequeue), status
+	case err != nil:
+		return results.WithError(err), link.Status
+	}
+	ver, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return results.WithError(err), link.Status
+	}
+	if !ver.GTE(crossClusterAPIKeyMinVersion) {
+		status.Phase = remoteclusterv1alpha1.InvalidPhase
+		status.Message = fmt.Sprintf("Remote cluster links require Elasticsearch %s or later", crossClusterAPIKeyMinVersion)
+		r.recorder.Event(&link, corev1.EventTypeWarning, events.EventReasonValidation, status.Message)
+		return results, status
+	}
+
+	if link.Spec.Server != nil {
+		r.reconcileServer(ctx, link, es, &status)
+	} else {
+		r.reconcileClient(ctx, link, es, &status)
+	}
+
+	switch {
+	case status.Phase != remoteclusterv1alpha1.ReadyPhase:
+		// requeue if not ready
+		results.WithResult(apiresource.DefaultRequeue)
+	case link.Spec.Client != nil:
+		// refresh the connection state
+		results.WithResult(connectionCheckRequeue)
+	}
+
+	return results, status
+}
+
+// newStatus returns the initial status of a reconciliation of the given link. It keeps track of the API key created on
+// the server side across reconciliations.
+func newStatus(link remoteclusterv1alpha1.RemoteClusterLink) remoteclusterv1alpha1.RemoteClusterLinkStatus {
+	return remoteclusterv1alpha1.RemoteClusterLinkStatus{
+		Role:               link.Role(),
+		Phase:              remoteclusterv1alpha1.ReadyPhase,
+		APIKeyID:           link.Status.APIKeyID,
+		AccessHash:         link.Status.AccessHash,
+		ObservedGeneration: link.Generation,
+	}
+}
+
+// Remove invalidates the cross-cluster API key on the server side, or removes the remote cluster from the settings
+// on the client side.
+func (r *linkKind) Remove(ctx context.Context, obj *remoteclusterv1alpha1.RemoteClusterLink) (reconcile.Result, error) {
+	link := *obj
+	es, err := r.getElasticsearch(ctx, link)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	// there is nothing to unlink once the Elasticsearch cluster is deleted
+	if err == nil && es.DeletionTimestamp.IsZero() {
+		if link.Spec.Server != nil {
+			err = r.deleteServer(ctx, link, es)
+		} else {
+			err = r.deleteClient(ctx, link, es)
+		}
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+func (r *linkKind) getElasticsearch(ctx context.Context, link remoteclusterv1alpha1.RemoteClusterLink) (esv1.Elasticsearch, error) {
+	var es esv1.Elasticsearch
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: link.Namespace, Name: link.Spec.ElasticsearchRef.Name}, &es)
+	return es, err
+}
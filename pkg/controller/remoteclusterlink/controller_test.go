@@ -0,0 +1,377 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package remoteclusterlink
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	remoteclusterv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/remotecluster/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/remoteca"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeElasticsearch stores the cross-cluster API keys and the remote clusters of a cluster in memory.
+type fakeElasticsearch struct {
+	apiKeys        map[string]esclient.CrossClusterAPIKeyAccess
+	invalidated    []string
+	remoteClusters map[string]esclient.RemoteCluster
+	connected      map[string]bool
+	settingUpdates int
+}
+
+type fakeEsClient struct {
+	esclient.Client
+	es *fakeElasticsearch
+}
+
+func (c fakeEsClient) CreateCrossClusterAPIKey(_ context.Context, request esclient.CrossClusterAPIKeyRequest) (esclient.CrossClusterAPIKey, error) {
+	id := "key-" + string(rune('a'+len(c.es.apiKeys)+len(c.es.invalidated)))
+	c.es.apiKeys[id] = request.Access
+	return esclient.CrossClusterAPIKey{
+		ID:      id,
+		Name:    request.Name,
+		APIKey:  "secret",
+		Encoded: base64.StdEncoding.EncodeToString([]byte(id + ":secret")),
+	}, nil
+}
+
+func (c fakeEsClient) UpdateCrossClusterAPIKey(_ context.Context, id string, access esclient.CrossClusterAPIKeyAccess) error {
+	c.es.apiKeys[id] = access
+	return nil
+}
+
+func (c fakeEsClient) InvalidateAPIKeys(_ context.Context, ids ...string) error {
+	for _, id := range ids {
+		delete(c.es.apiKeys, id)
+		c.es.invalidated = append(c.es.invalidated, id)
+	}
+	return nil
+}
+
+func (c fakeEsClient) GetRemoteClusterSettings(_ context.Context) (esclient.RemoteClustersSettings, error) {
+	return esclient.RemoteClustersSettings{
+		PersistentSettings: &esclient.SettingsGroup{Cluster: esclient.RemoteClusters{RemoteClusters: c.es.remoteClusters}},
+	}, nil
+}
+
+func (c fakeEsClient) UpdateRemoteClusterSettings(_ context.Context, settings esclient.RemoteClustersSettings) error {
+	c.es.settingUpdates++
+	for alias, remoteCluster := range settings.PersistentSettings.Cluster.RemoteClusters {
+		if remoteCluster.RemoteClusterOptions.Mode == nil {
+			delete(c.es.remoteClusters, alias)
+			continue
+		}
+		c.es.remoteClusters[alias] = remoteCluster
+	}
+	return nil
+}
+
+func (c fakeEsClient) GetRemoteClusterInfo(_ context.Context) (esclient.RemoteClustersInfo, error) {
+	info := esclient.RemoteClustersInfo{}
+	for alias := range c.es.remoteClusters {
+		info[alias] = esclient.RemoteClusterInfo{Connected: c.es.connected[alias], Mode: "proxy"}
+	}
+	return info, nil
+}
+
+func (c fakeEsClient) Close() {}
+
+func fakeClientProvider(es *fakeElasticsearch) commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return fakeEsClient{es: es}, nil
+	}
+}
+
+func newFakeElasticsearch() *fakeElasticsearch {
+	return &fakeElasticsearch{
+		apiKeys:        map[string]esclient.CrossClusterAPIKeyAccess{},
+		remoteClusters: map[string]esclient.RemoteCluster{},
+		connected:      map[string]bool{},
+	}
+}
+
+func elasticsearch(ver string, phase esv1.ElasticsearchOrchestrationPhase) *esv1.Elasticsearch {
+	return &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{Version: ver},
+		Status:     esv1.ElasticsearchStatus{Phase: phase},
+	}
+}
+
+func newReconcilerForTest(
+	es *fakeElasticsearch,
+	enterprise bool,
+	objs ...client.Object,
+) (*apiresource.Reconciler[*remoteclusterv1alpha1.RemoteClusterLink, remoteclusterv1alpha1.RemoteClusterLinkStatus], k8s.Client) {
+	k8sClient := k8s.NewFakeClient(objs...)
+	recorder := record.NewFakeRecorder(10)
+	return apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &linkKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(es),
+		recorder:         recorder,
+		licenseChecker:   license.MockLicenseChecker{EnterpriseEnabled: enterprise},
+	}), k8sClient
+}
+
+func TestReconcileRemoteClusterLink_Server(t *testing.T) {
+	ctx := context.Background()
+	link := &remoteclusterv1alpha1.RemoteClusterLink{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "to-es2"},
+		Spec: remoteclusterv1alpha1.RemoteClusterLinkSpec{
+			ElasticsearchRef:   commonv1.LocalObjectSelector{Name: "es"},
+			ExchangeSecretName: "es-link",
+			Server: &remoteclusterv1alpha1.RemoteClusterLinkServer{
+				Address:    "es.example.com:9443",
+				ServerName: "es.example.com",
+				Access: remoteclusterv1alpha1.CrossClusterAccess{
+					Search: []remoteclusterv1alpha1.CrossClusterIndices{{Names: []string{"logs-*"}}},
+				},
+			},
+		},
+	}
+	transportCA := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: certificates.PublicTransportCertsSecretName(esv1.ESNamer, "es")},
+		Data:       map[string][]byte{certificates.CAFileName: []byte("ca")},
+	}
+	fakeES := newFakeElasticsearch()
+	r, k8sClient := newReconcilerForTest(fakeES, true, link, transportCA, elasticsearch("8.15.0", esv1.ElasticsearchReadyPhase))
+	request := reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(link)}
+	getLink := func() remoteclusterv1alpha1.RemoteClusterLink {
+		var actual remoteclusterv1alpha1.RemoteClusterLink
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+	getExchangeSecret := func() corev1.Secret {
+		var secret corev1.Secret
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "es-link"}, &secret))
+		return secret
+	}
+
+	// the API key is created and published in the exchange Secret
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{}, res)
+	actual := getLink()
+	require.Contains(t, actual.Finalizers, LinkFinalizer)
+	require.Equal(t, remoteclusterv1alpha1.ServerRole, actual.Status.Role)
+	require.Equal(t, remoteclusterv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, "key-a", actual.Status.APIKeyID)
+	require.Equal(t, []esclient.CrossClusterIndices{{Names: []string{"logs-*"}}}, fakeES.apiKeys["key-a"].Search)
+	secret := getExchangeSecret()
+	require.Equal(t, map[string][]byte{
+		remoteclusterv1alpha1.ExchangeSecretCAKey:         []byte("ca"),
+		remoteclusterv1alpha1.ExchangeSecretAPIKeyKey:     []byte(base64.StdEncoding.EncodeToString([]byte("key-a:secret"))),
+		remoteclusterv1alpha1.ExchangeSecretAddressKey:    []byte("es.example.com:9443"),
+		remoteclusterv1alpha1.ExchangeSecretServerNameKey: []byte("es.example.com"),
+	}, secret.Data)
+
+	// the API key is not recreated on the next reconciliation
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Len(t, fakeES.apiKeys, 1)
+
+	// the access of the API key is updated when it changes
+	actual = getLink()
+	actual.Spec.Server.Access.Replication = []remoteclusterv1alpha1.CrossClusterIndices{{Names: []string{"metrics-*"}}}
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Len(t, fakeES.apiKeys, 1)
+	require.Equal(t, []esclient.CrossClusterIndices{{Names: []string{"metrics-*"}}}, fakeES.apiKeys["key-a"].Replication)
+
+	// a new API key is created, and the previous one invalidated, if the exchange Secret is deleted
+	secret = getExchangeSecret()
+	require.NoError(t, k8sClient.Delete(ctx, &secret))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, []string{"key-a"}, fakeES.invalidated)
+	require.Equal(t, "key-b", getLink().Status.APIKeyID)
+	require.Contains(t, fakeES.apiKeys, "key-b")
+
+	// the API key is invalidated when the link is deleted
+	actual = getLink()
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, fakeES.apiKeys)
+	require.Equal(t, []string{"key-a", "key-b"}, fakeES.invalidated)
+}
+
+func TestReconcileRemoteClusterLink_Client(t *testing.T) {
+	ctx := context.Background()
+	link := &remoteclusterv1alpha1.RemoteClusterLink{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "to-es1"},
+		Spec: remoteclusterv1alpha1.RemoteClusterLinkSpec{
+			ElasticsearchRef:   commonv1.LocalObjectSelector{Name: "es"},
+			ExchangeSecretName: "es1-link",
+			Client: &remoteclusterv1alpha1.RemoteClusterLinkClient{
+				Alias:           "es1",
+				SkipUnavailable: ptr.To(true),
+			},
+		},
+	}
+	fakeES := newFakeElasticsearch()
+	r, k8sClient := newReconcilerForTest(fakeES, true, link, elasticsearch("8.15.0", esv1.ElasticsearchReadyPhase))
+	request := reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(link)}
+	getStatus := func() remoteclusterv1alpha1.RemoteClusterLinkStatus {
+		var actual remoteclusterv1alpha1.RemoteClusterLink
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual.Status
+	}
+
+	// wait for the exchange Secret to be copied from the server side
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	status := getStatus()
+	require.Equal(t, remoteclusterv1alpha1.ClientRole, status.Role)
+	require.Equal(t, remoteclusterv1alpha1.ApplyingChangesPhase, status.Phase)
+	require.Equal(t, "Waiting for the exchange Secret es1-link to be copied from the server side", status.Message)
+
+	// the CA is trusted and the remote cluster declared once the exchange Secret is copied
+	require.NoError(t, k8sClient.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es1-link"},
+		Data: map[string][]byte{
+			remoteclusterv1alpha1.ExchangeSecretCAKey:      []byte("ca"),
+			remoteclusterv1alpha1.ExchangeSecretAPIKeyKey:  []byte("encoded"),
+			remoteclusterv1alpha1.ExchangeSecretAddressKey: []byte("es1.example.com:9443"),
+		},
+	}))
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	require.Equal(t, "Waiting for Elasticsearch es to connect to remote cluster es1", getStatus().Message)
+	var remoteCA corev1.Secret
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: remoteCASecretName("es", "to-es1")}, &remoteCA))
+	require.Equal(t, map[string]string(remoteca.Labels("es")), remoteCA.Labels)
+	require.Equal(t, []byte("ca"), remoteCA.Data[certificates.CAFileName])
+	require.Equal(t, esclient.RemoteCluster{RemoteClusterOptions: &esclient.RemoteClusterOptions{
+		Mode:            ptr.To("proxy"),
+		ProxyAddress:    ptr.To("es1.example.com:9443"),
+		SkipUnavailable: ptr.To(true),
+	}}, fakeES.remoteClusters["es1"])
+
+	// the link is ready once the cluster is connected, and the settings are not updated again
+	fakeES.connected["es1"] = true
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, connectionCheckRequeue, res)
+	status = getStatus()
+	require.Equal(t, remoteclusterv1alpha1.ReadyPhase, status.Phase)
+	require.True(t, status.Connected)
+	require.Equal(t, 1, fakeES.settingUpdates)
+
+	// the remote cluster is removed from the settings when the link is deleted
+	var actual remoteclusterv1alpha1.RemoteClusterLink
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, fakeES.remoteClusters)
+}
+
+func TestReconcileRemoteClusterLink_Client_AliasDeclaredInSpec(t *testing.T) {
+	link := &remoteclusterv1alpha1.RemoteClusterLink{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es1"},
+		Spec: remoteclusterv1alpha1.RemoteClusterLinkSpec{
+			ElasticsearchRef:   commonv1.LocalObjectSelector{Name: "es"},
+			ExchangeSecretName: "es1-link",
+			Client:             &remoteclusterv1alpha1.RemoteClusterLinkClient{},
+		},
+	}
+	es := elasticsearch("8.15.0", esv1.ElasticsearchReadyPhase)
+	es.Spec.RemoteClusters = []esv1.RemoteCluster{{Name: "es1"}}
+	r, k8sClient := newReconcilerForTest(newFakeElasticsearch(), true, link, es)
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(link)})
+	require.NoError(t, err)
+	var actual remoteclusterv1alpha1.RemoteClusterLink
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(link), &actual))
+	require.Equal(t, remoteclusterv1alpha1.ErrorPhase, actual.Status.Phase)
+	require.Equal(t, "Remote cluster es1 is already declared in the specification of Elasticsearch es", actual.Status.Message)
+}
+
+func TestReconcileRemoteClusterLink_Preconditions(t *testing.T) {
+	link := func() *remoteclusterv1alpha1.RemoteClusterLink {
+		return &remoteclusterv1alpha1.RemoteClusterLink{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "link"},
+			Spec: remoteclusterv1alpha1.RemoteClusterLinkSpec{
+				ElasticsearchRef:   commonv1.LocalObjectSelector{Name: "es"},
+				ExchangeSecretName: "link",
+				Client:             &remoteclusterv1alpha1.RemoteClusterLinkClient{},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		enterprise  bool
+		objs        []client.Object
+		wantPhase   remoteclusterv1alpha1.Phase
+		wantMessage string
+	}{
+		{
+			name:        "enterprise features disabled",
+			enterprise:  false,
+			objs:        []client.Object{link(), elasticsearch("8.15.0", esv1.ElasticsearchReadyPhase)},
+			wantPhase:   remoteclusterv1alpha1.ErrorPhase,
+			wantMessage: enterpriseFeaturesDisabledMsg,
+		},
+		{
+			name:        "Elasticsearch not found",
+			enterprise:  true,
+			objs:        []client.Object{link()},
+			wantPhase:   remoteclusterv1alpha1.ApplyingChangesPhase,
+			wantMessage: "Waiting for Elasticsearch es to be created",
+		},
+		{
+			name:        "Elasticsearch version not supported",
+			enterprise:  true,
+			objs:        []client.Object{link(), elasticsearch("8.9.2", esv1.ElasticsearchReadyPhase)},
+			wantPhase:   remoteclusterv1alpha1.InvalidPhase,
+			wantMessage: "Remote cluster links require Elasticsearch 8.10.0 or later",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, k8sClient := newReconcilerForTest(newFakeElasticsearch(), tt.enterprise, tt.objs...)
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "link"}}
+			_, err := r.Reconcile(context.Background(), request)
+			require.NoError(t, err)
+			var actual remoteclusterv1alpha1.RemoteClusterLink
+			require.NoError(t, k8sClient.Get(context.Background(), request.NamespacedName, &actual))
+			require.Equal(t, tt.wantPhase, actual.Status.Phase)
+			require.Equal(t, tt.wantMessage, actual.Status.Message)
+		})
+	}
+}
+
+func Test_apiKeyID(t *testing.T) {
+	id, ok := apiKeyID([]byte(base64.StdEncoding.EncodeToString([]byte("id:key"))))
+	require.True(t, ok)
+	require.Equal(t, "id", id)
+	for _, encoded := range []string{"", "not base64", base64.StdEncoding.EncodeToString([]byte("no-separator"))} {
+		_, ok = apiKeyID([]byte(encoded))
+		require.False(t, ok, encoded)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package remoteclusterlink
+
+import (
+	"context"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	remoteclusterv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/remotecluster/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// GetSecureSettingsSecretSourcesForResources returns the exchange Secrets holding the cross-cluster API keys of the
+// RemoteClusterLink resources connecting the given resource to a remote cluster. Only Elasticsearch resources are supported.
+func GetSecureSettingsSecretSourcesForResources(ctx context.Context, c k8s.Client, resource metav1.Object, resourceKind string) ([]commonv1.NamespacedSecretSource, error) {
+	if resourceKind != esv1.Kind {
+		return nil, nil
+	}
+
+	var links remoteclusterv1alpha1.RemoteClusterLinkList
+	if err := c.List(ctx, &links, client.InNamespace(resource.GetNamespace())); err != nil {
+		return nil, err
+	}
+	// sort the links for the keystore to be built in a stable order
+	sort.Slice(links.Items, func(i, j int) bool {
+		return links.Items[i].Name < links.Items[j].Name
+	})
+
+	var sources []commonv1.NamespacedSecretSource
+	for _, link := range links.Items {
+		// links being deleted no longer provide credentials to the remote cluster
+		if link.Spec.Client == nil || link.IsMarkedForDeletion() || !link.References(k8s.ExtractNamespacedName(resource)) {
+			continue
+		}
+		sources = append(sources, commonv1.NamespacedSecretSource{
+			Namespace:  link.Namespace,
+			SecretName: link.Spec.ExchangeSecretName,
+			Entries: []commonv1.KeyToPath{{
+				Key:  remoteclusterv1alpha1.ExchangeSecretAPIKeyKey,
+				Path: esv1.RemoteClusterCredentialsSetting(link.AliasOrDefault()),
+			}},
+		})
+	}
+	return sources, nil
+}
@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package remoteclusterlink
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	remoteclusterv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/remotecluster/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestGetSecureSettingsSecretSourcesForResources(t *testing.T) {
+	clientLink := func(namespace, name, esName, alias string) *remoteclusterv1alpha1.RemoteClusterLink {
+		return &remoteclusterv1alpha1.RemoteClusterLink{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: remoteclusterv1alpha1.RemoteClusterLinkSpec{
+				ElasticsearchRef:   commonv1.LocalObjectSelector{Name: esName},
+				ExchangeSecretName: name + "-exchange",
+				Client:             &remoteclusterv1alpha1.RemoteClusterLinkClient{Alias: alias},
+			},
+		}
+	}
+	serverLink := clientLink("ns", "server", "es", "")
+	serverLink.Spec.Client = nil
+	serverLink.Spec.Server = &remoteclusterv1alpha1.RemoteClusterLinkServer{Address: "es.example.com:9443"}
+	k8sClient := k8s.NewFakeClient(
+		clientLink("ns", "z-link", "es", ""),
+		clientLink("ns", "a-link", "es", "remote-a"),
+		clientLink("ns", "other-es-link", "other-es", ""),
+		clientLink("other-ns", "other-ns-link", "es", ""),
+		serverLink,
+	)
+	es := metav1.ObjectMeta{Namespace: "ns", Name: "es"}
+
+	sources, err := GetSecureSettingsSecretSourcesForResources(context.Background(), k8sClient, &esv1.Elasticsearch{ObjectMeta: es}, esv1.Kind)
+	require.NoError(t, err)
+	require.Equal(t, []commonv1.NamespacedSecretSource{
+		{
+			Namespace:  "ns",
+			SecretName: "a-link-exchange",
+			Entries:    []commonv1.KeyToPath{{Key: "api-key", Path: "cluster.remote.remote-a.credentials"}},
+		},
+		{
+			Namespace:  "ns",
+			SecretName: "z-link-exchange",
+			Entries:    []commonv1.KeyToPath{{Key: "api-key", Path: "cluster.remote.z-link.credentials"}},
+		},
+	}, sources)
+
+	sources, err = GetSecureSettingsSecretSourcesForResources(context.Background(), k8sClient, &kbv1.Kibana{ObjectMeta: es}, kbv1.Kind)
+	require.NoError(t, err)
+	require.Empty(t, sources)
+}
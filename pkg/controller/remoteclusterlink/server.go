@@ -0,0 +1,200 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package remoteclusterlink
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	remoteclusterv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/remotecluster/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// linkMetadataKey is the key of the metadata of the cross-cluster API keys referencing the RemoteClusterLink they were
+// created for.
+const linkMetadataKey = "remotecluster.k8s.elastic.co/link"
+
+// reconcileServer creates the cross-cluster API key granting the client cluster access to the local cluster, and
+// publishes it in the exchange Secret with the transport CA and the address of the local cluster.
+func (r *linkKind) reconcileServer(ctx context.Context, link remoteclusterv1alpha1.RemoteClusterLink, es esv1.Elasticsearch, status *remoteclusterv1alpha1.RemoteClusterLinkStatus) {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx).WithValues("es_name", es.Name)
+
+	var caSecret corev1.Secret
+	caSecretName := certificates.PublicTransportCertsSecretName(esv1.ESNamer, es.Name)
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: caSecretName}, &caSecret); err != nil && !apierrors.IsNotFound(err) {
+		setApplyingChanges(status, err.Error())
+		return
+	}
+	ca := caSecret.Data[certificates.CAFileName]
+	if len(ca) == 0 {
+		setApplyingChanges(status, fmt.Sprintf("Waiting for the transport CA of Elasticsearch %s", es.Name))
+		return
+	}
+	if es.Status.Phase != esv1.ElasticsearchReadyPhase {
+		setApplyingChanges(status, "Waiting for Elasticsearch to be ready")
+		return
+	}
+
+	var exchangeSecret corev1.Secret
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: link.Namespace, Name: link.Spec.ExchangeSecretName}, &exchangeSecret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		setApplyingChanges(status, err.Error())
+		return
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		setApplyingChanges(status, err.Error())
+		return
+	}
+	defer esClient.Close()
+
+	access := crossClusterAPIKeyAccess(link.Spec.Server.Access)
+	accessHash := hash.HashObject(link.Spec.Server.Access)
+	encoded := exchangeSecret.Data[remoteclusterv1alpha1.ExchangeSecretAPIKeyKey]
+	id, published := apiKeyID(encoded)
+	switch {
+	case !published:
+		log.Info("Creating cross-cluster API key", "name", apiKeyName(link))
+		apiKey, err := esClient.CreateCrossClusterAPIKey(ctx, esclient.CrossClusterAPIKeyRequest{
+			Name:     apiKeyName(link),
+			Access:   access,
+			Metadata: map[string]interface{}{linkMetadataKey: link.Namespace + "/" + link.Name},
+		})
+		if err != nil {
+			msg := fmt.Sprintf("Failed to create the cross-cluster API key on Elasticsearch %s: %s", es.Name, esclient.ErrorReason(err))
+			r.recorder.Event(&link, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+			setError(status, msg)
+			return
+		}
+		// the API key previously created for the link cannot be used without its encoded value anymore
+		if status.APIKeyID != "" && status.APIKeyID != apiKey.ID {
+			if err := esClient.InvalidateAPIKeys(ctx, status.APIKeyID); err != nil {
+				log.Error(err, "Failed to invalidate the previous cross-cluster API key", "id", status.APIKeyID)
+			}
+		}
+		id, encoded = apiKey.ID, []byte(apiKey.Encoded)
+	case status.APIKeyID != id || status.AccessHash != accessHash:
+		log.Info("Updating cross-cluster API key access", "id", id)
+		if err := esClient.UpdateCrossClusterAPIKey(ctx, id, access); err != nil {
+			msg := fmt.Sprintf("Failed to update the cross-cluster API key on Elasticsearch %s: %s", es.Name, esclient.ErrorReason(err))
+			r.recorder.Event(&link, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+			setError(status, msg)
+			return
+		}
+	}
+	status.APIKeyID = id
+	status.AccessHash = accessHash
+
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: link.Namespace, Name: link.Spec.ExchangeSecretName},
+		Data: map[string][]byte{
+			remoteclusterv1alpha1.ExchangeSecretCAKey:      ca,
+			remoteclusterv1alpha1.ExchangeSecretAPIKeyKey:  encoded,
+			remoteclusterv1alpha1.ExchangeSecretAddressKey: []byte(link.Spec.Server.Address),
+		},
+	}
+	if link.Spec.Server.ServerName != "" {
+		expected.Data[remoteclusterv1alpha1.ExchangeSecretServerNameKey] = []byte(link.Spec.Server.ServerName)
+	}
+	if _, err := reconciler.ReconcileSecret(ctx, r.Client, expected, &link); err != nil {
+		setApplyingChanges(status, err.Error())
+	}
+}
+
+// deleteServer invalidates the cross-cluster API keys created for the link, the exchange Secret being garbage collected
+// with the RemoteClusterLink.
+func (r *linkKind) deleteServer(ctx context.Context, link remoteclusterv1alpha1.RemoteClusterLink, es esv1.Elasticsearch) error {
+	defer tracing.Span(&ctx)()
+
+	var ids []string
+	if link.Status.APIKeyID != "" {
+		ids = append(ids, link.Status.APIKeyID)
+	}
+	var exchangeSecret corev1.Secret
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: link.Namespace, Name: link.Spec.ExchangeSecretName}, &exchangeSecret); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if id, published := apiKeyID(exchangeSecret.Data[remoteclusterv1alpha1.ExchangeSecretAPIKeyKey]); published && id != link.Status.APIKeyID {
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+
+	ulog.FromContext(ctx).Info("Invalidating cross-cluster API keys", "es_name", es.Name, "ids", ids)
+	if err := esClient.InvalidateAPIKeys(ctx, ids...); err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// apiKeyName returns the name of the cross-cluster API key created for the given link.
+func apiKeyName(link remoteclusterv1alpha1.RemoteClusterLink) string {
+	return "eck-" + link.Namespace + "-" + link.Name
+}
+
+// apiKeyID returns the ID of the given encoded API key, which is the base64 encoding of the ID and of the key separated
+// by a colon.
+func apiKeyID(encoded []byte) (string, bool) {
+	if len(encoded) == 0 {
+		return "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(string(encoded))
+	if err != nil {
+		return "", false
+	}
+	id, _, found := strings.Cut(string(decoded), ":")
+	return id, found && id != ""
+}
+
+func crossClusterAPIKeyAccess(access remoteclusterv1alpha1.CrossClusterAccess) esclient.CrossClusterAPIKeyAccess {
+	return esclient.CrossClusterAPIKeyAccess{
+		Search:      crossClusterIndices(access.Search),
+		Replication: crossClusterIndices(access.Replication),
+	}
+}
+
+func crossClusterIndices(indices []remoteclusterv1alpha1.CrossClusterIndices) []esclient.CrossClusterIndices {
+	if len(indices) == 0 {
+		return nil
+	}
+	result := make([]esclient.CrossClusterIndices, 0, len(indices))
+	for _, i := range indices {
+		result = append(result, esclient.CrossClusterIndices{Names: i.Names, AllowRestrictedIndices: i.AllowRestrictedIndices})
+	}
+	return result
+}
+
+func setApplyingChanges(status *remoteclusterv1alpha1.RemoteClusterLinkStatus, msg string) {
+	status.Phase = remoteclusterv1alpha1.ApplyingChangesPhase
+	status.Message = msg
+}
+
+func setError(status *remoteclusterv1alpha1.RemoteClusterLinkStatus, msg string) {
+	status.Phase = remoteclusterv1alpha1.ErrorPhase
+	status.Message = msg
+}
@@ -0,0 +1,150 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotlifecyclepolicy
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	controllerName = "snapshotlifecyclepolicy-controller"
+)
+
+// statusRefreshRequeue is used to periodically refresh the executions of the policy reported in the status.
+var statusRefreshRequeue = reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Minute}
+
+// config identifies the SnapshotLifecyclePolicy controller. Deleted policies stay configured in Elasticsearch.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "SnapshotLifecyclePolicy",
+	NameField:      "policy_name",
+}
+
+// Add creates a new SnapshotLifecyclePolicy Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Elasticsearch and reconcile the SnapshotLifecyclePolicy resources referencing them
+		source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestForPolicies(r.Client, func(policy *snapshotv1alpha1.SnapshotLifecyclePolicy, obj client.Object) bool {
+			return policy.References(k8s.ExtractNamespacedName(obj))
+		})),
+		// watch for changes to SnapshotRepository and reconcile the SnapshotLifecyclePolicy resources storing snapshots in them
+		source.Kind[client.Object](mgr.GetCache(), &snapshotv1alpha1.SnapshotRepository{}, reconcileRequestForPolicies(r.Client, func(policy *snapshotv1alpha1.SnapshotLifecyclePolicy, obj client.Object) bool {
+			return policy.Spec.RepositoryRef.Name == obj.GetName()
+		})),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of SnapshotLifecyclePolicy.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*snapshotv1alpha1.SnapshotLifecyclePolicy, snapshotv1alpha1.SnapshotLifecyclePolicyStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &policyKind{
+		Client:           c,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+// reconcileRequestForPolicies returns the requests to reconcile the SnapshotLifecyclePolicy resources, in the namespace
+// of the watched object, that match the given predicate.
+func reconcileRequestForPolicies(
+	clnt k8s.Client,
+	matches func(policy *snapshotv1alpha1.SnapshotLifecyclePolicy, obj client.Object) bool,
+) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &snapshotv1alpha1.SnapshotLifecyclePolicyList{}, matches)
+}
+
+// policyKind configures SnapshotLifecyclePolicy resources in Elasticsearch.
+type policyKind struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var _ apiresource.Kind[*snapshotv1alpha1.SnapshotLifecyclePolicy, snapshotv1alpha1.SnapshotLifecyclePolicyStatus] = &policyKind{}
+
+func (r *policyKind) NewObject() *snapshotv1alpha1.SnapshotLifecyclePolicy {
+	return &snapshotv1alpha1.SnapshotLifecyclePolicy{}
+}
+
+func (r *policyKind) GetStatus(policy *snapshotv1alpha1.SnapshotLifecyclePolicy) snapshotv1alpha1.SnapshotLifecyclePolicyStatus {
+	return policy.Status
+}
+
+func (r *policyKind) SetStatus(policy *snapshotv1alpha1.SnapshotLifecyclePolicy, status snapshotv1alpha1.SnapshotLifecyclePolicyStatus) {
+	policy.Status = status
+}
+
+func (r *policyKind) InvalidStatus(policy *snapshotv1alpha1.SnapshotLifecyclePolicy, _ error) snapshotv1alpha1.SnapshotLifecyclePolicyStatus {
+	status := snapshotv1alpha1.NewPolicyStatus(*policy)
+	status.Phase = snapshotv1alpha1.InvalidPhase
+	return status
+}
+
+// Configure configures the policy on the referenced Elasticsearch clusters, and deletes it from the clusters that are
+// no longer referenced.
+func (r *policyKind) Configure(ctx context.Context, obj *snapshotv1alpha1.SnapshotLifecyclePolicy) (*reconciler.Results, snapshotv1alpha1.SnapshotLifecyclePolicyStatus) {
+	policy := *obj
+	log := ulog.FromContext(ctx)
+	results := reconciler.NewResult(ctx)
+	status := snapshotv1alpha1.NewPolicyStatus(policy)
+
+	// retrieve the repository snapshots are stored in
+	repository, err := r.getRepository(ctx, policy)
+	if err != nil {
+		return results.WithError(err), status
+	}
+
+	// configure the policy on the referenced Elasticsearch clusters
+	referenced := make(map[string]struct{}, len(policy.Spec.ElasticsearchRefs))
+	for _, ref := range policy.Spec.ElasticsearchRefs {
+		referenced[ref.Name] = struct{}{}
+		status.SetElasticsearchStatus(ref.Name, r.reconcileElasticsearch(ctx, policy, repository, ref.Name))
+	}
+
+	// delete the policy from the clusters that are no longer referenced
+	for esName := range policy.Status.Details {
+		if _, exists := referenced[esName]; exists {
+			continue
+		}
+		if err := r.deletePolicy(ctx, policy, esName); err != nil {
+			log.Error(err, "Failed to delete the snapshot lifecycle policy", "es_name", esName)
+			status.SetElasticsearchStatus(esName, snapshotv1alpha1.ElasticsearchPolicyStatus{
+				Phase:   snapshotv1alpha1.ApplyingChangesPhase,
+				Message: "Failed to delete the policy: " + err.Error(),
+			})
+		}
+	}
+
+	if status.Phase != snapshotv1alpha1.ReadyPhase {
+		// requeue if not ready
+		results.WithResult(apiresource.DefaultRequeue)
+	} else {
+		// refresh the executions of the policy
+		results.WithResult(statusRefreshRequeue)
+	}
+
+	return results, status
+}
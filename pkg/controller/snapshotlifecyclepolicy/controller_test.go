@@ -0,0 +1,209 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotlifecyclepolicy
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+const nextExecutionMillis = 1704245400000
+
+// fakeEsClient stores SLM policies in memory.
+type fakeEsClient struct {
+	esclient.Client
+
+	policies map[string]esclient.SnapshotLifecyclePolicyInfo
+	updates  *int
+}
+
+func (c fakeEsClient) GetSnapshotLifecyclePolicy(_ context.Context, id string) (esclient.SnapshotLifecyclePolicyInfo, error) {
+	policy, exists := c.policies[id]
+	if !exists {
+		return esclient.SnapshotLifecyclePolicyInfo{}, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return policy, nil
+}
+
+func (c fakeEsClient) UpdateSnapshotLifecyclePolicy(_ context.Context, id string, policy esclient.SnapshotLifecyclePolicy) error {
+	*c.updates++
+	info := c.policies[id]
+	info.Policy = policy
+	info.NextExecutionMillis = nextExecutionMillis
+	c.policies[id] = info
+	return nil
+}
+
+func (c fakeEsClient) DeleteSnapshotLifecyclePolicy(_ context.Context, id string) error {
+	if _, exists := c.policies[id]; !exists {
+		return &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	delete(c.policies, id)
+	return nil
+}
+
+func (c fakeEsClient) Close() {}
+
+type fakeElasticsearch struct {
+	policies map[string]esclient.SnapshotLifecyclePolicyInfo
+	updates  int
+}
+
+func fakeClientProvider(clusters map[string]*fakeElasticsearch) commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, es esv1.Elasticsearch) (esclient.Client, error) {
+		cluster := clusters[es.Name]
+		return fakeEsClient{policies: cluster.policies, updates: &cluster.updates}, nil
+	}
+}
+
+func TestReconcileSnapshotLifecyclePolicy_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	policy := &snapshotv1alpha1.SnapshotLifecyclePolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "daily"},
+		Spec: snapshotv1alpha1.SnapshotLifecyclePolicySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es1"}, {Name: "es2"}},
+			Schedule:          "0 30 1 * * ?",
+			RepositoryRef:     snapshotv1alpha1.RepositoryReference{Name: "backups"},
+			Config:            &commonv1.Config{Data: map[string]interface{}{"indices": []interface{}{"*"}}},
+			Retention:         &snapshotv1alpha1.SnapshotRetention{ExpireAfter: "30d", MaxCount: ptr.To[int32](10)},
+		},
+	}
+	repository := &snapshotv1alpha1.SnapshotRepository{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backups"},
+		Spec: snapshotv1alpha1.SnapshotRepositorySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es1"}, {Name: "es2"}},
+			RepositoryName:    "my_repository",
+			Type:              snapshotv1alpha1.S3RepositoryType,
+		},
+		Status: snapshotv1alpha1.SnapshotRepositoryStatus{
+			Details: map[string]snapshotv1alpha1.ElasticsearchRepositoryStatus{
+				"es1": {Phase: snapshotv1alpha1.ReadyPhase},
+				"es2": {Phase: snapshotv1alpha1.ApplyingChangesPhase},
+			},
+		},
+	}
+	elasticsearch := func(name string) *esv1.Elasticsearch {
+		return &esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+		}
+	}
+	clusters := map[string]*fakeElasticsearch{
+		"es1": {policies: map[string]esclient.SnapshotLifecyclePolicyInfo{}},
+		"es2": {policies: map[string]esclient.SnapshotLifecyclePolicyInfo{}},
+	}
+	k8sClient := k8s.NewFakeClient(policy, repository, elasticsearch("es1"), elasticsearch("es2"))
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &policyKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(clusters),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "daily"}}
+	getStatus := func() snapshotv1alpha1.SnapshotLifecyclePolicyStatus {
+		var actual snapshotv1alpha1.SnapshotLifecyclePolicy
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual.Status
+	}
+
+	// the policy is configured on the cluster the repository is registered on
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	status := getStatus()
+	require.Equal(t, snapshotv1alpha1.ApplyingChangesPhase, status.Phase)
+	require.Equal(t, "1/2", status.ReadyCount)
+	require.Equal(t, "Waiting for SnapshotRepository backups to be registered", status.Details["es2"].Message)
+	require.Equal(t, esclient.SnapshotLifecyclePolicy{
+		Schedule:   "0 30 1 * * ?",
+		Name:       "<daily-{now/d}>",
+		Repository: "my_repository",
+		Config:     map[string]interface{}{"indices": []interface{}{"*"}},
+		Retention:  &esclient.SnapshotLifecyclePolicyRetention{ExpireAfter: "30d", MaxCount: ptr.To[int32](10)},
+	}, clusters["es1"].policies["daily"].Policy)
+	require.True(t, status.Details["es1"].NextExecution.Equal(&metav1.Time{Time: time.UnixMilli(nextExecutionMillis)}))
+	require.Empty(t, clusters["es2"].policies)
+
+	// once the repository is registered on the second cluster, the policy is configured on both clusters
+	// and the executions of the policy are reported
+	repository.Status.Details["es2"] = snapshotv1alpha1.ElasticsearchRepositoryStatus{Phase: snapshotv1alpha1.ReadyPhase}
+	require.NoError(t, k8sClient.Status().Update(ctx, repository))
+	info := clusters["es1"].policies["daily"]
+	info.LastSuccess = &esclient.SnapshotInvocation{SnapshotName: "daily-2024.01.02-abc", TimeMillis: 1704159060123}
+	clusters["es1"].policies["daily"] = info
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, statusRefreshRequeue, res)
+	status = getStatus()
+	require.Equal(t, snapshotv1alpha1.ReadyPhase, status.Phase)
+	require.Equal(t, "2/2", status.ReadyCount)
+	require.Equal(t, "daily-2024.01.02-abc", status.Details["es1"].LastSuccess.SnapshotName)
+	require.True(t, status.Details["es1"].LastSuccess.Time.Equal(&metav1.Time{Time: time.Unix(1704159060, 0)}))
+	require.Equal(t, 1, clusters["es1"].updates)
+	require.Equal(t, 1, clusters["es2"].updates)
+
+	// the policy is not updated if it did not change
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 1, clusters["es1"].updates)
+
+	// the policy is deleted from the clusters that are no longer referenced
+	var actual snapshotv1alpha1.SnapshotLifecyclePolicy
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+	actual.Spec.ElasticsearchRefs = actual.Spec.ElasticsearchRefs[1:]
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	status = getStatus()
+	require.Equal(t, "1/1", status.ReadyCount)
+	require.NotContains(t, status.Details, "es1")
+	require.Empty(t, clusters["es1"].policies)
+	require.NotEmpty(t, clusters["es2"].policies)
+}
+
+func TestReconcileSnapshotLifecyclePolicy_Reconcile_RepositoryNotFound(t *testing.T) {
+	policy := &snapshotv1alpha1.SnapshotLifecyclePolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "daily"},
+		Spec: snapshotv1alpha1.SnapshotLifecyclePolicySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			Schedule:          "0 30 1 * * ?",
+			RepositoryRef:     snapshotv1alpha1.RepositoryReference{Name: "backups"},
+		},
+	}
+	es := &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	k8sClient := k8s.NewFakeClient(policy, es)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &policyKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(nil),
+		recorder:         recorder,
+	})
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(policy)})
+	require.NoError(t, err)
+
+	var actual snapshotv1alpha1.SnapshotLifecyclePolicy
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(policy), &actual))
+	require.Equal(t, snapshotv1alpha1.ErrorPhase, actual.Status.Phase)
+	require.Equal(t, "SnapshotRepository backups not found", actual.Status.Details["es"].Message)
+}
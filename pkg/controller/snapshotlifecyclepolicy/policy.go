@@ -0,0 +1,190 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotlifecyclepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// getRepository returns the SnapshotRepository referenced by the policy, or nil if it does not exist.
+func (r *policyKind) getRepository(ctx context.Context, policy snapshotv1alpha1.SnapshotLifecyclePolicy) (*snapshotv1alpha1.SnapshotRepository, error) {
+	var repository snapshotv1alpha1.SnapshotRepository
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: policy.Namespace, Name: policy.Spec.RepositoryRef.Name}, &repository)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &repository, nil
+}
+
+// reconcileElasticsearch configures the policy on the given Elasticsearch cluster if it does not exist yet or if it
+// changed, and returns the status of the policy for this cluster.
+func (r *policyKind) reconcileElasticsearch(
+	ctx context.Context,
+	policy snapshotv1alpha1.SnapshotLifecyclePolicy,
+	repository *snapshotv1alpha1.SnapshotRepository,
+	esName string,
+) snapshotv1alpha1.ElasticsearchPolicyStatus {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx).WithValues("es_name", esName)
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: policy.Namespace, Name: esName}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return errorStatus(fmt.Sprintf("Elasticsearch %s not found", esName))
+		}
+		return applyingChangesStatus(err.Error())
+	}
+
+	// the repository must be registered on the cluster for the policy to be accepted
+	switch {
+	case repository == nil:
+		return errorStatus(fmt.Sprintf("SnapshotRepository %s not found", policy.Spec.RepositoryRef.Name))
+	case !repository.References(types.NamespacedName{Namespace: es.Namespace, Name: es.Name}):
+		return errorStatus(fmt.Sprintf("SnapshotRepository %s is not registered on Elasticsearch %s", repository.Name, esName))
+	case repository.Status.Details[esName].Phase != snapshotv1alpha1.ReadyPhase:
+		return applyingChangesStatus(fmt.Sprintf("Waiting for SnapshotRepository %s to be registered", repository.Name))
+	case es.Status.Phase != esv1.ElasticsearchReadyPhase:
+		return applyingChangesStatus("Waiting for Elasticsearch to be ready")
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	defer esClient.Close()
+
+	id := policy.PolicyNameOrDefault()
+	expected := expectedPolicy(policy, *repository)
+	actual, err := esClient.GetSnapshotLifecyclePolicy(ctx, id)
+	if err != nil && !esclient.IsNotFound(err) {
+		return applyingChangesStatus(err.Error())
+	}
+	if err != nil || !SamePolicy(expected, actual.Policy) {
+		log.Info("Updating snapshot lifecycle policy", "policy", id)
+		if err := esClient.UpdateSnapshotLifecyclePolicy(ctx, id, expected); err != nil {
+			msg := fmt.Sprintf("Failed to update snapshot lifecycle policy %s on Elasticsearch %s: %s", id, esName, esclient.ErrorReason(err))
+			r.recorder.Event(&policy, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+			return errorStatus(msg)
+		}
+		// retrieve the next execution of the updated policy
+		if actual, err = esClient.GetSnapshotLifecyclePolicy(ctx, id); err != nil {
+			return applyingChangesStatus(err.Error())
+		}
+	}
+	return readyStatus(actual)
+}
+
+// deletePolicy deletes the policy from the given Elasticsearch cluster. The snapshots it took are not deleted.
+func (r *policyKind) deletePolicy(ctx context.Context, policy snapshotv1alpha1.SnapshotLifecyclePolicy, esName string) error {
+	defer tracing.Span(&ctx)()
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: policy.Namespace, Name: esName}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+
+	ulog.FromContext(ctx).Info("Deleting snapshot lifecycle policy", "es_name", esName, "policy", policy.PolicyNameOrDefault())
+	if err := esClient.DeleteSnapshotLifecyclePolicy(ctx, policy.PolicyNameOrDefault()); err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func expectedPolicy(policy snapshotv1alpha1.SnapshotLifecyclePolicy, repository snapshotv1alpha1.SnapshotRepository) esclient.SnapshotLifecyclePolicy {
+	expected := esclient.SnapshotLifecyclePolicy{
+		Schedule:   policy.Spec.Schedule,
+		Name:       policy.SnapshotNameOrDefault(),
+		Repository: repository.RepositoryNameOrDefault(),
+	}
+	if policy.Spec.Config != nil {
+		expected.Config = policy.Spec.Config.Data
+	}
+	if retention := policy.Spec.Retention; retention != nil {
+		expected.Retention = &esclient.SnapshotLifecyclePolicyRetention{
+			ExpireAfter: retention.ExpireAfter,
+			MinCount:    retention.MinCount,
+			MaxCount:    retention.MaxCount,
+		}
+	}
+	return expected
+}
+
+// SamePolicy compares the JSON representations of the policies, as numbers in the policy configuration may be
+// decoded with different types.
+func SamePolicy(expected, actual esclient.SnapshotLifecyclePolicy) bool {
+	expectedBytes, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	actualBytes, err := json.Marshal(actual)
+	if err != nil {
+		return false
+	}
+	return string(expectedBytes) == string(actualBytes)
+}
+
+func readyStatus(info esclient.SnapshotLifecyclePolicyInfo) snapshotv1alpha1.ElasticsearchPolicyStatus {
+	status := snapshotv1alpha1.ElasticsearchPolicyStatus{
+		Phase:       snapshotv1alpha1.ReadyPhase,
+		LastSuccess: toSnapshotInvocation(info.LastSuccess),
+		LastFailure: toSnapshotInvocation(info.LastFailure),
+	}
+	if info.NextExecutionMillis > 0 {
+		nextExecution := toTime(info.NextExecutionMillis)
+		status.NextExecution = &nextExecution
+	}
+	return status
+}
+
+func toSnapshotInvocation(invocation *esclient.SnapshotInvocation) *snapshotv1alpha1.SnapshotInvocation {
+	if invocation == nil {
+		return nil
+	}
+	return &snapshotv1alpha1.SnapshotInvocation{
+		SnapshotName: invocation.SnapshotName,
+		Time:         toTime(invocation.TimeMillis),
+		Details:      invocation.Details,
+	}
+}
+
+// toTime converts milliseconds since epoch to a time truncated to the second, as serialized in the status.
+func toTime(millis int64) metav1.Time {
+	return metav1.NewTime(time.UnixMilli(millis).Truncate(time.Second))
+}
+
+func applyingChangesStatus(msg string) snapshotv1alpha1.ElasticsearchPolicyStatus {
+	return snapshotv1alpha1.ElasticsearchPolicyStatus{Phase: snapshotv1alpha1.ApplyingChangesPhase, Message: msg}
+}
+
+func errorStatus(msg string) snapshotv1alpha1.ElasticsearchPolicyStatus {
+	return snapshotv1alpha1.ElasticsearchPolicyStatus{Phase: snapshotv1alpha1.ErrorPhase, Message: msg}
+}
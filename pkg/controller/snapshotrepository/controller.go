@@ -0,0 +1,197 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	controllerName = "snapshotrepository-controller"
+
+	// RepositoryFinalizer lets the operator unregister the repository from Elasticsearch before the SnapshotRepository
+	// resource is deleted.
+	RepositoryFinalizer = "snapshot.k8s.elastic.co/unregister-repository"
+)
+
+// config identifies the SnapshotRepository controller. Secure settings of deleted repositories are removed from the
+// keystore by the Elasticsearch controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "SnapshotRepository",
+	NameField:      "repository_name",
+	Finalizer:      RepositoryFinalizer,
+}
+
+// Add creates a new SnapshotRepository Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Elasticsearch and reconcile the SnapshotRepository resources referencing them
+		source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestForReferencingRepositories(r.Client)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of SnapshotRepository.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*snapshotv1alpha1.SnapshotRepository, snapshotv1alpha1.SnapshotRepositoryStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &repositoryKind{
+		Client:           c,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+// reconcileRequestForReferencingRepositories returns the requests to reconcile the SnapshotRepository resources
+// referencing an Elasticsearch cluster.
+func reconcileRequestForReferencingRepositories(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return handler.TypedEnqueueRequestsFromMapFunc[client.Object](func(ctx context.Context, es client.Object) []reconcile.Request {
+		var repositories snapshotv1alpha1.SnapshotRepositoryList
+		if err := clnt.List(ctx, &repositories, client.InNamespace(es.GetNamespace())); err != nil {
+			ulog.Log.Error(err, "Fail to list SnapshotRepositoryList while watching Elasticsearch")
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, repository := range repositories.Items {
+			repository := repository
+			if repository.References(k8s.ExtractNamespacedName(es)) {
+				requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&repository)})
+			}
+		}
+		return requests
+	})
+}
+
+// repositoryKind registers SnapshotRepository resources in Elasticsearch.
+type repositoryKind struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*snapshotv1alpha1.SnapshotRepository, snapshotv1alpha1.SnapshotRepositoryStatus] = &repositoryKind{}
+	_ apiresource.Remover[*snapshotv1alpha1.SnapshotRepository]                                         = &repositoryKind{}
+)
+
+func (r *repositoryKind) NewObject() *snapshotv1alpha1.SnapshotRepository {
+	return &snapshotv1alpha1.SnapshotRepository{}
+}
+
+func (r *repositoryKind) GetStatus(repository *snapshotv1alpha1.SnapshotRepository) snapshotv1alpha1.SnapshotRepositoryStatus {
+	return repository.Status
+}
+
+func (r *repositoryKind) SetStatus(repository *snapshotv1alpha1.SnapshotRepository, status snapshotv1alpha1.SnapshotRepositoryStatus) {
+	repository.Status = status
+}
+
+func (r *repositoryKind) InvalidStatus(repository *snapshotv1alpha1.SnapshotRepository, _ error) snapshotv1alpha1.SnapshotRepositoryStatus {
+	status := snapshotv1alpha1.NewStatus(*repository)
+	status.Phase = snapshotv1alpha1.InvalidPhase
+	return status
+}
+
+// Configure registers the repository on the referenced Elasticsearch clusters, and unregisters it from the clusters
+// that are no longer referenced.
+func (r *repositoryKind) Configure(ctx context.Context, obj *snapshotv1alpha1.SnapshotRepository) (*reconciler.Results, snapshotv1alpha1.SnapshotRepositoryStatus) {
+	repository := *obj
+	log := ulog.FromContext(ctx)
+	results := reconciler.NewResult(ctx)
+	status := snapshotv1alpha1.NewStatus(repository)
+	defer status.Update()
+
+	// make sure the service account of the workload identity exists before the Elasticsearch pods use it
+	if err := reconcileServiceAccount(ctx, r.Client, repository); err != nil {
+		status.Phase = snapshotv1alpha1.ApplyingChangesPhase
+		return results.WithError(err), status
+	}
+
+	// register the repository on the referenced Elasticsearch clusters
+	referenced := make(map[string]struct{}, len(repository.Spec.ElasticsearchRefs))
+	for _, ref := range repository.Spec.ElasticsearchRefs {
+		referenced[ref.Name] = struct{}{}
+		status.SetElasticsearchStatus(ref.Name, r.reconcileElasticsearch(ctx, repository, ref.Name))
+	}
+
+	// unregister the repository from the clusters that are no longer referenced
+	for esName := range repository.Status.Details {
+		if _, exists := referenced[esName]; exists {
+			continue
+		}
+		if err := r.unregister(ctx, repository, esName); err != nil {
+			log.Error(err, "Failed to unregister the snapshot repository", "es_name", esName)
+			status.SetElasticsearchStatus(esName, snapshotv1alpha1.ElasticsearchRepositoryStatus{
+				Phase:   snapshotv1alpha1.ApplyingChangesPhase,
+				Message: "Failed to unregister the repository: " + err.Error(),
+			})
+		}
+	}
+
+	// requeue if not ready
+	if status.Phase != snapshotv1alpha1.ReadyPhase {
+		results.WithResult(apiresource.DefaultRequeue)
+	}
+
+	return results, status
+}
+
+// Remove unregisters the repository from all the clusters it is registered on. It waits for the clusters being deleted
+// to complete their final snapshot in the repository first.
+func (r *repositoryKind) Remove(ctx context.Context, obj *snapshotv1alpha1.SnapshotRepository) (reconcile.Result, error) {
+	repository := *obj
+	for esName := range repository.Status.Details {
+		pending, err := r.pendingFinalSnapshot(ctx, repository, esName)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if pending {
+			ulog.FromContext(ctx).Info("Waiting for the final snapshot of Elasticsearch before unregistering the repository", "es_name", esName)
+			return apiresource.DefaultRequeue, nil
+		}
+	}
+	for esName := range repository.Status.Details {
+		if err := r.unregister(ctx, repository, esName); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// pendingFinalSnapshot returns true if the given cluster is being deleted and its final snapshot is stored in the
+// repository. The final snapshot is completed once the cluster is deleted, or its final snapshot is disabled.
+func (r *repositoryKind) pendingFinalSnapshot(ctx context.Context, repository snapshotv1alpha1.SnapshotRepository, esName string) (bool, error) {
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: repository.Namespace, Name: esName}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return !es.DeletionTimestamp.IsZero() &&
+		es.Spec.FinalSnapshot != nil &&
+		es.Spec.FinalSnapshot.Repository == repository.RepositoryNameOrDefault(), nil
+}
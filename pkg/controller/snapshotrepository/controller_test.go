@@ -0,0 +1,404 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeEsClient registers snapshot repositories in memory, per Elasticsearch cluster.
+type fakeEsClient struct {
+	esclient.Client
+
+	repositories  map[string]esclient.SnapshotRepository
+	updateErr     error
+	updates       *int
+	verifyErr     error
+	verifications *int
+}
+
+func (c fakeEsClient) GetSnapshotRepository(_ context.Context, name string) (esclient.SnapshotRepository, error) {
+	repository, exists := c.repositories[name]
+	if !exists {
+		return esclient.SnapshotRepository{}, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return repository, nil
+}
+
+func (c fakeEsClient) UpdateSnapshotRepository(_ context.Context, name string, repository esclient.SnapshotRepository, _ bool) error {
+	if c.updateErr != nil {
+		return c.updateErr
+	}
+	*c.updates++
+	// Elasticsearch returns all the settings values as strings
+	settings := map[string]interface{}{}
+	for k, v := range flatten(repository.Settings) {
+		settings[k] = v
+	}
+	c.repositories[name] = esclient.SnapshotRepository{Type: repository.Type, Settings: settings}
+	return nil
+}
+
+func (c fakeEsClient) DeleteSnapshotRepository(_ context.Context, name string) error {
+	if _, exists := c.repositories[name]; !exists {
+		return &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	delete(c.repositories, name)
+	return nil
+}
+
+func (c fakeEsClient) VerifySnapshotRepository(_ context.Context, _ string) error {
+	*c.verifications++
+	return c.verifyErr
+}
+
+func (c fakeEsClient) Close() {}
+
+type fakeElasticsearch struct {
+	repositories  map[string]esclient.SnapshotRepository
+	updateErr     error
+	updates       int
+	verifyErr     error
+	verifications int
+}
+
+func fakeClientProvider(clusters map[string]*fakeElasticsearch) commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, es esv1.Elasticsearch) (esclient.Client, error) {
+		cluster := clusters[es.Name]
+		return fakeEsClient{
+			repositories:  cluster.repositories,
+			updateErr:     cluster.updateErr,
+			updates:       &cluster.updates,
+			verifyErr:     cluster.verifyErr,
+			verifications: &cluster.verifications,
+		}, nil
+	}
+}
+
+func elasticsearch(name string, phase esv1.ElasticsearchOrchestrationPhase) *esv1.Elasticsearch {
+	return &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Status:     esv1.ElasticsearchStatus{Phase: phase},
+	}
+}
+
+func TestReconcileSnapshotRepository_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	repository := &snapshotv1alpha1.SnapshotRepository{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backups", Generation: 1},
+		Spec: snapshotv1alpha1.SnapshotRepositorySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es1"}, {Name: "es2"}},
+			Type:              snapshotv1alpha1.S3RepositoryType,
+			Settings: &commonv1.Config{Data: map[string]interface{}{
+				"bucket":   "bucket",
+				"compress": true,
+				"client":   map[string]interface{}{"name": "default"},
+			}},
+		},
+	}
+	clusters := map[string]*fakeElasticsearch{
+		"es1": {repositories: map[string]esclient.SnapshotRepository{}},
+		"es2": {repositories: map[string]esclient.SnapshotRepository{}},
+	}
+	k8sClient := k8s.NewFakeClient(
+		repository,
+		elasticsearch("es1", esv1.ElasticsearchReadyPhase),
+		elasticsearch("es2", esv1.ElasticsearchApplyingChangesPhase),
+	)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &repositoryKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(clusters),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "backups"}}
+	getStatus := func() snapshotv1alpha1.SnapshotRepositoryStatus {
+		var actual snapshotv1alpha1.SnapshotRepository
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual.Status
+	}
+
+	// the repository is registered on the ready cluster only
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.True(t, res.RequeueAfter > 0)
+	status := getStatus()
+	require.Equal(t, snapshotv1alpha1.ApplyingChangesPhase, status.Phase)
+	require.Equal(t, "1/2", status.ReadyCount)
+	require.Equal(t, snapshotv1alpha1.ReadyPhase, status.Details["es1"].Phase)
+	require.Equal(t, "Waiting for Elasticsearch to be ready", status.Details["es2"].Message)
+	require.Equal(t, "s3", clusters["es1"].repositories["backups"].Type)
+	require.Empty(t, clusters["es2"].repositories)
+
+	// once the second cluster is ready, registration fails
+	var es2 esv1.Elasticsearch
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "es2"}, &es2))
+	es2.Status.Phase = esv1.ElasticsearchReadyPhase
+	require.NoError(t, k8sClient.Status().Update(ctx, &es2))
+	apiErr := &esclient.APIError{StatusCode: http.StatusInternalServerError}
+	apiErr.ErrorResponse.Error.Reason = "[backups] path  is not accessible on master node"
+	apiErr.ErrorResponse.Error.CausedBy.Reason = "access denied"
+	clusters["es2"].updateErr = apiErr
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	status = getStatus()
+	require.Equal(t, snapshotv1alpha1.ErrorPhase, status.Phase)
+	require.Equal(t, "Failed to register snapshot repository backups on Elasticsearch es2: [backups] path  is not accessible on master node: access denied",
+		status.Details["es2"].Message)
+	require.Len(t, recorder.Events, 1)
+	require.Equal(t, 1, clusters["es1"].updates)
+
+	// the repository is registered on both clusters, the existing registration is left untouched
+	clusters["es2"].updateErr = nil
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{}, res)
+	status = getStatus()
+	require.Equal(t, snapshotv1alpha1.ReadyPhase, status.Phase)
+	require.Equal(t, "2/2", status.ReadyCount)
+	require.Equal(t, 1, clusters["es1"].updates)
+	require.Equal(t, 1, clusters["es2"].updates)
+
+	// settings changes are applied
+	var actual snapshotv1alpha1.SnapshotRepository
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+	actual.Spec.Settings.Data["bucket"] = "other-bucket"
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, 2, clusters["es1"].updates)
+	require.Equal(t, "other-bucket", clusters["es1"].repositories["backups"].Settings["bucket"])
+
+	// the repository is unregistered from the clusters that are no longer referenced
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+	actual.Spec.ElasticsearchRefs = actual.Spec.ElasticsearchRefs[:1]
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	status = getStatus()
+	require.Equal(t, "1/1", status.ReadyCount)
+	require.NotContains(t, status.Details, "es2")
+	require.Empty(t, clusters["es2"].repositories)
+	require.NotEmpty(t, clusters["es1"].repositories)
+
+	// the repository is unregistered from Elasticsearch with the SnapshotRepository
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+	require.Equal(t, []string{RepositoryFinalizer}, actual.Finalizers)
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, clusters["es1"].repositories)
+	err = k8sClient.Get(ctx, request.NamespacedName, &snapshotv1alpha1.SnapshotRepository{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileSnapshotRepository_Reconcile_RotatedCredentials(t *testing.T) {
+	ctx := context.Background()
+	repository := &snapshotv1alpha1.SnapshotRepository{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backups", Generation: 1},
+		Spec: snapshotv1alpha1.SnapshotRepositorySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			Type:              snapshotv1alpha1.S3RepositoryType,
+			Settings:          &commonv1.Config{Data: map[string]interface{}{"bucket": "bucket"}},
+			SecureSettings: []commonv1.SecretSource{{
+				SecretName: "s3-credentials",
+				Entries:    []commonv1.KeyToPath{{Key: "secret", Path: "s3.client.default.secret_key"}},
+			}},
+		},
+	}
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s3-credentials"},
+		Data:       map[string][]byte{"secret": []byte("old")},
+	}
+	keystore := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: esv1.SecureSettingsSecret("es")},
+		Data:       map[string][]byte{"s3.client.default.secret_key": []byte("old")},
+	}
+	clusters := map[string]*fakeElasticsearch{
+		"es": {repositories: map[string]esclient.SnapshotRepository{}},
+	}
+	k8sClient := k8s.NewFakeClient(repository, credentials, keystore, elasticsearch("es", esv1.ElasticsearchReadyPhase))
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &repositoryKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(clusters),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(repository)}
+	reconcileStatus := func() snapshotv1alpha1.ElasticsearchRepositoryStatus {
+		_, err := r.Reconcile(ctx, request)
+		require.NoError(t, err)
+		var actual snapshotv1alpha1.SnapshotRepository
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual.Status.Details["es"]
+	}
+	// simulate the Elasticsearch controller restarting all the nodes with the content of the keystore Secret
+	rollNodes := func() {
+		require.NoError(t, k8sClient.Get(ctx, k8s.ExtractNamespacedName(keystore), keystore))
+		var es esv1.Elasticsearch
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "es"}, &es))
+		annotations, err := hints.OrchestrationsHints{SecureSettingsHash: hash.HashObject(keystore.Data)}.AsAnnotation()
+		require.NoError(t, err)
+		es.Annotations = annotations
+		require.NoError(t, k8sClient.Update(ctx, &es))
+	}
+	rotate := func(value string) {
+		credentials.Data["secret"] = []byte(value)
+		require.NoError(t, k8sClient.Update(ctx, credentials))
+	}
+
+	// the repository is registered with the current credentials
+	status := reconcileStatus()
+	require.Equal(t, snapshotv1alpha1.ReadyPhase, status.Phase)
+	initialHash := status.SecureSettingsHash
+	require.NotEmpty(t, initialHash)
+	require.Equal(t, 1, clusters["es"].updates)
+
+	// the credentials are rotated, wait for the keystore Secret to be updated
+	rotate("new")
+	status = reconcileStatus()
+	require.Equal(t, snapshotv1alpha1.ApplyingChangesPhase, status.Phase)
+	require.Equal(t, "Waiting for the Elasticsearch nodes to load the rotated credentials", status.Message)
+	require.Equal(t, initialHash, status.SecureSettingsHash)
+
+	// the keystore Secret is updated, wait for the nodes to restart
+	keystore.Data["s3.client.default.secret_key"] = []byte("new")
+	require.NoError(t, k8sClient.Update(ctx, keystore))
+	status = reconcileStatus()
+	require.Equal(t, snapshotv1alpha1.ApplyingChangesPhase, status.Phase)
+	require.Equal(t, 0, clusters["es"].verifications)
+
+	// the nodes restarted, the repository is verified with the rotated credentials
+	rollNodes()
+	status = reconcileStatus()
+	require.Equal(t, snapshotv1alpha1.ReadyPhase, status.Phase)
+	require.NotEqual(t, initialHash, status.SecureSettingsHash)
+	require.Equal(t, 1, clusters["es"].verifications)
+	require.Equal(t, 1, clusters["es"].updates)
+
+	// the repository is not verified again as long as the credentials do not change
+	status = reconcileStatus()
+	require.Equal(t, snapshotv1alpha1.ReadyPhase, status.Phase)
+	require.Equal(t, 1, clusters["es"].verifications)
+
+	// invalid credentials are reported
+	rotate("invalid")
+	keystore.Data["s3.client.default.secret_key"] = []byte("invalid")
+	require.NoError(t, k8sClient.Update(ctx, keystore))
+	rollNodes()
+	apiErr := &esclient.APIError{StatusCode: http.StatusInternalServerError}
+	apiErr.ErrorResponse.Error.Reason = "[backups] path  is not accessible on master node"
+	apiErr.ErrorResponse.Error.CausedBy.Reason = "access denied"
+	clusters["es"].verifyErr = apiErr
+	status = reconcileStatus()
+	require.Equal(t, snapshotv1alpha1.ErrorPhase, status.Phase)
+	require.Equal(t, "Failed to verify snapshot repository backups on Elasticsearch es with the rotated credentials: [backups] path  is not accessible on master node: access denied",
+		status.Message)
+	// the verification failure and the degraded status are both reported
+	require.Len(t, recorder.Events, 2)
+
+	// the verification is retried until it succeeds
+	clusters["es"].verifyErr = nil
+	status = reconcileStatus()
+	require.Equal(t, snapshotv1alpha1.ReadyPhase, status.Phase)
+	require.Equal(t, 3, clusters["es"].verifications)
+}
+
+func TestReconcileSnapshotRepository_Reconcile_Invalid(t *testing.T) {
+	repository := &snapshotv1alpha1.SnapshotRepository{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backups"},
+		Spec: snapshotv1alpha1.SnapshotRepositorySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Namespace: "other", Name: "es"}},
+			Type:              snapshotv1alpha1.FSRepositoryType,
+			Verify:            ptr.To(false),
+		},
+	}
+	k8sClient := k8s.NewFakeClient(repository)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &repositoryKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(nil),
+		recorder:         recorder,
+	})
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(repository)})
+	require.Error(t, err)
+
+	var actual snapshotv1alpha1.SnapshotRepository
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(repository), &actual))
+	require.Equal(t, snapshotv1alpha1.InvalidPhase, actual.Status.Phase)
+}
+
+func TestReconcileSnapshotRepository_Reconcile_FinalSnapshot(t *testing.T) {
+	ctx := context.Background()
+	repository := &snapshotv1alpha1.SnapshotRepository{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backups", Finalizers: []string{RepositoryFinalizer}},
+		Spec: snapshotv1alpha1.SnapshotRepositorySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: "es"}},
+			Type:              snapshotv1alpha1.FSRepositoryType,
+		},
+		Status: snapshotv1alpha1.SnapshotRepositoryStatus{
+			Details: map[string]snapshotv1alpha1.ElasticsearchRepositoryStatus{"es": {Phase: snapshotv1alpha1.ReadyPhase}},
+		},
+	}
+	es := elasticsearch("es", esv1.ElasticsearchReadyPhase)
+	es.Finalizers = []string{"elasticsearch.k8s.elastic.co/final-snapshot"}
+	es.Spec.FinalSnapshot = &esv1.FinalSnapshot{Repository: "backups"}
+	clusters := map[string]*fakeElasticsearch{
+		"es": {repositories: map[string]esclient.SnapshotRepository{"backups": {Type: "fs"}}},
+	}
+	k8sClient := k8s.NewFakeClient(repository, es)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &repositoryKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(clusters),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "backups"}}
+
+	// both the cluster and the repository are deleted
+	require.NoError(t, k8sClient.Delete(ctx, es))
+	require.NoError(t, k8sClient.Delete(ctx, repository))
+
+	// the repository stays registered until the final snapshot of the cluster is completed
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	require.Contains(t, clusters["es"].repositories, "backups")
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &snapshotv1alpha1.SnapshotRepository{}))
+
+	// the cluster is deleted once its final snapshot is completed, the repository can then be deleted
+	var actual esv1.Elasticsearch
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "es"}, &actual))
+	actual.Finalizers = nil
+	require.NoError(t, k8sClient.Update(ctx, &actual))
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{}, res)
+	err = k8sClient.Get(ctx, request.NamespacedName, &snapshotv1alpha1.SnapshotRepository{})
+	require.True(t, apierrors.IsNotFound(err))
+}
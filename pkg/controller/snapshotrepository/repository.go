@@ -0,0 +1,218 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileElasticsearch registers the repository on the given Elasticsearch cluster if it is not registered yet
+// or if its settings changed, verifies it again once rotated credentials are loaded by all the nodes, and returns
+// the status of the repository for this cluster.
+func (r *repositoryKind) reconcileElasticsearch(ctx context.Context, repository snapshotv1alpha1.SnapshotRepository, esName string) (status snapshotv1alpha1.ElasticsearchRepositoryStatus) {
+	defer tracing.Span(&ctx)()
+	log := ulog.FromContext(ctx).WithValues("es_name", esName)
+
+	// keep track of the credentials the repository was last verified with until the current ones are
+	previousHash := repository.Status.Details[esName].SecureSettingsHash
+	defer func() {
+		if status.Phase != snapshotv1alpha1.ReadyPhase {
+			status.SecureSettingsHash = previousHash
+		}
+	}()
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: repository.Namespace, Name: esName}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return errorStatus(fmt.Sprintf("Elasticsearch %s not found", esName))
+		}
+		return applyingChangesStatus(err.Error())
+	}
+	// the Elasticsearch controller runs the pods with the service account of the workload identity
+	msg, err := checkWorkloadIdentity(ctx, r.Client, repository, es)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	if msg != "" {
+		return errorStatus(msg)
+	}
+	// secure settings are added to the keystore by the Elasticsearch controller, wait for the cluster to be ready
+	// before registering the repository as the verification requires the credentials on all the nodes
+	if es.Status.Phase != esv1.ElasticsearchReadyPhase {
+		return applyingChangesStatus("Waiting for Elasticsearch to be ready")
+	}
+
+	settings, err := secureSettings(ctx, r.Client, repository)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	settingsHash := hash.HashObject(settings)
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	defer esClient.Close()
+
+	name := repository.RepositoryNameOrDefault()
+	expected := expectedRepository(repository)
+	actual, err := esClient.GetSnapshotRepository(ctx, name)
+	switch {
+	case err == nil && SameRepository(expected, actual):
+		if previousHash == "" || previousHash == settingsHash || !repository.VerifyOrDefault() {
+			return readyStatus(settingsHash)
+		}
+		// the credentials were rotated since the repository was last verified
+		return r.verifyRotatedCredentials(ctx, esClient, repository, es, settings, settingsHash)
+	case err != nil && !esclient.IsNotFound(err):
+		return applyingChangesStatus(err.Error())
+	}
+
+	log.Info("Registering snapshot repository", "repository", name)
+	if err := esClient.UpdateSnapshotRepository(ctx, name, expected, repository.VerifyOrDefault()); err != nil {
+		msg := fmt.Sprintf("Failed to register snapshot repository %s on Elasticsearch %s: %s", name, esName, esclient.ErrorReason(err))
+		r.recorder.Event(&repository, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return errorStatus(msg)
+	}
+	return readyStatus(settingsHash)
+}
+
+// verifyRotatedCredentials verifies the repository on all the nodes of the given Elasticsearch cluster once they
+// restarted with the rotated credentials in their keystore, to report credentials that do not give access to the
+// repository before the next snapshot fails.
+func (r *repositoryKind) verifyRotatedCredentials(
+	ctx context.Context,
+	esClient esclient.Client,
+	repository snapshotv1alpha1.SnapshotRepository,
+	es esv1.Elasticsearch,
+	settings map[string][]byte,
+	settingsHash string,
+) snapshotv1alpha1.ElasticsearchRepositoryStatus {
+	loaded, err := credentialsLoaded(ctx, r.Client, es, settings)
+	if err != nil {
+		return applyingChangesStatus(err.Error())
+	}
+	if !loaded {
+		return applyingChangesStatus("Waiting for the Elasticsearch nodes to load the rotated credentials")
+	}
+
+	name := repository.RepositoryNameOrDefault()
+	ulog.FromContext(ctx).Info("Verifying snapshot repository with rotated credentials", "es_name", es.Name, "repository", name)
+	if err := esClient.VerifySnapshotRepository(ctx, name); err != nil {
+		msg := fmt.Sprintf("Failed to verify snapshot repository %s on Elasticsearch %s with the rotated credentials: %s", name, es.Name, esclient.ErrorReason(err))
+		r.recorder.Event(&repository, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return errorStatus(msg)
+	}
+	return readyStatus(settingsHash)
+}
+
+// credentialsLoaded returns true if the given secure settings are in the operator-managed secure settings Secret of
+// the Elasticsearch cluster and all the nodes restarted with the content of this Secret in their keystore.
+func credentialsLoaded(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, settings map[string][]byte) (bool, error) {
+	var secret corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: esv1.SecureSettingsSecret(es.Name)}, &secret)
+	if apierrors.IsNotFound(err) {
+		// credentials removed from a cluster without any other secure settings
+		return len(settings) == 0, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for k, v := range settings {
+		if !bytes.Equal(secret.Data[k], v) {
+			return false, nil
+		}
+	}
+	esHints, err := hints.NewFrom(es)
+	if err != nil {
+		return false, err
+	}
+	return esHints.SecureSettingsHash == hash.HashObject(secret.Data), nil
+}
+
+// unregister removes the repository from the given Elasticsearch cluster. The snapshots it holds are not deleted.
+func (r *repositoryKind) unregister(ctx context.Context, repository snapshotv1alpha1.SnapshotRepository, esName string) error {
+	defer tracing.Span(&ctx)()
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: repository.Namespace, Name: esName}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+
+	ulog.FromContext(ctx).Info("Unregistering snapshot repository", "es_name", esName, "repository", repository.RepositoryNameOrDefault())
+	if err := esClient.DeleteSnapshotRepository(ctx, repository.RepositoryNameOrDefault()); err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func expectedRepository(repository snapshotv1alpha1.SnapshotRepository) esclient.SnapshotRepository {
+	expected := esclient.SnapshotRepository{Type: string(repository.Spec.Type)}
+	if repository.Spec.Settings != nil {
+		expected.Settings = repository.Spec.Settings.Data
+	}
+	return expected
+}
+
+// SameRepository compares the expected repository with the one registered in Elasticsearch, which returns
+// all the settings values as strings.
+func SameRepository(expected, actual esclient.SnapshotRepository) bool {
+	return expected.Type == actual.Type && reflect.DeepEqual(flatten(expected.Settings), flatten(actual.Settings))
+}
+
+// flatten returns the given settings indexed by their dotted key with string values.
+func flatten(settings map[string]interface{}) map[string]string {
+	flattened := map[string]string{}
+	var flattenInto func(prefix string, settings map[string]interface{})
+	flattenInto = func(prefix string, settings map[string]interface{}) {
+		for k, v := range settings {
+			if nested, ok := v.(map[string]interface{}); ok {
+				flattenInto(prefix+k+".", nested)
+				continue
+			}
+			flattened[prefix+k] = fmt.Sprintf("%v", v)
+		}
+	}
+	flattenInto("", settings)
+	return flattened
+}
+
+func readyStatus(secureSettingsHash string) snapshotv1alpha1.ElasticsearchRepositoryStatus {
+	return snapshotv1alpha1.ElasticsearchRepositoryStatus{Phase: snapshotv1alpha1.ReadyPhase, SecureSettingsHash: secureSettingsHash}
+}
+
+func applyingChangesStatus(msg string) snapshotv1alpha1.ElasticsearchRepositoryStatus {
+	return snapshotv1alpha1.ElasticsearchRepositoryStatus{Phase: snapshotv1alpha1.ApplyingChangesPhase, Message: msg}
+}
+
+func errorStatus(msg string) snapshotv1alpha1.ElasticsearchRepositoryStatus {
+	return snapshotv1alpha1.ElasticsearchRepositoryStatus{Phase: snapshotv1alpha1.ErrorPhase, Message: msg}
+}
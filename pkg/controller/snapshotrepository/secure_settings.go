@@ -0,0 +1,84 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// GetSecureSettingsSecretSourcesForResources returns the Secrets holding the secure settings of the
+// SnapshotRepository resources registered on the given resource. Only Elasticsearch resources are supported.
+func GetSecureSettingsSecretSourcesForResources(ctx context.Context, c k8s.Client, resource metav1.Object, resourceKind string) ([]commonv1.NamespacedSecretSource, error) {
+	if resourceKind != esv1.Kind {
+		return nil, nil
+	}
+
+	var repositories snapshotv1alpha1.SnapshotRepositoryList
+	if err := c.List(ctx, &repositories, client.InNamespace(resource.GetNamespace())); err != nil {
+		return nil, err
+	}
+	// sort the repositories for the keystore to be built in a stable order
+	sort.Slice(repositories.Items, func(i, j int) bool {
+		return repositories.Items[i].Name < repositories.Items[j].Name
+	})
+
+	var sources []commonv1.NamespacedSecretSource
+	for _, repository := range repositories.Items {
+		if !repository.References(k8s.ExtractNamespacedName(resource)) {
+			continue
+		}
+		for _, source := range repository.Spec.SecureSettings {
+			sources = append(sources, commonv1.NamespacedSecretSource{
+				Namespace:  repository.Namespace,
+				SecretName: source.SecretName,
+				Entries:    source.Entries,
+			})
+		}
+	}
+	return sources, nil
+}
+
+// secureSettings returns the secure settings of the repository as they are added to the keystore of the Elasticsearch
+// clusters, projected on the entries of each Secret. Secrets that do not exist yet are ignored as they are by the keystore.
+func secureSettings(ctx context.Context, c k8s.Client, repository snapshotv1alpha1.SnapshotRepository) (map[string][]byte, error) {
+	settings := map[string][]byte{}
+	for _, source := range repository.Spec.SecureSettings {
+		var secret corev1.Secret
+		if err := c.Get(ctx, types.NamespacedName{Namespace: repository.Namespace, Name: source.SecretName}, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if source.Entries == nil {
+			for k, v := range secret.Data {
+				settings[k] = v
+			}
+			continue
+		}
+		for _, entry := range source.Entries {
+			key := entry.Path
+			if key == "" {
+				key = entry.Key
+			}
+			if value, exists := secret.Data[entry.Key]; exists {
+				settings[key] = value
+			}
+		}
+	}
+	return settings, nil
+}
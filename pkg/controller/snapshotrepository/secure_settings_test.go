@@ -0,0 +1,49 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestGetSecureSettingsSecretSourcesForResources(t *testing.T) {
+	repository := func(namespace, name, esName, secretName string) *snapshotv1alpha1.SnapshotRepository {
+		return &snapshotv1alpha1.SnapshotRepository{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: snapshotv1alpha1.SnapshotRepositorySpec{
+				ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: esName}},
+				SecureSettings:    []commonv1.SecretSource{{SecretName: secretName}},
+			},
+		}
+	}
+	k8sClient := k8s.NewFakeClient(
+		repository("ns", "z-repository", "es", "z-credentials"),
+		repository("ns", "a-repository", "es", "a-credentials"),
+		repository("ns", "other-es-repository", "other-es", "other-es-credentials"),
+		repository("other-ns", "other-ns-repository", "es", "other-ns-credentials"),
+	)
+	es := metav1.ObjectMeta{Namespace: "ns", Name: "es"}
+
+	sources, err := GetSecureSettingsSecretSourcesForResources(context.Background(), k8sClient, &esv1.Elasticsearch{ObjectMeta: es}, esv1.Kind)
+	require.NoError(t, err)
+	require.Equal(t, []commonv1.NamespacedSecretSource{
+		{Namespace: "ns", SecretName: "a-credentials"},
+		{Namespace: "ns", SecretName: "z-credentials"},
+	}, sources)
+
+	sources, err = GetSecureSettingsSecretSourcesForResources(context.Background(), k8sClient, &kbv1.Kibana{ObjectMeta: es}, kbv1.Kind)
+	require.NoError(t, err)
+	require.Empty(t, sources)
+}
@@ -0,0 +1,148 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// GCPServiceAccountAnnotation binds a Kubernetes service account to a Google Cloud service account on GKE.
+const GCPServiceAccountAnnotation = "iam.gke.io/gcp-service-account"
+
+// workloadIdentityMinVersions are the minimum Elasticsearch versions supporting workload identity for each repository type.
+var workloadIdentityMinVersions = map[snapshotv1alpha1.RepositoryType]version.Version{
+	snapshotv1alpha1.S3RepositoryType:    version.MinFor(8, 1, 0),
+	snapshotv1alpha1.GCSRepositoryType:   version.MinFor(7, 13, 0),
+	snapshotv1alpha1.AzureRepositoryType: version.MinFor(8, 16, 0),
+}
+
+// WorkloadIdentityFor returns the workload identity the pods of the given Elasticsearch cluster run with, or nil if
+// none of the SnapshotRepository resources registered on the cluster has one. The service account is that of the
+// first repository by name, the identities of the repositories using another service account are ignored.
+func WorkloadIdentityFor(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) (*snapshotv1alpha1.WorkloadIdentity, error) {
+	var repositories snapshotv1alpha1.SnapshotRepositoryList
+	if err := c.List(ctx, &repositories, client.InNamespace(es.Namespace)); err != nil {
+		return nil, err
+	}
+	sort.Slice(repositories.Items, func(i, j int) bool {
+		return repositories.Items[i].Name < repositories.Items[j].Name
+	})
+
+	var identity *snapshotv1alpha1.WorkloadIdentity
+	for _, repository := range repositories.Items {
+		repositoryIdentity := repository.Spec.WorkloadIdentity
+		if repositoryIdentity == nil || !repository.References(k8s.ExtractNamespacedName(&es)) {
+			continue
+		}
+		if identity == nil {
+			identity = repositoryIdentity.DeepCopy()
+			continue
+		}
+		if repositoryIdentity.ServiceAccountName != identity.ServiceAccountName {
+			continue
+		}
+		// repositories of different types can share the same service account
+		if identity.AWSRoleARN == "" {
+			identity.AWSRoleARN = repositoryIdentity.AWSRoleARN
+		}
+		if identity.GCPServiceAccount == "" {
+			identity.GCPServiceAccount = repositoryIdentity.GCPServiceAccount
+		}
+		if identity.AzureClientID == "" {
+			identity.AzureClientID = repositoryIdentity.AzureClientID
+			identity.AzureTenantID = repositoryIdentity.AzureTenantID
+		}
+	}
+	return identity, nil
+}
+
+// checkWorkloadIdentity returns a message explaining why the Elasticsearch cluster cannot access the repository with
+// its workload identity, or an empty string if it can.
+func checkWorkloadIdentity(ctx context.Context, c k8s.Client, repository snapshotv1alpha1.SnapshotRepository, es esv1.Elasticsearch) (string, error) {
+	expected := repository.Spec.WorkloadIdentity
+	if expected == nil {
+		return "", nil
+	}
+	ver, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return "", err
+	}
+	if minVersion := workloadIdentityMinVersions[repository.Spec.Type]; ver.LT(minVersion) {
+		return fmt.Sprintf("Workload identity for %s repositories requires Elasticsearch %s or later", repository.Spec.Type, version.WithoutPre(minVersion)), nil
+	}
+	for _, nodeSet := range es.Spec.NodeSets {
+		if name := nodeSet.PodTemplate.Spec.ServiceAccountName; name != "" && name != expected.ServiceAccountName {
+			return fmt.Sprintf("Elasticsearch %s node set %s runs with service account %s", es.Name, nodeSet.Name, name), nil
+		}
+	}
+	actual, err := WorkloadIdentityFor(ctx, c, es)
+	if err != nil {
+		return "", err
+	}
+	if actual != nil && actual.ServiceAccountName != expected.ServiceAccountName {
+		return fmt.Sprintf("Elasticsearch %s runs with service account %s of another SnapshotRepository", es.Name, actual.ServiceAccountName), nil
+	}
+	return "", nil
+}
+
+// reconcileServiceAccount creates the service account of the workload identity of the repository if it does not
+// exist, and binds it to the Google Cloud service account on GKE.
+func reconcileServiceAccount(ctx context.Context, c k8s.Client, repository snapshotv1alpha1.SnapshotRepository) error {
+	identity := repository.Spec.WorkloadIdentity
+	if identity == nil {
+		return nil
+	}
+	expectedAnnotations := map[string]string{}
+	if identity.GCPServiceAccount != "" {
+		expectedAnnotations[GCPServiceAccountAnnotation] = identity.GCPServiceAccount
+	}
+
+	var serviceAccount corev1.ServiceAccount
+	err := c.Get(ctx, types.NamespacedName{Namespace: repository.Namespace, Name: identity.ServiceAccountName}, &serviceAccount)
+	if apierrors.IsNotFound(err) {
+		ulog.FromContext(ctx).Info("Creating service account", "namespace", repository.Namespace, "service_account", identity.ServiceAccountName)
+		return c.Create(ctx, &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   repository.Namespace,
+				Name:        identity.ServiceAccountName,
+				Annotations: expectedAnnotations,
+			},
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	updated := false
+	for k, v := range expectedAnnotations {
+		if serviceAccount.Annotations[k] == v {
+			continue
+		}
+		if serviceAccount.Annotations == nil {
+			serviceAccount.Annotations = map[string]string{}
+		}
+		serviceAccount.Annotations[k] = v
+		updated = true
+	}
+	if !updated {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Updating service account annotations", "namespace", repository.Namespace, "service_account", identity.ServiceAccountName)
+	return c.Update(ctx, &serviceAccount)
+}
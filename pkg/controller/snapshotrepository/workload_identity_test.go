@@ -0,0 +1,150 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	snapshotv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/snapshot/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func identityRepository(name, esName string, repositoryType snapshotv1alpha1.RepositoryType, identity *snapshotv1alpha1.WorkloadIdentity) *snapshotv1alpha1.SnapshotRepository {
+	return &snapshotv1alpha1.SnapshotRepository{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Spec: snapshotv1alpha1.SnapshotRepositorySpec{
+			ElasticsearchRefs: []commonv1.LocalObjectSelector{{Name: esName}},
+			Type:              repositoryType,
+			WorkloadIdentity:  identity,
+		},
+	}
+}
+
+func TestWorkloadIdentityFor(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+
+	identity, err := WorkloadIdentityFor(context.Background(), k8s.NewFakeClient(identityRepository("static-keys", "es", snapshotv1alpha1.S3RepositoryType, nil)), es)
+	require.NoError(t, err)
+	require.Nil(t, identity)
+
+	k8sClient := k8s.NewFakeClient(
+		identityRepository("b-s3", "es", snapshotv1alpha1.S3RepositoryType, &snapshotv1alpha1.WorkloadIdentity{ServiceAccountName: "snapshots", AWSRoleARN: "arn:aws:iam::123456789012:role/snapshots"}),
+		identityRepository("a-azure", "es", snapshotv1alpha1.AzureRepositoryType, &snapshotv1alpha1.WorkloadIdentity{ServiceAccountName: "snapshots", AzureClientID: "client", AzureTenantID: "tenant"}),
+		identityRepository("c-gcs", "es", snapshotv1alpha1.GCSRepositoryType, &snapshotv1alpha1.WorkloadIdentity{ServiceAccountName: "other", GCPServiceAccount: "snapshots@project.iam.gserviceaccount.com"}),
+		identityRepository("other-es", "other-es", snapshotv1alpha1.GCSRepositoryType, &snapshotv1alpha1.WorkloadIdentity{ServiceAccountName: "snapshots", GCPServiceAccount: "snapshots@project.iam.gserviceaccount.com"}),
+	)
+	identity, err = WorkloadIdentityFor(context.Background(), k8sClient, es)
+	require.NoError(t, err)
+	require.Equal(t, &snapshotv1alpha1.WorkloadIdentity{
+		ServiceAccountName: "snapshots",
+		AWSRoleARN:         "arn:aws:iam::123456789012:role/snapshots",
+		AzureClientID:      "client",
+		AzureTenantID:      "tenant",
+	}, identity)
+}
+
+func Test_checkWorkloadIdentity(t *testing.T) {
+	gcs := identityRepository("gcs", "es", snapshotv1alpha1.GCSRepositoryType, &snapshotv1alpha1.WorkloadIdentity{ServiceAccountName: "snapshots", GCPServiceAccount: "snapshots@project.iam.gserviceaccount.com"})
+	es := func(version, serviceAccountName string) esv1.Elasticsearch {
+		return esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+			Spec: esv1.ElasticsearchSpec{
+				Version: version,
+				NodeSets: []esv1.NodeSet{{
+					Name:        "default",
+					PodTemplate: corev1.PodTemplateSpec{Spec: corev1.PodSpec{ServiceAccountName: serviceAccountName}},
+				}},
+			},
+		}
+	}
+	tests := []struct {
+		name       string
+		repository *snapshotv1alpha1.SnapshotRepository
+		others     []*snapshotv1alpha1.SnapshotRepository
+		es         esv1.Elasticsearch
+		want       string
+	}{
+		{
+			name:       "no workload identity",
+			repository: identityRepository("s3", "es", snapshotv1alpha1.S3RepositoryType, nil),
+			es:         es("7.0.0", "custom"),
+		},
+		{
+			name:       "workload identity",
+			repository: gcs,
+			es:         es("8.15.0", ""),
+		},
+		{
+			name:       "same service account in the pod template",
+			repository: gcs,
+			es:         es("8.15.0", "snapshots"),
+		},
+		{
+			name:       "Elasticsearch version too old",
+			repository: identityRepository("s3", "es", snapshotv1alpha1.S3RepositoryType, &snapshotv1alpha1.WorkloadIdentity{ServiceAccountName: "snapshots", AWSRoleARN: "arn"}),
+			es:         es("8.0.0", ""),
+			want:       "Workload identity for s3 repositories requires Elasticsearch 8.1.0 or later",
+		},
+		{
+			name:       "other service account in the pod template",
+			repository: gcs,
+			es:         es("8.15.0", "custom"),
+			want:       "Elasticsearch es node set default runs with service account custom",
+		},
+		{
+			name:       "other service account of another repository",
+			repository: gcs,
+			others: []*snapshotv1alpha1.SnapshotRepository{
+				identityRepository("azure", "es", snapshotv1alpha1.AzureRepositoryType, &snapshotv1alpha1.WorkloadIdentity{ServiceAccountName: "azure-snapshots", AzureClientID: "client", AzureTenantID: "tenant"}),
+			},
+			es:   es("8.16.0", ""),
+			want: "Elasticsearch es runs with service account azure-snapshots of another SnapshotRepository",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := k8s.NewFakeClient(tt.repository)
+			for _, other := range tt.others {
+				require.NoError(t, k8sClient.Create(context.Background(), other))
+			}
+			got, err := checkWorkloadIdentity(context.Background(), k8sClient, *tt.repository, tt.es)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_reconcileServiceAccount(t *testing.T) {
+	repository := identityRepository("gcs", "es", snapshotv1alpha1.GCSRepositoryType, &snapshotv1alpha1.WorkloadIdentity{ServiceAccountName: "snapshots", GCPServiceAccount: "snapshots@project.iam.gserviceaccount.com"})
+	key := types.NamespacedName{Namespace: "ns", Name: "snapshots"}
+
+	// the service account is created
+	k8sClient := k8s.NewFakeClient()
+	require.NoError(t, reconcileServiceAccount(context.Background(), k8sClient, *repository))
+	var serviceAccount corev1.ServiceAccount
+	require.NoError(t, k8sClient.Get(context.Background(), key, &serviceAccount))
+	require.Equal(t, map[string]string{GCPServiceAccountAnnotation: "snapshots@project.iam.gserviceaccount.com"}, serviceAccount.Annotations)
+
+	// the annotation is added to an existing service account, other annotations are preserved
+	k8sClient = k8s.NewFakeClient(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snapshots", Annotations: map[string]string{"a": "b"}}})
+	require.NoError(t, reconcileServiceAccount(context.Background(), k8sClient, *repository))
+	require.NoError(t, k8sClient.Get(context.Background(), key, &serviceAccount))
+	require.Equal(t, map[string]string{"a": "b", GCPServiceAccountAnnotation: "snapshots@project.iam.gserviceaccount.com"}, serviceAccount.Annotations)
+
+	// nothing to do without workload identity
+	k8sClient = k8s.NewFakeClient()
+	require.NoError(t, reconcileServiceAccount(context.Background(), k8sClient, *identityRepository("s3", "es", snapshotv1alpha1.S3RepositoryType, nil)))
+	var serviceAccounts corev1.ServiceAccountList
+	require.NoError(t, k8sClient.List(context.Background(), &serviceAccounts))
+	require.Empty(t, serviceAccounts.Items)
+}
@@ -0,0 +1,119 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package transform
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	transformv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/transform/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	controllerName = "transform-controller"
+
+	// TransformFinalizer lets the operator delete the transform from Elasticsearch before the Transform resource is deleted.
+	TransformFinalizer = "transform.k8s.elastic.co/delete-transform"
+)
+
+// config identifies the Transform controller.
+var config = apiresource.Config{
+	ControllerName: controllerName,
+	KindName:       "Transform",
+	NameField:      "transform_name",
+	Finalizer:      TransformFinalizer,
+}
+
+// Add creates a new Transform Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return apiresource.Add(mgr, params, r,
+		// watch for changes to Elasticsearch and reconcile the Transform resources referencing them
+		source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestForTransforms(r.Client)),
+	)
+}
+
+// newReconciler returns a new reconcile.Reconciler of Transform.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *apiresource.Reconciler[*transformv1alpha1.Transform, transformv1alpha1.TransformStatus] {
+	c, recorder := mgr.GetClient(), mgr.GetEventRecorderFor(controllerName)
+	return apiresource.NewReconciler(c, recorder, params, config, &transformKind{
+		Client:           c,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         recorder,
+		params:           params,
+	})
+}
+
+// reconcileRequestForTransforms returns the requests to reconcile the Transform resources, in the namespace of the
+// watched Elasticsearch cluster, referencing it.
+func reconcileRequestForTransforms(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return apiresource.RequestsForMatching(clnt, &transformv1alpha1.TransformList{}, func(transform *transformv1alpha1.Transform, obj client.Object) bool {
+		return transform.References(k8s.ExtractNamespacedName(obj))
+	})
+}
+
+// transformKind configures Transform resources in Elasticsearch.
+type transformKind struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+}
+
+var (
+	_ apiresource.Kind[*transformv1alpha1.Transform, transformv1alpha1.TransformStatus] = &transformKind{}
+	_ apiresource.Remover[*transformv1alpha1.Transform]                                 = &transformKind{}
+)
+
+func (r *transformKind) NewObject() *transformv1alpha1.Transform {
+	return &transformv1alpha1.Transform{}
+}
+
+func (r *transformKind) GetStatus(transform *transformv1alpha1.Transform) transformv1alpha1.TransformStatus {
+	return transform.Status
+}
+
+func (r *transformKind) SetStatus(transform *transformv1alpha1.Transform, status transformv1alpha1.TransformStatus) {
+	transform.Status = status
+}
+
+func (r *transformKind) InvalidStatus(transform *transformv1alpha1.Transform, _ error) transformv1alpha1.TransformStatus {
+	return transformv1alpha1.TransformStatus{
+		Phase:              transformv1alpha1.InvalidPhase,
+		ObservedGeneration: transform.Generation,
+	}
+}
+
+// Configure configures the transform on the referenced Elasticsearch cluster.
+func (r *transformKind) Configure(ctx context.Context, obj *transformv1alpha1.Transform) (*reconciler.Results, transformv1alpha1.TransformStatus) {
+	transform := *obj
+	results := reconciler.NewResult(ctx)
+
+	status := r.reconcileTransform(ctx, transform)
+	status.TransformID = transform.TransformIDOrDefault()
+	status.ObservedGeneration = transform.Generation
+
+	results.WithResult(apiresource.Requeue(status.Phase == transformv1alpha1.ReadyPhase))
+
+	return results, status
+}
+
+// Remove deletes the transform from Elasticsearch.
+func (r *transformKind) Remove(ctx context.Context, obj *transformv1alpha1.Transform) (reconcile.Result, error) {
+	return reconcile.Result{}, r.deleteTransform(ctx, *obj)
+}
@@ -0,0 +1,335 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package transform
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	transformv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/transform/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// fakeEsClient stores transforms and their stats in memory, adding server-side fields to transforms like Elasticsearch.
+type fakeEsClient struct {
+	esclient.Client
+
+	transforms map[string]map[string]interface{}
+	stats      map[string]*esclient.TransformStats
+	updates    map[string]int
+}
+
+func notFound() error {
+	return &esclient.APIError{StatusCode: http.StatusNotFound}
+}
+
+func (c fakeEsClient) GetTransform(_ context.Context, id string) (map[string]interface{}, error) {
+	transform, exists := c.transforms[id]
+	if !exists {
+		return nil, notFound()
+	}
+	return transform, nil
+}
+
+func (c fakeEsClient) CreateTransform(_ context.Context, id string, transform map[string]interface{}) error {
+	stored := map[string]interface{}{"id": id, "version": "10.0.0", "create_time": 1760000000000, "settings": map[string]interface{}{}}
+	for k, v := range transform {
+		stored[k] = v
+	}
+	c.transforms[id] = stored
+	c.stats[id] = &esclient.TransformStats{ID: id, State: esclient.TransformStopped}
+	return nil
+}
+
+func (c fakeEsClient) UpdateTransform(_ context.Context, id string, transform map[string]interface{}) error {
+	stored, exists := c.transforms[id]
+	if !exists {
+		return notFound()
+	}
+	c.updates[id]++
+	for k, v := range transform {
+		if v == nil {
+			delete(stored, k)
+			continue
+		}
+		stored[k] = v
+	}
+	return nil
+}
+
+func (c fakeEsClient) DeleteTransform(_ context.Context, id string) error {
+	if _, exists := c.transforms[id]; !exists {
+		return notFound()
+	}
+	delete(c.transforms, id)
+	delete(c.stats, id)
+	return nil
+}
+
+func (c fakeEsClient) StartTransform(_ context.Context, id string) error {
+	c.stats[id].State = esclient.TransformStarted
+	return nil
+}
+
+func (c fakeEsClient) StopTransform(_ context.Context, id string, force bool) error {
+	if c.stats[id].State == esclient.TransformFailed && !force {
+		return &esclient.APIError{StatusCode: http.StatusConflict}
+	}
+	c.stats[id].State = esclient.TransformStopped
+	c.stats[id].Reason = ""
+	return nil
+}
+
+func (c fakeEsClient) GetTransformStats(_ context.Context, id string) (esclient.TransformStats, error) {
+	stats, exists := c.stats[id]
+	if !exists {
+		return esclient.TransformStats{}, notFound()
+	}
+	return *stats, nil
+}
+
+func (c fakeEsClient) Close() {}
+
+func fakeClientProvider(esClient esclient.Client) commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return esClient, nil
+	}
+}
+
+func ordersTransform(frequency string) map[string]interface{} {
+	return map[string]interface{}{
+		"source": map[string]interface{}{"index": []interface{}{"orders"}},
+		"dest":   map[string]interface{}{"index": "orders-by-customer"},
+		"pivot": map[string]interface{}{
+			"group_by":     map[string]interface{}{"customer_id": map[string]interface{}{"terms": map[string]interface{}{"field": "customer_id"}}},
+			"aggregations": map[string]interface{}{"total": map[string]interface{}{"sum": map[string]interface{}{"field": "price"}}},
+		},
+		"sync":      map[string]interface{}{"time": map[string]interface{}{"field": "order_date", "delay": "60s"}},
+		"frequency": frequency,
+	}
+}
+
+func TestReconcileTransform_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	transform := &transformv1alpha1.Transform{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "orders-by-customer", Generation: 1},
+		Spec: transformv1alpha1.TransformSpec{
+			ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es"},
+			Transform:        &commonv1.Config{Data: ordersTransform("1m")},
+		},
+	}
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	esClient := fakeEsClient{
+		transforms: map[string]map[string]interface{}{},
+		stats:      map[string]*esclient.TransformStats{},
+		updates:    map[string]int{},
+	}
+	k8sClient := k8s.NewFakeClient(transform, es)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &transformKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(esClient),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "orders-by-customer"}}
+	id := "orders-by-customer"
+	getTransform := func() transformv1alpha1.Transform {
+		var actual transformv1alpha1.Transform
+		require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+		return actual
+	}
+	updateSpec := func(update func(*transformv1alpha1.Transform)) {
+		actual := getTransform()
+		update(&actual)
+		actual.Generation++
+		require.NoError(t, k8sClient.Update(ctx, &actual))
+	}
+
+	// the transform is created and started
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DriftCheckRequeue, res)
+	actual := getTransform()
+	require.Equal(t, transformv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, id, actual.Status.TransformID)
+	require.Equal(t, []string{TransformFinalizer}, actual.Finalizers)
+	require.Equal(t, esclient.TransformStarted, actual.Status.State)
+	require.Equal(t, "1m", esClient.transforms[id]["frequency"])
+	require.Equal(t, esclient.TransformStarted, esClient.stats[id].State)
+
+	// the checkpoint progress is reported, the transform returned by Elasticsearch with its server-side fields is not updated
+	esClient.stats[id].State = esclient.TransformIndexing
+	esClient.stats[id].Checkpointing.Last = esclient.TransformCheckpoint{Checkpoint: 3, TimestampMillis: 1760000000123}
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	actual = getTransform()
+	require.Equal(t, transformv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, esclient.TransformIndexing, actual.Status.State)
+	require.Equal(t, int64(3), actual.Status.Checkpoint)
+	require.Equal(t, int64(1760000000), actual.Status.LastCheckpointTime.Unix())
+	require.Equal(t, 0, esClient.updates[id])
+	require.Empty(t, recorder.Events)
+
+	// the transform is restored and restarted if it is modified and stopped in Elasticsearch
+	esClient.transforms[id]["frequency"] = "1h"
+	esClient.stats[id].State = esclient.TransformStopped
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "1m", esClient.transforms[id]["frequency"])
+	require.Equal(t, esclient.TransformStarted, esClient.stats[id].State)
+	require.NotNil(t, getTransform().Status.LastDriftTime)
+	require.Contains(t, <-recorder.Events, "Transform orders-by-customer was modified in Elasticsearch es")
+
+	// a failed transform is reported with the failure reason
+	esClient.stats[id].State = esclient.TransformFailed
+	esClient.stats[id].Reason = "task encountered irrecoverable failure: no such index [orders]"
+	res, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	actual = getTransform()
+	require.Equal(t, transformv1alpha1.ErrorPhase, actual.Status.Phase)
+	require.Equal(t, esclient.TransformFailed, actual.Status.State)
+	require.Equal(t, "task encountered irrecoverable failure: no such index [orders]", actual.Status.FailureReason)
+	require.Contains(t, <-recorder.Events, "Transform health degraded")
+
+	// a failed transform is restarted when its specification changes, which is not a drift
+	updateSpec(func(tr *transformv1alpha1.Transform) {
+		tr.Spec.Transform = &commonv1.Config{Data: ordersTransform("5m")}
+	})
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	actual = getTransform()
+	require.Equal(t, transformv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Empty(t, actual.Status.FailureReason)
+	require.Equal(t, "5m", esClient.transforms[id]["frequency"])
+	require.Equal(t, esclient.TransformStarted, esClient.stats[id].State)
+	require.Equal(t, 2, esClient.updates[id])
+	require.Empty(t, recorder.Events)
+
+	// the transform is stopped, then reported stopped
+	updateSpec(func(tr *transformv1alpha1.Transform) { tr.Spec.State = transformv1alpha1.StoppedState })
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	actual = getTransform()
+	require.Equal(t, transformv1alpha1.ApplyingChangesPhase, actual.Status.Phase)
+	require.Equal(t, esclient.TransformStopping, actual.Status.State)
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	actual = getTransform()
+	require.Equal(t, transformv1alpha1.ReadyPhase, actual.Status.Phase)
+	require.Equal(t, esclient.TransformStopped, actual.Status.State)
+
+	// the transform is recreated when its pivot changes
+	updateSpec(func(tr *transformv1alpha1.Transform) {
+		definition := ordersTransform("5m")
+		delete(definition, "pivot")
+		definition["latest"] = map[string]interface{}{"unique_key": []interface{}{"customer_id"}, "sort": "order_date"}
+		tr.Spec.Transform = &commonv1.Config{Data: definition}
+	})
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.NotContains(t, esClient.transforms[id], "pivot")
+	require.Contains(t, esClient.transforms[id], "latest")
+	require.Equal(t, esclient.TransformStopped, esClient.stats[id].State)
+	require.Equal(t, transformv1alpha1.ReadyPhase, getTransform().Status.Phase)
+
+	// the transform is deleted from Elasticsearch with the Transform
+	actual = getTransform()
+	require.NoError(t, k8sClient.Delete(ctx, &actual))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Empty(t, esClient.transforms)
+	err = k8sClient.Get(ctx, request.NamespacedName, &transformv1alpha1.Transform{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileTransform_CreationError(t *testing.T) {
+	ctx := context.Background()
+	transform := &transformv1alpha1.Transform{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "orders-by-customer"},
+		Spec: transformv1alpha1.TransformSpec{
+			ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es"},
+			Transform:        &commonv1.Config{Data: ordersTransform("1m")},
+		},
+	}
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Status:     esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase},
+	}
+	k8sClient := k8s.NewFakeClient(transform, es)
+	recorder := record.NewFakeRecorder(10)
+	r := apiresource.NewReconciler(k8sClient, recorder, operator.Parameters{}, config, &transformKind{
+		Client:           k8sClient,
+		esClientProvider: fakeClientProvider(failingCreationClient{}),
+		recorder:         recorder,
+	})
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "orders-by-customer"}}
+
+	res, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, apiresource.DefaultRequeue, res)
+	var actual transformv1alpha1.Transform
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &actual))
+	require.Equal(t, transformv1alpha1.ErrorPhase, actual.Status.Phase)
+	require.Equal(t, "Failed to configure transform orders-by-customer on Elasticsearch es: source index [orders] does not exist", actual.Status.Message)
+}
+
+// failingCreationClient fails to create transforms as Elasticsearch does when their source index does not exist.
+type failingCreationClient struct {
+	esclient.Client
+}
+
+func (c failingCreationClient) GetTransform(_ context.Context, _ string) (map[string]interface{}, error) {
+	return nil, notFound()
+}
+
+func (c failingCreationClient) CreateTransform(_ context.Context, _ string, _ map[string]interface{}) error {
+	apiErr := &esclient.APIError{StatusCode: http.StatusBadRequest}
+	apiErr.ErrorResponse.Error.Reason = "source index [orders] does not exist"
+	return apiErr
+}
+
+func (c failingCreationClient) Close() {}
+
+func Test_reconcileDefinition_RetentionPolicy(t *testing.T) {
+	esClient := fakeEsClient{
+		transforms: map[string]map[string]interface{}{},
+		stats:      map[string]*esclient.TransformStats{},
+		updates:    map[string]int{},
+	}
+	definition := ordersTransform("1m")
+	definition["retention_policy"] = map[string]interface{}{"time": map[string]interface{}{"field": "order_date", "max_age": "30d"}}
+	changed, err := reconcileDefinition(context.Background(), esClient, "orders", definition)
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	// the retention policy removed from the specification is removed from the transform
+	changed, err = reconcileDefinition(context.Background(), esClient, "orders", ordersTransform("1m"))
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.NotContains(t, esClient.transforms["orders"], "retention_policy")
+
+	changed, err = reconcileDefinition(context.Background(), esClient, "orders", ordersTransform("1m"))
+	require.NoError(t, err)
+	require.False(t, changed)
+}
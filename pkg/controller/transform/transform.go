@@ -0,0 +1,276 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	transformv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/transform/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/apiresource"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+var (
+	// updatableFields are the fields of a transform accepted by the Elasticsearch update transform API. A transform
+	// must be recreated to change its other fields.
+	updatableFields = []string{"description", "dest", "frequency", "_meta", "retention_policy", "settings", "source", "sync"}
+
+	// recreatedFields are the fields of a transform that cannot be updated.
+	recreatedFields = []string{"pivot", "latest"}
+)
+
+// reconcileTransform creates, updates, starts or stops the transform in Elasticsearch to match the specification, and
+// returns the status of the Transform.
+func (r *transformKind) reconcileTransform(ctx context.Context, transform transformv1alpha1.Transform) transformv1alpha1.TransformStatus {
+	defer tracing.Span(&ctx)()
+	esName := transform.Spec.ElasticsearchRef.Name
+	id := transform.TransformIDOrDefault()
+	log := ulog.FromContext(ctx).WithValues("es_name", esName, "transform_id", id)
+
+	// keep track of the progress of the transform until it can be read again
+	status := transformv1alpha1.TransformStatus{
+		Phase:              transformv1alpha1.ReadyPhase,
+		State:              transform.Status.State,
+		Checkpoint:         transform.Status.Checkpoint,
+		LastCheckpointTime: transform.Status.LastCheckpointTime,
+		FailureReason:      transform.Status.FailureReason,
+		LastDriftTime:      transform.Status.LastDriftTime,
+	}
+
+	es, phase, msg := apiresource.ReadyElasticsearch(ctx, r.Client, types.NamespacedName{Namespace: transform.Namespace, Name: esName})
+	if phase != transformv1alpha1.ReadyPhase {
+		return withPhase(status, phase, msg)
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return withPhase(status, transformv1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	defer esClient.Close()
+
+	// the transform was configured from the current specification, any difference was made outside of the operator
+	specChanged := transform.Status.ObservedGeneration != transform.Generation
+	configured := transform.Status.Phase == transformv1alpha1.ReadyPhase && !specChanged
+
+	changed, err := reconcileDefinition(ctx, esClient, id, transform.Spec.Transform.Data)
+	if err != nil {
+		if esclient.IsUnavailable(err) {
+			return withPhase(status, transformv1alpha1.ApplyingChangesPhase, err.Error())
+		}
+		msg := fmt.Sprintf("Failed to configure transform %s on Elasticsearch %s: %s", id, esName, esclient.ErrorReason(err))
+		r.recorder.Event(&transform, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		return withPhase(status, transformv1alpha1.ErrorPhase, msg)
+	}
+	drifted := configured && changed
+
+	stats, err := esClient.GetTransformStats(ctx, id)
+	if err != nil {
+		return withPhase(status, transformv1alpha1.ApplyingChangesPhase, err.Error())
+	}
+	status.State = stats.State
+	status.Checkpoint = stats.Checkpointing.Last.Checkpoint
+	status.LastCheckpointTime = nil
+	if millis := stats.Checkpointing.Last.TimestampMillis; millis > 0 {
+		lastCheckpointTime := metav1.NewTime(time.UnixMilli(millis).Truncate(time.Second))
+		status.LastCheckpointTime = &lastCheckpointTime
+	}
+	status.FailureReason = ""
+	if stats.State == esclient.TransformFailed {
+		status.FailureReason = stats.Reason
+	}
+
+	switch {
+	case stats.State == esclient.TransformStopping || stats.State == esclient.TransformAborting:
+		// wait for the transform to stop before starting it or reporting it stopped
+		status = withPhase(status, transformv1alpha1.ApplyingChangesPhase, fmt.Sprintf("Waiting for transform %s to stop", id))
+	case stats.State == esclient.TransformFailed && transform.StateOrDefault() == transformv1alpha1.StartedState && !specChanged:
+		// a failed transform is restarted once its specification is fixed
+		status = withPhase(status, transformv1alpha1.ErrorPhase, fmt.Sprintf("Transform %s failed: %s", id, stats.Reason))
+	case stats.State == esclient.TransformFailed:
+		log.Info("Stopping failed transform", "reason", stats.Reason)
+		if err := esClient.StopTransform(ctx, id, true); err != nil {
+			return withPhase(status, transformv1alpha1.ApplyingChangesPhase, err.Error())
+		}
+		status.State = esclient.TransformStopped
+		status.FailureReason = ""
+		status = r.reconcileState(ctx, esClient, transform, status)
+	default:
+		drifted = drifted || (configured && !inExpectedState(transform, stats.State))
+		status = r.reconcileState(ctx, esClient, transform, status)
+	}
+
+	if drifted {
+		msg := fmt.Sprintf("Transform %s was modified in Elasticsearch %s, restoring it from the Transform specification", id, esName)
+		status.LastDriftTime = apiresource.RecordDrift(log, r.recorder, &transform, msg)
+	}
+	return status
+}
+
+// reconcileDefinition creates the transform if it does not exist, recreates it if its pivot or latest configuration
+// changed, or updates it if its other fields changed. It returns true if the transform was changed.
+func reconcileDefinition(ctx context.Context, esClient esclient.Client, id string, expected map[string]interface{}) (bool, error) {
+	log := ulog.FromContext(ctx)
+	actual, err := esClient.GetTransform(ctx, id)
+	if esclient.IsNotFound(err) {
+		log.Info("Creating transform")
+		return true, esClient.CreateTransform(ctx, id, expected)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, key := range recreatedFields {
+		if !contains(expected[key], actual[key]) {
+			// the destination index is kept, the new transform starts from its first checkpoint
+			log.Info("Recreating transform", "field", key)
+			if err := esClient.DeleteTransform(ctx, id); err != nil {
+				return false, err
+			}
+			return true, esClient.CreateTransform(ctx, id, expected)
+		}
+	}
+
+	update := map[string]interface{}{}
+	for _, key := range updatableFields {
+		if value, exists := expected[key]; exists {
+			update[key] = value
+		}
+	}
+	// a retention policy is removed by setting it to null
+	_, expectedRetention := expected["retention_policy"]
+	_, actualRetention := actual["retention_policy"]
+	removeRetention := !expectedRetention && actualRetention
+	if contains(update, actual) && !removeRetention {
+		return false, nil
+	}
+	if removeRetention {
+		update["retention_policy"] = nil
+	}
+	log.Info("Updating transform")
+	return true, esClient.UpdateTransform(ctx, id, update)
+}
+
+// reconcileState starts or stops the transform according to the specification.
+func (r *transformKind) reconcileState(
+	ctx context.Context,
+	esClient esclient.Client,
+	transform transformv1alpha1.Transform,
+	status transformv1alpha1.TransformStatus,
+) transformv1alpha1.TransformStatus {
+	id := transform.TransformIDOrDefault()
+	log := ulog.FromContext(ctx)
+	switch {
+	case inExpectedState(transform, status.State):
+		return status
+	case transform.StateOrDefault() == transformv1alpha1.StartedState:
+		log.Info("Starting transform")
+		if err := esClient.StartTransform(ctx, id); err != nil {
+			msg := fmt.Sprintf("Failed to start transform %s on Elasticsearch %s: %s", id, transform.Spec.ElasticsearchRef.Name, esclient.ErrorReason(err))
+			r.recorder.Event(&transform, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+			return withPhase(status, transformv1alpha1.ErrorPhase, msg)
+		}
+		status.State = esclient.TransformStarted
+		return status
+	default:
+		log.Info("Stopping transform")
+		if err := esClient.StopTransform(ctx, id, false); err != nil {
+			return withPhase(status, transformv1alpha1.ApplyingChangesPhase, err.Error())
+		}
+		// the transform stops at the end of its current checkpoint
+		status.State = esclient.TransformStopping
+		return withPhase(status, transformv1alpha1.ApplyingChangesPhase, fmt.Sprintf("Waiting for transform %s to stop", id))
+	}
+}
+
+// inExpectedState returns true if the given state of the transform in Elasticsearch matches the specification.
+func inExpectedState(transform transformv1alpha1.Transform, state string) bool {
+	if transform.StateOrDefault() == transformv1alpha1.StoppedState {
+		return state == esclient.TransformStopped
+	}
+	return state == esclient.TransformStarted || state == esclient.TransformIndexing
+}
+
+// deleteTransform deletes the transform from the referenced Elasticsearch cluster. Its destination index is kept.
+func (r *transformKind) deleteTransform(ctx context.Context, transform transformv1alpha1.Transform) error {
+	defer tracing.Span(&ctx)()
+
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: transform.Namespace, Name: transform.Spec.ElasticsearchRef.Name}, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+
+	ulog.FromContext(ctx).Info("Deleting transform", "es_name", es.Name, "transform_id", transform.TransformIDOrDefault())
+	if err := esClient.DeleteTransform(ctx, transform.TransformIDOrDefault()); err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// contains returns true if the expected value is a subset of the actual value returned by Elasticsearch, which adds
+// defaults and server-side fields such as the version or the creation time to transforms.
+func contains(expected, actual interface{}) bool {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualMap, ok := actual.(map[string]interface{})
+		if !ok {
+			return len(expectedValue) == 0 && actual == nil
+		}
+		for k, v := range expectedValue {
+			if !contains(v, actualMap[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		actualSlice, ok := actual.([]interface{})
+		if !ok || len(actualSlice) != len(expectedValue) {
+			return len(expectedValue) == 0 && actual == nil
+		}
+		for i := range expectedValue {
+			if !contains(expectedValue[i], actualSlice[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		// compare the JSON representations of the values to ignore the differences of numeric types
+		expectedBytes, err := json.Marshal(expected)
+		if err != nil {
+			return false
+		}
+		actualBytes, err := json.Marshal(actual)
+		if err != nil {
+			return false
+		}
+		return string(expectedBytes) == string(actualBytes)
+	}
+}
+
+func withPhase(status transformv1alpha1.TransformStatus, phase transformv1alpha1.Phase, msg string) transformv1alpha1.TransformStatus {
+	status.Phase = phase
+	status.Message = msg
+	return status
+}
@@ -27,6 +27,7 @@ import (
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
 	mapsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
 	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/namespaces"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/monitoring"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
@@ -60,15 +61,10 @@ func NewReporter(
 	info about.OperatorInfo,
 	client client.Client,
 	operatorNamespace string,
-	managedNamespaces []string,
+	managedNamespaces namespaces.Managed,
 	telemetryInterval time.Duration,
 	tracer *apm.Tracer,
 ) Reporter {
-	if len(managedNamespaces) == 0 {
-		// treat no managed namespaces as managing all namespaces, ie. set empty string for namespace filtering
-		managedNamespaces = append(managedNamespaces, "")
-	}
-
 	return Reporter{
 		operatorInfo:      info,
 		client:            client,
@@ -83,7 +79,7 @@ type Reporter struct {
 	operatorInfo      about.OperatorInfo
 	client            k8s.Client
 	operatorNamespace string
-	managedNamespaces []string
+	managedNamespaces namespaces.Managed
 	telemetryInterval time.Duration
 	tracer            *apm.Tracer
 }
@@ -109,6 +105,17 @@ func marshalTelemetry(ctx context.Context, info about.OperatorInfo, stats map[st
 	})
 }
 
+// namespaces returns the namespaces to report on, which may change over time when namespaces are managed based on
+// a label selector.
+func (r *Reporter) namespaces() []string {
+	managedNamespaces := r.managedNamespaces.Namespaces()
+	if len(managedNamespaces) == 0 {
+		// treat no managed namespaces as managing all namespaces, ie. set empty string for namespace filtering
+		managedNamespaces = append(managedNamespaces, "")
+	}
+	return managedNamespaces
+}
+
 func (r *Reporter) getResourceStats(ctx context.Context) (map[string]interface{}, error) {
 	span, _ := apm.StartSpan(ctx, "get_resource_stats", tracing.SpanTypeApp)
 	defer span.End()
@@ -125,7 +132,7 @@ func (r *Reporter) getResourceStats(ctx context.Context) (map[string]interface{}
 		scpStats,
 		logstashStats,
 	} {
-		key, statsPart, err := f(r.client, r.managedNamespaces)
+		key, statsPart, err := f(r.client, r.namespaces())
 		if err != nil {
 			return nil, err
 		}
@@ -159,7 +166,7 @@ func (r *Reporter) report(ctx context.Context) {
 		return
 	}
 
-	for _, ns := range r.managedNamespaces {
+	for _, ns := range r.namespaces() {
 		var kibanaList kbv1.KibanaList
 		if err := r.client.List(ctx, &kibanaList, client.InNamespace(ns)); err != nil {
 			log.Error(err, "failed to list Kibanas")
@@ -27,6 +27,7 @@ import (
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
 	mapsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
 	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/namespaces"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
@@ -430,7 +431,7 @@ func TestNewReporter(t *testing.T) {
 	)
 
 	// We only want the reporter to handle the managed namespaces, in this test only ns1 and ns2 are managed.
-	r := NewReporter(testOperatorInfo, client, "elastic-system", []string{kb1.Namespace, kb2.Namespace}, 1*time.Hour, nil)
+	r := NewReporter(testOperatorInfo, client, "elastic-system", namespaces.Static{kb1.Namespace, kb2.Namespace}, 1*time.Hour, nil)
 	r.report(context.Background())
 
 	wantData := map[string][]byte{
@@ -723,7 +724,7 @@ func TestReporter_report(t *testing.T) {
 				operatorInfo:      testOperatorInfo,
 				client:            client,
 				operatorNamespace: "elastic-system",
-				managedNamespaces: []string{testNS},
+				managedNamespaces: namespaces.Static{testNS},
 				telemetryInterval: 1 * time.Hour,
 			}
 			r.report(context.Background())
@@ -0,0 +1,17 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package cryptutil
+
+// FIPSEnabled returns true if the operator is built with FIPS validated cryptography (the goexperiment.boringcrypto
+// build tag). In that case TLS is restricted to FIPS approved settings, and password hashes are generated with PBKDF2.
+func FIPSEnabled() bool {
+	return fipsBuild
+}
+
+// BoringCryptoEnabled returns true if the cryptographic primitives are provided by the BoringCrypto module at runtime,
+// which requires the operator to be built with both the boringcrypto Go experiment and cgo.
+func BoringCryptoEnabled() bool {
+	return boringCryptoEnabled()
+}
@@ -0,0 +1,19 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+//go:build goexperiment.boringcrypto
+
+package cryptutil
+
+import (
+	"crypto/boring"
+	// restricts all TLS connections of the operator to FIPS approved versions, cipher suites and curves
+	_ "crypto/tls/fipsonly"
+)
+
+const fipsBuild = true
+
+func boringCryptoEnabled() bool {
+	return boring.Enabled()
+}
@@ -11,7 +11,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-// NewPasswordHasher returns a bcrypt hash generator.
+// NewPasswordHasher returns a bcrypt hash generator, or a PBKDF2 hash generator if FIPS validated cryptography is used.
 // If size is greater than 0 the hashes are cached using a cache of the provided size.
 func NewPasswordHasher(size int) (PasswordHasher, error) {
 	if size > 0 {
@@ -19,15 +19,32 @@ func NewPasswordHasher(size int) (PasswordHasher, error) {
 		if err != nil {
 			return nil, err
 		}
+		generate, compare, cost := hashAlgorithm()
 		return &lruHashCache{
-			generateFromPassword:   bcrypt.GenerateFromPassword,
-			compareHashAndPassword: bcrypt.CompareHashAndPassword,
+			generateFromPassword:   generate,
+			compareHashAndPassword: compare,
+			cost:                   cost,
 			hashCache:              lruCache,
 		}, nil
 	}
 	return &passwordHashProvider{}, nil
 }
 
+// ReuseOrGenerateHash returns the existing hash if it matches the password, or a new hash of the password otherwise.
+func ReuseOrGenerateHash(password, existingHash []byte) ([]byte, error) {
+	return (&passwordHashProvider{}).ReuseOrGenerateHash(password, existingHash)
+}
+
+// hashAlgorithm returns the functions generating and comparing password hashes, with the cost to use. Hashes are
+// generated with PBKDF2 if FIPS validated cryptography is used, as bcrypt is not a FIPS approved algorithm. Existing
+// bcrypt hashes are then replaced.
+func hashAlgorithm() (generateFromPassword, compareHashAndPassword, int) {
+	if FIPSEnabled() {
+		return generatePBKDF2StretchHash, comparePBKDF2StretchHash, pbkdf2DefaultCost
+	}
+	return bcrypt.GenerateFromPassword, bcrypt.CompareHashAndPassword, bcrypt.DefaultCost
+}
+
 type PasswordHasher interface {
 	ReuseOrGenerateHash(password, existingHash []byte) ([]byte, error)
 }
@@ -36,6 +53,7 @@ type generateFromPassword func(password []byte, cost int) ([]byte, error)
 type compareHashAndPassword func(hashedPassword, password []byte) error
 type lruHashCache struct {
 	hashCache *lru.Cache[string, []byte]
+	cost      int
 
 	// only to be changed for unit tests
 	generateFromPassword
@@ -61,7 +79,7 @@ func (h *lruHashCache) ReuseOrGenerateHash(password, existingHash []byte) ([]byt
 	}
 
 	// No existing hash or existing hash is not valid
-	hash, err := h.generateFromPassword(password, bcrypt.DefaultCost)
+	hash, err := h.generateFromPassword(password, h.cost)
 	if err != nil {
 		return nil, err
 	}
@@ -80,8 +98,9 @@ func (h *lruHashCache) get(key string) []byte {
 type passwordHashProvider struct{}
 
 func (n *passwordHashProvider) ReuseOrGenerateHash(password, existingHash []byte) ([]byte, error) {
-	if len(existingHash) > 0 && bcrypt.CompareHashAndPassword(existingHash, password) == nil {
+	generate, compare, cost := hashAlgorithm()
+	if len(existingHash) > 0 && compare(existingHash, password) == nil {
 		return existingHash, nil
 	}
-	return bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
+	return generate(password, cost)
 }
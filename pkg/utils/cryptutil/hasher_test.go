@@ -12,6 +12,9 @@ import (
 )
 
 func Test_lruHashCache_ReuseOrGenerateHash(t *testing.T) {
+	if FIPSEnabled() {
+		t.Skip("bcrypt is not used with FIPS validated cryptography")
+	}
 	newHasher, err := NewPasswordHasher(2)
 	assert.NoError(t, err)
 	passwordHasher, ok := newHasher.(*lruHashCache)
@@ -71,6 +74,9 @@ func Test_lruHashCache_ReuseOrGenerateHash(t *testing.T) {
 }
 
 func Test_passwordHashProvider_ReuseOrGenerateHash(t *testing.T) {
+	if FIPSEnabled() {
+		t.Skip("bcrypt is not used with FIPS validated cryptography")
+	}
 	passwordHasher, err := NewPasswordHasher(0)
 	assert.NoError(t, err)
 	_, ok := passwordHasher.(*passwordHashProvider)
@@ -86,3 +92,26 @@ func Test_passwordHashProvider_ReuseOrGenerateHash(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, storedHash, string(hash2))
 }
+
+func Test_passwordHashProvider_ReuseOrGenerateHash_FIPS(t *testing.T) {
+	if !FIPSEnabled() {
+		t.Skip("requires FIPS validated cryptography")
+	}
+	passwordHasher, err := NewPasswordHasher(0)
+	assert.NoError(t, err)
+
+	hash1, err := passwordHasher.ReuseOrGenerateHash([]byte("password1"), nil)
+	assert.NoError(t, err)
+	assert.NoError(t, comparePBKDF2StretchHash(hash1, []byte("password1")))
+
+	// valid PBKDF2 hashes are reused
+	hash2, err := passwordHasher.ReuseOrGenerateHash([]byte("password1"), hash1)
+	assert.NoError(t, err)
+	assert.Equal(t, hash1, hash2)
+
+	// bcrypt hashes are replaced
+	storedHash := "$2a$10$aAeQF8kG.AOrsp4mtzFQ4.1z5lvL0w8odQl2tvaxGdKkQTyHMOSEe"
+	hash3, err := passwordHasher.ReuseOrGenerateHash([]byte("password2"), []byte(storedHash))
+	assert.NoError(t, err)
+	assert.NoError(t, comparePBKDF2StretchHash(hash3, []byte("password2")))
+}
@@ -0,0 +1,75 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package cryptutil
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Elasticsearch pbkdf2_stretch password hashes parameters.
+const (
+	pbkdf2StretchPrefix = "{PBKDF2_STRETCH}"
+	pbkdf2DefaultCost   = 10000
+	pbkdf2KeyLength     = 32
+	pbkdf2SaltLength    = 32
+)
+
+var errPBKDF2HashMismatch = errors.New("hash does not match password")
+
+// generatePBKDF2StretchHash generates a hash of the password in the pbkdf2_stretch format of Elasticsearch:
+// {PBKDF2_STRETCH}<cost>$<base64 salt>$<base64 key>, the key being derived from the SHA-512 hash of the password.
+func generatePBKDF2StretchHash(password []byte, cost int) ([]byte, error) {
+	salt := make([]byte, pbkdf2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	var hash bytes.Buffer
+	hash.WriteString(pbkdf2StretchPrefix)
+	hash.WriteString(strconv.Itoa(cost))
+	hash.WriteString("$")
+	hash.WriteString(base64.StdEncoding.EncodeToString(salt))
+	hash.WriteString("$")
+	hash.WriteString(base64.StdEncoding.EncodeToString(pbkdf2StretchKey(password, salt, cost)))
+	return hash.Bytes(), nil
+}
+
+// comparePBKDF2StretchHash returns nil if the hash in the pbkdf2_stretch format matches the password.
+func comparePBKDF2StretchHash(hash, password []byte) error {
+	parts := strings.Split(strings.TrimPrefix(string(hash), pbkdf2StretchPrefix), "$")
+	if !bytes.HasPrefix(hash, []byte(pbkdf2StretchPrefix)) || len(parts) != 3 {
+		return errPBKDF2HashMismatch
+	}
+	cost, err := strconv.Atoi(parts[0])
+	if err != nil || cost <= 0 {
+		return errPBKDF2HashMismatch
+	}
+	salt, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return errPBKDF2HashMismatch
+	}
+	key, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errPBKDF2HashMismatch
+	}
+	if subtle.ConstantTimeCompare(key, pbkdf2StretchKey(password, salt, cost)) != 1 {
+		return errPBKDF2HashMismatch
+	}
+	return nil
+}
+
+func pbkdf2StretchKey(password, salt []byte, cost int) []byte {
+	hashedPassword := sha512.Sum512(password)
+	return pbkdf2.Key([]byte(hex.EncodeToString(hashedPassword[:])), salt, cost, pbkdf2KeyLength, sha512.New)
+}
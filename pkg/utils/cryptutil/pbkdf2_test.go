@@ -0,0 +1,47 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package cryptutil
+
+import (
+	"encoding/base64"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pbkdf2StretchHashRegexp = regexp.MustCompile(`^\{PBKDF2_STRETCH\}10000\$(.*)\$(.*)$`)
+
+func Test_generatePBKDF2StretchHash(t *testing.T) {
+	hash, err := generatePBKDF2StretchHash([]byte("password"), pbkdf2DefaultCost)
+	require.NoError(t, err)
+
+	parts := pbkdf2StretchHashRegexp.FindStringSubmatch(string(hash))
+	require.Len(t, parts, 3, "hash %s does not match %s", hash, pbkdf2StretchHashRegexp)
+	salt, err := base64.StdEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	assert.Len(t, salt, pbkdf2SaltLength)
+	key, err := base64.StdEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	assert.Len(t, key, pbkdf2KeyLength)
+
+	// salts are random
+	other, err := generatePBKDF2StretchHash([]byte("password"), pbkdf2DefaultCost)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+}
+
+func Test_comparePBKDF2StretchHash(t *testing.T) {
+	hash, err := generatePBKDF2StretchHash([]byte("password"), 1000)
+	require.NoError(t, err)
+
+	assert.NoError(t, comparePBKDF2StretchHash(hash, []byte("password")))
+	assert.Error(t, comparePBKDF2StretchHash(hash, []byte("other")))
+	// bcrypt hashes are not PBKDF2 hashes
+	assert.Error(t, comparePBKDF2StretchHash([]byte("$2a$10$aAeQF8kG.AOrsp4mtzFQ4.1z5lvL0w8odQl2tvaxGdKkQTyHMOSEe"), []byte("password2")))
+	assert.Error(t, comparePBKDF2StretchHash([]byte("{PBKDF2_STRETCH}1000$invalid"), []byte("password")))
+	assert.Error(t, comparePBKDF2StretchHash([]byte("{PBKDF2_STRETCH}x$c2FsdA==$a2V5"), []byte("password")))
+}
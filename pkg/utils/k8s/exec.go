@@ -0,0 +1,69 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// PodExecutor runs commands in the containers of running Pods.
+type PodExecutor interface {
+	// Exec runs the command in the given container of the Pod, streaming stdin to it if not nil.
+	// It returns an error including the standard error output of the command if the command fails.
+	Exec(ctx context.Context, pod corev1.Pod, container string, command []string, stdin io.Reader) error
+}
+
+// NewPodExecutor returns a PodExecutor relying on the exec subresource of the Pods of the Kubernetes API server
+// reached with the given configuration.
+func NewPodExecutor(cfg *rest.Config) PodExecutor {
+	return &podExecutor{cfg: cfg}
+}
+
+type podExecutor struct {
+	cfg *rest.Config
+}
+
+var _ PodExecutor = &podExecutor{}
+
+func (e *podExecutor) Exec(ctx context.Context, pod corev1.Pod, container string, command []string, stdin io.Reader) error {
+	client, err := corev1client.NewForConfig(e.cfg)
+	if err != nil {
+		return err
+	}
+	req := client.RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(e.cfg, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: io.Discard,
+		Stderr: &stderr,
+	}); err != nil {
+		return fmt.Errorf("while running command in container %s of Pod %s/%s: %w: %s", container, pod.Namespace, pod.Name, err, stderr.String())
+	}
+	return nil
+}
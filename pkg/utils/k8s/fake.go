@@ -6,24 +6,67 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 // NewFakeClient creates a new fake Kubernetes client.
+// Server-side apply patches, not supported by the fake client, are approximated by a creation or a JSON merge patch.
 func NewFakeClient(initObjs ...client.Object) Client {
+	return NewFakeClientWithScheme(clientgoscheme.Scheme, initObjs...)
+}
+
+// NewFakeClientWithScheme creates a new fake Kubernetes client aware of the types of the given scheme.
+func NewFakeClientWithScheme(scheme *runtime.Scheme, initObjs ...client.Object) Client {
 	return fake.NewClientBuilder().
 		WithObjects(initObjs...).
 		WithStatusSubresource(initObjs...).
-		WithScheme(clientgoscheme.Scheme).
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{Patch: applyAsMergePatch}).
 		Build()
 }
 
+func applyAsMergePatch(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Patch(ctx, obj, patch, opts...)
+	}
+	existing, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	if apierrors.IsNotFound(err) {
+		return c.Create(ctx, obj)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	// like an apply patch, leave the status untouched
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	delete(fields, "status")
+	if data, err = json.Marshal(fields); err != nil {
+		return err
+	}
+	return c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, data))
+}
+
 var (
 	_ Client              = failingClient{}
 	_ client.StatusWriter = failingSubClient{}
@@ -0,0 +1,100 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+// LabeledResourcesSelector selects the resources created by the operator, which all carry the type label.
+func LabeledResourcesSelector() labels.Selector {
+	requirement, err := labels.NewRequirement(commonv1.TypeLabelName, selection.Exists, nil)
+	if err != nil {
+		// cannot happen with a valid constant label name
+		panic(err)
+	}
+	return labels.NewSelector().Add(*requirement)
+}
+
+// isLabelFiltered returns true for the kinds of resources which are only cached when labeled by the operator, if
+// enabled. These are the kinds of which there can be a huge number unrelated to the operator in a cluster.
+func isLabelFiltered(obj client.Object) bool {
+	switch obj.(type) {
+	case *corev1.Secret, *corev1.ConfigMap, *corev1.Pod, *corev1.Service:
+		return true
+	default:
+		return false
+	}
+}
+
+// CacheLabeledResourcesOnly restricts the cache to the Secrets, ConfigMaps, Pods and Services created by the operator,
+// to reduce its memory usage. Resources in the operator namespace, such as the enterprise licenses, are all cached.
+// It must be called after the cache default namespaces are set.
+func CacheLabeledResourcesOnly(opts *cache.Options, operatorNamespace string) {
+	selector := LabeledResourcesSelector()
+	namespaces := map[string]cache.Config{}
+	for ns := range opts.DefaultNamespaces {
+		namespaces[ns] = cache.Config{LabelSelector: selector}
+	}
+	if len(namespaces) == 0 {
+		namespaces[cache.AllNamespaces] = cache.Config{LabelSelector: selector}
+	}
+	namespaces[operatorNamespace] = cache.Config{LabelSelector: labels.Everything()}
+
+	if opts.ByObject == nil {
+		opts.ByObject = map[client.Object]cache.ByObject{}
+	}
+	for _, obj := range []client.Object{&corev1.Secret{}, &corev1.ConfigMap{}, &corev1.Pod{}, &corev1.Service{}} {
+		byObject := cache.ByObject{Namespaces: map[string]cache.Config{}}
+		for ns, config := range namespaces {
+			byObject.Namespaces[ns] = config
+		}
+		opts.ByObject[obj] = byObject
+	}
+}
+
+// NewFallbackClientFunc returns a function creating a client which reads Secrets, ConfigMaps, Pods and Services from
+// the API server when they are not found in the cache, to be used along with CacheLabeledResourcesOnly so that
+// resources provided by users, such as custom certificates, can still be read.
+func NewFallbackClientFunc() client.NewClientFunc {
+	return func(config *rest.Config, options client.Options) (client.Client, error) {
+		cached, err := client.New(config, options)
+		if err != nil {
+			return nil, err
+		}
+		uncachedOptions := options
+		uncachedOptions.Cache = nil
+		uncached, err := client.New(config, uncachedOptions)
+		if err != nil {
+			return nil, err
+		}
+		return &fallbackClient{Client: cached, apiReader: uncached}, nil
+	}
+}
+
+// fallbackClient is a client reading the label filtered resources from the API server if they are not in the cache.
+type fallbackClient struct {
+	client.Client
+	apiReader client.Reader
+}
+
+// Get implements client.Reader.
+func (c *fallbackClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	err := c.Client.Get(ctx, key, obj, opts...)
+	if apierrors.IsNotFound(err) && isLabelFiltered(obj) {
+		return c.apiReader.Get(ctx, key, obj, opts...)
+	}
+	return err
+}
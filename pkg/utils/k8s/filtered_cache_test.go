@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func TestLabeledResourcesSelector(t *testing.T) {
+	selector := LabeledResourcesSelector()
+	assert.True(t, selector.Matches(labels.Set{commonv1.TypeLabelName: "elasticsearch"}))
+	assert.False(t, selector.Matches(labels.Set{"app": "elasticsearch"}))
+}
+
+func TestCacheLabeledResourcesOnly(t *testing.T) {
+	// all namespaces
+	opts := cache.Options{}
+	CacheLabeledResourcesOnly(&opts, "elastic-system")
+	require.Len(t, opts.ByObject, 4)
+	var secrets cache.ByObject
+	for obj, byObject := range opts.ByObject {
+		if _, isSecret := obj.(*corev1.Secret); isSecret {
+			secrets = byObject
+		}
+	}
+	assert.Equal(t, labels.Everything(), secrets.Namespaces["elastic-system"].LabelSelector)
+	assert.Equal(t, LabeledResourcesSelector(), secrets.Namespaces[cache.AllNamespaces].LabelSelector)
+
+	// specific namespaces, ByObject must not watch other namespaces
+	opts = cache.Options{DefaultNamespaces: map[string]cache.Config{"ns1": {}, "elastic-system": {}}}
+	CacheLabeledResourcesOnly(&opts, "elastic-system")
+	for _, byObject := range opts.ByObject {
+		assert.Len(t, byObject.Namespaces, 2)
+		assert.Equal(t, LabeledResourcesSelector(), byObject.Namespaces["ns1"].LabelSelector)
+		assert.Equal(t, labels.Everything(), byObject.Namespaces["elastic-system"].LabelSelector)
+	}
+}
+
+func Test_fallbackClient_Get(t *testing.T) {
+	userSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "user-certs"}}
+	es := &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	c := &fallbackClient{
+		Client:    NewFakeClient(),
+		apiReader: NewFakeClient(userSecret, es),
+	}
+	// filtered kinds missing from the cache are read from the API server
+	var secret corev1.Secret
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "user-certs"}, &secret))
+	assert.Equal(t, "user-certs", secret.Name)
+	// other kinds are only read from the cache
+	err := c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "es"}, &esv1.Elasticsearch{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
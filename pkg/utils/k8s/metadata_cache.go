@@ -0,0 +1,179 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MetadataOnlyObjects returns the kinds of resources of which only the metadata is cached when metadata-only watches
+// are enabled. These are the kinds of which there can be thousands of resources in a cluster.
+func MetadataOnlyObjects() []client.Object {
+	return []client.Object{&corev1.Pod{}, &corev1.Secret{}}
+}
+
+// ReadMetadataOnlyObjectsUncached makes the client read the MetadataOnlyObjects from the API server rather than from
+// the cache, which would otherwise start informers caching the full objects.
+func ReadMetadataOnlyObjectsUncached(opts *client.Options) {
+	if opts.Cache == nil {
+		opts.Cache = &client.CacheOptions{}
+	}
+	opts.Cache.DisableFor = append(opts.Cache.DisableFor, MetadataOnlyObjects()...)
+}
+
+// isMetadataOnlyKind returns true if the given kind, or kind of list, is one of the MetadataOnlyObjects.
+func isMetadataOnlyKind(gvk schema.GroupVersionKind) bool {
+	if gvk.GroupVersion() != corev1.SchemeGroupVersion {
+		return false
+	}
+	switch strings.TrimSuffix(gvk.Kind, "List") {
+	case "Pod", "Secret":
+		return true
+	default:
+		return false
+	}
+}
+
+// NewMetadataList returns an empty list of the metadata of the objects of the given kind, for example Secret.
+func NewMetadataList(gvk schema.GroupVersionKind) *metav1.PartialObjectMetadataList {
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	return list
+}
+
+// NewMetadataCachedClientFunc returns a function creating clients with newClient, or with client.New if nil, which
+// read the metadata of the MetadataOnlyObjects from the cache, through metav1.PartialObjectMetadata and
+// metav1.PartialObjectMetadataList. If metadataOnly is true, only their metadata is cached and the client reads the
+// full objects from the API server, see ReadMetadataOnlyObjectsUncached. Otherwise, their metadata is taken from the
+// full objects in the cache, rather than from a second informer caching their metadata.
+// Reading only the metadata of these objects is therefore always served by the cache, whichever objects are cached.
+func NewMetadataCachedClientFunc(newClient client.NewClientFunc, metadataOnly bool) client.NewClientFunc {
+	if newClient == nil {
+		newClient = client.New
+	}
+	return func(config *rest.Config, options client.Options) (client.Client, error) {
+		c, err := newClient(config, options)
+		if err != nil {
+			return nil, err
+		}
+		if options.Cache == nil || options.Cache.Reader == nil {
+			return c, nil
+		}
+		return &metadataCachedClient{Client: c, cache: options.Cache.Reader, metadataOnly: metadataOnly}, nil
+	}
+}
+
+// metadataCachedClient reads the metadata of the MetadataOnlyObjects from the cache.
+type metadataCachedClient struct {
+	client.Client
+	cache        client.Reader
+	metadataOnly bool
+}
+
+// Get implements client.Reader.
+func (c *metadataCachedClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	metadata, ok := obj.(*metav1.PartialObjectMetadata)
+	if !ok || !isMetadataOnlyKind(metadata.GroupVersionKind()) {
+		return c.Client.Get(ctx, key, obj, opts...)
+	}
+	if c.metadataOnly {
+		err := c.cache.Get(ctx, key, obj, opts...)
+		if apierrors.IsNotFound(err) {
+			// the object may not be cached if the cache is restricted to the resources created by the operator
+			return c.Client.Get(ctx, key, obj, opts...)
+		}
+		return err
+	}
+	full, err := c.newObject(metadata.GroupVersionKind())
+	if err != nil {
+		return err
+	}
+	if err := c.Client.Get(ctx, key, full, opts...); err != nil {
+		return err
+	}
+	return toMetadata(full, metadata)
+}
+
+// List implements client.Reader.
+func (c *metadataCachedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	metadataList, ok := list.(*metav1.PartialObjectMetadataList)
+	if !ok || !isMetadataOnlyKind(metadataList.GroupVersionKind()) {
+		return c.Client.List(ctx, list, opts...)
+	}
+	if c.metadataOnly {
+		return c.cache.List(ctx, list, opts...)
+	}
+	gvk := metadataList.GroupVersionKind()
+	if !strings.HasSuffix(gvk.Kind, "List") {
+		gvk.Kind += "List"
+	}
+	obj, err := c.Scheme().New(gvk)
+	if err != nil {
+		return err
+	}
+	typedList, ok := obj.(client.ObjectList)
+	if !ok {
+		return fmt.Errorf("unexpected list of type %T for %s", obj, gvk)
+	}
+	// only the metadata is copied out of the cache
+	if err := c.Client.List(ctx, typedList, append(opts, client.UnsafeDisableDeepCopy)...); err != nil {
+		return err
+	}
+	objects, err := meta.ExtractList(typedList)
+	if err != nil {
+		return err
+	}
+	metadataList.ResourceVersion = typedList.GetResourceVersion()
+	metadataList.Continue = typedList.GetContinue()
+	metadataList.Items = make([]metav1.PartialObjectMetadata, len(objects))
+	itemGVK := schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: strings.TrimSuffix(gvk.Kind, "List")}
+	for i, obj := range objects {
+		clientObj, ok := obj.(client.Object)
+		if !ok {
+			return fmt.Errorf("unexpected object of type %T in %s", obj, gvk)
+		}
+		metadataList.Items[i].SetGroupVersionKind(itemGVK)
+		if err := toMetadata(clientObj, &metadataList.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *metadataCachedClient) newObject(gvk schema.GroupVersionKind) (client.Object, error) {
+	obj, err := c.Scheme().New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	clientObj, ok := obj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object of type %T for %s", obj, gvk)
+	}
+	return clientObj, nil
+}
+
+// toMetadata copies the metadata of the given object into the given metadata.
+func toMetadata(obj client.Object, metadata *metav1.PartialObjectMetadata) error {
+	accessor, ok := obj.(metav1.ObjectMetaAccessor)
+	if !ok {
+		return fmt.Errorf("unexpected object of type %T", obj)
+	}
+	objectMeta, ok := accessor.GetObjectMeta().(*metav1.ObjectMeta)
+	if !ok {
+		return fmt.Errorf("unexpected metadata of type %T", accessor.GetObjectMeta())
+	}
+	objectMeta.DeepCopyInto(&metadata.ObjectMeta)
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReadMetadataOnlyObjectsUncached(t *testing.T) {
+	opts := client.Options{}
+	ReadMetadataOnlyObjectsUncached(&opts)
+	require.Equal(t, []client.Object{&corev1.Pod{}, &corev1.Secret{}}, opts.Cache.DisableFor)
+
+	// other objects read uncached are kept
+	opts = client.Options{Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.ConfigMap{}}}}
+	ReadMetadataOnlyObjectsUncached(&opts)
+	require.Equal(t, []client.Object{&corev1.ConfigMap{}, &corev1.Pod{}, &corev1.Secret{}}, opts.Cache.DisableFor)
+}
+
+func TestMetadataCachedClient(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod", Labels: map[string]string{"a": "b"}}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "secret"}, Data: map[string][]byte{"k": []byte("v")}}
+	podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+	secretGVK := corev1.SchemeGroupVersion.WithKind("Secret")
+
+	t.Run("metadata taken from the full objects", func(t *testing.T) {
+		c := &metadataCachedClient{Client: NewFakeClient(pod, secret), cache: NewFakeClient(), metadataOnly: false}
+		pods := NewMetadataList(podGVK)
+		require.NoError(t, c.List(context.Background(), pods, client.MatchingLabels{"a": "b"}))
+		require.Len(t, pods.Items, 1)
+		require.Equal(t, "pod", pods.Items[0].Name)
+		require.Equal(t, podGVK, pods.Items[0].GroupVersionKind())
+
+		metadata := &metav1.PartialObjectMetadata{}
+		metadata.SetGroupVersionKind(secretGVK)
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "secret"}, metadata))
+		require.Equal(t, "secret", metadata.Name)
+	})
+
+	t.Run("metadata read from the cache", func(t *testing.T) {
+		// the Pod is only in the cache, the Secret is not cached
+		c := &metadataCachedClient{Client: NewFakeClient(secret), cache: NewFakeClient(pod), metadataOnly: true}
+		pods := NewMetadataList(podGVK)
+		require.NoError(t, c.List(context.Background(), pods))
+		require.Len(t, pods.Items, 1)
+		secrets := NewMetadataList(secretGVK)
+		require.NoError(t, c.List(context.Background(), secrets))
+		require.Empty(t, secrets.Items)
+
+		// objects not found in the cache are read from the API server
+		metadata := &metav1.PartialObjectMetadata{}
+		metadata.SetGroupVersionKind(secretGVK)
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "secret"}, metadata))
+		require.Equal(t, "secret", metadata.Name)
+	})
+
+	t.Run("full objects", func(t *testing.T) {
+		c := &metadataCachedClient{Client: NewFakeClient(secret), cache: NewFakeClient(), metadataOnly: true}
+		var secrets corev1.SecretList
+		require.NoError(t, c.List(context.Background(), &secrets))
+		require.Len(t, secrets.Items, 1)
+		require.Equal(t, secret.Data, secrets.Items[0].Data)
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	crzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	AuditLoggerName = "audit"
+
+	// AuditLogStdout is the special audit log destination used to write audit entries to the standard output.
+	AuditLogStdout = "stdout"
+)
+
+// NewAuditLogger returns a logger writing ECS formatted JSON entries to the given writer, independently of the
+// verbosity of the global operator logger. It is meant to produce structured audit streams.
+func NewAuditLogger(w io.Writer) logr.Logger {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	return crzap.New(func(o *crzap.Options) {
+		o.DestWriter = w
+		o.Level = &level
+		o.Encoder = zapcore.NewJSONEncoder(ecsEncoderConfig())
+		o.ZapOpts = []zap.Option{zap.Fields(
+			zap.String("service.version", getVersionString()),
+			zap.String("service.type", EcsServiceType),
+			zap.String("ecs.version", EcsVersion),
+		)}
+	}).WithName(AuditLoggerName)
+}
+
+// OpenAuditLog opens the given audit log destination for writing. Destination is either AuditLogStdout or
+// the path to a file, in which case entries are appended to the file.
+func OpenAuditLog(destination string) (io.WriteCloser, error) {
+	if destination == AuditLogStdout {
+		return nopCloser{Writer: os.Stdout}, nil
+	}
+	f, err := os.OpenFile(destination, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("while opening audit log %s: %w", destination, err)
+	}
+	return f, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
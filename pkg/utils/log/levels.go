@@ -0,0 +1,105 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package log
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// levels holds the verbosity of the global logger. Once loggers have been derived from the global logger it cannot
+// be swapped anymore, levels are therefore updated in place to change the verbosity at runtime.
+var levels = &levelEnabler{overrides: map[string]zapcore.Level{}}
+
+// levelEnabler enables log entries according to a global level, which can be overridden for specific loggers.
+type levelEnabler struct {
+	mutex     sync.RWMutex
+	global    zapcore.Level
+	overrides map[string]zapcore.Level
+	// lowest is the lowest level across the global level and the overrides
+	lowest zapcore.Level
+}
+
+// setGlobal replaces the global level.
+func (l *levelEnabler) setGlobal(global zapcore.Level) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.global = global
+	l.updateLowest()
+}
+
+// setOverrides replaces the per logger levels.
+func (l *levelEnabler) setOverrides(overrides map[string]zapcore.Level) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.overrides = overrides
+	l.updateLowest()
+}
+
+func (l *levelEnabler) updateLowest() {
+	l.lowest = l.global
+	for _, level := range l.overrides {
+		if level < l.lowest {
+			l.lowest = level
+		}
+	}
+}
+
+// Enabled implements zapcore.LevelEnabler. It returns true if the given level may be enabled for at least one logger.
+func (l *levelEnabler) Enabled(level zapcore.Level) bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return level >= l.lowest
+}
+
+// enabledFor returns true if the given level is enabled for the logger with the given name. The most specific override
+// applies: an override for "elasticsearch-controller" applies to the "elasticsearch-controller.driver" logger as well.
+func (l *levelEnabler) enabledFor(loggerName string, level zapcore.Level) bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	effective := l.global
+	matched := -1
+	for name, override := range l.overrides {
+		if (loggerName == name || strings.HasPrefix(loggerName, name+".")) && len(name) > matched {
+			effective = override
+			matched = len(name)
+		}
+	}
+	return level >= effective
+}
+
+// leveledCore filters entries of the underlying core according to the level of the logger which produced them.
+type leveledCore struct {
+	zapcore.Core
+	levels *levelEnabler
+}
+
+func (c *leveledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &leveledCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *leveledCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.enabledFor(entry.LoggerName, entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// ParseVerbosityOverrides parses per logger verbosity levels, given as logger names mapped to verbosity levels.
+func ParseVerbosityOverrides(overrides map[string]string) (map[string]int, error) {
+	parsed := make(map[string]int, len(overrides))
+	for name, value := range overrides {
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid verbosity %q for logger %s: %w", value, name, err)
+		}
+		parsed[name] = v
+	}
+	return parsed, nil
+}
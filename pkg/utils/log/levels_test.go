@@ -0,0 +1,66 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLeveledCore(t *testing.T) {
+	l := &levelEnabler{}
+	core, logs := observer.New(l)
+	logger := zap.New(&leveledCore{Core: core, levels: l})
+	es := logger.Named("elasticsearch-controller")
+	driver := es.Named("driver")
+	kb := logger.Named("kibana-controller")
+
+	logAll := func() []string {
+		for _, lg := range []*zap.Logger{logger, es, driver, kb} {
+			lg.Debug("debug")
+			lg.Info("info")
+		}
+		var entries []string
+		for _, e := range logs.TakeAll() {
+			entries = append(entries, e.LoggerName+":"+e.Message)
+		}
+		return entries
+	}
+
+	l.setGlobal(zapcore.InfoLevel)
+	assert.Equal(t, []string{":info", "elasticsearch-controller:info", "elasticsearch-controller.driver:info", "kibana-controller:info"}, logAll())
+
+	// debug logs for the elasticsearch controller and its descendants only
+	l.setOverrides(map[string]zapcore.Level{"elasticsearch-controller": zapcore.DebugLevel})
+	assert.Equal(t, []string{
+		":info",
+		"elasticsearch-controller:debug", "elasticsearch-controller:info",
+		"elasticsearch-controller.driver:debug", "elasticsearch-controller.driver:info",
+		"kibana-controller:info",
+	}, logAll())
+
+	// the most specific override applies
+	l.setOverrides(map[string]zapcore.Level{"elasticsearch-controller": zapcore.DebugLevel, "elasticsearch-controller.driver": zapcore.ErrorLevel})
+	assert.Equal(t, []string{":info", "elasticsearch-controller:debug", "elasticsearch-controller:info", "kibana-controller:info"}, logAll())
+
+	// global level change and removal of the overrides
+	l.setGlobal(zapcore.DebugLevel)
+	l.setOverrides(nil)
+	assert.Len(t, logAll(), 8)
+}
+
+func TestParseVerbosityOverrides(t *testing.T) {
+	overrides, err := ParseVerbosityOverrides(map[string]string{"elasticsearch-controller": "1", "license": "-1"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"elasticsearch-controller": 1, "license": -1}, overrides)
+
+	_, err = ParseVerbosityOverrides(map[string]string{"license": "debug"})
+	require.Error(t, err)
+}
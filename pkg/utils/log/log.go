@@ -28,6 +28,8 @@ const (
 	EcsVersion     = "1.4.0"
 	EcsServiceType = "eck"
 	FlagName       = "log-verbosity"
+	// OverridesFlagName is the name of the flag used to set the verbosity of specific loggers.
+	OverridesFlagName = "log-verbosity-overrides"
 
 	testLogLevelEnvVar = "ECK_TEST_LOG_LEVEL"
 )
@@ -48,6 +50,11 @@ var verbosity = flag.Int(FlagName, 0, "Verbosity level of logs (-2=Error, -1=War
 // BindFlags attaches logging flags to the given flag set.
 func BindFlags(flags *pflag.FlagSet) {
 	flags.AddGoFlag(flag.Lookup("log-verbosity"))
+	flags.StringToString(
+		OverridesFlagName,
+		nil,
+		"Verbosity level of logs per logger name, taking precedence over log-verbosity (eg. elasticsearch-controller=1,license=-1)",
+	)
 }
 
 // InitLogger initializes the global logger informed by the value of log-verbosity flag.
@@ -68,22 +75,51 @@ func ChangeVerbosity(v int) {
 	setLogger(&v)
 }
 
-func setLogger(v *int) {
-	zapLevel := determineLogLevel(v)
+// SetVerbosity changes the verbosity level of the global logger in place, without replacing it.
+// Unlike ChangeVerbosity, it is safe to call once loggers have been derived from the global logger.
+func SetVerbosity(v int) {
+	zapLevel := determineLogLevel(&v)
+	setKlogLevel(zapLevel.Level())
+	levels.setGlobal(zapLevel.Level())
+}
+
+// SetVerbosityOverrides sets the verbosity level of the loggers with the given names, and of their descendants,
+// in place of the global verbosity level. Overrides previously set and absent from the given ones are removed.
+func SetVerbosityOverrides(overrides map[string]int) {
+	zapLevels := make(map[string]zapcore.Level, len(overrides))
+	for name, v := range overrides {
+		zapLevels[name] = determineLogLevel(&v).Level()
+	}
+	levels.setOverrides(zapLevels)
+}
 
-	// if the Zap custom level is less than debug (verbosity level 2 and above) set the klog level to the same level
-	if zapLevel.Level() < zap.DebugLevel {
-		flagset := flag.NewFlagSet("", flag.ContinueOnError)
-		klog.InitFlags(flagset)
-		_ = flagset.Set("v", strconv.Itoa(int(zapLevel.Level())*-1))
+// setKlogLevel sets the klog level to the given Zap level if it is a custom level less than debug
+// (verbosity level 2 and above), or to 0 otherwise, so that lowering the verbosity also lowers the klog level.
+func setKlogLevel(level zapcore.Level) {
+	klogLevel := 0
+	if level < zap.DebugLevel {
+		klogLevel = int(level) * -1
 	}
+	flagset := flag.NewFlagSet("", flag.ContinueOnError)
+	klog.InitFlags(flagset)
+	_ = flagset.Set("v", strconv.Itoa(klogLevel))
+}
+
+func setLogger(v *int) {
+	zapLevel := determineLogLevel(v)
+	setKlogLevel(zapLevel.Level())
+	levels.setGlobal(zapLevel.Level())
 
 	// send error logs to APM
 	tracing := zap.WrapCore((&apmzap.Core{}).WrapCore)
+	// filter log entries according to the verbosity of the logger that produced them
+	leveled := zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &leveledCore{Core: core, levels: levels}
+	})
 
 	opts := []zap.Option{zap.Fields(
 		zap.String("service.version", getVersionString()),
-	), tracing}
+	), tracing, leveled}
 
 	var encoder zapcore.Encoder
 	if dev.Enabled {
@@ -91,14 +127,7 @@ func setLogger(v *int) {
 		encoderConf.EncodeLevel = zapcore.CapitalColorLevelEncoder
 		encoder = zapcore.NewConsoleEncoder(encoderConf)
 	} else {
-		encoderConf := zap.NewProductionEncoderConfig()
-		encoderConf.MessageKey = "message"
-		encoderConf.TimeKey = "@timestamp"
-		encoderConf.LevelKey = "log.level"
-		encoderConf.NameKey = "log.logger"
-		encoderConf.StacktraceKey = "error.stack_trace"
-		encoderConf.EncodeTime = zapcore.ISO8601TimeEncoder
-		encoder = zapcore.NewJSONEncoder(encoderConf)
+		encoder = zapcore.NewJSONEncoder(ecsEncoderConfig())
 		opts = append(opts,
 			zap.Fields(
 				zap.String("service.type", EcsServiceType),
@@ -110,13 +139,25 @@ func setLogger(v *int) {
 	crlog.SetLogger(crzap.New(func(o *crzap.Options) {
 		o.DestWriter = os.Stderr
 		o.Development = dev.Enabled
-		o.Level = &zapLevel
+		o.Level = levels
 		o.StacktraceLevel = &stackTraceLevel
 		o.Encoder = encoder
 		o.ZapOpts = opts
 	}))
 }
 
+// ecsEncoderConfig returns the encoder configuration used to produce ECS compatible JSON logs.
+func ecsEncoderConfig() zapcore.EncoderConfig {
+	encoderConf := zap.NewProductionEncoderConfig()
+	encoderConf.MessageKey = "message"
+	encoderConf.TimeKey = "@timestamp"
+	encoderConf.LevelKey = "log.level"
+	encoderConf.NameKey = "log.logger"
+	encoderConf.StacktraceKey = "error.stack_trace"
+	encoderConf.EncodeTime = zapcore.ISO8601TimeEncoder
+	return encoderConf
+}
+
 func determineLogLevel(v *int) zap.AtomicLevel {
 	switch {
 	case v != nil && *v > -3:
@@ -0,0 +1,26 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/klog/v2"
+)
+
+func TestSetVerbosity_Klog(t *testing.T) {
+	defer SetVerbosity(0)
+
+	// verbosity levels from 2 are passed to klog
+	SetVerbosity(3)
+	assert.True(t, bool(klog.V(3).Enabled()))
+	assert.False(t, bool(klog.V(4).Enabled()))
+
+	// lowering the verbosity to debug or less resets the klog level
+	SetVerbosity(1)
+	assert.False(t, bool(klog.V(1).Enabled()))
+	assert.True(t, bool(klog.V(0).Enabled()))
+}
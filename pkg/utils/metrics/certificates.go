@@ -0,0 +1,108 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	certificatesSubsystem = "certificates"
+
+	SecretLabel = "secret"
+	KeyLabel    = "key"
+
+	// caCertKey and certKeySuffix identify the CA and the certificates in the Secrets managed by the operator,
+	// including the per Pod transport certificates.
+	caCertKey     = "ca.crt"
+	certKeySuffix = "tls.crt"
+)
+
+// CertificateExpiryGauge reports the expiry time of the certificates held in the Secrets managed by the operator.
+var CertificateExpiryGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Subsystem: certificatesSubsystem,
+	Name:      "expiry_timestamp_seconds",
+	Help:      "Unix time at which the certificates held in the Secrets managed by the operator expire, by Secret and key",
+}, []string{NamespaceLabel, SecretLabel, KeyLabel}))
+
+// secretCertificates are the expiry times of the certificates of a Secret, by key, at a given version of the Secret.
+type secretCertificates struct {
+	resourceVersion string
+	expiries        map[string]time.Time
+}
+
+// reportCertificates parses the certificates of the Secrets managed by the operator and updates their expiry gauge.
+// Only the metadata of the Secrets is listed, the Secrets updated since the last report are then read to parse their
+// certificates again, as reading them may not be served by the cache.
+func (r ResourcesReporter) reportCertificates(ctx context.Context) error {
+	secrets := k8s.NewMetadataList(corev1.SchemeGroupVersion.WithKind("Secret"))
+	if err := r.client.List(ctx, secrets, client.HasLabels{commonv1.TypeLabelName}); err != nil {
+		return err
+	}
+	current := make(map[types.NamespacedName]secretCertificates, len(secrets.Items))
+	for _, metadata := range secrets.Items {
+		nsn := types.NamespacedName{Namespace: metadata.Namespace, Name: metadata.Name}
+		certs, exists := r.certificates[nsn]
+		if !exists || certs.resourceVersion != metadata.ResourceVersion {
+			var secret corev1.Secret
+			if err := r.client.Get(ctx, nsn, &secret); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return err
+			}
+			certs = secretCertificates{resourceVersion: secret.ResourceVersion, expiries: certificateExpiries(secret)}
+		}
+		current[nsn] = certs
+	}
+	// forget the Secrets which do not exist anymore
+	clear(r.certificates)
+	maps.Copy(r.certificates, current)
+
+	// reset to drop the certificates which do not exist anymore
+	CertificateExpiryGauge.Reset()
+	for nsn, certs := range current {
+		for key, expiry := range certs.expiries {
+			CertificateExpiryGauge.WithLabelValues(nsn.Namespace, nsn.Name, key).Set(float64(expiry.Unix()))
+		}
+	}
+	return nil
+}
+
+// certificateExpiries returns the expiry time of the certificates held in the given Secret, by key.
+func certificateExpiries(secret corev1.Secret) map[string]time.Time {
+	expiries := map[string]time.Time{}
+	for key, data := range secret.Data {
+		if key != caCertKey && !strings.HasSuffix(key, certKeySuffix) {
+			continue
+		}
+		// only the first certificate of a chain, the one issued for the application, is reported
+		block, _ := pem.Decode(data)
+		if block == nil {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		expiries[key] = cert.NotAfter
+	}
+	return expiries
+}
@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	elasticsearchClientSubsystem = "elasticsearch_client"
+
+	NamespaceLabel = "namespace"
+	ESNameLabel    = "es_name"
+	EndpointLabel  = "endpoint"
+	MethodLabel    = "method"
+	CodeLabel      = "code"
+
+	// CodeTimeout is the code reported for requests which timed out before a response was received.
+	CodeTimeout = "timeout"
+	// CodeError is the code reported for requests which failed for another reason before a response was received.
+	CodeError = "error"
+)
+
+// ElasticsearchClientRequestDuration reports the duration of the requests made by the operator to the Elasticsearch
+// API, per cluster, endpoint group, HTTP method and response code.
+var ElasticsearchClientRequestDuration = register(prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Subsystem: elasticsearchClientSubsystem,
+	Name:      "request_duration_seconds",
+	Help:      "Duration in seconds of the requests made to the Elasticsearch API, by cluster, endpoint group, method and response code",
+	Buckets:   []float64{0.005, 0.025, 0.1, 0.25, 0.5, 1.0, 2.0, 4.0, 8.0, 15.0, 30.0, 60.0},
+}, []string{NamespaceLabel, ESNameLabel, EndpointLabel, MethodLabel, CodeLabel}))
+
+// ElasticsearchClientThrottledRequests reports the number of requests to the Elasticsearch API delayed by the client
+// rate limits, per cluster.
+var ElasticsearchClientThrottledRequests = register(prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: elasticsearchClientSubsystem,
+	Name:      "throttled_requests_total",
+	Help:      "Number of requests to the Elasticsearch API delayed by the client rate limits, by cluster",
+}, []string{NamespaceLabel, ESNameLabel}))
+
+// ElasticsearchClientThrottledSeconds reports the time spent by the requests to the Elasticsearch API waiting for the
+// client rate limits, per cluster.
+var ElasticsearchClientThrottledSeconds = register(prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: elasticsearchClientSubsystem,
+	Name:      "throttled_seconds_total",
+	Help:      "Time in seconds spent by the requests to the Elasticsearch API waiting for the client rate limits, by cluster",
+}, []string{NamespaceLabel, ESNameLabel}))
+
+// DeleteElasticsearchClientMetrics removes the Elasticsearch API metrics reported for the given cluster.
+func DeleteElasticsearchClientMetrics(es types.NamespacedName) {
+	labels := prometheus.Labels{NamespaceLabel: es.Namespace, ESNameLabel: es.Name}
+	ElasticsearchClientRequestDuration.DeletePartialMatch(labels)
+	ElasticsearchClientThrottledRequests.DeletePartialMatch(labels)
+	ElasticsearchClientThrottledSeconds.DeletePartialMatch(labels)
+}
@@ -0,0 +1,43 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	elasticsearchDiskSubsystem = "elasticsearch_disk"
+
+	NodeLabel      = "node"
+	WatermarkLabel = "watermark"
+)
+
+var (
+	// ElasticsearchDiskUsageRatio reports the ratio of the disk space used on each Elasticsearch node.
+	ElasticsearchDiskUsageRatio = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: elasticsearchDiskSubsystem,
+		Name:      "usage_ratio",
+		Help:      "Ratio of the disk space used on each Elasticsearch node, by cluster and node",
+	}, []string{NamespaceLabel, ESNameLabel, NodeLabel}))
+
+	// ElasticsearchDiskWatermarkExceeded reports whether the disk usage of each Elasticsearch node exceeds the high
+	// and flood stage disk watermarks.
+	ElasticsearchDiskWatermarkExceeded = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: elasticsearchDiskSubsystem,
+		Name:      "watermark_exceeded",
+		Help:      "Whether the disk usage of each Elasticsearch node exceeds a disk watermark (1) or not (0), by cluster, node and watermark",
+	}, []string{NamespaceLabel, ESNameLabel, NodeLabel, WatermarkLabel}))
+)
+
+// DeleteElasticsearchDiskMetrics removes the disk metrics reported for the given cluster.
+func DeleteElasticsearchDiskMetrics(es types.NamespacedName) {
+	labels := prometheus.Labels{NamespaceLabel: es.Namespace, ESNameLabel: es.Name}
+	ElasticsearchDiskUsageRatio.DeletePartialMatch(labels)
+	ElasticsearchDiskWatermarkExceeded.DeletePartialMatch(labels)
+}
@@ -0,0 +1,42 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	elasticsearchSnapshotSubsystem = "elasticsearch_snapshot"
+
+	PolicyLabel = "policy"
+)
+
+var (
+	// ElasticsearchSnapshotLastSuccessAge reports the age of the last snapshot successfully taken by each snapshot
+	// lifecycle management policy.
+	ElasticsearchSnapshotLastSuccessAge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: elasticsearchSnapshotSubsystem,
+		Name:      "last_success_age_seconds",
+		Help:      "Age in seconds of the last snapshot successfully taken by each snapshot lifecycle policy, by cluster and policy",
+	}, []string{NamespaceLabel, ESNameLabel, PolicyLabel}))
+
+	// ElasticsearchBackupHealthy reports whether the backups of each Elasticsearch cluster are healthy.
+	ElasticsearchBackupHealthy = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: elasticsearchSnapshotSubsystem,
+		Name:      "backup_healthy",
+		Help:      "Whether the snapshot lifecycle policies of each Elasticsearch cluster with a snapshot repository are running without failure (1) or not (0), by cluster",
+	}, []string{NamespaceLabel, ESNameLabel}))
+)
+
+// DeleteElasticsearchSnapshotMetrics removes the snapshot metrics reported for the given cluster.
+func DeleteElasticsearchSnapshotMetrics(es types.NamespacedName) {
+	labels := prometheus.Labels{NamespaceLabel: es.Namespace, ESNameLabel: es.Name}
+	ElasticsearchSnapshotLastSuccessAge.DeletePartialMatch(labels)
+	ElasticsearchBackupHealthy.DeletePartialMatch(labels)
+}
@@ -0,0 +1,102 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
+)
+
+const (
+	KindLabel   = "kind"
+	HealthLabel = "health"
+
+	// unknownHealth is reported for the resources which do not have a health in their status yet.
+	unknownHealth = "unknown"
+)
+
+// HealthGauge reports the health of each Elastic Stack application managed by the operator.
+var HealthGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Subsystem: resourcesSubsystem,
+	Name:      "health",
+	Help:      "Health of each Elastic Stack application, set to 1 for its current health, by kind and resource",
+}, []string{KindLabel, NamespaceLabel, NameLabel, HealthLabel}))
+
+// reportedHealth are the kinds of the resources for which the health is reported.
+var reportedHealth = map[string]func() client.ObjectList{
+	esv1.Kind:             func() client.ObjectList { return &esv1.ElasticsearchList{} },
+	kbv1.Kind:             func() client.ObjectList { return &kbv1.KibanaList{} },
+	apmv1.Kind:            func() client.ObjectList { return &apmv1.ApmServerList{} },
+	entv1.Kind:            func() client.ObjectList { return &entv1.EnterpriseSearchList{} },
+	beatv1beta1.Kind:      func() client.ObjectList { return &beatv1beta1.BeatList{} },
+	agentv1alpha1.Kind:    func() client.ObjectList { return &agentv1alpha1.AgentList{} },
+	emsv1alpha1.Kind:      func() client.ObjectList { return &emsv1alpha1.ElasticMapsServerList{} },
+	logstashv1alpha1.Kind: func() client.ObjectList { return &logstashv1alpha1.LogstashList{} },
+}
+
+// healthOf returns the health reported in the status of the given resource.
+func healthOf(obj runtime.Object) string {
+	var health string
+	switch o := obj.(type) {
+	case *esv1.Elasticsearch:
+		health = string(o.Status.Health)
+	case *kbv1.Kibana:
+		health = string(o.Status.Health)
+	case *apmv1.ApmServer:
+		health = string(o.Status.Health)
+	case *entv1.EnterpriseSearch:
+		health = string(o.Status.Health)
+	case *beatv1beta1.Beat:
+		health = string(o.Status.Health)
+	case *agentv1alpha1.Agent:
+		health = string(o.Status.Health)
+	case *emsv1alpha1.ElasticMapsServer:
+		health = string(o.Status.Health)
+	case *logstashv1alpha1.Logstash:
+		health = string(o.Status.Health)
+	}
+	if health == "" {
+		return unknownHealth
+	}
+	return health
+}
+
+// reportHealth lists the resources of each reported kind and updates their health gauge.
+func (r ResourcesReporter) reportHealth(ctx context.Context) error {
+	for kind, newList := range reportedHealth {
+		list := newList()
+		if err := r.client.List(ctx, list, client.UnsafeDisableDeepCopy); err != nil {
+			return err
+		}
+		objects, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		// reset to drop the resources which do not exist anymore and the previous health of the others
+		HealthGauge.DeletePartialMatch(prometheus.Labels{KindLabel: kind})
+		for _, obj := range objects {
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return err
+			}
+			HealthGauge.WithLabelValues(kind, accessor.GetNamespace(), accessor.GetName(), healthOf(obj)).Set(1)
+		}
+	}
+	return nil
+}
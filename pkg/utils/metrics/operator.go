@@ -23,14 +23,14 @@ const (
 )
 
 var (
-	Leader = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Leader = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: namespace,
 		Name:      LeaderKey,
 		Help:      "Gauge used to evaluate if an instance is elected",
 	}, []string{UUIDLabel, OperatorNamespaceLabel}))
 
 	// LicensingMaxERUGauge reports the maximum allowed enterprise resource units for licensing purposes.
-	LicensingMaxERUGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	LicensingMaxERUGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: licensingSubsystem,
 		Name:      "enterprise_resource_units_max",
@@ -38,7 +38,7 @@ var (
 	}, []string{LicenseLevelLabel}))
 
 	// LicensingTotalERUGauge reports the total enterprise resource units usage for licensing purposes.
-	LicensingTotalERUGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	LicensingTotalERUGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: licensingSubsystem,
 		Name:      "enterprise_resource_units_total",
@@ -46,7 +46,7 @@ var (
 	}, []string{LicenseLevelLabel}))
 
 	// LicensingTotalMemoryGauge reports the total memory usage for licensing purposes.
-	LicensingTotalMemoryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	LicensingTotalMemoryGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: licensingSubsystem,
 		Name:      "memory_gibibytes_total",
@@ -54,7 +54,7 @@ var (
 	}, []string{LicenseLevelLabel}))
 
 	// LicensingESMemoryGauge reports the Elasticsearch memory usage for licensing purposes.
-	LicensingESMemoryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	LicensingESMemoryGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: licensingSubsystem,
 		Name:      "memory_gibibytes_elasticsearch",
@@ -62,7 +62,7 @@ var (
 	}, []string{LicenseLevelLabel}))
 
 	// LicensingKBMemoryGauge reports the Kibana memory usage for licensing purposes.
-	LicensingKBMemoryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	LicensingKBMemoryGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: licensingSubsystem,
 		Name:      "memory_gibibytes_kibana",
@@ -70,7 +70,7 @@ var (
 	}, []string{LicenseLevelLabel}))
 
 	// LicensingAPMMemoryGauge reports the APM server memory usage for licensing purposes.
-	LicensingAPMMemoryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	LicensingAPMMemoryGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: licensingSubsystem,
 		Name:      "memory_gibibytes_apm",
@@ -78,7 +78,7 @@ var (
 	}, []string{LicenseLevelLabel}))
 
 	// LicensingEntSearchMemoryGauge reports the Enterprise Search memory usage for licensing purposes.
-	LicensingEntSearchMemoryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	LicensingEntSearchMemoryGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: licensingSubsystem,
 		Name:      "memory_gibibytes_enterprise_search",
@@ -86,7 +86,7 @@ var (
 	}, []string{LicenseLevelLabel}))
 
 	// LicensingLogstashMemoryGauge reports the Logstash memory usage for licensing purposes.
-	LicensingLogstashMemoryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	LicensingLogstashMemoryGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: licensingSubsystem,
 		Name:      "memory_gibibytes_logstash",
@@ -94,16 +94,18 @@ var (
 	}, []string{LicenseLevelLabel}))
 )
 
-func registerGauge(gauge *prometheus.GaugeVec) *prometheus.GaugeVec {
-	err := crmetrics.Registry.Register(gauge)
+// register registers the given collector with the controller-runtime registry, or returns the collector already
+// registered with the same descriptors.
+func register[T prometheus.Collector](collector T) T {
+	err := crmetrics.Registry.Register(collector)
 	if err != nil {
 		existsErr := new(prometheus.AlreadyRegisteredError)
 		if errors.As(err, &existsErr) {
-			return existsErr.ExistingCollector.(*prometheus.GaugeVec) //nolint:forcetypeassert
+			return existsErr.ExistingCollector.(T) //nolint:forcetypeassert
 		}
 
-		panic(fmt.Errorf("failed to register gauge: %w", err))
+		panic(fmt.Errorf("failed to register collector: %w", err))
 	}
 
-	return gauge
+	return collector
 }
@@ -0,0 +1,116 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	reconciliationSubsystem = "reconciliation"
+
+	ControllerLabel = "controller"
+	NameLabel       = "name"
+	ReasonLabel     = "reason"
+
+	// ReasonTimeout is the reason reported for reconciliations which failed because a deadline was exceeded.
+	ReasonTimeout = "Timeout"
+	// ReasonCanceled is the reason reported for reconciliations which were interrupted.
+	ReasonCanceled = "Canceled"
+	// ReasonUnknown is the reason reported for reconciliations which failed with an error which is not a Kubernetes
+	// API error.
+	ReasonUnknown = "Unknown"
+)
+
+var (
+	// ReconciliationLastDuration reports the duration of the last reconciliation of each resource.
+	ReconciliationLastDuration = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: reconciliationSubsystem,
+		Name:      "last_duration_seconds",
+		Help:      "Duration in seconds of the last reconciliation of each resource, by controller and resource",
+	}, []string{ControllerLabel, NamespaceLabel, NameLabel}))
+
+	// ReconciliationLastSuccess reports the time of the last reconciliation of each resource which did not return an
+	// error.
+	ReconciliationLastSuccess = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: reconciliationSubsystem,
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix time of the last reconciliation of each resource which did not return an error, by controller and resource",
+	}, []string{ControllerLabel, NamespaceLabel, NameLabel}))
+
+	// ReconciliationErrors counts the reconciliations which returned an error, per controller and reason.
+	ReconciliationErrors = register(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: reconciliationSubsystem,
+		Name:      "errors_total",
+		Help:      "Number of reconciliations which returned an error, by controller and reason",
+	}, []string{ControllerLabel, ReasonLabel}))
+)
+
+type reconciledResource struct {
+	controller string
+	resource   types.NamespacedName
+}
+
+// reconciledResources holds the time of the last reconciliation of each resource for which metrics are reported,
+// the metrics of the resources which are not reconciled anymore can then be removed.
+var reconciledResources = struct {
+	sync.Mutex
+	lastSeen map[reconciledResource]time.Time
+}{lastSeen: map[reconciledResource]time.Time{}}
+
+// ObserveReconciliation reports the outcome of a reconciliation of the given resource by the given controller.
+func ObserveReconciliation(controller string, resource types.NamespacedName, start time.Time, err error) {
+	now := time.Now()
+	ReconciliationLastDuration.WithLabelValues(controller, resource.Namespace, resource.Name).Set(now.Sub(start).Seconds())
+	if err != nil {
+		ReconciliationErrors.WithLabelValues(controller, ErrorReason(err)).Inc()
+	} else {
+		ReconciliationLastSuccess.WithLabelValues(controller, resource.Namespace, resource.Name).Set(float64(now.Unix()))
+	}
+	reconciledResources.Lock()
+	defer reconciledResources.Unlock()
+	reconciledResources.lastSeen[reconciledResource{controller: controller, resource: resource}] = now
+}
+
+// PruneReconciliationMetrics removes the metrics of the resources which were not reconciled for longer than the given
+// duration. All the resources are reconciled periodically, those are expected to have been deleted.
+func PruneReconciliationMetrics(maxAge time.Duration) {
+	reconciledResources.Lock()
+	defer reconciledResources.Unlock()
+	for r, lastSeen := range reconciledResources.lastSeen {
+		if time.Since(lastSeen) <= maxAge {
+			continue
+		}
+		labels := prometheus.Labels{ControllerLabel: r.controller, NamespaceLabel: r.resource.Namespace, NameLabel: r.resource.Name}
+		ReconciliationLastDuration.Delete(labels)
+		ReconciliationLastSuccess.Delete(labels)
+		delete(reconciledResources.lastSeen, r)
+	}
+}
+
+// ErrorReason returns the reason reported for the given reconciliation error: the reason of Kubernetes API errors,
+// such as Conflict or NotFound, or one of Timeout, Canceled and Unknown.
+func ErrorReason(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ReasonTimeout
+	case errors.Is(err, context.Canceled):
+		return ReasonCanceled
+	}
+	if reason := apierrors.ReasonForError(err); reason != "" {
+		return string(reason)
+	}
+	return ReasonUnknown
+}
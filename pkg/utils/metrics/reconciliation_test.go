@@ -0,0 +1,57 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestErrorReason(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want string
+	}{
+		{err: apierrors.NewConflict(schema.GroupResource{Resource: "pods"}, "pod", errors.New("conflict")), want: "Conflict"},
+		{err: fmt.Errorf("wrapped: %w", apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "secret")), want: "NotFound"},
+		{err: fmt.Errorf("wrapped: %w", context.DeadlineExceeded), want: ReasonTimeout},
+		{err: context.Canceled, want: ReasonCanceled},
+		{err: errors.New("boom"), want: ReasonUnknown},
+	} {
+		assert.Equal(t, tt.want, ErrorReason(tt.err), tt.err.Error())
+	}
+}
+
+func TestObserveReconciliation(t *testing.T) {
+	es := types.NamespacedName{Namespace: "ns", Name: "es"}
+	kb := types.NamespacedName{Namespace: "ns", Name: "kb"}
+	errorsBefore := testutil.ToFloat64(ReconciliationErrors.WithLabelValues("es-controller", "Conflict"))
+
+	ObserveReconciliation("es-controller", es, time.Now().Add(-2*time.Second), nil)
+	ObserveReconciliation("kb-controller", kb, time.Now(), apierrors.NewConflict(schema.GroupResource{Resource: "pods"}, "pod", errors.New("conflict")))
+
+	assert.GreaterOrEqual(t, testutil.ToFloat64(ReconciliationLastDuration.WithLabelValues("es-controller", "ns", "es")), 2.0)
+	assert.NotZero(t, testutil.ToFloat64(ReconciliationLastSuccess.WithLabelValues("es-controller", "ns", "es")))
+	assert.Equal(t, errorsBefore+1, testutil.ToFloat64(ReconciliationErrors.WithLabelValues("kb-controller", "Conflict")))
+	// no success reported for the failed reconciliation
+	assert.Equal(t, 1, testutil.CollectAndCount(ReconciliationLastSuccess))
+
+	// recently reconciled resources are kept
+	PruneReconciliationMetrics(time.Hour)
+	assert.Equal(t, 2, testutil.CollectAndCount(ReconciliationLastDuration))
+	// resources not reconciled anymore are removed
+	PruneReconciliationMetrics(0)
+	assert.Equal(t, 0, testutil.CollectAndCount(ReconciliationLastDuration))
+	assert.Equal(t, 0, testutil.CollectAndCount(ReconciliationLastSuccess))
+}
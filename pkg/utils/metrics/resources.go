@@ -0,0 +1,151 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	resourcesSubsystem = "resources"
+
+	// ResourcesReporterFrequency defines the reporting frequency of the resources reporter.
+	ResourcesReporterFrequency = 1 * time.Minute
+
+	ResourceLabel = "resource"
+	TypeLabel     = "type"
+)
+
+var (
+	// CachedObjectsGauge reports the number of objects held in the operator informer caches, per resource.
+	CachedObjectsGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: resourcesSubsystem,
+		Name:      "cached_objects",
+		Help:      "Number of objects held in the operator informer caches",
+	}, []string{ResourceLabel}))
+
+	// ManagedObjectsGauge reports the number of objects managed by the operator, per resource and per application type.
+	ManagedObjectsGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: resourcesSubsystem,
+		Name:      "managed_objects",
+		Help:      "Number of objects managed by the operator, by application type",
+	}, []string{ResourceLabel, TypeLabel}))
+
+	// DynamicWatchesGauge reports the number of dynamic watches registered by the controllers, per watched resource.
+	DynamicWatchesGauge = register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: resourcesSubsystem,
+		Name:      "dynamic_watches",
+		Help:      "Number of dynamic watches registered by the controllers",
+	}, []string{ResourceLabel}))
+)
+
+// reportedResources are the resources for which cache and managed objects metrics are reported. Only the metadata of
+// Secrets and Pods is read, as it may be the only thing cached, see k8s.MetadataOnlyObjects.
+var reportedResources = map[string]func() client.ObjectList{
+	"secrets":    func() client.ObjectList { return k8s.NewMetadataList(corev1.SchemeGroupVersion.WithKind("Secret")) },
+	"configmaps": func() client.ObjectList { return &corev1.ConfigMapList{} },
+	"services":   func() client.ObjectList { return &corev1.ServiceList{} },
+	"pods":       func() client.ObjectList { return k8s.NewMetadataList(corev1.SchemeGroupVersion.WithKind("Pod")) },
+}
+
+// ResourcesReporter periodically reports the number of cached and managed objects, the health of the Elastic Stack
+// applications and the expiry of their certificates as Prometheus metrics.
+type ResourcesReporter struct {
+	// client is expected to be backed by the operator informer caches.
+	client client.Client
+	// reconciliationMaxAge is the duration after which the reconciliation metrics of a resource which was not
+	// reconciled again are removed.
+	reconciliationMaxAge time.Duration
+	// certificates are the certificates of the Secrets managed by the operator at the last report.
+	certificates map[types.NamespacedName]secretCertificates
+}
+
+// NewResourcesReporter returns a new ResourcesReporter.
+func NewResourcesReporter(c client.Client, reconciliationMaxAge time.Duration) ResourcesReporter {
+	return ResourcesReporter{
+		client:               c,
+		reconciliationMaxAge: reconciliationMaxAge,
+		certificates:         map[types.NamespacedName]secretCertificates{},
+	}
+}
+
+// Start reports the metrics repeatedly at regular intervals until the context is cancelled.
+func (r ResourcesReporter) Start(ctx context.Context, refreshPeriod time.Duration) {
+	ctx = ulog.InitInContext(ctx, "resources-reporter")
+	log := ulog.FromContext(ctx)
+	ticker := time.NewTicker(refreshPeriod)
+	defer ticker.Stop()
+	for {
+		if err := r.Report(ctx); err != nil {
+			log.Error(err, "Failed to report resources metrics")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report lists the objects of each reported resource and updates the corresponding gauges.
+func (r ResourcesReporter) Report(ctx context.Context) error {
+	if err := r.reportObjects(ctx); err != nil {
+		return err
+	}
+	if err := r.reportHealth(ctx); err != nil {
+		return err
+	}
+	if err := r.reportCertificates(ctx); err != nil {
+		return err
+	}
+	PruneReconciliationMetrics(r.reconciliationMaxAge)
+	return nil
+}
+
+// reportObjects updates the number of cached and managed objects of each reported resource.
+func (r ResourcesReporter) reportObjects(ctx context.Context) error {
+	for resource, newList := range reportedResources {
+		list := newList()
+		// objects are only counted, there is no need to copy them out of the cache
+		if err := r.client.List(ctx, list, client.UnsafeDisableDeepCopy); err != nil {
+			return err
+		}
+		objects, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		managed := map[string]int{}
+		for _, obj := range objects {
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return err
+			}
+			if appType, exists := accessor.GetLabels()[commonv1.TypeLabelName]; exists {
+				managed[appType]++
+			}
+		}
+		CachedObjectsGauge.WithLabelValues(resource).Set(float64(len(objects)))
+		// reset to drop application types which are not managed anymore
+		ManagedObjectsGauge.DeletePartialMatch(prometheus.Labels{ResourceLabel: resource})
+		for appType, count := range managed {
+			ManagedObjectsGauge.WithLabelValues(resource, appType).Set(float64(count))
+		}
+	}
+	return nil
+}
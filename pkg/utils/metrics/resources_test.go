@@ -0,0 +1,154 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+)
+
+func TestResourcesReporter_Report(t *testing.T) {
+	scheme.SetupScheme()
+	managedBy := func(name, appType string) metav1.ObjectMeta {
+		meta := metav1.ObjectMeta{Namespace: "ns", Name: name}
+		if appType != "" {
+			meta.Labels = map[string]string{commonv1.TypeLabelName: appType}
+		}
+		return meta
+	}
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+		&corev1.Secret{ObjectMeta: managedBy("es-1", "elasticsearch")},
+		&corev1.Secret{ObjectMeta: managedBy("es-2", "elasticsearch")},
+		&corev1.Secret{ObjectMeta: managedBy("kb", "kibana")},
+		&corev1.Secret{ObjectMeta: managedBy("unrelated", "")},
+		&corev1.Service{ObjectMeta: managedBy("es", "elasticsearch")},
+	).Build()
+
+	require.NoError(t, NewResourcesReporter(c, time.Hour).Report(context.Background()))
+
+	assert.Equal(t, 4.0, testutil.ToFloat64(CachedObjectsGauge.WithLabelValues("secrets")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(CachedObjectsGauge.WithLabelValues("services")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(CachedObjectsGauge.WithLabelValues("configmaps")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(ManagedObjectsGauge.WithLabelValues("secrets", "elasticsearch")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(ManagedObjectsGauge.WithLabelValues("secrets", "kibana")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(ManagedObjectsGauge.WithLabelValues("services", "elasticsearch")))
+
+	// application types which are not managed anymore are not reported
+	require.NoError(t, c.Delete(context.Background(), &corev1.Secret{ObjectMeta: managedBy("kb", "kibana")}))
+	require.NoError(t, NewResourcesReporter(c, time.Hour).Report(context.Background()))
+	assert.Equal(t, 3.0, testutil.ToFloat64(CachedObjectsGauge.WithLabelValues("secrets")))
+	// secrets/elasticsearch and services/elasticsearch
+	assert.Equal(t, 2, testutil.CollectAndCount(ManagedObjectsGauge))
+}
+
+func TestResourcesReporter_reportHealth(t *testing.T) {
+	scheme.SetupScheme()
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Status:     esv1.ElasticsearchStatus{Health: esv1.ElasticsearchYellowHealth},
+	}
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+		es,
+		&kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"}},
+	).Build()
+
+	r := NewResourcesReporter(c, time.Hour)
+	require.NoError(t, r.reportHealth(context.Background()))
+	assert.Equal(t, 1.0, testutil.ToFloat64(HealthGauge.WithLabelValues(esv1.Kind, "ns", "es", "yellow")))
+	// resources without health in their status yet
+	assert.Equal(t, 1.0, testutil.ToFloat64(HealthGauge.WithLabelValues(kbv1.Kind, "ns", "kb", "unknown")))
+
+	// only the current health is reported
+	es.Status.Health = esv1.ElasticsearchGreenHealth
+	require.NoError(t, c.Update(context.Background(), es))
+	require.NoError(t, r.reportHealth(context.Background()))
+	assert.Equal(t, 1.0, testutil.ToFloat64(HealthGauge.WithLabelValues(esv1.Kind, "ns", "es", "green")))
+	assert.Equal(t, 2, testutil.CollectAndCount(HealthGauge))
+
+	// deleted resources are not reported anymore
+	require.NoError(t, c.Delete(context.Background(), es))
+	require.NoError(t, r.reportHealth(context.Background()))
+	assert.Equal(t, 1, testutil.CollectAndCount(HealthGauge))
+}
+
+func TestResourcesReporter_reportCertificates(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	notAfter := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "test"}, NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	managed := metav1.ObjectMeta{Namespace: "ns", Name: "es-http-certs-internal", Labels: map[string]string{commonv1.TypeLabelName: "elasticsearch"}}
+	secretReads := 0
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			secretReads++
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).WithObjects(
+		&corev1.Secret{ObjectMeta: managed, Data: map[string][]byte{
+			"ca.crt":          cert,
+			"tls.crt":         cert,
+			"es-0.tls.crt":    cert,
+			"tls.key":         []byte("not a certificate"),
+			"previous-ca.crt": cert,
+		}},
+		// not managed by the operator
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "user-certs"}, Data: map[string][]byte{"tls.crt": cert}},
+	).Build()
+
+	r := NewResourcesReporter(c, time.Hour)
+	require.NoError(t, r.reportCertificates(context.Background()))
+	assert.Equal(t, 3, testutil.CollectAndCount(CertificateExpiryGauge))
+	for _, key := range []string{"ca.crt", "tls.crt", "es-0.tls.crt"} {
+		assert.Equal(t, float64(notAfter.Unix()), testutil.ToFloat64(CertificateExpiryGauge.WithLabelValues("ns", "es-http-certs-internal", key)))
+	}
+	assert.Equal(t, 1, secretReads)
+
+	// unchanged Secrets are not read again
+	require.NoError(t, r.reportCertificates(context.Background()))
+	assert.Equal(t, 3, testutil.CollectAndCount(CertificateExpiryGauge))
+	assert.Equal(t, 1, secretReads)
+
+	// updated Secrets are
+	var secret corev1.Secret
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: managed.Name}, &secret))
+	delete(secret.Data, "es-0.tls.crt")
+	require.NoError(t, c.Update(context.Background(), &secret))
+	secretReads = 0
+	require.NoError(t, r.reportCertificates(context.Background()))
+	assert.Equal(t, 2, testutil.CollectAndCount(CertificateExpiryGauge))
+	assert.Equal(t, 1, secretReads)
+
+	// deleted Secrets are not reported anymore
+	require.NoError(t, c.Delete(context.Background(), &secret))
+	require.NoError(t, r.reportCertificates(context.Background()))
+	assert.Equal(t, 0, testutil.CollectAndCount(CertificateExpiryGauge))
+	assert.Empty(t, r.certificates)
+}
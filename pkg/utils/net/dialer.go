@@ -14,3 +14,19 @@ type Dialer interface {
 	// DialContext specifies the dial function for creating connections.
 	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
 }
+
+// ClusterDialer is a Dialer connecting to the endpoints of a remote Kubernetes cluster managed by the operator.
+type ClusterDialer interface {
+	Dialer
+	// ClusterName returns the name of the remote Kubernetes cluster.
+	ClusterName() string
+}
+
+// ClusterName returns the name of the remote Kubernetes cluster the given dialer connects to, or an empty string for
+// the cluster the operator runs in.
+func ClusterName(dialer Dialer) string {
+	if d, ok := dialer.(ClusterDialer); ok {
+		return d.ClusterName()
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package rbac
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	associationv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/association/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// AssociationPolicyReviewer allows the references to the resources selected by an AssociationPolicy of their namespace,
+// and delegates the review of the other references to another AccessReviewer.
+type AssociationPolicyReviewer struct {
+	client   k8s.Client
+	delegate AccessReviewer
+}
+
+var _ AccessReviewer = &AssociationPolicyReviewer{}
+
+// NewAssociationPolicyReviewer returns an AccessReviewer consulting the AssociationPolicies before the given AccessReviewer.
+func NewAssociationPolicyReviewer(client k8s.Client, delegate AccessReviewer) AccessReviewer {
+	return &AssociationPolicyReviewer{
+		client:   client,
+		delegate: delegate,
+	}
+}
+
+func (r *AssociationPolicyReviewer) AccessAllowed(ctx context.Context, serviceAccount string, sourceNamespace string, object runtime.Object) (bool, error) {
+	metaObject, err := meta.Accessor(object)
+	if err != nil {
+		return false, nil //nolint:nilerr
+	}
+	if sourceNamespace != metaObject.GetNamespace() {
+		var policies associationv1alpha1.AssociationPolicyList
+		err := r.client.List(ctx, &policies, client.InNamespace(metaObject.GetNamespace()))
+		// the AssociationPolicy CRD may not be installed
+		if err != nil && !meta.IsNoMatchError(err) {
+			return false, err
+		}
+		kind := object.GetObjectKind().GroupVersionKind().Kind
+		for _, policy := range policies.Items {
+			if policy.Allows(serviceAccount, sourceNamespace, kind, metaObject.GetName()) {
+				ulog.FromContext(ctx).V(1).Info(
+					"Reference allowed by association policy", "policy", policy.Name,
+					"service_account", serviceAccount,
+					"source_namespace", sourceNamespace,
+					"remote_kind", kind,
+					"remote_namespace", metaObject.GetNamespace(),
+					"remote_name", metaObject.GetName(),
+				)
+				return true, nil
+			}
+		}
+	}
+	return r.delegate.AccessAllowed(ctx, serviceAccount, sourceNamespace, object)
+}
@@ -0,0 +1,130 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package rbac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	associationv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/association/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+type denyingAccessReviewer struct{}
+
+func (denyingAccessReviewer) AccessAllowed(_ context.Context, _ string, _ string, _ runtime.Object) (bool, error) {
+	return false, nil
+}
+
+func TestAssociationPolicyReviewer_AccessAllowed(t *testing.T) {
+	es := &esv1.Elasticsearch{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1.Kind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "elastic", Name: "es"},
+	}
+	kb := &kbv1.Kibana{
+		TypeMeta:   metav1.TypeMeta{Kind: kbv1.Kind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "elastic", Name: "kb"},
+	}
+	policy := func(namespace string, spec associationv1alpha1.AssociationPolicySpec) *associationv1alpha1.AssociationPolicy {
+		return &associationv1alpha1.AssociationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "policy"},
+			Spec:       spec,
+		}
+	}
+	tenantToES := associationv1alpha1.AssociationPolicySpec{
+		From: []associationv1alpha1.AssociationPolicySubject{{Namespace: "tenant"}},
+		To:   []associationv1alpha1.AssociationPolicyTarget{{Kind: esv1.Kind, Name: "es"}},
+	}
+	tests := []struct {
+		name            string
+		policies        []*associationv1alpha1.AssociationPolicy
+		serviceAccount  string
+		sourceNamespace string
+		object          runtime.Object
+		want            bool
+	}{
+		{
+			name:            "no policy",
+			sourceNamespace: "tenant",
+			object:          es,
+			want:            false,
+		},
+		{
+			name:            "allowed by a policy",
+			policies:        []*associationv1alpha1.AssociationPolicy{policy("elastic", tenantToES)},
+			sourceNamespace: "tenant",
+			object:          es,
+			want:            true,
+		},
+		{
+			name:            "policy in another namespace",
+			policies:        []*associationv1alpha1.AssociationPolicy{policy("tenant", tenantToES)},
+			sourceNamespace: "tenant",
+			object:          es,
+			want:            false,
+		},
+		{
+			name:            "other source namespace",
+			policies:        []*associationv1alpha1.AssociationPolicy{policy("elastic", tenantToES)},
+			sourceNamespace: "other-tenant",
+			object:          es,
+			want:            false,
+		},
+		{
+			name:            "other kind",
+			policies:        []*associationv1alpha1.AssociationPolicy{policy("elastic", tenantToES)},
+			sourceNamespace: "tenant",
+			object:          kb,
+			want:            false,
+		},
+		{
+			name: "all the resources of the namespace",
+			policies: []*associationv1alpha1.AssociationPolicy{policy("elastic", associationv1alpha1.AssociationPolicySpec{
+				From: []associationv1alpha1.AssociationPolicySubject{{Namespace: "tenant"}},
+			})},
+			sourceNamespace: "tenant",
+			object:          kb,
+			want:            true,
+		},
+		{
+			name: "default service account",
+			policies: []*associationv1alpha1.AssociationPolicy{policy("elastic", associationv1alpha1.AssociationPolicySpec{
+				From: []associationv1alpha1.AssociationPolicySubject{{Namespace: "tenant", ServiceAccountName: "default"}},
+			})},
+			sourceNamespace: "tenant",
+			object:          es,
+			want:            true,
+		},
+		{
+			name: "other service account",
+			policies: []*associationv1alpha1.AssociationPolicy{policy("elastic", associationv1alpha1.AssociationPolicySpec{
+				From: []associationv1alpha1.AssociationPolicySubject{{Namespace: "tenant", ServiceAccountName: "kibana"}},
+			})},
+			serviceAccount:  "beats",
+			sourceNamespace: "tenant",
+			object:          es,
+			want:            false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objects []client.Object
+			for _, p := range tt.policies {
+				objects = append(objects, p)
+			}
+			reviewer := NewAssociationPolicyReviewer(k8s.NewFakeClient(objects...), denyingAccessReviewer{})
+			got, err := reviewer.AccessAllowed(context.Background(), tt.serviceAccount, tt.sourceNamespace, tt.object)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}